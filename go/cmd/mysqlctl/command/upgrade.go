@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/vt/mysqlctl"
+)
+
+var Upgrade = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Restarts mysqld with the installed MySQL binaries and upgrades its data directory.",
+	Long: "Upgrade a `mysqld` instance that was previously started with `init` or `start` in place.\n\n" +
+		"It shuts `mysqld` down, runs the `mysqld_upgrade` hook, if any, to install the new MySQL binaries, " +
+		"and starts `mysqld` again. Without a hook, the new binaries must already be installed under `$VT_MYSQL_ROOT`. " +
+		"The data directory is then upgraded to the new version, by `mysql_upgrade` before MySQL 8.0.16, and by the server itself after.\n\n" +
+		"The tablet of this `mysqld` should be taken out of serving first, see `vtctldclient UpgradeShardMysql`.",
+	Example: `mysqlctl --tablet_uid 101 --alsologtostderr upgrade`,
+	Args:    cobra.NoArgs,
+	RunE:    commandUpgrade,
+}
+
+var upgradeArgs = struct {
+	WaitTime time.Duration
+}{
+	WaitTime: 5 * time.Minute,
+}
+
+func commandUpgrade(cmd *cobra.Command, args []string) error {
+	// There ought to be an existing my.cnf, so use it to find mysqld.
+	mysqld, cnf, err := mysqlctl.OpenMysqldAndMycnf(tabletUID, collationEnv)
+	if err != nil {
+		return fmt.Errorf("failed to find mysql config: %v", err)
+	}
+	defer mysqld.Close()
+
+	// Shutting down and starting up both wait for at most WaitTime.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*upgradeArgs.WaitTime+10*time.Second)
+	defer cancel()
+	if err := mysqlctl.Upgrade(ctx, mysqld, cnf, upgradeArgs.WaitTime); err != nil {
+		return fmt.Errorf("failed upgrade mysql: %v", err)
+	}
+	return nil
+}
+
+func init() {
+	Upgrade.Flags().DurationVar(&upgradeArgs.WaitTime, "wait_time", upgradeArgs.WaitTime, "How long to wait for mysqld shutdown and startup.")
+
+	Root.AddCommand(Upgrade)
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	scheduledBackupSucceeded = "Succeeded"
+	scheduledBackupFailed    = "Failed"
+	scheduledBackupSkipped   = "Skipped"
+)
+
+var (
+	controllerMode         bool
+	controllerPollInterval = 1 * time.Minute
+
+	scheduledBackupRuns = stats.NewCountersWithSingleLabel(
+		"ScheduledBackupRuns",
+		"Number of scheduled backup runs in controller mode, by result.",
+		"result",
+		scheduledBackupSucceeded, scheduledBackupFailed, scheduledBackupSkipped,
+	)
+	lastSuccessfulBackupTimestamp = stats.NewGauge(
+		"LastSuccessfulBackupTimestamp",
+		"Unix timestamp of the last successful scheduled backup run in controller mode.",
+	)
+	nextScheduledBackupTimestamp = stats.NewGauge(
+		"NextScheduledBackupTimestamp",
+		"Unix timestamp of the next scheduled backup run in controller mode, or 0 if the shard has no backup schedule.",
+	)
+)
+
+func init() {
+	Main.Flags().BoolVar(&controllerMode, "controller", controllerMode, "Instead of performing a single pass of backup maintenance and exiting, keep running and take backups and prune old ones on the schedule stored in the topo for the shard (see 'vtctldclient SetBackupSchedule').")
+	Main.Flags().DurationVar(&controllerPollInterval, "controller-schedule-poll-interval", controllerPollInterval, "In controller mode, how often to re-read the backup schedule of the shard from the topo.")
+}
+
+// backupController takes backups of a shard following the BackupSchedule in
+// the topo, until its context is cancelled.
+type backupController struct {
+	ts            *topo.Server
+	backupStorage backupstorage.BackupStorage
+	backupDir     string
+
+	schedule *topodatapb.BackupSchedule
+	cron     *timer.CronSchedule
+	next     time.Time
+
+	// lastBackupTime is the unix timestamp of the most recent complete
+	// backup, or 0 if it is not known.
+	lastBackupTime atomic.Int64
+}
+
+func runController(ctx context.Context, ts *topo.Server, backupStorage backupstorage.BackupStorage, backupDir string) error {
+	if initialBackup {
+		return fmt.Errorf("--initial_backup cannot be used with --controller")
+	}
+	if controllerPollInterval <= 0 {
+		return fmt.Errorf("--controller-schedule-poll-interval must be positive")
+	}
+
+	bc := &backupController{
+		ts:            ts,
+		backupStorage: backupStorage,
+		backupDir:     backupDir,
+	}
+	stats.NewGaugeFunc(
+		"LastBackupAgeSeconds",
+		"Age of the most recent complete backup of the shard in controller mode, or 0 if there is none.",
+		bc.lastBackupAge,
+	)
+	if backups, err := backupStorage.ListBackups(ctx, backupDir); err != nil {
+		log.Warningf("Can't list backups to find the most recent one: %v", err)
+	} else if backup := lastCompleteBackup(ctx, backups); backup != nil {
+		if backupTime, err := parseBackupTime(backup.Name()); err == nil {
+			bc.lastBackupTime.Store(backupTime.Unix())
+		}
+	}
+
+	log.Infof("Running in controller mode for shard %v/%v, polling the backup schedule every %v.", initKeyspace, initShard, controllerPollInterval)
+	ticker := time.NewTicker(controllerPollInterval)
+	defer ticker.Stop()
+	for {
+		bc.refreshSchedule(ctx)
+		if !bc.next.IsZero() && !time.Now().Before(bc.next) {
+			bc.runScheduledBackup(ctx)
+			// Runs missed while the backup was in progress are skipped.
+			bc.setNext(bc.cron.Next(time.Now().UTC()))
+			if !bc.next.IsZero() {
+				log.Infof("Next scheduled backup at %v.", bc.next)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Stopping backup controller.")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshSchedule reads the backup schedule of the shard from the topo, and
+// recomputes the time of the next run if it changed. If the schedule can't be
+// read, the previous one is kept.
+func (bc *backupController) refreshSchedule(ctx context.Context) {
+	getCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+	schedule, err := bc.ts.GetBackupSchedule(getCtx, initKeyspace, initShard)
+	switch {
+	case topo.IsErrType(err, topo.NoNode):
+		schedule = nil
+	case err != nil:
+		log.Warningf("Can't read the backup schedule of %v/%v, keeping the previous one: %v", initKeyspace, initShard, err)
+		return
+	}
+
+	if bc.schedule.GetCronSpec() == schedule.GetCronSpec() {
+		bc.schedule = schedule
+		return
+	}
+	bc.schedule, bc.cron = schedule, nil
+	bc.setNext(time.Time{})
+	if schedule == nil {
+		log.Infof("Shard %v/%v has no backup schedule, waiting for one to be set.", initKeyspace, initShard)
+		return
+	}
+
+	cron, err := timer.ParseCron(schedule.CronSpec)
+	if err != nil {
+		log.Errorf("Ignoring the backup schedule of %v/%v: %v", initKeyspace, initShard, err)
+		return
+	}
+	bc.cron = cron
+	bc.setNext(cron.Next(time.Now().UTC()))
+	log.Infof("Backup schedule of %v/%v set to %q, next backup at %v.", initKeyspace, initShard, schedule.CronSpec, bc.next)
+}
+
+// setNext sets the time of the next run. A zero time means there is none.
+func (bc *backupController) setNext(next time.Time) {
+	bc.next = next
+	if next.IsZero() {
+		nextScheduledBackupTimestamp.Set(0)
+		return
+	}
+	nextScheduledBackupTimestamp.Set(next.Unix())
+}
+
+// runScheduledBackup takes a backup if one is needed, then prunes old
+// backups following the retention policy of the schedule.
+func (bc *backupController) runScheduledBackup(ctx context.Context) {
+	log.Infof("Starting scheduled backup of %v/%v.", initKeyspace, initShard)
+	doBackup, err := shouldBackup(ctx, bc.ts, bc.backupStorage, bc.backupDir)
+	if err != nil {
+		log.Errorf("Can't take scheduled backup: %v", err)
+		scheduledBackupRuns.Add(scheduledBackupFailed, 1)
+		return
+	}
+	if doBackup {
+		if err := takeBackup(ctx, bc.ts, bc.backupStorage); err != nil {
+			log.Errorf("Failed to take scheduled backup: %v", err)
+			scheduledBackupRuns.Add(scheduledBackupFailed, 1)
+			return
+		}
+		now := time.Now()
+		bc.lastBackupTime.Store(now.Unix())
+		lastSuccessfulBackupTimestamp.Set(now.Unix())
+	}
+
+	retentionTime, retentionCount := bc.retentionPolicy()
+	if err := pruneBackups(ctx, bc.backupStorage, bc.backupDir, retentionTime, retentionCount); err != nil {
+		log.Errorf("Couldn't prune old backups: %v", err)
+		scheduledBackupRuns.Add(scheduledBackupFailed, 1)
+		return
+	}
+
+	if doBackup {
+		scheduledBackupRuns.Add(scheduledBackupSucceeded, 1)
+	} else {
+		scheduledBackupRuns.Add(scheduledBackupSkipped, 1)
+	}
+}
+
+// retentionPolicy returns the minimum retention time and count to prune
+// backups with. The values set in the schedule take precedence over the
+// --min_retention_time and --min_retention_count flags.
+func (bc *backupController) retentionPolicy() (time.Duration, int) {
+	retentionTime, retentionCount := minRetentionTime, minRetentionCount
+	if bc.schedule.GetMinRetentionTime() != nil {
+		if d, ok, err := protoutil.DurationFromProto(bc.schedule.GetMinRetentionTime()); ok && err == nil {
+			retentionTime = d
+		}
+	}
+	if n := int(bc.schedule.GetMinRetentionCount()); n > 0 {
+		retentionCount = n
+	}
+	return retentionTime, retentionCount
+}
+
+func (bc *backupController) lastBackupAge() int64 {
+	last := bc.lastBackupTime.Load()
+	if last == 0 {
+		return 0
+	}
+	return int64(time.Since(time.Unix(last, 0)).Seconds())
+}
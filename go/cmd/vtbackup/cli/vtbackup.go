@@ -176,7 +176,16 @@ to the handling of the query path.
 The command-line parameters to vtbackup specify a policy for when a new backup
 is needed, and when old backups should be removed. If the existing backups
 already satisfy the policy, then vtbackup will do nothing and return success
-immediately.`,
+immediately.
+
+With --controller, vtbackup instead keeps running and follows the backup schedule
+of the shard stored in the topo (see 'vtctldclient SetBackupSchedule'): at each
+time given by the schedule's cron spec, it performs the pass described above,
+pruning old backups with the retention policy of the schedule if it has one.
+The schedule is re-read periodically, so it can be changed without restarting
+vtbackup. The ScheduledBackupRuns, LastSuccessfulBackupTimestamp,
+LastBackupAgeSeconds and NextScheduledBackupTimestamp metrics can be used to
+alert on missing backups.`,
 		Version: servenv.AppVersion.String(),
 		Args:    cobra.NoArgs,
 		PreRunE: servenv.CobraPreRunE,
@@ -273,10 +282,14 @@ func run(_ *cobra.Command, args []string) error {
 		}
 	}
 
+	backupDir := mysqlctl.GetBackupDir(initKeyspace, initShard)
+	if controllerMode {
+		return runController(ctx, topoServer, backupStorage, backupDir)
+	}
+
 	// Try to take a backup, if it's been long enough since the last one.
 	// Skip pruning if backup wasn't fully successful. We don't want to be
 	// deleting things if the backup process is not healthy.
-	backupDir := mysqlctl.GetBackupDir(initKeyspace, initShard)
 	doBackup, err := shouldBackup(ctx, topoServer, backupStorage, backupDir)
 	if err != nil {
 		return fmt.Errorf("Can't take backup: %w", err)
@@ -288,7 +301,7 @@ func run(_ *cobra.Command, args []string) error {
 	}
 
 	// Prune old backups.
-	if err := pruneBackups(ctx, backupStorage, backupDir); err != nil {
+	if err := pruneBackups(ctx, backupStorage, backupDir, minRetentionTime, minRetentionCount); err != nil {
 		return fmt.Errorf("Couldn't prune old backups: %w", err)
 	}
 
@@ -716,7 +729,7 @@ func retryOnError(ctx context.Context, fn func() error) error {
 	}
 }
 
-func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage, backupDir string) error {
+func pruneBackups(ctx context.Context, backupStorage backupstorage.BackupStorage, backupDir string, minRetentionTime time.Duration, minRetentionCount int) error {
 	if minRetentionTime == 0 {
 		log.Info("Pruning of old backups is disabled.")
 		return nil
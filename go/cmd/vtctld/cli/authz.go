@@ -0,0 +1,42 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"google.golang.org/grpc"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtctl/authz"
+)
+
+var authzConfigPath string
+
+func init() {
+	Main.Flags().StringVar(&authzConfigPath, "authz_config", authzConfigPath, "Path to the authorization config of the gRPC vtctl services, which binds the reader, migrator and admin roles to the callers. If unset, all the callers are allowed.")
+
+	servenv.RegisterGRPCServerInterceptors(func() (grpc.StreamServerInterceptor, grpc.UnaryServerInterceptor) {
+		if authzConfigPath == "" {
+			return nil, nil
+		}
+		authorizer, err := authz.LoadConfig(authzConfigPath)
+		if err != nil {
+			log.Exitf("Failed to load the authorization config %s: %v", authzConfigPath, err)
+		}
+		return authorizer.StreamServerInterceptor(), authorizer.UnaryServerInterceptor()
+	})
+}
@@ -29,13 +29,14 @@ import (
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 var (
 	// Backup makes a Backup gRPC call to a vtctld.
 	Backup = &cobra.Command{
-		Use:                   "Backup [--concurrency <concurrency>] [--allow-primary] [--incremental-from-pos=<pos>|<backup-name>|auto] [--upgrade-safe] <tablet_alias>",
+		Use:                   "Backup [--concurrency <concurrency>] [--allow-primary] [--incremental | --incremental-from-pos=<pos>|<backup-name>|auto] [--upgrade-safe] <tablet_alias>",
 		Short:                 "Uses the BackupStorage service on the given tablet to create and store a new backup.",
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
@@ -43,7 +44,7 @@ var (
 	}
 	// BackupShard makes a BackupShard gRPC call to a vtctld.
 	BackupShard = &cobra.Command{
-		Use:   "BackupShard [--concurrency <concurrency>] [--allow-primary] [--incremental-from-pos=<pos>|<backup-name>|auto] [--upgrade-safe] <keyspace/shard>",
+		Use:   "BackupShard [--concurrency <concurrency>] [--allow-primary] [--incremental | --incremental-from-pos=<pos>|<backup-name>|auto] [--upgrade-safe] <keyspace/shard>",
 		Short: "Finds the most up-to-date REPLICA, RDONLY, or SPARE tablet in the given shard and uses the BackupStorage service on that tablet to create and store a new backup.",
 		Long: `Finds the most up-to-date REPLICA, RDONLY, or SPARE tablet in the given shard and uses the BackupStorage service on that tablet to create and store a new backup.
 
@@ -52,6 +53,14 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandBackupShard,
 	}
+	// GetBackupSchedule makes a GetBackupSchedule gRPC call to a vtctld.
+	GetBackupSchedule = &cobra.Command{
+		Use:                   "GetBackupSchedule <keyspace/shard>",
+		Short:                 "Outputs a JSON structure with the backup schedule of the given shard.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetBackupSchedule,
+	}
 	// GetBackups makes a GetBackups gRPC call to a vtctld.
 	GetBackups = &cobra.Command{
 		Use:                   "GetBackups [--limit <limit>] [--json] <keyspace/shard>",
@@ -60,6 +69,20 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetBackups,
 	}
+	// SetBackupSchedule makes a SetBackupSchedule gRPC call to a vtctld.
+	SetBackupSchedule = &cobra.Command{
+		Use:   "SetBackupSchedule {--cron <spec> [--min-retention-time <duration>] [--min-retention-count <count>] | --clear} <keyspace/shard>",
+		Short: "Sets or clears the backup schedule of the given shard.",
+		Long: `Sets or clears the backup schedule of the given shard.
+
+The schedule is followed by vtbackup when it runs with --controller: it takes
+a backup at the times given by the cron spec, in UTC, and prunes the backups
+older than --min-retention-time, always keeping at least --min-retention-count
+of them.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSetBackupSchedule,
+	}
 	// RemoveBackup makes a RemoveBackup gRPC call to a vtctld.
 	RemoveBackup = &cobra.Command{
 		Use:                   "RemoveBackup <keyspace/shard> <backup name>",
@@ -78,9 +101,23 @@ If no replica-type tablet can be found, the backup can be taken on the primary i
 	}
 )
 
+// resolveIncrementalFromPos returns the position an incremental backup should
+// start from. --incremental is a shorthand for --incremental-from-pos=auto,
+// which takes the backup from the end position of the last successful backup.
+func resolveIncrementalFromPos(incremental bool, incrementalFromPos string) (string, error) {
+	if !incremental {
+		return incrementalFromPos, nil
+	}
+	if incrementalFromPos != "" {
+		return "", fmt.Errorf("--incremental and --incremental-from-pos are mutually exclusive")
+	}
+	return mysqlctl.AutoIncrementalFromPos, nil
+}
+
 var backupOptions = struct {
 	AllowPrimary       bool
 	Concurrency        int32
+	Incremental        bool
 	IncrementalFromPos string
 	UpgradeSafe        bool
 }{}
@@ -91,13 +128,18 @@ func commandBackup(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	incrementalFromPos, err := resolveIncrementalFromPos(backupOptions.Incremental, backupOptions.IncrementalFromPos)
+	if err != nil {
+		return err
+	}
+
 	cli.FinishedParsing(cmd)
 
 	stream, err := client.Backup(commandCtx, &vtctldatapb.BackupRequest{
 		TabletAlias:        tabletAlias,
 		AllowPrimary:       backupOptions.AllowPrimary,
 		Concurrency:        backupOptions.Concurrency,
-		IncrementalFromPos: backupOptions.IncrementalFromPos,
+		IncrementalFromPos: incrementalFromPos,
 		UpgradeSafe:        backupOptions.UpgradeSafe,
 	})
 	if err != nil {
@@ -120,6 +162,7 @@ func commandBackup(cmd *cobra.Command, args []string) error {
 var backupShardOptions = struct {
 	AllowPrimary       bool
 	Concurrency        int32
+	Incremental        bool
 	IncrementalFromPos string
 	UpgradeSafe        bool
 }{}
@@ -130,6 +173,11 @@ func commandBackupShard(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	incrementalFromPos, err := resolveIncrementalFromPos(backupShardOptions.Incremental, backupShardOptions.IncrementalFromPos)
+	if err != nil {
+		return err
+	}
+
 	cli.FinishedParsing(cmd)
 
 	stream, err := client.BackupShard(commandCtx, &vtctldatapb.BackupShardRequest{
@@ -137,7 +185,7 @@ func commandBackupShard(cmd *cobra.Command, args []string) error {
 		Shard:              shard,
 		AllowPrimary:       backupShardOptions.AllowPrimary,
 		Concurrency:        backupShardOptions.Concurrency,
-		IncrementalFromPos: backupShardOptions.IncrementalFromPos,
+		IncrementalFromPos: incrementalFromPos,
 		UpgradeSafe:        backupShardOptions.UpgradeSafe,
 	})
 	if err != nil {
@@ -157,6 +205,31 @@ func commandBackupShard(cmd *cobra.Command, args []string) error {
 	}
 }
 
+func commandGetBackupSchedule(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetBackupSchedule(commandCtx, &vtctldatapb.GetBackupScheduleRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.BackupSchedule)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var getBackupsOptions = struct {
 	Limit      uint32
 	OutputJSON bool
@@ -217,6 +290,54 @@ func commandRemoveBackup(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+var setBackupScheduleOptions = struct {
+	CronSpec          string
+	MinRetentionTime  time.Duration
+	MinRetentionCount int32
+	Clear             bool
+}{}
+
+func commandSetBackupSchedule(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var schedule *topodatapb.BackupSchedule
+	switch {
+	case setBackupScheduleOptions.Clear && setBackupScheduleOptions.CronSpec != "":
+		return fmt.Errorf("cannot specify both --cron and --clear")
+	case setBackupScheduleOptions.Clear:
+	case setBackupScheduleOptions.CronSpec == "":
+		return fmt.Errorf("one of --cron or --clear is required")
+	default:
+		schedule = &topodatapb.BackupSchedule{
+			CronSpec:          setBackupScheduleOptions.CronSpec,
+			MinRetentionTime:  protoutil.DurationToProto(setBackupScheduleOptions.MinRetentionTime),
+			MinRetentionCount: setBackupScheduleOptions.MinRetentionCount,
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.SetBackupSchedule(commandCtx, &vtctldatapb.SetBackupScheduleRequest{
+		Keyspace:       keyspace,
+		Shard:          shard,
+		BackupSchedule: schedule,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.BackupSchedule)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var restoreFromBackupOptions = struct {
 	BackupTimestamp    string
 	RestoreToPos       string
@@ -281,6 +402,7 @@ func commandRestoreFromBackup(cmd *cobra.Command, args []string) error {
 func init() {
 	Backup.Flags().BoolVar(&backupOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	Backup.Flags().Int32Var(&backupOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
+	Backup.Flags().BoolVar(&backupOptions.Incremental, "incremental", false, "Take an incremental backup from the end position of the last successful backup. Shorthand for --incremental-from-pos=auto.")
 	Backup.Flags().StringVar(&backupOptions.IncrementalFromPos, "incremental-from-pos", "", "Position, or name of backup from which to create an incremental backup. Default: empty. If given, then this backup becomes an incremental backup from given position or given backup. If value is 'auto', this backup will be taken from the last successful backup position.")
 
 	Backup.Flags().BoolVar(&backupOptions.UpgradeSafe, "upgrade-safe", false, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
@@ -288,16 +410,25 @@ func init() {
 
 	BackupShard.Flags().BoolVar(&backupShardOptions.AllowPrimary, "allow-primary", false, "Allow the primary of a shard to be used for the backup. WARNING: If using the builtin backup engine, this will shutdown mysqld on the primary and stop writes for the duration of the backup.")
 	BackupShard.Flags().Int32Var(&backupShardOptions.Concurrency, "concurrency", 4, "Specifies the number of compression/checksum jobs to run simultaneously.")
+	BackupShard.Flags().BoolVar(&backupShardOptions.Incremental, "incremental", false, "Take an incremental backup from the end position of the last successful backup. Shorthand for --incremental-from-pos=auto.")
 	BackupShard.Flags().StringVar(&backupShardOptions.IncrementalFromPos, "incremental-from-pos", "", "Position, or name of backup from which to create an incremental backup. Default: empty. If given, then this backup becomes an incremental backup from given position or given backup. If value is 'auto', this backup will be taken from the last successful backup position.")
-	BackupShard.Flags().BoolVar(&backupOptions.UpgradeSafe, "upgrade-safe", false, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
+	BackupShard.Flags().BoolVar(&backupShardOptions.UpgradeSafe, "upgrade-safe", false, "Whether to use innodb_fast_shutdown=0 for the backup so it is safe to use for MySQL upgrades.")
 	Root.AddCommand(BackupShard)
 
+	Root.AddCommand(GetBackupSchedule)
+
 	GetBackups.Flags().Uint32VarP(&getBackupsOptions.Limit, "limit", "l", 0, "Retrieve only the most recent N backups.")
 	GetBackups.Flags().BoolVarP(&getBackupsOptions.OutputJSON, "json", "j", false, "Output backup info in JSON format rather than a list of backups.")
 	Root.AddCommand(GetBackups)
 
 	Root.AddCommand(RemoveBackup)
 
+	SetBackupSchedule.Flags().StringVar(&setBackupScheduleOptions.CronSpec, "cron", "", "When to take backups, as a cron spec in UTC, e.g. \"0 3 * * *\" or \"@daily\".")
+	SetBackupSchedule.Flags().DurationVar(&setBackupScheduleOptions.MinRetentionTime, "min-retention-time", 0, "Keep each backup for at least this long before pruning it. Backups are not pruned if 0.")
+	SetBackupSchedule.Flags().Int32Var(&setBackupScheduleOptions.MinRetentionCount, "min-retention-count", 1, "Always keep at least this many of the most recent backups, even if they are older than --min-retention-time.")
+	SetBackupSchedule.Flags().BoolVar(&setBackupScheduleOptions.Clear, "clear", false, "Remove the backup schedule of the shard.")
+	Root.AddCommand(SetBackupSchedule)
+
 	RestoreFromBackup.Flags().StringVarP(&restoreFromBackupOptions.BackupTimestamp, "backup-timestamp", "t", "", "Use the backup taken at, or closest before, this timestamp. Omit to use the latest backup. Timestamp format is \"YYYY-mm-DD.HHMMSS\".")
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToPos, "restore-to-pos", "", "Run a point in time recovery that ends with the given position. This will attempt to use one full backup followed by zero or more incremental backups")
 	RestoreFromBackup.Flags().StringVar(&restoreFromBackupOptions.RestoreToTimestamp, "restore-to-timestamp", "", "Run a point in time recovery that restores up to, and excluding, given timestamp in RFC3339 format (`2006-01-02T15:04:05Z07:00`). This will attempt to use one full backup followed by zero or more incremental backups")
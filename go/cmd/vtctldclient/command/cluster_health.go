@@ -0,0 +1,374 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// ClusterHealth aggregates the health of the cluster into a single report.
+var ClusterHealth = &cobra.Command{
+	Use:   "ClusterHealth [--keyspace <keyspace> ...] [--lag-threshold <duration>]",
+	Short: "Outputs a JSON report of tablet health, replication, primary consistency, semi-sync state, and pending migrations and workflows.",
+	Long: `Outputs a JSON report of tablet health, replication, primary consistency, semi-sync state, and pending migrations and workflows.
+
+The report is meant to be consulted before maintenance operations. Every tablet is
+contacted to collect its replication and semi-sync status. The command exits with
+an error if any issues were found; pending Online DDL migrations and VReplication
+workflows are reported, but are not considered issues on their own.`,
+	DisableFlagsInUseLine: true,
+	Args:                  cobra.NoArgs,
+	RunE:                  commandClusterHealth,
+}
+
+var clusterHealthOptions = struct {
+	Keyspaces    []string
+	LagThreshold time.Duration
+}{}
+
+type clusterHealthReport struct {
+	Healthy   bool              `json:"healthy"`
+	Keyspaces []*keyspaceHealth `json:"keyspaces"`
+}
+
+type keyspaceHealth struct {
+	Name              string              `json:"name"`
+	Shards            []*shardHealth      `json:"shards"`
+	PendingMigrations []*pendingMigration `json:"pending_migrations,omitempty"`
+	Workflows         []*workflowHealth   `json:"workflows,omitempty"`
+	Issues            []string            `json:"issues,omitempty"`
+}
+
+type shardHealth struct {
+	Name         string          `json:"name"`
+	PrimaryAlias string          `json:"primary_alias,omitempty"`
+	Tablets      []*tabletHealth `json:"tablets"`
+	Issues       []string        `json:"issues,omitempty"`
+}
+
+type tabletHealth struct {
+	Alias                  string `json:"alias"`
+	Type                   string `json:"type"`
+	Reachable              bool   `json:"reachable"`
+	Error                  string `json:"error,omitempty"`
+	ReadOnly               bool   `json:"read_only"`
+	ReplicationRunning     bool   `json:"replication_running,omitempty"`
+	ReplicationLagSeconds  uint32 `json:"replication_lag_seconds,omitempty"`
+	ReplicationLagUnknown  bool   `json:"replication_lag_unknown,omitempty"`
+	SemiSyncPrimaryEnabled bool   `json:"semi_sync_primary_enabled,omitempty"`
+	SemiSyncPrimaryStatus  bool   `json:"semi_sync_primary_status,omitempty"`
+	SemiSyncPrimaryClients uint32 `json:"semi_sync_primary_clients,omitempty"`
+	SemiSyncReplicaEnabled bool   `json:"semi_sync_replica_enabled,omitempty"`
+	SemiSyncReplicaStatus  bool   `json:"semi_sync_replica_status,omitempty"`
+}
+
+type pendingMigration struct {
+	UUID     string  `json:"uuid"`
+	Shard    string  `json:"shard"`
+	Table    string  `json:"table"`
+	Status   string  `json:"status"`
+	Progress float32 `json:"progress"`
+}
+
+type workflowHealth struct {
+	Name                string `json:"name"`
+	Type                string `json:"type"`
+	MaxVReplicationLag  int64  `json:"max_vreplication_lag"`
+	ErroredStreamsCount int    `json:"errored_streams_count,omitempty"`
+}
+
+func commandClusterHealth(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	keyspaces := clusterHealthOptions.Keyspaces
+	if len(keyspaces) == 0 {
+		resp, err := client.GetKeyspaces(commandCtx, &vtctldatapb.GetKeyspacesRequest{})
+		if err != nil {
+			return err
+		}
+		for _, ks := range resp.Keyspaces {
+			keyspaces = append(keyspaces, ks.Name)
+		}
+	}
+	sort.Strings(keyspaces)
+
+	report := &clusterHealthReport{}
+	for _, keyspace := range keyspaces {
+		ksHealth, err := getKeyspaceHealth(commandCtx, keyspace, clusterHealthOptions.LagThreshold)
+		if err != nil {
+			return err
+		}
+		report.Keyspaces = append(report.Keyspaces, ksHealth)
+	}
+
+	issues := report.countIssues()
+	report.Healthy = issues == 0
+
+	data, err := cli.MarshalJSON(report)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", data)
+
+	if issues > 0 {
+		return fmt.Errorf("found %d issue(s) in the cluster; see the report for details", issues)
+	}
+	return nil
+}
+
+func (r *clusterHealthReport) countIssues() int {
+	count := 0
+	for _, ks := range r.Keyspaces {
+		count += len(ks.Issues)
+		for _, shard := range ks.Shards {
+			count += len(shard.Issues)
+		}
+	}
+	return count
+}
+
+func getKeyspaceHealth(ctx context.Context, keyspace string, lagThreshold time.Duration) (*keyspaceHealth, error) {
+	ksHealth := &keyspaceHealth{Name: keyspace}
+
+	shardsResp, err := client.FindAllShardsInKeyspace(ctx, &vtctldatapb.FindAllShardsInKeyspaceRequest{Keyspace: keyspace})
+	if err != nil {
+		return nil, err
+	}
+	tabletsResp, err := client.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{Keyspace: keyspace})
+	if err != nil {
+		return nil, err
+	}
+	tabletsByShard := make(map[string][]*topodatapb.Tablet)
+	for _, tablet := range tabletsResp.Tablets {
+		tabletsByShard[tablet.Shard] = append(tabletsByShard[tablet.Shard], tablet)
+	}
+
+	shardNames := make([]string, 0, len(shardsResp.Shards))
+	for name := range shardsResp.Shards {
+		shardNames = append(shardNames, name)
+	}
+	sort.Strings(shardNames)
+	for _, name := range shardNames {
+		tablets := tabletsByShard[name]
+		statuses, errs := getTabletFullStatuses(ctx, tablets)
+		ksHealth.Shards = append(ksHealth.Shards, buildShardHealth(shardsResp.Shards[name], tablets, statuses, errs, lagThreshold))
+	}
+
+	for _, status := range []vtctldatapb.SchemaMigration_Status{
+		vtctldatapb.SchemaMigration_QUEUED,
+		vtctldatapb.SchemaMigration_READY,
+		vtctldatapb.SchemaMigration_RUNNING,
+	} {
+		resp, err := client.GetSchemaMigrations(ctx, &vtctldatapb.GetSchemaMigrationsRequest{
+			Keyspace: keyspace,
+			Status:   status,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range resp.Migrations {
+			ksHealth.PendingMigrations = append(ksHealth.PendingMigrations, &pendingMigration{
+				UUID:     m.Uuid,
+				Shard:    m.Shard,
+				Table:    m.Table,
+				Status:   m.Status.String(),
+				Progress: m.Progress,
+			})
+		}
+	}
+
+	workflowsResp, err := client.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{
+		Keyspace:   keyspace,
+		ActiveOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, workflow := range workflowsResp.Workflows {
+		wfHealth := buildWorkflowHealth(workflow)
+		if wfHealth.ErroredStreamsCount > 0 {
+			ksHealth.Issues = append(ksHealth.Issues, fmt.Sprintf("workflow %s has %d stream(s) in error state", workflow.Name, wfHealth.ErroredStreamsCount))
+		}
+		ksHealth.Workflows = append(ksHealth.Workflows, wfHealth)
+	}
+
+	return ksHealth, nil
+}
+
+// getTabletFullStatuses fetches the full status of every given tablet
+// concurrently. The results are keyed by tablet alias string; tablets that
+// could not be reached are present in the error map instead.
+func getTabletFullStatuses(ctx context.Context, tablets []*topodatapb.Tablet) (map[string]*replicationdatapb.FullStatus, map[string]error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		statuses = make(map[string]*replicationdatapb.FullStatus, len(tablets))
+		errs     = make(map[string]error)
+	)
+	for _, tablet := range tablets {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+			alias := topoproto.TabletAliasString(tablet.Alias)
+			resp, err := client.GetFullStatus(ctx, &vtctldatapb.GetFullStatusRequest{TabletAlias: tablet.Alias})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[alias] = err
+				return
+			}
+			statuses[alias] = resp.Status
+		}(tablet)
+	}
+	wg.Wait()
+	return statuses, errs
+}
+
+// buildShardHealth assembles the health of a single shard from its topo
+// record, its tablet records, and the full status reported by each tablet.
+func buildShardHealth(shard *vtctldatapb.Shard, tablets []*topodatapb.Tablet, statuses map[string]*replicationdatapb.FullStatus, errs map[string]error, lagThreshold time.Duration) *shardHealth {
+	health := &shardHealth{Name: shard.Name}
+
+	var topoPrimary *topodatapb.Tablet
+	if shard.Shard.PrimaryAlias == nil {
+		health.Issues = append(health.Issues, "shard record has no primary")
+	} else {
+		health.PrimaryAlias = topoproto.TabletAliasString(shard.Shard.PrimaryAlias)
+	}
+
+	sort.Slice(tablets, func(i, j int) bool {
+		return topoproto.TabletAliasString(tablets[i].Alias) < topoproto.TabletAliasString(tablets[j].Alias)
+	})
+	for _, tablet := range tablets {
+		alias := topoproto.TabletAliasString(tablet.Alias)
+		th := &tabletHealth{
+			Alias: alias,
+			Type:  topoproto.TabletTypeLString(tablet.Type),
+		}
+		health.Tablets = append(health.Tablets, th)
+
+		isTopoPrimary := proto.Equal(tablet.Alias, shard.Shard.PrimaryAlias)
+		if isTopoPrimary {
+			topoPrimary = tablet
+		}
+		switch {
+		case tablet.Type == topodatapb.TabletType_PRIMARY && !isTopoPrimary:
+			health.Issues = append(health.Issues, fmt.Sprintf("tablet %s is of type PRIMARY, but the shard record has %s as primary", alias, health.PrimaryAlias))
+		case tablet.Type != topodatapb.TabletType_PRIMARY && isTopoPrimary:
+			health.Issues = append(health.Issues, fmt.Sprintf("shard primary %s has tablet type %s", alias, th.Type))
+		}
+
+		if err, ok := errs[alias]; ok {
+			th.Error = err.Error()
+			health.Issues = append(health.Issues, fmt.Sprintf("tablet %s is unreachable: %v", alias, err))
+			continue
+		}
+		status, ok := statuses[alias]
+		if !ok {
+			continue
+		}
+		th.Reachable = true
+		th.ReadOnly = status.ReadOnly
+		th.SemiSyncPrimaryEnabled = status.SemiSyncPrimaryEnabled
+		th.SemiSyncPrimaryStatus = status.SemiSyncPrimaryStatus
+		th.SemiSyncPrimaryClients = status.SemiSyncPrimaryClients
+		th.SemiSyncReplicaEnabled = status.SemiSyncReplicaEnabled
+		th.SemiSyncReplicaStatus = status.SemiSyncReplicaStatus
+
+		if tablet.Type == topodatapb.TabletType_PRIMARY {
+			if status.SemiSyncPrimaryEnabled && !status.SemiSyncPrimaryStatus {
+				health.Issues = append(health.Issues, fmt.Sprintf("primary %s has semi-sync enabled, but it is not active", alias))
+			}
+			continue
+		}
+
+		if !topo.IsReplicaType(tablet.Type) {
+			continue
+		}
+		if status.SemiSyncReplicaEnabled && !status.SemiSyncReplicaStatus {
+			health.Issues = append(health.Issues, fmt.Sprintf("replica %s has semi-sync enabled, but it is not active", alias))
+		}
+		rs := status.ReplicationStatus
+		if rs == nil {
+			health.Issues = append(health.Issues, fmt.Sprintf("replica %s is not replicating", alias))
+			continue
+		}
+		th.ReplicationRunning = replication.ReplicationState(rs.IoState) == replication.ReplicationStateRunning &&
+			replication.ReplicationState(rs.SqlState) == replication.ReplicationStateRunning
+		th.ReplicationLagSeconds = rs.ReplicationLagSeconds
+		th.ReplicationLagUnknown = rs.ReplicationLagUnknown
+		switch {
+		case !th.ReplicationRunning:
+			health.Issues = append(health.Issues, fmt.Sprintf("replica %s is not replicating", alias))
+		case rs.ReplicationLagUnknown:
+			health.Issues = append(health.Issues, fmt.Sprintf("replica %s has unknown replication lag", alias))
+		case lagThreshold > 0 && time.Duration(rs.ReplicationLagSeconds)*time.Second > lagThreshold:
+			health.Issues = append(health.Issues, fmt.Sprintf("replica %s is lagging by %ds", alias, rs.ReplicationLagSeconds))
+		}
+	}
+
+	if shard.Shard.PrimaryAlias != nil {
+		switch {
+		case topoPrimary == nil:
+			health.Issues = append(health.Issues, fmt.Sprintf("shard primary %s has no tablet record", health.PrimaryAlias))
+		case !proto.Equal(topoPrimary.PrimaryTermStartTime, shard.Shard.PrimaryTermStartTime):
+			health.Issues = append(health.Issues, fmt.Sprintf("primary term start time of tablet %s does not match the shard record", health.PrimaryAlias))
+		}
+	}
+
+	return health
+}
+
+func buildWorkflowHealth(workflow *vtctldatapb.Workflow) *workflowHealth {
+	health := &workflowHealth{
+		Name:               workflow.Name,
+		Type:               workflow.WorkflowType,
+		MaxVReplicationLag: workflow.MaxVReplicationLag,
+	}
+	for _, shardStream := range workflow.ShardStreams {
+		for _, stream := range shardStream.Streams {
+			if stream.State == binlogdatapb.VReplicationWorkflowState_Error.String() {
+				health.ErroredStreamsCount++
+			}
+		}
+	}
+	return health
+}
+
+func init() {
+	ClusterHealth.Flags().StringSliceVar(&clusterHealthOptions.Keyspaces, "keyspace", nil, "Keyspace(s) to report on. If not specified, all keyspaces are reported.")
+	ClusterHealth.Flags().DurationVar(&clusterHealthOptions.LagThreshold, "lag-threshold", 30*time.Second, "Replication lag above which a replica is reported as an issue. Set to zero to disable the lag check.")
+	Root.AddCommand(ClusterHealth)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/mysql/replication"
+
+	replicationdatapb "vitess.io/vitess/go/vt/proto/replicationdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	"vitess.io/vitess/go/vt/proto/vttime"
+)
+
+func TestBuildShardHealth(t *testing.T) {
+	primaryAlias := &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}
+	termStart := &vttime.Time{Seconds: 1000}
+	shard := &vtctldatapb.Shard{
+		Keyspace: "ks",
+		Name:     "-",
+		Shard: &topodatapb.Shard{
+			PrimaryAlias:         primaryAlias,
+			PrimaryTermStartTime: termStart,
+		},
+	}
+	primary := &topodatapb.Tablet{
+		Alias:                primaryAlias,
+		Type:                 topodatapb.TabletType_PRIMARY,
+		PrimaryTermStartTime: termStart,
+	}
+	replica := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+		Type:  topodatapb.TabletType_REPLICA,
+	}
+	rdonly := &topodatapb.Tablet{
+		Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+		Type:  topodatapb.TabletType_RDONLY,
+	}
+	replicating := func(lag uint32) *replicationdatapb.FullStatus {
+		return &replicationdatapb.FullStatus{
+			ReadOnly: true,
+			ReplicationStatus: &replicationdatapb.Status{
+				IoState:               int32(replication.ReplicationStateRunning),
+				SqlState:              int32(replication.ReplicationStateRunning),
+				ReplicationLagSeconds: lag,
+			},
+		}
+	}
+
+	tcases := []struct {
+		name     string
+		tablets  []*topodatapb.Tablet
+		statuses map[string]*replicationdatapb.FullStatus
+		errs     map[string]error
+		issues   []string
+	}{
+		{
+			name:    "healthy",
+			tablets: []*topodatapb.Tablet{primary, replica},
+			statuses: map[string]*replicationdatapb.FullStatus{
+				"zone1-0000000100": {},
+				"zone1-0000000101": replicating(1),
+			},
+		},
+		{
+			name:    "lagging and unreachable",
+			tablets: []*topodatapb.Tablet{primary, replica, rdonly},
+			statuses: map[string]*replicationdatapb.FullStatus{
+				"zone1-0000000100": {},
+				"zone1-0000000101": replicating(60),
+			},
+			errs: map[string]error{
+				"zone1-0000000102": errors.New("connection refused"),
+			},
+			issues: []string{
+				"replica zone1-0000000101 is lagging by 60s",
+				"tablet zone1-0000000102 is unreachable: connection refused",
+			},
+		},
+		{
+			name: "primary mismatch",
+			tablets: []*topodatapb.Tablet{
+				{Alias: primaryAlias, Type: topodatapb.TabletType_REPLICA},
+				{Alias: replica.Alias, Type: topodatapb.TabletType_PRIMARY},
+			},
+			statuses: map[string]*replicationdatapb.FullStatus{
+				"zone1-0000000100": replicating(0),
+				"zone1-0000000101": {},
+			},
+			issues: []string{
+				"shard primary zone1-0000000100 has tablet type replica",
+				"tablet zone1-0000000101 is of type PRIMARY, but the shard record has zone1-0000000100 as primary",
+				"primary term start time of tablet zone1-0000000100 does not match the shard record",
+			},
+		},
+		{
+			name:    "semi-sync inactive and replication stopped",
+			tablets: []*topodatapb.Tablet{primary, replica},
+			statuses: map[string]*replicationdatapb.FullStatus{
+				"zone1-0000000100": {SemiSyncPrimaryEnabled: true},
+				"zone1-0000000101": {
+					SemiSyncReplicaEnabled: true,
+					ReplicationStatus:      &replicationdatapb.Status{},
+				},
+			},
+			issues: []string{
+				"primary zone1-0000000100 has semi-sync enabled, but it is not active",
+				"replica zone1-0000000101 has semi-sync enabled, but it is not active",
+				"replica zone1-0000000101 is not replicating",
+			},
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			health := buildShardHealth(shard, tcase.tablets, tcase.statuses, tcase.errs, 30*time.Second)
+			assert.Equal(t, "zone1-0000000100", health.PrimaryAlias)
+			assert.Len(t, health.Tablets, len(tcase.tablets))
+			assert.Equal(t, tcase.issues, health.Issues)
+		})
+	}
+}
+
+func TestBuildWorkflowHealth(t *testing.T) {
+	health := buildWorkflowHealth(&vtctldatapb.Workflow{
+		Name:               "wf",
+		WorkflowType:       "MoveTables",
+		MaxVReplicationLag: 5,
+		ShardStreams: map[string]*vtctldatapb.Workflow_ShardStream{
+			"-80/zone1-0000000100": {Streams: []*vtctldatapb.Workflow_Stream{{State: "Running"}}},
+			"80-/zone1-0000000200": {Streams: []*vtctldatapb.Workflow_Stream{{State: "Error"}}},
+		},
+	})
+	assert.Equal(t, &workflowHealth{
+		Name:                "wf",
+		Type:                "MoveTables",
+		MaxVReplicationLag:  5,
+		ErroredStreamsCount: 1,
+	}, health)
+}
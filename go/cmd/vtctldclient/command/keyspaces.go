@@ -43,7 +43,10 @@ var (
 		Long: `Creates the specified keyspace in the topology.
 	
 For a SNAPSHOT keyspace, the request must specify the name of a base keyspace,
-as well as a snapshot time.`,
+as well as a snapshot time. Its tablets restore the base keyspace as of the
+snapshot time: with the incremental backups of the base keyspace when they
+cover it, which resolves the matching GTID position automatically, or else
+with the most recent full backup before it, caught up from a binlog server.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandCreateKeyspace,
@@ -109,6 +112,14 @@ SetKeyspaceDurabilityPolicy --durability-policy='semi_sync' customer`,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandSetKeyspaceDurabilityPolicy,
 	}
+	// ValidatePermissionsKeyspace makes a ValidatePermissionsKeyspace gRPC call to a vtctld.
+	ValidatePermissionsKeyspace = &cobra.Command{
+		Use:                   "ValidatePermissionsKeyspace <keyspace>",
+		Short:                 "Validates that the permissions on the primary tablet of shard 0 match all of the other tablets in the keyspace.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandValidatePermissionsKeyspace,
+	}
 	// ValidateSchemaKeyspace makes a ValidateSchemaKeyspace gRPC call to a vtctld.
 	ValidateSchemaKeyspace = &cobra.Command{
 		Use:                   "ValidateSchemaKeyspace [--exclude-tables=<exclude_tables>] [--include-views] [--skip-no-primary] [--include-vschema] <keyspace>",
@@ -350,6 +361,27 @@ func commandSetKeyspaceDurabilityPolicy(cmd *cobra.Command, args []string) error
 	return nil
 }
 
+func commandValidatePermissionsKeyspace(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	ks := cmd.Flags().Arg(0)
+	resp, err := client.ValidatePermissionsKeyspace(commandCtx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace: ks,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 var validateSchemaKeyspaceOptions = struct {
 	ExcludeTables  []string
 	IncludeViews   bool
@@ -428,6 +460,8 @@ func init() {
 	SetKeyspaceDurabilityPolicy.Flags().StringVar(&setKeyspaceDurabilityPolicyOptions.DurabilityPolicy, "durability-policy", "none", "Type of durability to enforce for this keyspace. Default is none. Other values include 'semi_sync' and others as dictated by registered plugins.")
 	Root.AddCommand(SetKeyspaceDurabilityPolicy)
 
+	Root.AddCommand(ValidatePermissionsKeyspace)
+
 	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.IncludeViews, "include-views", false, "Includes views in compared schemas.")
 	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.IncludeVSchema, "include-vschema", false, "Includes VSchema validation in validation results.")
 	ValidateSchemaKeyspace.Flags().BoolVar(&validateSchemaKeyspaceOptions.SkipNoPrimary, "skip-no-primary", false, "Skips validation on whether or not a primary exists in shards.")
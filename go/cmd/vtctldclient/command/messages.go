@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// RequeueDeadLetters makes a RequeueDeadLetters gRPC call to a vtctld.
+	RequeueDeadLetters = &cobra.Command{
+		Use:   "RequeueDeadLetters [--ids <id1,id2,...>] <keyspace> <table>",
+		Short: "Moves the dead letters of a message table back to it, so they are sent again.",
+		Long: `Moves the messages that were moved to the dead-letter table of a message table, after
+exceeding its vt_max_attempts, back to the message table on every shard of the keyspace.
+The requeued messages are sent again with a fresh attempt count.
+All the dead letters are requeued unless --ids is given.`,
+		Example:               "RequeueDeadLetters --ids 1,2,3 commerce order_events",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandRequeueDeadLetters,
+	}
+)
+
+var requeueDeadLettersOptions = struct {
+	IDs []string
+}{}
+
+func commandRequeueDeadLetters(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+	table := cmd.Flags().Arg(1)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RequeueDeadLetters(commandCtx, &vtctldatapb.RequeueDeadLettersRequest{
+		Keyspace: keyspace,
+		Table:    table,
+		Ids:      requeueDeadLettersOptions.IDs,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully requeued %d messages of %s.%s\n", resp.Count, keyspace, table)
+
+	return nil
+}
+
+func init() {
+	RequeueDeadLetters.Flags().StringSliceVar(&requeueDeadLettersOptions.IDs, "ids", nil, "Ids of the dead letters to requeue. All the dead letters are requeued if not specified.")
+	Root.AddCommand(RequeueDeadLetters)
+}
@@ -25,6 +25,7 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
@@ -55,6 +56,27 @@ var (
 		RunE:                  commandExecuteMultiFetchAsDBA,
 		Aliases:               []string{"ExecuteMultiFetchAsDba"},
 	}
+	// UpdateQueryFingerprintRules makes an UpdateQueryFingerprintRules gRPC call to a vtctld.
+	UpdateQueryFingerprintRules = &cobra.Command{
+		Use:   "UpdateQueryFingerprintRules [--pin <query> ... --plan-type <plan_type>] [--block <query> ... [--error-message <message>]] [--delete <query> ...] <keyspace/shard>",
+		Short: "Pins the queries of a fingerprint to a plan type, or blocks them, on every tablet of the shard.",
+		Long: `Pins the queries with the same fingerprint as the given queries to a plan type, or blocks
+them, on every tablet of the shard, and prints the resulting rules as JSON.
+
+The rules are stored in the sidecar database of the shard primary, and the other tablets
+of the shard reload them once they have replicated the update. A query pinned to a plan
+type fails if it cannot be executed with it: update and delete queries can be pinned to
+UpdateLimit and DeleteLimit, which fail them if they change more rows than the maximum
+result size. Blocked queries fail with the given error message.
+
+Without any of --pin, --block or --delete, the tablets reload the rules and the command
+prints them.`,
+		Example: `UpdateQueryFingerprintRules --block "select * from t where c like '%x'" --error-message "use the index on c" commerce/0
+UpdateQueryFingerprintRules --pin "delete from t where id < 100" --plan-type DeleteLimit commerce/0`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandUpdateQueryFingerprintRules,
+	}
 )
 
 var executeFetchAsAppOptions = struct {
@@ -198,6 +220,61 @@ func commandExecuteMultiFetchAsDBA(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var updateQueryFingerprintRulesOptions = struct {
+	Pin          []string
+	PlanType     string
+	Block        []string
+	ErrorMessage string
+	Delete       []string
+}{}
+
+func commandUpdateQueryFingerprintRules(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	opts := updateQueryFingerprintRulesOptions
+	if len(opts.Pin) > 0 && opts.PlanType == "" {
+		return fmt.Errorf("--plan-type is required with --pin")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	req := &vtctldatapb.UpdateQueryFingerprintRulesRequest{
+		Keyspace:      keyspace,
+		Shard:         shard,
+		DeleteQueries: opts.Delete,
+	}
+	for _, query := range opts.Pin {
+		req.SetRules = append(req.SetRules, &tabletmanagerdatapb.QueryFingerprintRule{
+			Fingerprint: query,
+			Action:      tabletmanagerdatapb.QueryFingerprintRule_PIN,
+			PlanType:    opts.PlanType,
+		})
+	}
+	for _, query := range opts.Block {
+		req.SetRules = append(req.SetRules, &tabletmanagerdatapb.QueryFingerprintRule{
+			Fingerprint:  query,
+			Action:       tabletmanagerdatapb.QueryFingerprintRule_BLOCK,
+			ErrorMessage: opts.ErrorMessage,
+		})
+	}
+
+	resp, err := client.UpdateQueryFingerprintRules(commandCtx, req)
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 func init() {
 	ExecuteFetchAsApp.Flags().Int64Var(&executeFetchAsAppOptions.MaxRows, "max-rows", 10_000, "The maximum number of rows to fetch from the remote tablet.")
 	ExecuteFetchAsApp.Flags().BoolVar(&executeFetchAsAppOptions.UsePool, "use-pool", false, "Use the tablet connection pool instead of creating a fresh connection.")
@@ -215,4 +292,11 @@ func init() {
 	ExecuteMultiFetchAsDBA.Flags().BoolVar(&executeMultiFetchAsDBAOptions.ReloadSchema, "reload-schema", false, "Instructs the tablet to reload its schema after executing the query.")
 	ExecuteMultiFetchAsDBA.Flags().BoolVarP(&executeMultiFetchAsDBAOptions.JSON, "json", "j", false, "Output the results in JSON instead of a human-readable table.")
 	Root.AddCommand(ExecuteMultiFetchAsDBA)
+
+	UpdateQueryFingerprintRules.Flags().StringArrayVar(&updateQueryFingerprintRulesOptions.Pin, "pin", nil, "Pin the queries with the same fingerprint as this query to --plan-type. May be repeated.")
+	UpdateQueryFingerprintRules.Flags().StringVar(&updateQueryFingerprintRulesOptions.PlanType, "plan-type", "", "The plan type to pin the queries to (e.g. UpdateLimit or DeleteLimit).")
+	UpdateQueryFingerprintRules.Flags().StringArrayVar(&updateQueryFingerprintRulesOptions.Block, "block", nil, "Block the queries with the same fingerprint as this query. May be repeated.")
+	UpdateQueryFingerprintRules.Flags().StringVar(&updateQueryFingerprintRulesOptions.ErrorMessage, "error-message", "", "The error message that the blocked queries fail with.")
+	UpdateQueryFingerprintRules.Flags().StringArrayVar(&updateQueryFingerprintRulesOptions.Delete, "delete", nil, "Delete the rule of the queries with the same fingerprint as this query. May be repeated.")
+	Root.AddCommand(UpdateQueryFingerprintRules)
 }
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/vt/vtgate/quota"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// ApplyQuotaRules makes an ApplyQuotaRules gRPC call to a vtctld.
+	ApplyQuotaRules = &cobra.Command{
+		Use:   "ApplyQuotaRules {--rules RULES | --rules-file RULES_FILE} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run]",
+		Short: "Applies the provided vtgate quota rules.",
+		Long: `Applies the provided vtgate quota rules.
+
+Each rule limits the rate (max_qps, burst) and the concurrency (max_concurrency)
+of the queries matching all of its user, keyspace and workload selectors. An
+empty selector matches every query, and all of them share the limits of the
+rule. A selector set to "*" also matches every query, but gives each distinct
+user, keyspace or workload its own limits.`,
+		Example:               `ApplyQuotaRules --rules '{"rules": [{"name": "per-user", "user": "*", "max_qps": 100, "max_concurrency": 10}]}'`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandApplyQuotaRules,
+	}
+	// GetQuotaRules makes a GetQuotaRules gRPC call to a vtctld.
+	GetQuotaRules = &cobra.Command{
+		Use:                   "GetQuotaRules",
+		Short:                 "Displays the vtgate quota rules as a JSON document.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetQuotaRules,
+	}
+)
+
+var applyQuotaRulesOptions = struct {
+	Rules         string
+	RulesFilePath string
+	Cells         []string
+	SkipRebuild   bool
+	DryRun        bool
+}{}
+
+func commandApplyQuotaRules(cmd *cobra.Command, args []string) error {
+	if applyQuotaRulesOptions.Rules != "" && applyQuotaRulesOptions.RulesFilePath != "" {
+		return fmt.Errorf("cannot pass both --rules (=%s) and --rules-file (=%s)", applyQuotaRulesOptions.Rules, applyQuotaRulesOptions.RulesFilePath)
+	}
+
+	if applyQuotaRulesOptions.Rules == "" && applyQuotaRulesOptions.RulesFilePath == "" {
+		return errors.New("must pass exactly one of --rules or --rules-file")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	var rulesBytes []byte
+	if applyQuotaRulesOptions.RulesFilePath != "" {
+		data, err := os.ReadFile(applyQuotaRulesOptions.RulesFilePath)
+		if err != nil {
+			return err
+		}
+
+		rulesBytes = data
+	} else {
+		rulesBytes = []byte(applyQuotaRulesOptions.Rules)
+	}
+
+	qr := &vschemapb.QuotaRules{}
+	if err := json2.Unmarshal(rulesBytes, &qr); err != nil {
+		return err
+	}
+	if err := quota.ValidateRules(qr); err != nil {
+		return err
+	}
+	// Round-trip so when we display the result it's readable.
+	data, err := cli.MarshalJSON(qr)
+	if err != nil {
+		return err
+	}
+
+	if applyQuotaRulesOptions.DryRun {
+		fmt.Printf("[DRY RUN] Would have saved new QuotaRules object:\n%s\n", data)
+
+		if applyQuotaRulesOptions.SkipRebuild {
+			fmt.Println("[DRY RUN] Would not have rebuilt VSchema graph, would have required operator to run RebuildVSchemaGraph for changes to take effect.")
+		} else {
+			fmt.Print("[DRY RUN] Would have rebuilt the VSchema graph")
+			if len(applyQuotaRulesOptions.Cells) == 0 {
+				fmt.Print(" in all cells\n")
+			} else {
+				fmt.Printf(" in the following cells: %s.\n", strings.Join(applyQuotaRulesOptions.Cells, ", "))
+			}
+		}
+
+		return nil
+	}
+
+	_, err = client.ApplyQuotaRules(commandCtx, &vtctldatapb.ApplyQuotaRulesRequest{
+		QuotaRules:   qr,
+		SkipRebuild:  applyQuotaRulesOptions.SkipRebuild,
+		RebuildCells: applyQuotaRulesOptions.Cells,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("New QuotaRules object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
+
+	if applyQuotaRulesOptions.SkipRebuild {
+		fmt.Println("Skipping rebuild of VSchema graph as requested, you will need to run RebuildVSchemaGraph for the changes to take effect.")
+	}
+
+	return nil
+}
+
+func commandGetQuotaRules(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetQuotaRules(commandCtx, &vtctldatapb.GetQuotaRulesRequest{})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.QuotaRules)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func init() {
+	ApplyQuotaRules.Flags().StringVarP(&applyQuotaRulesOptions.Rules, "rules", "r", "", "Quota rules, specified as a string")
+	ApplyQuotaRules.Flags().StringVarP(&applyQuotaRulesOptions.RulesFilePath, "rules-file", "f", "", "Path to a file containing quota rules specified as JSON")
+	ApplyQuotaRules.Flags().StringSliceVarP(&applyQuotaRulesOptions.Cells, "cells", "c", nil, "Limit the VSchema graph rebuilding to the specified cells. Ignored if --skip-rebuild is specified.")
+	ApplyQuotaRules.Flags().BoolVar(&applyQuotaRulesOptions.SkipRebuild, "skip-rebuild", false, "Skip rebuilding the SrvVSchema objects.")
+	ApplyQuotaRules.Flags().BoolVarP(&applyQuotaRulesOptions.DryRun, "dry-run", "d", false, "Validate the specified quota rules and note actions that would be taken, but do not actually apply the rules to the topo.")
+	Root.AddCommand(ApplyQuotaRules)
+
+	Root.AddCommand(GetQuotaRules)
+}
@@ -76,16 +76,35 @@ EmergencyReparentShard should be used instead.
 	// TabletExternallyReparented makes a TabletExternallyReparented gRPC call
 	// to a vtctld.
 	TabletExternallyReparented = &cobra.Command{
-		Use:   "TabletExternallyReparented <alias>",
+		Use:   "TabletExternallyReparented [--verify-fencing [--term-start-time <timestamp>] [--allow-unreachable-old-primary]] <alias>",
 		Short: "Updates the topology record for the tablet's shard to acknowledge that an external tool made this tablet the primary.",
 		Long: `Updates the topology record for the tablet's shard to acknowledge that an external tool made this tablet the primary.
 
+With --verify-fencing, the reparent is first rejected unless the old primary is read-only and has no transaction that
+the new primary misses, and the shard record is updated with the term start of the new primary before returning.
+The reparent is also rejected if the shard already records a later term start.
+
 See the Reparenting guide for more information: https://vitess.io/docs/user-guides/configuration-advanced/reparenting/#external-reparenting.
 `,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandTabletExternallyReparented,
 	}
+	// UpgradeShardMysql makes an UpgradeShardMysql gRPC call to a vtctld.
+	UpgradeShardMysql = &cobra.Command{
+		Use:   "UpgradeShardMysql [--new-primary <alias>] [--in-place] [--wait-replicas-timeout <duration>] <keyspace/shard>",
+		Short: "Upgrades MySQL on every tablet of the shard, replicas first, then reparents to an upgraded replica and upgrades the old primary.",
+		Long: `Upgrades MySQL on every tablet of the shard to the MySQL binaries installed on their hosts.
+
+Each replica is taken out of serving, has mysqld restarted with the new binaries and its data directory upgraded,
+catches up on replication, and is then returned to serving. Once all the replicas are upgraded, the shard is
+reparented to one of them with a PlannedReparentShard, and the old primary is upgraded like a replica.
+
+With --in-place, the primary is upgraded where it is instead of being reparented, at the cost of write downtime.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandUpgradeShardMysql,
+	}
 )
 
 var emergencyReparentShardOptions = struct {
@@ -258,19 +277,99 @@ func commandReparentTablet(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var tabletExternallyReparentedOptions = struct {
+	VerifyFencing              bool
+	TermStartTime              string
+	AllowUnreachableOldPrimary bool
+}{}
+
 func commandTabletExternallyReparented(cmd *cobra.Command, args []string) error {
 	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
 		return err
 	}
 
-	resp, err := client.TabletExternallyReparented(commandCtx, &vtctldatapb.TabletExternallyReparentedRequest{
-		Tablet: alias,
+	if !tabletExternallyReparentedOptions.VerifyFencing {
+		if cmd.Flags().Changed("term-start-time") || cmd.Flags().Changed("allow-unreachable-old-primary") {
+			return fmt.Errorf("--term-start-time and --allow-unreachable-old-primary require --verify-fencing")
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	var resp any
+	if tabletExternallyReparentedOptions.VerifyFencing {
+		termStartTime := time.Now()
+		if tabletExternallyReparentedOptions.TermStartTime != "" {
+			termStartTime, err = time.Parse(time.RFC3339, tabletExternallyReparentedOptions.TermStartTime)
+			if err != nil {
+				return fmt.Errorf("cannot parse --term-start-time as RFC3339: %w", err)
+			}
+		}
+
+		resp, err = client.TabletExternallyReparentedV2(commandCtx, &vtctldatapb.TabletExternallyReparentedV2Request{
+			Tablet:                     alias,
+			TermStartTime:              protoutil.TimeToProto(termStartTime),
+			AllowUnreachableOldPrimary: tabletExternallyReparentedOptions.AllowUnreachableOldPrimary,
+		})
+	} else {
+		resp, err = client.TabletExternallyReparented(commandCtx, &vtctldatapb.TabletExternallyReparentedRequest{
+			Tablet: alias,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+var upgradeShardMysqlOptions = struct {
+	NewPrimaryAliasStr  string
+	InPlace             bool
+	WaitReplicasTimeout time.Duration
+}{}
+
+func commandUpgradeShardMysql(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var newPrimaryAlias *topodatapb.TabletAlias
+	if upgradeShardMysqlOptions.NewPrimaryAliasStr != "" {
+		newPrimaryAlias, err = topoproto.ParseTabletAlias(upgradeShardMysqlOptions.NewPrimaryAliasStr)
+		if err != nil {
+			return err
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.UpgradeShardMysql(commandCtx, &vtctldatapb.UpgradeShardMysqlRequest{
+		Keyspace:            keyspace,
+		Shard:               shard,
+		NewPrimary:          newPrimaryAlias,
+		InPlace:             upgradeShardMysqlOptions.InPlace,
+		WaitReplicasTimeout: protoutil.DurationToProto(upgradeShardMysqlOptions.WaitReplicasTimeout),
 	})
 	if err != nil {
 		return err
 	}
 
+	for _, event := range resp.Events {
+		fmt.Println(logutil.EventString(event))
+	}
+
+	resp.Events = nil
+
 	data, err := cli.MarshalJSON(resp)
 	if err != nil {
 		return err
@@ -300,5 +399,13 @@ func init() {
 	Root.AddCommand(PlannedReparentShard)
 
 	Root.AddCommand(ReparentTablet)
+	TabletExternallyReparented.Flags().BoolVar(&tabletExternallyReparentedOptions.VerifyFencing, "verify-fencing", false, "Verify that the old primary is fenced, and update the shard record with the term start of the new primary before returning.")
+	TabletExternallyReparented.Flags().StringVar(&tabletExternallyReparentedOptions.TermStartTime, "term-start-time", "", "The time at which the external tool promoted the tablet, in RFC3339 format. Defaults to the current time. Requires --verify-fencing.")
+	TabletExternallyReparented.Flags().BoolVar(&tabletExternallyReparentedOptions.AllowUnreachableOldPrimary, "allow-unreachable-old-primary", false, "Record the reparent without verifying that the old primary is fenced when it cannot be reached. Requires --verify-fencing.")
 	Root.AddCommand(TabletExternallyReparented)
+
+	UpgradeShardMysql.Flags().StringVar(&upgradeShardMysqlOptions.NewPrimaryAliasStr, "new-primary", "", "Alias of the upgraded replica to promote. If not specified, the vtctld will select the best candidate to promote.")
+	UpgradeShardMysql.Flags().BoolVar(&upgradeShardMysqlOptions.InPlace, "in-place", false, "Upgrade the primary in place instead of reparenting the shard to an upgraded replica. Writes are unavailable while the primary is upgraded.")
+	UpgradeShardMysql.Flags().DurationVar(&upgradeShardMysqlOptions.WaitReplicasTimeout, "wait-replicas-timeout", topo.RemoteOperationTimeout, "Time to wait for each upgraded tablet to catch up on replication, and for replicas to catch up in reparenting.")
+	Root.AddCommand(UpgradeShardMysql)
 }
@@ -21,11 +21,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -34,8 +37,19 @@ import (
 var (
 	// ApplyRoutingRules makes an ApplyRoutingRules gRPC call to a vtctld.
 	ApplyRoutingRules = &cobra.Command{
-		Use:                   "ApplyRoutingRules {--rules RULES | --rules-file RULES_FILE} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run]",
-		Short:                 "Applies the VSchema routing rules.",
+		Use:   "ApplyRoutingRules {--rules RULES | --rules-file RULES_FILE} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run]",
+		Short: "Applies the VSchema routing rules.",
+		Long: `Applies the VSchema routing rules.
+
+A rule can move its table to a target table, typically the copy of the table in the
+target keyspace of a MoveTables workflow, to cut over one table at a time:
+
+  {"from_table": "t1", "to_tables": ["source.t1"], "target_table": "target.t1",
+   "target_read_percent": 10, "cutover_time": {"seconds": "1735689600"}}
+
+Until its cutover_time, target_read_percent percent of the reads of the table are
+routed to target_table, and the other queries to to_tables. From cutover_time on,
+all the queries are routed to target_table.`,
 		DisableFlagsInUseLine: true,
 		Args:                  cobra.NoArgs,
 		RunE:                  commandApplyRoutingRules,
@@ -85,6 +99,9 @@ func commandApplyRoutingRules(cmd *cobra.Command, args []string) error {
 	if err := json2.Unmarshal(rulesBytes, &rr); err != nil {
 		return err
 	}
+	if err := vindexes.ValidateRoutingRules(rr); err != nil {
+		return err
+	}
 
 	// Round-trip so when we display the result it's readable.
 	data, err := cli.MarshalJSON(rr)
@@ -94,6 +111,7 @@ func commandApplyRoutingRules(cmd *cobra.Command, args []string) error {
 
 	if applyRoutingRulesOptions.DryRun {
 		fmt.Printf("[DRY RUN] Would have saved new RoutingRules object:\n%s\n", data)
+		printTargetRoutingRules(rr)
 
 		if applyRoutingRulesOptions.SkipRebuild {
 			fmt.Println("[DRY RUN] Would not have rebuilt VSchema graph, would have required operator to run RebuildVSchemaGraph for changes to take effect")
@@ -119,6 +137,7 @@ func commandApplyRoutingRules(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("New RoutingRules object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
+	printTargetRoutingRules(rr)
 
 	if applyRoutingRulesOptions.SkipRebuild {
 		fmt.Println("Skipping rebuild of VSchema graph, will need to run RebuildVSchemaGraph for changes to take effect.")
@@ -127,6 +146,21 @@ func commandApplyRoutingRules(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printTargetRoutingRules describes how the rules that move their table to a
+// target table route it.
+func printTargetRoutingRules(rr *vschemapb.RoutingRules) {
+	for _, rule := range rr.Rules {
+		if rule.TargetTable == "" {
+			continue
+		}
+		fmt.Printf("Table %s: %d%% of the reads are routed to %s", rule.FromTable, rule.TargetReadPercent, rule.TargetTable)
+		if rule.CutoverTime != nil {
+			fmt.Printf(", and all the queries from %s on", protoutil.TimeFromProto(rule.CutoverTime).UTC().Format(time.RFC3339))
+		}
+		fmt.Println(".")
+	}
+}
+
 func commandGetRoutingRules(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
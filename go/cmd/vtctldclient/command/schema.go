@@ -58,6 +58,18 @@ For --sql, semi-colons and repeated values may be mixed, for example:
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandApplySchema,
 	}
+	// CopySchemaShard makes a CopySchemaShard gRPC call to a vtctld.
+	CopySchemaShard = &cobra.Command{
+		Use:   "CopySchemaShard [--tables <tables>] [--exclude-tables <tables>] [--include-views] [--skip-verify] [--wait-replicas-timeout <duration>] {<source_tablet_alias> | <source_keyspace/shard>} <destination_keyspace/shard>",
+		Short: "Copies the schema from a source shard's primary (or a specific tablet) to a destination shard.",
+		Long: `Copies the schema from a source shard's primary (or a specific tablet) to a destination shard.
+
+The schema is applied directly on the destination primary and propagated to its replicas via replication.
+If the destination already has tables with the same names, the command fails unless their schemas match.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandCopySchemaShard,
+	}
 	// GetSchema makes a GetSchema gRPC call to a vtctld.
 	GetSchema = &cobra.Command{
 		Use:                   "GetSchema [--tables TABLES ...] [--exclude-tables EXCLUDE_TABLES ...] [{--table-names-only | --table-sizes-only}] [--include-views] alias",
@@ -90,6 +102,22 @@ For --sql, semi-colons and repeated values may be mixed, for example:
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandReloadSchemaShard,
 	}
+	// SchemaDiff makes a SchemaDiff gRPC call to a vtctld.
+	SchemaDiff = &cobra.Command{
+		Use:   "SchemaDiff [--schema-file <file>] [--tables <tables>] [--exclude-tables <tables>] [--include-views] [--skip-no-primary] <keyspace>",
+		Short: "Diffs the schemas of the shards of a keyspace, against each other or against a desired schema.",
+		Long: `Diffs the schemas of the shards of a keyspace, against each other or against a desired schema.
+
+The schema of every shard primary is diffed against the desired schema in --schema-file, a file of CREATE TABLE and CREATE VIEW statements.
+Without --schema-file, the schemas are diffed against the schema of the first shard of the keyspace.
+The differences (missing and extra tables and views, column, index and constraint differences) are printed as JSON,
+along with the DDL that would bring each shard to the reference schema.
+
+The command exits with a nonzero status if any difference is found, so that it can be used in CI.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandSchemaDiff,
+	}
 )
 
 var applySchemaOptions = struct {
@@ -103,6 +131,7 @@ var applySchemaOptions = struct {
 	SkipPreflight           bool
 	CallerID                string
 	BatchSize               int64
+	DryRun                  bool
 }{}
 
 func commandApplySchema(cmd *cobra.Command, args []string) error {
@@ -122,12 +151,18 @@ func commandApplySchema(cmd *cobra.Command, args []string) error {
 		allSQL = strings.Join(applySchemaOptions.SQL, ";")
 	}
 
-	parts, err := env.Parser().SplitStatementToPieces(allSQL)
+	cli.FinishedParsing(cmd)
+
+	// All the statements that fail to parse are reported at once, with their
+	// positions, rather than the first one that the server rejects.
+	statements, err := env.Parser().ParseStatementsWithRecovery(allSQL)
 	if err != nil {
 		return err
 	}
-
-	cli.FinishedParsing(cmd)
+	parts := make([]string, 0, len(statements))
+	for _, stmt := range statements {
+		parts = append(parts, stmt.SQL)
+	}
 
 	var cid *vtrpc.CallerID
 	if applySchemaOptions.CallerID != "" {
@@ -145,15 +180,60 @@ func commandApplySchema(cmd *cobra.Command, args []string) error {
 		WaitReplicasTimeout: protoutil.DurationToProto(applySchemaOptions.WaitReplicasTimeout),
 		CallerId:            cid,
 		BatchSize:           applySchemaOptions.BatchSize,
+		DryRun:              applySchemaOptions.DryRun,
 	})
 	if err != nil {
 		return err
 	}
 
+	if applySchemaOptions.DryRun {
+		fmt.Println(strings.Join(resp.DryRunResults, "\n"))
+		return nil
+	}
 	fmt.Println(strings.Join(resp.UuidList, "\n"))
 	return nil
 }
 
+var copySchemaShardOptions = struct {
+	Tables              []string
+	ExcludeTables       []string
+	IncludeViews        bool
+	SkipVerify          bool
+	WaitReplicasTimeout time.Duration
+}{}
+
+func commandCopySchemaShard(cmd *cobra.Command, args []string) error {
+	req := &vtctldatapb.CopySchemaShardRequest{
+		Tables:              copySchemaShardOptions.Tables,
+		ExcludeTables:       copySchemaShardOptions.ExcludeTables,
+		IncludeViews:        copySchemaShardOptions.IncludeViews,
+		SkipVerify:          copySchemaShardOptions.SkipVerify,
+		WaitReplicasTimeout: protoutil.DurationToProto(copySchemaShardOptions.WaitReplicasTimeout),
+	}
+
+	source := cmd.Flags().Arg(0)
+	if alias, err := topoproto.ParseTabletAlias(source); err == nil {
+		req.SourceTabletAlias = alias
+	} else {
+		keyspace, shard, err := topoproto.ParseKeyspaceShard(source)
+		if err != nil {
+			return fmt.Errorf("source must be a tablet alias or a keyspace/shard: %s", source)
+		}
+		req.SourceKeyspace, req.SourceShard = keyspace, shard
+	}
+
+	var err error
+	req.DestinationKeyspace, req.DestinationShard, err = topoproto.ParseKeyspaceShard(cmd.Flags().Arg(1))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	_, err = client.CopySchemaShard(commandCtx, req)
+	return err
+}
+
 var getSchemaOptions = struct {
 	Tables          []string
 	ExcludeTables   []string
@@ -284,6 +364,53 @@ func commandReloadSchemaShard(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+var schemaDiffOptions = struct {
+	SchemaFile    string
+	Tables        []string
+	ExcludeTables []string
+	IncludeViews  bool
+	SkipNoPrimary bool
+}{}
+
+func commandSchemaDiff(cmd *cobra.Command, args []string) error {
+	var desiredSchema string
+	if schemaDiffOptions.SchemaFile != "" {
+		data, err := os.ReadFile(schemaDiffOptions.SchemaFile)
+		if err != nil {
+			return err
+		}
+
+		desiredSchema = string(data)
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.SchemaDiff(commandCtx, &vtctldatapb.SchemaDiffRequest{
+		Keyspace:      cmd.Flags().Arg(0),
+		DesiredSchema: desiredSchema,
+		Tables:        schemaDiffOptions.Tables,
+		ExcludeTables: schemaDiffOptions.ExcludeTables,
+		IncludeViews:  schemaDiffOptions.IncludeViews,
+		SkipNoPrimary: schemaDiffOptions.SkipNoPrimary,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	if len(resp.Differences) > 0 {
+		return fmt.Errorf("found %d schema differences against %s; see above for details", len(resp.Differences), resp.Reference)
+	}
+
+	return nil
+}
+
 func init() {
 	ApplySchema.Flags().StringVar(&applySchemaOptions.DDLStrategy, "ddl-strategy", string(schema.DDLStrategyDirect), "Online DDL strategy, compatible with @@ddl_strategy session variable (examples: 'gh-ost', 'pt-osc', 'gh-ost --max-load=Threads_running=100'.")
 	ApplySchema.Flags().StringSliceVar(&applySchemaOptions.UUIDList, "uuid", nil, "Optional, comma-delimited, repeatable, explicit UUIDs for migration. If given, must match number of DDL changes.")
@@ -293,9 +420,17 @@ func init() {
 	ApplySchema.Flags().StringArrayVar(&applySchemaOptions.SQL, "sql", nil, "Semicolon-delimited, repeatable SQL commands to apply. Exactly one of --sql|--sql-file is required.")
 	ApplySchema.Flags().StringVar(&applySchemaOptions.SQLFile, "sql-file", "", "Path to a file containing semicolon-delimited SQL commands to apply. Exactly one of --sql|--sql-file is required.")
 	ApplySchema.Flags().Int64Var(&applySchemaOptions.BatchSize, "batch-size", 0, "How many queries to batch together. Only applicable when all queries are CREATE TABLE|VIEW")
+	ApplySchema.Flags().BoolVar(&applySchemaOptions.DryRun, "dry-run", false, "For declarative migrations, print the schema changes that would be applied without submitting any migration. The changes are listed per shard when the schemas of the shards differ.")
 
 	Root.AddCommand(ApplySchema)
 
+	CopySchemaShard.Flags().StringSliceVar(&copySchemaShardOptions.Tables, "tables", nil, "List of tables to copy. Each is either an exact match, or a regular expression of the form `/regexp/`.")
+	CopySchemaShard.Flags().StringSliceVar(&copySchemaShardOptions.ExcludeTables, "exclude-tables", nil, "List of tables to exclude from the copy. Each is either an exact match, or a regular expression of the form `/regexp/`.")
+	CopySchemaShard.Flags().BoolVar(&copySchemaShardOptions.IncludeViews, "include-views", true, "Also copy views.")
+	CopySchemaShard.Flags().BoolVar(&copySchemaShardOptions.SkipVerify, "skip-verify", false, "Skip verifying that the destination schema matches the source after the copy.")
+	CopySchemaShard.Flags().DurationVar(&copySchemaShardOptions.WaitReplicasTimeout, "wait-replicas-timeout", grpcvtctldserver.DefaultWaitReplicasTimeout, "Amount of time to wait for replicas to receive the schema change via replication.")
+	Root.AddCommand(CopySchemaShard)
+
 	GetSchema.Flags().StringSliceVar(&getSchemaOptions.Tables, "tables", nil, "List of tables to display the schema for. Each is either an exact match, or a regular expression of the form `/regexp/`.")
 	GetSchema.Flags().StringSliceVar(&getSchemaOptions.ExcludeTables, "exclude-tables", nil, "List of tables to exclude from the result. Each is either an exact match, or a regular expression of the form `/regexp/`.")
 	GetSchema.Flags().BoolVar(&getSchemaOptions.IncludeViews, "include-views", false, "Includes views in the output in addition to base tables.")
@@ -314,4 +449,11 @@ func init() {
 	ReloadSchemaShard.Flags().Int32Var(&reloadSchemaShardOptions.Concurrency, "concurrency", 10, "Number of tablets to reload in parallel. Set to zero for unbounded concurrency.")
 	ReloadSchemaShard.Flags().BoolVar(&reloadSchemaShardOptions.IncludePrimary, "include-primary", false, "Also reload the primary tablet.")
 	Root.AddCommand(ReloadSchemaShard)
+
+	SchemaDiff.Flags().StringVar(&schemaDiffOptions.SchemaFile, "schema-file", "", "Path to a file with the CREATE statements of the desired schema. If not set, the shards are diffed against the first shard.")
+	SchemaDiff.Flags().StringSliceVar(&schemaDiffOptions.Tables, "tables", nil, "List of tables to diff. Each is either an exact match, or a regular expression of the form `/regexp/`.")
+	SchemaDiff.Flags().StringSliceVar(&schemaDiffOptions.ExcludeTables, "exclude-tables", nil, "List of tables to exclude from the diff. Each is either an exact match, or a regular expression of the form `/regexp/`.")
+	SchemaDiff.Flags().BoolVar(&schemaDiffOptions.IncludeViews, "include-views", false, "Also diff views.")
+	SchemaDiff.Flags().BoolVar(&schemaDiffOptions.SkipNoPrimary, "skip-no-primary", false, "Skip the shards that have no primary instead of failing.")
+	Root.AddCommand(SchemaDiff)
 }
@@ -19,13 +19,16 @@ package command
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -101,6 +104,24 @@ that shard.`,
 		Args:                  cobra.MinimumNArgs(1),
 		RunE:                  commandGetShardReplication,
 	}
+	// PlanReshard makes a PlanReshard gRPC request to a vtctld.
+	PlanReshard = &cobra.Command{
+		Use:   "PlanReshard [--max-shard-size-bytes <bytes>] [--max-shard-qps <qps>] [--merge-threshold <fraction>] <keyspace>",
+		Short: "Proposes the shard splits and merges that keep the size and the QPS of the shards of a keyspace within limits.",
+		Long: `Proposes the shard splits and merges that keep the size and the QPS of the shards of a keyspace within limits.
+
+The size of a shard is the data length of the tables of its primary, and its QPS is the
+sum of the QPS of its serving tablets. A shard above one of the limits is split evenly
+into as many shards as needed, and adjacent shards that stay under the merge threshold
+together are merged. The estimated size and QPS of the new shards assume that the rows
+and the queries of a shard are spread evenly over its key range.
+
+Each proposal comes with the request of its Reshard workflow, which can be created once
+the tablets of its target shards are up.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandPlanReshard,
+	}
 	// RemoveShardCell makes a RemoveShardCell gRPC request to a vtctld.
 	RemoveShardCell = &cobra.Command{
 		Use:                   "RemoveShardCell [--force|-f] [--recursive|-r] <keyspace/shard> <cell>",
@@ -194,7 +215,30 @@ Use ctrl-C to interrupt the command and see partial results if needed.`,
 		Args:                  cobra.ExactArgs(2),
 		RunE:                  commandSourceShardDelete,
 	}
-
+	// UpdateSrvKeyspacePartitions makes an UpdateSrvKeyspacePartitions gRPC request to a vtctld.
+	UpdateSrvKeyspacePartitions = &cobra.Command{
+		Use:                   "UpdateSrvKeyspacePartitions [--cells <cell1,cell2,...>] [--remove] <keyspace/shard> <tablet_type>",
+		Short:                 "Adds the shard to, or removes it from, the partition of the tablet type in the SrvKeyspace. Only use this for emergency fixes.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandUpdateSrvKeyspacePartitions,
+	}
+	// ValidatePermissionsShard makes a ValidatePermissionsShard gRPC request to a vtctld.
+	ValidatePermissionsShard = &cobra.Command{
+		Use:                   "ValidatePermissionsShard <keyspace/shard>",
+		Short:                 "Validates that the permissions on the primary match all of the replicas.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandValidatePermissionsShard,
+	}
+	// ValidateSchemaShard makes a ValidateSchemaShard gRPC request to a vtctld.
+	ValidateSchemaShard = &cobra.Command{
+		Use:                   "ValidateSchemaShard [--exclude-tables=<exclude_tables>] [--include-views] [--include-vschema] <keyspace/shard>",
+		Short:                 "Validates that the schema on the primary matches all of the replicas.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandValidateSchemaShard,
+	}
 	// ValidateVersionShard makes a ValidateVersionShard gRPC request to a vtctld.
 	ValidateVersionShard = &cobra.Command{
 		Use:                   "ValidateVersionShard <keyspace/shard>",
@@ -203,6 +247,14 @@ Use ctrl-C to interrupt the command and see partial results if needed.`,
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandValidateVersionShard,
 	}
+	// WaitForFilteredReplication makes a WaitForFilteredReplication gRPC request to a vtctld.
+	WaitForFilteredReplication = &cobra.Command{
+		Use:                   "WaitForFilteredReplication [--max-delay <duration>] <keyspace/shard>",
+		Short:                 "Blocks until the primary of the shard has caught up with the filtered replication of its source shards.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandWaitForFilteredReplication,
+	}
 )
 
 var createShardOptions = struct {
@@ -324,6 +376,35 @@ func commandGetShardReplication(cmd *cobra.Command, args []string) error {
 
 }
 
+var planReshardOptions = struct {
+	MaxShardSizeBytes uint64
+	MaxShardQPS       float64
+	MergeThreshold    float64
+}{}
+
+func commandPlanReshard(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.PlanReshard(commandCtx, &vtctldatapb.PlanReshardRequest{
+		Keyspace:          cmd.Flags().Arg(0),
+		MaxShardSizeBytes: planReshardOptions.MaxShardSizeBytes,
+		MaxShardQps:       planReshardOptions.MaxShardQPS,
+		MergeThreshold:    planReshardOptions.MergeThreshold,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 var removeShardCellOptions = struct {
 	Force     bool
 	Recursive bool
@@ -626,6 +707,93 @@ func commandSourceShardDelete(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var updateSrvKeyspacePartitionsOptions = struct {
+	Cells  []string
+	Remove bool
+}{}
+
+func commandUpdateSrvKeyspacePartitions(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	tabletType, err := topoproto.ParseTabletType(cmd.Flags().Arg(1))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	_, err = client.UpdateSrvKeyspacePartitions(commandCtx, &vtctldatapb.UpdateSrvKeyspacePartitionsRequest{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: tabletType,
+		Cells:      updateSrvKeyspacePartitionsOptions.Cells,
+		Remove:     updateSrvKeyspacePartitionsOptions.Remove,
+	})
+	return err
+}
+
+func commandValidatePermissionsShard(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ValidatePermissionsShard(commandCtx, &vtctldatapb.ValidatePermissionsShardRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+var validateSchemaShardOptions = struct {
+	ExcludeTables  []string
+	IncludeViews   bool
+	IncludeVSchema bool
+}{}
+
+func commandValidateSchemaShard(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ValidateSchemaShard(commandCtx, &vtctldatapb.ValidateSchemaShardRequest{
+		Keyspace:       keyspace,
+		Shard:          shard,
+		ExcludeTables:  validateSchemaShardOptions.ExcludeTables,
+		IncludeViews:   validateSchemaShardOptions.IncludeViews,
+		IncludeVschema: validateSchemaShardOptions.IncludeVSchema,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 func commandValidateVersionShard(cmd *cobra.Command, args []string) error {
 	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
 	if err != nil {
@@ -651,6 +819,36 @@ func commandValidateVersionShard(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var waitForFilteredReplicationOptions = struct {
+	MaxDelay time.Duration
+}{}
+
+func commandWaitForFilteredReplication(cmd *cobra.Command, args []string) error {
+	keyspace, shard, err := topoproto.ParseKeyspaceShard(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.WaitForFilteredReplication(commandCtx, &vtctldatapb.WaitForFilteredReplicationRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+		MaxDelay: protoutil.DurationToProto(waitForFilteredReplicationOptions.MaxDelay),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
 func init() {
 	CreateShard.Flags().BoolVarP(&createShardOptions.Force, "force", "f", false, "Overwrite an existing shard record, if one exists.")
 	CreateShard.Flags().BoolVarP(&createShardOptions.IncludeParent, "include-parent", "p", false, "Creates the parent keyspace record if does not already exist.")
@@ -665,6 +863,11 @@ func init() {
 	Root.AddCommand(GetShardReplication)
 	Root.AddCommand(GenerateShardRanges)
 
+	PlanReshard.Flags().Uint64Var(&planReshardOptions.MaxShardSizeBytes, "max-shard-size-bytes", 0, "Data size in bytes above which a shard is split. 0 does not limit the size of the shards.")
+	PlanReshard.Flags().Float64Var(&planReshardOptions.MaxShardQPS, "max-shard-qps", 0, "QPS, summed over the serving tablets of a shard, above which the shard is split. 0 does not limit the QPS of the shards.")
+	PlanReshard.Flags().Float64Var(&planReshardOptions.MergeThreshold, "merge-threshold", 0, "Fraction of the limits under which adjacent shards must stay together to be merged. 0 disables merges.")
+	Root.AddCommand(PlanReshard)
+
 	RemoveShardCell.Flags().BoolVarP(&removeShardCellOptions.Force, "force", "f", false, "Proceed even if the cell's topology server cannot be reached. The assumption is that you turned down the entire cell, and just need to update the global topo data.")
 	RemoveShardCell.Flags().BoolVarP(&removeShardCellOptions.Recursive, "recursive", "r", false, "Also delete all tablets in that cell beloning to the specified shard.")
 	Root.AddCommand(RemoveShardCell)
@@ -681,8 +884,22 @@ func init() {
 	Root.AddCommand(ShardReplicationFix)
 	Root.AddCommand(ShardReplicationPositions)
 	Root.AddCommand(ShardReplicationRemove)
+	UpdateSrvKeyspacePartitions.Flags().StringSliceVarP(&updateSrvKeyspacePartitionsOptions.Cells, "cells", "c", nil, "Specifies a comma-separated list of cells to update. Updates all cells if empty.")
+	UpdateSrvKeyspacePartitions.Flags().BoolVar(&updateSrvKeyspacePartitionsOptions.Remove, "remove", false, "Removes the shard from the partition, instead of adding it.")
+	Root.AddCommand(UpdateSrvKeyspacePartitions)
+
+	Root.AddCommand(ValidatePermissionsShard)
+
+	ValidateSchemaShard.Flags().BoolVar(&validateSchemaShardOptions.IncludeViews, "include-views", false, "Includes views in compared schemas.")
+	ValidateSchemaShard.Flags().BoolVar(&validateSchemaShardOptions.IncludeVSchema, "include-vschema", false, "Includes VSchema validation in validation results.")
+	ValidateSchemaShard.Flags().StringSliceVar(&validateSchemaShardOptions.ExcludeTables, "exclude-tables", []string{}, "Tables to exclude during schema comparison.")
+	Root.AddCommand(ValidateSchemaShard)
+
 	Root.AddCommand(ValidateVersionShard)
 
+	WaitForFilteredReplication.Flags().DurationVar(&waitForFilteredReplicationOptions.MaxDelay, "max-delay", grpcvtctldserver.DefaultWaitForFilteredReplicationMaxDelay, "Filtered replication lag of the shard primary to wait for.")
+	Root.AddCommand(WaitForFilteredReplication)
+
 	SourceShardAdd.Flags().StringVar(&sourceShardAddOptions.KeyRangeStr, "key-range", "", "Key range to use for the SourceShard.")
 	SourceShardAdd.Flags().StringSliceVar(&sourceShardAddOptions.Tables, "tables", nil, "Comma-separated lists of tables to replicate (for MoveTables). Each table name is either an exact match, or a regular expression of the form \"/regexp/\".")
 	Root.AddCommand(SourceShardAdd)
@@ -132,6 +132,21 @@ Valid output formats are "awk" and "json".`, strings.Join(topoproto.MakeUniqueSt
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandGetTabletVersion,
 	}
+	// KillQueries makes a KillQueries gRPC call to a vtctld.
+	KillQueries = &cobra.Command{
+		Use:   "KillQueries [--query <query>] [--plan-type <plan_type>] [--caller <caller>] [--dry-run] <tablet_alias>",
+		Short: "Kills the queries running on the tablet that match a query fingerprint, a plan type or a caller.",
+		Long: `Kills the queries running on the tablet that match all of the given filters, and prints them as JSON.
+
+The --query filter matches queries with the same fingerprint, i.e. the same normalized
+query with all literals replaced by placeholders. The --caller filter matches either the
+effective caller principal or the immediate caller username. At least one filter is required.
+
+With --dry-run, the matching queries are printed but not killed.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandKillQueries,
+	}
 	// PingTablet makes a PingTablet gRPC call to a vtctld.
 	PingTablet = &cobra.Command{
 		Use:                   "PingTablet <alias>",
@@ -156,6 +171,20 @@ Valid output formats are "awk" and "json".`, strings.Join(topoproto.MakeUniqueSt
 		Args:                  cobra.ExactArgs(1),
 		RunE:                  commandRefreshStateByShard,
 	}
+	// RunDiagnosticQuery makes a RunDiagnosticQuery gRPC call to a vtctld.
+	RunDiagnosticQuery = &cobra.Command{
+		Use:   "RunDiagnosticQuery [--include-query-text] <tablet_alias> {innodb_status|replication_status|processlist}",
+		Short: "Runs one of the allow-listed, read-only diagnostic queries on the MySQL of the remote tablet.",
+		Long: `Runs one of the allow-listed, read-only diagnostic queries on the MySQL of the remote tablet, and prints its result as JSON.
+
+The queries are:
+  - innodb_status: the output of SHOW ENGINE INNODB STATUS, split into its sections.
+  - replication_status: the replication status of the tablet.
+  - processlist: the MySQL processlist. The text of the queries is only included with --include-query-text.`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(2),
+		RunE:                  commandRunDiagnosticQuery,
+	}
 	// RunHealthCheck makes a RunHealthCheck gRPC call to a vtctld.
 	RunHealthCheck = &cobra.Command{
 		Use:                   "RunHealthCheck <tablet_alias>",
@@ -478,6 +507,46 @@ func commandGetTabletVersion(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var killQueriesOptions = struct {
+	Query    string
+	PlanType string
+	Caller   string
+	DryRun   bool
+}{}
+
+func commandKillQueries(cmd *cobra.Command, args []string) error {
+	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if killQueriesOptions.Query == "" && killQueriesOptions.PlanType == "" && killQueriesOptions.Caller == "" {
+		return fmt.Errorf("at least one of --query, --plan-type or --caller must be specified")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.KillQueries(commandCtx, &vtctldatapb.KillQueriesRequest{
+		TabletAlias: alias,
+		Query:       killQueriesOptions.Query,
+		PlanType:    killQueriesOptions.PlanType,
+		Caller:      killQueriesOptions.Caller,
+		DryRun:      killQueriesOptions.DryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 func commandPingTablet(cmd *cobra.Command, args []string) error {
 	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
@@ -546,6 +615,42 @@ func commandRefreshStateByShard(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+var runDiagnosticQueryOptions = struct {
+	IncludeQueryText bool
+}{}
+
+func commandRunDiagnosticQuery(cmd *cobra.Command, args []string) error {
+	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
+	if err != nil {
+		return err
+	}
+
+	query, ok := tabletmanagerdatapb.RunDiagnosticQueryRequest_Query_value[strings.ToUpper(cmd.Flags().Arg(1))]
+	if !ok || query == int32(tabletmanagerdatapb.RunDiagnosticQueryRequest_UNKNOWN) {
+		return fmt.Errorf("invalid diagnostic query %s, expected one of innodb_status, replication_status or processlist", cmd.Flags().Arg(1))
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.RunDiagnosticQuery(commandCtx, &vtctldatapb.RunDiagnosticQueryRequest{
+		TabletAlias:      alias,
+		Query:            tabletmanagerdatapb.RunDiagnosticQueryRequest_Query(query),
+		IncludeQueryText: runDiagnosticQueryOptions.IncludeQueryText,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Result)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 func commandRunHealthCheck(cmd *cobra.Command, args []string) error {
 	alias, err := topoproto.ParseTabletAlias(cmd.Flags().Arg(0))
 	if err != nil {
@@ -650,12 +755,21 @@ func init() {
 	Root.AddCommand(GetTablets)
 
 	Root.AddCommand(GetTabletVersion)
+	KillQueries.Flags().StringVar(&killQueriesOptions.Query, "query", "", "Kill the queries with the same fingerprint as this query.")
+	KillQueries.Flags().StringVar(&killQueriesOptions.PlanType, "plan-type", "", "Kill the queries with this plan type (e.g. Select or Update).")
+	KillQueries.Flags().StringVar(&killQueriesOptions.Caller, "caller", "", "Kill the queries of this caller.")
+	KillQueries.Flags().BoolVar(&killQueriesOptions.DryRun, "dry-run", false, "Print the matching queries without killing them.")
+	Root.AddCommand(KillQueries)
+
 	Root.AddCommand(PingTablet)
 	Root.AddCommand(RefreshState)
 
 	RefreshStateByShard.Flags().StringSliceVarP(&refreshStateByShardOptions.Cells, "cells", "c", nil, "If specified, only call RefreshState on tablets in the specified cells. If empty, all cells are considered.")
 	Root.AddCommand(RefreshStateByShard)
 
+	RunDiagnosticQuery.Flags().BoolVar(&runDiagnosticQueryOptions.IncludeQueryText, "include-query-text", false, "Include the text of the queries in the processlist. The queries may contain sensitive data.")
+	Root.AddCommand(RunDiagnosticQuery)
+
 	Root.AddCommand(RunHealthCheck)
 	Root.AddCommand(SetWritable)
 	Root.AddCommand(SleepTablet)
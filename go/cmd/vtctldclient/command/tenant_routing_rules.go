@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/json2"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// ApplyTenantRoutingRules makes an ApplyTenantRoutingRules gRPC call to a vtctld.
+	ApplyTenantRoutingRules = &cobra.Command{
+		Use:   "ApplyTenantRoutingRules {--rules RULES | --rules-file RULES_FILE} [--cells=c1,c2,...] [--skip-rebuild] [--dry-run]",
+		Short: "Applies the provided tenant routing rules.",
+		Long: `Applies the provided tenant routing rules.
+
+Each rule maps a tenant id to the target that serves the queries of the vtgate
+sessions of that tenant. The target is a keyspace, a shard (keyspace:shard) or
+a key range (keyspace[keyrange]), and must not have a tablet type: the tablet
+type of the session is kept. Sessions select their tenant with
+"SET tenant_id = '<tenant>'" or with the "tenant_id" connection attribute.`,
+		Example:               `ApplyTenantRoutingRules --rules '{"rules": [{"tenant_id": "acme", "target": "customer_acme"}, {"tenant_id": "globex", "target": "customer:-80"}]}'`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandApplyTenantRoutingRules,
+	}
+	// GetTenantRoutingRules makes a GetTenantRoutingRules gRPC call to a vtctld.
+	GetTenantRoutingRules = &cobra.Command{
+		Use:                   "GetTenantRoutingRules",
+		Short:                 "Displays the tenant routing rules as a JSON document.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetTenantRoutingRules,
+	}
+)
+
+var applyTenantRoutingRulesOptions = struct {
+	Rules         string
+	RulesFilePath string
+	Cells         []string
+	SkipRebuild   bool
+	DryRun        bool
+}{}
+
+func commandApplyTenantRoutingRules(cmd *cobra.Command, args []string) error {
+	if applyTenantRoutingRulesOptions.Rules != "" && applyTenantRoutingRulesOptions.RulesFilePath != "" {
+		return fmt.Errorf("cannot pass both --rules (=%s) and --rules-file (=%s)", applyTenantRoutingRulesOptions.Rules, applyTenantRoutingRulesOptions.RulesFilePath)
+	}
+
+	if applyTenantRoutingRulesOptions.Rules == "" && applyTenantRoutingRulesOptions.RulesFilePath == "" {
+		return errors.New("must pass exactly one of --rules or --rules-file")
+	}
+
+	cli.FinishedParsing(cmd)
+
+	var rulesBytes []byte
+	if applyTenantRoutingRulesOptions.RulesFilePath != "" {
+		data, err := os.ReadFile(applyTenantRoutingRulesOptions.RulesFilePath)
+		if err != nil {
+			return err
+		}
+
+		rulesBytes = data
+	} else {
+		rulesBytes = []byte(applyTenantRoutingRulesOptions.Rules)
+	}
+
+	trr := &vschemapb.TenantRoutingRules{}
+	if err := json2.Unmarshal(rulesBytes, &trr); err != nil {
+		return err
+	}
+	if err := vindexes.ValidateTenantRoutingRules(trr); err != nil {
+		return err
+	}
+	// Round-trip so when we display the result it's readable.
+	data, err := cli.MarshalJSON(trr)
+	if err != nil {
+		return err
+	}
+
+	if applyTenantRoutingRulesOptions.DryRun {
+		fmt.Printf("[DRY RUN] Would have saved new TenantRoutingRules object:\n%s\n", data)
+
+		if applyTenantRoutingRulesOptions.SkipRebuild {
+			fmt.Println("[DRY RUN] Would not have rebuilt VSchema graph, would have required operator to run RebuildVSchemaGraph for changes to take effect.")
+		} else {
+			fmt.Print("[DRY RUN] Would have rebuilt the VSchema graph")
+			if len(applyTenantRoutingRulesOptions.Cells) == 0 {
+				fmt.Print(" in all cells\n")
+			} else {
+				fmt.Printf(" in the following cells: %s.\n", strings.Join(applyTenantRoutingRulesOptions.Cells, ", "))
+			}
+		}
+
+		return nil
+	}
+
+	_, err = client.ApplyTenantRoutingRules(commandCtx, &vtctldatapb.ApplyTenantRoutingRulesRequest{
+		TenantRoutingRules: trr,
+		SkipRebuild:        applyTenantRoutingRulesOptions.SkipRebuild,
+		RebuildCells:       applyTenantRoutingRulesOptions.Cells,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("New TenantRoutingRules object:\n%s\nIf this is not what you expected, check the input data (as JSON parsing will skip unexpected fields).\n", data)
+
+	if applyTenantRoutingRulesOptions.SkipRebuild {
+		fmt.Println("Skipping rebuild of VSchema graph as requested, you will need to run RebuildVSchemaGraph for the changes to take effect.")
+	}
+
+	return nil
+}
+
+func commandGetTenantRoutingRules(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetTenantRoutingRules(commandCtx, &vtctldatapb.GetTenantRoutingRulesRequest{})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.TenantRoutingRules)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func init() {
+	ApplyTenantRoutingRules.Flags().StringVarP(&applyTenantRoutingRulesOptions.Rules, "rules", "r", "", "Tenant routing rules, specified as a string")
+	ApplyTenantRoutingRules.Flags().StringVarP(&applyTenantRoutingRulesOptions.RulesFilePath, "rules-file", "f", "", "Path to a file containing tenant routing rules specified as JSON")
+	ApplyTenantRoutingRules.Flags().StringSliceVarP(&applyTenantRoutingRulesOptions.Cells, "cells", "c", nil, "Limit the VSchema graph rebuilding to the specified cells. Ignored if --skip-rebuild is specified.")
+	ApplyTenantRoutingRules.Flags().BoolVar(&applyTenantRoutingRulesOptions.SkipRebuild, "skip-rebuild", false, "Skip rebuilding the SrvVSchema objects.")
+	ApplyTenantRoutingRules.Flags().BoolVarP(&applyTenantRoutingRulesOptions.DryRun, "dry-run", "d", false, "Validate the specified tenant routing rules and note actions that would be taken, but do not actually apply the rules to the topo.")
+	Root.AddCommand(ApplyTenantRoutingRules)
+
+	Root.AddCommand(GetTenantRoutingRules)
+}
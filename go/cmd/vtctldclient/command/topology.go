@@ -18,15 +18,40 @@ package command
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 var (
+	// ForceUnlock makes a ForceUnlock gRPC call to a vtctld.
+	ForceUnlock = &cobra.Command{
+		Use:   "ForceUnlock --reason <reason> <keyspace|keyspace/shard>",
+		Short: "Releases the lock held on a keyspace or a shard, whatever process holds it.",
+		Long: `Releases the lock held on a keyspace or a shard, whatever process holds it.
+
+The process that holds the lock, if still running, is not notified and keeps going as if it still held it, so this is only meant
+to recover from the processes that died without releasing their locks. Use GetTopoLocks to find them first.
+
+The release is logged and audited with the given reason.`,
+		Example:               `ForceUnlock --reason "vtctld crashed during the reparent" commerce/-80`,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandForceUnlock,
+	}
+	// GetTopoLocks makes a GetTopoLocks gRPC call to a vtctld.
+	GetTopoLocks = &cobra.Command{
+		Use:                   "GetTopoLocks [--keyspace <keyspace>]",
+		Short:                 "Lists the keyspace and shard locks currently held, with the action, holder and age of each.",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		RunE:                  commandGetTopoLocks,
+	}
 	// GetTopologyPath makes a GetTopologyPath gRPC call to a vtctld.
 	GetTopologyPath = &cobra.Command{
 		Use:                   "GetTopologyPath <path>",
@@ -37,6 +62,68 @@ var (
 	}
 )
 
+var forceUnlockOptions = struct {
+	Reason string
+}{}
+
+func commandForceUnlock(cmd *cobra.Command, args []string) error {
+	var (
+		keyspace = cmd.Flags().Arg(0)
+		shard    string
+	)
+	if strings.Contains(keyspace, "/") {
+		var err error
+		keyspace, shard, err = topoproto.ParseKeyspaceShard(keyspace)
+		if err != nil {
+			return err
+		}
+	}
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.ForceUnlock(commandCtx, &vtctldatapb.ForceUnlockRequest{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Reason:   forceUnlockOptions.Reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Lock)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+var getTopoLocksOptions = struct {
+	Keyspace string
+}{}
+
+func commandGetTopoLocks(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetTopoLocks(commandCtx, &vtctldatapb.GetTopoLocksRequest{
+		Keyspace: getTopoLocksOptions.Keyspace,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
 func commandGetTopologyPath(cmd *cobra.Command, args []string) error {
 	path := cmd.Flags().Arg(0)
 
@@ -60,5 +147,12 @@ func commandGetTopologyPath(cmd *cobra.Command, args []string) error {
 }
 
 func init() {
+	ForceUnlock.Flags().StringVar(&forceUnlockOptions.Reason, "reason", "", "The reason to release the lock, for the audit log.")
+	ForceUnlock.MarkFlagRequired("reason")
+	Root.AddCommand(ForceUnlock)
+
+	GetTopoLocks.Flags().StringVar(&getTopoLocksOptions.Keyspace, "keyspace", "", "Only list the locks of this keyspace and its shards.")
+	Root.AddCommand(GetTopoLocks)
+
 	Root.AddCommand(GetTopologyPath)
 }
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+var (
+	// GetUnresolvedTransactions makes a GetUnresolvedTransactions gRPC call to a vtctld.
+	GetUnresolvedTransactions = &cobra.Command{
+		Use:                   "GetUnresolvedTransactions [--abandon-age <duration>] <keyspace>",
+		Short:                 "Lists the distributed transactions in the keyspace that have not been resolved.",
+		Long:                  "Lists the distributed transactions whose metadata is held by the primaries of the keyspace and which have not been resolved for longer than the given abandon age.",
+		Example:               "GetUnresolvedTransactions --abandon-age 5m commerce",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandGetUnresolvedTransactions,
+	}
+	// ResolveTransaction makes a ResolveTransaction gRPC call to a vtctld.
+	ResolveTransaction = &cobra.Command{
+		Use:                   "ResolveTransaction <dtid>",
+		Short:                 "Resolves the given distributed transaction through its coordinator.",
+		Example:               "ResolveTransaction commerce:-80:1726239475612345678",
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE:                  commandResolveTransaction,
+	}
+)
+
+var getUnresolvedTransactionsOptions = struct {
+	AbandonAge time.Duration
+}{}
+
+func commandGetUnresolvedTransactions(cmd *cobra.Command, args []string) error {
+	keyspace := cmd.Flags().Arg(0)
+
+	cli.FinishedParsing(cmd)
+
+	resp, err := client.GetUnresolvedTransactions(commandCtx, &vtctldatapb.GetUnresolvedTransactionsRequest{
+		Keyspace:   keyspace,
+		AbandonAge: int64(getUnresolvedTransactionsOptions.AbandonAge.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp.Transactions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", data)
+
+	return nil
+}
+
+func commandResolveTransaction(cmd *cobra.Command, args []string) error {
+	dtid := cmd.Flags().Arg(0)
+
+	cli.FinishedParsing(cmd)
+
+	_, err := client.ResolveTransaction(commandCtx, &vtctldatapb.ResolveTransactionRequest{
+		Dtid: dtid,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully resolved transaction %s\n", dtid)
+
+	return nil
+}
+
+func init() {
+	GetUnresolvedTransactions.Flags().DurationVar(&getUnresolvedTransactionsOptions.AbandonAge, "abandon-age", 0, "Only list transactions that have been unresolved for at least this long.")
+	Root.AddCommand(GetUnresolvedTransactions)
+
+	Root.AddCommand(ResolveTransaction)
+}
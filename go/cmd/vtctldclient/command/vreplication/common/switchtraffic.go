@@ -89,15 +89,17 @@ func commandSwitchTraffic(cmd *cobra.Command, args []string) error {
 	cli.FinishedParsing(cmd)
 
 	req := &vtctldatapb.WorkflowSwitchTrafficRequest{
-		Keyspace:                  BaseOptions.TargetKeyspace,
-		Workflow:                  BaseOptions.Workflow,
-		TabletTypes:               SwitchTrafficOptions.TabletTypes,
-		MaxReplicationLagAllowed:  protoutil.DurationToProto(SwitchTrafficOptions.MaxReplicationLagAllowed),
-		Timeout:                   protoutil.DurationToProto(SwitchTrafficOptions.Timeout),
-		DryRun:                    SwitchTrafficOptions.DryRun,
-		EnableReverseReplication:  SwitchTrafficOptions.EnableReverseReplication,
-		InitializeTargetSequences: SwitchTrafficOptions.InitializeTargetSequences,
-		Direction:                 int32(SwitchTrafficOptions.Direction),
+		Keyspace:                   BaseOptions.TargetKeyspace,
+		Workflow:                   BaseOptions.Workflow,
+		TabletTypes:                SwitchTrafficOptions.TabletTypes,
+		MaxReplicationLagAllowed:   protoutil.DurationToProto(SwitchTrafficOptions.MaxReplicationLagAllowed),
+		Timeout:                    protoutil.DurationToProto(SwitchTrafficOptions.Timeout),
+		DryRun:                     SwitchTrafficOptions.DryRun,
+		EnableReverseReplication:   SwitchTrafficOptions.EnableReverseReplication,
+		InitializeTargetSequences:  SwitchTrafficOptions.InitializeTargetSequences,
+		Direction:                  int32(SwitchTrafficOptions.Direction),
+		RollbackWindow:             protoutil.DurationToProto(SwitchTrafficOptions.RollbackWindow),
+		MaxTimeSinceWritesSwitched: protoutil.DurationToProto(SwitchTrafficOptions.MaxTimeSinceWritesSwitched),
 	}
 	resp, err := GetClient().WorkflowSwitchTraffic(GetCommandCtx(), req)
 	if err != nil {
@@ -26,6 +26,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
@@ -197,6 +198,16 @@ func OutputStatusResponse(resp *vtctldatapb.WorkflowStatusResponse, format strin
 					shardstream.Id, BaseOptions.TargetKeyspace, tablet, shardstream.Status, shardstream.Info))
 			}
 		}
+		if len(resp.TableCopyState) > 0 {
+			tout.WriteString(fmt.Sprintf("\nCopy Progress: %.2f%% of rows copied", resp.CopyPercentage))
+			if eta, ok, _ := protoutil.DurationFromProto(resp.Eta); ok {
+				tout.WriteString(fmt.Sprintf(", ETA: %v", eta))
+			}
+			tout.WriteString("\n")
+		}
+		if maxLag, ok, _ := protoutil.DurationFromProto(resp.MaxReplicationLag); ok {
+			tout.WriteString(fmt.Sprintf("\nMax Replication Lag: %v\n", maxLag))
+		}
 		tout.WriteString("\nTraffic State: ")
 		tout.WriteString(resp.TrafficState)
 		output = tout.Bytes()
@@ -225,15 +236,17 @@ func AddCommonCreateFlags(cmd *cobra.Command) {
 }
 
 var SwitchTrafficOptions = struct {
-	Cells                     []string
-	TabletTypes               []topodatapb.TabletType
-	Timeout                   time.Duration
-	MaxReplicationLagAllowed  time.Duration
-	EnableReverseReplication  bool
-	DryRun                    bool
-	Direction                 workflow.TrafficSwitchDirection
-	InitializeTargetSequences bool
-	Shards                    []string
+	Cells                      []string
+	TabletTypes                []topodatapb.TabletType
+	Timeout                    time.Duration
+	MaxReplicationLagAllowed   time.Duration
+	EnableReverseReplication   bool
+	DryRun                     bool
+	Direction                  workflow.TrafficSwitchDirection
+	InitializeTargetSequences  bool
+	Shards                     []string
+	RollbackWindow             time.Duration
+	MaxTimeSinceWritesSwitched time.Duration
 }{}
 
 func AddCommonSwitchTrafficFlags(cmd *cobra.Command, initializeTargetSequences bool) {
@@ -248,6 +261,14 @@ func AddCommonSwitchTrafficFlags(cmd *cobra.Command, initializeTargetSequences b
 	}
 }
 
+func AddSwitchTrafficRollbackFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&SwitchTrafficOptions.RollbackWindow, "rollback-window", 0, "Retain the reverse workflow for this amount of time after writes are switched, so that traffic can be reversed: the workflow cannot be completed or deleted until then. A value of 0 means it is not retained.")
+}
+
+func AddReverseTrafficFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&SwitchTrafficOptions.MaxTimeSinceWritesSwitched, "max-time-since-writes-switched", 0, "Only allow traffic to be reversed if writes were switched within this amount of time. A value of 0 means there is no limit.")
+}
+
 func AddShardSubsetFlag(cmd *cobra.Command, shardsOption *[]string) {
 	cmd.Flags().StringSliceVar(shardsOption, "shards", nil, "(Optional) Specifies a comma-separated list of shards to operate on.")
 }
@@ -24,6 +24,7 @@ import (
 	"vitess.io/vitess/go/cmd/vtctldclient/cli"
 	"vitess.io/vitess/go/cmd/vtctldclient/command/vreplication/common"
 
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
@@ -31,7 +32,7 @@ var (
 	// migrate is the base command for all actions related to the migrate command.
 	migrate = &cobra.Command{
 		Use:                   "Migrate --workflow <workflow> --target-keyspace <keyspace> [command] [command-flags]",
-		Short:                 "Migrate is used to import data from an external cluster into the current cluster.",
+		Short:                 "Migrate is used to import data from an external cluster or an external MySQL into the current cluster.",
 		DisableFlagsInUseLine: true,
 		Aliases:               []string{"migrate"},
 		Args:                  cobra.ExactArgs(1),
@@ -46,12 +47,24 @@ var createOptions = struct {
 	ExcludeTables  []string
 	SourceTimeZone string
 	NoRoutingRules bool
+
+	ExternalMysql         string
+	SourceServerID        uint32
+	SourceUseFilePosition bool
+	SourceSslMode         string
+	SourceSslCa           string
+	SourceSslCert         string
+	SourceSslKey          string
+	SourceSslServerName   string
 }{}
 
 var createCommand = &cobra.Command{
-	Use:                   "create",
-	Short:                 "Create and optionally run a Migrate VReplication workflow.",
-	Example:               `vtctldclient --server localhost:15999 migrate --workflow import --target-keyspace customer create --source-keyspace commerce --mount-name ext1 --tablet-types replica`,
+	Use:   "create",
+	Short: "Create and optionally run a Migrate VReplication workflow.",
+	Example: `vtctldclient --server localhost:15999 migrate --workflow import --target-keyspace customer create --source-keyspace commerce --mount-name ext1 --tablet-types replica
+
+# Migrate from an external MySQL without GTIDs, as configured in the externalConnections of the target tablets.
+vtctldclient --server localhost:15999 migrate --workflow import --target-keyspace customer create --source-keyspace commerce --external-mysql rds1 --tables customer,corder --source-use-file-position --source-server-id 1000`,
 	SilenceUsage:          true,
 	DisableFlagsInUseLine: true,
 	Aliases:               []string{"Create"},
@@ -61,6 +74,20 @@ var createCommand = &cobra.Command{
 		if !cmd.Flags().Lookup("tables").Changed && !cmd.Flags().Lookup("all-tables").Changed {
 			return fmt.Errorf("tables or all-tables are required to specify which tables to move")
 		}
+		if (createOptions.MountName == "") == (createOptions.ExternalMysql == "") {
+			return fmt.Errorf("exactly one of mount-name or external-mysql is required to specify where to migrate from")
+		}
+		if createOptions.ExternalMysql != "" {
+			if createOptions.AllTables {
+				return fmt.Errorf("all-tables is not supported when migrating from an external mysql: the tables must be listed with tables")
+			}
+		} else {
+			for _, flag := range []string{"source-server-id", "source-use-file-position", "source-ssl-mode", "source-ssl-ca", "source-ssl-cert", "source-ssl-key", "source-ssl-server-name"} {
+				if cmd.Flags().Lookup(flag).Changed {
+					return fmt.Errorf("%s can only be used with external-mysql", flag)
+				}
+			}
+		}
 		if err := common.ParseAndValidateCreateOptions(cmd); err != nil {
 			return err
 		}
@@ -90,6 +117,18 @@ func commandCreate(cmd *cobra.Command, args []string) error {
 		AutoStart:                 common.CreateOptions.AutoStart,
 		StopAfterCopy:             common.CreateOptions.StopAfterCopy,
 		NoRoutingRules:            createOptions.NoRoutingRules,
+		ExternalMysql:             createOptions.ExternalMysql,
+	}
+	if createOptions.ExternalMysql != "" {
+		req.ExternalMysqlOptions = &binlogdatapb.ExternalMysqlOptions{
+			UseFilePosition: createOptions.SourceUseFilePosition,
+			ServerId:        createOptions.SourceServerID,
+			SslMode:         createOptions.SourceSslMode,
+			SslCa:           createOptions.SourceSslCa,
+			SslCert:         createOptions.SourceSslCert,
+			SslKey:          createOptions.SourceSslKey,
+			ServerName:      createOptions.SourceSslServerName,
+		}
 	}
 
 	_, err := common.GetClient().MigrateCreate(common.GetCommandCtx(), req)
@@ -105,7 +144,14 @@ func addCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&createOptions.SourceKeyspace, "source-keyspace", "", "Keyspace where the tables are being moved from.")
 	cmd.MarkFlagRequired("source-keyspace")
 	cmd.Flags().StringVar(&createOptions.MountName, "mount-name", "", "Name external cluster is mounted as.")
-	cmd.MarkFlagRequired("mount-name")
+	cmd.Flags().StringVar(&createOptions.ExternalMysql, "external-mysql", "", "Name of the external MySQL, in the externalConnections config of the target tablets, to migrate from instead of a mounted cluster. The tables must already exist in the target keyspace.")
+	cmd.Flags().Uint32Var(&createOptions.SourceServerID, "source-server-id", 0, "Server id the target shards replicate from the external MySQL with, incremented for each target shard. If not set, the ids are picked by the target tablets.")
+	cmd.Flags().BoolVar(&createOptions.SourceUseFilePosition, "source-use-file-position", false, "Replicate from the external MySQL using binlog file positions, for the servers that don't have GTIDs enabled.")
+	cmd.Flags().StringVar(&createOptions.SourceSslMode, "source-ssl-mode", "", "SSL mode of the connections to the external MySQL, overriding the one in the tablet config.")
+	cmd.Flags().StringVar(&createOptions.SourceSslCa, "source-ssl-ca", "", "Path to the CA file of the connections to the external MySQL, overriding the one in the tablet config.")
+	cmd.Flags().StringVar(&createOptions.SourceSslCert, "source-ssl-cert", "", "Path to the client certificate of the connections to the external MySQL, overriding the one in the tablet config.")
+	cmd.Flags().StringVar(&createOptions.SourceSslKey, "source-ssl-key", "", "Path to the client key of the connections to the external MySQL, overriding the one in the tablet config.")
+	cmd.Flags().StringVar(&createOptions.SourceSslServerName, "source-ssl-server-name", "", "Server name to verify the certificate of the external MySQL with, overriding the one in the tablet config.")
 	cmd.Flags().StringVar(&createOptions.SourceTimeZone, "source-time-zone", "", "Specifying this causes any DATETIME fields to be converted from the given time zone into UTC.")
 	cmd.Flags().BoolVar(&createOptions.AllTables, "all-tables", false, "Copy all tables from the source.")
 	cmd.Flags().StringSliceVar(&createOptions.IncludeTables, "tables", nil, "Source tables to copy.")
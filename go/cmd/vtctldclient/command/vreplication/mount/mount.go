@@ -19,6 +19,7 @@ package migrate
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -40,16 +41,27 @@ var (
 )
 
 var mountOptions struct {
-	Name       string
-	TopoType   string
-	TopoServer string
-	TopoRoot   string
+	Name            string
+	TopoType        string
+	TopoServer      string
+	TopoRoot        string
+	TLSCert         string
+	TLSKey          string
+	TLSCA           string
+	TLSServerName   string
+	CredentialsUser string
 }
 
 var register = &cobra.Command{
-	Use:                   "register",
-	Short:                 "Register an external Vitess Cluster.",
-	Example:               `vtctldclient --server localhost:15999 mount register --name ext1 --topo-type etcd2 --topo-server localhost:12379 --topo-root /vitess/global`,
+	Use:   "register",
+	Short: "Register an external Vitess Cluster.",
+	Long: `Register an external Vitess Cluster.
+
+The TLS client certificate, key and certificate authority are read from local files, and the credentials are
+looked up under --credentials-user in the credentials server (file or vault) of the vtctld. They are stored
+encrypted in the topo, with the key of the --external-cluster-secrets-key-file of the vtctld.`,
+	Example: `vtctldclient --server localhost:15999 mount register --name ext1 --topo-type etcd2 --topo-server localhost:12379 --topo-root /vitess/global
+vtctldclient --server localhost:15999 mount register --name ext1 --topo-type etcd2 --topo-server etcd.dc2:2379 --topo-root /vitess/global --tls-cert client.pem --tls-key client-key.pem --tls-ca ca.pem --credentials-user ext1`,
 	DisableFlagsInUseLine: true,
 	Aliases:               []string{"Register"},
 	Args:                  cobra.NoArgs,
@@ -57,15 +69,33 @@ var register = &cobra.Command{
 }
 
 func commandRegister(cmd *cobra.Command, args []string) error {
+	tlsCert, err := readOptionalFile(mountOptions.TLSCert)
+	if err != nil {
+		return err
+	}
+	tlsKey, err := readOptionalFile(mountOptions.TLSKey)
+	if err != nil {
+		return err
+	}
+	tlsCA, err := readOptionalFile(mountOptions.TLSCA)
+	if err != nil {
+		return err
+	}
+
 	cli.FinishedParsing(cmd)
 
 	req := &vtctldatapb.MountRegisterRequest{
-		Name:       mountOptions.Name,
-		TopoType:   mountOptions.TopoType,
-		TopoServer: mountOptions.TopoServer,
-		TopoRoot:   mountOptions.TopoRoot,
+		Name:            mountOptions.Name,
+		TopoType:        mountOptions.TopoType,
+		TopoServer:      mountOptions.TopoServer,
+		TopoRoot:        mountOptions.TopoRoot,
+		TlsCert:         tlsCert,
+		TlsKey:          tlsKey,
+		TlsCa:           tlsCA,
+		TlsServerName:   mountOptions.TLSServerName,
+		CredentialsUser: mountOptions.CredentialsUser,
 	}
-	_, err := common.GetClient().MountRegister(common.GetCommandCtx(), req)
+	_, err = common.GetClient().MountRegister(common.GetCommandCtx(), req)
 	if err != nil {
 		return err
 	}
@@ -73,6 +103,19 @@ func commandRegister(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// readOptionalFile returns the content of the file at path, or an empty
+// string if path is empty.
+func readOptionalFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 var unregister = &cobra.Command{
 	Use:                   "unregister",
 	Short:                 "Unregister a previously mounted external Vitess Cluster.",
@@ -163,6 +206,11 @@ func registerCommands(root *cobra.Command) {
 	register.MarkFlagRequired("topo-server")
 	register.Flags().StringVar(&mountOptions.TopoRoot, "topo-root", "", "Topo server root path.")
 	register.MarkFlagRequired("topo-root")
+	register.Flags().StringVar(&mountOptions.TLSCert, "tls-cert", "", "Path to the client certificate to connect to the topo server with TLS.")
+	register.Flags().StringVar(&mountOptions.TLSKey, "tls-key", "", "Path to the client key to connect to the topo server with TLS.")
+	register.Flags().StringVar(&mountOptions.TLSCA, "tls-ca", "", "Path to the certificate authority that validates the certificate of the topo server.")
+	register.Flags().StringVar(&mountOptions.TLSServerName, "tls-server-name", "", "Server name to validate the certificate of the topo server, if it differs from its address.")
+	register.Flags().StringVar(&mountOptions.CredentialsUser, "credentials-user", "", "User whose credentials, in the credentials server of the vtctld, authenticate to the topo server.")
 	base.AddCommand(register)
 
 	unregister.Flags().StringVar(&mountOptions.Name, "name", "", "Name of the mount.")
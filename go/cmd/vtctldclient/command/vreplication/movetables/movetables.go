@@ -66,11 +66,13 @@ func registerCommands(root *cobra.Command) {
 
 	switchTrafficCommand := common.GetSwitchTrafficCommand(opts)
 	common.AddCommonSwitchTrafficFlags(switchTrafficCommand, true)
+	common.AddSwitchTrafficRollbackFlags(switchTrafficCommand)
 	common.AddShardSubsetFlag(switchTrafficCommand, &common.SwitchTrafficOptions.Shards)
 	base.AddCommand(switchTrafficCommand)
 
 	reverseTrafficCommand := common.GetReverseTrafficCommand(opts)
 	common.AddCommonSwitchTrafficFlags(reverseTrafficCommand, false)
+	common.AddReverseTrafficFlags(reverseTrafficCommand)
 	common.AddShardSubsetFlag(reverseTrafficCommand, &common.SwitchTrafficOptions.Shards)
 	base.AddCommand(reverseTrafficCommand)
 
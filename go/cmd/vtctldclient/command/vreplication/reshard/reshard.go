@@ -50,10 +50,12 @@ func registerReshardCommands(root *cobra.Command) {
 
 	switchTrafficCommand := common.GetSwitchTrafficCommand(opts)
 	common.AddCommonSwitchTrafficFlags(switchTrafficCommand, false)
+	common.AddSwitchTrafficRollbackFlags(switchTrafficCommand)
 	reshard.AddCommand(switchTrafficCommand)
 
 	reverseTrafficCommand := common.GetReverseTrafficCommand(opts)
 	common.AddCommonSwitchTrafficFlags(reverseTrafficCommand, false)
+	common.AddReverseTrafficFlags(reverseTrafficCommand)
 	reshard.AddCommand(reverseTrafficCommand)
 
 	reshard.AddCommand(common.GetCompleteCommand(opts))
@@ -74,6 +74,12 @@ var (
 		Arg string
 	}{}
 
+	reportOptions = struct {
+		UUID           string
+		Limit          int64
+		OnlyMismatches bool
+	}{}
+
 	resumeOptions = struct {
 		UUID uuid.UUID
 	}{}
@@ -194,6 +200,30 @@ vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --targe
 		RunE: commandResume,
 	}
 
+	// report makes a GetVDiffReport gRPC call to a vtctld.
+	report = &cobra.Command{
+		Use:   "report",
+		Short: "Show the persisted reports of the VDiffs of a workflow, with samples of the rows that did not match.",
+		Example: `vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --target-keyspace customer report
+vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --target-keyspace customer report --limit 5 --only-mismatches
+vtctldclient --server localhost:15999 vdiff --workflow commerce2customer --target-keyspace customer report a037a9e2-5628-11ee-8c99-0242ac120002`,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{"Report"},
+		Args:                  cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			reportOptions.UUID = ""
+			if len(args) == 1 {
+				uuid, err := uuid.Parse(args[0])
+				if err != nil {
+					return fmt.Errorf("invalid UUID provided: %v", err)
+				}
+				reportOptions.UUID = uuid.String()
+			}
+			return nil
+		},
+		RunE: commandReport,
+	}
+
 	// show makes a VDiffShow gRPC call to a vtctld.
 	show = &cobra.Command{
 		Use:   "show",
@@ -820,6 +850,29 @@ func buildProgressReport(summary *summary, rowsToCompare int64) {
 	summary.Progress = report
 }
 
+func commandReport(cmd *cobra.Command, args []string) error {
+	cli.FinishedParsing(cmd)
+
+	resp, err := common.GetClient().GetVDiffReport(common.GetCommandCtx(), &vtctldatapb.GetVDiffReportRequest{
+		Workflow:       common.BaseOptions.Workflow,
+		TargetKeyspace: common.BaseOptions.TargetKeyspace,
+		Uuid:           reportOptions.UUID,
+		Limit:          reportOptions.Limit,
+		OnlyMismatches: reportOptions.OnlyMismatches,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := cli.MarshalJSON(resp)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+
+	return nil
+}
+
 func commandShow(cmd *cobra.Command, args []string) error {
 	format, err := common.GetOutputFormat(cmd)
 	if err != nil {
@@ -890,6 +943,10 @@ func registerCommands(root *cobra.Command) {
 
 	base.AddCommand(delete)
 
+	report.Flags().Int64Var(&reportOptions.Limit, "limit", 0, "The number of most recent VDiffs to report (0 for all of them).")
+	report.Flags().BoolVar(&reportOptions.OnlyMismatches, "only-mismatches", false, "Only report the VDiffs that found mismatches.")
+	base.AddCommand(report)
+
 	base.AddCommand(resume)
 
 	show.Flags().BoolVar(&showOptions.Verbose, "verbose", false, "Show verbose output in summaries")
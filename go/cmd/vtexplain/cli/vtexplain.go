@@ -47,6 +47,7 @@ var (
 	normalize          bool
 	dbName             string
 	plannerVersionStr  string
+	vindexLookups      bool
 
 	numShards       = 2
 	replicationMode = "ROW"
@@ -82,7 +83,9 @@ If no keyspace name is present, VTExplain will return the following error:
 		Example: "Explain how Vitess will execute the query `SELECT * FROM users` using the VSchema contained in `vschemas.json` and the database schema `schema.sql`:\n\n" +
 			"```\nvtexplain --vschema-file vschema.json --schema-file schema.sql --sql \"SELECT * FROM users\"\n```\n\n" +
 			"Explain how the example will execute on 128 shards using Row-based replication:\n\n" +
-			"```\nvtexplain -- -shards 128 --vschema-file vschema.json --schema-file schema.sql --replication-mode \"ROW\" --output-mode text --sql \"INSERT INTO users (user_id, name) VALUES(1, 'john')\"\n```\n",
+			"```\nvtexplain -- -shards 128 --vschema-file vschema.json --schema-file schema.sql --replication-mode \"ROW\" --output-mode text --sql \"INSERT INTO users (user_id, name) VALUES(1, 'john')\"\n```\n\n" +
+			"Explain a transaction before and after splitting the `customer` keyspace into 4 shards, together with the vindex lookups of every statement:\n\n" +
+			"```\nvtexplain --vschema-file vschema.json --schema-file schema.sql --vindex-lookups --sql \"BEGIN; UPDATE users SET name = 'jane' WHERE user_id = 1; COMMIT; RESHARD customer TO 4; SELECT * FROM users WHERE user_id = 1\"\n```\n",
 		Args:    cobra.NoArgs,
 		PreRunE: servenv.CobraPreRunE,
 		Version: servenv.AppVersion.String(),
@@ -107,6 +110,7 @@ func init() {
 	Main.Flags().IntVar(&numShards, "shards", numShards, "Number of shards per keyspace. Passing --ks-shard-map/--ks-shard-map-file causes this flag to be ignored.")
 	Main.Flags().StringVar(&executionMode, "execution-mode", executionMode, "The execution mode to simulate -- must be set to multi, legacy-autocommit, or twopc")
 	Main.Flags().StringVar(&outputMode, "output-mode", outputMode, "Output in human-friendly text or json")
+	Main.Flags().BoolVar(&vindexLookups, "vindex-lookups", vindexLookups, "Include the vindex lookups of each statement in the text output")
 
 	acl.RegisterFlags(Main.Flags())
 }
@@ -175,6 +179,7 @@ func parseAndRun() error {
 		NumShards:       numShards,
 		Normalize:       normalize,
 		Target:          dbName,
+		VindexLookups:   vindexLookups,
 	}
 
 	env, err := vtenv.New(vtenv.Options{
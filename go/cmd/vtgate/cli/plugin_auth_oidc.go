@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+// This plugin imports oidcauthserver to register the OIDC implementation of AuthServer.
+
+import (
+	"vitess.io/vitess/go/mysql/oidcauthserver"
+	"vitess.io/vitess/go/vt/vtgate"
+)
+
+var (
+	oidcAuthConfigFile   string
+	oidcAuthConfigString string
+)
+
+func init() {
+	Main.Flags().StringVar(&oidcAuthConfigFile, "mysql-oidc-auth-config-file", "", "JSON File from which to read the OIDC token introspection config.")
+	Main.Flags().StringVar(&oidcAuthConfigString, "mysql-oidc-auth-config-string", "", "JSON representation of the OIDC token introspection config.")
+
+	vtgate.RegisterPluginInitializer(func() { oidcauthserver.Init(oidcAuthConfigFile, oidcAuthConfigString) })
+}
@@ -76,6 +76,7 @@ var (
       <a href="/debug/health">Query Service Health Check</a></br>
       <a href="/livequeryz/">Real-time Queries</a></br>
       <a href="/debug/status_details">JSON Status Details</a></br>
+      <a href="/debug/restore_status">JSON Restore Status</a></br>
       <a href="/debug/env">View/Change Environment variables</a></br>
     </td>
   </tr>
@@ -92,4 +93,5 @@ func addStatusParts(qsc tabletserver.Controller) {
 	})
 	qsc.AddStatusPart()
 	vreplication.AddStatusPart()
+	tm.AddRestoreStatusPart()
 }
@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// AuthCache caches the result of validating a user's credentials against
+// an external system, like an LDAP server or an OIDC provider, so that
+// every new connection doesn't need a round-trip to that system.
+//
+// Entries are keyed by a hash of the user and its secret, so the secrets
+// themselves are never kept in memory.
+type AuthCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]authCacheEntry
+}
+
+type authCacheEntry struct {
+	getter  Getter
+	expires time.Time
+}
+
+// NewAuthCache returns an AuthCache that keeps entries for at most ttl.
+// A ttl of zero or less disables caching.
+func NewAuthCache(ttl time.Duration) *AuthCache {
+	return &AuthCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[[sha256.Size]byte]authCacheEntry),
+	}
+}
+
+func authCacheKey(user, secret string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(user + "\x00" + secret))
+}
+
+// Get returns the cached user data for the given credentials, if any.
+func (ac *AuthCache) Get(user, secret string) (Getter, bool) {
+	if ac.ttl <= 0 {
+		return nil, false
+	}
+	key := authCacheKey(user, secret)
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	entry, ok := ac.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !ac.now().Before(entry.expires) {
+		delete(ac.entries, key)
+		return nil, false
+	}
+	return entry.getter, true
+}
+
+// Add caches the user data for the given credentials. The entry expires
+// after the cache's ttl, or at expires if that is earlier and not zero.
+func (ac *AuthCache) Add(user, secret string, getter Getter, expires time.Time) {
+	if ac.ttl <= 0 {
+		return
+	}
+	now := ac.now()
+	if deadline := now.Add(ac.ttl); expires.IsZero() || deadline.Before(expires) {
+		expires = deadline
+	}
+	if !now.Before(expires) {
+		return
+	}
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	// Drop the expired entries, so the cache doesn't grow forever.
+	for key, entry := range ac.entries {
+		if !now.Before(entry.expires) {
+			delete(ac.entries, key)
+		}
+	}
+	ac.entries[authCacheKey(user, secret)] = authCacheEntry{getter: getter, expires: expires}
+}
+
+// MapGroupsToUser returns the Vitess user that the given groups map to. The
+// first group, in order, that has an entry in mapping wins. If none of the
+// groups are mapped, username is returned as is.
+func MapGroupsToUser(username string, groups []string, mapping map[string]string) string {
+	for _, group := range groups {
+		if user, ok := mapping[group]; ok {
+			return user
+		}
+	}
+	return username
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthCache(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewAuthCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	userData := &StaticUserData{Username: "user1"}
+	cache.Add("user1", "secret", userData, time.Time{})
+	cache.Add("user2", "secret", &StaticUserData{Username: "user2"}, now.Add(10*time.Second))
+
+	got, ok := cache.Get("user1", "secret")
+	assert.True(t, ok)
+	assert.Equal(t, userData, got)
+
+	// A different secret is a miss.
+	_, ok = cache.Get("user1", "other")
+	assert.False(t, ok)
+
+	// user2 expires with its own deadline, user1 with the cache's ttl.
+	now = now.Add(30 * time.Second)
+	_, ok = cache.Get("user2", "secret")
+	assert.False(t, ok)
+	_, ok = cache.Get("user1", "secret")
+	assert.True(t, ok)
+
+	now = now.Add(30 * time.Second)
+	_, ok = cache.Get("user1", "secret")
+	assert.False(t, ok)
+
+	// A disabled cache never returns anything.
+	disabled := NewAuthCache(0)
+	disabled.Add("user1", "secret", userData, time.Time{})
+	_, ok = disabled.Get("user1", "secret")
+	assert.False(t, ok)
+}
+
+func TestMapGroupsToUser(t *testing.T) {
+	mapping := map[string]string{
+		"dba":     "vt_dba",
+		"readers": "vt_readonly",
+	}
+	assert.Equal(t, "vt_dba", MapGroupsToUser("alice", []string{"dba", "readers"}, mapping))
+	assert.Equal(t, "vt_readonly", MapGroupsToUser("alice", []string{"eng", "readers"}, mapping))
+	assert.Equal(t, "alice", MapGroupsToUser("alice", []string{"eng"}, mapping))
+	assert.Equal(t, "alice", MapGroupsToUser("alice", nil, nil))
+}
@@ -99,6 +99,11 @@ type BinlogEvent interface {
 	// also serves as a BEGIN statement.
 	// This is only valid if IsGTID() returns true.
 	GTID(BinlogFormat) (replication.GTID, bool, error)
+	// CommitTimestamp returns the time the transaction of a GTID event was
+	// committed on its original source, in microseconds since the epoch,
+	// or 0 if the event doesn't record it.
+	// This is only valid if IsGTID() returns true.
+	CommitTimestamp(BinlogFormat) int64
 	// Query returns a Query struct representing data from a QUERY_EVENT.
 	// This is only valid if IsQuery() returns true.
 	Query(BinlogFormat) (Query, error)
@@ -44,6 +44,10 @@ func (*filePosBinlogEvent) GTID(BinlogFormat) (replication.GTID, bool, error) {
 	return nil, false, nil
 }
 
+func (*filePosBinlogEvent) CommitTimestamp(BinlogFormat) int64 {
+	return 0
+}
+
 // IsSemiSyncAckRequested implements BinlogEvent.IsSemiSyncAckRequested().
 func (ev *filePosBinlogEvent) IsSemiSyncAckRequested() bool {
 	return ev.semiSyncAckRequested
@@ -219,6 +223,10 @@ func (ev filePosFakeEvent) GTID(BinlogFormat) (replication.GTID, bool, error) {
 	return nil, false, nil
 }
 
+func (ev filePosFakeEvent) CommitTimestamp(BinlogFormat) int64 {
+	return 0
+}
+
 func (ev filePosFakeEvent) Query(BinlogFormat) (Query, error) {
 	return Query{}, nil
 }
@@ -324,6 +324,20 @@ func NewMariaDBGTIDEvent(f BinlogFormat, s *FakeBinlogStream, gtid replication.M
 	return NewMariadbBinlogEvent(ev)
 }
 
+// NewMySQL56GTIDEvent returns a MySQL 5.6+ GTID event.
+func NewMySQL56GTIDEvent(f BinlogFormat, s *FakeBinlogStream, gtid replication.Mysql56GTID) BinlogEvent {
+	length := 1 + // commit flag
+		16 + // SID
+		8 // GNO
+	data := make([]byte, length)
+	data[0] = 1
+	copy(data[1:17], gtid.Server[:])
+	binary.LittleEndian.PutUint64(data[17:25], uint64(gtid.Sequence))
+
+	ev := s.Packetize(f, eGTIDEvent, 0, data)
+	return NewMysql56BinlogEvent(ev)
+}
+
 // NewTableMapEvent returns a TableMap event.
 // Only works with post_header_length=8.
 func NewTableMapEvent(f BinlogFormat, s *FakeBinlogStream, tableID uint64, tm *TableMap) BinlogEvent {
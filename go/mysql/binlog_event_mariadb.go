@@ -73,6 +73,13 @@ func (ev mariadbBinlogEvent) GTID(f BinlogFormat) (replication.GTID, bool, error
 	}, flags2&FLStandalone == 0, nil
 }
 
+// CommitTimestamp implements BinlogEvent.CommitTimestamp().
+//
+// MariaDB doesn't record the commit time of transactions in the binlog.
+func (ev mariadbBinlogEvent) CommitTimestamp(f BinlogFormat) int64 {
+	return 0
+}
+
 // PreviousGTIDs implements BinlogEvent.PreviousGTIDs().
 func (ev mariadbBinlogEvent) PreviousGTIDs(f BinlogFormat) (replication.Position, error) {
 	return replication.Position{}, vterrors.Errorf(vtrpc.Code_INTERNAL, "MariaDB should not provide PREVIOUS_GTIDS_EVENT events")
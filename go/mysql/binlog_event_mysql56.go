@@ -68,6 +68,43 @@ func (ev mysql56BinlogEvent) GTID(f BinlogFormat) (replication.GTID, bool, error
 	return replication.Mysql56GTID{Server: sid, Sequence: gno}, false /* hasBegin */, nil
 }
 
+// CommitTimestamp implements BinlogEvent.CommitTimestamp().
+//
+// Since MySQL 8.0.1, the GTID is followed by:
+//
+//	# bytes   field
+//	1         logical clock timestamp typecode
+//	8         last_committed
+//	8         sequence_number
+//	7         immediate_commit_timestamp, the highest bit is set if
+//	          original_commit_timestamp follows
+//	7         original_commit_timestamp (optional)
+func (ev mysql56BinlogEvent) CommitTimestamp(f BinlogFormat) int64 {
+	const immediateCommitTimestampPos = 1 + 16 + 8 + 1 + 8 + 8
+	const originalCommitTimestampFlag = uint64(1) << 55
+
+	data := ev.Bytes()[f.HeaderLength:]
+	if len(data) < immediateCommitTimestampPos+7 {
+		return 0
+	}
+	ts := readUint56(data[immediateCommitTimestampPos:])
+	if ts&originalCommitTimestampFlag == 0 {
+		// The transaction was committed on this server.
+		return int64(ts)
+	}
+	if len(data) < immediateCommitTimestampPos+7+7 {
+		return 0
+	}
+	return int64(readUint56(data[immediateCommitTimestampPos+7:]))
+}
+
+// readUint56 reads a 7-byte little endian unsigned integer.
+func readUint56(data []byte) uint64 {
+	var buf [8]byte
+	copy(buf[:7], data[:7])
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
 // PreviousGTIDs implements BinlogEvent.PreviousGTIDs().
 func (ev mysql56BinlogEvent) PreviousGTIDs(f BinlogFormat) (replication.Position, error) {
 	data := ev.Bytes()[f.HeaderLength:]
@@ -91,6 +91,31 @@ func TestMysql56GTID(t *testing.T) {
 	assert.Equal(t, want, got, "GTID() = %#v, want %#v", got, want)
 }
 
+func TestMysql56CommitTimestamp(t *testing.T) {
+	format, err := mysql56FormatEvent.Format()
+	require.NoError(t, err, "Format() error: %v", err)
+
+	// MySQL 5.6 doesn't record commit timestamps.
+	input, _, err := mysql56GTIDEvent.StripChecksum(format)
+	require.NoError(t, err, "StripChecksum() error: %v", err)
+	assert.Zero(t, input.CommitTimestamp(format))
+
+	f := NewMySQL56BinlogFormat()
+	s := NewFakeBinlogStream()
+	gtidEvent := func(timestamps ...byte) BinlogEvent {
+		data := make([]byte, 1+16+8+1+8+8)
+		data = append(data, timestamps...)
+		return NewMysql56BinlogEvent(s.Packetize(f, eGTIDEvent, 0, data))
+	}
+
+	// 1712345678123456 microseconds since the epoch.
+	ts := []byte{0xc0, 0x91, 0xe0, 0x8b, 0x5e, 0x15, 0x06}
+	assert.Equal(t, int64(1712345678123456), gtidEvent(ts...).CommitTimestamp(f), "transaction committed on this server")
+
+	immediate := []byte{0xd0, 0x91, 0xe0, 0x8b, 0x5e, 0x15, 0x86}
+	assert.Equal(t, int64(1712345678123456), gtidEvent(append(immediate, ts...)...).CommitTimestamp(f), "transaction committed on another server")
+}
+
 func TestMysql56DecodeTransactionPayload(t *testing.T) {
 	format := NewMySQL56BinlogFormat()
 	tableMap := &TableMap{}
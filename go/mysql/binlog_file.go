@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// binlogEventHeaderLength is the length of the header of the events of the
+// binary log files, in binlog format version 4.
+const binlogEventHeaderLength = 19
+
+// BinlogFileReader reads the events of a binary log file, such as the ones
+// archived by the incremental backups. The events are the same as the ones
+// sent by the source in a binlog dump.
+type BinlogFileReader struct {
+	r *bufio.Reader
+}
+
+// NewBinlogFileReader returns a reader of the events of the binary log file
+// read from r. It returns an error if r does not start with the magic number
+// of the binary logs.
+func NewBinlogFileReader(r io.Reader) (*BinlogFileReader, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(BinglogMagicNumber))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return nil, vterrors.Wrap(err, "cannot read the magic number of the binary log")
+	}
+	if !bytes.Equal(magic, BinglogMagicNumber) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "not a binary log: unexpected magic number %x", magic)
+	}
+	return &BinlogFileReader{r: br}, nil
+}
+
+// ReadEvent returns the next event of the binary log file, or io.EOF after
+// the last one. The events are MySQL 5.6+ events: the files of MariaDB are
+// not supported.
+func (r *BinlogFileReader) ReadEvent() (BinlogEvent, error) {
+	header := make([]byte, binlogEventHeaderLength)
+	if _, err := io.ReadFull(r.r, header); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "truncated binary log event header")
+		}
+		return nil, err
+	}
+	length := binary.LittleEndian.Uint32(header[9:13])
+	if length < binlogEventHeaderLength {
+		return nil, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "invalid binary log event length %d", length)
+	}
+	buf := make([]byte, length)
+	copy(buf, header)
+	if _, err := io.ReadFull(r.r, buf[binlogEventHeaderLength:]); err != nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "truncated binary log event of type %d: %v", header[4], err)
+	}
+	return NewMysql56BinlogEvent(buf), nil
+}
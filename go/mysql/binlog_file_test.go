@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/replication"
+)
+
+func TestBinlogFileReader(t *testing.T) {
+	f := NewMySQL56BinlogFormat()
+	s := NewFakeBinlogStream()
+	sid, err := replication.ParseSID("00010203-0405-0607-0809-0a0b0c0d0e0f")
+	require.NoError(t, err)
+	gtid := replication.Mysql56GTID{Server: sid, Sequence: 7}
+
+	events := []BinlogEvent{
+		NewFormatDescriptionEvent(f, s),
+		NewMySQL56GTIDEvent(f, s, gtid),
+		NewQueryEvent(f, s, Query{Database: "db", SQL: "insert into t values (1)"}),
+		NewXIDEvent(f, s),
+	}
+	var file bytes.Buffer
+	file.Write(BinglogMagicNumber)
+	for _, ev := range events {
+		file.Write(ev.Bytes())
+	}
+
+	r, err := NewBinlogFileReader(bytes.NewReader(file.Bytes()))
+	require.NoError(t, err)
+	for _, want := range events {
+		got, err := r.ReadEvent()
+		require.NoError(t, err)
+		assert.Equal(t, want.Bytes(), got.Bytes())
+	}
+	_, err = r.ReadEvent()
+	assert.Equal(t, io.EOF, err)
+
+	// The GTID event is parsed back.
+	ev, _, err := events[1].StripChecksum(f)
+	require.NoError(t, err)
+	require.True(t, ev.IsGTID())
+	got, hasBegin, err := ev.GTID(f)
+	require.NoError(t, err)
+	assert.False(t, hasBegin)
+	assert.Equal(t, gtid, got)
+
+	// A truncated event is an error.
+	r, err = NewBinlogFileReader(bytes.NewReader(file.Bytes()[:file.Len()-3]))
+	require.NoError(t, err)
+	for range events[:len(events)-1] {
+		_, err = r.ReadEvent()
+		require.NoError(t, err)
+	}
+	_, err = r.ReadEvent()
+	assert.ErrorContains(t, err, "truncated binary log event")
+
+	// A file that is not a binary log is rejected.
+	_, err = NewBinlogFileReader(bytes.NewReader([]byte("not a binlog")))
+	assert.ErrorContains(t, err, "not a binary log")
+}
@@ -174,7 +174,7 @@ func Connect(ctx context.Context, params *ConnParams) (*Conn, error) {
 // Ping implements mysql ping command.
 func (c *Conn) Ping() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComPing
 
@@ -284,6 +284,16 @@ func (c *Conn) clientHandshake(params *ConnParams) error {
 		scrambledPassword = ScrambleMysqlNativePassword(salt, []byte(params.Pass))
 	}
 
+	// Query attributes are opt-in, and only used if the server
+	// supports them.
+	if params.Flags&CapabilityClientQueryAttributes != 0 && capabilities&CapabilityClientQueryAttributes != 0 {
+		c.Capabilities |= CapabilityClientQueryAttributes
+	}
+
+	// Same for the compressed protocol. zstd is preferred over zlib
+	// when both are requested and supported.
+	c.Capabilities |= uint32(params.Flags) & capabilities & (CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm)
+
 	// Client Session Tracking Capability.
 	if capabilities&CapabilityClientSessionTrack == CapabilityClientSessionTrack {
 		// If the server also supports it, we will have enabled
@@ -306,6 +316,11 @@ func (c *Conn) clientHandshake(params *ConnParams) error {
 		return err
 	}
 
+	// The compressed protocol, if negotiated, starts after the OK packet.
+	if algorithm := c.negotiatedCompression(); algorithm != CompressionNone {
+		c.enableCompression(algorithm, DefaultZstdCompressionLevel)
+	}
+
 	// If the server didn't support DbName in its handshake, set
 	// it now. This is what the 'mysql' client does.
 	if capabilities&CapabilityClientConnectWithDB == 0 && params.DbName != "" {
@@ -537,7 +552,11 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		CapabilityClientFoundRows&uint32(params.Flags) |
 		// If the server supported
 		// CapabilityClientSessionTrack, we also support it.
-		c.Capabilities&CapabilityClientSessionTrack
+		c.Capabilities&CapabilityClientSessionTrack |
+		// Query attributes, if negotiated above.
+		c.Capabilities&CapabilityClientQueryAttributes |
+		// Compression, if negotiated above.
+		c.Capabilities&(CapabilityClientCompress|CapabilityClientZstdCompressionAlgorithm)
 
 	// FIXME(alainjobart) add multi statement.
 
@@ -564,6 +583,11 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 		length++
 	}
 
+	// The zstd compression level, if zstd was negotiated.
+	if capabilityFlags&CapabilityClientZstdCompressionAlgorithm != 0 {
+		length++
+	}
+
 	data, pos := c.startEphemeralPacketWithHeader(length)
 
 	// Client capability flags.
@@ -600,6 +624,10 @@ func (c *Conn) writeHandshakeResponse41(capabilities uint32, scrambledPassword [
 	// Assume native client during response
 	pos = writeNullString(data, pos, string(c.authPluginName))
 
+	if capabilityFlags&CapabilityClientZstdCompressionAlgorithm != 0 {
+		pos = writeByte(data, pos, DefaultZstdCompressionLevel)
+	}
+
 	// Sanity-check the length.
 	if pos != len(data) {
 		return sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "writeHandshakeResponse41: only packed %v bytes, out of %v allocated", pos, len(data))
@@ -0,0 +1,271 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Compression algorithms of the compressed protocol.
+const (
+	// CompressionNone means the connection doesn't use the compressed
+	// protocol.
+	CompressionNone = "none"
+
+	// CompressionZlib is negotiated with CapabilityClientCompress.
+	CompressionZlib = "zlib"
+
+	// CompressionZstd is negotiated with
+	// CapabilityClientZstdCompressionAlgorithm.
+	CompressionZstd = "zstd"
+)
+
+const (
+	// compressedPacketHeaderSize is the size of the header of a
+	// compressed packet: 3 bytes of compressed length, 1 byte of
+	// sequence, and 3 bytes of uncompressed length.
+	compressedPacketHeaderSize = 7
+
+	// minCompressLength is the payload size under which we don't
+	// bother compressing, like MySQL does.
+	minCompressLength = 50
+
+	// DefaultZstdCompressionLevel is the zstd level used when the client
+	// doesn't specify one.
+	DefaultZstdCompressionLevel = 3
+)
+
+// zstdDecoder, shared with the binlog event decompression, is used to
+// decompress the packets. The encoders are created per level.
+var (
+	zstdEncodersMu sync.Mutex
+	zstdEncoders   = make(map[int]*zstd.Encoder)
+)
+
+// zstdEncoder returns the shared encoder for the given level. Encoders
+// are safe for concurrent use with EncodeAll.
+func zstdEncoder(level int) (*zstd.Encoder, error) {
+	zstdEncodersMu.Lock()
+	defer zstdEncodersMu.Unlock()
+	if enc, ok := zstdEncoders[level]; ok {
+		return enc, nil
+	}
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	zstdEncoders[level] = enc
+	return enc, nil
+}
+
+// compressedConn implements the framing of the compressed protocol on top
+// of the reader and writer of a Conn. The regular packets are the payload
+// of the compressed packets, and can span several of them.
+type compressedConn struct {
+	c         *Conn
+	algorithm string
+	level     int
+
+	r io.Reader
+	w io.Writer
+
+	// readBuf holds the uncompressed payload of the last compressed
+	// packet, readBuf[readPos:] is what's left to be read.
+	readBuf []byte
+	readPos int
+}
+
+// Read is part of the io.Reader interface.
+func (cc *compressedConn) Read(p []byte) (int, error) {
+	for cc.readPos == len(cc.readBuf) {
+		if err := cc.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, cc.readBuf[cc.readPos:])
+	cc.readPos += n
+	return n, nil
+}
+
+func (cc *compressedConn) readCompressedPacket() error {
+	var header [compressedPacketHeaderSize]byte
+	if _, err := io.ReadFull(cc.r, header[:]); err != nil {
+		return err
+	}
+	compressedLength := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	sequence := header[3]
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+
+	if sequence != cc.c.compressedSequence {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "invalid compressed sequence, expected %v got %v", cc.c.compressedSequence, sequence)
+	}
+	cc.c.compressedSequence++
+
+	payload := make([]byte, compressedLength)
+	if _, err := io.ReadFull(cc.r, payload); err != nil {
+		return vterrors.Wrapf(err, "io.ReadFull(compressed packet body of length %v) failed", compressedLength)
+	}
+	cc.readPos = 0
+	if uncompressedLength == 0 {
+		// The payload was sent as is.
+		cc.readBuf = payload
+		return nil
+	}
+
+	var err error
+	switch cc.algorithm {
+	case CompressionZstd:
+		cc.readBuf, err = zstdDecoder.DecodeAll(payload, make([]byte, 0, uncompressedLength))
+	default:
+		var zr io.ReadCloser
+		if zr, err = zlib.NewReader(bytes.NewReader(payload)); err == nil {
+			cc.readBuf = make([]byte, uncompressedLength)
+			_, err = io.ReadFull(zr, cc.readBuf)
+			zr.Close()
+		}
+	}
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot decompress %v packet", cc.algorithm)
+	}
+	if len(cc.readBuf) != uncompressedLength {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "decompressed packet has length %v, expected %v", len(cc.readBuf), uncompressedLength)
+	}
+	return nil
+}
+
+// Write is part of the io.Writer interface. Every call sends one
+// compressed packet, or more if p is bigger than MaxPacketSize.
+func (cc *compressedConn) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), MaxPacketSize)]
+		if err := cc.writeCompressedPacket(chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+func (cc *compressedConn) writeCompressedPacket(data []byte) error {
+	payload := data
+	uncompressedLength := 0
+	if len(data) >= minCompressLength {
+		compressed, err := cc.compress(data)
+		if err != nil {
+			return err
+		}
+		// Only use the compressed payload if it is actually smaller.
+		if len(compressed) < len(data) {
+			payload = compressed
+			uncompressedLength = len(data)
+		}
+	}
+
+	packet := make([]byte, compressedPacketHeaderSize+len(payload))
+	packet[0] = byte(len(payload))
+	packet[1] = byte(len(payload) >> 8)
+	packet[2] = byte(len(payload) >> 16)
+	packet[3] = cc.c.compressedSequence
+	packet[4] = byte(uncompressedLength)
+	packet[5] = byte(uncompressedLength >> 8)
+	packet[6] = byte(uncompressedLength >> 16)
+	copy(packet[compressedPacketHeaderSize:], payload)
+	cc.c.compressedSequence++
+
+	if n, err := cc.w.Write(packet); err != nil {
+		return vterrors.Wrapf(err, "Write(compressed packet) failed")
+	} else if n != len(packet) {
+		return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Write(compressed packet) returned a short write: %v < %v", n, len(packet))
+	}
+	return nil
+}
+
+func (cc *compressedConn) compress(data []byte) ([]byte, error) {
+	switch cc.algorithm {
+	case CompressionZstd:
+		enc, err := zstdEncoder(cc.level)
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(data, nil), nil
+	default:
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// enableCompression switches the connection to the compressed protocol
+// with the given algorithm. It must be called right after the handshake,
+// before any buffered write is started.
+func (c *Conn) enableCompression(algorithm string, level int) {
+	if level == 0 {
+		level = DefaultZstdCompressionLevel
+	}
+	c.compressed = &compressedConn{
+		c:         c,
+		algorithm: algorithm,
+		level:     level,
+		r:         c.getReader(),
+		w:         c.conn,
+	}
+}
+
+// negotiatedCompression returns the compression algorithm to use given the
+// negotiated capabilities. zstd is preferred over zlib.
+func (c *Conn) negotiatedCompression() string {
+	switch {
+	case c.Capabilities&CapabilityClientZstdCompressionAlgorithm != 0:
+		return CompressionZstd
+	case c.Capabilities&CapabilityClientCompress != 0:
+		return CompressionZlib
+	default:
+		return CompressionNone
+	}
+}
+
+// CompressionAlgorithm returns the compression algorithm negotiated for
+// this connection, or CompressionNone.
+func (c *Conn) CompressionAlgorithm() string {
+	if c.compressed == nil {
+		return CompressionNone
+	}
+	return c.compressed.algorithm
+}
+
+// resetSequence resets the packet sequences at the start of a new command.
+func (c *Conn) resetSequence() {
+	c.sequence = 0
+	c.compressedSequence = 0
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+)
+
+func TestCompressedPackets(t *testing.T) {
+	for _, algorithm := range []string{CompressionZlib, CompressionZstd} {
+		t.Run(algorithm, func(t *testing.T) {
+			listener, sConn, cConn := createSocketPair(t)
+			defer func() {
+				listener.Close()
+				sConn.Close()
+				cConn.Close()
+			}()
+			cConn.enableCompression(algorithm, 0)
+			sConn.enableCompression(algorithm, 0)
+
+			// Small one, sent as is.
+			verifyPacketComms(t, cConn, sConn, []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+			// Compressible one.
+			verifyPacketComms(t, cConn, sConn, bytes.Repeat([]byte("vitess"), 100))
+
+			// Over the limit, split over several packets.
+			data := make([]byte, MaxPacketSize+1000)
+			data[0] = 0xab
+			data[MaxPacketSize+999] = 0xef
+			verifyPacketComms(t, cConn, sConn, data)
+
+			assert.Equal(t, cConn.compressedSequence, sConn.compressedSequence)
+			assert.Equal(t, algorithm, cConn.CompressionAlgorithm())
+		})
+	}
+}
+
+func TestCompressedProtocol(t *testing.T) {
+	th := &testHandler{}
+
+	authServer := NewAuthServerStatic("", "", 0)
+	authServer.entries["user1"] = []*AuthServerStaticEntry{{
+		Password: "password1",
+		UserData: "userData1",
+	}}
+	defer authServer.close()
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err, "NewListener failed")
+	defer l.Close()
+	go l.Accept()
+
+	host, port := getHostPort(t, l.Addr())
+
+	tcases := []struct {
+		name     string
+		allow    bool
+		flags    uint32
+		expected string
+	}{{
+		name:     "not requested",
+		allow:    true,
+		expected: CompressionNone,
+	}, {
+		name:     "zlib",
+		allow:    true,
+		flags:    CapabilityClientCompress,
+		expected: CompressionZlib,
+	}, {
+		name:     "zstd",
+		allow:    true,
+		flags:    CapabilityClientZstdCompressionAlgorithm,
+		expected: CompressionZstd,
+	}, {
+		name:     "zstd preferred",
+		allow:    true,
+		flags:    CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm,
+		expected: CompressionZstd,
+	}, {
+		name:     "not allowed by the server",
+		flags:    CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm,
+		expected: CompressionNone,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			l.AllowCompression.Store(tcase.allow)
+			params := &ConnParams{
+				Host:  host,
+				Port:  port,
+				Uname: "user1",
+				Pass:  "password1",
+				Flags: uint64(tcase.flags),
+			}
+
+			c, err := Connect(context.Background(), params)
+			require.NoError(t, err, "Connect failed")
+			defer c.Close()
+			assert.Equal(t, tcase.expected, c.CompressionAlgorithm())
+			assert.Equal(t, tcase.expected, th.LastConn().CompressionAlgorithm())
+
+			// Run a few commands, to check the sequences are reset.
+			for i := 0; i < 3; i++ {
+				result, err := c.ExecuteFetch("select rows", 10000, true)
+				require.NoError(t, err, "ExecuteFetch failed")
+				utils.MustMatch(t, result, selectRowsResult)
+			}
+		})
+	}
+}
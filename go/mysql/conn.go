@@ -159,10 +159,20 @@ type Conn struct {
 	// the client and the server, and currently in use.
 	// It is set during the initial handshake.
 	//
-	// It is only used for CapabilityClientDeprecateEOF
-	// and CapabilityClientFoundRows.
+	// It is only used for CapabilityClientDeprecateEOF,
+	// CapabilityClientFoundRows and CapabilityClientQueryAttributes.
 	Capabilities uint32
 
+	// queryAttributes are the query attributes sent by the client
+	// along with the command currently being handled. It is only
+	// set while the Handler is executing that command.
+	queryAttributes map[string]string
+
+	// connectionAttributes are the connection attributes sent by the
+	// client in the handshake, e.g. program_name. It is set during the
+	// initial handshake on the server side.
+	connectionAttributes map[string]string
+
 	// closed is set to true when Close() is called on the connection.
 	closed atomic.Bool
 
@@ -194,6 +204,16 @@ type Conn struct {
 	// Packet encoding variables.
 	sequence uint8
 
+	// compressed is set once the connection switched to the compressed
+	// protocol. compressedSequence is the sequence of the compressed
+	// packets, which is separate from the one of the regular packets.
+	compressed         *compressedConn
+	compressedSequence uint8
+
+	// zstdCompressionLevel is the zstd level the client asked for
+	// during the handshake. Zero means the default level.
+	zstdCompressionLevel int
+
 	// ExpectSemiSyncIndicator is applicable when the connection is used for replication (ComBinlogDump).
 	// When 'true', events are assumed to be padded with 2-byte semi-sync information
 	// See https://dev.mysql.com/doc/internals/en/semi-sync-binlog-event.html
@@ -260,6 +280,18 @@ func newConn(conn net.Conn, flushDelay time.Duration, truncateErrLen int) *Conn
 	}
 }
 
+// NewAuthConn returns a Conn that only wraps the network connection of a
+// client, to authenticate it through the AuthMethods of an AuthServer when
+// it speaks another protocol than the MySQL binary protocol, e.g. the X
+// Protocol. It must not be used to read or write packets.
+func NewAuthConn(conn net.Conn) *Conn {
+	c := &Conn{conn: conn}
+	if _, ok := conn.(*tls.Conn); ok {
+		c.Capabilities |= CapabilityClientSSL
+	}
+	return c
+}
+
 // newServerConn should be used to create server connections.
 //
 // It stashes a reference to the listener to be able to determine if
@@ -323,7 +355,7 @@ func (c *Conn) startWriterBuffering() {
 	defer c.bufMu.Unlock()
 
 	c.bufferedWriter = writersPool.Get().(*bufio.Writer)
-	c.bufferedWriter.Reset(c.conn)
+	c.bufferedWriter.Reset(c.getWriter())
 }
 
 // endWriterBuffering must be called to terminate startWriteBuffering.
@@ -371,14 +403,26 @@ func (c *Conn) startFlushTimer() {
 }
 
 // getReader returns reader for connection. It can be *bufio.Reader or net.Conn
-// depending on which buffer size was passed to newServerConn.
+// depending on which buffer size was passed to newServerConn, wrapped in the
+// compressed protocol if it is in use.
 func (c *Conn) getReader() io.Reader {
+	if c.compressed != nil {
+		return c.compressed
+	}
 	if c.bufferedReader != nil {
 		return c.bufferedReader
 	}
 	return c.conn
 }
 
+// getWriter returns the unbuffered writer for the connection.
+func (c *Conn) getWriter() io.Writer {
+	if c.compressed != nil {
+		return c.compressed
+	}
+	return c.conn
+}
+
 func (c *Conn) readHeaderFrom(r io.Reader) (int, error) {
 	// Note io.ReadFull will return two different types of errors:
 	// 1. if the socket is already closed, and the go runtime knows it,
@@ -483,6 +527,9 @@ func (c *Conn) readEphemeralPacketDirect() ([]byte, error) {
 	}
 
 	var r io.Reader = c.conn
+	if c.compressed != nil {
+		r = c.compressed
+	}
 
 	length, err := c.readHeaderFrom(r)
 	if err != nil {
@@ -611,7 +658,7 @@ func (c *Conn) writePacket(data []byte) error {
 		}()
 	} else {
 		c.bufMu.Unlock()
-		w = c.conn
+		w = c.getWriter()
 	}
 
 	var header [packetHeaderSize]byte
@@ -715,7 +762,7 @@ func (c *Conn) recycleWritePacket() {
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComQuit() error {
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
 
 	data, pos := c.startEphemeralPacketWithHeader(1)
 	data[pos] = ComQuit
@@ -921,7 +968,7 @@ func (c *Conn) writeEOFPacket(flags uint16, warnings uint16) error {
 // handleNextCommand is called in the server loop to process
 // incoming packets.
 func (c *Conn) handleNextCommand(handler Handler) bool {
-	c.sequence = 0
+	c.resetSequence()
 	data, err := c.readEphemeralPacket()
 	if err != nil {
 		// Don't log EOF errors. They cause too much spam.
@@ -959,6 +1006,8 @@ func (c *Conn) handleNextCommand(handler Handler) bool {
 		return c.handleComStmtExecute(handler, data)
 	case ComStmtSendLongData:
 		return c.handleComStmtSendLongData(data)
+	case ComStmtBulkExecute:
+		return c.handleComStmtBulkExecute(handler, data)
 	case ComStmtClose:
 		stmtID, ok := c.parseComStmtClose(data)
 		c.recycleReadPacket()
@@ -1140,8 +1189,10 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 		}
 	}()
 	queryStart := time.Now()
-	stmtID, _, err := c.parseComStmtExecute(c.PrepareData, data)
+	stmtID, _, attributes, err := c.parseComStmtExecute(c.PrepareData, data)
 	c.recycleReadPacket()
+	c.queryAttributes = attributes
+	defer func() { c.queryAttributes = nil }()
 
 	if stmtID != uint32(0) {
 		defer func() {
@@ -1221,6 +1272,82 @@ func (c *Conn) handleComStmtExecute(handler Handler, data []byte) (kontinue bool
 	return true
 }
 
+func (c *Conn) handleComStmtBulkExecute(handler Handler, data []byte) (kontinue bool) {
+	c.startWriterBuffering()
+	defer func() {
+		if err := c.endWriterBuffering(); err != nil {
+			log.Errorf("conn %v: flush() failed: %v", c.ID(), err)
+			kontinue = false
+		}
+	}()
+	queryStart := time.Now()
+	stmtID, rows, err := c.parseComStmtBulkExecute(c.PrepareData, data)
+	c.recycleReadPacket()
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	prepare := c.PrepareData[stmtID]
+	var qr *sqltypes.Result
+	if bulkHandler, ok := handler.(BulkHandler); ok {
+		qr, err = bulkHandler.ComStmtBulkExecute(c, prepare, rows)
+	} else {
+		qr, err = ExecuteBulkRows(prepare, rows, func(prepare *PrepareData, callback func(*sqltypes.Result) error) error {
+			return handler.ComStmtExecute(c, prepare, callback)
+		})
+	}
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+
+	ok := PacketOK{
+		affectedRows:     qr.RowsAffected,
+		lastInsertID:     qr.InsertID,
+		statusFlags:      c.StatusFlags,
+		warnings:         handler.WarningCount(c),
+		sessionStateData: qr.SessionStateChanges,
+	}
+	if err := c.writeOKPacket(&ok); err != nil {
+		log.Errorf("Error writing bulk execute result to %s: %v", c, err)
+		return false
+	}
+
+	timings.Record(queryTimingKey, queryStart)
+	return true
+}
+
+// ExecuteBulkRows executes a prepared statement once for every row of bind
+// variables of a bulk execute, and aggregates the results. Statements that
+// return rows are rejected.
+func ExecuteBulkRows(prepare *PrepareData, rows []map[string]*querypb.BindVariable, execute func(*PrepareData, func(*sqltypes.Result) error) error) (*sqltypes.Result, error) {
+	result := &sqltypes.Result{}
+	for _, bindVars := range rows {
+		rowPrepare := &PrepareData{
+			StatementID: prepare.StatementID,
+			PrepareStmt: prepare.PrepareStmt,
+			ParamsCount: prepare.ParamsCount,
+			ParamsType:  prepare.ParamsType,
+			ColumnNames: prepare.ColumnNames,
+			BindVars:    bindVars,
+		}
+		err := execute(rowPrepare, func(qr *sqltypes.Result) error {
+			if len(qr.Fields) > 0 {
+				return sqlerror.NewSQLError(sqlerror.ERNotSupportedYet, sqlerror.SSUnknownSQLState, "bulk execute of statements that return rows is not supported")
+			}
+			result.RowsAffected += qr.RowsAffected
+			if result.InsertID == 0 {
+				result.InsertID = qr.InsertID
+			}
+			result.SessionStateChanges = qr.SessionStateChanges
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
 func (c *Conn) handleComPrepare(handler Handler, data []byte) (kontinue bool) {
 	c.startWriterBuffering()
 	defer func() {
@@ -1358,11 +1485,15 @@ func (c *Conn) handleComQuery(handler Handler, data []byte) (kontinue bool) {
 	}()
 
 	queryStart := time.Now()
-	query := c.parseComQuery(data)
+	query, attributes, err := c.parseComQuery(data)
 	c.recycleReadPacket()
+	if err != nil {
+		return c.writeErrorPacketFromErrorAndLog(err)
+	}
+	c.queryAttributes = attributes
+	defer func() { c.queryAttributes = nil }()
 
 	var queries []string
-	var err error
 	if c.Capabilities&CapabilityClientMultiStatements != 0 {
 		queries, err = handler.Env().Parser().SplitStatementToPieces(query)
 		if err != nil {
@@ -1424,7 +1555,7 @@ func (c *Conn) execQuery(query string, handler Handler, more bool) execResult {
 					lastInsertID:     qr.InsertID,
 					statusFlags:      flag,
 					warnings:         handler.WarningCount(c),
-					info:             "",
+					info:             qr.Info,
 					sessionStateData: qr.SessionStateChanges,
 				}
 				return c.writeOKPacket(&ok)
@@ -1702,3 +1833,15 @@ func (c *Conn) IsMarkedForClose() bool {
 func (c *Conn) IsShuttingDown() bool {
 	return c.listener.shutdown.Load()
 }
+
+// QueryAttributes returns the query attributes the client sent along with
+// the query that is currently being executed, or nil if there are none.
+func (c *Conn) QueryAttributes() map[string]string {
+	return c.queryAttributes
+}
+
+// ConnectionAttributes returns the connection attributes the client sent
+// in the handshake, or nil if there are none.
+func (c *Conn) ConnectionAttributes() map[string]string {
+	return c.connectionAttributes
+}
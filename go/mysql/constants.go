@@ -76,15 +76,19 @@ const (
 	// CLIENT_NO_SCHEMA 1 << 4
 	// Do not permit database.table.column. We do permit it.
 
-	// CLIENT_COMPRESS 1 << 5
-	// We do not support compression. CPU is usually our bottleneck.
+	// CapabilityClientCompress is CLIENT_COMPRESS.
+	// Can use the compressed protocol, with zlib.
+	// Only negotiated by the server if the listener allows compression.
+	// Supported by the client if ConnParams.Flags has it.
+	CapabilityClientCompress = 1 << 5
 
 	// CLIENT_ODBC 1 << 6
 	// No special behavior since 3.22.
 
-	// CLIENT_LOCAL_FILES 1 << 7
+	// CapabilityClientLocalFiles is CLIENT_LOCAL_FILES.
 	// Client can use LOCAL INFILE request of LOAD DATA|XML.
-	// We do not set it.
+	// Only negotiated by the server if the listener allows local infile.
+	CapabilityClientLocalFiles = 1 << 7
 
 	// CLIENT_IGNORE_SPACE 1 << 8
 	// Parser can ignore spaces before '('.
@@ -148,8 +152,36 @@ const (
 	// CapabilityClientDeprecateEOF is CLIENT_DEPRECATE_EOF
 	// Expects an OK (instead of EOF) after the resultset rows of a Text Resultset.
 	CapabilityClientDeprecateEOF = 1 << 24
+
+	// CapabilityClientZstdCompressionAlgorithm is
+	// CLIENT_ZSTD_COMPRESSION_ALGORITHM
+	// Can use the compressed protocol, with zstd. The handshake response
+	// then ends with the compression level.
+	CapabilityClientZstdCompressionAlgorithm = 1 << 26
+
+	// CapabilityClientQueryAttributes is CLIENT_QUERY_ATTRIBUTES
+	// Can send query attributes along with COM_QUERY and
+	// COM_STMT_EXECUTE.
+	CapabilityClientQueryAttributes = 1 << 27
+)
+
+// MariaDB extended capability flags. MariaDB sends them in the last 4
+// reserved bytes of the handshake packets, but only reads them if the
+// other side doesn't set CLIENT_MYSQL, which is CapabilityClientLongPassword.
+// https://mariadb.com/kb/en/connection/#capabilities
+const (
+	// MariaDBCapabilityStmtBulkOperations is
+	// MARIADB_CLIENT_STMT_BULK_OPERATIONS, which is 1 << 34 in MariaDB's
+	// 64-bit capabilities. It is set by servers that support
+	// COM_STMT_BULK_EXECUTE.
+	MariaDBCapabilityStmtBulkOperations = 1 << 2
 )
 
+// StmtExecuteParameterCountAvailable is the PARAMETER_COUNT_AVAILABLE
+// flag of COM_STMT_EXECUTE. When query attributes are enabled, it is set
+// if the packet holds an explicit parameter count.
+const StmtExecuteParameterCountAvailable = 0x08
+
 // Status flags. They are returned by the server in a few cases.
 // Originally found in include/mysql/mysql_com.h
 // See http://dev.mysql.com/doc/internals/en/status-flags.html
@@ -246,6 +278,10 @@ const (
 	// https://dev.mysql.com/doc/internals/en/com-register-slave.html
 	ComRegisterReplica = 0x15
 
+	// ComStmtBulkExecute is MariaDB's COM_STMT_BULK_EXECUTE.
+	// https://mariadb.com/kb/en/com_stmt_bulk_execute/
+	ComStmtBulkExecute = 0xfa
+
 	// OKPacket is the header of the OK packet.
 	OKPacket = 0x00
 
@@ -259,6 +295,28 @@ const (
 	NullValue = 0xfb
 )
 
+// COM_STMT_BULK_EXECUTE flags and parameter indicators.
+const (
+	// StmtBulkFlagSendUnitResults asks for one result per row.
+	StmtBulkFlagSendUnitResults = 64
+
+	// StmtBulkFlagSendTypesToServer is set when the parameter types are
+	// sent before the rows.
+	StmtBulkFlagSendTypesToServer = 128
+
+	// StmtBulkIndicatorNone means the parameter value follows.
+	StmtBulkIndicatorNone = 0
+
+	// StmtBulkIndicatorNull means the parameter is NULL.
+	StmtBulkIndicatorNull = 1
+
+	// StmtBulkIndicatorDefault means the parameter is the column default.
+	StmtBulkIndicatorDefault = 2
+
+	// StmtBulkIndicatorIgnore means the parameter is ignored.
+	StmtBulkIndicatorIgnore = 3
+)
+
 // Auth packet types
 const (
 	// AuthMoreDataPacket is sent when server requires more data to authenticate
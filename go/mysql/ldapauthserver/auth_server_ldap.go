@@ -43,7 +43,15 @@ type AuthServerLdap struct {
 	GroupQuery     string
 	UserDnPattern  string
 	RefreshSeconds int64
-	methods        []mysql.AuthMethod
+	// GroupUserMapping maps LDAP groups to the Vitess user that members
+	// of the group are identified as. See mysql.MapGroupsToUser.
+	GroupUserMapping map[string]string
+	// CacheTTLSeconds is how long successful logins are cached for, so
+	// new connections of the same user don't need to bind again. Caching
+	// is disabled if it is zero.
+	CacheTTLSeconds int64
+	methods         []mysql.AuthMethod
+	cache           *mysql.AuthCache
 }
 
 // Init is public so it can be called from plugin_auth_ldap.go (go/cmd/vtgate)
@@ -76,6 +84,7 @@ func Init(ldapAuthConfigFile, ldapAuthConfigString, ldapAuthMethod string) {
 	if err := json.Unmarshal(data, ldapAuthServer); err != nil {
 		log.Exitf("Error parsing AuthServerLdap config: %v", err)
 	}
+	ldapAuthServer.cache = mysql.NewAuthCache(time.Duration(ldapAuthServer.CacheTTLSeconds) * time.Second)
 
 	var authMethod mysql.AuthMethod
 	switch mysql.AuthMethodDescription(ldapAuthMethod) {
@@ -112,7 +121,19 @@ func (asl *AuthServerLdap) HandleUser(user string) bool {
 // UserEntryWithPassword is part of the PlaintextStorage interface
 // and called after the password is sent by the client.
 func (asl *AuthServerLdap) UserEntryWithPassword(conn *mysql.Conn, user string, password string, remoteAddr net.Addr) (mysql.Getter, error) {
-	return asl.validate(user, password)
+	if asl.cache != nil {
+		if userData, ok := asl.cache.Get(user, password); ok {
+			return userData, nil
+		}
+	}
+	userData, err := asl.validate(user, password)
+	if err != nil {
+		return nil, err
+	}
+	if asl.cache != nil {
+		asl.cache.Add(user, password, userData, time.Time{})
+	}
+	return userData, nil
 }
 
 func (asl *AuthServerLdap) validate(username, password string) (mysql.Getter, error) {
@@ -193,12 +214,15 @@ func (lud *LdapUserData) update() {
 	lud.Unlock()
 }
 
-// Get returns wrapped username and LDAP groups and possibly updates the cache
+// Get returns wrapped username and LDAP groups and possibly updates the cache.
+// The username is mapped to a Vitess user if one of the groups is in the
+// GroupUserMapping.
 func (lud *LdapUserData) Get() *querypb.VTGateCallerID {
 	if int64(time.Since(lud.lastUpdated).Seconds()) > lud.asl.RefreshSeconds {
 		go lud.update()
 	}
-	return &querypb.VTGateCallerID{Username: lud.username, Groups: lud.groups}
+	username := mysql.MapGroupsToUser(lud.username, lud.groups, lud.asl.GroupUserMapping)
+	return &querypb.VTGateCallerID{Username: username, Groups: lud.groups}
 }
 
 // ServerConfig holds the config for and LDAP server
@@ -19,23 +19,35 @@ package ldapauthserver
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	ldap "gopkg.in/ldap.v2"
+
+	"vitess.io/vitess/go/mysql"
 )
 
-type MockLdapClient struct{}
+type MockLdapClient struct {
+	groups []string
+	binds  int
+}
 
 func (mlc *MockLdapClient) Connect(network string, config *ServerConfig) error { return nil }
 func (mlc *MockLdapClient) Close()                                             {}
 func (mlc *MockLdapClient) Bind(username, password string) error {
+	mlc.binds++
 	if username != "testuser" || password != "testpass" {
 		return fmt.Errorf("invalid credentials: %s, %s", username, password)
 	}
 	return nil
 }
 func (mlc *MockLdapClient) Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error) {
-	return &ldap.SearchResult{}, nil
+	res := &ldap.SearchResult{}
+	for _, group := range mlc.groups {
+		res.Entries = append(res.Entries, ldap.NewEntry("cn="+group, map[string][]string{"cn": {group}}))
+	}
+	return res, nil
 }
 
 func TestValidateClearText(t *testing.T) {
@@ -53,3 +65,34 @@ func TestValidateClearText(t *testing.T) {
 	require.Error(t, err, "AuthServerLdap validated invalid credentials.")
 
 }
+
+func TestGroupUserMappingAndCache(t *testing.T) {
+	client := &MockLdapClient{groups: []string{"eng", "dba"}}
+	asl := &AuthServerLdap{
+		Client:           client,
+		User:             "testuser",
+		Password:         "testpass",
+		UserDnPattern:    "%s",
+		RefreshSeconds:   3600,
+		GroupUserMapping: map[string]string{"dba": "vt_dba"},
+		cache:            mysql.NewAuthCache(time.Minute),
+	}
+
+	userData, err := asl.UserEntryWithPassword(nil, "testuser", "testpass", nil)
+	require.NoError(t, err)
+	callerID := userData.Get()
+	assert.Equal(t, "vt_dba", callerID.Username)
+	assert.Equal(t, []string{"eng", "dba"}, callerID.Groups)
+	binds := client.binds
+
+	// The second login is served from the cache.
+	cached, err := asl.UserEntryWithPassword(nil, "testuser", "testpass", nil)
+	require.NoError(t, err)
+	assert.Same(t, userData, cached)
+	assert.Equal(t, binds, client.binds)
+
+	// Invalid credentials are not.
+	_, err = asl.UserEntryWithPassword(nil, "testuser", "invalidpass", nil)
+	require.Error(t, err)
+	assert.Greater(t, client.binds, binds)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"io"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// localInfilePacket is the header of the packet that asks the client for
+// the content of the file of a LOAD DATA LOCAL INFILE statement.
+const localInfilePacket = 0xfb
+
+// RequestLocalInfile asks the client for the content of the file of a
+// LOAD DATA LOCAL INFILE statement. It must be called while executing the
+// statement, before anything else is written to the connection. The content
+// is read as the client sends it, and the returned reader must be closed
+// before the response to the statement is written: Close drains what the
+// client did not send yet.
+func (c *Conn) RequestLocalInfile(fileName string) (io.ReadCloser, error) {
+	if c.Capabilities&CapabilityClientLocalFiles == 0 {
+		return nil, sqlerror.NewSQLError(sqlerror.ERNotAllowedCommand, sqlerror.SSClientError, "Loading local data is disabled; this must be enabled on both the client and server sides")
+	}
+
+	data, pos := c.startEphemeralPacketWithHeader(1 + len(fileName))
+	data[pos] = localInfilePacket
+	copy(data[pos+1:], fileName)
+	if err := c.writeEphemeralPacket(); err != nil {
+		return nil, err
+	}
+	if err := c.flushWriter(); err != nil {
+		return nil, vterrors.Wrapf(err, "conn %v", c.ID())
+	}
+	return &localInfileReader{c: c}, nil
+}
+
+// flushWriter flushes the buffered writer if writes are being buffered, so
+// that the client receives what was written so far.
+func (c *Conn) flushWriter() error {
+	c.bufMu.Lock()
+	defer c.bufMu.Unlock()
+
+	if c.bufferedWriter == nil {
+		return nil
+	}
+	return c.bufferedWriter.Flush()
+}
+
+// localInfileReader reads the content of a local infile sent by the client.
+// The client sends the content in packets, terminated by an empty packet.
+// The packets are read one by one: a packet of MaxPacketSize must not be
+// merged with the terminating empty packet.
+type localInfileReader struct {
+	c    *Conn
+	data []byte
+	done bool
+	err  error
+}
+
+// Read is part of the io.Reader interface.
+func (r *localInfileReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// Close is part of the io.Closer interface. It reads the rest of the content,
+// so that the connection is ready to write the response to the client.
+func (r *localInfileReader) Close() error {
+	for {
+		r.data = nil
+		if err := r.next(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// next reads the next packet of the content. It returns io.EOF once the
+// terminating empty packet was read.
+func (r *localInfileReader) next() error {
+	if r.done {
+		return io.EOF
+	}
+	if r.err != nil {
+		return r.err
+	}
+	data, err := r.c.readOnePacket()
+	if err != nil {
+		r.err = vterrors.Wrapf(err, "cannot read the local infile from conn %v", r.c.ID())
+		return r.err
+	}
+	if len(data) == 0 {
+		r.done = true
+		return io.EOF
+	}
+	r.data = data
+	return nil
+}
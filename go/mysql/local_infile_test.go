@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysql
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+)
+
+// sendLocalInfile plays the client side of a local infile request: it checks
+// the requested file name, then sends the packets and the empty terminator.
+func sendLocalInfile(t *testing.T, cConn *Conn, fileName string, packets ...string) {
+	data, err := cConn.readPacket()
+	require.NoError(t, err)
+	assert.Equal(t, append([]byte{localInfilePacket}, fileName...), data)
+
+	for _, packet := range packets {
+		useWritePacket(t, cConn, []byte(packet))
+	}
+	useWritePacket(t, cConn, nil)
+}
+
+func TestRequestLocalInfile(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	_, err := sConn.RequestLocalInfile("/tmp/data.csv")
+	sqlErr, ok := err.(*sqlerror.SQLError)
+	require.True(t, ok, "unexpected error: %v", err)
+	assert.Equal(t, sqlerror.ERNotAllowedCommand, sqlErr.Number())
+
+	sConn.Capabilities |= CapabilityClientLocalFiles
+
+	// The content is read as the client sends it.
+	sConn.startWriterBuffering()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sendLocalInfile(t, cConn, "/tmp/data.csv", "1,a\n", "2,b\n")
+	}()
+	r, err := sConn.RequestLocalInfile("/tmp/data.csv")
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "1,a\n2,b\n", string(content))
+	require.NoError(t, r.Close())
+	require.NoError(t, sConn.endWriterBuffering())
+	<-done
+
+	// Close drains the content the server did not read, so the
+	// connection is ready for the response.
+	sConn.sequence, cConn.sequence = 0, 0
+	done = make(chan struct{})
+	go func() {
+		defer close(done)
+		sendLocalInfile(t, cConn, "data.csv", "1,a\n", "2,b\n")
+	}()
+	r, err = sConn.RequestLocalInfile("data.csv")
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	<-done
+
+	useWritePacket(t, sConn, []byte("ok"))
+	data, err := cConn.readPacket()
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(data))
+}
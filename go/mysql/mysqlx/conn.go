@@ -0,0 +1,556 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+)
+
+const (
+	mechanismMySQL41 = "MYSQL41"
+	mechanismPlain   = "PLAIN"
+
+	erNotSupportedAuthMode    = sqlerror.ErrorCode(1251)
+	erSecureTransportRequired = sqlerror.ErrorCode(3159)
+)
+
+// Conn is a client connection of the X Protocol.
+type Conn struct {
+	// ConnectionID is the id of the connection, unique for the Listener.
+	ConnectionID uint32
+
+	// User and UserData are the user the client authenticated as, and what
+	// the AuthServer returned for it.
+	User     string
+	UserData mysql.Getter
+
+	// SchemaName is the default schema the client asked for when it
+	// authenticated.
+	SchemaName string
+
+	// Attributes are the session_connect_attrs of the client.
+	Attributes map[string]string
+
+	// ClientData is a place where the Handler can store any session data.
+	ClientData any
+
+	listener *Listener
+	conn     net.Conn
+	reader   *bufio.Reader
+	writer   *bufio.Writer
+	readBuf  []byte
+	closed   atomic.Bool
+
+	// authenticated is set from the authentication of the client until its
+	// session closes.
+	authenticated bool
+	// authSalt is the salt sent to the clients that authenticate with the
+	// MYSQL41 mechanism.
+	authSalt []byte
+}
+
+func newConn(conn net.Conn, l *Listener, connectionID uint32) *Conn {
+	return &Conn{
+		ConnectionID: connectionID,
+		listener:     l,
+		conn:         conn,
+		reader:       bufio.NewReader(conn),
+		writer:       bufio.NewWriter(conn),
+	}
+}
+
+// RemoteAddr returns the address of the client.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// TLSEnabled returns whether the client enabled TLS.
+func (c *Conn) TLSEnabled() bool {
+	_, ok := c.conn.(*tls.Conn)
+	return ok
+}
+
+// IsShuttingDown returns whether the Listener is shutting down.
+func (c *Conn) IsShuttingDown() bool {
+	return c.listener.IsShutdown()
+}
+
+// Close closes the connection. It can be called concurrently with the
+// statements of the client, which fail.
+func (c *Conn) Close() {
+	if c.closed.CompareAndSwap(false, true) {
+		c.conn.Close()
+	}
+}
+
+// IsClosed returns whether Close was called.
+func (c *Conn) IsClosed() bool {
+	return c.closed.Load()
+}
+
+func (c *Conn) String() string {
+	return fmt.Sprintf("client %v (%s)", c.ConnectionID, c.RemoteAddr().String())
+}
+
+func isEOF(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (c *Conn) readMessage() (byte, []byte, error) {
+	if c.listener.connReadTimeout != 0 {
+		if err := c.conn.SetReadDeadline(time.Now().Add(c.listener.connReadTimeout)); err != nil {
+			return 0, nil, err
+		}
+	}
+	msgType, payload, err := readMessage(c.reader, c.readBuf, c.listener.MaxMessageSize)
+	if err == nil && cap(payload) <= 16*1024 {
+		// Keep the buffer of the small messages.
+		c.readBuf = payload
+	}
+	return msgType, payload, err
+}
+
+func (c *Conn) writeMessage(msgType byte, payload []byte) error {
+	return writeMessage(c.writer, msgType, payload)
+}
+
+func (c *Conn) flush() error {
+	if c.listener.connWriteTimeout != 0 {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(c.listener.connWriteTimeout)); err != nil {
+			return err
+		}
+	}
+	return c.writer.Flush()
+}
+
+// newError returns an error of the X Plugin.
+func newError(code sqlerror.ErrorCode, format string, args ...any) *sqlerror.SQLError {
+	return sqlerror.NewSQLError(code, sqlerror.SSUnknownSQLState, format, args...)
+}
+
+// writeError writes an error to the client and flushes. The fatal errors
+// are followed by the closing of the connection.
+func (c *Conn) writeError(err error, fatal bool) error {
+	var serr *sqlerror.SQLError
+	if !errors.As(err, &serr) {
+		serr = sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+	}
+	var w messageWriter
+	if fatal {
+		w.varint(1, 1)
+	}
+	w.varint(2, uint64(serr.Num))
+	w.string(3, serr.Message)
+	w.string(4, serr.State)
+	if err := c.writeMessage(serverError, w.b); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// writeOk writes an Ok to the client and flushes.
+func (c *Conn) writeOk(msg string) error {
+	var w messageWriter
+	if msg != "" {
+		w.string(1, msg)
+	}
+	if err := c.writeMessage(serverOk, w.b); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// dispatch handles a message of the client. It returns false when the
+// connection must be closed.
+func (c *Conn) dispatch(msgType byte, payload []byte) bool {
+	var err error
+	switch msgType {
+	case clientConCapabilitiesGet:
+		err = c.handleCapabilitiesGet()
+	case clientConCapabilitiesSet:
+		err = c.handleCapabilitiesSet(payload)
+	case clientConClose, clientSessClose:
+		// The connection closes with the session.
+		c.writeOk("bye!")
+		return false
+	case clientSessAuthenticateStart:
+		err = c.handleAuthenticateStart(payload)
+	case clientSessAuthenticateCont:
+		err = c.handleAuthenticateContinue(payload)
+	default:
+		if !c.authenticated {
+			err = c.writeError(newError(sqlerror.ERUnknownComError, "Unexpected message received"), false)
+			break
+		}
+		err = c.dispatchSession(msgType, payload)
+	}
+	if err != nil {
+		if !c.IsClosed() && !isEOF(err) {
+			log.Warningf("Error writing to X Protocol %s: %v", c, err)
+		}
+		return false
+	}
+	return true
+}
+
+// dispatchSession handles a message of an authenticated client.
+func (c *Conn) dispatchSession(msgType byte, payload []byte) error {
+	switch msgType {
+	case clientSQLStmtExecute:
+		return c.handleStmtExecute(payload)
+	case clientCrudFind, clientCrudInsert, clientCrudUpdate, clientCrudDelete:
+		return c.handleCrud(msgType, payload)
+	case clientSessReset:
+		keepOpen := false
+		if err := parseMessage(payload, func(f field) error {
+			if f.num == 1 {
+				keepOpen = f.varint != 0
+			}
+			return nil
+		}); err != nil {
+			return c.writeError(newError(ERXBadMessage, "Invalid message: %v", err), false)
+		}
+		if keepOpen {
+			c.listener.handler.ComResetConnection(c)
+		} else {
+			// The client has to authenticate again.
+			c.closeSession()
+		}
+		return c.writeOk("")
+	case clientExpectOpen, clientExpectClose:
+		// The expectations are only checked by the clients for the
+		// features of the server, which are the ones we support.
+		return c.writeOk("")
+	default:
+		return c.writeError(newError(sqlerror.ERUnknownComError, "Unexpected message received"), false)
+	}
+}
+
+// closeSession closes the session of the client, if authenticated.
+func (c *Conn) closeSession() {
+	if c.authenticated {
+		c.authenticated = false
+		c.listener.handler.ConnectionClosed(c)
+	}
+}
+
+// capabilities returns the capabilities of the server for the client.
+func (c *Conn) capabilities() []objectField {
+	var mechanisms []*anyValue
+	if c.authMethod(mysql.MysqlNativePassword) != nil {
+		mechanisms = append(mechanisms, anyOf(stringScalar(mechanismMySQL41)))
+	}
+	if c.plainAllowed() {
+		mechanisms = append(mechanisms, anyOf(stringScalar(mechanismPlain)))
+	}
+	caps := []objectField{
+		{key: "authentication.mechanisms", value: anyArrayOf(mechanisms...)},
+		{key: "doc.formats", value: anyOf(stringScalar("text"))},
+		{key: "node_type", value: anyOf(stringScalar("mysql"))},
+		{key: "client.pwd_expire_ok", value: anyOf(&scalar{typ: scalarBool})},
+	}
+	if c.listener.TLSConfig.Load() != nil {
+		caps = append([]objectField{{key: "tls", value: anyOf(&scalar{typ: scalarBool, bool: c.TLSEnabled()})}}, caps...)
+	}
+	return caps
+}
+
+func (c *Conn) handleCapabilitiesGet() error {
+	var w messageWriter
+	for _, capability := range c.capabilities() {
+		w.message(1, func(w *messageWriter) {
+			w.string(1, capability.key)
+			w.message(2, func(w *messageWriter) { writeAny(w, capability.value) })
+		})
+	}
+	if err := c.writeMessage(serverConnCapabilities, w.b); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+func (c *Conn) handleCapabilitiesSet(payload []byte) error {
+	var capabilities []objectField
+	err := parseMessage(payload, func(f field) error {
+		if f.num != 1 {
+			return nil
+		}
+		return parseMessage(f.bytes, func(f field) error {
+			if f.num != 1 {
+				return nil
+			}
+			capability, err := parseAnyObjectField(f.bytes)
+			capabilities = append(capabilities, capability)
+			return err
+		})
+	})
+	if err != nil {
+		return c.writeError(newError(ERXBadMessage, "Invalid message: %v", err), false)
+	}
+	if c.authenticated {
+		return c.writeError(newError(ERXCapabilitiesPrepareFailed, "Capabilities cannot be set after authentication"), false)
+	}
+
+	// The capabilities are validated before any is set.
+	var (
+		startTLS   bool
+		attributes map[string]string
+	)
+	for _, capability := range capabilities {
+		switch capability.key {
+		case "tls":
+			enable, ok := capability.value.boolValue()
+			if !ok || (!enable && c.TLSEnabled()) {
+				return c.writeError(newError(ERXCapabilitiesPrepareFailed, "Capability prepare failed for 'tls'"), false)
+			}
+			if enable && !c.TLSEnabled() {
+				if c.listener.TLSConfig.Load() == nil {
+					return c.writeError(newError(ERXCapabilitiesPrepareFailed, "Capability prepare failed for 'tls'"), false)
+				}
+				startTLS = true
+			}
+		case "client.pwd_expire_ok", "client.interactive":
+			if _, ok := capability.value.boolValue(); !ok {
+				return c.writeError(newError(ERXCapabilitiesPrepareFailed, "Capability prepare failed for '%s'", capability.key), false)
+			}
+		case "session_connect_attrs":
+			if capability.value.typ != anyObject {
+				return c.writeError(newError(ERXCapabilitiesPrepareFailed, "Capability prepare failed for 'session_connect_attrs'"), false)
+			}
+			attributes = make(map[string]string, len(capability.value.object))
+			for _, attr := range capability.value.object {
+				value, _ := attr.value.stringValue()
+				attributes[attr.key] = value
+			}
+		default:
+			return c.writeError(newError(ERXCapabilityNotFound, "Capability '%s' doesn't exist", capability.key), false)
+		}
+	}
+	if attributes != nil {
+		c.Attributes = attributes
+	}
+	if err := c.writeOk(""); err != nil {
+		return err
+	}
+	if startTLS {
+		tlsConn := tls.Server(c.conn, c.listener.TLSConfig.Load())
+		if err := tlsConn.Handshake(); err != nil {
+			return fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		c.conn = tlsConn
+		c.reader.Reset(tlsConn)
+		c.writer.Reset(tlsConn)
+	}
+	return nil
+}
+
+// authMethod returns the AuthMethod of the AuthServer with the given name.
+func (c *Conn) authMethod(name mysql.AuthMethodDescription) mysql.AuthMethod {
+	for _, method := range c.listener.authServer.AuthMethods() {
+		if method.Name() == name {
+			return method
+		}
+	}
+	return nil
+}
+
+// plainAllowed returns whether the client can authenticate with the PLAIN
+// mechanism, that sends the password in clear text.
+func (c *Conn) plainAllowed() bool {
+	if !c.TLSEnabled() && !c.listener.AllowClearTextWithoutTLS.Load() {
+		return false
+	}
+	return c.authMethod(mysql.MysqlClearPassword) != nil || c.authMethod(mysql.MysqlNativePassword) != nil
+}
+
+func (c *Conn) handleAuthenticateStart(payload []byte) error {
+	var mechanism string
+	var authData []byte
+	err := parseMessage(payload, func(f field) error {
+		switch f.num {
+		case 1:
+			mechanism = string(f.bytes)
+		case 2:
+			authData = f.bytes
+		}
+		return nil
+	})
+	if err != nil {
+		return c.writeError(newError(ERXBadMessage, "Invalid message: %v", err), false)
+	}
+	if c.authenticated {
+		return c.writeError(newError(sqlerror.ERUnknownComError, "Unexpected message received"), false)
+	}
+	if c.listener.RequireSecureTransport && !c.TLSEnabled() {
+		return c.writeError(newError(erSecureTransportRequired, "Connections using insecure transport are prohibited while --require_secure_transport=ON."), true)
+	}
+
+	switch {
+	case mechanism == mechanismMySQL41 && c.authMethod(mysql.MysqlNativePassword) != nil:
+		c.authSalt, err = newSalt()
+		if err != nil {
+			return err
+		}
+		var w messageWriter
+		w.bytes(1, c.authSalt)
+		if err := c.writeMessage(serverSessAuthenticateCont, w.b); err != nil {
+			return err
+		}
+		return c.flush()
+	case mechanism == mechanismPlain && c.plainAllowed():
+		schema, user, password, ok := splitAuthData(authData)
+		if !ok {
+			return c.writeError(newError(sqlerror.ERAccessDeniedError, "Invalid user or password"), false)
+		}
+		return c.authenticatePlain(schema, user, password)
+	default:
+		return c.writeError(newError(erNotSupportedAuthMode, "Invalid authentication method %s", mechanism), false)
+	}
+}
+
+func (c *Conn) handleAuthenticateContinue(payload []byte) error {
+	var authData []byte
+	err := parseMessage(payload, func(f field) error {
+		if f.num == 1 {
+			authData = f.bytes
+		}
+		return nil
+	})
+	if err != nil {
+		return c.writeError(newError(ERXBadMessage, "Invalid message: %v", err), false)
+	}
+	if c.authenticated || c.authSalt == nil {
+		return c.writeError(newError(sqlerror.ERUnknownComError, "Unexpected message received"), false)
+	}
+	salt := c.authSalt
+	c.authSalt = nil
+
+	// The client sends the scramble of mysql_native_password in hex,
+	// after a *, or nothing for the empty passwords.
+	schema, user, hexScramble, ok := splitAuthData(authData)
+	var scramble []byte
+	if ok && hexScramble != "" {
+		scramble, err = hex.DecodeString(strings.TrimPrefix(hexScramble, "*"))
+		ok = err == nil && strings.HasPrefix(hexScramble, "*")
+	}
+	if !ok {
+		connAuthFail.Add(1)
+		return c.writeError(newError(sqlerror.ERAccessDeniedError, "Invalid user or password"), false)
+	}
+	return c.authenticate(schema, user, c.authMethod(mysql.MysqlNativePassword), append(salt, 0), scramble)
+}
+
+// authenticatePlain authenticates a client that sent its password in clear
+// text, with mysql_clear_password or, failing that, mysql_native_password.
+func (c *Conn) authenticatePlain(schema, user, password string) error {
+	if method := c.authMethod(mysql.MysqlClearPassword); method != nil {
+		return c.authenticate(schema, user, method, nil, append([]byte(password), 0))
+	}
+	salt, err := newSalt()
+	if err != nil {
+		return err
+	}
+	var scramble []byte
+	if password != "" {
+		scramble = mysql.ScrambleMysqlNativePassword(salt, []byte(password))
+	}
+	return c.authenticate(schema, user, c.authMethod(mysql.MysqlNativePassword), append(salt, 0), scramble)
+}
+
+// authenticate checks the credentials of a client with an AuthMethod, then
+// opens its session.
+func (c *Conn) authenticate(schema, user string, method mysql.AuthMethod, serverAuthPluginData, clientAuthPluginData []byte) error {
+	authConn := mysql.NewAuthConn(c.conn)
+	accessDenied := func() error {
+		connAuthFail.Add(1)
+		log.Warningf("Error authenticating X Protocol %s as user %s using %s", c, user, method.Name())
+		return c.writeError(sqlerror.NewSQLError(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user), false)
+	}
+	if !method.HandleUser(authConn, user) {
+		return accessDenied()
+	}
+	userData, err := method.HandleAuthPluginData(authConn, user, serverAuthPluginData, clientAuthPluginData, c.RemoteAddr())
+	if err != nil {
+		return accessDenied()
+	}
+
+	c.User = user
+	c.UserData = userData
+	c.SchemaName = schema
+	c.authenticated = true
+	c.listener.handler.NewConnection(c)
+
+	if schema != "" {
+		err := c.listener.handler.ComQuery(c, "use "+sqlescape.EscapeID(schema), func(*sqltypes.Result) error { return nil })
+		if err != nil {
+			c.closeSession()
+			return c.writeError(err, false)
+		}
+	}
+	connAuthOK.Add(1)
+
+	// The clients learn their id from a notice.
+	if err := c.writeStateChanged(stateClientIDAssigned, uintScalar(uint64(c.ConnectionID))); err != nil {
+		return err
+	}
+	if err := c.writeMessage(serverSessAuthenticateOk, nil); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// splitAuthData splits the schema\0user\0password authentication data.
+func splitAuthData(authData []byte) (schema, user, password string, ok bool) {
+	parts := bytes.SplitN(authData, []byte{0}, 3)
+	if len(parts) != 3 || len(parts[1]) == 0 {
+		return "", "", "", false
+	}
+	return string(parts[0]), string(parts[1]), string(parts[2]), true
+}
+
+// newSalt returns a salt for mysql_native_password, of printable characters
+// as MySQL sends.
+func newSalt() ([]byte, error) {
+	salt := make([]byte, 20)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	for i := range salt {
+		salt[i] &= 0x7f
+		if salt[i] == 0 || salt[i] == '$' {
+			salt[i]++
+		}
+	}
+	return salt, nil
+}
@@ -0,0 +1,708 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// This file contains the Mysqlx.Crud messages, and their translation to SQL.
+// The collections are tables with a doc JSON column, and an _id primary key
+// generated from the _id member of the documents.
+
+// crudField names the fields of the CRUD messages, whose numbers differ from
+// one message to the other.
+type crudField int
+
+const (
+	crudCollection crudField = iota + 1
+	crudDataModel
+	crudProjection
+	crudColumns
+	crudRows
+	crudUpsert
+	crudCriteria
+	crudLimit
+	crudLimitExpr
+	crudOrder
+	crudGrouping
+	crudGroupingCriteria
+	crudOperations
+	crudArgs
+	crudLocking
+	crudLockingOptions
+)
+
+var crudFields = map[byte]map[protowire.Number]crudField{
+	clientCrudFind: {
+		2: crudCollection, 3: crudDataModel, 4: crudProjection, 5: crudCriteria, 6: crudLimit, 7: crudOrder,
+		8: crudGrouping, 9: crudGroupingCriteria, 11: crudArgs, 12: crudLocking, 13: crudLockingOptions, 14: crudLimitExpr,
+	},
+	clientCrudInsert: {
+		1: crudCollection, 2: crudDataModel, 3: crudColumns, 4: crudRows, 5: crudArgs, 6: crudUpsert,
+	},
+	clientCrudUpdate: {
+		2: crudCollection, 3: crudDataModel, 4: crudCriteria, 5: crudLimit, 6: crudOrder, 7: crudOperations,
+		8: crudArgs, 9: crudLimitExpr,
+	},
+	clientCrudDelete: {
+		1: crudCollection, 2: crudDataModel, 3: crudCriteria, 4: crudLimit, 5: crudOrder, 6: crudArgs, 7: crudLimitExpr,
+	},
+}
+
+const (
+	dataModelDocument = 1
+	dataModelTable    = 2
+
+	orderDesc = 2
+
+	lockShared    = 1
+	lockExclusive = 2
+
+	lockNoWait     = 1
+	lockSkipLocked = 2
+)
+
+// updateType is Mysqlx.Crud.UpdateOperation.UpdateType.
+type updateType uint64
+
+const (
+	updateSet         updateType = 1
+	updateItemRemove  updateType = 2
+	updateItemSet     updateType = 3
+	updateItemReplace updateType = 4
+	updateItemMerge   updateType = 5
+	updateArrayInsert updateType = 6
+	updateArrayAppend updateType = 7
+	updateMergePatch  updateType = 8
+)
+
+// crud is any of the Find, Insert, Update and Delete messages.
+type crud struct {
+	schema     string
+	collection string
+	// dataModel is the document model by default.
+	dataModel uint64
+
+	projection []projection
+	columns    []columnIdentifier
+	rows       [][]*expr
+	upsert     bool
+
+	criteria         *expr
+	rowCount         *expr
+	offset           *expr
+	order            []order
+	grouping         []*expr
+	groupingCriteria *expr
+	operations       []updateOperation
+	args             []*scalar
+
+	locking        uint64
+	lockingOptions uint64
+}
+
+type projection struct {
+	source *expr
+	alias  string
+}
+
+type order struct {
+	expr *expr
+	desc bool
+}
+
+type updateOperation struct {
+	source *columnIdentifier
+	typ    updateType
+	value  *expr
+}
+
+func parseCrud(msgType byte, b []byte) (*crud, error) {
+	fields := crudFields[msgType]
+	c := &crud{dataModel: dataModelDocument}
+	err := parseMessage(b, func(f field) error {
+		var err error
+		switch fields[f.num] {
+		case crudCollection:
+			err = parseMessage(f.bytes, func(f field) error {
+				switch f.num {
+				case 1:
+					c.collection = string(f.bytes)
+				case 2:
+					c.schema = string(f.bytes)
+				}
+				return nil
+			})
+		case crudDataModel:
+			c.dataModel = f.varint
+		case crudProjection:
+			var p projection
+			err = parseMessage(f.bytes, func(f field) error {
+				var err error
+				switch f.num {
+				case 1:
+					p.source, err = parseExpr(f.bytes)
+				case 2:
+					p.alias = string(f.bytes)
+				}
+				return err
+			})
+			c.projection = append(c.projection, p)
+		case crudColumns:
+			// Column: the name, then the alias, which we don't need, then
+			// the document path.
+			var col columnIdentifier
+			err = parseMessage(f.bytes, func(f field) error {
+				switch f.num {
+				case 1:
+					col.name = string(f.bytes)
+				case 3:
+					item, err := parseDocPathItem(f.bytes)
+					if err != nil {
+						return err
+					}
+					col.docPath = append(col.docPath, item)
+				}
+				return nil
+			})
+			c.columns = append(c.columns, col)
+		case crudRows:
+			var row []*expr
+			err = parseMessage(f.bytes, func(f field) error {
+				if f.num != 1 {
+					return nil
+				}
+				value, err := parseExpr(f.bytes)
+				if err != nil {
+					return err
+				}
+				row = append(row, value)
+				return nil
+			})
+			c.rows = append(c.rows, row)
+		case crudUpsert:
+			c.upsert = f.varint != 0
+		case crudCriteria:
+			c.criteria, err = parseExpr(f.bytes)
+		case crudLimit:
+			err = parseMessage(f.bytes, func(f field) error {
+				switch f.num {
+				case 1:
+					c.rowCount = &expr{typ: exprLiteral, literal: uintScalar(f.varint)}
+				case 2:
+					c.offset = &expr{typ: exprLiteral, literal: uintScalar(f.varint)}
+				}
+				return nil
+			})
+		case crudLimitExpr:
+			err = parseMessage(f.bytes, func(f field) error {
+				var err error
+				switch f.num {
+				case 1:
+					c.rowCount, err = parseExpr(f.bytes)
+				case 2:
+					c.offset, err = parseExpr(f.bytes)
+				}
+				return err
+			})
+		case crudOrder:
+			var o order
+			err = parseMessage(f.bytes, func(f field) error {
+				var err error
+				switch f.num {
+				case 1:
+					o.expr, err = parseExpr(f.bytes)
+				case 2:
+					o.desc = f.varint == orderDesc
+				}
+				return err
+			})
+			c.order = append(c.order, o)
+		case crudGrouping:
+			var g *expr
+			g, err = parseExpr(f.bytes)
+			c.grouping = append(c.grouping, g)
+		case crudGroupingCriteria:
+			c.groupingCriteria, err = parseExpr(f.bytes)
+		case crudOperations:
+			var op updateOperation
+			err = parseMessage(f.bytes, func(f field) error {
+				var err error
+				switch f.num {
+				case 1:
+					op.source, err = parseColumnIdentifier(f.bytes)
+				case 2:
+					op.typ = updateType(f.varint)
+				case 3:
+					op.value, err = parseExpr(f.bytes)
+				}
+				return err
+			})
+			c.operations = append(c.operations, op)
+		case crudArgs:
+			var arg *scalar
+			arg, err = parseScalar(f.bytes)
+			c.args = append(c.args, arg)
+		case crudLocking:
+			c.locking = f.varint
+		case crudLockingOptions:
+			c.lockingOptions = f.varint
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.collection == "" {
+		return nil, newError(ERXInvalidCollection, "invalid collection")
+	}
+	return c, nil
+}
+
+func (c *crud) generator() *exprGenerator {
+	return &exprGenerator{args: c.args, documentModel: c.dataModel == dataModelDocument}
+}
+
+func (c *crud) tableSQL() string {
+	if c.schema == "" {
+		return sqlescape.EscapeID(c.collection)
+	}
+	return sqlescape.EscapeID(c.schema) + "." + sqlescape.EscapeID(c.collection)
+}
+
+// filterSQL appends the WHERE, ORDER BY and LIMIT clauses. The offset is
+// only allowed for the finds.
+func (c *crud) filterSQL(b *strings.Builder, g *exprGenerator, withOffset bool) error {
+	if c.criteria != nil {
+		criteria, err := g.sql(c.criteria)
+		if err != nil {
+			return err
+		}
+		b.WriteString(" WHERE ")
+		b.WriteString(criteria)
+	}
+	if len(c.grouping) > 0 {
+		grouping, err := g.list(c.grouping)
+		if err != nil {
+			return err
+		}
+		b.WriteString(" GROUP BY ")
+		b.WriteString(grouping)
+		if c.groupingCriteria != nil {
+			having, err := g.sql(c.groupingCriteria)
+			if err != nil {
+				return err
+			}
+			b.WriteString(" HAVING ")
+			b.WriteString(having)
+		}
+	}
+	for i, o := range c.order {
+		sql, err := g.sql(o.expr)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			b.WriteString(" ORDER BY ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(sql)
+		if o.desc {
+			b.WriteString(" DESC")
+		}
+	}
+	if c.rowCount != nil {
+		rowCount, err := g.sql(c.rowCount)
+		if err != nil {
+			return err
+		}
+		b.WriteString(" LIMIT ")
+		if c.offset != nil {
+			if !withOffset {
+				return newError(ERXExprBadValue, "invalid limit offset, only allowed for finds")
+			}
+			offset, err := g.sql(c.offset)
+			if err != nil {
+				return err
+			}
+			b.WriteString(offset)
+			b.WriteString(", ")
+		}
+		b.WriteString(rowCount)
+	}
+	return nil
+}
+
+// findSQL returns the SELECT of a Find.
+func (c *crud) findSQL() (string, error) {
+	g := c.generator()
+	var b strings.Builder
+	b.WriteString("SELECT ")
+	switch {
+	case len(c.projection) == 0 && g.documentModel:
+		b.WriteString("doc")
+	case len(c.projection) == 0:
+		b.WriteString("*")
+	case g.documentModel:
+		// The projections of documents build new documents.
+		b.WriteString("JSON_OBJECT(")
+		for i, p := range c.projection {
+			alias := p.alias
+			if alias == "" && p.source.typ == exprIdent && p.source.identifier != nil {
+				if path := p.source.identifier.docPath; len(path) > 0 && path[len(path)-1].typ == docPathMember {
+					alias = path[len(path)-1].value
+				}
+			}
+			if alias == "" {
+				return "", newError(ERXExprBadValue, "invalid projection target name")
+			}
+			source, err := g.sql(p.source)
+			if err != nil {
+				return "", err
+			}
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(sqltypes.EncodeStringSQL(alias))
+			b.WriteString(", ")
+			b.WriteString(source)
+		}
+		b.WriteString(") AS doc")
+	default:
+		for i, p := range c.projection {
+			source, err := g.sql(p.source)
+			if err != nil {
+				return "", err
+			}
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(source)
+			if p.alias != "" {
+				b.WriteString(" AS ")
+				b.WriteString(sqlescape.EscapeID(p.alias))
+			}
+		}
+	}
+	b.WriteString(" FROM ")
+	b.WriteString(c.tableSQL())
+	if err := c.filterSQL(&b, g, true); err != nil {
+		return "", err
+	}
+	switch c.locking {
+	case lockShared:
+		b.WriteString(" FOR SHARE")
+	case lockExclusive:
+		b.WriteString(" FOR UPDATE")
+	}
+	if c.locking != 0 {
+		switch c.lockingOptions {
+		case lockNoWait:
+			b.WriteString(" NOWAIT")
+		case lockSkipLocked:
+			b.WriteString(" SKIP LOCKED")
+		}
+	}
+	return b.String(), nil
+}
+
+// insertSQL returns the INSERT of an Insert, and the _id of the documents
+// that had none, generated with newID.
+func (c *crud) insertSQL(newID func() string) (string, []string, error) {
+	if len(c.rows) == 0 {
+		return "", nil, newError(ERXBadInsertData, "missing row data for insert")
+	}
+	g := c.generator()
+	var (
+		b   strings.Builder
+		ids []string
+	)
+	b.WriteString("INSERT INTO ")
+	b.WriteString(c.tableSQL())
+	switch {
+	case g.documentModel:
+		b.WriteString(" (doc)")
+	case len(c.columns) > 0:
+		names := make([]string, 0, len(c.columns))
+		for _, col := range c.columns {
+			if col.name == "" || len(col.docPath) > 0 {
+				return "", nil, newError(ERXBadInsertData, "invalid column name to insert")
+			}
+			names = append(names, sqlescape.EscapeID(col.name))
+		}
+		b.WriteString(" (")
+		b.WriteString(strings.Join(names, ", "))
+		b.WriteString(")")
+	}
+	b.WriteString(" VALUES ")
+	for i, row := range c.rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if g.documentModel {
+			if len(row) != 1 {
+				return "", nil, newError(ERXBadInsertData, "wrong number of fields in the row being inserted")
+			}
+			doc, id, err := c.documentSQL(g, row[0], newID)
+			if err != nil {
+				return "", nil, err
+			}
+			if id != "" {
+				ids = append(ids, id)
+			}
+			b.WriteString("(")
+			b.WriteString(doc)
+			b.WriteString(")")
+			continue
+		}
+		if len(c.columns) > 0 && len(row) != len(c.columns) {
+			return "", nil, newError(ERXBadInsertData, "wrong number of fields in the row being inserted")
+		}
+		values, err := g.list(row)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString("(")
+		b.WriteString(values)
+		b.WriteString(")")
+	}
+	if c.upsert {
+		if !g.documentModel {
+			return "", nil, newError(ERXBadInsertData, "upsert is only supported for collections")
+		}
+		b.WriteString(" ON DUPLICATE KEY UPDATE doc = VALUES(doc)")
+	}
+	return b.String(), ids, nil
+}
+
+// documentSQL returns the SQL of a document to insert, with a generated _id
+// if it has none.
+func (c *crud) documentSQL(g *exprGenerator, doc *expr, newID func() string) (string, string, error) {
+	if doc.typ == exprPlaceholder {
+		if int(doc.position) >= len(c.args) {
+			return "", "", newError(ERXExprMissingArg, "no value for the placeholder %d", doc.position)
+		}
+		doc = &expr{typ: exprLiteral, literal: c.args[doc.position]}
+	}
+
+	switch doc.typ {
+	case exprObject:
+		for _, fld := range doc.object {
+			if fld.key == "_id" {
+				sql, err := g.sql(doc)
+				return sql, "", err
+			}
+		}
+		id := newID()
+		withID := *doc
+		withID.object = append(append([]exprObjectField(nil), doc.object...), exprObjectField{
+			key:   "_id",
+			value: &expr{typ: exprLiteral, literal: stringScalar(id)},
+		})
+		sql, err := g.sql(&withID)
+		return sql, id, err
+	case exprLiteral:
+		text, ok := doc.literal.stringValue()
+		if !ok {
+			return "", "", newError(ERXBadInsertData, "invalid document, expected a JSON object")
+		}
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(text), &members); err != nil {
+			return "", "", newError(ERXBadInsertData, "invalid document, expected a JSON object: %v", err)
+		}
+		sql := "CAST(" + sqltypes.EncodeStringSQL(text) + " AS JSON)"
+		if _, ok := members["_id"]; ok {
+			return sql, "", nil
+		}
+		id := newID()
+		return "JSON_INSERT(" + sql + ", '$._id', " + sqltypes.EncodeStringSQL(id) + ")", id, nil
+	default:
+		sql, err := g.sql(doc)
+		if err != nil {
+			return "", "", err
+		}
+		// JSON_INSERT keeps the _id of the documents that have one, we
+		// cannot tell which do.
+		return "JSON_INSERT(" + sql + ", '$._id', " + sqltypes.EncodeStringSQL(newID()) + ")", "", nil
+	}
+}
+
+// updateFunctions maps the item updates to the JSON function that applies
+// them.
+var updateFunctions = map[updateType]string{
+	updateItemRemove:  "JSON_REMOVE",
+	updateItemSet:     "JSON_SET",
+	updateItemReplace: "JSON_REPLACE",
+	updateArrayInsert: "JSON_ARRAY_INSERT",
+	updateArrayAppend: "JSON_ARRAY_APPEND",
+}
+
+// updateSQL returns the UPDATE of an Update.
+func (c *crud) updateSQL() (string, error) {
+	if len(c.operations) == 0 {
+		return "", newError(ERXBadUpdateData, "invalid update expression list")
+	}
+	g := c.generator()
+
+	// The new values of the columns, in the order of their first update.
+	var (
+		columns []string
+		values  = map[string]string{}
+	)
+	for _, op := range c.operations {
+		if op.source == nil {
+			return "", newError(ERXBadUpdateData, "invalid update without a source")
+		}
+		column := sqlescape.EscapeID(op.source.name)
+		if g.documentModel {
+			if op.source.name != "" {
+				return "", newError(ERXBadUpdateData, "invalid column name to update")
+			}
+			if len(op.source.docPath) > 0 && op.source.docPath[0].typ == docPathMember && op.source.docPath[0].value == "_id" {
+				return "", newError(ERXBadMemberToUpdate, "forbidden update operation on '$._id' member")
+			}
+			column = "doc"
+		} else if op.source.name == "" {
+			return "", newError(ERXBadUpdateData, "invalid column name to update")
+		}
+		current, ok := values[column]
+		if !ok {
+			columns = append(columns, column)
+			current = column
+		}
+
+		var value string
+		if op.typ != updateItemRemove {
+			if op.value == nil {
+				return "", newError(ERXBadUpdateData, "invalid update without a value")
+			}
+			var err error
+			if value, err = g.sql(op.value); err != nil {
+				return "", err
+			}
+		}
+
+		switch op.typ {
+		case updateSet:
+			if g.documentModel || len(op.source.docPath) > 0 {
+				return "", newError(ERXBadUpdateData, "invalid type of update operation for the column %s", column)
+			}
+			values[column] = value
+		case updateItemMerge, updateMergePatch:
+			function := "JSON_MERGE_PRESERVE"
+			if op.typ == updateMergePatch {
+				function = "JSON_MERGE_PATCH"
+			}
+			merged := function + "(" + current + ", " + g.jsonSQL(op.value, value) + ")"
+			if g.documentModel {
+				// The _id of documents cannot change.
+				merged = "JSON_SET(" + merged + ", '$._id', JSON_EXTRACT(doc, '$._id'))"
+			}
+			values[column] = merged
+		default:
+			function, ok := updateFunctions[op.typ]
+			if !ok {
+				return "", newError(ERXBadUpdateData, "invalid type of update operation %d", op.typ)
+			}
+			if len(op.source.docPath) == 0 {
+				return "", newError(ERXBadUpdateData, "invalid document path to update")
+			}
+			path, err := docPathSQL(op.source.docPath)
+			if err != nil {
+				return "", err
+			}
+			args := []string{current, sqltypes.EncodeStringSQL(path)}
+			if op.typ != updateItemRemove {
+				args = append(args, value)
+			}
+			values[column] = function + "(" + strings.Join(args, ", ") + ")"
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("UPDATE ")
+	b.WriteString(c.tableSQL())
+	b.WriteString(" SET ")
+	for i, column := range columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(column)
+		b.WriteString(" = ")
+		b.WriteString(values[column])
+	}
+	if err := c.filterSQL(&b, g, false); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// deleteSQL returns the DELETE of a Delete.
+func (c *crud) deleteSQL() (string, error) {
+	g := c.generator()
+	var b strings.Builder
+	b.WriteString("DELETE FROM ")
+	b.WriteString(c.tableSQL())
+	if err := c.filterSQL(&b, g, false); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// collectionSQL returns the CREATE TABLE of a collection.
+func collectionSQL(schema, name string, ifNotExists bool) string {
+	table := sqlescape.EscapeID(name)
+	if schema != "" {
+		table = sqlescape.EscapeID(schema) + "." + table
+	}
+	var b strings.Builder
+	b.WriteString("CREATE TABLE ")
+	if ifNotExists {
+		b.WriteString("IF NOT EXISTS ")
+	}
+	b.WriteString(table)
+	b.WriteString(" (doc JSON, _id VARBINARY(32) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(doc, '$._id'))) STORED PRIMARY KEY) CHARSET utf8mb4 ENGINE=InnoDB")
+	return b.String()
+}
+
+// documentIDs generates the _id of the documents inserted without one.
+type documentIDs struct {
+	base    string
+	counter atomic.Uint64
+}
+
+// newDocumentIDs returns a generator of _id values unique for the given
+// prefix and start time: as the ids generated by MySQL, they are the prefix,
+// start time and a counter, in hexadecimal.
+func newDocumentIDs(prefix uint16, start time.Time) *documentIDs {
+	return &documentIDs{base: fmt.Sprintf("%04x%012x", prefix, start.Unix()&0xffffffffffff)}
+}
+
+func (d *documentIDs) next() string {
+	return fmt.Sprintf("%s%016x", d.base, d.counter.Add(1))
+}
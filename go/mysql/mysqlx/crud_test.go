@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+)
+
+func docIdent(path ...string) *expr {
+	ci := &columnIdentifier{}
+	for _, p := range path {
+		ci.docPath = append(ci.docPath, docPathItem{typ: docPathMember, value: p})
+	}
+	return &expr{typ: exprIdent, identifier: ci}
+}
+
+func columnIdent(name string) *expr {
+	return &expr{typ: exprIdent, identifier: &columnIdentifier{name: name}}
+}
+
+func literal(s *scalar) *expr {
+	return &expr{typ: exprLiteral, literal: s}
+}
+
+func operator(name string, params ...*expr) *expr {
+	return &expr{typ: exprOperator, name: name, params: params}
+}
+
+func placeholder(position uint32) *expr {
+	return &expr{typ: exprPlaceholder, position: position}
+}
+
+func TestCrudSQL(t *testing.T) {
+	testcases := []struct {
+		name    string
+		msgType byte
+		crud    *crud
+		sql     string
+	}{{
+		name:    "find documents",
+		msgType: clientCrudFind,
+		crud: &crud{
+			schema:     "app",
+			collection: "people",
+			dataModel:  dataModelDocument,
+			criteria: operator("&&",
+				operator("==", docIdent("name"), placeholder(0)),
+				operator(">", docIdent("age"), literal(uintScalar(18)))),
+			order:          []order{{expr: docIdent("age"), desc: true}},
+			rowCount:       literal(uintScalar(10)),
+			offset:         literal(uintScalar(5)),
+			args:           []*scalar{stringScalar("alice")},
+			locking:        lockExclusive,
+			lockingOptions: lockSkipLocked,
+		},
+		sql: "SELECT doc FROM `app`.`people` WHERE ((JSON_EXTRACT(doc, '$.name') = 'alice') AND (JSON_EXTRACT(doc, '$.age') > 18)) ORDER BY JSON_EXTRACT(doc, '$.age') DESC LIMIT 5, 10 FOR UPDATE SKIP LOCKED",
+	}, {
+		name:    "find with a projection",
+		msgType: clientCrudFind,
+		crud: &crud{
+			collection: "people",
+			dataModel:  dataModelDocument,
+			projection: []projection{{source: docIdent("name"), alias: "n"}},
+		},
+		sql: "SELECT JSON_OBJECT('n', JSON_EXTRACT(doc, '$.name')) AS doc FROM `people`",
+	}, {
+		name:    "find rows",
+		msgType: clientCrudFind,
+		crud: &crud{
+			collection: "t",
+			dataModel:  dataModelTable,
+			projection: []projection{{source: columnIdent("a")}},
+			criteria:   operator("in", columnIdent("a"), literal(uintScalar(1)), literal(uintScalar(2))),
+		},
+		sql: "SELECT `a` FROM `t` WHERE (`a` IN (1, 2))",
+	}, {
+		name:    "insert rows",
+		msgType: clientCrudInsert,
+		crud: &crud{
+			collection: "t",
+			dataModel:  dataModelTable,
+			columns:    []columnIdentifier{{name: "a"}, {name: "b"}},
+			rows:       [][]*expr{{literal(uintScalar(1)), literal(stringScalar("x"))}},
+		},
+		sql: "INSERT INTO `t` (`a`, `b`) VALUES (1, 'x')",
+	}, {
+		name:    "update documents",
+		msgType: clientCrudUpdate,
+		crud: &crud{
+			collection: "people",
+			dataModel:  dataModelDocument,
+			criteria:   operator("==", docIdent("_id"), literal(stringScalar("1"))),
+			operations: []updateOperation{
+				{source: docIdent("age").identifier, typ: updateItemSet, value: literal(uintScalar(3))},
+				{source: docIdent("tmp").identifier, typ: updateItemRemove},
+			},
+		},
+		sql: "UPDATE `people` SET doc = JSON_REMOVE(JSON_SET(doc, '$.age', 3), '$.tmp') WHERE (JSON_EXTRACT(doc, '$._id') = '1')",
+	}, {
+		name:    "delete documents",
+		msgType: clientCrudDelete,
+		crud: &crud{
+			collection: "people",
+			dataModel:  dataModelDocument,
+			criteria:   operator("==", docIdent("age"), placeholder(0)),
+			rowCount:   literal(uintScalar(1)),
+			args:       []*scalar{uintScalar(30)},
+		},
+		sql: "DELETE FROM `people` WHERE (JSON_EXTRACT(doc, '$.age') = 30) LIMIT 1",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				sql string
+				err error
+			)
+			switch tc.msgType {
+			case clientCrudFind:
+				sql, err = tc.crud.findSQL()
+			case clientCrudInsert:
+				sql, _, err = tc.crud.insertSQL(nil)
+			case clientCrudUpdate:
+				sql, err = tc.crud.updateSQL()
+			case clientCrudDelete:
+				sql, err = tc.crud.deleteSQL()
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.sql, sql)
+		})
+	}
+}
+
+func TestCrudInsertDocuments(t *testing.T) {
+	ids := newDocumentIDs(1, time.Unix(100, 0))
+	c := &crud{
+		collection: "people",
+		dataModel:  dataModelDocument,
+		rows: [][]*expr{{{
+			typ:    exprObject,
+			object: []exprObjectField{{key: "name", value: literal(stringScalar("bob"))}},
+		}}},
+	}
+
+	sql, generated, err := c.insertSQL(ids.next)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `people` (doc) VALUES (JSON_OBJECT('name', 'bob', '_id', '00010000000000640000000000000001'))", sql)
+	assert.Equal(t, []string{"00010000000000640000000000000001"}, generated)
+
+	c.upsert = true
+	sql, generated, err = c.insertSQL(ids.next)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `people` (doc) VALUES (JSON_OBJECT('name', 'bob', '_id', '00010000000000640000000000000002')) ON DUPLICATE KEY UPDATE doc = VALUES(doc)", sql)
+	assert.Equal(t, []string{"00010000000000640000000000000002"}, generated)
+}
+
+func TestCrudErrors(t *testing.T) {
+	c := &crud{
+		collection: "people",
+		dataModel:  dataModelDocument,
+		operations: []updateOperation{{source: docIdent("_id").identifier, typ: updateItemSet, value: literal(uintScalar(3))}},
+	}
+	_, err := c.updateSQL()
+	assert.Equal(t, sqlerror.ErrorCode(ERXBadMemberToUpdate), sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Num)
+
+	c = &crud{
+		collection: "people",
+		dataModel:  dataModelDocument,
+		criteria:   operator("==", docIdent("age"), placeholder(0)),
+	}
+	_, err = c.deleteSQL()
+	assert.Equal(t, sqlerror.ErrorCode(ERXExprMissingArg), sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Num)
+
+	_, err = parseCrud(clientCrudFind, nil)
+	assert.Equal(t, sqlerror.ErrorCode(ERXInvalidCollection), sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError).Num)
+}
+
+func TestBindPlaceholders(t *testing.T) {
+	query, err := bindPlaceholders("select ?, '?', `?` -- ?\n, ?", []*anyValue{anyOf(uintScalar(1)), anyOf(stringScalar("it's"))})
+	require.NoError(t, err)
+	assert.Equal(t, "select 1, '?', `?` -- ?\n, 'it\\'s'", query)
+
+	query, err = bindPlaceholders("select ?", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "select ?", query)
+
+	_, err = bindPlaceholders("select ?, ?", []*anyValue{anyOf(uintScalar(1))})
+	assert.ErrorContains(t, err, "Too few arguments")
+
+	_, err = bindPlaceholders("select ?", []*anyValue{anyOf(uintScalar(1)), anyOf(uintScalar(2))})
+	assert.ErrorContains(t, err, "Too many arguments")
+}
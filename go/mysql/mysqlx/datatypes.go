@@ -0,0 +1,340 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// This file contains the Mysqlx.Datatypes messages.
+
+// scalarType is Mysqlx.Datatypes.Scalar.Type.
+type scalarType uint64
+
+const (
+	scalarSInt   scalarType = 1
+	scalarUInt   scalarType = 2
+	scalarNull   scalarType = 3
+	scalarOctets scalarType = 4
+	scalarDouble scalarType = 5
+	scalarFloat  scalarType = 6
+	scalarBool   scalarType = 7
+	scalarString scalarType = 8
+)
+
+// Content types of the octets, from Mysqlx.Resultset.ContentType_BYTES.
+const (
+	contentTypePlain    = 0
+	contentTypeGeometry = 1
+	contentTypeJSON     = 2
+	contentTypeXML      = 3
+)
+
+// scalar is Mysqlx.Datatypes.Scalar.
+type scalar struct {
+	typ         scalarType
+	signedInt   int64
+	unsignedInt uint64
+	// octets is the value of both the octets and the strings.
+	octets      []byte
+	contentType uint64
+	double      float64
+	bool        bool
+}
+
+func parseScalar(b []byte) (*scalar, error) {
+	s := &scalar{}
+	err := parseMessage(b, func(f field) error {
+		switch f.num {
+		case 1:
+			s.typ = scalarType(f.varint)
+		case 2:
+			s.signedInt = protowire.DecodeZigZag(f.varint)
+		case 3:
+			s.unsignedInt = f.varint
+		case 5, 9:
+			// Octets and String have the same layout, with the content
+			// type or the collation in their second field.
+			if err := f.expectBytes(); err != nil {
+				return err
+			}
+			return parseMessage(f.bytes, func(f field) error {
+				switch f.num {
+				case 1:
+					s.octets = f.bytes
+				case 2:
+					s.contentType = f.varint
+				}
+				return nil
+			})
+		case 6:
+			s.double = math.Float64frombits(f.varint)
+		case 7:
+			s.double = float64(math.Float32frombits(uint32(f.varint)))
+		case 8:
+			s.bool = f.varint != 0
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.typ == scalarString {
+		// Strings have a collation rather than a content type.
+		s.contentType = contentTypePlain
+	}
+	return s, nil
+}
+
+// stringValue returns the value of the strings and octets.
+func (s *scalar) stringValue() (string, bool) {
+	if s == nil || (s.typ != scalarString && s.typ != scalarOctets) {
+		return "", false
+	}
+	return string(s.octets), true
+}
+
+// sqlLiteral returns the SQL literal of the scalar.
+func (s *scalar) sqlLiteral() (string, error) {
+	switch s.typ {
+	case scalarSInt:
+		return strconv.FormatInt(s.signedInt, 10), nil
+	case scalarUInt:
+		return strconv.FormatUint(s.unsignedInt, 10), nil
+	case scalarNull:
+		return "NULL", nil
+	case scalarOctets, scalarString:
+		literal := sqltypes.EncodeStringSQL(string(s.octets))
+		if s.contentType == contentTypeJSON {
+			return "CAST(" + literal + " AS JSON)", nil
+		}
+		return literal, nil
+	case scalarDouble, scalarFloat:
+		if math.IsInf(s.double, 0) || math.IsNaN(s.double) {
+			return "", newError(ERXExprBadValue, "invalid floating point value %v", s.double)
+		}
+		bitSize := 64
+		if s.typ == scalarFloat {
+			bitSize = 32
+		}
+		literal := strconv.FormatFloat(s.double, 'g', -1, bitSize)
+		if _, err := strconv.ParseInt(literal, 10, 64); err == nil {
+			// Keep the integral values floating point.
+			literal += "e0"
+		}
+		return literal, nil
+	case scalarBool:
+		if s.bool {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	default:
+		return "", newError(ERXExprBadTypeValue, "invalid scalar type %d", s.typ)
+	}
+}
+
+func writeScalar(w *messageWriter, s *scalar) {
+	w.varint(1, uint64(s.typ))
+	switch s.typ {
+	case scalarSInt:
+		w.varint(2, protowire.EncodeZigZag(s.signedInt))
+	case scalarUInt:
+		w.varint(3, s.unsignedInt)
+	case scalarOctets:
+		w.message(5, func(w *messageWriter) {
+			w.bytes(1, s.octets)
+			if s.contentType != contentTypePlain {
+				w.varint(2, s.contentType)
+			}
+		})
+	case scalarDouble:
+		w.fixed64(6, math.Float64bits(s.double))
+	case scalarFloat:
+		w.fixed32(7, math.Float32bits(float32(s.double)))
+	case scalarBool:
+		w.bool(8, s.bool)
+	case scalarString:
+		w.message(9, func(w *messageWriter) {
+			w.bytes(1, s.octets)
+		})
+	}
+}
+
+// anyType is Mysqlx.Datatypes.Any.Type.
+type anyType uint64
+
+const (
+	anyScalar anyType = 1
+	anyObject anyType = 2
+	anyArray  anyType = 3
+)
+
+// anyValue is Mysqlx.Datatypes.Any.
+type anyValue struct {
+	typ    anyType
+	scalar *scalar
+	// object holds the fields of the objects, in order.
+	object []objectField
+	array  []*anyValue
+}
+
+type objectField struct {
+	key   string
+	value *anyValue
+}
+
+func parseAny(b []byte) (*anyValue, error) {
+	a := &anyValue{}
+	err := parseMessage(b, func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			a.typ = anyType(f.varint)
+		case 2:
+			if err = f.expectBytes(); err == nil {
+				a.scalar, err = parseScalar(f.bytes)
+			}
+		case 3:
+			if err = f.expectBytes(); err == nil {
+				err = parseMessage(f.bytes, func(f field) error {
+					if f.num != 1 {
+						return nil
+					}
+					fld, err := parseAnyObjectField(f.bytes)
+					if err != nil {
+						return err
+					}
+					a.object = append(a.object, fld)
+					return nil
+				})
+			}
+		case 4:
+			if err = f.expectBytes(); err == nil {
+				err = parseMessage(f.bytes, func(f field) error {
+					if f.num != 1 {
+						return nil
+					}
+					v, err := parseAny(f.bytes)
+					if err != nil {
+						return err
+					}
+					a.array = append(a.array, v)
+					return nil
+				})
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func parseAnyObjectField(b []byte) (objectField, error) {
+	var fld objectField
+	err := parseMessage(b, func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			fld.key = string(f.bytes)
+		case 2:
+			fld.value, err = parseAny(f.bytes)
+		}
+		return err
+	})
+	if err == nil && fld.value == nil {
+		err = fmt.Errorf("object field %q has no value", fld.key)
+	}
+	return fld, err
+}
+
+// get returns the value of a field of an object, or nil.
+func (a *anyValue) get(key string) *anyValue {
+	if a == nil {
+		return nil
+	}
+	for _, fld := range a.object {
+		if fld.key == key {
+			return fld.value
+		}
+	}
+	return nil
+}
+
+// stringValue returns the value of the string and octets scalars.
+func (a *anyValue) stringValue() (string, bool) {
+	if a == nil || a.typ != anyScalar {
+		return "", false
+	}
+	return a.scalar.stringValue()
+}
+
+// boolValue returns the value of the bool scalars.
+func (a *anyValue) boolValue() (bool, bool) {
+	if a == nil || a.typ != anyScalar || a.scalar == nil || a.scalar.typ != scalarBool {
+		return false, false
+	}
+	return a.scalar.bool, true
+}
+
+func writeAny(w *messageWriter, a *anyValue) {
+	w.varint(1, uint64(a.typ))
+	switch a.typ {
+	case anyScalar:
+		w.message(2, func(w *messageWriter) { writeScalar(w, a.scalar) })
+	case anyObject:
+		w.message(3, func(w *messageWriter) {
+			for _, fld := range a.object {
+				w.message(1, func(w *messageWriter) {
+					w.string(1, fld.key)
+					w.message(2, func(w *messageWriter) { writeAny(w, fld.value) })
+				})
+			}
+		})
+	case anyArray:
+		w.message(4, func(w *messageWriter) {
+			for _, v := range a.array {
+				w.message(1, func(w *messageWriter) { writeAny(w, v) })
+			}
+		})
+	}
+}
+
+// Helpers to build the values sent to the clients.
+
+func stringScalar(v string) *scalar {
+	return &scalar{typ: scalarString, octets: []byte(v)}
+}
+
+func uintScalar(v uint64) *scalar {
+	return &scalar{typ: scalarUInt, unsignedInt: v}
+}
+
+func anyOf(s *scalar) *anyValue {
+	return &anyValue{typ: anyScalar, scalar: s}
+}
+
+func anyArrayOf(values ...*anyValue) *anyValue {
+	return &anyValue{typ: anyArray, array: values}
+}
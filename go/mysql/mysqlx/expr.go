@@ -0,0 +1,546 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+)
+
+// This file contains the Mysqlx.Expr messages, and their translation to SQL.
+
+// exprType is Mysqlx.Expr.Expr.Type.
+type exprType uint64
+
+const (
+	exprIdent       exprType = 1
+	exprLiteral     exprType = 2
+	exprVariable    exprType = 3
+	exprFuncCall    exprType = 4
+	exprOperator    exprType = 5
+	exprPlaceholder exprType = 6
+	exprObject      exprType = 7
+	exprArray       exprType = 8
+)
+
+// expr is Mysqlx.Expr.Expr.
+type expr struct {
+	typ        exprType
+	identifier *columnIdentifier
+	literal    *scalar
+	// name is the name of the function calls and operators, and of the
+	// variables.
+	name       string
+	nameSchema string
+	// params are the parameters of the function calls and operators, and
+	// the values of the arrays.
+	params   []*expr
+	position uint32
+	object   []exprObjectField
+}
+
+type exprObjectField struct {
+	key   string
+	value *expr
+}
+
+// docPathItemType is Mysqlx.Expr.DocumentPathItem.Type.
+type docPathItemType uint64
+
+const (
+	docPathMember             docPathItemType = 1
+	docPathMemberAsterisk     docPathItemType = 2
+	docPathArrayIndex         docPathItemType = 3
+	docPathArrayIndexAsterisk docPathItemType = 4
+	docPathDoubleAsterisk     docPathItemType = 5
+)
+
+type docPathItem struct {
+	typ   docPathItemType
+	value string
+	index uint32
+}
+
+// columnIdentifier is Mysqlx.Expr.ColumnIdentifier.
+type columnIdentifier struct {
+	docPath    []docPathItem
+	name       string
+	tableName  string
+	schemaName string
+}
+
+func parseExpr(b []byte) (*expr, error) {
+	e := &expr{}
+	err := parseMessage(b, func(f field) error {
+		var err error
+		switch f.num {
+		case 1:
+			e.typ = exprType(f.varint)
+		case 2:
+			e.identifier, err = parseColumnIdentifier(f.bytes)
+		case 3:
+			e.name = string(f.bytes)
+		case 4:
+			e.literal, err = parseScalar(f.bytes)
+		case 5:
+			// FunctionCall: the name is an Identifier.
+			err = parseMessage(f.bytes, func(f field) error {
+				switch f.num {
+				case 1:
+					return parseMessage(f.bytes, func(f field) error {
+						switch f.num {
+						case 1:
+							e.name = string(f.bytes)
+						case 2:
+							e.nameSchema = string(f.bytes)
+						}
+						return nil
+					})
+				case 2:
+					param, err := parseExpr(f.bytes)
+					if err != nil {
+						return err
+					}
+					e.params = append(e.params, param)
+				}
+				return nil
+			})
+		case 6:
+			err = parseMessage(f.bytes, func(f field) error {
+				switch f.num {
+				case 1:
+					e.name = string(f.bytes)
+				case 2:
+					param, err := parseExpr(f.bytes)
+					if err != nil {
+						return err
+					}
+					e.params = append(e.params, param)
+				}
+				return nil
+			})
+		case 7:
+			e.position = uint32(f.varint)
+		case 8:
+			err = parseMessage(f.bytes, func(f field) error {
+				if f.num != 1 {
+					return nil
+				}
+				var fld exprObjectField
+				err := parseMessage(f.bytes, func(f field) error {
+					var err error
+					switch f.num {
+					case 1:
+						fld.key = string(f.bytes)
+					case 2:
+						fld.value, err = parseExpr(f.bytes)
+					}
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				if fld.value == nil {
+					return fmt.Errorf("object field %q has no value", fld.key)
+				}
+				e.object = append(e.object, fld)
+				return nil
+			})
+		case 9:
+			err = parseMessage(f.bytes, func(f field) error {
+				if f.num != 1 {
+					return nil
+				}
+				value, err := parseExpr(f.bytes)
+				if err != nil {
+					return err
+				}
+				e.params = append(e.params, value)
+				return nil
+			})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func parseColumnIdentifier(b []byte) (*columnIdentifier, error) {
+	ci := &columnIdentifier{}
+	err := parseMessage(b, func(f field) error {
+		switch f.num {
+		case 1:
+			item, err := parseDocPathItem(f.bytes)
+			if err != nil {
+				return err
+			}
+			ci.docPath = append(ci.docPath, item)
+		case 2:
+			ci.name = string(f.bytes)
+		case 3:
+			ci.tableName = string(f.bytes)
+		case 4:
+			ci.schemaName = string(f.bytes)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ci, nil
+}
+
+func parseDocPathItem(b []byte) (docPathItem, error) {
+	var item docPathItem
+	err := parseMessage(b, func(f field) error {
+		switch f.num {
+		case 1:
+			item.typ = docPathItemType(f.varint)
+		case 2:
+			item.value = string(f.bytes)
+		case 3:
+			item.index = uint32(f.varint)
+		}
+		return nil
+	})
+	return item, err
+}
+
+// docPathSQL returns the JSON path of a document path, e.g. $.a[1].
+func docPathSQL(path []docPathItem) (string, error) {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, item := range path {
+		switch item.typ {
+		case docPathMember:
+			b.WriteString(".")
+			if plainMember.MatchString(item.value) {
+				b.WriteString(item.value)
+			} else {
+				b.WriteString(`"`)
+				b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(item.value))
+				b.WriteString(`"`)
+			}
+		case docPathMemberAsterisk:
+			b.WriteString(".*")
+		case docPathArrayIndex:
+			fmt.Fprintf(&b, "[%d]", item.index)
+		case docPathArrayIndexAsterisk:
+			b.WriteString("[*]")
+		case docPathDoubleAsterisk:
+			b.WriteString("**")
+		default:
+			return "", newError(ERXExprBadValue, "invalid document path item type %d", item.typ)
+		}
+	}
+	return b.String(), nil
+}
+
+var (
+	plainMember  = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	functionName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	castType     = regexp.MustCompile(`(?i)^(BINARY|CHAR|DATE|DATETIME|TIME|DECIMAL|SIGNED|UNSIGNED|JSON)( ?\(\d+( ?, ?\d+)?\))?( INTEGER)?$`)
+	intervalUnit = regexp.MustCompile(`(?i)^(MICROSECOND|SECOND|MINUTE|HOUR|DAY|WEEK|MONTH|QUARTER|YEAR|SECOND_MICROSECOND|MINUTE_MICROSECOND|MINUTE_SECOND|HOUR_MICROSECOND|HOUR_SECOND|HOUR_MINUTE|DAY_MICROSECOND|DAY_SECOND|DAY_MINUTE|DAY_HOUR|YEAR_MONTH)$`)
+)
+
+// binaryOperators maps the X Protocol binary operators to the SQL ones.
+var binaryOperators = map[string]string{
+	"==":          "=",
+	"!=":          "!=",
+	"<>":          "<>",
+	">":           ">",
+	">=":          ">=",
+	"<":           "<",
+	"<=":          "<=",
+	"&":           "&",
+	"|":           "|",
+	"^":           "^",
+	"<<":          "<<",
+	">>":          ">>",
+	"+":           "+",
+	"-":           "-",
+	"*":           "*",
+	"/":           "/",
+	"div":         "DIV",
+	"%":           "%",
+	"is":          "IS",
+	"is_not":      "IS NOT",
+	"regexp":      "REGEXP",
+	"not_regexp":  "NOT REGEXP",
+	"&&":          "AND",
+	"||":          "OR",
+	"xor":         "XOR",
+	"sounds_like": "SOUNDS LIKE",
+}
+
+// unaryOperators maps the X Protocol unary operators to the SQL ones.
+var unaryOperators = map[string]string{
+	"!":          "NOT ",
+	"not":        "NOT ",
+	"~":          "~",
+	"sign_plus":  "+",
+	"sign_minus": "-",
+}
+
+// exprGenerator translates expressions to SQL.
+type exprGenerator struct {
+	// args are the values of the placeholders.
+	args []*scalar
+	// documentModel is set for the expressions on collections, whose
+	// identifiers are paths into the doc column.
+	documentModel bool
+}
+
+// sql returns the SQL of an expression.
+func (g *exprGenerator) sql(e *expr) (string, error) {
+	switch e.typ {
+	case exprIdent:
+		if e.identifier == nil {
+			return "", newError(ERXExprBadValue, "identifier expression without an identifier")
+		}
+		return g.identifierSQL(e.identifier)
+	case exprLiteral:
+		if e.literal == nil {
+			return "", newError(ERXExprBadValue, "literal expression without a value")
+		}
+		return e.literal.sqlLiteral()
+	case exprPlaceholder:
+		if int(e.position) >= len(g.args) {
+			return "", newError(ERXExprMissingArg, "no value for the placeholder %d", e.position)
+		}
+		return g.args[e.position].sqlLiteral()
+	case exprFuncCall:
+		if !functionName.MatchString(e.name) {
+			return "", newError(ERXExprBadValue, "invalid function name %q", e.name)
+		}
+		name := e.name
+		if e.nameSchema != "" {
+			name = sqlescape.EscapeID(e.nameSchema) + "." + sqlescape.EscapeID(e.name)
+		}
+		params, err := g.list(e.params)
+		if err != nil {
+			return "", err
+		}
+		return name + "(" + params + ")", nil
+	case exprOperator:
+		return g.operatorSQL(e)
+	case exprObject:
+		var b strings.Builder
+		b.WriteString("JSON_OBJECT(")
+		for i, fld := range e.object {
+			value, err := g.sql(fld.value)
+			if err != nil {
+				return "", err
+			}
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(sqltypes.EncodeStringSQL(fld.key))
+			b.WriteString(", ")
+			b.WriteString(value)
+		}
+		b.WriteString(")")
+		return b.String(), nil
+	case exprArray:
+		values, err := g.list(e.params)
+		if err != nil {
+			return "", err
+		}
+		return "JSON_ARRAY(" + values + ")", nil
+	case exprVariable:
+		return "", newError(ERXExprBadTypeValue, "variables are not supported")
+	default:
+		return "", newError(ERXExprBadTypeValue, "invalid expression type %d", e.typ)
+	}
+}
+
+func (g *exprGenerator) list(exprs []*expr) (string, error) {
+	values := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		value, err := g.sql(e)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, ", "), nil
+}
+
+func (g *exprGenerator) identifierSQL(ci *columnIdentifier) (string, error) {
+	var column string
+	switch {
+	case ci.name != "":
+		var parts []string
+		if ci.schemaName != "" {
+			parts = append(parts, ci.schemaName)
+		}
+		if ci.tableName != "" {
+			parts = append(parts, ci.tableName)
+		}
+		parts = append(parts, ci.name)
+		column = strings.Join(sqlescape.EscapeIDs(parts), ".")
+	case g.documentModel:
+		column = "doc"
+	default:
+		return "", newError(ERXExprBadValue, "identifier without a column name")
+	}
+	if len(ci.docPath) == 0 {
+		return column, nil
+	}
+	path, err := docPathSQL(ci.docPath)
+	if err != nil {
+		return "", err
+	}
+	return "JSON_EXTRACT(" + column + ", " + sqltypes.EncodeStringSQL(path) + ")", nil
+}
+
+func (g *exprGenerator) operatorSQL(e *expr) (string, error) {
+	params := make([]string, 0, len(e.params))
+	for _, param := range e.params {
+		value, err := g.sql(param)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, value)
+	}
+	numParams := func(min, max int) error {
+		if len(params) < min || len(params) > max {
+			return newError(ERXExprBadNumArgs, "invalid number of parameters for the operator %q: %d", e.name, len(params))
+		}
+		return nil
+	}
+
+	if op, ok := binaryOperators[e.name]; ok {
+		if e.name == "*" && len(params) == 0 {
+			// As in COUNT(*).
+			return "*", nil
+		}
+		if err := numParams(2, 2); err != nil {
+			return "", err
+		}
+		return "(" + params[0] + " " + op + " " + params[1] + ")", nil
+	}
+	if op, ok := unaryOperators[e.name]; ok {
+		if err := numParams(1, 1); err != nil {
+			return "", err
+		}
+		return "(" + op + params[0] + ")", nil
+	}
+
+	switch e.name {
+	case "like", "not_like":
+		if err := numParams(2, 3); err != nil {
+			return "", err
+		}
+		op := " LIKE "
+		if e.name == "not_like" {
+			op = " NOT LIKE "
+		}
+		sql := params[0] + op + params[1]
+		if len(params) == 3 {
+			sql += " ESCAPE " + params[2]
+		}
+		return "(" + sql + ")", nil
+	case "in", "not_in":
+		if err := numParams(2, len(params)); err != nil {
+			return "", err
+		}
+		op := " IN "
+		if e.name == "not_in" {
+			op = " NOT IN "
+		}
+		return "(" + params[0] + op + "(" + strings.Join(params[1:], ", ") + "))", nil
+	case "cont_in", "not_cont_in":
+		if err := numParams(2, 2); err != nil {
+			return "", err
+		}
+		sql := "JSON_CONTAINS(" + g.jsonSQL(e.params[1], params[1]) + ", " + g.jsonSQL(e.params[0], params[0]) + ")"
+		if e.name == "not_cont_in" {
+			sql = "(NOT " + sql + ")"
+		}
+		return sql, nil
+	case "overlaps", "not_overlaps":
+		if err := numParams(2, 2); err != nil {
+			return "", err
+		}
+		sql := "JSON_OVERLAPS(" + g.jsonSQL(e.params[0], params[0]) + ", " + g.jsonSQL(e.params[1], params[1]) + ")"
+		if e.name == "not_overlaps" {
+			sql = "(NOT " + sql + ")"
+		}
+		return sql, nil
+	case "between", "not_between":
+		if err := numParams(3, 3); err != nil {
+			return "", err
+		}
+		op := " BETWEEN "
+		if e.name == "not_between" {
+			op = " NOT BETWEEN "
+		}
+		return "(" + params[0] + op + params[1] + " AND " + params[2] + ")", nil
+	case "cast":
+		if err := numParams(2, 2); err != nil {
+			return "", err
+		}
+		typ, ok := e.params[1].literal.stringValue()
+		if e.params[1].typ != exprLiteral || !ok || !castType.MatchString(typ) {
+			return "", newError(ERXExprBadValue, "invalid type for the cast operator")
+		}
+		return "CAST(" + params[0] + " AS " + strings.ToUpper(typ) + ")", nil
+	case "date_add", "date_sub":
+		if err := numParams(3, 3); err != nil {
+			return "", err
+		}
+		unit, ok := e.params[2].literal.stringValue()
+		if e.params[2].typ != exprLiteral || !ok || !intervalUnit.MatchString(unit) {
+			return "", newError(ERXExprBadValue, "invalid unit for the %s operator", e.name)
+		}
+		return strings.ToUpper(e.name) + "(" + params[0] + ", INTERVAL " + params[1] + " " + strings.ToUpper(unit) + ")", nil
+	default:
+		return "", newError(ERXExprBadOperator, "invalid operator %q", e.name)
+	}
+}
+
+// jsonSQL returns the SQL of an expression as a JSON value, as needed by the
+// JSON functions.
+func (g *exprGenerator) jsonSQL(e *expr, sql string) string {
+	switch e.typ {
+	case exprObject, exprArray:
+		return sql
+	case exprIdent:
+		if len(e.identifier.docPath) > 0 || e.identifier.name == "" {
+			return sql
+		}
+	case exprLiteral, exprPlaceholder:
+		lit := e.literal
+		if e.typ == exprPlaceholder {
+			lit = g.args[e.position]
+		}
+		switch {
+		case lit.typ == scalarOctets && lit.contentType == contentTypeJSON:
+			return sql
+		case lit.typ == scalarString || lit.typ == scalarOctets:
+			return "JSON_QUOTE(" + sql + ")"
+		}
+	}
+	return "CAST(" + sql + " AS JSON)"
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mysqlx is the server side of the MySQL X Protocol, the protobuf
+// based protocol spoken by the X DevAPI connectors on port 33060.
+//
+// It supports the connection, session, SQL and CRUD message sets, which is
+// what the connectors need for their basic operations. Prepared statements,
+// cursors, compression and notices subscriptions are not supported: the
+// connectors detect it from the errors they get and fall back.
+//
+// The messages are encoded and decoded with protowire from the field numbers
+// of the mysqlx*.proto files that ship with MySQL, rather than from code
+// generated from those files.
+package mysqlx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Client message types, from Mysqlx.ClientMessages.Type.
+const (
+	clientConCapabilitiesGet    = 1
+	clientConCapabilitiesSet    = 2
+	clientConClose              = 3
+	clientSessAuthenticateStart = 4
+	clientSessAuthenticateCont  = 5
+	clientSessReset             = 6
+	clientSessClose             = 7
+	clientSQLStmtExecute        = 12
+	clientCrudFind              = 17
+	clientCrudInsert            = 18
+	clientCrudUpdate            = 19
+	clientCrudDelete            = 20
+	clientExpectOpen            = 24
+	clientExpectClose           = 25
+)
+
+// Server message types, from Mysqlx.ServerMessages.Type.
+const (
+	serverOk                   = 0
+	serverError                = 1
+	serverConnCapabilities     = 2
+	serverSessAuthenticateCont = 3
+	serverSessAuthenticateOk   = 4
+	serverNotice               = 11
+	serverResultsetColumnMeta  = 12
+	serverResultsetRow         = 13
+	serverResultsetFetchDone   = 14
+	serverSQLStmtExecuteOk     = 17
+)
+
+const (
+	// messageHeaderSize is the size of the length and type of a message.
+	messageHeaderSize = 5
+
+	// DefaultMaxMessageSize is the default size limit of the messages of
+	// the clients, as mysqlx_max_allowed_packet in MySQL.
+	DefaultMaxMessageSize = 64 * 1024 * 1024
+)
+
+// Error codes of the X Plugin, sent along with the MySQL ones.
+const (
+	// ERXBadMessage is sent when a message cannot be decoded.
+	ERXBadMessage = 5000
+	// ERXCapabilitiesPrepareFailed is sent when a capability cannot be set.
+	ERXCapabilitiesPrepareFailed = 5001
+	// ERXCapabilityNotFound is sent for the capabilities we don't know.
+	ERXCapabilityNotFound = 5002
+	// ERXBadInsertData is sent for the inserts that have no rows or
+	// mismatched columns.
+	ERXBadInsertData = 5013
+	// ERXCmdNumArguments is sent for the admin commands with missing
+	// arguments.
+	ERXCmdNumArguments = 5015
+	// ERXCmdArgumentType is sent for the admin commands with arguments of
+	// the wrong type.
+	ERXCmdArgumentType = 5016
+	// ERXBadUpdateData is sent for the updates that have no operations.
+	ERXBadUpdateData = 5050
+	// ERXBadMemberToUpdate is sent for the updates of the _id of documents.
+	ERXBadMemberToUpdate = 5053
+	// ERXInvalidCollection is sent for the CRUD messages without a
+	// collection.
+	ERXInvalidCollection = 5113
+	// ERXExprBadOperator is sent for the operators we don't know.
+	ERXExprBadOperator = 5150
+	// ERXExprBadNumArgs is sent for the operators with the wrong number of
+	// parameters.
+	ERXExprBadNumArgs = 5151
+	// ERXExprMissingArg is sent for the placeholders without an argument.
+	ERXExprMissingArg = 5152
+	// ERXExprBadTypeValue is sent for the expressions we don't support.
+	ERXExprBadTypeValue = 5153
+	// ERXExprBadValue is sent for the expressions with invalid values.
+	ERXExprBadValue = 5154
+	// ERXInvalidAdminCommand is sent for the admin commands we don't know.
+	ERXInvalidAdminCommand = 5157
+	// ERXInvalidNamespace is sent for the statements of an unknown
+	// namespace.
+	ERXInvalidNamespace = 5162
+)
+
+// readMessage reads the header and payload of a message. The payload is read
+// into buf if it fits.
+func readMessage(r io.Reader, buf []byte, maxSize uint32) (byte, []byte, error) {
+	var header [messageHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	// The length includes the type byte.
+	length := binary.LittleEndian.Uint32(header[:4])
+	if length == 0 {
+		return 0, nil, fmt.Errorf("invalid message length 0")
+	}
+	if length > maxSize {
+		return 0, nil, fmt.Errorf("message of %d bytes is larger than the maximum of %d bytes", length, maxSize)
+	}
+	size := int(length - 1)
+	if cap(buf) < size {
+		buf = make([]byte, size)
+	}
+	buf = buf[:size]
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, nil, err
+	}
+	return header[4], buf, nil
+}
+
+// writeMessage writes a message with its header.
+func writeMessage(w io.Writer, msgType byte, payload []byte) error {
+	var header [messageHeaderSize]byte
+	binary.LittleEndian.PutUint32(header[:4], uint32(len(payload)+1))
+	header[4] = msgType
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
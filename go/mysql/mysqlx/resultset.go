@@ -0,0 +1,330 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// This file contains the Mysqlx.Resultset messages, the encoding of the
+// results of the queries.
+
+// fieldType is Mysqlx.Resultset.ColumnMetaData.FieldType.
+type fieldType uint64
+
+const (
+	fieldSInt     fieldType = 1
+	fieldUInt     fieldType = 2
+	fieldDouble   fieldType = 5
+	fieldFloat    fieldType = 6
+	fieldBytes    fieldType = 7
+	fieldTime     fieldType = 10
+	fieldDatetime fieldType = 12
+	fieldSet      fieldType = 15
+	fieldEnum     fieldType = 16
+	fieldBit      fieldType = 17
+	fieldDecimal  fieldType = 18
+)
+
+// Flags of the columns, some of which depend on the type.
+const (
+	columnFlagTypeSpecific  = 0x0001
+	columnFlagNotNull       = 0x0010
+	columnFlagPrimaryKey    = 0x0020
+	columnFlagUniqueKey     = 0x0040
+	columnFlagMultipleKey   = 0x0080
+	columnFlagAutoIncrement = 0x0100
+)
+
+// columnFlags maps the MySQL flags of the columns to the X Protocol ones.
+var columnFlags = map[querypb.MySqlFlag]uint32{
+	querypb.MySqlFlag_NOT_NULL_FLAG:       columnFlagNotNull,
+	querypb.MySqlFlag_PRI_KEY_FLAG:        columnFlagPrimaryKey,
+	querypb.MySqlFlag_UNIQUE_KEY_FLAG:     columnFlagUniqueKey,
+	querypb.MySqlFlag_MULTIPLE_KEY_FLAG:   columnFlagMultipleKey,
+	querypb.MySqlFlag_AUTO_INCREMENT_FLAG: columnFlagAutoIncrement,
+}
+
+// xFieldType returns the X Protocol type of a column, and its content type.
+func xFieldType(typ querypb.Type) (fieldType, uint64) {
+	switch {
+	case typ == querypb.Type_YEAR:
+		return fieldUInt, contentTypePlain
+	case sqltypes.IsSigned(typ):
+		return fieldSInt, contentTypePlain
+	case sqltypes.IsUnsigned(typ):
+		return fieldUInt, contentTypePlain
+	}
+	switch typ {
+	case querypb.Type_FLOAT32:
+		return fieldFloat, contentTypePlain
+	case querypb.Type_FLOAT64:
+		return fieldDouble, contentTypePlain
+	case querypb.Type_DECIMAL:
+		return fieldDecimal, contentTypePlain
+	case querypb.Type_DATE, querypb.Type_DATETIME, querypb.Type_TIMESTAMP:
+		return fieldDatetime, contentTypePlain
+	case querypb.Type_TIME:
+		return fieldTime, contentTypePlain
+	case querypb.Type_BIT:
+		return fieldBit, contentTypePlain
+	case querypb.Type_ENUM:
+		return fieldEnum, contentTypePlain
+	case querypb.Type_SET:
+		return fieldSet, contentTypePlain
+	case querypb.Type_JSON:
+		return fieldBytes, contentTypeJSON
+	case querypb.Type_GEOMETRY:
+		return fieldBytes, contentTypeGeometry
+	default:
+		return fieldBytes, contentTypePlain
+	}
+}
+
+// columnMetaData encodes the ColumnMetaData of a field.
+func columnMetaData(f *querypb.Field) []byte {
+	typ, contentType := xFieldType(f.Type)
+
+	flags := uint32(0)
+	for mysqlFlag, xFlag := range columnFlags {
+		if f.Flags&uint32(mysqlFlag) != 0 {
+			flags |= xFlag
+		}
+	}
+	switch typ {
+	case fieldSInt, fieldUInt:
+		if f.Flags&uint32(querypb.MySqlFlag_ZEROFILL_FLAG) != 0 {
+			flags |= columnFlagTypeSpecific
+		}
+	case fieldDouble, fieldFloat, fieldDecimal:
+		if f.Flags&uint32(querypb.MySqlFlag_UNSIGNED_FLAG) != 0 {
+			flags |= columnFlagTypeSpecific
+		}
+	case fieldDatetime:
+		if f.Type == querypb.Type_TIMESTAMP {
+			flags |= columnFlagTypeSpecific
+		}
+	}
+
+	var w messageWriter
+	w.varint(1, uint64(typ))
+	w.string(2, f.Name)
+	w.string(3, f.OrgName)
+	w.string(4, f.Table)
+	w.string(5, f.OrgTable)
+	w.string(6, f.Database)
+	w.string(7, "def")
+	if typ == fieldBytes || typ == fieldEnum || typ == fieldSet {
+		w.varint(8, uint64(f.Charset))
+	}
+	switch typ {
+	case fieldDouble, fieldFloat, fieldDecimal, fieldDatetime, fieldTime:
+		w.varint(9, uint64(f.Decimals))
+	}
+	w.varint(10, uint64(f.ColumnLength))
+	w.varint(11, uint64(flags))
+	if contentType != contentTypePlain {
+		w.varint(12, contentType)
+	}
+	return w.b
+}
+
+// row encodes the Row of the values of a row.
+func row(fields []*querypb.Field, values []sqltypes.Value) ([]byte, error) {
+	var w messageWriter
+	for i, v := range values {
+		if v.IsNull() {
+			w.bytes(1, nil)
+			continue
+		}
+		typ, _ := xFieldType(fields[i].Type)
+		b, err := encodeValue(typ, v)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", fields[i].Name, err)
+		}
+		w.bytes(1, b)
+	}
+	return w.b, nil
+}
+
+// encodeValue encodes a non-NULL value as the X Protocol expects it for its
+// type.
+func encodeValue(typ fieldType, v sqltypes.Value) ([]byte, error) {
+	raw := v.RawStr()
+	switch typ {
+	case fieldSInt:
+		i, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendVarint(nil, protowire.EncodeZigZag(i)), nil
+	case fieldUInt:
+		u, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendVarint(nil, u), nil
+	case fieldDouble:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint64(nil, math.Float64bits(f)), nil
+	case fieldFloat:
+		f, err := strconv.ParseFloat(raw, 32)
+		if err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.AppendUint32(nil, math.Float32bits(float32(f))), nil
+	case fieldDecimal:
+		return encodeDecimal(raw)
+	case fieldDatetime:
+		return encodeDatetime(raw)
+	case fieldTime:
+		return encodeTime(raw)
+	case fieldBit:
+		var u uint64
+		for _, c := range v.Raw() {
+			u = u<<8 | uint64(c)
+		}
+		return protowire.AppendVarint(nil, u), nil
+	case fieldSet:
+		if raw == "" {
+			// The empty set, which differs from the set of the empty string.
+			return []byte{0x01}, nil
+		}
+		var b []byte
+		for _, elem := range strings.Split(raw, ",") {
+			b = protowire.AppendString(b, elem)
+		}
+		return b, nil
+	default:
+		// The bytes are terminated by a 0x00 to tell the empty ones from
+		// the NULLs.
+		return append(append(make([]byte, 0, len(raw)+1), raw...), 0x00), nil
+	}
+}
+
+// encodeDecimal encodes a decimal: its scale, then its digits in BCD, then
+// its sign in the next nibble.
+func encodeDecimal(raw string) ([]byte, error) {
+	sign := byte(0xc)
+	if strings.HasPrefix(raw, "-") {
+		sign = 0xd
+		raw = raw[1:]
+	}
+	scale := 0
+	if dot := strings.IndexByte(raw, '.'); dot >= 0 {
+		scale = len(raw) - dot - 1
+		raw = raw[:dot] + raw[dot+1:]
+	}
+	if scale > math.MaxUint8 {
+		return nil, fmt.Errorf("invalid decimal scale %d", scale)
+	}
+	nibbles := make([]byte, 0, len(raw)+2)
+	for _, c := range []byte(raw) {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("invalid decimal %q", raw)
+		}
+		nibbles = append(nibbles, c-'0')
+	}
+	nibbles = append(nibbles, sign)
+	if len(nibbles)%2 != 0 {
+		nibbles = append(nibbles, 0)
+	}
+	b := make([]byte, 0, 1+len(nibbles)/2)
+	b = append(b, byte(scale))
+	for i := 0; i < len(nibbles); i += 2 {
+		b = append(b, nibbles[i]<<4|nibbles[i+1])
+	}
+	return b, nil
+}
+
+// encodeDatetime encodes a date or datetime as the varints of its parts. The
+// dates only have their year, month and day.
+func encodeDatetime(raw string) ([]byte, error) {
+	date, clock, hasClock := strings.Cut(raw, " ")
+	parts := strings.Split(date, "-")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid datetime %q", raw)
+	}
+	if hasClock {
+		t, err := timeParts(clock)
+		if err != nil {
+			return nil, fmt.Errorf("invalid datetime %q", raw)
+		}
+		parts = append(parts, t...)
+	}
+	var b []byte
+	for _, p := range parts {
+		u, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid datetime %q", raw)
+		}
+		b = protowire.AppendVarint(b, u)
+	}
+	return b, nil
+}
+
+// encodeTime encodes a time as its sign, then the varints of its parts.
+func encodeTime(raw string) ([]byte, error) {
+	b := []byte{0x00}
+	if strings.HasPrefix(raw, "-") {
+		b[0] = 0x01
+		raw = raw[1:]
+	}
+	parts, err := timeParts(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q", raw)
+	}
+	for _, p := range parts {
+		u, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time %q", raw)
+		}
+		b = protowire.AppendVarint(b, u)
+	}
+	return b, nil
+}
+
+// timeParts splits hh:mm:ss[.ffffff] in its hours, minutes, seconds and,
+// if any, microseconds.
+func timeParts(clock string) ([]string, error) {
+	hms, frac, hasFrac := strings.Cut(clock, ".")
+	parts := strings.Split(hms, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid time %q", clock)
+	}
+	if hasFrac && strings.Trim(frac, "0") != "" {
+		// The fractional part are microseconds, whatever its precision.
+		if len(frac) > 6 {
+			return nil, fmt.Errorf("invalid time %q", clock)
+		}
+		parts = append(parts, frac+strings.Repeat("0", 6-len(frac)))
+	}
+	return parts, nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"crypto/tls"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/tb"
+	"vitess.io/vitess/go/vt/log"
+)
+
+var (
+	connCount    = stats.NewGauge("MysqlxServerConnCount", "Active X Protocol server connections")
+	connAccept   = stats.NewCounter("MysqlxServerConnAccepted", "X Protocol server connections accepted")
+	connAuthOK   = stats.NewCounter("MysqlxServerConnAuthOK", "X Protocol server connections authenticated")
+	connAuthFail = stats.NewCounter("MysqlxServerConnAuthFail", "X Protocol server connections that failed to authenticate")
+)
+
+// Handler is the interface the Listener calls for the clients' sessions.
+// The calls for a given Conn are not concurrent.
+type Handler interface {
+	// NewConnection is called when a client authenticated.
+	NewConnection(c *Conn)
+
+	// ConnectionClosed is called when the session of an authenticated
+	// client is closed, with its connection or not.
+	ConnectionClosed(c *Conn)
+
+	// ComQuery executes a SQL statement, with the same contract as
+	// mysql.Handler.ComQuery: the first result passed to the callback has
+	// the fields of the result set, if any.
+	ComQuery(c *Conn, query string, callback func(*sqltypes.Result) error) error
+
+	// ComResetConnection resets the session of a client, as the
+	// COM_RESET_CONNECTION of the MySQL binary protocol.
+	ComResetConnection(c *Conn)
+}
+
+// Listener is the X Protocol server. It authenticates the clients with the
+// mysql_native_password and mysql_clear_password methods of an AuthServer,
+// as the MYSQL41 and PLAIN mechanisms of the X Protocol.
+type Listener struct {
+	listener   net.Listener
+	authServer mysql.AuthServer
+	handler    Handler
+
+	// connectionID is the id of the next connection, only used by Accept.
+	connectionID uint32
+
+	connReadTimeout  time.Duration
+	connWriteTimeout time.Duration
+
+	// TLSConfig, if set, lets the clients enable TLS through the tls
+	// capability.
+	TLSConfig atomic.Pointer[tls.Config]
+
+	// RequireSecureTransport rejects the clients that authenticate without
+	// TLS.
+	RequireSecureTransport bool
+
+	// AllowClearTextWithoutTLS allows the PLAIN mechanism without TLS.
+	AllowClearTextWithoutTLS atomic.Bool
+
+	// MaxMessageSize is the size limit of the messages of the clients.
+	MaxMessageSize uint32
+
+	// documentIDs generates the _id of the documents inserted without one.
+	documentIDs *documentIDs
+
+	shutdown atomic.Bool
+}
+
+// NewListener creates a Listener on the given protocol and address.
+func NewListener(protocol, address string, authServer mysql.AuthServer, handler Handler, connReadTimeout, connWriteTimeout time.Duration) (*Listener, error) {
+	listener, err := net.Listen(protocol, address)
+	if err != nil {
+		return nil, err
+	}
+	return newListener(listener, authServer, handler, connReadTimeout, connWriteTimeout), nil
+}
+
+func newListener(listener net.Listener, authServer mysql.AuthServer, handler Handler, connReadTimeout, connWriteTimeout time.Duration) *Listener {
+	return &Listener{
+		listener:         listener,
+		authServer:       authServer,
+		handler:          handler,
+		connectionID:     1,
+		connReadTimeout:  connReadTimeout,
+		connWriteTimeout: connWriteTimeout,
+		MaxMessageSize:   DefaultMaxMessageSize,
+		documentIDs:      newDocumentIDs(0, time.Now()),
+	}
+}
+
+// Addr returns the listener address.
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Accept runs an accept loop until the listener is closed.
+func (l *Listener) Accept() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			// Close() was probably called.
+			return
+		}
+
+		connectionID := l.connectionID
+		l.connectionID++
+
+		connCount.Add(1)
+		connAccept.Add(1)
+		go l.handle(conn, connectionID)
+	}
+}
+
+// handle serves a client until it disconnects.
+func (l *Listener) handle(conn net.Conn, connectionID uint32) {
+	c := newConn(conn, l, connectionID)
+	defer connCount.Add(-1)
+	defer c.Close()
+	defer func() {
+		if x := recover(); x != nil {
+			log.Errorf("mysqlx_server caught panic:\n%v\n%s", x, tb.Stack(4))
+		}
+	}()
+	defer c.closeSession()
+
+	for {
+		msgType, payload, err := c.readMessage()
+		if err != nil {
+			if !c.IsClosed() && !isEOF(err) {
+				log.Warningf("Error reading message from X Protocol client %d: %v", c.ConnectionID, err)
+			}
+			return
+		}
+		if !c.dispatch(msgType, payload) {
+			return
+		}
+	}
+}
+
+// Close stops listening for new connections, the open connections are not
+// closed.
+func (l *Listener) Close() {
+	l.listener.Close()
+}
+
+// Shutdown closes the listener, and makes the connections reject their new
+// statements outside of transactions.
+func (l *Listener) Shutdown() {
+	if l.shutdown.CompareAndSwap(false, true) {
+		l.Close()
+	}
+}
+
+// IsShutdown returns whether Shutdown was called.
+func (l *Listener) IsShutdown() bool {
+	return l.shutdown.Load()
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"bufio"
+	"encoding/hex"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// testHandler records the queries of the clients, and returns a single
+// integer column for the SELECTs.
+type testHandler struct {
+	mu      sync.Mutex
+	queries []string
+	opened  int
+	closed  int
+}
+
+func (th *testHandler) NewConnection(c *Conn) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	th.opened++
+}
+
+func (th *testHandler) ConnectionClosed(c *Conn) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	th.closed++
+}
+
+func (th *testHandler) ComQuery(c *Conn, query string, callback func(*sqltypes.Result) error) error {
+	th.mu.Lock()
+	th.queries = append(th.queries, query)
+	th.mu.Unlock()
+	if !strings.HasPrefix(strings.ToLower(query), "select") {
+		return callback(&sqltypes.Result{RowsAffected: 1})
+	}
+	return callback(&sqltypes.Result{
+		Fields: []*querypb.Field{{Name: "id", Type: querypb.Type_INT64}},
+		Rows:   [][]sqltypes.Value{{sqltypes.NewInt64(-2)}},
+	})
+}
+
+func (th *testHandler) ComResetConnection(c *Conn) {}
+
+func (th *testHandler) lastQuery() string {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	if len(th.queries) == 0 {
+		return ""
+	}
+	return th.queries[len(th.queries)-1]
+}
+
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func (tc *testClient) write(msgType byte, payload []byte) {
+	require.NoError(tc.t, writeMessage(tc.conn, msgType, payload))
+}
+
+func (tc *testClient) read() (byte, []byte) {
+	msgType, payload, err := readMessage(tc.reader, nil, DefaultMaxMessageSize)
+	require.NoError(tc.t, err)
+	return msgType, payload
+}
+
+// readUntil reads the messages up to one of the given type, skipping the
+// notices, and returns the types of the others and the payload of the last.
+func (tc *testClient) readUntil(last byte) ([]byte, []byte) {
+	var types []byte
+	for {
+		msgType, payload := tc.read()
+		if msgType == last {
+			return types, payload
+		}
+		if msgType == serverError {
+			code, msg := errorMessage(payload)
+			tc.t.Fatalf("unexpected error %d: %s", code, msg)
+		}
+		if msgType != serverNotice {
+			types = append(types, msgType)
+		}
+	}
+}
+
+func errorMessage(payload []byte) (code uint64, msg string) {
+	_ = parseMessage(payload, func(f field) error {
+		switch f.num {
+		case 2:
+			code = f.varint
+		case 3:
+			msg = string(f.bytes)
+		}
+		return nil
+	})
+	return code, msg
+}
+
+func (tc *testClient) authenticate(user, password string) (byte, []byte) {
+	var w messageWriter
+	w.string(1, mechanismMySQL41)
+	tc.write(clientSessAuthenticateStart, w.b)
+
+	msgType, payload := tc.read()
+	require.EqualValues(tc.t, serverSessAuthenticateCont, msgType)
+	var salt []byte
+	require.NoError(tc.t, parseMessage(payload, func(f field) error {
+		salt = f.bytes
+		return nil
+	}))
+
+	scramble := mysql.ScrambleMysqlNativePassword(salt, []byte(password))
+	w = messageWriter{}
+	w.bytes(1, []byte("\x00"+user+"\x00*"+hex.EncodeToString(scramble)))
+	tc.write(clientSessAuthenticateCont, w.b)
+
+	for {
+		msgType, payload = tc.read()
+		if msgType != serverNotice {
+			return msgType, payload
+		}
+	}
+}
+
+func newTestServer(t *testing.T) (*testHandler, *testClient) {
+	authServer := mysql.NewAuthServerStatic("", `{"user1": [{"Password": "password1", "UserData": "userData1"}]}`, 0)
+	th := &testHandler{}
+	l, err := NewListener("tcp", "127.0.0.1:0", authServer, th, 0, 0)
+	require.NoError(t, err)
+	t.Cleanup(l.Close)
+	go l.Accept()
+
+	conn, err := net.DialTimeout("tcp", l.Addr().String(), 10*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return th, &testClient{t: t, conn: conn, reader: bufio.NewReader(conn)}
+}
+
+func TestServerCapabilities(t *testing.T) {
+	_, client := newTestServer(t)
+
+	client.write(clientConCapabilitiesGet, nil)
+	msgType, payload := client.read()
+	require.EqualValues(t, serverConnCapabilities, msgType)
+
+	// The capabilities are a list of name and value pairs.
+	capabilities := map[string]*anyValue{}
+	require.NoError(t, parseMessage(payload, func(f field) error {
+		var name string
+		var value *anyValue
+		err := parseMessage(f.bytes, func(f field) error {
+			var err error
+			switch f.num {
+			case 1:
+				name = string(f.bytes)
+			case 2:
+				value, err = parseAny(f.bytes)
+			}
+			return err
+		})
+		capabilities[name] = value
+		return err
+	}))
+	require.Contains(t, capabilities, "authentication.mechanisms")
+	mechanism, ok := capabilities["authentication.mechanisms"].array[0].stringValue()
+	require.True(t, ok)
+	assert.Equal(t, mechanismMySQL41, mechanism)
+	assert.NotContains(t, capabilities, "tls")
+
+	// The capabilities we don't know are rejected.
+	var w messageWriter
+	w.message(1, func(w *messageWriter) {
+		w.message(1, func(w *messageWriter) {
+			w.string(1, "unknown")
+			w.message(2, func(w *messageWriter) { writeAny(w, anyOf(uintScalar(1))) })
+		})
+	})
+	client.write(clientConCapabilitiesSet, w.b)
+	msgType, payload = client.read()
+	require.EqualValues(t, serverError, msgType)
+	code, _ := errorMessage(payload)
+	assert.EqualValues(t, ERXCapabilityNotFound, code)
+}
+
+func TestServerAuthentication(t *testing.T) {
+	th, client := newTestServer(t)
+
+	msgType, payload := client.authenticate("user1", "wrong")
+	require.EqualValues(t, serverError, msgType)
+	code, _ := errorMessage(payload)
+	assert.EqualValues(t, 1045, code)
+
+	msgType, _ = client.authenticate("user1", "password1")
+	require.EqualValues(t, serverSessAuthenticateOk, msgType)
+
+	th.mu.Lock()
+	assert.Equal(t, 1, th.opened)
+	th.mu.Unlock()
+
+	client.write(clientSessClose, nil)
+	msgType, _ = client.read()
+	assert.EqualValues(t, serverOk, msgType)
+	assert.Eventually(t, func() bool {
+		th.mu.Lock()
+		defer th.mu.Unlock()
+		return th.closed == 1
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestServerStatements(t *testing.T) {
+	th, client := newTestServer(t)
+
+	// The statements need an authenticated session.
+	var w messageWriter
+	w.string(1, "select 1")
+	client.write(clientSQLStmtExecute, w.b)
+	msgType, _ := client.read()
+	require.EqualValues(t, serverError, msgType)
+
+	msgType, _ = client.authenticate("user1", "password1")
+	require.EqualValues(t, serverSessAuthenticateOk, msgType)
+
+	w = messageWriter{}
+	w.string(1, "select ?")
+	w.message(2, func(w *messageWriter) { writeAny(w, anyOf(uintScalar(7))) })
+	client.write(clientSQLStmtExecute, w.b)
+	types, _ := client.readUntil(serverSQLStmtExecuteOk)
+	assert.Equal(t, []byte{serverResultsetColumnMeta, serverResultsetRow, serverResultsetFetchDone}, types)
+	assert.Equal(t, "select 7", th.lastQuery())
+
+	// A find of a collection, with a criteria on a document field.
+	w = messageWriter{}
+	w.message(2, func(w *messageWriter) { w.string(1, "people") })
+	w.varint(3, dataModelDocument)
+	w.message(5, func(w *messageWriter) {
+		w.varint(1, uint64(exprOperator))
+		w.message(6, func(w *messageWriter) {
+			w.string(1, "==")
+			w.message(2, func(w *messageWriter) {
+				w.varint(1, uint64(exprIdent))
+				w.message(2, func(w *messageWriter) {
+					w.message(1, func(w *messageWriter) {
+						w.varint(1, uint64(docPathMember))
+						w.string(2, "name")
+					})
+				})
+			})
+			w.message(2, func(w *messageWriter) {
+				w.varint(1, uint64(exprLiteral))
+				w.message(4, func(w *messageWriter) { writeScalar(w, stringScalar("alice")) })
+			})
+		})
+	})
+	client.write(clientCrudFind, w.b)
+	types, _ = client.readUntil(serverSQLStmtExecuteOk)
+	assert.Equal(t, []byte{serverResultsetColumnMeta, serverResultsetRow, serverResultsetFetchDone}, types)
+	assert.Equal(t, "SELECT doc FROM `people` WHERE (JSON_EXTRACT(doc, '$.name') = 'alice')", th.lastQuery())
+}
+
+func TestResultsetEncoding(t *testing.T) {
+	fields := []*querypb.Field{
+		{Name: "i", Type: querypb.Type_INT64},
+		{Name: "u", Type: querypb.Type_UINT32},
+		{Name: "s", Type: querypb.Type_VARCHAR},
+		{Name: "d", Type: querypb.Type_DECIMAL},
+		{Name: "t", Type: querypb.Type_DATETIME},
+		{Name: "n", Type: querypb.Type_VARCHAR},
+	}
+	payload, err := row(fields, []sqltypes.Value{
+		sqltypes.NewInt64(-2),
+		sqltypes.NewUint32(300),
+		sqltypes.NewVarChar("ab"),
+		sqltypes.NewDecimal("-12.3"),
+		sqltypes.NewDatetime("2024-01-02 03:04:05"),
+		sqltypes.NULL,
+	})
+	require.NoError(t, err)
+
+	var values [][]byte
+	require.NoError(t, parseMessage(payload, func(f field) error {
+		values = append(values, f.bytes)
+		return nil
+	}))
+	assert.Equal(t, [][]byte{
+		protowire.AppendVarint(nil, protowire.EncodeZigZag(-2)),
+		protowire.AppendVarint(nil, 300),
+		[]byte("ab\x00"),
+		{0x01, 0x12, 0x3d},
+		{0xe8, 0x0f, 0x01, 0x02, 0x03, 0x04, 0x05},
+		// The NULLs are empty, unlike the empty strings.
+		{},
+	}, values)
+}
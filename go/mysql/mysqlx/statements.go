@@ -0,0 +1,383 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqlescape"
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// This file contains the execution of the SQL statements and CRUD messages,
+// and the admin commands of the mysqlx namespace.
+
+// Parameters of the SessionStateChanged notices.
+const (
+	stateGeneratedInsertID    = 3
+	stateRowsAffected         = 4
+	stateProducedMessage      = 10
+	stateClientIDAssigned     = 11
+	stateGeneratedDocumentIDs = 12
+)
+
+const (
+	noticeWarning             = 1
+	noticeSessionStateChanged = 3
+	noticeScopeLocal          = 2
+	warningLevelWarning       = 2
+)
+
+func (c *Conn) writeNotice(typ uint64, payload []byte) error {
+	var w messageWriter
+	w.varint(1, typ)
+	w.varint(2, noticeScopeLocal)
+	w.bytes(3, payload)
+	return c.writeMessage(serverNotice, w.b)
+}
+
+// writeStateChanged writes a SessionStateChanged notice.
+func (c *Conn) writeStateChanged(param uint64, values ...*scalar) error {
+	var w messageWriter
+	w.varint(1, param)
+	for _, value := range values {
+		w.message(2, func(w *messageWriter) { writeScalar(w, value) })
+	}
+	return c.writeNotice(noticeSessionStateChanged, w.b)
+}
+
+func (c *Conn) handleStmtExecute(payload []byte) error {
+	var (
+		namespace = "sql"
+		stmt      string
+		args      []*anyValue
+	)
+	err := parseMessage(payload, func(f field) error {
+		switch f.num {
+		case 1:
+			stmt = string(f.bytes)
+		case 2:
+			arg, err := parseAny(f.bytes)
+			if err != nil {
+				return err
+			}
+			args = append(args, arg)
+		case 3:
+			namespace = string(f.bytes)
+		}
+		return nil
+	})
+	if err != nil {
+		return c.writeError(newError(ERXBadMessage, "Invalid message: %v", err), false)
+	}
+
+	switch namespace {
+	case "sql":
+		query, err := bindPlaceholders(stmt, args)
+		if err != nil {
+			return c.writeError(err, false)
+		}
+		return c.execute(query, nil)
+	case "mysqlx", "xplugin":
+		return c.executeAdminCommand(stmt, args)
+	default:
+		return c.writeError(newError(ERXInvalidNamespace, "Unknown namespace %s", namespace), false)
+	}
+}
+
+func (c *Conn) handleCrud(msgType byte, payload []byte) error {
+	msg, err := parseCrud(msgType, payload)
+	if err != nil {
+		if _, ok := err.(*sqlerror.SQLError); !ok {
+			err = newError(ERXBadMessage, "Invalid message: %v", err)
+		}
+		return c.writeError(err, false)
+	}
+
+	var (
+		query string
+		ids   []string
+	)
+	switch msgType {
+	case clientCrudFind:
+		query, err = msg.findSQL()
+	case clientCrudInsert:
+		query, ids, err = msg.insertSQL(c.listener.documentIDs.next)
+	case clientCrudUpdate:
+		query, err = msg.updateSQL()
+	case clientCrudDelete:
+		query, err = msg.deleteSQL()
+	}
+	if err != nil {
+		return c.writeError(err, false)
+	}
+	return c.execute(query, ids)
+}
+
+// execute executes a query, and writes its result to the client: its result
+// set if any, then its notices and StmtExecuteOk. The errors of the query
+// are written to the client; the returned errors are the ones of the
+// connection.
+func (c *Conn) execute(query string, documentIDs []string) error {
+	var (
+		fields       []*querypb.Field
+		rowsAffected uint64
+		insertID     uint64
+		info         string
+		warnings     []*querypb.QueryWarning
+		writeErr     error
+	)
+	err := c.listener.handler.ComQuery(c, query, func(qr *sqltypes.Result) error {
+		if fields == nil && len(qr.Fields) > 0 {
+			fields = qr.Fields
+			for _, f := range fields {
+				if writeErr = c.writeMessage(serverResultsetColumnMeta, columnMetaData(f)); writeErr != nil {
+					return writeErr
+				}
+			}
+		}
+		for _, r := range qr.Rows {
+			payload, err := row(fields, r)
+			if err != nil {
+				return err
+			}
+			if writeErr = c.writeMessage(serverResultsetRow, payload); writeErr != nil {
+				return writeErr
+			}
+		}
+		rowsAffected += qr.RowsAffected
+		if qr.InsertID != 0 {
+			insertID = qr.InsertID
+		}
+		if qr.Info != "" {
+			info = qr.Info
+		}
+		warnings = append(warnings, qr.Warnings...)
+		return nil
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+	if err != nil {
+		return c.writeError(err, false)
+	}
+
+	if fields != nil {
+		if err := c.writeMessage(serverResultsetFetchDone, nil); err != nil {
+			return err
+		}
+	}
+	for _, warning := range warnings {
+		var w messageWriter
+		w.varint(1, warningLevelWarning)
+		w.varint(2, uint64(warning.Code))
+		w.string(3, warning.Message)
+		if err := c.writeNotice(noticeWarning, w.b); err != nil {
+			return err
+		}
+	}
+	if fields == nil {
+		if err := c.writeStateChanged(stateRowsAffected, uintScalar(rowsAffected)); err != nil {
+			return err
+		}
+	}
+	if insertID != 0 {
+		if err := c.writeStateChanged(stateGeneratedInsertID, uintScalar(insertID)); err != nil {
+			return err
+		}
+	}
+	if len(documentIDs) > 0 {
+		values := make([]*scalar, 0, len(documentIDs))
+		for _, id := range documentIDs {
+			values = append(values, &scalar{typ: scalarOctets, octets: []byte(id)})
+		}
+		if err := c.writeStateChanged(stateGeneratedDocumentIDs, values...); err != nil {
+			return err
+		}
+	}
+	if info != "" {
+		if err := c.writeStateChanged(stateProducedMessage, stringScalar(info)); err != nil {
+			return err
+		}
+	}
+	if err := c.writeMessage(serverSQLStmtExecuteOk, nil); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// bindPlaceholders replaces the ? placeholders of a SQL statement with the
+// literals of the arguments. The question marks in strings, quoted
+// identifiers and comments are not placeholders.
+func bindPlaceholders(query string, args []*anyValue) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+	var (
+		b    strings.Builder
+		next int
+	)
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			end := i + 1
+			for end < len(query) && query[end] != ch {
+				if query[end] == '\\' && ch != '`' {
+					end++
+				}
+				end++
+			}
+			b.WriteString(query[i:min(end+1, len(query))])
+			i = end
+		case ch == '#' || (ch == '-' && strings.HasPrefix(query[i:], "-- ")):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i - 1
+			}
+			b.WriteString(query[i : i+end+1])
+			i += end
+		case ch == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i - 4
+			}
+			b.WriteString(query[i : i+end+4])
+			i += end + 3
+		case ch == '?':
+			if next >= len(args) {
+				return "", newError(ERXExprMissingArg, "Too few arguments")
+			}
+			arg := args[next]
+			next++
+			if arg.typ != anyScalar || arg.scalar == nil {
+				return "", newError(ERXCmdArgumentType, "Invalid argument type, only scalars are supported")
+			}
+			literal, err := arg.scalar.sqlLiteral()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(literal)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	if next != len(args) {
+		return "", newError(ERXCmdNumArguments, "Too many arguments")
+	}
+	return b.String(), nil
+}
+
+// adminArg returns the argument of an admin command, from the object of the
+// arguments or, as the older clients send them, from its position.
+func adminArg(args []*anyValue, position int, name string) *anyValue {
+	if len(args) == 1 && args[0].typ == anyObject {
+		return args[0].get(name)
+	}
+	if position < len(args) {
+		return args[position]
+	}
+	return nil
+}
+
+// adminStringArg returns a string argument of an admin command, which must
+// be set if required.
+func adminStringArg(args []*anyValue, position int, name string, required bool) (string, error) {
+	arg := adminArg(args, position, name)
+	if arg == nil {
+		if required {
+			return "", newError(ERXCmdNumArguments, "Invalid number of arguments, expected value for '%s'", name)
+		}
+		return "", nil
+	}
+	value, ok := arg.stringValue()
+	if !ok {
+		return "", newError(ERXCmdArgumentType, "Invalid type for argument '%s', expected string", name)
+	}
+	return value, nil
+}
+
+// executeAdminCommand executes the commands of the mysqlx namespace that the
+// connectors use to manage the collections.
+func (c *Conn) executeAdminCommand(command string, args []*anyValue) error {
+	switch command {
+	case "ping", "enable_notices", "disable_notices":
+		if err := c.writeMessage(serverSQLStmtExecuteOk, nil); err != nil {
+			return err
+		}
+		return c.flush()
+	case "create_collection", "ensure_collection", "drop_collection":
+		schema, err := adminStringArg(args, 0, "schema", false)
+		if err != nil {
+			return c.writeError(err, false)
+		}
+		name, err := adminStringArg(args, 1, "name", true)
+		if err != nil {
+			return c.writeError(err, false)
+		}
+		if name == "" {
+			return c.writeError(newError(ERXInvalidCollection, "Invalid collection name"), false)
+		}
+		if command == "drop_collection" {
+			table := sqlescape.EscapeID(name)
+			if schema != "" {
+				table = sqlescape.EscapeID(schema) + "." + table
+			}
+			return c.execute("DROP TABLE "+table, nil)
+		}
+		return c.execute(collectionSQL(schema, name, command == "ensure_collection"), nil)
+	case "list_objects":
+		schema, err := adminStringArg(args, 0, "schema", false)
+		if err != nil {
+			return c.writeError(err, false)
+		}
+		pattern, err := adminStringArg(args, 1, "pattern", false)
+		if err != nil {
+			return c.writeError(err, false)
+		}
+		return c.execute(listObjectsSQL(schema, pattern), nil)
+	default:
+		return c.writeError(newError(ERXInvalidAdminCommand, "Invalid mysqlx command %s", command), false)
+	}
+}
+
+// listObjectsSQL returns the query of the tables, views and collections of
+// a schema, the current one by default. The collections are the tables with
+// the doc and _id columns.
+func listObjectsSQL(schema, pattern string) string {
+	schemaSQL := "DATABASE()"
+	if schema != "" {
+		schemaSQL = sqltypes.EncodeStringSQL(schema)
+	}
+	var b strings.Builder
+	b.WriteString("SELECT T.table_name AS name, ")
+	b.WriteString("IF(T.table_type = 'VIEW', 'VIEW', IF(COUNT(C.column_name) = 2, 'COLLECTION', 'TABLE')) AS type ")
+	b.WriteString("FROM information_schema.tables AS T LEFT JOIN information_schema.columns AS C ")
+	b.WriteString("ON T.table_schema = C.table_schema AND T.table_name = C.table_name ")
+	b.WriteString("AND ((C.column_name = 'doc' AND C.data_type = 'json') OR (C.column_name = '_id' AND C.generation_expression != '')) ")
+	b.WriteString("WHERE T.table_schema = ")
+	b.WriteString(schemaSQL)
+	if pattern != "" {
+		b.WriteString(" AND T.table_name LIKE ")
+		b.WriteString(sqltypes.EncodeStringSQL(pattern))
+	}
+	b.WriteString(" GROUP BY T.table_name, T.table_type ORDER BY T.table_name")
+	return b.String()
+}
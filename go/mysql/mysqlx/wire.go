@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlx
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// field is a field of a protobuf message, as read from the wire.
+type field struct {
+	num protowire.Number
+	typ protowire.Type
+
+	// varint is the value of the varint and fixed fields.
+	varint uint64
+	// bytes is the value of the length-delimited fields: the strings,
+	// bytes and embedded messages.
+	bytes []byte
+}
+
+// parseFields splits a protobuf message in its fields. Repeated fields
+// appear once per value.
+func parseFields(b []byte) ([]field, error) {
+	var fields []field
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		f := field{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(b)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(b)
+			f.varint = uint64(v)
+		case protowire.Fixed64Type:
+			f.varint, n = protowire.ConsumeFixed64(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// parseMessage calls fn for each field of a protobuf message.
+func parseMessage(b []byte, fn func(f field) error) error {
+	fields, err := parseFields(b)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expectBytes checks that f is length-delimited.
+func (f field) expectBytes() error {
+	if f.typ != protowire.BytesType {
+		return fmt.Errorf("field %d: expected a length-delimited value, got wire type %d", f.num, f.typ)
+	}
+	return nil
+}
+
+// expectVarint checks that f is a varint.
+func (f field) expectVarint() error {
+	if f.typ != protowire.VarintType {
+		return fmt.Errorf("field %d: expected a varint, got wire type %d", f.num, f.typ)
+	}
+	return nil
+}
+
+// messageWriter encodes a protobuf message.
+type messageWriter struct {
+	b []byte
+}
+
+func (w *messageWriter) varint(num protowire.Number, v uint64) {
+	w.b = protowire.AppendTag(w.b, num, protowire.VarintType)
+	w.b = protowire.AppendVarint(w.b, v)
+}
+
+func (w *messageWriter) bool(num protowire.Number, v bool) {
+	w.varint(num, protowire.EncodeBool(v))
+}
+
+func (w *messageWriter) bytes(num protowire.Number, v []byte) {
+	w.b = protowire.AppendTag(w.b, num, protowire.BytesType)
+	w.b = protowire.AppendBytes(w.b, v)
+}
+
+func (w *messageWriter) string(num protowire.Number, v string) {
+	w.b = protowire.AppendTag(w.b, num, protowire.BytesType)
+	w.b = protowire.AppendString(w.b, v)
+}
+
+func (w *messageWriter) fixed64(num protowire.Number, v uint64) {
+	w.b = protowire.AppendTag(w.b, num, protowire.Fixed64Type)
+	w.b = protowire.AppendFixed64(w.b, v)
+}
+
+func (w *messageWriter) fixed32(num protowire.Number, v uint32) {
+	w.b = protowire.AppendTag(w.b, num, protowire.Fixed32Type)
+	w.b = protowire.AppendFixed32(w.b, v)
+}
+
+// message encodes an embedded message with fn.
+func (w *messageWriter) message(num protowire.Number, fn func(w *messageWriter)) {
+	var inner messageWriter
+	fn(&inner)
+	w.bytes(num, inner.b)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package oidcauthserver implements a mysql.AuthServer that validates OAuth2
+// access tokens, sent by clients as their password, against the token
+// introspection endpoint (RFC 7662) of an OIDC provider.
+package oidcauthserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/vt/log"
+)
+
+const (
+	defaultUsernameClaim  = "username"
+	defaultGroupsClaim    = "groups"
+	defaultTimeoutSeconds = 10
+)
+
+// AuthServerOidc implements AuthServer with an OIDC token introspection
+// backend. Clients authenticate with their user name and an access token as
+// the password, over mysql_clear_password.
+type AuthServerOidc struct {
+	// IntrospectionURL is the token introspection endpoint of the provider.
+	IntrospectionURL string
+	// ClientID and ClientSecret authenticate vtgate to the introspection
+	// endpoint, using HTTP basic authentication.
+	ClientID     string
+	ClientSecret string
+	// UsernameClaim is the claim holding the user the token was issued to.
+	// It must match the user the client connects as. It defaults to
+	// "username", with a fallback to "sub".
+	UsernameClaim string
+	// GroupsClaim is the claim holding the groups of the user, either as a
+	// list or as a space-separated string. It defaults to "groups".
+	GroupsClaim string
+	// GroupUserMapping maps groups to the Vitess user that members of the
+	// group are identified as. See mysql.MapGroupsToUser.
+	GroupUserMapping map[string]string
+	// CacheTTLSeconds is how long validated tokens are cached for. Entries
+	// never outlive the expiry of the token. Caching is disabled if it is
+	// zero.
+	CacheTTLSeconds int64
+	// TimeoutSeconds bounds each call to the introspection endpoint.
+	TimeoutSeconds int64
+
+	methods []mysql.AuthMethod
+	client  *http.Client
+	cache   *mysql.AuthCache
+}
+
+// Init is public so it can be called from plugin_auth_oidc.go (go/cmd/vtgate)
+func Init(oidcAuthConfigFile, oidcAuthConfigString string) {
+	if oidcAuthConfigFile == "" && oidcAuthConfigString == "" {
+		log.Infof("Not configuring AuthServerOidc because mysql-oidc-auth-config-file and mysql-oidc-auth-config-string are empty")
+		return
+	}
+	if oidcAuthConfigFile != "" && oidcAuthConfigString != "" {
+		log.Infof("Both mysql-oidc-auth-config-file and mysql-oidc-auth-config-string are non-empty, can only use one.")
+		return
+	}
+
+	data := []byte(oidcAuthConfigString)
+	if oidcAuthConfigFile != "" {
+		var err error
+		data, err = os.ReadFile(oidcAuthConfigFile)
+		if err != nil {
+			log.Exitf("Failed to read mysql-oidc-auth-config-file: %v", err)
+		}
+	}
+	oidcAuthServer, err := newAuthServerOidc(data)
+	if err != nil {
+		log.Exitf("%v", err)
+	}
+	mysql.RegisterAuthServer("oidc", oidcAuthServer)
+}
+
+func newAuthServerOidc(config []byte) (*AuthServerOidc, error) {
+	aso := &AuthServerOidc{}
+	if err := json.Unmarshal(config, aso); err != nil {
+		return nil, fmt.Errorf("error parsing AuthServerOidc config: %v", err)
+	}
+	if aso.IntrospectionURL == "" {
+		return nil, fmt.Errorf("AuthServerOidc config requires an IntrospectionURL")
+	}
+	if aso.UsernameClaim == "" {
+		aso.UsernameClaim = defaultUsernameClaim
+	}
+	if aso.GroupsClaim == "" {
+		aso.GroupsClaim = defaultGroupsClaim
+	}
+	if aso.TimeoutSeconds <= 0 {
+		aso.TimeoutSeconds = defaultTimeoutSeconds
+	}
+
+	aso.client = &http.Client{Timeout: time.Duration(aso.TimeoutSeconds) * time.Second}
+	aso.cache = mysql.NewAuthCache(time.Duration(aso.CacheTTLSeconds) * time.Second)
+	aso.methods = []mysql.AuthMethod{mysql.NewMysqlClearAuthMethod(aso, aso)}
+	return aso, nil
+}
+
+// AuthMethods returns the list of registered auth methods
+// implemented by this auth server.
+func (aso *AuthServerOidc) AuthMethods() []mysql.AuthMethod {
+	return aso.methods
+}
+
+// DefaultAuthMethodDescription returns MysqlNativePassword as the default
+// authentication method for the auth server implementation.
+func (aso *AuthServerOidc) DefaultAuthMethodDescription() mysql.AuthMethodDescription {
+	return mysql.MysqlNativePassword
+}
+
+// HandleUser is part of the Validator interface. We
+// handle any user here since we don't check up front.
+func (aso *AuthServerOidc) HandleUser(user string) bool {
+	return true
+}
+
+// UserEntryWithPassword is part of the PlaintextStorage interface
+// and called after the access token is sent by the client.
+func (aso *AuthServerOidc) UserEntryWithPassword(conn *mysql.Conn, user string, password string, remoteAddr net.Addr) (mysql.Getter, error) {
+	if userData, ok := aso.cache.Get(user, password); ok {
+		return userData, nil
+	}
+
+	token, err := aso.introspect(password)
+	if err != nil {
+		log.Errorf("Error introspecting the access token of user %v: %v", user, err)
+		return nil, sqlerror.NewSQLError(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
+	}
+	if !token.active || token.username != user {
+		return nil, sqlerror.NewSQLError(sqlerror.ERAccessDeniedError, sqlerror.SSAccessDeniedError, "Access denied for user '%v'", user)
+	}
+
+	userData := &mysql.StaticUserData{
+		Username: mysql.MapGroupsToUser(user, token.groups, aso.GroupUserMapping),
+		Groups:   token.groups,
+	}
+	aso.cache.Add(user, password, userData, token.expires)
+	return userData, nil
+}
+
+// introspectedToken holds the fields we use from a token introspection
+// response.
+type introspectedToken struct {
+	active   bool
+	username string
+	groups   []string
+	expires  time.Time
+}
+
+// introspect calls the introspection endpoint for the given token.
+func (aso *AuthServerOidc) introspect(token string) (*introspectedToken, error) {
+	form := url.Values{
+		"token":           {token},
+		"token_type_hint": {"access_token"},
+	}
+	req, err := http.NewRequest(http.MethodPost, aso.IntrospectionURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if aso.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(aso.ClientID), url.QueryEscape(aso.ClientSecret))
+	}
+
+	resp, err := aso.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection endpoint returned %v: %s", resp.Status, body)
+	}
+
+	var claims map[string]any
+	if err := json.Unmarshal(body, &claims); err != nil {
+		return nil, fmt.Errorf("cannot parse introspection response: %v", err)
+	}
+	return aso.parseClaims(claims), nil
+}
+
+// parseClaims extracts the token details from the claims of an
+// introspection response.
+func (aso *AuthServerOidc) parseClaims(claims map[string]any) *introspectedToken {
+	token := &introspectedToken{}
+	token.active, _ = claims["active"].(bool)
+
+	token.username, _ = claims[aso.UsernameClaim].(string)
+	if token.username == "" {
+		token.username, _ = claims["sub"].(string)
+	}
+
+	switch groups := claims[aso.GroupsClaim].(type) {
+	case []any:
+		for _, group := range groups {
+			if group, ok := group.(string); ok {
+				token.groups = append(token.groups, group)
+			}
+		}
+	case string:
+		token.groups = strings.Fields(groups)
+	}
+
+	if exp, ok := claims["exp"].(float64); ok {
+		token.expires = time.Unix(int64(exp), 0)
+	}
+	return token
+}
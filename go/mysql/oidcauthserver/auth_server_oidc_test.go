@@ -0,0 +1,162 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcauthserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+)
+
+func newTestIntrospectionServer(t *testing.T, calls *int) *httptest.Server {
+	tokens := map[string]map[string]any{
+		"alice-token": {
+			"active":   true,
+			"username": "alice",
+			"groups":   []string{"eng", "dba"},
+			"exp":      time.Now().Add(time.Hour).Unix(),
+		},
+		"bob-token": {
+			"active": true,
+			"sub":    "bob",
+			"groups": "eng readers",
+		},
+		"expired-token": {
+			"active": false,
+		},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "vtgate" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		claims, ok := tokens[r.PostForm.Get("token")]
+		if !ok {
+			claims = map[string]any{"active": false}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(claims))
+	}))
+}
+
+func TestUserEntryWithPassword(t *testing.T) {
+	var calls int
+	server := newTestIntrospectionServer(t, &calls)
+	defer server.Close()
+
+	aso, err := newAuthServerOidc([]byte(fmt.Sprintf(`{
+		"IntrospectionURL": %q,
+		"ClientID": "vtgate",
+		"ClientSecret": "secret",
+		"GroupUserMapping": {"dba": "vt_dba", "readers": "vt_readonly"},
+		"CacheTTLSeconds": 60
+	}`, server.URL)))
+	require.NoError(t, err)
+
+	tcases := []struct {
+		name   string
+		user   string
+		token  string
+		vtUser string
+		groups []string
+		denied bool
+	}{
+		{
+			name:   "groups as a list",
+			user:   "alice",
+			token:  "alice-token",
+			vtUser: "vt_dba",
+			groups: []string{"eng", "dba"},
+		},
+		{
+			name:   "username from sub and groups as a string",
+			user:   "bob",
+			token:  "bob-token",
+			vtUser: "vt_readonly",
+			groups: []string{"eng", "readers"},
+		},
+		{
+			name:   "token of another user",
+			user:   "bob",
+			token:  "alice-token",
+			denied: true,
+		},
+		{
+			name:   "inactive token",
+			user:   "alice",
+			token:  "expired-token",
+			denied: true,
+		},
+		{
+			name:   "unknown token",
+			user:   "alice",
+			token:  "bogus",
+			denied: true,
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			userData, err := aso.UserEntryWithPassword(nil, tcase.user, tcase.token, nil)
+			if tcase.denied {
+				var sqlErr *sqlerror.SQLError
+				require.ErrorAs(t, err, &sqlErr)
+				assert.Equal(t, sqlerror.ERAccessDeniedError, sqlErr.Number())
+				return
+			}
+			require.NoError(t, err)
+			callerID := userData.Get()
+			assert.Equal(t, tcase.vtUser, callerID.Username)
+			assert.Equal(t, tcase.groups, callerID.Groups)
+		})
+	}
+
+	// Valid tokens are served from the cache.
+	calls = 0
+	_, err = aso.UserEntryWithPassword(nil, "alice", "alice-token", nil)
+	require.NoError(t, err)
+	assert.Zero(t, calls)
+
+	// Failures to call the endpoint deny access.
+	aso.ClientSecret = "wrong"
+	_, err = aso.UserEntryWithPassword(nil, "carol", "carol-token", nil)
+	assert.ErrorContains(t, err, "Access denied for user 'carol'")
+}
+
+func TestNewAuthServerOidc(t *testing.T) {
+	_, err := newAuthServerOidc([]byte(`{}`))
+	assert.ErrorContains(t, err, "requires an IntrospectionURL")
+
+	_, err = newAuthServerOidc([]byte(`not json`))
+	assert.ErrorContains(t, err, "error parsing AuthServerOidc config")
+
+	aso, err := newAuthServerOidc([]byte(`{"IntrospectionURL": "https://idp.example.com/introspect"}`))
+	require.NoError(t, err)
+	assert.Equal(t, defaultUsernameClaim, aso.UsernameClaim)
+	assert.Equal(t, defaultGroupsClaim, aso.GroupsClaim)
+	assert.EqualValues(t, defaultTimeoutSeconds, aso.TimeoutSeconds)
+	assert.Len(t, aso.AuthMethods(), 1)
+}
@@ -20,12 +20,15 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 
+	"vitess.io/vitess/go/mysql/binlog"
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -54,12 +57,65 @@ const (
 // Client -> Server.
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) WriteComQuery(query string) error {
+	return c.WriteComQueryWithAttributes(query, nil)
+}
+
+// WriteComQueryWithAttributes writes a query for the server to execute,
+// along with the given query attributes. The attributes are sent as
+// strings, and require CapabilityClientQueryAttributes to have been
+// negotiated.
+// Client -> Server.
+// Returns SQLError(CRServerGone) if it can't.
+func (c *Conn) WriteComQueryWithAttributes(query string, attributes map[string]string) error {
+	queryAttributes := c.Capabilities&CapabilityClientQueryAttributes != 0
+	if len(attributes) > 0 && !queryAttributes {
+		return sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "query attributes were not negotiated with the server")
+	}
+
 	// This is a new command, need to reset the sequence.
-	c.sequence = 0
+	c.resetSequence()
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	length := 1 + len(query)
+	if queryAttributes {
+		length += lenEncIntSize(uint64(len(names))) + 1
+		if len(names) > 0 {
+			// NULL-bitmap and new-params-bind-flag.
+			length += (len(names)+7)/8 + 1
+			for _, name := range names {
+				// Type, flags, name and value.
+				length += 2 + lenEncIntSize(uint64(len(name))) + len(name) +
+					lenEncIntSize(uint64(len(attributes[name]))) + len(attributes[name])
+			}
+		}
+	}
 
-	data, pos := c.startEphemeralPacketWithHeader(len(query) + 1)
+	data, pos := c.startEphemeralPacketWithHeader(length)
 	data[pos] = ComQuery
 	pos++
+	if queryAttributes {
+		// Parameter count, and parameter set count which is always 1.
+		pos = writeLenEncInt(data, pos, uint64(len(names)))
+		pos = writeLenEncInt(data, pos, 1)
+		if len(names) > 0 {
+			// No attribute is NULL.
+			pos = writeZeroes(data, pos, (len(names)+7)/8)
+			pos = writeByte(data, pos, 0x01)
+			for _, name := range names {
+				pos = writeByte(data, pos, binlog.TypeVarString)
+				pos = writeByte(data, pos, 0)
+				pos = writeLenEncString(data, pos, name)
+			}
+			for _, name := range names {
+				pos = writeLenEncString(data, pos, attributes[name])
+			}
+		}
+	}
 	copy(data[pos:], query)
 	if err := c.writeEphemeralPacket(); err != nil {
 		return sqlerror.NewSQLError(sqlerror.CRServerGone, sqlerror.SSUnknownSQLState, err.Error())
@@ -71,6 +127,7 @@ func (c *Conn) WriteComQuery(query string) error {
 // Client -> Server.
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComInitDB(db string) error {
+	c.resetSequence()
 	data, pos := c.startEphemeralPacketWithHeader(len(db) + 1)
 	data[pos] = ComInitDB
 	pos++
@@ -84,6 +141,7 @@ func (c *Conn) writeComInitDB(db string) error {
 // writeComSetOption changes the connection's capability of executing multi statements.
 // Returns SQLError(CRServerGone) if it can't.
 func (c *Conn) writeComSetOption(operation uint16) error {
+	c.resetSequence()
 	data, pos := c.startEphemeralPacketWithHeader(16 + 1)
 	data[pos] = ComSetOption
 	pos++
@@ -389,6 +447,61 @@ func (c *Conn) ExecuteFetchWithWarningCount(query string, maxrows int, wantfield
 	return res, warnings, err
 }
 
+// ExecuteFetchWithWarnings is like ExecuteFetch, but it also returns the
+// warnings the statement raised. They are read with SHOW WARNINGS, which is
+// only sent when the statement reported a warning count. The result of the
+// statement is returned even if the warnings could not be read, since the
+// statement ran.
+func (c *Conn) ExecuteFetchWithWarnings(query string, maxrows int, wantfields bool) (result *sqltypes.Result, warnings []*querypb.QueryWarning, err error) {
+	defer func() {
+		if err != nil {
+			if sqlerr, ok := err.(*sqlerror.SQLError); ok {
+				sqlerr.Query = sqlparser.TruncateQuery(query, c.truncateErrLen)
+			}
+		}
+	}()
+
+	// Send the query as a COM_QUERY packet.
+	if err = c.WriteComQuery(query); err != nil {
+		return nil, nil, err
+	}
+
+	result, more, count, err := c.ReadQueryResult(maxrows, wantfields)
+	if more {
+		// Multiple results are unexpected. Prioritize this "unexpected" error over whatever error we got from the first result.
+		err = errors.Join(ErrExecuteFetchMultipleResults, err)
+	}
+	// draining to make the connection clean.
+	if err = c.drainMoreResults(more, err); err != nil || count == 0 {
+		return result, nil, err
+	}
+	return result, c.readWarnings(count), nil
+}
+
+// readWarnings returns the count warnings of the last statement, or nil if
+// they could not be read. SHOW WARNINGS returns at most max_error_count of
+// them.
+func (c *Conn) readWarnings(count uint16) []*querypb.QueryWarning {
+	qr, err := c.ExecuteFetch("show warnings", int(count), false)
+	if err != nil {
+		log.Warningf("could not read the warnings of the last statement: %v", err)
+		return nil
+	}
+	warnings := make([]*querypb.QueryWarning, 0, len(qr.Rows))
+	for _, row := range qr.Rows {
+		// The columns are Level, Code and Message.
+		if len(row) < 3 {
+			continue
+		}
+		code, _ := row[1].ToUint64()
+		warnings = append(warnings, &querypb.QueryWarning{
+			Code:    uint32(code),
+			Message: row[2].ToString(),
+		})
+	}
+	return warnings
+}
+
 // ReadQueryResult gets the result from the last written query.
 func (c *Conn) ReadQueryResult(maxrows int, wantfields bool) (*sqltypes.Result, bool, uint16, error) {
 	var packetOk PacketOK
@@ -574,8 +687,82 @@ func (c *Conn) readComQueryResponse(packetOk *PacketOK) (int, error) {
 // Server side methods.
 //
 
-func (c *Conn) parseComQuery(data []byte) string {
-	return string(data[1:])
+// parseComQuery parses a COM_QUERY packet. If the client negotiated
+// CapabilityClientQueryAttributes, the query is preceded by the query
+// attributes, which are returned as strings.
+func (c *Conn) parseComQuery(data []byte) (string, map[string]string, error) {
+	payload := data[1:]
+	if c.Capabilities&CapabilityClientQueryAttributes == 0 {
+		return string(payload), nil, nil
+	}
+
+	paramsCount, pos, ok := readLenEncInt(payload, 0)
+	if !ok {
+		return "", nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes count failed")
+	}
+	// The parameter set count is always 1.
+	_, pos, ok = readLenEncInt(payload, pos)
+	if !ok {
+		return "", nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes set count failed")
+	}
+	if paramsCount == 0 {
+		return string(payload[pos:]), nil, nil
+	}
+
+	bitMap, pos, ok := readBytes(payload, pos, int(paramsCount+7)/8)
+	if !ok {
+		return "", nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes NULL-bitmap failed")
+	}
+	newParamsBoundFlag, pos, ok := readByte(payload, pos)
+	if !ok || newParamsBoundFlag != 0x01 {
+		return "", nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading query attributes types failed")
+	}
+
+	names := make([]string, paramsCount)
+	types := make([]querypb.Type, paramsCount)
+	for i := range names {
+		var err error
+		types[i], names[i], pos, err = readQueryAttributeType(payload, pos)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	attributes := make(map[string]string, paramsCount)
+	for i, name := range names {
+		if (bitMap[i/8] & (1 << uint(i%8))) > 0 {
+			continue
+		}
+		var val sqltypes.Value
+		val, pos, ok = c.parseStmtArgs(payload, types[i], pos)
+		if !ok {
+			return "", nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding query attribute %s failed", name)
+		}
+		attributes[name] = val.ToString()
+	}
+	return string(payload[pos:]), attributes, nil
+}
+
+// readQueryAttributeType reads the type, flags and name of a parameter sent
+// when query attributes are enabled.
+func readQueryAttributeType(payload []byte, pos int) (querypb.Type, string, int, error) {
+	mysqlType, pos, ok := readByte(payload, pos)
+	if !ok {
+		return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
+	}
+	flags, pos, ok := readByte(payload, pos)
+	if !ok {
+		return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
+	}
+	valType, err := sqltypes.MySQLToType(mysqlType, int64(flags))
+	if err != nil {
+		return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
+	}
+	name, pos, ok := readLenEncString(payload, pos)
+	if !ok {
+		return 0, "", 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter name failed")
+	}
+	return valType, name, pos, nil
 }
 
 func (c *Conn) parseComSetOption(data []byte) (uint16, bool) {
@@ -587,7 +774,7 @@ func (c *Conn) parseComPrepare(data []byte) string {
 	return string(data[1:])
 }
 
-func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []byte) (uint32, byte, error) {
+func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []byte) (uint32, byte, map[string]string, error) {
 	pos := 0
 	payload := data[1:]
 	bitMap := make([]byte, 0)
@@ -595,53 +782,84 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 	// statement ID
 	stmtID, pos, ok := readUint32(payload, 0)
 	if !ok {
-		return 0, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading statement ID failed")
+		return 0, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading statement ID failed")
 	}
 	prepare, ok := prepareData[stmtID]
 	if !ok {
-		return 0, 0, sqlerror.NewSQLError(sqlerror.CRCommandsOutOfSync, sqlerror.SSUnknownSQLState, "statement ID is not found from record")
+		return 0, 0, nil, sqlerror.NewSQLError(sqlerror.CRCommandsOutOfSync, sqlerror.SSUnknownSQLState, "statement ID is not found from record")
 	}
 
 	// cursor type flags
 	cursorType, pos, ok := readByte(payload, pos)
 	if !ok {
-		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading cursor type flags failed")
+		return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading cursor type flags failed")
 	}
 
 	// iteration count
 	iterCount, pos, ok := readUint32(payload, pos)
 	if !ok {
-		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading iteration count failed")
+		return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading iteration count failed")
 	}
 	if iterCount != uint32(1) {
-		return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "iteration count is not equal to 1")
+		return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "iteration count is not equal to 1")
+	}
+
+	// With query attributes, the client may send more parameters than the
+	// statement has. The extra ones are the query attributes.
+	queryAttributes := c.Capabilities&CapabilityClientQueryAttributes != 0
+	paramsCount := int(prepare.ParamsCount)
+	if queryAttributes && cursorType&StmtExecuteParameterCountAvailable != 0 {
+		var count uint64
+		count, pos, ok = readLenEncInt(payload, pos)
+		if !ok || count < uint64(prepare.ParamsCount) {
+			return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter count failed")
+		}
+		paramsCount = int(count)
 	}
 
-	if prepare.ParamsCount > 0 {
-		bitMap, pos, ok = readBytes(payload, pos, (int(prepare.ParamsCount)+7)/8)
+	if paramsCount > 0 {
+		bitMap, pos, ok = readBytes(payload, pos, (paramsCount+7)/8)
 		if !ok {
-			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading NULL-bitmap failed")
+			return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading NULL-bitmap failed")
 		}
 	}
 
+	attributeNames := make([]string, paramsCount-int(prepare.ParamsCount))
+	attributeTypes := make([]querypb.Type, len(attributeNames))
 	newParamsBoundFlag, pos, ok := readByte(payload, pos)
 	if ok && newParamsBoundFlag == 0x01 {
-		var mysqlType, flags byte
-		for i := uint16(0); i < prepare.ParamsCount; i++ {
-			mysqlType, pos, ok = readByte(payload, pos)
-			if !ok {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
-			}
+		for i := 0; i < paramsCount; i++ {
+			var valType querypb.Type
+			if queryAttributes {
+				var name string
+				var err error
+				valType, name, pos, err = readQueryAttributeType(payload, pos)
+				if err != nil {
+					return stmtID, 0, nil, err
+				}
+				if i >= int(prepare.ParamsCount) {
+					attributeNames[i-int(prepare.ParamsCount)] = name
+					attributeTypes[i-int(prepare.ParamsCount)] = valType
+					continue
+				}
+			} else {
+				var mysqlType, flags byte
+				mysqlType, pos, ok = readByte(payload, pos)
+				if !ok {
+					return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
+				}
 
-			flags, pos, ok = readByte(payload, pos)
-			if !ok {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
-			}
+				flags, pos, ok = readByte(payload, pos)
+				if !ok {
+					return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
+				}
 
-			// convert MySQL type to internal type.
-			valType, err := sqltypes.MySQLToType(mysqlType, int64(flags))
-			if err != nil {
-				return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
+				// convert MySQL type to internal type.
+				var err error
+				valType, err = sqltypes.MySQLToType(mysqlType, int64(flags))
+				if err != nil {
+					return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, flags, err)
+				}
 			}
 
 			prepare.ParamsType[i] = int32(valType)
@@ -663,13 +881,107 @@ func (c *Conn) parseComStmtExecute(prepareData map[uint32]*PrepareData, data []b
 			val, pos, ok = c.parseStmtArgs(payload, querypb.Type(prepare.ParamsType[i]), pos)
 		}
 		if !ok {
-			return stmtID, 0, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding parameter value failed: %v", prepare.ParamsType[i])
+			return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding parameter value failed: %v", prepare.ParamsType[i])
 		}
 
 		prepare.BindVars[parameterID] = sqltypes.ValueBindVariable(val)
 	}
 
-	return stmtID, cursorType, nil
+	// The types and names of the query attributes are only known if they
+	// were bound with this packet.
+	if len(attributeNames) == 0 || newParamsBoundFlag != 0x01 {
+		return stmtID, cursorType, nil, nil
+	}
+	attributes := make(map[string]string, len(attributeNames))
+	for i, name := range attributeNames {
+		param := int(prepare.ParamsCount) + i
+		if (bitMap[param/8] & (1 << uint(param%8))) > 0 {
+			continue
+		}
+		var val sqltypes.Value
+		val, pos, ok = c.parseStmtArgs(payload, attributeTypes[i], pos)
+		if !ok {
+			return stmtID, 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding query attribute %s failed", name)
+		}
+		attributes[name] = val.ToString()
+	}
+
+	return stmtID, cursorType, attributes, nil
+}
+
+// parseComStmtBulkExecute parses a COM_STMT_BULK_EXECUTE packet. It returns
+// the statement ID and the bind variables of every row in the batch.
+func (c *Conn) parseComStmtBulkExecute(prepareData map[uint32]*PrepareData, data []byte) (uint32, []map[string]*querypb.BindVariable, error) {
+	payload := data[1:]
+
+	// statement ID
+	stmtID, pos, ok := readUint32(payload, 0)
+	if !ok {
+		return 0, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading statement ID failed")
+	}
+	prepare, ok := prepareData[stmtID]
+	if !ok {
+		return 0, nil, sqlerror.NewSQLError(sqlerror.CRCommandsOutOfSync, sqlerror.SSUnknownSQLState, "statement ID is not found from record")
+	}
+
+	flags, pos, ok := readUint16(payload, pos)
+	if !ok {
+		return stmtID, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading bulk flags failed")
+	}
+	if flags&StmtBulkFlagSendUnitResults != 0 {
+		return stmtID, nil, sqlerror.NewSQLError(sqlerror.ERNotSupportedYet, sqlerror.SSUnknownSQLState, "bulk execute with unit results is not supported")
+	}
+
+	if flags&StmtBulkFlagSendTypesToServer != 0 {
+		var mysqlType, typeFlags byte
+		for i := uint16(0); i < prepare.ParamsCount; i++ {
+			mysqlType, pos, ok = readByte(payload, pos)
+			if !ok {
+				return stmtID, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter type failed")
+			}
+			typeFlags, pos, ok = readByte(payload, pos)
+			if !ok {
+				return stmtID, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter flags failed")
+			}
+			valType, err := sqltypes.MySQLToType(mysqlType, int64(typeFlags))
+			if err != nil {
+				return stmtID, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "MySQLToType(%v,%v) failed: %v", mysqlType, typeFlags, err)
+			}
+			prepare.ParamsType[i] = int32(valType)
+		}
+	}
+
+	var rows []map[string]*querypb.BindVariable
+	for pos < len(payload) {
+		row := make(map[string]*querypb.BindVariable, prepare.ParamsCount)
+		for i := 0; i < int(prepare.ParamsCount); i++ {
+			var indicator byte
+			indicator, pos, ok = readByte(payload, pos)
+			if !ok {
+				return stmtID, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "reading parameter indicator failed")
+			}
+
+			var val sqltypes.Value
+			switch indicator {
+			case StmtBulkIndicatorNone:
+				val, pos, ok = c.parseStmtArgs(payload, querypb.Type(prepare.ParamsType[i]), pos)
+				if !ok {
+					return stmtID, nil, sqlerror.NewSQLError(sqlerror.CRMalformedPacket, sqlerror.SSUnknownSQLState, "decoding parameter value failed: %v", prepare.ParamsType[i])
+				}
+			case StmtBulkIndicatorNull:
+				val = sqltypes.NULL
+			default:
+				return stmtID, nil, sqlerror.NewSQLError(sqlerror.ERNotSupportedYet, sqlerror.SSUnknownSQLState, "bulk execute parameter indicator %d is not supported", indicator)
+			}
+			row[fmt.Sprintf("v%d", i+1)] = sqltypes.ValueBindVariable(val)
+		}
+		rows = append(rows, row)
+		if prepare.ParamsCount == 0 {
+			break
+		}
+	}
+
+	return stmtID, rows, nil
 }
 
 func (c *Conn) parseStmtArgs(data []byte, typ querypb.Type, pos int) (sqltypes.Value, int, bool) {
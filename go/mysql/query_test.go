@@ -123,6 +123,79 @@ func TestComSetOption(t *testing.T) {
 
 }
 
+func TestComQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	// Without the capability, attributes cannot be sent.
+	err := cConn.WriteComQueryWithAttributes("select 1", map[string]string{"request_id": "abc"})
+	require.ErrorContains(t, err, "query attributes were not negotiated")
+
+	cConn.Capabilities |= CapabilityClientQueryAttributes
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+
+	tcases := []struct {
+		query      string
+		attributes map[string]string
+	}{
+		{query: "select 1"},
+		{query: "select 2", attributes: map[string]string{"request_id": "abc", "workload": "batch"}},
+	}
+	for _, tcase := range tcases {
+		// Every command starts a new sequence.
+		sConn.sequence = 0
+		require.NoError(t, cConn.WriteComQueryWithAttributes(tcase.query, tcase.attributes))
+		data, err := sConn.ReadPacket()
+		require.NoError(t, err)
+		require.EqualValues(t, ComQuery, data[0])
+
+		query, attributes, err := sConn.parseComQuery(data)
+		require.NoError(t, err)
+		assert.Equal(t, tcase.query, query)
+		assert.Equal(t, tcase.attributes, attributes)
+	}
+}
+
+func TestComStmtExecuteQueryAttributes(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+	sConn.Capabilities |= CapabilityClientQueryAttributes
+
+	prepareDataMap := map[uint32]*PrepareData{
+		1: {
+			StatementID: 1,
+			PrepareStmt: "select * from t where id = ?",
+			ParamsCount: 1,
+			ParamsType:  make([]int32, 1),
+			BindVars:    map[string]*querypb.BindVariable{},
+		}}
+
+	// This is a simulated COM_STMT_EXECUTE packet with one parameter (5)
+	// and one query attribute (request_id = 'abc').
+	data := []byte{
+		ComStmtExecute, 0x01, 0x00, 0x00, 0x00, StmtExecuteParameterCountAvailable, 0x01, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x01,
+		0x08, 0x00, 0x00,
+		0xfd, 0x00, 0x0a, 'r', 'e', 'q', 'u', 'e', 's', 't', '_', 'i', 'd',
+		0x05, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x03, 'a', 'b', 'c',
+	}
+
+	stmtID, _, attributes, err := sConn.parseComStmtExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stmtID)
+	assert.Equal(t, map[string]*querypb.BindVariable{"v1": sqltypes.Int64BindVariable(5)}, prepareDataMap[1].BindVars)
+	assert.Equal(t, map[string]string{"request_id": "abc"}, attributes)
+}
+
 func TestComStmtPrepare(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	defer func() {
@@ -248,12 +321,85 @@ func TestComStmtExecute(t *testing.T) {
 	// This is simulated packets for `select * from test_table where id = ?`
 	data := []byte{23, 18, 0, 0, 0, 128, 1, 0, 0, 0, 0, 1, 1, 128, 1}
 
-	stmtID, _, err := sConn.parseComStmtExecute(cConn.PrepareData, data)
+	stmtID, _, _, err := sConn.parseComStmtExecute(cConn.PrepareData, data)
 	require.NoError(t, err, "parseComStmtExeute failed: %v", err)
 	require.Equal(t, uint32(18), stmtID, "Parsed incorrect values")
 
 }
 
+func TestComStmtBulkExecute(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	prepareDataMap := map[uint32]*PrepareData{
+		1: {
+			StatementID: 1,
+			PrepareStmt: "insert into t(id, name) values (?, ?)",
+			ParamsCount: 2,
+			ParamsType:  make([]int32, 2),
+			BindVars:    map[string]*querypb.BindVariable{},
+		}}
+
+	// This is a simulated COM_STMT_BULK_EXECUTE packet that sends the
+	// parameter types (LONGLONG, VAR_STRING) and two rows: (1, 'a') and (2, NULL).
+	data := []byte{
+		ComStmtBulkExecute, 0x01, 0x00, 0x00, 0x00, StmtBulkFlagSendTypesToServer, 0x00,
+		0x08, 0x00, 0xfd, 0x00,
+		StmtBulkIndicatorNone, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, StmtBulkIndicatorNone, 0x01, 'a',
+		StmtBulkIndicatorNone, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, StmtBulkIndicatorNull,
+	}
+
+	stmtID, rows, err := sConn.parseComStmtBulkExecute(prepareDataMap, data)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stmtID)
+	assert.Equal(t, []map[string]*querypb.BindVariable{
+		{"v1": sqltypes.Int64BindVariable(1), "v2": sqltypes.BytesBindVariable([]byte("a"))},
+		{"v1": sqltypes.Int64BindVariable(2), "v2": sqltypes.NullBindVariable},
+	}, rows)
+
+	// Unit results are not supported.
+	data[5] = StmtBulkFlagSendUnitResults
+	_, _, err = sConn.parseComStmtBulkExecute(prepareDataMap, data)
+	assert.ErrorContains(t, err, "unit results is not supported")
+
+	// Neither are DEFAULT values.
+	data[5] = StmtBulkFlagSendTypesToServer
+	data[len(data)-1] = StmtBulkIndicatorDefault
+	_, _, err = sConn.parseComStmtBulkExecute(prepareDataMap, data)
+	assert.ErrorContains(t, err, "parameter indicator 2 is not supported")
+}
+
+func TestExecuteBulkRows(t *testing.T) {
+	prepare := &PrepareData{
+		StatementID: 1,
+		PrepareStmt: "insert into t(id) values (?)",
+		ParamsCount: 1,
+	}
+	rows := []map[string]*querypb.BindVariable{
+		{"v1": sqltypes.Int64BindVariable(1)},
+		{"v1": sqltypes.Int64BindVariable(2)},
+	}
+
+	var executed []*querypb.BindVariable
+	qr, err := ExecuteBulkRows(prepare, rows, func(prepare *PrepareData, callback func(*sqltypes.Result) error) error {
+		executed = append(executed, prepare.BindVars["v1"])
+		return callback(&sqltypes.Result{RowsAffected: 1, InsertID: uint64(10 + len(executed))})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*querypb.BindVariable{rows[0]["v1"], rows[1]["v1"]}, executed)
+	assert.EqualValues(t, 2, qr.RowsAffected)
+	assert.EqualValues(t, 11, qr.InsertID)
+
+	_, err = ExecuteBulkRows(prepare, rows, func(prepare *PrepareData, callback func(*sqltypes.Result) error) error {
+		return callback(&sqltypes.Result{Fields: []*querypb.Field{{Name: "id"}}})
+	})
+	assert.ErrorContains(t, err, "statements that return rows is not supported")
+}
+
 func TestComStmtExecuteUpdStmt(t *testing.T) {
 	listener, sConn, cConn := createSocketPair(t)
 	defer func() {
@@ -292,7 +438,7 @@ func TestComStmtExecuteUpdStmt(t *testing.T) {
 		0x35, 0x36, 0x37, 0x38, 0x0c, 0xe9, 0x9f, 0xa9, 0xe5, 0x86, 0xac, 0xe7, 0x9c, 0x9f, 0xe8, 0xb5,
 		0x9e, 0x03, 0x66, 0x6f, 0x6f, 0x07, 0x66, 0x6f, 0x6f, 0x2c, 0x62, 0x61, 0x72}
 
-	stmtID, _, err := sConn.parseComStmtExecute(prepareDataMap, data[4:]) // first 4 are header
+	stmtID, _, _, err := sConn.parseComStmtExecute(prepareDataMap, data[4:]) // first 4 are header
 	require.NoError(t, err)
 	require.EqualValues(t, 1, stmtID)
 
@@ -34,6 +34,16 @@ const (
 		ORDER BY table_name, SEQ_IN_INDEX`
 	// ShowRowsRead is the query used to find the number of rows read.
 	ShowRowsRead = "show status like 'Innodb_rows_read'"
+	// TableBufferPoolSizes is the query used to find the size of the pages of
+	// each table of the database that are in the InnoDB buffer pool. The pages
+	// of all the indexes and partitions of a table are added up.
+	TableBufferPoolSizes = `
+		SELECT SUBSTRING_INDEX(SUBSTRING_INDEX(t.NAME, '/', -1), '#', 1) AS table_name, SUM(c.N_CACHED_PAGES) * @@innodb_page_size AS size
+		FROM information_schema.INNODB_CACHED_INDEXES c
+		JOIN information_schema.INNODB_INDEXES i ON i.INDEX_ID = c.INDEX_ID
+		JOIN information_schema.INNODB_TABLES t ON t.TABLE_ID = i.TABLE_ID
+		WHERE t.NAME LIKE CONCAT(DATABASE(), '/%')
+		GROUP BY table_name`
 
 	// GetColumnNamesQueryPatternForTable is used for mocking queries in unit tests
 	GetColumnNamesQueryPatternForTable = `SELECT COLUMN_NAME.*TABLE_NAME.*%s.*`
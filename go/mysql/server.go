@@ -74,6 +74,8 @@ var (
 		}
 		return connCount.Get() - totalUsers
 	})
+
+	connCountByCompression = stats.NewGaugesWithSingleLabel("MysqlServerConnCountByCompression", "Active MySQL server connections by compression algorithm", "algorithm")
 )
 
 // A Handler is an interface used by Listener to send queries.
@@ -136,6 +138,17 @@ type Handler interface {
 	Env() *vtenv.Environment
 }
 
+// BulkHandler can be implemented by a Handler to execute all the rows of a
+// COM_STMT_BULK_EXECUTE at once. For handlers that don't implement it, every
+// row is executed through its own ComStmtExecute call.
+type BulkHandler interface {
+	// ComStmtBulkExecute is called when a connection receives a bulk
+	// execute for a prepared statement. bindVars holds the bind variables
+	// of every row. The returned result holds the total number of affected
+	// rows, and the insert ID of the first row that generated one.
+	ComStmtBulkExecute(c *Conn, prepare *PrepareData, bindVars []map[string]*querypb.BindVariable) (*sqltypes.Result, error)
+}
+
 // UnimplementedHandler implemnts all of the optional callbacks so as to satisy
 // the Handler interface. Intended to be embedded into your custom Handler
 // implementation without needing to define every callback and to help be forwards
@@ -178,6 +191,21 @@ type Listener struct {
 	// by the server when TLS is not in use.
 	AllowClearTextWithoutTLS atomic.Bool
 
+	// AllowCompression makes the server advertise the compressed
+	// protocol, so clients can negotiate it with zlib or zstd.
+	AllowCompression atomic.Bool
+
+	// AllowLocalInfile makes the server advertise that it can request
+	// the files of LOAD DATA LOCAL INFILE statements from the clients.
+	AllowLocalInfile atomic.Bool
+
+	// AllowBulkOperations makes the server advertise MariaDB's
+	// MARIADB_CLIENT_STMT_BULK_OPERATIONS, so MariaDB connectors send
+	// batches of prepared statement executions as COM_STMT_BULK_EXECUTE.
+	// To do so, the server stops advertising CLIENT_MYSQL, which MariaDB
+	// connectors take as a MySQL server without extended capabilities.
+	AllowBulkOperations atomic.Bool
+
 	// SlowConnectWarnThreshold if non-zero specifies an amount of time
 	// beyond which a warning is logged to identify the slow connection
 	SlowConnectWarnThreshold atomic.Int64
@@ -391,7 +419,7 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 	defer connCount.Add(-1)
 
 	// First build and send the server handshake packet.
-	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil)
+	serverAuthPluginData, err := c.writeHandshakeV10(l.ServerVersion, l.authServer, uint8(l.charset), l.TLSConfig.Load() != nil, l.AllowCompression.Load(), l.AllowLocalInfile.Load(), l.AllowBulkOperations.Load())
 	if err != nil {
 		if err != io.EOF {
 			log.Errorf("Cannot send HandshakeV10 packet to %s: %v", c, err)
@@ -534,6 +562,13 @@ func (l *Listener) handle(conn net.Conn, connectionID uint32, acceptTime time.Ti
 		return
 	}
 
+	// The compressed protocol, if negotiated, starts after the OK packet.
+	if algorithm := c.negotiatedCompression(); algorithm != CompressionNone {
+		c.enableCompression(algorithm, c.zstdCompressionLevel)
+	}
+	connCountByCompression.Add(c.CompressionAlgorithm(), 1)
+	defer connCountByCompression.Add(c.CompressionAlgorithm(), -1)
+
 	// Record how long we took to establish the connection
 	timings.Record(connectTimingKey, acceptTime)
 
@@ -572,7 +607,7 @@ func (l *Listener) Shutdown() {
 
 // writeHandshakeV10 writes the Initial Handshake Packet, server side.
 // It returns the salt data.
-func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool) ([]byte, error) {
+func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, charset uint8, enableTLS bool, enableCompression bool, enableLocalInfile bool, enableBulkOperations bool) ([]byte, error) {
 	capabilities := CapabilityClientLongPassword |
 		CapabilityClientFoundRows |
 		CapabilityClientLongFlag |
@@ -585,10 +620,24 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, ch
 		CapabilityClientPluginAuth |
 		CapabilityClientPluginAuthLenencClientData |
 		CapabilityClientDeprecateEOF |
-		CapabilityClientConnAttr
+		CapabilityClientConnAttr |
+		CapabilityClientQueryAttributes
 	if enableTLS {
 		capabilities |= CapabilityClientSSL
 	}
+	if enableCompression {
+		capabilities |= CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm
+	}
+	if enableLocalInfile {
+		capabilities |= CapabilityClientLocalFiles
+	}
+	var mariaDBCapabilities uint32
+	if enableBulkOperations {
+		// MariaDB connectors only read the extended capabilities of
+		// servers that don't set CLIENT_MYSQL.
+		capabilities &^= CapabilityClientLongPassword
+		mariaDBCapabilities |= MariaDBCapabilityStmtBulkOperations
+	}
 
 	// Grab the default auth method. This can only be either
 	// mysql_native_password or caching_sha2_password. Both
@@ -613,7 +662,8 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, ch
 			2 + // status flag
 			2 + // capability flags (upper 2 bytes)
 			1 + // length of auth plugin data
-			10 + // reserved (0)
+			6 + // reserved (0)
+			4 + // MariaDB extended capabilities
 			13 + // auth-plugin-data
 			lenNullString(string(authMethod)) // auth-plugin-name
 
@@ -659,8 +709,11 @@ func (c *Conn) writeHandshakeV10(serverVersion string, authServer AuthServer, ch
 	// Always 21 (8 + 13).
 	pos = writeByte(data, pos, 21)
 
-	// Reserved 10 bytes: all 0
-	pos = writeZeroes(data, pos, 10)
+	// Reserved 6 bytes: all 0
+	pos = writeZeroes(data, pos, 6)
+
+	// MariaDB extended capabilities, 0 unless advertised.
+	pos = writeUint32(data, pos, mariaDBCapabilities)
 
 	// Second part of auth plugin data.
 	pos += copy(data[pos:], pluginData[8:])
@@ -706,7 +759,7 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 	// later in the protocol. If we re-received the handshake packet
 	// after SSL negotiation, do not overwrite capabilities.
 	if firstTime {
-		c.Capabilities = clientFlags & (CapabilityClientDeprecateEOF | CapabilityClientFoundRows)
+		c.Capabilities = clientFlags & (CapabilityClientDeprecateEOF | CapabilityClientFoundRows | CapabilityClientQueryAttributes)
 	}
 
 	// set connection capability for executing multi statements
@@ -714,6 +767,18 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 		c.Capabilities |= CapabilityClientMultiStatements
 	}
 
+	// Remember if the client asked for compression, which we only
+	// accept if we advertised it.
+	if l.AllowCompression.Load() {
+		c.Capabilities |= clientFlags & (CapabilityClientCompress | CapabilityClientZstdCompressionAlgorithm)
+	}
+
+	// Remember if the client can send the files of LOAD DATA LOCAL
+	// INFILE statements, which we only accept if we advertised it.
+	if l.AllowLocalInfile.Load() {
+		c.Capabilities |= clientFlags & CapabilityClientLocalFiles
+	}
+
 	// Max packet size. Don't do anything with this now.
 	// See doc.go for more information.
 	_, pos, ok = readUint32(data, pos)
@@ -806,8 +871,19 @@ func (l *Listener) parseClientHandshakePacket(c *Conn, firstTime bool, data []by
 
 	// Decode connection attributes send by the client
 	if clientFlags&CapabilityClientConnAttr != 0 {
-		if _, _, err := parseConnAttrs(data, pos); err != nil {
+		if attrs, attrsEnd, err := parseConnAttrs(data, pos); err != nil {
 			log.Warningf("Decode connection attributes send by the client: %v", err)
+		} else {
+			c.connectionAttributes = attrs
+			pos = attrsEnd
+		}
+	}
+
+	// The zstd compression level comes last.
+	if c.Capabilities&CapabilityClientZstdCompressionAlgorithm != 0 {
+		level, _, ok := readByte(data, pos)
+		if ok {
+			c.zstdCompressionLevel = int(level)
 		}
 	}
 
@@ -19,7 +19,9 @@ package mysql
 import (
 	"context"
 	"crypto/tls"
+	"encoding/binary"
 	"fmt"
+	"maps"
 	"net"
 	"os"
 	"os/exec"
@@ -194,6 +196,21 @@ func (th *testHandler) ComQuery(c *Conn, query string, callback func(*sqltypes.R
 				},
 			},
 		})
+	case "show warnings":
+		callback(&sqltypes.Result{
+			Fields: []*querypb.Field{
+				{Name: "Level", Type: querypb.Type_VARCHAR},
+				{Name: "Code", Type: querypb.Type_UINT32},
+				{Name: "Message", Type: querypb.Type_VARCHAR},
+			},
+			Rows: [][]sqltypes.Value{
+				{
+					sqltypes.NewVarChar("Warning"),
+					sqltypes.NewUint32(1265),
+					sqltypes.NewVarChar("Data truncated for column 'msg' at row 1"),
+				},
+			},
+		})
 	case "50ms delay":
 		callback(&sqltypes.Result{
 			Fields: []*querypb.Field{{
@@ -628,6 +645,38 @@ func TestServer(t *testing.T) {
 	//	time.Sleep(60 * time.Minute)
 }
 
+func TestExecuteFetchWithWarnings(t *testing.T) {
+	th := &testHandler{}
+
+	authServer := NewAuthServerNone()
+	l, err := NewListener("tcp", "127.0.0.1:", authServer, th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer l.Close()
+	go l.Accept()
+
+	host, port := getHostPort(t, l.Addr())
+	params := &ConnParams{
+		Host: host,
+		Port: port,
+	}
+	conn, err := Connect(context.Background(), params)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// SHOW WARNINGS isn't sent when there are no warnings.
+	result, warnings, err := conn.ExecuteFetchWithWarnings("insert", 1000, true)
+	require.NoError(t, err)
+	assert.EqualValues(t, 123, result.RowsAffected)
+	assert.Nil(t, warnings)
+
+	th.SetWarnings(1)
+	defer th.SetWarnings(0)
+	result, warnings, err = conn.ExecuteFetchWithWarnings("insert", 1000, true)
+	require.NoError(t, err)
+	assert.EqualValues(t, 123, result.RowsAffected)
+	assert.Equal(t, []*querypb.QueryWarning{{Code: 1265, Message: "Data truncated for column 'msg' at row 1"}}, warnings)
+}
+
 func TestServerStats(t *testing.T) {
 	th := &testHandler{}
 
@@ -1464,6 +1513,33 @@ func TestParseConnAttrs(t *testing.T) {
 	}
 }
 
+func TestParseClientHandshakePacketConnAttrs(t *testing.T) {
+	flags := uint32(CapabilityClientProtocol41 | CapabilityClientSecureConnection | CapabilityClientPluginAuth | CapabilityClientConnAttr)
+	data := binary.LittleEndian.AppendUint32(nil, flags)
+	data = binary.LittleEndian.AppendUint32(data, 1<<24) // max packet size
+	data = append(data, 33)                              // character set
+	data = append(data, make([]byte, 23)...)
+	data = append(data, "user\x00"...)
+	data = append(data, 0) // empty auth response
+	data = append(data, string(MysqlNativePassword)+"\x00"...)
+	attrs := []byte{}
+	for _, kv := range [][2]string{{"program_name", "app"}, {"_client_name", "libmysql"}} {
+		attrs = append(attrs, byte(len(kv[0])))
+		attrs = append(attrs, kv[0]...)
+		attrs = append(attrs, byte(len(kv[1])))
+		attrs = append(attrs, kv[1]...)
+	}
+	data = append(data, byte(len(attrs)))
+	data = append(data, attrs...)
+
+	l := &Listener{}
+	c := &Conn{}
+	username, _, _, err := l.parseClientHandshakePacket(c, true, data)
+	require.NoError(t, err)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, map[string]string{"program_name": "app", "_client_name": "libmysql"}, c.ConnectionAttributes())
+}
+
 func TestServerFlush(t *testing.T) {
 	mysqlServerFlushDelay := 10 * time.Millisecond
 	th := &testHandler{}
@@ -1538,3 +1614,132 @@ func TestTcpKeepAlive(t *testing.T) {
 	err = setTcpConnProperties(th.lastConn.conn.(*net.TCPConn), 0)
 	require.ErrorContains(t, err, "unable to enable keepalive on tcp connection")
 }
+
+// stmtTestHandler records the bind variables of the executed prepared
+// statements.
+type stmtTestHandler struct {
+	testHandler
+	executed []map[string]*querypb.BindVariable
+}
+
+func (th *stmtTestHandler) ComStmtExecute(c *Conn, prepare *PrepareData, callback func(*sqltypes.Result) error) error {
+	th.mu.Lock()
+	th.executed = append(th.executed, maps.Clone(prepare.BindVars))
+	th.mu.Unlock()
+	return callback(&sqltypes.Result{RowsAffected: 1})
+}
+
+func TestServerBulkOperations(t *testing.T) {
+	th := &stmtTestHandler{}
+	l, err := NewListener("tcp", "127.0.0.1:", NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer l.Close()
+	go l.Accept()
+
+	host, port := getHostPort(t, l.Addr())
+
+	// mariaDBCapabilities reads the capabilities of a new server handshake.
+	mariaDBCapabilities := func() (capabilities uint32, mariaDBCapabilities uint32) {
+		conn, err := net.Dial("tcp", net.JoinHostPort(host, fmt.Sprint(port)))
+		require.NoError(t, err)
+		c := newConn(conn, 0, 0)
+		defer c.Close()
+
+		data, err := c.readPacket()
+		require.NoError(t, err)
+		capabilities, _, err = c.parseInitialHandshakePacket(data)
+		require.NoError(t, err)
+
+		// The MariaDB capabilities are the last 4 bytes of the reserved
+		// field, which follows the protocol version, the server version,
+		// the connection id, the first part of the auth plugin data, the
+		// filler, the capabilities, the charset, the status flags and the
+		// auth plugin data length.
+		pos := 1 + len(c.ServerVersion) + 1 + 4 + 8 + 1 + 2 + 1 + 2 + 2 + 1 + 6
+		mariaDBCapabilities, _, ok := readUint32(data, pos)
+		require.True(t, ok)
+		return capabilities, mariaDBCapabilities
+	}
+
+	// By default, the server is a MySQL server without extended capabilities.
+	capabilities, extended := mariaDBCapabilities()
+	assert.NotZero(t, capabilities&CapabilityClientLongPassword)
+	assert.Zero(t, extended)
+
+	l.AllowBulkOperations.Store(true)
+	capabilities, extended = mariaDBCapabilities()
+	assert.Zero(t, capabilities&CapabilityClientLongPassword)
+	assert.EqualValues(t, MariaDBCapabilityStmtBulkOperations, extended)
+
+	c, err := Connect(context.Background(), &ConnParams{Host: host, Port: port})
+	require.NoError(t, err)
+	defer c.Close()
+
+	// writeCommand sends a command packet without waiting for its response.
+	writeCommand := func(data ...byte) {
+		c.sequence = 0
+		useWritePacket(t, c, data)
+	}
+	// readOK reads the OK response of a command.
+	readOK := func() *PacketOK {
+		c.sequence = 1
+		data, err := c.readPacket()
+		require.NoError(t, err)
+		require.EqualValues(t, OKPacket, data[0], "unexpected response: %v", data)
+		packetOK := &PacketOK{}
+		require.NoError(t, c.parseOKPacket(packetOK, data))
+		return packetOK
+	}
+
+	// The statement is prepared with two parameters, whose definitions
+	// follow the response.
+	writeCommand(append([]byte{ComPrepare}, "insert into t(id, name) values (?, ?)"...)...)
+	c.sequence = 1
+	data, err := c.readPacket()
+	require.NoError(t, err)
+	require.EqualValues(t, OKPacket, data[0], "unexpected response: %v", data)
+	stmtID, _, _ := readUint32(data, 1)
+	params, _, _ := readUint16(data, 7)
+	require.EqualValues(t, 2, params)
+	for range params {
+		_, err := c.readPacket()
+		require.NoError(t, err)
+	}
+	if c.Capabilities&CapabilityClientDeprecateEOF == 0 {
+		data, err := c.readPacket()
+		require.NoError(t, err)
+		require.True(t, c.isEOFPacket(data))
+	}
+
+	// The client pipelines two executions and a bulk execution of the
+	// statement, then reads their responses in order.
+	execute := func(id byte, name string) []byte {
+		return append([]byte{
+			ComStmtExecute, byte(stmtID), 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+			0x00,                   // NULL bitmap
+			0x01,                   // new params bound
+			0x08, 0x00, 0xfd, 0x00, // LONGLONG, VAR_STRING
+			id, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, byte(len(name)),
+		}, name...)
+	}
+	writeCommand(execute(1, "a")...)
+	writeCommand(execute(2, "b")...)
+	writeCommand(
+		ComStmtBulkExecute, byte(stmtID), 0x00, 0x00, 0x00, StmtBulkFlagSendTypesToServer, 0x00,
+		0x08, 0x00, 0xfd, 0x00,
+		StmtBulkIndicatorNone, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, StmtBulkIndicatorNone, 0x01, 'c',
+		StmtBulkIndicatorNone, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, StmtBulkIndicatorNull,
+	)
+	assert.EqualValues(t, 1, readOK().affectedRows)
+	assert.EqualValues(t, 1, readOK().affectedRows)
+	assert.EqualValues(t, 2, readOK().affectedRows)
+
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	assert.Equal(t, []map[string]*querypb.BindVariable{
+		{"v1": sqltypes.Int64BindVariable(1), "v2": sqltypes.BytesBindVariable([]byte("a"))},
+		{"v1": sqltypes.Int64BindVariable(2), "v2": sqltypes.BytesBindVariable([]byte("b"))},
+		{"v1": sqltypes.Int64BindVariable(3), "v2": sqltypes.BytesBindVariable([]byte("c"))},
+		{"v1": sqltypes.Int64BindVariable(4), "v2": sqltypes.NullBindVariable},
+	}, th.executed)
+}
@@ -125,6 +125,9 @@ const (
 	ERDupIndex                      = ErrorCode(1831)
 	ERInnodbReadOnly                = ErrorCode(1874)
 
+	// ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION
+	ERCantExecuteInReadOnlyTransaction = ErrorCode(1792)
+
 	// already exists
 	ERDbCreateExists = ErrorCode(1007)
 	ERTableExists    = ErrorCode(1050)
@@ -220,6 +223,8 @@ const (
 	ERDerivedMustHaveAlias         = ErrorCode(1248)
 	ERTableNameNotAllowedHere      = ErrorCode(1250)
 	ERCollationCharsetMismatch     = ErrorCode(1253)
+	ERWarnTooFewRecords            = ErrorCode(1261)
+	ERWarnTooManyRecords           = ErrorCode(1262)
 	ERWarnDataTruncated            = ErrorCode(1265)
 	ERCantAggregate2Collations     = ErrorCode(1267)
 	ERCantAggregate3Collations     = ErrorCode(1270)
@@ -321,6 +326,10 @@ const (
 	// ER_CANT_DO_THIS_DURING_AN_TRANSACTION
 	SSCantDoThisDuringAnTransaction = "25000"
 
+	// SSCantExecuteInReadOnlyTransaction is
+	// ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION
+	SSCantExecuteInReadOnlyTransaction = "25006"
+
 	// SSAccessDeniedError is ER_ACCESS_DENIED_ERROR
 	SSAccessDeniedError = "28000"
 
@@ -192,6 +192,7 @@ var stateToMysqlCode = map[vterrors.State]mysqlCode{
 	vterrors.ForbidSchemaChange:           {num: ERForbidSchemaChange, state: SSUnknownSQLState},
 	vterrors.MixOfGroupFuncAndFields:      {num: ERMixOfGroupFuncAndFields, state: SSClientError},
 	vterrors.NetPacketTooLarge:            {num: ERNetPacketTooLarge, state: SSNetError},
+	vterrors.UserLimitReached:             {num: ERUserLimitReached, state: SSClientError},
 	vterrors.NonUniqError:                 {num: ERNonUniq, state: SSConstraintViolation},
 	vterrors.NonUniqTable:                 {num: ERNonUniqTable, state: SSClientError},
 	vterrors.NonUpdateableTable:           {num: ERNonUpdateableTable, state: SSUnknownSQLState},
@@ -201,6 +202,7 @@ var stateToMysqlCode = map[vterrors.State]mysqlCode{
 	vterrors.UnsupportedPS:                {num: ERUnsupportedPS, state: SSUnknownSQLState},
 	vterrors.UnknownSystemVariable:        {num: ERUnknownSystemVariable, state: SSUnknownSQLState},
 	vterrors.UnknownTable:                 {num: ERUnknownTable, state: SSUnknownTable},
+	vterrors.WriteInReadOnlyTransaction:   {num: ERCantExecuteInReadOnlyTransaction, state: SSCantExecuteInReadOnlyTransaction},
 	vterrors.WrongGroupField:              {num: ERWrongGroupField, state: SSClientError},
 	vterrors.WrongNumberOfColumnsInSelect: {num: ERWrongNumberOfColumnsInSelect, state: SSWrongNumberOfColumns},
 	vterrors.WrongTypeForVar:              {num: ERWrongTypeForVar, state: SSClientError},
@@ -109,6 +109,16 @@ func TestNewSQLErrorFromError(t *testing.T) {
 			num: ERTooManyUserConnections,
 			ss:  SSClientError,
 		},
+		{
+			err: vterrors.VT08001("ingest", "rate limit of 10 queries per second"),
+			num: ERUserLimitReached,
+			ss:  SSClientError,
+		},
+		{
+			err: vterrors.VT09025(),
+			num: ERCantExecuteInReadOnlyTransaction,
+			ss:  SSCantExecuteInReadOnlyTransaction,
+		},
 		{
 			err: vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "failed precondition"),
 			num: ERUnknownError,
@@ -35,6 +35,8 @@ type Result struct {
 	SessionStateChanges string           `json:"session_state_changes"`
 	StatusFlags         uint16           `json:"status_flags"`
 	Info                string           `json:"info"`
+	// Warnings are the warnings MySQL raised while executing the query.
+	Warnings []*querypb.QueryWarning `json:"warnings,omitempty"`
 }
 
 //goland:noinspection GoUnusedConst
@@ -96,6 +98,12 @@ func (result *Result) Copy() *Result {
 		StatusFlags:         result.StatusFlags,
 		Info:                result.Info,
 	}
+	if result.Warnings != nil {
+		out.Warnings = make([]*querypb.QueryWarning, len(result.Warnings))
+		for i, w := range result.Warnings {
+			out.Warnings[i] = w.CloneVT()
+		}
+	}
 	if result.Fields != nil {
 		out.Fields = make([]*querypb.Field, len(result.Fields))
 		for i, f := range result.Fields {
@@ -120,6 +128,7 @@ func (result *Result) ShallowCopy() *Result {
 		Info:                result.Info,
 		SessionStateChanges: result.SessionStateChanges,
 		Rows:                result.Rows,
+		Warnings:            result.Warnings,
 	}
 }
 
@@ -331,12 +340,23 @@ func (result *Result) AppendResult(src *Result) {
 		result.Fields = src.Fields
 	}
 	result.RowsAffected += src.RowsAffected
-	if src.InsertID != 0 {
-		result.InsertID = src.InsertID
-	}
+	result.InsertID = MergeInsertID(result.InsertID, src.InsertID)
 	result.Rows = append(result.Rows, src.Rows...)
 }
 
+// MergeInsertID returns the insert id of a statement whose queries ran
+// concurrently, like on several shards, and returned the insert ids a and b.
+// It is the smallest of them that is not 0, which doesn't depend on the order
+// in which the queries finished. It is the id of the first row of the
+// statement when the generated ids grow with the rows, like MySQL reports for
+// a multi-row insert.
+func MergeInsertID(a, b uint64) uint64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
 // Named returns a NamedResult based on this struct
 func (result *Result) Named() *NamedResult {
 	return ToNamedResult(result)
@@ -345,3 +345,20 @@ func TestAppendResult(t *testing.T) {
 		t.Errorf("Got:\n%#v, want:\n%#v", result, want)
 	}
 }
+
+func TestMergeInsertID(t *testing.T) {
+	testcases := []struct {
+		a, b, want uint64
+	}{
+		{a: 0, b: 0, want: 0},
+		{a: 0, b: 5, want: 5},
+		{a: 5, b: 0, want: 5},
+		{a: 3, b: 5, want: 3},
+		{a: 5, b: 3, want: 3},
+	}
+	for _, tc := range testcases {
+		if got := MergeInsertID(tc.a, tc.b); got != tc.want {
+			t.Errorf("MergeInsertID(%d, %d): %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The names of the labels of the dimensions. The metrics created with
+// dimensions always use these names, which the Prometheus backend exports as
+// keyspace, shard and cell.
+const (
+	KeyspaceLabel = "Keyspace"
+	ShardLabel    = "Shard"
+	CellLabel     = "Cell"
+)
+
+var dimensionLabels = []string{KeyspaceLabel, ShardLabel, CellLabel}
+
+// dimensionLabelAliases are the lowercased names that other metrics use for
+// the dimension labels. They are rejected as the labels of a metric created
+// with dimensions, which already has the dimension labels.
+var dimensionLabelAliases = map[string]string{
+	"keyspace":      KeyspaceLabel,
+	"keyspacename":  KeyspaceLabel,
+	"keyspace_name": KeyspaceLabel,
+	"shard":         ShardLabel,
+	"shardname":     ShardLabel,
+	"shard_name":    ShardLabel,
+	"cell":          CellLabel,
+	"cellname":      CellLabel,
+	"cell_name":     CellLabel,
+}
+
+// Dimensions are the keyspace, shard and cell of a component. The metrics
+// created with them are tagged with their current values, so that the
+// component does not have to pass them on every update. The values can be
+// set later, once the component knows them. Dimensions are safe for
+// concurrent use.
+type Dimensions struct {
+	mu     sync.RWMutex
+	values []string
+}
+
+// NewDimensions returns Dimensions with the given values.
+func NewDimensions(keyspace, shard, cell string) *Dimensions {
+	return &Dimensions{values: []string{keyspace, shard, cell}}
+}
+
+// Set sets the values of the dimensions.
+func (d *Dimensions) Set(keyspace, shard, cell string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.values = []string{keyspace, shard, cell}
+}
+
+// Get returns the values of the dimensions.
+func (d *Dimensions) Get() (keyspace, shard, cell string) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.values[0], d.values[1], d.values[2]
+}
+
+// tag returns the values of the dimensions followed by names.
+func (d *Dimensions) tag(names []string) []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append(slices.Clip(d.values), names...)
+}
+
+var (
+	// dimensionedMu protects dimensionedVars.
+	dimensionedMu sync.Mutex
+	// dimensionedVars are the published variables of the metrics created with
+	// dimensions, by name. Components that create the same metric share the
+	// variable, their series being told apart by the dimensions.
+	dimensionedVars = make(map[string]dimensionedVar)
+)
+
+type dimensionedVar struct {
+	labels []string
+	v      Variable
+}
+
+// publishWithDimensions returns the variable of the metric, creating it with
+// the dimension labels followed by labels if needed. It panics if the labels
+// include a dimension label, or if the metric was already created with other
+// labels or of another type.
+func publishWithDimensions[T Variable](name string, labels []string, create func(labels []string) T) T {
+	for _, label := range labels {
+		if dim, ok := dimensionLabelAliases[strings.ToLower(label)]; ok {
+			panic(fmt.Sprintf("stats: label %s of %s duplicates the %s dimension", label, name, dim))
+		}
+	}
+	labels = append(slices.Clip(dimensionLabels), labels...)
+
+	dimensionedMu.Lock()
+	defer dimensionedMu.Unlock()
+	if dv, ok := dimensionedVars[name]; ok {
+		v, ok := dv.v.(T)
+		if !ok || !slices.Equal(dv.labels, labels) {
+			panic(fmt.Sprintf("stats: %s is already created with labels %v as a %T", name, dv.labels, dv.v))
+		}
+		return v
+	}
+	v := create(labels)
+	dimensionedVars[name] = dimensionedVar{labels: labels, v: v}
+	return v
+}
+
+// CountersWithDimensions is a CountersWithMultiLabels whose first labels are
+// the dimensions of a component.
+type CountersWithDimensions struct {
+	dims     *Dimensions
+	counters *CountersWithMultiLabels
+}
+
+// NewCountersWithDimensions creates a CountersWithMultiLabels tagged with the
+// dimensions. Its labels are the dimension labels followed by labels.
+func NewCountersWithDimensions(dims *Dimensions, name, help string, labels []string) *CountersWithDimensions {
+	return &CountersWithDimensions{
+		dims: dims,
+		counters: publishWithDimensions(name, labels, func(labels []string) *CountersWithMultiLabels {
+			return NewCountersWithMultiLabels(name, help, labels)
+		}),
+	}
+}
+
+// Add adds a value to the counter of the names, tagged with the dimensions.
+func (c *CountersWithDimensions) Add(names []string, value int64) {
+	c.counters.Add(c.dims.tag(names), value)
+}
+
+// Counts returns the counts of all the components that created the counters.
+func (c *CountersWithDimensions) Counts() map[string]int64 {
+	return c.counters.Counts()
+}
+
+// GaugesWithDimensions is a GaugesWithMultiLabels whose first labels are the
+// dimensions of a component.
+type GaugesWithDimensions struct {
+	dims   *Dimensions
+	gauges *GaugesWithMultiLabels
+}
+
+// NewGaugesWithDimensions creates a GaugesWithMultiLabels tagged with the
+// dimensions. Its labels are the dimension labels followed by labels.
+func NewGaugesWithDimensions(dims *Dimensions, name, help string, labels []string) *GaugesWithDimensions {
+	return &GaugesWithDimensions{
+		dims: dims,
+		gauges: publishWithDimensions(name, labels, func(labels []string) *GaugesWithMultiLabels {
+			return NewGaugesWithMultiLabels(name, help, labels)
+		}),
+	}
+}
+
+// Set sets the value of the gauge of the names, tagged with the dimensions.
+func (g *GaugesWithDimensions) Set(names []string, value int64) {
+	g.gauges.Set(g.dims.tag(names), value)
+}
+
+// Add adds a value to the gauge of the names, tagged with the dimensions.
+func (g *GaugesWithDimensions) Add(names []string, value int64) {
+	g.gauges.Add(g.dims.tag(names), value)
+}
+
+// Counts returns the values of all the components that created the gauges.
+func (g *GaugesWithDimensions) Counts() map[string]int64 {
+	return g.gauges.Counts()
+}
+
+// TimingsWithDimensions is a MultiTimings whose first labels are the
+// dimensions of a component.
+type TimingsWithDimensions struct {
+	dims    *Dimensions
+	timings *MultiTimings
+}
+
+// NewTimingsWithDimensions creates a MultiTimings tagged with the dimensions.
+// Its labels are the dimension labels followed by labels.
+func NewTimingsWithDimensions(dims *Dimensions, name, help string, labels []string) *TimingsWithDimensions {
+	return &TimingsWithDimensions{
+		dims: dims,
+		timings: publishWithDimensions(name, labels, func(labels []string) *MultiTimings {
+			return NewMultiTimings(name, help, labels)
+		}),
+	}
+}
+
+// Add adds a timing to the names, tagged with the dimensions.
+func (t *TimingsWithDimensions) Add(names []string, elapsed time.Duration) {
+	t.timings.Add(t.dims.tag(names), elapsed)
+}
+
+// Record records the time elapsed since startTime for the names, tagged with
+// the dimensions.
+func (t *TimingsWithDimensions) Record(names []string, startTime time.Time) {
+	t.timings.Record(t.dims.tag(names), startTime)
+}
+
+// Counts returns the counts of all the components that created the timings.
+func (t *TimingsWithDimensions) Counts() map[string]int64 {
+	return t.timings.Counts()
+}
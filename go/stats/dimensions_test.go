@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountersWithDimensions(t *testing.T) {
+	clearStats()
+	dims1 := NewDimensions("ks", "-80", "zone1")
+	dims2 := NewDimensions("ks", "80-", "zone1")
+	c1 := NewCountersWithDimensions(dims1, "dimensionedCounters", "help", []string{"Table"})
+	c2 := NewCountersWithDimensions(dims2, "dimensionedCounters", "help", []string{"Table"})
+	c1.Add([]string{"t1"}, 1)
+	c1.Add([]string{"t1"}, 2)
+	c2.Add([]string{"t1"}, 4)
+
+	want := map[string]int64{"ks.-80.zone1.t1": 3, "ks.80-.zone1.t1": 4}
+	assert.Equal(t, want, c1.Counts())
+	assert.Equal(t, want, c2.Counts())
+	assert.Equal(t, []string{"Keyspace", "Shard", "Cell", "Table"}, expvar.Get("dimensionedCounters").(*CountersWithMultiLabels).Labels())
+
+	// The dimensions can be set once they are known.
+	dims1.Set("ks", "-40", "zone1")
+	c1.Add([]string{"t2"}, 1)
+	assert.Equal(t, int64(1), c1.Counts()["ks.-40.zone1.t2"])
+}
+
+func TestGaugesWithDimensions(t *testing.T) {
+	clearStats()
+	g := NewGaugesWithDimensions(NewDimensions("ks", "0", "zone1"), "dimensionedGauges", "help", []string{"State"})
+	g.Set([]string{"Serving"}, 5)
+	g.Add([]string{"Serving"}, -2)
+	assert.Equal(t, map[string]int64{"ks.0.zone1.Serving": 3}, g.Counts())
+}
+
+func TestTimingsWithDimensions(t *testing.T) {
+	clearStats()
+	tm := NewTimingsWithDimensions(NewDimensions("ks", "0", "zone1"), "dimensionedTimings", "help", []string{"Operation"})
+	tm.Add([]string{"Read"}, 500*time.Microsecond)
+	assert.Equal(t, map[string]int64{"All": 1, "ks.0.zone1.Read": 1}, tm.Counts())
+}
+
+func TestDimensionLabelsEnforced(t *testing.T) {
+	clearStats()
+	dims := NewDimensions("ks", "0", "zone1")
+	assert.PanicsWithValue(t, "stats: label ShardName of badLabels duplicates the Shard dimension", func() {
+		NewCountersWithDimensions(dims, "badLabels", "help", []string{"ShardName"})
+	})
+
+	NewCountersWithDimensions(dims, "conflicting", "help", []string{"Table"})
+	assert.PanicsWithValue(t, "stats: conflicting is already created with labels [Keyspace Shard Cell Table] as a *stats.CountersWithMultiLabels", func() {
+		NewCountersWithDimensions(dims, "conflicting", "help", []string{"Plan"})
+	})
+	assert.Panics(t, func() {
+		NewGaugesWithDimensions(dims, "conflicting", "help", []string{"Table"})
+	})
+}
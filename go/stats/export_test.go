@@ -31,6 +31,7 @@ func clearStats() {
 	dropVariables = ""
 	combinedDimensions = nil
 	droppedVars = nil
+	dimensionedVars = make(map[string]dimensionedVar)
 }
 
 func TestNoHook(t *testing.T) {
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// VarMetadata describes a published stats variable.
+type VarMetadata struct {
+	Name string `json:"name"`
+	// Type is the name of the type of the variable in this package,
+	// e.g. "CountersWithSingleLabel".
+	Type   string   `json:"type"`
+	Help   string   `json:"help"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+// varFilter selects variables by the "prefix" and "label" query parameters
+// of a request. Each parameter can be repeated or hold a comma-separated list
+// of values, and a variable must match one of the values of each parameter
+// that is set.
+type varFilter struct {
+	prefixes []string
+	labels   []string
+}
+
+func newVarFilter(r *http.Request) varFilter {
+	values := func(key string) []string {
+		var vals []string
+		for _, param := range r.URL.Query()[key] {
+			for _, val := range strings.Split(param, ",") {
+				if val = strings.TrimSpace(val); val != "" {
+					vals = append(vals, val)
+				}
+			}
+		}
+		return vals
+	}
+	return varFilter{
+		prefixes: values("prefix"),
+		labels:   values("label"),
+	}
+}
+
+func (f varFilter) matches(name string, v expvar.Var) bool {
+	if len(f.prefixes) > 0 && !slices.ContainsFunc(f.prefixes, func(prefix string) bool {
+		return strings.HasPrefix(name, prefix)
+	}) {
+		return false
+	}
+	if len(f.labels) > 0 {
+		labels := varLabels(v)
+		if !slices.ContainsFunc(f.labels, func(label string) bool {
+			return slices.Contains(labels, label)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// varLabels returns the names of the dimensions of a variable.
+func varLabels(v expvar.Var) []string {
+	switch v := v.(type) {
+	case *Histogram:
+		// The labels of a histogram name its buckets, not dimensions.
+		return nil
+	case *StringMapFuncWithMultiLabels:
+		return v.KeyLabels()
+	case interface{ Labels() []string }:
+		return v.Labels()
+	case interface{ Label() string }:
+		return []string{v.Label()}
+	}
+	return nil
+}
+
+// VarsHandler returns an http.Handler that serves the published variables in
+// the format of expvar.Handler. The variables can be filtered with the
+// "prefix" query parameter, which matches the start of their names, and the
+// "label" query parameter, which matches the names of their dimensions.
+func VarsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := newVarFilter(r)
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprintf(w, "{\n")
+		first := true
+		expvar.Do(func(kv expvar.KeyValue) {
+			if !filter.matches(kv.Key, kv.Value) {
+				return
+			}
+			if !first {
+				fmt.Fprintf(w, ",\n")
+			}
+			first = false
+			fmt.Fprintf(w, "%q: %s", kv.Key, kv.Value)
+		})
+		fmt.Fprintf(w, "\n}\n")
+	})
+}
+
+// Metadata returns the description of the published stats variables, sorted
+// by name. Variables that were not created by this package, like the
+// "memstats" expvar, are left out.
+func Metadata() []VarMetadata {
+	return metadata(varFilter{})
+}
+
+func metadata(filter varFilter) []VarMetadata {
+	var vars []VarMetadata
+	// expvar.Do iterates in the order of the names.
+	expvar.Do(func(kv expvar.KeyValue) {
+		v, ok := kv.Value.(Variable)
+		if !ok || !filter.matches(kv.Key, kv.Value) {
+			return
+		}
+		vars = append(vars, VarMetadata{
+			Name:   kv.Key,
+			Type:   reflect.Indirect(reflect.ValueOf(v)).Type().Name(),
+			Help:   v.Help(),
+			Labels: varLabels(kv.Value),
+		})
+	})
+	return vars
+}
+
+// MetadataHandler returns an http.Handler that serves the description of the
+// published stats variables as a JSON list. It takes the same query
+// parameters as VarsHandler.
+func MetadataHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := metadata(newVarFilter(r))
+		if vars == nil {
+			vars = []VarMetadata{}
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(vars); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
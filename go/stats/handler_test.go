@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarsHandler(t *testing.T) {
+	clearStats()
+	NewCounter("VarsHandlerQueries", "Queries served").Add(3)
+	NewCountersWithSingleLabel("VarsHandlerQueriesByTable", "Queries served by table", "Table").Add("t1", 2)
+	NewGaugesWithMultiLabels("VarsHandlerConnections", "Open connections", []string{"Keyspace", "Shard"}).Set([]string{"ks", "0"}, 1)
+	NewHistogram("VarsHandlerLatency", "Query latency", []int64{1, 10})
+
+	serve := func(query string) string {
+		w := httptest.NewRecorder()
+		VarsHandler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/vars"+query, nil))
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		return w.Body.String()
+	}
+	get := func(query string) map[string]any {
+		body := serve(query)
+
+		var vars map[string]any
+		require.NoError(t, json.Unmarshal([]byte(body), &vars), body)
+		return vars
+	}
+	keys := func(vars map[string]any) []string {
+		var names []string
+		for name := range vars {
+			names = append(names, name)
+		}
+		return names
+	}
+
+	// Other tests publish variables that are not valid JSON.
+	body := serve("")
+	assert.Contains(t, body, `"memstats": `, "all the expvars must be served without filter")
+	assert.Contains(t, body, `"VarsHandlerQueries": 3`)
+
+	assert.ElementsMatch(t, []string{"VarsHandlerQueries", "VarsHandlerQueriesByTable"}, keys(get("?prefix=VarsHandlerQueries")))
+	assert.ElementsMatch(t, []string{"VarsHandlerQueriesByTable", "VarsHandlerConnections", "VarsHandlerLatency"}, keys(get("?prefix=VarsHandlerQueriesBy,VarsHandlerConnections&prefix=VarsHandlerLatency")))
+	assert.ElementsMatch(t, []string{"VarsHandlerQueriesByTable", "VarsHandlerConnections"}, keys(get("?prefix=VarsHandler&label=Table&label=Shard")))
+	assert.Empty(t, get("?prefix=VarsHandlerQueries&label=Shard"))
+	assert.Empty(t, get("?prefix=VarsHandlerLatency&label=inf"), "histogram buckets are not labels")
+}
+
+func TestMetadataHandler(t *testing.T) {
+	clearStats()
+	NewCounter("MetadataHandlerQueries", "Queries served")
+	NewGaugesWithMultiLabels("MetadataHandlerConnections", "Open connections", []string{"Keyspace", "Shard"})
+	NewStringMapFuncWithMultiLabels("MetadataHandlerVersions", "Versions of the tablets", []string{"Tablet"}, "Version", func() map[string]string { return nil })
+
+	w := httptest.NewRecorder()
+	MetadataHandler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/metrics/metadata?prefix=MetadataHandler", nil))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var vars []VarMetadata
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &vars), w.Body.String())
+	assert.Equal(t, []VarMetadata{
+		{Name: "MetadataHandlerConnections", Type: "GaugesWithMultiLabels", Help: "Open connections", Labels: []string{"Keyspace", "Shard"}},
+		{Name: "MetadataHandlerQueries", Type: "Counter", Help: "Queries served"},
+		{Name: "MetadataHandlerVersions", Type: "StringMapFuncWithMultiLabels", Help: "Versions of the tablets", Labels: []string{"Tablet"}},
+	}, vars)
+
+	w = httptest.NewRecorder()
+	MetadataHandler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/metrics/metadata?prefix=MetadataHandler&label=Table", nil))
+	assert.JSONEq(t, "[]", w.Body.String())
+
+	for _, v := range Metadata() {
+		assert.NotEqual(t, "memstats", v.Name, "only the variables of the stats package have metadata")
+	}
+}
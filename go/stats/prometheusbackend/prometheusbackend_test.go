@@ -213,6 +213,15 @@ func TestPrometheusGaugesWithMultiLabels(t *testing.T) {
 	checkHandlerForMetricWithMultiLabels(t, name, labels, labelValues2, 1)
 }
 
+func TestPrometheusCountersWithDimensions(t *testing.T) {
+	name := "blah_counterswithdimensions"
+	dims := stats.NewDimensions("ks", "-80", "zone1")
+	c := stats.NewCountersWithDimensions(dims, name, "help", []string{"Table"})
+	c.Add([]string{"t1"}, 1)
+	// The dimension labels are named consistently across metrics.
+	checkHandlerForMetricWithMultiLabels(t, name, []string{"cell", "keyspace", "shard", "table"}, []string{"zone1", "ks", "-80", "t1"}, 1)
+}
+
 func TestPrometheusCountersWithMultiLabels_AddPanic(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
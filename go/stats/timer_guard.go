@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TimerGuard times a span of work, and records it into the Timings it was
+// started from when it is stopped. It is meant to be used with defer:
+//
+//	defer timings.Start(ctx, "Execute").Stop()
+//
+// Spans whose context exceeded its deadline by the time they are stopped are
+// also counted separately, so that slow work can be told apart from work
+// that was cut short by its deadline. The counts are published as
+// <name>DeadlineExceeded once the first TimerGuard of a Timings is started.
+type TimerGuard struct {
+	ctx     context.Context
+	timings *Timings
+	name    string
+	start   time.Time
+	stopped bool
+}
+
+// Start starts timing a span of work of the named category.
+func (t *Timings) Start(ctx context.Context, name string) *TimerGuard {
+	return t.start(ctx, name, []string{t.label})
+}
+
+func (t *Timings) start(ctx context.Context, name string, labels []string) *TimerGuard {
+	if t.name != "" {
+		t.publishDeadlineExceeded.Do(func() {
+			NewCountersFuncWithMultiLabels(
+				t.name+"DeadlineExceeded",
+				t.help+": spans that exceeded their deadline",
+				labels,
+				t.DeadlineExceededCounts,
+			)
+		})
+	}
+	return &TimerGuard{
+		ctx:     ctx,
+		timings: t,
+		name:    name,
+		start:   time.Now(),
+	}
+}
+
+// Start starts timing a span of work of the category made of names.
+func (mt *MultiTimings) Start(ctx context.Context, names []string) *TimerGuard {
+	if len(names) != len(mt.labels) {
+		panic("MultiTimings: wrong number of values in Start")
+	}
+	return mt.Timings.start(ctx, safeJoinLabels(names, mt.combinedLabels), mt.labels)
+}
+
+// Elapsed returns the time elapsed since the span was started.
+func (g *TimerGuard) Elapsed() time.Duration {
+	return time.Since(g.start)
+}
+
+// Stop records the span and returns its duration. Only the first call
+// records anything.
+func (g *TimerGuard) Stop() time.Duration {
+	elapsed := g.Elapsed()
+	if g.stopped {
+		return elapsed
+	}
+	g.stopped = true
+
+	name := g.name
+	if g.timings.labelCombined {
+		name = StatsAllStr
+	}
+	g.timings.Add(name, elapsed)
+	if g.ctx != nil && errors.Is(g.ctx.Err(), context.DeadlineExceeded) {
+		g.timings.deadlineExceeded.add(name, 1)
+	}
+	return elapsed
+}
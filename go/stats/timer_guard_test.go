@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"context"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimerGuard(t *testing.T) {
+	clearStats()
+	tm := NewTimings("timerGuardTimings", "help", "category")
+
+	g := tm.Start(context.Background(), "fast")
+	elapsed := g.Stop()
+	// Only the first Stop records the span.
+	g.Stop()
+	assert.Equal(t, map[string]int64{"All": 1, "fast": 1}, tm.Counts())
+	assert.Equal(t, elapsed.Nanoseconds(), tm.Time())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	g = tm.Start(ctx, "slow")
+	<-ctx.Done()
+	g.Stop()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tm.Start(cancelled, "slow").Stop()
+
+	assert.Equal(t, map[string]int64{"All": 3, "fast": 1, "slow": 2}, tm.Counts())
+	assert.Equal(t, map[string]int64{"slow": 1}, tm.DeadlineExceededCounts())
+
+	v := expvar.Get("timerGuardTimingsDeadlineExceeded")
+	require.NotNil(t, v)
+	assert.Equal(t, `{"slow": 1}`, v.String())
+
+	tm.Reset()
+	assert.Empty(t, tm.DeadlineExceededCounts())
+}
+
+func TestMultiTimingsTimerGuard(t *testing.T) {
+	clearStats()
+	mtm := NewMultiTimings("timerGuardMultiTimings", "help", []string{"dim1", "dim2"})
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	mtm.Start(ctx, []string{"a", "b"}).Stop()
+	mtm.Start(context.Background(), []string{"a", "c"}).Stop()
+
+	assert.Equal(t, map[string]int64{"All": 2, "a.b": 1, "a.c": 1}, mtm.Counts())
+	assert.Equal(t, map[string]int64{"a.b": 1}, mtm.DeadlineExceededCounts())
+	assert.Panics(t, func() { mtm.Start(ctx, []string{"a"}) })
+}
@@ -33,6 +33,12 @@ type Timings struct {
 	mu         sync.RWMutex
 	histograms map[string]*Histogram
 
+	// deadlineExceeded counts, per category, the spans timed with a
+	// TimerGuard whose context exceeded its deadline. They are published
+	// when the first TimerGuard is started.
+	deadlineExceeded        counters
+	publishDeadlineExceeded sync.Once
+
 	name          string
 	help          string
 	label         string
@@ -45,11 +51,12 @@ type Timings struct {
 // first time they are updated.
 func NewTimings(name, help, label string, categories ...string) *Timings {
 	t := &Timings{
-		histograms:    make(map[string]*Histogram),
-		name:          name,
-		help:          help,
-		label:         label,
-		labelCombined: IsDimensionCombined(label),
+		histograms:       make(map[string]*Histogram),
+		deadlineExceeded: counters{counts: make(map[string]int64)},
+		name:             name,
+		help:             help,
+		label:            label,
+		labelCombined:    IsDimensionCombined(label),
 	}
 	for _, cat := range categories {
 		t.histograms[cat] = NewGenericHistogram("", "", bucketCutoffs, bucketLabels, "Count", "Time")
@@ -67,6 +74,7 @@ func (t *Timings) Reset() {
 	t.histograms = make(map[string]*Histogram)
 	t.totalCount.Store(0)
 	t.totalTime.Store(0)
+	t.deadlineExceeded.reset()
 	t.mu.RUnlock()
 }
 
@@ -152,6 +160,12 @@ func (t *Timings) Time() int64 {
 	return t.totalTime.Load()
 }
 
+// DeadlineExceededCounts returns, for each category, the number of spans
+// timed with a TimerGuard whose context exceeded its deadline.
+func (t *Timings) DeadlineExceededCounts() map[string]int64 {
+	return t.deadlineExceeded.Counts()
+}
+
 // Counts returns the total count for each value.
 func (t *Timings) Counts() map[string]int64 {
 	t.mu.RLock()
@@ -210,10 +224,11 @@ func NewMultiTimings(name string, help string, labels []string) *MultiTimings {
 	}
 	t := &MultiTimings{
 		Timings: Timings{
-			histograms: make(map[string]*Histogram),
-			name:       name,
-			help:       help,
-			label:      safeJoinLabels(labels, combinedLabels),
+			histograms:       make(map[string]*Histogram),
+			deadlineExceeded: counters{counts: make(map[string]int64)},
+			name:             name,
+			help:             help,
+			label:            safeJoinLabels(labels, combinedLabels),
 		},
 		labels:         labels,
 		combinedLabels: combinedLabels,
@@ -37,7 +37,7 @@ var numSidecarDBTables int
 var ddls1, ddls2 []string
 
 func init() {
-	sidecarDBTables = []string{"copy_state", "dt_participant", "dt_state", "heartbeat", "post_copy_action", "redo_state",
+	sidecarDBTables = []string{"copy_state", "dt_audit", "dt_participant", "dt_state", "heartbeat", "post_copy_action", "query_fingerprint_rules", "redo_state",
 		"redo_statement", "reparent_journal", "resharding_journal", "schema_migrations", "schema_version", "tables",
 		"vdiff", "vdiff_log", "vdiff_table", "views", "vreplication", "vreplication_log"}
 	numSidecarDBTables = len(sidecarDBTables)
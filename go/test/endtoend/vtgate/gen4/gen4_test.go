@@ -187,24 +187,19 @@ func TestSubQueriesOnOuterJoinOnCondition(t *testing.T) {
 	}
 }
 
-func TestPlannerWarning(t *testing.T) {
+func TestStraightJoin(t *testing.T) {
 	mcmp, closer := start(t)
 	defer closer()
 
-	// straight_join query
-	_ = utils.Exec(t, mcmp.VtConn, `select 1 from t1 straight_join t2 on t1.id = t2.id`)
-	utils.AssertMatches(t, mcmp.VtConn, `show warnings`, `[[VARCHAR("Warning") UINT16(1235) VARCHAR("straight join is converted to normal join")]]`)
+	utils.Exec(t, mcmp.VtConn, `insert into t1(id, col) values (1, 1),(2, 3)`)
+	utils.Exec(t, mcmp.VtConn, `insert into t2(id, tcol1, tcol2) values (1, 'A', 'A'),(2, 'B', 'C')`)
 
-	// execute same query again.
-	_ = utils.Exec(t, mcmp.VtConn, `select 1 from t1 straight_join t2 on t1.id = t2.id`)
-	utils.AssertMatches(t, mcmp.VtConn, `show warnings`, `[[VARCHAR("Warning") UINT16(1235) VARCHAR("straight join is converted to normal join")]]`)
+	// the straight join is kept, so no warning is raised.
+	utils.AssertMatches(t, mcmp.VtConn, `select t1.id from t1 straight_join t2 on t1.id = t2.id order by t1.id`, `[[INT64(1)] [INT64(2)]]`)
+	utils.AssertMatches(t, mcmp.VtConn, `show warnings`, `[]`)
 
-	// random query to reset the warning.
-	_ = utils.Exec(t, mcmp.VtConn, `select 1 from t1`)
-
-	// execute same query again.
-	_ = utils.Exec(t, mcmp.VtConn, `select 1 from t1 straight_join t2 on t1.id = t2.id`)
-	utils.AssertMatches(t, mcmp.VtConn, `show warnings`, `[[VARCHAR("Warning") UINT16(1235) VARCHAR("straight join is converted to normal join")]]`)
+	utils.AssertMatches(t, mcmp.VtConn, `select straight_join t1.id from t1, t2 where t1.id = t2.id order by t1.id`, `[[INT64(1)] [INT64(2)]]`)
+	utils.AssertMatches(t, mcmp.VtConn, `show warnings`, `[]`)
 }
 
 func TestHashJoin(t *testing.T) {
@@ -139,6 +139,13 @@ func TestAPIEndpoints(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 400, status, resp)
 		assert.Equal(t, "Filtering by shard without keyspace isn't supported\n", resp)
+
+		// Verify that the replication graph shows the stopped replica
+		status, resp, err = utils.MakeAPICall(t, vtorc, "/api/replication-graph?keyspace=ks&shard=0")
+		require.NoError(t, err)
+		assert.Equal(t, 200, status, resp)
+		assert.Contains(t, resp, fmt.Sprintf(`"ReplicaAlias": "%s"`, replica.Alias))
+		assert.Contains(t, resp, `"ReplicationStopped"`)
 	})
 
 	t.Run("Enable Recoveries API", func(t *testing.T) {
@@ -33,6 +33,7 @@ import (
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
@@ -135,6 +136,13 @@ func (vw *VSchemaWrapper) Environment() *vtenv.Environment {
 func (vw *VSchemaWrapper) PlannerWarning(_ string) {
 }
 
+func (vw *VSchemaWrapper) PlannerBindVarTypes(map[string]evalengine.Type) {
+}
+
+func (vw *VSchemaWrapper) QueryPositions() sqlparser.Positions {
+	return nil
+}
+
 func (vw *VSchemaWrapper) ForeignKeyMode(keyspace string) (vschemapb.Keyspace_ForeignKeyMode, error) {
 	defaultFkMode := vschemapb.Keyspace_unmanaged
 	if vw.V.Keyspaces[keyspace] != nil && vw.V.Keyspaces[keyspace].ForeignKeyMode != vschemapb.Keyspace_unspecified {
@@ -0,0 +1,100 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronNext(t *testing.T) {
+	// a Wednesday
+	from := time.Date(2024, time.January, 10, 10, 30, 15, 0, time.UTC)
+	tcs := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "* * * * *", want: time.Date(2024, time.January, 10, 10, 31, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2024, time.January, 10, 10, 45, 0, 0, time.UTC)},
+		{spec: "30 * * * *", want: time.Date(2024, time.January, 10, 11, 30, 0, 0, time.UTC)},
+		{spec: "0 3 * * *", want: time.Date(2024, time.January, 11, 3, 0, 0, 0, time.UTC)},
+		{spec: "@daily", want: time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", want: time.Date(2024, time.January, 10, 11, 0, 0, 0, time.UTC)},
+		{spec: "@weekly", want: time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC)},
+		{spec: "@monthly", want: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "@yearly", want: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 2 * * sat,sun", want: time.Date(2024, time.January, 13, 2, 0, 0, 0, time.UTC)},
+		{spec: "0 2 * * 7", want: time.Date(2024, time.January, 14, 2, 0, 0, 0, time.UTC)},
+		{spec: "0 2 * * 1-5", want: time.Date(2024, time.January, 11, 2, 0, 0, 0, time.UTC)},
+		{spec: "15 1,13 * * *", want: time.Date(2024, time.January, 10, 13, 15, 0, 0, time.UTC)},
+		{spec: "0 0 29 feb *", want: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 31 * *", want: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week
+		{spec: "0 0 20 * mon", want: time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 30 feb *", want: time.Time{}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.spec, func(t *testing.T) {
+			cs, err := ParseCron(tc.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, cs.Next(from))
+			assert.Equal(t, tc.spec, cs.String())
+		})
+	}
+}
+
+func TestCronNextChained(t *testing.T) {
+	cs, err := ParseCron("0 */6 * * *")
+	require.NoError(t, err)
+	next := time.Date(2024, time.December, 31, 12, 0, 0, 0, time.UTC)
+	var got []time.Time
+	for i := 0; i < 3; i++ {
+		next = cs.Next(next)
+		got = append(got, next)
+	}
+	assert.Equal(t, []time.Time{
+		time.Date(2024, time.December, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 1, 6, 0, 0, 0, time.UTC),
+	}, got)
+}
+
+func TestParseCronErrors(t *testing.T) {
+	tcs := []struct {
+		spec string
+		err  string
+	}{
+		{spec: "", err: "expected 5 fields, got 0"},
+		{spec: "* * * *", err: "expected 5 fields, got 4"},
+		{spec: "60 * * * *", err: `invalid value "60" in minute field, expected 0-59`},
+		{spec: "* 24 * * *", err: `invalid value "24" in hour field, expected 0-23`},
+		{spec: "* * 0 * *", err: `invalid value "0" in day of month field, expected 1-31`},
+		{spec: "* * * foo *", err: `invalid value "foo" in month field, expected 1-12`},
+		{spec: "* * * * 8", err: `invalid value "8" in day of week field, expected 0-7`},
+		{spec: "*/0 * * * *", err: `invalid step "0" in minute field`},
+		{spec: "10-5 * * * *", err: `invalid range "10-5" in minute field`},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.spec, func(t *testing.T) {
+			_, err := ParseCron(tc.spec)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
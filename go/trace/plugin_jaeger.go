@@ -90,6 +90,8 @@ func init() {
 // JAEGER_AGENT_HOST
 // JAEGER_AGENT_PORT
 func newJagerTracerFromEnv(serviceName string) (tracingService, io.Closer, error) {
+	log.Warningf("The opentracing-jaeger tracer is deprecated and will be removed in a future release, use --tracer=opentelemetry instead")
+
 	cfg, err := config.FromEnv()
 	if err != nil {
 		return nil, nil, err
@@ -0,0 +1,414 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"vitess.io/vitess/go/viperutil"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	otelConfigKey = viperutil.KeyPrefixFunc(configKey("otel"))
+	otelEndpoint  = viperutil.Configure(
+		otelConfigKey("endpoint"),
+		viperutil.Options[string]{
+			FlagName: "otel-endpoint",
+		},
+	)
+	otelInsecure = viperutil.Configure(
+		otelConfigKey("insecure"),
+		viperutil.Options[bool]{
+			FlagName: "otel-insecure",
+		},
+	)
+	otelSamplingRate = viperutil.Configure(
+		otelConfigKey("sampling-rate"),
+		viperutil.Options[float64]{
+			Default:  0.1,
+			FlagName: "otel-sampling-rate",
+		},
+	)
+	otelKeyspaceSamplingRates = viperutil.Configure(
+		otelConfigKey("keyspace-sampling-rates"),
+		viperutil.Options[string]{
+			FlagName: "otel-keyspace-sampling-rates",
+		},
+	)
+	otelSQLComments = viperutil.Configure(
+		otelConfigKey("sql-comments"),
+		viperutil.Options[bool]{
+			Default:  true,
+			FlagName: "otel-sql-comments",
+		},
+	)
+)
+
+// otelShutdownTimeout is how long the tracer waits for the last spans to be
+// exported when the process exits.
+const otelShutdownTimeout = 5 * time.Second
+
+func init() {
+	pluginFlags = append(pluginFlags, func(fs *pflag.FlagSet) {
+		fs.String("otel-endpoint", otelEndpoint.Default(), "host:port of the OTLP gRPC collector to send spans to. If empty, the OTEL_EXPORTER_OTLP_ENDPOINT environment variable or localhost:4317 is used.")
+		fs.Bool("otel-insecure", otelInsecure.Default(), "Send the spans to the OTLP collector without TLS.")
+		fs.Float64("otel-sampling-rate", otelSamplingRate.Default(), "Fraction of the traces that start in this process that are sampled by the opentelemetry tracer.")
+		fs.String("otel-keyspace-sampling-rates", otelKeyspaceSamplingRates.Default(), "Comma-separated list of keyspace:rate pairs that override --otel-sampling-rate for the traces of the queries that target a keyspace, for example 'commerce:1,customer:0.01'.")
+		fs.Bool("otel-sql-comments", otelSQLComments.Default(), "Add the traceparent of the sampled queries to the queries that vttablet sends to MySQL, as a comment.")
+
+		viperutil.BindFlags(fs, otelEndpoint, otelInsecure, otelSamplingRate, otelKeyspaceSamplingRates, otelSQLComments)
+	})
+
+	tracingBackendFactories["opentelemetry"] = newOpenTelemetryTracer
+}
+
+// newOpenTelemetryTracer creates a tracingService that exports its spans to
+// an OTLP collector over gRPC. The trace context is propagated with the W3C
+// traceparent header across gRPC calls, and as a comment in the queries sent
+// to MySQL.
+func newOpenTelemetryTracer(serviceName string) (tracingService, io.Closer, error) {
+	keyspaceRates, err := parseKeyspaceSamplingRates(otelKeyspaceSamplingRates.Get())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var opts []otlptracegrpc.Option
+	if endpoint := otelEndpoint.Get(); endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+	}
+	if otelInsecure.Get() {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sampler := newKeyspaceSampler(otelSamplingRate.Get(), keyspaceRates)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	log.Infof("Tracing with opentelemetry as %v, sampler %v", serviceName, sampler.Description())
+
+	return newOpenTelemetryService(provider, otelSQLComments.Get()), &otelCloser{provider: provider}, nil
+}
+
+func newOpenTelemetryService(provider oteltrace.TracerProvider, sqlComments bool) *openTelemetryService {
+	return &openTelemetryService{
+		tracer:      provider.Tracer("vitess.io/vitess"),
+		propagator:  propagation.TraceContext{},
+		sqlComments: sqlComments,
+	}
+}
+
+type otelCloser struct {
+	provider *sdktrace.TracerProvider
+}
+
+// Close exports the spans that are left and stops the tracer provider.
+func (c *otelCloser) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), otelShutdownTimeout)
+	defer cancel()
+	return c.provider.Shutdown(ctx)
+}
+
+// parseKeyspaceSamplingRates parses a list of keyspace:rate pairs.
+func parseKeyspaceSamplingRates(in string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	if in == "" {
+		return rates, nil
+	}
+	for _, pair := range strings.Split(in, ",") {
+		keyspace, rate, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || keyspace == "" {
+			return nil, fmt.Errorf("invalid keyspace sampling rate %q, expected keyspace:rate", pair)
+		}
+		r, err := strconv.ParseFloat(rate, 64)
+		if err != nil || r < 0 || r > 1 {
+			return nil, fmt.Errorf("invalid sampling rate %q for keyspace %s, expected a number between 0 and 1", rate, keyspace)
+		}
+		rates[keyspace] = r
+	}
+	return rates, nil
+}
+
+// keyspaceSampler samples the traces that start in this process at the rate
+// of the keyspace that WithKeyspace set in their context, or at the default
+// rate.
+type keyspaceSampler struct {
+	defaultSampler sdktrace.Sampler
+	keyspaces      map[string]sdktrace.Sampler
+}
+
+func newKeyspaceSampler(rate float64, keyspaceRates map[string]float64) *keyspaceSampler {
+	ks := &keyspaceSampler{
+		defaultSampler: sdktrace.TraceIDRatioBased(rate),
+		keyspaces:      make(map[string]sdktrace.Sampler, len(keyspaceRates)),
+	}
+	for keyspace, r := range keyspaceRates {
+		ks.keyspaces[keyspace] = sdktrace.TraceIDRatioBased(r)
+	}
+	return ks
+}
+
+// ShouldSample is part of the sdktrace.Sampler interface.
+func (ks *keyspaceSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if sampler, ok := ks.keyspaces[keyspaceFromContext(params.ParentContext)]; ok {
+		return sampler.ShouldSample(params)
+	}
+	return ks.defaultSampler.ShouldSample(params)
+}
+
+// Description is part of the sdktrace.Sampler interface.
+func (ks *keyspaceSampler) Description() string {
+	return fmt.Sprintf("KeyspaceSampler{default:%s,keyspaces:%d}", ks.defaultSampler.Description(), len(ks.keyspaces))
+}
+
+var _ Span = (*otelSpan)(nil)
+
+type otelSpan struct {
+	span oteltrace.Span
+}
+
+// Finish will mark a span as finished
+func (s otelSpan) Finish() {
+	s.span.End()
+}
+
+// Annotate will add information to an existing span
+func (s otelSpan) Annotate(key string, value any) {
+	s.span.SetAttributes(otelAttribute(key, value))
+}
+
+func otelAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case uint64:
+		return attribute.Int64(key, int64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	}
+	return attribute.String(key, fmt.Sprint(value))
+}
+
+var _ tracingService = (*openTelemetryService)(nil)
+
+type openTelemetryService struct {
+	tracer      oteltrace.Tracer
+	propagator  propagation.TextMapPropagator
+	sqlComments bool
+}
+
+// New is part of an interface implementation
+func (ots *openTelemetryService) New(parent Span, label string) Span {
+	return ots.NewFromContext(context.Background(), parent, label)
+}
+
+// NewFromContext is part of an interface implementation
+func (ots *openTelemetryService) NewFromContext(ctx context.Context, parent Span, label string) Span {
+	if p, ok := parent.(otelSpan); ok {
+		ctx = oteltrace.ContextWithSpan(ctx, p.span)
+	}
+	_, span := ots.tracer.Start(ctx, label)
+	return otelSpan{span: span}
+}
+
+// NewFromString creates a span whose parent is the span context in parent,
+// which is a base64 encoded JSON map of the traceparent and tracestate
+// headers.
+func (ots *openTelemetryService) NewFromString(parent, label string) (Span, error) {
+	decoded, err := base64.StdEncoding.DecodeString(parent)
+	if err != nil {
+		return nil, err
+	}
+	carrier := propagation.MapCarrier{}
+	if err := json.Unmarshal(decoded, &carrier); err != nil {
+		return nil, err
+	}
+	ctx := ots.propagator.Extract(context.Background(), carrier)
+	if !oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return nil, vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "failed to deserialize span context")
+	}
+	_, span := ots.tracer.Start(ctx, label)
+	return otelSpan{span: span}, nil
+}
+
+// FromContext is part of an interface implementation
+func (ots *openTelemetryService) FromContext(ctx context.Context) (Span, bool) {
+	span := oteltrace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return nil, false
+	}
+	return otelSpan{span: span}, true
+}
+
+// NewContext is part of an interface implementation
+func (ots *openTelemetryService) NewContext(parent context.Context, s Span) context.Context {
+	span, ok := s.(otelSpan)
+	if !ok {
+		return nil
+	}
+	return oteltrace.ContextWithSpan(parent, span.span)
+}
+
+// SQLComment returns the traceparent of the span in ctx as a comment, in the
+// format of sqlcommenter, if the span is sampled.
+func (ots *openTelemetryService) SQLComment(ctx context.Context) string {
+	if !ots.sqlComments || !oteltrace.SpanContextFromContext(ctx).IsSampled() {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	ots.propagator.Inject(ctx, carrier)
+	traceparent := carrier.Get("traceparent")
+	if traceparent == "" {
+		return ""
+	}
+	return "/*traceparent='" + traceparent + "'*/"
+}
+
+// AddGrpcServerOptions is part of an interface implementation
+func (ots *openTelemetryService) AddGrpcServerOptions(addInterceptors func(s grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor)) {
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := ots.startServerSpan(ctx, info.FullMethod)
+		defer span.End()
+		resp, err := handler(ctx, req)
+		recordError(span, err)
+		return resp, err
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := ots.startServerSpan(ss.Context(), info.FullMethod)
+		defer span.End()
+		err := handler(srv, &otelServerStream{ServerStream: ss, ctx: ctx})
+		recordError(span, err)
+		return err
+	}
+	addInterceptors(stream, unary)
+}
+
+func (ots *openTelemetryService) startServerSpan(ctx context.Context, method string) (context.Context, oteltrace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = ots.propagator.Extract(ctx, metadataCarrier(md))
+	return ots.tracer.Start(ctx, method, oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+}
+
+// AddGrpcClientOptions is part of an interface implementation
+func (ots *openTelemetryService) AddGrpcClientOptions(addInterceptors func(s grpc.StreamClientInterceptor, u grpc.UnaryClientInterceptor)) {
+	unary := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := ots.startClientSpan(ctx, method)
+		defer span.End()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		recordError(span, err)
+		return err
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		// The span of a stream covers its creation only, as the stream
+		// doesn't tell when its caller is done with it.
+		ctx, span := ots.startClientSpan(ctx, method)
+		defer span.End()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		recordError(span, err)
+		return cs, err
+	}
+	addInterceptors(stream, unary)
+}
+
+func (ots *openTelemetryService) startClientSpan(ctx context.Context, method string) (context.Context, oteltrace.Span) {
+	ctx, span := ots.tracer.Start(ctx, method, oteltrace.WithSpanKind(oteltrace.SpanKindClient))
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	ots.propagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+func recordError(span oteltrace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// otelServerStream is a grpc.ServerStream whose context holds the span of
+// the call.
+type otelServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *otelServerStream) Context() context.Context {
+	return ss.ctx
+}
+
+// metadataCarrier lets the propagator read and write the trace context in
+// the metadata of a gRPC call.
+type metadataCarrier metadata.MD
+
+func (mc metadataCarrier) Get(key string) string {
+	values := metadata.MD(mc).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (mc metadataCarrier) Set(key, value string) {
+	metadata.MD(mc).Set(key, value)
+}
+
+func (mc metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(mc))
+	for k := range mc {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func newTestOpenTelemetryService(t *testing.T, sampler sdktrace.Sampler) (*openTelemetryService, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter), sdktrace.WithSampler(sampler))
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+	return newOpenTelemetryService(provider, true), exporter
+}
+
+func TestOpenTelemetrySpans(t *testing.T) {
+	ots, exporter := newTestOpenTelemetryService(t, sdktrace.AlwaysSample())
+	defer func(tracer tracingService) {
+		currentTracer = tracer
+	}(currentTracer)
+	currentTracer = ots
+
+	parent, ctx := NewSpan(context.Background(), "parent")
+	child, ctx := NewSpan(ctx, "child")
+	child.Annotate("shard_queries", uint64(2))
+	child.Annotate("plan_type", "Scatter")
+
+	// The comment carries the span that the queries are sent for.
+	spanContext := oteltrace.SpanContextFromContext(ctx)
+	assert.Equal(t, "/*traceparent='00-"+spanContext.TraceID().String()+"-"+spanContext.SpanID().String()+"-01'*/", SQLComment(ctx))
+
+	child.Finish()
+	parent.Finish()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.Int64("shard_queries", 2),
+		attribute.String("plan_type", "Scatter"),
+	}, spans[0].Attributes)
+}
+
+func TestOpenTelemetryNewFromString(t *testing.T) {
+	ots, exporter := newTestOpenTelemetryService(t, sdktrace.AlwaysSample())
+
+	carrier, err := json.Marshal(map[string]string{
+		"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+	})
+	require.NoError(t, err)
+	span, err := ots.NewFromString(base64.StdEncoding.EncodeToString(carrier), "label")
+	require.NoError(t, err)
+	span.Finish()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", spans[0].Parent.SpanID().String())
+
+	carrier, err = json.Marshal(map[string]string{"traceparent": "garbage"})
+	require.NoError(t, err)
+	_, err = ots.NewFromString(base64.StdEncoding.EncodeToString(carrier), "label")
+	assert.EqualError(t, err, "failed to deserialize span context")
+}
+
+func TestOpenTelemetryGrpcPropagation(t *testing.T) {
+	ots, exporter := newTestOpenTelemetryService(t, sdktrace.AlwaysSample())
+
+	var clientInterceptor grpc.UnaryClientInterceptor
+	ots.AddGrpcClientOptions(func(_ grpc.StreamClientInterceptor, u grpc.UnaryClientInterceptor) {
+		clientInterceptor = u
+	})
+	var serverInterceptor grpc.UnaryServerInterceptor
+	ots.AddGrpcServerOptions(func(_ grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor) {
+		serverInterceptor = u
+	})
+
+	root, ctx := ots.New(nil, "vtgate"), context.Background()
+	ctx = ots.NewContext(ctx, root)
+
+	// The client sends the trace context in the metadata of the call, and the
+	// server continues the trace from it.
+	var serverTraceID oteltrace.TraceID
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, ok := metadata.FromOutgoingContext(ctx)
+		require.True(t, ok)
+		require.Len(t, md.Get("traceparent"), 1)
+
+		serverCtx := metadata.NewIncomingContext(context.Background(), md)
+		_, err := serverInterceptor(serverCtx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			serverTraceID = oteltrace.SpanContextFromContext(ctx).TraceID()
+			return nil, nil
+		})
+		return err
+	}
+	err := clientInterceptor(ctx, "/queryservice.Query/Execute", nil, nil, nil, invoker)
+	require.NoError(t, err)
+	root.Finish()
+
+	rootTraceID := root.(otelSpan).span.SpanContext().TraceID()
+	assert.Equal(t, rootTraceID, serverTraceID)
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	for _, span := range spans {
+		assert.Equal(t, rootTraceID, span.SpanContext.TraceID())
+	}
+}
+
+func TestKeyspaceSampler(t *testing.T) {
+	rates, err := parseKeyspaceSamplingRates("commerce:1, customer:0")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"commerce": 1, "customer": 0}, rates)
+
+	ots, _ := newTestOpenTelemetryService(t, sdktrace.ParentBased(newKeyspaceSampler(0, rates)))
+	defer func(tracer tracingService) {
+		currentTracer = tracer
+	}(currentTracer)
+	currentTracer = ots
+
+	sampled := func(ctx context.Context) bool {
+		span, ctx := NewSpan(ctx, "query")
+		defer span.Finish()
+		// The children of a span follow its sampling decision.
+		child, ctx := NewSpan(WithKeyspace(ctx, "commerce"), "child")
+		defer child.Finish()
+		return oteltrace.SpanContextFromContext(ctx).IsSampled()
+	}
+	assert.True(t, sampled(WithKeyspace(context.Background(), "commerce")))
+	assert.False(t, sampled(WithKeyspace(context.Background(), "customer")))
+	assert.False(t, sampled(context.Background()))
+
+	// No comment is added for the spans that are not sampled.
+	span, ctx := NewSpan(context.Background(), "query")
+	defer span.Finish()
+	assert.Empty(t, SQLComment(ctx))
+
+	_, err = parseKeyspaceSamplingRates("commerce")
+	assert.EqualError(t, err, `invalid keyspace sampling rate "commerce", expected keyspace:rate`)
+	_, err = parseKeyspaceSamplingRates("commerce:2")
+	assert.EqualError(t, err, `invalid sampling rate "2" for keyspace commerce, expected a number between 0 and 1`)
+}
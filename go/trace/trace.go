@@ -48,7 +48,12 @@ type Span interface {
 // If no tracing plugin is installed, it returns a fake Span that does nothing.
 func NewSpan(inCtx context.Context, label string) (Span, context.Context) {
 	parent, _ := currentTracer.FromContext(inCtx)
-	span := currentTracer.New(parent, label)
+	var span Span
+	if cs, ok := currentTracer.(contextSpanner); ok {
+		span = cs.NewFromContext(inCtx, parent, label)
+	} else {
+		span = currentTracer.New(parent, label)
+	}
 	outCtx := currentTracer.NewContext(inCtx, span)
 
 	return span, outCtx
@@ -91,6 +96,36 @@ func CopySpan(parentCtx, spanCtx context.Context) context.Context {
 	return parentCtx
 }
 
+type keyspaceKey struct{}
+
+// WithKeyspace returns a context that tells the tracing service that the
+// spans created from it belong to a query that targets keyspace, so that it
+// can sample them at the rate of the keyspace.
+func WithKeyspace(ctx context.Context, keyspace string) context.Context {
+	if keyspace == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, keyspaceKey{}, keyspace)
+}
+
+func keyspaceFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	keyspace, _ := ctx.Value(keyspaceKey{}).(string)
+	return keyspace
+}
+
+// SQLComment returns a comment that carries the span in ctx to MySQL, to be
+// added to the queries that are sent for it, or "" if the tracing service
+// doesn't propagate spans to MySQL.
+func SQLComment(ctx context.Context) string {
+	if sc, ok := currentTracer.(sqlCommenter); ok {
+		return sc.SQLComment(ctx)
+	}
+	return ""
+}
+
 // AddGrpcServerOptions adds GRPC interceptors that read the parent span from the grpc packets
 func AddGrpcServerOptions(addInterceptors func(s grpc.StreamServerInterceptor, u grpc.UnaryServerInterceptor)) {
 	currentTracer.AddGrpcServerOptions(addInterceptors)
@@ -122,6 +157,18 @@ type tracingService interface {
 	AddGrpcClientOptions(addInterceptors func(s grpc.StreamClientInterceptor, u grpc.UnaryClientInterceptor))
 }
 
+// contextSpanner is implemented by the tracing services whose spans depend on
+// the context they are created from, like the keyspace that WithKeyspace sets.
+type contextSpanner interface {
+	NewFromContext(ctx context.Context, parent Span, label string) Span
+}
+
+// sqlCommenter is implemented by the tracing services that propagate spans
+// to MySQL in query comments.
+type sqlCommenter interface {
+	SQLComment(ctx context.Context) string
+}
+
 // TracerFactory creates a tracing service for the service provided. It's important to close the provided io.Closer
 // object to make sure that all spans are sent to the backend before the process exits.
 type TracerFactory func(serviceName string) (tracingService, io.Closer, error)
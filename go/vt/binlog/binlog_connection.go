@@ -48,8 +48,10 @@ type BinlogConnection struct {
 	*mysql.Conn
 	cp       dbconfigs.Connector
 	serverID uint32
-	cancel   context.CancelFunc
-	wg       sync.WaitGroup
+	// pooledServerID is set if serverID comes from serverIDPool.
+	pooledServerID bool
+	cancel         context.CancelFunc
+	wg             sync.WaitGroup
 }
 
 // serverIDPool is the IDPool for server IDs used to connect as a replica.
@@ -73,10 +75,30 @@ func NewBinlogConnection(cp dbconfigs.Connector) (*BinlogConnection, error) {
 		return nil, err
 	}
 
+	bc := &BinlogConnection{
+		Conn:           conn,
+		cp:             cp,
+		serverID:       serverIDPool.Get(),
+		pooledServerID: true,
+	}
+	log.Infof("new binlog connection: serverID=%d", bc.serverID)
+	return bc, nil
+}
+
+// NewBinlogConnectionWithServerID creates a new binlog connection to the
+// mysqld instance that identifies itself with the given server ID instead of
+// one from the pool. It is used for the external mysqls whose server IDs are
+// managed by their operators.
+func NewBinlogConnectionWithServerID(cp dbconfigs.Connector, serverID uint32) (*BinlogConnection, error) {
+	conn, err := connectForReplication(cp)
+	if err != nil {
+		return nil, err
+	}
+
 	bc := &BinlogConnection{
 		Conn:     conn,
 		cp:       cp,
-		serverID: serverIDPool.Get(),
+		serverID: serverID,
 	}
 	log.Infof("new binlog connection: serverID=%d", bc.serverID)
 	return bc, nil
@@ -302,8 +324,12 @@ func (bc *BinlogConnection) Close() {
 			bc.cancel = nil
 		}
 
-		log.Infof("closing binlog MySQL client with serverID %v. Will recycle ID.", bc.serverID)
 		bc.Conn = nil
-		serverIDPool.Put(bc.serverID)
+		if bc.pooledServerID {
+			log.Infof("closing binlog MySQL client with serverID %v. Will recycle ID.", bc.serverID)
+			serverIDPool.Put(bc.serverID)
+		} else {
+			log.Infof("closing binlog MySQL client with serverID %v.", bc.serverID)
+		}
 	}
 }
@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binlog
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// StreamBinlogFile sends the events of the binary log file at path to events,
+// as a binlog dump from pos would. The events of the transactions that pos
+// already contains are skipped, but the FORMAT_DESCRIPTION, PREVIOUS_GTIDS and
+// ROTATE events are always sent.
+//
+// It returns pos with the GTIDs of all the transactions of the file, that is
+// the position from which the stream continues after the file.
+func StreamBinlogFile(ctx context.Context, path string, pos replication.Position, events chan<- mysql.BinlogEvent) (replication.Position, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return pos, err
+	}
+	defer file.Close()
+
+	reader, err := mysql.NewBinlogFileReader(file)
+	if err != nil {
+		return pos, vterrors.Wrapf(err, "cannot read binary log %v", path)
+	}
+
+	var format mysql.BinlogFormat
+	skipping := false
+	for {
+		ev, err := reader.ReadEvent()
+		if err == io.EOF {
+			return pos, nil
+		}
+		if err != nil {
+			return pos, vterrors.Wrapf(err, "cannot read binary log %v", path)
+		}
+
+		send := !skipping
+		switch {
+		case ev.IsFormatDescription():
+			if format, err = ev.Format(); err != nil {
+				return pos, vterrors.Wrapf(err, "cannot parse the format of binary log %v", path)
+			}
+			send = true
+		case ev.IsPreviousGTIDs(), ev.IsRotate():
+			send = true
+		case ev.IsGTID():
+			if format.IsZero() {
+				return pos, vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "binary log %v has a GTID event before its FORMAT_DESCRIPTION event", path)
+			}
+			stripped, _, err := ev.StripChecksum(format)
+			if err != nil {
+				return pos, vterrors.Wrapf(err, "cannot read binary log %v", path)
+			}
+			gtid, _, err := stripped.GTID(format)
+			if err != nil {
+				return pos, vterrors.Wrapf(err, "cannot parse GTID of binary log %v", path)
+			}
+			skipping = pos.GTIDSet != nil && pos.GTIDSet.ContainsGTID(gtid)
+			send = !skipping
+			pos = replication.AppendGTID(pos, gtid)
+		}
+		if !send {
+			continue
+		}
+
+		select {
+		case events <- ev:
+		case <-ctx.Done():
+			return pos, ctx.Err()
+		}
+	}
+}
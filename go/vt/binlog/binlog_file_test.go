@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package binlog
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/replication"
+)
+
+func TestStreamBinlogFile(t *testing.T) {
+	f := mysql.NewMySQL56BinlogFormat()
+	s := mysql.NewFakeBinlogStream()
+	sid, err := replication.ParseSID("00010203-0405-0607-0809-0a0b0c0d0e0f")
+	require.NoError(t, err)
+
+	transaction := func(seq int64, sql string) []mysql.BinlogEvent {
+		return []mysql.BinlogEvent{
+			mysql.NewMySQL56GTIDEvent(f, s, replication.Mysql56GTID{Server: sid, Sequence: seq}),
+			mysql.NewQueryEvent(f, s, mysql.Query{Database: "db", SQL: "BEGIN"}),
+			mysql.NewQueryEvent(f, s, mysql.Query{Database: "db", SQL: sql}),
+			mysql.NewXIDEvent(f, s),
+		}
+	}
+	fd := mysql.NewFormatDescriptionEvent(f, s)
+	tx1 := transaction(1, "insert into t values (1)")
+	tx2 := transaction(2, "insert into t values (2)")
+	rotate := mysql.NewRotateEvent(f, s, 4, "binlog.000002")
+
+	var file bytes.Buffer
+	file.Write(mysql.BinglogMagicNumber)
+	for _, ev := range append(append(append([]mysql.BinlogEvent{fd}, tx1...), tx2...), rotate) {
+		file.Write(ev.Bytes())
+	}
+	path := filepath.Join(t.TempDir(), "binlog.000001")
+	require.NoError(t, os.WriteFile(path, file.Bytes(), 0o600))
+
+	stream := func(pos replication.Position) ([]mysql.BinlogEvent, replication.Position, error) {
+		events := make(chan mysql.BinlogEvent, 20)
+		pos, err := StreamBinlogFile(context.Background(), path, pos, events)
+		close(events)
+		var got []mysql.BinlogEvent
+		for ev := range events {
+			got = append(got, ev)
+		}
+		return got, pos, err
+	}
+	bytesOf := func(events ...mysql.BinlogEvent) [][]byte {
+		var b [][]byte
+		for _, ev := range events {
+			b = append(b, ev.Bytes())
+		}
+		return b
+	}
+
+	// From scratch, all the events are sent.
+	got, pos, err := stream(replication.Position{})
+	require.NoError(t, err)
+	assert.Equal(t, bytesOf(append(append(append([]mysql.BinlogEvent{fd}, tx1...), tx2...), rotate)...), bytesOf(got...))
+	assert.Equal(t, "00010203-0405-0607-0809-0a0b0c0d0e0f:1-2", pos.GTIDSet.String())
+
+	// The transactions already streamed are skipped.
+	from, err := replication.DecodePosition("MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1")
+	require.NoError(t, err)
+	got, pos, err = stream(from)
+	require.NoError(t, err)
+	assert.Equal(t, bytesOf(append(append([]mysql.BinlogEvent{fd}, tx2...), rotate)...), bytesOf(got...))
+	assert.Equal(t, "00010203-0405-0607-0809-0a0b0c0d0e0f:1-2", pos.GTIDSet.String())
+
+	// A missing file is an error.
+	_, err = StreamBinlogFile(context.Background(), filepath.Join(t.TempDir(), "missing"), from, make(chan mysql.BinlogEvent))
+	assert.Error(t, err)
+}
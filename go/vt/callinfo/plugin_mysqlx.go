@@ -0,0 +1,34 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package callinfo
+
+// This file implements the CallInfo interface for MySQL X Protocol contexts.
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/mysql/mysqlx"
+)
+
+// MysqlxCallInfo returns an augmented context with a CallInfo structure,
+// only for MySQL X Protocol contexts.
+func MysqlxCallInfo(ctx context.Context, c *mysqlx.Conn) context.Context {
+	return NewContext(ctx, &mysqlCallInfoImpl{
+		remoteAddr: c.RemoteAddr().String(),
+		user:       c.User,
+	})
+}
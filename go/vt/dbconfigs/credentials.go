@@ -61,6 +61,7 @@ var (
 		"mysqlctld",
 		"vtbackup",
 		"vtcombo",
+		"vtctld",
 		"vttablet",
 	}
 )
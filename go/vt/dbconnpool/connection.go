@@ -26,6 +26,8 @@ import (
 	"vitess.io/vitess/go/pools/smartconnpool"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/dbconfigs"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
 )
 
 type PooledDBConnection = smartconnpool.Pooled[*DBConnection]
@@ -84,8 +86,20 @@ func (dbc *DBConnection) ExecuteFetch(query string, maxrows int, wantfields bool
 	return mqr, nil
 }
 
-// ExecuteStreamFetch overwrites mysql.Conn.ExecuteStreamFetch.
-func (dbc *DBConnection) ExecuteStreamFetch(query string, callback func(*sqltypes.Result) error, alloc func() *sqltypes.Result, streamBufferSize int) error {
+// ExecuteFetchWithWarnings overwrites mysql.Conn.ExecuteFetchWithWarnings.
+func (dbc *DBConnection) ExecuteFetchWithWarnings(query string, maxrows int, wantfields bool) (*sqltypes.Result, []*querypb.QueryWarning, error) {
+	mqr, warnings, err := dbc.Conn.ExecuteFetchWithWarnings(query, maxrows, wantfields)
+	if err != nil {
+		dbc.handleError(err)
+		return nil, nil, err
+	}
+	return mqr, warnings, nil
+}
+
+// ExecuteStreamFetch overwrites mysql.Conn.ExecuteStreamFetch. The rows are
+// sent in chunks of streamBufferSize bytes or, if streamBufferRows is set, in
+// chunks of streamBufferRows rows.
+func (dbc *DBConnection) ExecuteStreamFetch(query string, callback func(*sqltypes.Result) error, alloc func() *sqltypes.Result, streamBufferSize, streamBufferRows int) error {
 
 	err := dbc.Conn.ExecuteStreamFetch(query)
 	if err != nil {
@@ -122,7 +136,11 @@ func (dbc *DBConnection) ExecuteStreamFetch(query string, callback func(*sqltype
 			byteCount += s.Len()
 		}
 
-		if byteCount >= streamBufferSize {
+		full := byteCount >= streamBufferSize
+		if streamBufferRows > 0 {
+			full = len(qr.Rows) >= streamBufferRows
+		}
+		if full {
 			err = callback(qr)
 			if err != nil {
 				return err
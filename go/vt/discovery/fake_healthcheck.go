@@ -80,6 +80,14 @@ func (fhc *FakeHealthCheck) WaitForAllServingTablets(ctx context.Context, target
 // GetHealthyTabletStats returns only the healthy tablets - Serving true and LastError is not nil
 func (fhc *FakeHealthCheck) GetHealthyTabletStats(target *querypb.Target) []*TabletHealth {
 	result := make([]*TabletHealth, 0)
+	if len(target.TabletTags) > 0 || target.MaxReplicaLagSeconds > 0 {
+		// Like HealthCheckImpl, tablet tags and the maximum replication lag
+		// are not part of the health data key; filtering by them is up to
+		// the caller.
+		target = target.CloneVT()
+		target.TabletTags = nil
+		target.MaxReplicaLagSeconds = 0
+	}
 	fhc.mu.Lock()
 	defer fhc.mu.Unlock()
 	for _, item := range fhc.items {
@@ -361,3 +361,46 @@ func (fbk *FilterByKeyspace) IsIncluded(tablet *topodata.Tablet) bool {
 	_, exist := fbk.keyspaces[tablet.Keyspace]
 	return exist
 }
+
+// FilterByTabletTags is a filter that filters tablets by tablet tag key/values.
+type FilterByTabletTags struct {
+	tags map[string]string
+}
+
+// NewFilterByTabletTags creates a new FilterByTabletTags. All tablets that
+// carry all of the given tags will be forwarded to the TopologyWatcher's
+// consumer.
+func NewFilterByTabletTags(tabletTags map[string]string) *FilterByTabletTags {
+	return &FilterByTabletTags{
+		tags: tabletTags,
+	}
+}
+
+// IsIncluded returns true if the tablet's tags match what we have.
+func (fbtg *FilterByTabletTags) IsIncluded(tablet *topodata.Tablet) bool {
+	return TabletHasTags(tablet, fbtg.tags)
+}
+
+// TabletHasTags returns true if the tablet carries all of the given tags.
+func TabletHasTags(tablet *topodata.Tablet, tags map[string]string) bool {
+	for key, val := range tags {
+		if tabletVal, found := tablet.Tags[key]; !found || tabletVal != val {
+			return false
+		}
+	}
+	return true
+}
+
+// TabletFilters is a TabletFilter that only includes tablets that are
+// included by all of its filters.
+type TabletFilters []TabletFilter
+
+// IsIncluded returns true if the tablet is included by all filters.
+func (tf TabletFilters) IsIncluded(tablet *topodata.Tablet) bool {
+	for _, filter := range tf {
+		if !filter.IsIncluded(tablet) {
+			return false
+		}
+	}
+	return true
+}
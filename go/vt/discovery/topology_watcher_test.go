@@ -461,6 +461,73 @@ func TestFilterByKeyspace(t *testing.T) {
 	}
 }
 
+func TestFilterByTabletTags(t *testing.T) {
+	tags := map[string]string{
+		"instance_type": "i3.xlarge",
+		"team":          "analytics",
+	}
+	filter := NewFilterByTabletTags(tags)
+	combined := TabletFilters{NewFilterByKeyspace(testKeyspacesToWatch), filter}
+
+	testcases := []struct {
+		name             string
+		keyspace         string
+		tags             map[string]string
+		expected         bool
+		expectedCombined bool
+	}{
+		{
+			name:     "no tags",
+			keyspace: "ks1",
+		},
+		{
+			name:     "partial tags",
+			keyspace: "ks1",
+			tags: map[string]string{
+				"team": "analytics",
+			},
+		},
+		{
+			name:     "mismatched value",
+			keyspace: "ks1",
+			tags: map[string]string{
+				"instance_type": "i3.xlarge",
+				"team":          "oltp",
+			},
+		},
+		{
+			name:     "all tags",
+			keyspace: "ks1",
+			tags: map[string]string{
+				"instance_type": "i3.xlarge",
+				"team":          "analytics",
+				"zone":          "a",
+			},
+			expected:         true,
+			expectedCombined: true,
+		},
+		{
+			name:     "all tags in unwatched keyspace",
+			keyspace: "ks3",
+			tags: map[string]string{
+				"instance_type": "i3.xlarge",
+				"team":          "analytics",
+			},
+			expected: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tablet := &topodatapb.Tablet{
+				Keyspace: tc.keyspace,
+				Tags:     tc.tags,
+			}
+			assert.Equal(t, tc.expected, filter.IsIncluded(tablet))
+			assert.Equal(t, tc.expectedCombined, combined.IsIncluded(tablet))
+		})
+	}
+}
+
 // TestFilterByKeyspaceSkipsIgnoredTablets confirms a bug fix for the case when a TopologyWatcher
 // has a FilterByKeyspace TabletFilter configured along with refreshKnownTablets turned off. We want
 // to ensure that the TopologyWatcher:
@@ -46,9 +46,9 @@ func (s SnappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
 }
 
 func appendCompression(opts []grpc.DialOption) ([]grpc.DialOption, error) {
-	if compression == "snappy" {
-		compression := grpc.WithDefaultCallOptions(grpc.UseCompressor("snappy"))
-		opts = append(opts, compression)
+	switch compression {
+	case "snappy", "zstd":
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.UseCompressor(compression)))
 	}
 
 	return opts, nil
@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+
+	"vitess.io/vitess/go/stats"
+)
+
+var (
+	compressionUncompressedBytes = stats.NewCountersWithSingleLabel(
+		"GrpcCompressionUncompressedBytes",
+		"Number of bytes before compression, or after decompression, of gRPC messages by compressor",
+		"Compressor")
+	compressionCompressedBytes = stats.NewCountersWithSingleLabel(
+		"GrpcCompressionCompressedBytes",
+		"Number of bytes after compression, or before decompression, of gRPC messages by compressor",
+		"Compressor")
+
+	zstdEncoders = sync.Pool{New: func() any {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedFastest))
+		return enc
+	}}
+	zstdDecoders = sync.Pool{New: func() any {
+		dec, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+		return dec
+	}}
+)
+
+// ZstdCompressor is a gRPC compressor using the Zstandard algorithm. The
+// compressed and uncompressed sizes of the messages are counted in the
+// GrpcCompression*Bytes stats, so that the compression ratio can be tracked.
+type ZstdCompressor struct{}
+
+// Name is "zstd"
+func (z ZstdCompressor) Name() string {
+	return "zstd"
+}
+
+// Compress wraps with a pooled zstd encoder.
+func (z ZstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	cw := &countingWriter{w: w}
+	enc := zstdEncoders.Get().(*zstd.Encoder)
+	enc.Reset(cw)
+	return &zstdWriter{enc: enc, cw: cw}, nil
+}
+
+// Decompress wraps with a pooled zstd decoder, which is returned to the pool
+// once the message has been read entirely.
+func (z ZstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	cr := &countingReader{r: r}
+	dec := zstdDecoders.Get().(*zstd.Decoder)
+	if err := dec.Reset(cr); err != nil {
+		zstdDecoders.Put(dec)
+		return nil, err
+	}
+	return &zstdReader{dec: dec, cr: cr}, nil
+}
+
+type zstdWriter struct {
+	enc *zstd.Encoder
+	cw  *countingWriter
+	n   int64
+}
+
+func (zw *zstdWriter) Write(p []byte) (int, error) {
+	n, err := zw.enc.Write(p)
+	zw.n += int64(n)
+	return n, err
+}
+
+func (zw *zstdWriter) Close() error {
+	err := zw.enc.Close()
+	zw.enc.Reset(nil)
+	zstdEncoders.Put(zw.enc)
+	compressionUncompressedBytes.Add("zstd", zw.n)
+	compressionCompressedBytes.Add("zstd", zw.cw.n)
+	return err
+}
+
+type zstdReader struct {
+	dec *zstd.Decoder
+	cr  *countingReader
+	n   int64
+}
+
+func (zr *zstdReader) Read(p []byte) (int, error) {
+	if zr.dec == nil {
+		return 0, io.EOF
+	}
+	n, err := zr.dec.Read(p)
+	zr.n += int64(n)
+	if err != nil {
+		_ = zr.dec.Reset(nil)
+		zstdDecoders.Put(zr.dec)
+		zr.dec = nil
+		compressionUncompressedBytes.Add("zstd", zr.n)
+		compressionCompressedBytes.Add("zstd", zr.cr.n)
+	}
+	return n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+func init() {
+	encoding.RegisterCompressor(ZstdCompressor{})
+}
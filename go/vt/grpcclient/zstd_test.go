@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestZstdCompressDecompress(t *testing.T) {
+	require.Equal(t, ZstdCompressor{}, encoding.GetCompressor("zstd"))
+
+	uncompressedBefore := compressionUncompressedBytes.Counts()["zstd"]
+	compressedBefore := compressionCompressedBytes.Counts()["zstd"]
+
+	msg := []byte(strings.Repeat("insert into customer values (1, 'a'); ", 100))
+	for range 3 {
+		// The pooled encoders and decoders are reused.
+		var buf bytes.Buffer
+		w, err := ZstdCompressor{}.Compress(&buf)
+		require.NoError(t, err)
+		_, err = w.Write(msg)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		require.Less(t, buf.Len(), len(msg))
+
+		compressedLen := buf.Len()
+		r, err := ZstdCompressor{}.Decompress(&buf)
+		require.NoError(t, err)
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, msg, got)
+
+		uncompressed := compressionUncompressedBytes.Counts()["zstd"]
+		compressed := compressionCompressedBytes.Counts()["zstd"]
+		require.EqualValues(t, 2*len(msg), uncompressed-uncompressedBefore)
+		require.EqualValues(t, 2*compressedLen, compressed-compressedBefore)
+		uncompressedBefore, compressedBefore = uncompressed, compressed
+	}
+}
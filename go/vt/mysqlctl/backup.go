@@ -27,6 +27,8 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"vitess.io/vitess/go/mysql/replication"
+
 	"vitess.io/vitess/go/textutil"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
@@ -49,7 +51,7 @@ const (
 	backupData                  = "Data"
 
 	// backupManifestFileName is the MANIFEST file name within a backup.
-	backupManifestFileName = "MANIFEST"
+	backupManifestFileName = backupstorage.ManifestFileName
 	// RestoreState is the name of the sentinel file used to detect whether a previous restore
 	// terminated abnormally
 	RestoreState = "restore_in_progress"
@@ -496,3 +498,56 @@ func Restore(ctx context.Context, params RestoreParams) (*BackupManifest, error)
 	params.Logger.Infof("Restore: complete")
 	return manifest, nil
 }
+
+// FindPITRToTimeBackups returns the full backup and incremental backups of a
+// shard that restore it up to restoreToTime, as found by FindPITRToTimePath.
+// The incremental backups archive the binary logs of the shard, and their
+// manifests index them by timestamp, so this tells whether a point in time
+// can be recovered with the backups alone, without a binlog server.
+func FindPITRToTimeBackups(ctx context.Context, bs backupstorage.BackupStorage, keyspace, shard string, restoreToTime time.Time) ([]*BackupManifest, error) {
+	backupDir := GetBackupDir(keyspace, shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+
+	manifests := make([]*BackupManifest, 0, len(bhs))
+	for _, bh := range bhs {
+		bm, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			log.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: can't read MANIFEST: %v", bh.Name(), backupDir, err)
+			continue
+		}
+		manifests = append(manifests, bm)
+	}
+	return FindPITRToTimePath(restoreToTime, manifests)
+}
+
+// FindArchivedBinlogBackups returns the handles of the incremental backups of
+// a shard whose binary logs take a stream from fromPosition to a position
+// containing purgedPosition, in the order in which they are to be streamed,
+// as found by FindBinlogArchivePath.
+func FindArchivedBinlogBackups(ctx context.Context, bs backupstorage.BackupStorage, keyspace, shard string, fromPosition, purgedPosition replication.Position) ([]backupstorage.BackupHandle, error) {
+	backupDir := GetBackupDir(keyspace, shard)
+	bhs, err := bs.ListBackups(ctx, backupDir)
+	if err != nil {
+		return nil, vterrors.Wrap(err, "ListBackups failed")
+	}
+
+	manifests := make([]*BackupManifest, 0, len(bhs))
+	manifestHandleMap := NewManifestHandleMap()
+	for _, bh := range bhs {
+		bm, err := GetBackupManifest(ctx, bh)
+		if err != nil {
+			log.Warningf("Possibly incomplete backup %v in directory %v on BackupStorage: can't read MANIFEST: %v", bh.Name(), backupDir, err)
+			continue
+		}
+		manifests = append(manifests, bm)
+		manifestHandleMap.Map(bm, bh)
+	}
+	path, err := FindBinlogArchivePath(fromPosition.GTIDSet, purgedPosition.GTIDSet, manifests)
+	if err != nil {
+		return nil, err
+	}
+	return manifestHandleMap.Handles(path), nil
+}
@@ -541,6 +541,66 @@ func TestRestoreManifestMySQLVersionValidation(t *testing.T) {
 
 }
 
+func TestFindPITRToTimeBackups(t *testing.T) {
+	ctx := context.Background()
+	generatePosition := func(posRange string) replication.Position {
+		return replication.MustParsePosition(replication.Mysql56FlavorID, fmt.Sprintf("16b1039f-22b6-11ed-b765-0a43f95f28a3:%s", posRange))
+	}
+	backupHandle := func(manifest *BackupManifest) backupstorage.BackupHandle {
+		manifestBytes, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		return &FakeBackupHandle{
+			ReadFileReturnF: func(context.Context, string) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewBuffer(manifestBytes)), nil
+			},
+		}
+	}
+	fullBackup := backupHandle(&BackupManifest{
+		BackupMethod: builtinBackupEngineName,
+		Position:     generatePosition("1-50"),
+		BackupTime:   "2020-02-02T02:20:20.000000Z",
+		FinishedTime: "2020-02-02T02:20:20.000000Z",
+	})
+	incrementalBackup := backupHandle(&BackupManifest{
+		Position:     generatePosition("1-60"),
+		FromPosition: generatePosition("1-50"),
+		Incremental:  true,
+		IncrementalDetails: &IncrementalBackupDetails{
+			FirstTimestamp: "2020-02-02T02:20:21.000000Z",
+			LastTimestamp:  "2020-02-02T02:47:20.000000Z",
+		},
+	})
+	incompleteBackup := &FakeBackupHandle{
+		ReadFileReturnF: func(context.Context, string) (io.ReadCloser, error) {
+			return nil, fmt.Errorf("no MANIFEST")
+		},
+	}
+
+	bs := &FakeBackupStorage{}
+	bs.ListBackupsReturn = FakeBackupStorageListBackupsReturn{
+		BackupHandles: []backupstorage.BackupHandle{fullBackup, incompleteBackup, incrementalBackup},
+	}
+
+	restoreToTime, err := ParseRFC3339("2020-02-02T02:30:00.000000Z")
+	require.NoError(t, err)
+	manifests, err := FindPITRToTimeBackups(ctx, bs, "ks", "-", restoreToTime)
+	require.NoError(t, err)
+	require.Len(t, manifests, 2)
+	require.False(t, manifests[0].Incremental)
+	require.True(t, manifests[1].Incremental)
+	require.Equal(t, "ks/-", bs.ListBackupsCalls[0].Dir)
+
+	// The binary logs archived by the incremental backup don't go that far.
+	restoreToTime, err = ParseRFC3339("2020-02-02T03:00:00.000000Z")
+	require.NoError(t, err)
+	_, err = FindPITRToTimeBackups(ctx, bs, "ks", "-", restoreToTime)
+	require.Error(t, err)
+
+	bs.ListBackupsReturn = FakeBackupStorageListBackupsReturn{Err: fmt.Errorf("storage is down")}
+	_, err = FindPITRToTimeBackups(ctx, bs, "ks", "-", restoreToTime)
+	require.ErrorContains(t, err, "storage is down")
+}
+
 type forTest []FileEntry
 
 func (f forTest) Len() int           { return len(f) }
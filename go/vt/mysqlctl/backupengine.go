@@ -326,6 +326,10 @@ type BackupManifest struct {
 
 	// IncrementalDetails is nil for non-incremental backups
 	IncrementalDetails *IncrementalBackupDetails
+
+	// Encryption is nil unless the backup files, other than the MANIFEST,
+	// were encrypted client-side. It holds the wrapped data key.
+	Encryption *backupstorage.EncryptionInfo `json:",omitempty"`
 }
 
 func (m *BackupManifest) HashKey() string {
@@ -0,0 +1,208 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// An encrypted file starts with a random nonce, followed by the file split
+// in chunks of encryptionChunkSize bytes, each sealed with AES-GCM. The
+// nonce of a chunk is the file nonce XORed with the chunk number, and the
+// last chunk, which is always shorter than encryptionChunkSize (and can be
+// empty), is authenticated as such, so a truncated file fails to decrypt.
+const (
+	encryptionChunkSize = 64 * 1024
+	encryptionNonceSize = 12
+	encryptionTagSize   = 16
+)
+
+var (
+	lastChunk     = []byte{1}
+	notLastChunk  = []byte{0}
+	errTruncation = vterrors.Errorf(vtrpcpb.Code_DATA_LOSS, "encrypted backup file is truncated")
+)
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptedSize returns the size of an encrypted file from the size of the
+// plain one.
+func encryptedSize(size int64) int64 {
+	if size == FileSizeUnknown {
+		return size
+	}
+	return encryptionNonceSize + size + (size/encryptionChunkSize+1)*encryptionTagSize
+}
+
+func chunkNonce(fileNonce []byte, chunk uint64) []byte {
+	nonce := make([]byte, encryptionNonceSize)
+	copy(nonce, fileNonce)
+	binary.BigEndian.PutUint64(nonce[4:], binary.BigEndian.Uint64(nonce[4:])^chunk)
+	return nonce
+}
+
+type encryptingWriter struct {
+	w     io.WriteCloser
+	aead  cipher.AEAD
+	nonce []byte
+	chunk uint64
+	buf   []byte
+}
+
+func newEncryptingWriter(w io.WriteCloser, key []byte) (*encryptingWriter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, encryptionNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(nonce); err != nil {
+		return nil, err
+	}
+	return &encryptingWriter{
+		w:     w,
+		aead:  aead,
+		nonce: nonce,
+		buf:   make([]byte, 0, encryptionChunkSize+encryptionTagSize),
+	}, nil
+}
+
+// Write is part of the io.Writer interface.
+func (ew *encryptingWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(encryptionChunkSize-len(ew.buf), len(p))
+		ew.buf = append(ew.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if len(ew.buf) == encryptionChunkSize {
+			if err := ew.seal(notLastChunk); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (ew *encryptingWriter) seal(ad []byte) error {
+	sealed := ew.aead.Seal(ew.buf[:0], chunkNonce(ew.nonce, ew.chunk), ew.buf, ad)
+	ew.chunk++
+	ew.buf = ew.buf[:0]
+	_, err := ew.w.Write(sealed)
+	return err
+}
+
+// Close is part of the io.Closer interface. It writes the last chunk and
+// closes the underlying writer.
+func (ew *encryptingWriter) Close() error {
+	err := ew.seal(lastChunk)
+	if cerr := ew.w.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+type decryptingReader struct {
+	r     io.ReadCloser
+	aead  cipher.AEAD
+	nonce []byte
+	chunk uint64
+	buf   []byte
+	// plain is what's left to be read of the last decrypted chunk.
+	plain []byte
+	last  bool
+	err   error
+}
+
+func newDecryptingReader(r io.ReadCloser, key []byte) (*decryptingReader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, encryptionNonceSize)
+	if _, err := io.ReadFull(r, nonce); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, errTruncation
+		}
+		return nil, err
+	}
+	return &decryptingReader{
+		r:     r,
+		aead:  aead,
+		nonce: nonce,
+		buf:   make([]byte, encryptionChunkSize+encryptionTagSize),
+	}, nil
+}
+
+// Read is part of the io.Reader interface.
+func (dr *decryptingReader) Read(p []byte) (int, error) {
+	for len(dr.plain) == 0 {
+		switch {
+		case dr.err != nil:
+			return 0, dr.err
+		case dr.last:
+			return 0, io.EOF
+		}
+		dr.err = dr.open()
+	}
+	n := copy(p, dr.plain)
+	dr.plain = dr.plain[n:]
+	return n, nil
+}
+
+func (dr *decryptingReader) open() error {
+	n, err := io.ReadFull(dr.r, dr.buf)
+	ad := notLastChunk
+	switch err {
+	case nil:
+	case io.ErrUnexpectedEOF:
+		ad = lastChunk
+	case io.EOF:
+		return errTruncation
+	default:
+		return err
+	}
+	plain, err := dr.aead.Open(dr.buf[:0], chunkNonce(dr.nonce, dr.chunk), dr.buf[:n], ad)
+	if err != nil {
+		return vterrors.Wrapf(err, "cannot decrypt chunk %v of backup file", dr.chunk)
+	}
+	dr.chunk++
+	dr.plain = plain
+	dr.last = ad[0] == lastChunk[0]
+	return nil
+}
+
+// Close is part of the io.Closer interface.
+func (dr *decryptingReader) Close() error {
+	return dr.r.Close()
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// EncryptionAESGCM encrypts the files of a backup with AES-256-GCM,
+	// using a data key generated for the backup and wrapped by a KeyProvider.
+	EncryptionAESGCM = "aes-gcm"
+
+	// ManifestFileName is the name of the MANIFEST file within a backup.
+	// The MANIFEST is never encrypted, so backups can be listed and
+	// inspected without access to the keys.
+	ManifestFileName = "MANIFEST"
+
+	// dataKeySize is the size of the data keys, for AES-256.
+	dataKeySize = 32
+)
+
+var (
+	// BackupEncryption is the client-side encryption of new backups.
+	// Exported for test purposes.
+	BackupEncryption string
+	// EncryptionKeyProvider is the name of the KeyProvider that wraps the
+	// data keys of new backups. Exported for test purposes.
+	EncryptionKeyProvider string
+	// EncryptionKeyID identifies the master key used to wrap the data keys
+	// of new backups. Exported for test purposes.
+	EncryptionKeyID string
+)
+
+func registerEncryptionFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&BackupEncryption, "backup-storage-encryption", BackupEncryption, "Client-side encryption of the backup files, on top of any encryption done by the storage itself. Supported values: 'aes-gcm'. Default is no encryption.")
+	fs.StringVar(&EncryptionKeyProvider, "backup-storage-encryption-key-provider", EncryptionKeyProvider, "Key management service wrapping the data keys of encrypted backups: 'aws-kms', 'gcp-kms' or 'vault'.")
+	fs.StringVar(&EncryptionKeyID, "backup-storage-encryption-key-id", EncryptionKeyID, "Master key wrapping the data keys of encrypted backups: the key ARN or alias for aws-kms, the CryptoKey resource name for gcp-kms, the transit key name for vault. Changing it only affects new backups, existing ones record their key in the MANIFEST.")
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerEncryptionFlags)
+	servenv.OnParseFor("vttablet", registerEncryptionFlags)
+}
+
+// EncryptionInfo describes how the files of a backup are encrypted. It is
+// recorded in the MANIFEST, so a backup can still be restored after the
+// master key has been rotated.
+type EncryptionInfo struct {
+	// Algorithm is the encryption of the files, i.e. EncryptionAESGCM.
+	Algorithm string
+
+	// KeyProvider is the name of the KeyProvider that wrapped the data key.
+	KeyProvider string
+
+	// KeyID identifies the master key that wrapped the data key.
+	KeyID string
+
+	// KeyVersion is the version of the master key that wrapped the data key,
+	// if the key management service reports it.
+	KeyVersion string `json:",omitempty"`
+
+	// WrappedKey is the data key of the backup, encrypted with the master key.
+	WrappedKey []byte
+}
+
+// KeyProvider wraps and unwraps data keys with master keys held by a key
+// management service.
+type KeyProvider interface {
+	// WrapKey encrypts dataKey with the master key keyID. It returns the
+	// wrapped key, and the version of the master key if known.
+	WrapKey(ctx context.Context, keyID string, dataKey []byte) (wrapped []byte, keyVersion string, err error)
+
+	// UnwrapKey decrypts a data key that was wrapped by WrapKey.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// KeyProviderMap contains the registered implementations of KeyProvider.
+var KeyProviderMap = make(map[string]KeyProvider)
+
+// HandleEncryption returns how the files added to the given backup are
+// encrypted, or nil if they are stored as is. Backup engines record it in
+// the MANIFEST.
+func HandleEncryption(bh BackupHandle) *EncryptionInfo {
+	if eh, ok := bh.(*encryptedHandle); ok {
+		return eh.info
+	}
+	return nil
+}
+
+func validateEncryption() error {
+	if BackupEncryption == "" {
+		return nil
+	}
+	if BackupEncryption != EncryptionAESGCM {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unsupported backup encryption %q", BackupEncryption)
+	}
+	if _, ok := KeyProviderMap[EncryptionKeyProvider]; !ok {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "no registered backup encryption key provider %q", EncryptionKeyProvider)
+	}
+	if EncryptionKeyID == "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "backup encryption requires a key id")
+	}
+	return nil
+}
+
+// newDataKey generates the data key of a new backup, and wraps it with the
+// configured master key.
+func newDataKey(ctx context.Context) (*EncryptionInfo, []byte, error) {
+	if err := validateEncryption(); err != nil {
+		return nil, nil, err
+	}
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, nil, vterrors.Wrapf(err, "cannot generate backup data key")
+	}
+	wrapped, version, err := KeyProviderMap[EncryptionKeyProvider].WrapKey(ctx, EncryptionKeyID, key)
+	if err != nil {
+		return nil, nil, vterrors.Wrapf(err, "cannot wrap backup data key with %v key %v", EncryptionKeyProvider, EncryptionKeyID)
+	}
+	return &EncryptionInfo{
+		Algorithm:   EncryptionAESGCM,
+		KeyProvider: EncryptionKeyProvider,
+		KeyID:       EncryptionKeyID,
+		KeyVersion:  version,
+		WrappedKey:  wrapped,
+	}, key, nil
+}
+
+// unwrapDataKey returns the data key of an encrypted backup.
+func unwrapDataKey(ctx context.Context, info *EncryptionInfo) ([]byte, error) {
+	if info.Algorithm != EncryptionAESGCM {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "unsupported backup encryption %q", info.Algorithm)
+	}
+	provider, ok := KeyProviderMap[info.KeyProvider]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no registered backup encryption key provider %q", info.KeyProvider)
+	}
+	key, err := provider.UnwrapKey(ctx, info.KeyID, info.WrappedKey)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot unwrap backup data key with %v key %v", info.KeyProvider, info.KeyID)
+	}
+	if len(key) != dataKeySize {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "invalid backup data key size %v", len(key))
+	}
+	return key, nil
+}
+
+// encryptedStorage is the BackupStorage returned by GetBackupStorage. It
+// encrypts the files of new backups if encryption is enabled, and decrypts
+// the files of encrypted backups, whatever the current configuration is.
+type encryptedStorage struct {
+	BackupStorage
+}
+
+// ListBackups is part of the BackupStorage interface.
+func (es *encryptedStorage) ListBackups(ctx context.Context, dir string) ([]BackupHandle, error) {
+	handles, err := es.BackupStorage.ListBackups(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	for i, bh := range handles {
+		handles[i] = &encryptedHandle{BackupHandle: bh}
+	}
+	return handles, nil
+}
+
+// StartBackup is part of the BackupStorage interface.
+func (es *encryptedStorage) StartBackup(ctx context.Context, dir, name string) (BackupHandle, error) {
+	bh, err := es.BackupStorage.StartBackup(ctx, dir, name)
+	if err != nil || BackupEncryption == "" {
+		return bh, err
+	}
+	info, key, err := newDataKey(ctx)
+	if err != nil {
+		_ = bh.AbortBackup(ctx)
+		return nil, err
+	}
+	return &encryptedHandle{BackupHandle: bh, info: info, key: key, loaded: true}, nil
+}
+
+// WithParams is part of the BackupStorage interface.
+func (es *encryptedStorage) WithParams(params Params) BackupStorage {
+	return &encryptedStorage{BackupStorage: es.BackupStorage.WithParams(params)}
+}
+
+// encryptedHandle encrypts or decrypts the files of a backup, except for
+// the MANIFEST.
+type encryptedHandle struct {
+	BackupHandle
+
+	info *EncryptionInfo
+
+	mu sync.Mutex
+	// key is the data key of the backup, nil if its files are not encrypted.
+	key []byte
+	// loaded is set once key is known: from the start for new backups, or
+	// after reading the MANIFEST for existing ones.
+	loaded bool
+}
+
+// AddFile is part of the BackupHandle interface.
+func (eh *encryptedHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	if eh.key == nil || filename == ManifestFileName {
+		return eh.BackupHandle.AddFile(ctx, filename, filesize)
+	}
+	wc, err := eh.BackupHandle.AddFile(ctx, filename, encryptedSize(filesize))
+	if err != nil {
+		return nil, err
+	}
+	ew, err := newEncryptingWriter(wc, eh.key)
+	if err != nil {
+		wc.Close()
+		return nil, err
+	}
+	return ew, nil
+}
+
+// ReadFile is part of the BackupHandle interface.
+func (eh *encryptedHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	if filename == ManifestFileName {
+		return eh.BackupHandle.ReadFile(ctx, filename)
+	}
+	key, err := eh.dataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := eh.BackupHandle.ReadFile(ctx, filename)
+	if err != nil || key == nil {
+		return rc, err
+	}
+	dr, err := newDecryptingReader(rc, key)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return dr, nil
+}
+
+// dataKey returns the data key of the backup, reading the encryption
+// details from its MANIFEST the first time.
+func (eh *encryptedHandle) dataKey(ctx context.Context) ([]byte, error) {
+	eh.mu.Lock()
+	defer eh.mu.Unlock()
+	if eh.loaded {
+		return eh.key, nil
+	}
+
+	rc, err := eh.BackupHandle.ReadFile(ctx, ManifestFileName)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot read %v of backup %v", ManifestFileName, eh.Name())
+	}
+	defer rc.Close()
+	var manifest struct {
+		Encryption *EncryptionInfo
+	}
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, vterrors.Wrapf(err, "cannot decode %v of backup %v", ManifestFileName, eh.Name())
+	}
+	if manifest.Encryption != nil {
+		eh.key, err = unwrapDataKey(ctx, manifest.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		eh.info = manifest.Encryption
+	}
+	eh.loaded = true
+	return eh.key, nil
+}
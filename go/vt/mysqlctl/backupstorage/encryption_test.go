@@ -0,0 +1,323 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/concurrency"
+)
+
+// memHandle is a BackupHandle keeping its files in memory.
+type memHandle struct {
+	concurrency.AllErrorRecorder
+	name  string
+	files map[string]*bytes.Buffer
+	sizes map[string]int64
+}
+
+func (h *memHandle) Directory() string { return "dir" }
+func (h *memHandle) Name() string      { return h.name }
+
+func (h *memHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	buf := &bytes.Buffer{}
+	h.files[filename] = buf
+	h.sizes[filename] = filesize
+	return nopWriteCloser{buf}, nil
+}
+
+func (h *memHandle) EndBackup(ctx context.Context) error   { return nil }
+func (h *memHandle) AbortBackup(ctx context.Context) error { return nil }
+
+func (h *memHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	buf, ok := h.files[filename]
+	if !ok {
+		return nil, fmt.Errorf("no file %v", filename)
+	}
+	return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// memStorage is a BackupStorage with a single backup.
+type memStorage struct {
+	bh *memHandle
+}
+
+func (s *memStorage) ListBackups(ctx context.Context, dir string) ([]BackupHandle, error) {
+	return []BackupHandle{s.bh}, nil
+}
+
+func (s *memStorage) StartBackup(ctx context.Context, dir, name string) (BackupHandle, error) {
+	s.bh = &memHandle{name: name, files: map[string]*bytes.Buffer{}, sizes: map[string]int64{}}
+	return s.bh, nil
+}
+
+func (s *memStorage) RemoveBackup(ctx context.Context, dir, name string) error { return nil }
+func (s *memStorage) Close() error                                             { return nil }
+func (s *memStorage) WithParams(params Params) BackupStorage                   { return s }
+
+// xorKeyProvider is a KeyProvider wrapping keys with a XOR, and recording
+// the key ids it is used with.
+type xorKeyProvider struct {
+	keyIDs []string
+}
+
+func (p *xorKeyProvider) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, string, error) {
+	p.keyIDs = append(p.keyIDs, keyID)
+	return xor(dataKey), "v1", nil
+}
+
+func (p *xorKeyProvider) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	p.keyIDs = append(p.keyIDs, keyID)
+	return xor(wrapped), nil
+}
+
+func xor(b []byte) []byte {
+	res := make([]byte, len(b))
+	for i := range b {
+		res[i] = b[i] ^ 0x5a
+	}
+	return res
+}
+
+func setEncryption(t *testing.T, encryption, provider, keyID string) {
+	oldEncryption, oldProvider, oldKeyID := BackupEncryption, EncryptionKeyProvider, EncryptionKeyID
+	BackupEncryption, EncryptionKeyProvider, EncryptionKeyID = encryption, provider, keyID
+	t.Cleanup(func() {
+		BackupEncryption, EncryptionKeyProvider, EncryptionKeyID = oldEncryption, oldProvider, oldKeyID
+	})
+}
+
+func registerXORKeyProvider(t *testing.T) *xorKeyProvider {
+	p := &xorKeyProvider{}
+	KeyProviderMap["xor"] = p
+	t.Cleanup(func() {
+		delete(KeyProviderMap, "xor")
+	})
+	return p
+}
+
+// writeBackup writes a backup with the given files through bs, and a
+// MANIFEST recording its encryption like the backup engines do.
+func writeBackup(t *testing.T, bs BackupStorage, files map[string][]byte) {
+	ctx := context.Background()
+	bh, err := bs.StartBackup(ctx, "dir", "backup")
+	require.NoError(t, err)
+	for name, data := range files {
+		wc, err := bh.AddFile(ctx, name, int64(len(data)))
+		require.NoError(t, err)
+		_, err = wc.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, wc.Close())
+	}
+	manifest, err := json.Marshal(struct {
+		BackupName string
+		Encryption *EncryptionInfo `json:",omitempty"`
+	}{
+		BackupName: bh.Name(),
+		Encryption: HandleEncryption(bh),
+	})
+	require.NoError(t, err)
+	wc, err := bh.AddFile(ctx, ManifestFileName, FileSizeUnknown)
+	require.NoError(t, err)
+	_, err = wc.Write(manifest)
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+	require.NoError(t, bh.EndBackup(ctx))
+}
+
+func readBackupFile(bs BackupStorage, name string) ([]byte, error) {
+	ctx := context.Background()
+	bhs, err := bs.ListBackups(ctx, "dir")
+	if err != nil {
+		return nil, err
+	}
+	rc, err := bhs[0].ReadFile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+	return b
+}
+
+func TestEncryptedBackupRoundTrip(t *testing.T) {
+	provider := registerXORKeyProvider(t)
+	setEncryption(t, EncryptionAESGCM, "xor", "key1")
+
+	files := map[string][]byte{}
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, encryptionChunkSize + 1, 3*encryptionChunkSize + 17} {
+		files[fmt.Sprintf("file-%d", size)] = randomBytes(t, size)
+	}
+
+	mem := &memStorage{}
+	bs := &encryptedStorage{BackupStorage: mem}
+	writeBackup(t, bs, files)
+
+	// the files are encrypted, and their announced size is the actual one
+	for name, data := range files {
+		stored := mem.bh.files[name].Bytes()
+		assert.Equal(t, encryptedSize(int64(len(data))), int64(len(stored)), name)
+		assert.Equal(t, mem.bh.sizes[name], int64(len(stored)), name)
+		if len(data) > 0 {
+			assert.False(t, bytes.Contains(stored, data), name)
+		}
+	}
+
+	// the MANIFEST is readable without the key
+	var manifest struct {
+		BackupName string
+		Encryption *EncryptionInfo
+	}
+	require.NoError(t, json.Unmarshal(mem.bh.files[ManifestFileName].Bytes(), &manifest))
+	require.NotNil(t, manifest.Encryption)
+	assert.Equal(t, EncryptionAESGCM, manifest.Encryption.Algorithm)
+	assert.Equal(t, "xor", manifest.Encryption.KeyProvider)
+	assert.Equal(t, "key1", manifest.Encryption.KeyID)
+	assert.Equal(t, "v1", manifest.Encryption.KeyVersion)
+	assert.Len(t, manifest.Encryption.WrappedKey, dataKeySize)
+
+	// the backup is restored using the key in the MANIFEST, even after the
+	// configured key or encryption changed
+	setEncryption(t, "", "", "")
+	provider.keyIDs = nil
+	for name, data := range files {
+		got, err := readBackupFile(bs, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, data, got, name)
+	}
+	for _, keyID := range provider.keyIDs {
+		assert.Equal(t, "key1", keyID)
+	}
+}
+
+func TestUnencryptedBackup(t *testing.T) {
+	setEncryption(t, "", "", "")
+
+	mem := &memStorage{}
+	bs := &encryptedStorage{BackupStorage: mem}
+	files := map[string][]byte{"file": []byte("some data")}
+	writeBackup(t, bs, files)
+
+	assert.Equal(t, files["file"], mem.bh.files["file"].Bytes())
+	assert.Equal(t, int64(len(files["file"])), mem.bh.sizes["file"])
+	got, err := readBackupFile(bs, "file")
+	require.NoError(t, err)
+	assert.Equal(t, files["file"], got)
+}
+
+func TestEncryptedBackupCorruption(t *testing.T) {
+	registerXORKeyProvider(t)
+	setEncryption(t, EncryptionAESGCM, "xor", "key1")
+
+	data := randomBytes(t, 2*encryptionChunkSize+100)
+	mem := &memStorage{}
+	bs := &encryptedStorage{BackupStorage: mem}
+	writeBackup(t, bs, map[string][]byte{"file": data})
+	stored := mem.bh.files["file"].Bytes()
+
+	tcs := []struct {
+		name   string
+		stored []byte
+	}{{
+		name:   "empty",
+		stored: nil,
+	}, {
+		name:   "truncated nonce",
+		stored: stored[:encryptionNonceSize-1],
+	}, {
+		name:   "truncated after a full chunk",
+		stored: stored[:encryptionNonceSize+encryptionChunkSize+encryptionTagSize],
+	}, {
+		name:   "truncated within a chunk",
+		stored: stored[:len(stored)-1],
+	}, {
+		name: "tampered",
+		stored: func() []byte {
+			b := bytes.Clone(stored)
+			b[encryptionNonceSize+encryptionChunkSize+10] ^= 1
+			return b
+		}(),
+	}, {
+		name:   "chunks swapped",
+		stored: swapChunks(stored),
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			mem.bh.files["file"] = bytes.NewBuffer(tc.stored)
+			_, err := readBackupFile(bs, "file")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func swapChunks(stored []byte) []byte {
+	sealed := encryptionChunkSize + encryptionTagSize
+	b := bytes.Clone(stored)
+	first := b[encryptionNonceSize : encryptionNonceSize+sealed]
+	second := b[encryptionNonceSize+sealed : encryptionNonceSize+2*sealed]
+	tmp := bytes.Clone(first)
+	copy(first, second)
+	copy(second, tmp)
+	return b
+}
+
+func TestValidateEncryption(t *testing.T) {
+	registerXORKeyProvider(t)
+
+	tcs := []struct {
+		encryption, provider, keyID string
+		err                         string
+	}{
+		{},
+		{encryption: EncryptionAESGCM, provider: "xor", keyID: "key1"},
+		{encryption: "rot13", provider: "xor", keyID: "key1", err: `unsupported backup encryption "rot13"`},
+		{encryption: EncryptionAESGCM, provider: "unknown", keyID: "key1", err: `no registered backup encryption key provider "unknown"`},
+		{encryption: EncryptionAESGCM, provider: "xor", err: "backup encryption requires a key id"},
+	}
+	for _, tc := range tcs {
+		t.Run(fmt.Sprintf("%s/%s/%s", tc.encryption, tc.provider, tc.keyID), func(t *testing.T) {
+			setEncryption(t, tc.encryption, tc.provider, tc.keyID)
+			err := validateEncryption()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -131,7 +131,9 @@ type BackupStorage interface {
 // BackupStorageMap contains the registered implementations for BackupStorage
 var BackupStorageMap = make(map[string]BackupStorage)
 
-// GetBackupStorage returns the current BackupStorage implementation.
+// GetBackupStorage returns the current BackupStorage implementation,
+// which encrypts new backups if --backup-storage-encryption is set, and
+// decrypts encrypted ones.
 // Should be called after flags have been initialized.
 // When all operations are done, call BackupStorage.Close() to free resources.
 func GetBackupStorage() (BackupStorage, error) {
@@ -139,5 +141,10 @@ func GetBackupStorage() (BackupStorage, error) {
 	if !ok {
 		return nil, fmt.Errorf("no registered implementation of BackupStorage")
 	}
-	return bs, nil
+	if err := validateEncryption(); err != nil {
+		return nil, err
+	}
+	// The bandwidth limits apply to what is actually transferred, so the
+	// throttling happens below the encryption.
+	return &encryptedStorage{BackupStorage: &throttledStorage{BackupStorage: bs}}, nil
 }
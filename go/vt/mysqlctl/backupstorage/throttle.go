@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/time/rate"
+
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var (
+	// UploadBandwidthLimit is the maximum number of bytes per second
+	// written to the backup storage, across all the files of a backup.
+	// 0 means unlimited. Exported for test purposes.
+	UploadBandwidthLimit int64
+	// DownloadBandwidthLimit is the maximum number of bytes per second read
+	// from the backup storage, across all the files of a backup. 0 means
+	// unlimited. Exported for test purposes.
+	DownloadBandwidthLimit int64
+)
+
+func registerBandwidthFlags(fs *pflag.FlagSet) {
+	fs.Int64Var(&UploadBandwidthLimit, "backup-storage-upload-bandwidth-limit", UploadBandwidthLimit, "Maximum number of bytes per second sent to the backup storage while taking a backup, shared by all the files transferred in parallel. 0 means unlimited.")
+	fs.Int64Var(&DownloadBandwidthLimit, "backup-storage-download-bandwidth-limit", DownloadBandwidthLimit, "Maximum number of bytes per second received from the backup storage while restoring a backup, shared by all the files transferred in parallel. 0 means unlimited.")
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerBandwidthFlags)
+	servenv.OnParseFor("vttablet", registerBandwidthFlags)
+}
+
+// newLimiter returns a limiter allowing bytesPerSecond, or nil if
+// bytesPerSecond is not positive. The burst is one second worth of
+// transfer, so the limit is enforced on a one second window.
+func newLimiter(bytesPerSecond int64) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(bytesPerSecond))
+}
+
+// throttledStorage limits the bandwidth used by the files of the backups
+// it returns. Each backup gets its own limiter, shared by its files.
+type throttledStorage struct {
+	BackupStorage
+}
+
+// ListBackups is part of the BackupStorage interface.
+func (ts *throttledStorage) ListBackups(ctx context.Context, dir string) ([]BackupHandle, error) {
+	handles, err := ts.BackupStorage.ListBackups(ctx, dir)
+	if err != nil || DownloadBandwidthLimit <= 0 {
+		return handles, err
+	}
+	for i, bh := range handles {
+		handles[i] = &throttledHandle{BackupHandle: bh, limiter: newLimiter(DownloadBandwidthLimit)}
+	}
+	return handles, nil
+}
+
+// StartBackup is part of the BackupStorage interface.
+func (ts *throttledStorage) StartBackup(ctx context.Context, dir, name string) (BackupHandle, error) {
+	bh, err := ts.BackupStorage.StartBackup(ctx, dir, name)
+	if err != nil || UploadBandwidthLimit <= 0 {
+		return bh, err
+	}
+	return &throttledHandle{BackupHandle: bh, limiter: newLimiter(UploadBandwidthLimit)}, nil
+}
+
+// WithParams is part of the BackupStorage interface.
+func (ts *throttledStorage) WithParams(params Params) BackupStorage {
+	return &throttledStorage{BackupStorage: ts.BackupStorage.WithParams(params)}
+}
+
+// throttledHandle limits the bandwidth of the files of a backup.
+type throttledHandle struct {
+	BackupHandle
+	limiter *rate.Limiter
+}
+
+// AddFile is part of the BackupHandle interface.
+func (th *throttledHandle) AddFile(ctx context.Context, filename string, filesize int64) (io.WriteCloser, error) {
+	wc, err := th.BackupHandle.AddFile(ctx, filename, filesize)
+	if err != nil {
+		return nil, err
+	}
+	return &throttledWriter{ctx: ctx, wc: wc, limiter: th.limiter}, nil
+}
+
+// ReadFile is part of the BackupHandle interface.
+func (th *throttledHandle) ReadFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	rc, err := th.BackupHandle.ReadFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	return &throttledReader{ctx: ctx, rc: rc, limiter: th.limiter}, nil
+}
+
+type throttledWriter struct {
+	ctx     context.Context
+	wc      io.WriteCloser
+	limiter *rate.Limiter
+}
+
+// Write is part of the io.Writer interface. Large writes are split, as the
+// limiter can't grant more than its burst at once.
+func (tw *throttledWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), tw.limiter.Burst())
+		if err := tw.limiter.WaitN(tw.ctx, n); err != nil {
+			return written, err
+		}
+		n, err := tw.wc.Write(p[:n])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Close is part of the io.Closer interface.
+func (tw *throttledWriter) Close() error {
+	return tw.wc.Close()
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	rc      io.ReadCloser
+	limiter *rate.Limiter
+}
+
+// Read is part of the io.Reader interface. The bytes are accounted for
+// after they are read, so a read is never larger than the burst.
+func (tr *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > tr.limiter.Burst() {
+		p = p[:tr.limiter.Burst()]
+	}
+	n, err := tr.rc.Read(p)
+	if n > 0 {
+		if werr := tr.limiter.WaitN(tr.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// Close is part of the io.Closer interface.
+func (tr *throttledReader) Close() error {
+	return tr.rc.Close()
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backupstorage
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setBandwidthLimits(t *testing.T, upload, download int64) {
+	oldUpload, oldDownload := UploadBandwidthLimit, DownloadBandwidthLimit
+	UploadBandwidthLimit, DownloadBandwidthLimit = upload, download
+	t.Cleanup(func() {
+		UploadBandwidthLimit, DownloadBandwidthLimit = oldUpload, oldDownload
+	})
+}
+
+func TestThrottledStorage(t *testing.T) {
+	// The limiter starts with one second worth of bytes, so transferring
+	// 2.5 seconds worth takes at least 1.5 seconds.
+	const limit = 10000
+	data := randomBytes(t, 5*limit/2)
+	setBandwidthLimits(t, limit, limit)
+	setEncryption(t, "", "", "")
+	ctx := context.Background()
+
+	mem := &memStorage{}
+	bs := &throttledStorage{BackupStorage: mem}
+	bh, err := bs.StartBackup(ctx, "dir", "backup")
+	require.NoError(t, err)
+	wc, err := bh.AddFile(ctx, "file", int64(len(data)))
+	require.NoError(t, err)
+	start := time.Now()
+	// both halves share the limit of the backup
+	_, err = wc.Write(data[:len(data)/2])
+	require.NoError(t, err)
+	wc2, err := bh.AddFile(ctx, "file2", int64(len(data)))
+	require.NoError(t, err)
+	_, err = wc2.Write(data[len(data)/2:])
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 1400*time.Millisecond)
+	require.NoError(t, wc.Close())
+	require.NoError(t, wc2.Close())
+
+	bhs, err := bs.ListBackups(ctx, "dir")
+	require.NoError(t, err)
+	rc, err := bhs[0].ReadFile(ctx, "file")
+	require.NoError(t, err)
+	start = time.Now()
+	got, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, data[:len(data)/2], got)
+	rc, err = bhs[0].ReadFile(ctx, "file2")
+	require.NoError(t, err)
+	got, err = io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, data[len(data)/2:], got)
+	assert.GreaterOrEqual(t, time.Since(start), 1400*time.Millisecond)
+}
+
+func TestThrottledStorageCanceled(t *testing.T) {
+	setBandwidthLimits(t, 1000, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	bs := &throttledStorage{BackupStorage: &memStorage{}}
+	bh, err := bs.StartBackup(ctx, "dir", "backup")
+	require.NoError(t, err)
+	wc, err := bh.AddFile(ctx, "file", FileSizeUnknown)
+	require.NoError(t, err)
+	cancel()
+	_, err = wc.Write(make([]byte, 5000))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestUnthrottledStorage(t *testing.T) {
+	setBandwidthLimits(t, 0, 0)
+	ctx := context.Background()
+
+	bs := &throttledStorage{BackupStorage: &memStorage{}}
+	bh, err := bs.StartBackup(ctx, "dir", "backup")
+	require.NoError(t, err)
+	assert.IsType(t, &memHandle{}, bh)
+	bhs, err := bs.ListBackups(ctx, "dir")
+	require.NoError(t, err)
+	assert.IsType(t, &memHandle{}, bhs[0])
+}
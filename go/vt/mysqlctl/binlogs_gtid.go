@@ -406,3 +406,35 @@ func FindPITRToTimePath(restoreToTime time.Time, manifests [](*BackupManifest))
 	}
 	return shortestPath, nil
 }
+
+// FindBinlogArchivePath evaluates the incremental backups whose binary logs take a stream from fromGTIDSet
+// to a position from which the binary logs of the source pick up, that is a position containing purgedGTIDSet.
+// The binary logs of the returned backups are to be streamed in order. The function returns an error when
+// the archived binary logs do not cover the gap.
+func FindBinlogArchivePath(fromGTIDSet replication.GTIDSet, purgedGTIDSet replication.GTIDSet, manifests [](*BackupManifest)) (path [](*BackupManifest), err error) {
+	sortedManifests := make([](*BackupManifest), 0, len(manifests))
+	for _, m := range manifests {
+		if m != nil && m.Incremental {
+			sortedManifests = append(sortedManifests, m)
+		}
+	}
+	sort.SliceStable(sortedManifests, func(i, j int) bool {
+		return sortedManifests[j].Position.GTIDSet.Union(sortedManifests[i].PurgedPosition.GTIDSet).Contains(sortedManifests[i].Position.GTIDSet)
+	})
+	// Unlike a restore, the stream can go through overlapping backups, as it skips the transactions it
+	// has already seen. Therefore, the first path found is as good as any.
+	baseGTIDSet := fromGTIDSet
+	for _, manifest := range sortedManifests {
+		if baseGTIDSet.Contains(purgedGTIDSet) {
+			break
+		}
+		if IsValidIncrementalBakcup(baseGTIDSet, purgedGTIDSet, manifest) {
+			path = append(path, manifest)
+			baseGTIDSet = baseGTIDSet.Union(manifest.Position.GTIDSet)
+		}
+	}
+	if !baseGTIDSet.Contains(purgedGTIDSet) {
+		return nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "no archived binary logs found that lead from GTID %v to purged GTID %v", fromGTIDSet, purgedGTIDSet)
+	}
+	return path, nil
+}
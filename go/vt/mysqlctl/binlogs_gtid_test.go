@@ -918,3 +918,82 @@ func TestFindPITRToTimePath(t *testing.T) {
 		}
 	})
 }
+
+func TestFindBinlogArchivePath(t *testing.T) {
+	generatePosition := func(posRange string) replication.Position {
+		return replication.MustParsePosition(replication.Mysql56FlavorID, fmt.Sprintf("16b1039f-22b6-11ed-b765-0a43f95f28a3:%s", posRange))
+	}
+	incrementalManifest := func(backupPos string, backupFromPos string) *BackupManifest {
+		return &BackupManifest{
+			Position:     generatePosition(backupPos),
+			FromPosition: generatePosition(backupFromPos),
+			Incremental:  true,
+		}
+	}
+	manifests := []*BackupManifest{
+		{Position: generatePosition("1-50")},
+		incrementalManifest("1-60", "1-50"),
+		incrementalManifest("1-34", "1-5"),
+		incrementalManifest("1-52", "1-35"),
+		incrementalManifest("1-38", "1-34"),
+		incrementalManifest("1-92", "1-79"),
+		incrementalManifest("1-70", "1-60"),
+	}
+	tt := []struct {
+		name        string
+		fromGTID    string
+		purgedGTID  string
+		expectPath  []*BackupManifest
+		expectError string
+	}{
+		{
+			name:       "from 1-36 to 1-60",
+			fromGTID:   "1-36",
+			purgedGTID: "1-60",
+			expectPath: []*BackupManifest{
+				incrementalManifest("1-38", "1-34"),
+				incrementalManifest("1-52", "1-35"),
+				incrementalManifest("1-60", "1-50"),
+			},
+		},
+		{
+			name:       "from 1-55 to 1-65",
+			fromGTID:   "1-55",
+			purgedGTID: "1-65",
+			expectPath: []*BackupManifest{
+				incrementalManifest("1-60", "1-50"),
+				incrementalManifest("1-70", "1-60"),
+			},
+		},
+		{
+			name:       "nothing purged since",
+			fromGTID:   "1-55",
+			purgedGTID: "1-40",
+		},
+		{
+			name:        "gap in the archive",
+			fromGTID:    "1-55",
+			purgedGTID:  "1-80",
+			expectError: "no archived binary logs found",
+		},
+		{
+			name:        "purged before the archive",
+			fromGTID:    "1-2",
+			purgedGTID:  "1-10",
+			expectError: "no archived binary logs found",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			from := generatePosition(tc.fromGTID)
+			purged := generatePosition(tc.purgedGTID)
+			path, err := FindBinlogArchivePath(from.GTIDSet, purged.GTIDSet, manifests)
+			if tc.expectError != "" {
+				assert.ErrorContains(t, err, tc.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectPath, path)
+		})
+	}
+}
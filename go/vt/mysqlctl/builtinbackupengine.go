@@ -115,14 +115,19 @@ type builtinBackupManifest struct {
 	//
 	// When taking a backup with --compression-engine=external,
 	// ExternalDecompressor will be set to the value of
-	// --manifest-external-decompressor, if set, or else left as an empty
-	// string.
+	// --manifest-external-decompressor, if set, or else to the decompression
+	// command of the compression binary if it is zstd, pzstd or lz4, or else
+	// left as an empty string.
 	//
 	// When restoring from a backup with CompressionEngine "external",
 	// --external-decompressor will be consulted first and, if that is not set,
-	// ExternalDecompressor will be used. If neither are set, the restore will
-	// abort.
+	// ExternalDecompressor will be used, and then the decompression command of
+	// ExternalCompressor. If none are set, the restore will abort.
 	ExternalDecompressor string
+
+	// ExternalCompressor is the value of --external-compressor the backup was
+	// taken with, if any.
+	ExternalCompressor string `json:",omitempty"`
 }
 
 // FileEntry is one file to backup
@@ -686,13 +691,15 @@ func (be *BuiltinBackupEngine) backupFiles(
 			MySQLVersion:       mysqlVersion,
 			UpgradeSafe:        params.UpgradeSafe,
 			IncrementalDetails: incrDetails,
+			Encryption:         backupstorage.HandleEncryption(bh),
 		},
 
 		// Builtin-specific fields
 		FileEntries:          fes,
 		SkipCompress:         !backupStorageCompress,
 		CompressionEngine:    CompressionEngineName,
-		ExternalDecompressor: ManifestExternalDecompressorCmd,
+		ExternalDecompressor: manifestExternalDecompressor(),
+		ExternalCompressor:   ExternalCompressorCmd,
 	}
 	data, err := json.MarshalIndent(bm, "", "  ")
 	if err != nil {
@@ -992,6 +999,44 @@ func (be *BuiltinBackupEngine) ExecuteRestore(ctx context.Context, params Restor
 	return &bm.BackupManifest, nil
 }
 
+// RestoreArchivedBinlogs copies the binary logs archived by the incremental
+// backup bh to a new temporary directory, for them to be streamed rather than
+// applied. It returns the directory, which the caller removes when done, and
+// the paths of the binary logs in order.
+func RestoreArchivedBinlogs(ctx context.Context, bh backupstorage.BackupHandle, logger logutil.Logger) (dir string, binlogFiles []string, err error) {
+	var bm builtinBackupManifest
+	if err := getBackupManifestInto(ctx, bh, &bm); err != nil {
+		return "", nil, err
+	}
+	if !bm.Incremental || bm.BackupMethod != builtinBackupEngineName {
+		return "", nil, vterrors.Errorf(vtrpc.Code_FAILED_PRECONDITION, "backup %v is not an incremental backup", bh.Name())
+	}
+
+	params := RestoreParams{
+		// The binary logs are restored right into the temporary directory.
+		Cnf:         &Mycnf{BinLogPath: "binlog"},
+		Logger:      logger,
+		Concurrency: 1,
+		Stats:       stats.NoStats(),
+	}
+	var be BuiltinBackupEngine
+	dir, err = be.restoreFiles(ctx, params, bh, bm)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, vterrors.Wrapf(err, "failed to restore the binary logs of backup %v", bh.Name())
+	}
+	for _, fe := range bm.FileEntries {
+		fe.ParentPath = dir
+		binlogFile, err := fe.fullPath(params.Cnf)
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", nil, err
+		}
+		binlogFiles = append(binlogFiles, binlogFile)
+	}
+	return dir, binlogFiles, nil
+}
+
 // restoreFiles will copy all the files from the BackupStorage to the
 // right place.
 func (be *BuiltinBackupEngine) restoreFiles(ctx context.Context, params RestoreParams, bh backupstorage.BackupHandle, bm builtinBackupManifest) (createdDir string, err error) {
@@ -1114,10 +1159,7 @@ func (be *BuiltinBackupEngine) restoreFile(ctx context.Context, params RestorePa
 			// for backward compatibility
 			deCompressionEngine = PgzipCompressor
 		}
-		externalDecompressorCmd := ExternalDecompressorCmd
-		if externalDecompressorCmd == "" && bm.ExternalDecompressor != "" {
-			externalDecompressorCmd = bm.ExternalDecompressor
-		}
+		externalDecompressorCmd := resolveExternalDecompressor(bm.ExternalDecompressor, bm.ExternalCompressor)
 		if externalDecompressorCmd != "" {
 			if deCompressionEngine == ExternalCompressor {
 				deCompressionEngine = externalDecompressorCmd
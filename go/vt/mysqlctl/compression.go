@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"path/filepath"
 	"sync"
 
 	"github.com/google/shlex"
@@ -63,8 +64,23 @@ var (
 		".lz4": {Lz4Compressor},
 		".zst": {ZstdCompressor},
 	}
+
+	// externalCompressorPresets are the external compression binaries whose
+	// extension and decompressor don't need to be given with flags.
+	externalCompressorPresets = map[string]externalCompressorPreset{
+		"lz4":   {extension: ".lz4", decompressor: "lz4 -d -c"},
+		"pzstd": {extension: ".zst", decompressor: "pzstd -d -c"},
+		"zstd":  {extension: ".zst", decompressor: "zstd -d -c"},
+	}
 )
 
+// externalCompressorPreset is the extension of the files an external
+// compression binary writes, and the command that decompresses them.
+type externalCompressorPreset struct {
+	extension    string
+	decompressor string
+}
+
 func init() {
 	for _, cmd := range []string{"vtbackup", "vtcombo", "vttablet", "vttestserver"} {
 		servenv.OnParseFor(cmd, registerBackupCompressionFlags)
@@ -75,9 +91,9 @@ func registerBackupCompressionFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&compressionLevel, "compression-level", compressionLevel, "what level to pass to the compressor.")
 	fs.StringVar(&CompressionEngineName, "compression-engine-name", CompressionEngineName, "compressor engine used for compression.")
 	fs.StringVar(&ExternalCompressorCmd, "external-compressor", ExternalCompressorCmd, "command with arguments to use when compressing a backup.")
-	fs.StringVar(&ExternalCompressorExt, "external-compressor-extension", ExternalCompressorExt, "extension to use when using an external compressor.")
+	fs.StringVar(&ExternalCompressorExt, "external-compressor-extension", ExternalCompressorExt, "extension to use when using an external compressor. Defaults to .zst for zstd and pzstd, and to .lz4 for lz4.")
 	fs.StringVar(&ExternalDecompressorCmd, "external-decompressor", ExternalDecompressorCmd, "command with arguments to use when decompressing a backup.")
-	fs.StringVar(&ManifestExternalDecompressorCmd, "manifest-external-decompressor", ManifestExternalDecompressorCmd, "command with arguments to store in the backup manifest when compressing a backup with an external compression engine.")
+	fs.StringVar(&ManifestExternalDecompressorCmd, "manifest-external-decompressor", ManifestExternalDecompressorCmd, "command with arguments to store in the backup manifest when compressing a backup with an external compression engine. Defaults to the decompression command of zstd, pzstd and lz4.")
 }
 
 func getExtensionFromEngine(engine string) (string, error) {
@@ -91,6 +107,60 @@ func getExtensionFromEngine(engine string) (string, error) {
 	return "", fmt.Errorf("%w %q", errUnsupportedCompressionEngine, engine)
 }
 
+// getExternalCompressorPreset returns the preset of the binary the external
+// compression command runs, if it is a known one.
+func getExternalCompressorPreset(cmdStr string) (externalCompressorPreset, bool) {
+	cmdArgs, err := shlex.Split(cmdStr)
+	if err != nil || len(cmdArgs) == 0 {
+		return externalCompressorPreset{}, false
+	}
+	preset, ok := externalCompressorPresets[filepath.Base(cmdArgs[0])]
+	return preset, ok
+}
+
+// externalCompressorExtension returns the extension of the files written by
+// the external compressor: --external-compressor-extension if it is set, or
+// else the extension of the compression binary.
+func externalCompressorExtension() string {
+	if ExternalCompressorExt != "" {
+		return ExternalCompressorExt
+	}
+	if preset, ok := getExternalCompressorPreset(ExternalCompressorCmd); ok {
+		return preset.extension
+	}
+	return ""
+}
+
+// manifestExternalDecompressor returns the decompression command stored in
+// the manifest of a backup: --manifest-external-decompressor if it is set, or
+// else the decompression command of the external compression binary.
+func manifestExternalDecompressor() string {
+	if ManifestExternalDecompressorCmd != "" || ExternalCompressorCmd == "" {
+		return ManifestExternalDecompressorCmd
+	}
+	if preset, ok := getExternalCompressorPreset(ExternalCompressorCmd); ok {
+		return preset.decompressor
+	}
+	return ""
+}
+
+// resolveExternalDecompressor returns the command that decompresses a backup
+// taken with an external compressor: --external-decompressor if it is set,
+// the decompressor stored in the manifest, or else the decompression command
+// of the compression binary stored in the manifest.
+func resolveExternalDecompressor(manifestDecompressor, manifestCompressor string) string {
+	if ExternalDecompressorCmd != "" {
+		return ExternalDecompressorCmd
+	}
+	if manifestDecompressor != "" {
+		return manifestDecompressor
+	}
+	if preset, ok := getExternalCompressorPreset(manifestCompressor); ok {
+		return preset.decompressor
+	}
+	return ""
+}
+
 // Validates if the external decompressor exists and return its path.
 func validateExternalCmd(cmd string) (string, error) {
 	if cmd == "" {
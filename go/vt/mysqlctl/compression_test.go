@@ -174,6 +174,44 @@ func TestExternalCompressors(t *testing.T) {
 	}
 }
 
+func TestExternalCompressorPresets(t *testing.T) {
+	defer func(compressor, ext, decompressor, manifestDecompressor string) {
+		ExternalCompressorCmd = compressor
+		ExternalCompressorExt = ext
+		ExternalDecompressorCmd = decompressor
+		ManifestExternalDecompressorCmd = manifestDecompressor
+	}(ExternalCompressorCmd, ExternalCompressorExt, ExternalDecompressorCmd, ManifestExternalDecompressorCmd)
+
+	tests := []struct {
+		compressor, ext, manifestDecompressor string
+		wantExt, wantManifestDecompressor     string
+	}{
+		{"zstd -T0 -3", "", "", ".zst", "zstd -d -c"},
+		{"/usr/bin/pzstd -p 8", "", "", ".zst", "pzstd -d -c"},
+		{"lz4 -c", "", "", ".lz4", "lz4 -d -c"},
+		{"zstd", ".zstd", "unzstd -c", ".zstd", "unzstd -c"},
+		{"gzip -c", "", "", "", ""},
+		{"", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.compressor, func(t *testing.T) {
+			ExternalCompressorCmd = tt.compressor
+			ExternalCompressorExt = tt.ext
+			ManifestExternalDecompressorCmd = tt.manifestDecompressor
+			require.Equal(t, tt.wantExt, externalCompressorExtension())
+			require.Equal(t, tt.wantManifestDecompressor, manifestExternalDecompressor())
+		})
+	}
+
+	// The decompressor is resolved from the flag, then from the manifest.
+	ExternalDecompressorCmd = ""
+	require.Equal(t, "zstd -d -c", resolveExternalDecompressor("", "zstd -T0"))
+	require.Equal(t, "unzstd -c", resolveExternalDecompressor("unzstd -c", "zstd -T0"))
+	require.Empty(t, resolveExternalDecompressor("", "gzip -c"))
+	ExternalDecompressorCmd = "pzstd -d -c"
+	require.Equal(t, "pzstd -d -c", resolveExternalDecompressor("unzstd -c", "zstd -T0"))
+}
+
 func TestValidateExternalCmd(t *testing.T) {
 	tests := []struct {
 		cmdName string
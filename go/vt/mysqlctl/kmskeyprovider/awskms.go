@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kmskeyprovider implements the backupstorage.KeyProvider interface
+// for the key management services of AWS and GCP, and for the transit
+// secrets engine of HashiCorp Vault.
+//
+// The master keys never leave the key management service: only the data
+// keys of the backups are sent to it, to be wrapped or unwrapped. Rotating a
+// master key is handled by the service, which keeps the previous versions
+// around to unwrap the data keys of older backups.
+package kmskeyprovider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+// awsRegion is the region of the AWS KMS keys.
+var awsRegion string
+
+func registerAWSFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&awsRegion, "backup-storage-encryption-aws-region", awsRegion, "AWS region of the KMS key, if not part of the key ARN. Defaults to the region of the AWS configuration.")
+}
+
+// AWSKMS implements backupstorage.KeyProvider with AWS KMS.
+//
+// AWS access credentials are configured via standard AWS means, like for
+// the s3 backup storage.
+type AWSKMS struct {
+	mu      sync.Mutex
+	_client kmsiface.KMSAPI
+}
+
+var _ backupstorage.KeyProvider = (*AWSKMS)(nil)
+
+// WrapKey is part of the backupstorage.KeyProvider interface. keyID is a key
+// id, key ARN, alias name or alias ARN. The returned version is the ARN of
+// the key that wrapped dataKey, which is useful when keyID is an alias.
+func (a *AWSKMS) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, string, error) {
+	client, err := a.client()
+	if err != nil {
+		return nil, "", err
+	}
+	out, err := client.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return out.CiphertextBlob, aws.StringValue(out.KeyId), nil
+}
+
+// UnwrapKey is part of the backupstorage.KeyProvider interface. The key is
+// not passed to KMS, which finds it in the wrapped key, so backups can still
+// be restored after an alias has been moved to a new key.
+func (a *AWSKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	client, err := a.client()
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.DecryptWithContext(ctx, &kms.DecryptInput{
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+func (a *AWSKMS) client() (kmsiface.KMSAPI, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a._client == nil {
+		sess, err := session.NewSession()
+		if err != nil {
+			return nil, err
+		}
+		config := &aws.Config{}
+		if awsRegion != "" {
+			config.Region = aws.String(awsRegion)
+		}
+		a._client = kms.New(sess, config)
+	}
+	return a._client, nil
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerAWSFlags)
+	servenv.OnParseFor("vttablet", registerAWSFlags)
+
+	backupstorage.KeyProviderMap["aws-kms"] = &AWSKMS{}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kmskeyprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/oauth2/google"
+
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+const (
+	gcpKMSEndpoint = "https://cloudkms.googleapis.com"
+	gcpKMSScope    = "https://www.googleapis.com/auth/cloudkms"
+)
+
+// GCPKMS implements backupstorage.KeyProvider with Google Cloud KMS. The key
+// ids are CryptoKey resource names, i.e.
+// projects/*/locations/*/keyRings/*/cryptoKeys/*.
+//
+// Credentials are the Application Default Credentials, like for the gcs
+// backup storage.
+type GCPKMS struct {
+	// endpoint is the Cloud KMS API endpoint. Overridden in tests.
+	endpoint string
+
+	mu      sync.Mutex
+	_client *http.Client
+}
+
+var _ backupstorage.KeyProvider = (*GCPKMS)(nil)
+
+type gcpEncryptRequest struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+type gcpEncryptResponse struct {
+	// Name is the CryptoKeyVersion used to encrypt.
+	Name       string `json:"name"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type gcpDecryptRequest struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type gcpDecryptResponse struct {
+	Plaintext []byte `json:"plaintext"`
+}
+
+// WrapKey is part of the backupstorage.KeyProvider interface. The returned
+// version is the CryptoKeyVersion resource name.
+func (g *GCPKMS) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, string, error) {
+	var resp gcpEncryptResponse
+	if err := g.call(ctx, keyID+":encrypt", &gcpEncryptRequest{Plaintext: dataKey}, &resp); err != nil {
+		return nil, "", err
+	}
+	return resp.Ciphertext, resp.Name, nil
+}
+
+// UnwrapKey is part of the backupstorage.KeyProvider interface. Cloud KMS
+// finds the CryptoKeyVersion in the wrapped key, so backups can still be
+// restored after the key has been rotated.
+func (g *GCPKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	var resp gcpDecryptResponse
+	if err := g.call(ctx, keyID+":decrypt", &gcpDecryptRequest{Ciphertext: wrapped}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+// call POSTs req to the given method of the Cloud KMS REST API, and decodes
+// the response into resp.
+func (g *GCPKMS) call(ctx context.Context, method string, req, resp any) error {
+	client, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	endpoint := g.endpoint
+	if endpoint == "" {
+		endpoint = gcpKMSEndpoint
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/v1/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	data, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cloud KMS call %v returned %v: %s", method, httpResp.Status, data)
+	}
+	return json.Unmarshal(data, resp)
+}
+
+// client returns the authenticated HTTP client.
+// If there isn't one yet, it tries to create one.
+func (g *GCPKMS) client(ctx context.Context) (*http.Client, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g._client == nil {
+		// The context needs to be valid for longer than just
+		// the creation context, so we create a new one, but
+		// keep the span information.
+		ctx = trace.CopySpan(context.Background(), ctx)
+		client, err := google.DefaultClient(ctx, gcpKMSScope)
+		if err != nil {
+			return nil, err
+		}
+		g._client = client
+	}
+	return g._client, nil
+}
+
+func init() {
+	backupstorage.KeyProviderMap["gcp-kms"] = &GCPKMS{}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kmskeyprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+func TestRegistered(t *testing.T) {
+	for _, name := range []string{"aws-kms", "gcp-kms", "vault"} {
+		assert.Contains(t, backupstorage.KeyProviderMap, name)
+	}
+}
+
+type fakeKMS struct {
+	kmsiface.KMSAPI
+	decryptInput *kms.DecryptInput
+}
+
+func (f *fakeKMS) EncryptWithContext(ctx aws.Context, in *kms.EncryptInput, opts ...request.Option) (*kms.EncryptOutput, error) {
+	return &kms.EncryptOutput{
+		CiphertextBlob: append([]byte("wrapped:"), in.Plaintext...),
+		KeyId:          aws.String("arn:aws:kms:us-east-1:123456789012:key/" + aws.StringValue(in.KeyId)),
+	}, nil
+}
+
+func (f *fakeKMS) DecryptWithContext(ctx aws.Context, in *kms.DecryptInput, opts ...request.Option) (*kms.DecryptOutput, error) {
+	f.decryptInput = in
+	return &kms.DecryptOutput{
+		Plaintext: in.CiphertextBlob[len("wrapped:"):],
+	}, nil
+}
+
+func TestAWSKMS(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeKMS{}
+	a := &AWSKMS{_client: fake}
+
+	wrapped, version, err := a.WrapKey(ctx, "key1", []byte("data key"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("wrapped:data key"), wrapped)
+	assert.Equal(t, "arn:aws:kms:us-east-1:123456789012:key/key1", version)
+
+	key, err := a.UnwrapKey(ctx, "key1", wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data key"), key)
+	assert.Nil(t, fake.decryptInput.KeyId)
+}
+
+func TestGCPKMS(t *testing.T) {
+	ctx := context.Background()
+	keyName := "projects/p/locations/global/keyRings/r/cryptoKeys/k"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string][]byte
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/" + keyName + ":encrypt":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"name":       keyName + "/cryptoKeyVersions/3",
+				"ciphertext": append([]byte("wrapped:"), req["plaintext"]...),
+			})
+		case "/v1/" + keyName + ":decrypt":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"plaintext": req["ciphertext"][len("wrapped:"):],
+			})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	g := &GCPKMS{endpoint: server.URL, _client: server.Client()}
+
+	wrapped, version, err := g.WrapKey(ctx, keyName, []byte("data key"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("wrapped:data key"), wrapped)
+	assert.Equal(t, keyName+"/cryptoKeyVersions/3", version)
+
+	key, err := g.UnwrapKey(ctx, keyName, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data key"), key)
+
+	_, _, err = g.WrapKey(ctx, "unknown", []byte("data key"))
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestVault(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		var req map[string]string
+		if r.Method == http.MethodPost {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		var data map[string]any
+		switch r.URL.Path {
+		case "/v1/auth/token/lookup-self":
+			data = map[string]any{"id": "token"}
+		case "/v1/transit/encrypt/backups":
+			plaintext, _ := base64.StdEncoding.DecodeString(req["plaintext"])
+			data = map[string]any{"ciphertext": "vault:v2:" + string(plaintext)}
+		case "/v1/transit/decrypt/backups":
+			data = map[string]any{"plaintext": []byte(req["ciphertext"][len("vault:v2:"):])}
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token\n"), 0600))
+	oldAddr, oldTokenFile := vaultAddr, vaultTokenFile
+	vaultAddr, vaultTokenFile = server.URL, tokenFile
+	defer func() {
+		vaultAddr, vaultTokenFile = oldAddr, oldTokenFile
+	}()
+	t.Setenv("VAULT_ADDR", "")
+	t.Setenv("VAULT_TOKEN", "")
+	v := &Vault{}
+
+	wrapped, version, err := v.WrapKey(ctx, "backups", []byte("data key"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("vault:v2:data key"), wrapped)
+	assert.Equal(t, "v2", version)
+
+	key, err := v.UnwrapKey(ctx, "backups", wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data key"), key)
+
+	_, _, err = v.WrapKey(ctx, "unknown", []byte("data key"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kmskeyprovider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	vaultapi "github.com/aquarapid/vaultlib"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+var (
+	vaultAddr             string
+	vaultTimeout          = 10 * time.Second
+	vaultCACert           string
+	vaultTokenFile        string
+	vaultRoleID           string
+	vaultRoleSecretIDFile string
+	vaultRoleMountPoint   = "approle"
+	vaultTransitMount     = "transit"
+)
+
+func registerVaultFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&vaultAddr, "backup-storage-encryption-vault-addr", vaultAddr, "URL to Vault server")
+	fs.DurationVar(&vaultTimeout, "backup-storage-encryption-vault-timeout", vaultTimeout, "Timeout for vault API operations")
+	fs.StringVar(&vaultCACert, "backup-storage-encryption-vault-tls-ca", vaultCACert, "Path to CA PEM for validating Vault server certificate")
+	fs.StringVar(&vaultTokenFile, "backup-storage-encryption-vault-tokenfile", vaultTokenFile, "Path to file containing Vault auth token; token can also be passed using VAULT_TOKEN environment variable")
+	fs.StringVar(&vaultRoleID, "backup-storage-encryption-vault-roleid", vaultRoleID, "Vault AppRole id; can also be passed using VAULT_ROLEID environment variable")
+	fs.StringVar(&vaultRoleSecretIDFile, "backup-storage-encryption-vault-role-secretidfile", vaultRoleSecretIDFile, "Path to file containing Vault AppRole secret_id; can also be passed using VAULT_SECRETID environment variable")
+	fs.StringVar(&vaultRoleMountPoint, "backup-storage-encryption-vault-role-mountpoint", vaultRoleMountPoint, "Vault AppRole mountpoint; can also be passed using VAULT_MOUNTPOINT environment variable")
+	fs.StringVar(&vaultTransitMount, "backup-storage-encryption-vault-transit-mountpoint", vaultTransitMount, "Mountpoint of the Vault transit secrets engine holding the backup encryption keys")
+}
+
+// Vault implements backupstorage.KeyProvider with the transit secrets engine
+// of HashiCorp Vault. The key ids are names of transit keys.
+type Vault struct {
+	mu          sync.Mutex
+	vaultClient *vaultapi.Client
+}
+
+var _ backupstorage.KeyProvider = (*Vault)(nil)
+
+type vaultTransitResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  []byte `json:"plaintext"`
+	} `json:"data"`
+}
+
+// WrapKey is part of the backupstorage.KeyProvider interface. The returned
+// version is the version of the transit key, e.g. "v2".
+func (v *Vault) WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, string, error) {
+	resp, err := v.transit("encrypt", keyID, map[string]any{"plaintext": dataKey})
+	if err != nil {
+		return nil, "", err
+	}
+	// The ciphertext is formatted as vault:<version>:<base64 ciphertext>.
+	parts := strings.SplitN(resp.Data.Ciphertext, ":", 3)
+	if len(parts) != 3 {
+		return nil, "", errors.New("unexpected Vault transit ciphertext format")
+	}
+	return []byte(resp.Data.Ciphertext), parts[1], nil
+}
+
+// UnwrapKey is part of the backupstorage.KeyProvider interface. Vault keeps
+// the versions of rotated keys, unless their min_decryption_version says
+// otherwise, so backups can still be restored after a rotation.
+func (v *Vault) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	resp, err := v.transit("decrypt", keyID, map[string]any{"ciphertext": string(wrapped)})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data.Plaintext, nil
+}
+
+func (v *Vault) transit(op, keyID string, payload any) (*vaultTransitResponse, error) {
+	client, err := v.client()
+	if err != nil {
+		return nil, err
+	}
+	data, err := client.RawRequest("POST", "/v1/"+vaultTransitMount+"/"+op+"/"+keyID, payload)
+	if err != nil {
+		return nil, err
+	}
+	var resp vaultTransitResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (v *Vault) client() (*vaultapi.Client, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.vaultClient != nil {
+		return v.vaultClient, nil
+	}
+
+	config := vaultapi.NewConfig()
+
+	// NewConfig defaults the address to a local server, so it is
+	// only kept if it comes from the environment.
+	if os.Getenv("VAULT_ADDR") == "" {
+		if vaultAddr == "" {
+			return nil, errors.New("no Vault server specified")
+		}
+		config.Address = vaultAddr
+	}
+
+	// All these can be overridden by environment
+	//   so we need to check if they have been set by NewConfig
+	if config.Timeout == (0 * time.Second) {
+		config.Timeout = vaultTimeout
+	}
+	if config.CACert == "" {
+		config.CACert = vaultCACert
+	}
+	if config.Token == "" && vaultTokenFile != "" {
+		token, err := readFromFile(vaultTokenFile)
+		if err != nil {
+			return nil, errors.New("no Vault token in provided filename")
+		}
+		config.Token = token
+	}
+	if config.AppRoleCredentials.RoleID == "" {
+		config.AppRoleCredentials.RoleID = vaultRoleID
+	}
+	if config.AppRoleCredentials.SecretID == "" && vaultRoleSecretIDFile != "" {
+		secretID, err := readFromFile(vaultRoleSecretIDFile)
+		if err != nil {
+			return nil, errors.New("no Vault secret_id in provided filename")
+		}
+		config.AppRoleCredentials.SecretID = secretID
+	}
+	if config.AppRoleCredentials.MountPoint == "" {
+		config.AppRoleCredentials.MountPoint = vaultRoleMountPoint
+	}
+
+	if config.CACert != "" {
+		// If we provide a CA, ensure we actually use it
+		config.InsecureSSL = false
+	}
+
+	client, err := vaultapi.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	v.vaultClient = client
+	return client, nil
+}
+
+func readFromFile(filePath string) (string, error) {
+	fileBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(fileBytes)), nil
+}
+
+func init() {
+	servenv.OnParseFor("vtbackup", registerVaultFlags)
+	servenv.OnParseFor("vttablet", registerVaultFlags)
+
+	backupstorage.KeyProviderMap["vault"] = &Vault{}
+}
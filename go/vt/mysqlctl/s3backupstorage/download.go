@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3backupstorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"vitess.io/vitess/go/vt/log"
+	stats "vitess.io/vitess/go/vt/mysqlctl/backupstats"
+)
+
+// readFileParallel downloads an object in byte ranges of downloadPartSize,
+// downloadConcurrency of them at a time, and returns them in order. A byte
+// range that fails, including while its body is being read, is retried on
+// its own.
+func (bh *S3BackupHandle) readFileParallel(ctx context.Context, object *string) (io.ReadCloser, error) {
+	head, err := bh.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:               &bucket,
+		Key:                  object,
+		SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
+	})
+	if err != nil {
+		return nil, err
+	}
+	size := aws.Int64Value(head.ContentLength)
+
+	ctx, cancel := context.WithCancel(ctx)
+	pr := &parallelReader{
+		cancel: cancel,
+		// the buffer bounds the number of parts being downloaded, or
+		// downloaded but not read yet.
+		parts: make(chan chan partResult, downloadConcurrency),
+	}
+	partSize, retries := max(downloadPartSize, 1), downloadPartRetries
+	go func() {
+		defer close(pr.parts)
+		for first := int64(0); first < size; first += partSize {
+			last := min(first+partSize, size) - 1
+			result := make(chan partResult, 1)
+			select {
+			case pr.parts <- result:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				data, err := bh.getRange(ctx, object, first, last, retries)
+				result <- partResult{data: data, err: err}
+			}()
+		}
+	}()
+	return pr, nil
+}
+
+// getRange downloads the bytes first to last, both included, of an object,
+// retrying up to retries times.
+func (bh *S3BackupHandle) getRange(ctx context.Context, object *string, first, last int64, retries int) ([]byte, error) {
+	sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			log.Warningf("Retrying download of bytes %v-%v of %v after error: %v", first, last, *object, err)
+		}
+		var data []byte
+		data, err = bh.tryGetRange(ctx, object, first, last, sendStats)
+		if err == nil {
+			return data, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, err
+}
+
+func (bh *S3BackupHandle) tryGetRange(ctx context.Context, object *string, first, last int64, sendStats stats.Stats) ([]byte, error) {
+	out, err := bh.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket:               &bucket,
+		Key:                  object,
+		Range:                aws.String(fmt.Sprintf("bytes=%d-%d", first, last)),
+		SSECustomerAlgorithm: bh.bs.s3SSE.customerAlg,
+		SSECustomerKey:       bh.bs.s3SSE.customerKey,
+		SSECustomerKeyMD5:    bh.bs.s3SSE.customerMd5,
+	}, func(r *request.Request) {
+		r.Handlers.CompleteAttempt.PushBack(func(r *request.Request) {
+			sendStats.TimedIncrement(time.Since(r.AttemptTime))
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != last-first+1 {
+		return nil, fmt.Errorf("expected %v bytes, got %v", last-first+1, len(data))
+	}
+	return data, nil
+}
+
+type partResult struct {
+	data []byte
+	err  error
+}
+
+// parallelReader returns the parts of a parallel download in order.
+type parallelReader struct {
+	cancel context.CancelFunc
+	parts  chan chan partResult
+	data   []byte
+	err    error
+}
+
+// Read is part of the io.Reader interface.
+func (pr *parallelReader) Read(p []byte) (int, error) {
+	for len(pr.data) == 0 {
+		if pr.err != nil {
+			return 0, pr.err
+		}
+		result, ok := <-pr.parts
+		if !ok {
+			pr.err = io.EOF
+			continue
+		}
+		part := <-result
+		pr.data, pr.err = part.data, part.err
+	}
+	n := copy(p, pr.data)
+	pr.data = pr.data[n:]
+	return n, nil
+}
+
+// Close is part of the io.Closer interface. It stops the download.
+func (pr *parallelReader) Close() error {
+	pr.cancel()
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package s3backupstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+)
+
+// s3FakeRangeClient serves a single object, failing the first
+// failuresPerRange attempts at each byte range.
+type s3FakeRangeClient struct {
+	s3iface.S3API
+	object           []byte
+	failuresPerRange int
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func (c *s3FakeRangeClient) HeadObjectWithContext(ctx aws.Context, in *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	return &s3.HeadObjectOutput{ContentLength: aws.Int64(int64(len(c.object)))}, nil
+}
+
+func (c *s3FakeRangeClient) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	var first, last int
+	if _, err := fmt.Sscanf(aws.StringValue(in.Range), "bytes=%d-%d", &first, &last); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.attempts[*in.Range]++
+	attempt := c.attempts[*in.Range]
+	c.mu.Unlock()
+
+	body := c.object[first : last+1]
+	if attempt <= c.failuresPerRange {
+		if attempt%2 == 1 {
+			return nil, errors.New("connection reset")
+		}
+		// a body cut short
+		body = body[:len(body)/2]
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(body))}, nil
+}
+
+func setDownloadFlags(t *testing.T, concurrency int, partSize int64, retries int) {
+	oldConcurrency, oldPartSize, oldRetries := downloadConcurrency, downloadPartSize, downloadPartRetries
+	downloadConcurrency, downloadPartSize, downloadPartRetries = concurrency, partSize, retries
+	t.Cleanup(func() {
+		downloadConcurrency, downloadPartSize, downloadPartRetries = oldConcurrency, oldPartSize, oldRetries
+	})
+}
+
+func TestReadFileParallel(t *testing.T) {
+	object := make([]byte, 1000)
+	_, err := rand.Read(object)
+	require.NoError(t, err)
+
+	tcs := []struct {
+		name             string
+		size             int
+		partSize         int64
+		failuresPerRange int
+		retries          int
+		wantErr          bool
+	}{
+		{name: "empty", size: 0, partSize: 100},
+		{name: "single part", size: 50, partSize: 100},
+		{name: "exact parts", size: 1000, partSize: 100},
+		{name: "partial last part", size: 999, partSize: 100},
+		{name: "retried parts", size: 1000, partSize: 64, failuresPerRange: 2, retries: 2},
+		{name: "too many failures", size: 1000, partSize: 64, failuresPerRange: 3, retries: 2, wantErr: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			setDownloadFlags(t, 4, tc.partSize, tc.retries)
+			bh := &S3BackupHandle{
+				client: &s3FakeRangeClient{
+					object:           object[:tc.size],
+					failuresPerRange: tc.failuresPerRange,
+					attempts:         map[string]int{},
+				},
+				bs: &S3BackupStorage{
+					params: backupstorage.NoParams(),
+				},
+				readOnly: true,
+			}
+
+			rc, err := bh.ReadFile(context.Background(), "somefile")
+			require.NoError(t, err)
+			defer rc.Close()
+			data, err := io.ReadAll(rc)
+			if tc.wantErr {
+				assert.ErrorContains(t, err, "connection reset")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, object[:tc.size], data)
+		})
+	}
+}
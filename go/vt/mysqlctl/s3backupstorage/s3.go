@@ -81,6 +81,21 @@ var (
 	// sse is the server-side encryption algorithm used when storing this object in S3
 	sse string
 
+	// uploadPartSize is the minimum size of the parts of multipart uploads
+	uploadPartSize int64
+
+	// uploadConcurrency is the number of parts of a file uploaded in parallel
+	uploadConcurrency = s3manager.DefaultUploadConcurrency
+
+	// downloadPartSize is the size of the byte ranges of parallel downloads
+	downloadPartSize int64 = 64 * 1024 * 1024
+
+	// downloadConcurrency is the number of byte ranges of a file downloaded in parallel
+	downloadConcurrency = 1
+
+	// downloadPartRetries is the number of times a failed byte range is retried
+	downloadPartRetries = 3
+
 	// path component delimiter
 	delimiter = "/"
 )
@@ -95,6 +110,11 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&tlsSkipVerifyCert, "s3_backup_tls_skip_verify_cert", false, "skip the 'certificate is valid' check for SSL connections.")
 	fs.StringVar(&requiredLogLevel, "s3_backup_log_level", "LogOff", "determine the S3 loglevel to use from LogOff, LogDebug, LogDebugWithSigning, LogDebugWithHTTPBody, LogDebugWithRequestRetries, LogDebugWithRequestErrors.")
 	fs.StringVar(&sse, "s3_backup_server_side_encryption", "", "server-side encryption algorithm (e.g., AES256, aws:kms, sse_c:/path/to/key/file).")
+	fs.Int64Var(&uploadPartSize, "s3_backup_upload_part_size", uploadPartSize, "minimum size in bytes of the parts of multipart uploads; raised as needed to stay under the S3 parts limit. Uses the AWS SDK default (5MiB) when set to 0.")
+	fs.IntVar(&uploadConcurrency, "s3_backup_upload_concurrency", uploadConcurrency, "number of parts of each file uploaded in parallel; each part is retried on its own.")
+	fs.Int64Var(&downloadPartSize, "s3_backup_download_part_size", downloadPartSize, "size in bytes of the byte ranges downloaded in parallel when s3_backup_download_concurrency is greater than 1.")
+	fs.IntVar(&downloadConcurrency, "s3_backup_download_concurrency", downloadConcurrency, "number of byte ranges of each file downloaded in parallel; requires s3_backup_download_concurrency * s3_backup_download_part_size bytes of memory per file. Files are downloaded in a single request when set to 1.")
+	fs.IntVar(&downloadPartRetries, "s3_backup_download_part_retries", downloadPartRetries, "number of times a byte range that failed to download is retried, when downloading in parallel.")
 }
 
 func init() {
@@ -154,6 +174,9 @@ func (bh *S3BackupHandle) AddFile(ctx context.Context, filename string, filesize
 
 	// Calculate s3 upload part size using the source filesize
 	partSizeBytes := s3manager.DefaultUploadPartSize
+	if uploadPartSize > partSizeBytes {
+		partSizeBytes = uploadPartSize
+	}
 	if filesize > 0 {
 		minimumPartSize := float64(filesize) / float64(s3manager.MaxUploadParts)
 		// Round up to ensure large enough partsize
@@ -170,6 +193,9 @@ func (bh *S3BackupHandle) AddFile(ctx context.Context, filename string, filesize
 		defer bh.waitGroup.Done()
 		uploader := s3manager.NewUploaderWithClient(bh.client, func(u *s3manager.Uploader) {
 			u.PartSize = partSizeBytes
+			if uploadConcurrency > 0 {
+				u.Concurrency = uploadConcurrency
+			}
 		})
 		object := objName(bh.dir, bh.name, filename)
 		sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
@@ -219,6 +245,9 @@ func (bh *S3BackupHandle) ReadFile(ctx context.Context, filename string) (io.Rea
 		return nil, fmt.Errorf("ReadFile cannot be called on read-write backup")
 	}
 	object := objName(bh.dir, bh.name, filename)
+	if downloadConcurrency > 1 {
+		return bh.readFileParallel(ctx, object)
+	}
 	sendStats := bh.bs.params.Stats.Scope(stats.Operation("AWS:Request:Send"))
 	out, err := bh.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
 		Bucket:               &bucket,
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mysqlctl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/vt/hook"
+	"vitess.io/vitess/go/vt/log"
+)
+
+// Upgrade upgrades mysqld in place. It shuts mysqld down, runs the
+// 'mysqld_upgrade' hook, if any, to install the new MySQL binaries, starts
+// mysqld again and upgrades the data directory to the new version. The data
+// directory of MySQL 8.0.16 and later is upgraded by the server itself when it
+// starts, older versions run mysql_upgrade.
+//
+// Without a 'mysqld_upgrade' hook, the new binaries must already be installed
+// where mysqld_safe finds them, i.e. under $VT_MYSQL_ROOT.
+func Upgrade(ctx context.Context, mysqld MysqlDaemon, cnf *Mycnf, shutdownTimeout time.Duration) error {
+	if err := mysqld.Shutdown(ctx, cnf, true, shutdownTimeout); err != nil {
+		return fmt.Errorf("failed to shut down mysqld: %v", err)
+	}
+
+	switch hr := hook.NewSimpleHook("mysqld_upgrade").ExecuteContext(ctx); hr.ExitStatus {
+	case hook.HOOK_SUCCESS:
+		log.Infof("mysqld_upgrade hook installed the new MySQL binaries")
+	case hook.HOOK_DOES_NOT_EXIST:
+		log.Infof("No mysqld_upgrade hook, starting mysqld with the installed binaries")
+	default:
+		// Don't leave the tablet without its database if the binaries
+		// couldn't be installed, the old ones are still in place.
+		if err := mysqld.Start(ctx, cnf); err != nil {
+			log.Errorf("failed to restart mysqld after the mysqld_upgrade hook failed: %v", err)
+		}
+		return fmt.Errorf("mysqld_upgrade hook failed: %v", hr.String())
+	}
+
+	if err := mysqld.Start(ctx, cnf); err != nil {
+		return fmt.Errorf("failed to start mysqld: %v", err)
+	}
+	if err := mysqld.RunMysqlUpgrade(ctx); err != nil {
+		return fmt.Errorf("failed to upgrade the data directory: %v", err)
+	}
+	return nil
+}
@@ -108,14 +108,19 @@ type xtraBackupManifest struct {
 	//
 	// When taking a backup with --compression-engine=external,
 	// ExternalDecompressor will be set to the value of
-	// --manifest-external-decompressor, if set, or else left as an empty
-	// string.
+	// --manifest-external-decompressor, if set, or else to the decompression
+	// command of the compression binary if it is zstd, pzstd or lz4, or else
+	// left as an empty string.
 	//
 	// When restoring from a backup with CompressionEngine "external",
 	// --external-decompressor will be consulted first and, if that is not set,
-	// ExternalDecompressor will be used. If neither are set, the restore will
-	// abort.
+	// ExternalDecompressor will be used, and then the decompression command of
+	// ExternalCompressor. If none are set, the restore will abort.
 	ExternalDecompressor string
+
+	// ExternalCompressor is the value of --external-compressor the backup was
+	// taken with, if any.
+	ExternalCompressor string `json:",omitempty"`
 }
 
 func init() {
@@ -142,8 +147,8 @@ func (be *XtrabackupEngine) backupFileName() string {
 		fileName += xtrabackupStreamMode
 	}
 	if backupStorageCompress {
-		if ExternalDecompressorCmd != "" {
-			fileName += ExternalCompressorExt
+		if ExternalCompressorCmd != "" {
+			fileName += externalCompressorExtension()
 		} else {
 			if ext, err := getExtensionFromEngine(CompressionEngineName); err != nil {
 				// there is a check for this, but just in case that fails, we set a extension to the file
@@ -186,7 +191,7 @@ func (be *XtrabackupEngine) executeFullBackup(ctx context.Context, params Backup
 	}
 
 	// an extension is required when using an external compressor
-	if backupStorageCompress && ExternalCompressorCmd != "" && ExternalCompressorExt == "" {
+	if backupStorageCompress && ExternalCompressorCmd != "" && externalCompressorExtension() == "" {
 		return BackupUnusable, vterrors.New(vtrpc.Code_INVALID_ARGUMENT,
 			"flag --external-compressor-extension not provided when using an external compressor")
 	}
@@ -258,6 +263,7 @@ func (be *XtrabackupEngine) executeFullBackup(ctx context.Context, params Backup
 			// xtrabackup backups are always created such that they
 			// are safe to use for upgrades later on.
 			UpgradeSafe: true,
+			Encryption:  backupstorage.HandleEncryption(bh),
 		},
 
 		// XtraBackup-specific fields
@@ -269,7 +275,8 @@ func (be *XtrabackupEngine) executeFullBackup(ctx context.Context, params Backup
 		StripeBlockSize: int32(xtrabackupStripeBlockSize),
 		// builtin specific field
 		CompressionEngine:    CompressionEngineName,
-		ExternalDecompressor: ManifestExternalDecompressorCmd,
+		ExternalDecompressor: manifestExternalDecompressor(),
+		ExternalCompressor:   ExternalCompressorCmd,
 	}
 
 	data, err := json.MarshalIndent(bm, "", "  ")
@@ -644,10 +651,7 @@ func (be *XtrabackupEngine) extractFiles(ctx context.Context, logger logutil.Log
 				// then we assign the default value of compressionEngine.
 				deCompressionEngine = PgzipCompressor
 			}
-			externalDecompressorCmd := ExternalDecompressorCmd
-			if externalDecompressorCmd == "" && bm.ExternalDecompressor != "" {
-				externalDecompressorCmd = bm.ExternalDecompressor
-			}
+			externalDecompressorCmd := resolveExternalDecompressor(bm.ExternalDecompressor, bm.ExternalCompressor)
 			if externalDecompressorCmd != "" {
 				if deCompressionEngine == ExternalCompressor {
 					deCompressionEngine = externalDecompressorCmd
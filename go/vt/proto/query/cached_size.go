@@ -17,7 +17,13 @@ limitations under the License.
 
 package query
 
-import hack "vitess.io/vitess/go/hack"
+import (
+	"math"
+	"reflect"
+	"unsafe"
+
+	hack "vitess.io/vitess/go/hack"
+)
 
 func (cached *BindVariable) CachedSize(alloc bool) int64 {
 	if cached == nil {
@@ -86,13 +92,15 @@ func (cached *QueryWarning) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Message)))
 	return size
 }
+
+//go:nocheckptr
 func (cached *Target) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(96)
+		size += int64(112)
 	}
 	// field unknownFields []byte
 	{
@@ -104,6 +112,21 @@ func (cached *Target) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Shard)))
 	// field Cell string
 	size += hack.RuntimeAllocSize(int64(len(cached.Cell)))
+	// field TabletTags map[string]string
+	if cached.TabletTags != nil {
+		size += int64(48)
+		hmap := reflect.ValueOf(cached.TabletTags)
+		numBuckets := int(math.Pow(2, float64((*(*uint8)(unsafe.Pointer(hmap.Pointer() + uintptr(9)))))))
+		numOldBuckets := (*(*uint16)(unsafe.Pointer(hmap.Pointer() + uintptr(10))))
+		size += hack.RuntimeAllocSize(int64(numOldBuckets * 272))
+		if len(cached.TabletTags) > 0 || numBuckets > 1 {
+			size += hack.RuntimeAllocSize(int64(numBuckets * 272))
+		}
+		for k, v := range cached.TabletTags {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+			size += hack.RuntimeAllocSize(int64(len(v)))
+		}
+	}
 	return size
 }
 func (cached *Value) CachedSize(alloc bool) int64 {
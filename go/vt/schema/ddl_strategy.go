@@ -30,7 +30,9 @@ var (
 	strategyParserRegexp        = regexp.MustCompile(`^([\S]+)\s+(.*)$`)
 	cutOverThresholdFlagRegexp  = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, cutOverThresholdFlag))
 	forceCutOverAfterFlagRegexp = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, forceCutOverAfterFlag))
+	cutOverLockWaitFlagRegexp   = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, cutOverLockWaitFlag))
 	retainArtifactsFlagRegexp   = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, retainArtifactsFlag))
+	dependsOnFlagRegexp         = regexp.MustCompile(fmt.Sprintf(`^[-]{1,2}%s=(.*?)$`, dependsOnFlag))
 )
 
 const (
@@ -51,6 +53,10 @@ const (
 	vreplicationTestSuite  = "vreplication-test-suite"
 	allowForeignKeysFlag   = "unsafe-allow-foreign-keys"
 	analyzeTableFlag       = "analyze-table"
+	cutOverPreflightFlag   = "cut-over-preflight"
+	postponeCutOverFlag    = "postpone-cut-over"
+	cutOverLockWaitFlag    = "cut-over-lock-wait"
+	dependsOnFlag          = "depends-on"
 )
 
 // DDLStrategy suggests how an ALTER TABLE should run (e.g. "direct", "online", "gh-ost" or "pt-osc")
@@ -115,9 +121,15 @@ func ParseDDLStrategy(strategyVariable string) (*DDLStrategySetting, error) {
 	if _, err := setting.CutOverThreshold(); err != nil {
 		return nil, err
 	}
+	if _, err := setting.CutOverLockWait(); err != nil {
+		return nil, err
+	}
 	if _, err := setting.RetainArtifactsDuration(); err != nil {
 		return nil, err
 	}
+	if _, err := setting.DependsOn(); err != nil {
+		return nil, err
+	}
 	cutoverAfter, err := setting.ForceCutOverAfter()
 	if err != nil {
 		return nil, err
@@ -230,6 +242,15 @@ func isForceCutOverFlag(opt string) (string, bool) {
 	return submatch[1], true
 }
 
+// isCutOverLockWaitFlag returns true when given option denotes a `--cut-over-lock-wait=[...]` flag
+func isCutOverLockWaitFlag(opt string) (string, bool) {
+	submatch := cutOverLockWaitFlagRegexp.FindStringSubmatch(opt)
+	if len(submatch) == 0 {
+		return "", false
+	}
+	return submatch[1], true
+}
+
 // isRetainArtifactsFlag returns true when given option denotes a `--retain-artifacts=[...]` flag
 func isRetainArtifactsFlag(opt string) (string, bool) {
 	submatch := retainArtifactsFlagRegexp.FindStringSubmatch(opt)
@@ -239,6 +260,15 @@ func isRetainArtifactsFlag(opt string) (string, bool) {
 	return submatch[1], true
 }
 
+// isDependsOnFlag returns true when given option denotes a `--depends-on=[...]` flag
+func isDependsOnFlag(opt string) (string, bool) {
+	submatch := dependsOnFlagRegexp.FindStringSubmatch(opt)
+	if len(submatch) == 0 {
+		return "", false
+	}
+	return submatch[1], true
+}
+
 // CutOverThreshold returns a the duration threshold indicated by --cut-over-threshold
 func (setting *DDLStrategySetting) CutOverThreshold() (d time.Duration, err error) {
 	// We do some ugly manual parsing of --cut-over-threshold value
@@ -275,6 +305,24 @@ func (setting *DDLStrategySetting) ForceCutOverAfter() (d time.Duration, err err
 	return d, err
 }
 
+// CutOverLockWait returns the duration indicated by --cut-over-lock-wait, which bounds the time the
+// cut-over waits to lock the migrated table
+func (setting *DDLStrategySetting) CutOverLockWait() (d time.Duration, err error) {
+	opts, _ := shlex.Split(setting.Options)
+	for _, opt := range opts {
+		if val, isLockWait := isCutOverLockWaitFlag(opt); isLockWait {
+			// value is possibly quoted
+			if s, err := strconv.Unquote(val); err == nil {
+				val = s
+			}
+			if val != "" {
+				d, err = time.ParseDuration(val)
+			}
+		}
+	}
+	return d, err
+}
+
 // RetainArtifactsDuration returns a the duration indicated by --retain-artifacts
 func (setting *DDLStrategySetting) RetainArtifactsDuration() (d time.Duration, err error) {
 	// We do some ugly manual parsing of --retain-artifacts
@@ -293,6 +341,33 @@ func (setting *DDLStrategySetting) RetainArtifactsDuration() (d time.Duration, e
 	return d, err
 }
 
+// DependsOn returns the UUIDs of the migrations indicated by --depends-on, which must
+// complete before this migration can run
+func (setting *DDLStrategySetting) DependsOn() (uuids []string, err error) {
+	opts, _ := shlex.Split(setting.Options)
+	for _, opt := range opts {
+		val, isDependsOn := isDependsOnFlag(opt)
+		if !isDependsOn {
+			continue
+		}
+		// value is possibly quoted
+		if s, err := strconv.Unquote(val); err == nil {
+			val = s
+		}
+		for _, uuid := range strings.Split(val, ",") {
+			uuid = strings.TrimSpace(uuid)
+			if uuid == "" {
+				continue
+			}
+			if !IsOnlineDDLUUID(uuid) {
+				return nil, fmt.Errorf("invalid migration UUID in --%s: '%s'", dependsOnFlag, uuid)
+			}
+			uuids = append(uuids, uuid)
+		}
+	}
+	return uuids, nil
+}
+
 // IsVreplicationTestSuite checks if strategy options include --vreplicatoin-test-suite
 func (setting *DDLStrategySetting) IsVreplicationTestSuite() bool {
 	return setting.hasFlag(vreplicationTestSuite)
@@ -308,6 +383,16 @@ func (setting *DDLStrategySetting) IsAnalyzeTableFlag() bool {
 	return setting.hasFlag(analyzeTableFlag)
 }
 
+// IsCutOverPreflightFlag checks if strategy options include --cut-over-preflight
+func (setting *DDLStrategySetting) IsCutOverPreflightFlag() bool {
+	return setting.hasFlag(cutOverPreflightFlag)
+}
+
+// IsPostponeCutOver checks if strategy options include --postpone-cut-over
+func (setting *DDLStrategySetting) IsPostponeCutOver() bool {
+	return setting.hasFlag(postponeCutOverFlag)
+}
+
 // RuntimeOptions returns the options used as runtime flags for given strategy, removing any internal hint options
 func (setting *DDLStrategySetting) RuntimeOptions() []string {
 	opts, _ := shlex.Split(setting.Options)
@@ -319,9 +404,15 @@ func (setting *DDLStrategySetting) RuntimeOptions() []string {
 		if _, ok := isForceCutOverFlag(opt); ok {
 			continue
 		}
+		if _, ok := isCutOverLockWaitFlag(opt); ok {
+			continue
+		}
 		if _, ok := isRetainArtifactsFlag(opt); ok {
 			continue
 		}
+		if _, ok := isDependsOnFlag(opt); ok {
+			continue
+		}
 		switch {
 		case isFlag(opt, declarativeFlag):
 		case isFlag(opt, skipTopoFlag):
@@ -337,6 +428,8 @@ func (setting *DDLStrategySetting) RuntimeOptions() []string {
 		case isFlag(opt, vreplicationTestSuite):
 		case isFlag(opt, allowForeignKeysFlag):
 		case isFlag(opt, analyzeTableFlag):
+		case isFlag(opt, cutOverPreflightFlag):
+		case isFlag(opt, postponeCutOverFlag):
 		default:
 			validOpts = append(validOpts, opt)
 		}
@@ -197,9 +197,13 @@ func TestParseDDLStrategy(t *testing.T) {
 		fastRangeRotation    bool
 		allowForeignKeys     bool
 		analyzeTable         bool
+		cutOverPreflight     bool
+		postponeCutOver      bool
 		cutOverThreshold     time.Duration
+		cutOverLockWait      time.Duration
 		forceCutOverAfter    time.Duration
 		expireArtifacts      time.Duration
+		dependsOn            []string
 		runtimeOptions       string
 		expectError          string
 	}{
@@ -354,6 +358,39 @@ func TestParseDDLStrategy(t *testing.T) {
 			runtimeOptions:   "",
 			analyzeTable:     true,
 		},
+		{
+			strategyVariable:     "vitess --cut-over-preflight --postpone-completion",
+			strategy:             DDLStrategyVitess,
+			options:              "--cut-over-preflight --postpone-completion",
+			runtimeOptions:       "",
+			cutOverPreflight:     true,
+			isPostponeCompletion: true,
+		},
+		{
+			strategyVariable: "vitess --postpone-cut-over --cut-over-lock-wait=5s",
+			strategy:         DDLStrategyVitess,
+			options:          "--postpone-cut-over --cut-over-lock-wait=5s",
+			runtimeOptions:   "",
+			postponeCutOver:  true,
+			cutOverLockWait:  5 * time.Second,
+		},
+		{
+			strategyVariable: "vitess --cut-over-lock-wait=5",
+			strategy:         DDLStrategyVitess,
+			expectError:      "time: missing unit in duration",
+		},
+		{
+			strategyVariable: "vitess --depends-on=a0638f6b_ec7b_11ea_9bf8_000d3a9b8a9a,b0638f6b_ec7b_11ea_9bf8_000d3a9b8a9a",
+			strategy:         DDLStrategyVitess,
+			options:          "--depends-on=a0638f6b_ec7b_11ea_9bf8_000d3a9b8a9a,b0638f6b_ec7b_11ea_9bf8_000d3a9b8a9a",
+			runtimeOptions:   "",
+			dependsOn:        []string{"a0638f6b_ec7b_11ea_9bf8_000d3a9b8a9a", "b0638f6b_ec7b_11ea_9bf8_000d3a9b8a9a"},
+		},
+		{
+			strategyVariable: "vitess --depends-on=a0638f6b",
+			strategy:         DDLStrategyVitess,
+			expectError:      "invalid migration UUID in --depends-on",
+		},
 
 		{
 			strategyVariable: "vitess --alow-concrrnt", // intentional typo
@@ -387,12 +424,20 @@ func TestParseDDLStrategy(t *testing.T) {
 			assert.Equal(t, ts.fastRangeRotation, setting.IsFastRangeRotationFlag())
 			assert.Equal(t, ts.allowForeignKeys, setting.IsAllowForeignKeysFlag())
 			assert.Equal(t, ts.analyzeTable, setting.IsAnalyzeTableFlag())
+			assert.Equal(t, ts.cutOverPreflight, setting.IsCutOverPreflightFlag())
+			assert.Equal(t, ts.postponeCutOver, setting.IsPostponeCutOver())
 			cutOverThreshold, err := setting.CutOverThreshold()
 			assert.NoError(t, err)
 			assert.Equal(t, ts.cutOverThreshold, cutOverThreshold)
+			cutOverLockWait, err := setting.CutOverLockWait()
+			assert.NoError(t, err)
+			assert.Equal(t, ts.cutOverLockWait, cutOverLockWait)
 			forceCutOverAfter, err := setting.ForceCutOverAfter()
 			assert.NoError(t, err)
 			assert.Equal(t, ts.forceCutOverAfter, forceCutOverAfter)
+			dependsOn, err := setting.DependsOn()
+			assert.NoError(t, err)
+			assert.Equal(t, ts.dependsOn, dependsOn)
 
 			runtimeOptions := strings.Join(setting.RuntimeOptions(), " ")
 			assert.Equal(t, ts.runtimeOptions, runtimeOptions)
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// DeclarativeTableNames returns the names of the tables and views that are
+// the subject of the given declarative statements.
+func DeclarativeTableNames(sqls []string, parser *sqlparser.Parser) ([]string, error) {
+	var names []string
+	for _, sql := range sqls {
+		stmt, err := parser.Parse(sql)
+		if err != nil {
+			return nil, err
+		}
+		ddlStmt, ok := stmt.(sqlparser.DDLStatement)
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "declarative migrations only support DDL statements: %s", sql)
+		}
+		for _, table := range ddlStmt.AffectedTables() {
+			names = append(names, table.Name.String())
+		}
+	}
+	return names, nil
+}
+
+// DiffDeclarativeStatements computes the schema changes that declarative
+// migrations for the given statements would apply. currentSchema maps the
+// name of each existing table or view to its CREATE statement. The returned
+// list holds the canonical DDL for each statement that results in a change;
+// statements that match the current schema produce no entry.
+func DiffDeclarativeStatements(env *schemadiff.Environment, currentSchema map[string]string, sqls []string) ([]string, error) {
+	hints := &schemadiff.DiffHints{
+		AutoIncrementStrategy: schemadiff.AutoIncrementApplyHigher,
+	}
+	var diffs []string
+	for _, sql := range sqls {
+		stmt, err := env.Parser().ParseStrictDDL(sql)
+		if err != nil {
+			return nil, err
+		}
+		var diff schemadiff.EntityDiff
+		switch stmt := stmt.(type) {
+		case *sqlparser.CreateTable:
+			existing, ok := currentSchema[stmt.Table.Name.String()]
+			if !ok {
+				diffs = append(diffs, sqlparser.CanonicalString(stmt))
+				continue
+			}
+			diff, err = schemadiff.DiffCreateTablesQueries(env, existing, sql, hints)
+		case *sqlparser.CreateView:
+			existing, ok := currentSchema[stmt.ViewName.Name.String()]
+			if !ok {
+				diffs = append(diffs, sqlparser.CanonicalString(stmt))
+				continue
+			}
+			diff, err = schemadiff.DiffCreateViewsQueries(env, existing, sql, hints)
+		case *sqlparser.DropTable:
+			for _, table := range stmt.FromTables {
+				if _, ok := currentSchema[table.Name.String()]; ok {
+					diffs = append(diffs, sqlparser.CanonicalString(&sqlparser.DropTable{FromTables: sqlparser.TableNames{table}}))
+				}
+			}
+			continue
+		case *sqlparser.DropView:
+			for _, view := range stmt.FromTables {
+				if _, ok := currentSchema[view.Name.String()]; ok {
+					diffs = append(diffs, sqlparser.CanonicalString(&sqlparser.DropView{FromTables: sqlparser.TableNames{view}}))
+				}
+			}
+			continue
+		default:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "declarative migrations only support CREATE and DROP statements: %s", sql)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !diff.IsEmpty() {
+			diffs = append(diffs, diff.CanonicalStatementString())
+		}
+	}
+	return diffs, nil
+}
+
+// MergeShardDiffs merges the schema changes computed for each shard of a
+// keyspace, keyed by shard name, into the results of a dry run. When all the
+// shards require the same changes, they are returned as is. Otherwise the
+// shards have drifted, and the changes are listed under each group of shards
+// that requires them.
+func MergeShardDiffs(diffsByShard map[string][]string) []string {
+	shards := make([]string, 0, len(diffsByShard))
+	for shard := range diffsByShard {
+		shards = append(shards, shard)
+	}
+	sort.Strings(shards)
+
+	var (
+		plans         [][]string
+		shardsByPlans [][]string
+	)
+	for _, shard := range shards {
+		diffs := diffsByShard[shard]
+		i := slices.IndexFunc(plans, func(plan []string) bool { return slices.Equal(plan, diffs) })
+		if i < 0 {
+			plans = append(plans, diffs)
+			shardsByPlans = append(shardsByPlans, nil)
+			i = len(plans) - 1
+		}
+		shardsByPlans[i] = append(shardsByPlans[i], shard)
+	}
+
+	results := func(diffs []string) []string {
+		if len(diffs) == 0 {
+			return []string{"No changes required"}
+		}
+		return diffs
+	}
+	switch len(plans) {
+	case 0:
+		return results(nil)
+	case 1:
+		return results(plans[0])
+	}
+	merged := []string{fmt.Sprintf("The schemas of the %d shards differ, the changes depend on the shard:", len(shards))}
+	for i, plan := range plans {
+		merged = append(merged, fmt.Sprintf("Shards %s:", strings.Join(shardsByPlans[i], ", ")))
+		for _, diff := range results(plan) {
+			merged = append(merged, "  "+diff)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemamanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestDeclarativeTableNames(t *testing.T) {
+	names, err := DeclarativeTableNames([]string{
+		"create table t1 (id int primary key)",
+		"create view v1 as select id from t1",
+		"drop table t2, t3",
+	}, sqlparser.NewTestParser())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "v1", "t2", "t3"}, names)
+
+	_, err = DeclarativeTableNames([]string{"select 1"}, sqlparser.NewTestParser())
+	assert.ErrorContains(t, err, "only support DDL statements")
+}
+
+func TestDiffDeclarativeStatements(t *testing.T) {
+	currentSchema := map[string]string{
+		"t1": "create table t1 (id int primary key)",
+		"t2": "create table t2 (id int primary key)",
+		"v1": "create view v1 as select id from t1",
+	}
+	tcases := []struct {
+		name   string
+		sqls   []string
+		expect []string
+		err    string
+	}{
+		{
+			name: "no changes",
+			sqls: []string{
+				"create table t1 (id int primary key)",
+				"create view v1 as select id from t1",
+			},
+		},
+		{
+			name:   "new table",
+			sqls:   []string{"create table t3 (id int primary key)"},
+			expect: []string{"CREATE TABLE `t3` (\n\t`id` int PRIMARY KEY\n)"},
+		},
+		{
+			name:   "alter table",
+			sqls:   []string{"create table t1 (id int primary key, name varchar(64))"},
+			expect: []string{"ALTER TABLE `t1` ADD COLUMN `name` varchar(64)"},
+		},
+		{
+			name:   "alter view",
+			sqls:   []string{"create view v1 as select id from t2"},
+			expect: []string{"ALTER VIEW `v1` AS SELECT `id` FROM `t2`"},
+		},
+		{
+			name:   "drop existing and missing tables",
+			sqls:   []string{"drop table t2, t4"},
+			expect: []string{"DROP TABLE `t2`"},
+		},
+		{
+			name: "unsupported statement",
+			sqls: []string{"alter table t1 add column name varchar(64)"},
+			err:  "only support CREATE and DROP statements",
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			diffs, err := DiffDeclarativeStatements(schemadiff.NewTestEnv(), currentSchema, tcase.sqls)
+			if tcase.err != "" {
+				assert.ErrorContains(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expect, diffs)
+		})
+	}
+}
+
+func TestMergeShardDiffs(t *testing.T) {
+	alter := "alter table t1 add column c int"
+	assert.Equal(t, []string{alter}, MergeShardDiffs(map[string][]string{
+		"-80": {alter},
+		"80-": {alter},
+	}))
+	assert.Equal(t, []string{"No changes required"}, MergeShardDiffs(map[string][]string{
+		"-80": nil,
+		"80-": nil,
+	}))
+	assert.Equal(t, []string{
+		"The schemas of the 3 shards differ, the changes depend on the shard:",
+		"Shards -40, 80-:",
+		"  " + alter,
+		"Shards 40-80:",
+		"  No changes required",
+	}, MergeShardDiffs(map[string][]string{
+		"-40":   {alter},
+		"40-80": nil,
+		"80-":   {alter},
+	}))
+}
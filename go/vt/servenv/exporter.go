@@ -17,6 +17,7 @@ limitations under the License.
 package servenv
 
 import (
+	"context"
 	"expvar"
 	"net/http"
 	"net/url"
@@ -107,7 +108,8 @@ type Exporter struct {
 }
 
 func init() {
-	HTTPHandle("/debug/vars", expvar.Handler())
+	HTTPHandle("/debug/vars", stats.VarsHandler())
+	HTTPHandle("/debug/metrics/metadata", stats.MetadataHandler())
 }
 
 // NewExporter creates a new Exporter with name as namespace.
@@ -646,6 +648,14 @@ func (tw *TimingsWrapper) Record(name string, startTime time.Time) {
 	tw.timings.Record([]string{tw.name, name}, startTime)
 }
 
+// Start behaves like Timings.Start.
+func (tw *TimingsWrapper) Start(ctx context.Context, name string) *stats.TimerGuard {
+	if tw.name == "" {
+		return tw.timings.Start(ctx, []string{name})
+	}
+	return tw.timings.Start(ctx, []string{tw.name, name})
+}
+
 // Counts behaves like Timings.Counts.
 func (tw *TimingsWrapper) Counts() map[string]int64 {
 	return tw.timings.Counts()
@@ -684,6 +694,14 @@ func (tw *MultiTimingsWrapper) Record(names []string, startTime time.Time) {
 	tw.timings.Record(newlabels, startTime)
 }
 
+// Start behaves like MultiTimings.Start.
+func (tw *MultiTimingsWrapper) Start(ctx context.Context, names []string) *stats.TimerGuard {
+	if tw.name == "" {
+		return tw.timings.Start(ctx, names)
+	}
+	return tw.timings.Start(ctx, combineLabels(tw.name, names))
+}
+
 // Counts behaves lie MultiTimings.Counts.
 func (tw *MultiTimingsWrapper) Counts() map[string]int64 {
 	return tw.timings.Counts()
@@ -269,9 +269,27 @@ func interceptors() []grpc.ServerOption {
 
 	trace.AddGrpcServerOptions(interceptors.Add)
 
+	for _, hook := range gRPCServerInterceptorHooks {
+		if stream, unary := hook(); stream != nil && unary != nil {
+			interceptors.Add(stream, unary)
+		}
+	}
+
 	return interceptors.Build()
 }
 
+// gRPCServerInterceptorHooks are the hooks registered with
+// RegisterGRPCServerInterceptors.
+var gRPCServerInterceptorHooks []func() (grpc.StreamServerInterceptor, grpc.UnaryServerInterceptor)
+
+// RegisterGRPCServerInterceptors registers a hook that returns interceptors to
+// add to the gRPC server. The hook is called when the server is created, after
+// the flags are parsed, and the interceptors run after the authentication
+// ones. The hook returns nil interceptors to add none.
+func RegisterGRPCServerInterceptors(hook func() (grpc.StreamServerInterceptor, grpc.UnaryServerInterceptor)) {
+	gRPCServerInterceptorHooks = append(gRPCServerInterceptorHooks, hook)
+}
+
 func serveGRPC() {
 	if grpccommon.EnableGRPCPrometheus() {
 		grpc_prometheus.Register(GRPCServer)
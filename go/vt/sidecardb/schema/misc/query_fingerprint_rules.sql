@@ -0,0 +1,26 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+CREATE TABLE IF NOT EXISTS query_fingerprint_rules
+(
+    `fingerprint`   varbinary(3072) NOT NULL,
+    `action`        varbinary(32)   NOT NULL,
+    `plan_type`     varbinary(64)   NOT NULL DEFAULT '',
+    `error_message` varbinary(1024) NOT NULL DEFAULT '',
+    `time_updated`  timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (`fingerprint`)
+) ENGINE = InnoDB
@@ -73,6 +73,7 @@ CREATE TABLE IF NOT EXISTS schema_migrations
     `removed_foreign_key_names`       text             NOT NULL,
     `last_cutover_attempt_timestamp`  timestamp        NULL DEFAULT NULL,
     `force_cutover`                   tinyint unsigned NOT NULL DEFAULT '0',
+    `cut_over_approved`               tinyint unsigned NOT NULL DEFAULT '0',
     PRIMARY KEY (`id`),
     UNIQUE KEY `uuid_idx` (`migration_uuid`),
     KEY `keyspace_shard_idx` (`keyspace`(64), `shard`(64)),
@@ -0,0 +1,27 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+CREATE TABLE IF NOT EXISTS dt_audit
+(
+  id bigint NOT NULL AUTO_INCREMENT,
+  dtid varbinary(512) NOT NULL,
+  action varbinary(64) NOT NULL,
+  source varbinary(64) NOT NULL,
+  message text NOT NULL,
+  time_created bigint NOT NULL,
+  primary key(id),
+  key dtid_idx(dtid)
+) ENGINE = InnoDB
@@ -89,7 +89,7 @@ func ASTToStatementType(stmt Statement) StatementType {
 		return StmtShowMigrationLogs
 	case *Use:
 		return StmtUse
-	case *OtherAdmin, *Load:
+	case *OtherAdmin, *Load, *ResetVitessPlans:
 		return StmtOther
 	case *Analyze:
 		return StmtAnalyze
@@ -238,7 +238,7 @@ func Preview(sql string) StatementType {
 		return StmtUse
 	case "describe", "desc", "explain":
 		return StmtExplain
-	case "repair", "optimize":
+	case "repair", "optimize", "reset":
 		return StmtOther
 	case "analyze":
 		return StmtAnalyze
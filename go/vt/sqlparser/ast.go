@@ -473,6 +473,9 @@ type (
 		Comments Comments
 	}
 
+	// ResetVitessPlans represents a RESET VITESS_PLANS statement
+	ResetVitessPlans struct{}
+
 	// RevertMigration represents a REVERT VITESS_MIGRATION statement
 	RevertMigration struct {
 		UUID     string
@@ -562,8 +565,34 @@ type (
 	// DDLAction is an enum for DDL.Action
 	DDLAction int8
 
-	// Load represents a LOAD statement
+	// Load represents a LOAD DATA statement. The LOAD DATA FROM S3
+	// statements are parsed as an empty Load.
 	Load struct {
+		Local       bool
+		File        string
+		Replace     bool
+		Ignore      Ignore
+		Table       TableName
+		Charset     ColumnCharset
+		Fields      *LoadFields
+		Lines       *LoadLines
+		IgnoreLines *Literal
+		Columns     Columns
+		SetExprs    UpdateExprs
+	}
+
+	// LoadFields represents the FIELDS clause of a LOAD DATA statement.
+	LoadFields struct {
+		TerminatedBy       *Literal
+		EnclosedBy         *Literal
+		OptionallyEnclosed bool
+		EscapedBy          *Literal
+	}
+
+	// LoadLines represents the LINES clause of a LOAD DATA statement.
+	LoadLines struct {
+		StartingBy   *Literal
+		TerminatedBy *Literal
 	}
 
 	// PurgeBinaryLogs represents a PURGE BINARY LOGS statement
@@ -758,6 +787,7 @@ func (*RevertMigration) iStatement()     {}
 func (*ShowMigrationLogs) iStatement()   {}
 func (*ShowThrottledApps) iStatement()   {}
 func (*ShowThrottlerStatus) iStatement() {}
+func (*ResetVitessPlans) iStatement()    {}
 func (*DropTable) iStatement()           {}
 func (*DropView) iStatement()            {}
 func (*TruncateTable) iStatement()       {}
@@ -2186,9 +2216,10 @@ type (
 	// FrameClause represents frame_clause
 	// More information available here: https://dev.mysql.com/doc/refman/8.0/en/window-functions-frames.html
 	FrameClause struct {
-		Unit  FrameUnitType
-		Start *FramePoint
-		End   *FramePoint
+		Unit    FrameUnitType
+		Start   *FramePoint
+		End     *FramePoint
+		Exclude FrameExclusionType
 	}
 
 	// FramePoint refers to frame_start/frame_end
@@ -2212,6 +2243,9 @@ type (
 	// FrameUnitType is an enum to get types of FramePoint.
 	FramePointType int8
 
+	// FrameExclusionType is an enum to get the frame_exclusion of a FrameClause.
+	FrameExclusionType int8
+
 	// NullTreatmentClause refers to null_treatment
 	// According to SQL Docs:  Some window functions permit a null_treatment clause that specifies how to handle NULL values when calculating results.
 	// This clause is optional. It is part of the SQL standard, but the MySQL implementation permits only RESPECT NULLS (which is also the default).
@@ -3008,6 +3042,21 @@ type (
 		Arg Expr
 	}
 
+	// JSONArrayAgg represents a call to JSON_ARRAYAGG
+	// More information on https://dev.mysql.com/doc/refman/8.0/en/aggregate-functions.html#function_json-arrayagg
+	JSONArrayAgg struct {
+		Arg        Expr
+		OverClause *OverClause
+	}
+
+	// JSONObjectAgg represents a call to JSON_OBJECTAGG
+	// More information on https://dev.mysql.com/doc/refman/8.0/en/aggregate-functions.html#function_json-objectagg
+	JSONObjectAgg struct {
+		Key        Expr
+		Value      Expr
+		OverClause *OverClause
+	}
+
 	// RegexpInstrExpr represents REGEXP_INSTR()
 	// For more information, see https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
 	RegexpInstrExpr struct {
@@ -3249,6 +3298,8 @@ func (*CountStar) IsExpr()                          {}
 func (*Count) IsExpr()                              {}
 func (*GroupConcatExpr) IsExpr()                    {}
 func (*AnyValue) IsExpr()                           {}
+func (*JSONArrayAgg) IsExpr()                       {}
+func (*JSONObjectAgg) IsExpr()                      {}
 func (*BitAnd) IsExpr()                             {}
 func (*BitOr) IsExpr()                              {}
 func (*BitXor) IsExpr()                             {}
@@ -3298,6 +3349,8 @@ func (*ConvertUsingExpr) iCallable()                   {}
 func (*MatchExpr) iCallable()                          {}
 func (*GroupConcatExpr) iCallable()                    {}
 func (*AnyValue) iCallable()                           {}
+func (*JSONArrayAgg) iCallable()                       {}
+func (*JSONObjectAgg) iCallable()                      {}
 func (*JSONSchemaValidFuncExpr) iCallable()            {}
 func (*JSONSchemaValidationReportFuncExpr) iCallable() {}
 func (*JSONPrettyExpr) iCallable()                     {}
@@ -3379,6 +3432,8 @@ func (varP *VarPop) GetArg() Expr               { return varP.Arg }
 func (varS *VarSamp) GetArg() Expr              { return varS.Arg }
 func (variance *Variance) GetArg() Expr         { return variance.Arg }
 func (av *AnyValue) GetArg() Expr               { return av.Arg }
+func (jaa *JSONArrayAgg) GetArg() Expr          { return jaa.Arg }
+func (joa *JSONObjectAgg) GetArg() Expr         { return joa.Key }
 
 func (sum *Sum) GetArgs() Exprs                   { return Exprs{sum.Arg} }
 func (min *Min) GetArgs() Exprs                   { return Exprs{min.Arg} }
@@ -3398,6 +3453,8 @@ func (varP *VarPop) GetArgs() Exprs               { return Exprs{varP.Arg} }
 func (varS *VarSamp) GetArgs() Exprs              { return Exprs{varS.Arg} }
 func (variance *Variance) GetArgs() Exprs         { return Exprs{variance.Arg} }
 func (av *AnyValue) GetArgs() Exprs               { return Exprs{av.Arg} }
+func (jaa *JSONArrayAgg) GetArgs() Exprs          { return Exprs{jaa.Arg} }
+func (joa *JSONObjectAgg) GetArgs() Exprs         { return Exprs{joa.Key, joa.Value} }
 
 func (sum *Sum) IsDistinct() bool                   { return sum.Distinct }
 func (min *Min) IsDistinct() bool                   { return min.Distinct }
@@ -3431,6 +3488,8 @@ func (*VarPop) AggrName() string          { return "var_pop" }
 func (*VarSamp) AggrName() string         { return "var_samp" }
 func (*Variance) AggrName() string        { return "variance" }
 func (*AnyValue) AggrName() string        { return "any_value" }
+func (*JSONArrayAgg) AggrName() string    { return "json_arrayagg" }
+func (*JSONObjectAgg) AggrName() string   { return "json_objectagg" }
 
 // Exprs represents a list of value expressions.
 // It's not a valid expression because it's not parenthesized.
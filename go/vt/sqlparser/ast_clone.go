@@ -233,6 +233,8 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfIntroducerExpr(in)
 	case *IsExpr:
 		return CloneRefOfIsExpr(in)
+	case *JSONArrayAgg:
+		return CloneRefOfJSONArrayAgg(in)
 	case *JSONArrayExpr:
 		return CloneRefOfJSONArrayExpr(in)
 	case *JSONAttributesExpr:
@@ -245,6 +247,8 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfJSONExtractExpr(in)
 	case *JSONKeysExpr:
 		return CloneRefOfJSONKeysExpr(in)
+	case *JSONObjectAgg:
+		return CloneRefOfJSONObjectAgg(in)
 	case *JSONObjectExpr:
 		return CloneRefOfJSONObjectExpr(in)
 	case *JSONObjectParam:
@@ -303,6 +307,10 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfLiteral(in)
 	case *Load:
 		return CloneRefOfLoad(in)
+	case *LoadFields:
+		return CloneRefOfLoadFields(in)
+	case *LoadLines:
+		return CloneRefOfLoadLines(in)
 	case *LocateExpr:
 		return CloneRefOfLocateExpr(in)
 	case *LockOption:
@@ -417,6 +425,8 @@ func CloneSQLNode(in SQLNode) SQLNode {
 		return CloneRefOfRenameTable(in)
 	case *RenameTableName:
 		return CloneRefOfRenameTableName(in)
+	case *ResetVitessPlans:
+		return CloneRefOfResetVitessPlans(in)
 	case *RevertMigration:
 		return CloneRefOfRevertMigration(in)
 	case *Rollback:
@@ -1691,6 +1701,17 @@ func CloneRefOfIsExpr(n *IsExpr) *IsExpr {
 	return &out
 }
 
+// CloneRefOfJSONArrayAgg creates a deep clone of the input.
+func CloneRefOfJSONArrayAgg(n *JSONArrayAgg) *JSONArrayAgg {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Arg = CloneExpr(n.Arg)
+	out.OverClause = CloneRefOfOverClause(n.OverClause)
+	return &out
+}
+
 // CloneRefOfJSONArrayExpr creates a deep clone of the input.
 func CloneRefOfJSONArrayExpr(n *JSONArrayExpr) *JSONArrayExpr {
 	if n == nil {
@@ -1758,6 +1779,18 @@ func CloneRefOfJSONKeysExpr(n *JSONKeysExpr) *JSONKeysExpr {
 	return &out
 }
 
+// CloneRefOfJSONObjectAgg creates a deep clone of the input.
+func CloneRefOfJSONObjectAgg(n *JSONObjectAgg) *JSONObjectAgg {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.Key = CloneExpr(n.Key)
+	out.Value = CloneExpr(n.Value)
+	out.OverClause = CloneRefOfOverClause(n.OverClause)
+	return &out
+}
+
 // CloneRefOfJSONObjectExpr creates a deep clone of the input.
 func CloneRefOfJSONObjectExpr(n *JSONObjectExpr) *JSONObjectExpr {
 	if n == nil {
@@ -2060,6 +2093,36 @@ func CloneRefOfLoad(n *Load) *Load {
 		return nil
 	}
 	out := *n
+	out.Table = CloneTableName(n.Table)
+	out.Charset = CloneColumnCharset(n.Charset)
+	out.Fields = CloneRefOfLoadFields(n.Fields)
+	out.Lines = CloneRefOfLoadLines(n.Lines)
+	out.IgnoreLines = CloneRefOfLiteral(n.IgnoreLines)
+	out.Columns = CloneColumns(n.Columns)
+	out.SetExprs = CloneUpdateExprs(n.SetExprs)
+	return &out
+}
+
+// CloneRefOfLoadFields creates a deep clone of the input.
+func CloneRefOfLoadFields(n *LoadFields) *LoadFields {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.TerminatedBy = CloneRefOfLiteral(n.TerminatedBy)
+	out.EnclosedBy = CloneRefOfLiteral(n.EnclosedBy)
+	out.EscapedBy = CloneRefOfLiteral(n.EscapedBy)
+	return &out
+}
+
+// CloneRefOfLoadLines creates a deep clone of the input.
+func CloneRefOfLoadLines(n *LoadLines) *LoadLines {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	out.StartingBy = CloneRefOfLiteral(n.StartingBy)
+	out.TerminatedBy = CloneRefOfLiteral(n.TerminatedBy)
 	return &out
 }
 
@@ -2668,6 +2731,15 @@ func CloneRefOfRenameTableName(n *RenameTableName) *RenameTableName {
 	return &out
 }
 
+// CloneRefOfResetVitessPlans creates a deep clone of the input.
+func CloneRefOfResetVitessPlans(n *ResetVitessPlans) *ResetVitessPlans {
+	if n == nil {
+		return nil
+	}
+	out := *n
+	return &out
+}
+
 // CloneRefOfRevertMigration creates a deep clone of the input.
 func CloneRefOfRevertMigration(n *RevertMigration) *RevertMigration {
 	if n == nil {
@@ -3448,6 +3520,10 @@ func CloneAggrFunc(in AggrFunc) AggrFunc {
 		return CloneRefOfCountStar(in)
 	case *GroupConcatExpr:
 		return CloneRefOfGroupConcatExpr(in)
+	case *JSONArrayAgg:
+		return CloneRefOfJSONArrayAgg(in)
+	case *JSONObjectAgg:
+		return CloneRefOfJSONObjectAgg(in)
 	case *Max:
 		return CloneRefOfMax(in)
 	case *Min:
@@ -3592,6 +3668,8 @@ func CloneCallable(in Callable) Callable {
 		return CloneRefOfIntervalDateExpr(in)
 	case *IntervalFuncExpr:
 		return CloneRefOfIntervalFuncExpr(in)
+	case *JSONArrayAgg:
+		return CloneRefOfJSONArrayAgg(in)
 	case *JSONArrayExpr:
 		return CloneRefOfJSONArrayExpr(in)
 	case *JSONAttributesExpr:
@@ -3604,6 +3682,8 @@ func CloneCallable(in Callable) Callable {
 		return CloneRefOfJSONExtractExpr(in)
 	case *JSONKeysExpr:
 		return CloneRefOfJSONKeysExpr(in)
+	case *JSONObjectAgg:
+		return CloneRefOfJSONObjectAgg(in)
 	case *JSONObjectExpr:
 		return CloneRefOfJSONObjectExpr(in)
 	case *JSONOverlapsExpr:
@@ -3892,6 +3972,8 @@ func CloneExpr(in Expr) Expr {
 		return CloneRefOfIntroducerExpr(in)
 	case *IsExpr:
 		return CloneRefOfIsExpr(in)
+	case *JSONArrayAgg:
+		return CloneRefOfJSONArrayAgg(in)
 	case *JSONArrayExpr:
 		return CloneRefOfJSONArrayExpr(in)
 	case *JSONAttributesExpr:
@@ -3904,6 +3986,8 @@ func CloneExpr(in Expr) Expr {
 		return CloneRefOfJSONExtractExpr(in)
 	case *JSONKeysExpr:
 		return CloneRefOfJSONKeysExpr(in)
+	case *JSONObjectAgg:
+		return CloneRefOfJSONObjectAgg(in)
 	case *JSONObjectExpr:
 		return CloneRefOfJSONObjectExpr(in)
 	case *JSONOverlapsExpr:
@@ -4190,6 +4274,8 @@ func CloneStatement(in Statement) Statement {
 		return CloneRefOfRelease(in)
 	case *RenameTable:
 		return CloneRefOfRenameTable(in)
+	case *ResetVitessPlans:
+		return CloneRefOfResetVitessPlans(in)
 	case *RevertMigration:
 		return CloneRefOfRevertMigration(in)
 	case *Rollback:
@@ -232,6 +232,8 @@ func (c *cow) copyOnRewriteSQLNode(n SQLNode, parent SQLNode) (out SQLNode, chan
 		return c.copyOnRewriteRefOfIntroducerExpr(n, parent)
 	case *IsExpr:
 		return c.copyOnRewriteRefOfIsExpr(n, parent)
+	case *JSONArrayAgg:
+		return c.copyOnRewriteRefOfJSONArrayAgg(n, parent)
 	case *JSONArrayExpr:
 		return c.copyOnRewriteRefOfJSONArrayExpr(n, parent)
 	case *JSONAttributesExpr:
@@ -244,6 +246,8 @@ func (c *cow) copyOnRewriteSQLNode(n SQLNode, parent SQLNode) (out SQLNode, chan
 		return c.copyOnRewriteRefOfJSONExtractExpr(n, parent)
 	case *JSONKeysExpr:
 		return c.copyOnRewriteRefOfJSONKeysExpr(n, parent)
+	case *JSONObjectAgg:
+		return c.copyOnRewriteRefOfJSONObjectAgg(n, parent)
 	case *JSONObjectExpr:
 		return c.copyOnRewriteRefOfJSONObjectExpr(n, parent)
 	case *JSONObjectParam:
@@ -302,6 +306,10 @@ func (c *cow) copyOnRewriteSQLNode(n SQLNode, parent SQLNode) (out SQLNode, chan
 		return c.copyOnRewriteRefOfLiteral(n, parent)
 	case *Load:
 		return c.copyOnRewriteRefOfLoad(n, parent)
+	case *LoadFields:
+		return c.copyOnRewriteRefOfLoadFields(n, parent)
+	case *LoadLines:
+		return c.copyOnRewriteRefOfLoadLines(n, parent)
 	case *LocateExpr:
 		return c.copyOnRewriteRefOfLocateExpr(n, parent)
 	case *LockOption:
@@ -416,6 +424,8 @@ func (c *cow) copyOnRewriteSQLNode(n SQLNode, parent SQLNode) (out SQLNode, chan
 		return c.copyOnRewriteRefOfRenameTable(n, parent)
 	case *RenameTableName:
 		return c.copyOnRewriteRefOfRenameTableName(n, parent)
+	case *ResetVitessPlans:
+		return c.copyOnRewriteRefOfResetVitessPlans(n, parent)
 	case *RevertMigration:
 		return c.copyOnRewriteRefOfRevertMigration(n, parent)
 	case *Rollback:
@@ -2955,6 +2965,30 @@ func (c *cow) copyOnRewriteRefOfIsExpr(n *IsExpr, parent SQLNode) (out SQLNode,
 	}
 	return
 }
+func (c *cow) copyOnRewriteRefOfJSONArrayAgg(n *JSONArrayAgg, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_Arg, changedArg := c.copyOnRewriteExpr(n.Arg, n)
+		_OverClause, changedOverClause := c.copyOnRewriteRefOfOverClause(n.OverClause, n)
+		if changedArg || changedOverClause {
+			res := *n
+			res.Arg, _ = _Arg.(Expr)
+			res.OverClause, _ = _OverClause.(*OverClause)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
 func (c *cow) copyOnRewriteRefOfJSONArrayExpr(n *JSONArrayExpr, parent SQLNode) (out SQLNode, changed bool) {
 	if n == nil || c.cursor.stop {
 		return n, false
@@ -3125,6 +3159,32 @@ func (c *cow) copyOnRewriteRefOfJSONKeysExpr(n *JSONKeysExpr, parent SQLNode) (o
 	}
 	return
 }
+func (c *cow) copyOnRewriteRefOfJSONObjectAgg(n *JSONObjectAgg, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_Key, changedKey := c.copyOnRewriteExpr(n.Key, n)
+		_Value, changedValue := c.copyOnRewriteExpr(n.Value, n)
+		_OverClause, changedOverClause := c.copyOnRewriteRefOfOverClause(n.OverClause, n)
+		if changedKey || changedValue || changedOverClause {
+			res := *n
+			res.Key, _ = _Key.(Expr)
+			res.Value, _ = _Value.(Expr)
+			res.OverClause, _ = _OverClause.(*OverClause)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
 func (c *cow) copyOnRewriteRefOfJSONObjectExpr(n *JSONObjectExpr, parent SQLNode) (out SQLNode, changed bool) {
 	if n == nil || c.cursor.stop {
 		return n, false
@@ -3771,6 +3831,76 @@ func (c *cow) copyOnRewriteRefOfLoad(n *Load, parent SQLNode) (out SQLNode, chan
 	}
 	out = n
 	if c.pre == nil || c.pre(n, parent) {
+		_Table, changedTable := c.copyOnRewriteTableName(n.Table, n)
+		_Fields, changedFields := c.copyOnRewriteRefOfLoadFields(n.Fields, n)
+		_Lines, changedLines := c.copyOnRewriteRefOfLoadLines(n.Lines, n)
+		_IgnoreLines, changedIgnoreLines := c.copyOnRewriteRefOfLiteral(n.IgnoreLines, n)
+		_Columns, changedColumns := c.copyOnRewriteColumns(n.Columns, n)
+		_SetExprs, changedSetExprs := c.copyOnRewriteUpdateExprs(n.SetExprs, n)
+		if changedTable || changedFields || changedLines || changedIgnoreLines || changedColumns || changedSetExprs {
+			res := *n
+			res.Table, _ = _Table.(TableName)
+			res.Fields, _ = _Fields.(*LoadFields)
+			res.Lines, _ = _Lines.(*LoadLines)
+			res.IgnoreLines, _ = _IgnoreLines.(*Literal)
+			res.Columns, _ = _Columns.(Columns)
+			res.SetExprs, _ = _SetExprs.(UpdateExprs)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
+func (c *cow) copyOnRewriteRefOfLoadFields(n *LoadFields, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_TerminatedBy, changedTerminatedBy := c.copyOnRewriteRefOfLiteral(n.TerminatedBy, n)
+		_EnclosedBy, changedEnclosedBy := c.copyOnRewriteRefOfLiteral(n.EnclosedBy, n)
+		_EscapedBy, changedEscapedBy := c.copyOnRewriteRefOfLiteral(n.EscapedBy, n)
+		if changedTerminatedBy || changedEnclosedBy || changedEscapedBy {
+			res := *n
+			res.TerminatedBy, _ = _TerminatedBy.(*Literal)
+			res.EnclosedBy, _ = _EnclosedBy.(*Literal)
+			res.EscapedBy, _ = _EscapedBy.(*Literal)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
+func (c *cow) copyOnRewriteRefOfLoadLines(n *LoadLines, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+		_StartingBy, changedStartingBy := c.copyOnRewriteRefOfLiteral(n.StartingBy, n)
+		_TerminatedBy, changedTerminatedBy := c.copyOnRewriteRefOfLiteral(n.TerminatedBy, n)
+		if changedStartingBy || changedTerminatedBy {
+			res := *n
+			res.StartingBy, _ = _StartingBy.(*Literal)
+			res.TerminatedBy, _ = _TerminatedBy.(*Literal)
+			out = &res
+			if c.cloned != nil {
+				c.cloned(n, out)
+			}
+			changed = true
+		}
 	}
 	if c.post != nil {
 		out, changed = c.postVisit(out, parent, changed)
@@ -5033,6 +5163,18 @@ func (c *cow) copyOnRewriteRefOfRenameTableName(n *RenameTableName, parent SQLNo
 	}
 	return
 }
+func (c *cow) copyOnRewriteRefOfResetVitessPlans(n *ResetVitessPlans, parent SQLNode) (out SQLNode, changed bool) {
+	if n == nil || c.cursor.stop {
+		return n, false
+	}
+	out = n
+	if c.pre == nil || c.pre(n, parent) {
+	}
+	if c.post != nil {
+		out, changed = c.postVisit(out, parent, changed)
+	}
+	return
+}
 func (c *cow) copyOnRewriteRefOfRevertMigration(n *RevertMigration, parent SQLNode) (out SQLNode, changed bool) {
 	if n == nil || c.cursor.stop {
 		return n, false
@@ -6671,6 +6813,10 @@ func (c *cow) copyOnRewriteAggrFunc(n AggrFunc, parent SQLNode) (out SQLNode, ch
 		return c.copyOnRewriteRefOfCountStar(n, parent)
 	case *GroupConcatExpr:
 		return c.copyOnRewriteRefOfGroupConcatExpr(n, parent)
+	case *JSONArrayAgg:
+		return c.copyOnRewriteRefOfJSONArrayAgg(n, parent)
+	case *JSONObjectAgg:
+		return c.copyOnRewriteRefOfJSONObjectAgg(n, parent)
 	case *Max:
 		return c.copyOnRewriteRefOfMax(n, parent)
 	case *Min:
@@ -6811,6 +6957,8 @@ func (c *cow) copyOnRewriteCallable(n Callable, parent SQLNode) (out SQLNode, ch
 		return c.copyOnRewriteRefOfIntervalDateExpr(n, parent)
 	case *IntervalFuncExpr:
 		return c.copyOnRewriteRefOfIntervalFuncExpr(n, parent)
+	case *JSONArrayAgg:
+		return c.copyOnRewriteRefOfJSONArrayAgg(n, parent)
 	case *JSONArrayExpr:
 		return c.copyOnRewriteRefOfJSONArrayExpr(n, parent)
 	case *JSONAttributesExpr:
@@ -6823,6 +6971,8 @@ func (c *cow) copyOnRewriteCallable(n Callable, parent SQLNode) (out SQLNode, ch
 		return c.copyOnRewriteRefOfJSONExtractExpr(n, parent)
 	case *JSONKeysExpr:
 		return c.copyOnRewriteRefOfJSONKeysExpr(n, parent)
+	case *JSONObjectAgg:
+		return c.copyOnRewriteRefOfJSONObjectAgg(n, parent)
 	case *JSONObjectExpr:
 		return c.copyOnRewriteRefOfJSONObjectExpr(n, parent)
 	case *JSONOverlapsExpr:
@@ -7099,6 +7249,8 @@ func (c *cow) copyOnRewriteExpr(n Expr, parent SQLNode) (out SQLNode, changed bo
 		return c.copyOnRewriteRefOfIntroducerExpr(n, parent)
 	case *IsExpr:
 		return c.copyOnRewriteRefOfIsExpr(n, parent)
+	case *JSONArrayAgg:
+		return c.copyOnRewriteRefOfJSONArrayAgg(n, parent)
 	case *JSONArrayExpr:
 		return c.copyOnRewriteRefOfJSONArrayExpr(n, parent)
 	case *JSONAttributesExpr:
@@ -7111,6 +7263,8 @@ func (c *cow) copyOnRewriteExpr(n Expr, parent SQLNode) (out SQLNode, changed bo
 		return c.copyOnRewriteRefOfJSONExtractExpr(n, parent)
 	case *JSONKeysExpr:
 		return c.copyOnRewriteRefOfJSONKeysExpr(n, parent)
+	case *JSONObjectAgg:
+		return c.copyOnRewriteRefOfJSONObjectAgg(n, parent)
 	case *JSONObjectExpr:
 		return c.copyOnRewriteRefOfJSONObjectExpr(n, parent)
 	case *JSONOverlapsExpr:
@@ -7385,6 +7539,8 @@ func (c *cow) copyOnRewriteStatement(n Statement, parent SQLNode) (out SQLNode,
 		return c.copyOnRewriteRefOfRelease(n, parent)
 	case *RenameTable:
 		return c.copyOnRewriteRefOfRenameTable(n, parent)
+	case *ResetVitessPlans:
+		return c.copyOnRewriteRefOfResetVitessPlans(n, parent)
 	case *RevertMigration:
 		return c.copyOnRewriteRefOfRevertMigration(n, parent)
 	case *Rollback:
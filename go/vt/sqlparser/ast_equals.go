@@ -656,6 +656,12 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfIsExpr(a, b)
+	case *JSONArrayAgg:
+		b, ok := inB.(*JSONArrayAgg)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfJSONArrayAgg(a, b)
 	case *JSONArrayExpr:
 		b, ok := inB.(*JSONArrayExpr)
 		if !ok {
@@ -692,6 +698,12 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfJSONKeysExpr(a, b)
+	case *JSONObjectAgg:
+		b, ok := inB.(*JSONObjectAgg)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfJSONObjectAgg(a, b)
 	case *JSONObjectExpr:
 		b, ok := inB.(*JSONObjectExpr)
 		if !ok {
@@ -866,6 +878,18 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfLoad(a, b)
+	case *LoadFields:
+		b, ok := inB.(*LoadFields)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfLoadFields(a, b)
+	case *LoadLines:
+		b, ok := inB.(*LoadLines)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfLoadLines(a, b)
 	case *LocateExpr:
 		b, ok := inB.(*LocateExpr)
 		if !ok {
@@ -1208,6 +1232,12 @@ func (cmp *Comparator) SQLNode(inA, inB SQLNode) bool {
 			return false
 		}
 		return cmp.RefOfRenameTableName(a, b)
+	case *ResetVitessPlans:
+		b, ok := inB.(*ResetVitessPlans)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfResetVitessPlans(a, b)
 	case *RevertMigration:
 		b, ok := inB.(*RevertMigration)
 		if !ok {
@@ -2599,7 +2629,8 @@ func (cmp *Comparator) RefOfFrameClause(a, b *FrameClause) bool {
 	}
 	return a.Unit == b.Unit &&
 		cmp.RefOfFramePoint(a.Start, b.Start) &&
-		cmp.RefOfFramePoint(a.End, b.End)
+		cmp.RefOfFramePoint(a.End, b.End) &&
+		a.Exclude == b.Exclude
 }
 
 // RefOfFramePoint does deep equals between the two objects.
@@ -2957,6 +2988,18 @@ func (cmp *Comparator) RefOfIsExpr(a, b *IsExpr) bool {
 		a.Right == b.Right
 }
 
+// RefOfJSONArrayAgg does deep equals between the two objects.
+func (cmp *Comparator) RefOfJSONArrayAgg(a, b *JSONArrayAgg) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return cmp.Expr(a.Arg, b.Arg) &&
+		cmp.RefOfOverClause(a.OverClause, b.OverClause)
+}
+
 // RefOfJSONArrayExpr does deep equals between the two objects.
 func (cmp *Comparator) RefOfJSONArrayExpr(a, b *JSONArrayExpr) bool {
 	if a == b {
@@ -3031,6 +3074,19 @@ func (cmp *Comparator) RefOfJSONKeysExpr(a, b *JSONKeysExpr) bool {
 		cmp.Expr(a.Path, b.Path)
 }
 
+// RefOfJSONObjectAgg does deep equals between the two objects.
+func (cmp *Comparator) RefOfJSONObjectAgg(a, b *JSONObjectAgg) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return cmp.Expr(a.Key, b.Key) &&
+		cmp.Expr(a.Value, b.Value) &&
+		cmp.RefOfOverClause(a.OverClause, b.OverClause)
+}
+
 // RefOfJSONObjectExpr does deep equals between the two objects.
 func (cmp *Comparator) RefOfJSONObjectExpr(a, b *JSONObjectExpr) bool {
 	if a == b {
@@ -3373,7 +3429,43 @@ func (cmp *Comparator) RefOfLoad(a, b *Load) bool {
 	if a == nil || b == nil {
 		return false
 	}
-	return true
+	return a.Local == b.Local &&
+		a.File == b.File &&
+		a.Replace == b.Replace &&
+		a.Ignore == b.Ignore &&
+		cmp.TableName(a.Table, b.Table) &&
+		cmp.ColumnCharset(a.Charset, b.Charset) &&
+		cmp.RefOfLoadFields(a.Fields, b.Fields) &&
+		cmp.RefOfLoadLines(a.Lines, b.Lines) &&
+		cmp.RefOfLiteral(a.IgnoreLines, b.IgnoreLines) &&
+		cmp.Columns(a.Columns, b.Columns) &&
+		cmp.UpdateExprs(a.SetExprs, b.SetExprs)
+}
+
+// RefOfLoadFields does deep equals between the two objects.
+func (cmp *Comparator) RefOfLoadFields(a, b *LoadFields) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return a.OptionallyEnclosed == b.OptionallyEnclosed &&
+		cmp.RefOfLiteral(a.TerminatedBy, b.TerminatedBy) &&
+		cmp.RefOfLiteral(a.EnclosedBy, b.EnclosedBy) &&
+		cmp.RefOfLiteral(a.EscapedBy, b.EscapedBy)
+}
+
+// RefOfLoadLines does deep equals between the two objects.
+func (cmp *Comparator) RefOfLoadLines(a, b *LoadLines) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return cmp.RefOfLiteral(a.StartingBy, b.StartingBy) &&
+		cmp.RefOfLiteral(a.TerminatedBy, b.TerminatedBy)
 }
 
 // RefOfLocateExpr does deep equals between the two objects.
@@ -4067,6 +4159,17 @@ func (cmp *Comparator) RefOfRenameTableName(a, b *RenameTableName) bool {
 	return cmp.TableName(a.Table, b.Table)
 }
 
+// RefOfResetVitessPlans does deep equals between the two objects.
+func (cmp *Comparator) RefOfResetVitessPlans(a, b *ResetVitessPlans) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	return true
+}
+
 // RefOfRevertMigration does deep equals between the two objects.
 func (cmp *Comparator) RefOfRevertMigration(a, b *RevertMigration) bool {
 	if a == b {
@@ -4988,6 +5091,18 @@ func (cmp *Comparator) AggrFunc(inA, inB AggrFunc) bool {
 			return false
 		}
 		return cmp.RefOfGroupConcatExpr(a, b)
+	case *JSONArrayAgg:
+		b, ok := inB.(*JSONArrayAgg)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfJSONArrayAgg(a, b)
+	case *JSONObjectAgg:
+		b, ok := inB.(*JSONObjectAgg)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfJSONObjectAgg(a, b)
 	case *Max:
 		b, ok := inB.(*Max)
 		if !ok {
@@ -5378,6 +5493,12 @@ func (cmp *Comparator) Callable(inA, inB Callable) bool {
 			return false
 		}
 		return cmp.RefOfIntervalFuncExpr(a, b)
+	case *JSONArrayAgg:
+		b, ok := inB.(*JSONArrayAgg)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfJSONArrayAgg(a, b)
 	case *JSONArrayExpr:
 		b, ok := inB.(*JSONArrayExpr)
 		if !ok {
@@ -5414,6 +5535,12 @@ func (cmp *Comparator) Callable(inA, inB Callable) bool {
 			return false
 		}
 		return cmp.RefOfJSONKeysExpr(a, b)
+	case *JSONObjectAgg:
+		b, ok := inB.(*JSONObjectAgg)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfJSONObjectAgg(a, b)
 	case *JSONObjectExpr:
 		b, ok := inB.(*JSONObjectExpr)
 		if !ok {
@@ -6152,6 +6279,12 @@ func (cmp *Comparator) Expr(inA, inB Expr) bool {
 			return false
 		}
 		return cmp.RefOfIsExpr(a, b)
+	case *JSONArrayAgg:
+		b, ok := inB.(*JSONArrayAgg)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfJSONArrayAgg(a, b)
 	case *JSONArrayExpr:
 		b, ok := inB.(*JSONArrayExpr)
 		if !ok {
@@ -6188,6 +6321,12 @@ func (cmp *Comparator) Expr(inA, inB Expr) bool {
 			return false
 		}
 		return cmp.RefOfJSONKeysExpr(a, b)
+	case *JSONObjectAgg:
+		b, ok := inB.(*JSONObjectAgg)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfJSONObjectAgg(a, b)
 	case *JSONObjectExpr:
 		b, ok := inB.(*JSONObjectExpr)
 		if !ok {
@@ -6920,6 +7059,12 @@ func (cmp *Comparator) Statement(inA, inB Statement) bool {
 			return false
 		}
 		return cmp.RefOfRenameTable(a, b)
+	case *ResetVitessPlans:
+		b, ok := inB.(*ResetVitessPlans)
+		if !ok {
+			return false
+		}
+		return cmp.RefOfResetVitessPlans(a, b)
 	case *RevertMigration:
 		b, ok := inB.(*RevertMigration)
 		if !ok {
@@ -307,6 +307,8 @@ func (node *AlterMigration) Format(buf *TrackedBuffer) {
 		alterType = "force_cutover"
 	case ForceCutOverAllMigrationType:
 		alterType = "force_cutover all"
+	case CutOverMigrationType:
+		alterType = "cutover"
 	}
 	buf.astPrintf(node, " %#s", alterType)
 	if node.Expire != "" {
@@ -335,6 +337,11 @@ func (node *ShowThrottledApps) Format(buf *TrackedBuffer) {
 	buf.astPrintf(node, "show vitess_throttled_apps")
 }
 
+// Format formats the node.
+func (node *ResetVitessPlans) Format(buf *TrackedBuffer) {
+	buf.astPrintf(node, "reset vitess_plans")
+}
+
 // Format formats the node.
 func (node *ShowThrottlerStatus) Format(buf *TrackedBuffer) {
 	buf.astPrintf(node, "show vitess_throttler status")
@@ -1648,6 +1655,9 @@ func (node *FrameClause) Format(buf *TrackedBuffer) {
 	} else {
 		buf.astPrintf(node, "%v", node.Start)
 	}
+	if node.Exclude != NoFrameExclusionType {
+		buf.astPrintf(node, " %s", node.Exclude.ToString())
+	}
 }
 
 // Format formats the node
@@ -2025,7 +2035,68 @@ func (node IdentifierCS) Format(buf *TrackedBuffer) {
 
 // Format formats the node.
 func (node *Load) Format(buf *TrackedBuffer) {
-	buf.literal("AST node missing for Load type")
+	if node.Table.Name.IsEmpty() {
+		buf.literal("AST node missing for Load type")
+		return
+	}
+	buf.literal("load data ")
+	if node.Local {
+		buf.literal("local ")
+	}
+	buf.astPrintf(node, "infile %#s", encodeSQLString(node.File))
+	if node.Replace {
+		buf.literal(" replace")
+	} else if node.Ignore {
+		buf.literal(" ignore")
+	}
+	buf.astPrintf(node, " into table %v", node.Table)
+	if node.Charset.Name != "" {
+		buf.astPrintf(node, " character set %#s", node.Charset.Name)
+	}
+	buf.astPrintf(node, "%v%v", node.Fields, node.Lines)
+	if node.IgnoreLines != nil {
+		buf.astPrintf(node, " ignore %v lines", node.IgnoreLines)
+	}
+	if len(node.Columns) > 0 {
+		buf.astPrintf(node, " %v", node.Columns)
+	}
+	if len(node.SetExprs) > 0 {
+		buf.astPrintf(node, " set %v", node.SetExprs)
+	}
+}
+
+// Format formats the node.
+func (node *LoadFields) Format(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.literal(" fields")
+	if node.TerminatedBy != nil {
+		buf.astPrintf(node, " terminated by %v", node.TerminatedBy)
+	}
+	if node.EnclosedBy != nil {
+		if node.OptionallyEnclosed {
+			buf.literal(" optionally")
+		}
+		buf.astPrintf(node, " enclosed by %v", node.EnclosedBy)
+	}
+	if node.EscapedBy != nil {
+		buf.astPrintf(node, " escaped by %v", node.EscapedBy)
+	}
+}
+
+// Format formats the node.
+func (node *LoadLines) Format(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.literal(" lines")
+	if node.StartingBy != nil {
+		buf.astPrintf(node, " starting by %v", node.StartingBy)
+	}
+	if node.TerminatedBy != nil {
+		buf.astPrintf(node, " terminated by %v", node.TerminatedBy)
+	}
 }
 
 // Format formats the node.
@@ -2763,6 +2834,20 @@ func (node *Sum) Format(buf *TrackedBuffer) {
 	}
 }
 
+func (node *JSONArrayAgg) Format(buf *TrackedBuffer) {
+	buf.astPrintf(node, "json_arrayagg(%v)", node.Arg)
+	if node.OverClause != nil {
+		buf.astPrintf(node, " %v", node.OverClause)
+	}
+}
+
+func (node *JSONObjectAgg) Format(buf *TrackedBuffer) {
+	buf.astPrintf(node, "json_objectagg(%v, %v)", node.Key, node.Value)
+	if node.OverClause != nil {
+		buf.astPrintf(node, " %v", node.OverClause)
+	}
+}
+
 func (node *BitAnd) Format(buf *TrackedBuffer) {
 	buf.astPrintf(node, "bit_and(%v)", node.Arg)
 	if node.OverClause != nil {
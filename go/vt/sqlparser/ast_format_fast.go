@@ -428,6 +428,8 @@ func (node *AlterMigration) FormatFast(buf *TrackedBuffer) {
 		alterType = "force_cutover"
 	case ForceCutOverAllMigrationType:
 		alterType = "force_cutover all"
+	case CutOverMigrationType:
+		alterType = "cutover"
 	}
 	buf.WriteByte(' ')
 	buf.WriteString(alterType)
@@ -468,6 +470,11 @@ func (node *ShowThrottledApps) FormatFast(buf *TrackedBuffer) {
 	buf.WriteString("show vitess_throttled_apps")
 }
 
+// FormatFast formats the node.
+func (node *ResetVitessPlans) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("reset vitess_plans")
+}
+
 // FormatFast formats the node.
 func (node *ShowThrottlerStatus) FormatFast(buf *TrackedBuffer) {
 	buf.WriteString("show vitess_throttler status")
@@ -2191,6 +2198,10 @@ func (node *FrameClause) FormatFast(buf *TrackedBuffer) {
 	} else {
 		node.Start.FormatFast(buf)
 	}
+	if node.Exclude != NoFrameExclusionType {
+		buf.WriteByte(' ')
+		buf.WriteString(node.Exclude.ToString())
+	}
 }
 
 // FormatFast formats the node
@@ -2688,7 +2699,81 @@ func (node IdentifierCS) FormatFast(buf *TrackedBuffer) {
 
 // FormatFast formats the node.
 func (node *Load) FormatFast(buf *TrackedBuffer) {
-	buf.WriteString("AST node missing for Load type")
+	if node.Table.Name.IsEmpty() {
+		buf.WriteString("AST node missing for Load type")
+		return
+	}
+	buf.WriteString("load data ")
+	if node.Local {
+		buf.WriteString("local ")
+	}
+	buf.WriteString("infile ")
+	buf.WriteString(encodeSQLString(node.File))
+	if node.Replace {
+		buf.WriteString(" replace")
+	} else if node.Ignore {
+		buf.WriteString(" ignore")
+	}
+	buf.WriteString(" into table ")
+	node.Table.FormatFast(buf)
+	if node.Charset.Name != "" {
+		buf.WriteString(" character set ")
+		buf.WriteString(node.Charset.Name)
+	}
+	node.Fields.FormatFast(buf)
+	node.Lines.FormatFast(buf)
+	if node.IgnoreLines != nil {
+		buf.WriteString(" ignore ")
+		node.IgnoreLines.FormatFast(buf)
+		buf.WriteString(" lines")
+	}
+	if len(node.Columns) > 0 {
+		buf.WriteByte(' ')
+		node.Columns.FormatFast(buf)
+	}
+	if len(node.SetExprs) > 0 {
+		buf.WriteString(" set ")
+		node.SetExprs.FormatFast(buf)
+	}
+}
+
+// FormatFast formats the node.
+func (node *LoadFields) FormatFast(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.WriteString(" fields")
+	if node.TerminatedBy != nil {
+		buf.WriteString(" terminated by ")
+		node.TerminatedBy.FormatFast(buf)
+	}
+	if node.EnclosedBy != nil {
+		if node.OptionallyEnclosed {
+			buf.WriteString(" optionally")
+		}
+		buf.WriteString(" enclosed by ")
+		node.EnclosedBy.FormatFast(buf)
+	}
+	if node.EscapedBy != nil {
+		buf.WriteString(" escaped by ")
+		node.EscapedBy.FormatFast(buf)
+	}
+}
+
+// FormatFast formats the node.
+func (node *LoadLines) FormatFast(buf *TrackedBuffer) {
+	if node == nil {
+		return
+	}
+	buf.WriteString(" lines")
+	if node.StartingBy != nil {
+		buf.WriteString(" starting by ")
+		node.StartingBy.FormatFast(buf)
+	}
+	if node.TerminatedBy != nil {
+		buf.WriteString(" terminated by ")
+		node.TerminatedBy.FormatFast(buf)
+	}
 }
 
 // FormatFast formats the node.
@@ -3640,6 +3725,28 @@ func (node *Sum) FormatFast(buf *TrackedBuffer) {
 	}
 }
 
+func (node *JSONArrayAgg) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("json_arrayagg(")
+	buf.printExpr(node, node.Arg, true)
+	buf.WriteByte(')')
+	if node.OverClause != nil {
+		buf.WriteByte(' ')
+		node.OverClause.FormatFast(buf)
+	}
+}
+
+func (node *JSONObjectAgg) FormatFast(buf *TrackedBuffer) {
+	buf.WriteString("json_objectagg(")
+	buf.printExpr(node, node.Key, true)
+	buf.WriteString(", ")
+	buf.printExpr(node, node.Value, true)
+	buf.WriteByte(')')
+	if node.OverClause != nil {
+		buf.WriteByte(' ')
+		node.OverClause.FormatFast(buf)
+	}
+}
+
 func (node *BitAnd) FormatFast(buf *TrackedBuffer) {
 	buf.WriteString("bit_and(")
 	buf.printExpr(node, node.Arg, true)
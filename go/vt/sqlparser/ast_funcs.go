@@ -865,7 +865,7 @@ func NewVariableExpression(str string, at AtCount) *Variable {
 
 func createIdentifierCI(str string) IdentifierCI {
 	size := len(str)
-	if str[0] == '`' && str[size-1] == '`' {
+	if size >= 2 && str[0] == '`' && str[size-1] == '`' {
 		str = str[1 : size-1]
 	}
 	return NewIdentifierCI(str)
@@ -1657,6 +1657,22 @@ func (ty FramePointType) ToString() string {
 	}
 }
 
+// ToString returns the type as a string
+func (ty FrameExclusionType) ToString() string {
+	switch ty {
+	case ExcludeCurrentRowType:
+		return ExcludeCurrentRowStr
+	case ExcludeGroupType:
+		return ExcludeGroupStr
+	case ExcludeTiesType:
+		return ExcludeTiesStr
+	case ExcludeNoOthersType:
+		return ExcludeNoOthersStr
+	default:
+		return "Unknown FrameExclusionType"
+	}
+}
+
 // ToString returns the type as a string
 func (ty ArgumentLessWindowExprType) ToString() string {
 	switch ty {
@@ -1960,6 +1976,8 @@ func (ty ShowCommandType) ToString() string {
 		return VGtidExecGlobalStr
 	case VitessMigrations:
 		return VitessMigrationsStr
+	case VitessPlans:
+		return VitessPlansStr
 	case VitessReplicationStatus:
 		return VitessReplicationStatusStr
 	case VitessShards:
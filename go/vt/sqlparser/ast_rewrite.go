@@ -232,6 +232,8 @@ func (a *application) rewriteSQLNode(parent SQLNode, node SQLNode, replacer repl
 		return a.rewriteRefOfIntroducerExpr(parent, node, replacer)
 	case *IsExpr:
 		return a.rewriteRefOfIsExpr(parent, node, replacer)
+	case *JSONArrayAgg:
+		return a.rewriteRefOfJSONArrayAgg(parent, node, replacer)
 	case *JSONArrayExpr:
 		return a.rewriteRefOfJSONArrayExpr(parent, node, replacer)
 	case *JSONAttributesExpr:
@@ -244,6 +246,8 @@ func (a *application) rewriteSQLNode(parent SQLNode, node SQLNode, replacer repl
 		return a.rewriteRefOfJSONExtractExpr(parent, node, replacer)
 	case *JSONKeysExpr:
 		return a.rewriteRefOfJSONKeysExpr(parent, node, replacer)
+	case *JSONObjectAgg:
+		return a.rewriteRefOfJSONObjectAgg(parent, node, replacer)
 	case *JSONObjectExpr:
 		return a.rewriteRefOfJSONObjectExpr(parent, node, replacer)
 	case *JSONObjectParam:
@@ -302,6 +306,10 @@ func (a *application) rewriteSQLNode(parent SQLNode, node SQLNode, replacer repl
 		return a.rewriteRefOfLiteral(parent, node, replacer)
 	case *Load:
 		return a.rewriteRefOfLoad(parent, node, replacer)
+	case *LoadFields:
+		return a.rewriteRefOfLoadFields(parent, node, replacer)
+	case *LoadLines:
+		return a.rewriteRefOfLoadLines(parent, node, replacer)
 	case *LocateExpr:
 		return a.rewriteRefOfLocateExpr(parent, node, replacer)
 	case *LockOption:
@@ -416,6 +424,8 @@ func (a *application) rewriteSQLNode(parent SQLNode, node SQLNode, replacer repl
 		return a.rewriteRefOfRenameTable(parent, node, replacer)
 	case *RenameTableName:
 		return a.rewriteRefOfRenameTableName(parent, node, replacer)
+	case *ResetVitessPlans:
+		return a.rewriteRefOfResetVitessPlans(parent, node, replacer)
 	case *RevertMigration:
 		return a.rewriteRefOfRevertMigration(parent, node, replacer)
 	case *Rollback:
@@ -4103,6 +4113,43 @@ func (a *application) rewriteRefOfIsExpr(parent SQLNode, node *IsExpr, replacer
 	}
 	return true
 }
+func (a *application) rewriteRefOfJSONArrayAgg(parent SQLNode, node *JSONArrayAgg, replacer replacerFunc) bool {
+	if node == nil {
+		return true
+	}
+	if a.pre != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		kontinue := !a.pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteExpr(parent, a.cur.node.(Expr), replacer)
+		}
+		if kontinue {
+			return true
+		}
+	}
+	if !a.rewriteExpr(node, node.Arg, func(newNode, parent SQLNode) {
+		parent.(*JSONArrayAgg).Arg = newNode.(Expr)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfOverClause(node, node.OverClause, func(newNode, parent SQLNode) {
+		parent.(*JSONArrayAgg).OverClause = newNode.(*OverClause)
+	}) {
+		return false
+	}
+	if a.post != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.post(&a.cur) {
+			return false
+		}
+	}
+	return true
+}
 func (a *application) rewriteRefOfJSONArrayExpr(parent SQLNode, node *JSONArrayExpr, replacer replacerFunc) bool {
 	if node == nil {
 		return true
@@ -4342,6 +4389,48 @@ func (a *application) rewriteRefOfJSONKeysExpr(parent SQLNode, node *JSONKeysExp
 	}
 	return true
 }
+func (a *application) rewriteRefOfJSONObjectAgg(parent SQLNode, node *JSONObjectAgg, replacer replacerFunc) bool {
+	if node == nil {
+		return true
+	}
+	if a.pre != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		kontinue := !a.pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteExpr(parent, a.cur.node.(Expr), replacer)
+		}
+		if kontinue {
+			return true
+		}
+	}
+	if !a.rewriteExpr(node, node.Key, func(newNode, parent SQLNode) {
+		parent.(*JSONObjectAgg).Key = newNode.(Expr)
+	}) {
+		return false
+	}
+	if !a.rewriteExpr(node, node.Value, func(newNode, parent SQLNode) {
+		parent.(*JSONObjectAgg).Value = newNode.(Expr)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfOverClause(node, node.OverClause, func(newNode, parent SQLNode) {
+		parent.(*JSONObjectAgg).OverClause = newNode.(*OverClause)
+	}) {
+		return false
+	}
+	if a.post != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.post(&a.cur) {
+			return false
+		}
+	}
+	return true
+}
 func (a *application) rewriteRefOfJSONObjectExpr(parent SQLNode, node *JSONObjectExpr, replacer replacerFunc) bool {
 	if node == nil {
 		return true
@@ -5312,12 +5401,109 @@ func (a *application) rewriteRefOfLoad(parent SQLNode, node *Load, replacer repl
 			return true
 		}
 	}
+	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
+		parent.(*Load).Table = newNode.(TableName)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLoadFields(node, node.Fields, func(newNode, parent SQLNode) {
+		parent.(*Load).Fields = newNode.(*LoadFields)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLoadLines(node, node.Lines, func(newNode, parent SQLNode) {
+		parent.(*Load).Lines = newNode.(*LoadLines)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLiteral(node, node.IgnoreLines, func(newNode, parent SQLNode) {
+		parent.(*Load).IgnoreLines = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if !a.rewriteColumns(node, node.Columns, func(newNode, parent SQLNode) {
+		parent.(*Load).Columns = newNode.(Columns)
+	}) {
+		return false
+	}
+	if !a.rewriteUpdateExprs(node, node.SetExprs, func(newNode, parent SQLNode) {
+		parent.(*Load).SetExprs = newNode.(UpdateExprs)
+	}) {
+		return false
+	}
+	if a.post != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.post(&a.cur) {
+			return false
+		}
+	}
+	return true
+}
+func (a *application) rewriteRefOfLoadFields(parent SQLNode, node *LoadFields, replacer replacerFunc) bool {
+	if node == nil {
+		return true
+	}
+	if a.pre != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.pre(&a.cur) {
+			return true
+		}
+	}
+	if !a.rewriteRefOfLiteral(node, node.TerminatedBy, func(newNode, parent SQLNode) {
+		parent.(*LoadFields).TerminatedBy = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLiteral(node, node.EnclosedBy, func(newNode, parent SQLNode) {
+		parent.(*LoadFields).EnclosedBy = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLiteral(node, node.EscapedBy, func(newNode, parent SQLNode) {
+		parent.(*LoadFields).EscapedBy = newNode.(*Literal)
+	}) {
+		return false
+	}
 	if a.post != nil {
-		if a.pre == nil {
-			a.cur.replacer = replacer
-			a.cur.parent = parent
-			a.cur.node = node
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.post(&a.cur) {
+			return false
 		}
+	}
+	return true
+}
+func (a *application) rewriteRefOfLoadLines(parent SQLNode, node *LoadLines, replacer replacerFunc) bool {
+	if node == nil {
+		return true
+	}
+	if a.pre != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.pre(&a.cur) {
+			return true
+		}
+	}
+	if !a.rewriteRefOfLiteral(node, node.StartingBy, func(newNode, parent SQLNode) {
+		parent.(*LoadLines).StartingBy = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if !a.rewriteRefOfLiteral(node, node.TerminatedBy, func(newNode, parent SQLNode) {
+		parent.(*LoadLines).TerminatedBy = newNode.(*Literal)
+	}) {
+		return false
+	}
+	if a.post != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
 		if !a.post(&a.cur) {
 			return false
 		}
@@ -7229,6 +7415,30 @@ func (a *application) rewriteRefOfRenameTableName(parent SQLNode, node *RenameTa
 	}
 	return true
 }
+func (a *application) rewriteRefOfResetVitessPlans(parent SQLNode, node *ResetVitessPlans, replacer replacerFunc) bool {
+	if node == nil {
+		return true
+	}
+	if a.pre != nil {
+		a.cur.replacer = replacer
+		a.cur.parent = parent
+		a.cur.node = node
+		if !a.pre(&a.cur) {
+			return true
+		}
+	}
+	if a.post != nil {
+		if a.pre == nil {
+			a.cur.replacer = replacer
+			a.cur.parent = parent
+			a.cur.node = node
+		}
+		if !a.post(&a.cur) {
+			return false
+		}
+	}
+	return true
+}
 func (a *application) rewriteRefOfRevertMigration(parent SQLNode, node *RevertMigration, replacer replacerFunc) bool {
 	if node == nil {
 		return true
@@ -9621,6 +9831,10 @@ func (a *application) rewriteAggrFunc(parent SQLNode, node AggrFunc, replacer re
 		return a.rewriteRefOfCountStar(parent, node, replacer)
 	case *GroupConcatExpr:
 		return a.rewriteRefOfGroupConcatExpr(parent, node, replacer)
+	case *JSONArrayAgg:
+		return a.rewriteRefOfJSONArrayAgg(parent, node, replacer)
+	case *JSONObjectAgg:
+		return a.rewriteRefOfJSONObjectAgg(parent, node, replacer)
 	case *Max:
 		return a.rewriteRefOfMax(parent, node, replacer)
 	case *Min:
@@ -9761,6 +9975,8 @@ func (a *application) rewriteCallable(parent SQLNode, node Callable, replacer re
 		return a.rewriteRefOfIntervalDateExpr(parent, node, replacer)
 	case *IntervalFuncExpr:
 		return a.rewriteRefOfIntervalFuncExpr(parent, node, replacer)
+	case *JSONArrayAgg:
+		return a.rewriteRefOfJSONArrayAgg(parent, node, replacer)
 	case *JSONArrayExpr:
 		return a.rewriteRefOfJSONArrayExpr(parent, node, replacer)
 	case *JSONAttributesExpr:
@@ -9773,6 +9989,8 @@ func (a *application) rewriteCallable(parent SQLNode, node Callable, replacer re
 		return a.rewriteRefOfJSONExtractExpr(parent, node, replacer)
 	case *JSONKeysExpr:
 		return a.rewriteRefOfJSONKeysExpr(parent, node, replacer)
+	case *JSONObjectAgg:
+		return a.rewriteRefOfJSONObjectAgg(parent, node, replacer)
 	case *JSONObjectExpr:
 		return a.rewriteRefOfJSONObjectExpr(parent, node, replacer)
 	case *JSONOverlapsExpr:
@@ -10049,6 +10267,8 @@ func (a *application) rewriteExpr(parent SQLNode, node Expr, replacer replacerFu
 		return a.rewriteRefOfIntroducerExpr(parent, node, replacer)
 	case *IsExpr:
 		return a.rewriteRefOfIsExpr(parent, node, replacer)
+	case *JSONArrayAgg:
+		return a.rewriteRefOfJSONArrayAgg(parent, node, replacer)
 	case *JSONArrayExpr:
 		return a.rewriteRefOfJSONArrayExpr(parent, node, replacer)
 	case *JSONAttributesExpr:
@@ -10061,6 +10281,8 @@ func (a *application) rewriteExpr(parent SQLNode, node Expr, replacer replacerFu
 		return a.rewriteRefOfJSONExtractExpr(parent, node, replacer)
 	case *JSONKeysExpr:
 		return a.rewriteRefOfJSONKeysExpr(parent, node, replacer)
+	case *JSONObjectAgg:
+		return a.rewriteRefOfJSONObjectAgg(parent, node, replacer)
 	case *JSONObjectExpr:
 		return a.rewriteRefOfJSONObjectExpr(parent, node, replacer)
 	case *JSONOverlapsExpr:
@@ -10335,6 +10557,8 @@ func (a *application) rewriteStatement(parent SQLNode, node Statement, replacer
 		return a.rewriteRefOfRelease(parent, node, replacer)
 	case *RenameTable:
 		return a.rewriteRefOfRenameTable(parent, node, replacer)
+	case *ResetVitessPlans:
+		return a.rewriteRefOfResetVitessPlans(parent, node, replacer)
 	case *RevertMigration:
 		return a.rewriteRefOfRevertMigration(parent, node, replacer)
 	case *Rollback:
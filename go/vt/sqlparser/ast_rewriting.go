@@ -384,17 +384,25 @@ func (er *astRewriter) sysVarRewrite(cursor *Cursor, node *Variable) {
 		sysvars.Charset.Name,
 		sysvars.ClientFoundRows.Name,
 		sysvars.DDLStrategy.Name,
+		sysvars.MaxReplicaLag.Name,
 		sysvars.MigrationContext.Name,
 		sysvars.Names.Name,
 		sysvars.TransactionMode.Name,
 		sysvars.ReadAfterWriteGTID.Name,
 		sysvars.ReadAfterWriteTimeOut.Name,
+		sysvars.ReadOnlyTxOnReplica.Name,
 		sysvars.SessionEnableSystemSettings.Name,
 		sysvars.SessionTrackGTIDs.Name,
 		sysvars.SessionUUID.Name,
 		sysvars.SkipQueryPlanCache.Name,
+		sysvars.SkipReadRetry.Name,
+		sysvars.SnapshotReads.Name,
 		sysvars.Socket.Name,
 		sysvars.SQLSelectLimit.Name,
+		sysvars.StreamChunkRows.Name,
+		sysvars.StreamChunkTimeout.Name,
+		sysvars.TabletTags.Name,
+		sysvars.TenantID.Name,
 		sysvars.Version.Name,
 		sysvars.VersionComment.Name,
 		sysvars.QueryTimeout.Name,
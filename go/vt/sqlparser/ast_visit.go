@@ -232,6 +232,8 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfIntroducerExpr(in, f)
 	case *IsExpr:
 		return VisitRefOfIsExpr(in, f)
+	case *JSONArrayAgg:
+		return VisitRefOfJSONArrayAgg(in, f)
 	case *JSONArrayExpr:
 		return VisitRefOfJSONArrayExpr(in, f)
 	case *JSONAttributesExpr:
@@ -244,6 +246,8 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfJSONExtractExpr(in, f)
 	case *JSONKeysExpr:
 		return VisitRefOfJSONKeysExpr(in, f)
+	case *JSONObjectAgg:
+		return VisitRefOfJSONObjectAgg(in, f)
 	case *JSONObjectExpr:
 		return VisitRefOfJSONObjectExpr(in, f)
 	case *JSONObjectParam:
@@ -302,6 +306,10 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfLiteral(in, f)
 	case *Load:
 		return VisitRefOfLoad(in, f)
+	case *LoadFields:
+		return VisitRefOfLoadFields(in, f)
+	case *LoadLines:
+		return VisitRefOfLoadLines(in, f)
 	case *LocateExpr:
 		return VisitRefOfLocateExpr(in, f)
 	case *LockOption:
@@ -416,6 +424,8 @@ func VisitSQLNode(in SQLNode, f Visit) error {
 		return VisitRefOfRenameTable(in, f)
 	case *RenameTableName:
 		return VisitRefOfRenameTableName(in, f)
+	case *ResetVitessPlans:
+		return VisitRefOfResetVitessPlans(in, f)
 	case *RevertMigration:
 		return VisitRefOfRevertMigration(in, f)
 	case *Rollback:
@@ -2081,6 +2091,21 @@ func VisitRefOfIsExpr(in *IsExpr, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfJSONArrayAgg(in *JSONArrayAgg, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitExpr(in.Arg, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfOverClause(in.OverClause, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfJSONArrayExpr(in *JSONArrayExpr, f Visit) error {
 	if in == nil {
 		return nil
@@ -2180,6 +2205,24 @@ func VisitRefOfJSONKeysExpr(in *JSONKeysExpr, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfJSONObjectAgg(in *JSONObjectAgg, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitExpr(in.Key, f); err != nil {
+		return err
+	}
+	if err := VisitExpr(in.Value, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfOverClause(in.OverClause, f); err != nil {
+		return err
+	}
+	return nil
+}
 func VisitRefOfJSONObjectExpr(in *JSONObjectExpr, f Visit) error {
 	if in == nil {
 		return nil
@@ -2588,6 +2631,57 @@ func VisitRefOfLoad(in *Load, f Visit) error {
 	if cont, err := f(in); err != nil || !cont {
 		return err
 	}
+	if err := VisitTableName(in.Table, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLoadFields(in.Fields, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLoadLines(in.Lines, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.IgnoreLines, f); err != nil {
+		return err
+	}
+	if err := VisitColumns(in.Columns, f); err != nil {
+		return err
+	}
+	if err := VisitUpdateExprs(in.SetExprs, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfLoadFields(in *LoadFields, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.TerminatedBy, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.EnclosedBy, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.EscapedBy, f); err != nil {
+		return err
+	}
+	return nil
+}
+func VisitRefOfLoadLines(in *LoadLines, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.StartingBy, f); err != nil {
+		return err
+	}
+	if err := VisitRefOfLiteral(in.TerminatedBy, f); err != nil {
+		return err
+	}
 	return nil
 }
 func VisitRefOfLocateExpr(in *LocateExpr, f Visit) error {
@@ -3399,6 +3493,15 @@ func VisitRefOfRenameTableName(in *RenameTableName, f Visit) error {
 	}
 	return nil
 }
+func VisitRefOfResetVitessPlans(in *ResetVitessPlans, f Visit) error {
+	if in == nil {
+		return nil
+	}
+	if cont, err := f(in); err != nil || !cont {
+		return err
+	}
+	return nil
+}
 func VisitRefOfRevertMigration(in *RevertMigration, f Visit) error {
 	if in == nil {
 		return nil
@@ -4437,6 +4540,10 @@ func VisitAggrFunc(in AggrFunc, f Visit) error {
 		return VisitRefOfCountStar(in, f)
 	case *GroupConcatExpr:
 		return VisitRefOfGroupConcatExpr(in, f)
+	case *JSONArrayAgg:
+		return VisitRefOfJSONArrayAgg(in, f)
+	case *JSONObjectAgg:
+		return VisitRefOfJSONObjectAgg(in, f)
 	case *Max:
 		return VisitRefOfMax(in, f)
 	case *Min:
@@ -4577,6 +4684,8 @@ func VisitCallable(in Callable, f Visit) error {
 		return VisitRefOfIntervalDateExpr(in, f)
 	case *IntervalFuncExpr:
 		return VisitRefOfIntervalFuncExpr(in, f)
+	case *JSONArrayAgg:
+		return VisitRefOfJSONArrayAgg(in, f)
 	case *JSONArrayExpr:
 		return VisitRefOfJSONArrayExpr(in, f)
 	case *JSONAttributesExpr:
@@ -4589,6 +4698,8 @@ func VisitCallable(in Callable, f Visit) error {
 		return VisitRefOfJSONExtractExpr(in, f)
 	case *JSONKeysExpr:
 		return VisitRefOfJSONKeysExpr(in, f)
+	case *JSONObjectAgg:
+		return VisitRefOfJSONObjectAgg(in, f)
 	case *JSONObjectExpr:
 		return VisitRefOfJSONObjectExpr(in, f)
 	case *JSONOverlapsExpr:
@@ -4865,6 +4976,8 @@ func VisitExpr(in Expr, f Visit) error {
 		return VisitRefOfIntroducerExpr(in, f)
 	case *IsExpr:
 		return VisitRefOfIsExpr(in, f)
+	case *JSONArrayAgg:
+		return VisitRefOfJSONArrayAgg(in, f)
 	case *JSONArrayExpr:
 		return VisitRefOfJSONArrayExpr(in, f)
 	case *JSONAttributesExpr:
@@ -4877,6 +4990,8 @@ func VisitExpr(in Expr, f Visit) error {
 		return VisitRefOfJSONExtractExpr(in, f)
 	case *JSONKeysExpr:
 		return VisitRefOfJSONKeysExpr(in, f)
+	case *JSONObjectAgg:
+		return VisitRefOfJSONObjectAgg(in, f)
 	case *JSONObjectExpr:
 		return VisitRefOfJSONObjectExpr(in, f)
 	case *JSONOverlapsExpr:
@@ -5151,6 +5266,8 @@ func VisitStatement(in Statement, f Visit) error {
 		return VisitRefOfRelease(in, f)
 	case *RenameTable:
 		return VisitRefOfRenameTable(in, f)
+	case *ResetVitessPlans:
+		return VisitRefOfResetVitessPlans(in, f)
 	case *RevertMigration:
 		return VisitRefOfRevertMigration(in, f)
 	case *Rollback:
@@ -1410,7 +1410,7 @@ func (cached *FrameClause) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(24)
+		size += int64(32)
 	}
 	// field Start *vitess.io/vitess/go/vt/sqlparser.FramePoint
 	size += cached.Start.CachedSize(true)
@@ -1960,6 +1960,22 @@ func (cached *IsExpr) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *JSONArrayAgg) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(24)
+	}
+	// field Arg vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.Arg.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field OverClause *vitess.io/vitess/go/vt/sqlparser.OverClause
+	size += cached.OverClause.CachedSize(true)
+	return size
+}
 func (cached *JSONArrayExpr) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -2092,6 +2108,26 @@ func (cached *JSONKeysExpr) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *JSONObjectAgg) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(48)
+	}
+	// field Key vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.Key.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field Value vitess.io/vitess/go/vt/sqlparser.Expr
+	if cc, ok := cached.Value.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field OverClause *vitess.io/vitess/go/vt/sqlparser.OverClause
+	size += cached.OverClause.CachedSize(true)
+	return size
+}
 func (cached *JSONObjectExpr) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -2645,6 +2681,72 @@ func (cached *Literal) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Val)))
 	return size
 }
+func (cached *Load) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(160)
+	}
+	// field File string
+	size += hack.RuntimeAllocSize(int64(len(cached.File)))
+	// field Table vitess.io/vitess/go/vt/sqlparser.TableName
+	size += cached.Table.CachedSize(false)
+	// field Charset vitess.io/vitess/go/vt/sqlparser.ColumnCharset
+	size += cached.Charset.CachedSize(false)
+	// field Fields *vitess.io/vitess/go/vt/sqlparser.LoadFields
+	size += cached.Fields.CachedSize(true)
+	// field Lines *vitess.io/vitess/go/vt/sqlparser.LoadLines
+	size += cached.Lines.CachedSize(true)
+	// field IgnoreLines *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.IgnoreLines.CachedSize(true)
+	// field Columns vitess.io/vitess/go/vt/sqlparser.Columns
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Columns)) * int64(32))
+		for _, elem := range cached.Columns {
+			size += elem.CachedSize(false)
+		}
+	}
+	// field SetExprs vitess.io/vitess/go/vt/sqlparser.UpdateExprs
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.SetExprs)) * int64(8))
+		for _, elem := range cached.SetExprs {
+			size += elem.CachedSize(true)
+		}
+	}
+	return size
+}
+func (cached *LoadFields) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(32)
+	}
+	// field TerminatedBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.TerminatedBy.CachedSize(true)
+	// field EnclosedBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.EnclosedBy.CachedSize(true)
+	// field EscapedBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.EscapedBy.CachedSize(true)
+	return size
+}
+func (cached *LoadLines) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(16)
+	}
+	// field StartingBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.StartingBy.CachedSize(true)
+	// field TerminatedBy *vitess.io/vitess/go/vt/sqlparser.Literal
+	size += cached.TerminatedBy.CachedSize(true)
+	return size
+}
 func (cached *LocateExpr) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
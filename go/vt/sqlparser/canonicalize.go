@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlparser
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Canonicalize rewrites the expressions of a query into a canonical form, so
+// that queries that only differ by the order of commutative expressions or by
+// the format of their constants are formatted the same way:
+//   - the operands of comparisons are ordered, columns first, with the
+//     operator mirrored when needed: 1 < a becomes a > 1;
+//   - the conditions of AND chains are sorted;
+//   - integers lose their leading zeros, and hexadecimal constants are
+//     written in lower case.
+//
+// Only expressions made of columns, constants, arguments and logical
+// operators are reordered, as their order cannot change the result of the
+// query. It should run before normalization, so that the arguments are
+// numbered in the canonical order. It returns whether the query changed.
+func Canonicalize(stmt Statement) bool {
+	switch stmt.(type) {
+	case *Select, *Union, *Update, *Delete:
+	default:
+		return false
+	}
+
+	changed := false
+	_ = SafeRewrite(stmt, nil, func(cursor *Cursor) bool {
+		switch node := cursor.Node().(type) {
+		case *Literal:
+			changed = canonicalizeLiteral(node) || changed
+		case *ComparisonExpr:
+			changed = canonicalizeComparison(node) || changed
+		case *AndExpr:
+			if _, inChain := cursor.Parent().(*AndExpr); inChain {
+				// The whole chain is sorted from its top.
+				return true
+			}
+			if and, ok := canonicalizeAndChain(node); ok {
+				cursor.Replace(and)
+				changed = true
+			}
+		}
+		return true
+	})
+	return changed
+}
+
+func canonicalizeLiteral(lit *Literal) bool {
+	val := lit.Val
+	switch lit.Type {
+	case IntVal:
+		if len(val) > 1 && val[0] == '0' {
+			if u, err := strconv.ParseUint(val, 10, 64); err == nil {
+				val = strconv.FormatUint(u, 10)
+			}
+		}
+	case HexNum, HexVal:
+		val = strings.ToLower(val)
+	}
+	if val == lit.Val {
+		return false
+	}
+	lit.Val = val
+	return true
+}
+
+var mirroredComparisons = map[ComparisonExprOperator]ComparisonExprOperator{
+	EqualOp:         EqualOp,
+	NotEqualOp:      NotEqualOp,
+	NullSafeEqualOp: NullSafeEqualOp,
+	LessThanOp:      GreaterThanOp,
+	GreaterThanOp:   LessThanOp,
+	LessEqualOp:     GreaterEqualOp,
+	GreaterEqualOp:  LessEqualOp,
+}
+
+func canonicalizeComparison(cmp *ComparisonExpr) bool {
+	mirrored, ok := mirroredComparisons[cmp.Operator]
+	if !ok || !canReorder(cmp.Left) || !canReorder(cmp.Right) {
+		return false
+	}
+	if compareOperands(cmp.Left, cmp.Right) <= 0 {
+		return false
+	}
+	cmp.Left, cmp.Right = cmp.Right, cmp.Left
+	cmp.Operator = mirrored
+	return true
+}
+
+// compareOperands orders the operands of a comparison: columns, then the
+// other expressions, then constants and arguments.
+func compareOperands(a, b Expr) int {
+	rank := func(e Expr) int {
+		switch e.(type) {
+		case *ColName:
+			return 0
+		case *Literal, *Argument, *NullVal, BoolVal:
+			return 2
+		}
+		return 1
+	}
+	if c := rank(a) - rank(b); c != 0 {
+		return c
+	}
+	return strings.Compare(String(a), String(b))
+}
+
+func canonicalizeAndChain(and *AndExpr) (Expr, bool) {
+	conds := SplitAndExpression(nil, and)
+	for _, cond := range conds {
+		if !canReorder(cond) {
+			return nil, false
+		}
+	}
+	keys := make([]string, len(conds))
+	order := make([]int, len(conds))
+	for i, cond := range conds {
+		keys[i] = String(cond)
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return strings.Compare(keys[a], keys[b])
+	})
+	if slices.IsSorted(order) {
+		return nil, false
+	}
+
+	sorted := make([]Expr, len(conds))
+	for i, j := range order {
+		sorted[i] = conds[j]
+	}
+	result := sorted[0]
+	for _, cond := range sorted[1:] {
+		result = &AndExpr{Left: result, Right: cond}
+	}
+	return result, true
+}
+
+// canReorder returns whether an expression can be evaluated in any order
+// with other expressions: it has no side effects, and no subqueries.
+func canReorder(e Expr) bool {
+	ok := true
+	_ = Walk(func(node SQLNode) (bool, error) {
+		switch node.(type) {
+		case *ColName, IdentifierCI, IdentifierCS, TableName,
+			*Literal, *Argument, *NullVal, BoolVal, ValTuple,
+			*ComparisonExpr, *BetweenExpr, *IsExpr, *NotExpr, *AndExpr, *OrExpr:
+			return true, nil
+		}
+		ok = false
+		return false, nil
+	}, e)
+	return ok
+}
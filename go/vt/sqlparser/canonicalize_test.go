@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out string
+	}{{
+		in:  "select * from t where 1 = a",
+		out: "select * from t where a = 1",
+	}, {
+		in:  "select * from t where 5 < a and b >= 3",
+		out: "select * from t where a > 5 and b >= 3",
+	}, {
+		in:  "select * from t where b = 2 and a = 1 and c is null",
+		out: "select * from t where a = 1 and b = 2 and c is null",
+	}, {
+		in:  "select * from t1 join t2 on t2.id = t1.id where t2.x = :x and t1.y = :y",
+		out: "select * from t1 join t2 on t1.id = t2.id where t1.y = :y and t2.x = :x",
+	}, {
+		in:  "select * from t where (b = 2 or a = 1) and a = 3",
+		out: "select * from t where a = 3 and (b = 2 or a = 1)",
+	}, {
+		in:  "select * from t where id = 007 and h = 0xAB and x = X'CD'",
+		out: "select * from t where h = 0xab and id = 7 and x = X'cd'",
+	}, {
+		in:  "update t set a = 1 where c = 3 and b = 2",
+		out: "update t set a = 1 where b = 2 and c = 3",
+	}, {
+		in:  "delete from t where 2 = b",
+		out: "delete from t where b = 2",
+	}, {
+		// functions and subqueries are not reordered.
+		in:  "select * from t where b = rand() and a = 1",
+		out: "select * from t where b = rand() and a = 1",
+	}, {
+		in:  "select * from t where b in (select id from u) and a = 1",
+		out: "select * from t where b in (select id from u) and a = 1",
+	}, {
+		in:  "select * from t where 1 = f(a)",
+		out: "select * from t where 1 = f(a)",
+	}, {
+		in:  "select * from t where a like 'x%' and 'y' = b",
+		out: "select * from t where a like 'x%' and b = 'y'",
+	}, {
+		in:  "select * from t where a = 1 and b = 2",
+		out: "select * from t where a = 1 and b = 2",
+	}, {
+		in:  "insert into t values (1 = a)",
+		out: "insert into t values (1 = a)",
+	}}
+	parser := NewTestParser()
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			stmt, err := parser.Parse(tc.in)
+			require.NoError(t, err)
+			changed := Canonicalize(stmt)
+			assert.Equal(t, tc.out, String(stmt))
+			assert.Equal(t, tc.in != tc.out, changed)
+
+			// The canonical form is stable.
+			stmt, err = parser.Parse(String(stmt))
+			require.NoError(t, err)
+			assert.False(t, Canonicalize(stmt))
+		})
+	}
+}
+
+func TestCanonicalizeEquivalentQueries(t *testing.T) {
+	queries := []string{
+		"select * from t where a = 1 and b = 'x' and c > 3",
+		"select * from t where c > 3 and 'x' = b and 1 = a",
+		"select * from t where 3 < c and a = 1 and b = 'x'",
+	}
+	parser := NewTestParser()
+	var normalized []string
+	for _, query := range queries {
+		stmt, reservedVars, err := parser.Parse2(query)
+		require.NoError(t, err)
+		Canonicalize(stmt)
+		err = Normalize(stmt, NewReservedVars("vtg", reservedVars), map[string]*querypb.BindVariable{})
+		require.NoError(t, err)
+		normalized = append(normalized, String(stmt))
+	}
+	assert.Equal(t, "select * from t where a = :a /* INT64 */ and b = :b /* VARCHAR */ and c > :c /* INT64 */", normalized[0])
+	for _, n := range normalized[1:] {
+		assert.Equal(t, normalized[0], n)
+	}
+}
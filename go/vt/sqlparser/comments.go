@@ -58,6 +58,16 @@ const (
 	// DirectivePriority specifies the priority of a workload. It should be an integer between 0 and MaxPriorityValue,
 	// where 0 is the highest priority, and MaxPriorityValue is the lowest one.
 	DirectivePriority = "PRIORITY"
+	// DirectiveStreamChunkRows sets the number of rows per chunk of a streaming query.
+	DirectiveStreamChunkRows = "STREAM_CHUNK_ROWS"
+	// DirectiveStreamChunkTimeout sets the maximum time in milliseconds between two chunks of a streaming query.
+	DirectiveStreamChunkTimeout = "STREAM_CHUNK_TIMEOUT_MS"
+	// DirectiveMaxReplicaLag sets the maximum replication lag in seconds of the replicas that can serve a query.
+	DirectiveMaxReplicaLag = "MAX_REPLICA_LAG"
+	// DirectiveInsertBatchRows sets the maximum number of rows of an insert that are inserted into a shard with a single query.
+	DirectiveInsertBatchRows = "INSERT_BATCH_ROWS"
+	// DirectiveReadFromPrimary keeps a read on the primary when read/write splitting would route it to the replicas.
+	DirectiveReadFromPrimary = "READ_FROM_PRIMARY"
 
 	// MaxPriorityValue specifies the maximum value allowed for the priority query directive. Valid priority values are
 	// between zero and MaxPriorityValue.
@@ -549,6 +559,12 @@ func IgnoreMaxMaxMemoryRowsDirective(stmt Statement) bool {
 	return checkDirective(stmt, DirectiveIgnoreMaxMemoryRows)
 }
 
+// ReadFromPrimaryDirective returns true if the read must not be routed to the
+// replicas by read/write splitting.
+func ReadFromPrimaryDirective(stmt Statement) bool {
+	return checkDirective(stmt, DirectiveReadFromPrimary)
+}
+
 // AllowScatterDirective returns true if the allow scatter override is set to true
 func AllowScatterDirective(stmt Statement) bool {
 	return checkDirective(stmt, DirectiveAllowScatter)
@@ -629,6 +645,43 @@ func Consolidator(stmt Statement) querypb.ExecuteOptions_Consolidator {
 	return querypb.ExecuteOptions_CONSOLIDATOR_UNSPECIFIED
 }
 
+// StreamChunkOptions returns the number of rows per chunk and the timeout in
+// milliseconds between two chunks that the statement sets with the
+// DirectiveStreamChunkRows and DirectiveStreamChunkTimeout directives. Values
+// that are not set, or are not positive integers, are returned as 0.
+func StreamChunkOptions(stmt Statement) (rows int64, timeoutMs int64) {
+	cmt, ok := stmt.(Commented)
+	if !ok {
+		return 0, 0
+	}
+	directives := cmt.GetParsedComments().Directives()
+	return positiveIntDirective(directives, DirectiveStreamChunkRows), positiveIntDirective(directives, DirectiveStreamChunkTimeout)
+}
+
+// MaxReplicaLag returns the maximum replication lag in seconds of the
+// replicas that can serve the statement, as set with the
+// DirectiveMaxReplicaLag directive. It returns 0 if the directive is not set
+// or is not a positive integer.
+func MaxReplicaLag(stmt Statement) int64 {
+	cmt, ok := stmt.(Commented)
+	if !ok {
+		return 0
+	}
+	return positiveIntDirective(cmt.GetParsedComments().Directives(), DirectiveMaxReplicaLag)
+}
+
+func positiveIntDirective(directives *CommentDirectives, key string) int64 {
+	val, ok := directives.GetString(key, "")
+	if !ok {
+		return 0
+	}
+	intVal, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || intVal < 0 {
+		return 0
+	}
+	return intVal
+}
+
 // GetWorkloadNameFromStatement gets the workload name from the provided Statement, using workloadLabel as the name of
 // the query directive that specifies it.
 func GetWorkloadNameFromStatement(statement Statement) string {
@@ -480,6 +480,76 @@ func TestConsolidator(t *testing.T) {
 	}
 }
 
+func TestStreamChunkOptions(t *testing.T) {
+	testCases := []struct {
+		query     string
+		rows      int64
+		timeoutMs int64
+	}{
+		{"select * from users", 0, 0},
+		{"select /*vt+ STREAM_CHUNK_ROWS=1000 */ * from users", 1000, 0},
+		{"select /*vt+ STREAM_CHUNK_TIMEOUT_MS=500 */ * from users", 0, 500},
+		{"select /*vt+ STREAM_CHUNK_ROWS=1000 STREAM_CHUNK_TIMEOUT_MS=500 */ * from users", 1000, 500},
+		{"select /*vt+ STREAM_CHUNK_ROWS=-1 STREAM_CHUNK_TIMEOUT_MS=abc */ * from users", 0, 0},
+		{"show /*vt+ STREAM_CHUNK_ROWS=1000 */ create table users", 0, 0},
+	}
+
+	parser := NewTestParser()
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			rows, timeoutMs := StreamChunkOptions(stmt)
+			assert.Equal(t, test.rows, rows)
+			assert.Equal(t, test.timeoutMs, timeoutMs)
+		})
+	}
+}
+
+func TestMaxReplicaLag(t *testing.T) {
+	testCases := []struct {
+		query string
+		lag   int64
+	}{
+		{"select * from users", 0},
+		{"select /*vt+ MAX_REPLICA_LAG=10 */ * from users", 10},
+		{"select /*vt+ MAX_REPLICA_LAG=-1 */ * from users", 0},
+		{"select /*vt+ MAX_REPLICA_LAG=abc */ * from users", 0},
+		{"show /*vt+ MAX_REPLICA_LAG=10 */ create table users", 0},
+	}
+
+	parser := NewTestParser()
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.lag, MaxReplicaLag(stmt))
+		})
+	}
+}
+
+func TestReadFromPrimaryDirective(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected bool
+	}{
+		{"select * from users", false},
+		{"select /*vt+ READ_FROM_PRIMARY */ * from users", true},
+		{"select /*vt+ READ_FROM_PRIMARY=1 */ * from users", true},
+		{"select /*vt+ READ_FROM_PRIMARY=0 */ * from users", false},
+		{"(select /*vt+ READ_FROM_PRIMARY */ * from users) union (select * from users)", true},
+	}
+
+	parser := NewTestParser()
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := parser.Parse(test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, ReadFromPrimaryDirective(stmt))
+		})
+	}
+}
+
 func TestGetPriorityFromStatement(t *testing.T) {
 	testCases := []struct {
 		query            string
@@ -311,6 +311,7 @@ const (
 	VGtidExecGlobalStr         = " global vgtid_executed"
 	KeyspaceStr                = " keyspaces"
 	VitessMigrationsStr        = " vitess_migrations"
+	VitessPlansStr             = " vitess_plans"
 	VitessReplicationStatusStr = " vitess_replication_status"
 	VitessShardsStr            = " vitess_shards"
 	VitessTabletsStr           = " vitess_tablets"
@@ -343,6 +344,12 @@ const (
 	ExprPrecedingStr      = "preceding"
 	ExprFollowingStr      = "following"
 
+	// FrameExclusionType strings
+	ExcludeCurrentRowStr = "exclude current row"
+	ExcludeGroupStr      = "exclude group"
+	ExcludeTiesStr       = "exclude ties"
+	ExcludeNoOthersStr   = "exclude no others"
+
 	// ArgumentLessWindowExprType strings
 	CumeDistExprStr    = "cume_dist"
 	DenseRankExprStr   = "dense_rank"
@@ -561,6 +568,15 @@ const (
 	ExprFollowingType
 )
 
+// Constants for Enum Type - FrameExclusionType
+const (
+	NoFrameExclusionType FrameExclusionType = iota
+	ExcludeCurrentRowType
+	ExcludeGroupType
+	ExcludeTiesType
+	ExcludeNoOthersType
+)
+
 // Constants for Enum Type - ArgumentLessWindowExprType
 const (
 	CumeDistExprType ArgumentLessWindowExprType = iota
@@ -889,6 +905,7 @@ const (
 	VariableSession
 	VGtidExecGlobal
 	VitessMigrations
+	VitessPlans
 	VitessReplicationStatus
 	VitessShards
 	VitessTablets
@@ -933,6 +950,7 @@ const (
 	UnthrottleAllMigrationType
 	ForceCutOverMigrationType
 	ForceCutOverAllMigrationType
+	CutOverMigrationType
 )
 
 // ColumnStorage constants
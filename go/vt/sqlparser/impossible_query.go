@@ -40,6 +40,9 @@ func FormatImpossibleQuery(buf *TrackedBuffer, node SQLNode) {
 		if node.GroupBy != nil {
 			node.GroupBy.Format(buf)
 		}
+		if node.Windows != nil {
+			buf.Myprintf(" %v", node.Windows)
+		}
 	case *Union:
 		if requiresParen(node.Left) {
 			buf.astPrintf(node, "(%v)", node.Left)
@@ -204,6 +204,7 @@ var keywords = []keyword{
 	{"current_timestamp", CURRENT_TIMESTAMP},
 	{"current_user", CURRENT_USER},
 	{"cursor", UNUSED},
+	{"cutover", CUTOVER},
 	{"data", DATA},
 	{"database", DATABASE},
 	{"databases", DATABASES},
@@ -261,6 +262,7 @@ var keywords = []keyword{
 	{"escaped", ESCAPED},
 	{"event", EVENT},
 	{"exchange", EXCHANGE},
+	{"exclude", EXCLUDE},
 	{"exclusive", EXCLUSIVE},
 	{"execute", EXECUTE},
 	{"exists", EXISTS},
@@ -326,7 +328,7 @@ var keywords = []keyword{
 	{"in", IN},
 	{"index", INDEX},
 	{"indexes", INDEXES},
-	{"infile", UNUSED},
+	{"infile", INFILE},
 	{"inout", UNUSED},
 	{"inner", INNER},
 	{"inplace", INPLACE},
@@ -355,6 +357,7 @@ var keywords = []keyword{
 	{"join", JOIN},
 	{"json", JSON},
 	{"json_array", JSON_ARRAY},
+	{"json_arrayagg", JSON_ARRAYAGG},
 	{"json_array_append", JSON_ARRAY_APPEND},
 	{"json_array_insert", JSON_ARRAY_INSERT},
 	{"json_contains", JSON_CONTAINS},
@@ -368,6 +371,7 @@ var keywords = []keyword{
 	{"json_merge_patch", JSON_MERGE_PATCH},
 	{"json_merge_preserve", JSON_MERGE_PRESERVE},
 	{"json_object", JSON_OBJECT},
+	{"json_objectagg", JSON_OBJECTAGG},
 	{"json_overlaps", JSON_OVERLAPS},
 	{"json_pretty", JSON_PRETTY},
 	{"json_remove", JSON_REMOVE},
@@ -481,6 +485,7 @@ var keywords = []keyword{
 	{"or", OR},
 	{"order", ORDER},
 	{"ordinality", ORDINALITY},
+	{"others", OTHERS},
 	{"out", UNUSED},
 	{"outer", OUTER},
 	{"outfile", OUTFILE},
@@ -542,6 +547,7 @@ var keywords = []keyword{
 	{"repeatable", REPEATABLE},
 	{"replace", REPLACE},
 	{"require", UNUSED},
+	{"reset", RESET},
 	{"resignal", UNUSED},
 	{"respect", RESPECT},
 	{"restrict", RESTRICT},
@@ -697,6 +703,7 @@ var keywords = []keyword{
 	{"than", THAN},
 	{"then", THEN},
 	{"throttle", THROTTLE},
+	{"ties", TIES},
 	{"time", TIME},
 	{"timestamp", TIMESTAMP},
 	{"timestampadd", TIMESTAMPADD},
@@ -756,6 +763,7 @@ var keywords = []keyword{
 	{"vitess_metadata", VITESS_METADATA},
 	{"vitess_migration", VITESS_MIGRATION},
 	{"vitess_migrations", VITESS_MIGRATIONS},
+	{"vitess_plans", VITESS_PLANS},
 	{"vitess_replication_status", VITESS_REPLICATION_STATUS},
 	{"vitess_shards", VITESS_SHARDS},
 	{"vitess_tablets", VITESS_TABLETS},
@@ -65,7 +65,7 @@ func (nz *normalizer) walkStatementUp(cursor *Cursor) bool {
 		return false
 	}
 	node, isLiteral := cursor.Node().(*Literal)
-	if !isLiteral {
+	if !isLiteral || mustStayLiteral(node, cursor.Parent()) {
 		return true
 	}
 	nz.convertLiteral(node, cursor)
@@ -143,7 +143,7 @@ func (nz *normalizer) walkUpSelect(cursor *Cursor) bool {
 		return false
 	}
 	node, isLiteral := cursor.Node().(*Literal)
-	if !isLiteral {
+	if !isLiteral || mustStayLiteral(node, cursor.Parent()) {
 		return true
 	}
 	parent := cursor.Parent()
@@ -158,6 +158,21 @@ func (nz *normalizer) walkUpSelect(cursor *Cursor) bool {
 	return nz.err == nil // only continue if we haven't found any errors
 }
 
+// mustStayLiteral returns true for the literals that MySQL only accepts as
+// string literals, such as the paths and the ON EMPTY/ON ERROR defaults of
+// JSON_TABLE and JSON_VALUE.
+func mustStayLiteral(node *Literal, parent SQLNode) bool {
+	switch parent := parent.(type) {
+	case *JSONTableExpr:
+		return parent.Filter == node
+	case *JSONValueExpr:
+		return parent.Path == node
+	case *JtOnResponse:
+		return true
+	}
+	return false
+}
+
 func validateLiteral(node *Literal) error {
 	switch node.Type {
 	case DateVal:
@@ -42,6 +42,21 @@ func TestNormalize(t *testing.T) {
 		outstmt string
 		outbv   map[string]*querypb.BindVariable
 	}{{
+		// json_table paths and defaults are not normalized
+		in:      "select jt.c1 from t, json_table(t.doc, '$[*]' columns(c1 int path '$.c1' default '0' on empty)) as jt where t.id = 5",
+		outstmt: "select jt.c1 from t, json_table(t.doc, '$[*]' columns(\n\tc1 int path '$.c1' default '0' on empty \n\t)\n) as jt where t.id = :t_id /* INT64 */",
+		outbv: map[string]*querypb.BindVariable{
+			"t_id": sqltypes.Int64BindVariable(5),
+		},
+	}, {
+		// json_value paths are not normalized, but other json function paths are
+		in:      "select json_value(doc, '$.a' default 'x' on empty) from t where json_extract(doc, '$.b') = 1",
+		outstmt: "select json_value(doc, '$.a' default 'x' on empty) from t where json_extract(doc, :bv1 /* VARCHAR */) = :bv2 /* INT64 */",
+		outbv: map[string]*querypb.BindVariable{
+			"bv1": sqltypes.StringBindVariable("$.b"),
+			"bv2": sqltypes.Int64BindVariable(1),
+		},
+	}, {
 		// str val
 		in:      "select * from t where foobar = 'aa'",
 		outstmt: "select * from t where foobar = :foobar /* VARCHAR */",
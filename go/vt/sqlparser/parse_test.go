@@ -2158,6 +2158,8 @@ var (
 		input: "purge binary logs to 'x'",
 	}, {
 		input: "purge binary logs before '2020-02-02 20:20:20'",
+	}, {
+		input: "reset vitess_plans",
 	}, {
 		input:  "show character set",
 		output: "show charset",
@@ -2366,6 +2368,8 @@ var (
 		input: "show vitess_replication_status",
 	}, {
 		input: "show vitess_replication_status like '%'",
+	}, {
+		input: "show vitess_replication_status where ReplicationLag > 10",
 	}, {
 		input: "show vitess_shards",
 	}, {
@@ -2378,6 +2382,12 @@ var (
 		input: "show vitess_tablets where hostname = 'some-tablet'",
 	}, {
 		input: "show vitess_targets",
+	}, {
+		input: "show vitess_plans",
+	}, {
+		input: "show vitess_plans like '%user%'",
+	}, {
+		input: "show vitess_plans where ExecCount > 10",
 	}, {
 		input: "show vschema tables",
 	}, {
@@ -2430,6 +2440,11 @@ var (
 	}, {
 		input:  "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' FORCE_CUTOVER",
 		output: "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' force_cutover",
+	}, {
+		input: "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' cutover",
+	}, {
+		input:  "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' CUTOVER",
+		output: "alter vitess_migration '9748c3b7_7fdb_11eb_ac2c_f875a4d24e90' cutover",
 	}, {
 		input: "alter vitess_migration cancel all",
 	}, {
@@ -2471,6 +2486,10 @@ var (
 		// Making sure "force_cutover" is not a keyword
 		input:  "select force_cutover from t",
 		output: "select `force_cutover` from t",
+	}, {
+		// Making sure "cutover" is not a reserved keyword
+		input:  "select cutover from t",
+		output: "select `cutover` from t",
 	}, {
 		input:  "use db",
 		output: "use db",
@@ -3570,6 +3589,24 @@ var (
 	}, {
 		input:  "SELECT time, subject, val, FIRST_VALUE(val)  OVER w AS 'first', LAST_VALUE(val) OVER w AS 'last', NTH_VALUE(val, 2) OVER w AS 'second', NTH_VALUE(val, 4) OVER w AS 'fourth' FROM observations WINDOW w AS (PARTITION BY subject ORDER BY time ASC RANGE BETWEEN 10 PRECEDING AND 10 FOLLOWING);",
 		output: "select `time`, subject, val, first_value(val) over w as `first`, last_value(val) over w as `last`, nth_value(val, 2) over w as `second`, nth_value(val, 4) over w as fourth from observations window w AS ( partition by subject order by `time` asc range between 10 preceding and 10 following)",
+	}, {
+		input:  "SELECT val, SUM(val) OVER (ORDER BY time ROWS BETWEEN 1 PRECEDING AND 1 FOLLOWING EXCLUDE CURRENT ROW) FROM observations",
+		output: "select val, sum(val) over ( order by `time` asc rows between 1 preceding and 1 following exclude current row) from observations",
+	}, {
+		input:  "SELECT val, SUM(val) OVER (ORDER BY time RANGE UNBOUNDED PRECEDING EXCLUDE GROUP) FROM observations",
+		output: "select val, sum(val) over ( order by `time` asc range unbounded preceding exclude group) from observations",
+	}, {
+		input:  "SELECT val, SUM(val) OVER (ORDER BY time RANGE BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING EXCLUDE TIES) FROM observations",
+		output: "select val, sum(val) over ( order by `time` asc range between current row and unbounded following exclude ties) from observations",
+	}, {
+		input:  "SELECT val, SUM(val) OVER (ORDER BY time ROWS 2 PRECEDING EXCLUDE NO OTHERS) FROM observations",
+		output: "select val, sum(val) over ( order by `time` asc rows 2 preceding exclude no others) from observations",
+	}, {
+		input:  "SELECT val, SUM(val) OVER (ORDER BY time ROWS BETWEEN ? PRECEDING AND :n FOLLOWING) FROM observations",
+		output: "select val, sum(val) over ( order by `time` asc rows between :v1 preceding and :n following) from observations",
+	}, {
+		input:  "SELECT val, ROW_NUMBER() OVER w2 FROM observations WINDOW w1 AS (PARTITION BY subject), w2 AS (w1 ORDER BY time ROWS UNBOUNDED PRECEDING)",
+		output: "select val, row_number() over w2 from observations window w1 AS ( partition by subject), w2 AS ( w1 order by `time` asc rows unbounded preceding)",
 	}, {
 		input:  "SELECT ExtractValue('<a><b/></a>', '/a/b')",
 		output: "select extractvalue('<a><b/></a>', '/a/b') from dual",
@@ -3717,6 +3754,18 @@ var (
 	}, {
 		input:  "SELECT time, subject, BIT_AND(val) OVER (PARTITION BY time, subject) AS window_result FROM observations GROUP BY time, subject;",
 		output: "select `time`, subject, bit_and(val) over ( partition by `time`, subject) as window_result from observations group by `time`, subject",
+	}, {
+		input:  "SELECT time, subject, JSON_ARRAYAGG(val) OVER (PARTITION BY time, subject) AS window_result FROM observations GROUP BY time, subject;",
+		output: "select `time`, subject, json_arrayagg(val) over ( partition by `time`, subject) as window_result from observations group by `time`, subject",
+	}, {
+		input:  "SELECT time, JSON_OBJECTAGG(subject, val) OVER w AS window_result FROM observations WINDOW w AS (PARTITION BY time)",
+		output: "select `time`, json_objectagg(subject, val) over w as window_result from observations window w AS ( partition by `time`)",
+	}, {
+		input:  "select json_arrayagg(a), json_objectagg(a, b) from t group by c",
+		output: "select json_arrayagg(a), json_objectagg(a, b) from t group by c",
+	}, {
+		input:  "select json_arrayagg, json_objectagg from t",
+		output: "select `json_arrayagg`, `json_objectagg` from t",
 	}, {
 		input:  "SELECT time, subject, BIT_OR(val) OVER (PARTITION BY time, subject) AS window_result FROM observations GROUP BY time, subject;",
 		output: "select `time`, subject, bit_or(val) over ( partition by `time`, subject) as window_result from observations group by `time`, subject",
@@ -4652,7 +4701,7 @@ func TestLoadData(t *testing.T) {
 		"load data from s3 'x.txt'",
 		"load data from s3 manifest 'x.txt'",
 		"load data from s3 file 'x.txt'",
-		"load data infile 'x.txt' into table 'c'",
+		"load data infile 'x.txt' into table c",
 		"load data from s3 'x.txt' into table x"}
 
 	parser := NewTestParser()
@@ -4660,6 +4709,34 @@ func TestLoadData(t *testing.T) {
 		_, err := parser.Parse(tcase)
 		require.NoError(t, err)
 	}
+
+	testcases := []struct {
+		input, output string
+	}{{
+		input: "load data local infile '/tmp/x.csv' into table t",
+	}, {
+		input: "load data infile 'x.txt' replace into table ks.t character set utf8mb4",
+	}, {
+		input:  "LOAD DATA LOCAL INFILE 'x.csv' IGNORE INTO TABLE t COLUMNS TERMINATED BY ',' OPTIONALLY ENCLOSED BY '\"' ESCAPED BY '\\\\' LINES STARTING BY 'x' TERMINATED BY '\\r\\n' IGNORE 1 ROWS (a, b, c)",
+		output: "load data local infile 'x.csv' ignore into table t fields terminated by ',' optionally enclosed by '\\\"' escaped by '\\\\' lines starting by 'x' terminated by '\\r\\n' ignore 1 lines (a, b, c)",
+	}, {
+		input:  "load data local infile 'x.csv' into table t fields escaped by '' enclosed by '\\'' terminated by '\\t' lines terminated by '\\n' (a, b) set c = now(), d = a + b",
+		output: "load data local infile 'x.csv' into table t fields terminated by '\\t' enclosed by '\\'' escaped by '' lines terminated by '\\n' (a, b) set c = now(), d = a + b",
+	}}
+	for _, tcase := range testcases {
+		t.Run(tcase.input, func(t *testing.T) {
+			if tcase.output == "" {
+				tcase.output = tcase.input
+			}
+			tree, err := parser.Parse(tcase.input)
+			require.NoError(t, err)
+			require.IsType(t, &Load{}, tree)
+			require.Equal(t, tcase.output, String(tree))
+		})
+	}
+
+	_, err := parser.Parse("load data local infile 'x.txt' into table 'c'")
+	require.Error(t, err)
 }
 
 func TestCreateTable(t *testing.T) {
@@ -6466,3 +6543,7 @@ func parsePartial(r *bufio.Reader, readType []string, lineno int, fileName strin
 func locateFile(name string) string {
 	return "testdata/" + name
 }
+
+func TestErrorCases(t *testing.T) {
+	testFile(t, "error_cases.txt", makeTestOutput(t))
+}
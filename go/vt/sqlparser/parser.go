@@ -76,13 +76,29 @@ func yyParsePooled(yylex yyLexer) int {
 // is partially parsed but still contains a syntax error, the
 // error is ignored and the DDL is returned anyway.
 func (p *Parser) Parse2(sql string) (Statement, BindVars, error) {
+	return p.parse(p.NewStringTokenizer(sql))
+}
+
+// Parse2WithPositions parses the SQL like Parse2, and also returns the
+// positions of the expressions of the Statement in the SQL, which let errors
+// point at the part of the query that caused them.
+func (p *Parser) Parse2WithPositions(sql string) (Statement, BindVars, Positions, error) {
 	tokenizer := p.NewStringTokenizer(sql)
+	tokenizer.positions = make(Positions)
+	stmt, bindVars, err := p.parse(tokenizer)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return stmt, bindVars, tokenizer.positions, nil
+}
+
+func (p *Parser) parse(tokenizer *Tokenizer) (Statement, BindVars, error) {
 	if yyParsePooled(tokenizer) != 0 {
 		if tokenizer.partialDDL != nil {
 			if typ, val := tokenizer.Scan(); typ != 0 {
 				return nil, nil, fmt.Errorf("extra characters encountered after end of DDL: '%s'", val)
 			}
-			log.Warningf("ignoring error parsing DDL '%s': %v", sql, tokenizer.LastError)
+			log.Warningf("ignoring error parsing DDL '%s': %v", tokenizer.buf, tokenizer.LastError)
 			switch x := tokenizer.partialDDL.(type) {
 			case DBDDLStatement:
 				x.SetFullyParsed(false)
@@ -157,7 +173,10 @@ func (p *Parser) Parse(sql string) (Statement, error) {
 // ParseStrictDDL is the same as Parse except it errors on
 // partially parsed DDL statements.
 func (p *Parser) ParseStrictDDL(sql string) (Statement, error) {
-	tokenizer := p.NewStringTokenizer(sql)
+	return parseStrictDDL(p.NewStringTokenizer(sql))
+}
+
+func parseStrictDDL(tokenizer *Tokenizer) (Statement, error) {
 	if yyParsePooled(tokenizer) != 0 {
 		return nil, tokenizer.LastError
 	}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlparser
+
+import "reflect"
+
+// Positions maps the nodes of a parsed statement to the offset of their first
+// character in the query text. The parser records the positions of the
+// expressions that errors usually point at: column names, function calls and
+// subqueries. The nodes are compared by identity, so the positions survive the
+// rewrites that keep these nodes, and rewriters that replace them use Transfer.
+// Only the nodes that are pointers have a position.
+// A nil Positions is valid and knows no position.
+type Positions map[SQLNode]int
+
+// Of returns the 0-based offset of the node in the query text.
+func (p Positions) Of(node SQLNode) (int, bool) {
+	if len(p) == 0 || !isPointer(node) {
+		return 0, false
+	}
+	pos, ok := p[node]
+	return pos, ok
+}
+
+// Find returns the offset of the node in the query text or, if the position of
+// the node is unknown, the smallest offset of the expressions it contains.
+func (p Positions) Find(node SQLNode) (int, bool) {
+	if pos, ok := p.Of(node); ok || len(p) == 0 || node == nil {
+		return pos, ok
+	}
+	found, first := false, 0
+	_ = Walk(func(child SQLNode) (bool, error) {
+		if pos, ok := p.Of(child); ok {
+			if !found || pos < first {
+				found, first = true, pos
+			}
+			// The position of a node comes before the positions of its children.
+			return false, nil
+		}
+		return true, nil
+	}, node)
+	return first, found
+}
+
+// Transfer records the position of from, if known, as the position of to.
+// Rewriters use it when they replace a node, so that errors about the new node
+// still point at the original query text.
+func (p Positions) Transfer(from, to SQLNode) {
+	pos, ok := p.Of(from)
+	if !ok || !isPointer(to) {
+		return
+	}
+	p[to] = pos
+}
+
+// Offset moves all the positions by n characters, for a statement that was
+// parsed out of a larger query text.
+func (p Positions) Offset(n int) {
+	if n == 0 {
+		return
+	}
+	for node := range p {
+		p[node] += n
+	}
+}
+
+func isPointer(node SQLNode) bool {
+	return node != nil && reflect.ValueOf(node).Kind() == reflect.Pointer
+}
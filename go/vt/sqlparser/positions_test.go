@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse2WithPositions(t *testing.T) {
+	parser := NewTestParser()
+	tcases := []struct {
+		sql  string
+		node string
+		pos  int
+	}{{
+		sql:  "select a, b from t",
+		node: "a",
+		pos:  7,
+	}, {
+		sql:  "select a,   b from t",
+		node: "b",
+		pos:  12,
+	}, {
+		sql:  "select t.col from t where x = 1",
+		node: "t.col",
+		pos:  7,
+	}, {
+		sql:  "select 1 from t where x =  1",
+		node: "x",
+		pos:  22,
+	}, {
+		sql:  "select count(*), max(id) from t",
+		node: "max(id)",
+		pos:  17,
+	}, {
+		sql:  "select unknown_fn(a, b) from t",
+		node: "unknown_fn(a, b)",
+		pos:  7,
+	}, {
+		sql:  "select a from t where id in (select id from u)",
+		node: "(select id from u)",
+		pos:  28,
+	}, {
+		sql:  "select /*! b */ from t",
+		node: "b",
+		pos:  7,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.sql, func(t *testing.T) {
+			stmt, _, positions, err := parser.Parse2WithPositions(tcase.sql)
+			require.NoError(t, err)
+
+			found := false
+			_ = Walk(func(node SQLNode) (bool, error) {
+				if expr, ok := node.(Expr); ok && !found && String(expr) == tcase.node {
+					pos, ok := positions.Of(expr)
+					require.True(t, ok, "no position for %s", tcase.node)
+					assert.Equal(t, tcase.pos, pos)
+					found = true
+				}
+				return true, nil
+			}, stmt)
+			require.True(t, found, "%s not found", tcase.node)
+		})
+	}
+}
+
+func TestPositions(t *testing.T) {
+	stmt, _, positions, err := NewTestParser().Parse2WithPositions("select 1 from t where a = 1 and b > 2")
+	require.NoError(t, err)
+	where := stmt.(*Select).Where.Expr
+
+	// The position of a node without one is the first position it contains.
+	pos, ok := positions.Find(where)
+	require.True(t, ok)
+	assert.Equal(t, 22, pos)
+	_, ok = positions.Of(where)
+	assert.False(t, ok)
+
+	// Nodes that are not pointers have no position.
+	_, ok = positions.Of(ValTuple{NewIntLiteral("1")})
+	assert.False(t, ok)
+
+	b := where.(*AndExpr).Right.(*ComparisonExpr).Left
+	qualified := NewColNameWithQualifier("b", NewTableName("t"))
+	positions.Transfer(b, qualified)
+	pos, ok = positions.Of(qualified)
+	require.True(t, ok)
+	assert.Equal(t, 32, pos)
+
+	positions.Offset(10)
+	pos, _ = positions.Of(qualified)
+	assert.Equal(t, 42, pos)
+
+	var none Positions
+	_, ok = none.Find(where)
+	assert.False(t, ok)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlparser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// This file implements the recovery mode of the parser: the statements of a
+// multi-statement input are parsed one by one, and a syntax error in one of
+// them doesn't stop the parsing of the next ones, so that all the errors of
+// an input can be reported at once.
+
+// ParsedStatement is a statement of a multi-statement input.
+type ParsedStatement struct {
+	// SQL is the text of the statement, without its ';'.
+	SQL string
+	// Offset is the offset of the statement in the input.
+	Offset int
+	// Statement is the AST of the statement.
+	Statement Statement
+}
+
+// StatementError is the error of a statement of a multi-statement input that
+// failed to parse.
+type StatementError struct {
+	// Index is the index of the statement in the input, from 0, counting
+	// the statements that parsed and the ones that did not.
+	Index int
+	// SQL is the text of the statement, without its ';'.
+	SQL string
+	// Offset is the offset of the statement in the input.
+	Offset int
+	// Line and Column are the position of the error in the input, from 1.
+	// The column counts bytes.
+	Line   int
+	Column int
+	// Err is the error of the parser, usually a PositionedErr whose
+	// position is the one in the input.
+	Err error
+}
+
+func (e *StatementError) Error() string {
+	return fmt.Sprintf("statement %d at line %d, column %d: %v", e.Index+1, e.Line, e.Column, e.Err)
+}
+
+func (e *StatementError) Unwrap() error {
+	return e.Err
+}
+
+// StatementErrors are the errors of all the statements of a multi-statement
+// input that failed to parse, in order.
+type StatementErrors []*StatementError
+
+func (errs StatementErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d statement(s) failed to parse:\n%s", len(errs), strings.Join(msgs, "\n"))
+}
+
+// ParseStatementsWithRecovery parses all the statements of a multi-statement
+// input. The statements that fail to parse are skipped, and their errors are
+// returned together as StatementErrors once the whole input is parsed. The
+// empty and comment-only statements are skipped.
+func (p *Parser) ParseStatementsWithRecovery(blob string) ([]ParsedStatement, error) {
+	return p.parseStatementsWithRecovery(blob, false)
+}
+
+// ParseStatementsWithRecoveryStrictDDL is the same as
+// ParseStatementsWithRecovery except it errors on partially parsed DDL
+// statements.
+func (p *Parser) ParseStatementsWithRecoveryStrictDDL(blob string) ([]ParsedStatement, error) {
+	return p.parseStatementsWithRecovery(blob, true)
+}
+
+func (p *Parser) parseStatementsWithRecovery(blob string, strictDDL bool) ([]ParsedStatement, error) {
+	var (
+		statements []ParsedStatement
+		errs       StatementErrors
+	)
+	for i, piece := range p.splitStatementsWithOffsets(blob) {
+		tokenizer := p.NewStringTokenizer(piece.SQL)
+		var (
+			stmt Statement
+			err  error
+		)
+		if strictDDL {
+			stmt, err = parseStrictDDL(tokenizer)
+		} else {
+			stmt, _, err = p.parse(tokenizer)
+		}
+		if errors.Is(err, ErrEmpty) {
+			continue
+		}
+		if err != nil {
+			// The errors of the parser have the position, in the
+			// statement, of the end of the token that could not be parsed:
+			// the line and column are the ones of its start.
+			pos := piece.Offset
+			var posErr PositionedErr
+			if errors.As(tokenizer.LastError, &posErr) {
+				end := min(max(posErr.Pos-1, 0), len(piece.SQL))
+				start := end
+				if strings.HasSuffix(piece.SQL[:end], posErr.Near) {
+					start -= len(posErr.Near)
+				}
+				pos += start
+				posErr.Pos += piece.Offset
+				err = posErr
+			}
+			line, column := lineAndColumn(blob, pos)
+			errs = append(errs, &StatementError{
+				Index:  i,
+				SQL:    piece.SQL,
+				Offset: piece.Offset,
+				Line:   line,
+				Column: column,
+				Err:    err,
+			})
+			continue
+		}
+		if _, ok := stmt.(*CommentOnly); ok {
+			continue
+		}
+		piece.Statement = stmt
+		statements = append(statements, piece)
+	}
+	if len(errs) > 0 {
+		return statements, errs
+	}
+	return statements, nil
+}
+
+// splitStatementsWithOffsets splits a multi-statement input like
+// SplitStatementToPieces, and also returns the offsets of the statements.
+// An unterminated string or comment makes the rest of the input the last
+// statement, which fails to parse.
+func (p *Parser) splitStatementsWithOffsets(blob string) []ParsedStatement {
+	var pieces []ParsedStatement
+	tokenizer := p.NewStringTokenizer(blob)
+	stmtBegin := 0
+	emptyStatement := true
+	for {
+		tkn, _ := tokenizer.Scan()
+		switch tkn {
+		case ';':
+			if !emptyStatement {
+				pieces = append(pieces, ParsedStatement{SQL: blob[stmtBegin : tokenizer.Pos-1], Offset: stmtBegin})
+				emptyStatement = true
+			}
+			stmtBegin = tokenizer.Pos
+		case 0, eofChar:
+			if !emptyStatement {
+				pieces = append(pieces, ParsedStatement{SQL: blob[stmtBegin:], Offset: stmtBegin})
+			}
+			return pieces
+		default:
+			emptyStatement = false
+		}
+	}
+}
+
+// lineAndColumn returns the line and the column, from 1, of an offset in
+// a text.
+func lineAndColumn(text string, offset int) (int, int) {
+	offset = min(offset, len(text))
+	line := strings.Count(text[:offset], "\n") + 1
+	column := offset - strings.LastIndexByte(text[:offset], '\n')
+	return line, column
+}
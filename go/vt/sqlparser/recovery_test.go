@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatementsWithRecovery(t *testing.T) {
+	parser := NewTestParser()
+	sql := "create table t (id int primary key);\n" +
+		"-- a comment\n" +
+		"select * frm t;\n" +
+		"create table u (id int);;\n" +
+		"\n" +
+		"alter table t add column x int fooo;\n" +
+		"insert into t values ('unterminated);\n" +
+		"select 1"
+
+	statements, err := parser.ParseStatementsWithRecoveryStrictDDL(sql)
+	require.Len(t, statements, 2)
+	assert.Equal(t, "create table t (\n\tid int primary key\n)", String(statements[0].Statement))
+	assert.Equal(t, 0, statements[0].Offset)
+	assert.Equal(t, "\ncreate table u (id int)", statements[1].SQL)
+	assert.Equal(t, 65, statements[1].Offset)
+
+	var errs StatementErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 3)
+
+	assert.Equal(t, 1, errs[0].Index)
+	assert.Equal(t, 3, errs[0].Line)
+	assert.Equal(t, 10, errs[0].Column)
+	assert.EqualError(t, errs[0], "statement 2 at line 3, column 10: syntax error at position 63 near 'frm'")
+
+	assert.Equal(t, 3, errs[1].Index)
+	assert.Equal(t, 6, errs[1].Line)
+	assert.Equal(t, 32, errs[1].Column)
+
+	// The unterminated string swallows the rest of the input.
+	assert.Equal(t, 4, errs[2].Index)
+	assert.Equal(t, 7, errs[2].Line)
+	assert.Equal(t, "\ninsert into t values ('unterminated);\nselect 1", errs[2].SQL)
+
+	assert.True(t, strings.HasPrefix(err.Error(), "3 statement(s) failed to parse:\n"))
+}
+
+func TestParseStatementsWithRecoveryPartialDDL(t *testing.T) {
+	parser := NewTestParser()
+	sql := "create table t (id int default); select 1"
+
+	statements, err := parser.ParseStatementsWithRecovery(sql)
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+	assert.False(t, statements[0].Statement.(DDLStatement).IsFullyParsed())
+
+	statements, err = parser.ParseStatementsWithRecoveryStrictDDL(sql)
+	require.Error(t, err)
+	require.Len(t, statements, 1)
+	assert.Equal(t, "select 1 from dual", String(statements[0].Statement))
+}
+
+func TestParseStatementsWithRecoveryEmpty(t *testing.T) {
+	parser := NewTestParser()
+	for _, sql := range []string{"", " ;; ", "/* comment */", "-- comment\n;\n"} {
+		statements, err := parser.ParseStatementsWithRecovery(sql)
+		require.NoError(t, err, sql)
+		assert.Empty(t, statements, sql)
+	}
+}
+
+// TestErrorCasesWithRecovery parses all the cases of the error corpus in a
+// single input, between valid statements, and checks that all of them fail
+// with the errors they fail with on their own.
+func TestErrorCasesWithRecovery(t *testing.T) {
+	parser := NewTestParser()
+	var (
+		sql    strings.Builder
+		cases  []testCase
+		starts []int
+	)
+	for tcase := range iterateExecFile("error_cases.txt") {
+		sql.WriteString("select 1;")
+		starts = append(starts, sql.Len())
+		sql.WriteString("\n" + tcase.input + ";\n")
+		cases = append(cases, tcase)
+	}
+	sql.WriteString("select 1")
+	require.NotEmpty(t, cases)
+
+	statements, err := parser.ParseStatementsWithRecovery(sql.String())
+	assert.Len(t, statements, len(cases)+1)
+	var errs StatementErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, len(cases))
+	for i, tcase := range cases {
+		assert.Equal(t, 2*i+1, errs[i].Index, tcase.input)
+		assert.Equal(t, starts[i], errs[i].Offset, tcase.input)
+		assert.Equal(t, 2*i+2, errs[i].Line, tcase.input)
+
+		// The position of the error is the one in the whole input, where
+		// the statement starts after a newline.
+		posErr, ok := errs[i].Err.(PositionedErr)
+		require.True(t, ok, tcase.input)
+		posErr.Pos -= starts[i] + 1
+		assert.Equal(t, tcase.errStr, posErr.Error(), tcase.input)
+	}
+}
@@ -41,6 +41,14 @@ func markBindVariable(yylex yyLexer, bvar string) {
   yylex.(*Tokenizer).BindVars[bvar] = struct{}{}
 }
 
+// setPosition records pos as the position of the node, if the
+// positions of the nodes are tracked.
+func setPosition(yylex yyLexer, node SQLNode, pos int) {
+  if positions := yylex.(*Tokenizer).positions; positions != nil {
+    positions[node] = pos
+  }
+}
+
 %}
 
 %struct {
@@ -57,6 +65,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
   databaseOption DatabaseOption
   columnType    *ColumnType
   columnCharset ColumnCharset
+  pos           int
 }
 
 %union {
@@ -100,6 +109,8 @@ func markBindVariable(yylex yyLexer, bvar string) {
   showFilter    *ShowFilter
   optLike       *OptLike
   selectInto	  *SelectInto
+  loadFields    *LoadFields
+  loadLines     *LoadLines
   createDatabase  *CreateDatabase
   alterDatabase  *AlterDatabase
   createTable      *CreateTable
@@ -119,6 +130,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
   framePoint 	  *FramePoint
   frameUnitType   FrameUnitType
   framePointType  FramePointType
+  frameExclusionType FrameExclusionType
   argumentLessWindowExprType ArgumentLessWindowExprType
   windowSpecification *WindowSpecification
   overClause *OverClause
@@ -243,7 +255,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> ALL DISTINCT AS EXISTS ASC DESC INTO DUPLICATE DEFAULT SET LOCK UNLOCK KEYS DO CALL
 %token <str> DISTINCTROW PARSER GENERATED ALWAYS
 %token <str> OUTFILE S3 DATA LOAD LINES TERMINATED ESCAPED ENCLOSED
-%token <str> DUMPFILE CSV HEADER MANIFEST OVERWRITE STARTING OPTIONALLY
+%token <str> DUMPFILE CSV HEADER MANIFEST OVERWRITE STARTING OPTIONALLY INFILE
 %token <str> VALUES LAST_INSERT_ID
 %token <str> NEXT VALUE SHARE MODE
 %token <str> SQL_NO_CACHE SQL_CACHE SQL_CALC_FOUND_ROWS
@@ -315,7 +327,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> SEQUENCE MERGE TEMPORARY TEMPTABLE INVOKER SECURITY FIRST AFTER LAST
 
 // Migration tokens
-%token <str> VITESS_MIGRATION CANCEL RETRY LAUNCH COMPLETE CLEANUP THROTTLE UNTHROTTLE FORCE_CUTOVER EXPIRE RATIO
+%token <str> VITESS_MIGRATION CANCEL RETRY LAUNCH COMPLETE CLEANUP THROTTLE UNTHROTTLE FORCE_CUTOVER CUTOVER EXPIRE RATIO
 // Throttler tokens
 %token <str> VITESS_THROTTLER
 
@@ -339,10 +351,13 @@ func markBindVariable(yylex yyLexer, bvar string) {
 // PURGE tokens
 %token <str> PURGE BEFORE
 
+// RESET tokens
+%token <str> RESET
+
 // SHOW tokens
 %token <str> CODE COLLATION COLUMNS DATABASES ENGINES EVENT EXTENDED FIELDS FULL FUNCTION GTID_EXECUTED
 %token <str> KEYSPACES OPEN PLUGINS PRIVILEGES PROCESSLIST SCHEMAS TABLES TRIGGERS USER
-%token <str> VGTID_EXECUTED VITESS_KEYSPACES VITESS_METADATA VITESS_MIGRATIONS VITESS_REPLICATION_STATUS VITESS_SHARDS VITESS_TABLETS VITESS_TARGET VSCHEMA VITESS_THROTTLED_APPS
+%token <str> VGTID_EXECUTED VITESS_KEYSPACES VITESS_METADATA VITESS_MIGRATIONS VITESS_REPLICATION_STATUS VITESS_SHARDS VITESS_TABLETS VITESS_TARGET VSCHEMA VITESS_THROTTLED_APPS VITESS_PLANS
 
 // SET tokens
 %token <str> NAMES GLOBAL SESSION ISOLATION LEVEL READ WRITE ONLY REPEATABLE COMMITTED UNCOMMITTED SERIALIZABLE
@@ -363,7 +378,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %token <str> JSON_ARRAY JSON_OBJECT JSON_QUOTE
 %token <str> JSON_DEPTH JSON_TYPE JSON_LENGTH JSON_VALID
 %token <str> JSON_ARRAY_APPEND JSON_ARRAY_INSERT JSON_INSERT JSON_MERGE JSON_MERGE_PATCH JSON_MERGE_PRESERVE JSON_REMOVE JSON_REPLACE JSON_SET JSON_UNQUOTE
-%token <str> COUNT AVG MAX MIN SUM GROUP_CONCAT BIT_AND BIT_OR BIT_XOR STD STDDEV STDDEV_POP STDDEV_SAMP VAR_POP VAR_SAMP VARIANCE ANY_VALUE
+%token <str> COUNT AVG MAX MIN SUM GROUP_CONCAT BIT_AND BIT_OR BIT_XOR STD STDDEV STDDEV_POP STDDEV_SAMP VAR_POP VAR_SAMP VARIANCE ANY_VALUE JSON_ARRAYAGG JSON_OBJECTAGG
 %token <str> REGEXP_INSTR REGEXP_LIKE REGEXP_REPLACE REGEXP_SUBSTR
 %token <str> ExtractValue UpdateXML
 %token <str> GET_LOCK RELEASE_LOCK RELEASE_ALL_LOCKS IS_FREE_LOCK IS_USED_LOCK
@@ -432,7 +447,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <databaseOption> collate character_set encryption
 %type <databaseOptions> create_options create_options_opt
 %type <boolean> default_optional first_opt linear_opt jt_exists_opt jt_path_opt partition_storage_opt
-%type <statement> analyze_statement show_statement use_statement purge_statement other_statement
+%type <statement> analyze_statement show_statement use_statement purge_statement reset_statement other_statement
 %type <statement> begin_statement commit_statement rollback_statement savepoint_statement release_statement load_statement
 %type <statement> lock_statement unlock_statement call_statement
 %type <statement> revert_statement
@@ -442,6 +457,7 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <vexplainType> vexplain_type_opt
 %type <trimType> trim_type
 %type <frameUnitType> frame_units
+%type <frameExclusionType> frame_exclusion_opt
 %type <argumentLessWindowExprType> argument_less_window_expr_type
 %type <framePoint> frame_point
 %type <frameClause> frame_clause frame_clause_opt
@@ -554,6 +570,11 @@ func markBindVariable(yylex yyLexer, bvar string) {
 %type <intPtr> length_opt
 %type <integer> func_datetime_precision
 %type <columnCharset> charset_opt
+%type <loadFields> load_fields_opt load_fields_list
+%type <loadLines> load_lines_opt load_lines_list
+%type <boolean> load_local_opt
+%type <literal> load_ignore_lines_opt
+%type <updateExprs> load_set_opt
 %type <str> collate_opt
 %type <boolean> binary_opt
 %type <LengthScaleOption> double_length_opt float_length_opt decimal_length_opt
@@ -645,6 +666,7 @@ command:
 | truncate_statement
 | analyze_statement
 | purge_statement
+| reset_statement
 | show_statement
 | use_statement
 | begin_statement
@@ -709,10 +731,121 @@ do_statement:
   }
 
 load_statement:
-  LOAD DATA skip_to_end
+  LOAD DATA FROM skip_to_end
   {
     $$ = &Load{}
   }
+| LOAD DATA load_local_opt INFILE STRING INTO TABLE table_name charset_opt load_fields_opt load_lines_opt load_ignore_lines_opt column_list_opt load_set_opt
+  {
+    $$ = &Load{Local: $3, File: $5, Table: $8, Charset: $9, Fields: $10, Lines: $11, IgnoreLines: $12, Columns: $13, SetExprs: $14}
+  }
+| LOAD DATA load_local_opt INFILE STRING REPLACE INTO TABLE table_name charset_opt load_fields_opt load_lines_opt load_ignore_lines_opt column_list_opt load_set_opt
+  {
+    $$ = &Load{Local: $3, File: $5, Replace: true, Table: $9, Charset: $10, Fields: $11, Lines: $12, IgnoreLines: $13, Columns: $14, SetExprs: $15}
+  }
+| LOAD DATA load_local_opt INFILE STRING IGNORE INTO TABLE table_name charset_opt load_fields_opt load_lines_opt load_ignore_lines_opt column_list_opt load_set_opt
+  {
+    $$ = &Load{Local: $3, File: $5, Ignore: true, Table: $9, Charset: $10, Fields: $11, Lines: $12, IgnoreLines: $13, Columns: $14, SetExprs: $15}
+  }
+
+load_local_opt:
+  {
+    $$ = false
+  }
+| LOCAL
+  {
+    $$ = true
+  }
+
+load_fields_opt:
+  {
+    $$ = nil
+  }
+| columns_or_fields load_fields_list
+  {
+    $$ = $2
+  }
+
+load_fields_list:
+  TERMINATED BY STRING
+  {
+    $$ = &LoadFields{TerminatedBy: NewStrLiteral($3)}
+  }
+| optionally_opt ENCLOSED BY STRING
+  {
+    $$ = &LoadFields{EnclosedBy: NewStrLiteral($4), OptionallyEnclosed: $1 != ""}
+  }
+| ESCAPED BY STRING
+  {
+    $$ = &LoadFields{EscapedBy: NewStrLiteral($3)}
+  }
+| load_fields_list TERMINATED BY STRING
+  {
+    $1.TerminatedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+| load_fields_list optionally_opt ENCLOSED BY STRING
+  {
+    $1.EnclosedBy = NewStrLiteral($5)
+    $1.OptionallyEnclosed = $2 != ""
+    $$ = $1
+  }
+| load_fields_list ESCAPED BY STRING
+  {
+    $1.EscapedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+
+load_lines_opt:
+  {
+    $$ = nil
+  }
+| LINES load_lines_list
+  {
+    $$ = $2
+  }
+
+load_lines_list:
+  STARTING BY STRING
+  {
+    $$ = &LoadLines{StartingBy: NewStrLiteral($3)}
+  }
+| TERMINATED BY STRING
+  {
+    $$ = &LoadLines{TerminatedBy: NewStrLiteral($3)}
+  }
+| load_lines_list STARTING BY STRING
+  {
+    $1.StartingBy = NewStrLiteral($4)
+    $$ = $1
+  }
+| load_lines_list TERMINATED BY STRING
+  {
+    $1.TerminatedBy = NewStrLiteral($4)
+    $$ = $1
+  }
+
+load_ignore_lines_opt:
+  {
+    $$ = nil
+  }
+| IGNORE INTEGRAL LINES
+  {
+    $$ = NewIntLiteral($2)
+  }
+| IGNORE INTEGRAL ROWS
+  {
+    $$ = NewIntLiteral($2)
+  }
+
+load_set_opt:
+  {
+    $$ = nil
+  }
+| SET update_list
+  {
+    $$ = $2
+  }
 
 with_clause:
   WITH with_list
@@ -3392,6 +3525,13 @@ alter_statement:
       Type: ForceCutOverAllMigrationType,
     }
   }
+| ALTER comment_opt VITESS_MIGRATION STRING CUTOVER
+  {
+    $$ = &AlterMigration{
+      Type: CutOverMigrationType,
+      UUID: string($4),
+    }
+  }
 
 partitions_options_opt:
   {
@@ -4002,6 +4142,12 @@ purge_statement:
     $$ = &PurgeBinaryLogs{Before: string($5)}
   }
 
+reset_statement:
+  RESET VITESS_PLANS
+  {
+    $$ = &ResetVitessPlans{}
+  }
+
 show_statement:
   SHOW charset_or_character_set like_or_where_opt
   {
@@ -4139,7 +4285,7 @@ show_statement:
   {
     $$ = &ShowThrottledApps{}
   }
-| SHOW VITESS_REPLICATION_STATUS like_opt
+| SHOW VITESS_REPLICATION_STATUS like_or_where_opt
   {
     $$ = &Show{&ShowBasic{Command: VitessReplicationStatus, Filter: $3}}
   }
@@ -4175,6 +4321,10 @@ show_statement:
   {
     $$ = &Show{&ShowBasic{Command: VitessTablets, Filter: $3}}
   }
+| SHOW VITESS_PLANS like_or_where_opt
+  {
+    $$ = &Show{&ShowBasic{Command: VitessPlans, Filter: $3}}
+  }
 | SHOW VITESS_TARGET
   {
     $$ = &Show{&ShowBasic{Command: VitessTarget}}
@@ -5416,18 +5566,22 @@ simple_expr:
 function_call_keyword
   {
   	$$ = $1
+  	setPosition(yylex, $$, $<pos>1)
   }
 | function_call_nonkeyword
   {
   	$$ = $1
+  	setPosition(yylex, $$, $<pos>1)
   }
 | function_call_generic
   {
   	$$ = $1
+  	setPosition(yylex, $$, $<pos>1)
   }
 | function_call_conflict
   {
   	$$ = $1
+  	setPosition(yylex, $$, $<pos>1)
   }
 | simple_expr COLLATE charset %prec UNARY
   {
@@ -5440,6 +5594,7 @@ function_call_keyword
 | column_name_or_offset
   {
   	$$ = $1
+  	setPosition(yylex, $$, $<pos>1)
   }
 | variable_expr
   {
@@ -5464,6 +5619,7 @@ function_call_keyword
 | subquery
   {
 	$$= $1
+	setPosition(yylex, $$, $<pos>1)
   }
 | tuple_expression
   {
@@ -5610,6 +5766,14 @@ frame_point:
   {
     $$ = &FramePoint{Type:ExprFollowingType, Expr:$1}
   }
+| VALUE_ARG PRECEDING
+  {
+    $$ = &FramePoint{Type:ExprPrecedingType, Expr:parseBindVariable(yylex, $1[1:])}
+  }
+| VALUE_ARG FOLLOWING
+  {
+    $$ = &FramePoint{Type:ExprFollowingType, Expr:parseBindVariable(yylex, $1[1:])}
+  }
 | INTERVAL bit_expr interval FOLLOWING
   {
     $$ = &FramePoint{Type:ExprFollowingType, Expr:$2, Unit:$3}
@@ -5625,13 +5789,34 @@ frame_clause_opt:
   }
 
 frame_clause:
-  frame_units frame_point
+  frame_units frame_point frame_exclusion_opt
+  {
+    $$ = &FrameClause{ Unit: $1, Start: $2, Exclude: $3 }
+  }
+| frame_units BETWEEN frame_point AND frame_point frame_exclusion_opt
+  {
+    $$ = &FrameClause{ Unit: $1, Start: $3, End: $5, Exclude: $6 }
+  }
+
+frame_exclusion_opt:
   {
-    $$ = &FrameClause{ Unit: $1, Start: $2 }
+    $$ = NoFrameExclusionType
   }
-| frame_units BETWEEN frame_point AND frame_point
+| EXCLUDE CURRENT ROW
   {
-    $$ = &FrameClause{ Unit: $1, Start: $3, End: $5 }
+    $$ = ExcludeCurrentRowType
+  }
+| EXCLUDE GROUP
+  {
+    $$ = ExcludeGroupType
+  }
+| EXCLUDE TIES
+  {
+    $$ = ExcludeTiesType
+  }
+| EXCLUDE NO OTHERS
+  {
+    $$ = ExcludeNoOthersType
   }
 
 window_partition_clause_opt:
@@ -5645,6 +5830,7 @@ window_partition_clause_opt:
 
 sql_id_opt:
   {
+    $$ = IdentifierCI{}
   }
 | sql_id
   {
@@ -5835,6 +6021,7 @@ col_tuple:
 | subquery
   {
     $$ = $1
+    setPosition(yylex, $$, $<pos>1)
   }
 | LIST_ARG
   {
@@ -6032,6 +6219,14 @@ UTC_DATE func_paren_opt
   {
     $$ = &AnyValue{Arg:$3}
   }
+| JSON_ARRAYAGG openb expression closeb over_clause_opt
+  {
+    $$ = &JSONArrayAgg{Arg:$3, OverClause: $5}
+  }
+| JSON_OBJECTAGG openb expression ',' expression closeb over_clause_opt
+  {
+    $$ = &JSONObjectAgg{Key:$3, Value:$5, OverClause: $7}
+  }
 | TIMESTAMPADD openb timestampadd_interval ',' expression ',' expression closeb
   {
     $$ = &IntervalDateExpr{Syntax: IntervalDateExprTimestampadd, Date: $7, Interval: $5, Unit: $3}
@@ -7277,7 +7472,7 @@ having_opt:
   }
 
 named_window:
-  WINDOW window_definition_list %prec WINDOW_EXPR
+  WINDOW window_definition_list
   {
     $$ = &NamedWindow{$2}
   }
@@ -7287,10 +7482,6 @@ named_windows_list:
   {
     $$ = NamedWindows{$1}
   }
-| named_windows_list ',' named_window
-  {
-    $$ = append($1,$3)
-  }
 
 named_windows_list_opt:
   {
@@ -8184,6 +8375,7 @@ non_reserved_keyword:
 | COUNT %prec FUNCTION_CALL_NON_KEYWORD
 | CSV
 | CURRENT
+| CUTOVER
 | DATA
 | DATE %prec STRING_TYPE_PREFIX_NON_KEYWORD
 | DATE_ADD %prec FUNCTION_CALL_NON_KEYWORD
@@ -8268,6 +8460,7 @@ non_reserved_keyword:
 | ISOLATION
 | JSON
 | JSON_ARRAY %prec FUNCTION_CALL_NON_KEYWORD
+| JSON_ARRAYAGG %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_ARRAY_APPEND %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_ARRAY_INSERT %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_CONTAINS %prec FUNCTION_CALL_NON_KEYWORD
@@ -8280,6 +8473,7 @@ non_reserved_keyword:
 | JSON_MERGE_PATCH %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_MERGE_PRESERVE %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_OBJECT %prec FUNCTION_CALL_NON_KEYWORD
+| JSON_OBJECTAGG %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_OVERLAPS %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_PRETTY %prec FUNCTION_CALL_NON_KEYWORD
 | JSON_QUOTE %prec FUNCTION_CALL_NON_KEYWORD
@@ -8403,6 +8597,7 @@ non_reserved_keyword:
 | REORGANIZE
 | REPAIR
 | REPEATABLE
+| RESET
 | RESTRICT
 | REQUIRE_ROW_FORMAT
 | RESOURCE
@@ -8560,6 +8755,7 @@ non_reserved_keyword:
 | VITESS_METADATA
 | VITESS_MIGRATION
 | VITESS_MIGRATIONS
+| VITESS_PLANS
 | VITESS_REPLICATION_STATUS
 | VITESS_SHARDS
 | VITESS_TABLETS
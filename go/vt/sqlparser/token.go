@@ -44,6 +44,12 @@ type Tokenizer struct {
 	multi          bool
 	specialComment *Tokenizer
 
+	// positions, if not nil, records the positions of the parsed nodes.
+	positions Positions
+	// tokenPos is the position of the first character of the last token.
+	// The tokens of a MySQL specific comment are at the start of the comment.
+	tokenPos int
+
 	Pos    int
 	buf    string
 	parser *Parser
@@ -81,6 +87,7 @@ func (tkn *Tokenizer) Lex(lval *yySymType) int {
 		tkn.partialDDL = nil
 	}
 	lval.str = val
+	lval.pos = tkn.tokenPos
 	tkn.lastToken = val
 	return typ
 }
@@ -124,6 +131,7 @@ func (tkn *Tokenizer) Scan() (int, string) {
 	}
 
 	tkn.skipBlank()
+	tkn.tokenPos = tkn.Pos
 	switch ch := tkn.cur(); {
 	case ch == '@':
 		tokenID := AT_ID
@@ -44,12 +44,26 @@ var (
 	srvTopoTimeout      = 5 * time.Second
 	srvTopoCacheTTL     = 1 * time.Second
 	srvTopoCacheRefresh = 1 * time.Second
+
+	// srvTopoCacheMaxStaleness controls how long watched entries can be
+	// served past srv_topo_cache_ttl when the watch cannot be established,
+	// e.g. because the topo server is unavailable. While the watch is being
+	// re-established, such stale entries are returned right away instead of
+	// waiting for the topo server.
+	srvTopoCacheMaxStaleness time.Duration
+
+	// srvTopoFallbackCells lists the cells whose SrvKeyspace records are
+	// read when the SrvKeyspace of the requested cell cannot be read, e.g.
+	// during a cell-local topo outage. Fallback cells are only read from.
+	srvTopoFallbackCells []string
 )
 
 func registerFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&srvTopoTimeout, "srv_topo_timeout", srvTopoTimeout, "topo server timeout")
 	fs.DurationVar(&srvTopoCacheTTL, "srv_topo_cache_ttl", srvTopoCacheTTL, "how long to use cached entries for topology")
 	fs.DurationVar(&srvTopoCacheRefresh, "srv_topo_cache_refresh", srvTopoCacheRefresh, "how frequently to refresh the topology for cached entries")
+	fs.DurationVar(&srvTopoCacheMaxStaleness, "srv-topo-cache-max-staleness", srvTopoCacheMaxStaleness, "how long past srv_topo_cache_ttl to keep serving cached watched entries (SrvKeyspace, SrvVSchema) while the topology server is unavailable. 0 disables serving stale entries.")
+	fs.StringSliceVar(&srvTopoFallbackCells, "srv-topo-fallback-cells", srvTopoFallbackCells, "comma-separated list of cells, in order of preference, to read SrvKeyspace records from when they cannot be read from the requested cell's topology server.")
 }
 
 func init() {
@@ -59,9 +73,11 @@ func init() {
 }
 
 const (
-	queryCategory  = "query"
-	cachedCategory = "cached"
-	errorCategory  = "error"
+	queryCategory    = "query"
+	cachedCategory   = "cached"
+	staleCategory    = "stale"
+	fallbackCategory = "fallback"
+	errorCategory    = "error"
 )
 
 // ResilientServer is an implementation of srvtopo.Server based
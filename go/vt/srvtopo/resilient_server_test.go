@@ -853,3 +853,85 @@ func TestSrvKeyspaceListener(t *testing.T) {
 	// only 3 times the callback called for the listener
 	assert.EqualValues(t, 3, callbackCount.Load())
 }
+
+// TestWatchServesStaleValue tests that an expired value is served while the
+// watch is being re-established, as long as it's within the max staleness.
+func TestWatchServesStaleValue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The watcher never manages to reach the topo server.
+	unblock := make(chan struct{})
+	defer close(unblock)
+	counts := stats.NewCountersWithSingleLabel("", "Resilient srvtopo server operations", "type")
+	rw := &resilientWatcher{
+		watcher: func(entry *watchEntry) {
+			<-unblock
+		},
+		counts:               counts,
+		cacheRefreshInterval: 0,
+		cacheTTL:             10 * time.Millisecond,
+		entries:              make(map[string]*watchEntry),
+	}
+	key := &srvKeyspaceKey{"test_cell", "ks"}
+	entry := rw.getEntry(key)
+	value := &topodatapb.SrvKeyspace{}
+	entry.update(ctx, value, nil, true)
+	// The watch stream breaks.
+	entry.update(ctx, nil, topo.NewError(topo.Timeout, "test"), false)
+	time.Sleep(2 * rw.cacheTTL)
+
+	// Without max staleness, we wait for the watch to be re-established.
+	waitCtx, waitCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer waitCancel()
+	_, err := rw.getValue(waitCtx, key)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// With max staleness, we serve the stale value right away.
+	rw.cacheMaxStaleness = time.Hour
+	v, err := rw.getValue(ctx, key)
+	require.NoError(t, err)
+	assert.Same(t, value, v)
+	assert.EqualValues(t, 1, counts.Counts()[staleCategory])
+}
+
+func TestGetSrvKeyspaceFallbackCells(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fallbackValue := &topodatapb.SrvKeyspace{}
+	counts := stats.NewCountersWithSingleLabel("", "Resilient srvtopo server operations", "type")
+	rw := &resilientWatcher{
+		watcher: func(entry *watchEntry) {
+			switch key := entry.key.(*srvKeyspaceKey); key.cell {
+			case "fallback_cell":
+				entry.update(ctx, fallbackValue, nil, true)
+			case "missing_cell":
+				entry.update(ctx, nil, topo.NewError(topo.NoNode, key.String()), true)
+			default:
+				entry.update(ctx, nil, topo.NewError(topo.Timeout, key.String()), true)
+			}
+		},
+		counts:               counts,
+		cacheRefreshInterval: time.Hour,
+		cacheTTL:             time.Hour,
+		entries:              make(map[string]*watchEntry),
+	}
+
+	// Without fallback cells, the error of the requested cell is returned.
+	w := &SrvKeyspaceWatcher{rw: rw}
+	_, err := w.GetSrvKeyspace(ctx, "local_cell", "ks")
+	require.True(t, topo.IsErrType(err, topo.Timeout), "unexpected error: %v", err)
+
+	// With fallback cells, the first readable fallback cell is used.
+	w.fallbackCells = []string{"local_cell", "other_cell", "fallback_cell"}
+	ks, err := w.GetSrvKeyspace(ctx, "local_cell", "ks")
+	require.NoError(t, err)
+	assert.Same(t, fallbackValue, ks)
+	assert.EqualValues(t, 1, counts.Counts()[fallbackCategory])
+
+	// A missing SrvKeyspace in the requested cell is authoritative.
+	_, err = w.GetSrvKeyspace(ctx, "missing_cell", "ks")
+	require.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+	assert.EqualValues(t, 1, counts.Counts()[fallbackCategory])
+}
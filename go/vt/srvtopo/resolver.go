@@ -91,6 +91,7 @@ func (rs *ResolvedShard) WithKeyspace(newKeyspace string) *ResolvedShard {
 			Shard:      rs.Target.Shard,
 			TabletType: rs.Target.TabletType,
 			Cell:       rs.Target.Cell,
+			TabletTags: rs.Target.TabletTags,
 		},
 		Gateway: rs.Gateway,
 	}
@@ -62,6 +62,7 @@ type resilientWatcher struct {
 	counts               *stats.CountersWithSingleLabel
 	cacheRefreshInterval time.Duration
 	cacheTTL             time.Duration
+	cacheMaxStaleness    time.Duration
 
 	mutex   sync.Mutex
 	entries map[string]*watchEntry
@@ -135,6 +136,12 @@ func (entry *watchEntry) currentValueLocked(ctx context.Context) (any, error) {
 	}
 
 	if entry.watchState == watchStateStarting {
+		if entry.servableStaleLocked() {
+			// Don't wait for the watch to be re-established, serve
+			// the last known value in the meantime.
+			entry.rw.counts.Add(staleCategory, 1)
+			return entry.value, nil
+		}
 		watchStartingChan := entry.watchStartingChan
 		entry.mutex.Unlock()
 		select {
@@ -151,6 +158,15 @@ func (entry *watchEntry) currentValueLocked(ctx context.Context) (any, error) {
 	return nil, entry.lastError
 }
 
+// servableStaleLocked returns true if the cached value has expired, but is
+// still within the max staleness window past the cache TTL.
+func (entry *watchEntry) servableStaleLocked() bool {
+	if entry.value == nil || entry.rw.cacheMaxStaleness <= 0 {
+		return false
+	}
+	return time.Since(entry.lastValueTime) < entry.rw.cacheTTL+entry.rw.cacheMaxStaleness
+}
+
 func (entry *watchEntry) update(ctx context.Context, value any, err error, init bool) {
 	entry.mutex.Lock()
 	defer entry.mutex.Unlock()
@@ -200,7 +216,7 @@ func (entry *watchEntry) onErrorLocked(ctx context.Context, err error, init bool
 		// This watcher will able to continue to return the last value till it is not able to connect to the topo server even if the cache TTL is reached.
 		// TTL cache is only checked if the error is a known error i.e topo.Error.
 		_, isTopoErr := err.(topo.Error)
-		if entry.value != nil && isTopoErr && time.Since(entry.lastValueTime) > entry.rw.cacheTTL {
+		if entry.value != nil && isTopoErr && time.Since(entry.lastValueTime) > entry.rw.cacheTTL+entry.rw.cacheMaxStaleness {
 			log.Errorf("WatchSrvKeyspace clearing cached entry for %v", entry.key)
 			entry.value = nil
 		}
@@ -21,12 +21,19 @@ import (
 	"time"
 
 	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/topo"
 )
 
+var logFallback = logutil.NewThrottledLogger("SrvKeyspaceFallback", 1*time.Minute)
+
 type SrvKeyspaceWatcher struct {
 	rw *resilientWatcher
+
+	// fallbackCells are tried in order when the SrvKeyspace of the
+	// requested cell cannot be read.
+	fallbackCells []string
 }
 
 type srvKeyspaceKey struct {
@@ -67,13 +74,38 @@ func NewSrvKeyspaceWatcher(ctx context.Context, topoServer *topo.Server, counts
 		counts:               counts,
 		cacheRefreshInterval: cacheRefresh,
 		cacheTTL:             cacheTTL,
+		cacheMaxStaleness:    srvTopoCacheMaxStaleness,
 		entries:              make(map[string]*watchEntry),
 	}
 
-	return &SrvKeyspaceWatcher{rw}
+	return &SrvKeyspaceWatcher{rw: rw, fallbackCells: srvTopoFallbackCells}
 }
 
 func (w *SrvKeyspaceWatcher) GetSrvKeyspace(ctx context.Context, cell, keyspace string) (*topodata.SrvKeyspace, error) {
+	ks, err := w.getSrvKeyspace(ctx, cell, keyspace)
+	if err == nil || topo.IsErrType(err, topo.NoNode) {
+		return ks, err
+	}
+
+	// The SrvKeyspace of the requested cell could not be read. A missing
+	// record is authoritative, but any other error may be caused by an
+	// outage of the cell's topo server, so we try the fallback cells.
+	for _, fallbackCell := range w.fallbackCells {
+		if fallbackCell == cell {
+			continue
+		}
+		fallbackKs, fallbackErr := w.getSrvKeyspace(ctx, fallbackCell, keyspace)
+		if fallbackErr != nil {
+			continue
+		}
+		w.rw.counts.Add(fallbackCategory, 1)
+		logFallback.Warningf("Serving SrvKeyspace for %v.%v from fallback cell %v: %v", cell, keyspace, fallbackCell, err)
+		return fallbackKs, nil
+	}
+	return ks, err
+}
+
+func (w *SrvKeyspaceWatcher) getSrvKeyspace(ctx context.Context, cell, keyspace string) (*topodata.SrvKeyspace, error) {
 	key := &srvKeyspaceKey{cell, keyspace}
 	v, err := w.rw.getValue(ctx, key)
 	ks, _ := v.(*topodata.SrvKeyspace)
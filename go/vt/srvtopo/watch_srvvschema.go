@@ -65,6 +65,7 @@ func NewSrvVSchemaWatcher(ctx context.Context, topoServer *topo.Server, counts *
 		counts:               counts,
 		cacheRefreshInterval: cacheRefresh,
 		cacheTTL:             cacheTTL,
+		cacheMaxStaleness:    srvTopoCacheMaxStaleness,
 		entries:              make(map[string]*watchEntry),
 	}
 
@@ -64,15 +64,23 @@ var (
 	ClientFoundRows             = SystemVariable{Name: "client_found_rows", IsBoolean: true, Default: off}
 	SessionEnableSystemSettings = SystemVariable{Name: "enable_system_settings", IsBoolean: true, Default: on}
 	Names                       = SystemVariable{Name: "names", Default: utf8mb4, IdentifierAsString: true}
+	ReadOnlyTxOnReplica         = SystemVariable{Name: "read_only_transactions_on_replica", IsBoolean: true, Default: off}
 	SessionUUID                 = SystemVariable{Name: "session_uuid", IdentifierAsString: true}
 	SkipQueryPlanCache          = SystemVariable{Name: "skip_query_plan_cache", IsBoolean: true, Default: off}
+	SkipReadRetry               = SystemVariable{Name: "skip_read_retry", IsBoolean: true, Default: off}
+	SnapshotReads               = SystemVariable{Name: "snapshot_reads", IsBoolean: true, Default: off}
 	Socket                      = SystemVariable{Name: "socket", Default: off}
 	SQLSelectLimit              = SystemVariable{Name: "sql_select_limit", Default: off, SupportSetVar: true}
+	TenantID                    = SystemVariable{Name: "tenant_id", IdentifierAsString: true}
 	TransactionMode             = SystemVariable{Name: "transaction_mode", IdentifierAsString: true}
 	TransactionReadOnly         = SystemVariable{Name: "transaction_read_only", IsBoolean: true, Default: off}
 	TxReadOnly                  = SystemVariable{Name: "tx_read_only", IsBoolean: true, Default: off}
 	Workload                    = SystemVariable{Name: "workload", IdentifierAsString: true}
 	QueryTimeout                = SystemVariable{Name: "query_timeout"}
+	StreamChunkRows             = SystemVariable{Name: "stream_chunk_rows"}
+	StreamChunkTimeout          = SystemVariable{Name: "stream_chunk_timeout"}
+	MaxReplicaLag               = SystemVariable{Name: "max_replica_lag"}
+	TabletTags                  = SystemVariable{Name: "tablet_tags", IdentifierAsString: true}
 
 	// Online DDL
 	DDLStrategy      = SystemVariable{Name: "ddl_strategy", IdentifierAsString: true}
@@ -106,6 +114,14 @@ var (
 		ReadAfterWriteTimeOut,
 		SessionTrackGTIDs,
 		QueryTimeout,
+		SnapshotReads,
+		StreamChunkRows,
+		StreamChunkTimeout,
+		SkipReadRetry,
+		ReadOnlyTxOnReplica,
+		TenantID,
+		MaxReplicaLag,
+		TabletTags,
 	}
 
 	ReadOnly = []SystemVariable{
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"path"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func backupScheduleFilePath(keyspace, shard string) string {
+	return path.Join(KeyspacesPath, keyspace, ShardsPath, shard, BackupScheduleFile)
+}
+
+// GetBackupSchedule returns the backup schedule of a shard. It returns a
+// NoNode error if the shard has no backup schedule.
+func (ts *Server) GetBackupSchedule(ctx context.Context, keyspace, shard string) (*topodatapb.BackupSchedule, error) {
+	if err := ValidateKeyspaceName(keyspace); err != nil {
+		return nil, err
+	}
+	if _, _, err := ValidateShardName(shard); err != nil {
+		return nil, err
+	}
+
+	data, _, err := ts.globalCell.Get(ctx, backupScheduleFilePath(keyspace, shard))
+	if err != nil {
+		return nil, err
+	}
+	schedule := &topodatapb.BackupSchedule{}
+	if err := schedule.UnmarshalVT(data); err != nil {
+		return nil, vterrors.Wrapf(err, "GetBackupSchedule(%v,%v): bad backup schedule data", keyspace, shard)
+	}
+	return schedule, nil
+}
+
+// SaveBackupSchedule creates or updates the backup schedule of a shard. A nil
+// schedule removes it, which is not an error if there is none.
+func (ts *Server) SaveBackupSchedule(ctx context.Context, keyspace, shard string, schedule *topodatapb.BackupSchedule) error {
+	if err := ValidateKeyspaceName(keyspace); err != nil {
+		return err
+	}
+	if _, _, err := ValidateShardName(shard); err != nil {
+		return err
+	}
+
+	filePath := backupScheduleFilePath(keyspace, shard)
+	if schedule == nil {
+		if err := ts.globalCell.Delete(ctx, filePath, nil); err != nil && !IsErrType(err, NoNode) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := schedule.MarshalVT()
+	if err != nil {
+		return err
+	}
+	_, err = ts.globalCell.Update(ctx, filePath, data, nil)
+	return err
+}
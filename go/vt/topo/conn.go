@@ -18,6 +18,7 @@ package topo
 
 import (
 	"context"
+	"fmt"
 	"sort"
 )
 
@@ -78,6 +79,27 @@ type Conn interface {
 	// Can return ErrNoNode if there are no matches.
 	List(ctx context.Context, filePathPrefix string) ([]KVInfo, error)
 
+	// GetMulti returns the contents and versions of several files,
+	// read from a single consistent view of the topology where the
+	// implementation supports it.
+	// filePaths are paths relative to the root directory of the cell.
+	// The returned list has one entry per requested path, in the same
+	// order. Files that do not exist are returned with nil Value and
+	// Version: unlike Get, GetMulti does not return ErrNoNode.
+	GetMulti(ctx context.Context, filePaths []string) ([]KVInfo, error)
+
+	// Txn atomically applies the provided operations: either all of
+	// them are applied, or none is. See TxnOp for the available
+	// operations and their conditions.
+	// It returns the new Version of each file created or updated by
+	// the transaction, with one entry per operation (nil for
+	// TxnOpDelete and TxnOpCheck).
+	// Returns ErrNodeExists, ErrNoNode or ErrBadVersion if the
+	// conditions of any of the operations are not met. Some
+	// implementations cannot tell which condition failed, in which
+	// case ErrBadVersion is returned.
+	Txn(ctx context.Context, ops []TxnOp) ([]Version, error)
+
 	// Delete deletes the provided file.
 	// If version is nil, it is an unconditional delete.
 	// If the last entry of a directory is deleted, using ListDir
@@ -344,6 +366,63 @@ type KVInfo struct {
 	Version Version // version - used to prevent stomping concurrent writes
 }
 
+// TxnOpType is the type of a TxnOp.
+type TxnOpType int
+
+const (
+	// TxnOpCreate creates a file. The file must not exist.
+	TxnOpCreate TxnOpType = iota
+
+	// TxnOpUpdate updates a file. If Version is set, the file must
+	// exist and be at that version. If Version is nil, the update is
+	// unconditional, and creates the file if it doesn't exist.
+	TxnOpUpdate
+
+	// TxnOpDelete deletes a file. The file must exist and, if Version
+	// is set, be at that version.
+	TxnOpDelete
+
+	// TxnOpCheck does not modify the file. It only makes the
+	// transaction conditional on the file existing and, if Version is
+	// set, being at that version.
+	TxnOpCheck
+)
+
+// String is part of the fmt.Stringer interface.
+func (t TxnOpType) String() string {
+	switch t {
+	case TxnOpCreate:
+		return "create"
+	case TxnOpUpdate:
+		return "update"
+	case TxnOpDelete:
+		return "delete"
+	case TxnOpCheck:
+		return "check"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
+// TxnOp is a single operation of a transaction, as used by Conn.Txn.
+type TxnOp struct {
+	// Type is the type of the operation.
+	Type TxnOpType
+
+	// FilePath is the path of the file, relative to the root
+	// directory of the cell.
+	FilePath string
+
+	// Contents is the new content of the file, for TxnOpCreate and
+	// TxnOpUpdate.
+	Contents []byte
+
+	// Version is the expected current version of the file, for
+	// TxnOpUpdate, TxnOpDelete and TxnOpCheck. It is ignored for
+	// TxnOpCreate.
+	Version Version
+}
+
 // LeaderParticipation is the object returned by NewLeaderParticipation.
 // Sample usage:
 //
@@ -24,6 +24,9 @@ import (
 	"github.com/hashicorp/consul/api"
 
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	"vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 // Create is part of the topo.Conn interface.
@@ -167,3 +170,124 @@ func (s *Server) Delete(ctx context.Context, filePath string, version topo.Versi
 	}
 	return nil
 }
+
+// maxTxnOps is the maximum number of operations consul accepts in a
+// single transaction.
+const maxTxnOps = 64
+
+// GetMulti is part of the topo.Conn interface.
+// The files are read with get-or-empty operations, which unlike get
+// operations don't fail the transaction when a file doesn't exist.
+// Consul limits the number of operations in a transaction, so more than
+// maxTxnOps files are read in several transactions, which are not
+// a single consistent view.
+func (s *Server) GetMulti(ctx context.Context, filePaths []string) ([]topo.KVInfo, error) {
+	results := make([]topo.KVInfo, len(filePaths))
+	for start := 0; start < len(filePaths); start += maxTxnOps {
+		chunk := filePaths[start:min(start+maxTxnOps, len(filePaths))]
+		ops := make(api.KVTxnOps, len(chunk))
+		for i, filePath := range chunk {
+			ops[i] = &api.KVTxnOp{
+				Verb: api.KVGetOrEmpty,
+				Key:  path.Join(s.root, filePath),
+			}
+		}
+		ok, resp, _, err := s.kv.Txn(ops, nil)
+		if err != nil {
+			// Communication error.
+			return nil, err
+		}
+		if !ok || len(resp.Results) != len(chunk) {
+			return nil, ErrBadResponse
+		}
+		for i, pair := range resp.Results {
+			result := &results[start+i]
+			result.Key = []byte(chunk[i])
+			// A file that doesn't exist is returned empty, and
+			// was never modified.
+			if pair.ModifyIndex != 0 {
+				result.Value = pair.Value
+				result.Version = ConsulVersion(pair.ModifyIndex)
+			}
+		}
+	}
+	return results, nil
+}
+
+// Txn is part of the topo.Conn interface.
+func (s *Server) Txn(ctx context.Context, ops []topo.TxnOp) ([]topo.Version, error) {
+	// txnOps maps each consul operation back to the index of the
+	// topo operation it was generated for, so we can tell which
+	// one failed.
+	var kvOps api.KVTxnOps
+	var txnOps []int
+	add := func(i int, kvOp *api.KVTxnOp) {
+		kvOps = append(kvOps, kvOp)
+		txnOps = append(txnOps, i)
+	}
+	for i, op := range ops {
+		nodePath := path.Join(s.root, op.FilePath)
+		switch op.Type {
+		case topo.TxnOpCreate:
+			add(i, &api.KVTxnOp{Verb: api.KVCAS, Key: nodePath, Value: op.Contents, Index: 0})
+		case topo.TxnOpUpdate:
+			if op.Version != nil {
+				add(i, &api.KVTxnOp{Verb: api.KVCAS, Key: nodePath, Value: op.Contents, Index: uint64(op.Version.(ConsulVersion))})
+			} else {
+				add(i, &api.KVTxnOp{Verb: api.KVSet, Key: nodePath, Value: op.Contents})
+			}
+		case topo.TxnOpDelete, topo.TxnOpCheck:
+			// The get fails if the node doesn't exist, see Delete.
+			add(i, &api.KVTxnOp{Verb: api.KVGet, Key: nodePath})
+			switch {
+			case op.Type == topo.TxnOpDelete && op.Version != nil:
+				add(i, &api.KVTxnOp{Verb: api.KVDeleteCAS, Key: nodePath, Index: uint64(op.Version.(ConsulVersion))})
+			case op.Type == topo.TxnOpDelete:
+				add(i, &api.KVTxnOp{Verb: api.KVDelete, Key: nodePath})
+			case op.Version != nil:
+				add(i, &api.KVTxnOp{Verb: api.KVCheckIndex, Key: nodePath, Index: uint64(op.Version.(ConsulVersion))})
+			}
+		default:
+			return nil, topo.NewError(topo.NoImplementation, op.Type.String())
+		}
+	}
+	if len(kvOps) > maxTxnOps {
+		// Splitting the transaction would make it non atomic.
+		return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "transaction needs %d consul operations, more than the maximum of %d", len(kvOps), maxTxnOps)
+	}
+
+	ok, resp, _, err := s.kv.Txn(kvOps, nil)
+	if err != nil {
+		// Communication error.
+		return nil, err
+	}
+	if !ok {
+		// Transaction was rolled back. See which operation failed.
+		if len(resp.Errors) == 0 || resp.Errors[0].OpIndex >= len(kvOps) {
+			// very unexpected.
+			return nil, ErrBadResponse
+		}
+		failed := resp.Errors[0].OpIndex
+		nodePath := kvOps[failed].Key
+		switch {
+		case ops[txnOps[failed]].Type == topo.TxnOpCreate:
+			return nil, topo.NewError(topo.NodeExists, nodePath)
+		case kvOps[failed].Verb == api.KVGet:
+			return nil, topo.NewError(topo.NoNode, nodePath)
+		default:
+			return nil, topo.NewError(topo.BadVersion, nodePath)
+		}
+	}
+
+	modifyIndexes := make(map[string]uint64, len(resp.Results))
+	for _, pair := range resp.Results {
+		modifyIndexes[pair.Key] = pair.ModifyIndex
+	}
+	versions := make([]topo.Version, len(ops))
+	for i, op := range ops {
+		if op.Type == topo.TxnOpCreate || op.Type == topo.TxnOpUpdate {
+			versions[i] = ConsulVersion(modifyIndexes[path.Join(s.root, op.FilePath)])
+		}
+	}
+	return versions, nil
+}
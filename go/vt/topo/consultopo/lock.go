@@ -151,6 +151,41 @@ func (s *Server) lock(ctx context.Context, dirPath, contents string) (topo.LockD
 	}, nil
 }
 
+// lockHolder returns the lock key of dirPath, if it is held.
+func (s *Server) lockHolder(ctx context.Context, dirPath string) (*api.KVPair, error) {
+	lockPath := path.Join(s.root, dirPath, locksFilename)
+	kv, _, err := s.kv.Get(lockPath, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, convertError(err, lockPath)
+	}
+	if kv == nil || kv.Session == "" {
+		return nil, topo.NewError(topo.NoNode, lockPath)
+	}
+	return kv, nil
+}
+
+// LockContents is part of the topo.LockInspector interface.
+func (s *Server) LockContents(ctx context.Context, dirPath string) (string, error) {
+	kv, err := s.lockHolder(ctx, dirPath)
+	if err != nil {
+		return "", err
+	}
+	return string(kv.Value), nil
+}
+
+// ForceUnlock is part of the topo.LockInspector interface. It destroys the
+// session of the holder, which releases the lock.
+func (s *Server) ForceUnlock(ctx context.Context, dirPath string) error {
+	kv, err := s.lockHolder(ctx, dirPath)
+	if err != nil {
+		return err
+	}
+	if _, err := s.client.Session().Destroy(kv.Session, (&api.WriteOptions{}).WithContext(ctx)); err != nil {
+		return convertError(err, kv.Key)
+	}
+	return nil
+}
+
 // Check is part of the topo.LockDescriptor interface.
 func (ld *consulLockDescriptor) Check(ctx context.Context) error {
 	select {
@@ -150,3 +150,86 @@ func (s *Server) Delete(ctx context.Context, filePath string, version topo.Versi
 	}
 	return nil
 }
+
+// GetMulti is part of the topo.Conn interface.
+func (s *Server) GetMulti(ctx context.Context, filePaths []string) ([]topo.KVInfo, error) {
+	// A transaction with no condition is used to read all the files
+	// at the same revision.
+	gets := make([]clientv3.Op, len(filePaths))
+	for i, filePath := range filePaths {
+		gets[i] = clientv3.OpGet(path.Join(s.root, filePath))
+	}
+	txnresp, err := s.cli.Txn(ctx).Then(gets...).Commit()
+	if err != nil {
+		return nil, convertError(err, s.root)
+	}
+	results := make([]topo.KVInfo, len(filePaths))
+	for i, filePath := range filePaths {
+		results[i].Key = []byte(filePath)
+		if kvs := txnresp.Responses[i].GetResponseRange().Kvs; len(kvs) == 1 {
+			results[i].Value = kvs[0].Value
+			results[i].Version = EtcdVersion(kvs[0].ModRevision)
+		}
+	}
+	return results, nil
+}
+
+// Txn is part of the topo.Conn interface.
+func (s *Server) Txn(ctx context.Context, ops []topo.TxnOp) ([]topo.Version, error) {
+	var cmps []clientv3.Cmp
+	var thenOps []clientv3.Op
+	// If the transaction fails, we read all the files, so we can
+	// tell which condition was not met.
+	elseOps := make([]clientv3.Op, len(ops))
+	for i, op := range ops {
+		nodePath := path.Join(s.root, op.FilePath)
+		elseOps[i] = clientv3.OpGet(nodePath)
+		switch op.Type {
+		case topo.TxnOpCreate:
+			cmps = append(cmps, clientv3.Compare(clientv3.Version(nodePath), "=", 0))
+			thenOps = append(thenOps, clientv3.OpPut(nodePath, string(op.Contents)))
+		case topo.TxnOpUpdate:
+			if op.Version != nil {
+				cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(nodePath), "=", int64(op.Version.(EtcdVersion))))
+			}
+			thenOps = append(thenOps, clientv3.OpPut(nodePath, string(op.Contents)))
+		case topo.TxnOpDelete, topo.TxnOpCheck:
+			if op.Version != nil {
+				cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(nodePath), "=", int64(op.Version.(EtcdVersion))))
+			} else {
+				cmps = append(cmps, clientv3.Compare(clientv3.Version(nodePath), ">", 0))
+			}
+			if op.Type == topo.TxnOpDelete {
+				thenOps = append(thenOps, clientv3.OpDelete(nodePath))
+			}
+		default:
+			return nil, topo.NewError(topo.NoImplementation, op.Type.String())
+		}
+	}
+
+	txnresp, err := s.cli.Txn(ctx).If(cmps...).Then(thenOps...).Else(elseOps...).Commit()
+	if err != nil {
+		return nil, convertError(err, s.root)
+	}
+	if !txnresp.Succeeded {
+		for i, op := range ops {
+			nodePath := path.Join(s.root, op.FilePath)
+			exists := len(txnresp.Responses[i].GetResponseRange().Kvs) > 0
+			switch {
+			case op.Type == topo.TxnOpCreate && exists:
+				return nil, topo.NewError(topo.NodeExists, nodePath)
+			case op.Type != topo.TxnOpCreate && !exists && (op.Type != topo.TxnOpUpdate || op.Version != nil):
+				return nil, topo.NewError(topo.NoNode, nodePath)
+			}
+		}
+		return nil, topo.NewError(topo.BadVersion, s.root)
+	}
+
+	versions := make([]topo.Version, len(ops))
+	for i, op := range ops {
+		if op.Type == topo.TxnOpCreate || op.Type == topo.TxnOpUpdate {
+			versions[i] = EtcdVersion(txnresp.Header.Revision)
+		}
+	}
+	return versions, nil
+}
@@ -218,6 +218,42 @@ func (s *Server) lock(ctx context.Context, nodePath, contents string) (topo.Lock
 	}
 }
 
+// lockHolder returns the node of the holder of the lock on dirPath, i.e. the
+// oldest node in its locks directory.
+func (s *Server) lockHolder(ctx context.Context, dirPath string) (*mvccpb.KeyValue, error) {
+	nodePath := path.Join(s.root, dirPath, locksPath)
+	resp, err := s.cli.Get(ctx, nodePath+"/", clientv3.WithFirstCreate()...)
+	if err != nil {
+		return nil, convertError(err, nodePath)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, topo.NewError(topo.NoNode, nodePath)
+	}
+	return resp.Kvs[0], nil
+}
+
+// LockContents is part of the topo.LockInspector interface.
+func (s *Server) LockContents(ctx context.Context, dirPath string) (string, error) {
+	kv, err := s.lockHolder(ctx, dirPath)
+	if err != nil {
+		return "", err
+	}
+	return string(kv.Value), nil
+}
+
+// ForceUnlock is part of the topo.LockInspector interface. It revokes the
+// lease of the holder, as Unlock does.
+func (s *Server) ForceUnlock(ctx context.Context, dirPath string) error {
+	kv, err := s.lockHolder(ctx, dirPath)
+	if err != nil {
+		return err
+	}
+	if _, err := s.cli.Revoke(ctx, clientv3.LeaseID(kv.Lease)); err != nil {
+		return convertError(err, string(kv.Key))
+	}
+	return nil
+}
+
 // Check is part of the topo.LockDescriptor interface.
 // We use KeepAliveOnce to make sure the lease is still active and well.
 func (ld *etcdLockDescriptor) Check(ctx context.Context) error {
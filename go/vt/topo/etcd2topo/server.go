@@ -36,6 +36,7 @@ package etcd2topo
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"strings"
 	"time"
 
@@ -47,6 +48,8 @@ import (
 
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 )
 
 var (
@@ -68,6 +71,11 @@ func (f Factory) Create(cell, serverAddr, root string) (topo.Conn, error) {
 	return NewServer(serverAddr, root)
 }
 
+// CreateWithSecrets is part of the topo.SecureFactory interface.
+func (f Factory) CreateWithSecrets(cell, serverAddr, root string, secrets *topodatapb.ExternalClusterSecrets) (topo.Conn, error) {
+	return NewServerWithSecrets(serverAddr, root, secrets)
+}
+
 // Server is the implementation of topo.Server for etcd.
 type Server struct {
 	// cli is the v3 client.
@@ -135,6 +143,34 @@ func newTLSConfig(certPath, keyPath, caPath string) (*tls.Config, error) {
 	return tlscfg, nil
 }
 
+// newTLSConfigFromPEM is like newTLSConfig, with the PEM encoded cert, key and
+// ca instead of their paths. TLS is also enabled with only a ca.
+func newTLSConfigFromPEM(certPEM, keyPEM, caPEM, serverName string) (*tls.Config, error) {
+	if certPEM == "" && keyPEM == "" && caPEM == "" {
+		return nil, nil
+	}
+
+	tlscfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+	if certPEM != "" || keyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS client certificate: %w", err)
+		}
+		tlscfg.Certificates = []tls.Certificate{cert}
+	}
+	if caPEM != "" {
+		cp := x509.NewCertPool()
+		if !cp.AppendCertsFromPEM([]byte(caPEM)) {
+			return nil, fmt.Errorf("invalid TLS certificate authority")
+		}
+		tlscfg.RootCAs = cp
+	}
+	return tlscfg, nil
+}
+
 // NewServerWithOpts creates a new server with the provided TLS options
 func NewServerWithOpts(serverAddr, root, certPath, keyPath, caPath string) (*Server, error) {
 	// TODO: Rename this to NewServer and change NewServer to a name that signifies it uses the process-wide TLS settings.
@@ -151,6 +187,31 @@ func NewServerWithOpts(serverAddr, root, certPath, keyPath, caPath string) (*Ser
 
 	config.TLS = tlscfg
 
+	return newServerWithConfig(config, root)
+}
+
+// NewServerWithSecrets creates a new server that connects with the TLS
+// configuration and the credentials of the secrets of an external cluster.
+func NewServerWithSecrets(serverAddr, root string, secrets *topodatapb.ExternalClusterSecrets) (*Server, error) {
+	config := clientv3.Config{
+		Endpoints:   strings.Split(serverAddr, ","),
+		DialTimeout: 5 * time.Second,
+		DialOptions: []grpc.DialOption{grpc.WithBlock()},
+		Username:    secrets.Username,
+		Password:    secrets.Password,
+	}
+
+	tlscfg, err := newTLSConfigFromPEM(secrets.TlsCert, secrets.TlsKey, secrets.TlsCa, secrets.TlsServerName)
+	if err != nil {
+		return nil, err
+	}
+
+	config.TLS = tlscfg
+
+	return newServerWithConfig(config, root)
+}
+
+func newServerWithConfig(config clientv3.Config, root string) (*Server, error) {
 	cli, err := clientv3.New(config)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,33 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+// LockForceUnlock is an event that describes the forced release of a keyspace
+// lock, or of a shard lock if ShardName is set.
+type LockForceUnlock struct {
+	KeyspaceName string
+	ShardName    string
+
+	// Action, HostName, UserName and LockTime describe the released lock.
+	Action   string
+	HostName string
+	UserName string
+	LockTime string
+
+	// Reason is why the lock was released.
+	Reason string
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"log/syslog"
+
+	"vitess.io/vitess/go/event/syslogger"
+)
+
+// Syslog writes the event to syslog.
+func (lfu *LockForceUnlock) Syslog() (syslog.Priority, string) {
+	target := lfu.KeyspaceName
+	if lfu.ShardName != "" {
+		target += "/" + lfu.ShardName
+	}
+	return syslog.LOG_WARNING, fmt.Sprintf("%s [lock] force unlocked, held by %s@%s since %s for action %q: %s",
+		target, lfu.UserName, lfu.HostName, lfu.LockTime, lfu.Action, lfu.Reason)
+}
+
+var _ syslogger.Syslogger = (*LockForceUnlock)(nil) // compile-time interface check
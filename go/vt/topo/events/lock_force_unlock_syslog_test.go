@@ -0,0 +1,45 @@
+//go:build !windows
+
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"log/syslog"
+	"testing"
+)
+
+func TestLockForceUnlockSyslog(t *testing.T) {
+	wantSev, wantMsg := syslog.LOG_WARNING, `keyspace-123/shard-123 [lock] force unlocked, held by user@host since 2024-01-02T03:04:05Z for action "Reshard": stuck workflow`
+	lfu := &LockForceUnlock{
+		KeyspaceName: "keyspace-123",
+		ShardName:    "shard-123",
+		Action:       "Reshard",
+		HostName:     "host",
+		UserName:     "user",
+		LockTime:     "2024-01-02T03:04:05Z",
+		Reason:       "stuck workflow",
+	}
+	gotSev, gotMsg := lfu.Syslog()
+
+	if gotSev != wantSev {
+		t.Errorf("wrong severity: got %v, want %v", gotSev, wantSev)
+	}
+	if gotMsg != wantMsg {
+		t.Errorf("wrong message: got %v, want %v", gotMsg, wantMsg)
+	}
+}
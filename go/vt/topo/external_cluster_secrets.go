@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/vt/servenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// externalClusterSecretsKeyFile is the file holding the key that encrypts the
+// secrets of the external clusters in the topo.
+var externalClusterSecretsKeyFile string
+
+func init() {
+	servenv.OnParseFor("vtctld", func(fs *pflag.FlagSet) {
+		fs.StringVar(&externalClusterSecretsKeyFile, "external-cluster-secrets-key-file", externalClusterSecretsKeyFile, "File holding the key that encrypts the TLS and credentials of the mounted external clusters in the topo. Required to mount or use an external cluster with TLS or credentials.")
+	})
+}
+
+// SecureFactory is implemented by the topo factories that can connect to a
+// topo server with the secrets of an external cluster.
+type SecureFactory interface {
+	Factory
+
+	// CreateWithSecrets creates a topo.Conn object that connects with the
+	// TLS configuration and the credentials of secrets.
+	CreateWithSecrets(cell, serverAddr, root string, secrets *topodatapb.ExternalClusterSecrets) (Conn, error)
+}
+
+// secretsFactory is a Factory that creates all its connections with the same
+// secrets, so that the cell connections of an external cluster also use them.
+type secretsFactory struct {
+	SecureFactory
+	secrets *topodatapb.ExternalClusterSecrets
+}
+
+// Create is part of the Factory interface.
+func (f *secretsFactory) Create(cell, serverAddr, root string) (Conn, error) {
+	return f.CreateWithSecrets(cell, serverAddr, root, f.secrets)
+}
+
+// OpenServerWithSecrets returns a Server using the provided implementation,
+// address and root for the global server, and secrets for all its connections.
+func OpenServerWithSecrets(implementation, serverAddress, root string, secrets *topodatapb.ExternalClusterSecrets) (*Server, error) {
+	factory, ok := factories[implementation]
+	if !ok {
+		return nil, NewError(NoImplementation, implementation)
+	}
+	secureFactory, ok := factory.(SecureFactory)
+	if !ok {
+		return nil, fmt.Errorf("topo implementation %s does not support TLS or credentials for external clusters", implementation)
+	}
+	return NewWithFactory(&secretsFactory{SecureFactory: secureFactory, secrets: secrets}, serverAddress, root)
+}
+
+// externalClusterSecretsCipher returns the cipher that encrypts the secrets
+// of the external clusters. Its key is derived from the content of the
+// external cluster secrets key file.
+func externalClusterSecretsCipher() (cipher.AEAD, error) {
+	if externalClusterSecretsKeyFile == "" {
+		return nil, fmt.Errorf("--external-cluster-secrets-key-file must be set to use the secrets of external clusters")
+	}
+	data, err := os.ReadFile(externalClusterSecretsKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the external cluster secrets key: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("the external cluster secrets key file %s is empty", externalClusterSecretsKeyFile)
+	}
+	key := sha256.Sum256(data)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptExternalClusterSecrets encrypts the secrets of an external cluster
+// to store them in the topo.
+func EncryptExternalClusterSecrets(secrets *topodatapb.ExternalClusterSecrets) ([]byte, error) {
+	aead, err := externalClusterSecretsCipher()
+	if err != nil {
+		return nil, err
+	}
+	data, err := secrets.MarshalVT()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// DecryptExternalClusterSecrets decrypts the secrets of an external cluster
+// that were encrypted by EncryptExternalClusterSecrets.
+func DecryptExternalClusterSecrets(encrypted []byte) (*topodatapb.ExternalClusterSecrets, error) {
+	aead, err := externalClusterSecretsCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) < aead.NonceSize() {
+		return nil, fmt.Errorf("the encrypted external cluster secrets are too short")
+	}
+	nonce, ciphertext := encrypted[:aead.NonceSize()], encrypted[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt the external cluster secrets, the key may have changed: %w", err)
+	}
+	secrets := &topodatapb.ExternalClusterSecrets{}
+	if err := secrets.UnmarshalVT(data); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
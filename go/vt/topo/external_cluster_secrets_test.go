@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func setExternalClusterSecretsKey(t *testing.T, key string) {
+	path := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(path, []byte(key), 0600))
+	old := externalClusterSecretsKeyFile
+	externalClusterSecretsKeyFile = path
+	t.Cleanup(func() { externalClusterSecretsKeyFile = old })
+}
+
+func TestExternalClusterSecrets(t *testing.T) {
+	secrets := &topodatapb.ExternalClusterSecrets{
+		TlsCa:    "-----BEGIN CERTIFICATE-----",
+		Username: "ext",
+		Password: "secret",
+	}
+
+	_, err := EncryptExternalClusterSecrets(secrets)
+	require.ErrorContains(t, err, "--external-cluster-secrets-key-file must be set")
+
+	setExternalClusterSecretsKey(t, "key1\n")
+	encrypted, err := EncryptExternalClusterSecrets(secrets)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "secret")
+
+	decrypted, err := DecryptExternalClusterSecrets(encrypted)
+	require.NoError(t, err)
+	utils.MustMatch(t, secrets, decrypted)
+
+	setExternalClusterSecretsKey(t, "key2")
+	_, err = DecryptExternalClusterSecrets(encrypted)
+	require.ErrorContains(t, err, "the key may have changed")
+}
+
+type fakeSecureFactory struct {
+	secrets map[string]*topodatapb.ExternalClusterSecrets
+}
+
+func (f *fakeSecureFactory) HasGlobalReadOnlyCell(serverAddr, root string) bool {
+	return false
+}
+
+func (f *fakeSecureFactory) Create(cell, serverAddr, root string) (Conn, error) {
+	return nil, nil
+}
+
+func (f *fakeSecureFactory) CreateWithSecrets(cell, serverAddr, root string, secrets *topodatapb.ExternalClusterSecrets) (Conn, error) {
+	f.secrets[cell] = secrets
+	return nil, nil
+}
+
+func TestOpenServerWithSecrets(t *testing.T) {
+	factory := &fakeSecureFactory{secrets: make(map[string]*topodatapb.ExternalClusterSecrets)}
+	factories["fake_secure"] = factory
+	defer delete(factories, "fake_secure")
+
+	secrets := &topodatapb.ExternalClusterSecrets{Username: "ext", Password: "secret"}
+	ts, err := OpenServerWithSecrets("fake_secure", "localhost:2379", "/vitess/global", secrets)
+	require.NoError(t, err)
+	require.NotNil(t, ts)
+	assert.Equal(t, secrets, factory.secrets[GlobalCell])
+
+	_, err = OpenServerWithSecrets("unknown", "localhost:2379", "/vitess/global", secrets)
+	require.Error(t, err)
+}
@@ -258,6 +258,16 @@ func (f *FakeConn) List(ctx context.Context, filePathPrefix string) ([]topo.KVIn
 	return nil, topo.NewError(topo.NoImplementation, "List not supported in fake topo")
 }
 
+// GetMulti is part of the topo.Conn interface.
+func (f *FakeConn) GetMulti(ctx context.Context, filePaths []string) ([]topo.KVInfo, error) {
+	return nil, topo.NewError(topo.NoImplementation, "GetMulti not supported in fake topo")
+}
+
+// Txn is part of the topo.Conn interface.
+func (f *FakeConn) Txn(ctx context.Context, ops []topo.TxnOp) ([]topo.Version, error) {
+	return nil, topo.NewError(topo.NoImplementation, "Txn not supported in fake topo")
+}
+
 // Delete implements the Conn interface
 func (f *FakeConn) Delete(ctx context.Context, filePath string, version topo.Version) error {
 	panic("implement me")
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo/events"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// This file contains the methods to inspect the keyspace and shard locks
+// held by any process, and to forcibly release them.
+
+var locksForceUnlocked = stats.NewCountersWithSingleLabel(
+	"TopoLocksForceUnlocked",
+	"Number of keyspace and shard locks forcibly released",
+	"Type")
+
+// LockInspector is implemented by the Conn implementations that can read the
+// locks held by other processes on a directory, and release them.
+type LockInspector interface {
+	// LockContents returns the contents of the lock held on dirPath, as
+	// passed to Lock by its holder. It returns a NoNode error if dirPath is
+	// not locked.
+	LockContents(ctx context.Context, dirPath string) (string, error)
+
+	// ForceUnlock releases the lock held on dirPath, whatever process holds
+	// it. It returns a NoNode error if dirPath is not locked.
+	ForceUnlock(ctx context.Context, dirPath string) error
+}
+
+// lockInspector returns the LockInspector of the global cell connection.
+func (ts *Server) lockInspector() (LockInspector, error) {
+	li, ok := ts.globalCell.(LockInspector)
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "the topo server does not support lock inspection")
+	}
+	return li, nil
+}
+
+// getLockHolder returns the Lock held on dirPath, or nil if it is not locked.
+func (ts *Server) getLockHolder(ctx context.Context, dirPath string) (*Lock, error) {
+	li, err := ts.lockInspector()
+	if err != nil {
+		return nil, err
+	}
+	contents, err := li.LockContents(ctx, dirPath)
+	if IsErrType(err, NoNode) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseLock(contents), nil
+}
+
+// parseLock parses the contents of a lock. Locks that were not taken by
+// LockKeyspace or LockShard, e.g. by an older version, only have an action.
+func parseLock(contents string) *Lock {
+	l := &Lock{}
+	if err := json.Unmarshal([]byte(contents), l); err != nil {
+		return &Lock{Action: contents}
+	}
+	return l
+}
+
+// GetKeyspaceLockHolder returns the Lock held on the keyspace, or nil if the
+// keyspace is not locked.
+func (ts *Server) GetKeyspaceLockHolder(ctx context.Context, keyspace string) (*Lock, error) {
+	return ts.getLockHolder(ctx, path.Join(KeyspacesPath, keyspace))
+}
+
+// GetShardLockHolder returns the Lock held on the shard, or nil if the shard
+// is not locked.
+func (ts *Server) GetShardLockHolder(ctx context.Context, keyspace, shard string) (*Lock, error) {
+	return ts.getLockHolder(ctx, path.Join(KeyspacesPath, keyspace, ShardsPath, shard))
+}
+
+// ForceUnlockKeyspace releases the lock held on the keyspace by any process,
+// and returns it. The process that holds the lock, if still running, is not
+// notified and keeps going as if it still held it, so this is only meant to
+// recover from the processes that died without releasing their locks. The
+// release is logged and dispatched as an event, with the given reason.
+func (ts *Server) ForceUnlockKeyspace(ctx context.Context, keyspace, reason string) (*Lock, error) {
+	return ts.forceUnlock(ctx, path.Join(KeyspacesPath, keyspace), keyspaceLockType, keyspace, "", reason)
+}
+
+// ForceUnlockShard releases the lock held on the shard by any process, and
+// returns it. See ForceUnlockKeyspace.
+func (ts *Server) ForceUnlockShard(ctx context.Context, keyspace, shard, reason string) (*Lock, error) {
+	return ts.forceUnlock(ctx, path.Join(KeyspacesPath, keyspace, ShardsPath, shard), shardLockType, keyspace, shard, reason)
+}
+
+func (ts *Server) forceUnlock(ctx context.Context, dirPath, lockType, keyspace, shard, reason string) (*Lock, error) {
+	if reason == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a reason is required to force the release of the lock on %s", dirPath)
+	}
+	li, err := ts.lockInspector()
+	if err != nil {
+		return nil, err
+	}
+	contents, err := li.LockContents(ctx, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := li.ForceUnlock(ctx, dirPath); err != nil {
+		return nil, err
+	}
+
+	l := parseLock(contents)
+	locksForceUnlocked.Add(lockType, 1)
+	log.Warningf("Forcibly released the lock on %s held by %s@%s since %s for action %q: %s", dirPath, l.UserName, l.HostName, l.Time, l.Action, reason)
+	event.Dispatch(&events.LockForceUnlock{
+		KeyspaceName: keyspace,
+		ShardName:    shard,
+		Action:       l.Action,
+		HostName:     l.HostName,
+		UserName:     l.UserName,
+		LockTime:     l.Time,
+		Reason:       reason,
+	})
+	return l, nil
+}
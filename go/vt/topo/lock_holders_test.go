@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestLockHolders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "-80"))
+
+	// Nothing is locked yet.
+	l, err := ts.GetKeyspaceLockHolder(ctx, "ks")
+	require.NoError(t, err)
+	assert.Nil(t, l)
+	l, err = ts.GetShardLockHolder(ctx, "ks", "-80")
+	require.NoError(t, err)
+	assert.Nil(t, l)
+
+	_, unlockKeyspace, err := ts.LockKeyspace(ctx, "ks", "Reshard")
+	require.NoError(t, err)
+	_, unlockShard, err := ts.LockShard(ctx, "ks", "-80", "PlannedReparentShard")
+	require.NoError(t, err)
+
+	l, err = ts.GetKeyspaceLockHolder(ctx, "ks")
+	require.NoError(t, err)
+	require.NotNil(t, l)
+	assert.Equal(t, "Reshard", l.Action)
+	assert.Equal(t, "Running", l.Status)
+	l, err = ts.GetShardLockHolder(ctx, "ks", "-80")
+	require.NoError(t, err)
+	require.NotNil(t, l)
+	assert.Equal(t, "PlannedReparentShard", l.Action)
+
+	// A reason is required to force the release of a lock.
+	_, err = ts.ForceUnlockShard(ctx, "ks", "-80", "")
+	require.Error(t, err)
+
+	l, err = ts.ForceUnlockShard(ctx, "ks", "-80", "the reparent is stuck")
+	require.NoError(t, err)
+	assert.Equal(t, "PlannedReparentShard", l.Action)
+	l, err = ts.GetShardLockHolder(ctx, "ks", "-80")
+	require.NoError(t, err)
+	assert.Nil(t, l)
+
+	// The shard can be locked again right away.
+	lockCtx, lockCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer lockCancel()
+	_, unlockShardAgain, err := ts.LockShard(lockCtx, "ks", "-80", "EmergencyReparentShard")
+	require.NoError(t, err)
+	unlockShardAgain(&err)
+	require.NoError(t, err)
+
+	// Releasing an unlocked lock fails.
+	_, err = ts.ForceUnlockShard(ctx, "ks", "-80", "again")
+	require.True(t, topo.IsErrType(err, topo.NoNode), "unexpected error: %v", err)
+
+	// The original holder of the shard lock can no longer release it.
+	unlockShard(&err)
+	require.Error(t, err)
+
+	err = nil
+	unlockKeyspace(&err)
+	require.NoError(t, err)
+	l, err = ts.GetKeyspaceLockHolder(ctx, "ks")
+	require.NoError(t, err)
+	assert.Nil(t, l)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/pflag"
 
 	_flag "vitess.io/vitess/go/internal/flag"
+	"vitess.io/vitess/go/stats"
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/proto/vtrpc"
@@ -49,6 +50,48 @@ var (
 	RemoteOperationTimeout = 15 * time.Second
 )
 
+var (
+	lockWaitTimings = stats.NewMultiTimings(
+		"TopoLockWaitTimings",
+		"Time waited to acquire the keyspace and shard locks",
+		[]string{"Type", "Result"})
+
+	lockHoldTimings = stats.NewTimings(
+		"TopoLockHoldTimings",
+		"Time the keyspace and shard locks were held for",
+		"Type")
+
+	locksHeld = stats.NewGaugesWithSingleLabel(
+		"TopoLocksHeld",
+		"Number of keyspace and shard locks currently held by this process",
+		"Type")
+)
+
+const (
+	keyspaceLockType = "Keyspace"
+	shardLockType    = "Shard"
+)
+
+// recordLockWait records the time waited for a lock of the given type since
+// startTime, and whether it was acquired.
+func recordLockWait(lockType string, startTime time.Time, err error) {
+	result := "Acquired"
+	if err != nil {
+		result = "Failed"
+	}
+	lockWaitTimings.Record([]string{lockType, result}, startTime)
+	if err == nil {
+		locksHeld.Add(lockType, 1)
+	}
+}
+
+// recordLockHold records the time a lock of the given type was held for since
+// acquiredTime, when it is released.
+func recordLockHold(lockType string, acquiredTime time.Time) {
+	lockHoldTimings.Record(lockType, acquiredTime)
+	locksHeld.Add(lockType, -1)
+}
+
 // Lock describes a long-running lock on a keyspace or a shard.
 // It needs to be public as we JSON-serialize it.
 type Lock struct {
@@ -145,10 +188,13 @@ func (ts *Server) LockKeyspace(ctx context.Context, keyspace, action string) (co
 
 	// lock
 	l := newLock(action)
+	startTime := time.Now()
 	lockDescriptor, err := l.lockKeyspace(ctx, ts, keyspace)
+	recordLockWait(keyspaceLockType, startTime, err)
 	if err != nil {
 		return nil, nil, err
 	}
+	acquiredTime := time.Now()
 
 	// and update our structure
 	i.info[keyspace] = &lockInfo{
@@ -169,6 +215,7 @@ func (ts *Server) LockKeyspace(ctx context.Context, keyspace, action string) (co
 		}
 
 		err := l.unlockKeyspace(ctx, ts, keyspace, lockDescriptor, *finalErr)
+		recordLockHold(keyspaceLockType, acquiredTime)
 		if *finalErr != nil {
 			if err != nil {
 				// both error are set, just log the unlock error
@@ -337,6 +384,7 @@ func (ts *Server) internalLockShard(ctx context.Context, keyspace, shard, action
 
 	// lock
 	l := newLock(action)
+	startTime := time.Now()
 	var lockDescriptor LockDescriptor
 	var err error
 	if isBlocking {
@@ -344,9 +392,11 @@ func (ts *Server) internalLockShard(ctx context.Context, keyspace, shard, action
 	} else {
 		lockDescriptor, err = l.tryLockShard(ctx, ts, keyspace, shard)
 	}
+	recordLockWait(shardLockType, startTime, err)
 	if err != nil {
 		return nil, nil, err
 	}
+	acquiredTime := time.Now()
 
 	// and update our structure
 	i.info[mapKey] = &lockInfo{
@@ -367,6 +417,7 @@ func (ts *Server) internalLockShard(ctx context.Context, keyspace, shard, action
 		}
 
 		err := l.unlockShard(ctx, ts, keyspace, shard, lockDescriptor, *finalErr)
+		recordLockHold(shardLockType, acquiredTime)
 		if *finalErr != nil {
 			if err != nil {
 				// both error are set, just log the unlock error
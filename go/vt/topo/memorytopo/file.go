@@ -47,6 +47,11 @@ func (c *Conn) Create(ctx context.Context, filePath string, contents []byte) (to
 		return nil, c.factory.err
 	}
 
+	return c.createLocked(filePath, contents)
+}
+
+// createLocked creates a file. The factory mutex must be held.
+func (c *Conn) createLocked(filePath string, contents []byte) (topo.Version, error) {
 	// Get the parent dir.
 	dir, file := path.Split(filePath)
 	p := c.factory.getOrCreatePath(c.cell, dir)
@@ -93,6 +98,11 @@ func (c *Conn) Update(ctx context.Context, filePath string, contents []byte, ver
 		return nil, c.factory.err
 	}
 
+	return c.updateLocked(filePath, contents, version)
+}
+
+// updateLocked updates a file. The factory mutex must be held.
+func (c *Conn) updateLocked(filePath string, contents []byte, version topo.Version) (topo.Version, error) {
 	// Get the parent dir, we'll need it in case of creation.
 	dir, file := path.Split(filePath)
 	p := c.factory.nodeByPath(c.cell, dir)
@@ -245,6 +255,105 @@ func gatherChildren(n *node, dirPath string) []topo.KVInfo {
 	return result
 }
 
+// GetMulti is part of the topo.Conn interface.
+func (c *Conn) GetMulti(ctx context.Context, filePaths []string) ([]topo.KVInfo, error) {
+	c.factory.callstats.Add([]string{"GetMulti"}, 1)
+
+	if err := c.dial(ctx); err != nil {
+		return nil, err
+	}
+
+	c.factory.mu.Lock()
+	defer c.factory.mu.Unlock()
+
+	if c.factory.err != nil {
+		return nil, c.factory.err
+	}
+
+	result := make([]topo.KVInfo, len(filePaths))
+	for i, filePath := range filePaths {
+		result[i].Key = []byte(filePath)
+		n := c.factory.nodeByPath(c.cell, filePath)
+		if n == nil {
+			continue
+		}
+		if n.isDirectory() {
+			return nil, fmt.Errorf("cannot GetMulti() directory %v in cell %v", filePath, c.cell)
+		}
+		result[i].Value = n.contents
+		result[i].Version = NodeVersion(n.version)
+	}
+	return result, nil
+}
+
+// Txn is part of the topo.Conn interface.
+func (c *Conn) Txn(ctx context.Context, ops []topo.TxnOp) ([]topo.Version, error) {
+	c.factory.callstats.Add([]string{"Txn"}, 1)
+
+	if err := c.dial(ctx); err != nil {
+		return nil, err
+	}
+
+	c.factory.mu.Lock()
+	defer c.factory.mu.Unlock()
+
+	if c.factory.err != nil {
+		return nil, c.factory.err
+	}
+
+	// Check all the conditions first, so that we either apply all
+	// the operations, or none.
+	for _, op := range ops {
+		n := c.factory.nodeByPath(c.cell, op.FilePath)
+		if n != nil && n.isDirectory() {
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "Txn(%v, %v) failed: it's a directory", c.cell, op.FilePath)
+		}
+		switch op.Type {
+		case topo.TxnOpCreate:
+			if n != nil {
+				return nil, topo.NewError(topo.NodeExists, op.FilePath)
+			}
+		case topo.TxnOpUpdate, topo.TxnOpDelete, topo.TxnOpCheck:
+			if n == nil {
+				if op.Type == topo.TxnOpUpdate && op.Version == nil {
+					continue
+				}
+				return nil, topo.NewError(topo.NoNode, op.FilePath)
+			}
+			if op.Version != nil && n.version != uint64(op.Version.(NodeVersion)) {
+				return nil, topo.NewError(topo.BadVersion, op.FilePath)
+			}
+		default:
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "unknown transaction operation type %v", op.Type)
+		}
+	}
+
+	versions := make([]topo.Version, len(ops))
+	for i, op := range ops {
+		var err error
+		switch op.Type {
+		case topo.TxnOpCreate:
+			contents := op.Contents
+			if contents == nil {
+				contents = []byte{}
+			}
+			versions[i], err = c.createLocked(op.FilePath, contents)
+		case topo.TxnOpUpdate:
+			contents := op.Contents
+			if contents == nil {
+				contents = []byte{}
+			}
+			versions[i], err = c.updateLocked(op.FilePath, contents, op.Version)
+		case topo.TxnOpDelete:
+			err = c.deleteLocked(op.FilePath, op.Version)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return versions, nil
+}
+
 // Delete is part of topo.Conn interface.
 func (c *Conn) Delete(ctx context.Context, filePath string, version topo.Version) error {
 	c.factory.callstats.Add([]string{"Delete"}, 1)
@@ -260,6 +369,11 @@ func (c *Conn) Delete(ctx context.Context, filePath string, version topo.Version
 		return c.factory.err
 	}
 
+	return c.deleteLocked(filePath, version)
+}
+
+// deleteLocked deletes a file. The factory mutex must be held.
+func (c *Conn) deleteLocked(filePath string, version topo.Version) error {
 	// Get the parent dir.
 	dir, file := path.Split(filePath)
 	p := c.factory.nodeByPath(c.cell, dir)
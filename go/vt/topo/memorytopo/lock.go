@@ -115,6 +115,42 @@ func (ld *memoryTopoLockDescriptor) Unlock(ctx context.Context) error {
 	return ld.c.unlock(ctx, ld.dirPath)
 }
 
+// LockContents is part of the topo.LockInspector interface.
+func (c *Conn) LockContents(ctx context.Context, dirPath string) (string, error) {
+	c.factory.callstats.Add([]string{"LockContents"}, 1)
+
+	if err := c.dial(ctx); err != nil {
+		return "", err
+	}
+
+	c.factory.mu.Lock()
+	defer c.factory.mu.Unlock()
+
+	n := c.factory.nodeByPath(c.cell, dirPath)
+	if n == nil || n.lock == nil {
+		return "", topo.NewError(topo.NoNode, dirPath)
+	}
+	return n.lockContents, nil
+}
+
+// ForceUnlock is part of the topo.LockInspector interface.
+func (c *Conn) ForceUnlock(ctx context.Context, dirPath string) error {
+	c.factory.callstats.Add([]string{"ForceUnlock"}, 1)
+
+	if err := c.dial(ctx); err != nil {
+		return err
+	}
+
+	c.factory.mu.Lock()
+	n := c.factory.nodeByPath(c.cell, dirPath)
+	locked := n != nil && n.lock != nil
+	c.factory.mu.Unlock()
+	if !locked {
+		return topo.NewError(topo.NoNode, dirPath)
+	}
+	return c.unlock(ctx, dirPath)
+}
+
 func (c *Conn) unlock(ctx context.Context, dirPath string) error {
 	if c.closed.Load() {
 		return ErrConnectionClosed
@@ -67,18 +67,21 @@ const (
 
 // Filenames for all object types.
 const (
-	CellInfoFile          = "CellInfo"
-	CellsAliasFile        = "CellsAlias"
-	KeyspaceFile          = "Keyspace"
-	ShardFile             = "Shard"
-	VSchemaFile           = "VSchema"
-	ShardReplicationFile  = "ShardReplication"
-	TabletFile            = "Tablet"
-	SrvVSchemaFile        = "SrvVSchema"
-	SrvKeyspaceFile       = "SrvKeyspace"
-	RoutingRulesFile      = "RoutingRules"
-	ExternalClustersFile  = "ExternalClusters"
-	ShardRoutingRulesFile = "ShardRoutingRules"
+	CellInfoFile           = "CellInfo"
+	CellsAliasFile         = "CellsAlias"
+	KeyspaceFile           = "Keyspace"
+	ShardFile              = "Shard"
+	VSchemaFile            = "VSchema"
+	ShardReplicationFile   = "ShardReplication"
+	TabletFile             = "Tablet"
+	SrvVSchemaFile         = "SrvVSchema"
+	SrvKeyspaceFile        = "SrvKeyspace"
+	RoutingRulesFile       = "RoutingRules"
+	ExternalClustersFile   = "ExternalClusters"
+	ShardRoutingRulesFile  = "ShardRoutingRules"
+	QuotaRulesFile         = "QuotaRules"
+	TenantRoutingRulesFile = "TenantRoutingRules"
+	BackupScheduleFile     = "BackupSchedule"
 )
 
 // Path for all object types.
@@ -367,7 +370,16 @@ func (ts *Server) OpenExternalVitessClusterServer(ctx context.Context, clusterNa
 		return nil, fmt.Errorf("no vitess cluster found with name %s", clusterName)
 	}
 	var externalTopo *Server
-	externalTopo, err = OpenServer(vc.TopoConfig.TopoType, vc.TopoConfig.Server, vc.TopoConfig.Root)
+	if len(vc.EncryptedSecrets) > 0 {
+		var secrets *topodata.ExternalClusterSecrets
+		secrets, err = DecryptExternalClusterSecrets(vc.EncryptedSecrets)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open external topo for config %s: %w", clusterName, err)
+		}
+		externalTopo, err = OpenServerWithSecrets(vc.TopoConfig.TopoType, vc.TopoConfig.Server, vc.TopoConfig.Root, secrets)
+	} else {
+		externalTopo, err = OpenServer(vc.TopoConfig.TopoType, vc.TopoConfig.Server, vc.TopoConfig.Root)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -248,6 +248,100 @@ func (ts *Server) UpdateShardFields(ctx context.Context, keyspace, shard string,
 	}
 }
 
+// UpdateShardsFields is a high level helper like UpdateShardFields, for
+// several shards of a keyspace: the shard records are read with a single
+// GetMulti, the update function is called on each of them, and they are
+// written back with a single Txn, so either all of them are updated, or
+// none is. If the write fails due to a version mismatch, the records are
+// re-read and the update is retried.
+// If keyspaceInfo is not nil, the write also checks the keyspace record
+// has not changed since keyspaceInfo was read, and ErrBadVersion is
+// returned if it has.
+// The shards for which the update method returns ErrNoUpdateNeeded are
+// not written. It returns the updated ShardInfos.
+func (ts *Server) UpdateShardsFields(ctx context.Context, keyspace string, shards []string, keyspaceInfo *KeyspaceInfo, update func(*ShardInfo) error) ([]*ShardInfo, error) {
+	if err := ValidateKeyspaceName(keyspace); err != nil {
+		return nil, err
+	}
+	filePaths := make([]string, 0, len(shards)+1)
+	for _, shard := range shards {
+		if _, _, err := ValidateShardName(shard); err != nil {
+			return nil, err
+		}
+		filePaths = append(filePaths, shardFilePath(keyspace, shard))
+	}
+	keyspacePath := path.Join(KeyspacesPath, keyspace, KeyspaceFile)
+	if keyspaceInfo != nil {
+		filePaths = append(filePaths, keyspacePath)
+	}
+
+	span, ctx := trace.NewSpan(ctx, "TopoServer.UpdateShardsFields")
+	span.Annotate("keyspace", keyspace)
+	span.Annotate("shards", strings.Join(shards, ","))
+	defer span.Finish()
+
+	for {
+		kvs, err := ts.globalCell.GetMulti(ctx, filePaths)
+		if err != nil {
+			return nil, err
+		}
+		var ops []TxnOp
+		if keyspaceInfo != nil {
+			// The keyspace record is the last one read.
+			if version := kvs[len(shards)].Version; version == nil || version.String() != keyspaceInfo.version.String() {
+				return nil, NewError(BadVersion, keyspacePath)
+			}
+			ops = append(ops, TxnOp{Type: TxnOpCheck, FilePath: keyspacePath, Version: keyspaceInfo.version})
+		}
+
+		var updated []*ShardInfo
+		for i, shard := range shards {
+			if kvs[i].Version == nil {
+				return nil, NewError(NoNode, filePaths[i])
+			}
+			value := &topodatapb.Shard{}
+			if err := value.UnmarshalVT(kvs[i].Value); err != nil {
+				return nil, vterrors.Wrapf(err, "UpdateShardsFields(%v,%v): bad shard data", keyspace, shard)
+			}
+			si := NewShardInfo(keyspace, shard, value, kvs[i].Version)
+			if err := update(si); err != nil {
+				if IsErrType(err, NoUpdateNeeded) {
+					continue
+				}
+				return nil, err
+			}
+			data, err := si.Shard.MarshalVT()
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, TxnOp{Type: TxnOpUpdate, FilePath: filePaths[i], Contents: data, Version: si.version})
+			updated = append(updated, si)
+		}
+		if len(updated) == 0 {
+			return nil, nil
+		}
+
+		versions, err := ts.globalCell.Txn(ctx, ops)
+		if IsErrType(err, BadVersion) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions = versions[len(ops)-len(updated):]
+		for i, si := range updated {
+			si.version = versions[i]
+			event.Dispatch(&events.ShardChange{
+				KeyspaceName: si.Keyspace(),
+				ShardName:    si.ShardName(),
+				Shard:        si.Shard,
+				Status:       "updated",
+			})
+		}
+		return updated, nil
+	}
+}
+
 // CreateShard creates a new shard and tries to fill in the right information.
 // This will lock the Keyspace, as we may be looking at other shard servedTypes.
 // Using GetOrCreateShard is probably a better idea for most use cases.
@@ -355,6 +449,9 @@ func (ts *Server) GetOrCreateShard(ctx context.Context, keyspace, shard string)
 // DeleteShard wraps the underlying conn.Delete
 // and dispatches the event.
 func (ts *Server) DeleteShard(ctx context.Context, keyspace, shard string) error {
+	if err := ts.SaveBackupSchedule(ctx, keyspace, shard, nil); err != nil {
+		return err
+	}
 	shardPath := shardFilePath(keyspace, shard)
 	if err := ts.globalCell.Delete(ctx, shardPath, nil); err != nil {
 		return err
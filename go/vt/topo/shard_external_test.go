@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestServerUpdateShardsFields(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx)
+	defer ts.Close()
+
+	keyspace := "ks"
+	shards := []string{"-80", "80-"}
+	require.NoError(t, ts.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}))
+	for _, shard := range shards {
+		require.NoError(t, ts.CreateShard(ctx, keyspace, shard))
+	}
+	isPrimaryServing := func() map[string]bool {
+		serving := make(map[string]bool)
+		for _, shard := range shards {
+			si, err := ts.GetShard(ctx, keyspace, shard)
+			require.NoError(t, err)
+			serving[shard] = si.IsPrimaryServing
+		}
+		return serving
+	}
+	require.Equal(t, map[string]bool{"-80": true, "80-": true}, isPrimaryServing())
+
+	// Both shards are updated, in one transaction.
+	updated, err := ts.UpdateShardsFields(ctx, keyspace, shards, nil, func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = si.ShardName() == "80-"
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, updated, 2)
+	require.Equal(t, map[string]bool{"-80": false, "80-": true}, isPrimaryServing())
+
+	// Shards that need no update are not written.
+	updated, err = ts.UpdateShardsFields(ctx, keyspace, shards, nil, func(si *topo.ShardInfo) error {
+		if si.IsPrimaryServing {
+			return topo.NewError(topo.NoUpdateNeeded, si.ShardName())
+		}
+		si.IsPrimaryServing = true
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	require.Equal(t, "-80", updated[0].ShardName())
+	require.Equal(t, map[string]bool{"-80": true, "80-": true}, isPrimaryServing())
+
+	// A missing shard fails the whole update.
+	_, err = ts.UpdateShardsFields(ctx, keyspace, []string{"-80", "c0-"}, nil, func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = false
+		return nil
+	})
+	require.True(t, topo.IsErrType(err, topo.NoNode), err)
+	require.Equal(t, map[string]bool{"-80": true, "80-": true}, isPrimaryServing())
+
+	// The update succeeds while the keyspace record is unchanged, and
+	// fails once it changed.
+	keyspaceInfo, err := ts.GetKeyspace(ctx, keyspace)
+	require.NoError(t, err)
+	_, err = ts.UpdateShardsFields(ctx, keyspace, shards, keyspaceInfo, func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = false
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"-80": false, "80-": false}, isPrimaryServing())
+
+	lockCtx, unlock, err := ts.LockKeyspace(ctx, keyspace, "TestServerUpdateShardsFields")
+	require.NoError(t, err)
+	changed, err := ts.GetKeyspace(ctx, keyspace)
+	require.NoError(t, err)
+	changed.DurabilityPolicy = "semi_sync"
+	require.NoError(t, ts.UpdateKeyspace(lockCtx, changed))
+	unlock(&err)
+	require.NoError(t, err)
+
+	_, err = ts.UpdateShardsFields(ctx, keyspace, shards, keyspaceInfo, func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = true
+		return nil
+	})
+	require.True(t, topo.IsErrType(err, topo.BadVersion), err)
+	require.Equal(t, map[string]bool{"-80": false, "80-": false}, isPrimaryServing())
+}
@@ -25,7 +25,10 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 )
 
-var _ Conn = (*StatsConn)(nil)
+var (
+	_ Conn          = (*StatsConn)(nil)
+	_ LockInspector = (*StatsConn)(nil)
+)
 
 var (
 	topoStatsConnTimings = stats.NewMultiTimings(
@@ -128,6 +131,39 @@ func (st *StatsConn) List(ctx context.Context, filePathPrefix string) ([]KVInfo,
 	return bytes, err
 }
 
+// GetMulti is part of the Conn interface
+func (st *StatsConn) GetMulti(ctx context.Context, filePaths []string) ([]KVInfo, error) {
+	startTime := time.Now()
+	statsKey := []string{"GetMulti", st.cell}
+	defer topoStatsConnTimings.Record(statsKey, startTime)
+	res, err := st.conn.GetMulti(ctx, filePaths)
+	if err != nil {
+		topoStatsConnErrors.Add(statsKey, int64(1))
+		return res, err
+	}
+	return res, err
+}
+
+// Txn is part of the Conn interface
+func (st *StatsConn) Txn(ctx context.Context, ops []TxnOp) ([]Version, error) {
+	statsKey := []string{"Txn", st.cell}
+	if st.readOnly {
+		for _, op := range ops {
+			if op.Type != TxnOpCheck {
+				return nil, vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, statsKey[0], op.FilePath)
+			}
+		}
+	}
+	startTime := time.Now()
+	defer topoStatsConnTimings.Record(statsKey, startTime)
+	res, err := st.conn.Txn(ctx, ops)
+	if err != nil {
+		topoStatsConnErrors.Add(statsKey, int64(1))
+		return res, err
+	}
+	return res, err
+}
+
 // Delete is part of the Conn interface
 func (st *StatsConn) Delete(ctx context.Context, filePath string, version Version) error {
 	statsKey := []string{"Delete", st.cell}
@@ -176,6 +212,41 @@ func (st *StatsConn) internalLock(ctx context.Context, dirPath, contents string,
 	return res, err
 }
 
+// LockContents is part of the LockInspector interface
+func (st *StatsConn) LockContents(ctx context.Context, dirPath string) (string, error) {
+	li, ok := st.conn.(LockInspector)
+	if !ok {
+		return "", vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "the topo server does not support lock inspection")
+	}
+	startTime := time.Now()
+	statsKey := []string{"LockContents", st.cell}
+	defer topoStatsConnTimings.Record(statsKey, startTime)
+	res, err := li.LockContents(ctx, dirPath)
+	if err != nil && !IsErrType(err, NoNode) {
+		topoStatsConnErrors.Add(statsKey, int64(1))
+	}
+	return res, err
+}
+
+// ForceUnlock is part of the LockInspector interface
+func (st *StatsConn) ForceUnlock(ctx context.Context, dirPath string) error {
+	statsKey := []string{"ForceUnlock", st.cell}
+	if st.readOnly {
+		return vterrors.Errorf(vtrpc.Code_READ_ONLY, readOnlyErrorStrFormat, statsKey[0], dirPath)
+	}
+	li, ok := st.conn.(LockInspector)
+	if !ok {
+		return vterrors.Errorf(vtrpc.Code_UNIMPLEMENTED, "the topo server does not support lock inspection")
+	}
+	startTime := time.Now()
+	defer topoStatsConnTimings.Record(statsKey, startTime)
+	err := li.ForceUnlock(ctx, dirPath)
+	if err != nil {
+		topoStatsConnErrors.Add(statsKey, int64(1))
+	}
+	return err
+}
+
 // Watch is part of the Conn interface
 func (st *StatsConn) Watch(ctx context.Context, filePath string) (current *WatchData, changes <-chan *WatchData, err error) {
 	startTime := time.Now()
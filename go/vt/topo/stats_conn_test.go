@@ -81,6 +81,29 @@ func (st *fakeConn) List(ctx context.Context, filePathPrefix string) (bytes []KV
 	return bytes, err
 }
 
+// GetMulti is part of the Conn interface
+func (st *fakeConn) GetMulti(ctx context.Context, filePaths []string) (res []KVInfo, err error) {
+	for _, filePath := range filePaths {
+		if filePath == "error" {
+			return res, fmt.Errorf("Dummy error")
+		}
+	}
+	return res, err
+}
+
+// Txn is part of the Conn interface
+func (st *fakeConn) Txn(ctx context.Context, ops []TxnOp) (res []Version, err error) {
+	if st.readOnly {
+		return nil, vterrors.Errorf(vtrpc.Code_READ_ONLY, "topo server connection is read-only")
+	}
+	for _, op := range ops {
+		if op.FilePath == "error" {
+			return res, fmt.Errorf("Dummy error")
+		}
+	}
+	return res, err
+}
+
 // Delete is part of the Conn interface
 func (st *fakeConn) Delete(ctx context.Context, filePath string, version Version) (err error) {
 	if st.readOnly {
@@ -285,6 +308,43 @@ func TestStatsConnTopoDelete(t *testing.T) {
 	}
 }
 
+// TestStatsConnTopoTxn emits stats on Txn
+func TestStatsConnTopoTxn(t *testing.T) {
+	conn := &fakeConn{}
+	statsConn := NewStatsConn("global", conn)
+	ctx := context.Background()
+
+	statsConn.Txn(ctx, []TxnOp{{Type: TxnOpCreate, FilePath: ""}})
+	timingCounts := topoStatsConnTimings.Counts()["Txn.global"]
+	if got, want := timingCounts, int64(1); got != want {
+		t.Errorf("stats were not properly recorded: got = %d, want = %d", got, want)
+	}
+
+	// error is zero before getting an error
+	errorCount := topoStatsConnErrors.Counts()["Txn.global"]
+	if got, want := errorCount, int64(0); got != want {
+		t.Errorf("stats were not properly recorded: got = %d, want = %d", got, want)
+	}
+
+	statsConn.Txn(ctx, []TxnOp{{Type: TxnOpCheck, FilePath: ""}, {Type: TxnOpDelete, FilePath: "error"}})
+
+	// error stats gets emitted
+	errorCount = topoStatsConnErrors.Counts()["Txn.global"]
+	if got, want := errorCount, int64(1); got != want {
+		t.Errorf("stats were not properly recorded: got = %d, want = %d", got, want)
+	}
+
+	// read-only connections only allow checks
+	statsConn.SetReadOnly(true)
+	if _, err := statsConn.Txn(ctx, []TxnOp{{Type: TxnOpCheck, FilePath: ""}}); err != nil {
+		t.Errorf("unexpected error for read-only check: %v", err)
+	}
+	_, err := statsConn.Txn(ctx, []TxnOp{{Type: TxnOpCheck, FilePath: ""}, {Type: TxnOpUpdate, FilePath: "/keyspaces/ks/Keyspace"}})
+	if got, want := vterrors.Code(err), vtrpc.Code_READ_ONLY; got != want {
+		t.Errorf("unexpected error code for read-only update: got = %v, want = %v", got, want)
+	}
+}
+
 // TestStatsConnTopoLock emits stats on Lock
 func TestStatsConnTopoLock(t *testing.T) {
 	conn := &fakeConn{}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/topo"
+)
+
+// checkTxn tests the GetMulti and Txn parts of the Conn API.
+func checkTxn(t *testing.T, ctx context.Context, ts *topo.Server) {
+	conn, err := ts.ConnForCell(ctx, LocalCellName)
+	require.NoError(t, err)
+
+	// A transaction creating several files, in new directories.
+	versions, err := conn.Txn(ctx, []topo.TxnOp{
+		{Type: topo.TxnOpCreate, FilePath: "/txn/a/file", Contents: []byte("a")},
+		{Type: topo.TxnOpCreate, FilePath: "/txn/b/file", Contents: []byte("b")},
+	})
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	kvs, err := conn.GetMulti(ctx, []string{"/txn/a/file", "/txn/missing", "/txn/b/file"})
+	require.NoError(t, err)
+	require.Len(t, kvs, 3)
+	assert.Equal(t, "/txn/a/file", string(kvs[0].Key))
+	assert.Equal(t, "a", string(kvs[0].Value))
+	assert.Equal(t, versions[0], kvs[0].Version)
+	assert.Equal(t, "/txn/missing", string(kvs[1].Key))
+	assert.Nil(t, kvs[1].Value)
+	assert.Nil(t, kvs[1].Version)
+	assert.Equal(t, "b", string(kvs[2].Value))
+	assert.Equal(t, versions[1], kvs[2].Version)
+
+	// A file with a stale version. Note we cannot use the version
+	// of another file of the first transaction, as some
+	// implementations use the same version for all the files
+	// modified by a transaction.
+	staleVersion, err := conn.Create(ctx, "/txn/d/file", []byte("d"))
+	require.NoError(t, err)
+	_, err = conn.Update(ctx, "/txn/d/file", []byte("d2"), staleVersion)
+	require.NoError(t, err)
+
+	// Failed conditions: nothing is applied.
+	failures := []struct {
+		name string
+		op   topo.TxnOp
+		err  topo.ErrorCode
+	}{
+		{
+			name: "create existing",
+			op:   topo.TxnOp{Type: topo.TxnOpCreate, FilePath: "/txn/a/file", Contents: []byte("c")},
+			err:  topo.NodeExists,
+		},
+		{
+			name: "delete missing",
+			op:   topo.TxnOp{Type: topo.TxnOpDelete, FilePath: "/txn/missing"},
+			err:  topo.NoNode,
+		},
+		{
+			name: "check missing",
+			op:   topo.TxnOp{Type: topo.TxnOpCheck, FilePath: "/txn/missing"},
+			err:  topo.NoNode,
+		},
+		{
+			name: "check bad version",
+			op:   topo.TxnOp{Type: topo.TxnOpCheck, FilePath: "/txn/d/file", Version: staleVersion},
+			err:  topo.BadVersion,
+		},
+	}
+	for _, failure := range failures {
+		_, err := conn.Txn(ctx, []topo.TxnOp{
+			{Type: topo.TxnOpUpdate, FilePath: "/txn/b/file", Contents: []byte("updated"), Version: versions[1]},
+			failure.op,
+		})
+		assert.Truef(t, topo.IsErrType(err, failure.err), "%s: expected %v, got %v", failure.name, failure.err, err)
+
+		contents, version, err := conn.Get(ctx, "/txn/b/file")
+		require.NoError(t, err)
+		assert.Equalf(t, "b", string(contents), "%s: transaction was partially applied", failure.name)
+		assert.Equal(t, versions[1], version)
+	}
+
+	// A transaction updating, deleting and checking files.
+	newVersions, err := conn.Txn(ctx, []topo.TxnOp{
+		{Type: topo.TxnOpCheck, FilePath: "/txn/a/file", Version: versions[0]},
+		{Type: topo.TxnOpUpdate, FilePath: "/txn/b/file", Contents: []byte("updated"), Version: versions[1]},
+		{Type: topo.TxnOpUpdate, FilePath: "/txn/c/file", Contents: []byte("c")},
+		{Type: topo.TxnOpDelete, FilePath: "/txn/a/file", Version: versions[0]},
+	})
+	require.NoError(t, err)
+	require.Len(t, newVersions, 4)
+	assert.Nil(t, newVersions[0])
+	assert.Nil(t, newVersions[3])
+
+	kvs, err = conn.GetMulti(ctx, []string{"/txn/a/file", "/txn/b/file", "/txn/c/file"})
+	require.NoError(t, err)
+	assert.Nil(t, kvs[0].Version)
+	assert.Equal(t, "updated", string(kvs[1].Value))
+	assert.Equal(t, newVersions[1], kvs[1].Version)
+	assert.Equal(t, "c", string(kvs[2].Value))
+	assert.Equal(t, newVersions[2], kvs[2].Version)
+}
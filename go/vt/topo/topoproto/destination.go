@@ -29,11 +29,18 @@ import (
 
 // ParseDestination parses the string representation of a Destination
 // of the form keyspace:shard@tablet_type. You can use a / instead of a :.
+// The target may end with a set of tablet tags, which are validated but not
+// returned; use ParseTabletTags to extract them.
 func ParseDestination(targetString string, defaultTabletType topodatapb.TabletType) (string, topodatapb.TabletType, key.Destination, error) {
 	var dest key.Destination
 	var keyspace string
 	tabletType := defaultTabletType
 
+	targetString, _, err := splitTabletTags(targetString)
+	if err != nil {
+		return keyspace, tabletType, dest, err
+	}
+
 	last := strings.LastIndexAny(targetString, "@")
 	if last != -1 {
 		// No need to check the error. UNKNOWN will be returned on
@@ -77,3 +84,44 @@ func ParseDestination(targetString string, defaultTabletType topodatapb.TabletTy
 	keyspace = targetString
 	return keyspace, tabletType, dest, nil
 }
+
+// ParseTabletTags returns the tablet tags of a target string of the form
+// keyspace@tablet_type{key1=value1,key2=value2}. Only tablets that carry all
+// of the tags are eligible to serve queries for such a target. It returns a
+// nil map if the target has no tags.
+func ParseTabletTags(targetString string) (map[string]string, error) {
+	_, tags, err := splitTabletTags(targetString)
+	return tags, err
+}
+
+// splitTabletTags splits a target string into the target without its tablet
+// tags, and the tablet tags.
+func splitTabletTags(targetString string) (string, map[string]string, error) {
+	if !strings.HasSuffix(targetString, "}") {
+		return targetString, nil, nil
+	}
+	start := strings.LastIndexByte(targetString, '{')
+	if start == -1 {
+		return targetString, nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid tablet tags provided. Couldn't find tags start '{'")
+	}
+	tags, err := ParseTags(targetString[start+1 : len(targetString)-1])
+	return targetString[:start], tags, err
+}
+
+// ParseTags parses a list of tablet tags of the form
+// key1=value1,key2=value2. It returns a nil map if the list is empty.
+func ParseTags(tagsString string) (map[string]string, error) {
+	if strings.TrimSpace(tagsString) == "" {
+		return nil, nil
+	}
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(tagsString, ",") {
+		k, v, ok := strings.Cut(tag, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid tablet tag %q, expected key=value", tag)
+		}
+		tags[k] = strings.TrimSpace(v)
+	}
+	return tags, nil
+}
@@ -87,6 +87,15 @@ func TestParseDestination(t *testing.T) {
 		keyspace:     "ks",
 		dest:         key.DestinationShard("-80"),
 		tabletType:   topodatapb.TabletType_PRIMARY,
+	}, {
+		targetString: "ks@replica{tag=analytics}",
+		keyspace:     "ks",
+		tabletType:   topodatapb.TabletType_REPLICA,
+	}, {
+		targetString: "ks:-80@rdonly{}",
+		keyspace:     "ks",
+		dest:         key.DestinationShard("-80"),
+		tabletType:   topodatapb.TabletType_RDONLY,
 	}}
 
 	for _, tcase := range testcases {
@@ -121,3 +130,40 @@ func TestParseDestination(t *testing.T) {
 		t.Errorf("executorExec error: %v, want %s", err, want)
 	}
 }
+
+func TestParseTabletTags(t *testing.T) {
+	testcases := []struct {
+		targetString string
+		tags         map[string]string
+		err          string
+	}{{
+		targetString: "ks@replica",
+	}, {
+		targetString: "ks@replica{}",
+	}, {
+		targetString: "ks@replica{tag=analytics}",
+		tags:         map[string]string{"tag": "analytics"},
+	}, {
+		targetString: "ks:-80@rdonly{tag=analytics, region=us-east}",
+		tags:         map[string]string{"tag": "analytics", "region": "us-east"},
+	}, {
+		targetString: "ks@replica{tag}",
+		err:          "invalid tablet tag \"tag\", expected key=value",
+	}, {
+		targetString: "ks@replica}",
+		err:          "invalid tablet tags provided. Couldn't find tags start '{'",
+	}}
+
+	for _, tcase := range testcases {
+		tags, err := ParseTabletTags(tcase.targetString)
+		if tcase.err != "" {
+			if err == nil || err.Error() != tcase.err {
+				t.Errorf("ParseTabletTags(%s) error: %v, want %s", tcase.targetString, err, tcase.err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(tags, tcase.tags) {
+			t.Errorf("ParseTabletTags(%s) - got: (%v, %v), want %v", tcase.targetString, tags, err, tcase.tags)
+		}
+	}
+}
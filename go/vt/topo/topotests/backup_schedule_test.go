@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topotests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
+)
+
+func TestBackupSchedule(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	defer ts.Close()
+
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks", "0"))
+
+	_, err := ts.GetBackupSchedule(ctx, "ks", "0")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+
+	schedule := &topodatapb.BackupSchedule{
+		CronSpec:          "0 3 * * *",
+		MinRetentionTime:  &vttimepb.Duration{Seconds: 7 * 24 * 3600},
+		MinRetentionCount: 3,
+	}
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "0", schedule))
+	got, err := ts.GetBackupSchedule(ctx, "ks", "0")
+	require.NoError(t, err)
+	utils.MustMatch(t, schedule, got)
+
+	schedule.CronSpec = "@hourly"
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "0", schedule))
+	got, err = ts.GetBackupSchedule(ctx, "ks", "0")
+	require.NoError(t, err)
+	utils.MustMatch(t, schedule, got)
+
+	// the shard itself is untouched
+	si, err := ts.GetShard(ctx, "ks", "0")
+	require.NoError(t, err)
+	assert.True(t, si.IsPrimaryServing)
+
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "0", nil))
+	_, err = ts.GetBackupSchedule(ctx, "ks", "0")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+	// removing it again is a no-op
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "0", nil))
+
+	// deleting the shard deletes its schedule
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "ks", "0", schedule))
+	require.NoError(t, ts.DeleteShard(ctx, "ks", "0"))
+	_, err = ts.GetBackupSchedule(ctx, "ks", "0")
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+
+	_, err = ts.GetBackupSchedule(ctx, "ks", "0/1")
+	assert.Error(t, err)
+}
@@ -29,8 +29,10 @@ import (
 
 func TestRebuildVSchema(t *testing.T) {
 	emptySrvVSchema := &vschemapb.SrvVSchema{
-		RoutingRules:      &vschemapb.RoutingRules{},
-		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		RoutingRules:       &vschemapb.RoutingRules{},
+		ShardRoutingRules:  &vschemapb.ShardRoutingRules{},
+		QuotaRules:         &vschemapb.QuotaRules{},
+		TenantRoutingRules: &vschemapb.TenantRoutingRules{},
 	}
 
 	// Set up topology.
@@ -52,8 +54,10 @@ func TestRebuildVSchema(t *testing.T) {
 
 	// create a keyspace, rebuild, should see an empty entry
 	emptyKs1SrvVSchema := &vschemapb.SrvVSchema{
-		RoutingRules:      &vschemapb.RoutingRules{},
-		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		RoutingRules:       &vschemapb.RoutingRules{},
+		ShardRoutingRules:  &vschemapb.ShardRoutingRules{},
+		QuotaRules:         &vschemapb.QuotaRules{},
+		TenantRoutingRules: &vschemapb.TenantRoutingRules{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
 			"ks1": {},
 		},
@@ -81,8 +85,10 @@ func TestRebuildVSchema(t *testing.T) {
 		t.Errorf("RebuildVSchema failed: %v", err)
 	}
 	wanted1 := &vschemapb.SrvVSchema{
-		RoutingRules:      &vschemapb.RoutingRules{},
-		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		RoutingRules:       &vschemapb.RoutingRules{},
+		ShardRoutingRules:  &vschemapb.ShardRoutingRules{},
+		QuotaRules:         &vschemapb.QuotaRules{},
+		TenantRoutingRules: &vschemapb.TenantRoutingRules{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
 			"ks1": keyspace1,
 		},
@@ -122,8 +128,10 @@ func TestRebuildVSchema(t *testing.T) {
 		t.Errorf("RebuildVSchema failed: %v", err)
 	}
 	wanted2 := &vschemapb.SrvVSchema{
-		RoutingRules:      &vschemapb.RoutingRules{},
-		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		RoutingRules:       &vschemapb.RoutingRules{},
+		ShardRoutingRules:  &vschemapb.ShardRoutingRules{},
+		QuotaRules:         &vschemapb.QuotaRules{},
+		TenantRoutingRules: &vschemapb.TenantRoutingRules{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
 			"ks1": keyspace1,
 			"ks2": keyspace2,
@@ -160,8 +168,10 @@ func TestRebuildVSchema(t *testing.T) {
 		t.Errorf("RebuildVSchema failed: %v", err)
 	}
 	wanted3 := &vschemapb.SrvVSchema{
-		RoutingRules:      rr,
-		ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+		RoutingRules:       rr,
+		ShardRoutingRules:  &vschemapb.ShardRoutingRules{},
+		QuotaRules:         &vschemapb.QuotaRules{},
+		TenantRoutingRules: &vschemapb.TenantRoutingRules{},
 		Keyspaces: map[string]*vschemapb.Keyspace{
 			"ks1": keyspace1,
 			"ks2": keyspace2,
@@ -20,11 +20,13 @@ import (
 	"bytes"
 	"fmt"
 	"path"
+	"slices"
 
 	"context"
 
 	"github.com/z-division/go-zookeeper/zk"
 
+	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo"
 )
 
@@ -130,3 +132,220 @@ func (zs *Server) recursiveDeleteParentIfEmpty(ctx context.Context, filePath str
 		return err
 	}
 }
+
+// GetMulti is part of the topo.Conn interface.
+// ZooKeeper has no multi-read operation, so the files are read one by one.
+func (zs *Server) GetMulti(ctx context.Context, filePaths []string) ([]topo.KVInfo, error) {
+	results := make([]topo.KVInfo, len(filePaths))
+	for i, filePath := range filePaths {
+		zkPath := path.Join(zs.root, filePath)
+		results[i].Key = []byte(filePath)
+		contents, stat, err := zs.conn.Get(ctx, zkPath)
+		if err == zk.ErrNoNode {
+			continue
+		}
+		if err != nil {
+			return nil, convertError(err, zkPath)
+		}
+		results[i].Value = contents
+		results[i].Version = ZKVersion(stat.Version)
+	}
+	return results, nil
+}
+
+// maxTxnAttempts bounds how many times Txn rebuilds its requests when a
+// request it derived from a read loses a race: a parent directory created
+// or deleted concurrently, or the file of an unconditional update created
+// or deleted concurrently.
+const maxTxnAttempts = 5
+
+// txnRequests are the requests of a single zk multi built for a Txn.
+type txnRequests struct {
+	requests []any
+	// opIndex maps each request to the index of its operation, or -1
+	// for the creation of a parent directory.
+	opIndex []int
+	// opRequest maps each operation to the index of its request.
+	opRequest []int
+}
+
+// Txn is part of the topo.Conn interface.
+// All the operations, including the creation of the parent directories
+// of the created files, are applied in a single zk multi. The reads
+// used to build that multi only decide which requests it contains: if
+// they are stale, the multi fails and is rebuilt.
+func (zs *Server) Txn(ctx context.Context, ops []topo.TxnOp) ([]topo.Version, error) {
+	for attempt := 1; ; attempt++ {
+		txn, err := zs.buildTxnRequests(ctx, ops)
+		if err != nil {
+			return nil, err
+		}
+		responses, err := zs.conn.Multi(ctx, txn.requests...)
+		if err == nil {
+			return zs.txnCommitted(ctx, ops, txn, responses), nil
+		}
+
+		failed := -1
+		for i, resp := range responses {
+			if resp.Error == zk.ErrNoNode || resp.Error == zk.ErrNodeExists || resp.Error == zk.ErrBadVersion {
+				failed = i
+				break
+			}
+		}
+		if failed == -1 || failed >= len(txn.requests) {
+			return nil, convertError(err, zs.root)
+		}
+		zkPath := txnRequestPath(txn.requests[failed])
+		if attempt < maxTxnAttempts && zs.txnRequestIsDerived(ops, txn, failed, responses[failed].Error) {
+			continue
+		}
+		return nil, convertError(responses[failed].Error, zkPath)
+	}
+}
+
+// buildTxnRequests builds the zk requests applying ops.
+func (zs *Server) buildTxnRequests(ctx context.Context, ops []topo.TxnOp) (*txnRequests, error) {
+	txn := &txnRequests{opRequest: make([]int, len(ops))}
+	createdDirs := make(map[string]bool)
+	add := func(request any, opIndex int) {
+		txn.requests = append(txn.requests, request)
+		txn.opIndex = append(txn.opIndex, opIndex)
+	}
+	addCreate := func(zkPath string, contents []byte, opIndex int) error {
+		dirs, err := zs.missingParentDirectories(ctx, zkPath, createdDirs)
+		if err != nil {
+			return err
+		}
+		for _, dir := range dirs {
+			createdDirs[dir] = true
+			add(&zk.CreateRequest{Path: dir, Acl: zk.WorldACL(PermDirectory)}, -1)
+		}
+		txn.opRequest[opIndex] = len(txn.requests)
+		add(&zk.CreateRequest{Path: zkPath, Data: contents, Acl: zk.WorldACL(PermFile)}, opIndex)
+		return nil
+	}
+
+	for i, op := range ops {
+		zkPath := path.Join(zs.root, op.FilePath)
+		zkVersion := int32(-1)
+		if op.Version != nil {
+			zkVersion = int32(op.Version.(ZKVersion))
+		}
+		switch op.Type {
+		case topo.TxnOpCreate:
+			if err := addCreate(zkPath, op.Contents, i); err != nil {
+				return nil, err
+			}
+		case topo.TxnOpUpdate:
+			if zkVersion == -1 {
+				// An unconditional update creates the file if it
+				// doesn't exist, which a set request cannot do.
+				exists, _, err := zs.conn.Exists(ctx, zkPath)
+				if err != nil {
+					return nil, convertError(err, zkPath)
+				}
+				if !exists {
+					if err := addCreate(zkPath, op.Contents, i); err != nil {
+						return nil, err
+					}
+					continue
+				}
+			}
+			txn.opRequest[i] = len(txn.requests)
+			add(&zk.SetDataRequest{Path: zkPath, Data: op.Contents, Version: zkVersion}, i)
+		case topo.TxnOpDelete:
+			txn.opRequest[i] = len(txn.requests)
+			add(&zk.DeleteRequest{Path: zkPath, Version: zkVersion}, i)
+		case topo.TxnOpCheck:
+			txn.opRequest[i] = len(txn.requests)
+			add(&zk.CheckVersionRequest{Path: zkPath, Version: zkVersion}, i)
+		default:
+			return nil, topo.NewError(topo.NoImplementation, op.Type.String())
+		}
+	}
+	return txn, nil
+}
+
+// missingParentDirectories returns the parent directories of zkPath
+// that do not exist and are not in created, from the top down.
+func (zs *Server) missingParentDirectories(ctx context.Context, zkPath string, created map[string]bool) ([]string, error) {
+	var dirs []string
+	for dir := path.Dir(zkPath); dir != zs.root && dir != "/" && dir != "."; dir = path.Dir(dir) {
+		if created[dir] {
+			break
+		}
+		exists, _, err := zs.conn.Exists(ctx, dir)
+		if err != nil {
+			return nil, convertError(err, dir)
+		}
+		if exists {
+			break
+		}
+		dirs = append(dirs, dir)
+	}
+	slices.Reverse(dirs)
+	return dirs, nil
+}
+
+// txnRequestIsDerived returns true if the failure of the given request
+// comes from a stale read made while building the requests, rather than
+// from a condition of the operations.
+func (zs *Server) txnRequestIsDerived(ops []topo.TxnOp, txn *txnRequests, request int, err error) bool {
+	opIndex := txn.opIndex[request]
+	if opIndex == -1 {
+		// A parent directory was created concurrently.
+		return err == zk.ErrNodeExists
+	}
+	op := ops[opIndex]
+	switch txn.requests[request].(type) {
+	case *zk.CreateRequest:
+		// A parent directory was deleted concurrently, or the file
+		// of an unconditional update was created concurrently.
+		return err == zk.ErrNoNode || (err == zk.ErrNodeExists && op.Type == topo.TxnOpUpdate)
+	case *zk.SetDataRequest:
+		// The file of an unconditional update was deleted concurrently.
+		return err == zk.ErrNoNode && op.Version == nil
+	}
+	return false
+}
+
+// txnCommitted returns the versions of a committed Txn, and removes the
+// directories its deletions left empty.
+func (zs *Server) txnCommitted(ctx context.Context, ops []topo.TxnOp, txn *txnRequests, responses []zk.MultiResponse) []topo.Version {
+	versions := make([]topo.Version, len(ops))
+	for i := range ops {
+		request := txn.opRequest[i]
+		switch txn.requests[request].(type) {
+		case *zk.CreateRequest:
+			// Newly created nodes always start at version 0.
+			versions[i] = ZKVersion(0)
+		case *zk.SetDataRequest:
+			versions[i] = ZKVersion(responses[request].Stat.Version)
+		}
+	}
+	// The transaction is committed at this point: failing to remove
+	// empty directories must not report it as failed.
+	for _, op := range ops {
+		if op.Type == topo.TxnOpDelete {
+			if err := zs.recursiveDeleteParentIfEmpty(ctx, op.FilePath); err != nil {
+				log.Warningf("Txn: cannot delete empty parent directories of %v: %v", op.FilePath, err)
+			}
+		}
+	}
+	return versions
+}
+
+// txnRequestPath returns the path of a zk multi request.
+func txnRequestPath(request any) string {
+	switch r := request.(type) {
+	case *zk.CreateRequest:
+		return r.Path
+	case *zk.SetDataRequest:
+		return r.Path
+	case *zk.DeleteRequest:
+		return r.Path
+	case *zk.CheckVersionRequest:
+		return r.Path
+	}
+	return ""
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"sort"
 
 	"github.com/z-division/go-zookeeper/zk"
 
@@ -134,6 +135,47 @@ func (zs *Server) lock(ctx context.Context, dirPath, contents string) (topo.Lock
 	}, nil
 }
 
+// lockHolder returns the path of the node of the holder of the lock on
+// dirPath, i.e. the first node in its locks directory.
+func (zs *Server) lockHolder(ctx context.Context, dirPath string) (string, error) {
+	locksDir := path.Join(zs.root, dirPath, locksPath)
+	children, _, err := zs.conn.Children(ctx, locksDir)
+	if err != nil {
+		return "", convertError(err, locksDir)
+	}
+	if len(children) == 0 {
+		return "", topo.NewError(topo.NoNode, locksDir)
+	}
+	sort.Strings(children)
+	return path.Join(locksDir, children[0]), nil
+}
+
+// LockContents is part of the topo.LockInspector interface.
+func (zs *Server) LockContents(ctx context.Context, dirPath string) (string, error) {
+	nodePath, err := zs.lockHolder(ctx, dirPath)
+	if err != nil {
+		return "", err
+	}
+	data, _, err := zs.conn.Get(ctx, nodePath)
+	if err != nil {
+		return "", convertError(err, nodePath)
+	}
+	return string(data), nil
+}
+
+// ForceUnlock is part of the topo.LockInspector interface. It deletes the
+// node of the holder, as Unlock does.
+func (zs *Server) ForceUnlock(ctx context.Context, dirPath string) error {
+	nodePath, err := zs.lockHolder(ctx, dirPath)
+	if err != nil {
+		return err
+	}
+	if err := zs.conn.Delete(ctx, nodePath, -1); err != nil {
+		return convertError(err, nodePath)
+	}
+	return nil
+}
+
 // Check is part of the topo.LockDescriptor interface.
 func (ld *zkLockDescriptor) Check(ctx context.Context) error {
 	// TODO(alainjobart): check the connection has not been interrupted.
@@ -19,6 +19,8 @@ limitations under the License.
 package events
 
 import (
+	"time"
+
 	base "vitess.io/vitess/go/vt/events"
 	"vitess.io/vitess/go/vt/topo"
 
@@ -33,3 +35,17 @@ type Reparent struct {
 	OldPrimary, NewPrimary *topodatapb.Tablet
 	ExternalID             string
 }
+
+// ExternalReparent is an event that describes a single step in the recording
+// of a reparent performed by an external tool, with the verification of the
+// fencing of the old primary.
+type ExternalReparent struct {
+	Reparent
+
+	// TermStartTime is the start of the term of the new primary, as claimed
+	// by the external tool.
+	TermStartTime time.Time
+	// OldPrimaryFenced is true once the old primary was verified to be
+	// read-only, with no transaction that the new primary misses.
+	OldPrimaryFenced bool
+}
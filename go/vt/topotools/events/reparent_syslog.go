@@ -21,6 +21,7 @@ package events
 import (
 	"fmt"
 	"log/syslog"
+	"time"
 
 	"vitess.io/vitess/go/vt/proto/topodata"
 
@@ -46,4 +47,30 @@ func (r *Reparent) Syslog() (syslog.Priority, string) {
 		r.Status, r.ExternalID)
 }
 
-var _ syslogger.Syslogger = (*Reparent)(nil) // compile-time interface check
+// Syslog writes an ExternalReparent event to syslog.
+func (r *ExternalReparent) Syslog() (syslog.Priority, string) {
+	var oldAlias *topodata.TabletAlias
+	var newAlias *topodata.TabletAlias
+	if r.OldPrimary != nil {
+		oldAlias = r.OldPrimary.Alias
+	}
+	if r.NewPrimary != nil {
+		newAlias = r.NewPrimary.Alias
+	}
+	fencing := "unverified"
+	if r.OldPrimaryFenced {
+		fencing = "fenced"
+	}
+
+	return syslog.LOG_INFO, fmt.Sprintf("%s/%s [external reparent %v -> %v, term start %v, old primary %s] %s",
+		r.ShardInfo.Keyspace(), r.ShardInfo.ShardName(),
+		topoproto.TabletAliasString(oldAlias),
+		topoproto.TabletAliasString(newAlias),
+		r.TermStartTime.UTC().Format(time.RFC3339), fencing,
+		r.Status)
+}
+
+var (
+	_ syslogger.Syslogger = (*Reparent)(nil)         // compile-time interface check
+	_ syslogger.Syslogger = (*ExternalReparent)(nil) // compile-time interface check
+)
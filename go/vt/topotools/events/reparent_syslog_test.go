@@ -21,6 +21,7 @@ package events
 import (
 	"log/syslog"
 	"testing"
+	"time"
 
 	base "vitess.io/vitess/go/vt/events"
 	"vitess.io/vitess/go/vt/topo"
@@ -56,3 +57,35 @@ func TestReparentSyslog(t *testing.T) {
 		t.Errorf("wrong message: got %v, want %v", gotMsg, wantMsg)
 	}
 }
+
+func TestExternalReparentSyslog(t *testing.T) {
+	wantSev, wantMsg := syslog.LOG_INFO, "keyspace-123/shard-123 [external reparent cell-0000012345 -> cell-0000054321, term start 2024-03-01T10:00:00Z, old primary fenced] status"
+	tc := &ExternalReparent{
+		Reparent: Reparent{
+			ShardInfo: *topo.NewShardInfo("keyspace-123", "shard-123", nil, nil),
+			OldPrimary: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "cell",
+					Uid:  12345,
+				},
+			},
+			NewPrimary: &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "cell",
+					Uid:  54321,
+				},
+			},
+			StatusUpdater: base.StatusUpdater{Status: "status"},
+		},
+		TermStartTime:    time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		OldPrimaryFenced: true,
+	}
+	gotSev, gotMsg := tc.Syslog()
+
+	if gotSev != wantSev {
+		t.Errorf("wrong severity: got %v, want %v", gotSev, wantSev)
+	}
+	if gotMsg != wantMsg {
+		t.Errorf("wrong message: got %v, want %v", gotMsg, wantMsg)
+	}
+}
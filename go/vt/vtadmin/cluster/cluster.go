@@ -284,6 +284,7 @@ func (c *Cluster) parseTablets(rows *sql.Rows) ([]*vtadminpb.Tablet, error) {
 
 // Fields are:
 // Cell | Keyspace | Shard | TabletType (string) | ServingState (string) | Alias | Hostname | PrimaryTermStartTime.
+// The fields that newer vtgates add after these are ignored.
 func (c *Cluster) parseTablet(rows *sql.Rows) (*vtadminpb.Tablet, error) {
 	var (
 		cell            string
@@ -296,7 +297,7 @@ func (c *Cluster) parseTablet(rows *sql.Rows) (*vtadminpb.Tablet, error) {
 		err error
 	)
 
-	if err := rows.Scan(
+	dest := []any{
 		&cell,
 		&topotablet.Keyspace,
 		&topotablet.Shard,
@@ -305,7 +306,16 @@ func (c *Cluster) parseTablet(rows *sql.Rows) (*vtadminpb.Tablet, error) {
 		&aliasStr,
 		&topotablet.Hostname,
 		&mtstStr,
-	); err != nil {
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	for len(dest) < len(columns) {
+		dest = append(dest, new(sql.RawBytes))
+	}
+
+	if err := rows.Scan(dest...); err != nil {
 		return nil, err
 	}
 
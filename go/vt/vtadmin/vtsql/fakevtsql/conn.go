@@ -74,7 +74,7 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 
 	switch strings.ToLower(query) {
 	case "show vitess_tablets", "show tablets":
-		columns := []string{"Cell", "Keyspace", "Shard", "TabletType", "ServingState", "Alias", "Hostname", "PrimaryTermStartTime"}
+		columns := []string{"Cell", "Keyspace", "Shard", "TabletType", "ServingState", "Alias", "Hostname", "PrimaryTermStartTime", "CircuitBreaker"}
 		vals := [][]any{}
 
 		for _, tablet := range c.tablets {
@@ -87,6 +87,7 @@ func (c *conn) QueryContext(ctx context.Context, query string, args []driver.Nam
 				topoproto.TabletAliasString(tablet.Tablet.Alias),
 				tablet.Tablet.Hostname,
 				"", // (TODO:@amason) use real values here
+				"",
 			})
 		}
 
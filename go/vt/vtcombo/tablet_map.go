@@ -853,6 +853,22 @@ func (itmc *internalTabletManagerClient) ExecuteFetchAsApp(context.Context, *top
 	return nil, fmt.Errorf("not implemented in vtcombo")
 }
 
+func (itmc *internalTabletManagerClient) KillQueries(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.KillQueriesRequest) (*tabletmanagerdatapb.KillQueriesResponse, error) {
+	t, ok := tabletMap[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tmclient: cannot find tablet %v", tablet.Alias.Uid)
+	}
+	return t.tm.KillQueries(ctx, req)
+}
+
+func (itmc *internalTabletManagerClient) UpdateQueryFingerprintRules(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest) (*tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse, error) {
+	t, ok := tabletMap[tablet.Alias.Uid]
+	if !ok {
+		return nil, fmt.Errorf("tmclient: cannot find tablet %v", tablet.Alias.Uid)
+	}
+	return t.tm.UpdateQueryFingerprintRules(ctx, req)
+}
+
 func (itmc *internalTabletManagerClient) PrimaryStatus(context.Context, *topodatapb.Tablet) (*replicationdatapb.PrimaryStatus, error) {
 	return nil, fmt.Errorf("not implemented in vtcombo")
 }
@@ -949,6 +965,26 @@ func (itmc *internalTabletManagerClient) CheckThrottler(context.Context, *topoda
 	return nil, fmt.Errorf("not implemented in vtcombo")
 }
 
+func (itmc *internalTabletManagerClient) GetUnresolvedTransactions(context.Context, *topodatapb.Tablet, int64) ([]*querypb.TransactionMetadata, error) {
+	return nil, fmt.Errorf("not implemented in vtcombo")
+}
+
+func (itmc *internalTabletManagerClient) ResolveTransaction(context.Context, *topodatapb.Tablet, string) error {
+	return fmt.Errorf("not implemented in vtcombo")
+}
+
+func (itmc *internalTabletManagerClient) RequeueDeadLetters(context.Context, *topodatapb.Tablet, string, []string) (int64, error) {
+	return 0, fmt.Errorf("not implemented in vtcombo")
+}
+
+func (itmc *internalTabletManagerClient) RunDiagnosticQuery(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.RunDiagnosticQueryRequest) (*tabletmanagerdatapb.RunDiagnosticQueryResponse, error) {
+	return nil, fmt.Errorf("not implemented in vtcombo")
+}
+
+func (itmc *internalTabletManagerClient) UpgradeMysql(context.Context, *topodatapb.Tablet, *tabletmanagerdatapb.UpgradeMysqlRequest) (*tabletmanagerdatapb.UpgradeMysqlResponse, error) {
+	return nil, fmt.Errorf("not implemented in vtcombo")
+}
+
 func (itmc *internalTabletManagerClient) Close() {
 }
 
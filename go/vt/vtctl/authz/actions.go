@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"strings"
+
+	vtctlservicepb "vitess.io/vitess/go/vt/proto/vtctlservice"
+)
+
+// vtctldService is the name of the Vtctld gRPC service.
+var vtctldService = vtctlservicepb.Vtctld_ServiceDesc.ServiceName
+
+// vtctldMethods are the lowercased method names of the Vtctld service.
+var vtctldMethods = func() map[string]struct{} {
+	methods := map[string]struct{}{}
+	for _, m := range vtctlservicepb.Vtctld_ServiceDesc.Methods {
+		methods[strings.ToLower(m.MethodName)] = struct{}{}
+	}
+	for _, s := range vtctlservicepb.Vtctld_ServiceDesc.Streams {
+		methods[strings.ToLower(s.StreamName)] = struct{}{}
+	}
+	return methods
+}()
+
+// readerMethods are the RPCs of the Vtctld service that only read the state
+// of the cluster.
+var readerMethods = []string{
+	"FindAllShardsInKeyspace",
+	"GetBackups",
+	"GetBackupSchedule",
+	"GetCellInfo",
+	"GetCellInfoNames",
+	"GetCellsAliases",
+	"GetFullStatus",
+	"GetKeyspace",
+	"GetKeyspaces",
+	"GetPermissions",
+	"GetQuotaRules",
+	"GetRoutingRules",
+	"GetSchema",
+	"GetSchemaMigrations",
+	"GetShard",
+	"GetShardReplication",
+	"GetShardRoutingRules",
+	"GetSrvKeyspaceNames",
+	"GetSrvKeyspaces",
+	"GetSrvVSchema",
+	"GetSrvVSchemas",
+	"GetTablet",
+	"GetTablets",
+	"GetTenantRoutingRules",
+	"GetTopologyPath",
+	"GetTopoLocks",
+	"GetUnresolvedTransactions",
+	"GetVDiffReport",
+	"GetVersion",
+	"GetVSchema",
+	"GetWorkflows",
+	"MountList",
+	"MountShow",
+	"PingTablet",
+	"PlanReshard",
+	"RunDiagnosticQuery",
+	"RunHealthCheck",
+	"SchemaDiff",
+	"ShardReplicationPositions",
+	"Validate",
+	"ValidateKeyspace",
+	"ValidateSchemaKeyspace",
+	"ValidateShard",
+	"ValidateVersionKeyspace",
+	"ValidateVersionShard",
+	"ValidateVSchema",
+	"VDiffShow",
+	"WorkflowStatus",
+}
+
+// migratorMethods are the RPCs of the Vtctld service that change the schema,
+// the vschema, or run VReplication workflows.
+var migratorMethods = []string{
+	"ApplySchema",
+	"ApplyVSchema",
+	"CancelSchemaMigration",
+	"CleanupSchemaMigration",
+	"CompleteSchemaMigration",
+	"CopySchemaShard",
+	"ForceCutOverSchemaMigration",
+	"LaunchSchemaMigration",
+	"LookupVindexCreate",
+	"LookupVindexExternalize",
+	"MaterializeCreate",
+	"MigrateCreate",
+	"MoveTablesComplete",
+	"MoveTablesCreate",
+	"ReloadSchema",
+	"ReloadSchemaKeyspace",
+	"ReloadSchemaShard",
+	"ReshardCreate",
+	"RetrySchemaMigration",
+	"VDiffCreate",
+	"VDiffDelete",
+	"VDiffResume",
+	"VDiffStop",
+	"WorkflowDelete",
+	"WorkflowSwitchTraffic",
+	"WorkflowUpdate",
+}
+
+// methodRoles are the roles required by the RPCs of the Vtctld service,
+// other than admin.
+var methodRoles = func() map[string]Role {
+	roles := map[string]Role{}
+	for _, method := range readerMethods {
+		roles[method] = RoleReader
+	}
+	for _, method := range migratorMethods {
+		roles[method] = RoleMigrator
+	}
+	return roles
+}()
+
+// requiredRole returns the role an RPC of the Vtctld service requires by
+// default.
+func requiredRole(method string) Role {
+	if role, ok := methodRoles[method]; ok {
+		return role
+	}
+	return RoleAdmin
+}
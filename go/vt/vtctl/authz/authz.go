@@ -0,0 +1,237 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package authz provides the authorization of the vtctld gRPC services, so that
+a control plane shared by several teams can be locked down.
+
+Every RPC of the Vtctld service requires one of three roles:
+
+  - reader, for the RPCs that only read the state of the cluster;
+  - migrator, for the schema changes and the VReplication workflows;
+  - admin, for all the other RPCs: reparents, topology changes, queries as
+    the DBA and so on. The RPCs of the legacy Vtctl service, which run any
+    vtctl command, require it too.
+
+The roles are ordered: a migrator may call the RPCs of a reader, and an admin
+any RPC. The role an RPC requires may be overridden in the config.
+
+The callers are identified, in order, by the OIDC token of the "authorization"
+metadata ("Bearer <token>"), by their TLS client certificate when it was
+verified, or by the username of the static gRPC auth plugin. The roles of a
+caller are the ones bound to its subjects in the config, with the same subject
+syntax as the vtadmin RBAC rules:
+
+  - "user:<name>" is the subject of a caller with that name: the username
+    claim of its token, the common name of its certificate, or its static
+    auth username;
+  - "role:<group>" is the subject of a caller whose token has that group in
+    its groups claim;
+  - "*" is the subject of every caller, identified or not.
+
+The calls of the RPCs that require the migrator or admin role are written to
+the audit log, whether they are allowed or not.
+
+An example config:
+
+	oidc:
+	  issuer: https://accounts.example.com
+	  audience: vtctld
+	  usernameClaim: email
+	bindings:
+	  - role: reader
+	    subjects: ["*"]
+	  - role: migrator
+	    subjects: ["role:dba"]
+	  - role: admin
+	    subjects: ["user:oncall@example.com"]
+	actions:
+	  GetPermissions: admin
+*/
+package authz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+)
+
+// Role is a role of the callers of the vtctld gRPC services.
+type Role int
+
+// The roles, from the least to the most privileged one.
+const (
+	// RoleNone is the role of the callers bound to no role. It is never
+	// required by an RPC.
+	RoleNone Role = iota
+	RoleReader
+	RoleMigrator
+	RoleAdmin
+)
+
+var roleNames = []string{"none", "reader", "migrator", "admin"}
+
+// String is part of the fmt.Stringer interface.
+func (r Role) String() string {
+	if r < 0 || int(r) >= len(roleNames) {
+		return fmt.Sprintf("Role(%d)", int(r))
+	}
+	return roleNames[r]
+}
+
+// ParseRole parses the name of a role other than none.
+func ParseRole(name string) (Role, error) {
+	for r, roleName := range roleNames {
+		if r != int(RoleNone) && strings.EqualFold(name, roleName) {
+			return Role(r), nil
+		}
+	}
+	return RoleNone, fmt.Errorf("unknown role %q, expected one of reader, migrator or admin", name)
+}
+
+// Config is the authorization config, usually loaded from a file by
+// LoadConfig.
+type Config struct {
+	// OIDC configures the verification of the OIDC tokens. The callers are
+	// not identified by tokens if it is not set.
+	OIDC *OIDCConfig
+	// Bindings bind the roles to subjects.
+	Bindings []*struct {
+		Role     string
+		Subjects []string
+	}
+	// Actions overrides the roles required by RPCs, keyed by method name,
+	// such as "GetTablets".
+	Actions map[string]string
+}
+
+// LoadConfig reads the config at the given path, in any format supported by
+// viper, and returns its Authorizer.
+func LoadConfig(path string) (*Authorizer, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := v.UnmarshalExact(&cfg); err != nil {
+		return nil, err
+	}
+
+	return NewAuthorizer(&cfg)
+}
+
+// Authorizer checks that the callers of the vtctld gRPC services have the
+// roles the RPCs they call require.
+type Authorizer struct {
+	// subjects are the roles bound to each subject.
+	subjects map[string]Role
+	// actions are the roles required by the RPCs overridden by the config,
+	// keyed by their lowercased method name.
+	actions map[string]Role
+	oidc    *oidcVerifier
+}
+
+// NewAuthorizer validates a config and returns its Authorizer.
+func NewAuthorizer(cfg *Config) (*Authorizer, error) {
+	authz := &Authorizer{
+		subjects: map[string]Role{},
+		actions:  map[string]Role{},
+	}
+	rec := concurrency.AllErrorRecorder{}
+
+	for i, binding := range cfg.Bindings {
+		role, err := ParseRole(binding.Role)
+		if err != nil {
+			rec.RecordError(fmt.Errorf("binding %d: %w", i, err))
+			continue
+		}
+		for _, subject := range binding.Subjects {
+			if subject != "*" && !strings.HasPrefix(subject, "user:") && !strings.HasPrefix(subject, "role:") {
+				rec.RecordError(fmt.Errorf("binding %d: invalid subject %q, expected user:<name>, role:<group> or *", i, subject))
+				continue
+			}
+			authz.subjects[subject] = max(authz.subjects[subject], role)
+		}
+	}
+
+	for method, name := range cfg.Actions {
+		if _, ok := vtctldMethods[strings.ToLower(method)]; !ok {
+			rec.RecordError(fmt.Errorf("actions: unknown Vtctld method %s", method))
+			continue
+		}
+		role, err := ParseRole(name)
+		if err != nil {
+			rec.RecordError(fmt.Errorf("actions: %s: %w", method, err))
+			continue
+		}
+		authz.actions[strings.ToLower(method)] = role
+	}
+
+	if cfg.OIDC != nil {
+		verifier, err := newOIDCVerifier(cfg.OIDC)
+		if err != nil {
+			rec.RecordError(fmt.Errorf("oidc: %w", err))
+		}
+		authz.oidc = verifier
+	}
+
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+
+	log.Infof("[authz]: loaded vtctld authorizer with %d bindings and %d action overrides", len(cfg.Bindings), len(cfg.Actions))
+	return authz, nil
+}
+
+// RoleOf returns the most privileged role bound to the subjects of an actor.
+// A nil actor is a caller that was not identified.
+func (authz *Authorizer) RoleOf(actor *rbac.Actor) Role {
+	role := authz.subjects["*"]
+	if actor == nil {
+		return role
+	}
+	role = max(role, authz.subjects["user:"+actor.Name])
+	for _, group := range actor.Roles {
+		role = max(role, authz.subjects["role:"+group])
+	}
+	return role
+}
+
+// RequiredRole returns the role required by an RPC, given its full method
+// name, such as "/vtctlservice.Vtctld/GetTablets". The unknown RPCs require
+// the admin role.
+func (authz *Authorizer) RequiredRole(fullMethod string) Role {
+	method, ok := strings.CutPrefix(fullMethod, "/"+vtctldService+"/")
+	if !ok {
+		return RoleAdmin
+	}
+	if role, ok := authz.actions[strings.ToLower(method)]; ok {
+		return role
+	}
+	return requiredRole(method)
+}
+
+// IsAuthorized returns whether an actor may call an RPC.
+func (authz *Authorizer) IsAuthorized(actor *rbac.Actor, fullMethod string) bool {
+	return authz.RoleOf(actor) >= authz.RequiredRole(fullMethod)
+}
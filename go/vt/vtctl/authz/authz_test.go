@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+)
+
+func TestMethodRoles(t *testing.T) {
+	for method := range methodRoles {
+		_, ok := vtctldMethods[strings.ToLower(method)]
+		assert.True(t, ok, "%s is not a method of the Vtctld service", method)
+	}
+	assert.Len(t, methodRoles, len(readerMethods)+len(migratorMethods), "a method has several roles")
+}
+
+func TestRequiredRole(t *testing.T) {
+	authz, err := NewAuthorizer(&Config{
+		Actions: map[string]string{
+			"getpermissions": "admin",
+			"PingTablet":     "migrator",
+		},
+	})
+	require.NoError(t, err)
+
+	tcs := []struct {
+		fullMethod string
+		want       Role
+	}{
+		{"/vtctlservice.Vtctld/GetTablets", RoleReader},
+		{"/vtctlservice.Vtctld/ApplySchema", RoleMigrator},
+		{"/vtctlservice.Vtctld/WorkflowSwitchTraffic", RoleMigrator},
+		{"/vtctlservice.Vtctld/PlannedReparentShard", RoleAdmin},
+		{"/vtctlservice.Vtctld/ExecuteFetchAsDBA", RoleAdmin},
+		{"/vtctlservice.Vtctld/GetPermissions", RoleAdmin},
+		{"/vtctlservice.Vtctld/PingTablet", RoleMigrator},
+		{"/vtctlservice.Vtctld/NotAMethod", RoleAdmin},
+		{"/vtctlservice.Vtctl/ExecuteVtctlCommand", RoleAdmin},
+	}
+	for _, tc := range tcs {
+		assert.Equal(t, tc.want, authz.RequiredRole(tc.fullMethod), tc.fullMethod)
+	}
+}
+
+func TestRoleOf(t *testing.T) {
+	authz, err := NewAuthorizer(&Config{
+		Bindings: []*struct {
+			Role     string
+			Subjects []string
+		}{
+			{Role: "reader", Subjects: []string{"*"}},
+			{Role: "migrator", Subjects: []string{"role:dba", "user:alice"}},
+			{Role: "admin", Subjects: []string{"user:bob"}},
+			{Role: "reader", Subjects: []string{"user:bob"}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, RoleReader, authz.RoleOf(nil))
+	assert.Equal(t, RoleReader, authz.RoleOf(&rbac.Actor{Name: "carol", Roles: []string{"dev"}}))
+	assert.Equal(t, RoleMigrator, authz.RoleOf(&rbac.Actor{Name: "carol", Roles: []string{"dev", "dba"}}))
+	assert.Equal(t, RoleMigrator, authz.RoleOf(&rbac.Actor{Name: "alice"}))
+	assert.Equal(t, RoleAdmin, authz.RoleOf(&rbac.Actor{Name: "bob"}))
+
+	assert.True(t, authz.IsAuthorized(nil, "/vtctlservice.Vtctld/GetKeyspace"))
+	assert.False(t, authz.IsAuthorized(nil, "/vtctlservice.Vtctld/ApplySchema"))
+	assert.True(t, authz.IsAuthorized(&rbac.Actor{Name: "alice"}, "/vtctlservice.Vtctld/ApplySchema"))
+	assert.False(t, authz.IsAuthorized(&rbac.Actor{Name: "alice"}, "/vtctlservice.Vtctld/DeleteKeyspace"))
+	assert.True(t, authz.IsAuthorized(&rbac.Actor{Name: "bob"}, "/vtctlservice.Vtctld/DeleteKeyspace"))
+}
+
+func TestNewAuthorizerErrors(t *testing.T) {
+	_, err := NewAuthorizer(&Config{
+		OIDC: &OIDCConfig{Issuer: "https://issuer"},
+		Bindings: []*struct {
+			Role     string
+			Subjects []string
+		}{
+			{Role: "superuser", Subjects: []string{"*"}},
+			{Role: "admin", Subjects: []string{"alice"}},
+		},
+		Actions: map[string]string{
+			"GetTablet":  "writer",
+			"DropTables": "admin",
+		},
+	})
+	require.Error(t, err)
+	for _, want := range []string{
+		`binding 0: unknown role "superuser"`,
+		`binding 1: invalid subject "alice"`,
+		`actions: GetTablet: unknown role "writer"`,
+		`actions: unknown Vtctld method DropTables`,
+		`oidc: audience is required`,
+	} {
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authz.yaml")
+	err := os.WriteFile(path, []byte(`
+bindings:
+  - role: reader
+    subjects: ["*"]
+  - role: admin
+    subjects: ["user:bob"]
+actions:
+  GetPermissions: admin
+`), 0o600)
+	require.NoError(t, err)
+
+	authz, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, RoleAdmin, authz.RequiredRole("/vtctlservice.Vtctld/GetPermissions"))
+	assert.Equal(t, RoleReader, authz.RoleOf(nil))
+	assert.Equal(t, RoleAdmin, authz.RoleOf(&rbac.Actor{Name: "bob"}))
+
+	err = os.WriteFile(path, []byte("rules: []\n"), 0o600)
+	require.NoError(t, err)
+	_, err = LoadConfig(path)
+	assert.Error(t, err)
+}
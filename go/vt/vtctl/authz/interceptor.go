@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+)
+
+var authzDecisions = stats.NewCountersWithMultiLabels(
+	"VtctldAuthzDecisions",
+	"Authorization decisions of the vtctld gRPC calls, by role required and decision",
+	[]string{"Role", "Decision"})
+
+// isVtctlService returns whether a full method name is the one of an RPC of
+// the Vtctld or Vtctl services.
+func isVtctlService(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+vtctldService+"/") || strings.HasPrefix(fullMethod, "/vtctlservice.Vtctl/")
+}
+
+// UnaryServerInterceptor returns an interceptor that authorizes the unary
+// calls of the vtctld gRPC services. The calls of the other services are not
+// checked.
+func (authz *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isVtctlService(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, done, err := authz.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that authorizes the
+// streaming calls of the vtctld gRPC services. The calls of the other
+// services are not checked.
+func (authz *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !isVtctlService(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, done, err := authz.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		wrapped := servenv.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+		err = handler(srv, wrapped)
+		done(err)
+		return err
+	}
+}
+
+// authorize identifies the caller of an RPC and checks its role. It returns
+// the context of the call, with the actor of the caller, and the function to
+// call with the result of the RPC.
+func (authz *Authorizer) authorize(ctx context.Context, fullMethod string) (context.Context, func(error), error) {
+	required := authz.RequiredRole(fullMethod)
+	privileged := required >= RoleMigrator
+
+	actor, err := authz.Identify(ctx)
+	if err != nil {
+		authzDecisions.Add([]string{required.String(), "Unauthenticated"}, 1)
+		if privileged {
+			audit(ctx, fullMethod, nil, "unauthenticated: %v", err)
+		}
+		return nil, nil, status.Errorf(codes.Unauthenticated, "%v", err)
+	}
+
+	role := authz.RoleOf(actor)
+	if role < required {
+		authzDecisions.Add([]string{required.String(), "Denied"}, 1)
+		if privileged {
+			audit(ctx, fullMethod, actor, "denied, %s role required", required)
+		}
+		return nil, nil, status.Errorf(codes.PermissionDenied, "%s requires the %s role, %s has the %s role", strings.TrimPrefix(fullMethod, "/"), required, actorName(actor), role)
+	}
+
+	authzDecisions.Add([]string{required.String(), "Allowed"}, 1)
+	if !privileged {
+		return rbac.NewContext(ctx, actor), func(error) {}, nil
+	}
+	start := time.Now()
+	return rbac.NewContext(ctx, actor), func(err error) {
+		if err != nil {
+			audit(ctx, fullMethod, actor, "allowed, failed after %v: %v", time.Since(start), err)
+			return
+		}
+		audit(ctx, fullMethod, actor, "allowed, succeeded after %v", time.Since(start))
+	}, nil
+}
+
+// Identify returns the actor of the caller of an RPC, or nil if the caller is
+// not identified. The callers are identified, in order, by their OIDC token,
+// their verified TLS client certificate, their static auth username, or the
+// actor already in the context.
+func (authz *Authorizer) Identify(ctx context.Context) (*rbac.Actor, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, value := range md.Get("authorization") {
+			token, ok := strings.CutPrefix(value, "Bearer ")
+			if !ok {
+				continue
+			}
+			if authz.oidc == nil {
+				return nil, errors.New("OIDC tokens are not accepted")
+			}
+			return authz.oidc.verify(ctx, strings.TrimSpace(token))
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state := tlsInfo.State
+			if len(state.VerifiedChains) > 0 && len(state.PeerCertificates) > 0 && state.PeerCertificates[0].Subject.CommonName != "" {
+				return &rbac.Actor{Name: state.PeerCertificates[0].Subject.CommonName}, nil
+			}
+		}
+	}
+
+	if username := servenv.StaticAuthUsernameFromContext(ctx); username != "" {
+		return &rbac.Actor{Name: username}, nil
+	}
+
+	actor, _ := rbac.FromContext(ctx)
+	return actor, nil
+}
+
+func actorName(actor *rbac.Actor) string {
+	if actor == nil {
+		return "anonymous caller"
+	}
+	return "user " + actor.Name
+}
+
+// audit writes an entry of the audit log of the privileged calls.
+func audit(ctx context.Context, fullMethod string, actor *rbac.Actor, format string, args ...any) {
+	from := "unknown peer"
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		from = p.Addr.String()
+	}
+	var roles []string
+	if actor != nil {
+		roles = actor.Roles
+	}
+	args = append([]any{strings.TrimPrefix(fullMethod, "/"), actorName(actor), roles, from}, args...)
+	log.Infof("[authz] audit: %s called by %s (groups %v) from %s: "+format, args...)
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+)
+
+func newTestAuthorizer(t *testing.T, oidc *OIDCConfig) *Authorizer {
+	authz, err := NewAuthorizer(&Config{
+		OIDC: oidc,
+		Bindings: []*struct {
+			Role     string
+			Subjects []string
+		}{
+			{Role: "reader", Subjects: []string{"*"}},
+			{Role: "migrator", Subjects: []string{"role:dba"}},
+			{Role: "admin", Subjects: []string{"user:admin.example.com"}},
+		},
+	})
+	require.NoError(t, err)
+	return authz
+}
+
+// tlsPeerContext returns the context of a call from a TLS client with a
+// certificate of the given common name.
+func tlsPeerContext(commonName string, verified bool) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	if verified {
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4242},
+		AuthInfo: credentials.TLSInfo{State: state},
+	})
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	issuer := newTestIssuer(t)
+	key := issuer.addRSAKey(t, "key")
+	authz := newTestAuthorizer(t, &OIDCConfig{Issuer: issuer.URL, Audience: "vtctld"})
+	interceptor := authz.UnaryServerInterceptor()
+
+	dbaToken := signToken(t, key, "key", map[string]any{
+		"iss":    issuer.URL,
+		"aud":    "vtctld",
+		"sub":    "alice",
+		"groups": []string{"dba"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+	bearer := func(ctx context.Context, token string) context.Context {
+		return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	tcs := []struct {
+		name       string
+		ctx        context.Context
+		fullMethod string
+		wantActor  *rbac.Actor
+		wantCode   codes.Code
+	}{{
+		name:       "anonymous reader",
+		ctx:        context.Background(),
+		fullMethod: "/vtctlservice.Vtctld/GetTablets",
+	}, {
+		name:       "anonymous migrator",
+		ctx:        context.Background(),
+		fullMethod: "/vtctlservice.Vtctld/ApplySchema",
+		wantCode:   codes.PermissionDenied,
+	}, {
+		name:       "other service",
+		ctx:        context.Background(),
+		fullMethod: "/tabletmanagerservice.TabletManager/Ping",
+	}, {
+		name:       "token of a dba",
+		ctx:        bearer(context.Background(), dbaToken),
+		fullMethod: "/vtctlservice.Vtctld/ApplySchema",
+		wantActor:  &rbac.Actor{Name: "alice", Roles: []string{"dba"}},
+	}, {
+		name:       "token of a dba for an admin method",
+		ctx:        bearer(context.Background(), dbaToken),
+		fullMethod: "/vtctlservice.Vtctld/PlannedReparentShard",
+		wantCode:   codes.PermissionDenied,
+	}, {
+		name:       "invalid token",
+		ctx:        bearer(tlsPeerContext("admin.example.com", true), "invalid"),
+		fullMethod: "/vtctlservice.Vtctld/GetTablets",
+		wantCode:   codes.Unauthenticated,
+	}, {
+		name:       "certificate of an admin",
+		ctx:        tlsPeerContext("admin.example.com", true),
+		fullMethod: "/vtctlservice.Vtctl/ExecuteVtctlCommand",
+		wantActor:  &rbac.Actor{Name: "admin.example.com"},
+	}, {
+		name:       "unverified certificate of an admin",
+		ctx:        tlsPeerContext("admin.example.com", false),
+		fullMethod: "/vtctlservice.Vtctl/ExecuteVtctlCommand",
+		wantCode:   codes.PermissionDenied,
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req any) (any, error) {
+				called = true
+				actor, _ := rbac.FromContext(ctx)
+				assert.Equal(t, tc.wantActor, actor)
+				return "resp", nil
+			}
+			resp, err := interceptor(tc.ctx, "req", &grpc.UnaryServerInfo{FullMethod: tc.fullMethod}, handler)
+			if tc.wantCode != codes.OK {
+				assert.Equal(t, tc.wantCode, status.Code(err), "%v", err)
+				assert.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, called)
+			assert.Equal(t, "resp", resp)
+		})
+	}
+}
+
+func TestUnaryServerInterceptorWithoutOIDC(t *testing.T) {
+	authz := newTestAuthorizer(t, nil)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	_, err := authz.UnaryServerInterceptor()(ctx, "req", &grpc.UnaryServerInfo{FullMethod: "/vtctlservice.Vtctld/GetTablets"}, func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.ErrorContains(t, err, "OIDC tokens are not accepted")
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *testServerStream) Context() context.Context {
+	return ss.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	authz := newTestAuthorizer(t, nil)
+	interceptor := authz.StreamServerInterceptor()
+
+	var actor *rbac.Actor
+	handler := func(srv any, ss grpc.ServerStream) error {
+		actor, _ = rbac.FromContext(ss.Context())
+		return nil
+	}
+	info := &grpc.StreamServerInfo{FullMethod: "/vtctlservice.Vtctld/EmergencyReparentShard", IsServerStream: true}
+
+	err := interceptor(nil, &testServerStream{ctx: tlsPeerContext("admin.example.com", true)}, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, &rbac.Actor{Name: "admin.example.com"}, actor)
+
+	actor = nil
+	err = interceptor(nil, &testServerStream{ctx: tlsPeerContext("dev.example.com", true)}, info, handler)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.ErrorContains(t, err, "vtctlservice.Vtctld/EmergencyReparentShard requires the admin role, user dev.example.com has the reader role")
+	assert.Nil(t, actor)
+}
@@ -0,0 +1,382 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+)
+
+const (
+	// clockSkew is the tolerance of the checks of the expiration and
+	// not-before times of the tokens.
+	clockSkew = time.Minute
+	// keysRefreshInterval is the minimum interval between two fetches of the
+	// keys of the issuer, when tokens are signed with unknown keys.
+	keysRefreshInterval = time.Minute
+	// oidcRequestTimeout is the timeout of the requests to the issuer.
+	oidcRequestTimeout = 10 * time.Second
+)
+
+// OIDCConfig configures the verification of the OIDC tokens, which are JWTs
+// signed by the issuer with RSA or ECDSA keys.
+type OIDCConfig struct {
+	// Issuer is the URL of the issuer, which must be the iss claim of the
+	// tokens.
+	Issuer string
+	// Audience must be the aud claim of the tokens, or one of them.
+	Audience string
+	// JWKSURL is the URL of the keys of the issuer. By default, it is found
+	// with the OIDC discovery document of the issuer.
+	JWKSURL string
+	// UsernameClaim is the claim of the name of the callers, "sub" by
+	// default.
+	UsernameClaim string
+	// GroupsClaim is the claim of the groups of the callers, "groups" by
+	// default.
+	GroupsClaim string
+}
+
+// oidcVerifier verifies the OIDC tokens of a config.
+type oidcVerifier struct {
+	cfg    OIDCConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu sync.Mutex
+	// jwksURL is the URL of the keys, once discovered.
+	jwksURL string
+	// keys are the keys of the issuer, keyed by key id.
+	keys      map[string]crypto.PublicKey
+	refreshed time.Time
+}
+
+func newOIDCVerifier(cfg *OIDCConfig) (*oidcVerifier, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("issuer is required")
+	}
+	if cfg.Audience == "" {
+		return nil, errors.New("audience is required")
+	}
+	v := &oidcVerifier{
+		cfg:     *cfg,
+		client:  &http.Client{Timeout: oidcRequestTimeout},
+		now:     time.Now,
+		jwksURL: cfg.JWKSURL,
+	}
+	if v.cfg.UsernameClaim == "" {
+		v.cfg.UsernameClaim = "sub"
+	}
+	if v.cfg.GroupsClaim == "" {
+		v.cfg.GroupsClaim = "groups"
+	}
+	return v, nil
+}
+
+// verify verifies a token, and returns the actor it identifies.
+func (v *oidcVerifier) verify(ctx context.Context, token string) (*rbac.Actor, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if iss, _ := claims["iss"].(string); iss != v.cfg.Issuer {
+		return nil, fmt.Errorf("token issued by %q, expected %q", iss, v.cfg.Issuer)
+	}
+	if !slices.Contains(stringsClaim(claims["aud"]), v.cfg.Audience) {
+		return nil, fmt.Errorf("token not issued for the %q audience", v.cfg.Audience)
+	}
+	now := v.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.New("token has no expiration time")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return nil, errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.New("token not valid yet")
+	}
+	name, _ := claims[v.cfg.UsernameClaim].(string)
+	if name == "" {
+		return nil, fmt.Errorf("token has no %s claim", v.cfg.UsernameClaim)
+	}
+
+	return &rbac.Actor{
+		Name:  name,
+		Roles: stringsClaim(claims[v.cfg.GroupsClaim]),
+	}, nil
+}
+
+// key returns the key of the issuer with the given id. The keys are fetched
+// again when the id is unknown, at most once per keysRefreshInterval. A token
+// without key id may only be verified when the issuer has a single key.
+func (v *oidcVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	lookup := func() (crypto.PublicKey, bool) {
+		if kid == "" && len(v.keys) == 1 {
+			for _, key := range v.keys {
+				return key, true
+			}
+		}
+		key, ok := v.keys[kid]
+		return key, ok
+	}
+	if key, ok := lookup(); ok {
+		return key, nil
+	}
+	if v.keys == nil || v.now().Sub(v.refreshed) >= keysRefreshInterval {
+		if err := v.refreshKeys(ctx); err != nil {
+			return nil, fmt.Errorf("cannot fetch the keys of the issuer: %w", err)
+		}
+		if key, ok := lookup(); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("token signed with unknown key %q", kid)
+}
+
+// refreshKeys fetches the keys of the issuer. v.mu must be held.
+func (v *oidcVerifier) refreshKeys(ctx context.Context) error {
+	if v.jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.get(ctx, strings.TrimSuffix(v.cfg.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return err
+		}
+		if discovery.JWKSURI == "" {
+			return errors.New("the discovery document of the issuer has no jwks_uri")
+		}
+		v.jwksURL = discovery.JWKSURI
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := v.get(ctx, v.jwksURL, &jwks); err != nil {
+		return err
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	v.keys = keys
+	v.refreshed = v.now()
+	return nil
+}
+
+func (v *oidcVerifier) get(ctx context.Context, url string, value any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+// jsonWebKey is a public key of a JWK set.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// N and E are the modulus and the exponent of the RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// Crv, X and Y are the curve and the point of the EC keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey returns the key, or nil if its type is not supported.
+func (jwk *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// verifySignature verifies the signature of a token with one of the RS* or
+// ES* algorithms.
+func verifySignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported token signature algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("token signature algorithm %q does not match an RSA key", alg)
+		}
+		if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+			return errors.New("invalid token signature")
+		}
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("token signature algorithm %q does not match an EC key", alg)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+	return nil
+}
+
+func decodeSegment(segment string, value any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// stringsClaim returns the strings of a claim that is a string or an array
+// of strings.
+func stringsClaim(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []any:
+		values := make([]string, 0, len(claim))
+		for _, value := range claim {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
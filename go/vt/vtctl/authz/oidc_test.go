@@ -0,0 +1,272 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtadmin/rbac"
+)
+
+// testIssuer is an OIDC issuer serving its discovery document and keys.
+type testIssuer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	keys       []map[string]string
+	keyFetches int
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	issuer := &testIssuer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": issuer.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		issuer.keyFetches++
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": issuer.keys})
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (issuer *testIssuer) addRSAKey(t *testing.T, kid string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+	issuer.keys = append(issuer.keys, map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"n":   b64(key.N.Bytes()),
+		"e":   b64(big.NewInt(int64(key.E)).Bytes()),
+	})
+	return key
+}
+
+func (issuer *testIssuer) addECKey(t *testing.T, kid string) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	issuer.mu.Lock()
+	defer issuer.mu.Unlock()
+	issuer.keys = append(issuer.keys, map[string]string{
+		"kty": "EC",
+		"kid": kid,
+		"crv": "P-256",
+		"x":   b64(key.X.FillBytes(make([]byte, 32))),
+		"y":   b64(key.Y.FillBytes(make([]byte, 32))),
+	})
+	return key
+}
+
+// signToken returns a token with the given claims, signed with an RSA key
+// with RS256, or an EC P-256 key with ES256.
+func signToken(t *testing.T, key crypto.Signer, kid string, claims map[string]any) string {
+	alg := "RS256"
+	if _, ok := key.(*ecdsa.PrivateKey); ok {
+		alg = "ES256"
+	}
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := b64(header) + "." + b64(payload)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+
+	var signature []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest.Sum(nil))
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + b64(signature)
+}
+
+func TestOIDCVerify(t *testing.T) {
+	issuer := newTestIssuer(t)
+	rsaKey := issuer.addRSAKey(t, "rsa")
+	ecKey := issuer.addECKey(t, "ec")
+
+	verifier, err := newOIDCVerifier(&OIDCConfig{
+		Issuer:        issuer.URL,
+		Audience:      "vtctld",
+		UsernameClaim: "email",
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	claims := func(overrides map[string]any) map[string]any {
+		claims := map[string]any{
+			"iss":    issuer.URL,
+			"aud":    []string{"vtadmin", "vtctld"},
+			"sub":    "1234",
+			"email":  "alice@example.com",
+			"groups": []string{"dba", "dev"},
+			"exp":    now.Add(time.Hour).Unix(),
+			"nbf":    now.Add(-time.Minute).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(claims, k)
+				continue
+			}
+			claims[k] = v
+		}
+		return claims
+	}
+
+	ctx := context.Background()
+	want := &rbac.Actor{Name: "alice@example.com", Roles: []string{"dba", "dev"}}
+
+	actor, err := verifier.verify(ctx, signToken(t, rsaKey, "rsa", claims(nil)))
+	require.NoError(t, err)
+	assert.Equal(t, want, actor)
+
+	actor, err = verifier.verify(ctx, signToken(t, ecKey, "ec", claims(map[string]any{"aud": "vtctld", "groups": "dba"})))
+	require.NoError(t, err)
+	assert.Equal(t, &rbac.Actor{Name: "alice@example.com", Roles: []string{"dba"}}, actor)
+
+	tcs := []struct {
+		name  string
+		token string
+		err   string
+	}{{
+		name:  "malformed",
+		token: "not-a-token",
+		err:   "malformed token",
+	}, {
+		name:  "key of another kid",
+		token: signToken(t, ecKey, "rsa", claims(nil)),
+		err:   "token signature algorithm \"ES256\" does not match an RSA key",
+	}, {
+		name:  "unknown kid",
+		token: signToken(t, rsaKey, "other", claims(nil)),
+		err:   "token signed with unknown key \"other\"",
+	}, {
+		name: "invalid signature",
+		token: func() string {
+			// the claims of a token with the signature of another one.
+			token := strings.Split(signToken(t, rsaKey, "rsa", claims(nil)), ".")
+			other := strings.Split(signToken(t, rsaKey, "rsa", claims(map[string]any{"email": "bob@example.com"})), ".")
+			return strings.Join([]string{token[0], other[1], token[2]}, ".")
+		}(),
+		err: "invalid token signature",
+	}, {
+		name:  "other issuer",
+		token: signToken(t, rsaKey, "rsa", claims(map[string]any{"iss": "https://other"})),
+		err:   "token issued by \"https://other\"",
+	}, {
+		name:  "other audience",
+		token: signToken(t, rsaKey, "rsa", claims(map[string]any{"aud": "vtadmin"})),
+		err:   "token not issued for the \"vtctld\" audience",
+	}, {
+		name:  "expired",
+		token: signToken(t, rsaKey, "rsa", claims(map[string]any{"exp": now.Add(-time.Hour).Unix()})),
+		err:   "token expired",
+	}, {
+		name:  "no expiration",
+		token: signToken(t, rsaKey, "rsa", claims(map[string]any{"exp": nil})),
+		err:   "token has no expiration time",
+	}, {
+		name:  "not valid yet",
+		token: signToken(t, rsaKey, "rsa", claims(map[string]any{"nbf": now.Add(time.Hour).Unix()})),
+		err:   "token not valid yet",
+	}, {
+		name:  "no username",
+		token: signToken(t, rsaKey, "rsa", claims(map[string]any{"email": nil})),
+		err:   "token has no email claim",
+	}}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := verifier.verify(ctx, tc.token)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestOIDCKeysRefresh(t *testing.T) {
+	issuer := newTestIssuer(t)
+	oldKey := issuer.addRSAKey(t, "old")
+
+	verifier, err := newOIDCVerifier(&OIDCConfig{
+		Issuer:   issuer.URL,
+		Audience: "vtctld",
+		JWKSURL:  issuer.URL + "/keys",
+	})
+	require.NoError(t, err)
+	now := time.Now()
+	verifier.now = func() time.Time { return now }
+
+	claims := map[string]any{
+		"iss": issuer.URL,
+		"aud": "vtctld",
+		"sub": "alice",
+		"exp": now.Add(time.Hour).Unix(),
+	}
+	ctx := context.Background()
+	_, err = verifier.verify(ctx, signToken(t, oldKey, "old", claims))
+	require.NoError(t, err)
+	_, err = verifier.verify(ctx, signToken(t, oldKey, "", claims))
+	require.NoError(t, err, "a token without kid is verified with the single key")
+
+	// the keys are not fetched again for an unknown key until the refresh
+	// interval elapsed.
+	newKey := issuer.addRSAKey(t, "new")
+	_, err = verifier.verify(ctx, signToken(t, newKey, "new", claims))
+	require.ErrorContains(t, err, "token signed with unknown key \"new\"")
+	assert.Equal(t, 1, issuer.keyFetches)
+
+	now = now.Add(keysRefreshInterval)
+	actor, err := verifier.verify(ctx, signToken(t, newKey, "new", claims))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", actor.Name)
+	assert.Equal(t, 2, issuer.keyFetches)
+
+	_, err = verifier.verify(ctx, signToken(t, oldKey, "", claims))
+	require.ErrorContains(t, err, "token signed with unknown key \"\"")
+}
@@ -47,6 +47,15 @@ func (client *gRPCVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldata
 	return client.c.AddCellsAlias(ctx, in, opts...)
 }
 
+// ApplyQuotaRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ApplyQuotaRules(ctx context.Context, in *vtctldatapb.ApplyQuotaRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyQuotaRulesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ApplyQuotaRules(ctx, in, opts...)
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ApplyRoutingRules(ctx context.Context, in *vtctldatapb.ApplyRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -74,6 +83,15 @@ func (client *gRPCVtctldClient) ApplyShardRoutingRules(ctx context.Context, in *
 	return client.c.ApplyShardRoutingRules(ctx, in, opts...)
 }
 
+// ApplyTenantRoutingRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ApplyTenantRoutingRules(ctx context.Context, in *vtctldatapb.ApplyTenantRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyTenantRoutingRulesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ApplyTenantRoutingRules(ctx, in, opts...)
+}
+
 // ApplyVSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ApplyVSchema(ctx context.Context, in *vtctldatapb.ApplyVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyVSchemaResponse, error) {
 	if client.c == nil {
@@ -137,6 +155,15 @@ func (client *gRPCVtctldClient) CompleteSchemaMigration(ctx context.Context, in
 	return client.c.CompleteSchemaMigration(ctx, in, opts...)
 }
 
+// CopySchemaShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) CopySchemaShard(ctx context.Context, in *vtctldatapb.CopySchemaShardRequest, opts ...grpc.CallOption) (*vtctldatapb.CopySchemaShardResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.CopySchemaShard(ctx, in, opts...)
+}
+
 // CreateKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) CreateKeyspace(ctx context.Context, in *vtctldatapb.CreateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.CreateKeyspaceResponse, error) {
 	if client.c == nil {
@@ -236,22 +263,22 @@ func (client *gRPCVtctldClient) ExecuteFetchAsDBA(ctx context.Context, in *vtctl
 	return client.c.ExecuteFetchAsDBA(ctx, in, opts...)
 }
 
-// ExecuteMultiFetchAsDBA is part of the vtctlservicepb.VtctldClient interface.
-func (client *gRPCVtctldClient) ExecuteMultiFetchAsDBA(ctx context.Context, in *vtctldatapb.ExecuteMultiFetchAsDBARequest, opts ...grpc.CallOption) (*vtctldatapb.ExecuteMultiFetchAsDBAResponse, error) {
+// ExecuteHook is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ExecuteHook(ctx context.Context, in *vtctldatapb.ExecuteHookRequest, opts ...grpc.CallOption) (*vtctldatapb.ExecuteHookResponse, error) {
 	if client.c == nil {
 		return nil, status.Error(codes.Unavailable, connClosedMsg)
 	}
 
-	return client.c.ExecuteMultiFetchAsDBA(ctx, in, opts...)
+	return client.c.ExecuteHook(ctx, in, opts...)
 }
 
-// ExecuteHook is part of the vtctlservicepb.VtctldClient interface.
-func (client *gRPCVtctldClient) ExecuteHook(ctx context.Context, in *vtctldatapb.ExecuteHookRequest, opts ...grpc.CallOption) (*vtctldatapb.ExecuteHookResponse, error) {
+// ExecuteMultiFetchAsDBA is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ExecuteMultiFetchAsDBA(ctx context.Context, in *vtctldatapb.ExecuteMultiFetchAsDBARequest, opts ...grpc.CallOption) (*vtctldatapb.ExecuteMultiFetchAsDBAResponse, error) {
 	if client.c == nil {
 		return nil, status.Error(codes.Unavailable, connClosedMsg)
 	}
 
-	return client.c.ExecuteHook(ctx, in, opts...)
+	return client.c.ExecuteMultiFetchAsDBA(ctx, in, opts...)
 }
 
 // FindAllShardsInKeyspace is part of the vtctlservicepb.VtctldClient interface.
@@ -272,6 +299,24 @@ func (client *gRPCVtctldClient) ForceCutOverSchemaMigration(ctx context.Context,
 	return client.c.ForceCutOverSchemaMigration(ctx, in, opts...)
 }
 
+// ForceUnlock is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ForceUnlock(ctx context.Context, in *vtctldatapb.ForceUnlockRequest, opts ...grpc.CallOption) (*vtctldatapb.ForceUnlockResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ForceUnlock(ctx, in, opts...)
+}
+
+// GetBackupSchedule is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetBackupSchedule(ctx context.Context, in *vtctldatapb.GetBackupScheduleRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupScheduleResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetBackupSchedule(ctx, in, opts...)
+}
+
 // GetBackups is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetBackups(ctx context.Context, in *vtctldatapb.GetBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupsResponse, error) {
 	if client.c == nil {
@@ -344,6 +389,15 @@ func (client *gRPCVtctldClient) GetPermissions(ctx context.Context, in *vtctldat
 	return client.c.GetPermissions(ctx, in, opts...)
 }
 
+// GetQuotaRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetQuotaRules(ctx context.Context, in *vtctldatapb.GetQuotaRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetQuotaRulesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetQuotaRules(ctx, in, opts...)
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetRoutingRules(ctx context.Context, in *vtctldatapb.GetRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRoutingRulesResponse, error) {
 	if client.c == nil {
@@ -452,6 +506,24 @@ func (client *gRPCVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb.
 	return client.c.GetTablets(ctx, in, opts...)
 }
 
+// GetTenantRoutingRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetTenantRoutingRules(ctx context.Context, in *vtctldatapb.GetTenantRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTenantRoutingRulesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetTenantRoutingRules(ctx, in, opts...)
+}
+
+// GetTopoLocks is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetTopoLocks(ctx context.Context, in *vtctldatapb.GetTopoLocksRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTopoLocksResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetTopoLocks(ctx, in, opts...)
+}
+
 // GetTopologyPath is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetTopologyPath(ctx context.Context, in *vtctldatapb.GetTopologyPathRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTopologyPathResponse, error) {
 	if client.c == nil {
@@ -461,6 +533,24 @@ func (client *gRPCVtctldClient) GetTopologyPath(ctx context.Context, in *vtctlda
 	return client.c.GetTopologyPath(ctx, in, opts...)
 }
 
+// GetUnresolvedTransactions is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetUnresolvedTransactions(ctx context.Context, in *vtctldatapb.GetUnresolvedTransactionsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetUnresolvedTransactionsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetUnresolvedTransactions(ctx, in, opts...)
+}
+
+// GetVDiffReport is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) GetVDiffReport(ctx context.Context, in *vtctldatapb.GetVDiffReportRequest, opts ...grpc.CallOption) (*vtctldatapb.GetVDiffReportResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.GetVDiffReport(ctx, in, opts...)
+}
+
 // GetVSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) GetVSchema(ctx context.Context, in *vtctldatapb.GetVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.GetVSchemaResponse, error) {
 	if client.c == nil {
@@ -497,6 +587,15 @@ func (client *gRPCVtctldClient) InitShardPrimary(ctx context.Context, in *vtctld
 	return client.c.InitShardPrimary(ctx, in, opts...)
 }
 
+// KillQueries is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) KillQueries(ctx context.Context, in *vtctldatapb.KillQueriesRequest, opts ...grpc.CallOption) (*vtctldatapb.KillQueriesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.KillQueries(ctx, in, opts...)
+}
+
 // LaunchSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) LaunchSchemaMigration(ctx context.Context, in *vtctldatapb.LaunchSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.LaunchSchemaMigrationResponse, error) {
 	if client.c == nil {
@@ -605,6 +704,15 @@ func (client *gRPCVtctldClient) PingTablet(ctx context.Context, in *vtctldatapb.
 	return client.c.PingTablet(ctx, in, opts...)
 }
 
+// PlanReshard is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) PlanReshard(ctx context.Context, in *vtctldatapb.PlanReshardRequest, opts ...grpc.CallOption) (*vtctldatapb.PlanReshardResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.PlanReshard(ctx, in, opts...)
+}
+
 // PlannedReparentShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) PlannedReparentShard(ctx context.Context, in *vtctldatapb.PlannedReparentShardRequest, opts ...grpc.CallOption) (*vtctldatapb.PlannedReparentShardResponse, error) {
 	if client.c == nil {
@@ -713,6 +821,15 @@ func (client *gRPCVtctldClient) ReparentTablet(ctx context.Context, in *vtctldat
 	return client.c.ReparentTablet(ctx, in, opts...)
 }
 
+// RequeueDeadLetters is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RequeueDeadLetters(ctx context.Context, in *vtctldatapb.RequeueDeadLettersRequest, opts ...grpc.CallOption) (*vtctldatapb.RequeueDeadLettersResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RequeueDeadLetters(ctx, in, opts...)
+}
+
 // ReshardCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ReshardCreate(ctx context.Context, in *vtctldatapb.ReshardCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
 	if client.c == nil {
@@ -722,6 +839,15 @@ func (client *gRPCVtctldClient) ReshardCreate(ctx context.Context, in *vtctldata
 	return client.c.ReshardCreate(ctx, in, opts...)
 }
 
+// ResolveTransaction is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ResolveTransaction(ctx context.Context, in *vtctldatapb.ResolveTransactionRequest, opts ...grpc.CallOption) (*vtctldatapb.ResolveTransactionResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ResolveTransaction(ctx, in, opts...)
+}
+
 // RestoreFromBackup is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RestoreFromBackup(ctx context.Context, in *vtctldatapb.RestoreFromBackupRequest, opts ...grpc.CallOption) (vtctlservicepb.Vtctld_RestoreFromBackupClient, error) {
 	if client.c == nil {
@@ -740,6 +866,15 @@ func (client *gRPCVtctldClient) RetrySchemaMigration(ctx context.Context, in *vt
 	return client.c.RetrySchemaMigration(ctx, in, opts...)
 }
 
+// RunDiagnosticQuery is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) RunDiagnosticQuery(ctx context.Context, in *vtctldatapb.RunDiagnosticQueryRequest, opts ...grpc.CallOption) (*vtctldatapb.RunDiagnosticQueryResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.RunDiagnosticQuery(ctx, in, opts...)
+}
+
 // RunHealthCheck is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldatapb.RunHealthCheckRequest, opts ...grpc.CallOption) (*vtctldatapb.RunHealthCheckResponse, error) {
 	if client.c == nil {
@@ -749,6 +884,24 @@ func (client *gRPCVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldat
 	return client.c.RunHealthCheck(ctx, in, opts...)
 }
 
+// SchemaDiff is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SchemaDiff(ctx context.Context, in *vtctldatapb.SchemaDiffRequest, opts ...grpc.CallOption) (*vtctldatapb.SchemaDiffResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SchemaDiff(ctx, in, opts...)
+}
+
+// SetBackupSchedule is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) SetBackupSchedule(ctx context.Context, in *vtctldatapb.SetBackupScheduleRequest, opts ...grpc.CallOption) (*vtctldatapb.SetBackupScheduleResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.SetBackupSchedule(ctx, in, opts...)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	if client.c == nil {
@@ -875,6 +1028,15 @@ func (client *gRPCVtctldClient) TabletExternallyReparented(ctx context.Context,
 	return client.c.TabletExternallyReparented(ctx, in, opts...)
 }
 
+// TabletExternallyReparentedV2 is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) TabletExternallyReparentedV2(ctx context.Context, in *vtctldatapb.TabletExternallyReparentedV2Request, opts ...grpc.CallOption) (*vtctldatapb.TabletExternallyReparentedV2Response, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.TabletExternallyReparentedV2(ctx, in, opts...)
+}
+
 // UpdateCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) UpdateCellInfo(ctx context.Context, in *vtctldatapb.UpdateCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateCellInfoResponse, error) {
 	if client.c == nil {
@@ -893,6 +1055,15 @@ func (client *gRPCVtctldClient) UpdateCellsAlias(ctx context.Context, in *vtctld
 	return client.c.UpdateCellsAlias(ctx, in, opts...)
 }
 
+// UpdateQueryFingerprintRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) UpdateQueryFingerprintRules(ctx context.Context, in *vtctldatapb.UpdateQueryFingerprintRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateQueryFingerprintRulesResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.UpdateQueryFingerprintRules(ctx, in, opts...)
+}
+
 // UpdateThrottlerConfig is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) UpdateThrottlerConfig(ctx context.Context, in *vtctldatapb.UpdateThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
 	if client.c == nil {
@@ -902,6 +1073,24 @@ func (client *gRPCVtctldClient) UpdateThrottlerConfig(ctx context.Context, in *v
 	return client.c.UpdateThrottlerConfig(ctx, in, opts...)
 }
 
+// UpdateSrvKeyspacePartitions is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) UpdateSrvKeyspacePartitions(ctx context.Context, in *vtctldatapb.UpdateSrvKeyspacePartitionsRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateSrvKeyspacePartitionsResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.UpdateSrvKeyspacePartitions(ctx, in, opts...)
+}
+
+// UpgradeShardMysql is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) UpgradeShardMysql(ctx context.Context, in *vtctldatapb.UpgradeShardMysqlRequest, opts ...grpc.CallOption) (*vtctldatapb.UpgradeShardMysqlResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.UpgradeShardMysql(ctx, in, opts...)
+}
+
 // VDiffCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) VDiffCreate(ctx context.Context, in *vtctldatapb.VDiffCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffCreateResponse, error) {
 	if client.c == nil {
@@ -965,6 +1154,24 @@ func (client *gRPCVtctldClient) ValidateKeyspace(ctx context.Context, in *vtctld
 	return client.c.ValidateKeyspace(ctx, in, opts...)
 }
 
+// ValidatePermissionsKeyspace is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidatePermissionsKeyspace(ctx context.Context, in *vtctldatapb.ValidatePermissionsKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidatePermissionsKeyspaceResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidatePermissionsKeyspace(ctx, in, opts...)
+}
+
+// ValidatePermissionsShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidatePermissionsShard(ctx context.Context, in *vtctldatapb.ValidatePermissionsShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidatePermissionsShardResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidatePermissionsShard(ctx, in, opts...)
+}
+
 // ValidateSchemaKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ValidateSchemaKeyspace(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateSchemaKeyspaceResponse, error) {
 	if client.c == nil {
@@ -974,6 +1181,15 @@ func (client *gRPCVtctldClient) ValidateSchemaKeyspace(ctx context.Context, in *
 	return client.c.ValidateSchemaKeyspace(ctx, in, opts...)
 }
 
+// ValidateSchemaShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) ValidateSchemaShard(ctx context.Context, in *vtctldatapb.ValidateSchemaShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateSchemaShardResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.ValidateSchemaShard(ctx, in, opts...)
+}
+
 // ValidateShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) ValidateShard(ctx context.Context, in *vtctldatapb.ValidateShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateShardResponse, error) {
 	if client.c == nil {
@@ -1010,6 +1226,15 @@ func (client *gRPCVtctldClient) ValidateVersionShard(ctx context.Context, in *vt
 	return client.c.ValidateVersionShard(ctx, in, opts...)
 }
 
+// WaitForFilteredReplication is part of the vtctlservicepb.VtctldClient interface.
+func (client *gRPCVtctldClient) WaitForFilteredReplication(ctx context.Context, in *vtctldatapb.WaitForFilteredReplicationRequest, opts ...grpc.CallOption) (*vtctldatapb.WaitForFilteredReplicationResponse, error) {
+	if client.c == nil {
+		return nil, status.Error(codes.Unavailable, connClosedMsg)
+	}
+
+	return client.c.WaitForFilteredReplication(ctx, in, opts...)
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldClient interface.
 func (client *gRPCVtctldClient) WorkflowDelete(ctx context.Context, in *vtctldatapb.WorkflowDeleteRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowDeleteResponse, error) {
 	if client.c == nil {
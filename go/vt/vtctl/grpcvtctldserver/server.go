@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"path/filepath"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -34,14 +35,18 @@ import (
 	"google.golang.org/grpc"
 
 	"vitess.io/vitess/go/event"
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/netutil"
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sets"
 	"vitess.io/vitess/go/sqlescape"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/timer"
 	"vitess.io/vitess/go/trace"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/concurrency"
+	"vitess.io/vitess/go/vt/dtids"
+	"vitess.io/vitess/go/vt/grpcclient"
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/key"
 	"vitess.io/vitess/go/vt/log"
@@ -51,6 +56,7 @@ import (
 	"vitess.io/vitess/go/vt/mysqlctl/mysqlctlproto"
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/schemadiff"
 	"vitess.io/vitess/go/vt/schemamanager"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
@@ -58,11 +64,14 @@ import (
 	"vitess.io/vitess/go/vt/topotools"
 	"vitess.io/vitess/go/vt/topotools/events"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil"
+	"vitess.io/vitess/go/vt/vtctl/reshardplan"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
 	"vitess.io/vitess/go/vt/vtctl/workflow"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/quota"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	logutilpb "vitess.io/vitess/go/vt/proto/logutil"
@@ -82,6 +91,10 @@ const (
 
 	// DefaultWaitReplicasTimeout is the default value for waitReplicasTimeout, which is used when calling method ApplySchema.
 	DefaultWaitReplicasTimeout = 10 * time.Second
+
+	// DefaultWaitForFilteredReplicationMaxDelay is the default value for the
+	// maxDelay of WaitForFilteredReplication.
+	DefaultWaitForFilteredReplicationMaxDelay = 30 * time.Second
 )
 
 // VtctldServer implements the Vtctld RPC service protocol.
@@ -165,6 +178,36 @@ func (s *VtctldServer) AddCellsAlias(ctx context.Context, req *vtctldatapb.AddCe
 	return &vtctldatapb.AddCellsAliasResponse{}, nil
 }
 
+// ApplyQuotaRules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyQuotaRules(ctx context.Context, req *vtctldatapb.ApplyQuotaRulesRequest) (*vtctldatapb.ApplyQuotaRulesResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyQuotaRules")
+	defer span.Finish()
+
+	span.Annotate("skip_rebuild", req.SkipRebuild)
+	span.Annotate("rebuild_cells", strings.Join(req.RebuildCells, ","))
+
+	if err := quota.ValidateRules(req.QuotaRules); err != nil {
+		return nil, err
+	}
+
+	if err := s.ts.SaveQuotaRules(ctx, req.QuotaRules); err != nil {
+		return nil, err
+	}
+
+	resp := &vtctldatapb.ApplyQuotaRulesResponse{}
+
+	if req.SkipRebuild {
+		log.Warningf("Skipping rebuild of SrvVSchema as requested, you will need to run RebuildVSchemaGraph for changes to take effect")
+		return resp, nil
+	}
+
+	if err := s.ts.RebuildSrvVSchema(ctx, req.RebuildCells); err != nil {
+		return nil, vterrors.Wrapf(err, "RebuildSrvVSchema(%v) failed: %v", req.RebuildCells, err)
+	}
+
+	return resp, nil
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.ApplyRoutingRulesRequest) (resp *vtctldatapb.ApplyRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyRoutingRules")
@@ -175,6 +218,10 @@ func (s *VtctldServer) ApplyRoutingRules(ctx context.Context, req *vtctldatapb.A
 	span.Annotate("skip_rebuild", req.SkipRebuild)
 	span.Annotate("rebuild_cells", strings.Join(req.RebuildCells, ","))
 
+	if err = vindexes.ValidateRoutingRules(req.RoutingRules); err != nil {
+		return nil, err
+	}
+
 	if err = s.ts.SaveRoutingRules(ctx, req.RoutingRules); err != nil {
 		return nil, err
 	}
@@ -220,6 +267,36 @@ func (s *VtctldServer) ApplyShardRoutingRules(ctx context.Context, req *vtctldat
 	return resp, nil
 }
 
+// ApplyTenantRoutingRules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ApplyTenantRoutingRules(ctx context.Context, req *vtctldatapb.ApplyTenantRoutingRulesRequest) (*vtctldatapb.ApplyTenantRoutingRulesResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyTenantRoutingRules")
+	defer span.Finish()
+
+	span.Annotate("skip_rebuild", req.SkipRebuild)
+	span.Annotate("rebuild_cells", strings.Join(req.RebuildCells, ","))
+
+	if err := vindexes.ValidateTenantRoutingRules(req.TenantRoutingRules); err != nil {
+		return nil, err
+	}
+
+	if err := s.ts.SaveTenantRoutingRules(ctx, req.TenantRoutingRules); err != nil {
+		return nil, err
+	}
+
+	resp := &vtctldatapb.ApplyTenantRoutingRulesResponse{}
+
+	if req.SkipRebuild {
+		log.Warningf("Skipping rebuild of SrvVSchema as requested, you will need to run RebuildVSchemaGraph for changes to take effect")
+		return resp, nil
+	}
+
+	if err := s.ts.RebuildSrvVSchema(ctx, req.RebuildCells); err != nil {
+		return nil, vterrors.Wrapf(err, "RebuildSrvVSchema(%v) failed: %v", req.RebuildCells, err)
+	}
+
+	return resp, nil
+}
+
 // ApplySchema is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySchemaRequest) (resp *vtctldatapb.ApplySchemaResponse, err error) {
 	log.Infof("VtctldServer.ApplySchema: keyspace=%s, migrationContext=%v, ddlStrategy=%v, batchSize=%v", req.Keyspace, req.MigrationContext, req.DdlStrategy, req.BatchSize)
@@ -271,6 +348,16 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 		logstream = append(logstream, e)
 	})
 
+	if req.DryRun {
+		span.Annotate("dry_run", req.DryRun)
+		var dryRunResults []string
+		dryRunResults, err = s.declarativeDryRun(ctx, req.Keyspace, req.DdlStrategy, req.Sql)
+		if err != nil {
+			return nil, err
+		}
+		return &vtctldatapb.ApplySchemaResponse{DryRunResults: dryRunResults}, nil
+	}
+
 	executor := schemamanager.NewTabletExecutor(migrationContext, s.ts, s.tmc, logger, waitReplicasTimeout, req.BatchSize, s.ws.SQLParser())
 
 	if err = executor.SetDDLStrategy(req.DdlStrategy); err != nil {
@@ -307,6 +394,91 @@ func (s *VtctldServer) ApplySchema(ctx context.Context, req *vtctldatapb.ApplySc
 	return resp, err
 }
 
+// declarativeDryRun computes the schema changes that declarative migrations
+// for the given statements would apply, by diffing them against the current
+// schema of the primary tablet of each shard of the keyspace.
+func (s *VtctldServer) declarativeDryRun(ctx context.Context, keyspace string, ddlStrategy string, sqls []string) ([]string, error) {
+	setting, err := schema.ParseDDLStrategy(ddlStrategy)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid DdlStrategy: %s", ddlStrategy)
+	}
+	if !setting.IsDeclarative() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "dry run is only supported for declarative migrations, got DdlStrategy: %s", ddlStrategy)
+	}
+	tables, err := schemamanager.DeclarativeTableNames(sqls, s.ws.SQLParser())
+	if err != nil {
+		return nil, err
+	}
+	shards, err := s.ts.GetShardNames(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no shards found in keyspace %s", keyspace)
+	}
+	env := s.ws.Environment()
+	senv := schemadiff.NewEnv(env, env.CollationEnv().DefaultConnectionCharset())
+
+	var (
+		m            sync.Mutex
+		wg           sync.WaitGroup
+		rec          concurrency.AllErrorRecorder
+		diffsByShard = make(map[string][]string, len(shards))
+	)
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard string) {
+			defer wg.Done()
+			diffs, err := s.declarativeShardDiffs(ctx, senv, keyspace, shard, tables, sqls)
+			if err != nil {
+				rec.RecordError(err)
+				return
+			}
+			m.Lock()
+			defer m.Unlock()
+			diffsByShard[shard] = diffs
+		}(shard)
+	}
+	wg.Wait()
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+	return schemamanager.MergeShardDiffs(diffsByShard), nil
+}
+
+// declarativeShardDiffs diffs the given declarative statements against the
+// current schema of the primary tablet of the shard.
+func (s *VtctldServer) declarativeShardDiffs(ctx context.Context, senv *schemadiff.Environment, keyspace, shard string, tables, sqls []string) ([]string, error) {
+	si, err := s.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasPrimary() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary tablet for shard %s/%s", keyspace, shard)
+	}
+	ti, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+	sd, err := s.tmc.GetSchema(ctx, ti.Tablet, &tabletmanagerdatapb.GetSchemaRequest{
+		Tables:          tables,
+		IncludeViews:    true,
+		TableSchemaOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	currentSchema := make(map[string]string, len(sd.TableDefinitions))
+	for _, td := range sd.TableDefinitions {
+		currentSchema[td.Name] = td.Schema
+	}
+	diffs, err := schemamanager.DiffDeclarativeStatements(senv, currentSchema, sqls)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "shard %s/%s", keyspace, shard)
+	}
+	return diffs, nil
+}
+
 // ApplyVSchema is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ApplyVSchema(ctx context.Context, req *vtctldatapb.ApplyVSchemaRequest) (resp *vtctldatapb.ApplyVSchemaResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ApplyVSchema")
@@ -742,6 +914,32 @@ func (s *VtctldServer) ForceCutOverSchemaMigration(ctx context.Context, req *vtc
 	return resp, nil
 }
 
+// ForceUnlock is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ForceUnlock(ctx context.Context, req *vtctldatapb.ForceUnlockRequest) (resp *vtctldatapb.ForceUnlockResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ForceUnlock")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("reason", req.Reason)
+
+	var l *topo.Lock
+	if req.Shard == "" {
+		l, err = s.ts.ForceUnlockKeyspace(ctx, req.Keyspace, req.Reason)
+	} else {
+		l, err = s.ts.ForceUnlockShard(ctx, req.Keyspace, req.Shard, req.Reason)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.ForceUnlockResponse{
+		Lock: topoLockToProto(req.Keyspace, req.Shard, l, time.Now()),
+	}, nil
+}
+
 // CompleteSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CompleteSchemaMigration(ctx context.Context, req *vtctldatapb.CompleteSchemaMigrationRequest) (resp *vtctldatapb.CompleteSchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CompleteSchemaMigration")
@@ -773,6 +971,66 @@ func (s *VtctldServer) CompleteSchemaMigration(ctx context.Context, req *vtctlda
 	return resp, nil
 }
 
+// CopySchemaShard is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) CopySchemaShard(ctx context.Context, req *vtctldatapb.CopySchemaShardRequest) (resp *vtctldatapb.CopySchemaShardResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.CopySchemaShard")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("source_tablet_alias", topoproto.TabletAliasString(req.SourceTabletAlias))
+	span.Annotate("source_keyspace", req.SourceKeyspace)
+	span.Annotate("source_shard", req.SourceShard)
+	span.Annotate("destination_keyspace", req.DestinationKeyspace)
+	span.Annotate("destination_shard", req.DestinationShard)
+	span.Annotate("tables", strings.Join(req.Tables, ","))
+	span.Annotate("exclude_tables", strings.Join(req.ExcludeTables, ","))
+	span.Annotate("include_views", req.IncludeViews)
+	span.Annotate("skip_verify", req.SkipVerify)
+
+	if req.DestinationKeyspace == "" || req.DestinationShard == "" {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "destination keyspace and shard are required")
+		return nil, err
+	}
+
+	sourceTabletAlias := req.SourceTabletAlias
+	switch {
+	case sourceTabletAlias != nil && (req.SourceKeyspace != "" || req.SourceShard != ""):
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only one of source tablet alias and source keyspace/shard may be specified")
+		return nil, err
+	case sourceTabletAlias == nil:
+		if req.SourceKeyspace == "" || req.SourceShard == "" {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "either a source tablet alias or a source keyspace and shard are required")
+			return nil, err
+		}
+		var si *topo.ShardInfo
+		si, err = s.ts.GetShard(ctx, req.SourceKeyspace, req.SourceShard)
+		if err != nil {
+			return nil, err
+		}
+		if !si.HasPrimary() {
+			err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary in shard record %s/%s; provide a source tablet alias instead", req.SourceKeyspace, req.SourceShard)
+			return nil, err
+		}
+		sourceTabletAlias = si.PrimaryAlias
+	}
+
+	waitReplicasTimeout, ok, err := protoutil.DurationFromProto(req.WaitReplicasTimeout)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse WaitReplicasTimeout into a valid duration")
+		return nil, err
+	} else if !ok {
+		waitReplicasTimeout = DefaultWaitReplicasTimeout
+	}
+
+	err = s.ws.CopySchemaShard(ctx, sourceTabletAlias, req.Tables, req.ExcludeTables, req.IncludeViews, req.DestinationKeyspace, req.DestinationShard, waitReplicasTimeout, req.SkipVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.CopySchemaShardResponse{}, nil
+}
+
 // CreateKeyspace is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) CreateKeyspace(ctx context.Context, req *vtctldatapb.CreateKeyspaceRequest) (resp *vtctldatapb.CreateKeyspaceResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.CreateKeyspace")
@@ -1419,6 +1677,32 @@ func (s *VtctldServer) GetBackups(ctx context.Context, req *vtctldatapb.GetBacku
 	}, nil
 }
 
+// GetBackupSchedule is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetBackupSchedule(ctx context.Context, req *vtctldatapb.GetBackupScheduleRequest) (resp *vtctldatapb.GetBackupScheduleResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetBackupSchedule")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+
+	schedule, err := s.ts.GetBackupSchedule(ctx, req.Keyspace, req.Shard)
+	switch {
+	case topo.IsErrType(err, topo.NoNode):
+		// Make sure the shard exists, to not report a typo as no schedule.
+		if _, err = s.ts.GetShard(ctx, req.Keyspace, req.Shard); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	return &vtctldatapb.GetBackupScheduleResponse{
+		BackupSchedule: schedule,
+	}, nil
+}
+
 // GetCellInfoNames is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetCellInfoNames(ctx context.Context, req *vtctldatapb.GetCellInfoNamesRequest) (resp *vtctldatapb.GetCellInfoNamesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetCellInfoNames")
@@ -1572,6 +1856,21 @@ func (s *VtctldServer) GetPermissions(ctx context.Context, req *vtctldatapb.GetP
 	}, nil
 }
 
+// GetQuotaRules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetQuotaRules(ctx context.Context, req *vtctldatapb.GetQuotaRulesRequest) (*vtctldatapb.GetQuotaRulesResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetQuotaRules")
+	defer span.Finish()
+
+	qr, err := s.ts.GetQuotaRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetQuotaRulesResponse{
+		QuotaRules: qr,
+	}, nil
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) GetRoutingRules(ctx context.Context, req *vtctldatapb.GetRoutingRulesRequest) (resp *vtctldatapb.GetRoutingRulesResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetRoutingRules")
@@ -2254,6 +2553,21 @@ func (s *VtctldServer) GetTablets(ctx context.Context, req *vtctldatapb.GetTable
 	}, nil
 }
 
+// GetTenantRoutingRules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetTenantRoutingRules(ctx context.Context, req *vtctldatapb.GetTenantRoutingRulesRequest) (*vtctldatapb.GetTenantRoutingRulesResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetTenantRoutingRules")
+	defer span.Finish()
+
+	trr, err := s.ts.GetTenantRoutingRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetTenantRoutingRulesResponse{
+		TenantRoutingRules: trr,
+	}, nil
+}
+
 // GetTopologyPath is part of the vtctlservicepb.VtctldServer interface.
 // It returns the cell located at the provided path in the topology server.
 func (s *VtctldServer) GetTopologyPath(ctx context.Context, req *vtctldatapb.GetTopologyPathRequest) (*vtctldatapb.GetTopologyPathResponse, error) {
@@ -2287,52 +2601,187 @@ func (s *VtctldServer) GetTopologyPath(ctx context.Context, req *vtctldatapb.Get
 	}, nil
 }
 
-// GetVersion returns the version of a tablet from its debug vars
-func (s *VtctldServer) GetVersion(ctx context.Context, req *vtctldatapb.GetVersionRequest) (resp *vtctldatapb.GetVersionResponse, err error) {
-	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetVersion")
+// GetTopoLocks is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetTopoLocks(ctx context.Context, req *vtctldatapb.GetTopoLocksRequest) (resp *vtctldatapb.GetTopoLocksResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetTopoLocks")
 	defer span.Finish()
 
 	defer panicHandler(&err)
 
-	tabletAlias := req.TabletAlias
-	tablet, err := s.ts.GetTablet(ctx, tabletAlias)
-	if err != nil {
-		return nil, err
+	span.Annotate("keyspace", req.Keyspace)
+
+	keyspaces := []string{req.Keyspace}
+	if req.Keyspace == "" {
+		keyspaces, err = s.ts.GetKeyspaces(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	version, err := GetVersionFunc()(tablet.Addr())
-	if err != nil {
-		return nil, err
+	now := time.Now()
+	resp = &vtctldatapb.GetTopoLocksResponse{}
+	for _, keyspace := range keyspaces {
+		l, err := s.ts.GetKeyspaceLockHolder(ctx, keyspace)
+		if err != nil {
+			return nil, fmt.Errorf("GetKeyspaceLockHolder(%s) failed: %w", keyspace, err)
+		}
+		if l != nil {
+			resp.Locks = append(resp.Locks, topoLockToProto(keyspace, "", l, now))
+		}
+
+		shards, err := s.ts.GetShardNames(ctx, keyspace)
+		if err != nil {
+			return nil, fmt.Errorf("GetShardNames(%s) failed: %w", keyspace, err)
+		}
+		for _, shard := range shards {
+			l, err := s.ts.GetShardLockHolder(ctx, keyspace, shard)
+			if err != nil {
+				return nil, fmt.Errorf("GetShardLockHolder(%s/%s) failed: %w", keyspace, shard, err)
+			}
+			if l != nil {
+				resp.Locks = append(resp.Locks, topoLockToProto(keyspace, shard, l, now))
+			}
+		}
 	}
-	log.Infof("Tablet %v is running version '%v'", topoproto.TabletAliasString(tabletAlias), version)
-	return &vtctldatapb.GetVersionResponse{Version: version}, err
+	return resp, nil
 }
 
-// GetVSchema is part of the vtctlservicepb.VtctldServer interface.
-func (s *VtctldServer) GetVSchema(ctx context.Context, req *vtctldatapb.GetVSchemaRequest) (resp *vtctldatapb.GetVSchemaResponse, err error) {
-	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetVSchema")
+// topoLockToProto converts the lock of a keyspace, or of a shard if shard is
+// set, to its proto, with its age at now.
+func topoLockToProto(keyspace, shard string, l *topo.Lock, now time.Time) *vtctldatapb.TopoLock {
+	tl := &vtctldatapb.TopoLock{
+		Keyspace: keyspace,
+		Shard:    shard,
+		Action:   l.Action,
+		HostName: l.HostName,
+		UserName: l.UserName,
+		Time:     l.Time,
+		Status:   l.Status,
+	}
+	if lockTime, err := time.Parse(time.RFC3339, l.Time); err == nil {
+		tl.Age = protoutil.DurationToProto(now.Sub(lockTime))
+	}
+	return tl
+}
+
+// GetUnresolvedTransactions is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetUnresolvedTransactions(ctx context.Context, req *vtctldatapb.GetUnresolvedTransactionsRequest) (resp *vtctldatapb.GetUnresolvedTransactionsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetUnresolvedTransactions")
 	defer span.Finish()
 
 	defer panicHandler(&err)
 
 	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("abandon_age", req.AbandonAge)
 
-	vschema, err := s.ts.GetVSchema(ctx, req.Keyspace)
+	tabletsResp, err := s.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{
+		Keyspace:   req.Keyspace,
+		TabletType: topodatapb.TabletType_PRIMARY,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &vtctldatapb.GetVSchemaResponse{
-		VSchema: vschema,
-	}, nil
-}
+	var (
+		m            sync.Mutex
+		wg           sync.WaitGroup
+		rec          concurrency.AllErrorRecorder
+		transactions []*querypb.TransactionMetadata
+	)
+	for _, tablet := range tabletsResp.Tablets {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
 
-// GetWorkflows is part of the vtctlservicepb.VtctldServer interface.
-func (s *VtctldServer) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflowsRequest) (resp *vtctldatapb.GetWorkflowsResponse, err error) {
-	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetWorkflows")
-	defer span.Finish()
+			shardTransactions, err := s.tmc.GetUnresolvedTransactions(ctx, tablet, req.AbandonAge)
+			if err != nil {
+				rec.RecordError(fmt.Errorf("GetUnresolvedTransactions(%v) failed: %w", topoproto.TabletAliasString(tablet.Alias), err))
+				return
+			}
 
-	defer panicHandler(&err)
+			m.Lock()
+			defer m.Unlock()
+
+			transactions = append(transactions, shardTransactions...)
+		}(tablet)
+	}
+
+	wg.Wait()
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].Dtid < transactions[j].Dtid
+	})
+
+	return &vtctldatapb.GetUnresolvedTransactionsResponse{
+		Transactions: transactions,
+	}, nil
+}
+
+// GetVDiffReport is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetVDiffReport(ctx context.Context, req *vtctldatapb.GetVDiffReportRequest) (resp *vtctldatapb.GetVDiffReportResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetVDiffReport")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("uuid", req.Uuid)
+	span.Annotate("limit", req.Limit)
+
+	resp, err = s.ws.GetVDiffReport(ctx, req)
+	return resp, err
+}
+
+// GetVersion returns the version of a tablet from its debug vars
+func (s *VtctldServer) GetVersion(ctx context.Context, req *vtctldatapb.GetVersionRequest) (resp *vtctldatapb.GetVersionResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetVersion")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	tabletAlias := req.TabletAlias
+	tablet, err := s.ts.GetTablet(ctx, tabletAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := GetVersionFunc()(tablet.Addr())
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Tablet %v is running version '%v'", topoproto.TabletAliasString(tabletAlias), version)
+	return &vtctldatapb.GetVersionResponse{Version: version}, err
+}
+
+// GetVSchema is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetVSchema(ctx context.Context, req *vtctldatapb.GetVSchemaRequest) (resp *vtctldatapb.GetVSchemaResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetVSchema")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+
+	vschema, err := s.ts.GetVSchema(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.GetVSchemaResponse{
+		VSchema: vschema,
+	}, nil
+}
+
+// GetWorkflows is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflowsRequest) (resp *vtctldatapb.GetWorkflowsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.GetWorkflows")
+	defer span.Finish()
+
+	defer panicHandler(&err)
 
 	span.Annotate("keyspace", req.Keyspace)
 	span.Annotate("active_only", req.ActiveOnly)
@@ -2432,10 +2881,17 @@ func (s *VtctldServer) InitShardPrimaryLocked(
 	}
 	ev.ShardInfo = *shardInfo
 
-	durabilityName, err := s.ts.GetKeyspaceDurability(ctx, req.Keyspace)
+	// The keyspace record is checked again when recording the new
+	// primary, so the shard is not initialized with a durability
+	// policy that changed in the meantime.
+	keyspaceInfo, err := s.ts.GetKeyspace(ctx, req.Keyspace)
 	if err != nil {
 		return err
 	}
+	durabilityName := keyspaceInfo.GetDurabilityPolicy()
+	if durabilityName == "" {
+		durabilityName = "none"
+	}
 	log.Infof("Getting a new durability policy for %v", durabilityName)
 	durability, err := reparentutil.GetDurabilityPolicy(durabilityName)
 	if err != nil {
@@ -2581,7 +3037,7 @@ func (s *VtctldServer) InitShardPrimaryLocked(
 		return fmt.Errorf("failed to PopulateReparentJournal on primary: %v", primaryErr)
 	}
 	if !topoproto.TabletAliasEqual(shardInfo.PrimaryAlias, req.PrimaryElectTabletAlias) {
-		if _, err := s.ts.UpdateShardFields(ctx, req.Keyspace, req.Shard, func(si *topo.ShardInfo) error {
+		if _, err := s.ts.UpdateShardsFields(ctx, req.Keyspace, []string{req.Shard}, keyspaceInfo, func(si *topo.ShardInfo) error {
 			si.PrimaryAlias = req.PrimaryElectTabletAlias
 			return nil
 		}); err != nil {
@@ -2620,6 +3076,37 @@ func (s *VtctldServer) InitShardPrimaryLocked(
 	return nil
 }
 
+// KillQueries is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) KillQueries(ctx context.Context, req *vtctldatapb.KillQueriesRequest) (resp *vtctldatapb.KillQueriesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.KillQueries")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("query", req.Query)
+	span.Annotate("plan_type", req.PlanType)
+	span.Annotate("caller", req.Caller)
+	span.Annotate("dry_run", req.DryRun)
+
+	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.tmc.KillQueries(ctx, ti.Tablet, &tabletmanagerdatapb.KillQueriesRequest{
+		Query:    req.Query,
+		PlanType: req.PlanType,
+		Caller:   req.Caller,
+		DryRun:   req.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.KillQueriesResponse{Queries: result.Queries}, nil
+}
+
 // LaunchSchemaMigration is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) LaunchSchemaMigration(ctx context.Context, req *vtctldatapb.LaunchSchemaMigrationRequest) (resp *vtctldatapb.LaunchSchemaMigrationResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.LaunchSchemaMigration")
@@ -2827,6 +3314,138 @@ func (s *VtctldServer) PingTablet(ctx context.Context, req *vtctldatapb.PingTabl
 	return &vtctldatapb.PingTabletResponse{}, nil
 }
 
+// PlanReshard is part of the vtctldservicepb.VtctldServer interface.
+func (s *VtctldServer) PlanReshard(ctx context.Context, req *vtctldatapb.PlanReshardRequest) (resp *vtctldatapb.PlanReshardResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.PlanReshard")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("max_shard_size_bytes", req.MaxShardSizeBytes)
+	span.Annotate("max_shard_qps", req.MaxShardQps)
+	span.Annotate("merge_threshold", req.MergeThreshold)
+
+	limits := reshardplan.Limits{
+		MaxSizeBytes:   req.MaxShardSizeBytes,
+		MaxQPS:         req.MaxShardQps,
+		MergeThreshold: req.MergeThreshold,
+	}
+	if err = limits.Validate(); err != nil {
+		return nil, err
+	}
+
+	shards, err := s.ts.GetShardNames(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg  sync.WaitGroup
+		rec concurrency.AllErrorRecorder
+		// Each goroutine only sets the load of its own shard.
+		loads = make([]*vtctldatapb.ShardLoad, len(shards))
+	)
+	for i, shard := range shards {
+		wg.Add(1)
+		go func(i int, shard string) {
+			defer wg.Done()
+
+			load, err := s.getShardLoad(ctx, req.Keyspace, shard, req.MaxShardQps > 0)
+			if err != nil {
+				rec.RecordError(vterrors.Wrapf(err, "cannot get the load of shard %v/%v", req.Keyspace, shard))
+				return
+			}
+			loads[i] = load
+		}(i, shard)
+	}
+	wg.Wait()
+
+	if rec.HasErrors() {
+		err = rec.Error()
+		return nil, err
+	}
+
+	proposals, err := reshardplan.Plan(req.Keyspace, loads, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.PlanReshardResponse{
+		Shards:    loads,
+		Proposals: proposals,
+	}, nil
+}
+
+// getShardLoad returns the size of a shard, as the data length and the row
+// count of the tables of its primary, and optionally its QPS, as the sum of
+// the QPS reported by the health streams of its serving tablets.
+func (s *VtctldServer) getShardLoad(ctx context.Context, keyspace string, shard string, withQPS bool) (*vtctldatapb.ShardLoad, error) {
+	si, err := s.ts.GetShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, fmt.Errorf("GetShard(%v, %v) failed: %w", keyspace, shard, err)
+	}
+	if !si.HasPrimary() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary in shard %v/%v", keyspace, shard)
+	}
+
+	sd, err := schematools.GetSchema(ctx, s.ts, s.tmc, si.PrimaryAlias, &tabletmanagerdatapb.GetSchemaRequest{TableSchemaOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	load := &vtctldatapb.ShardLoad{Name: shard}
+	for _, td := range sd.TableDefinitions {
+		load.SizeBytes += td.DataLength
+		load.RowCount += td.RowCount
+	}
+
+	if !withQPS {
+		return load, nil
+	}
+
+	tablets, err := s.ts.GetTabletMapForShard(ctx, keyspace, shard)
+	if err != nil {
+		return nil, err
+	}
+	for _, ti := range tablets {
+		if !topo.IsInServingGraph(ti.Type) {
+			continue
+		}
+		qps, err := tabletQPS(ctx, ti.Tablet)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot get the QPS of tablet %v", topoproto.TabletAliasString(ti.Alias))
+		}
+		load.Qps += qps
+	}
+	return load, nil
+}
+
+// tabletQPS returns the QPS reported by the health stream of a tablet.
+func tabletQPS(ctx context.Context, tablet *topodatapb.Tablet) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+
+	conn, err := tabletconn.GetDialer()(tablet, grpcclient.FailFast(false))
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close(ctx)
+
+	var stats *querypb.RealtimeStats
+	err = conn.StreamHealth(ctx, func(shr *querypb.StreamHealthResponse) error {
+		stats = shr.RealtimeStats
+		return io.EOF
+	})
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	if stats == nil {
+		return 0, vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "the health stream ended without realtime stats")
+	}
+	return stats.Qps, nil
+}
+
 // PlannedReparentShard is part of the vtctldservicepb.VtctldServer interface.
 func (s *VtctldServer) PlannedReparentShard(ctx context.Context, req *vtctldatapb.PlannedReparentShardRequest) (resp *vtctldatapb.PlannedReparentShardResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.PlannedReparentShard")
@@ -3269,6 +3888,65 @@ func (s *VtctldServer) ReparentTablet(ctx context.Context, req *vtctldatapb.Repa
 	}, nil
 }
 
+// RequeueDeadLetters is part of the vtctlservicepb.VtctldServer interface.
+// It asks the primary of every shard of the keyspace to move the dead letters
+// of the message table back to it.
+func (s *VtctldServer) RequeueDeadLetters(ctx context.Context, req *vtctldatapb.RequeueDeadLettersRequest) (resp *vtctldatapb.RequeueDeadLettersResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RequeueDeadLetters")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("table", req.Table)
+	span.Annotate("num_ids", len(req.Ids))
+
+	if req.Table == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "table name is required")
+	}
+
+	tabletsResp, err := s.GetTablets(ctx, &vtctldatapb.GetTabletsRequest{
+		Keyspace:   req.Keyspace,
+		TabletType: topodatapb.TabletType_PRIMARY,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		m     sync.Mutex
+		wg    sync.WaitGroup
+		rec   concurrency.AllErrorRecorder
+		count int64
+	)
+	for _, tablet := range tabletsResp.Tablets {
+		wg.Add(1)
+		go func(tablet *topodatapb.Tablet) {
+			defer wg.Done()
+
+			shardCount, err := s.tmc.RequeueDeadLetters(ctx, tablet, req.Table, req.Ids)
+			if err != nil {
+				rec.RecordError(fmt.Errorf("RequeueDeadLetters(%v) failed: %w", topoproto.TabletAliasString(tablet.Alias), err))
+				return
+			}
+
+			m.Lock()
+			defer m.Unlock()
+
+			count += shardCount
+		}(tablet)
+	}
+
+	wg.Wait()
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+
+	return &vtctldatapb.RequeueDeadLettersResponse{
+		Count: count,
+	}, nil
+}
+
 // ReshardCreate is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ReshardCreate(ctx context.Context, req *vtctldatapb.ReshardCreateRequest) (resp *vtctldatapb.WorkflowStatusResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ReshardCreate")
@@ -3287,6 +3965,43 @@ func (s *VtctldServer) ReshardCreate(ctx context.Context, req *vtctldatapb.Resha
 	resp, err = s.ws.ReshardCreate(ctx, req)
 	return resp, err
 }
+
+// ResolveTransaction is part of the vtctlservicepb.VtctldServer interface.
+// It asks the primary of the shard holding the transaction's metadata to
+// resolve the distributed transaction through its coordinator.
+func (s *VtctldServer) ResolveTransaction(ctx context.Context, req *vtctldatapb.ResolveTransactionRequest) (resp *vtctldatapb.ResolveTransactionResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ResolveTransaction")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("dtid", req.Dtid)
+
+	mmShard, err := dtids.ShardSession(req.Dtid)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "invalid dtid %s", req.Dtid)
+	}
+
+	shard, err := s.ts.GetShard(ctx, mmShard.Target.Keyspace, mmShard.Target.Shard)
+	if err != nil {
+		return nil, err
+	}
+	if !shard.HasPrimary() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", mmShard.Target.Keyspace, mmShard.Target.Shard)
+	}
+
+	primary, err := s.ts.GetTablet(ctx, shard.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.tmc.ResolveTransaction(ctx, primary.Tablet, req.Dtid); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.ResolveTransactionResponse{}, nil
+}
+
 func (s *VtctldServer) RestoreFromBackup(req *vtctldatapb.RestoreFromBackupRequest, stream vtctlservicepb.Vtctld_RestoreFromBackupServer) (err error) {
 	span, ctx := trace.NewSpan(stream.Context(), "VtctldServer.RestoreFromBackup")
 	defer span.Finish()
@@ -3395,52 +4110,247 @@ func (s *VtctldServer) RetrySchemaMigration(ctx context.Context, req *vtctldatap
 	return resp, nil
 }
 
-// RunHealthCheck is part of the vtctlservicepb.VtctldServer interface.
-func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunHealthCheckRequest) (resp *vtctldatapb.RunHealthCheckResponse, err error) {
-	span, ctx := trace.NewSpan(ctx, "VtctldServer.RunHealthCheck")
+// RunDiagnosticQuery is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RunDiagnosticQuery(ctx context.Context, req *vtctldatapb.RunDiagnosticQueryRequest) (resp *vtctldatapb.RunDiagnosticQueryResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RunDiagnosticQuery")
 	defer span.Finish()
 
 	defer panicHandler(&err)
 
 	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
+	span.Annotate("query", req.Query.String())
+	span.Annotate("include_query_text", req.IncludeQueryText)
 
 	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.tmc.RunHealthCheck(ctx, ti.Tablet)
+	result, err := s.tmc.RunDiagnosticQuery(ctx, ti.Tablet, &tabletmanagerdatapb.RunDiagnosticQueryRequest{
+		Query:            req.Query,
+		IncludeQueryText: req.IncludeQueryText,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &vtctldatapb.RunHealthCheckResponse{}, nil
+	return &vtctldatapb.RunDiagnosticQueryResponse{Result: result}, nil
 }
 
-// SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldServer interface.
-func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceDurabilityPolicyRequest) (resp *vtctldatapb.SetKeyspaceDurabilityPolicyResponse, err error) {
-	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceDurabilityPolicy")
+// RunHealthCheck is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) RunHealthCheck(ctx context.Context, req *vtctldatapb.RunHealthCheckRequest) (resp *vtctldatapb.RunHealthCheckResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.RunHealthCheck")
 	defer span.Finish()
 
 	defer panicHandler(&err)
 
-	span.Annotate("keyspace", req.Keyspace)
-	span.Annotate("durability_policy", req.DurabilityPolicy)
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.TabletAlias))
 
-	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "SetKeyspaceDurabilityPolicy")
-	if lockErr != nil {
-		err = lockErr
+	ti, err := s.ts.GetTablet(ctx, req.TabletAlias)
+	if err != nil {
 		return nil, err
 	}
 
-	defer unlock(&err)
-
-	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	err = s.tmc.RunHealthCheck(ctx, ti.Tablet)
 	if err != nil {
 		return nil, err
 	}
 
-	policyValid := reparentutil.CheckDurabilityPolicyExists(req.DurabilityPolicy)
+	return &vtctldatapb.RunHealthCheckResponse{}, nil
+}
+
+// SchemaDiff is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SchemaDiff(ctx context.Context, req *vtctldatapb.SchemaDiffRequest) (resp *vtctldatapb.SchemaDiffResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SchemaDiff")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("desired_schema", req.DesiredSchema != "")
+	span.Annotate("tables", strings.Join(req.Tables, ","))
+	span.Annotate("exclude_tables", strings.Join(req.ExcludeTables, ","))
+	span.Annotate("include_views", req.IncludeViews)
+	span.Annotate("skip_no_primary", req.SkipNoPrimary)
+
+	env := s.ws.Environment()
+	senv := schemadiff.NewEnv(env, env.CollationEnv().DefaultConnectionCharset())
+
+	filter, err := tmutils.NewTableFilter(req.Tables, req.ExcludeTables, req.IncludeViews)
+	if err != nil {
+		err = vterrors.Wrapf(err, "invalid table filter")
+		return nil, err
+	}
+
+	shards, err := s.ts.GetShardNames(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(shards)
+
+	var (
+		m       sync.Mutex
+		wg      sync.WaitGroup
+		rec     concurrency.AllErrorRecorder
+		schemas = make(map[string][]string, len(shards))
+	)
+
+	r := &tabletmanagerdatapb.GetSchemaRequest{Tables: req.Tables, ExcludeTables: req.ExcludeTables, IncludeViews: req.IncludeViews}
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard string) {
+			defer wg.Done()
+
+			si, err := s.ts.GetShard(ctx, req.Keyspace, shard)
+			if err != nil {
+				rec.RecordError(fmt.Errorf("GetShard(%v, %v) failed: %w", req.Keyspace, shard, err))
+				return
+			}
+
+			if !si.HasPrimary() {
+				if !req.SkipNoPrimary {
+					rec.RecordError(vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary in shard %v/%v", req.Keyspace, shard))
+				}
+				return
+			}
+
+			sd, err := schematools.GetSchema(ctx, s.ts, s.tmc, si.PrimaryAlias, r)
+			if err != nil {
+				rec.RecordError(err)
+				return
+			}
+
+			queries := make([]string, 0, len(sd.TableDefinitions))
+			for _, td := range sd.TableDefinitions {
+				queries = append(queries, td.Schema)
+			}
+
+			m.Lock()
+			defer m.Unlock()
+			schemas[shard] = queries
+		}(shard)
+	}
+	wg.Wait()
+
+	if rec.HasErrors() {
+		err = rec.Error()
+		return nil, err
+	}
+
+	resp = &vtctldatapb.SchemaDiffResponse{}
+
+	var reference []string
+	if req.DesiredSchema != "" {
+		desired, err := schemadiff.NewSchemaFromSQL(senv, req.DesiredSchema)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "invalid desired schema")
+		}
+
+		for _, entity := range desired.Entities() {
+			tableType := tmutils.TableBaseTable
+			if _, ok := entity.(*schemadiff.CreateViewEntity); ok {
+				tableType = tmutils.TableView
+			}
+
+			if filter.Includes(entity.Name(), tableType) {
+				reference = append(reference, entity.Create().CanonicalStatementString())
+			}
+		}
+
+		resp.Reference = "desired schema"
+	} else {
+		// Without a desired schema, the shards are diffed against the first
+		// one, skipping the shards without a primary.
+		for _, shard := range shards {
+			if queries, ok := schemas[shard]; ok {
+				reference = queries
+				resp.Reference = shard
+				break
+			}
+		}
+	}
+
+	for _, shard := range shards {
+		queries, ok := schemas[shard]
+		if !ok || shard == resp.Reference {
+			continue
+		}
+
+		diffs, err := schematools.SchemaDifferences(senv, shard, queries, reference)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Differences = append(resp.Differences, diffs...)
+	}
+
+	return resp, nil
+}
+
+// SetBackupSchedule is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetBackupSchedule(ctx context.Context, req *vtctldatapb.SetBackupScheduleRequest) (resp *vtctldatapb.SetBackupScheduleResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetBackupSchedule")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+
+	if req.BackupSchedule != nil {
+		span.Annotate("cron_spec", req.BackupSchedule.CronSpec)
+		if _, err = timer.ParseCron(req.BackupSchedule.CronSpec); err != nil {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid backup schedule: %v", err)
+			return nil, err
+		}
+		minRetentionTime, _, durErr := protoutil.DurationFromProto(req.BackupSchedule.MinRetentionTime)
+		if durErr != nil || minRetentionTime < 0 {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid backup schedule min_retention_time %v", req.BackupSchedule.MinRetentionTime)
+			return nil, err
+		}
+		if req.BackupSchedule.MinRetentionCount < 0 {
+			err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid backup schedule min_retention_count %v", req.BackupSchedule.MinRetentionCount)
+			return nil, err
+		}
+	}
+
+	if _, err = s.ts.GetShard(ctx, req.Keyspace, req.Shard); err != nil {
+		return nil, err
+	}
+
+	if err = s.ts.SaveBackupSchedule(ctx, req.Keyspace, req.Shard, req.BackupSchedule); err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.SetBackupScheduleResponse{
+		BackupSchedule: req.BackupSchedule,
+	}, nil
+}
+
+// SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) SetKeyspaceDurabilityPolicy(ctx context.Context, req *vtctldatapb.SetKeyspaceDurabilityPolicyRequest) (resp *vtctldatapb.SetKeyspaceDurabilityPolicyResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.SetKeyspaceDurabilityPolicy")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("durability_policy", req.DurabilityPolicy)
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "SetKeyspaceDurabilityPolicy")
+	if lockErr != nil {
+		err = lockErr
+		return nil, err
+	}
+
+	defer unlock(&err)
+
+	ki, err := s.ts.GetKeyspace(ctx, req.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+
+	policyValid := reparentutil.CheckDurabilityPolicyExists(req.DurabilityPolicy)
 	if !policyValid {
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "durability policy <%v> is not a valid policy. Please register it as a policy first", req.DurabilityPolicy)
 		return nil, err
@@ -4081,6 +4991,201 @@ func (s *VtctldServer) TabletExternallyReparented(ctx context.Context, req *vtct
 	return resp, nil
 }
 
+// TabletExternallyReparentedV2 is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) TabletExternallyReparentedV2(ctx context.Context, req *vtctldatapb.TabletExternallyReparentedV2Request) (resp *vtctldatapb.TabletExternallyReparentedV2Response, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.TabletExternallyReparentedV2")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	if req.Tablet == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "TabletExternallyReparentedV2Request.Tablet must not be nil")
+		return nil, err
+	}
+	if req.TermStartTime == nil {
+		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "TabletExternallyReparentedV2Request.TermStartTime must not be nil")
+		return nil, err
+	}
+	termStartTime := protoutil.TimeFromProto(req.TermStartTime).UTC()
+
+	span.Annotate("tablet_alias", topoproto.TabletAliasString(req.Tablet))
+	span.Annotate("term_start_time", termStartTime.String())
+	span.Annotate("allow_unreachable_old_primary", req.AllowUnreachableOldPrimary)
+
+	tablet, err := s.ts.GetTablet(ctx, req.Tablet)
+	if err != nil {
+		log.Warningf("TabletExternallyReparentedV2: failed to read tablet record for %v: %v", topoproto.TabletAliasString(req.Tablet), err)
+		return nil, err
+	}
+
+	// The shard lock serializes the external reparents of the shard with each
+	// other and with the reparents run by Vitess.
+	ctx, unlock, err := s.ts.LockShard(ctx, tablet.Keyspace, tablet.Shard, fmt.Sprintf("TabletExternallyReparentedV2(%v)", topoproto.TabletAliasString(req.Tablet)))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock(&err)
+
+	shard, err := s.ts.GetShard(ctx, tablet.Keyspace, tablet.Shard)
+	if err != nil {
+		log.Warningf("TabletExternallyReparentedV2: failed to read global shard record for %v/%v: %v", tablet.Keyspace, tablet.Shard, err)
+		return nil, err
+	}
+
+	resp = &vtctldatapb.TabletExternallyReparentedV2Response{
+		Keyspace:   shard.Keyspace(),
+		Shard:      shard.ShardName(),
+		NewPrimary: req.Tablet,
+		OldPrimary: shard.PrimaryAlias,
+	}
+
+	// If the shard record already has the new primary, this is a no-op.
+	if topoproto.TabletAliasEqual(shard.PrimaryAlias, req.Tablet) && tablet.Type == topodatapb.TabletType_PRIMARY {
+		return resp, nil
+	}
+
+	// A failover tool may report its failovers out of order: the reparent
+	// must not go back to an older term.
+	if shard.PrimaryTermStartTime != nil {
+		if shardTermStartTime := protoutil.TimeFromProto(shard.PrimaryTermStartTime); shardTermStartTime.After(termStartTime) {
+			err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %v/%v has a primary term starting at %v, after the term start %v of %v",
+				shard.Keyspace(), shard.ShardName(), shardTermStartTime.UTC(), termStartTime, topoproto.TabletAliasString(req.Tablet))
+			return nil, err
+		}
+	}
+
+	ev := &events.ExternalReparent{
+		Reparent: events.Reparent{
+			ShardInfo:  *shard,
+			NewPrimary: tablet.Tablet.CloneVT(),
+			OldPrimary: &topodatapb.Tablet{
+				Alias: shard.PrimaryAlias,
+				Type:  topodatapb.TabletType_PRIMARY,
+			},
+		},
+		TermStartTime: termStartTime,
+	}
+
+	defer func() {
+		// Ensure we dispatch an update with any failure.
+		if err != nil {
+			event.DispatchUpdate(ev, "failed: "+err.Error())
+		}
+	}()
+
+	event.DispatchUpdate(ev, "starting external reparent")
+
+	var (
+		oldPrimary          *topo.TabletInfo
+		oldPrimaryReachable bool
+	)
+	if shard.PrimaryAlias != nil && !topoproto.TabletAliasEqual(shard.PrimaryAlias, req.Tablet) {
+		oldPrimary, err = s.ts.GetTablet(ctx, shard.PrimaryAlias)
+		if err != nil {
+			log.Warningf("TabletExternallyReparentedV2: failed to read tablet record for old primary %v: %v", topoproto.TabletAliasString(shard.PrimaryAlias), err)
+			return nil, err
+		}
+		ev.OldPrimary = oldPrimary.Tablet.CloneVT()
+
+		event.DispatchUpdate(ev, "verifying that the old primary is fenced")
+		oldPrimaryReachable, err = s.verifyOldPrimaryFenced(ctx, oldPrimary.Tablet, tablet.Tablet, termStartTime)
+		switch {
+		case err == nil:
+			ev.OldPrimaryFenced = true
+			resp.OldPrimaryFenced = true
+		case !oldPrimaryReachable && req.AllowUnreachableOldPrimary:
+			log.Warningf("TabletExternallyReparentedV2: not verifying that unreachable old primary %v is fenced: %v", topoproto.TabletAliasString(shard.PrimaryAlias), err)
+			err = nil
+		default:
+			return nil, err
+		}
+	}
+
+	durabilityName, err := s.ts.GetKeyspaceDurability(ctx, tablet.Keyspace)
+	if err != nil {
+		return nil, err
+	}
+	durability, err := reparentutil.GetDurabilityPolicy(durabilityName)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("TabletExternallyReparentedV2: executing tablet type change %v -> PRIMARY on %v", tablet.Type, topoproto.TabletAliasString(req.Tablet))
+	if err = s.tmc.ChangeType(ctx, tablet.Tablet, topodatapb.TabletType_PRIMARY, reparentutil.SemiSyncAckers(durability, tablet.Tablet) > 0); err != nil {
+		log.Warningf("ChangeType(%v, PRIMARY): %v", topoproto.TabletAliasString(req.Tablet), err)
+		return nil, err
+	}
+
+	event.DispatchUpdate(ev, "updating the shard record")
+	if _, err = s.ts.UpdateShardFields(ctx, tablet.Keyspace, tablet.Shard, func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = req.Tablet
+		si.PrimaryTermStartTime = protoutil.TimeToProto(termStartTime)
+		si.IsPrimaryServing = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if oldPrimary != nil {
+		// A running old primary owns its tablet record, and is demoted through
+		// its tablet manager. The record of an unreachable one is fixed in the
+		// topo, for it to start as a replica.
+		event.DispatchUpdate(ev, "demoting the old primary")
+		if oldPrimaryReachable {
+			err = s.tmc.ChangeType(ctx, oldPrimary.Tablet, topodatapb.TabletType_REPLICA, false)
+		} else {
+			_, err = topotools.ChangeType(ctx, s.ts, oldPrimary.Alias, topodatapb.TabletType_REPLICA, nil)
+		}
+		if err != nil {
+			log.Warningf("TabletExternallyReparentedV2: failed to demote old primary %v: %v", topoproto.TabletAliasString(oldPrimary.Alias), err)
+			return nil, err
+		}
+	}
+
+	event.DispatchUpdate(ev, "finished")
+
+	return resp, nil
+}
+
+// verifyOldPrimaryFenced verifies that oldPrimary is fenced: read-only, and
+// without any transaction that newPrimary misses, which it would have taken
+// after newPrimary was promoted at termStartTime. It returns whether
+// oldPrimary could be reached, which it was if it is not fenced.
+func (s *VtctldServer) verifyOldPrimaryFenced(ctx context.Context, oldPrimary *topodatapb.Tablet, newPrimary *topodatapb.Tablet, termStartTime time.Time) (reachable bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
+	defer cancel()
+
+	oldAlias := topoproto.TabletAliasString(oldPrimary.Alias)
+	status, err := s.tmc.FullStatus(ctx, oldPrimary)
+	if err != nil {
+		return false, vterrors.Wrapf(err, "cannot reach old primary %v to verify that it is fenced", oldAlias)
+	}
+	if !status.ReadOnly {
+		return true, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "old primary %v is not read-only", oldAlias)
+	}
+	if status.PrimaryStatus == nil {
+		return true, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "old primary %v has no binary log position", oldAlias)
+	}
+	oldPos, err := replication.DecodePosition(status.PrimaryStatus.Position)
+	if err != nil {
+		return true, err
+	}
+
+	newPosStr, err := s.tmc.PrimaryPosition(ctx, newPrimary)
+	if err != nil {
+		return true, vterrors.Wrapf(err, "cannot read the position of new primary %v", topoproto.TabletAliasString(newPrimary.Alias))
+	}
+	newPos, err := replication.DecodePosition(newPosStr)
+	if err != nil {
+		return true, err
+	}
+	if !newPos.AtLeast(oldPos) {
+		return true, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "old primary %v at %v has transactions that new primary %v at %v misses, written after the term start %v",
+			oldAlias, oldPos, topoproto.TabletAliasString(newPrimary.Alias), newPos, termStartTime)
+	}
+	return true, nil
+}
+
 // UpdateCellInfo is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) UpdateCellInfo(ctx context.Context, req *vtctldatapb.UpdateCellInfoRequest) (resp *vtctldatapb.UpdateCellInfoResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.UpdateCellInfo")
@@ -4153,9 +5258,324 @@ func (s *VtctldServer) UpdateCellsAlias(ctx context.Context, req *vtctldatapb.Up
 		return nil, err
 	}
 
-	return &vtctldatapb.UpdateCellsAliasResponse{
-		Name:       req.Name,
-		CellsAlias: updatedCa,
+	return &vtctldatapb.UpdateCellsAliasResponse{
+		Name:       req.Name,
+		CellsAlias: updatedCa,
+	}, nil
+}
+
+// UpdateQueryFingerprintRules is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) UpdateQueryFingerprintRules(ctx context.Context, req *vtctldatapb.UpdateQueryFingerprintRulesRequest) (resp *vtctldatapb.UpdateQueryFingerprintRulesResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.UpdateQueryFingerprintRules")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("set_rules", len(req.SetRules))
+	span.Annotate("delete_queries", len(req.DeleteQueries))
+
+	si, err := s.ts.GetShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		return nil, err
+	}
+	if !si.HasPrimary() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", req.Keyspace, req.Shard)
+	}
+
+	tabletMap, err := s.ts.GetTabletMapForShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		return nil, fmt.Errorf("GetTabletMapForShard(%s, %s) failed: %w", req.Keyspace, req.Shard, err)
+	}
+	primaryAlias := topoproto.TabletAliasString(si.PrimaryAlias)
+	primary, ok := tabletMap[primaryAlias]
+	if !ok {
+		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "primary %s of shard %s/%s not found", primaryAlias, req.Keyspace, req.Shard)
+	}
+
+	// The rules are written to the sidecar database of the primary, which
+	// replicates them to the other tablets of the shard.
+	result, err := s.tmc.UpdateQueryFingerprintRules(ctx, primary.Tablet, &tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest{
+		SetRules:      req.SetRules,
+		DeleteQueries: req.DeleteQueries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("UpdateQueryFingerprintRules(%s) failed: %w", primaryAlias, err)
+	}
+	pos, err := s.tmc.PrimaryPosition(ctx, primary.Tablet)
+	if err != nil {
+		return nil, fmt.Errorf("PrimaryPosition(%s) failed: %w", primaryAlias, err)
+	}
+
+	// The other tablets of the shard reload the rules once they have
+	// replicated the update.
+	var (
+		wg  sync.WaitGroup
+		rec concurrency.AllErrorRecorder
+	)
+	for alias, ti := range tabletMap {
+		if alias == primaryAlias {
+			continue
+		}
+		wg.Add(1)
+		go func(alias string, tablet *topodatapb.Tablet) {
+			defer wg.Done()
+
+			if err := s.tmc.WaitForPosition(ctx, tablet, pos); err != nil {
+				rec.RecordError(fmt.Errorf("WaitForPosition(%s) failed: %w", alias, err))
+				return
+			}
+			if _, err := s.tmc.UpdateQueryFingerprintRules(ctx, tablet, &tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest{}); err != nil {
+				rec.RecordError(fmt.Errorf("UpdateQueryFingerprintRules(%s) failed: %w", alias, err))
+			}
+		}(alias, ti.Tablet)
+	}
+	wg.Wait()
+	if rec.HasErrors() {
+		return nil, rec.Error()
+	}
+
+	return &vtctldatapb.UpdateQueryFingerprintRulesResponse{Rules: result.Rules}, nil
+}
+
+// UpdateSrvKeyspacePartitions is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) UpdateSrvKeyspacePartitions(ctx context.Context, req *vtctldatapb.UpdateSrvKeyspacePartitionsRequest) (resp *vtctldatapb.UpdateSrvKeyspacePartitionsResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.UpdateSrvKeyspacePartitions")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("tablet_type", topoproto.TabletTypeLString(req.TabletType))
+	span.Annotate("cells", strings.Join(req.Cells, ","))
+	span.Annotate("remove", req.Remove)
+
+	ctx, unlock, lockErr := s.ts.LockKeyspace(ctx, req.Keyspace, "UpdateSrvKeyspacePartitions")
+	if lockErr != nil {
+		err = lockErr
+		return nil, err
+	}
+	defer unlock(&err)
+
+	si, err := s.ts.GetShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Remove {
+		err = s.ts.DeleteSrvKeyspacePartitions(ctx, req.Keyspace, []*topo.ShardInfo{si}, req.TabletType, req.Cells)
+	} else {
+		err = s.ts.AddSrvKeyspacePartitions(ctx, req.Keyspace, []*topo.ShardInfo{si}, req.TabletType, req.Cells)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &vtctldatapb.UpdateSrvKeyspacePartitionsResponse{}, nil
+}
+
+// UpgradeShardMysql is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) UpgradeShardMysql(ctx context.Context, req *vtctldatapb.UpgradeShardMysqlRequest) (resp *vtctldatapb.UpgradeShardMysqlResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.UpgradeShardMysql")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	waitReplicasTimeout, ok, err := protoutil.DurationFromProto(req.WaitReplicasTimeout)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		waitReplicasTimeout = time.Second * 30
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("in_place", req.InPlace)
+	span.Annotate("wait_replicas_timeout_sec", waitReplicasTimeout.Seconds())
+
+	if req.NewPrimary != nil {
+		span.Annotate("new_primary_alias", topoproto.TabletAliasString(req.NewPrimary))
+	}
+
+	m := sync.RWMutex{}
+	logstream := []*logutilpb.Event{}
+	logger := logutil.NewCallbackLogger(func(e *logutilpb.Event) {
+		m.Lock()
+		defer m.Unlock()
+
+		logstream = append(logstream, e)
+	})
+
+	resp = &vtctldatapb.UpgradeShardMysqlResponse{}
+	defer func() {
+		m.RLock()
+		defer m.RUnlock()
+
+		resp.Events = make([]*logutilpb.Event, len(logstream))
+		copy(resp.Events, logstream)
+	}()
+
+	if req.InPlace && req.NewPrimary != nil {
+		return resp, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot promote %s when upgrading the primary in place", topoproto.TabletAliasString(req.NewPrimary))
+	}
+
+	shard, err := s.ts.GetShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		return resp, err
+	}
+	if !shard.HasPrimary() {
+		return resp, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no primary tablet for shard %v/%v", req.Keyspace, req.Shard)
+	}
+	primaryAlias := shard.PrimaryAlias
+
+	tabletMap, err := s.ts.GetTabletMapForShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		return resp, err
+	}
+	var replicas []*topodatapb.TabletAlias
+	for _, tablet := range tabletMap {
+		if topoproto.TabletAliasEqual(tablet.Alias, primaryAlias) {
+			continue
+		}
+		switch tablet.Type {
+		case topodatapb.TabletType_BACKUP, topodatapb.TabletType_RESTORE:
+			return resp, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "tablet %s is in the middle of a %s, retry when it is done", topoproto.TabletAliasString(tablet.Alias), topoproto.TabletTypeLString(tablet.Type))
+		}
+		replicas = append(replicas, tablet.Alias)
+	}
+	sort.Slice(replicas, func(i, j int) bool {
+		return topoproto.TabletAliasString(replicas[i]) < topoproto.TabletAliasString(replicas[j])
+	})
+
+	if req.NewPrimary != nil && !slices.ContainsFunc(replicas, func(alias *topodatapb.TabletAlias) bool {
+		return topoproto.TabletAliasEqual(alias, req.NewPrimary)
+	}) {
+		return resp, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "tablet %s is not a replica of shard %v/%v", topoproto.TabletAliasString(req.NewPrimary), req.Keyspace, req.Shard)
+	}
+	if !req.InPlace && len(replicas) == 0 {
+		return resp, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %v/%v has no replica to promote, its primary can only be upgraded in place", req.Keyspace, req.Shard)
+	}
+
+	// Upgrade the replicas first, one at a time, so the shard keeps serving
+	// reads from the others.
+	for _, alias := range replicas {
+		upgrade, err := s.upgradeReplicaMysql(ctx, logger, alias, waitReplicasTimeout)
+		if err != nil {
+			return resp, err
+		}
+		resp.Upgrades = append(resp.Upgrades, upgrade)
+	}
+
+	if req.InPlace {
+		primary, err := s.ts.GetTablet(ctx, primaryAlias)
+		if err != nil {
+			return resp, err
+		}
+		logger.Infof("Upgrading MySQL of primary %s in place", topoproto.TabletAliasString(primaryAlias))
+		r, err := s.tmc.UpgradeMysql(ctx, primary.Tablet, &tabletmanagerdatapb.UpgradeMysqlRequest{AllowPrimary: true})
+		if err != nil {
+			return resp, vterrors.Wrapf(err, "failed to upgrade MySQL of primary %s", topoproto.TabletAliasString(primaryAlias))
+		}
+		logger.Infof("Upgraded MySQL of primary %s from %s to %s", topoproto.TabletAliasString(primaryAlias), r.VersionBefore, r.VersionAfter)
+		resp.Upgrades = append(resp.Upgrades, &vtctldatapb.UpgradeShardMysqlResponse_TabletUpgrade{
+			TabletAlias:   primaryAlias,
+			VersionBefore: r.VersionBefore,
+			VersionAfter:  r.VersionAfter,
+		})
+		return resp, nil
+	}
+
+	// Promote an upgraded replica, and upgrade the old primary as one of its
+	// replicas.
+	ev, err := reparentutil.NewPlannedReparenter(s.ts, s.tmc, logger).ReparentShard(ctx,
+		req.Keyspace,
+		req.Shard,
+		reparentutil.PlannedReparentOptions{
+			AvoidPrimaryAlias:   primaryAlias,
+			NewPrimaryAlias:     req.NewPrimary,
+			WaitReplicasTimeout: waitReplicasTimeout,
+		},
+	)
+	if err != nil {
+		return resp, vterrors.Wrapf(err, "failed to reparent shard %v/%v to an upgraded replica", req.Keyspace, req.Shard)
+	}
+	if ev != nil && ev.NewPrimary != nil && !topoproto.TabletAliasIsZero(ev.NewPrimary.Alias) {
+		resp.PromotedPrimary = ev.NewPrimary.Alias
+	}
+
+	upgrade, err := s.upgradeReplicaMysql(ctx, logger, primaryAlias, waitReplicasTimeout)
+	if err != nil {
+		return resp, err
+	}
+	resp.Upgrades = append(resp.Upgrades, upgrade)
+
+	return resp, nil
+}
+
+// upgradeReplicaMysql upgrades the MySQL of a replica. A serving replica is
+// drained while its MySQL restarts, and is put back in serving once it caught
+// up with the primary of its shard.
+func (s *VtctldServer) upgradeReplicaMysql(ctx context.Context, logger logutil.Logger, alias *topodatapb.TabletAlias, waitReplicasTimeout time.Duration) (*vtctldatapb.UpgradeShardMysqlResponse_TabletUpgrade, error) {
+	aliasStr := topoproto.TabletAliasString(alias)
+	tablet, err := s.ts.GetTablet(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	servingType := tablet.Type
+	if topo.IsInServingGraph(servingType) {
+		logger.Infof("Taking tablet %s out of serving", aliasStr)
+		if _, err := s.ChangeTabletType(ctx, &vtctldatapb.ChangeTabletTypeRequest{
+			TabletAlias: alias,
+			DbType:      topodatapb.TabletType_DRAINED,
+		}); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to take tablet %s out of serving", aliasStr)
+		}
+	}
+
+	logger.Infof("Upgrading MySQL of tablet %s", aliasStr)
+	r, err := s.tmc.UpgradeMysql(ctx, tablet.Tablet, &tabletmanagerdatapb.UpgradeMysqlRequest{})
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to upgrade MySQL of tablet %s", aliasStr)
+	}
+	logger.Infof("Upgraded MySQL of tablet %s from %s to %s", aliasStr, r.VersionBefore, r.VersionAfter)
+
+	// Verify that the replica replicates from the primary before putting it
+	// back in serving.
+	shard, err := s.ts.GetShard(ctx, tablet.Keyspace, tablet.Shard)
+	if err != nil {
+		return nil, err
+	}
+	primary, err := s.ts.GetTablet(ctx, shard.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := s.tmc.PrimaryPosition(ctx, primary.Tablet)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get the position of primary %s", topoproto.TabletAliasString(primary.Alias))
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, waitReplicasTimeout)
+	defer cancel()
+	if err := s.tmc.WaitForPosition(waitCtx, tablet.Tablet, pos); err != nil {
+		return nil, vterrors.Wrapf(err, "upgraded tablet %s did not catch up with primary %s, it is left out of serving", aliasStr, topoproto.TabletAliasString(primary.Alias))
+	}
+
+	if topo.IsInServingGraph(servingType) {
+		logger.Infof("Putting tablet %s back in serving as %s", aliasStr, topoproto.TabletTypeLString(servingType))
+		if _, err := s.ChangeTabletType(ctx, &vtctldatapb.ChangeTabletTypeRequest{
+			TabletAlias: alias,
+			DbType:      servingType,
+		}); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to put tablet %s back in serving", aliasStr)
+		}
+	}
+
+	return &vtctldatapb.UpgradeShardMysqlResponse_TabletUpgrade{
+		TabletAlias:   alias,
+		VersionBefore: r.VersionBefore,
+		VersionAfter:  r.VersionAfter,
 	}, nil
 }
 
@@ -4346,6 +5766,146 @@ func (s *VtctldServer) ValidateKeyspace(ctx context.Context, req *vtctldatapb.Va
 	return resp, err
 }
 
+// ValidatePermissionsKeyspace is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ValidatePermissionsKeyspace(ctx context.Context, req *vtctldatapb.ValidatePermissionsKeyspaceRequest) (resp *vtctldatapb.ValidatePermissionsKeyspaceResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidatePermissionsKeyspace")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	keyspace := req.Keyspace
+
+	shards, err := s.ts.GetShardNames(ctx, keyspace)
+	resp = &vtctldatapb.ValidatePermissionsKeyspaceResponse{
+		Results:        []string{},
+		ResultsByShard: make(map[string]*vtctldatapb.ValidateShardResponse, len(shards)),
+	}
+
+	if err != nil {
+		resp.Results = append(resp.Results, fmt.Sprintf("TopologyServer.GetShardNames(%v) failed: %v", keyspace, err))
+		err = nil
+		return resp, err
+	}
+
+	if len(shards) == 0 {
+		resp.Results = append(resp.Results, fmt.Sprintf("no shards in keyspace %v", keyspace))
+		return resp, err
+	}
+
+	sort.Strings(shards)
+
+	si, err := s.ts.GetShard(ctx, keyspace, shards[0])
+	if err != nil {
+		resp.Results = append(resp.Results, fmt.Sprintf("GetShard(%v, %v) failed: %v", keyspace, shards[0], err))
+		err = nil
+		return resp, err
+	}
+	if !si.HasPrimary() {
+		resp.Results = append(resp.Results, fmt.Sprintf("no primary in shard %v/%v", keyspace, shards[0]))
+		return resp, err
+	}
+
+	referenceAlias := si.PrimaryAlias
+	referencePermissions, err := s.getPermissions(ctx, referenceAlias)
+	if err != nil {
+		resp.Results = append(resp.Results, fmt.Sprintf("unable to get reference permissions of first shard's primary tablet: %v", err))
+		err = nil
+		return resp, err
+	}
+
+	for _, shard := range shards {
+		shardResp := &vtctldatapb.ValidateShardResponse{
+			Results: []string{},
+		}
+		resp.ResultsByShard[shard] = shardResp
+
+		aliases, err := s.ts.FindAllTabletAliasesInShard(ctx, keyspace, shard)
+		if err != nil {
+			errMessage := fmt.Sprintf("FindAllTabletAliasesInShard(%v, %v) failed: %v", keyspace, shard, err)
+			shardResp.Results = append(shardResp.Results, errMessage)
+			resp.Results = append(resp.Results, errMessage)
+			continue
+		}
+
+		er := concurrency.AllErrorRecorder{}
+		wg := sync.WaitGroup{}
+		for _, alias := range aliases {
+			if topoproto.TabletAliasEqual(alias, referenceAlias) {
+				continue
+			}
+
+			wg.Add(1)
+			go s.diffPermissions(ctx, referencePermissions, referenceAlias, alias, &wg, &er)
+		}
+		wg.Wait()
+
+		if er.HasErrors() {
+			shardResp.Results = append(shardResp.Results, er.ErrorStrings()...)
+			resp.Results = append(resp.Results, shardResp.Results...)
+		}
+	}
+
+	return resp, err
+}
+
+// ValidatePermissionsShard is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ValidatePermissionsShard(ctx context.Context, req *vtctldatapb.ValidatePermissionsShardRequest) (resp *vtctldatapb.ValidatePermissionsShardResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidatePermissionsShard")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+
+	si, err := s.ts.GetShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		err = fmt.Errorf("GetShard(%s) failed: %v", req.Shard, err)
+		return nil, err
+	}
+
+	if !si.HasPrimary() {
+		err = fmt.Errorf("no primary in shard %v/%v", req.Keyspace, req.Shard)
+		return nil, err
+	}
+
+	log.Infof("Gathering permissions for primary %v", topoproto.TabletAliasString(si.PrimaryAlias))
+	primaryPermissions, err := s.getPermissions(ctx, si.PrimaryAlias)
+	if err != nil {
+		err = fmt.Errorf("GetPermissions(%s) failed: %v", topoproto.TabletAliasString(si.PrimaryAlias), err)
+		return nil, err
+	}
+
+	aliases, err := s.ts.FindAllTabletAliasesInShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		err = fmt.Errorf("FindAllTabletAliasesInShard(%s, %s) failed: %v", req.Keyspace, req.Shard, err)
+		return nil, err
+	}
+
+	er := concurrency.AllErrorRecorder{}
+	wg := sync.WaitGroup{}
+	for _, alias := range aliases {
+		if topoproto.TabletAliasEqual(alias, si.PrimaryAlias) {
+			continue
+		}
+
+		wg.Add(1)
+		go s.diffPermissions(ctx, primaryPermissions, si.PrimaryAlias, alias, &wg, &er)
+	}
+
+	wg.Wait()
+
+	resp = &vtctldatapb.ValidatePermissionsShardResponse{
+		Results: []string{},
+	}
+	if er.HasErrors() {
+		resp.Results = append(resp.Results, er.ErrorStrings()...)
+	}
+
+	return resp, nil
+}
+
 // ValidateSchemaKeyspace is a part of the vtctlservicepb.VtctldServer interface.
 // It will diff the schema from all the tablets in the keyspace.
 func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldatapb.ValidateSchemaKeyspaceRequest) (resp *vtctldatapb.ValidateSchemaKeyspaceResponse, err error) {
@@ -4486,6 +6046,94 @@ func (s *VtctldServer) ValidateSchemaKeyspace(ctx context.Context, req *vtctldat
 	return resp, err
 }
 
+// ValidateSchemaShard is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) ValidateSchemaShard(ctx context.Context, req *vtctldatapb.ValidateSchemaShardRequest) (resp *vtctldatapb.ValidateSchemaShardResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateSchemaShard")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("include_views", req.IncludeViews)
+	span.Annotate("include_vschema", req.IncludeVschema)
+
+	si, err := s.ts.GetShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		err = fmt.Errorf("GetShard(%s) failed: %v", req.Shard, err)
+		return nil, err
+	}
+
+	if !si.HasPrimary() {
+		err = fmt.Errorf("no primary in shard %v/%v", req.Keyspace, req.Shard)
+		return nil, err
+	}
+
+	resp = &vtctldatapb.ValidateSchemaShardResponse{
+		Results: []string{},
+	}
+
+	if req.IncludeVschema {
+		results, err2 := s.ValidateVSchema(ctx, &vtctldatapb.ValidateVSchemaRequest{
+			Keyspace:      req.Keyspace,
+			Shards:        []string{req.Shard},
+			ExcludeTables: req.ExcludeTables,
+			IncludeViews:  req.IncludeViews,
+		})
+		if err2 != nil {
+			err = err2
+			return nil, err
+		}
+
+		if len(results.Results) > 0 {
+			resp.Results = append(resp.Results, results.Results...)
+			return resp, err
+		}
+	}
+
+	r := &tabletmanagerdatapb.GetSchemaRequest{ExcludeTables: req.ExcludeTables, IncludeViews: req.IncludeViews}
+	log.Infof("Gathering schema for primary %v", topoproto.TabletAliasString(si.PrimaryAlias))
+	primarySchema, err := schematools.GetSchema(ctx, s.ts, s.tmc, si.PrimaryAlias, r)
+	if err != nil {
+		err = fmt.Errorf("GetSchema(%s, %v, %v) failed: %v", topoproto.TabletAliasString(si.PrimaryAlias), req.ExcludeTables, req.IncludeViews, err)
+		return nil, err
+	}
+
+	aliases, err := s.ts.FindAllTabletAliasesInShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		err = fmt.Errorf("FindAllTabletAliasesInShard(%s, %s) failed: %v", req.Keyspace, req.Shard, err)
+		return nil, err
+	}
+
+	er := concurrency.AllErrorRecorder{}
+	wg := sync.WaitGroup{}
+	for _, alias := range aliases {
+		if topoproto.TabletAliasEqual(alias, si.PrimaryAlias) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(alias *topodatapb.TabletAlias) {
+			defer wg.Done()
+			replicaSchema, err := schematools.GetSchema(ctx, s.ts, s.tmc, alias, r)
+			if err != nil {
+				er.RecordError(fmt.Errorf("GetSchema(%v, %v, %v) failed: %v", topoproto.TabletAliasString(alias), req.ExcludeTables, req.IncludeViews, err))
+				return
+			}
+
+			tmutils.DiffSchema(topoproto.TabletAliasString(si.PrimaryAlias), primarySchema, topoproto.TabletAliasString(alias), replicaSchema, &er)
+		}(alias)
+	}
+
+	wg.Wait()
+
+	if er.HasErrors() {
+		resp.Results = append(resp.Results, er.ErrorStrings()...)
+	}
+
+	return resp, nil
+}
+
 // ValidateShard is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) ValidateShard(ctx context.Context, req *vtctldatapb.ValidateShardRequest) (resp *vtctldatapb.ValidateShardResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.ValidateShard")
@@ -4995,6 +6643,94 @@ func (s *VtctldServer) VDiffStop(ctx context.Context, req *vtctldatapb.VDiffStop
 	return resp, err
 }
 
+// WaitForFilteredReplication is part of the vtctlservicepb.VtctldServer interface.
+func (s *VtctldServer) WaitForFilteredReplication(ctx context.Context, req *vtctldatapb.WaitForFilteredReplicationRequest) (resp *vtctldatapb.WaitForFilteredReplicationResponse, err error) {
+	span, ctx := trace.NewSpan(ctx, "VtctldServer.WaitForFilteredReplication")
+	defer span.Finish()
+
+	defer panicHandler(&err)
+
+	maxDelay, ok, err := protoutil.DurationFromProto(req.MaxDelay)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		maxDelay = DefaultWaitForFilteredReplicationMaxDelay
+	}
+
+	span.Annotate("keyspace", req.Keyspace)
+	span.Annotate("shard", req.Shard)
+	span.Annotate("max_delay_sec", maxDelay.Seconds())
+
+	si, err := s.ts.GetShard(ctx, req.Keyspace, req.Shard)
+	if err != nil {
+		return nil, err
+	}
+	if len(si.SourceShards) == 0 {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no source shard", req.Keyspace, req.Shard)
+	}
+	if !si.HasPrimary() {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %s/%s has no primary", req.Keyspace, req.Shard)
+	}
+
+	alias := topoproto.TabletAliasString(si.PrimaryAlias)
+	ti, err := s.ts.GetTablet(ctx, si.PrimaryAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	// Always run an explicit healthcheck first, so that the health stream
+	// doesn't start with an outdated filtered replication lag.
+	if err = s.tmc.RunHealthCheck(ctx, ti.Tablet); err != nil {
+		return nil, vterrors.Wrapf(err, "failed to run explicit healthcheck on tablet %s", alias)
+	}
+
+	conn, err := tabletconn.GetDialer()(ti.Tablet, grpcclient.FailFast(false))
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot connect to tablet %s", alias)
+	}
+	defer conn.Close(ctx)
+
+	var (
+		lastSeenDelay time.Duration
+		caughtUp      bool
+	)
+	err = conn.StreamHealth(ctx, func(shr *querypb.StreamHealthResponse) error {
+		stats := shr.RealtimeStats
+		if stats == nil {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "health record of tablet %s does not include RealtimeStats", alias)
+		}
+		if stats.HealthError != "" {
+			return vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "tablet %s is not healthy: %s", alias, stats.HealthError)
+		}
+		if stats.BinlogPlayersCount == 0 {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no filtered replication running on tablet %s", alias)
+		}
+
+		lastSeenDelay = time.Duration(stats.FilteredReplicationLagSeconds) * time.Second
+		if lastSeenDelay < 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "tablet %s reported a negative filtered replication lag: %v", alias, lastSeenDelay)
+		}
+		if lastSeenDelay <= maxDelay {
+			log.Infof("Filtered replication on tablet %s has caught up, last seen delay: %.1f seconds", alias, lastSeenDelay.Seconds())
+			caughtUp = true
+			return io.EOF
+		}
+
+		log.Infof("Waiting for filtered replication to catch up on tablet %s, last seen delay: %.1f seconds", alias, lastSeenDelay.Seconds())
+		return nil
+	})
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, vterrors.Wrapf(err, "could not stream health records from tablet %s", alias)
+	}
+	if !caughtUp {
+		return nil, vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "filtered replication on tablet %s did not catch up, last seen delay: %v", alias, lastSeenDelay)
+	}
+
+	return &vtctldatapb.WaitForFilteredReplicationResponse{
+		LastSeenDelay: protoutil.DurationToProto(lastSeenDelay),
+	}, nil
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldServer interface.
 func (s *VtctldServer) WorkflowDelete(ctx context.Context, req *vtctldatapb.WorkflowDeleteRequest) (resp *vtctldatapb.WorkflowDeleteResponse, err error) {
 	span, ctx := trace.NewSpan(ctx, "VtctldServer.WorkflowDelete")
@@ -5149,6 +6885,29 @@ func GetVersionFunc() func(string) (string, error) {
 	return getVersionFromTablet
 }
 
+// getPermissions returns the permissions of a tablet.
+func (s *VtctldServer) getPermissions(ctx context.Context, alias *topodatapb.TabletAlias) (*tabletmanagerdatapb.Permissions, error) {
+	ti, err := s.ts.GetTablet(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tmc.GetPermissions(ctx, ti.Tablet)
+}
+
+// helper method to asynchronously get and diff permissions
+func (s *VtctldServer) diffPermissions(ctx context.Context, primaryPermissions *tabletmanagerdatapb.Permissions, primaryAlias *topodatapb.TabletAlias, alias *topodatapb.TabletAlias, wg *sync.WaitGroup, er concurrency.ErrorRecorder) {
+	defer wg.Done()
+	log.Infof("Gathering permissions for %v", topoproto.TabletAliasString(alias))
+	replicaPermissions, err := s.getPermissions(ctx, alias)
+	if err != nil {
+		er.RecordError(fmt.Errorf("unable to get permissions for tablet %v: %v", topoproto.TabletAliasString(alias), err))
+		return
+	}
+
+	tmutils.DiffPermissions(topoproto.TabletAliasString(primaryAlias), primaryPermissions, topoproto.TabletAliasString(alias), replicaPermissions, er)
+}
+
 // helper method to asynchronously get and diff a version
 func (s *VtctldServer) diffVersion(ctx context.Context, primaryVersion string, primaryAlias *topodatapb.TabletAlias, alias *topodatapb.TabletAlias, wg *sync.WaitGroup, er concurrency.ErrorRecorder) {
 	defer wg.Done()
@@ -24,6 +24,7 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -37,8 +38,10 @@ import (
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/grpcclient"
 	hk "vitess.io/vitess/go/vt/hook"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
 	"vitess.io/vitess/go/vt/proto/vttime"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
@@ -47,6 +50,10 @@ import (
 	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
 	"vitess.io/vitess/go/vt/vtctl/schematools"
 	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/queryservice/fakes"
+	"vitess.io/vitess/go/vt/vttablet/tabletconn"
+	"vitess.io/vitess/go/vt/vttablet/tabletconntest"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 	"vitess.io/vitess/go/vt/vttablet/tmclienttest"
 
@@ -75,8 +82,22 @@ func init() {
 	tmclient.RegisterTabletManagerClientFactory("grpcvtctldserver.test", func() tmclient.TabletManagerClient {
 		return nil
 	})
+
+	// Tests that stream the health of a tablet register its QueryService in
+	// testQueryServices, keyed by tablet alias.
+	tabletconntest.SetProtocol("go.vt.vtctl.grpcvtctldserver.tabletconn", "grpcvtctldserver.test")
+	tabletconn.RegisterDialer("grpcvtctldserver.test", func(tablet *topodatapb.Tablet, failFast grpcclient.FailFast) (queryservice.QueryService, error) {
+		qs, ok := testQueryServices.Load(topoproto.TabletAliasString(tablet.Alias))
+		if !ok {
+			return nil, fmt.Errorf("no QueryService for tablet %s", topoproto.TabletAliasString(tablet.Alias))
+		}
+		return qs.(queryservice.QueryService), nil
+	})
 }
 
+// testQueryServices holds the QueryService of the tablets dialed by tests.
+var testQueryServices sync.Map
+
 func TestPanicHandler(t *testing.T) {
 	t.Parallel()
 
@@ -231,6 +252,80 @@ func TestAddCellsAlias(t *testing.T) {
 	}
 }
 
+func TestApplyQuotaRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *vtctldatapb.ApplyQuotaRulesRequest
+		expectedSrv *vschemapb.QuotaRules
+		shouldErr   bool
+	}{
+		{
+			name: "success",
+			req: &vtctldatapb.ApplyQuotaRulesRequest{
+				QuotaRules: &vschemapb.QuotaRules{
+					Rules: []*vschemapb.QuotaRule{{Name: "per-user", User: "*", MaxQps: 10}},
+				},
+			},
+			expectedSrv: &vschemapb.QuotaRules{
+				Rules: []*vschemapb.QuotaRule{{Name: "per-user", User: "*", MaxQps: 10}},
+			},
+		},
+		{
+			name: "skip rebuild",
+			req: &vtctldatapb.ApplyQuotaRulesRequest{
+				QuotaRules: &vschemapb.QuotaRules{
+					Rules: []*vschemapb.QuotaRule{{Name: "per-user", User: "*", MaxQps: 10}},
+				},
+				SkipRebuild: true,
+			},
+			expectedSrv: nil,
+		},
+		{
+			name: "invalid rules",
+			req: &vtctldatapb.ApplyQuotaRulesRequest{
+				QuotaRules: &vschemapb.QuotaRules{
+					Rules: []*vschemapb.QuotaRule{{Name: "no-limit", User: "*"}},
+				},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			_, err := vtctld.ApplyQuotaRules(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			resp, err := vtctld.GetQuotaRules(ctx, &vtctldatapb.GetQuotaRulesRequest{})
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.req.QuotaRules, resp.QuotaRules)
+
+			srvVSchema, err := ts.GetSrvVSchema(ctx, "zone1")
+			if tt.expectedSrv == nil {
+				assert.True(t, topo.IsErrType(err, topo.NoNode), "expected no SrvVSchema, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expectedSrv, srvVSchema.QuotaRules)
+		})
+	}
+}
+
 func TestApplyRoutingRules(t *testing.T) {
 	t.Parallel()
 
@@ -310,6 +405,51 @@ func TestApplyRoutingRules(t *testing.T) {
 			},
 			shouldErr: false,
 		},
+		{
+			name:  "scheduled cutover",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyRoutingRulesRequest{
+				RoutingRules: &vschemapb.RoutingRules{
+					Rules: []*vschemapb.RoutingRule{
+						{
+							FromTable:         "t1",
+							ToTables:          []string{"source.t1"},
+							TargetTable:       "target.t1",
+							TargetReadPercent: 10,
+							CutoverTime:       &vttime.Time{Seconds: 1700000000},
+						},
+					},
+				},
+			},
+			expectedRules: &vschemapb.RoutingRules{
+				Rules: []*vschemapb.RoutingRule{
+					{
+						FromTable:         "t1",
+						ToTables:          []string{"source.t1"},
+						TargetTable:       "target.t1",
+						TargetReadPercent: 10,
+						CutoverTime:       &vttime.Time{Seconds: 1700000000},
+					},
+				},
+			},
+		},
+		{
+			name:  "invalid target read percent",
+			cells: []string{"zone1"},
+			req: &vtctldatapb.ApplyRoutingRulesRequest{
+				RoutingRules: &vschemapb.RoutingRules{
+					Rules: []*vschemapb.RoutingRule{
+						{
+							FromTable:         "t1",
+							ToTables:          []string{"source.t1"},
+							TargetTable:       "target.t1",
+							TargetReadPercent: 101,
+						},
+					},
+				},
+			},
+			shouldErr: true,
+		},
 		{
 			name:      "topo down",
 			cells:     []string{"zone1"},
@@ -344,6 +484,109 @@ func TestApplyRoutingRules(t *testing.T) {
 	}
 }
 
+func TestApplyTenantRoutingRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		req         *vtctldatapb.ApplyTenantRoutingRulesRequest
+		expectedSrv *vschemapb.TenantRoutingRules
+		shouldErr   bool
+	}{
+		{
+			name: "success",
+			req: &vtctldatapb.ApplyTenantRoutingRulesRequest{
+				TenantRoutingRules: &vschemapb.TenantRoutingRules{
+					Rules: []*vschemapb.TenantRoutingRule{
+						{TenantId: "acme", Target: "ks1"},
+						{TenantId: "globex", Target: "ks2:-80"},
+						{TenantId: "initech", Target: "ks2[80-]"},
+					},
+				},
+			},
+			expectedSrv: &vschemapb.TenantRoutingRules{
+				Rules: []*vschemapb.TenantRoutingRule{
+					{TenantId: "acme", Target: "ks1"},
+					{TenantId: "globex", Target: "ks2:-80"},
+					{TenantId: "initech", Target: "ks2[80-]"},
+				},
+			},
+		},
+		{
+			name: "skip rebuild",
+			req: &vtctldatapb.ApplyTenantRoutingRulesRequest{
+				TenantRoutingRules: &vschemapb.TenantRoutingRules{
+					Rules: []*vschemapb.TenantRoutingRule{{TenantId: "acme", Target: "ks1"}},
+				},
+				SkipRebuild: true,
+			},
+			expectedSrv: nil,
+		},
+		{
+			name: "duplicate tenant",
+			req: &vtctldatapb.ApplyTenantRoutingRulesRequest{
+				TenantRoutingRules: &vschemapb.TenantRoutingRules{
+					Rules: []*vschemapb.TenantRoutingRule{
+						{TenantId: "acme", Target: "ks1"},
+						{TenantId: "acme", Target: "ks2"},
+					},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "tablet type in target",
+			req: &vtctldatapb.ApplyTenantRoutingRulesRequest{
+				TenantRoutingRules: &vschemapb.TenantRoutingRules{
+					Rules: []*vschemapb.TenantRoutingRule{{TenantId: "acme", Target: "ks1@replica"}},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "no keyspace in target",
+			req: &vtctldatapb.ApplyTenantRoutingRulesRequest{
+				TenantRoutingRules: &vschemapb.TenantRoutingRules{
+					Rules: []*vschemapb.TenantRoutingRule{{TenantId: "acme", Target: ":-80"}},
+				},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			_, err := vtctld.ApplyTenantRoutingRules(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			resp, err := vtctld.GetTenantRoutingRules(ctx, &vtctldatapb.GetTenantRoutingRulesRequest{})
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.req.TenantRoutingRules, resp.TenantRoutingRules)
+
+			srvVSchema, err := ts.GetSrvVSchema(ctx, "zone1")
+			if tt.expectedSrv == nil {
+				assert.True(t, topo.IsErrType(err, topo.NoNode), "expected no SrvVSchema, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expectedSrv, srvVSchema.TenantRoutingRules)
+		})
+	}
+}
+
 func TestApplyVSchema(t *testing.T) {
 	t.Parallel()
 
@@ -670,6 +913,12 @@ func TestApplyVSchema(t *testing.T) {
 					ShardRoutingRules: &vschemapb.ShardRoutingRules{
 						Rules: []*vschemapb.ShardRoutingRule{},
 					},
+					QuotaRules: &vschemapb.QuotaRules{
+						Rules: []*vschemapb.QuotaRule{},
+					},
+					TenantRoutingRules: &vschemapb.TenantRoutingRules{
+						Rules: []*vschemapb.TenantRoutingRule{},
+					},
 				}
 				utils.MustMatch(t, changedSrvVSchema, finalSrvVSchema)
 			}
@@ -2364,6 +2613,72 @@ func TestCompleteSchemaMigration(t *testing.T) {
 	}
 }
 
+func TestCopySchemaShardValidation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+		Keyspace: "testkeyspace",
+		Name:     "-",
+	})
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	tests := []struct {
+		name string
+		req  *vtctldatapb.CopySchemaShardRequest
+		err  string
+	}{
+		{
+			name: "missing destination",
+			req: &vtctldatapb.CopySchemaShardRequest{
+				SourceKeyspace: "testkeyspace",
+				SourceShard:    "-",
+			},
+			err: "destination keyspace and shard are required",
+		},
+		{
+			name: "missing source",
+			req: &vtctldatapb.CopySchemaShardRequest{
+				DestinationKeyspace: "otherkeyspace",
+				DestinationShard:    "-",
+			},
+			err: "either a source tablet alias or a source keyspace and shard are required",
+		},
+		{
+			name: "both sources",
+			req: &vtctldatapb.CopySchemaShardRequest{
+				SourceTabletAlias:   &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+				SourceKeyspace:      "testkeyspace",
+				SourceShard:         "-",
+				DestinationKeyspace: "otherkeyspace",
+				DestinationShard:    "-",
+			},
+			err: "only one of source tablet alias and source keyspace/shard may be specified",
+		},
+		{
+			name: "source shard without primary",
+			req: &vtctldatapb.CopySchemaShardRequest{
+				SourceKeyspace:      "testkeyspace",
+				SourceShard:         "-",
+				DestinationKeyspace: "otherkeyspace",
+				DestinationShard:    "-",
+			},
+			err: "no primary in shard record testkeyspace/-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vtctld.CopySchemaShard(ctx, tt.req)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
 func TestCreateKeyspace(t *testing.T) {
 	t.Parallel()
 
@@ -5313,6 +5628,35 @@ func TestGetBackups(t *testing.T) {
 	})
 }
 
+func TestGetBackupSchedule(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{Keyspace: "testkeyspace", Name: "-"})
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	resp, err := vtctld.GetBackupSchedule(ctx, &vtctldatapb.GetBackupScheduleRequest{Keyspace: "testkeyspace", Shard: "-"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.BackupSchedule)
+
+	schedule := &topodatapb.BackupSchedule{
+		CronSpec:          "@daily",
+		MinRetentionTime:  &vttime.Duration{Seconds: 3600},
+		MinRetentionCount: 2,
+	}
+	require.NoError(t, ts.SaveBackupSchedule(ctx, "testkeyspace", "-", schedule))
+	resp, err = vtctld.GetBackupSchedule(ctx, &vtctldatapb.GetBackupScheduleRequest{Keyspace: "testkeyspace", Shard: "-"})
+	require.NoError(t, err)
+	utils.MustMatch(t, schedule, resp.BackupSchedule)
+
+	_, err = vtctld.GetBackupSchedule(ctx, &vtctldatapb.GetBackupScheduleRequest{Keyspace: "testkeyspace", Shard: "-80"})
+	assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode for a missing shard, got %v", err)
+}
+
 func TestGetKeyspace(t *testing.T) {
 	t.Parallel()
 
@@ -7688,6 +8032,86 @@ func TestGetTablets(t *testing.T) {
 	}
 }
 
+func TestGetUnresolvedTransactions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Keyspace: "ks",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+		Keyspace: "ks",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 201},
+		Keyspace: "ks",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_REPLICA,
+	})
+
+	tests := []struct {
+		name    string
+		results map[string]struct {
+			Transactions []*querypb.TransactionMetadata
+			Error        error
+		}
+		expected  []*querypb.TransactionMetadata
+		shouldErr bool
+	}{
+		{
+			name: "success",
+			results: map[string]struct {
+				Transactions []*querypb.TransactionMetadata
+				Error        error
+			}{
+				"zone1-0000000100": {Transactions: []*querypb.TransactionMetadata{{Dtid: "ks:80-:2"}}},
+				"zone1-0000000200": {Transactions: []*querypb.TransactionMetadata{{Dtid: "ks:-80:1"}}},
+			},
+			expected: []*querypb.TransactionMetadata{{Dtid: "ks:-80:1"}, {Dtid: "ks:80-:2"}},
+		},
+		{
+			name: "tablet error",
+			results: map[string]struct {
+				Transactions []*querypb.TransactionMetadata
+				Error        error
+			}{
+				"zone1-0000000100": {Transactions: []*querypb.TransactionMetadata{{Dtid: "ks:80-:2"}}},
+				"zone1-0000000200": {Error: assert.AnError},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmc := &testutil.TabletManagerClient{
+				GetUnresolvedTransactionsResults: tt.results,
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.GetUnresolvedTransactions(ctx, &vtctldatapb.GetUnresolvedTransactionsRequest{
+				Keyspace:   "ks",
+				AbandonAge: 30,
+			})
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp.Transactions)
+		})
+	}
+}
+
 func TestGetTopologyPath(t *testing.T) {
 	t.Parallel()
 
@@ -7775,7 +8199,7 @@ func TestGetTopologyPath(t *testing.T) {
 	}
 }
 
-func TestGetVSchema(t *testing.T) {
+func TestGetTopoLocksAndForceUnlock(t *testing.T) {
 	t.Parallel()
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -7785,20 +8209,87 @@ func TestGetVSchema(t *testing.T) {
 		return NewVtctldServer(vtenv.NewTestEnv(), ts)
 	})
 
-	t.Run("found", func(t *testing.T) {
-		err := ts.SaveVSchema(ctx, "testkeyspace", &vschemapb.Keyspace{
-			Sharded: true,
-			Vindexes: map[string]*vschemapb.Vindex{
-				"v1": {
-					Type: "hash",
-				},
-			},
-		})
-		require.NoError(t, err)
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks1", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks1", "-80"))
+	require.NoError(t, ts.CreateShard(ctx, "ks1", "80-"))
+	require.NoError(t, ts.CreateKeyspace(ctx, "ks2", &topodatapb.Keyspace{}))
+	require.NoError(t, ts.CreateShard(ctx, "ks2", "0"))
 
-		expected := &vtctldatapb.GetVSchemaResponse{
-			VSchema: &vschemapb.Keyspace{
-				Sharded: true,
+	_, unlockKeyspace, err := ts.LockKeyspace(ctx, "ks1", "Reshard")
+	require.NoError(t, err)
+	defer unlockKeyspace(&err)
+	_, unlockShard, err := ts.LockShard(ctx, "ks2", "0", "PlannedReparentShard")
+	require.NoError(t, err)
+
+	resp, err := vtctld.GetTopoLocks(ctx, &vtctldatapb.GetTopoLocksRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Locks, 2)
+	assert.Equal(t, "ks1", resp.Locks[0].Keyspace)
+	assert.Empty(t, resp.Locks[0].Shard)
+	assert.Equal(t, "Reshard", resp.Locks[0].Action)
+	assert.Equal(t, "ks2", resp.Locks[1].Keyspace)
+	assert.Equal(t, "0", resp.Locks[1].Shard)
+	assert.Equal(t, "PlannedReparentShard", resp.Locks[1].Action)
+	assert.NotNil(t, resp.Locks[1].Age)
+
+	resp, err = vtctld.GetTopoLocks(ctx, &vtctldatapb.GetTopoLocksRequest{Keyspace: "ks2"})
+	require.NoError(t, err)
+	require.Len(t, resp.Locks, 1)
+	assert.Equal(t, "ks2", resp.Locks[0].Keyspace)
+
+	// A reason is required.
+	_, err = vtctld.ForceUnlock(ctx, &vtctldatapb.ForceUnlockRequest{Keyspace: "ks2", Shard: "0"})
+	assert.Error(t, err)
+
+	unlockResp, err := vtctld.ForceUnlock(ctx, &vtctldatapb.ForceUnlockRequest{
+		Keyspace: "ks2",
+		Shard:    "0",
+		Reason:   "the reparent is stuck",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "PlannedReparentShard", unlockResp.Lock.Action)
+
+	resp, err = vtctld.GetTopoLocks(ctx, &vtctldatapb.GetTopoLocksRequest{Keyspace: "ks2"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Locks)
+
+	// The shard is no longer locked.
+	_, err = vtctld.ForceUnlock(ctx, &vtctldatapb.ForceUnlockRequest{
+		Keyspace: "ks2",
+		Shard:    "0",
+		Reason:   "again",
+	})
+	assert.Error(t, err)
+
+	unlockShard(&err)
+	assert.Error(t, err, "the original holder can no longer release the lock")
+	err = nil
+}
+
+func TestGetVSchema(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	t.Run("found", func(t *testing.T) {
+		err := ts.SaveVSchema(ctx, "testkeyspace", &vschemapb.Keyspace{
+			Sharded: true,
+			Vindexes: map[string]*vschemapb.Vindex{
+				"v1": {
+					Type: "hash",
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		expected := &vtctldatapb.GetVSchemaResponse{
+			VSchema: &vschemapb.Keyspace{
+				Sharded: true,
 				Vindexes: map[string]*vschemapb.Vindex{
 					"v1": {
 						Type: "hash",
@@ -7824,6 +8315,139 @@ func TestGetVSchema(t *testing.T) {
 	})
 }
 
+func TestKillQueries(t *testing.T) {
+	t.Parallel()
+
+	killed := &tabletmanagerdatapb.KillQueriesResponse{
+		Queries: []*tabletmanagerdatapb.KillQueriesResponse_Query{
+			{ConnectionId: 7, Fingerprint: "select * from t where id = ?", PlanType: "Select", Caller: "user1"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		tablets   []*topodatapb.Tablet
+		tmc       testutil.TabletManagerClient
+		req       *vtctldatapb.KillQueriesRequest
+		expected  *vtctldatapb.KillQueriesResponse
+		shouldErr bool
+	}{
+		{
+			name: "ok",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				KillQueriesResults: map[string]struct {
+					Response *tabletmanagerdatapb.KillQueriesResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: killed,
+					},
+				},
+			},
+			req: &vtctldatapb.KillQueriesRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Query: "select * from t where id = 1",
+			},
+			expected: &vtctldatapb.KillQueriesResponse{
+				Queries: killed.Queries,
+			},
+		},
+		{
+			name: "no tablet",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  404,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				KillQueriesResults: map[string]struct {
+					Response *tabletmanagerdatapb.KillQueriesResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: killed,
+					},
+				},
+			},
+			req: &vtctldatapb.KillQueriesRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Caller: "user1",
+			},
+			shouldErr: true,
+		},
+		{
+			name: "tmc call failed",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+				},
+			},
+			tmc: testutil.TabletManagerClient{
+				KillQueriesResults: map[string]struct {
+					Response *tabletmanagerdatapb.KillQueriesResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Error: assert.AnError,
+					},
+				},
+			},
+			req: &vtctldatapb.KillQueriesRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				PlanType: "Select",
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, nil, tt.tablets...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.KillQueries(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestLaunchSchemaMigration(t *testing.T) {
 	t.Parallel()
 
@@ -8116,6 +8740,128 @@ func TestPingTablet(t *testing.T) {
 	}
 }
 
+func TestPlanReshard(t *testing.T) {
+	t.Parallel()
+
+	schema := func(sizes ...uint64) *tabletmanagerdatapb.SchemaDefinition {
+		sd := &tabletmanagerdatapb.SchemaDefinition{}
+		for i, size := range sizes {
+			sd.TableDefinitions = append(sd.TableDefinitions, &tabletmanagerdatapb.TableDefinition{
+				Name:       fmt.Sprintf("t%d", i),
+				DataLength: size,
+				RowCount:   size / 10,
+			})
+		}
+		return sd
+	}
+	tablets := []*topodatapb.Tablet{
+		{Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+		{Keyspace: "ks", Shard: "80-c0", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}},
+		{Keyspace: "ks", Shard: "c0-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 300}},
+	}
+
+	tests := []struct {
+		name        string
+		schemas     map[string]*tabletmanagerdatapb.SchemaDefinition
+		req         *vtctldatapb.PlanReshardRequest
+		expected    *vtctldatapb.PlanReshardResponse
+		expectedErr string
+	}{
+		{
+			name: "split and merge",
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000100": schema(1500, 500),
+				"zone1-0000000200": schema(100),
+				"zone1-0000000300": schema(200),
+			},
+			req: &vtctldatapb.PlanReshardRequest{Keyspace: "ks", MaxShardSizeBytes: 1000, MergeThreshold: 0.5},
+			expected: &vtctldatapb.PlanReshardResponse{
+				Shards: []*vtctldatapb.ShardLoad{
+					{Name: "-80", SizeBytes: 2000, RowCount: 200, Load: 2},
+					{Name: "80-c0", SizeBytes: 100, RowCount: 10, Load: 0.1},
+					{Name: "c0-", SizeBytes: 200, RowCount: 20, Load: 0.2},
+				},
+				Proposals: []*vtctldatapb.ReshardProposal{{
+					SourceShards: []string{"-80"},
+					TargetShards: []*vtctldatapb.ShardLoad{
+						{Name: "-40", SizeBytes: 1000, RowCount: 100, Load: 1},
+						{Name: "40-80", SizeBytes: 1000, RowCount: 100, Load: 1},
+					},
+					Reshard: &vtctldatapb.ReshardCreateRequest{
+						Workflow:     "split__80",
+						Keyspace:     "ks",
+						SourceShards: []string{"-80"},
+						TargetShards: []string{"-40", "40-80"},
+					},
+				}, {
+					SourceShards: []string{"80-c0", "c0-"},
+					TargetShards: []*vtctldatapb.ShardLoad{
+						{Name: "80-", SizeBytes: 300, RowCount: 30, Load: 0.3},
+					},
+					Reshard: &vtctldatapb.ReshardCreateRequest{
+						Workflow:     "merge_80_",
+						Keyspace:     "ks",
+						SourceShards: []string{"80-c0", "c0-"},
+						TargetShards: []string{"80-"},
+					},
+				}},
+			},
+		},
+		{
+			name:        "no limits",
+			req:         &vtctldatapb.PlanReshardRequest{Keyspace: "ks"},
+			expectedErr: "a maximum shard size or QPS is required",
+		},
+		{
+			name: "schema error",
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000100": schema(100),
+				"zone1-0000000200": schema(100),
+			},
+			req:         &vtctldatapb.PlanReshardRequest{Keyspace: "ks", MaxShardSizeBytes: 1000},
+			expectedErr: "cannot get the load of shard ks/c0-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tablets...)
+
+			results := make(map[string]struct {
+				Schema *tabletmanagerdatapb.SchemaDefinition
+				Error  error
+			}, len(tt.schemas))
+			for alias, sd := range tt.schemas {
+				results[alias] = struct {
+					Schema *tabletmanagerdatapb.SchemaDefinition
+					Error  error
+				}{Schema: sd}
+			}
+			tmc := &testutil.TabletManagerClient{
+				GetSchemaResults: results,
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.PlanReshard(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestPlannedReparentShard(t *testing.T) {
 	t.Parallel()
 
@@ -10265,54 +11011,207 @@ func TestReparentTablet(t *testing.T) {
 	}
 }
 
-func TestRestoreFromBackup(t *testing.T) {
+func TestRequeueDeadLetters(t *testing.T) {
+	t.Parallel()
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Keyspace: "ks",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+		Keyspace: "ks",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 201},
+		Keyspace: "ks",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_REPLICA,
+	})
+
 	tests := []struct {
-		name      string
-		ts        *topo.Server
-		tmc       tmclient.TabletManagerClient
-		tablets   []*topodatapb.Tablet
-		req       *vtctldatapb.RestoreFromBackupRequest
+		name    string
+		req     *vtctldatapb.RequeueDeadLettersRequest
+		results map[string]struct {
+			Count int64
+			Error error
+		}
+		expected  int64
 		shouldErr bool
-		assertion func(t *testing.T, responses []*vtctldatapb.RestoreFromBackupResponse, err error)
 	}{
 		{
-			name: "ok",
-			ts:   memorytopo.NewServer(ctx, "zone1"),
-			tmc: &testutil.TabletManagerClient{
-				RestoreFromBackupResults: map[string]struct {
-					Events        []*logutilpb.Event
-					EventInterval time.Duration
-					EventJitter   time.Duration
-					ErrorAfter    time.Duration
-				}{
-					"zone1-0000000100": {
-						Events: []*logutilpb.Event{{}, {}, {}},
-					},
-				},
-				SetReplicationSourceResults: map[string]error{
-					"zone1-0000000100": nil,
-				},
+			name: "success",
+			req:  &vtctldatapb.RequeueDeadLettersRequest{Keyspace: "ks", Table: "msg"},
+			results: map[string]struct {
+				Count int64
+				Error error
+			}{
+				"zone1-0000000100": {Count: 2},
+				"zone1-0000000200": {Count: 3},
 			},
-			tablets: []*topodatapb.Tablet{
-				{
-					Alias: &topodatapb.TabletAlias{
-						Cell: "zone1",
-						Uid:  100,
-					},
-					Keyspace: "ks",
-					Shard:    "-",
-					Type:     topodatapb.TabletType_REPLICA,
-				},
-				{
-					Alias: &topodatapb.TabletAlias{
-						Cell: "zone1",
-						Uid:  200,
-					},
-					Keyspace: "ks",
-					Shard:    "-",
+			expected: 5,
+		},
+		{
+			name:      "no table",
+			req:       &vtctldatapb.RequeueDeadLettersRequest{Keyspace: "ks"},
+			shouldErr: true,
+		},
+		{
+			name: "tablet error",
+			req:  &vtctldatapb.RequeueDeadLettersRequest{Keyspace: "ks", Table: "msg", Ids: []string{"1"}},
+			results: map[string]struct {
+				Count int64
+				Error error
+			}{
+				"zone1-0000000100": {Count: 1},
+				"zone1-0000000200": {Error: assert.AnError},
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmc := &testutil.TabletManagerClient{
+				RequeueDeadLettersResults: tt.results,
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.RequeueDeadLetters(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp.Count)
+		})
+	}
+}
+
+func TestResolveTransaction(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{AlsoSetShardPrimary: true}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+		Keyspace: "ks",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+	}, &topodatapb.Tablet{
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+		Keyspace: "ks",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_REPLICA,
+	})
+
+	tests := []struct {
+		name       string
+		dtid       string
+		resolveErr error
+		shouldErr  bool
+	}{
+		{
+			name: "success",
+			dtid: "ks:-80:1234",
+		},
+		{
+			name:      "invalid dtid",
+			dtid:      "1234",
+			shouldErr: true,
+		},
+		{
+			name:      "unknown shard",
+			dtid:      "ks:80-:1234",
+			shouldErr: true,
+		},
+		{
+			name:       "resolve failed",
+			dtid:       "ks:-80:1234",
+			resolveErr: assert.AnError,
+			shouldErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmc := &testutil.TabletManagerClient{
+				ResolveTransactionResults: map[string]error{
+					"zone1-0000000100": tt.resolveErr,
+				},
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			_, err := vtctld.ResolveTransaction(ctx, &vtctldatapb.ResolveTransactionRequest{Dtid: tt.dtid})
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRestoreFromBackup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tests := []struct {
+		name      string
+		ts        *topo.Server
+		tmc       tmclient.TabletManagerClient
+		tablets   []*topodatapb.Tablet
+		req       *vtctldatapb.RestoreFromBackupRequest
+		shouldErr bool
+		assertion func(t *testing.T, responses []*vtctldatapb.RestoreFromBackupResponse, err error)
+	}{
+		{
+			name: "ok",
+			ts:   memorytopo.NewServer(ctx, "zone1"),
+			tmc: &testutil.TabletManagerClient{
+				RestoreFromBackupResults: map[string]struct {
+					Events        []*logutilpb.Event
+					EventInterval time.Duration
+					EventJitter   time.Duration
+					ErrorAfter    time.Duration
+				}{
+					"zone1-0000000100": {
+						Events: []*logutilpb.Event{{}, {}, {}},
+					},
+				},
+				SetReplicationSourceResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+			},
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
+					Keyspace: "ks",
+					Shard:    "-",
+					Type:     topodatapb.TabletType_REPLICA,
+				},
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  200,
+					},
+					Keyspace: "ks",
+					Shard:    "-",
 					Type:     topodatapb.TabletType_PRIMARY,
 				},
 			},
@@ -10607,14 +11506,21 @@ func TestRetrySchemaMigration(t *testing.T) {
 	}
 }
 
-func TestRunHealthCheck(t *testing.T) {
+func TestRunDiagnosticQuery(t *testing.T) {
 	t.Parallel()
 
+	processlist := &tabletmanagerdatapb.RunDiagnosticQueryResponse{
+		Processlist: []*tabletmanagerdatapb.RunDiagnosticQueryResponse_Process{
+			{Id: 5, User: "vt_app", Command: "Query", Time: 2},
+		},
+	}
+
 	tests := []struct {
 		name      string
 		tablets   []*topodatapb.Tablet
 		tmc       testutil.TabletManagerClient
-		req       *vtctldatapb.RunHealthCheckRequest
+		req       *vtctldatapb.RunDiagnosticQueryRequest
+		expected  *vtctldatapb.RunDiagnosticQueryResponse
 		shouldErr bool
 	}{
 		{
@@ -10628,15 +11534,24 @@ func TestRunHealthCheck(t *testing.T) {
 				},
 			},
 			tmc: testutil.TabletManagerClient{
-				RunHealthCheckResults: map[string]error{
-					"zone1-0000000100": nil,
+				RunDiagnosticQueryResults: map[string]struct {
+					Response *tabletmanagerdatapb.RunDiagnosticQueryResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: processlist,
+					},
 				},
 			},
-			req: &vtctldatapb.RunHealthCheckRequest{
+			req: &vtctldatapb.RunDiagnosticQueryRequest{
 				TabletAlias: &topodatapb.TabletAlias{
 					Cell: "zone1",
 					Uid:  100,
 				},
+				Query: tabletmanagerdatapb.RunDiagnosticQueryRequest_PROCESSLIST,
+			},
+			expected: &vtctldatapb.RunDiagnosticQueryResponse{
+				Result: processlist,
 			},
 		},
 		{
@@ -10650,15 +11565,21 @@ func TestRunHealthCheck(t *testing.T) {
 				},
 			},
 			tmc: testutil.TabletManagerClient{
-				RunHealthCheckResults: map[string]error{
-					"zone1-0000000100": nil,
+				RunDiagnosticQueryResults: map[string]struct {
+					Response *tabletmanagerdatapb.RunDiagnosticQueryResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: processlist,
+					},
 				},
 			},
-			req: &vtctldatapb.RunHealthCheckRequest{
+			req: &vtctldatapb.RunDiagnosticQueryRequest{
 				TabletAlias: &topodatapb.TabletAlias{
 					Cell: "zone1",
 					Uid:  100,
 				},
+				Query: tabletmanagerdatapb.RunDiagnosticQueryRequest_PROCESSLIST,
 			},
 			shouldErr: true,
 		},
@@ -10673,15 +11594,21 @@ func TestRunHealthCheck(t *testing.T) {
 				},
 			},
 			tmc: testutil.TabletManagerClient{
-				RunHealthCheckResults: map[string]error{
-					"zone1-0000000100": assert.AnError,
+				RunDiagnosticQueryResults: map[string]struct {
+					Response *tabletmanagerdatapb.RunDiagnosticQueryResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Error: assert.AnError,
+					},
 				},
 			},
-			req: &vtctldatapb.RunHealthCheckRequest{
+			req: &vtctldatapb.RunDiagnosticQueryRequest{
 				TabletAlias: &topodatapb.TabletAlias{
 					Cell: "zone1",
 					Uid:  100,
 				},
+				Query: tabletmanagerdatapb.RunDiagnosticQueryRequest_INNODB_STATUS,
 			},
 			shouldErr: true,
 		},
@@ -10700,69 +11627,95 @@ func TestRunHealthCheck(t *testing.T) {
 			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
 				return NewVtctldServer(vtenv.NewTestEnv(), ts)
 			})
-			_, err := vtctld.RunHealthCheck(ctx, tt.req)
+			resp, err := vtctld.RunDiagnosticQuery(ctx, tt.req)
 			if tt.shouldErr {
 				assert.Error(t, err)
 				return
 			}
 
 			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
 		})
 	}
 }
 
-func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
+func TestRunHealthCheck(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name        string
-		keyspaces   []*vtctldatapb.Keyspace
-		req         *vtctldatapb.SetKeyspaceDurabilityPolicyRequest
-		expected    *vtctldatapb.SetKeyspaceDurabilityPolicyResponse
-		expectedErr string
+		name      string
+		tablets   []*topodatapb.Tablet
+		tmc       testutil.TabletManagerClient
+		req       *vtctldatapb.RunHealthCheckRequest
+		shouldErr bool
 	}{
 		{
 			name: "ok",
-			keyspaces: []*vtctldatapb.Keyspace{
-				{
-					Name:     "ks1",
-					Keyspace: &topodatapb.Keyspace{},
-				},
+			tablets: []*topodatapb.Tablet{
 				{
-					Name:     "ks2",
-					Keyspace: &topodatapb.Keyspace{},
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
 				},
 			},
-			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
-				Keyspace:         "ks1",
-				DurabilityPolicy: "none",
+			tmc: testutil.TabletManagerClient{
+				RunHealthCheckResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
 			},
-			expected: &vtctldatapb.SetKeyspaceDurabilityPolicyResponse{
-				Keyspace: &topodatapb.Keyspace{
-					DurabilityPolicy: "none",
+			req: &vtctldatapb.RunHealthCheckRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
 				},
 			},
 		},
 		{
-			name: "keyspace not found",
-			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
-				Keyspace: "ks1",
+			name: "no tablet",
+			tablets: []*topodatapb.Tablet{
+				{
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  404,
+					},
+				},
 			},
-			expectedErr: "node doesn't exist: keyspaces/ks1",
+			tmc: testutil.TabletManagerClient{
+				RunHealthCheckResults: map[string]error{
+					"zone1-0000000100": nil,
+				},
+			},
+			req: &vtctldatapb.RunHealthCheckRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			shouldErr: true,
 		},
 		{
-			name: "fail to update durability policy",
-			keyspaces: []*vtctldatapb.Keyspace{
+			name: "tmc call failed",
+			tablets: []*topodatapb.Tablet{
 				{
-					Name:     "ks1",
-					Keyspace: &topodatapb.Keyspace{},
+					Alias: &topodatapb.TabletAlias{
+						Cell: "zone1",
+						Uid:  100,
+					},
 				},
 			},
-			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
-				Keyspace:         "ks1",
-				DurabilityPolicy: "non-existent",
+			tmc: testutil.TabletManagerClient{
+				RunHealthCheckResults: map[string]error{
+					"zone1-0000000100": assert.AnError,
+				},
 			},
-			expectedErr: "durability policy <non-existent> is not a valid policy. Please register it as a policy first",
+			req: &vtctldatapb.RunHealthCheckRequest{
+				TabletAlias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+			},
+			shouldErr: true,
 		},
 	}
 
@@ -10774,32 +11727,435 @@ func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
 			defer cancel()
 
 			ts := memorytopo.NewServer(ctx, "zone1")
-			testutil.AddKeyspaces(ctx, t, ts, tt.keyspaces...)
+			testutil.AddTablets(ctx, t, ts, nil, tt.tablets...)
 
-			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
 				return NewVtctldServer(vtenv.NewTestEnv(), ts)
 			})
-			resp, err := vtctld.SetKeyspaceDurabilityPolicy(ctx, tt.req)
-			if tt.expectedErr != "" {
-				assert.EqualError(t, err, tt.expectedErr)
+			_, err := vtctld.RunHealthCheck(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
 				return
 			}
 
 			require.NoError(t, err)
-			utils.MustMatch(t, tt.expected, resp)
 		})
 	}
 }
 
-func TestSetShardIsPrimaryServing(t *testing.T) {
+func TestSchemaDiff(t *testing.T) {
 	t.Parallel()
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	type testcase struct {
-		name      string
-		ctx       context.Context
+	schema := func(queries ...string) *tabletmanagerdatapb.SchemaDefinition {
+		sd := &tabletmanagerdatapb.SchemaDefinition{}
+		for _, q := range queries {
+			sd.TableDefinitions = append(sd.TableDefinitions, &tabletmanagerdatapb.TableDefinition{Schema: q})
+		}
+		return sd
+	}
+	schemaResults := func(schemas map[string]*tabletmanagerdatapb.SchemaDefinition) map[string]struct {
+		Schema *tabletmanagerdatapb.SchemaDefinition
+		Error  error
+	} {
+		results := make(map[string]struct {
+			Schema *tabletmanagerdatapb.SchemaDefinition
+			Error  error
+		}, len(schemas))
+		for alias, sd := range schemas {
+			results[alias] = struct {
+				Schema *tabletmanagerdatapb.SchemaDefinition
+				Error  error
+			}{Schema: sd}
+		}
+		return results
+	}
+
+	t1 := "create table t1 (id int primary key, a int)"
+	t1Modified := "create table t1 (id int primary key, a bigint, key a_idx (a))"
+	t2 := "create table t2 (id int primary key)"
+
+	tests := []struct {
+		name      string
+		tablets   []*topodatapb.Tablet
+		schemas   map[string]*tabletmanagerdatapb.SchemaDefinition
+		req       *vtctldatapb.SchemaDiffRequest
+		expected  *vtctldatapb.SchemaDiffResponse
+		shouldErr bool
+	}{
+		{
+			name: "identical shards",
+			tablets: []*topodatapb.Tablet{
+				{Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+				{Keyspace: "ks", Shard: "80-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}},
+			},
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000100": schema(t1, t2),
+				"zone1-0000000200": schema(t1, t2),
+			},
+			req: &vtctldatapb.SchemaDiffRequest{Keyspace: "ks"},
+			expected: &vtctldatapb.SchemaDiffResponse{
+				Reference: "-80",
+			},
+		},
+		{
+			name: "different shards",
+			tablets: []*topodatapb.Tablet{
+				{Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+				{Keyspace: "ks", Shard: "80-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}},
+			},
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000100": schema(t1, t2),
+				"zone1-0000000200": schema(t1Modified),
+			},
+			req: &vtctldatapb.SchemaDiffRequest{Keyspace: "ks"},
+			expected: &vtctldatapb.SchemaDiffResponse{
+				Reference: "-80",
+				Differences: []*vtctldatapb.SchemaDifference{
+					{
+						Shard:     "80-",
+						Name:      "t1",
+						Type:      vtctldatapb.SchemaDifference_COLUMNS,
+						Details:   []string{"a"},
+						Statement: "ALTER TABLE `t1` DROP KEY `a_idx`, MODIFY COLUMN `a` int",
+					},
+					{
+						Shard:     "80-",
+						Name:      "t1",
+						Type:      vtctldatapb.SchemaDifference_INDEXES,
+						Details:   []string{"a_idx"},
+						Statement: "ALTER TABLE `t1` DROP KEY `a_idx`, MODIFY COLUMN `a` int",
+					},
+					{
+						Shard:     "80-",
+						Name:      "t2",
+						Type:      vtctldatapb.SchemaDifference_MISSING_TABLE,
+						Statement: "CREATE TABLE `t2` (\n\t`id` int,\n\tPRIMARY KEY (`id`)\n)",
+					},
+				},
+			},
+		},
+		{
+			name: "desired schema",
+			tablets: []*topodatapb.Tablet{
+				{Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+				{Keyspace: "ks", Shard: "80-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}},
+			},
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000100": schema(t1Modified),
+				"zone1-0000000200": schema(t1),
+			},
+			req: &vtctldatapb.SchemaDiffRequest{
+				Keyspace:      "ks",
+				DesiredSchema: t1 + ";\n" + t2 + ";\n",
+				ExcludeTables: []string{"t2"},
+			},
+			expected: &vtctldatapb.SchemaDiffResponse{
+				Reference: "desired schema",
+				Differences: []*vtctldatapb.SchemaDifference{
+					{
+						Shard:     "-80",
+						Name:      "t1",
+						Type:      vtctldatapb.SchemaDifference_COLUMNS,
+						Details:   []string{"a"},
+						Statement: "ALTER TABLE `t1` DROP KEY `a_idx`, MODIFY COLUMN `a` int",
+					},
+					{
+						Shard:     "-80",
+						Name:      "t1",
+						Type:      vtctldatapb.SchemaDifference_INDEXES,
+						Details:   []string{"a_idx"},
+						Statement: "ALTER TABLE `t1` DROP KEY `a_idx`, MODIFY COLUMN `a` int",
+					},
+				},
+			},
+		},
+		{
+			name: "invalid desired schema",
+			tablets: []*topodatapb.Tablet{
+				{Keyspace: "ks", Shard: "-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+			},
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000100": schema(t1),
+			},
+			req: &vtctldatapb.SchemaDiffRequest{
+				Keyspace:      "ks",
+				DesiredSchema: "create tabel t1 (id int primary key)",
+			},
+			shouldErr: true,
+		},
+		{
+			name: "no primary",
+			tablets: []*topodatapb.Tablet{
+				{Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_REPLICA, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+				{Keyspace: "ks", Shard: "80-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}},
+				{Keyspace: "ks", Shard: "c0-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 300}},
+			},
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000200": schema(t1),
+				"zone1-0000000300": schema(t1),
+			},
+			req:       &vtctldatapb.SchemaDiffRequest{Keyspace: "ks"},
+			shouldErr: true,
+		},
+		{
+			name: "skip no primary",
+			tablets: []*topodatapb.Tablet{
+				{Keyspace: "ks", Shard: "-80", Type: topodatapb.TabletType_REPLICA, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+				{Keyspace: "ks", Shard: "80-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 200}},
+				{Keyspace: "ks", Shard: "c0-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 300}},
+			},
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000200": schema(t1),
+				"zone1-0000000300": schema(t1, t2),
+			},
+			req: &vtctldatapb.SchemaDiffRequest{
+				Keyspace:      "ks",
+				SkipNoPrimary: true,
+			},
+			expected: &vtctldatapb.SchemaDiffResponse{
+				Reference: "80-",
+				Differences: []*vtctldatapb.SchemaDifference{
+					{
+						Shard:     "c0-",
+						Name:      "t2",
+						Type:      vtctldatapb.SchemaDifference_EXTRA_TABLE,
+						Statement: "DROP TABLE `t2`",
+					},
+				},
+			},
+		},
+		{
+			name: "tmc call failed",
+			tablets: []*topodatapb.Tablet{
+				{Keyspace: "ks", Shard: "-", Type: topodatapb.TabletType_PRIMARY, Alias: &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}},
+			},
+			req:       &vtctldatapb.SchemaDiffRequest{Keyspace: "ks"},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tt.tablets...)
+
+			tmc := &testutil.TabletManagerClient{
+				GetSchemaResults: schemaResults(tt.schemas),
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.SchemaDiff(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestSetBackupSchedule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		shards      []*vtctldatapb.Shard
+		req         *vtctldatapb.SetBackupScheduleRequest
+		expected    *topodatapb.BackupSchedule
+		expectedErr string
+	}{
+		{
+			name:   "ok",
+			shards: []*vtctldatapb.Shard{{Keyspace: "ks1", Name: "-"}},
+			req: &vtctldatapb.SetBackupScheduleRequest{
+				Keyspace: "ks1",
+				Shard:    "-",
+				BackupSchedule: &topodatapb.BackupSchedule{
+					CronSpec:          "0 3 * * *",
+					MinRetentionTime:  &vttime.Duration{Seconds: 7 * 24 * 3600},
+					MinRetentionCount: 3,
+				},
+			},
+			expected: &topodatapb.BackupSchedule{
+				CronSpec:          "0 3 * * *",
+				MinRetentionTime:  &vttime.Duration{Seconds: 7 * 24 * 3600},
+				MinRetentionCount: 3,
+			},
+		},
+		{
+			name:   "clear",
+			shards: []*vtctldatapb.Shard{{Keyspace: "ks1", Name: "-"}},
+			req: &vtctldatapb.SetBackupScheduleRequest{
+				Keyspace: "ks1",
+				Shard:    "-",
+			},
+		},
+		{
+			name:   "invalid cron spec",
+			shards: []*vtctldatapb.Shard{{Keyspace: "ks1", Name: "-"}},
+			req: &vtctldatapb.SetBackupScheduleRequest{
+				Keyspace:       "ks1",
+				Shard:          "-",
+				BackupSchedule: &topodatapb.BackupSchedule{CronSpec: "0 25 * * *"},
+			},
+			expectedErr: `invalid backup schedule: invalid cron spec "0 25 * * *": invalid value "25" in hour field, expected 0-23`,
+		},
+		{
+			name:   "negative min retention count",
+			shards: []*vtctldatapb.Shard{{Keyspace: "ks1", Name: "-"}},
+			req: &vtctldatapb.SetBackupScheduleRequest{
+				Keyspace: "ks1",
+				Shard:    "-",
+				BackupSchedule: &topodatapb.BackupSchedule{
+					CronSpec:          "@daily",
+					MinRetentionCount: -1,
+				},
+			},
+			expectedErr: "invalid backup schedule min_retention_count -1",
+		},
+		{
+			name: "shard not found",
+			req: &vtctldatapb.SetBackupScheduleRequest{
+				Keyspace:       "ks1",
+				Shard:          "-",
+				BackupSchedule: &topodatapb.BackupSchedule{CronSpec: "@daily"},
+			},
+			expectedErr: "node doesn't exist: keyspaces/ks1/shards/-/Shard",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddShards(ctx, t, ts, tt.shards...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.SetBackupSchedule(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp.BackupSchedule)
+
+			schedule, err := ts.GetBackupSchedule(ctx, tt.req.Keyspace, tt.req.Shard)
+			if tt.expected == nil {
+				assert.True(t, topo.IsErrType(err, topo.NoNode), "expected NoNode, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, schedule)
+		})
+	}
+}
+
+func TestSetKeyspaceDurabilityPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		keyspaces   []*vtctldatapb.Keyspace
+		req         *vtctldatapb.SetKeyspaceDurabilityPolicyRequest
+		expected    *vtctldatapb.SetKeyspaceDurabilityPolicyResponse
+		expectedErr string
+	}{
+		{
+			name: "ok",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+				{
+					Name:     "ks2",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+				Keyspace:         "ks1",
+				DurabilityPolicy: "none",
+			},
+			expected: &vtctldatapb.SetKeyspaceDurabilityPolicyResponse{
+				Keyspace: &topodatapb.Keyspace{
+					DurabilityPolicy: "none",
+				},
+			},
+		},
+		{
+			name: "keyspace not found",
+			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+				Keyspace: "ks1",
+			},
+			expectedErr: "node doesn't exist: keyspaces/ks1",
+		},
+		{
+			name: "fail to update durability policy",
+			keyspaces: []*vtctldatapb.Keyspace{
+				{
+					Name:     "ks1",
+					Keyspace: &topodatapb.Keyspace{},
+				},
+			},
+			req: &vtctldatapb.SetKeyspaceDurabilityPolicyRequest{
+				Keyspace:         "ks1",
+				DurabilityPolicy: "non-existent",
+			},
+			expectedErr: "durability policy <non-existent> is not a valid policy. Please register it as a policy first",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddKeyspaces(ctx, t, ts, tt.keyspaces...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.SetKeyspaceDurabilityPolicy(ctx, tt.req)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestSetShardIsPrimaryServing(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type testcase struct {
+		name      string
+		ctx       context.Context
 		ts        *topo.Server
 		setup     func(*testing.T, *testcase) context.Context
 		teardown  func(*testing.T, *testcase)
@@ -12724,6 +14080,201 @@ func TestTabletExternallyReparented(t *testing.T) {
 	}
 }
 
+func TestTabletExternallyReparentedV2(t *testing.T) {
+	t.Parallel()
+
+	oldPrimaryAlias := &topodatapb.TabletAlias{Cell: "zone1", Uid: 100}
+	newPrimaryAlias := &topodatapb.TabletAlias{Cell: "zone2", Uid: 200}
+	tablets := []*topodatapb.Tablet{
+		{
+			Alias:                oldPrimaryAlias,
+			Type:                 topodatapb.TabletType_PRIMARY,
+			Keyspace:             "testkeyspace",
+			Shard:                "-",
+			PrimaryTermStartTime: &vttime.Time{Seconds: 1000},
+		},
+		{
+			Alias:    newPrimaryAlias,
+			Type:     topodatapb.TabletType_REPLICA,
+			Keyspace: "testkeyspace",
+			Shard:    "-",
+		},
+	}
+	fenced := &replicationdatapb.FullStatus{
+		ReadOnly:      true,
+		PrimaryStatus: &replicationdatapb.PrimaryStatus{Position: "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-10"},
+	}
+
+	tests := []struct {
+		name               string
+		fullStatus         *replicationdatapb.FullStatus
+		fullStatusErr      error
+		req                *vtctldatapb.TabletExternallyReparentedV2Request
+		expected           *vtctldatapb.TabletExternallyReparentedV2Response
+		expectedErr        string
+		expectedOldPrimary topodatapb.TabletType
+	}{
+		{
+			name:       "old primary fenced",
+			fullStatus: fenced,
+			req: &vtctldatapb.TabletExternallyReparentedV2Request{
+				Tablet:        newPrimaryAlias,
+				TermStartTime: &vttime.Time{Seconds: 2000},
+			},
+			expected: &vtctldatapb.TabletExternallyReparentedV2Response{
+				Keyspace:         "testkeyspace",
+				Shard:            "-",
+				NewPrimary:       newPrimaryAlias,
+				OldPrimary:       oldPrimaryAlias,
+				OldPrimaryFenced: true,
+			},
+			expectedOldPrimary: topodatapb.TabletType_REPLICA,
+		},
+		{
+			name: "old primary not read-only",
+			fullStatus: &replicationdatapb.FullStatus{
+				PrimaryStatus: fenced.PrimaryStatus,
+			},
+			req: &vtctldatapb.TabletExternallyReparentedV2Request{
+				Tablet:        newPrimaryAlias,
+				TermStartTime: &vttime.Time{Seconds: 2000},
+			},
+			expectedErr:        "is not read-only",
+			expectedOldPrimary: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			name: "old primary wrote after the term start",
+			fullStatus: &replicationdatapb.FullStatus{
+				ReadOnly:      true,
+				PrimaryStatus: &replicationdatapb.PrimaryStatus{Position: "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-15"},
+			},
+			req: &vtctldatapb.TabletExternallyReparentedV2Request{
+				Tablet:        newPrimaryAlias,
+				TermStartTime: &vttime.Time{Seconds: 2000},
+			},
+			expectedErr:        "has transactions that new primary zone2-0000000200",
+			expectedOldPrimary: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			name:          "old primary unreachable",
+			fullStatusErr: assert.AnError,
+			req: &vtctldatapb.TabletExternallyReparentedV2Request{
+				Tablet:        newPrimaryAlias,
+				TermStartTime: &vttime.Time{Seconds: 2000},
+			},
+			expectedErr:        "cannot reach old primary zone1-0000000100",
+			expectedOldPrimary: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			name:          "old primary unreachable, allowed",
+			fullStatusErr: assert.AnError,
+			req: &vtctldatapb.TabletExternallyReparentedV2Request{
+				Tablet:                     newPrimaryAlias,
+				TermStartTime:              &vttime.Time{Seconds: 2000},
+				AllowUnreachableOldPrimary: true,
+			},
+			expected: &vtctldatapb.TabletExternallyReparentedV2Response{
+				Keyspace:   "testkeyspace",
+				Shard:      "-",
+				NewPrimary: newPrimaryAlias,
+				OldPrimary: oldPrimaryAlias,
+			},
+			expectedOldPrimary: topodatapb.TabletType_REPLICA,
+		},
+		{
+			name:       "stale term",
+			fullStatus: fenced,
+			req: &vtctldatapb.TabletExternallyReparentedV2Request{
+				Tablet:        newPrimaryAlias,
+				TermStartTime: &vttime.Time{Seconds: 500},
+			},
+			expectedErr:        "after the term start",
+			expectedOldPrimary: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			name: "already the primary",
+			req: &vtctldatapb.TabletExternallyReparentedV2Request{
+				Tablet:        oldPrimaryAlias,
+				TermStartTime: &vttime.Time{Seconds: 2000},
+			},
+			expected: &vtctldatapb.TabletExternallyReparentedV2Response{
+				Keyspace:   "testkeyspace",
+				Shard:      "-",
+				NewPrimary: oldPrimaryAlias,
+				OldPrimary: oldPrimaryAlias,
+			},
+			expectedOldPrimary: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			name: "term start is nil",
+			req: &vtctldatapb.TabletExternallyReparentedV2Request{
+				Tablet: newPrimaryAlias,
+			},
+			expectedErr:        "TermStartTime must not be nil",
+			expectedOldPrimary: topodatapb.TabletType_PRIMARY,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+			tmc := testutil.TabletManagerClient{
+				TopoServer: ts,
+				FullStatusResults: map[string]struct {
+					Status *replicationdatapb.FullStatus
+					Error  error
+				}{
+					"zone1-0000000100": {
+						Status: tt.fullStatus,
+						Error:  tt.fullStatusErr,
+					},
+				},
+				PrimaryPositionResults: map[string]struct {
+					Position string
+					Error    error
+				}{
+					"zone2-0000000200": {
+						Position: "MySQL56/3e11fa47-71ca-11e1-9e33-c80aa9429562:1-12",
+					},
+				},
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tablets...)
+
+			resp, err := vtctld.TabletExternallyReparentedV2(ctx, tt.req)
+
+			oldPrimary, terr := ts.GetTablet(ctx, oldPrimaryAlias)
+			require.NoError(t, terr)
+			assert.Equal(t, tt.expectedOldPrimary, oldPrimary.Type)
+			shard, serr := ts.GetShard(ctx, "testkeyspace", "-")
+			require.NoError(t, serr)
+
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				// The shard record is left alone.
+				utils.MustMatch(t, oldPrimaryAlias, shard.PrimaryAlias)
+				utils.MustMatch(t, &vttime.Time{Seconds: 1000}, shard.PrimaryTermStartTime)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+			utils.MustMatch(t, tt.req.Tablet, shard.PrimaryAlias)
+			if !topoproto.TabletAliasEqual(tt.req.Tablet, oldPrimaryAlias) {
+				utils.MustMatch(t, tt.req.TermStartTime, shard.PrimaryTermStartTime)
+			}
+		})
+	}
+}
+
 func TestUpdateCellInfo(t *testing.T) {
 	t.Parallel()
 
@@ -13005,23 +14556,590 @@ func TestUpdateCellsAlias(t *testing.T) {
 					require.NoError(t, err, "failed to create cell %v", cell)
 				}
 
-				err := ts.CreateCellsAlias(ctx, name, &topodatapb.CellsAlias{
-					Cells: cells,
-				})
-				require.NoError(t, err, "failed to create cell alias %v (cells = %v)", name, cells)
-			}
+				err := ts.CreateCellsAlias(ctx, name, &topodatapb.CellsAlias{
+					Cells: cells,
+				})
+				require.NoError(t, err, "failed to create cell alias %v (cells = %v)", name, cells)
+			}
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.UpdateCellsAlias(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+// upgradeTabletManagerClient records the reparent of a PlannedReparentShard in
+// the topo, like the tablets do, so that the old primary is a replica when it
+// is upgraded.
+type upgradeTabletManagerClient struct {
+	*testutil.TabletManagerClient
+}
+
+func (fake *upgradeTabletManagerClient) PromoteReplica(ctx context.Context, tablet *topodatapb.Tablet, semiSync bool) (string, error) {
+	pos, err := fake.TabletManagerClient.PromoteReplica(ctx, tablet, semiSync)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := fake.TopoServer.UpdateShardFields(ctx, tablet.Keyspace, tablet.Shard, func(si *topo.ShardInfo) error {
+		si.PrimaryAlias = tablet.Alias
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	return pos, fake.ChangeType(ctx, tablet, topodatapb.TabletType_PRIMARY, semiSync)
+}
+
+func (fake *upgradeTabletManagerClient) SetReplicationSource(ctx context.Context, tablet *topodatapb.Tablet, parent *topodatapb.TabletAlias, timeCreatedNS int64, waitPosition string, forceStartReplication bool, semiSync bool) error {
+	if err := fake.TabletManagerClient.SetReplicationSource(ctx, tablet, parent, timeCreatedNS, waitPosition, forceStartReplication, semiSync); err != nil {
+		return err
+	}
+
+	if tablet.Type != topodatapb.TabletType_PRIMARY {
+		return nil
+	}
+
+	return fake.ChangeType(ctx, tablet, topodatapb.TabletType_REPLICA, semiSync)
+}
+
+func TestUpdateQueryFingerprintRules(t *testing.T) {
+	t.Parallel()
+
+	blocked := &tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse{
+		Rules: []*tabletmanagerdatapb.QueryFingerprintRule{
+			{Fingerprint: "select * from t where id = ?", Action: tabletmanagerdatapb.QueryFingerprintRule_BLOCK, ErrorMessage: "too expensive"},
+		},
+	}
+	tablets := []*topodatapb.Tablet{
+		{
+			Keyspace: "ks",
+			Shard:    "-",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  100,
+			},
+			Type: topodatapb.TabletType_PRIMARY,
+		},
+		{
+			Keyspace: "ks",
+			Shard:    "-",
+			Alias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  101,
+			},
+			Type: topodatapb.TabletType_REPLICA,
+		},
+	}
+	req := &vtctldatapb.UpdateQueryFingerprintRulesRequest{
+		Keyspace: "ks",
+		Shard:    "-",
+		SetRules: []*tabletmanagerdatapb.QueryFingerprintRule{
+			{Fingerprint: "select * from t where id = 1", Action: tabletmanagerdatapb.QueryFingerprintRule_BLOCK, ErrorMessage: "too expensive"},
+		},
+	}
+	primaryPosition := map[string]struct {
+		Position string
+		Error    error
+	}{
+		"zone1-0000000100": {
+			Position: "primary-pos",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		tablets   []*topodatapb.Tablet
+		tmc       testutil.TabletManagerClient
+		req       *vtctldatapb.UpdateQueryFingerprintRulesRequest
+		expected  *vtctldatapb.UpdateQueryFingerprintRulesResponse
+		shouldErr bool
+	}{
+		{
+			name:    "ok",
+			tablets: tablets,
+			tmc: testutil.TabletManagerClient{
+				PrimaryPositionResults: primaryPosition,
+				WaitForPositionResults: map[string]map[string]error{
+					"zone1-0000000101": {
+						"primary-pos": nil,
+					},
+				},
+				UpdateQueryFingerprintRulesResults: map[string]struct {
+					Response *tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: blocked,
+					},
+					"zone1-0000000101": {
+						Response: blocked,
+					},
+				},
+			},
+			req: req,
+			expected: &vtctldatapb.UpdateQueryFingerprintRulesResponse{
+				Rules: blocked.Rules,
+			},
+		},
+		{
+			name:    "primary update failed",
+			tablets: tablets,
+			tmc: testutil.TabletManagerClient{
+				PrimaryPositionResults: primaryPosition,
+				UpdateQueryFingerprintRulesResults: map[string]struct {
+					Response *tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Error: assert.AnError,
+					},
+				},
+			},
+			req:       req,
+			shouldErr: true,
+		},
+		{
+			name:    "replica reload failed",
+			tablets: tablets,
+			tmc: testutil.TabletManagerClient{
+				PrimaryPositionResults: primaryPosition,
+				WaitForPositionResults: map[string]map[string]error{
+					"zone1-0000000101": {
+						"primary-pos": nil,
+					},
+				},
+				UpdateQueryFingerprintRulesResults: map[string]struct {
+					Response *tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse
+					Error    error
+				}{
+					"zone1-0000000100": {
+						Response: blocked,
+					},
+					"zone1-0000000101": {
+						Error: assert.AnError,
+					},
+				},
+			},
+			req:       req,
+			shouldErr: true,
+		},
+		{
+			name:    "no primary",
+			tablets: tablets[1:],
+			tmc: testutil.TabletManagerClient{
+				UpdateQueryFingerprintRulesResults: map[string]struct {
+					Response *tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse
+					Error    error
+				}{
+					"zone1-0000000101": {
+						Response: blocked,
+					},
+				},
+			},
+			req:       req,
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary: true,
+			}, tt.tablets...)
+
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &tt.tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+			resp, err := vtctld.UpdateQueryFingerprintRules(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
+func TestUpdateSrvKeyspacePartitions(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1", "zone2")
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+		Keyspace: "ks",
+		Name:     "-80",
+	}, &vtctldatapb.Shard{
+		Keyspace: "ks",
+		Name:     "80-",
+	})
+
+	testutil.UpdateSrvKeyspaces(ctx, t, ts, map[string]map[string]*topodatapb.SrvKeyspace{
+		"zone1": {"ks": {}},
+		"zone2": {"ks": {}},
+	})
+
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	replicaShards := func(cell string) []string {
+		srvKeyspace, err := ts.GetSrvKeyspace(ctx, cell, "ks")
+		require.NoError(t, err)
+
+		var shards []string
+		for _, partition := range srvKeyspace.Partitions {
+			if partition.ServedType != topodatapb.TabletType_REPLICA {
+				continue
+			}
+			for _, ref := range partition.ShardReferences {
+				shards = append(shards, ref.Name)
+			}
+		}
+		return shards
+	}
+
+	_, err := vtctld.UpdateSrvKeyspacePartitions(ctx, &vtctldatapb.UpdateSrvKeyspacePartitionsRequest{
+		Keyspace:   "ks",
+		Shard:      "-80",
+		TabletType: topodatapb.TabletType_REPLICA,
+		Cells:      []string{"zone1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-80"}, replicaShards("zone1"))
+	assert.Empty(t, replicaShards("zone2"), "only the requested cells should be updated")
+
+	_, err = vtctld.UpdateSrvKeyspacePartitions(ctx, &vtctldatapb.UpdateSrvKeyspacePartitionsRequest{
+		Keyspace:   "ks",
+		Shard:      "80-",
+		TabletType: topodatapb.TabletType_REPLICA,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-80", "80-"}, replicaShards("zone1"))
+	assert.Equal(t, []string{"80-"}, replicaShards("zone2"), "all cells should be updated when none are given")
+
+	_, err = vtctld.UpdateSrvKeyspacePartitions(ctx, &vtctldatapb.UpdateSrvKeyspacePartitionsRequest{
+		Keyspace:   "ks",
+		Shard:      "-80",
+		TabletType: topodatapb.TabletType_REPLICA,
+		Remove:     true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"80-"}, replicaShards("zone1"))
+	assert.Equal(t, []string{"80-"}, replicaShards("zone2"))
+
+	_, err = vtctld.UpdateSrvKeyspacePartitions(ctx, &vtctldatapb.UpdateSrvKeyspacePartitionsRequest{
+		Keyspace:   "ks",
+		Shard:      "c0-",
+		TabletType: topodatapb.TabletType_REPLICA,
+	})
+	assert.Error(t, err, "unknown shard")
+}
+
+func TestUpgradeShardMysql(t *testing.T) {
+	t.Parallel()
+
+	newTablets := func() []*topodatapb.Tablet {
+		return []*topodatapb.Tablet{
+			{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  100,
+				},
+				Type: topodatapb.TabletType_PRIMARY,
+				PrimaryTermStartTime: &vttime.Time{
+					Seconds: 100,
+				},
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+			},
+			{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  200,
+				},
+				Type:     topodatapb.TabletType_REPLICA,
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+			},
+			{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  101,
+				},
+				Type:     topodatapb.TabletType_RDONLY,
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+			},
+		}
+	}
+	newTMC := func() *testutil.TabletManagerClient {
+		return &testutil.TabletManagerClient{
+			UpgradeMysqlResults: map[string]struct {
+				Response *tabletmanagerdatapb.UpgradeMysqlResponse
+				Error    error
+			}{
+				"zone1-0000000100": {
+					Response: &tabletmanagerdatapb.UpgradeMysqlResponse{VersionBefore: "8.0.35", VersionAfter: "8.0.36"},
+				},
+				"zone1-0000000101": {
+					Response: &tabletmanagerdatapb.UpgradeMysqlResponse{VersionBefore: "8.0.35", VersionAfter: "8.0.36"},
+				},
+				"zone1-0000000200": {
+					Response: &tabletmanagerdatapb.UpgradeMysqlResponse{VersionBefore: "8.0.35", VersionAfter: "8.0.36"},
+				},
+			},
+			PrimaryPositionResults: map[string]struct {
+				Position string
+				Error    error
+			}{
+				"zone1-0000000100": {
+					Position: "primary position",
+				},
+				"zone1-0000000200": {
+					Position: "promotion position",
+				},
+			},
+			WaitForPositionResults: map[string]map[string]error{
+				"zone1-0000000100": {
+					"promotion position": nil,
+				},
+				"zone1-0000000101": {
+					"primary position": nil,
+				},
+				"zone1-0000000200": {
+					"primary position":          nil,
+					"primary-demotion position": nil,
+				},
+			},
+		}
+	}
+	upgrade := func(uid uint32) *vtctldatapb.UpgradeShardMysqlResponse_TabletUpgrade {
+		return &vtctldatapb.UpgradeShardMysqlResponse_TabletUpgrade{
+			TabletAlias: &topodatapb.TabletAlias{
+				Cell: "zone1",
+				Uid:  uid,
+			},
+			VersionBefore: "8.0.35",
+			VersionAfter:  "8.0.36",
+		}
+	}
+
+	tests := []struct {
+		name      string
+		tablets   []*topodatapb.Tablet
+		setupTMC  func(tmc *testutil.TabletManagerClient)
+		req       *vtctldatapb.UpgradeShardMysqlRequest
+		expected  *vtctldatapb.UpgradeShardMysqlResponse
+		types     map[uint32]topodatapb.TabletType
+		expectErr string
+	}{
+		{
+			name:    "replica swap",
+			tablets: newTablets(),
+			setupTMC: func(tmc *testutil.TabletManagerClient) {
+				tmc.DemotePrimaryResults = map[string]struct {
+					Status *replicationdatapb.PrimaryStatus
+					Error  error
+				}{
+					"zone1-0000000100": {
+						Status: &replicationdatapb.PrimaryStatus{
+							Position: "primary-demotion position",
+						},
+					},
+				}
+				tmc.PrimaryStatusResults = map[string]struct {
+					Status *replicationdatapb.PrimaryStatus
+					Error  error
+				}{
+					"zone1-0000000100": {Status: &replicationdatapb.PrimaryStatus{}},
+					"zone1-0000000101": {Status: &replicationdatapb.PrimaryStatus{}},
+					"zone1-0000000200": {Status: &replicationdatapb.PrimaryStatus{}},
+				}
+				tmc.PopulateReparentJournalResults = map[string]error{
+					"zone1-0000000200": nil,
+				}
+				tmc.PromoteReplicaResults = map[string]struct {
+					Result string
+					Error  error
+				}{
+					"zone1-0000000200": {
+						Result: "promotion position",
+					},
+				}
+				tmc.SetReplicationSourceResults = map[string]error{
+					"zone1-0000000100": nil,
+					"zone1-0000000101": nil,
+					"zone1-0000000200": nil,
+				}
+			},
+			req: &vtctldatapb.UpgradeShardMysqlRequest{
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+				NewPrimary: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  200,
+				},
+				WaitReplicasTimeout: protoutil.DurationToProto(time.Millisecond * 10),
+			},
+			expected: &vtctldatapb.UpgradeShardMysqlResponse{
+				Upgrades: []*vtctldatapb.UpgradeShardMysqlResponse_TabletUpgrade{upgrade(101), upgrade(200), upgrade(100)},
+				PromotedPrimary: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  200,
+				},
+			},
+			types: map[uint32]topodatapb.TabletType{
+				100: topodatapb.TabletType_REPLICA,
+				101: topodatapb.TabletType_RDONLY,
+				200: topodatapb.TabletType_PRIMARY,
+			},
+		},
+		{
+			name:    "in place",
+			tablets: newTablets(),
+			req: &vtctldatapb.UpgradeShardMysqlRequest{
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+				InPlace:  true,
+			},
+			expected: &vtctldatapb.UpgradeShardMysqlResponse{
+				Upgrades: []*vtctldatapb.UpgradeShardMysqlResponse_TabletUpgrade{upgrade(101), upgrade(200), upgrade(100)},
+			},
+			types: map[uint32]topodatapb.TabletType{
+				100: topodatapb.TabletType_PRIMARY,
+				101: topodatapb.TabletType_RDONLY,
+				200: topodatapb.TabletType_REPLICA,
+			},
+		},
+		{
+			name:    "replica does not catch up",
+			tablets: newTablets(),
+			setupTMC: func(tmc *testutil.TabletManagerClient) {
+				tmc.WaitForPositionResults["zone1-0000000200"]["primary position"] = assert.AnError
+			},
+			req: &vtctldatapb.UpgradeShardMysqlRequest{
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+				InPlace:  true,
+			},
+			types: map[uint32]topodatapb.TabletType{
+				100: topodatapb.TabletType_PRIMARY,
+				101: topodatapb.TabletType_RDONLY,
+				200: topodatapb.TabletType_DRAINED,
+			},
+			expectErr: "upgraded tablet zone1-0000000200 did not catch up with primary zone1-0000000100, it is left out of serving",
+		},
+		{
+			name:    "new primary is not a replica",
+			tablets: newTablets(),
+			req: &vtctldatapb.UpgradeShardMysqlRequest{
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+				NewPrimary: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  300,
+				},
+			},
+			expectErr: "tablet zone1-0000000300 is not a replica of shard testkeyspace/-",
+		},
+		{
+			name:    "new primary in place",
+			tablets: newTablets(),
+			req: &vtctldatapb.UpgradeShardMysqlRequest{
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+				NewPrimary: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  200,
+				},
+				InPlace: true,
+			},
+			expectErr: "cannot promote zone1-0000000200 when upgrading the primary in place",
+		},
+		{
+			name:    "no replicas",
+			tablets: newTablets()[:1],
+			req: &vtctldatapb.UpgradeShardMysqlRequest{
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+			},
+			expectErr: "shard testkeyspace/- has no replica to promote, its primary can only be upgraded in place",
+		},
+		{
+			name: "tablet taking a backup",
+			tablets: append(newTablets(), &topodatapb.Tablet{
+				Alias: &topodatapb.TabletAlias{
+					Cell: "zone1",
+					Uid:  300,
+				},
+				Type:     topodatapb.TabletType_BACKUP,
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+			}),
+			req: &vtctldatapb.UpgradeShardMysqlRequest{
+				Keyspace: "testkeyspace",
+				Shard:    "-",
+			},
+			expectErr: "tablet zone1-0000000300 is in the middle of a backup, retry when it is done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 
-			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, nil, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ts := memorytopo.NewServer(ctx, "zone1")
+			testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+				AlsoSetShardPrimary:  true,
+				ForceSetShardPrimary: true,
+				SkipShardCreation:    false,
+			}, tt.tablets...)
+
+			tmc := newTMC()
+			tmc.TopoServer = ts
+			if tt.setupTMC != nil {
+				tt.setupTMC(tmc)
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, &upgradeTabletManagerClient{tmc}, func(ts *topo.Server) vtctlservicepb.VtctldServer {
 				return NewVtctldServer(vtenv.NewTestEnv(), ts)
 			})
-			resp, err := vtctld.UpdateCellsAlias(ctx, tt.req)
-			if tt.shouldErr {
-				assert.Error(t, err)
-				return
+			resp, err := vtctld.UpgradeShardMysql(ctx, tt.req)
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+				testutil.AssertLogutilEventsOccurred(t, resp, "expected events to occur during the upgrade")
+				resp.Events = nil
+				utils.MustMatch(t, tt.expected, resp)
 			}
 
-			require.NoError(t, err)
-			utils.MustMatch(t, tt.expected, resp)
+			for uid, tabletType := range tt.types {
+				tablet, err := ts.GetTablet(ctx, &topodatapb.TabletAlias{Cell: "zone1", Uid: uid})
+				require.NoError(t, err)
+				assert.Equal(t, tabletType, tablet.Type, "type of tablet %d", uid)
+			}
 		})
 	}
 }
@@ -13372,6 +15490,281 @@ func TestValidateSchemaKeyspace(t *testing.T) {
 	}
 }
 
+func TestValidatePermissionsKeyspace(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary:  true,
+		ForceSetShardPrimary: true,
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_REPLICA,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_PRIMARY,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 200},
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_REPLICA,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 201},
+	})
+
+	permissions := &tabletmanagerdatapb.Permissions{
+		UserPermissions: []*tabletmanagerdatapb.UserPermission{{Host: "%", User: "vt_app"}},
+	}
+	extraUser := &tabletmanagerdatapb.Permissions{
+		UserPermissions: []*tabletmanagerdatapb.UserPermission{{Host: "%", User: "vt_app"}, {Host: "%", User: "vt_extra"}},
+	}
+	tmc := &testutil.TabletManagerClient{
+		GetPermissionsResults: map[string]struct {
+			Permissions *tabletmanagerdatapb.Permissions
+			Error       error
+		}{
+			"zone1-0000000100": {Permissions: permissions},
+			"zone1-0000000101": {Permissions: permissions},
+			"zone1-0000000200": {Permissions: permissions},
+			"zone1-0000000201": {Permissions: extraUser},
+		},
+	}
+	vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+		return NewVtctldServer(vtenv.NewTestEnv(), ts)
+	})
+
+	resp, err := vtctld.ValidatePermissionsKeyspace(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace: "ks",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Contains(t, resp.Results[0], "zone1-0000000201 has an extra user %:vt_extra")
+	assert.Empty(t, resp.ResultsByShard["-80"].Results)
+	assert.Equal(t, resp.Results, resp.ResultsByShard["80-"].Results)
+
+	testutil.AddKeyspace(ctx, t, ts, &vtctldatapb.Keyspace{
+		Name:     "empty",
+		Keyspace: &topodatapb.Keyspace{},
+	})
+	resp, err = vtctld.ValidatePermissionsKeyspace(ctx, &vtctldatapb.ValidatePermissionsKeyspaceRequest{
+		Keyspace: "empty",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"no shards in keyspace empty"}, resp.Results)
+}
+
+func TestValidatePermissionsShard(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary:  true,
+		ForceSetShardPrimary: true,
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_PRIMARY,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_REPLICA,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_RDONLY,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 102},
+	})
+	testutil.AddShards(ctx, t, ts, &vtctldatapb.Shard{
+		Keyspace: "ks",
+		Name:     "noprimary",
+	})
+
+	permissions := &tabletmanagerdatapb.Permissions{
+		UserPermissions: []*tabletmanagerdatapb.UserPermission{{Host: "%", User: "vt_app"}},
+	}
+
+	tests := []struct {
+		name        string
+		req         *vtctldatapb.ValidatePermissionsShardRequest
+		permissions map[string]struct {
+			Permissions *tabletmanagerdatapb.Permissions
+			Error       error
+		}
+		expected  []string
+		shouldErr bool
+	}{
+		{
+			name: "same permissions",
+			req:  &vtctldatapb.ValidatePermissionsShardRequest{Keyspace: "ks", Shard: "-"},
+			permissions: map[string]struct {
+				Permissions *tabletmanagerdatapb.Permissions
+				Error       error
+			}{
+				"zone1-0000000100": {Permissions: permissions},
+				"zone1-0000000101": {Permissions: permissions},
+				"zone1-0000000102": {Permissions: permissions},
+			},
+			expected: []string{},
+		},
+		{
+			name: "missing user and unreachable tablet",
+			req:  &vtctldatapb.ValidatePermissionsShardRequest{Keyspace: "ks", Shard: "-"},
+			permissions: map[string]struct {
+				Permissions *tabletmanagerdatapb.Permissions
+				Error       error
+			}{
+				"zone1-0000000100": {Permissions: permissions},
+				"zone1-0000000101": {Permissions: &tabletmanagerdatapb.Permissions{}},
+				"zone1-0000000102": {Error: assert.AnError},
+			},
+			expected: []string{
+				"zone1-0000000100 has an extra user %:vt_app",
+				"unable to get permissions for tablet zone1-0000000102: " + assert.AnError.Error(),
+			},
+		},
+		{
+			name: "no primary",
+			req:  &vtctldatapb.ValidatePermissionsShardRequest{Keyspace: "ks", Shard: "noprimary"},
+			permissions: map[string]struct {
+				Permissions *tabletmanagerdatapb.Permissions
+				Error       error
+			}{},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tmc := &testutil.TabletManagerClient{
+				GetPermissionsResults: tt.permissions,
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+
+			resp, err := vtctld.ValidatePermissionsShard(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, resp.Results)
+		})
+	}
+}
+
+func TestValidateSchemaShard(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary:  true,
+		ForceSetShardPrimary: true,
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_PRIMARY,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "-",
+		Type:     topodatapb.TabletType_REPLICA,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 101},
+	})
+
+	schema := func(tables ...string) *tabletmanagerdatapb.SchemaDefinition {
+		sd := &tabletmanagerdatapb.SchemaDefinition{}
+		for _, table := range tables {
+			sd.TableDefinitions = append(sd.TableDefinitions, &tabletmanagerdatapb.TableDefinition{
+				Name:   table,
+				Schema: fmt.Sprintf("CREATE TABLE %s (id int(11) not null, PRIMARY KEY (id))", table),
+				Type:   tmutils.TableBaseTable,
+			})
+		}
+		return sd
+	}
+
+	tests := []struct {
+		name      string
+		req       *vtctldatapb.ValidateSchemaShardRequest
+		schemas   map[string]*tabletmanagerdatapb.SchemaDefinition
+		expected  []string
+		shouldErr bool
+	}{
+		{
+			name: "same schema",
+			req:  &vtctldatapb.ValidateSchemaShardRequest{Keyspace: "ks", Shard: "-"},
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000100": schema("t1", "t2"),
+				"zone1-0000000101": schema("t1", "t2"),
+			},
+			expected: []string{},
+		},
+		{
+			name: "extra table on the primary",
+			req:  &vtctldatapb.ValidateSchemaShardRequest{Keyspace: "ks", Shard: "-"},
+			schemas: map[string]*tabletmanagerdatapb.SchemaDefinition{
+				"zone1-0000000100": schema("t1", "t2"),
+				"zone1-0000000101": schema("t1"),
+			},
+			expected: []string{"zone1-0000000100 has an extra table named t2"},
+		},
+		{
+			name:      "unknown shard",
+			req:       &vtctldatapb.ValidateSchemaShardRequest{Keyspace: "ks", Shard: "80-"},
+			schemas:   map[string]*tabletmanagerdatapb.SchemaDefinition{},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tmc := &testutil.TabletManagerClient{
+				GetSchemaResults: map[string]struct {
+					Schema *tabletmanagerdatapb.SchemaDefinition
+					Error  error
+				}{},
+			}
+			for alias, sd := range tt.schemas {
+				tmc.GetSchemaResults[alias] = struct {
+					Schema *tabletmanagerdatapb.SchemaDefinition
+					Error  error
+				}{Schema: sd}
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+
+			resp, err := vtctld.ValidateSchemaShard(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp.Results)
+		})
+	}
+}
+
 func TestValidateVersionKeyspace(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -14143,6 +16536,149 @@ func TestValidateShard(t *testing.T) {
 		})
 	}
 }
+
+// healthStreamQueryService is a queryservice.QueryService that streams a fixed
+// list of health responses.
+type healthStreamQueryService struct {
+	queryservice.QueryService
+	responses []*querypb.StreamHealthResponse
+}
+
+// StreamHealth is part of the queryservice.QueryService interface.
+func (q *healthStreamQueryService) StreamHealth(ctx context.Context, callback func(*querypb.StreamHealthResponse) error) error {
+	for _, shr := range q.responses {
+		if err := callback(shr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestWaitForFilteredReplication(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "zone1")
+	testutil.AddTablets(ctx, t, ts, &testutil.AddTabletOptions{
+		AlsoSetShardPrimary:  true,
+		ForceSetShardPrimary: true,
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "-80",
+		Type:     topodatapb.TabletType_PRIMARY,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 900},
+	}, &topodatapb.Tablet{
+		Keyspace: "ks",
+		Shard:    "80-",
+		Type:     topodatapb.TabletType_PRIMARY,
+		Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 901},
+	})
+	_, err := ts.UpdateShardFields(ctx, "ks", "-80", func(si *topo.ShardInfo) error {
+		si.SourceShards = []*topodatapb.Shard_SourceShard{{Keyspace: "ks", Shard: "-"}}
+		return nil
+	})
+	require.NoError(t, err)
+
+	health := func(lagSeconds int64, binlogPlayers int32) *querypb.StreamHealthResponse {
+		return &querypb.StreamHealthResponse{
+			RealtimeStats: &querypb.RealtimeStats{
+				FilteredReplicationLagSeconds: lagSeconds,
+				BinlogPlayersCount:            binlogPlayers,
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		req       *vtctldatapb.WaitForFilteredReplicationRequest
+		health    []*querypb.StreamHealthResponse
+		expected  *vtctldatapb.WaitForFilteredReplicationResponse
+		shouldErr bool
+	}{
+		{
+			name: "catches up",
+			req: &vtctldatapb.WaitForFilteredReplicationRequest{
+				Keyspace: "ks",
+				Shard:    "-80",
+				MaxDelay: protoutil.DurationToProto(5 * time.Second),
+			},
+			health: []*querypb.StreamHealthResponse{health(60, 1), health(10, 1), health(2, 1), health(90, 1)},
+			expected: &vtctldatapb.WaitForFilteredReplicationResponse{
+				LastSeenDelay: protoutil.DurationToProto(2 * time.Second),
+			},
+		},
+		{
+			name: "default max delay",
+			req: &vtctldatapb.WaitForFilteredReplicationRequest{
+				Keyspace: "ks",
+				Shard:    "-80",
+			},
+			health: []*querypb.StreamHealthResponse{health(60, 1), health(30, 1)},
+			expected: &vtctldatapb.WaitForFilteredReplicationResponse{
+				LastSeenDelay: protoutil.DurationToProto(30 * time.Second),
+			},
+		},
+		{
+			name: "stream ends before catching up",
+			req: &vtctldatapb.WaitForFilteredReplicationRequest{
+				Keyspace: "ks",
+				Shard:    "-80",
+				MaxDelay: protoutil.DurationToProto(5 * time.Second),
+			},
+			health:    []*querypb.StreamHealthResponse{health(60, 1), health(10, 1)},
+			shouldErr: true,
+		},
+		{
+			name: "no filtered replication",
+			req: &vtctldatapb.WaitForFilteredReplicationRequest{
+				Keyspace: "ks",
+				Shard:    "-80",
+			},
+			health:    []*querypb.StreamHealthResponse{health(0, 0)},
+			shouldErr: true,
+		},
+		{
+			name: "no source shards",
+			req: &vtctldatapb.WaitForFilteredReplicationRequest{
+				Keyspace: "ks",
+				Shard:    "80-",
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// Not parallel: the tests share the health stream of the primary.
+			testQueryServices.Store("zone1-0000000900", &healthStreamQueryService{
+				QueryService: fakes.ErrorQueryService,
+				responses:    tt.health,
+			})
+			defer testQueryServices.Delete("zone1-0000000900")
+
+			tmc := &testutil.TabletManagerClient{
+				RunHealthCheckResults: map[string]error{
+					"zone1-0000000900": nil,
+				},
+			}
+			vtctld := testutil.NewVtctldServerWithTabletManagerClient(t, ts, tmc, func(ts *topo.Server) vtctlservicepb.VtctldServer {
+				return NewVtctldServer(vtenv.NewTestEnv(), ts)
+			})
+
+			resp, err := vtctld.WaitForFilteredReplication(ctx, tt.req)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			utils.MustMatch(t, tt.expected, resp)
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	_flag.ParseFlagsForTest()
 	os.Exit(m.Run())
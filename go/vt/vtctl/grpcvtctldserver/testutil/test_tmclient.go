@@ -230,6 +230,11 @@ type TabletManagerClient struct {
 	}
 	// FullStatus result
 	FullStatusResult *replicationdatapb.FullStatus
+	// keyed by tablet alias. Takes precedence over FullStatusResult.
+	FullStatusResults map[string]struct {
+		Status *replicationdatapb.FullStatus
+		Error  error
+	}
 	// keyed by tablet alias.
 	GetPermissionsDelays map[string]time.Duration
 	// keyed by tablet alias.
@@ -250,6 +255,11 @@ type TabletManagerClient struct {
 		Error  error
 	}
 	// keyed by tablet alias.
+	GetUnresolvedTransactionsResults map[string]struct {
+		Transactions []*querypb.TransactionMetadata
+		Error        error
+	}
+	// keyed by tablet alias.
 	InitPrimaryDelays map[string]time.Duration
 	// keyed by tablet alias. injects a sleep to the end of the function
 	// regardless of parent context timeout or error result.
@@ -260,6 +270,11 @@ type TabletManagerClient struct {
 		Error  error
 	}
 	// keyed by tablet alias.
+	KillQueriesResults map[string]struct {
+		Response *tabletmanagerdatapb.KillQueriesResponse
+		Error    error
+	}
+	// keyed by tablet alias.
 	PrimaryPositionDelays map[string]time.Duration
 	// keyed by tablet alias.
 	PrimaryPositionResults map[string]struct {
@@ -289,9 +304,16 @@ type TabletManagerClient struct {
 	// keyed by `<tablet_alias>/<wait_pos>`.
 	ReloadSchemaDelays map[string]time.Duration
 	// keyed by `<tablet_alias>/<wait_pos>`.
-	ReloadSchemaResults      map[string]error
-	ReplicationStatusDelays  map[string]time.Duration
-	ReplicationStatusResults map[string]struct {
+	ReloadSchemaResults map[string]error
+	// keyed by tablet alias.
+	RequeueDeadLettersResults map[string]struct {
+		Count int64
+		Error error
+	}
+	// keyed by tablet alias.
+	ResolveTransactionResults map[string]error
+	ReplicationStatusDelays   map[string]time.Duration
+	ReplicationStatusResults  map[string]struct {
 		Position *replicationdatapb.Status
 		Error    error
 	}
@@ -306,6 +328,11 @@ type TabletManagerClient struct {
 		EventJitter   time.Duration
 		ErrorAfter    time.Duration
 	}
+	// keyed by tablet alias.
+	RunDiagnosticQueryResults map[string]struct {
+		Response *tabletmanagerdatapb.RunDiagnosticQueryResponse
+		Error    error
+	}
 	// keyed by tablet alias
 	RunHealthCheckDelays map[string]time.Duration
 	// keyed by tablet alias
@@ -347,6 +374,16 @@ type TabletManagerClient struct {
 	UndoDemotePrimaryDelays map[string]time.Duration
 	// keyed by tablet alias
 	UndoDemotePrimaryResults map[string]error
+	// keyed by tablet alias.
+	UpdateQueryFingerprintRulesResults map[string]struct {
+		Response *tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse
+		Error    error
+	}
+	// keyed by tablet alias.
+	UpgradeMysqlResults map[string]struct {
+		Response *tabletmanagerdatapb.UpgradeMysqlResponse
+		Error    error
+	}
 	// tablet alias => duration
 	VReplicationExecDelays map[string]time.Duration
 	// tablet alias => query string => result
@@ -632,6 +669,13 @@ func (fake *TabletManagerClient) ExecuteQuery(ctx context.Context, tablet *topod
 
 // FullStatus is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) FullStatus(ctx context.Context, tablet *topodatapb.Tablet) (*replicationdatapb.FullStatus, error) {
+	if fake.FullStatusResults != nil {
+		if result, ok := fake.FullStatusResults[topoproto.TabletAliasString(tablet.Alias)]; ok {
+			return result.Status, result.Error
+		}
+		return nil, assert.AnError
+	}
+
 	if fake.FullStatusResult != nil {
 		return fake.FullStatusResult, nil
 	}
@@ -717,6 +761,20 @@ func (fake *TabletManagerClient) GetSchema(ctx context.Context, tablet *topodata
 	return nil, fmt.Errorf("%w: no schemas for %s", assert.AnError, key)
 }
 
+// GetUnresolvedTransactions is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) GetUnresolvedTransactions(ctx context.Context, tablet *topodatapb.Tablet, abandonAge int64) ([]*querypb.TransactionMetadata, error) {
+	if fake.GetUnresolvedTransactionsResults == nil {
+		return nil, fmt.Errorf("%w: no GetUnresolvedTransactions results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.GetUnresolvedTransactionsResults[key]; ok {
+		return result.Transactions, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no GetUnresolvedTransactions result set for tablet %s", assert.AnError, key)
+}
+
 // InitPrimary is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) InitPrimary(ctx context.Context, tablet *topodatapb.Tablet, semiSync bool) (string, error) {
 	if fake.InitPrimaryResults == nil {
@@ -753,6 +811,20 @@ func (fake *TabletManagerClient) InitPrimary(ctx context.Context, tablet *topoda
 	return "", assert.AnError
 }
 
+// KillQueries is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) KillQueries(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.KillQueriesRequest) (*tabletmanagerdatapb.KillQueriesResponse, error) {
+	if fake.KillQueriesResults == nil {
+		return nil, fmt.Errorf("%w: no KillQueries results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.KillQueriesResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no KillQueries result set for tablet %s", assert.AnError, key)
+}
+
 // PrimaryPosition is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) PrimaryPosition(ctx context.Context, tablet *topodatapb.Tablet) (string, error) {
 	if fake.PrimaryPositionResults == nil {
@@ -996,6 +1068,34 @@ func (stream *backupRestoreStreamAdapter) Send(msg *logutilpb.Event) error {
 	}
 }
 
+// RequeueDeadLetters is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) RequeueDeadLetters(ctx context.Context, tablet *topodatapb.Tablet, table string, ids []string) (int64, error) {
+	if fake.RequeueDeadLettersResults == nil {
+		return 0, fmt.Errorf("%w: no RequeueDeadLetters results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.RequeueDeadLettersResults[key]; ok {
+		return result.Count, result.Error
+	}
+
+	return 0, fmt.Errorf("%w: no RequeueDeadLetters result set for tablet %s", assert.AnError, key)
+}
+
+// ResolveTransaction is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) ResolveTransaction(ctx context.Context, tablet *topodatapb.Tablet, dtid string) error {
+	if fake.ResolveTransactionResults == nil {
+		return fmt.Errorf("%w: no ResolveTransaction results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if err, ok := fake.ResolveTransactionResults[key]; ok {
+		return err
+	}
+
+	return fmt.Errorf("%w: no ResolveTransaction result set for tablet %s", assert.AnError, key)
+}
+
 // RestoreFromBackup is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) RestoreFromBackup(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) (logutil.EventStream, error) {
 	key := topoproto.TabletAliasString(tablet.Alias)
@@ -1061,6 +1161,20 @@ func (fake *TabletManagerClient) RestoreFromBackup(ctx context.Context, tablet *
 	return stream, nil
 }
 
+// RunDiagnosticQuery is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) RunDiagnosticQuery(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RunDiagnosticQueryRequest) (*tabletmanagerdatapb.RunDiagnosticQueryResponse, error) {
+	if fake.RunDiagnosticQueryResults == nil {
+		return nil, fmt.Errorf("%w: no RunDiagnosticQuery results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.RunDiagnosticQueryResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no RunDiagnosticQuery result set for tablet %s", assert.AnError, key)
+}
+
 // RunHealthCheck is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) RunHealthCheck(ctx context.Context, tablet *topodatapb.Tablet) error {
 	if fake.RunHealthCheckResults == nil {
@@ -1379,6 +1493,34 @@ func (fake *TabletManagerClient) UndoDemotePrimary(ctx context.Context, tablet *
 	return assert.AnError
 }
 
+// UpdateQueryFingerprintRules is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) UpdateQueryFingerprintRules(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest) (*tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse, error) {
+	if fake.UpdateQueryFingerprintRulesResults == nil {
+		return nil, fmt.Errorf("%w: no UpdateQueryFingerprintRules results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.UpdateQueryFingerprintRulesResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no UpdateQueryFingerprintRules result set for tablet %s", assert.AnError, key)
+}
+
+// UpgradeMysql is part of the tmclient.TabletManagerClient interface.
+func (fake *TabletManagerClient) UpgradeMysql(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpgradeMysqlRequest) (*tabletmanagerdatapb.UpgradeMysqlResponse, error) {
+	if fake.UpgradeMysqlResults == nil {
+		return nil, fmt.Errorf("%w: no UpgradeMysql results on fake TabletManagerClient", assert.AnError)
+	}
+
+	key := topoproto.TabletAliasString(tablet.Alias)
+	if result, ok := fake.UpgradeMysqlResults[key]; ok {
+		return result.Response, result.Error
+	}
+
+	return nil, fmt.Errorf("%w: no UpgradeMysql result set for tablet %s", assert.AnError, key)
+}
+
 // VReplicationExec is part of the tmclient.TabletManagerClient interface.
 func (fake *TabletManagerClient) VReplicationExec(ctx context.Context, tablet *topodatapb.Tablet, query string) (*querypb.QueryResult, error) {
 	if fake.VReplicationExecResults == nil {
@@ -39,6 +39,11 @@ func (client *localVtctldClient) AddCellsAlias(ctx context.Context, in *vtctldat
 	return client.s.AddCellsAlias(ctx, in)
 }
 
+// ApplyQuotaRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ApplyQuotaRules(ctx context.Context, in *vtctldatapb.ApplyQuotaRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyQuotaRulesResponse, error) {
+	return client.s.ApplyQuotaRules(ctx, in)
+}
+
 // ApplyRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyRoutingRules(ctx context.Context, in *vtctldatapb.ApplyRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyRoutingRulesResponse, error) {
 	return client.s.ApplyRoutingRules(ctx, in)
@@ -54,6 +59,11 @@ func (client *localVtctldClient) ApplyShardRoutingRules(ctx context.Context, in
 	return client.s.ApplyShardRoutingRules(ctx, in)
 }
 
+// ApplyTenantRoutingRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ApplyTenantRoutingRules(ctx context.Context, in *vtctldatapb.ApplyTenantRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyTenantRoutingRulesResponse, error) {
+	return client.s.ApplyTenantRoutingRules(ctx, in)
+}
+
 // ApplyVSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ApplyVSchema(ctx context.Context, in *vtctldatapb.ApplyVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.ApplyVSchemaResponse, error) {
 	return client.s.ApplyVSchema(ctx, in)
@@ -181,6 +191,11 @@ func (client *localVtctldClient) CompleteSchemaMigration(ctx context.Context, in
 	return client.s.CompleteSchemaMigration(ctx, in)
 }
 
+// CopySchemaShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) CopySchemaShard(ctx context.Context, in *vtctldatapb.CopySchemaShardRequest, opts ...grpc.CallOption) (*vtctldatapb.CopySchemaShardResponse, error) {
+	return client.s.CopySchemaShard(ctx, in)
+}
+
 // CreateKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) CreateKeyspace(ctx context.Context, in *vtctldatapb.CreateKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.CreateKeyspaceResponse, error) {
 	return client.s.CreateKeyspace(ctx, in)
@@ -236,16 +251,16 @@ func (client *localVtctldClient) ExecuteFetchAsDBA(ctx context.Context, in *vtct
 	return client.s.ExecuteFetchAsDBA(ctx, in)
 }
 
-// ExecuteMultiFetchAsDBA is part of the vtctlservicepb.VtctldClient interface.
-func (client *localVtctldClient) ExecuteMultiFetchAsDBA(ctx context.Context, in *vtctldatapb.ExecuteMultiFetchAsDBARequest, opts ...grpc.CallOption) (*vtctldatapb.ExecuteMultiFetchAsDBAResponse, error) {
-	return client.s.ExecuteMultiFetchAsDBA(ctx, in)
-}
-
 // ExecuteHook is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ExecuteHook(ctx context.Context, in *vtctldatapb.ExecuteHookRequest, opts ...grpc.CallOption) (*vtctldatapb.ExecuteHookResponse, error) {
 	return client.s.ExecuteHook(ctx, in)
 }
 
+// ExecuteMultiFetchAsDBA is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ExecuteMultiFetchAsDBA(ctx context.Context, in *vtctldatapb.ExecuteMultiFetchAsDBARequest, opts ...grpc.CallOption) (*vtctldatapb.ExecuteMultiFetchAsDBAResponse, error) {
+	return client.s.ExecuteMultiFetchAsDBA(ctx, in)
+}
+
 // FindAllShardsInKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) FindAllShardsInKeyspace(ctx context.Context, in *vtctldatapb.FindAllShardsInKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.FindAllShardsInKeyspaceResponse, error) {
 	return client.s.FindAllShardsInKeyspace(ctx, in)
@@ -256,6 +271,16 @@ func (client *localVtctldClient) ForceCutOverSchemaMigration(ctx context.Context
 	return client.s.ForceCutOverSchemaMigration(ctx, in)
 }
 
+// ForceUnlock is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ForceUnlock(ctx context.Context, in *vtctldatapb.ForceUnlockRequest, opts ...grpc.CallOption) (*vtctldatapb.ForceUnlockResponse, error) {
+	return client.s.ForceUnlock(ctx, in)
+}
+
+// GetBackupSchedule is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetBackupSchedule(ctx context.Context, in *vtctldatapb.GetBackupScheduleRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupScheduleResponse, error) {
+	return client.s.GetBackupSchedule(ctx, in)
+}
+
 // GetBackups is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetBackups(ctx context.Context, in *vtctldatapb.GetBackupsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetBackupsResponse, error) {
 	return client.s.GetBackups(ctx, in)
@@ -296,6 +321,11 @@ func (client *localVtctldClient) GetPermissions(ctx context.Context, in *vtctlda
 	return client.s.GetPermissions(ctx, in)
 }
 
+// GetQuotaRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetQuotaRules(ctx context.Context, in *vtctldatapb.GetQuotaRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetQuotaRulesResponse, error) {
+	return client.s.GetQuotaRules(ctx, in)
+}
+
 // GetRoutingRules is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetRoutingRules(ctx context.Context, in *vtctldatapb.GetRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetRoutingRulesResponse, error) {
 	return client.s.GetRoutingRules(ctx, in)
@@ -356,11 +386,31 @@ func (client *localVtctldClient) GetTablets(ctx context.Context, in *vtctldatapb
 	return client.s.GetTablets(ctx, in)
 }
 
+// GetTenantRoutingRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetTenantRoutingRules(ctx context.Context, in *vtctldatapb.GetTenantRoutingRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTenantRoutingRulesResponse, error) {
+	return client.s.GetTenantRoutingRules(ctx, in)
+}
+
+// GetTopoLocks is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetTopoLocks(ctx context.Context, in *vtctldatapb.GetTopoLocksRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTopoLocksResponse, error) {
+	return client.s.GetTopoLocks(ctx, in)
+}
+
 // GetTopologyPath is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetTopologyPath(ctx context.Context, in *vtctldatapb.GetTopologyPathRequest, opts ...grpc.CallOption) (*vtctldatapb.GetTopologyPathResponse, error) {
 	return client.s.GetTopologyPath(ctx, in)
 }
 
+// GetUnresolvedTransactions is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetUnresolvedTransactions(ctx context.Context, in *vtctldatapb.GetUnresolvedTransactionsRequest, opts ...grpc.CallOption) (*vtctldatapb.GetUnresolvedTransactionsResponse, error) {
+	return client.s.GetUnresolvedTransactions(ctx, in)
+}
+
+// GetVDiffReport is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) GetVDiffReport(ctx context.Context, in *vtctldatapb.GetVDiffReportRequest, opts ...grpc.CallOption) (*vtctldatapb.GetVDiffReportResponse, error) {
+	return client.s.GetVDiffReport(ctx, in)
+}
+
 // GetVSchema is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) GetVSchema(ctx context.Context, in *vtctldatapb.GetVSchemaRequest, opts ...grpc.CallOption) (*vtctldatapb.GetVSchemaResponse, error) {
 	return client.s.GetVSchema(ctx, in)
@@ -381,6 +431,11 @@ func (client *localVtctldClient) InitShardPrimary(ctx context.Context, in *vtctl
 	return client.s.InitShardPrimary(ctx, in)
 }
 
+// KillQueries is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) KillQueries(ctx context.Context, in *vtctldatapb.KillQueriesRequest, opts ...grpc.CallOption) (*vtctldatapb.KillQueriesResponse, error) {
+	return client.s.KillQueries(ctx, in)
+}
+
 // LaunchSchemaMigration is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) LaunchSchemaMigration(ctx context.Context, in *vtctldatapb.LaunchSchemaMigrationRequest, opts ...grpc.CallOption) (*vtctldatapb.LaunchSchemaMigrationResponse, error) {
 	return client.s.LaunchSchemaMigration(ctx, in)
@@ -441,6 +496,11 @@ func (client *localVtctldClient) PingTablet(ctx context.Context, in *vtctldatapb
 	return client.s.PingTablet(ctx, in)
 }
 
+// PlanReshard is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) PlanReshard(ctx context.Context, in *vtctldatapb.PlanReshardRequest, opts ...grpc.CallOption) (*vtctldatapb.PlanReshardResponse, error) {
+	return client.s.PlanReshard(ctx, in)
+}
+
 // PlannedReparentShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) PlannedReparentShard(ctx context.Context, in *vtctldatapb.PlannedReparentShardRequest, opts ...grpc.CallOption) (*vtctldatapb.PlannedReparentShardResponse, error) {
 	return client.s.PlannedReparentShard(ctx, in)
@@ -501,11 +561,21 @@ func (client *localVtctldClient) ReparentTablet(ctx context.Context, in *vtctlda
 	return client.s.ReparentTablet(ctx, in)
 }
 
+// RequeueDeadLetters is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RequeueDeadLetters(ctx context.Context, in *vtctldatapb.RequeueDeadLettersRequest, opts ...grpc.CallOption) (*vtctldatapb.RequeueDeadLettersResponse, error) {
+	return client.s.RequeueDeadLetters(ctx, in)
+}
+
 // ReshardCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ReshardCreate(ctx context.Context, in *vtctldatapb.ReshardCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowStatusResponse, error) {
 	return client.s.ReshardCreate(ctx, in)
 }
 
+// ResolveTransaction is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ResolveTransaction(ctx context.Context, in *vtctldatapb.ResolveTransactionRequest, opts ...grpc.CallOption) (*vtctldatapb.ResolveTransactionResponse, error) {
+	return client.s.ResolveTransaction(ctx, in)
+}
+
 type restoreFromBackupStreamAdapter struct {
 	*grpcshim.BidiStream
 	ch chan *vtctldatapb.RestoreFromBackupResponse
@@ -562,11 +632,26 @@ func (client *localVtctldClient) RetrySchemaMigration(ctx context.Context, in *v
 	return client.s.RetrySchemaMigration(ctx, in)
 }
 
+// RunDiagnosticQuery is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) RunDiagnosticQuery(ctx context.Context, in *vtctldatapb.RunDiagnosticQueryRequest, opts ...grpc.CallOption) (*vtctldatapb.RunDiagnosticQueryResponse, error) {
+	return client.s.RunDiagnosticQuery(ctx, in)
+}
+
 // RunHealthCheck is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) RunHealthCheck(ctx context.Context, in *vtctldatapb.RunHealthCheckRequest, opts ...grpc.CallOption) (*vtctldatapb.RunHealthCheckResponse, error) {
 	return client.s.RunHealthCheck(ctx, in)
 }
 
+// SchemaDiff is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SchemaDiff(ctx context.Context, in *vtctldatapb.SchemaDiffRequest, opts ...grpc.CallOption) (*vtctldatapb.SchemaDiffResponse, error) {
+	return client.s.SchemaDiff(ctx, in)
+}
+
+// SetBackupSchedule is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) SetBackupSchedule(ctx context.Context, in *vtctldatapb.SetBackupScheduleRequest, opts ...grpc.CallOption) (*vtctldatapb.SetBackupScheduleResponse, error) {
+	return client.s.SetBackupSchedule(ctx, in)
+}
+
 // SetKeyspaceDurabilityPolicy is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) SetKeyspaceDurabilityPolicy(ctx context.Context, in *vtctldatapb.SetKeyspaceDurabilityPolicyRequest, opts ...grpc.CallOption) (*vtctldatapb.SetKeyspaceDurabilityPolicyResponse, error) {
 	return client.s.SetKeyspaceDurabilityPolicy(ctx, in)
@@ -637,6 +722,11 @@ func (client *localVtctldClient) TabletExternallyReparented(ctx context.Context,
 	return client.s.TabletExternallyReparented(ctx, in)
 }
 
+// TabletExternallyReparentedV2 is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) TabletExternallyReparentedV2(ctx context.Context, in *vtctldatapb.TabletExternallyReparentedV2Request, opts ...grpc.CallOption) (*vtctldatapb.TabletExternallyReparentedV2Response, error) {
+	return client.s.TabletExternallyReparentedV2(ctx, in)
+}
+
 // UpdateCellInfo is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) UpdateCellInfo(ctx context.Context, in *vtctldatapb.UpdateCellInfoRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateCellInfoResponse, error) {
 	return client.s.UpdateCellInfo(ctx, in)
@@ -647,11 +737,26 @@ func (client *localVtctldClient) UpdateCellsAlias(ctx context.Context, in *vtctl
 	return client.s.UpdateCellsAlias(ctx, in)
 }
 
+// UpdateQueryFingerprintRules is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) UpdateQueryFingerprintRules(ctx context.Context, in *vtctldatapb.UpdateQueryFingerprintRulesRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateQueryFingerprintRulesResponse, error) {
+	return client.s.UpdateQueryFingerprintRules(ctx, in)
+}
+
 // UpdateThrottlerConfig is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) UpdateThrottlerConfig(ctx context.Context, in *vtctldatapb.UpdateThrottlerConfigRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateThrottlerConfigResponse, error) {
 	return client.s.UpdateThrottlerConfig(ctx, in)
 }
 
+// UpdateSrvKeyspacePartitions is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) UpdateSrvKeyspacePartitions(ctx context.Context, in *vtctldatapb.UpdateSrvKeyspacePartitionsRequest, opts ...grpc.CallOption) (*vtctldatapb.UpdateSrvKeyspacePartitionsResponse, error) {
+	return client.s.UpdateSrvKeyspacePartitions(ctx, in)
+}
+
+// UpgradeShardMysql is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) UpgradeShardMysql(ctx context.Context, in *vtctldatapb.UpgradeShardMysqlRequest, opts ...grpc.CallOption) (*vtctldatapb.UpgradeShardMysqlResponse, error) {
+	return client.s.UpgradeShardMysql(ctx, in)
+}
+
 // VDiffCreate is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) VDiffCreate(ctx context.Context, in *vtctldatapb.VDiffCreateRequest, opts ...grpc.CallOption) (*vtctldatapb.VDiffCreateResponse, error) {
 	return client.s.VDiffCreate(ctx, in)
@@ -687,11 +792,26 @@ func (client *localVtctldClient) ValidateKeyspace(ctx context.Context, in *vtctl
 	return client.s.ValidateKeyspace(ctx, in)
 }
 
+// ValidatePermissionsKeyspace is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidatePermissionsKeyspace(ctx context.Context, in *vtctldatapb.ValidatePermissionsKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidatePermissionsKeyspaceResponse, error) {
+	return client.s.ValidatePermissionsKeyspace(ctx, in)
+}
+
+// ValidatePermissionsShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidatePermissionsShard(ctx context.Context, in *vtctldatapb.ValidatePermissionsShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidatePermissionsShardResponse, error) {
+	return client.s.ValidatePermissionsShard(ctx, in)
+}
+
 // ValidateSchemaKeyspace is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ValidateSchemaKeyspace(ctx context.Context, in *vtctldatapb.ValidateSchemaKeyspaceRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateSchemaKeyspaceResponse, error) {
 	return client.s.ValidateSchemaKeyspace(ctx, in)
 }
 
+// ValidateSchemaShard is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) ValidateSchemaShard(ctx context.Context, in *vtctldatapb.ValidateSchemaShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateSchemaShardResponse, error) {
+	return client.s.ValidateSchemaShard(ctx, in)
+}
+
 // ValidateShard is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) ValidateShard(ctx context.Context, in *vtctldatapb.ValidateShardRequest, opts ...grpc.CallOption) (*vtctldatapb.ValidateShardResponse, error) {
 	return client.s.ValidateShard(ctx, in)
@@ -712,6 +832,11 @@ func (client *localVtctldClient) ValidateVersionShard(ctx context.Context, in *v
 	return client.s.ValidateVersionShard(ctx, in)
 }
 
+// WaitForFilteredReplication is part of the vtctlservicepb.VtctldClient interface.
+func (client *localVtctldClient) WaitForFilteredReplication(ctx context.Context, in *vtctldatapb.WaitForFilteredReplicationRequest, opts ...grpc.CallOption) (*vtctldatapb.WaitForFilteredReplicationResponse, error) {
+	return client.s.WaitForFilteredReplication(ctx, in)
+}
+
 // WorkflowDelete is part of the vtctlservicepb.VtctldClient interface.
 func (client *localVtctldClient) WorkflowDelete(ctx context.Context, in *vtctldatapb.WorkflowDeleteRequest, opts ...grpc.CallOption) (*vtctldatapb.WorkflowDeleteResponse, error) {
 	return client.s.WorkflowDelete(ctx, in)
@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reshardplan proposes the shard splits and merges that keep the
+// size and the QPS of the shards of a keyspace within limits.
+package reshardplan
+
+import (
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Limits are the limits of the size and the QPS of the shards.
+type Limits struct {
+	// MaxSizeBytes is the data size above which a shard is split, 0 if
+	// unlimited.
+	MaxSizeBytes uint64
+	// MaxQPS is the QPS above which a shard is split, 0 if unlimited.
+	MaxQPS float64
+	// MergeThreshold is the fraction of the limits under which adjacent
+	// shards must stay together to be merged, 0 to never merge shards.
+	MergeThreshold float64
+}
+
+// Validate returns an error if the limits cannot be planned with.
+func (l Limits) Validate() error {
+	if l.MaxSizeBytes == 0 && l.MaxQPS <= 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a maximum shard size or QPS is required")
+	}
+	if l.MaxQPS < 0 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid maximum shard QPS %v", l.MaxQPS)
+	}
+	if l.MergeThreshold < 0 || l.MergeThreshold >= 1 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the merge threshold must be at least 0 and below 1, got %v", l.MergeThreshold)
+	}
+	return nil
+}
+
+// Load returns the highest ratio of the size and the QPS of a shard to their
+// limits.
+func (l Limits) Load(sizeBytes uint64, qps float64) float64 {
+	var load float64
+	if l.MaxSizeBytes > 0 {
+		load = float64(sizeBytes) / float64(l.MaxSizeBytes)
+	}
+	if l.MaxQPS > 0 {
+		load = math.Max(load, qps/l.MaxQPS)
+	}
+	return load
+}
+
+type shard struct {
+	*vtctldatapb.ShardLoad
+	keyRange *topodatapb.KeyRange
+}
+
+// Plan sets the load of the shards of a keyspace, orders them by key range,
+// and returns the proposals that bring them within the limits:
+//   - a shard with a load above 1 is split evenly into as many shards as
+//     needed for each of them to be under the limits, assuming that its rows
+//     and queries are spread evenly over its key range.
+//   - runs of adjacent shards whose combined load stays under the merge
+//     threshold are merged, into as few shards as possible.
+//
+// Each proposal comes with the ReshardCreateRequest of its workflow.
+func Plan(keyspace string, shards []*vtctldatapb.ShardLoad, limits Limits) ([]*vtctldatapb.ReshardProposal, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+
+	ordered := make([]*shard, 0, len(shards))
+	for _, sl := range shards {
+		_, kr, err := topo.ValidateShardName(sl.Name)
+		if err != nil {
+			return nil, err
+		}
+		if kr == nil {
+			if len(shards) > 1 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shard %v/%v is not range based, and is not the only shard of its keyspace", keyspace, sl.Name)
+			}
+			kr = &topodatapb.KeyRange{}
+		}
+		sl.Load = limits.Load(sl.SizeBytes, sl.Qps)
+		ordered = append(ordered, &shard{ShardLoad: sl, keyRange: kr})
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return key.KeyRangeLess(ordered[i].keyRange, ordered[j].keyRange)
+	})
+	for i, s := range ordered {
+		shards[i] = s.ShardLoad
+		if i > 0 && key.KeyRangeIntersect(ordered[i-1].keyRange, s.keyRange) {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "shards %v and %v of keyspace %v overlap, finish the ongoing reshard first", ordered[i-1].Name, s.Name, keyspace)
+		}
+	}
+
+	var (
+		proposals []*vtctldatapb.ReshardProposal
+		merge     []*shard
+	)
+	flushMerge := func() {
+		if len(merge) > 1 {
+			proposals = append(proposals, mergeProposal(keyspace, merge, limits))
+		}
+		merge = nil
+	}
+	for _, s := range ordered {
+		if s.Load > 1 {
+			flushMerge()
+			proposal, err := splitProposal(keyspace, s)
+			if err != nil {
+				return nil, err
+			}
+			proposals = append(proposals, proposal)
+			continue
+		}
+		if limits.MergeThreshold == 0 {
+			continue
+		}
+		if len(merge) > 0 && key.KeyRangeContiguous(merge[len(merge)-1].keyRange, s.keyRange) {
+			sizeBytes, qps := sumLoads(merge)
+			if limits.Load(sizeBytes+s.SizeBytes, qps+s.Qps) <= limits.MergeThreshold {
+				merge = append(merge, s)
+				continue
+			}
+		}
+		flushMerge()
+		if s.Load <= limits.MergeThreshold {
+			merge = []*shard{s}
+		}
+	}
+	flushMerge()
+
+	return proposals, nil
+}
+
+func sumLoads(shards []*shard) (sizeBytes uint64, qps float64) {
+	for _, s := range shards {
+		sizeBytes += s.SizeBytes
+		qps += s.Qps
+	}
+	return sizeBytes, qps
+}
+
+func splitProposal(keyspace string, s *shard) (*vtctldatapb.ReshardProposal, error) {
+	n := int(math.Ceil(s.Load))
+	keyRanges, err := splitKeyRange(s.keyRange, n)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "cannot split shard %v/%v", keyspace, s.Name)
+	}
+
+	proposal := &vtctldatapb.ReshardProposal{
+		SourceShards: []string{s.Name},
+	}
+	for _, kr := range keyRanges {
+		proposal.TargetShards = append(proposal.TargetShards, &vtctldatapb.ShardLoad{
+			Name:      key.KeyRangeString(kr),
+			SizeBytes: s.SizeBytes / uint64(n),
+			RowCount:  s.RowCount / uint64(n),
+			Qps:       s.Qps / float64(n),
+			Load:      s.Load / float64(n),
+		})
+	}
+	proposal.Reshard = reshardRequest(keyspace, "split", proposal)
+	return proposal, nil
+}
+
+func mergeProposal(keyspace string, shards []*shard, limits Limits) *vtctldatapb.ReshardProposal {
+	proposal := &vtctldatapb.ReshardProposal{}
+	target := &vtctldatapb.ShardLoad{}
+	keyRange := shards[0].keyRange
+	for i, s := range shards {
+		proposal.SourceShards = append(proposal.SourceShards, s.Name)
+		target.SizeBytes += s.SizeBytes
+		target.RowCount += s.RowCount
+		target.Qps += s.Qps
+		if i > 0 {
+			// The shards were checked to be contiguous.
+			keyRange, _ = key.KeyRangeAdd(keyRange, s.keyRange)
+		}
+	}
+	target.Name = key.KeyRangeString(keyRange)
+	target.Load = limits.Load(target.SizeBytes, target.Qps)
+	proposal.TargetShards = []*vtctldatapb.ShardLoad{target}
+	proposal.Reshard = reshardRequest(keyspace, "merge", proposal)
+	return proposal
+}
+
+func reshardRequest(keyspace string, kind string, proposal *vtctldatapb.ReshardProposal) *vtctldatapb.ReshardCreateRequest {
+	req := &vtctldatapb.ReshardCreateRequest{
+		Keyspace:     keyspace,
+		SourceShards: proposal.SourceShards,
+	}
+	for _, target := range proposal.TargetShards {
+		req.TargetShards = append(req.TargetShards, target.Name)
+	}
+	// A split is named after its source shard, and a merge after its target.
+	name := req.SourceShards[0]
+	if kind == "merge" {
+		name = req.TargetShards[0]
+	}
+	req.Workflow = kind + "_" + strings.ReplaceAll(name, "-", "_")
+	return req
+}
+
+// maxSplit is the maximum number of shards a shard is split into.
+const maxSplit = 256
+
+// splitKeyRange splits a key range into n key ranges of the same width. The
+// boundaries use as few bytes as possible, while keeping the widths within a
+// few percents of each other.
+func splitKeyRange(kr *topodatapb.KeyRange, n int) ([]*topodatapb.KeyRange, error) {
+	if n > maxSplit {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "it would be split into %d shards, more than %d, check the limits", n, maxSplit)
+	}
+	if n < 2 {
+		return []*topodatapb.KeyRange{kr}, nil
+	}
+	var start, width *big.Int
+	// The width grows 256 times with every byte, and the key range is not
+	// empty, so the loop ends.
+	size := max(len(kr.Start), len(kr.End), 1)
+	for ; ; size++ {
+		start = keyToInt(kr.Start, size)
+		end := new(big.Int).Lsh(big.NewInt(1), uint(8*size))
+		if len(kr.End) > 0 {
+			end = keyToInt(kr.End, size)
+		}
+		width = end.Sub(end, start)
+		if width.Cmp(big.NewInt(int64(16*n))) >= 0 {
+			break
+		}
+	}
+
+	keyRanges := make([]*topodatapb.KeyRange, 0, n)
+	lower := kr.Start
+	for i := 1; i < n; i++ {
+		boundary := new(big.Int).Mul(width, big.NewInt(int64(i)))
+		boundary.Div(boundary, big.NewInt(int64(n)))
+		boundary.Add(boundary, start)
+		upper := intToKey(boundary, size)
+		keyRanges = append(keyRanges, &topodatapb.KeyRange{Start: lower, End: upper})
+		lower = upper
+	}
+	return append(keyRanges, &topodatapb.KeyRange{Start: lower, End: kr.End}), nil
+}
+
+// keyToInt returns the value of a key padded with zeros to size bytes.
+func keyToInt(k []byte, size int) *big.Int {
+	padded := make([]byte, size)
+	copy(padded, k)
+	return new(big.Int).SetBytes(padded)
+}
+
+// intToKey returns the key of size bytes of a value, without its trailing
+// zeros.
+func intToKey(v *big.Int, size int) []byte {
+	k := v.FillBytes(make([]byte, size))
+	for len(k) > 1 && k[len(k)-1] == 0 {
+		k = k[:len(k)-1]
+	}
+	return k
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reshardplan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/topo"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestPlan(t *testing.T) {
+	limits := Limits{MaxSizeBytes: 1000, MaxQPS: 100, MergeThreshold: 0.5}
+
+	t.Run("split and merge", func(t *testing.T) {
+		shards := []*vtctldatapb.ShardLoad{
+			{Name: "80-c0", SizeBytes: 100, RowCount: 10, Qps: 10},
+			{Name: "-40", SizeBytes: 2500, RowCount: 300, Qps: 30},
+			{Name: "40-80", SizeBytes: 500, RowCount: 50, Qps: 150},
+			{Name: "c0-e0", SizeBytes: 200, RowCount: 20, Qps: 10},
+			{Name: "e0-", SizeBytes: 300, RowCount: 30, Qps: 20},
+		}
+		proposals, err := Plan("ks", shards, limits)
+		require.NoError(t, err)
+
+		var names []string
+		for _, s := range shards {
+			names = append(names, s.Name)
+		}
+		assert.Equal(t, []string{"-40", "40-80", "80-c0", "c0-e0", "e0-"}, names)
+		assert.Equal(t, 2.5, shards[0].Load)
+		assert.Equal(t, 1.5, shards[1].Load)
+
+		utils.MustMatch(t, []*vtctldatapb.ReshardProposal{{
+			SourceShards: []string{"-40"},
+			TargetShards: []*vtctldatapb.ShardLoad{
+				{Name: "-15", SizeBytes: 833, RowCount: 100, Qps: 10, Load: 2.5 / 3},
+				{Name: "15-2a", SizeBytes: 833, RowCount: 100, Qps: 10, Load: 2.5 / 3},
+				{Name: "2a-40", SizeBytes: 833, RowCount: 100, Qps: 10, Load: 2.5 / 3},
+			},
+			Reshard: &vtctldatapb.ReshardCreateRequest{
+				Workflow:     "split__40",
+				Keyspace:     "ks",
+				SourceShards: []string{"-40"},
+				TargetShards: []string{"-15", "15-2a", "2a-40"},
+			},
+		}, {
+			SourceShards: []string{"40-80"},
+			TargetShards: []*vtctldatapb.ShardLoad{
+				{Name: "40-60", SizeBytes: 250, RowCount: 25, Qps: 75, Load: 0.75},
+				{Name: "60-80", SizeBytes: 250, RowCount: 25, Qps: 75, Load: 0.75},
+			},
+			Reshard: &vtctldatapb.ReshardCreateRequest{
+				Workflow:     "split_40_80",
+				Keyspace:     "ks",
+				SourceShards: []string{"40-80"},
+				TargetShards: []string{"40-60", "60-80"},
+			},
+		}, {
+			// e0- would bring the merge above the threshold.
+			SourceShards: []string{"80-c0", "c0-e0"},
+			TargetShards: []*vtctldatapb.ShardLoad{
+				{Name: "80-e0", SizeBytes: 300, RowCount: 30, Qps: 20, Load: 0.3},
+			},
+			Reshard: &vtctldatapb.ReshardCreateRequest{
+				Workflow:     "merge_80_e0",
+				Keyspace:     "ks",
+				SourceShards: []string{"80-c0", "c0-e0"},
+				TargetShards: []string{"80-e0"},
+			},
+		}}, proposals)
+	})
+
+	t.Run("balanced", func(t *testing.T) {
+		shards := []*vtctldatapb.ShardLoad{
+			{Name: "-80", SizeBytes: 600, Qps: 60},
+			{Name: "80-", SizeBytes: 700, Qps: 40},
+		}
+		proposals, err := Plan("ks", shards, limits)
+		require.NoError(t, err)
+		assert.Empty(t, proposals)
+	})
+
+	t.Run("no merges", func(t *testing.T) {
+		shards := []*vtctldatapb.ShardLoad{
+			{Name: "-80", SizeBytes: 10},
+			{Name: "80-", SizeBytes: 10},
+		}
+		proposals, err := Plan("ks", shards, Limits{MaxSizeBytes: 1000})
+		require.NoError(t, err)
+		assert.Empty(t, proposals)
+	})
+
+	t.Run("unsharded", func(t *testing.T) {
+		shards := []*vtctldatapb.ShardLoad{{Name: "0", SizeBytes: 1500}}
+		proposals, err := Plan("ks", shards, Limits{MaxSizeBytes: 1000})
+		require.NoError(t, err)
+		require.Len(t, proposals, 1)
+		assert.Equal(t, []string{"-80", "80-"}, proposals[0].Reshard.TargetShards)
+		assert.Equal(t, "split_0", proposals[0].Reshard.Workflow)
+	})
+
+	t.Run("overlapping shards", func(t *testing.T) {
+		shards := []*vtctldatapb.ShardLoad{
+			{Name: "-80"},
+			{Name: "-40"},
+			{Name: "40-80"},
+		}
+		_, err := Plan("ks", shards, limits)
+		assert.ErrorContains(t, err, "shards -40 and -80 of keyspace ks overlap")
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		shards := []*vtctldatapb.ShardLoad{{Name: "-"}}
+		_, err := Plan("ks", shards, Limits{})
+		assert.ErrorContains(t, err, "a maximum shard size or QPS is required")
+		_, err = Plan("ks", shards, Limits{MaxQPS: 10, MergeThreshold: 1})
+		assert.ErrorContains(t, err, "the merge threshold must be at least 0 and below 1")
+	})
+
+	t.Run("too many splits", func(t *testing.T) {
+		shards := []*vtctldatapb.ShardLoad{{Name: "-", SizeBytes: 1000}}
+		_, err := Plan("ks", shards, Limits{MaxSizeBytes: 1})
+		assert.ErrorContains(t, err, "cannot split shard ks/-: it would be split into 1000 shards")
+	})
+}
+
+func TestSplitKeyRange(t *testing.T) {
+	testCases := []struct {
+		shard    string
+		n        int
+		expected []string
+	}{
+		{"-", 2, []string{"-80", "80-"}},
+		{"-", 4, []string{"-40", "40-80", "80-c0", "c0-"}},
+		{"-", 3, []string{"-55", "55-aa", "aa-"}},
+		{"80-", 2, []string{"80-c0", "c0-"}},
+		{"80-90", 2, []string{"80-88", "88-90"}},
+		// the key range is too narrow for one byte boundaries.
+		{"80-81", 2, []string{"80-8080", "8080-81"}},
+		{"-01", 3, []string{"-0055", "0055-00aa", "00aa-01"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.shard, func(t *testing.T) {
+			_, kr, err := topo.ValidateShardName(tc.shard)
+			require.NoError(t, err)
+			keyRanges, err := splitKeyRange(kr, tc.n)
+			require.NoError(t, err)
+
+			var shards []string
+			for _, kr := range keyRanges {
+				shards = append(shards, key.KeyRangeString(kr))
+			}
+			assert.Equal(t, tc.expected, shards)
+		})
+	}
+}
@@ -19,13 +19,18 @@ package schematools
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"vitess.io/vitess/go/vt/mysqlctl/tmutils"
+	"vitess.io/vitess/go/vt/schemadiff"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vttablet/tmclient"
 
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
 )
 
 // CompareSchemas returns (nil, nil) if the schema of the two tablets match. If
@@ -55,3 +60,188 @@ func CompareSchemas(
 
 	return tmutils.DiffSchemaToArray("source", sourceSchema, "dest", destSchema), nil
 }
+
+// SchemaDifferences returns the structured differences between the schema of
+// a shard and a reference schema, both given as the CREATE statements of their
+// tables and views. The differences are ordered by table or view name, and
+// their statements are the DDLs that would bring the shard to the reference.
+func SchemaDifferences(env *schemadiff.Environment, shard string, shardSchema []string, referenceSchema []string) ([]*vtctldatapb.SchemaDifference, error) {
+	shardEntities, err := schemadiff.NewSchemaFromQueries(env, shardSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the schema of shard %v: %w", shard, err)
+	}
+	referenceEntities, err := schemadiff.NewSchemaFromQueries(env, referenceSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the reference schema: %w", err)
+	}
+
+	names := append(shardEntities.EntityNames(), referenceEntities.EntityNames()...)
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	hints := schemadiff.EmptyDiffHints()
+	var diffs []*vtctldatapb.SchemaDifference
+	for _, name := range names {
+		from, to := shardEntities.Entity(name), referenceEntities.Entity(name)
+		switch {
+		case from == nil:
+			typ := vtctldatapb.SchemaDifference_MISSING_TABLE
+			if _, ok := to.(*schemadiff.CreateViewEntity); ok {
+				typ = vtctldatapb.SchemaDifference_MISSING_VIEW
+			}
+			diffs = append(diffs, &vtctldatapb.SchemaDifference{
+				Shard:     shard,
+				Name:      name,
+				Type:      typ,
+				Statement: to.Create().CanonicalStatementString(),
+			})
+		case to == nil:
+			typ := vtctldatapb.SchemaDifference_EXTRA_TABLE
+			if _, ok := from.(*schemadiff.CreateViewEntity); ok {
+				typ = vtctldatapb.SchemaDifference_EXTRA_VIEW
+			}
+			diffs = append(diffs, &vtctldatapb.SchemaDifference{
+				Shard:     shard,
+				Name:      name,
+				Type:      typ,
+				Statement: from.Drop().CanonicalStatementString(),
+			})
+		default:
+			entityDiffs, err := entityDifferences(shard, name, from, to, hints)
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, entityDiffs...)
+		}
+	}
+	return diffs, nil
+}
+
+// entityDifferences returns the differences between two versions of a table or
+// a view, a table getting at most one difference per type.
+func entityDifferences(shard string, name string, from schemadiff.Entity, to schemadiff.Entity, hints *schemadiff.DiffHints) ([]*vtctldatapb.SchemaDifference, error) {
+	if isTable(from) != isTable(to) {
+		// A table on one side and a view on the other.
+		return []*vtctldatapb.SchemaDifference{{
+			Shard:     shard,
+			Name:      name,
+			Type:      vtctldatapb.SchemaDifference_OTHER,
+			Statement: to.Create().CanonicalStatementString(),
+		}}, nil
+	}
+
+	entityDiff, err := from.Diff(to, hints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %v of shard %v: %w", name, shard, err)
+	}
+	if entityDiff == nil || entityDiff.IsEmpty() {
+		return nil, nil
+	}
+	if !isTable(to) {
+		return []*vtctldatapb.SchemaDifference{{
+			Shard:     shard,
+			Name:      name,
+			Type:      vtctldatapb.SchemaDifference_VIEW_DEFINITION,
+			Statement: entityDiff.CanonicalStatementString(),
+		}}, nil
+	}
+
+	var (
+		statements []string
+		details    = make(map[vtctldatapb.SchemaDifference_Type][]string)
+	)
+	for d := entityDiff; d != nil && !d.IsEmpty(); d = d.SubsequentDiff() {
+		statements = append(statements, d.CanonicalStatementString())
+		alterTable, ok := d.Statement().(*sqlparser.AlterTable)
+		if !ok {
+			continue
+		}
+		for _, option := range alterTable.AlterOptions {
+			typ, names := classifyAlterOption(option)
+			details[typ] = append(details[typ], names...)
+		}
+		if alterTable.PartitionSpec != nil || alterTable.PartitionOption != nil {
+			details[vtctldatapb.SchemaDifference_OTHER] = append(details[vtctldatapb.SchemaDifference_OTHER], "PARTITION")
+		}
+	}
+	statement := strings.Join(statements, ";\n")
+
+	diffs := make([]*vtctldatapb.SchemaDifference, 0, len(details))
+	for _, typ := range []vtctldatapb.SchemaDifference_Type{
+		vtctldatapb.SchemaDifference_COLUMNS,
+		vtctldatapb.SchemaDifference_INDEXES,
+		vtctldatapb.SchemaDifference_CONSTRAINTS,
+		vtctldatapb.SchemaDifference_OTHER,
+	} {
+		names, ok := details[typ]
+		if !ok {
+			continue
+		}
+		slices.Sort(names)
+		diffs = append(diffs, &vtctldatapb.SchemaDifference{
+			Shard:     shard,
+			Name:      name,
+			Type:      typ,
+			Details:   slices.Compact(names),
+			Statement: statement,
+		})
+	}
+	return diffs, nil
+}
+
+func isTable(entity schemadiff.Entity) bool {
+	_, ok := entity.(*schemadiff.CreateTableEntity)
+	return ok
+}
+
+// classifyAlterOption returns the type of difference an ALTER TABLE option
+// fixes, and the names of the columns, indexes or constraints it changes.
+func classifyAlterOption(option sqlparser.AlterOption) (vtctldatapb.SchemaDifference_Type, []string) {
+	switch option := option.(type) {
+	case *sqlparser.AddColumns:
+		names := make([]string, 0, len(option.Columns))
+		for _, col := range option.Columns {
+			names = append(names, col.Name.String())
+		}
+		return vtctldatapb.SchemaDifference_COLUMNS, names
+	case *sqlparser.DropColumn:
+		return vtctldatapb.SchemaDifference_COLUMNS, []string{option.Name.Name.String()}
+	case *sqlparser.ModifyColumn:
+		return vtctldatapb.SchemaDifference_COLUMNS, []string{option.NewColDefinition.Name.String()}
+	case *sqlparser.ChangeColumn:
+		return vtctldatapb.SchemaDifference_COLUMNS, []string{option.OldColumn.Name.String(), option.NewColDefinition.Name.String()}
+	case *sqlparser.RenameColumn:
+		return vtctldatapb.SchemaDifference_COLUMNS, []string{option.OldName.Name.String(), option.NewName.Name.String()}
+	case *sqlparser.AlterColumn:
+		return vtctldatapb.SchemaDifference_COLUMNS, []string{option.Column.Name.String()}
+	case *sqlparser.AddIndexDefinition:
+		if option.IndexDefinition.Info.Type == sqlparser.IndexTypePrimary {
+			return vtctldatapb.SchemaDifference_INDEXES, []string{"PRIMARY"}
+		}
+		return vtctldatapb.SchemaDifference_INDEXES, []string{option.IndexDefinition.Info.Name.String()}
+	case *sqlparser.RenameIndex:
+		return vtctldatapb.SchemaDifference_INDEXES, []string{option.OldName.String(), option.NewName.String()}
+	case *sqlparser.AlterIndex:
+		return vtctldatapb.SchemaDifference_INDEXES, []string{option.Name.String()}
+	case *sqlparser.DropKey:
+		switch option.Type {
+		case sqlparser.PrimaryKeyType:
+			return vtctldatapb.SchemaDifference_INDEXES, []string{"PRIMARY"}
+		case sqlparser.NormalKeyType:
+			return vtctldatapb.SchemaDifference_INDEXES, []string{option.Name.String()}
+		default:
+			return vtctldatapb.SchemaDifference_CONSTRAINTS, []string{option.Name.String()}
+		}
+	case *sqlparser.AddConstraintDefinition:
+		return vtctldatapb.SchemaDifference_CONSTRAINTS, []string{option.ConstraintDefinition.Name.String()}
+	case *sqlparser.AlterCheck:
+		return vtctldatapb.SchemaDifference_CONSTRAINTS, []string{option.Name.String()}
+	case sqlparser.TableOptions:
+		names := make([]string, 0, len(option))
+		for _, tableOption := range option {
+			names = append(names, tableOption.Name)
+		}
+		return vtctldatapb.SchemaDifference_OTHER, names
+	}
+	return vtctldatapb.SchemaDifference_OTHER, nil
+}
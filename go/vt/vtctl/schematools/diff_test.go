@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schematools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/schemadiff"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestSchemaDifferences(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		shard     []string
+		reference []string
+		expected  []*vtctldatapb.SchemaDifference
+		shouldErr bool
+	}{
+		{
+			name:      "identical",
+			shard:     []string{"create table t1 (id int primary key)", "create view v1 as select id from t1"},
+			reference: []string{"create table t1 (id int primary key)", "create view v1 as select id from t1"},
+		},
+		{
+			name:      "missing and extra tables",
+			shard:     []string{"create table t1 (id int primary key)", "create table t3 (id int primary key)"},
+			reference: []string{"create table t1 (id int primary key)", "create table t2 (id int primary key)"},
+			expected: []*vtctldatapb.SchemaDifference{
+				{
+					Shard:     "-80",
+					Name:      "t2",
+					Type:      vtctldatapb.SchemaDifference_MISSING_TABLE,
+					Statement: "CREATE TABLE `t2` (\n\t`id` int,\n\tPRIMARY KEY (`id`)\n)",
+				},
+				{
+					Shard:     "-80",
+					Name:      "t3",
+					Type:      vtctldatapb.SchemaDifference_EXTRA_TABLE,
+					Statement: "DROP TABLE `t3`",
+				},
+			},
+		},
+		{
+			name:      "columns and indexes",
+			shard:     []string{"create table t1 (id int primary key, a int, c int, key a_idx (a))"},
+			reference: []string{"create table t1 (id int primary key, a bigint, b int, key b_idx (b))"},
+			expected: []*vtctldatapb.SchemaDifference{
+				{
+					Shard:     "-80",
+					Name:      "t1",
+					Type:      vtctldatapb.SchemaDifference_COLUMNS,
+					Details:   []string{"a", "b", "c"},
+					Statement: "ALTER TABLE `t1` DROP KEY `a_idx`, DROP COLUMN `c`, MODIFY COLUMN `a` bigint, ADD COLUMN `b` int, ADD KEY `b_idx` (`b`)",
+				},
+				{
+					Shard:     "-80",
+					Name:      "t1",
+					Type:      vtctldatapb.SchemaDifference_INDEXES,
+					Details:   []string{"a_idx", "b_idx"},
+					Statement: "ALTER TABLE `t1` DROP KEY `a_idx`, DROP COLUMN `c`, MODIFY COLUMN `a` bigint, ADD COLUMN `b` int, ADD KEY `b_idx` (`b`)",
+				},
+			},
+		},
+		{
+			name:      "constraints and options",
+			shard:     []string{"create table t1 (id int primary key, a int) engine=innodb comment='old'"},
+			reference: []string{"create table t1 (id int primary key, a int, constraint a_positive check (a > 0)) engine=innodb comment='new'"},
+			expected: []*vtctldatapb.SchemaDifference{
+				{
+					Shard:     "-80",
+					Name:      "t1",
+					Type:      vtctldatapb.SchemaDifference_CONSTRAINTS,
+					Details:   []string{"a_positive"},
+					Statement: "ALTER TABLE `t1` ADD CONSTRAINT `a_positive` CHECK (`a` > 0), COMMENT 'new'",
+				},
+				{
+					Shard:     "-80",
+					Name:      "t1",
+					Type:      vtctldatapb.SchemaDifference_OTHER,
+					Details:   []string{"comment"},
+					Statement: "ALTER TABLE `t1` ADD CONSTRAINT `a_positive` CHECK (`a` > 0), COMMENT 'new'",
+				},
+			},
+		},
+		{
+			name:      "views",
+			shard:     []string{"create table t1 (id int primary key, a int)", "create view v1 as select id from t1", "create view v3 as select id from t1"},
+			reference: []string{"create table t1 (id int primary key, a int)", "create view v1 as select id, a from t1", "create view v2 as select id from t1"},
+			expected: []*vtctldatapb.SchemaDifference{
+				{
+					Shard:     "-80",
+					Name:      "v1",
+					Type:      vtctldatapb.SchemaDifference_VIEW_DEFINITION,
+					Statement: "ALTER VIEW `v1` AS SELECT `id`, `a` FROM `t1`",
+				},
+				{
+					Shard:     "-80",
+					Name:      "v2",
+					Type:      vtctldatapb.SchemaDifference_MISSING_VIEW,
+					Statement: "CREATE VIEW `v2` AS SELECT `id` FROM `t1`",
+				},
+				{
+					Shard:     "-80",
+					Name:      "v3",
+					Type:      vtctldatapb.SchemaDifference_EXTRA_VIEW,
+					Statement: "DROP VIEW `v3`",
+				},
+			},
+		},
+		{
+			name:      "invalid reference",
+			shard:     []string{"create table t1 (id int primary key)"},
+			reference: []string{"create tabel t1 (id int primary key)"},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			diffs, err := SchemaDifferences(schemadiff.NewTestEnv(), "-80", tt.shard, tt.reference)
+			if tt.shouldErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, diffs)
+		})
+	}
+}
@@ -196,16 +196,20 @@ var commands = []commandGroup{
 				help:   "Deletes tablet(s) from the topology.",
 			},
 			{
-				name:   "SetReadOnly",
-				method: commandSetReadOnly,
-				params: "<tablet alias>",
-				help:   "Sets the tablet as read-only.",
+				name:         "SetReadOnly",
+				method:       commandSetReadOnly,
+				params:       "<tablet alias>",
+				help:         "Sets the tablet as read-only.",
+				deprecated:   true,
+				deprecatedBy: "vtctldclient SetWritable <alias> false",
 			},
 			{
-				name:   "SetReadWrite",
-				method: commandSetReadWrite,
-				params: "<tablet alias>",
-				help:   "Sets the tablet as read-write.",
+				name:         "SetReadWrite",
+				method:       commandSetReadWrite,
+				params:       "<tablet alias>",
+				help:         "Sets the tablet as read-write.",
+				deprecated:   true,
+				deprecatedBy: "vtctldclient SetWritable <alias> true",
 			},
 			{
 				name:   "StartReplication",
@@ -334,10 +338,12 @@ var commands = []commandGroup{
 					"To just remove the ShardTabletControl entirely, use the 'remove' flag.",
 			},
 			{
-				name:   "UpdateSrvKeyspacePartition",
-				method: commandUpdateSrvKeyspacePartition,
-				params: "[--cells=c1,c2,...] [--remove] <keyspace/shard> <tablet type>",
-				help:   "Updates KeyspaceGraph partition for a shard and tablet type. Only use this for emergency fixes. Specify the remove flag, if you want the shard to be removed from the desired partition.",
+				name:         "UpdateSrvKeyspacePartition",
+				method:       commandUpdateSrvKeyspacePartition,
+				params:       "[--cells=c1,c2,...] [--remove] <keyspace/shard> <tablet type>",
+				help:         "Updates KeyspaceGraph partition for a shard and tablet type. Only use this for emergency fixes. Specify the remove flag, if you want the shard to be removed from the desired partition.",
+				deprecated:   true,
+				deprecatedBy: "vtctldclient UpdateSrvKeyspacePartitions",
 			},
 			{
 				name:   "SourceShardDelete",
@@ -372,10 +378,12 @@ var commands = []commandGroup{
 				help:   "Walks through a ShardReplication object and fixes the first error that it encounters.",
 			},
 			{
-				name:   "WaitForFilteredReplication",
-				method: commandWaitForFilteredReplication,
-				params: "[--max_delay <max_delay, default 30s>] <keyspace/shard>",
-				help:   "Blocks until the specified shard has caught up with the filtered replication of its source shard.",
+				name:         "WaitForFilteredReplication",
+				method:       commandWaitForFilteredReplication,
+				params:       "[--max_delay <max_delay, default 30s>] <keyspace/shard>",
+				help:         "Blocks until the specified shard has caught up with the filtered replication of its source shard.",
+				deprecated:   true,
+				deprecatedBy: "vtctldclient WaitForFilteredReplication",
 			},
 			{
 				name:   "RemoveShardCell",
@@ -577,10 +585,12 @@ var commands = []commandGroup{
 				help:   "Reloads the schema on all the tablets in a keyspace.",
 			},
 			{
-				name:   "ValidateSchemaShard",
-				method: commandValidateSchemaShard,
-				params: "[--exclude_tables=''] [--include-views] [--include-vschema] <keyspace/shard>",
-				help:   "Validates that the schema on primary tablet matches all of the replica tablets.",
+				name:         "ValidateSchemaShard",
+				method:       commandValidateSchemaShard,
+				params:       "[--exclude_tables=''] [--include-views] [--include-vschema] <keyspace/shard>",
+				help:         "Validates that the schema on primary tablet matches all of the replica tablets.",
+				deprecated:   true,
+				deprecatedBy: "vtctldclient ValidateSchemaShard",
 			},
 			{
 				name:   "ValidateSchemaKeyspace",
@@ -595,10 +605,12 @@ var commands = []commandGroup{
 				help:   "Applies the schema change to the specified keyspace on every primary, running in parallel on all shards. The changes are then propagated to replicas via replication. -ddl_strategy is used to instruct migrations via vreplication, gh-ost or pt-osc with optional parameters. -migration_context allows the user to specify a custom request context for online DDL migrations.",
 			},
 			{
-				name:   "CopySchemaShard",
-				method: commandCopySchemaShard,
-				params: "[--tables=<table1>,<table2>,...] [--exclude_tables=<table1>,<table2>,...] [--include-views] [--skip-verify] [--wait_replicas_timeout=10s] {<source keyspace/shard> || <source tablet alias>} <destination keyspace/shard>",
-				help:   "Copies the schema from a source shard's primary (or a specific tablet) to a destination shard. The schema is applied directly on the primary of the destination shard, and it is propagated to the replicas through binlogs.",
+				name:         "CopySchemaShard",
+				method:       commandCopySchemaShard,
+				params:       "[--tables=<table1>,<table2>,...] [--exclude_tables=<table1>,<table2>,...] [--include-views] [--skip-verify] [--wait_replicas_timeout=10s] {<source keyspace/shard> || <source tablet alias>} <destination keyspace/shard>",
+				help:         "Copies the schema from a source shard's primary (or a specific tablet) to a destination shard. The schema is applied directly on the primary of the destination shard, and it is propagated to the replicas through binlogs.",
+				deprecated:   true,
+				deprecatedBy: "vtctldclient CopySchemaShard",
 			},
 			{
 				name:   "OnlineDDL",
@@ -646,16 +658,20 @@ var commands = []commandGroup{
 				help:   "Displays the permissions for a tablet.",
 			},
 			{
-				name:   "ValidatePermissionsShard",
-				method: commandValidatePermissionsShard,
-				params: "<keyspace/shard>",
-				help:   "Validates that the permissions on primary match all the replicas.",
+				name:         "ValidatePermissionsShard",
+				method:       commandValidatePermissionsShard,
+				params:       "<keyspace/shard>",
+				help:         "Validates that the permissions on primary match all the replicas.",
+				deprecated:   true,
+				deprecatedBy: "vtctldclient ValidatePermissionsShard",
 			},
 			{
-				name:   "ValidatePermissionsKeyspace",
-				method: commandValidatePermissionsKeyspace,
-				params: "<keyspace name>",
-				help:   "Validates that the permissions on primary of shard 0 match those of all of the other tablets in the keyspace.",
+				name:         "ValidatePermissionsKeyspace",
+				method:       commandValidatePermissionsKeyspace,
+				params:       "<keyspace name>",
+				help:         "Validates that the permissions on primary of shard 0 match those of all of the other tablets in the keyspace.",
+				deprecated:   true,
+				deprecatedBy: "vtctldclient ValidatePermissionsKeyspace",
 			},
 			{
 				name:   "GetVSchema",
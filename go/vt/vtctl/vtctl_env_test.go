@@ -126,8 +126,10 @@ func (env *testVTCtlEnv) addTablet(id int, keyspace, shard string, keyRange *top
 			panic(err)
 		}
 		emptySrvVSchema := &vschemapb.SrvVSchema{
-			RoutingRules:      &vschemapb.RoutingRules{},
-			ShardRoutingRules: &vschemapb.ShardRoutingRules{},
+			RoutingRules:       &vschemapb.RoutingRules{},
+			ShardRoutingRules:  &vschemapb.ShardRoutingRules{},
+			QuotaRules:         &vschemapb.QuotaRules{},
+			TenantRoutingRules: &vschemapb.TenantRoutingRules{},
 		}
 		if err = env.topoServ.UpdateSrvVSchema(ctx, env.cell, emptySrvVSchema); err != nil {
 			panic(err)
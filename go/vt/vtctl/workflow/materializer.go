@@ -158,6 +158,10 @@ func (mz *materializer) generateBinlogSources(ctx context.Context, targetShard *
 			TargetTimeZone:  mz.ms.TargetTimeZone,
 			OnDdl:           binlogdatapb.OnDDLAction(binlogdatapb.OnDDLAction_value[mz.ms.OnDdl]),
 		}
+		if mz.ms.ExternalMysql != "" {
+			bls.ExternalMysql = mz.ms.ExternalMysql
+			bls.ExternalMysqlOptions = mz.externalMysqlOptions(targetShard)
+		}
 		for _, ts := range mz.ms.TableSettings {
 			rule := &binlogdatapb.Rule{
 				Match: ts.TargetTable,
@@ -370,6 +374,9 @@ func (mz *materializer) buildMaterializer() error {
 			}
 		}
 	}
+	if ms.ExternalMysql != "" {
+		return mz.buildExternalMysqlMaterializer(targetVSchema)
+	}
 	isPartial := false
 	sourceShards, err := mz.sourceTs.GetServingShards(ctx, ms.SourceKeyspace)
 	if err != nil {
@@ -435,6 +442,45 @@ func (mz *materializer) buildMaterializer() error {
 	return nil
 }
 
+// buildExternalMysqlMaterializer builds the materializer of a workflow whose
+// source is an external mysql. The external mysql is streamed from as if it
+// were the only shard of the source keyspace, by every target shard.
+func (mz *materializer) buildExternalMysqlMaterializer(targetVSchema *vindexes.KeyspaceSchema) error {
+	ms := mz.ms
+	targetShards, err := mz.ts.GetServingShards(mz.ctx, ms.TargetKeyspace)
+	if err != nil {
+		return err
+	}
+	if len(targetShards) == 0 {
+		return fmt.Errorf("no target shards specified for workflow %s ", ms.Workflow)
+	}
+
+	mz.targetVSchema = targetVSchema
+	mz.sourceShards = []*topo.ShardInfo{topo.NewShardInfo(ms.SourceKeyspace, "0", &topodatapb.Shard{}, nil)}
+	mz.targetShards = targetShards
+	// All the target shards stream from the external mysql.
+	mz.primaryVindexesDiffer = true
+	return nil
+}
+
+// externalMysqlOptions returns the options of the connection to the external
+// mysql for the stream of the target shard. Each stream gets its own server
+// id, as a mysql server drops the replicas which connect with an id in use.
+func (mz *materializer) externalMysqlOptions(targetShard *topo.ShardInfo) *binlogdatapb.ExternalMysqlOptions {
+	opts := mz.ms.ExternalMysqlOptions
+	if opts == nil || opts.ServerId == 0 {
+		return opts
+	}
+	opts = opts.CloneVT()
+	for i, shard := range mz.targetShards {
+		if shard.ShardName() == targetShard.ShardName() {
+			opts.ServerId += uint32(i)
+			break
+		}
+	}
+	return opts
+}
+
 func (mz *materializer) startStreams(ctx context.Context) error {
 	return forAllShards(mz.targetShards, func(target *topo.ShardInfo) error {
 		targetPrimary, err := mz.ts.GetTablet(ctx, target.PrimaryAlias)
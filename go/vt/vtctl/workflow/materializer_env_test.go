@@ -175,6 +175,8 @@ type testMaterializerTMClient struct {
 
 	// Used to confirm the number of times WorkflowDelete was called.
 	workflowDeleteCalls int
+	// The tags of the workflows, by workflow name.
+	workflowTags map[string]string
 }
 
 func newTestMaterializerTMClient() *testMaterializerTMClient {
@@ -182,6 +184,7 @@ func newTestMaterializerTMClient() *testMaterializerTMClient {
 		schema:                             make(map[string]*tabletmanagerdatapb.SchemaDefinition),
 		vrQueries:                          make(map[int][]*queryResult),
 		createVReplicationWorkflowRequests: make(map[uint32]*tabletmanagerdatapb.CreateVReplicationWorkflowRequest),
+		workflowTags:                       make(map[string]string),
 	}
 }
 
@@ -200,9 +203,13 @@ func (tmc *testMaterializerTMClient) ReadVReplicationWorkflow(ctx context.Contex
 	if strings.Contains(request.Workflow, "lookup") {
 		workflowType = binlogdatapb.VReplicationWorkflowType_CreateLookupIndex
 	}
+	tmc.mu.Lock()
+	tags := tmc.workflowTags[request.Workflow]
+	tmc.mu.Unlock()
 	return &tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
 		Workflow:     request.Workflow,
 		WorkflowType: workflowType,
+		Tags:         tags,
 		Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{
 			{
 				Id: 1,
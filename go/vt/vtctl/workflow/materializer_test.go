@@ -41,6 +41,7 @@ import (
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
 )
 
 const (
@@ -490,7 +491,7 @@ func TestMoveTablesDDLFlag(t *testing.T) {
 			require.NoError(t, err)
 			sourceShard, err := env.topoServ.GetShardNames(ctx, ms.SourceKeyspace)
 			require.NoError(t, err)
-			want := fmt.Sprintf("shard_streams:{key:\"%s/%s\" value:{streams:{id:1 tablet:{cell:\"%s\" uid:200} source_shard:\"%s/%s\" position:\"%s\" status:\"Running\" info:\"VStream Lag: 0s\"}}} traffic_state:\"Reads Not Switched. Writes Not Switched\"",
+			want := fmt.Sprintf("shard_streams:{key:\"%s/%s\" value:{streams:{id:1 tablet:{cell:\"%s\" uid:200} source_shard:\"%s/%s\" position:\"%s\" status:\"Running\" info:\"VStream Lag: 0s\" replication_lag:{}}}} traffic_state:\"Reads Not Switched. Writes Not Switched\" max_replication_lag:{}",
 				ms.TargetKeyspace, targetShard[0], env.cell, ms.SourceKeyspace, sourceShard[0], position)
 
 			res, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
@@ -549,15 +550,17 @@ func TestMoveTablesNoRoutingRules(t *testing.T) {
 							Cell: env.cell,
 							Uid:  200,
 						},
-						SourceShard: fmt.Sprintf("%s/%s", ms.SourceKeyspace, sourceShard[0]),
-						Position:    position,
-						Status:      binlogdatapb.VReplicationWorkflowState_Running.String(),
-						Info:        "VStream Lag: 0s",
+						SourceShard:    fmt.Sprintf("%s/%s", ms.SourceKeyspace, sourceShard[0]),
+						Position:       position,
+						Status:         binlogdatapb.VReplicationWorkflowState_Running.String(),
+						Info:           "VStream Lag: 0s",
+						ReplicationLag: &vttimepb.Duration{},
 					},
 				},
 			},
 		},
-		TrafficState: "Reads Not Switched. Writes Not Switched",
+		TrafficState:      "Reads Not Switched. Writes Not Switched",
+		MaxReplicationLag: &vttimepb.Duration{},
 	}
 
 	res, err := env.ws.MoveTablesCreate(ctx, &vtctldatapb.MoveTablesCreateRequest{
@@ -2459,3 +2462,135 @@ func TestKeyRangesEqualOptimization(t *testing.T) {
 		})
 	}
 }
+
+// TestExternalMysqlStreams tests the streams of a workflow whose source is an
+// external mysql: every target shard streams from it, with its own server id.
+func TestExternalMysqlStreams(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	workflow := "testwf"
+	sourceKs := "sourceks"
+	targetKs := "targetks"
+	table := "t1"
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:              workflow,
+		MaterializationIntent: vtctldatapb.MaterializationIntent_MOVETABLES,
+		SourceKeyspace:        sourceKs,
+		TargetKeyspace:        targetKs,
+		ExternalMysql:         "ext1",
+		ExternalMysqlOptions: &binlogdatapb.ExternalMysqlOptions{
+			UseFilePosition: true,
+			ServerId:        1000,
+			SslMode:         "verify_identity",
+		},
+		TableSettings: []*vtctldatapb.TableMaterializeSettings{{
+			TargetTable:      table,
+			SourceExpression: fmt.Sprintf("select * from %s", table),
+		}},
+	}
+	env := newTestMaterializerEnv(t, ctx, ms, nil, []string{"-80", "80-"})
+	defer env.close()
+	err := env.ws.ts.SaveVSchema(ctx, targetKs, &vschemapb.Keyspace{
+		Sharded: true,
+		Vindexes: map[string]*vschemapb.Vindex{
+			"xxhash": {
+				Type: "xxhash",
+			},
+		},
+		Tables: map[string]*vschemapb.Table{
+			table: {
+				ColumnVindexes: []*vschemapb.ColumnVindex{
+					{
+						Column: "id",
+						Name:   "xxhash",
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	for uid, shard := range map[uint32]string{200: "-80", 210: "80-"} {
+		env.tmc.expectCreateVReplicationWorkflowRequest(uid, &tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+			Workflow:     workflow,
+			WorkflowType: binlogdatapb.VReplicationWorkflowType_Migrate,
+			BinlogSource: []*binlogdatapb.BinlogSource{
+				{
+					Keyspace:      sourceKs,
+					Shard:         "0",
+					ExternalMysql: "ext1",
+					ExternalMysqlOptions: &binlogdatapb.ExternalMysqlOptions{
+						UseFilePosition: true,
+						ServerId:        1000 + (uid-200)/10,
+						SslMode:         "verify_identity",
+					},
+					Filter: &binlogdatapb.Filter{
+						Rules: []*binlogdatapb.Rule{
+							{
+								Match:  table,
+								Filter: fmt.Sprintf("select * from %s where in_keyrange(id, '%s.xxhash', '%s')", table, targetKs, shard),
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	mz := &materializer{
+		ctx:          ctx,
+		ts:           env.ws.ts,
+		sourceTs:     env.ws.ts,
+		tmc:          env.tmc,
+		ms:           ms,
+		workflowType: binlogdatapb.VReplicationWorkflowType_Migrate,
+		env:          vtenv.NewTestEnv(),
+	}
+	err = mz.createWorkflowStreams(&tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+		Workflow:     workflow,
+		WorkflowType: binlogdatapb.VReplicationWorkflowType_Migrate,
+	})
+	require.NoError(t, err)
+	env.tmc.verifyQueries(t)
+
+	// The schema of an external mysql can't be copied, so the target tables
+	// must already exist.
+	delete(env.tmc.schema, targetKs+"."+table)
+	ms.TableSettings[0].CreateDdl = ""
+	err = mz.createWorkflowStreams(&tabletmanagerdatapb.CreateVReplicationWorkflowRequest{
+		Workflow:     workflow,
+		WorkflowType: binlogdatapb.VReplicationWorkflowType_Migrate,
+	})
+	require.ErrorContains(t, err, "target table t1 does not exist and there is no create ddl defined")
+}
+
+func TestMigrateExternalMysqlValidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "workflow",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+	}
+	env := newTestMaterializerEnv(t, ctx, ms, nil, []string{"0"})
+	defer env.close()
+
+	_, err := env.ws.MigrateCreate(ctx, &vtctldatapb.MigrateCreateRequest{
+		Workflow:       ms.Workflow,
+		SourceKeyspace: ms.SourceKeyspace,
+		TargetKeyspace: ms.TargetKeyspace,
+		ExternalMysql:  "ext1",
+		AllTables:      true,
+	})
+	require.ErrorContains(t, err, "the tables to move must be specified when the source is an external mysql")
+
+	_, err = env.ws.MigrateCreate(ctx, &vtctldatapb.MigrateCreateRequest{
+		Workflow:       ms.Workflow,
+		SourceKeyspace: ms.SourceKeyspace,
+		TargetKeyspace: ms.TargetKeyspace,
+		MountName:      "ext1",
+		ExternalMysql:  "ext1",
+		IncludeTables:  []string{"t1"},
+	})
+	require.ErrorContains(t, err, "only one of an external cluster and an external mysql can be the source of a workflow")
+}
@@ -19,7 +19,9 @@ package workflow
 import (
 	"context"
 
+	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vterrors"
 
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
@@ -46,9 +48,47 @@ func (s *Server) MountRegister(ctx context.Context, req *vtctldatapb.MountRegist
 			Root:     req.TopoRoot,
 		},
 	}
+	secrets, err := mountSecrets(req)
+	if err != nil {
+		return &vtctldatapb.MountRegisterResponse{}, err
+	}
+	if secrets != nil {
+		vc.EncryptedSecrets, err = topo.EncryptExternalClusterSecrets(secrets)
+		if err != nil {
+			return &vtctldatapb.MountRegisterResponse{},
+				vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "failed to encrypt the secrets of external vitess cluster in MountRegister: %v", err)
+		}
+	}
 	return &vtctldatapb.MountRegisterResponse{}, s.ts.CreateExternalVitessCluster(ctx, req.Name, vc)
 }
 
+// mountSecrets returns the secrets to connect to the topo server of the
+// external cluster of a MountRegister request, or nil if it has none. The
+// credentials are looked up in the credentials server, so that only their
+// reference is part of the request.
+func mountSecrets(req *vtctldatapb.MountRegisterRequest) (*topodata.ExternalClusterSecrets, error) {
+	if req.TlsCert == "" && req.TlsKey == "" && req.TlsCa == "" && req.CredentialsUser == "" {
+		return nil, nil
+	}
+	if (req.TlsCert == "") != (req.TlsKey == "") {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the TLS client certificate and key of external vitess cluster %s must be set together", req.Name)
+	}
+	secrets := &topodata.ExternalClusterSecrets{
+		TlsCert:       req.TlsCert,
+		TlsKey:        req.TlsKey,
+		TlsCa:         req.TlsCa,
+		TlsServerName: req.TlsServerName,
+	}
+	if req.CredentialsUser != "" {
+		user, password, err := dbconfigs.GetCredentialsServer().GetUserAndPassword(req.CredentialsUser)
+		if err != nil {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "failed to get the credentials of user %s for external vitess cluster %s: %v", req.CredentialsUser, req.Name, err)
+		}
+		secrets.Username, secrets.Password = user, password
+	}
+	return secrets, nil
+}
+
 func (s *Server) MountUnregister(ctx context.Context, req *vtctldatapb.MountUnregisterRequest) (*vtctldatapb.MountUnregisterResponse, error) {
 	vci, err := s.ts.GetExternalVitessCluster(ctx, req.Name)
 	if err != nil {
@@ -79,10 +119,20 @@ func (s *Server) MountShow(ctx context.Context, req *vtctldatapb.MountShowReques
 	if vci == nil {
 		return &vtctldatapb.MountShowResponse{}, notExistsError(req.Name)
 	}
-	return &vtctldatapb.MountShowResponse{
+	resp := &vtctldatapb.MountShowResponse{
 		TopoType:   vci.TopoConfig.TopoType,
 		TopoServer: vci.TopoConfig.Server,
 		TopoRoot:   vci.TopoConfig.Root,
 		Name:       req.Name,
-	}, nil
+	}
+	if len(vci.EncryptedSecrets) > 0 {
+		secrets, err := topo.DecryptExternalClusterSecrets(vci.EncryptedSecrets)
+		if err != nil {
+			return &vtctldatapb.MountShowResponse{},
+				vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "failed to decrypt the secrets of external vitess cluster in MountShow: %v", err)
+		}
+		resp.Tls = secrets.TlsCert != "" || secrets.TlsCa != ""
+		resp.Username = secrets.Username
+	}
+	return resp, nil
 }
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/test/utils"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func TestMountSecrets(t *testing.T) {
+	secrets, err := mountSecrets(&vtctldatapb.MountRegisterRequest{Name: "ext1"})
+	require.NoError(t, err)
+	require.Nil(t, secrets)
+
+	_, err = mountSecrets(&vtctldatapb.MountRegisterRequest{Name: "ext1", TlsCert: "cert"})
+	require.ErrorContains(t, err, "must be set together")
+
+	secrets, err = mountSecrets(&vtctldatapb.MountRegisterRequest{
+		Name:          "ext1",
+		TlsCert:       "cert",
+		TlsKey:        "key",
+		TlsCa:         "ca",
+		TlsServerName: "etcd.dc2",
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, &topodatapb.ExternalClusterSecrets{
+		TlsCert:       "cert",
+		TlsKey:        "key",
+		TlsCa:         "ca",
+		TlsServerName: "etcd.dc2",
+	}, secrets)
+}
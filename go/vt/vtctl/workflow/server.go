@@ -24,6 +24,7 @@ import (
 	"reflect"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
@@ -113,6 +114,13 @@ const (
 	cannotSwitchHighLag             = "replication lag %ds is higher than allowed lag %ds"
 	cannotSwitchFailedTabletRefresh = "could not refresh all of the tablets involved in the operation:\n%s"
 	cannotSwitchFrozen              = "workflow is frozen"
+	cannotSwitchReverseNotRunning   = "reverse replication stream %d on %s is not running (state: %s)"
+	cannotReverseWindowExpired      = "writes were switched %v ago which is more than the allowed %v"
+	cannotRemoveRetained            = "the %s workflow in the %s keyspace is retained until the end of its rollback window at %v"
+
+	// rollbackRetentionTagPrefix prefixes the tag of the reverse workflow streams
+	// recording until when they are retained, as a unix timestamp.
+	rollbackRetentionTagPrefix = "rollback_retention_until:"
 
 	// Number of LOCK TABLES cycles to perform on the sources during SwitchWrites.
 	lockTablesCycles = 2
@@ -121,6 +129,10 @@ const (
 
 	// Default duration used for lag, timeout, etc.
 	defaultDuration = 30 * time.Second
+
+	// A stream is reported as throttled in the workflow status if it was
+	// throttled within this period.
+	throttledRecency = 5 * time.Second
 )
 
 var (
@@ -163,6 +175,11 @@ func (s *Server) SQLParser() *sqlparser.Parser {
 	return s.env.Parser()
 }
 
+// Environment returns the environment used by the workflow server.
+func (s *Server) Environment() *vtenv.Environment {
+	return s.env
+}
+
 // CheckReshardingJournalExistsOnTablet returns the journal (or an empty
 // journal) and a boolean to indicate if the resharding_journal table exists on
 // the given tablet.
@@ -541,6 +558,7 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 				Message:                   rstream.Message,
 				Tags:                      strings.Split(res.Tags, ","),
 				RowsCopied:                rstream.RowsCopied,
+				TimeHeartbeat:             rstream.TimeHeartbeat,
 				ThrottlerStatus: &vtctldatapb.Workflow_Stream_ThrottlerStatus{
 					ComponentThrottled: rstream.ComponentThrottled,
 					TimeThrottled:      rstream.TimeThrottled,
@@ -623,37 +641,13 @@ func (s *Server) GetWorkflows(ctx context.Context, req *vtctldatapb.GetWorkflows
 			workflow.DeferSecondaryKeys = res.DeferSecondaryKeys
 
 			// MaxVReplicationTransactionLag estimates the actual statement processing lag
-			// between the source and the target. If we are still processing source events it
-			// is the difference b/w current time and the timestamp of the last event. If
-			// heartbeats are more recent than the last event, then the lag is the time since
-			// the last heartbeat as there can be an actual event immediately after the
-			// heartbeat, but which has not yet been processed on the target.
-			// We don't allow switching during the copy phase, so in that case we just return
-			// a large lag. All timestamps are in seconds since epoch.
+			// between the source and the target.
 			if _, ok := maxVReplicationTransactionLagByWorkflow[workflow.Name]; !ok {
 				maxVReplicationTransactionLagByWorkflow[workflow.Name] = 0
 			}
-			if rstream.TransactionTimestamp == nil {
-				rstream.TransactionTimestamp = &vttimepb.Time{}
-			}
-			lastTransactionTime := rstream.TransactionTimestamp.Seconds
-			if rstream.TimeHeartbeat == nil {
-				rstream.TimeHeartbeat = &vttimepb.Time{}
-			}
-			lastHeartbeatTime := rstream.TimeHeartbeat.Seconds
-			if stream.State == binlogdatapb.VReplicationWorkflowState_Copying.String() {
-				maxVReplicationTransactionLagByWorkflow[workflow.Name] = math.MaxInt64
-			} else {
-				if lastTransactionTime == 0 /* no new events after copy */ ||
-					lastHeartbeatTime > lastTransactionTime /* no recent transactions, so all caught up */ {
-
-					lastTransactionTime = lastHeartbeatTime
-				}
-				now := time.Now().Unix() /* seconds since epoch */
-				transactionReplicationLag := float64(now - lastTransactionTime)
-				if transactionReplicationLag > maxVReplicationTransactionLagByWorkflow[workflow.Name] {
-					maxVReplicationTransactionLagByWorkflow[workflow.Name] = transactionReplicationLag
-				}
+			transactionReplicationLag, ok := vreplicationTransactionLag(stream.State, stream.Message, rstream.TransactionTimestamp, rstream.TimeHeartbeat, time.Now())
+			if ok && transactionReplicationLag > maxVReplicationTransactionLagByWorkflow[workflow.Name] {
+				maxVReplicationTransactionLagByWorkflow[workflow.Name] = transactionReplicationLag
 			}
 		}
 
@@ -1297,6 +1291,9 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		sourceTopo   = s.ts
 	)
 
+	if req.ExternalClusterName != "" && req.ExternalMysql != "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "only one of an external cluster and an external mysql can be the source of a workflow")
+	}
 	// When the source is an external cluster mounted using the Mount command.
 	if req.ExternalClusterName != "" {
 		externalTopo, err = s.ts.OpenExternalVitessClusterServer(ctx, req.ExternalClusterName)
@@ -1306,6 +1303,18 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		sourceTopo = externalTopo
 		log.Infof("Successfully opened external topo: %+v", externalTopo)
 	}
+	// When the source is an external mysql, vtctld has no way to look at its
+	// schema: the tables must be listed explicitly and must already exist on
+	// the target.
+	externalMysql := req.ExternalMysql != ""
+	if externalMysql {
+		if len(tables) == 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the tables to move must be specified when the source is an external mysql")
+		}
+		if len(req.SourceShards) > 0 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "source shards cannot be specified when the source is an external mysql")
+		}
+	}
 
 	var vschema *vschemapb.Keyspace
 	var origVSchema *vschemapb.Keyspace // If we need to rollback a failed create
@@ -1316,26 +1325,28 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	if vschema == nil {
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no vschema found for target keyspace %s", targetKeyspace)
 	}
-	ksTables, err := getTablesInKeyspace(ctx, sourceTopo, s.tmc, sourceKeyspace)
-	if err != nil {
-		return nil, err
-	}
-	if len(tables) > 0 {
-		err = s.validateSourceTablesExist(ctx, sourceKeyspace, ksTables, tables)
+	if !externalMysql {
+		ksTables, err := getTablesInKeyspace(ctx, sourceTopo, s.tmc, sourceKeyspace)
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		if req.AllTables {
-			tables = ksTables
+		if len(tables) > 0 {
+			err = s.validateSourceTablesExist(ctx, sourceKeyspace, ksTables, tables)
+			if err != nil {
+				return nil, err
+			}
 		} else {
-			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no tables to move")
+			if req.AllTables {
+				tables = ksTables
+			} else {
+				return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no tables to move")
+			}
 		}
-	}
-	if len(req.ExcludeTables) > 0 {
-		err = s.validateSourceTablesExist(ctx, sourceKeyspace, ksTables, req.ExcludeTables)
-		if err != nil {
-			return nil, err
+		if len(req.ExcludeTables) > 0 {
+			err = s.validateSourceTablesExist(ctx, sourceKeyspace, ksTables, req.ExcludeTables)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	var tables2 []string
@@ -1354,7 +1365,7 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		// Save the original in case we need to restore it for a late failure
 		// in the defer().
 		origVSchema = vschema.CloneVT()
-		if err := s.addTablesToVSchema(ctx, sourceKeyspace, vschema, tables, externalTopo == nil); err != nil {
+		if err := s.addTablesToVSchema(ctx, sourceKeyspace, vschema, tables, externalTopo == nil && !externalMysql); err != nil {
 			return nil, err
 		}
 	}
@@ -1368,6 +1379,8 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		TabletSelectionPreference: req.TabletSelectionPreference,
 		StopAfterCopy:             req.StopAfterCopy,
 		ExternalCluster:           req.ExternalClusterName,
+		ExternalMysql:             req.ExternalMysql,
+		ExternalMysqlOptions:      req.ExternalMysqlOptions,
 		SourceShards:              req.SourceShards,
 		OnDdl:                     req.OnDdl,
 		DeferSecondaryKeys:        req.DeferSecondaryKeys,
@@ -1381,6 +1394,10 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 	if req.DropForeignKeys {
 		createDDLMode = createDDLAsCopyDropForeignKeys
 	}
+	if externalMysql {
+		// The schema of the source can't be copied.
+		createDDLMode = ""
+	}
 
 	for _, table := range tables {
 		buf := sqlparser.NewTrackedBuffer(nil)
@@ -1436,7 +1453,7 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 
 	// Now that the streams have been successfully created, let's put the associated
 	// routing rules in place.
-	if externalTopo == nil {
+	if externalTopo == nil && !externalMysql {
 		if req.NoRoutingRules {
 			log.Warningf("Found --no-routing-rules flag, not creating routing rules for workflow %s.%s", targetKeyspace, req.Workflow)
 		} else {
@@ -1494,7 +1511,7 @@ func (s *Server) moveTablesCreate(ctx context.Context, req *vtctldatapb.MoveTabl
 		return nil, err
 	}
 
-	if mz.ms.ExternalCluster == "" {
+	if mz.ms.ExternalCluster == "" && mz.ms.ExternalMysql == "" {
 		exists, tablets, err := s.checkIfPreviousJournalExists(ctx, mz, migrationID)
 		if err != nil {
 			return nil, err
@@ -1560,6 +1577,9 @@ func (s *Server) MoveTablesComplete(ctx context.Context, req *vtctldatapb.MoveTa
 	if !state.WritesSwitched || len(state.ReplicaCellsNotSwitched) > 0 || len(state.RdonlyCellsNotSwitched) > 0 {
 		return nil, ErrWorkflowNotFullySwitched
 	}
+	if err := s.checkRollbackRetention(ctx, ts); err != nil {
+		return nil, err
+	}
 	var renameTable TableRemovalType
 	if req.RenameTables {
 		renameTable = RenameTable
@@ -1930,7 +1950,14 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 	resp := &vtctldatapb.WorkflowStatusResponse{
 		TrafficState: state.String(),
 	}
+	// We only need the stream logs to estimate the copy rate.
+	workflow, err := s.GetWorkflow(ctx, req.Keyspace, req.Workflow, copyProgress != nil, req.Shards)
+	if err != nil {
+		return nil, err
+	}
 	if copyProgress != nil {
+		copyRate := workflowCopyRate(workflow, time.Now())
+		var rowsCopied, rowsTotal int64
 		resp.TableCopyState = make(map[string]*vtctldatapb.WorkflowStatusResponse_TableCopyState, len(*copyProgress))
 		// We sort the tables for intuitive and consistent output.
 		var tables []string
@@ -1955,12 +1982,18 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 			resp.TableCopyState[table].BytesCopied = progress.TargetTableSize
 			resp.TableCopyState[table].BytesTotal = progress.SourceTableSize
 			resp.TableCopyState[table].BytesPercentage = tableSizePct
+			if eta, ok := estimateCopyETA(progress.SourceRowCount-progress.TargetRowCount, copyRate); ok {
+				resp.TableCopyState[table].Eta = protoutil.DurationToProto(eta)
+			}
+			rowsCopied += progress.TargetRowCount
+			rowsTotal += progress.SourceRowCount
+		}
+		if rowsTotal > 0 {
+			resp.CopyPercentage = float32(100.0 * float64(rowsCopied) / float64(rowsTotal))
+		}
+		if eta, ok := estimateCopyETA(rowsTotal-rowsCopied, copyRate); ok {
+			resp.Eta = protoutil.DurationToProto(eta)
 		}
-	}
-
-	workflow, err := s.GetWorkflow(ctx, req.Keyspace, req.Workflow, false, req.Shards)
-	if err != nil {
-		return nil, err
 	}
 
 	// The stream key is target keyspace/tablet alias, e.g. 0/test-0000000100.
@@ -1971,6 +2004,7 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 	}
 	sort.Strings(streamKeys)
 	resp.ShardStreams = make(map[string]*vtctldatapb.WorkflowStatusResponse_ShardStreams, len(streamKeys))
+	var maxLag time.Duration
 	for _, streamKey := range streamKeys {
 		streams := workflow.ShardStreams[streamKey].GetStreams()
 		keyParts := strings.Split(streamKey, "/")
@@ -2011,13 +2045,110 @@ func (s *Server) WorkflowStatus(ctx context.Context, req *vtctldatapb.WorkflowSt
 			ts.Position = st.Position
 			ts.Status = st.State
 			ts.Info = strings.Join(info, "; ")
+			if st.State != binlogdatapb.VReplicationWorkflowState_Copying.String() {
+				if lagSeconds, ok := vreplicationTransactionLag(st.State, st.Message, st.TransactionTimestamp, st.TimeHeartbeat, time.Now()); ok {
+					lag := time.Duration(lagSeconds) * time.Second
+					ts.ReplicationLag = protoutil.DurationToProto(lag)
+					if lag > maxLag {
+						maxLag = lag
+					}
+				}
+			}
+			if throttled := st.GetThrottlerStatus(); throttled != nil && throttled.TimeThrottled != nil &&
+				time.Since(protoutil.TimeFromProto(throttled.TimeThrottled)) <= throttledRecency {
+				ts.Throttled = true
+				ts.ComponentThrottled = throttled.ComponentThrottled
+			}
 			resp.ShardStreams[ksShard].Streams[i] = ts
 		}
 	}
+	resp.MaxReplicationLag = protoutil.DurationToProto(maxLag)
 
 	return resp, nil
 }
 
+// vreplicationTransactionLag estimates the actual statement processing lag, in
+// seconds, between the source and the target of a stream in the given state. If
+// we are still processing source events it is the difference b/w now and the
+// timestamp of the last event. If heartbeats are more recent than the last
+// event, then the lag is the time since the last heartbeat as there can be an
+// actual event immediately after the heartbeat, but which has not yet been
+// processed on the target.
+// We don't allow switching during the copy phase, so in that case we just
+// return a large lag. A frozen stream has nothing left to replicate, so its lag
+// is 0. The lag is unknown, and ok is false, when the stream is not running or
+// when it has neither replicated a transaction nor received a heartbeat. All
+// timestamps are in seconds since epoch.
+func vreplicationTransactionLag(state, message string, transactionTimestamp, timeHeartbeat *vttimepb.Time, now time.Time) (lag float64, ok bool) {
+	if message == Frozen {
+		return 0, true
+	}
+	switch state {
+	case binlogdatapb.VReplicationWorkflowState_Copying.String():
+		return math.MaxInt64, true
+	case binlogdatapb.VReplicationWorkflowState_Running.String(), binlogdatapb.VReplicationWorkflowState_Lagging.String():
+	default:
+		return 0, false
+	}
+	lastTransactionTime := transactionTimestamp.GetSeconds()
+	lastHeartbeatTime := timeHeartbeat.GetSeconds()
+	if lastTransactionTime == 0 /* no new events after copy */ ||
+		lastHeartbeatTime > lastTransactionTime /* no recent transactions, so all caught up */ {
+
+		lastTransactionTime = lastHeartbeatTime
+	}
+	if lastTransactionTime == 0 {
+		return 0, false
+	}
+	return float64(now.Unix() - lastTransactionTime), true
+}
+
+// workflowCopyRate returns the rate, in rows per second, at which the
+// workflow's streams have been copying rows since their copy phase started.
+// The start of the copy phase is taken from the stream logs, so they must
+// have been fetched along with the workflow. It returns 0 if no rate can be
+// determined.
+func workflowCopyRate(workflow *vtctldatapb.Workflow, now time.Time) float64 {
+	var (
+		rowsCopied int64
+		copyStart  time.Time
+	)
+	for _, shardStreams := range workflow.GetShardStreams() {
+		for _, st := range shardStreams.GetStreams() {
+			rowsCopied += st.RowsCopied
+			for _, l := range st.GetLogs() {
+				if l.Type != vreplication.LogCopyStart || l.CreatedAt == nil {
+					continue
+				}
+				if createdAt := protoutil.TimeFromProto(l.CreatedAt); copyStart.IsZero() || createdAt.Before(copyStart) {
+					copyStart = createdAt
+				}
+			}
+		}
+	}
+	if rowsCopied == 0 || copyStart.IsZero() {
+		return 0
+	}
+	elapsed := now.Sub(copyStart).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(rowsCopied) / elapsed
+}
+
+// estimateCopyETA returns the estimated time needed to copy the remaining
+// rows at the given rate, in rows per second. The boolean is false when no
+// estimate can be made.
+func estimateCopyETA(rowsRemaining int64, rate float64) (time.Duration, bool) {
+	if rate <= 0 {
+		return 0, false
+	}
+	if rowsRemaining <= 0 {
+		return 0, true
+	}
+	return (time.Duration(float64(rowsRemaining)/rate) * time.Second).Truncate(time.Second), true
+}
+
 // GetCopyProgress returns the progress of all tables being copied in the
 // workflow.
 func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state *State) (*copyProgress, error) {
@@ -2043,6 +2174,10 @@ func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state
 			for i := 0; i < len(p3qr.Rows); i++ {
 				tables[qr.Rows[i][0].ToString()] = true
 			}
+			if bls.ExternalMysql != "" {
+				// Only the target side of the progress is known for an external mysql.
+				continue
+			}
 			sourcesi, err := s.ts.GetShard(ctx, bls.Keyspace, bls.Shard)
 			if err != nil {
 				return nil, err
@@ -2104,7 +2239,7 @@ func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state
 		sourceDbName = tsSource.GetPrimary().DbName()
 		break
 	}
-	if sourceDbName == "" {
+	if sourceDbName == "" && ts.externalMysql == "" {
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "no sources found for workflow %s.%s", state.TargetKeyspace, state.Workflow)
 	}
 	targetDbName := ""
@@ -2112,7 +2247,7 @@ func (s *Server) GetCopyProgress(ctx context.Context, ts *trafficSwitcher, state
 		targetDbName = tsTarget.GetPrimary().DbName()
 		break
 	}
-	if sourceDbName == "" || targetDbName == "" {
+	if (sourceDbName == "" && ts.externalMysql == "") || targetDbName == "" {
 		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "workflow %s.%s is incorrectly configured", state.TargetKeyspace, state.Workflow)
 	}
 	sort.Strings(tableList) // sort list for repeatability for mocking in tests
@@ -2417,6 +2552,9 @@ func (s *Server) DropTargets(ctx context.Context, targetKeyspace, workflow strin
 	if state.WritesSwitched || len(state.ReplicaCellsSwitched) > 0 || len(state.RdonlyCellsSwitched) > 0 {
 		return nil, ErrWorkflowPartiallySwitched
 	}
+	if err := s.checkRollbackRetention(ctx, ts); err != nil {
+		return nil, err
+	}
 
 	if state.WorkflowType == TypeMigrate {
 		_, err := s.finalizeMigrateWorkflow(ctx, targetKeyspace, workflow, "", true, keepData, keepRoutingRules, dryRun)
@@ -2509,6 +2647,7 @@ func (s *Server) buildTrafficSwitcher(ctx context.Context, targetKeyspace, workf
 				ts.sourceTimeZone = bls.SourceTimeZone
 				ts.targetTimeZone = bls.TargetTimeZone
 				ts.externalCluster = bls.ExternalCluster
+				ts.externalMysql = bls.ExternalMysql
 				if ts.externalCluster != "" {
 					externalTopo, err := s.ts.OpenExternalVitessClusterServer(ctx, ts.externalCluster)
 					if err != nil {
@@ -2537,7 +2676,8 @@ func (s *Server) buildTrafficSwitcher(ctx context.Context, targetKeyspace, workf
 				}
 			}
 
-			if _, ok := ts.sources[bls.Shard]; ok {
+			if _, ok := ts.sources[bls.Shard]; ok || bls.ExternalMysql != "" {
+				// An external mysql is not a shard of a keyspace.
 				continue
 			}
 			sourcesi, err := sourceTopo.GetShard(ctx, bls.Keyspace, bls.Shard)
@@ -2555,6 +2695,16 @@ func (s *Server) buildTrafficSwitcher(ctx context.Context, targetKeyspace, workf
 			ts.sources[bls.Shard] = NewMigrationSource(sourcesi, sourcePrimary)
 		}
 	}
+	if ts.externalMysql != "" {
+		ts.migrationType = binlogdatapb.MigrationType_TABLES
+		// There is no vschema nor shard for an external mysql, so there is
+		// nothing more to look at on the source side.
+		ts.sourceKSSchema, err = vindexes.BuildKeyspaceSchema(&vschemapb.Keyspace{}, ts.sourceKeyspace, s.env.Parser())
+		if err != nil {
+			return nil, err
+		}
+		return ts, nil
+	}
 	if ts.sourceKeyspace != ts.targetKeyspace || ts.externalCluster != "" {
 		ts.migrationType = binlogdatapb.MigrationType_TABLES
 	} else {
@@ -2910,12 +3060,29 @@ func (s *Server) WorkflowSwitchTraffic(ctx context.Context, req *vtctldatapb.Wor
 	if !set {
 		maxReplicationLagAllowed = defaultDuration
 	}
+	rollbackWindow, _, err := protoutil.DurationFromProto(req.RollbackWindow)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse RollbackWindow into a valid duration")
+		return nil, err
+	}
+	maxTimeSinceWritesSwitched, _, err := protoutil.DurationFromProto(req.MaxTimeSinceWritesSwitched)
+	if err != nil {
+		err = vterrors.Wrapf(err, "unable to parse MaxTimeSinceWritesSwitched into a valid duration")
+		return nil, err
+	}
 	direction := TrafficSwitchDirection(req.Direction)
 	if direction == DirectionBackward {
+		if maxTimeSinceWritesSwitched > 0 && startState.WritesSwitched {
+			if err := s.checkMaxTimeSinceWritesSwitched(ctx, req.Keyspace, req.Workflow, maxTimeSinceWritesSwitched, req.Shards); err != nil {
+				return nil, err
+			}
+		}
 		ts, startState, err = s.getWorkflowState(ctx, startState.SourceKeyspace, ts.reverseWorkflow)
 		if err != nil {
 			return nil, err
 		}
+	} else {
+		ts.rollbackWindow = rollbackWindow
 	}
 	reason, err := s.canSwitch(ctx, ts, startState, direction, int64(maxReplicationLagAllowed.Seconds()), req.Shards)
 	if err != nil {
@@ -3308,6 +3475,11 @@ func (s *Server) canSwitch(ctx context.Context, ts *trafficSwitcher, state *Stat
 			case binlogdatapb.VReplicationWorkflowState_Error.String():
 				return cannotSwitchError, nil
 			}
+			// When going backwards the reverse workflow must be actively replicating,
+			// otherwise writes done on the target since the switch would be lost.
+			if direction == DirectionBackward && st.State != binlogdatapb.VReplicationWorkflowState_Running.String() {
+				return fmt.Sprintf(cannotSwitchReverseNotRunning, st.Id, topoproto.TabletAliasString(st.Tablet), st.State), nil
+			}
 		}
 	}
 
@@ -3340,6 +3512,150 @@ func (s *Server) canSwitch(ctx context.Context, ts *trafficSwitcher, state *Stat
 	return "", nil
 }
 
+// checkMaxTimeSinceWritesSwitched returns an error if the writes for the
+// given (forward) workflow were switched longer ago than maxTime. The time
+// of the switch is derived from the frozen target streams, which stop being
+// updated once writes have been switched.
+func (s *Server) checkMaxTimeSinceWritesSwitched(ctx context.Context, keyspace, workflow string, maxTime time.Duration, shards []string) error {
+	wf, err := s.GetWorkflow(ctx, keyspace, workflow, false, shards)
+	if err != nil {
+		return err
+	}
+	switchedAt, ok := writesSwitchedTime(wf)
+	if !ok {
+		// The workflow was not frozen by a traffic switch so there is nothing
+		// to compare against.
+		return nil
+	}
+	if elapsed := time.Since(switchedAt); elapsed > maxTime {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot reverse traffic for workflow %s: "+cannotReverseWindowExpired,
+			workflow, elapsed.Truncate(time.Second), maxTime)
+	}
+	return nil
+}
+
+// writesSwitchedTime returns the time when writes were switched for the
+// workflow, which is the latest update time of its frozen streams. The
+// boolean is false if none of the streams are frozen.
+func writesSwitchedTime(wf *vtctldatapb.Workflow) (time.Time, bool) {
+	var (
+		switchedAt time.Time
+		found      bool
+	)
+	for _, stream := range wf.GetShardStreams() {
+		for _, st := range stream.GetStreams() {
+			if st.Message != Frozen || st.TimeUpdated == nil {
+				continue
+			}
+			if updated := protoutil.TimeFromProto(st.TimeUpdated); !found || updated.After(switchedAt) {
+				switchedAt = updated
+				found = true
+			}
+		}
+	}
+	return switchedAt, found
+}
+
+// checkRollbackRetention returns an error if the reverse workflow of the
+// MoveTables or Reshard workflow ts, or ts itself if it is the reverse
+// workflow, is still retained for the rollback window set when writes were
+// switched. Neither can be completed or deleted until then, as traffic could
+// not be reversed anymore.
+func (s *Server) checkRollbackRetention(ctx context.Context, ts *trafficSwitcher) error {
+	switch ts.workflowType {
+	case binlogdatapb.VReplicationWorkflowType_MoveTables, binlogdatapb.VReplicationWorkflowType_Reshard:
+	default:
+		return nil
+	}
+	// The reverse workflow runs on the primaries of the sources of the forward
+	// workflow.
+	keyspace, workflow := ts.SourceKeyspaceName(), ts.ReverseWorkflowName()
+	var primaries []*topo.TabletInfo
+	for _, source := range ts.Sources() {
+		primaries = append(primaries, source.GetPrimary())
+	}
+	if strings.HasSuffix(ts.WorkflowName(), reverseSuffix) {
+		keyspace, workflow = ts.TargetKeyspaceName(), ts.WorkflowName()
+		primaries = primaries[:0]
+		for _, target := range ts.Targets() {
+			primaries = append(primaries, target.GetPrimary())
+		}
+	}
+
+	var (
+		mu  sync.Mutex
+		wfs []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse
+		wg  sync.WaitGroup
+	)
+	allErrors := &concurrency.AllErrorRecorder{}
+	for _, primary := range primaries {
+		wg.Add(1)
+		go func(primary *topo.TabletInfo) {
+			defer wg.Done()
+			res, err := s.tmc.ReadVReplicationWorkflow(ctx, primary.Tablet, &tabletmanagerdatapb.ReadVReplicationWorkflowRequest{
+				Workflow: workflow,
+			})
+			if err != nil {
+				allErrors.RecordError(err)
+				return
+			}
+			if res == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			wfs = append(wfs, res)
+		}(primary)
+	}
+	wg.Wait()
+	if allErrors.HasErrors() {
+		return allErrors.AggrError(vterrors.Aggregate)
+	}
+	if until, ok := rollbackRetentionEnd(wfs); ok && time.Now().Before(until) {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, cannotRemoveRetained, workflow, keyspace, until.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// rollbackRetentionTag returns the tag of the reverse workflow that retains
+// it until the given time.
+func rollbackRetentionTag(until time.Time) string {
+	return rollbackRetentionTagPrefix + strconv.FormatInt(until.Unix(), 10)
+}
+
+// rollbackRetentionEnd returns until when the reverse workflow, as read from
+// each of its target shards, is retained according to its tags. The boolean
+// is false if it is not retained, which is also the case once traffic was
+// reversed and its streams are frozen.
+func rollbackRetentionEnd(wfs []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse) (time.Time, bool) {
+	var (
+		until time.Time
+		found bool
+	)
+	for _, wf := range wfs {
+		for _, st := range wf.GetStreams() {
+			if st.Message == Frozen {
+				return time.Time{}, false
+			}
+		}
+		for _, tag := range strings.Split(wf.GetTags(), ",") {
+			ts, ok := strings.CutPrefix(strings.TrimSpace(tag), rollbackRetentionTagPrefix)
+			if !ok {
+				continue
+			}
+			secs, err := strconv.ParseInt(ts, 10, 64)
+			if err != nil {
+				continue
+			}
+			if t := time.Unix(secs, 0); !found || t.After(until) {
+				until = t
+				found = true
+			}
+		}
+	}
+	return until, found
+}
+
 // VReplicationExec executes a query remotely using the DBA pool.
 func (s *Server) VReplicationExec(ctx context.Context, tabletAlias *topodatapb.TabletAlias, query string) (*querypb.QueryResult, error) {
 	ti, err := s.ts.GetTablet(ctx, tabletAlias)
@@ -3815,6 +4131,8 @@ func (s *Server) MigrateCreate(ctx context.Context, req *vtctldatapb.MigrateCrea
 		SourceKeyspace:            req.SourceKeyspace,
 		TargetKeyspace:            req.TargetKeyspace,
 		ExternalClusterName:       req.MountName,
+		ExternalMysql:             req.ExternalMysql,
+		ExternalMysqlOptions:      req.ExternalMysqlOptions,
 		Cells:                     req.Cells,
 		TabletTypes:               req.TabletTypes,
 		TabletSelectionPreference: req.TabletSelectionPreference,
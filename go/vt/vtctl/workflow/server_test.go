@@ -19,12 +19,15 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/encoding/prototext"
 
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/topo/memorytopo"
@@ -34,8 +37,10 @@ import (
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+	vttimepb "vitess.io/vitess/go/vt/proto/vttime"
 )
 
 type fakeTMC struct {
@@ -198,3 +203,244 @@ func TestVDiffCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestWritesSwitchedTime(t *testing.T) {
+	older := time.Unix(1700000000, 0)
+	newer := older.Add(5 * time.Second)
+	tests := []struct {
+		name    string
+		streams []*vtctldatapb.Workflow_Stream
+		want    time.Time
+		wantOk  bool
+	}{
+		{
+			name: "not frozen",
+			streams: []*vtctldatapb.Workflow_Stream{
+				{Id: 1, TimeUpdated: protoutil.TimeToProto(newer)},
+			},
+		},
+		{
+			name: "latest frozen stream",
+			streams: []*vtctldatapb.Workflow_Stream{
+				{Id: 1, Message: Frozen, TimeUpdated: protoutil.TimeToProto(older)},
+				{Id: 2, Message: Frozen, TimeUpdated: protoutil.TimeToProto(newer)},
+				{Id: 3, Message: Frozen},
+			},
+			want:   newer,
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &vtctldatapb.Workflow{
+				ShardStreams: map[string]*vtctldatapb.Workflow_ShardStream{
+					"-80": {Streams: tt.streams},
+				},
+			}
+			got, ok := writesSwitchedTime(wf)
+			require.Equal(t, tt.wantOk, ok)
+			require.True(t, tt.want.Equal(got), "got %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func TestRollbackRetentionEnd(t *testing.T) {
+	until := time.Unix(1700000000, 0)
+	tests := []struct {
+		name   string
+		wfs    []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse
+		want   time.Time
+		wantOk bool
+	}{
+		{
+			name: "not retained",
+			wfs: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+				{Tags: "", Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{{Id: 1}}},
+			},
+		},
+		{
+			name: "retained",
+			wfs: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+				{Tags: "other," + rollbackRetentionTag(until), Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{{Id: 1}}},
+				{Tags: rollbackRetentionTag(until.Add(-time.Second)), Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{{Id: 2}}},
+			},
+			want:   until,
+			wantOk: true,
+		},
+		{
+			name: "traffic reversed",
+			wfs: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse{
+				{Tags: rollbackRetentionTag(until), Streams: []*tabletmanagerdatapb.ReadVReplicationWorkflowResponse_Stream{{Id: 1, Message: Frozen}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rollbackRetentionEnd(tt.wfs)
+			require.Equal(t, tt.wantOk, ok)
+			require.True(t, tt.want.Equal(got), "got %v, want %v", got, tt.want)
+		})
+	}
+}
+
+// TestRollbackRetention confirms that the reverse workflow created when
+// switching writes with a rollback window is tagged as retained, and that it
+// cannot be deleted until the window has passed.
+func TestRollbackRetention(t *testing.T) {
+	ms := &vtctldatapb.MaterializeSettings{
+		Workflow:       "wf",
+		SourceKeyspace: "sourceks",
+		TargetKeyspace: "targetks",
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := newTestMaterializerEnv(t, ctx, ms, []string{"0"}, []string{"0"})
+	defer env.close()
+	require.NoError(t, env.topoServ.RebuildSrvVSchema(ctx, nil))
+
+	ts, _, err := env.ws.getWorkflowState(ctx, ms.TargetKeyspace, ms.Workflow)
+	require.NoError(t, err)
+	ts.rollbackWindow = time.Hour
+
+	env.tmc.expectVRQuery(100, "delete from _vt.vreplication where db_name = 'vt_sourceks' and workflow = 'wf_reverse'", &sqltypes.Result{})
+	env.tmc.expectVRQuery(100, "/insert into _vt.vreplication.*'wf_reverse'", &sqltypes.Result{})
+	env.tmc.expectVRQuery(100, `/update _vt.vreplication set tags = concat_ws\(',', nullif\(tags, ''\), 'rollback_retention_until:[0-9]+'\) where db_name = 'vt_sourceks' and workflow = 'wf_reverse'`, &sqltypes.Result{})
+	require.NoError(t, ts.createReverseVReplication(ctx))
+	env.tmc.verifyQueries(t)
+
+	env.tmc.workflowTags[ts.ReverseWorkflowName()] = "other," + rollbackRetentionTag(time.Now().Add(time.Hour))
+	_, err = env.ws.WorkflowDelete(ctx, &vtctldatapb.WorkflowDeleteRequest{
+		Keyspace: ms.SourceKeyspace,
+		Workflow: ts.ReverseWorkflowName(),
+	})
+	require.ErrorContains(t, err, "the wf_reverse workflow in the sourceks keyspace is retained until the end of its rollback window")
+	// Completing the forward workflow is refused for the same reason.
+	require.ErrorContains(t, env.ws.checkRollbackRetention(ctx, ts), "the wf_reverse workflow in the sourceks keyspace is retained")
+
+	env.tmc.workflowTags[ts.ReverseWorkflowName()] = rollbackRetentionTag(time.Now().Add(-time.Second))
+	require.NoError(t, env.ws.checkRollbackRetention(ctx, ts))
+}
+
+func TestVReplicationTransactionLag(t *testing.T) {
+	now := time.Unix(1700000100, 0)
+	running := binlogdatapb.VReplicationWorkflowState_Running.String()
+	stopped := binlogdatapb.VReplicationWorkflowState_Stopped.String()
+	lastTransaction := &vttimepb.Time{Seconds: now.Unix() - 30}
+
+	testCases := []struct {
+		name            string
+		state           string
+		message         string
+		lastTransaction *vttimepb.Time
+		lastHeartbeat   *vttimepb.Time
+		wantLag         float64
+		wantOK          bool
+	}{
+		{
+			name:            "running",
+			state:           running,
+			lastTransaction: lastTransaction,
+			lastHeartbeat:   &vttimepb.Time{Seconds: now.Unix() - 40},
+			wantLag:         30,
+			wantOK:          true,
+		},
+		{
+			name:            "caught up since its last transaction",
+			state:           running,
+			lastTransaction: lastTransaction,
+			lastHeartbeat:   &vttimepb.Time{Seconds: now.Unix() - 5},
+			wantLag:         5,
+			wantOK:          true,
+		},
+		{
+			name:          "no transaction since the copy",
+			state:         running,
+			lastHeartbeat: &vttimepb.Time{Seconds: now.Unix() - 5},
+			wantLag:       5,
+			wantOK:        true,
+		},
+		{
+			name:            "lagging",
+			state:           binlogdatapb.VReplicationWorkflowState_Lagging.String(),
+			lastTransaction: lastTransaction,
+			wantLag:         30,
+			wantOK:          true,
+		},
+		{
+			name:            "copying",
+			state:           binlogdatapb.VReplicationWorkflowState_Copying.String(),
+			lastTransaction: lastTransaction,
+			wantLag:         math.MaxInt64,
+			wantOK:          true,
+		},
+		{
+			name:            "frozen",
+			state:           stopped,
+			message:         Frozen,
+			lastTransaction: lastTransaction,
+			wantLag:         0,
+			wantOK:          true,
+		},
+		{
+			name:            "stopped",
+			state:           stopped,
+			lastTransaction: lastTransaction,
+		},
+		{
+			name:            "error",
+			state:           binlogdatapb.VReplicationWorkflowState_Error.String(),
+			lastTransaction: lastTransaction,
+		},
+		{
+			name:  "neither a transaction nor a heartbeat",
+			state: running,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lag, ok := vreplicationTransactionLag(tc.state, tc.message, tc.lastTransaction, tc.lastHeartbeat, now)
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.wantLag, lag)
+		})
+	}
+}
+
+func TestWorkflowCopyRate(t *testing.T) {
+	now := time.Unix(1700000100, 0)
+	copyStart := now.Add(-100 * time.Second)
+	wf := &vtctldatapb.Workflow{
+		ShardStreams: map[string]*vtctldatapb.Workflow_ShardStream{
+			"-80": {Streams: []*vtctldatapb.Workflow_Stream{{
+				Id:         1,
+				RowsCopied: 600,
+				Logs: []*vtctldatapb.Workflow_Stream_Log{
+					{Type: "Stream Created", CreatedAt: protoutil.TimeToProto(copyStart.Add(-time.Hour))},
+					{Type: "Started Copy Phase", CreatedAt: protoutil.TimeToProto(copyStart.Add(10 * time.Second))},
+				},
+			}}},
+			"80-": {Streams: []*vtctldatapb.Workflow_Stream{{
+				Id:         1,
+				RowsCopied: 400,
+				Logs: []*vtctldatapb.Workflow_Stream_Log{
+					{Type: "Started Copy Phase", CreatedAt: protoutil.TimeToProto(copyStart)},
+				},
+			}}},
+		},
+	}
+	require.Equal(t, 10.0, workflowCopyRate(wf, now))
+	require.Zero(t, workflowCopyRate(&vtctldatapb.Workflow{}, now))
+}
+
+func TestEstimateCopyETA(t *testing.T) {
+	_, ok := estimateCopyETA(100, 0)
+	require.False(t, ok)
+
+	eta, ok := estimateCopyETA(0, 10)
+	require.True(t, ok)
+	require.Zero(t, eta)
+
+	eta, ok = estimateCopyETA(1000, 10)
+	require.True(t, ok)
+	require.Equal(t, 100*time.Second, eta)
+}
@@ -148,6 +148,9 @@ func (dr *switcherDryRun) startReverseVReplication(ctx context.Context) error {
 
 func (dr *switcherDryRun) createReverseVReplication(ctx context.Context) error {
 	dr.drLog.Logf("Create reverse vreplication workflow %s", dr.ts.ReverseWorkflowName())
+	if dr.ts.rollbackWindow > 0 {
+		dr.drLog.Logf("Retain reverse vreplication workflow %s for the rollback window of %v", dr.ts.ReverseWorkflowName(), dr.ts.rollbackWindow)
+	}
 	return nil
 }
 
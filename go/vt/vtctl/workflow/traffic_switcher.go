@@ -74,6 +74,7 @@ const (
 	renameTableTemplate = "_%.59s_old" // limit table name to 64 characters
 
 	sqlDeleteWorkflow    = "delete from _vt.vreplication where db_name = %s and workflow = %s"
+	sqlAppendWorkflowTag = "update _vt.vreplication set tags = concat_ws(',', nullif(tags, ''), %s) where db_name = %s and workflow = %s"
 	sqlGetMaxSequenceVal = "select max(%a) as maxval from %a.%a"
 	sqlInitSequenceTable = "insert into %a.%a (id, next_id, cache) values (0, %d, 1000) on duplicate key update next_id = if(next_id < %d, %d, next_id)"
 )
@@ -226,10 +227,12 @@ type trafficSwitcher struct {
 	tables           []string
 	keepRoutingRules bool
 	sourceKSSchema   *vindexes.KeyspaceSchema
-	optCells         string // cells option passed to MoveTables/Reshard Create
-	optTabletTypes   string // tabletTypes option passed to MoveTables/Reshard Create
+	optCells         string        // cells option passed to MoveTables/Reshard Create
+	optTabletTypes   string        // tabletTypes option passed to MoveTables/Reshard Create
+	rollbackWindow   time.Duration // how long the reverse workflow is retained for after SwitchTraffic
 	externalCluster  string
 	externalTopo     *topo.Server
+	externalMysql    string
 	sourceTimeZone   string
 	targetTimeZone   string
 	workflowType     binlogdatapb.VReplicationWorkflowType
@@ -753,22 +756,23 @@ func (ts *trafficSwitcher) changeShardRouting(ctx context.Context) error {
 		log.Errorf("%w", err2)
 		return err2
 	}
-	err := ts.ForAllSources(func(source *MigrationSource) error {
-		_, err := ts.TopoServer().UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			si.IsPrimaryServing = false
-			return nil
-		})
-		return err
-	})
-	if err != nil {
-		return err
+	// Stop serving from the sources and start serving from the targets
+	// in a single topo transaction, so there is no point in time where
+	// both or neither of them are serving.
+	targets := make(map[string]bool)
+	for _, target := range ts.TargetShards() {
+		targets[target.ShardName()] = true
 	}
-	err = ts.ForAllTargets(func(target *MigrationTarget) error {
-		_, err := ts.TopoServer().UpdateShardFields(ctx, ts.TargetKeyspaceName(), target.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			si.IsPrimaryServing = true
-			return nil
-		})
-		return err
+	var shards []string
+	for _, source := range ts.SourceShards() {
+		shards = append(shards, source.ShardName())
+	}
+	for _, target := range ts.TargetShards() {
+		shards = append(shards, target.ShardName())
+	}
+	_, err := ts.TopoServer().UpdateShardsFields(ctx, ts.TargetKeyspaceName(), shards, nil, func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = targets[si.ShardName()]
+		return nil
 	})
 	if err != nil {
 		return err
@@ -837,6 +841,10 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 	if err := ts.deleteReverseVReplication(ctx); err != nil {
 		return err
 	}
+	var retainUntil string
+	if ts.rollbackWindow > 0 {
+		retainUntil = rollbackRetentionTag(time.Now().Add(ts.rollbackWindow))
+	}
 	err := ts.ForAllUIDs(func(target *MigrationTarget, uid int32) error {
 		bls := target.Sources[uid]
 		source := ts.Sources()[bls.Shard]
@@ -901,6 +909,14 @@ func (ts *trafficSwitcher) createReverseVReplication(ctx context.Context) error
 			return err
 		}
 
+		if retainUntil != "" {
+			_, err = ts.VReplicationExec(ctx, source.GetPrimary().Alias,
+				fmt.Sprintf(sqlAppendWorkflowTag, encodeString(retainUntil), encodeString(source.GetPrimary().DbName()), encodeString(ts.ReverseWorkflowName())))
+			if err != nil {
+				return err
+			}
+		}
+
 		// if user has defined the cell/tablet_types parameters in the forward workflow, update the reverse workflow as well
 		updateQuery := ts.getReverseVReplicationUpdateQuery(target.GetPrimary().Alias.Cell, source.GetPrimary().Alias.Cell, source.GetPrimary().DbName())
 		if updateQuery != "" {
@@ -0,0 +1,209 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/trace"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vdiff"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// vdiffStatePriority orders the states of the shards of a VDiff: the state of
+// the VDiff is the state of its shards with the highest priority.
+var vdiffStatePriority = map[string]int{
+	string(vdiff.CompletedState): 0,
+	string(vdiff.StoppedState):   1,
+	string(vdiff.PendingState):   2,
+	string(vdiff.StartedState):   3,
+	string(vdiff.ErrorState):     4,
+}
+
+// GetVDiffReport is part of the vtctlservicepb.VtctldServer interface.
+// It reads the reports the target primary tablets persisted for the VDiffs of
+// the workflow, and merges the reports of the shards of each VDiff.
+func (s *Server) GetVDiffReport(ctx context.Context, req *vtctldatapb.GetVDiffReportRequest) (*vtctldatapb.GetVDiffReportResponse, error) {
+	span, ctx := trace.NewSpan(ctx, "workflow.Server.GetVDiffReport")
+	defer span.Finish()
+
+	span.Annotate("keyspace", req.TargetKeyspace)
+	span.Annotate("workflow", req.Workflow)
+	span.Annotate("uuid", req.Uuid)
+
+	uuids := []string{req.Uuid}
+	if req.Uuid == "" {
+		all, err := s.VDiffShow(ctx, &vtctldatapb.VDiffShowRequest{
+			Workflow:       req.Workflow,
+			TargetKeyspace: req.TargetKeyspace,
+			Arg:            vdiff.AllActionArg,
+		})
+		if err != nil {
+			return nil, err
+		}
+		uuids = recentVDiffUUIDs(all.TabletResponses)
+	}
+
+	resp := &vtctldatapb.GetVDiffReportResponse{}
+	for _, uuid := range uuids {
+		if req.Limit > 0 && int64(len(resp.Reports)) >= req.Limit {
+			break
+		}
+		show, err := s.VDiffShow(ctx, &vtctldatapb.VDiffShowRequest{
+			Workflow:       req.Workflow,
+			TargetKeyspace: req.TargetKeyspace,
+			Arg:            uuid,
+		})
+		if err != nil {
+			return nil, err
+		}
+		report, err := buildVDiffReport(uuid, show.TabletResponses)
+		if err != nil {
+			return nil, err
+		}
+		if req.OnlyMismatches && !report.HasMismatch {
+			continue
+		}
+		resp.Reports = append(resp.Reports, report)
+	}
+	return resp, nil
+}
+
+// recentVDiffUUIDs returns the UUIDs of the VDiffs that the shards listed,
+// from the most recently created one to the oldest.
+func recentVDiffUUIDs(responses map[string]*tabletmanagerdatapb.VDiffResponse) []string {
+	createdAt := make(map[string]string)
+	for _, resp := range responses {
+		if resp == nil || resp.Output == nil {
+			continue
+		}
+		for _, row := range sqltypes.Proto3ToResult(resp.Output).Named().Rows {
+			uuid := row.AsString("vdiff_uuid", "")
+			if uuid == "" {
+				continue
+			}
+			created := row.AsString("created_at", "")
+			if last, ok := createdAt[uuid]; !ok || created > last {
+				createdAt[uuid] = created
+			}
+		}
+	}
+	uuids := make([]string, 0, len(createdAt))
+	for uuid := range createdAt {
+		uuids = append(uuids, uuid)
+	}
+	sort.Slice(uuids, func(i, j int) bool {
+		if createdAt[uuids[i]] != createdAt[uuids[j]] {
+			return createdAt[uuids[i]] > createdAt[uuids[j]]
+		}
+		return uuids[i] < uuids[j]
+	})
+	return uuids
+}
+
+// buildVDiffReport merges the summaries the shards returned for a VDiff.
+func buildVDiffReport(uuid string, responses map[string]*tabletmanagerdatapb.VDiffResponse) (*vtctldatapb.VDiffReport, error) {
+	report := &vtctldatapb.VDiffReport{Uuid: uuid}
+	completed := true
+	for shard, resp := range responses {
+		if resp == nil || resp.Output == nil {
+			continue
+		}
+		for _, row := range sqltypes.Proto3ToResult(resp.Output).Named().Rows {
+			state := row.AsString("vdiff_state", "")
+			if report.State == "" || vdiffStatePriority[state] > vdiffStatePriority[report.State] {
+				report.State = state
+			}
+			if started := row.AsString("started_at", ""); started != "" && (report.StartedAt == "" || started < report.StartedAt) {
+				report.StartedAt = started
+			}
+			if completedAt := row.AsString("completed_at", ""); completedAt == "" {
+				completed = false
+			} else if completedAt > report.CompletedAt {
+				report.CompletedAt = completedAt
+			}
+
+			tableName := row.AsString("table_name", "")
+			if tableName == "" {
+				// The VDiff hasn't started on this shard yet.
+				continue
+			}
+			table := &vtctldatapb.VDiffTableReport{
+				TableName:    tableName,
+				Shard:        shard,
+				State:        row.AsString("table_state", ""),
+				LastError:    row.AsString("last_error", ""),
+				HasMismatch:  row.AsInt64("has_mismatch", 0) == 1,
+				RowsCompared: row.AsInt64("rows_compared", 0),
+			}
+			if jsonReport := row.AsBytes("report", nil); len(jsonReport) > 0 {
+				var dr vdiff.DiffReport
+				if err := json.Unmarshal(jsonReport, &dr); err != nil {
+					return nil, vterrors.Wrapf(err, "invalid report of table %s on shard %s for vdiff %s", tableName, shard, uuid)
+				}
+				table.MatchingRows = dr.MatchingRows
+				table.MismatchedRows = dr.MismatchedRows
+				table.ExtraRowsSource = dr.ExtraRowsSource
+				table.ExtraRowsTarget = dr.ExtraRowsTarget
+				table.ExtraRowsSourceSample = vdiffRowSamples(dr.ExtraRowsSourceDiffs)
+				table.ExtraRowsTargetSample = vdiffRowSamples(dr.ExtraRowsTargetDiffs)
+				for _, mismatch := range dr.MismatchedRowsDiffs {
+					table.MismatchedRowsSample = append(table.MismatchedRowsSample, &vtctldatapb.VDiffMismatchSample{
+						Source: vdiffRowSample(mismatch.Source),
+						Target: vdiffRowSample(mismatch.Target),
+					})
+				}
+			}
+			report.HasMismatch = report.HasMismatch || table.HasMismatch
+			report.Tables = append(report.Tables, table)
+		}
+	}
+	if !completed {
+		report.CompletedAt = ""
+	}
+	sort.Slice(report.Tables, func(i, j int) bool {
+		if report.Tables[i].TableName != report.Tables[j].TableName {
+			return report.Tables[i].TableName < report.Tables[j].TableName
+		}
+		return report.Tables[i].Shard < report.Tables[j].Shard
+	})
+	return report, nil
+}
+
+func vdiffRowSamples(rows []*vdiff.RowDiff) []*vtctldatapb.VDiffRowSample {
+	var samples []*vtctldatapb.VDiffRowSample
+	for _, row := range rows {
+		samples = append(samples, vdiffRowSample(row))
+	}
+	return samples
+}
+
+func vdiffRowSample(row *vdiff.RowDiff) *vtctldatapb.VDiffRowSample {
+	if row == nil {
+		return nil
+	}
+	return &vtctldatapb.VDiffRowSample{
+		Row:   row.Row,
+		Query: row.Query,
+	}
+}
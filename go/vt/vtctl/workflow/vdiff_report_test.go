@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+func vdiffSummaryResponse(rows ...string) *tabletmanagerdatapb.VDiffResponse {
+	fields := sqltypes.MakeTestFields(
+		"vdiff_state|last_error|table_name|uuid|table_state|started_at|rows_compared|completed_at|has_mismatch|report",
+		"varbinary|varbinary|varbinary|varchar|varbinary|timestamp|int64|timestamp|int64|json",
+	)
+	return &tabletmanagerdatapb.VDiffResponse{Output: sqltypes.ResultToProto3(sqltypes.MakeTestResult(fields, rows...))}
+}
+
+func TestRecentVDiffUUIDs(t *testing.T) {
+	fields := sqltypes.MakeTestFields("vdiff_uuid|created_at", "varchar|timestamp")
+	responses := map[string]*tabletmanagerdatapb.VDiffResponse{
+		"-80": {Output: sqltypes.ResultToProto3(sqltypes.MakeTestResult(fields,
+			"uuid-1|2024-01-01 00:00:00",
+			"uuid-2|2024-01-02 00:00:00",
+		))},
+		"80-": {Output: sqltypes.ResultToProto3(sqltypes.MakeTestResult(fields,
+			"uuid-1|2024-01-01 00:00:01",
+			"uuid-3|2024-01-03 00:00:00",
+		))},
+		"c0-": nil,
+	}
+	require.Equal(t, []string{"uuid-3", "uuid-2", "uuid-1"}, recentVDiffUUIDs(responses))
+}
+
+func TestBuildVDiffReport(t *testing.T) {
+	mismatchReport := `{"TableName":"t1","ProcessedRows":3,"MatchingRows":1,"MismatchedRows":1,"ExtraRowsSource":1,"ExtraRowsTarget":0,` +
+		`"ExtraRowsSourceSample":[{"Row":{"id":"3"}}],` +
+		`"MismatchedRowsSample":[{"Source":{"Row":{"id":"2","c":"a"}},"Target":{"Row":{"id":"2","c":"b"}}}]}`
+	responses := map[string]*tabletmanagerdatapb.VDiffResponse{
+		"80-": vdiffSummaryResponse(
+			"completed||t1|uuid-1|completed|2024-01-01 00:00:00|3|2024-01-01 00:10:00|1|"+mismatchReport,
+			`completed||t2|uuid-1|completed|2024-01-01 00:00:00|2|2024-01-01 00:10:00|0|{"TableName":"t2","ProcessedRows":2,"MatchingRows":2}`,
+		),
+		"-80": vdiffSummaryResponse(
+			"started||t1|uuid-1|started|2023-12-31 23:59:00|1|null|0|null",
+		),
+	}
+
+	report, err := buildVDiffReport("uuid-1", responses)
+	require.NoError(t, err)
+	require.Equal(t, "uuid-1", report.Uuid)
+	require.Equal(t, "started", report.State)
+	require.True(t, report.HasMismatch)
+	require.Equal(t, "2023-12-31 23:59:00", report.StartedAt)
+	require.Empty(t, report.CompletedAt)
+
+	require.Len(t, report.Tables, 3)
+	require.Equal(t, "t1", report.Tables[0].TableName)
+	require.Equal(t, "-80", report.Tables[0].Shard)
+	require.False(t, report.Tables[0].HasMismatch)
+
+	t1 := report.Tables[1]
+	require.Equal(t, "t1", t1.TableName)
+	require.Equal(t, "80-", t1.Shard)
+	require.True(t, t1.HasMismatch)
+	require.EqualValues(t, 3, t1.RowsCompared)
+	require.EqualValues(t, 1, t1.MatchingRows)
+	require.EqualValues(t, 1, t1.MismatchedRows)
+	require.EqualValues(t, 1, t1.ExtraRowsSource)
+	require.Len(t, t1.ExtraRowsSourceSample, 1)
+	require.Equal(t, map[string]string{"id": "3"}, t1.ExtraRowsSourceSample[0].Row)
+	require.Len(t, t1.MismatchedRowsSample, 1)
+	require.Equal(t, "a", t1.MismatchedRowsSample[0].Source.Row["c"])
+	require.Equal(t, "b", t1.MismatchedRowsSample[0].Target.Row["c"])
+
+	require.Equal(t, "t2", report.Tables[2].TableName)
+	require.False(t, report.Tables[2].HasMismatch)
+	require.EqualValues(t, 2, report.Tables[2].MatchingRows)
+
+	_, err = buildVDiffReport("uuid-1", map[string]*tabletmanagerdatapb.VDiffResponse{
+		"-80": vdiffSummaryResponse("completed||t1|uuid-1|completed|2024-01-01 00:00:00|1|2024-01-01 00:10:00|0|{"),
+	})
+	require.ErrorContains(t, err, "invalid report of table t1 on shard -80")
+}
@@ -454,6 +454,18 @@ func initAPI(ctx context.Context, ts *topo.Server, actions *ActionRepository) {
 		return newTabletWithURL(t.Tablet), nil
 	})
 
+	// Topology graph: cells, keyspaces, shards, and tablets in a single call.
+	handleCollection("topology_graph", func(r *http.Request) (any, error) {
+		if getItemPath(r.URL.Path) != "" {
+			return nil, errors.New("topology graph can only be listed, not retrieved")
+		}
+		req, err := parseTopologyGraphRequest(r)
+		if err != nil {
+			return nil, err
+		}
+		return getTopologyGraph(r.Context(), ts, tabletHealthCache, req)
+	})
+
 	// Healthcheck real time status per (cell, keyspace, tablet type, metric).
 	handleAPI("tablet_statuses/", func(w http.ResponseWriter, r *http.Request) error {
 		http.NotFound(w, r)
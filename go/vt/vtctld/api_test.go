@@ -308,6 +308,39 @@ func TestAPI(t *testing.T) {
 				"Error": false
 			}`, http.StatusOK},
 
+		// Topology Graph
+		{"GET", "topology_graph/", "", `{
+				"cells": [
+					{"name": "cell1", "keyspaces": [{"name": "ks1", "shards": [{"name": "-80", "tablets": [
+						{"alias": "cell1-0000000100", "type": "replica", "hostname": "mysql1-cell1.test.net", "url": "http://mysql1-cell1.test.net:100"}
+					]}]}]},
+					{"name": "cell2", "keyspaces": [{"name": "ks1", "shards": [{"name": "-80", "tablets": [
+						{"alias": "cell2-0000000200", "type": "replica", "hostname": "mysql2-cell2.test.net", "url": "http://mysql2-cell2.test.net:200"}
+					]}]}]}
+				],
+				"total_tablets": 2
+			}`, http.StatusOK},
+		{"GET", "topology_graph/?page_size=1", "", `{
+				"cells": [
+					{"name": "cell1", "keyspaces": [{"name": "ks1", "shards": [{"name": "-80", "tablets": [
+						{"alias": "cell1-0000000100", "type": "replica", "hostname": "mysql1-cell1.test.net", "url": "http://mysql1-cell1.test.net:100"}
+					]}]}]}
+				],
+				"total_tablets": 2,
+				"next_page_token": "1"
+			}`, http.StatusOK},
+		{"GET", "topology_graph/?page_size=1&page_token=1", "", `{
+				"cells": [
+					{"name": "cell2", "keyspaces": [{"name": "ks1", "shards": [{"name": "-80", "tablets": [
+						{"alias": "cell2-0000000200", "type": "replica", "hostname": "mysql2-cell2.test.net", "url": "http://mysql2-cell2.test.net:200"}
+					]}]}]}
+				],
+				"total_tablets": 2
+			}`, http.StatusOK},
+		{"GET", "topology_graph/?cell=cell1&type=primary", "", `{"cells": [], "total_tablets": 0}`, http.StatusOK},
+		{"GET", "topology_graph/?keyspace=ks2", "", `{"cells": [], "total_tablets": 0}`, http.StatusOK},
+		{"GET", "topology_graph/?page_size=-1", "", "can't get topology_graph: invalid page_size \"-1\"", http.StatusInternalServerError},
+
 		// Tablet Updates
 		{"GET", "tablet_statuses/?keyspace=all&cell=all&type=all&metric=lag", "", "404 page not found", http.StatusNotFound},
 		{"GET", "tablet_statuses/cell1/REPLICA/lag", "", "404 page not found", http.StatusNotFound},
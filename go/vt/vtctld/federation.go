@@ -0,0 +1,317 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtctl"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
+	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+// This file implements the federated API of vtctld: read-only endpoints under
+// /api/federation/ that return the keyspaces, shards, tablets, workflows and
+// schemas of this cluster and of other clusters, fetched from their vtctlds,
+// so that a single dashboard can cover all of them.
+
+var (
+	federationClusters       []string
+	federationClusterID      = "local"
+	federationClientProtocol = "grpc"
+	federationRequestTimeout = 10 * time.Second
+
+	federationAuthorizers []FederationAuthorizer
+)
+
+func init() {
+	for _, cmd := range []string{"vtcombo", "vtctld"} {
+		servenv.OnParseFor(cmd, registerFederationFlags)
+	}
+}
+
+func registerFederationFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&federationClusters, "federation-clusters", federationClusters, "Comma-separated list of the other clusters to serve in the federated API under /api/federation/, as <cluster_id>=<vtctld_grpc_address>. The federated API is only served if set.")
+	fs.StringVar(&federationClusterID, "federation-cluster-id", federationClusterID, "ID of the cluster of this vtctld in the federated API.")
+	fs.StringVar(&federationClientProtocol, "federation-vtctld-client-protocol", federationClientProtocol, "Protocol to connect to the vtctlds of the other clusters of the federated API.")
+	fs.DurationVar(&federationRequestTimeout, "federation-request-timeout", federationRequestTimeout, "Timeout of the requests of the federated API to the vtctld of a cluster.")
+}
+
+// FederationAuthorizer decides whether an HTTP request of the federated API
+// may read a resource of a cluster. The resources are clusters, keyspaces,
+// shards, tablets, workflows and schema.
+//
+// The clusters that a request may not read are left out of its response.
+type FederationAuthorizer func(r *http.Request, cluster string, resource string) bool
+
+// RegisterFederationAuthorizer registers an authorizer for the federated API.
+// A request may only read a resource of a cluster if all the authorizers
+// allow it. It must be called before vtctld starts serving.
+func RegisterFederationAuthorizer(authorizer FederationAuthorizer) {
+	federationAuthorizers = append(federationAuthorizers, authorizer)
+}
+
+// federatedCluster is a cluster of the federated API.
+type federatedCluster struct {
+	ID string `json:"id"`
+	// Address is the address of the vtctld of the cluster. It is empty for
+	// the cluster of this vtctld.
+	Address string `json:"address,omitempty"`
+
+	client vtctldclient.VtctldClient
+}
+
+// federatedResult is the result of a request of the federated API for a
+// cluster.
+type federatedResult struct {
+	Cluster string          `json:"cluster"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// federation serves the federated API.
+type federation struct {
+	clusters    []*federatedCluster
+	timeout     time.Duration
+	authorizers []FederationAuthorizer
+}
+
+// newFederation returns the federation of the cluster of this vtctld, whose
+// API is served by server, and of the clusters given as <id>=<address>.
+func newFederation(localID string, server vtctldclient.VtctldClient, clusters []string, protocol string) (*federation, error) {
+	f := &federation{
+		clusters:    []*federatedCluster{{ID: localID, client: server}},
+		timeout:     federationRequestTimeout,
+		authorizers: slices.Clone(federationAuthorizers),
+	}
+	for _, cluster := range clusters {
+		id, addr, ok := strings.Cut(cluster, "=")
+		if !ok || id == "" || addr == "" {
+			return nil, fmt.Errorf("invalid federated cluster %q, expected <cluster_id>=<vtctld_address>", cluster)
+		}
+		if f.cluster(id) != nil {
+			return nil, fmt.Errorf("duplicate federated cluster %q", id)
+		}
+		client, err := vtctldclient.New(protocol, addr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot connect to the vtctld of federated cluster %q: %v", id, err)
+		}
+		f.clusters = append(f.clusters, &federatedCluster{ID: id, Address: addr, client: client})
+	}
+	sort.Slice(f.clusters, func(i, j int) bool { return f.clusters[i].ID < f.clusters[j].ID })
+	return f, nil
+}
+
+func (f *federation) cluster(id string) *federatedCluster {
+	for _, c := range f.clusters {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// initFederation serves the federated API if other clusters are configured.
+func initFederation(env *vtenv.Environment, ts *topo.Server) error {
+	if len(federationClusters) == 0 {
+		return nil
+	}
+	server := localvtctldclient.New(grpcvtctldserver.NewVtctldServer(env, ts))
+	f, err := newFederation(federationClusterID, server, federationClusters, federationClientProtocol)
+	if err != nil {
+		return err
+	}
+	handleAPI("federation/", f.handle)
+	return nil
+}
+
+// handle serves a request of the federated API. The valid requests are:
+//
+//	GET /api/federation/clusters
+//	GET /api/federation/keyspaces
+//	GET /api/federation/shards/<keyspace>
+//	GET /api/federation/tablets?keyspace=<keyspace>&cell=<cell>
+//	GET /api/federation/workflows/<keyspace>?active_only=true
+//	GET /api/federation/schema/<keyspace>
+//
+// The cluster parameter, which may be repeated, restricts the response to the
+// given clusters.
+func (f *federation) handle(w http.ResponseWriter, r *http.Request) error {
+	if err := acl.CheckAccessHTTP(r, acl.MONITORING); err != nil {
+		http.Error(w, "403 Forbidden", http.StatusForbidden)
+		return nil
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "the federated API is read-only", http.StatusMethodNotAllowed)
+		return nil
+	}
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+
+	resource, arg, _ := strings.Cut(getItemPath(r.URL.Path), "/")
+	var fetch func(ctx context.Context, client vtctldclient.VtctldClient) (any, error)
+	switch resource {
+	case "clusters":
+		return writeJSON(w, f.authorizedClusters(r, resource))
+	case "keyspaces":
+		fetch = func(ctx context.Context, client vtctldclient.VtctldClient) (any, error) {
+			return client.GetKeyspaces(ctx, &vtctldatapb.GetKeyspacesRequest{})
+		}
+	case "shards":
+		if arg == "" {
+			return errors.New("keyspace is required, expected path: /api/federation/shards/<keyspace>")
+		}
+		fetch = func(ctx context.Context, client vtctldclient.VtctldClient) (any, error) {
+			return client.FindAllShardsInKeyspace(ctx, &vtctldatapb.FindAllShardsInKeyspaceRequest{Keyspace: arg})
+		}
+	case "tablets":
+		req := &vtctldatapb.GetTabletsRequest{Keyspace: r.FormValue("keyspace")}
+		if cell := r.FormValue("cell"); cell != "" {
+			req.Cells = []string{cell}
+		}
+		fetch = func(ctx context.Context, client vtctldclient.VtctldClient) (any, error) {
+			return client.GetTablets(ctx, req)
+		}
+	case "workflows":
+		if arg == "" {
+			return errors.New("keyspace is required, expected path: /api/federation/workflows/<keyspace>")
+		}
+		activeOnly := r.FormValue("active_only") == "true"
+		fetch = func(ctx context.Context, client vtctldclient.VtctldClient) (any, error) {
+			return client.GetWorkflows(ctx, &vtctldatapb.GetWorkflowsRequest{Keyspace: arg, ActiveOnly: activeOnly})
+		}
+	case "schema":
+		if arg == "" {
+			return errors.New("keyspace is required, expected path: /api/federation/schema/<keyspace>")
+		}
+		fetch = func(ctx context.Context, client vtctldclient.VtctldClient) (any, error) {
+			return getKeyspaceSchema(ctx, client, arg)
+		}
+	default:
+		http.NotFound(w, r)
+		return nil
+	}
+
+	return writeJSON(w, f.fetch(r.Context(), f.authorizedClusters(r, resource), fetch))
+}
+
+// authorizedClusters returns the clusters requested by r whose resource r may
+// read.
+func (f *federation) authorizedClusters(r *http.Request, resource string) []*federatedCluster {
+	requested := r.Form["cluster"]
+
+	clusters := []*federatedCluster{}
+	for _, c := range f.clusters {
+		if len(requested) > 0 && !slices.Contains(requested, c.ID) {
+			continue
+		}
+		authorized := true
+		for _, authorizer := range f.authorizers {
+			if !authorizer(r, c.ID, resource) {
+				authorized = false
+				break
+			}
+		}
+		if authorized {
+			clusters = append(clusters, c)
+		}
+	}
+	return clusters
+}
+
+// fetch calls fetch for all the clusters concurrently, and returns their
+// results in the order of the clusters.
+func (f *federation) fetch(ctx context.Context, clusters []*federatedCluster, fetch func(ctx context.Context, client vtctldclient.VtctldClient) (any, error)) []*federatedResult {
+	results := make([]*federatedResult, len(clusters))
+	var wg sync.WaitGroup
+	for i, c := range clusters {
+		wg.Add(1)
+		go func(i int, c *federatedCluster) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, f.timeout)
+			defer cancel()
+
+			results[i] = &federatedResult{Cluster: c.ID}
+			obj, err := fetch(ctx, c.client)
+			if err == nil {
+				results[i].Result, err = vtctl.MarshalJSON(obj)
+			}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+	return results
+}
+
+// getKeyspaceSchema returns the schema of the keyspace, as seen by the
+// primary of its first shard.
+func getKeyspaceSchema(ctx context.Context, client vtctldclient.VtctldClient, keyspace string) (*vtctldatapb.GetSchemaResponse, error) {
+	resp, err := client.FindAllShardsInKeyspace(ctx, &vtctldatapb.FindAllShardsInKeyspaceRequest{Keyspace: keyspace})
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]string, 0, len(resp.Shards))
+	for name := range resp.Shards {
+		shards = append(shards, name)
+	}
+	sort.Strings(shards)
+
+	for _, name := range shards {
+		shard := resp.Shards[name].Shard
+		if shard == nil || shard.PrimaryAlias == nil {
+			continue
+		}
+		return client.GetSchema(ctx, &vtctldatapb.GetSchemaRequest{
+			TabletAlias:  shard.PrimaryAlias,
+			IncludeViews: true,
+		})
+	}
+	return nil, fmt.Errorf("no primary in keyspace %v", keyspace)
+}
+
+func writeJSON(w http.ResponseWriter, obj any) error {
+	data, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return fmt.Errorf("json error: %v", err)
+	}
+	w.Header().Set("Content-Type", jsonContentType)
+	w.Write(data)
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtctl/grpcvtctldserver"
+	"vitess.io/vitess/go/vt/vtctl/localvtctldclient"
+	"vitess.io/vitess/go/vt/vtctl/vtctldclient"
+	"vitess.io/vitess/go/vt/vtenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtctldatapb "vitess.io/vitess/go/vt/proto/vtctldata"
+)
+
+func newTestFederatedClient(ctx context.Context, t *testing.T, keyspaces ...string) vtctldclient.VtctldClient {
+	ts := memorytopo.NewServer(ctx, "cell1")
+	t.Cleanup(ts.Close)
+	for _, keyspace := range keyspaces {
+		require.NoError(t, ts.CreateKeyspace(ctx, keyspace, &topodatapb.Keyspace{}))
+		require.NoError(t, ts.CreateShard(ctx, keyspace, "0"))
+	}
+	return localvtctldclient.New(grpcvtctldserver.NewVtctldServer(vtenv.NewTestEnv(), ts))
+}
+
+func serveFederation(t *testing.T, f *federation, method string, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(method, url, nil)
+	if err := f.handle(w, r); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return w
+}
+
+func TestFederation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f, err := newFederation("prod", newTestFederatedClient(ctx, t, "commerce", "customer"), nil, "")
+	require.NoError(t, err)
+	f.clusters = append(f.clusters, &federatedCluster{
+		ID:      "staging",
+		Address: "vtctld-staging:15999",
+		client:  newTestFederatedClient(ctx, t, "commerce"),
+	})
+
+	keyspaceNames := func(t *testing.T, w *httptest.ResponseRecorder) map[string][]string {
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var results []*federatedResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+
+		names := make(map[string][]string)
+		for _, result := range results {
+			require.Empty(t, result.Error)
+			resp := &vtctldatapb.GetKeyspacesResponse{}
+			require.NoError(t, protojson.Unmarshal(result.Result, resp))
+			names[result.Cluster] = []string{}
+			for _, ks := range resp.Keyspaces {
+				names[result.Cluster] = append(names[result.Cluster], ks.Name)
+			}
+		}
+		return names
+	}
+
+	t.Run("clusters", func(t *testing.T) {
+		w := serveFederation(t, f, http.MethodGet, "/api/federation/clusters")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"id": "prod"}, {"id": "staging", "address": "vtctld-staging:15999"}]`, w.Body.String())
+	})
+
+	t.Run("keyspaces", func(t *testing.T) {
+		w := serveFederation(t, f, http.MethodGet, "/api/federation/keyspaces")
+		assert.Equal(t, map[string][]string{
+			"prod":    {"commerce", "customer"},
+			"staging": {"commerce"},
+		}, keyspaceNames(t, w))
+	})
+
+	t.Run("cluster parameter", func(t *testing.T) {
+		w := serveFederation(t, f, http.MethodGet, "/api/federation/keyspaces?cluster=staging")
+		assert.Equal(t, map[string][]string{
+			"staging": {"commerce"},
+		}, keyspaceNames(t, w))
+	})
+
+	t.Run("errors are per cluster", func(t *testing.T) {
+		w := serveFederation(t, f, http.MethodGet, "/api/federation/shards/customer")
+		require.Equal(t, http.StatusOK, w.Code)
+		var results []*federatedResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+		require.Len(t, results, 2)
+
+		assert.Equal(t, "prod", results[0].Cluster)
+		assert.Empty(t, results[0].Error)
+		resp := &vtctldatapb.FindAllShardsInKeyspaceResponse{}
+		require.NoError(t, protojson.Unmarshal(results[0].Result, resp))
+		assert.Contains(t, resp.Shards, "0")
+
+		assert.Equal(t, "staging", results[1].Cluster)
+		assert.NotEmpty(t, results[1].Error)
+		assert.Empty(t, results[1].Result)
+	})
+
+	t.Run("schema without a primary", func(t *testing.T) {
+		w := serveFederation(t, f, http.MethodGet, "/api/federation/schema/commerce?cluster=prod")
+		require.Equal(t, http.StatusOK, w.Code)
+		var results []*federatedResult
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+		require.Len(t, results, 1)
+		assert.Equal(t, "no primary in keyspace commerce", results[0].Error)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusMethodNotAllowed, serveFederation(t, f, http.MethodPost, "/api/federation/keyspaces").Code)
+		assert.Equal(t, http.StatusNotFound, serveFederation(t, f, http.MethodGet, "/api/federation/vschema").Code)
+		assert.Equal(t, http.StatusInternalServerError, serveFederation(t, f, http.MethodGet, "/api/federation/shards/").Code)
+	})
+
+	t.Run("authorizers", func(t *testing.T) {
+		f := *f
+		f.authorizers = []FederationAuthorizer{
+			func(r *http.Request, cluster string, resource string) bool {
+				return cluster != "prod" || resource != "keyspaces"
+			},
+		}
+
+		w := serveFederation(t, &f, http.MethodGet, "/api/federation/keyspaces")
+		assert.Equal(t, map[string][]string{
+			"staging": {"commerce"},
+		}, keyspaceNames(t, w))
+
+		w = serveFederation(t, &f, http.MethodGet, "/api/federation/clusters")
+		assert.JSONEq(t, `[{"id": "prod"}, {"id": "staging", "address": "vtctld-staging:15999"}]`, w.Body.String())
+	})
+}
+
+func TestNewFederation(t *testing.T) {
+	_, err := newFederation("prod", nil, []string{"staging"}, "grpc")
+	assert.ErrorContains(t, err, "invalid federated cluster")
+
+	_, err = newFederation("prod", nil, []string{"prod=vtctld:15999"}, "grpc")
+	assert.ErrorContains(t, err, "duplicate federated cluster")
+
+	_, err = newFederation("prod", nil, []string{"staging=vtctld:15999"}, "unknown")
+	assert.Error(t, err)
+}
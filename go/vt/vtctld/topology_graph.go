@@ -0,0 +1,276 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtctld
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// topologyGraphHealthTimeout bounds how long we wait for the health of
+	// each tablet when building the topology graph.
+	topologyGraphHealthTimeout = 5 * time.Second
+	// topologyGraphHealthConcurrency bounds the number of tablets whose
+	// health is fetched in parallel.
+	topologyGraphHealthConcurrency = 32
+)
+
+// topologyGraph is the response of the topology_graph API. It nests the
+// tablets of a single page under their cell, keyspace, and shard.
+type topologyGraph struct {
+	Cells         []*topologyGraphCell `json:"cells"`
+	TotalTablets  int                  `json:"total_tablets"`
+	NextPageToken string               `json:"next_page_token,omitempty"`
+}
+
+type topologyGraphCell struct {
+	Name      string                   `json:"name"`
+	Keyspaces []*topologyGraphKeyspace `json:"keyspaces"`
+}
+
+type topologyGraphKeyspace struct {
+	Name   string                `json:"name"`
+	Shards []*topologyGraphShard `json:"shards"`
+}
+
+type topologyGraphShard struct {
+	Name    string                 `json:"name"`
+	Tablets []*topologyGraphTablet `json:"tablets"`
+}
+
+type topologyGraphTablet struct {
+	Alias    string            `json:"alias"`
+	Type     string            `json:"type"`
+	Hostname string            `json:"hostname"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	URL      string            `json:"url"`
+
+	// The fields below are only set when health was requested.
+	Serving               *bool  `json:"serving,omitempty"`
+	ReplicationLagSeconds uint32 `json:"replication_lag_seconds,omitempty"`
+	HealthError           string `json:"health_error,omitempty"`
+}
+
+// topologyGraphRequest holds the filtering and pagination options of a
+// topology_graph API request.
+type topologyGraphRequest struct {
+	cells       []string
+	keyspaces   map[string]bool
+	shards      map[string]bool
+	tabletTypes map[topodatapb.TabletType]bool
+	health      bool
+	pageSize    int
+	offset      int
+}
+
+// parseTopologyGraphRequest parses the query parameters of a topology_graph
+// API request. The cell, keyspace, shard (as keyspace/shard) and type
+// parameters can be repeated, and restrict the tablets in the graph.
+// page_size and page_token control pagination, which is done over tablets
+// sorted by cell, keyspace, shard, and alias. If health is true, the current
+// health of every tablet in the page is included.
+func parseTopologyGraphRequest(r *http.Request) (*topologyGraphRequest, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	req := &topologyGraphRequest{
+		cells:       r.Form["cell"],
+		keyspaces:   make(map[string]bool),
+		shards:      make(map[string]bool),
+		tabletTypes: make(map[topodatapb.TabletType]bool),
+	}
+	for _, keyspace := range r.Form["keyspace"] {
+		req.keyspaces[keyspace] = true
+	}
+	for _, shard := range r.Form["shard"] {
+		keyspace, shard, err := topoproto.ParseKeyspaceShard(shard)
+		if err != nil {
+			return nil, err
+		}
+		req.shards[topoproto.KeyspaceShardString(keyspace, shard)] = true
+	}
+	for _, tabletType := range r.Form["type"] {
+		tt, err := topoproto.ParseTabletType(tabletType)
+		if err != nil {
+			return nil, err
+		}
+		req.tabletTypes[tt] = true
+	}
+
+	var err error
+	if health := r.FormValue("health"); health != "" {
+		if req.health, err = strconv.ParseBool(health); err != nil {
+			return nil, fmt.Errorf("invalid health %q: %v", health, err)
+		}
+	}
+	if pageSize := r.FormValue("page_size"); pageSize != "" {
+		if req.pageSize, err = strconv.Atoi(pageSize); err != nil || req.pageSize < 0 {
+			return nil, fmt.Errorf("invalid page_size %q", pageSize)
+		}
+	}
+	if pageToken := r.FormValue("page_token"); pageToken != "" {
+		if req.offset, err = strconv.Atoi(pageToken); err != nil || req.offset < 0 {
+			return nil, fmt.Errorf("invalid page_token %q", pageToken)
+		}
+	}
+	return req, nil
+}
+
+func (req *topologyGraphRequest) includes(tablet *topodatapb.Tablet) bool {
+	if len(req.keyspaces) > 0 && !req.keyspaces[tablet.Keyspace] {
+		return false
+	}
+	if len(req.shards) > 0 && !req.shards[topoproto.KeyspaceShardString(tablet.Keyspace, tablet.Shard)] {
+		return false
+	}
+	if len(req.tabletTypes) > 0 && !req.tabletTypes[tablet.Type] {
+		return false
+	}
+	return true
+}
+
+// getTopologyGraph builds the topology graph for the given request.
+func getTopologyGraph(ctx context.Context, ts *topo.Server, thc *tabletHealthCache, req *topologyGraphRequest) (*topologyGraph, error) {
+	cells := req.cells
+	if len(cells) == 0 {
+		var err error
+		if cells, err = ts.GetKnownCells(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var tablets []*topodatapb.Tablet
+	for _, cell := range cells {
+		tabletInfos, err := ts.GetTabletsByCell(ctx, cell, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, ti := range tabletInfos {
+			if req.includes(ti.Tablet) {
+				tablets = append(tablets, ti.Tablet)
+			}
+		}
+	}
+	sort.Slice(tablets, func(i, j int) bool {
+		a, b := tablets[i], tablets[j]
+		if a.Alias.Cell != b.Alias.Cell {
+			return a.Alias.Cell < b.Alias.Cell
+		}
+		if a.Keyspace != b.Keyspace {
+			return a.Keyspace < b.Keyspace
+		}
+		if a.Shard != b.Shard {
+			return a.Shard < b.Shard
+		}
+		return a.Alias.Uid < b.Alias.Uid
+	})
+
+	graph := &topologyGraph{
+		Cells:        []*topologyGraphCell{},
+		TotalTablets: len(tablets),
+	}
+	start := min(req.offset, len(tablets))
+	end := len(tablets)
+	if req.pageSize > 0 && start+req.pageSize < end {
+		end = start + req.pageSize
+		graph.NextPageToken = strconv.Itoa(end)
+	}
+	page := tablets[start:end]
+
+	graphTablets := make([]*topologyGraphTablet, len(page))
+	for i, tablet := range page {
+		twu := newTabletWithURL(tablet)
+		graphTablets[i] = &topologyGraphTablet{
+			Alias:    topoproto.TabletAliasString(tablet.Alias),
+			Type:     topoproto.TabletTypeLString(tablet.Type),
+			Hostname: tablet.Hostname,
+			Tags:     tablet.Tags,
+			URL:      twu.URL,
+		}
+	}
+	if req.health {
+		addTopologyGraphHealth(ctx, thc, page, graphTablets)
+	}
+
+	var (
+		cell     *topologyGraphCell
+		keyspace *topologyGraphKeyspace
+		shard    *topologyGraphShard
+	)
+	for i, tablet := range page {
+		if cell == nil || cell.Name != tablet.Alias.Cell {
+			cell = &topologyGraphCell{Name: tablet.Alias.Cell}
+			graph.Cells = append(graph.Cells, cell)
+			keyspace = nil
+		}
+		if keyspace == nil || keyspace.Name != tablet.Keyspace {
+			keyspace = &topologyGraphKeyspace{Name: tablet.Keyspace}
+			cell.Keyspaces = append(cell.Keyspaces, keyspace)
+			shard = nil
+		}
+		if shard == nil || shard.Name != tablet.Shard {
+			shard = &topologyGraphShard{Name: tablet.Shard}
+			keyspace.Shards = append(keyspace.Shards, shard)
+		}
+		shard.Tablets = append(shard.Tablets, graphTablets[i])
+	}
+	return graph, nil
+}
+
+// addTopologyGraphHealth fills in the health of the given tablets, fetching
+// it in parallel.
+func addTopologyGraphHealth(ctx context.Context, thc *tabletHealthCache, tablets []*topodatapb.Tablet, graphTablets []*topologyGraphTablet) {
+	ctx, cancel := context.WithTimeout(ctx, topologyGraphHealthTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, topologyGraphHealthConcurrency)
+	for i, tablet := range tablets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(tablet *topodatapb.Tablet, graphTablet *topologyGraphTablet) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			shr, err := thc.Get(ctx, tablet.Alias)
+			if err != nil {
+				graphTablet.HealthError = err.Error()
+				return
+			}
+			serving := shr.Serving
+			graphTablet.Serving = &serving
+			if stats := shr.RealtimeStats; stats != nil {
+				graphTablet.ReplicationLagSeconds = stats.ReplicationLagSeconds
+				graphTablet.HealthError = stats.HealthError
+			}
+		}(tablet, graphTablets[i])
+	}
+	wg.Wait()
+}
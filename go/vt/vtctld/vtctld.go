@@ -133,5 +133,10 @@ func InitVtctld(env *vtenv.Environment, ts *topo.Server) error {
 	// Serve the topology endpoint in the REST API at /topodata
 	initExplorer(ts)
 
+	// Serve the federated API at /api/federation/
+	if err := initFederation(env, ts); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -71,6 +71,8 @@ var (
 
 	VT07001 = errorWithState("VT07001", vtrpcpb.Code_PERMISSION_DENIED, KillDeniedError, "%s", "Kill statement is not allowed. More in docs about how to enable it and its limitations.")
 
+	VT08001 = errorWithState("VT08001", vtrpcpb.Code_RESOURCE_EXHAUSTED, UserLimitReached, "quota '%s' exceeded: %s", "The query was rejected because it exceeds the limits of a quota rule. Retry later, or ask an administrator to raise the limits of the rule.")
+
 	VT09001 = errorWithState("VT09001", vtrpcpb.Code_FAILED_PRECONDITION, RequiresPrimaryKey, PrimaryVindexNotSet, "the table does not have a primary vindex, the operation is impossible.")
 	VT09002 = errorWithState("VT09002", vtrpcpb.Code_FAILED_PRECONDITION, InnodbReadOnly, "%s statement with a replica target", "This type of DML statement is not allowed on a replica target.")
 	VT09003 = errorWithoutState("VT09003", vtrpcpb.Code_FAILED_PRECONDITION, "INSERT query does not have primary vindex column '%v' in the column list", "A vindex column is mandatory for the insert, please provide one.")
@@ -95,6 +97,7 @@ var (
 	VT09022 = errorWithoutState("VT09022", vtrpcpb.Code_FAILED_PRECONDITION, "Destination does not have exactly one shard: %v", "Cannot send query to multiple shards.")
 	VT09023 = errorWithoutState("VT09023", vtrpcpb.Code_FAILED_PRECONDITION, "could not map %v to a keyspace id", "Unable to determine the shard for the given row.")
 	VT09024 = errorWithoutState("VT09024", vtrpcpb.Code_FAILED_PRECONDITION, "could not map %v to a unique keyspace id: %v", "Unable to determine the shard for the given row.")
+	VT09025 = errorWithState("VT09025", vtrpcpb.Code_FAILED_PRECONDITION, WriteInReadOnlyTransaction, "Cannot execute statement in a READ ONLY transaction.", "The statement writes data, which is not allowed in a transaction started with START TRANSACTION READ ONLY.")
 
 	VT10001 = errorWithoutState("VT10001", vtrpcpb.Code_ABORTED, "foreign key constraints are not allowed", "Foreign key constraints are not allowed, see https://vitess.io/blog/2021-06-15-online-ddl-why-no-fk/.")
 
@@ -155,6 +158,7 @@ var (
 		VT05007,
 		VT06001,
 		VT07001,
+		VT08001,
 		VT09001,
 		VT09002,
 		VT09003,
@@ -179,6 +183,7 @@ var (
 		VT09022,
 		VT09023,
 		VT09024,
+		VT09025,
 		VT10001,
 		VT12001,
 		VT12002,
@@ -61,6 +61,7 @@ const (
 	NoReferencedRow2
 	UnknownStmtHandler
 	KeyDoesNotExist
+	WriteInReadOnlyTransaction
 
 	// not found
 	BadDb
@@ -76,6 +77,7 @@ const (
 
 	// resource exhausted
 	NetPacketTooLarge
+	UserLimitReached
 
 	// cancelled
 	QueryInterrupted
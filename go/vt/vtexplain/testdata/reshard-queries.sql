@@ -0,0 +1,16 @@
+begin;
+select * from user where id = 1;
+insert into user (id, name) values (3, 'bob');
+commit;
+
+reshard ks_sharded to 8;
+
+begin;
+select * from user where id = 1;
+update user set nickname = 'alice' where id = 3;
+commit;
+select * from user where name = 'bob';
+
+reshard ks_sharded to '-40', '40-c0', 'c0-';
+
+select * from user where id in (1, 2, 3);
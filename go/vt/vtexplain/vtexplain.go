@@ -21,6 +21,7 @@ package vtexplain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -93,6 +94,10 @@ type (
 		// Target is used to override the "database" target in the
 		// vtgate session to simulate `USE <target>`
 		Target string
+
+		// VindexLookups adds the vindex lookups of each statement to
+		// the text output
+		VindexLookups bool
 	}
 
 	// TabletQuery defines a query that was sent to a given tablet and how it was
@@ -129,6 +134,18 @@ type (
 
 		// list of queries / bind vars sent to each tablet
 		TabletActions map[string]*TabletActions
+
+		// the vindexes used to route the statement to its shards
+		VindexLookups []*VindexLookup `json:",omitempty"`
+	}
+
+	// VindexLookup defines a vindex that vtgate uses to route a statement, and
+	// the values it maps to keyspace ids.
+	VindexLookup struct {
+		Keyspace string
+		Table    string
+		Vindex   string
+		Values   []string
 	}
 
 	outputQuery struct {
@@ -149,7 +166,13 @@ type (
 		batchTime       *sync2.Batcher
 		globalTabletEnv *tabletEnv
 
-		env *vtenv.Environment
+		env           *vtenv.Environment
+		opts          *Options
+		srvTopoCounts *stats.CountersWithSingleLabel
+
+		// ctx bounds the lifetime of the test tablets, including the ones
+		// created when resharding
+		ctx context.Context
 	}
 )
 
@@ -234,36 +257,33 @@ func (vte *VTExplain) Stop() {
 	}
 }
 
+// parseSchema parses the CREATE TABLE statements of a schema. With StrictDDL,
+// the errors of all the statements that fail to parse are returned together;
+// otherwise they are logged and the statements skipped.
 func parseSchema(sqlSchema string, opts *Options, parser *sqlparser.Parser) ([]sqlparser.DDLStatement, error) {
-	parsedDDLs := make([]sqlparser.DDLStatement, 0, 16)
-	for {
-		sql, rem, err := parser.SplitStatement(sqlSchema)
-		sqlSchema = rem
+	var (
+		statements []sqlparser.ParsedStatement
+		err        error
+	)
+	if opts.StrictDDL {
+		statements, err = parser.ParseStatementsWithRecoveryStrictDDL(sqlSchema)
 		if err != nil {
 			return nil, err
 		}
-		if sql == "" {
-			break
-		}
-		sql, _ = sqlparser.SplitMarginComments(sql)
-		if sql == "" {
-			continue
-		}
-
-		var stmt sqlparser.Statement
-		if opts.StrictDDL {
-			stmt, err = parser.ParseStrictDDL(sql)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			stmt, err = parser.Parse(sql)
-			if err != nil {
-				log.Errorf("ERROR: failed to parse sql: %s, got error: %v", sql, err)
-				continue
+	} else {
+		statements, err = parser.ParseStatementsWithRecovery(sqlSchema)
+		var errs sqlparser.StatementErrors
+		if errors.As(err, &errs) {
+			for _, err := range errs {
+				log.Errorf("ERROR: failed to parse sql: %s, got error: %v", strings.TrimSpace(err.SQL), err)
 			}
 		}
-		ddl, ok := stmt.(sqlparser.DDLStatement)
+	}
+
+	parsedDDLs := make([]sqlparser.DDLStatement, 0, len(statements))
+	for _, stmt := range statements {
+		sql := strings.TrimSpace(stmt.SQL)
+		ddl, ok := stmt.Statement.(sqlparser.DDLStatement)
 		if !ok {
 			log.Infof("ignoring non-DDL statement: %s", sql)
 			continue
@@ -281,7 +301,9 @@ func parseSchema(sqlSchema string, opts *Options, parser *sqlparser.Parser) ([]s
 	return parsedDDLs, nil
 }
 
-// Run the explain analysis on the given queries
+// Run the explain analysis on the given queries. Reshard statements between
+// the queries change the shards of a keyspace for the queries that follow,
+// see parseReshard.
 func (vte *VTExplain) Run(sql string) ([]*Explain, error) {
 	explains := make([]*Explain, 0, 16)
 
@@ -306,7 +328,15 @@ func (vte *VTExplain) Run(sql string) ([]*Explain, error) {
 			return nil, err
 		}
 
-		if sql != "" {
+		if keyspace, shards, ok, err := parseReshard(sql); ok {
+			if err != nil {
+				return nil, err
+			}
+			if err := vte.reshard(vte.ctx, keyspace, shards); err != nil {
+				return nil, err
+			}
+			explains = append(explains, &Explain{SQL: sql})
+		} else if sql != "" {
 			// Reset the global time simulator unless there's an open transaction
 			// in the session from the previous statement.
 			if vte.vtgateSession == nil || !vte.vtgateSession.GetInTransaction() {
@@ -334,13 +364,63 @@ func (vte *VTExplain) explain(sql string) (*Explain, error) {
 		return nil, err
 	}
 
+	var lookups []*VindexLookup
+	for _, plan := range plans {
+		lookups = appendVindexLookups(lookups, engine.PrimitiveToPlanDescription(plan.Instructions))
+	}
+
 	return &Explain{
 		SQL:           sql,
 		Plans:         plans,
 		TabletActions: tabletActions,
+		VindexLookups: lookups,
 	}, nil
 }
 
+// appendVindexLookups appends the vindex lookups of the plan description and its inputs.
+func appendVindexLookups(lookups []*VindexLookup, pd engine.PrimitiveDescription) []*VindexLookup {
+	var keyspace string
+	if pd.Keyspace != nil {
+		keyspace = pd.Keyspace.Name
+	}
+
+	if vindex, ok := pd.Other["Vindex"].(string); ok {
+		// routes describe their table escaped, DMLs do not
+		table, _ := pd.Other["Table"].(string)
+		table = strings.ReplaceAll(table, "`", "")
+		values, _ := pd.Other["Values"].([]string)
+		lookups = append(lookups, &VindexLookup{
+			Keyspace: keyspace,
+			Table:    table,
+			Vindex:   vindex,
+			Values:   values,
+		})
+	}
+
+	// inserts route every row by all the vindexes of the table
+	if vindexValues, ok := pd.Other["VindexValues"].(map[string]string); ok {
+		table, _ := pd.Other["TableName"].(string)
+		vindexes := make([]string, 0, len(vindexValues))
+		for vindex := range vindexValues {
+			vindexes = append(vindexes, vindex)
+		}
+		sort.Strings(vindexes)
+		for _, vindex := range vindexes {
+			lookups = append(lookups, &VindexLookup{
+				Keyspace: keyspace,
+				Table:    table,
+				Vindex:   vindex,
+				Values:   []string{vindexValues[vindex]},
+			})
+		}
+	}
+
+	for _, input := range pd.Inputs {
+		lookups = appendVindexLookups(lookups, input)
+	}
+	return lookups
+}
+
 // ExplainsAsText returns a text representation of the explains in logical time
 // order
 func (vte *VTExplain) ExplainsAsText(explains []*Explain) (string, error) {
@@ -378,6 +458,18 @@ func (vte *VTExplain) ExplainsAsText(explains []*Explain) (string, error) {
 			fmt.Fprintf(&b, "%d %s: %s\n", q.Time, q.tablet, q.sql)
 		}
 		fmt.Fprintf(&b, "\n")
+
+		if vte.opts != nil && vte.opts.VindexLookups && len(explain.VindexLookups) > 0 {
+			fmt.Fprintf(&b, "vindex lookups:\n")
+			for _, l := range explain.VindexLookups {
+				target := l.Keyspace
+				if l.Table != "" {
+					target += "." + l.Table
+				}
+				fmt.Fprintf(&b, "%s %s: %s\n", target, l.Vindex, strings.Join(l.Values, ", "))
+			}
+			fmt.Fprintf(&b, "\n")
+		}
 	}
 	fmt.Fprintf(&b, "----------------------------------------------------------------------\n")
 	return b.String(), nil
@@ -0,0 +1,140 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtexplain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/vt/key"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// parseReshard recognizes the reshard statement that vtexplain accepts
+// between the statements it explains:
+//
+//	RESHARD <keyspace> TO <number of shards>
+//	RESHARD <keyspace> TO <shard>[, <shard>...]
+//
+// It returns false if sql is not a reshard statement.
+func parseReshard(sql string) (string, []*topodatapb.ShardReference, bool, error) {
+	words := strings.Fields(strings.ReplaceAll(sql, ",", " "))
+	if len(words) == 0 || !strings.EqualFold(words[0], "reshard") {
+		return "", nil, false, nil
+	}
+	if len(words) < 4 || !strings.EqualFold(words[2], "to") {
+		return "", nil, true, fmt.Errorf("invalid reshard statement '%s', expected 'reshard <keyspace> to <number of shards | shards>'", sql)
+	}
+	keyspace := strings.Trim(words[1], "`")
+
+	if len(words) == 4 {
+		if numShards, err := strconv.Atoi(words[3]); err == nil {
+			if numShards < 1 {
+				return "", nil, true, fmt.Errorf("invalid number of shards in '%s'", sql)
+			}
+			shards, err := evenShardRanges(numShards)
+			return keyspace, shards, true, err
+		}
+	}
+
+	shards := make([]*topodatapb.ShardReference, 0, len(words)-3)
+	for _, word := range words[3:] {
+		name, kr, err := topo.ValidateShardName(strings.Trim(word, `'"`))
+		if err != nil {
+			return "", nil, true, fmt.Errorf("invalid shard in '%s': %v", sql, err)
+		}
+		if kr == nil {
+			return "", nil, true, fmt.Errorf("shard %s in '%s' is not a key range", name, sql)
+		}
+		shards = append(shards, &topodatapb.ShardReference{Name: name, KeyRange: kr})
+	}
+	if err := validateShardCoverage(shards); err != nil {
+		return "", nil, true, fmt.Errorf("invalid shards in '%s': %v", sql, err)
+	}
+	return keyspace, shards, true, nil
+}
+
+// validateShardCoverage makes sure the shards cover the whole key space
+// exactly once.
+func validateShardCoverage(shards []*topodatapb.ShardReference) error {
+	sort.Slice(shards, func(i, j int) bool {
+		return key.KeyRangeLess(shards[i].KeyRange, shards[j].KeyRange)
+	})
+	if len(shards[0].KeyRange.Start) != 0 {
+		return fmt.Errorf("no shard starts at the beginning of the key space")
+	}
+	for i := 1; i < len(shards); i++ {
+		if !key.KeyRangeContiguous(shards[i-1].KeyRange, shards[i].KeyRange) {
+			return fmt.Errorf("shards %s and %s are not contiguous", shards[i-1].Name, shards[i].Name)
+		}
+	}
+	if len(shards[len(shards)-1].KeyRange.End) != 0 {
+		return fmt.Errorf("no shard ends at the end of the key space")
+	}
+	return nil
+}
+
+// reshard replaces the shards of a sharded keyspace with the given ones, as
+// if a resharding workflow had switched its traffic to them.
+func (vte *VTExplain) reshard(ctx context.Context, keyspace string, shards []*topodatapb.ShardReference) error {
+	if vte.vtgateSession.GetInTransaction() {
+		return fmt.Errorf("cannot reshard keyspace %s in the middle of a transaction", keyspace)
+	}
+
+	vte.explainTopo.Lock.Lock()
+	defer vte.explainTopo.Lock.Unlock()
+
+	vschema, ok := vte.explainTopo.Keyspaces[keyspace]
+	if !ok {
+		return fmt.Errorf("cannot reshard keyspace %s: keyspace not found", keyspace)
+	}
+	if !vschema.Sharded {
+		return fmt.Errorf("cannot reshard keyspace %s: keyspace is not sharded", keyspace)
+	}
+
+	for _, tablet := range vte.healthCheck.GetAllTablets() {
+		if tablet.Keyspace == keyspace {
+			vte.healthCheck.RemoveTablet(tablet)
+		}
+	}
+	for shard := range vte.explainTopo.KeyspaceShards[keyspace] {
+		hostname := fmt.Sprintf("%s/%s", keyspace, shard)
+		if conn, ok := vte.explainTopo.TabletConns[hostname]; ok {
+			conn.tsv.StopService()
+			conn.tsv.Close(ctx)
+			conn.db.Close()
+			delete(vte.explainTopo.TabletConns, hostname)
+		}
+	}
+	vte.explainTopo.KeyspaceShards[keyspace] = make(map[string]*topodatapb.ShardReference)
+
+	ts := vte.explainTopo.TopoServer
+	if err := writeSrvKeyspace(ctx, ts, keyspace, shards); err != nil {
+		return err
+	}
+	for _, shard := range shards {
+		log.Infof("resharding keyspace %s: adding shard %s", keyspace, shard.Name)
+		vte.addShardTablet(ctx, ts, keyspace, shard)
+	}
+	return nil
+}
@@ -149,8 +149,13 @@ func TestExplain(t *testing.T) {
 		name string
 		opts *Options
 	}
+	reshardOpts := defaultTestOpts()
+	reshardOpts.Normalize = false
+	reshardOpts.VindexLookups = true
+
 	tests := []test{
 		{"unsharded", defaultTestOpts()},
+		{"reshard", reshardOpts},
 	}
 
 	for _, tst := range tests {
@@ -187,6 +192,36 @@ func TestErrors(t *testing.T) {
 			SQL: "SELECT * FROM table_not_in_schema",
 			Err: "unknown error: unable to resolve table name table_not_in_schema",
 		},
+
+		{
+			SQL: "reshard ks_sharded",
+			Err: "invalid reshard statement 'reshard ks_sharded'",
+		},
+
+		{
+			SQL: "reshard ks_sharded to '-40', '80-'",
+			Err: "shards -40 and 80- are not contiguous",
+		},
+
+		{
+			SQL: "reshard ks_sharded to '-40', '40-80'",
+			Err: "no shard ends at the end of the key space",
+		},
+
+		{
+			SQL: "reshard ks_unsharded to 2",
+			Err: "cannot reshard keyspace ks_unsharded: keyspace is not sharded",
+		},
+
+		{
+			SQL: "reshard ks_unknown to 2",
+			Err: "cannot reshard keyspace ks_unknown: keyspace not found",
+		},
+
+		{
+			SQL: "begin; reshard ks_sharded to 2",
+			Err: "cannot reshard keyspace ks_sharded in the middle of a transaction",
+		},
 	}
 
 	for _, test := range tests {
@@ -49,13 +49,16 @@ import (
 )
 
 func (vte *VTExplain) initVtgateExecutor(ctx context.Context, ts *topo.Server, vSchemaStr, ksShardMapStr string, opts *Options, srvTopoCounts *stats.CountersWithSingleLabel) error {
+	vte.ctx = ctx
+	vte.opts = opts
+	vte.srvTopoCounts = srvTopoCounts
 	vte.explainTopo = &ExplainTopo{NumShards: opts.NumShards}
 	vte.explainTopo.TopoServer = ts
 	vte.healthCheck = discovery.NewFakeHealthCheck(nil)
 
 	resolver := vte.newFakeResolver(ctx, opts, vte.explainTopo, Cell)
 
-	err := vte.buildTopology(ctx, ts, opts, vSchemaStr, ksShardMapStr, opts.NumShards, srvTopoCounts)
+	err := vte.buildTopology(ctx, ts, vSchemaStr, ksShardMapStr, opts.NumShards)
 	if err != nil {
 		return err
 	}
@@ -93,7 +96,7 @@ func (vte *VTExplain) newFakeResolver(ctx context.Context, opts *Options, serv s
 	return vtgate.NewResolver(srvResolver, serv, cell, sc)
 }
 
-func (vte *VTExplain) buildTopology(ctx context.Context, ts *topo.Server, opts *Options, vschemaStr string, ksShardMapStr string, numShardsPerKeyspace int, srvTopoCounts *stats.CountersWithSingleLabel) error {
+func (vte *VTExplain) buildTopology(ctx context.Context, ts *topo.Server, vschemaStr string, ksShardMapStr string, numShardsPerKeyspace int) error {
 	vte.explainTopo.Lock.Lock()
 	defer vte.explainTopo.Lock.Unlock()
 
@@ -118,10 +121,6 @@ func (vte *VTExplain) buildTopology(ctx context.Context, ts *topo.Server, opts *
 		return err
 	}
 
-	conn, err := ts.ConnForCell(ctx, Cell)
-	if err != nil {
-		return err
-	}
 	vte.explainTopo.TabletConns = make(map[string]*explainTablet)
 	vte.explainTopo.KeyspaceShards = make(map[string]map[string]*topodatapb.ShardReference)
 	for ks, vschema := range vte.explainTopo.Keyspaces {
@@ -132,29 +131,7 @@ func (vte *VTExplain) buildTopology(ctx context.Context, ts *topo.Server, opts *
 
 		vte.explainTopo.KeyspaceShards[ks] = make(map[string]*topodatapb.ShardReference)
 
-		srvPath := path.Join(topo.KeyspacesPath, ks, topo.SrvKeyspaceFile)
-		srvKeyspace := &topodatapb.SrvKeyspace{
-			Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{
-				{
-					ServedType:      topodatapb.TabletType_PRIMARY,
-					ShardReferences: shards,
-				},
-				{
-					ServedType:      topodatapb.TabletType_REPLICA,
-					ShardReferences: shards,
-				},
-				{
-					ServedType:      topodatapb.TabletType_RDONLY,
-					ShardReferences: shards,
-				},
-			},
-		}
-		data, err := srvKeyspace.MarshalVT()
-		if err != nil {
-			return err
-		}
-		_, err = conn.Update(ctx, srvPath, data, nil)
-		if err != nil {
+		if err := writeSrvKeyspace(ctx, ts, ks, shards); err != nil {
 			return err
 		}
 
@@ -167,19 +144,56 @@ func (vte *VTExplain) buildTopology(ctx context.Context, ts *topo.Server, opts *
 			if shardInfo, ok := ksShardMap[ks][shard.Name]; ok && !shardInfo.IsPrimaryServing {
 				continue
 			}
-			hostname := fmt.Sprintf("%s/%s", ks, shard.Name)
-			log.Infof("registering test tablet %s for keyspace %s shard %s", hostname, ks, shard.Name)
-
-			tablet := vte.healthCheck.AddFakeTablet(Cell, hostname, 1, ks, shard.Name, topodatapb.TabletType_PRIMARY, true, 1, nil, func(t *topodatapb.Tablet) queryservice.QueryService {
-				return vte.newTablet(ctx, vte.env, opts, t, ts, srvTopoCounts)
-			})
-			vte.explainTopo.TabletConns[hostname] = tablet.(*explainTablet)
-			vte.explainTopo.KeyspaceShards[ks][shard.Name] = shard
+			vte.addShardTablet(ctx, ts, ks, shard)
 		}
 	}
 	return err
 }
 
+// writeSrvKeyspace stores a SrvKeyspace serving all tablet types from the given shards.
+func writeSrvKeyspace(ctx context.Context, ts *topo.Server, ks string, shards []*topodatapb.ShardReference) error {
+	conn, err := ts.ConnForCell(ctx, Cell)
+	if err != nil {
+		return err
+	}
+
+	srvPath := path.Join(topo.KeyspacesPath, ks, topo.SrvKeyspaceFile)
+	srvKeyspace := &topodatapb.SrvKeyspace{
+		Partitions: []*topodatapb.SrvKeyspace_KeyspacePartition{
+			{
+				ServedType:      topodatapb.TabletType_PRIMARY,
+				ShardReferences: shards,
+			},
+			{
+				ServedType:      topodatapb.TabletType_REPLICA,
+				ShardReferences: shards,
+			},
+			{
+				ServedType:      topodatapb.TabletType_RDONLY,
+				ShardReferences: shards,
+			},
+		},
+	}
+	data, err := srvKeyspace.MarshalVT()
+	if err != nil {
+		return err
+	}
+	_, err = conn.Update(ctx, srvPath, data, nil)
+	return err
+}
+
+// addShardTablet registers the test tablet of a shard. The caller must hold the explainTopo lock.
+func (vte *VTExplain) addShardTablet(ctx context.Context, ts *topo.Server, ks string, shard *topodatapb.ShardReference) {
+	hostname := fmt.Sprintf("%s/%s", ks, shard.Name)
+	log.Infof("registering test tablet %s for keyspace %s shard %s", hostname, ks, shard.Name)
+
+	tablet := vte.healthCheck.AddFakeTablet(Cell, hostname, 1, ks, shard.Name, topodatapb.TabletType_PRIMARY, true, 1, nil, func(t *topodatapb.Tablet) queryservice.QueryService {
+		return vte.newTablet(ctx, vte.env, vte.opts, t, ts, vte.srvTopoCounts)
+	})
+	vte.explainTopo.TabletConns[hostname] = tablet.(*explainTablet)
+	vte.explainTopo.KeyspaceShards[ks][shard.Name] = shard
+}
+
 func getKeyspaceShardMap(ksShardMapStr string) (map[string]map[string]*topo.ShardInfo, error) {
 	if ksShardMapStr == "" {
 		return map[string]map[string]*topo.ShardInfo{}, nil
@@ -212,7 +226,11 @@ func getShardRanges(ks string, vschema *vschemapb.Keyspace, ksShardMap map[strin
 	if vschema.Sharded {
 		numShards = numShardsPerKeyspace
 	}
+	return evenShardRanges(numShards)
+}
 
+// evenShardRanges splits the key space in numShards shards of the same size.
+func evenShardRanges(numShards int) ([]*topodatapb.ShardReference, error) {
 	shards := make([]*topodatapb.ShardReference, numShards)
 
 	for i := 0; i < numShards; i++ {
@@ -204,3 +204,23 @@ func TestErrParseSchema(t *testing.T) {
 	_, err = newTabletEnvironment(ddl, defaultTestOpts(), collations.MySQL8())
 	require.Error(t, err, "check your schema, table[t2] doesn't exist")
 }
+
+func TestParseSchemaAllErrors(t *testing.T) {
+	testSchema := "create table t1 (id int primary key);\n" +
+		"create table t2 (id int primary key,;\n" +
+		"create table t3 (id int primary key);\n" +
+		"create tabel t4 (id int primary key);\n"
+
+	_, err := parseSchema(testSchema, &Options{StrictDDL: true}, sqlparser.NewTestParser())
+	var errs sqlparser.StatementErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 2)
+	assert.Equal(t, 2, errs[0].Line)
+	assert.Equal(t, 4, errs[1].Line)
+
+	ddls, err := parseSchema(testSchema, &Options{StrictDDL: false}, sqlparser.NewTestParser())
+	require.NoError(t, err)
+	require.Len(t, ddls, 2)
+	assert.Equal(t, "t1", ddls[0].GetTable().Name.String())
+	assert.Equal(t, "t3", ddls[1].GetTable().Name.String())
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arrowipc
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// table is a flatbuffers table, with the value of each field at the index of
+// its slot in the schema. A nil value leaves the field out of the table.
+//
+// The values can be uint8, bool, int16, int32 and int64 scalars, strings,
+// tables, vectors of tables and vectors of structs.
+type table []any
+
+// structs is a vector of structs, aligned on 8 bytes like all the structs of
+// the Arrow format.
+type structs struct {
+	count int
+	data  []byte
+}
+
+// finish serializes t as the root table of a flatbuffer.
+//
+// Unlike the builders of the flatbuffers library, which write the buffer from
+// its end, the objects are written front to back: every object is followed by
+// the objects it references, which keeps the offsets positive.
+func finish(t table) []byte {
+	w := &fbWriter{buf: make([]byte, 4, 256)}
+	root := w.writeTable(t)
+	binary.LittleEndian.PutUint32(w.buf, uint32(root))
+	return w.buf
+}
+
+type fbWriter struct {
+	buf []byte
+}
+
+func (w *fbWriter) pad(align int) {
+	for len(w.buf)%align != 0 {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+// putOffset sets the uoffset at pos to reference the object at target.
+func (w *fbWriter) putOffset(pos, target int) {
+	binary.LittleEndian.PutUint32(w.buf[pos:], uint32(target-pos))
+}
+
+func inlineSize(v any) int {
+	switch v.(type) {
+	case uint8, bool:
+		return 1
+	case int16:
+		return 2
+	case int64:
+		return 8
+	default:
+		// int32 scalars and the uoffsets of the other objects
+		return 4
+	}
+}
+
+func (w *fbWriter) writeTable(t table) int {
+	offsets := make([]int, len(t))
+	size := 4
+	for i, v := range t {
+		if v == nil {
+			continue
+		}
+		s := inlineSize(v)
+		size = (size + s - 1) / s * s
+		offsets[i] = size
+		size += s
+	}
+
+	w.pad(2)
+	vtable := len(w.buf)
+	w.buf = binary.LittleEndian.AppendUint16(w.buf, uint16(4+2*len(t)))
+	w.buf = binary.LittleEndian.AppendUint16(w.buf, uint16(size))
+	for _, offset := range offsets {
+		w.buf = binary.LittleEndian.AppendUint16(w.buf, uint16(offset))
+	}
+
+	w.pad(8)
+	pos := len(w.buf)
+	w.buf = append(w.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(w.buf[pos:], uint32(int32(pos-vtable)))
+	for i, v := range t {
+		field := pos + offsets[i]
+		switch v := v.(type) {
+		case nil:
+		case uint8:
+			w.buf[field] = v
+		case bool:
+			if v {
+				w.buf[field] = 1
+			}
+		case int16:
+			binary.LittleEndian.PutUint16(w.buf[field:], uint16(v))
+		case int32:
+			binary.LittleEndian.PutUint32(w.buf[field:], uint32(v))
+		case int64:
+			binary.LittleEndian.PutUint64(w.buf[field:], uint64(v))
+		case string:
+			w.putOffset(field, w.writeString(v))
+		case table:
+			w.putOffset(field, w.writeTable(v))
+		case []table:
+			w.putOffset(field, w.writeTables(v))
+		case structs:
+			w.putOffset(field, w.writeStructs(v))
+		default:
+			panic(fmt.Sprintf("unsupported flatbuffers value %T", v))
+		}
+	}
+	return pos
+}
+
+func (w *fbWriter) writeString(s string) int {
+	w.pad(4)
+	pos := len(w.buf)
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(len(s)))
+	w.buf = append(w.buf, s...)
+	w.buf = append(w.buf, 0)
+	return pos
+}
+
+func (w *fbWriter) writeTables(tables []table) int {
+	w.pad(4)
+	pos := len(w.buf)
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(len(tables)))
+	w.buf = append(w.buf, make([]byte, 4*len(tables))...)
+	for i, t := range tables {
+		w.putOffset(pos+4+4*i, w.writeTable(t))
+	}
+	return pos
+}
+
+func (w *fbWriter) writeStructs(s structs) int {
+	// the length precedes the elements, which are aligned on 8 bytes
+	w.pad(8)
+	w.buf = append(w.buf, 0, 0, 0, 0)
+	pos := len(w.buf)
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(s.count))
+	w.buf = append(w.buf, s.data...)
+	return pos
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arrowipc
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/mysql/datetime"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The ids of the Arrow types in the Type union of Schema.fbs.
+const (
+	typeNull          = 1
+	typeInt           = 2
+	typeFloatingPoint = 3
+	typeBinary        = 4
+	typeUtf8          = 5
+	typeDecimal       = 7
+	typeDate          = 8
+	typeTimestamp     = 10
+	typeDuration      = 18
+)
+
+const (
+	precisionSingle  = 1
+	precisionDouble  = 2
+	dateUnitDay      = 0
+	timeUnitMicro    = 2
+	maxDecimalDigits = 38
+)
+
+// column is the Arrow type a column of a query result is encoded as.
+type column struct {
+	typeID uint8
+	typ    table
+	// width is the size of the fixed-width values, or 0 for the variable-length
+	// values, encoded as offsets and data, and for the Null type, which has no
+	// buffers.
+	width int
+	// encode appends the value v to dst. It returns false if v has no Arrow
+	// representation and is encoded as null.
+	encode func(dst []byte, v sqltypes.Value) ([]byte, bool, error)
+}
+
+// columnFor maps the MySQL type of field to the Arrow type of its column:
+//
+//   - the integral types map to the integers of the same sign and width;
+//   - FLOAT and DOUBLE map to the floating points of the same precision;
+//   - DECIMAL maps to a Decimal128 of the same precision and scale, or to Utf8
+//     if it has more than 38 digits;
+//   - DATE maps to Date32, and DATETIME and TIMESTAMP to microsecond timestamps
+//     without time zone. The zero dates, which Arrow cannot represent, are null;
+//   - TIME maps to a microsecond Duration, since it is not a time of the day;
+//   - the text types, ENUM, SET and JSON map to Utf8, and the binary types,
+//     BIT and GEOMETRY to Binary.
+func columnFor(field *querypb.Field) column {
+	typ := field.Type
+	switch {
+	case typ == sqltypes.Null:
+		return column{typeID: typeNull, typ: table{}}
+	case typ == sqltypes.Int8:
+		return intColumn(8, true)
+	case typ == sqltypes.Uint8:
+		return intColumn(8, false)
+	case typ == sqltypes.Int16:
+		return intColumn(16, true)
+	case typ == sqltypes.Uint16, typ == sqltypes.Year:
+		return intColumn(16, false)
+	case typ == sqltypes.Int24, typ == sqltypes.Int32:
+		return intColumn(32, true)
+	case typ == sqltypes.Uint24, typ == sqltypes.Uint32:
+		return intColumn(32, false)
+	case typ == sqltypes.Int64:
+		return intColumn(64, true)
+	case typ == sqltypes.Uint64:
+		return intColumn(64, false)
+	case typ == sqltypes.Float32:
+		return column{typeID: typeFloatingPoint, typ: table{int16(precisionSingle)}, width: 4, encode: encodeFloat32}
+	case typ == sqltypes.Float64:
+		return column{typeID: typeFloatingPoint, typ: table{int16(precisionDouble)}, width: 8, encode: encodeFloat64}
+	case typ == sqltypes.Decimal:
+		if precision, ok := decimalPrecision(field); ok {
+			return decimalColumn(precision, int(field.Decimals))
+		}
+		return column{typeID: typeUtf8, typ: table{}, encode: encodeBytes}
+	case typ == sqltypes.Date:
+		return column{typeID: typeDate, typ: table{int16(dateUnitDay)}, width: 4, encode: encodeDate}
+	case typ == sqltypes.Datetime, typ == sqltypes.Timestamp:
+		return column{typeID: typeTimestamp, typ: table{int16(timeUnitMicro)}, width: 8, encode: encodeDatetime}
+	case typ == sqltypes.Time:
+		return column{typeID: typeDuration, typ: table{int16(timeUnitMicro)}, width: 8, encode: encodeTime}
+	case sqltypes.IsText(typ), typ == sqltypes.Enum, typ == sqltypes.Set, typ == sqltypes.TypeJSON:
+		return column{typeID: typeUtf8, typ: table{}, encode: encodeBytes}
+	default:
+		return column{typeID: typeBinary, typ: table{}, encode: encodeBytes}
+	}
+}
+
+func intColumn(bits int, signed bool) column {
+	c := column{typeID: typeInt, typ: table{int32(bits), signed}, width: bits / 8}
+	c.encode = func(dst []byte, v sqltypes.Value) ([]byte, bool, error) {
+		var u uint64
+		if signed {
+			i, err := v.ToInt64()
+			if err != nil {
+				return dst, false, err
+			}
+			u = uint64(i)
+		} else {
+			var err error
+			if u, err = v.ToUint64(); err != nil {
+				return dst, false, err
+			}
+		}
+		switch bits {
+		case 8:
+			return append(dst, uint8(u)), true, nil
+		case 16:
+			return binary.LittleEndian.AppendUint16(dst, uint16(u)), true, nil
+		case 32:
+			return binary.LittleEndian.AppendUint32(dst, uint32(u)), true, nil
+		default:
+			return binary.LittleEndian.AppendUint64(dst, u), true, nil
+		}
+	}
+	return c
+}
+
+func encodeFloat32(dst []byte, v sqltypes.Value) ([]byte, bool, error) {
+	f, err := v.ToFloat64()
+	if err != nil {
+		return dst, false, err
+	}
+	return binary.LittleEndian.AppendUint32(dst, math.Float32bits(float32(f))), true, nil
+}
+
+func encodeFloat64(dst []byte, v sqltypes.Value) ([]byte, bool, error) {
+	f, err := v.ToFloat64()
+	if err != nil {
+		return dst, false, err
+	}
+	return binary.LittleEndian.AppendUint64(dst, math.Float64bits(f)), true, nil
+}
+
+func encodeBytes(dst []byte, v sqltypes.Value) ([]byte, bool, error) {
+	return append(dst, v.Raw()...), true, nil
+}
+
+// decimalPrecision returns the number of digits of the DECIMAL field, whose
+// length counts its sign and decimal point too.
+func decimalPrecision(field *querypb.Field) (int, bool) {
+	precision := int(field.ColumnLength)
+	if field.Flags&uint32(querypb.MySqlFlag_UNSIGNED_FLAG) == 0 {
+		precision--
+	}
+	if field.Decimals > 0 {
+		precision--
+	}
+	if precision < int(field.Decimals) || precision < 1 {
+		precision = maxDecimalDigits
+	}
+	return precision, precision <= maxDecimalDigits
+}
+
+func decimalColumn(precision, scale int) column {
+	c := column{typeID: typeDecimal, typ: table{int32(precision), int32(scale), int32(128)}, width: 16}
+	limit := new(big.Int).Lsh(big.NewInt(1), 127)
+	c.encode = func(dst []byte, v sqltypes.Value) ([]byte, bool, error) {
+		s := strings.TrimSpace(v.ToString())
+		neg := strings.HasPrefix(s, "-")
+		s = strings.TrimPrefix(s, "-")
+		intPart, frac, _ := strings.Cut(s, ".")
+		if len(frac) > scale {
+			frac = frac[:scale]
+		}
+		frac += strings.Repeat("0", scale-len(frac))
+
+		unscaled, ok := new(big.Int).SetString(intPart+frac, 10)
+		if !ok || unscaled.Cmp(limit) >= 0 {
+			return dst, false, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "cannot encode %q as a DECIMAL(%d,%d)", v.ToString(), precision, scale)
+		}
+		if neg {
+			// two's complement on 128 bits
+			unscaled.Sub(new(big.Int).Lsh(limit, 1), unscaled)
+		}
+		var be [16]byte
+		unscaled.FillBytes(be[:])
+		for i := 15; i >= 0; i-- {
+			dst = append(dst, be[i])
+		}
+		return dst, true, nil
+	}
+	return c
+}
+
+func parseDate(s string) (time.Time, bool) {
+	d, ok := datetime.ParseDate(s)
+	if !ok || d.Month() == 0 || d.Day() == 0 {
+		return time.Time{}, false
+	}
+	return d.ToStdTime(time.UTC), true
+}
+
+func encodeDate(dst []byte, v sqltypes.Value) ([]byte, bool, error) {
+	t, ok := parseDate(v.ToString())
+	if !ok {
+		return dst, false, nil
+	}
+	return binary.LittleEndian.AppendUint32(dst, uint32(int32(t.Unix()/86400))), true, nil
+}
+
+func encodeDatetime(dst []byte, v sqltypes.Value) ([]byte, bool, error) {
+	dt, _, ok := datetime.ParseDateTime(v.ToString(), -1)
+	if !ok || dt.Date.Month() == 0 || dt.Date.Day() == 0 {
+		return dst, false, nil
+	}
+	t := dt.Date.ToStdTime(time.UTC).Add(dt.Time.ToDuration())
+	return binary.LittleEndian.AppendUint64(dst, uint64(t.UnixMicro())), true, nil
+}
+
+func encodeTime(dst []byte, v sqltypes.Value) ([]byte, bool, error) {
+	t, _, state := datetime.ParseTime(v.ToString(), -1)
+	if state != datetime.TimeOK {
+		return dst, false, nil
+	}
+	return binary.LittleEndian.AppendUint64(dst, uint64(t.ToDuration().Microseconds())), true, nil
+}
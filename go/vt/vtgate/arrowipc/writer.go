@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package arrowipc encodes the results of queries in the Apache Arrow IPC
+// streaming format, for the clients that consume large results as columns:
+// https://arrow.apache.org/docs/format/Columnar.html#ipc-streaming-format
+package arrowipc
+
+import (
+	"encoding/binary"
+	"math"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// The values of the Message table of Message.fbs.
+const (
+	metadataV5        = 4
+	headerSchema      = 1
+	headerRecordBatch = 3
+)
+
+// continuation marks the start of every message of the stream, and followed
+// by a zero length, its end.
+const continuation = 0xFFFFFFFF
+
+// Writer encodes the results of a streaming query as an Arrow IPC stream: the
+// schema of the fields of the query, a record batch per result with rows, and
+// the end of the stream.
+type Writer struct {
+	columns []column
+	// started is set once the schema is written.
+	started bool
+}
+
+// NewWriter returns a Writer for a new stream.
+func NewWriter() *Writer {
+	return &Writer{}
+}
+
+// Write returns the part of the stream encoding qr: the schema if qr has the
+// fields of the query, followed by a record batch if qr has rows.
+func (w *Writer) Write(qr *sqltypes.Result) ([]byte, error) {
+	var out []byte
+	if len(qr.Fields) > 0 && !w.started {
+		w.columns = make([]column, 0, len(qr.Fields))
+		fields := make([]table, 0, len(qr.Fields))
+		for _, field := range qr.Fields {
+			c := columnFor(field)
+			w.columns = append(w.columns, c)
+			// The Field table: name, nullable, the type union and children.
+			fields = append(fields, table{field.Name, true, c.typeID, c.typ, nil, []table{}})
+		}
+		out = appendMessage(out, headerSchema, table{int16(0), fields}, nil)
+		w.started = true
+	}
+	if len(qr.Rows) == 0 {
+		return out, nil
+	}
+	if !w.started {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "rows of a result streamed before its fields")
+	}
+	batch, body, err := w.recordBatch(qr.Rows)
+	if err != nil {
+		return nil, err
+	}
+	return appendMessage(out, headerRecordBatch, batch, body), nil
+}
+
+// Close returns the end of the stream, preceded by an empty schema if the
+// query had no fields.
+func (w *Writer) Close() []byte {
+	var out []byte
+	if !w.started {
+		out = appendMessage(out, headerSchema, table{int16(0), []table{}}, nil)
+		w.started = true
+	}
+	out = binary.LittleEndian.AppendUint32(out, continuation)
+	return binary.LittleEndian.AppendUint32(out, 0)
+}
+
+// recordBatch encodes the columns of rows as the buffers of a record batch body.
+func (w *Writer) recordBatch(rows []sqltypes.Row) (table, []byte, error) {
+	var nodes, buffers, body []byte
+	addBuffer := func(b []byte) {
+		buffers = binary.LittleEndian.AppendUint64(buffers, uint64(len(body)))
+		buffers = binary.LittleEndian.AppendUint64(buffers, uint64(len(b)))
+		body = append(body, b...)
+		for len(body)%8 != 0 {
+			body = append(body, 0)
+		}
+	}
+
+	nbuffers := 0
+	for i, c := range w.columns {
+		nulls := 0
+		validity := make([]byte, (len(rows)+7)/8)
+		var offsets, data []byte
+		if c.width == 0 {
+			offsets = binary.LittleEndian.AppendUint32(offsets, 0)
+		}
+		for r, row := range rows {
+			var ok bool
+			if c.typeID != typeNull && !row[i].IsNull() {
+				var err error
+				if data, ok, err = c.encode(data, row[i]); err != nil {
+					return nil, nil, err
+				}
+			}
+			if ok {
+				validity[r/8] |= 1 << (r % 8)
+			} else {
+				nulls++
+				data = append(data, make([]byte, c.width)...)
+			}
+			if c.width == 0 {
+				if len(data) > math.MaxInt32 {
+					return nil, nil, vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "column %d of the record batch exceeds 2GB", i)
+				}
+				offsets = binary.LittleEndian.AppendUint32(offsets, uint32(len(data)))
+			}
+		}
+
+		nodes = binary.LittleEndian.AppendUint64(nodes, uint64(len(rows)))
+		nodes = binary.LittleEndian.AppendUint64(nodes, uint64(nulls))
+		if c.typeID == typeNull {
+			continue
+		}
+		if nulls == 0 {
+			validity = nil
+		}
+		addBuffer(validity)
+		if c.width == 0 {
+			addBuffer(offsets)
+			nbuffers++
+		}
+		addBuffer(data)
+		nbuffers += 2
+	}
+
+	batch := table{
+		int64(len(rows)),
+		structs{count: len(w.columns), data: nodes},
+		structs{count: nbuffers, data: buffers},
+	}
+	return batch, body, nil
+}
+
+// appendMessage appends the encapsulated message with the given header and
+// body to out: the continuation, the length of the metadata padded to 8 bytes,
+// the Message flatbuffer and the body.
+func appendMessage(out []byte, headerType uint8, header table, body []byte) []byte {
+	metadata := finish(table{int16(metadataV5), headerType, header, int64(len(body))})
+	for len(metadata)%8 != 0 {
+		metadata = append(metadata, 0)
+	}
+	out = binary.LittleEndian.AppendUint32(out, continuation)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(metadata)))
+	out = append(out, metadata...)
+	return append(out, body...)
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arrowipc
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// fbReader reads a table of a flatbuffer.
+type fbReader struct {
+	buf []byte
+	pos int
+}
+
+func root(buf []byte) fbReader {
+	return fbReader{buf: buf, pos: int(binary.LittleEndian.Uint32(buf))}
+}
+
+func (r fbReader) field(slot int) int {
+	vtable := r.pos - int(int32(binary.LittleEndian.Uint32(r.buf[r.pos:])))
+	if 4+2*slot >= int(binary.LittleEndian.Uint16(r.buf[vtable:])) {
+		return 0
+	}
+	offset := int(binary.LittleEndian.Uint16(r.buf[vtable+4+2*slot:]))
+	if offset == 0 {
+		return 0
+	}
+	return r.pos + offset
+}
+
+func (r fbReader) deref(pos int) int {
+	return pos + int(binary.LittleEndian.Uint32(r.buf[pos:]))
+}
+
+func (r fbReader) uint8(slot int) uint8 {
+	return r.buf[r.field(slot)]
+}
+
+func (r fbReader) int16(slot int) int16 {
+	return int16(binary.LittleEndian.Uint16(r.buf[r.field(slot):]))
+}
+
+func (r fbReader) int32(slot int) int32 {
+	return int32(binary.LittleEndian.Uint32(r.buf[r.field(slot):]))
+}
+
+func (r fbReader) int64(slot int) int64 {
+	return int64(binary.LittleEndian.Uint64(r.buf[r.field(slot):]))
+}
+
+func (r fbReader) string(slot int) string {
+	pos := r.deref(r.field(slot))
+	n := int(binary.LittleEndian.Uint32(r.buf[pos:]))
+	return string(r.buf[pos+4 : pos+4+n])
+}
+
+func (r fbReader) table(slot int) fbReader {
+	return fbReader{buf: r.buf, pos: r.deref(r.field(slot))}
+}
+
+func (r fbReader) tables(slot int) []fbReader {
+	pos := r.deref(r.field(slot))
+	var tables []fbReader
+	for i := 0; i < int(binary.LittleEndian.Uint32(r.buf[pos:])); i++ {
+		tables = append(tables, fbReader{buf: r.buf, pos: r.deref(pos + 4 + 4*i)})
+	}
+	return tables
+}
+
+// int64s reads a vector of structs as their int64 fields.
+func (r fbReader) int64s(slot int, fields int) []int64 {
+	pos := r.deref(r.field(slot))
+	var values []int64
+	n := int(binary.LittleEndian.Uint32(r.buf[pos:]))
+	for i := 0; i < n*fields; i++ {
+		values = append(values, int64(binary.LittleEndian.Uint64(r.buf[pos+4+8*i:])))
+	}
+	return values
+}
+
+type message struct {
+	headerType uint8
+	header     fbReader
+	body       []byte
+}
+
+// readStream reads the messages of an IPC stream, checking its framing.
+func readStream(t *testing.T, stream []byte) []message {
+	var messages []message
+	for {
+		require.GreaterOrEqual(t, len(stream), 8)
+		require.EqualValues(t, continuation, binary.LittleEndian.Uint32(stream))
+		length := int(binary.LittleEndian.Uint32(stream[4:]))
+		if length == 0 {
+			require.Len(t, stream, 8, "data after the end of the stream")
+			return messages
+		}
+		require.Zero(t, length%8, "unaligned metadata")
+		m := root(stream[8 : 8+length])
+		assert.EqualValues(t, metadataV5, m.int16(0))
+		bodyLength := int(m.int64(3))
+		require.Zero(t, bodyLength%8, "unaligned body")
+		messages = append(messages, message{
+			headerType: m.uint8(1),
+			header:     m.table(2),
+			body:       stream[8+length : 8+length+bodyLength],
+		})
+		stream = stream[8+length+bodyLength:]
+	}
+}
+
+func TestWriter(t *testing.T) {
+	fields := []*querypb.Field{
+		{Name: "id", Type: sqltypes.Int64},
+		{Name: "tiny", Type: sqltypes.Uint8},
+		{Name: "name", Type: sqltypes.VarChar},
+		{Name: "price", Type: sqltypes.Decimal, ColumnLength: 12, Decimals: 2},
+		{Name: "created", Type: sqltypes.Datetime},
+		{Name: "day", Type: sqltypes.Date},
+		{Name: "elapsed", Type: sqltypes.Time},
+		{Name: "ratio", Type: sqltypes.Float64},
+		{Name: "data", Type: sqltypes.Blob},
+		{Name: "nothing", Type: sqltypes.Null},
+	}
+	rows := [][]sqltypes.Value{{
+		sqltypes.NewInt64(-1),
+		sqltypes.NewUint64(200),
+		sqltypes.NewVarChar("alice"),
+		sqltypes.MakeTrusted(sqltypes.Decimal, []byte("-12.34")),
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte("2024-02-03 04:05:06.000007")),
+		sqltypes.MakeTrusted(sqltypes.Date, []byte("1970-01-02")),
+		sqltypes.MakeTrusted(sqltypes.Time, []byte("-838:59:59")),
+		sqltypes.NewFloat64(0.5),
+		sqltypes.MakeTrusted(sqltypes.Blob, []byte{0, 1}),
+		sqltypes.NULL,
+	}, {
+		sqltypes.NULL,
+		sqltypes.NewUint64(1),
+		sqltypes.NULL,
+		sqltypes.MakeTrusted(sqltypes.Decimal, []byte("5")),
+		sqltypes.MakeTrusted(sqltypes.Datetime, []byte("0000-00-00 00:00:00")),
+		sqltypes.NULL,
+		sqltypes.MakeTrusted(sqltypes.Time, []byte("01:00:00")),
+		sqltypes.NULL,
+		sqltypes.MakeTrusted(sqltypes.Blob, nil),
+		sqltypes.NULL,
+	}}
+
+	// The fields come first, then the rows, as in the streaming results.
+	w := NewWriter()
+	var stream []byte
+	for _, qr := range []*sqltypes.Result{{Fields: fields}, {Rows: rows}, {}} {
+		b, err := w.Write(qr)
+		require.NoError(t, err)
+		stream = append(stream, b...)
+	}
+	stream = append(stream, w.Close()...)
+
+	messages := readStream(t, stream)
+	require.Len(t, messages, 2)
+
+	schema := messages[0]
+	require.EqualValues(t, headerSchema, schema.headerType)
+	schemaFields := schema.header.tables(1)
+	require.Len(t, schemaFields, len(fields))
+	wantTypes := []uint8{typeInt, typeInt, typeUtf8, typeDecimal, typeTimestamp, typeDate, typeDuration, typeFloatingPoint, typeBinary, typeNull}
+	for i, f := range schemaFields {
+		assert.Equal(t, fields[i].Name, f.string(0))
+		assert.EqualValues(t, 1, f.uint8(1), "nullable")
+		assert.Equal(t, wantTypes[i], f.uint8(2), "type of %s", fields[i].Name)
+		assert.Empty(t, f.tables(5), "children")
+	}
+	assert.EqualValues(t, 64, schemaFields[0].table(3).int32(0))
+	assert.EqualValues(t, 1, schemaFields[0].table(3).uint8(1))
+	assert.EqualValues(t, 8, schemaFields[1].table(3).int32(0))
+	assert.EqualValues(t, 0, schemaFields[1].table(3).uint8(1))
+	assert.EqualValues(t, 10, schemaFields[3].table(3).int32(0))
+	assert.EqualValues(t, 2, schemaFields[3].table(3).int32(1))
+	assert.EqualValues(t, timeUnitMicro, schemaFields[4].table(3).int16(0))
+	assert.EqualValues(t, precisionDouble, schemaFields[7].table(3).int16(0))
+
+	batch := messages[1]
+	require.EqualValues(t, headerRecordBatch, batch.headerType)
+	assert.EqualValues(t, 2, batch.header.int64(0))
+	nodes := batch.header.int64s(1, 2)
+	assert.Equal(t, []int64{2, 1, 2, 0, 2, 1, 2, 0, 2, 1, 2, 1, 2, 0, 2, 1, 2, 0, 2, 2}, nodes)
+
+	// every column has a validity buffer, the variable-length ones offsets
+	// too, and the null column none
+	specs := batch.header.int64s(2, 2)
+	require.Len(t, specs, 2*(2*9+2))
+	var buffers [][]byte
+	for i := 0; i < len(specs); i += 2 {
+		assert.Zero(t, specs[i]%8, "unaligned buffer")
+		buffers = append(buffers, batch.body[specs[i]:specs[i]+specs[i+1]])
+	}
+	le := binary.LittleEndian
+
+	// id
+	assert.Equal(t, []byte{0b01}, buffers[0])
+	assert.EqualValues(t, -1, int64(le.Uint64(buffers[1])))
+	// tiny
+	assert.Empty(t, buffers[2])
+	assert.Equal(t, []byte{200, 1}, buffers[3])
+	// name
+	assert.Equal(t, []byte{0b01}, buffers[4])
+	assert.Equal(t, []uint32{0, 5, 5}, []uint32{le.Uint32(buffers[5]), le.Uint32(buffers[5][4:]), le.Uint32(buffers[5][8:])})
+	assert.Equal(t, "alice", string(buffers[6]))
+	// price, in cents on 128 bits
+	assert.Empty(t, buffers[7])
+	assert.EqualValues(t, -1234, int64(le.Uint64(buffers[8])))
+	assert.EqualValues(t, uint64(math.MaxUint64), le.Uint64(buffers[8][8:]))
+	assert.EqualValues(t, 500, le.Uint64(buffers[8][16:]))
+	assert.EqualValues(t, 0, le.Uint64(buffers[8][24:]))
+	// created, the zero date is null
+	assert.Equal(t, []byte{0b01}, buffers[9])
+	assert.Equal(t, time.Date(2024, 2, 3, 4, 5, 6, 7000, time.UTC).UnixMicro(), int64(le.Uint64(buffers[10])))
+	// day
+	assert.EqualValues(t, 1, int32(le.Uint32(buffers[12])))
+	// elapsed
+	assert.Empty(t, buffers[13])
+	assert.Equal(t, -(838*time.Hour + 59*time.Minute + 59*time.Second).Microseconds(), int64(le.Uint64(buffers[14])))
+	assert.Equal(t, time.Hour.Microseconds(), int64(le.Uint64(buffers[14][8:])))
+	// ratio
+	assert.Equal(t, 0.5, math.Float64frombits(le.Uint64(buffers[16])))
+	// data
+	assert.Empty(t, buffers[17])
+	assert.Equal(t, []byte{0, 1}, buffers[19])
+}
+
+func TestWriterWithoutFields(t *testing.T) {
+	w := NewWriter()
+	b, err := w.Write(&sqltypes.Result{RowsAffected: 1})
+	require.NoError(t, err)
+	assert.Empty(t, b)
+
+	messages := readStream(t, w.Close())
+	require.Len(t, messages, 1)
+	assert.EqualValues(t, headerSchema, messages[0].headerType)
+	assert.Empty(t, messages[0].header.tables(1))
+
+	_, err = NewWriter().Write(&sqltypes.Result{Rows: [][]sqltypes.Value{{sqltypes.NewInt64(1)}}})
+	assert.ErrorContains(t, err, "rows of a result streamed before its fields")
+}
+
+// TestWriterGolden compares the streams of the writer with the ones of testdata,
+// which were checked with the reader of arrow-go (ipc.NewReader): it reads the
+// columns and values of these results from them, with the types of columnFor.
+func TestWriterGolden(t *testing.T) {
+	fields := []*querypb.Field{
+		{Name: "i8", Type: sqltypes.Int8},
+		{Name: "u8", Type: sqltypes.Uint8},
+		{Name: "i16", Type: sqltypes.Int16},
+		{Name: "u16", Type: sqltypes.Uint16},
+		{Name: "i24", Type: sqltypes.Int24},
+		{Name: "u32", Type: sqltypes.Uint32},
+		{Name: "i64", Type: sqltypes.Int64},
+		{Name: "u64", Type: sqltypes.Uint64},
+		{Name: "year", Type: sqltypes.Year},
+		{Name: "f32", Type: sqltypes.Float32},
+		{Name: "f64", Type: sqltypes.Float64},
+		{Name: "dec", Type: sqltypes.Decimal, ColumnLength: 12, Decimals: 2},
+		{Name: "wide_dec", Type: sqltypes.Decimal, ColumnLength: 66, Decimals: 0},
+		{Name: "day", Type: sqltypes.Date},
+		{Name: "created", Type: sqltypes.Datetime},
+		{Name: "updated", Type: sqltypes.Timestamp},
+		{Name: "elapsed", Type: sqltypes.Time},
+		{Name: "name", Type: sqltypes.VarChar},
+		{Name: "color", Type: sqltypes.Enum},
+		{Name: "doc", Type: sqltypes.TypeJSON},
+		{Name: "data", Type: sqltypes.Blob},
+		{Name: "flags", Type: sqltypes.Bit},
+		{Name: "nothing", Type: sqltypes.Null},
+	}
+	row := func(values ...string) sqltypes.Row {
+		r := make(sqltypes.Row, len(values))
+		for i, v := range values {
+			if v == "NULL" {
+				r[i] = sqltypes.NULL
+				continue
+			}
+			r[i] = sqltypes.MakeTrusted(fields[i].Type, []byte(v))
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		results []*sqltypes.Result
+	}{{
+		name: "types",
+		results: []*sqltypes.Result{{Fields: fields}, {
+			Rows: []sqltypes.Row{
+				row("-128", "255", "-32768", "65535", "-8388608", "4294967295", "-9223372036854775808", "18446744073709551615", "2024",
+					"1.5", "-0.25", "-12.34", "123456789012345678901234567890123456789012345678901234567890", "2024-02-29", "2024-02-03 04:05:06.000007", "1970-01-01 00:00:01", "-838:59:59",
+					"héllo", "red", `{"a": [1, 2]}`, "\x00\x01", "\x05", "NULL"),
+				row("NULL", "NULL", "NULL", "NULL", "NULL", "NULL", "NULL", "NULL", "NULL",
+					"NULL", "NULL", "NULL", "NULL", "0000-00-00", "0000-00-00 00:00:00", "NULL", "NULL",
+					"NULL", "NULL", "NULL", "NULL", "NULL", "NULL"),
+			},
+		}, {
+			Rows: []sqltypes.Row{
+				row("127", "0", "32767", "0", "8388607", "0", "9223372036854775807", "0", "1901",
+					"0", "1e300", "99999999.99", "-1", "1000-01-01", "9999-12-31 23:59:59.999999", "2038-01-19 03:14:07", "01:00:00.5",
+					"", "", "null", "", "\x00", "NULL"),
+			},
+		}},
+	}, {
+		name:    "no_fields",
+		results: []*sqltypes.Result{{RowsAffected: 1}},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := NewWriter()
+			var stream []byte
+			for _, qr := range test.results {
+				b, err := w.Write(qr)
+				require.NoError(t, err)
+				stream = append(stream, b...)
+			}
+			stream = append(stream, w.Close()...)
+
+			want, err := os.ReadFile(filepath.Join("testdata", test.name+".arrows"))
+			require.NoError(t, err)
+			assert.Equal(t, want, stream)
+		})
+	}
+}
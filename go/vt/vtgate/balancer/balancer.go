@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package balancer implements the policies that vtgate uses to select the
+// tablet a query is sent to, among the healthy tablets of its target.
+package balancer
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// The names of the tablet selection policies.
+const (
+	// PolicyRandom prefers the tablets of the local cell, and picks randomly
+	// among them.
+	PolicyRandom = "random"
+	// PolicyLowestLag prefers the tablets of the local cell, then the tablets
+	// with the lowest replication lag.
+	PolicyLowestLag = "lowest_lag"
+	// PolicyLeastOutstanding prefers the tablets with the fewest requests in
+	// flight from this vtgate, then the tablets of the local cell.
+	PolicyLeastOutstanding = "least_outstanding"
+	// PolicyWeightedRoundRobin prefers the tablets of the local cell, and
+	// distributes the requests among them in proportion to their weight.
+	PolicyWeightedRoundRobin = "weighted_round_robin"
+)
+
+// Policies is the list of the supported tablet selection policies.
+var Policies = []string{PolicyRandom, PolicyLowestLag, PolicyLeastOutstanding, PolicyWeightedRoundRobin}
+
+// Policy orders the healthy tablets of a target by preference.
+type Policy interface {
+	// Order reorders tablets in place, from the most preferred tablet to the
+	// least preferred one. The gateway sends a query to the first tablet of
+	// the list it did not already try.
+	Order(tablets []*discovery.TabletHealth)
+}
+
+// Balancer selects the tablet selection policy of each keyspace, and tracks
+// the requests in flight to each tablet for the load-aware policies.
+// It is safe for concurrent use.
+type Balancer struct {
+	defaultPolicy Policy
+	policies      map[string]Policy
+
+	// outstanding is only set when a policy uses the requests in flight.
+	outstanding *outstandingRequests
+}
+
+// New returns a Balancer that uses defaultPolicy for every keyspace that does
+// not have a policy in keyspacePolicies. localCell is the cell of the vtgate.
+func New(localCell, defaultPolicy string, keyspacePolicies map[string]string) (*Balancer, error) {
+	b := &Balancer{
+		policies: make(map[string]Policy, len(keyspacePolicies)),
+	}
+	var err error
+	if b.defaultPolicy, err = b.newPolicy(localCell, defaultPolicy); err != nil {
+		return nil, err
+	}
+	for keyspace, name := range keyspacePolicies {
+		if b.policies[keyspace], err = b.newPolicy(localCell, name); err != nil {
+			return nil, fmt.Errorf("keyspace %s: %w", keyspace, err)
+		}
+	}
+	return b, nil
+}
+
+func (b *Balancer) newPolicy(localCell, name string) (Policy, error) {
+	switch name {
+	case PolicyRandom:
+		return &randomPolicy{localCell: localCell}, nil
+	case PolicyLowestLag:
+		return &lowestLagPolicy{localCell: localCell}, nil
+	case PolicyLeastOutstanding:
+		if b.outstanding == nil {
+			b.outstanding = &outstandingRequests{}
+		}
+		return &leastOutstandingPolicy{localCell: localCell, outstanding: b.outstanding}, nil
+	case PolicyWeightedRoundRobin:
+		return &weightedRoundRobinPolicy{localCell: localCell, current: make(map[discovery.KeyspaceShardTabletType]map[string]int)}, nil
+	}
+	return nil, fmt.Errorf("unknown tablet selection policy %q, must be one of %v", name, Policies)
+}
+
+// Order orders the healthy tablets of the target with the policy of its
+// keyspace.
+func (b *Balancer) Order(target *querypb.Target, tablets []*discovery.TabletHealth) {
+	policy, ok := b.policies[target.GetKeyspace()]
+	if !ok {
+		policy = b.defaultPolicy
+	}
+	policy.Order(tablets)
+}
+
+// TracksOutstanding returns true if a policy uses the requests in flight to
+// each tablet, in which case the requests must be recorded with Track.
+func (b *Balancer) TracksOutstanding() bool {
+	return b.outstanding != nil
+}
+
+// Track records that a request was sent to the tablet. The returned function
+// must be called once the request has finished. It must only be called when
+// TracksOutstanding returns true.
+func (b *Balancer) Track(alias *topodatapb.TabletAlias) func() {
+	count := b.outstanding.counter(topoproto.TabletAliasString(alias))
+	count.Add(1)
+	return func() {
+		count.Add(-1)
+	}
+}
+
+// outstandingRequests counts the requests in flight to each tablet, with a
+// counter per tablet so that the requests to different tablets don't contend.
+// A tablet keeps its counter once it got a request: there is one per tablet
+// of the targets this vtgate sends requests to.
+type outstandingRequests struct {
+	// counts maps the tablet aliases to their *atomic.Int64 counter.
+	counts sync.Map
+}
+
+func (o *outstandingRequests) counter(alias string) *atomic.Int64 {
+	if count, ok := o.counts.Load(alias); ok {
+		return count.(*atomic.Int64)
+	}
+	count, _ := o.counts.LoadOrStore(alias, new(atomic.Int64))
+	return count.(*atomic.Int64)
+}
+
+func (o *outstandingRequests) get(alias string) int64 {
+	if count, ok := o.counts.Load(alias); ok {
+		return count.(*atomic.Int64).Load()
+	}
+	return 0
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func newTabletHealth(uid uint32, cell string, lag uint32) *discovery.TabletHealth {
+	return &discovery.TabletHealth{
+		Tablet:  topo.NewTablet(uid, cell, "host"),
+		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
+		Serving: true,
+		Stats:   &querypb.RealtimeStats{ReplicationLagSeconds: lag, CpuUsage: 0.2},
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("cell1", PolicyRandom, map[string]string{"ks": PolicyLowestLag})
+	require.NoError(t, err)
+
+	_, err = New("cell1", "fastest", nil)
+	require.EqualError(t, err, `unknown tablet selection policy "fastest", must be one of [random lowest_lag least_outstanding weighted_round_robin]`)
+
+	_, err = New("cell1", PolicyRandom, map[string]string{"ks": "fastest"})
+	require.ErrorContains(t, err, "keyspace ks: unknown tablet selection policy")
+}
+
+func TestBalancerKeyspacePolicies(t *testing.T) {
+	b, err := New("cell1", PolicyRandom, map[string]string{"lagged": PolicyLowestLag})
+	require.NoError(t, err)
+
+	th1 := newTabletHealth(1, "cell1", 100)
+	th2 := newTabletHealth(2, "cell1", 1)
+	th3 := newTabletHealth(3, "cell2", 1)
+	for i := 0; i < 10; i++ {
+		tablets := []*discovery.TabletHealth{th1, th3}
+		b.Order(&querypb.Target{Keyspace: "k"}, tablets)
+		assert.Equal(t, th1, tablets[0], "the random policy prefers the local cell")
+
+		tablets = []*discovery.TabletHealth{th1, th2, th3}
+		b.Order(&querypb.Target{Keyspace: "lagged"}, tablets)
+		assert.Equal(t, th2, tablets[0], "the lowest lag policy prefers the lowest lag")
+	}
+	assert.False(t, b.TracksOutstanding(), "only the least outstanding policy tracks requests")
+}
+
+func TestRandomPolicy(t *testing.T) {
+	p := &randomPolicy{localCell: "cell1"}
+
+	ts1 := newTabletHealth(1, "cell1", 1)
+	ts2 := newTabletHealth(2, "cell1", 1)
+	ts3 := newTabletHealth(3, "cell2", 1)
+	ts4 := newTabletHealth(4, "cell2", 1)
+
+	sameCellTablets := []*discovery.TabletHealth{ts1, ts2}
+	diffCellTablets := []*discovery.TabletHealth{ts3, ts4}
+	mixedTablets := []*discovery.TabletHealth{ts1, ts2, ts3, ts4}
+	// repeat shuffling 10 times and every time the same cell tablets should be in the front
+	for i := 0; i < 10; i++ {
+		p.Order(sameCellTablets)
+		assert.Len(t, sameCellTablets, 2, "Wrong number of TabletHealth")
+		assert.Equal(t, sameCellTablets[0].Tablet.Alias.Cell, "cell1", "Wrong tablet cell")
+		assert.Equal(t, sameCellTablets[1].Tablet.Alias.Cell, "cell1", "Wrong tablet cell")
+
+		p.Order(diffCellTablets)
+		assert.Len(t, diffCellTablets, 2, "should shuffle in only diff cell tablets")
+		assert.Contains(t, diffCellTablets, ts3, "diffCellTablets should contain %v", ts3)
+		assert.Contains(t, diffCellTablets, ts4, "diffCellTablets should contain %v", ts4)
+
+		p.Order(mixedTablets)
+		assert.Len(t, mixedTablets, 4, "should have 4 tablets, got %+v", mixedTablets)
+
+		assert.Contains(t, mixedTablets[0:2], ts1, "should have same cell tablets in the front, got %+v", mixedTablets)
+		assert.Contains(t, mixedTablets[0:2], ts2, "should have same cell tablets in the front, got %+v", mixedTablets)
+
+		assert.Contains(t, mixedTablets[2:4], ts3, "should have diff cell tablets in the rear, got %+v", mixedTablets)
+		assert.Contains(t, mixedTablets[2:4], ts4, "should have diff cell tablets in the rear, got %+v", mixedTablets)
+	}
+}
+
+func TestLowestLagPolicy(t *testing.T) {
+	p := &lowestLagPolicy{localCell: "cell1"}
+
+	ts1 := newTabletHealth(1, "cell1", 10)
+	ts2 := newTabletHealth(2, "cell2", 1)
+	ts3 := newTabletHealth(3, "cell1", 1)
+	ts4 := newTabletHealth(4, "cell2", 5)
+
+	tablets := []*discovery.TabletHealth{ts1, ts2, ts3, ts4}
+	for i := 0; i < 10; i++ {
+		p.Order(tablets)
+		// The local cell comes first, then the lowest lag.
+		assert.Equal(t, []*discovery.TabletHealth{ts3, ts1, ts2, ts4}, tablets)
+	}
+}
+
+func TestLeastOutstandingPolicy(t *testing.T) {
+	b, err := New("cell1", PolicyRandom, map[string]string{"k": PolicyLeastOutstanding})
+	require.NoError(t, err)
+	require.True(t, b.TracksOutstanding())
+	target := &querypb.Target{Keyspace: "k"}
+
+	ts1 := newTabletHealth(1, "cell1", 1)
+	ts2 := newTabletHealth(2, "cell1", 1)
+	ts3 := newTabletHealth(3, "cell2", 1)
+
+	done1 := b.Track(ts1.Tablet.Alias)
+	done2 := b.Track(ts1.Tablet.Alias)
+	done3 := b.Track(ts2.Tablet.Alias)
+
+	tablets := []*discovery.TabletHealth{ts1, ts2, ts3}
+	b.Order(target, tablets)
+	assert.Equal(t, []*discovery.TabletHealth{ts3, ts2, ts1}, tablets)
+
+	done1()
+	done2()
+	done3()
+	// Without outstanding requests, the local cell comes first.
+	b.Order(target, tablets)
+	assert.Equal(t, ts3, tablets[2])
+	assert.Zero(t, b.outstanding.get("cell1-0000000001"))
+	assert.Zero(t, b.outstanding.get("cell1-0000000002"))
+}
+
+func TestWeightedRoundRobinPolicy(t *testing.T) {
+	p := &weightedRoundRobinPolicy{localCell: "cell1", current: make(map[discovery.KeyspaceShardTabletType]map[string]int)}
+
+	ts1 := newTabletHealth(1, "cell1", 1)
+	ts1.Tablet.Tags = map[string]string{WeightTag: "2"}
+	ts2 := newTabletHealth(2, "cell1", 1)
+	ts2.Tablet.Tags = map[string]string{WeightTag: "invalid"}
+	ts3 := newTabletHealth(3, "cell2", 1)
+	ts3.Tablet.Tags = map[string]string{WeightTag: "100"}
+
+	picks := make(map[*discovery.TabletHealth]int)
+	tablets := []*discovery.TabletHealth{ts1, ts2, ts3}
+	for i := 0; i < 30; i++ {
+		p.Order(tablets)
+		picks[tablets[0]]++
+		assert.Equal(t, ts3, tablets[2], "the tablets of other cells come last")
+	}
+	assert.Equal(t, map[*discovery.TabletHealth]int{ts1: 20, ts2: 10}, picks)
+
+	// Without tablets in the local cell, the round robin runs among all tablets.
+	picks = make(map[*discovery.TabletHealth]int)
+	ts4 := newTabletHealth(4, "cell2", 1)
+	tablets = []*discovery.TabletHealth{ts3, ts4}
+	for i := 0; i < 101; i++ {
+		p.Order(tablets)
+		picks[tablets[0]]++
+	}
+	assert.Equal(t, map[*discovery.TabletHealth]int{ts3: 100, ts4: 1}, picks)
+
+	// The tablets that are no longer candidates are dropped.
+	current := p.current[discovery.KeyFromTarget(ts3.Target)]
+	assert.Len(t, current, 2)
+	assert.Contains(t, current, "cell2-0000000003")
+	assert.Contains(t, current, "cell2-0000000004")
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package balancer
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// WeightTag is the tablet tag that holds the weight of a tablet for the
+// weighted_round_robin policy. Tablets without a positive integer weight
+// have a weight of 1.
+const WeightTag = "balancer_weight"
+
+type randomPolicy struct {
+	localCell string
+}
+
+func (p *randomPolicy) Order(tablets []*discovery.TabletHealth) {
+	shuffleTablets(p.localCell, tablets)
+}
+
+type lowestLagPolicy struct {
+	localCell string
+}
+
+func (p *lowestLagPolicy) Order(tablets []*discovery.TabletHealth) {
+	// The tablets of the local cell come first, and each cell group is
+	// sorted by lag. The stable sort keeps the random order among the
+	// tablets with the same lag.
+	shuffleTablets(p.localCell, tablets)
+	local := slices.IndexFunc(tablets, func(th *discovery.TabletHealth) bool {
+		return th.Tablet.Alias.Cell != p.localCell
+	})
+	if local == -1 {
+		local = len(tablets)
+	}
+	byLag := func(a, b *discovery.TabletHealth) int {
+		return cmp.Compare(a.Stats.GetReplicationLagSeconds(), b.Stats.GetReplicationLagSeconds())
+	}
+	slices.SortStableFunc(tablets[:local], byLag)
+	slices.SortStableFunc(tablets[local:], byLag)
+}
+
+type leastOutstandingPolicy struct {
+	localCell   string
+	outstanding *outstandingRequests
+}
+
+func (p *leastOutstandingPolicy) Order(tablets []*discovery.TabletHealth) {
+	counts := make(map[*discovery.TabletHealth]int64, len(tablets))
+	for _, th := range tablets {
+		counts[th] = p.outstanding.get(topoproto.TabletAliasString(th.Tablet.Alias))
+	}
+	shuffleTablets(p.localCell, tablets)
+	slices.SortStableFunc(tablets, func(a, b *discovery.TabletHealth) int {
+		return cmp.Compare(counts[a], counts[b])
+	})
+}
+
+// weightedRoundRobinPolicy implements a smooth weighted round robin among the
+// tablets of the local cell, or among all the tablets if none of them is in
+// the local cell.
+type weightedRoundRobinPolicy struct {
+	localCell string
+
+	mu sync.Mutex
+	// current holds the current weight of each candidate tablet, by alias,
+	// for each target. The tablets that are no longer candidates, like the
+	// tablets that are no longer healthy, are dropped from it.
+	current map[discovery.KeyspaceShardTabletType]map[string]int
+}
+
+func (p *weightedRoundRobinPolicy) Order(tablets []*discovery.TabletHealth) {
+	shuffleTablets(p.localCell, tablets)
+
+	candidates := tablets
+	for i, th := range tablets {
+		if th.Tablet.Alias.Cell != p.localCell {
+			if i > 0 {
+				candidates = tablets[:i]
+			}
+			break
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	key := discovery.KeyFromTarget(candidates[0].Target)
+	current, ok := p.current[key]
+	if !ok {
+		current = make(map[string]int, len(candidates))
+		p.current[key] = current
+	}
+	total, best := 0, 0
+	var bestAlias string
+	for i, th := range candidates {
+		alias := topoproto.TabletAliasString(th.Tablet.Alias)
+		weight := tabletWeight(th.Tablet)
+		current[alias] += weight
+		total += weight
+		if i == 0 || current[alias] > current[bestAlias] {
+			best, bestAlias = i, alias
+		}
+	}
+	current[bestAlias] -= total
+	if len(current) > len(candidates) {
+		for alias := range current {
+			if !slices.ContainsFunc(candidates, func(th *discovery.TabletHealth) bool {
+				return topoproto.TabletAliasString(th.Tablet.Alias) == alias
+			}) {
+				delete(current, alias)
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	tablets[0], tablets[best] = tablets[best], tablets[0]
+}
+
+func tabletWeight(tablet *topodatapb.Tablet) int {
+	weight, err := strconv.Atoi(tablet.GetTags()[WeightTag])
+	if err != nil || weight < 1 {
+		return 1
+	}
+	return weight
+}
+
+// shuffleTablets moves the tablets of the cell to the front of the list, and
+// shuffles the tablets of the cell and the tablets of the other cells.
+func shuffleTablets(cell string, tablets []*discovery.TabletHealth) {
+	sameCell, diffCell, sameCellMax := 0, 0, -1
+	length := len(tablets)
+
+	// move all same cell tablets to the front, this is O(n)
+	for {
+		sameCellMax = diffCell - 1
+		sameCell = nextTablet(cell, tablets, sameCell, length, true)
+		diffCell = nextTablet(cell, tablets, diffCell, length, false)
+		// either no more diffs or no more same cells should stop the iteration
+		if sameCell < 0 || diffCell < 0 {
+			break
+		}
+
+		if sameCell < diffCell {
+			// fast forward the `sameCell` lookup to `diffCell + 1`, `diffCell` unchanged
+			sameCell = diffCell + 1
+		} else {
+			// sameCell > diffCell, swap needed
+			tablets[sameCell], tablets[diffCell] = tablets[diffCell], tablets[sameCell]
+			sameCell++
+			diffCell++
+		}
+	}
+
+	// shuffle in same cell tablets
+	for i := sameCellMax; i > 0; i-- {
+		swap := rand.IntN(i + 1)
+		tablets[i], tablets[swap] = tablets[swap], tablets[i]
+	}
+
+	// shuffle in diff cell tablets
+	for i, diffCellMin := length-1, sameCellMax+1; i > diffCellMin; i-- {
+		swap := rand.IntN(i-sameCellMax) + diffCellMin
+		tablets[i], tablets[swap] = tablets[swap], tablets[i]
+	}
+}
+
+func nextTablet(cell string, tablets []*discovery.TabletHealth, offset, length int, sameCell bool) int {
+	for ; offset < length; offset++ {
+		if (tablets[offset].Tablet.Alias.Cell == cell) == sameCell {
+			return offset
+		}
+	}
+	return -1
+}
@@ -0,0 +1,372 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package breaker implements the circuit breakers that vtgate keeps for the
+// tablets it sends queries to.
+//
+// The circuit breaker of a tablet trips when the error rate or the timeout
+// rate of the tablet over a sliding window is too high. The thresholds adapt
+// to the other tablets of the same target: a tablet only trips if it fails
+// notably more than its peers, so that errors caused by the queries
+// themselves, or an outage of the whole target, do not take every tablet out
+// of rotation. Once open, the circuit breaker keeps the tablet out of
+// rotation for a while, then probes it with canary queries, and only closes
+// if all of them succeed.
+package breaker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// State is the state of the circuit breaker of a tablet.
+type State int
+
+const (
+	// Closed lets the queries through to the tablet.
+	Closed State = iota
+	// Open keeps the tablet out of rotation.
+	Open
+	// HalfOpen keeps the tablet out of rotation while it is probed with
+	// canary queries.
+	HalfOpen
+)
+
+// String returns the name of the state, as shown in SHOW VITESS_TABLETS.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "CLOSED"
+	case Open:
+		return "OPEN"
+	case HalfOpen:
+		return "HALF_OPEN"
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", int(s))
+}
+
+// windowBuckets is the number of buckets the sliding window is split into.
+const windowBuckets = 10
+
+var (
+	transitions = stats.NewCountersWithMultiLabels(
+		"TabletCircuitBreakerTransitions",
+		"Transitions of the circuit breakers of the tablets, by the state they transitioned to",
+		[]string{"Keyspace", "Shard", "TabletType", "Tablet", "State"})
+	states = stats.NewGaugesWithMultiLabels(
+		"TabletCircuitBreakerState",
+		"State of the circuit breakers of the tablets: 0 for closed, 1 for open and 2 for half open",
+		[]string{"Keyspace", "Shard", "TabletType", "Tablet"})
+)
+
+// Config is the configuration of the circuit breakers.
+type Config struct {
+	// Window is the duration over which the error and timeout rates of a
+	// tablet are measured.
+	Window time.Duration
+	// MinRequests is the number of requests a tablet must have received in
+	// the window for its circuit breaker to trip.
+	MinRequests int
+	// ErrorRate is the error rate at which the circuit breaker of a tablet
+	// trips.
+	ErrorRate float64
+	// TimeoutRate is the timeout rate at which the circuit breaker of a
+	// tablet trips.
+	TimeoutRate float64
+	// PeerMultiplier raises the thresholds of a tablet to this many times the
+	// rates of the other tablets of its target, if that is higher.
+	PeerMultiplier float64
+	// OpenDuration is the duration a circuit breaker stays open before the
+	// tablet is probed. It doubles every time the probe fails, up to
+	// MaxOpenDuration.
+	OpenDuration    time.Duration
+	MaxOpenDuration time.Duration
+	// CanaryQueries is the number of consecutive canary queries that must
+	// succeed for the circuit breaker to close.
+	CanaryQueries int
+	// CanaryTimeout is the timeout of a canary query.
+	CanaryTimeout time.Duration
+}
+
+// ProbeFunc sends a canary query to the tablet.
+type ProbeFunc func(ctx context.Context, th *discovery.TabletHealth) error
+
+// Manager keeps the circuit breakers of the tablets. It is safe for
+// concurrent use.
+type Manager struct {
+	cfg   Config
+	probe ProbeFunc
+	now   func() time.Time
+
+	// mu protects targets.
+	mu sync.Mutex
+	// targets is indexed by keyspace/shard/tablet_type.
+	targets map[string]*targetBreakers
+}
+
+// NewManager returns a Manager that probes the tablets with probe.
+func NewManager(cfg Config, probe ProbeFunc) *Manager {
+	return &Manager{
+		cfg:     cfg,
+		probe:   probe,
+		now:     time.Now,
+		targets: make(map[string]*targetBreakers),
+	}
+}
+
+// targetBreakers are the circuit breakers of the tablets of a target.
+type targetBreakers struct {
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+type breaker struct {
+	target *querypb.Target
+	alias  string
+
+	state        State
+	openedAt     time.Time
+	openDuration time.Duration
+
+	buckets [windowBuckets]bucket
+}
+
+type bucket struct {
+	start    time.Time
+	requests int
+	errors   int
+	timeouts int
+}
+
+func (m *Manager) targetBreakers(target *querypb.Target) *targetBreakers {
+	key := fmt.Sprintf("%v/%v/%v", target.Keyspace, target.Shard, target.TabletType.String())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tb, ok := m.targets[key]
+	if !ok {
+		tb = &targetBreakers{breakers: make(map[string]*breaker)}
+		m.targets[key] = tb
+	}
+	return tb
+}
+
+// breaker returns the circuit breaker of the tablet. tb.mu must be held.
+func (tb *targetBreakers) breaker(th *discovery.TabletHealth) *breaker {
+	alias := topoproto.TabletAliasString(th.Tablet.Alias)
+	b, ok := tb.breakers[alias]
+	if !ok {
+		b = &breaker{target: th.Target, alias: alias}
+		tb.breakers[alias] = b
+	}
+	return b
+}
+
+// Filter removes the tablets whose circuit breaker is not closed from the
+// healthy tablets of the target, and starts probing the tablets whose circuit
+// breaker has been open long enough. If no tablet is left, all of them are
+// returned: the circuit breakers never make a target unavailable.
+func (m *Manager) Filter(target *querypb.Target, tablets []*discovery.TabletHealth) []*discovery.TabletHealth {
+	tb := m.targetBreakers(target)
+	now := m.now()
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	filtered := make([]*discovery.TabletHealth, 0, len(tablets))
+	for _, th := range tablets {
+		b, ok := tb.breakers[topoproto.TabletAliasString(th.Tablet.Alias)]
+		if !ok || b.state == Closed {
+			filtered = append(filtered, th)
+			continue
+		}
+		if b.state == Open && now.Sub(b.openedAt) >= b.openDuration {
+			b.transition(HalfOpen)
+			go m.runProbe(tb, b, th)
+		}
+	}
+	if len(filtered) == 0 {
+		return tablets
+	}
+	return filtered
+}
+
+// Record records the outcome of a query sent to the tablet, and trips its
+// circuit breaker if needed.
+func (m *Manager) Record(th *discovery.TabletHealth, err error) {
+	tb := m.targetBreakers(th.Target)
+	now := m.now()
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	b := tb.breaker(th)
+	if b.state != Closed {
+		return
+	}
+	cur := b.bucket(now, m.cfg.Window)
+	cur.requests++
+	switch classify(err) {
+	case outcomeError:
+		cur.errors++
+	case outcomeTimeout:
+		cur.timeouts++
+	default:
+		return
+	}
+
+	requests, errors, timeouts := b.counts(now, m.cfg.Window)
+	if requests < m.cfg.MinRequests {
+		return
+	}
+	var peerRequests, peerErrors, peerTimeouts int
+	for _, peer := range tb.breakers {
+		if peer == b || peer.state != Closed {
+			continue
+		}
+		r, e, t := peer.counts(now, m.cfg.Window)
+		peerRequests += r
+		peerErrors += e
+		peerTimeouts += t
+	}
+	if rate(errors, requests) >= m.threshold(m.cfg.ErrorRate, peerErrors, peerRequests) ||
+		rate(timeouts, requests) >= m.threshold(m.cfg.TimeoutRate, peerTimeouts, peerRequests) {
+		log.Warningf("Tripping the circuit breaker of tablet %s: %d errors and %d timeouts out of %d requests", b.alias, errors, timeouts, requests)
+		b.openDuration = m.cfg.OpenDuration
+		b.open(now)
+	}
+}
+
+// State returns the state of the circuit breaker of the tablet.
+func (m *Manager) State(th *discovery.TabletHealth) State {
+	tb := m.targetBreakers(th.Target)
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	if b, ok := tb.breakers[topoproto.TabletAliasString(th.Tablet.Alias)]; ok {
+		return b.state
+	}
+	return Closed
+}
+
+// threshold returns the threshold for a rate, adapted to the rate of the
+// peers.
+func (m *Manager) threshold(base float64, peerCount, peerRequests int) float64 {
+	return max(base, m.cfg.PeerMultiplier*rate(peerCount, peerRequests))
+}
+
+// runProbe sends the canary queries to the tablet of a half open circuit
+// breaker, and closes or reopens it.
+func (m *Manager) runProbe(tb *targetBreakers, b *breaker, th *discovery.TabletHealth) {
+	var err error
+	for i := 0; i < m.cfg.CanaryQueries && err == nil; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), m.cfg.CanaryTimeout)
+		err = m.probe(ctx, th)
+		cancel()
+	}
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	if b.state != HalfOpen {
+		return
+	}
+	if err != nil {
+		log.Warningf("Canary query to tablet %s failed, keeping its circuit breaker open: %v", b.alias, err)
+		b.openDuration = min(2*b.openDuration, m.cfg.MaxOpenDuration)
+		b.open(m.now())
+		return
+	}
+	log.Infof("Closing the circuit breaker of tablet %s", b.alias)
+	b.buckets = [windowBuckets]bucket{}
+	b.transition(Closed)
+}
+
+func (b *breaker) open(now time.Time) {
+	b.openedAt = now
+	b.transition(Open)
+}
+
+func (b *breaker) transition(state State) {
+	b.state = state
+	labels := []string{b.target.Keyspace, b.target.Shard, b.target.TabletType.String(), b.alias}
+	transitions.Add(append(labels, state.String()), 1)
+	states.Set(labels, int64(state))
+}
+
+// bucket returns the bucket of the window for now, resetting it if it is
+// stale.
+func (b *breaker) bucket(now time.Time, window time.Duration) *bucket {
+	width := window / windowBuckets
+	start := now.Truncate(width)
+	cur := &b.buckets[(start.UnixNano()/int64(width))%windowBuckets]
+	if !cur.start.Equal(start) {
+		*cur = bucket{start: start}
+	}
+	return cur
+}
+
+// counts returns the requests, errors and timeouts in the window ending now.
+func (b *breaker) counts(now time.Time, window time.Duration) (requests, errors, timeouts int) {
+	for _, cur := range b.buckets {
+		if now.Sub(cur.start) >= window {
+			continue
+		}
+		requests += cur.requests
+		errors += cur.errors
+		timeouts += cur.timeouts
+	}
+	return requests, errors, timeouts
+}
+
+func rate(count, requests int) float64 {
+	if requests == 0 {
+		return 0
+	}
+	return float64(count) / float64(requests)
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeError
+	outcomeTimeout
+)
+
+// classify returns the outcome of a query for the circuit breaker. Only the
+// errors that point at the tablet count: the errors caused by the query, like
+// a syntax error or a duplicate key, are successes.
+func classify(err error) outcome {
+	if err == nil {
+		return outcomeSuccess
+	}
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_DEADLINE_EXCEEDED:
+		return outcomeTimeout
+	case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_INTERNAL, vtrpcpb.Code_UNKNOWN, vtrpcpb.Code_RESOURCE_EXHAUSTED:
+		return outcomeError
+	}
+	return outcomeSuccess
+}
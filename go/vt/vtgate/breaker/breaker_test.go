@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	errUnavailable = vterrors.New(vtrpcpb.Code_UNAVAILABLE, "connection refused")
+	errTimeout     = vterrors.New(vtrpcpb.Code_DEADLINE_EXCEEDED, "context deadline exceeded")
+	errQuery       = vterrors.New(vtrpcpb.Code_ALREADY_EXISTS, "duplicate entry")
+)
+
+var testConfig = Config{
+	Window:          10 * time.Second,
+	MinRequests:     10,
+	ErrorRate:       0.5,
+	TimeoutRate:     0.2,
+	PeerMultiplier:  2,
+	OpenDuration:    time.Second,
+	MaxOpenDuration: 4 * time.Second,
+	CanaryQueries:   3,
+	CanaryTimeout:   time.Second,
+}
+
+func newTabletHealth(uid uint32) *discovery.TabletHealth {
+	return &discovery.TabletHealth{
+		Tablet:  topo.NewTablet(uid, "cell1", "host"),
+		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
+		Serving: true,
+	}
+}
+
+// newTestManager returns a Manager whose clock is advanced by the returned
+// function.
+func newTestManager(probe ProbeFunc) (*Manager, func(time.Duration)) {
+	m := NewManager(testConfig, probe)
+	now := time.Unix(1000, 0)
+	m.now = func() time.Time { return now }
+	return m, func(d time.Duration) { now = now.Add(d) }
+}
+
+func record(m *Manager, th *discovery.TabletHealth, err error, n int) {
+	for i := 0; i < n; i++ {
+		m.Record(th, err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	assert.Equal(t, outcomeSuccess, classify(nil))
+	assert.Equal(t, outcomeSuccess, classify(errQuery))
+	assert.Equal(t, outcomeError, classify(errUnavailable))
+	assert.Equal(t, outcomeError, classify(errors.New("unclassified")))
+	assert.Equal(t, outcomeTimeout, classify(errTimeout))
+}
+
+func TestTripOnErrorRate(t *testing.T) {
+	m, _ := newTestManager(nil)
+	th1, th2 := newTabletHealth(1), newTabletHealth(2)
+	target := th1.Target
+	opened := transitions.Counts()["k.s.REPLICA.cell1-0000000001.OPEN"]
+
+	// Errors caused by the queries do not count.
+	record(m, th1, errQuery, 20)
+	assert.Equal(t, Closed, m.State(th1))
+
+	// The rate is 8 errors out of 28 requests.
+	record(m, th1, errUnavailable, 8)
+	assert.Equal(t, Closed, m.State(th1))
+
+	record(m, th1, errUnavailable, 12)
+	assert.Equal(t, Open, m.State(th1))
+	assert.Equal(t, []*discovery.TabletHealth{th2}, m.Filter(target, []*discovery.TabletHealth{th1, th2}))
+	assert.Equal(t, opened+1, transitions.Counts()["k.s.REPLICA.cell1-0000000001.OPEN"])
+	assert.Equal(t, int64(Open), states.Counts()["k.s.REPLICA.cell1-0000000001"])
+
+	// The circuit breakers never make the target unavailable.
+	assert.Equal(t, []*discovery.TabletHealth{th1}, m.Filter(target, []*discovery.TabletHealth{th1}))
+}
+
+func TestTripOnTimeoutRate(t *testing.T) {
+	m, _ := newTestManager(nil)
+	th := newTabletHealth(1)
+
+	record(m, th, nil, 8)
+	record(m, th, errTimeout, 1)
+	assert.Equal(t, Closed, m.State(th), "below the minimum number of requests")
+
+	record(m, th, errTimeout, 1)
+	assert.Equal(t, Open, m.State(th))
+}
+
+func TestMinRequests(t *testing.T) {
+	m, advance := newTestManager(nil)
+	th := newTabletHealth(1)
+
+	record(m, th, errUnavailable, 9)
+	// The errors are out of the window by now.
+	advance(11 * time.Second)
+	record(m, th, errUnavailable, 9)
+	assert.Equal(t, Closed, m.State(th))
+
+	record(m, th, errUnavailable, 1)
+	assert.Equal(t, Open, m.State(th))
+}
+
+func TestAdaptiveThreshold(t *testing.T) {
+	m, _ := newTestManager(nil)
+	th1, th2, th3 := newTabletHealth(1), newTabletHealth(2), newTabletHealth(3)
+
+	// Every tablet of the target fails half of the time: the thresholds
+	// rise above 100%, and no circuit breaker trips.
+	for i := 0; i < 20; i++ {
+		for _, th := range []*discovery.TabletHealth{th1, th2, th3} {
+			m.Record(th, nil)
+			m.Record(th, errUnavailable)
+		}
+	}
+	assert.Equal(t, Closed, m.State(th1))
+	assert.Equal(t, Closed, m.State(th2))
+	assert.Equal(t, Closed, m.State(th3))
+
+	// A tablet that times out much more than its peers trips.
+	th4 := newTabletHealth(4)
+	record(m, th4, nil, 7)
+	record(m, th4, errTimeout, 3)
+	assert.Equal(t, Open, m.State(th4))
+}
+
+func TestProbe(t *testing.T) {
+	var fail atomic.Bool
+	var probes atomic.Int64
+	m, advance := newTestManager(func(ctx context.Context, th *discovery.TabletHealth) error {
+		probes.Add(1)
+		if fail.Load() {
+			return errUnavailable
+		}
+		return nil
+	})
+	th := newTabletHealth(1)
+	tablets := []*discovery.TabletHealth{th, newTabletHealth(2)}
+
+	record(m, th, errUnavailable, 10)
+	require.Equal(t, Open, m.State(th))
+
+	// The tablet is not probed before the open duration.
+	advance(500 * time.Millisecond)
+	m.Filter(th.Target, tablets)
+	assert.Equal(t, Open, m.State(th))
+	assert.Zero(t, probes.Load())
+
+	// A failed probe doubles the open duration.
+	fail.Store(true)
+	advance(500 * time.Millisecond)
+	m.Filter(th.Target, tablets)
+	require.Eventually(t, func() bool { return m.State(th) == Open }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(1), probes.Load())
+
+	fail.Store(false)
+	advance(time.Second)
+	m.Filter(th.Target, tablets)
+	assert.Equal(t, Open, m.State(th))
+	advance(time.Second)
+	assert.Len(t, m.Filter(th.Target, tablets), 1)
+	require.Eventually(t, func() bool { return m.State(th) == Closed }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(4), probes.Load(), "all the canary queries must succeed")
+	assert.Len(t, m.Filter(th.Target, tablets), 2)
+
+	// The window starts afresh once the circuit breaker closes.
+	record(m, th, errUnavailable, 9)
+	assert.Equal(t, Closed, m.State(th))
+}
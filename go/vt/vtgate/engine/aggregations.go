@@ -42,6 +42,12 @@ type AggregateParams struct {
 	WCol   int
 	Type   evalengine.Type
 
+	// These are used only for the group_concat opcode. A nil separator
+	// stands for the default one: a comma. Distinct values are found by
+	// hashing the values of the group.
+	Separator *string
+	Distinct  bool
+
 	Alias    string `json:",omitempty"`
 	Expr     sqlparser.Expr
 	Original *sqlparser.AliasedExpr
@@ -79,6 +85,12 @@ func (ap *AggregateParams) String() string {
 	if sqltypes.IsText(ap.Type.Type()) && ap.CollationEnv.IsSupported(ap.Type.Collation()) {
 		keyCol += " COLLATE " + ap.CollationEnv.LookupName(ap.Type.Collation())
 	}
+	if ap.Distinct {
+		keyCol = "DISTINCT " + keyCol
+	}
+	if ap.Separator != nil {
+		keyCol += " SEPARATOR " + sqltypes.EncodeStringSQL(*ap.Separator)
+	}
 	dispOrigOp := ""
 	if ap.OrigOpcode != AggregateUnassigned && ap.OrigOpcode != ap.Opcode {
 		dispOrigOp = "_" + ap.OrigOpcode.String()
@@ -254,8 +266,14 @@ func (a *aggregatorScalar) reset() {
 }
 
 type aggregatorGroupConcat struct {
-	from  int
-	type_ sqltypes.Type
+	from      int
+	type_     sqltypes.Type
+	separator []byte
+
+	// distinct is used to skip the values already concatenated,
+	// it is nil when the aggregation is not distinct
+	distinct     *probeTable
+	distinctCols []CheckCol
 
 	concat []byte
 	n      int
@@ -265,8 +283,17 @@ func (a *aggregatorGroupConcat) add(row []sqltypes.Value) error {
 	if row[a.from].IsNull() {
 		return nil
 	}
+	if a.distinct != nil {
+		unseen, err := a.distinct.exists(row)
+		if err != nil {
+			return err
+		}
+		if unseen == nil {
+			return nil
+		}
+	}
 	if a.n > 0 {
-		a.concat = append(a.concat, ',')
+		a.concat = append(a.concat, a.separator...)
 	}
 	a.concat = append(a.concat, row[a.from].Raw()...)
 	a.n++
@@ -283,6 +310,9 @@ func (a *aggregatorGroupConcat) finish() sqltypes.Value {
 func (a *aggregatorGroupConcat) reset() {
 	a.n = 0
 	a.concat = nil // not safe to reuse this byte slice as it's returned as MakeTrusted
+	if a.distinct != nil {
+		a.distinct = newProbeTable(a.distinctCols, a.distinct.collationEnv)
+	}
 }
 
 type aggregatorGtid struct {
@@ -431,7 +461,24 @@ func newAggregation(fields []*querypb.Field, aggregates []*AggregateParams) (agg
 			ag = &aggregatorScalar{from: aggr.Col}
 
 		case AggregateGroupConcat:
-			ag = &aggregatorGroupConcat{from: aggr.Col, type_: targetType}
+			gc := &aggregatorGroupConcat{from: aggr.Col, type_: targetType, separator: []byte(",")}
+			if aggr.Separator != nil {
+				gc.separator = []byte(*aggr.Separator)
+			}
+			if aggr.Distinct {
+				typ := aggr.Type
+				if !typ.Valid() {
+					// the planner did not know the type of the values, use the one of the field instead
+					typ = evalengine.NewType(sourceType, collations.ID(fields[aggr.Col].Charset))
+				}
+				checkCol := CheckCol{Col: aggr.Col, Type: typ, CollationEnv: aggr.CollationEnv}
+				if aggr.WAssigned() {
+					checkCol.WsCol = &aggr.WCol
+				}
+				gc.distinctCols = []CheckCol{checkCol}
+				gc.distinct = newProbeTable(gc.distinctCols, aggr.CollationEnv)
+			}
+			ag = gc
 
 		default:
 			panic("BUG: unexpected Aggregation opcode")
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// CoerceBindVars coerces the values the client provided for the bind variables
+// of the plan to the types of the vindex columns they are compared with.
+//
+// MySQL compares an integral column with a string as numbers, while the vindexes
+// map the string as it is: '5' and ' 5' must be routed like 5 for the query to
+// find the rows MySQL would. Strings that are integral numbers are coerced to the
+// type of the column, and other values are left to MySQL.
+func (p *Plan) CoerceBindVars(bindVars map[string]*querypb.BindVariable) {
+	for name, typ := range p.BindVarTypes {
+		bv, ok := bindVars[name]
+		if !ok || !sqltypes.IsIntegral(typ.Type()) {
+			continue
+		}
+		bindVars[name] = coerceBindVar(bv, typ.Type())
+	}
+}
+
+func coerceBindVar(bv *querypb.BindVariable, typ querypb.Type) *querypb.BindVariable {
+	if bv.Type != querypb.Type_TUPLE {
+		val, changed := coerceToIntegral(sqltypes.ProtoToValue(&querypb.Value{Type: bv.Type, Value: bv.Value}), typ)
+		if !changed {
+			return bv
+		}
+		return sqltypes.ValueBindVariable(val)
+	}
+
+	var values []*querypb.Value
+	for i, v := range bv.Values {
+		val, changed := coerceToIntegral(sqltypes.ProtoToValue(v), typ)
+		if !changed {
+			continue
+		}
+		if values == nil {
+			values = make([]*querypb.Value, len(bv.Values))
+			copy(values, bv.Values)
+		}
+		values[i] = sqltypes.ValueToProto(val)
+	}
+	if values == nil {
+		return bv
+	}
+	return &querypb.BindVariable{Type: querypb.Type_TUPLE, Values: values}
+}
+
+// coerceToIntegral converts a string that is an integral number to a value of
+// the integral type typ, and returns whether it did.
+func coerceToIntegral(val sqltypes.Value, typ querypb.Type) (sqltypes.Value, bool) {
+	if !val.IsText() && !val.IsBinary() {
+		return val, false
+	}
+	s := strings.TrimSpace(val.ToString())
+	unsigned := sqltypes.IsUnsigned(typ)
+	if unsigned {
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return sqltypes.NewUint64(u), true
+		}
+	} else if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return sqltypes.NewInt64(i), true
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || strings.ContainsAny(s, "xXpPiInN_") {
+		// not a number, or one that is out of range or that MySQL doesn't
+		// parse, like hexadecimal, infinite and NaN floats: the value is left
+		// for MySQL to compare
+		return val, false
+	}
+	switch {
+	case f != math.Trunc(f):
+	case unsigned && f >= 0 && f < math.MaxUint64:
+		return sqltypes.NewUint64(uint64(f)), true
+	case !unsigned && f >= math.MinInt64 && f < math.MaxInt64:
+		return sqltypes.NewInt64(int64(f)), true
+	}
+	return val, false
+}
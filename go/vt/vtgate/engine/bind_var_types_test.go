@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+func TestCoerceBindVars(t *testing.T) {
+	plan := &Plan{BindVarTypes: map[string]evalengine.Type{
+		"id":   evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+		"uid":  evalengine.NewType(sqltypes.Uint32, collations.CollationBinaryID),
+		"name": evalengine.NewType(sqltypes.VarChar, collations.CollationUtf8mb4ID),
+	}}
+
+	tests := []struct {
+		name string
+		in   *querypb.BindVariable
+		out  *querypb.BindVariable
+	}{{
+		name: "id",
+		in:   sqltypes.StringBindVariable("5"),
+		out:  sqltypes.Int64BindVariable(5),
+	}, {
+		name: "id",
+		in:   sqltypes.BytesBindVariable([]byte(" -5 ")),
+		out:  sqltypes.Int64BindVariable(-5),
+	}, {
+		name: "id",
+		in:   sqltypes.StringBindVariable("5.0"),
+		out:  sqltypes.Int64BindVariable(5),
+	}, {
+		name: "id",
+		in:   sqltypes.Int64BindVariable(5),
+		out:  sqltypes.Int64BindVariable(5),
+	}, {
+		// not integral, MySQL compares it as a double
+		name: "id",
+		in:   sqltypes.StringBindVariable("5.5"),
+		out:  sqltypes.StringBindVariable("5.5"),
+	}, {
+		name: "id",
+		in:   sqltypes.StringBindVariable("99999999999999999999999"),
+		out:  sqltypes.StringBindVariable("99999999999999999999999"),
+	}, {
+		name: "id",
+		in:   sqltypes.StringBindVariable("abc"),
+		out:  sqltypes.StringBindVariable("abc"),
+	}, {
+		name: "id",
+		in:   sqltypes.StringBindVariable("0x10"),
+		out:  sqltypes.StringBindVariable("0x10"),
+	}, {
+		name: "uid",
+		in:   sqltypes.StringBindVariable("18446744073709551615"),
+		out:  sqltypes.Uint64BindVariable(18446744073709551615),
+	}, {
+		name: "uid",
+		in:   sqltypes.StringBindVariable("-1"),
+		out:  sqltypes.StringBindVariable("-1"),
+	}, {
+		name: "id",
+		in:   sqltypes.TestBindVariable([]any{"1", 2, "3"}),
+		out:  sqltypes.TestBindVariable([]any{1, 2, 3}),
+	}, {
+		name: "id",
+		in:   sqltypes.TestBindVariable([]any{"1", "x"}),
+		out:  sqltypes.TestBindVariable([]any{1, "x"}),
+	}, {
+		// text columns are left to MySQL
+		name: "name",
+		in:   sqltypes.Int64BindVariable(5),
+		out:  sqltypes.Int64BindVariable(5),
+	}, {
+		name: "other",
+		in:   sqltypes.StringBindVariable("abc"),
+		out:  sqltypes.StringBindVariable("abc"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bindVars := map[string]*querypb.BindVariable{test.name: test.in}
+			plan.CoerceBindVars(bindVars)
+			utils.MustMatch(t, test.out, bindVars[test.name])
+		})
+	}
+}
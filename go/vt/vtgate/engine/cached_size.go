@@ -35,8 +35,10 @@ func (cached *AggregateParams) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(112)
+		size += int64(128)
 	}
+	// field Separator *string
+	size += hack.RuntimeAllocSize(int64(16))
 	// field Alias string
 	size += hack.RuntimeAllocSize(int64(len(cached.Alias)))
 	// field Expr vitess.io/vitess/go/vt/sqlparser.Expr
@@ -364,7 +366,7 @@ func (cached *Generate) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(48)
+		size += int64(80)
 	}
 	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
 	size += cached.Keyspace.CachedSize(true)
@@ -374,6 +376,8 @@ func (cached *Generate) CachedSize(alloc bool) int64 {
 	if cc, ok := cached.Values.(cachedObject); ok {
 		size += cc.CachedSize(true)
 	}
+	// field Sequence string
+	size += hack.RuntimeAllocSize(int64(len(cached.Sequence)))
 	return size
 }
 func (cached *GroupByParams) CachedSize(alloc bool) int64 {
@@ -426,7 +430,7 @@ func (cached *Insert) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(208)
+		size += int64(216)
 	}
 	// field InsertCommon vitess.io/vitess/go/vt/vtgate/engine.InsertCommon
 	size += cached.InsertCommon.CachedSize(false)
@@ -473,7 +477,7 @@ func (cached *InsertCommon) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(144)
+		size += int64(152)
 	}
 	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
 	size += cached.Keyspace.CachedSize(true)
@@ -505,7 +509,7 @@ func (cached *InsertSelect) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(176)
+		size += int64(184)
 	}
 	// field InsertCommon vitess.io/vitess/go/vt/vtgate/engine.InsertCommon
 	size += cached.InsertCommon.CachedSize(false)
@@ -584,6 +588,60 @@ func (cached *Limit) CachedSize(alloc bool) int64 {
 	}
 	return size
 }
+func (cached *LoadData) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(240)
+	}
+	// field Keyspace *vitess.io/vitess/go/vt/vtgate/vindexes.Keyspace
+	size += cached.Keyspace.CachedSize(true)
+	// field TargetDestination vitess.io/vitess/go/vt/key.Destination
+	if cc, ok := cached.TargetDestination.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field TableName string
+	size += hack.RuntimeAllocSize(int64(len(cached.TableName)))
+	// field FileName string
+	size += hack.RuntimeAllocSize(int64(len(cached.FileName)))
+	// field Format vitess.io/vitess/go/vt/vtgate/engine.LoadDataFormat
+	size += cached.Format.CachedSize(false)
+	// field Vindex vitess.io/vitess/go/vt/vtgate/vindexes.Vindex
+	if cc, ok := cached.Vindex.(cachedObject); ok {
+		size += cc.CachedSize(true)
+	}
+	// field VindexOffsets []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.VindexOffsets)) * int64(8))
+	}
+	// field Prefix string
+	size += hack.RuntimeAllocSize(int64(len(cached.Prefix)))
+	// field RowSuffix string
+	size += hack.RuntimeAllocSize(int64(len(cached.RowSuffix)))
+	return size
+}
+func (cached *LoadDataFormat) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(80)
+	}
+	// field FieldsTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.FieldsTerminatedBy)))
+	// field EnclosedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.EnclosedBy)))
+	// field EscapedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.EscapedBy)))
+	// field LinesStartingBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesStartingBy)))
+	// field LinesTerminatedBy string
+	size += hack.RuntimeAllocSize(int64(len(cached.LinesTerminatedBy)))
+	return size
+}
 func (cached *Lock) CachedSize(alloc bool) int64 {
 	if cached == nil {
 		return int64(0)
@@ -766,7 +824,7 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 	}
 	size := int64(0)
 	if alloc {
-		size += int64(144)
+		size += int64(168)
 	}
 	// field Original string
 	size += hack.RuntimeAllocSize(int64(len(cached.Original)))
@@ -783,6 +841,20 @@ func (cached *Plan) CachedSize(alloc bool) int64 {
 			size += elem.CachedSize(true)
 		}
 	}
+	// field BindVarTypes map[string]vitess.io/vitess/go/vt/vtgate/evalengine.Type
+	if cached.BindVarTypes != nil {
+		size += int64(48)
+		hmap := reflect.ValueOf(cached.BindVarTypes)
+		numBuckets := int(math.Pow(2, float64((*(*uint8)(unsafe.Pointer(hmap.Pointer() + uintptr(9)))))))
+		numOldBuckets := (*(*uint16)(unsafe.Pointer(hmap.Pointer() + uintptr(10))))
+		size += hack.RuntimeAllocSize(int64(numOldBuckets * 272))
+		if len(cached.BindVarTypes) > 0 || numBuckets > 1 {
+			size += hack.RuntimeAllocSize(int64(numBuckets * 272))
+		}
+		for k := range cached.BindVarTypes {
+			size += hack.RuntimeAllocSize(int64(len(k)))
+		}
+	}
 	// field TablesUsed []string
 	{
 		size += hack.RuntimeAllocSize(int64(cap(cached.TablesUsed)) * int64(16))
@@ -1518,6 +1590,29 @@ func (cached *VitessMetadata) CachedSize(alloc bool) int64 {
 	size += hack.RuntimeAllocSize(int64(len(cached.Value)))
 	return size
 }
+func (cached *VitessMetadataTable) CachedSize(alloc bool) int64 {
+	if cached == nil {
+		return int64(0)
+	}
+	size := int64(0)
+	if alloc {
+		size += int64(64)
+	}
+	// field Table string
+	size += hack.RuntimeAllocSize(int64(len(cached.Table)))
+	// field Fields []*vitess.io/vitess/go/vt/proto/query.Field
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Fields)) * int64(8))
+		for _, elem := range cached.Fields {
+			size += elem.CachedSize(true)
+		}
+	}
+	// field Cols []int
+	{
+		size += hack.RuntimeAllocSize(int64(cap(cached.Cols)) * int64(8))
+	}
+	return size
+}
 
 //go:nocheckptr
 func (cached *shardRoute) CachedSize(alloc bool) int64 {
@@ -95,15 +95,15 @@ func (c *DBDDL) GetTableName() string {
 }
 
 // TryExecute implements the Primitive interface
-func (c *DBDDL) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+func (c *DBDDL) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (_ *sqltypes.Result, err error) {
 	name := vcursor.GetDBDDLPluginName()
 	plugin, ok := databaseCreatorPlugins[name]
 	if !ok {
 		log.Errorf("'%s' database ddl plugin is not registered. Falling back to default plugin", name)
 		plugin = databaseCreatorPlugins[defaultDBDDLPlugin]
 	}
-	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, c.queryTimeout)
-	defer cancelFunc()
+	ctx, done := addQueryTimeout(ctx, vcursor, nil, c.queryTimeout)
+	defer func() { err = done(err) }()
 
 	if c.create {
 		return c.createDatabase(ctx, vcursor, plugin)
@@ -75,10 +75,10 @@ func TestDBDDLTimeout(t *testing.T) {
 	primitive := &DBDDL{name: "ks", create: true, queryTimeout: 100}
 	vc := &loggingVCursor{dbDDLPlugin: pluginName, shardErr: fmt.Errorf("db not available")}
 	_, err := primitive.TryExecute(context.Background(), vc, nil, false)
-	assert.EqualError(t, err, "could not validate create database: destination not resolved")
+	assert.EqualError(t, err, "query timed out after 100ms set by the QUERY_TIMEOUT_MS comment directive: could not validate create database: destination not resolved")
 
 	primitive = &DBDDL{name: "ks", queryTimeout: 100}
 	vc = &loggingVCursor{dbDDLPlugin: pluginName, ksAvailable: true}
 	_, err = primitive.TryExecute(context.Background(), vc, nil, false)
-	assert.EqualError(t, err, "could not validate drop database: keyspace still available in vschema")
+	assert.EqualError(t, err, "query timed out after 100ms set by the QUERY_TIMEOUT_MS comment directive: could not validate drop database: keyspace still available in vschema")
 }
@@ -41,9 +41,9 @@ type Delete struct {
 }
 
 // TryExecute performs a non-streaming exec.
-func (del *Delete) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
-	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, del.QueryTimeout)
-	defer cancelFunc()
+func (del *Delete) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (_ *sqltypes.Result, err error) {
+	ctx, done := addQueryTimeout(ctx, vcursor, del.Keyspace, del.QueryTimeout)
+	defer func() { err = done(err) }()
 
 	rss, _, err := del.findRoute(ctx, vcursor, bindVars)
 	if err != nil {
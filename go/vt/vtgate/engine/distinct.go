@@ -90,6 +90,15 @@ func (pt *probeTable) hashCodeForRow(inputRow sqltypes.Row) (vthash.Hash, error)
 	return hasher.Sum128(), nil
 }
 
+// checkMemory fails once the probe table holds more distinct rows than
+// vtgate is allowed to keep in memory.
+func (pt *probeTable) checkMemory(vcursor VCursor) error {
+	if vcursor.ExceedsMaxMemoryRows(len(pt.seenRows)) {
+		return fmt.Errorf("in-memory row count exceeded allowed limit of %d", vcursor.MaxMemoryRows())
+	}
+	return nil
+}
+
 func newProbeTable(checkCols []CheckCol, collationEnv *collations.Environment) *probeTable {
 	cols := make([]CheckCol, len(checkCols))
 	copy(cols, checkCols)
@@ -121,6 +130,9 @@ func (d *Distinct) TryExecute(ctx context.Context, vcursor VCursor, bindVars map
 		}
 		if appendRow != nil {
 			result.Rows = append(result.Rows, appendRow)
+			if err := pt.checkMemory(vcursor); err != nil {
+				return nil, err
+			}
 		}
 	}
 	if d.Truncate > 0 {
@@ -148,6 +160,9 @@ func (d *Distinct) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVa
 			}
 			if appendRow != nil {
 				result.Rows = append(result.Rows, appendRow)
+				if err := pt.checkMemory(vcursor); err != nil {
+					return err
+				}
 			}
 		}
 		return callback(result.Truncate(len(d.CheckCols)))
@@ -217,3 +217,50 @@ func TestWeightStringFallBack(t *testing.T) {
 		Type:  evalengine.NewType(sqltypes.VarBinary, collations.CollationBinaryID),
 	}}, distinct.CheckCols, "checkCols should not be updated")
 }
+
+func TestDistinctMaxMemoryRows(t *testing.T) {
+	saveMax := testMaxMemoryRows
+	saveIgnore := testIgnoreMaxMemoryRows
+	testMaxMemoryRows = 3
+	defer func() {
+		testMaxMemoryRows = saveMax
+		testIgnoreMaxMemoryRows = saveIgnore
+	}()
+
+	testCases := []struct {
+		ignoreMaxMemoryRows bool
+		err                 string
+	}{
+		{true, ""},
+		{false, "in-memory row count exceeded allowed limit of 3"},
+	}
+	for _, test := range testCases {
+		// duplicates do not count against the limit, only the distinct rows do
+		input := r("myid", "int64", "1", "1", "2", "2", "3", "4")
+		distinct := &Distinct{
+			Source: &fakePrimitive{results: []*sqltypes.Result{input}},
+			CheckCols: []CheckCol{{
+				Col:  0,
+				Type: evalengine.NewType(sqltypes.Int64, collations.CollationBinaryID),
+			}},
+		}
+
+		testIgnoreMaxMemoryRows = test.ignoreMaxMemoryRows
+		_, err := distinct.TryExecute(context.Background(), &noopVCursor{}, nil, true)
+		if testIgnoreMaxMemoryRows {
+			require.NoError(t, err)
+		} else {
+			require.EqualError(t, err, test.err)
+		}
+
+		distinct.Source.(*fakePrimitive).rewind()
+		err = distinct.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+			return nil
+		})
+		if testIgnoreMaxMemoryRows {
+			require.NoError(t, err)
+		} else {
+			require.EqualError(t, err, test.err)
+		}
+	}
+}
@@ -48,6 +48,8 @@ import (
 
 var testMaxMemoryRows = 100
 var testIgnoreMaxMemoryRows = false
+var testLoadDataBatchSize = 100
+var testInsertBatchRows = 0
 
 var _ VCursor = (*noopVCursor)(nil)
 var _ SessionActions = (*noopVCursor)(nil)
@@ -108,6 +110,10 @@ func (t *noopVCursor) GetWarmingReadsChannel() chan bool {
 	panic("implement me")
 }
 
+func (t *noopVCursor) SequenceCache() *SequenceCache {
+	return nil
+}
+
 func (t *noopVCursor) CloneForReplicaWarming(ctx context.Context) VCursor {
 	panic("implement me")
 }
@@ -116,6 +122,10 @@ func (t *noopVCursor) SetExec(ctx context.Context, name string, value string) er
 	panic("implement me")
 }
 
+func (t *noopVCursor) ClearPlans(ctx context.Context) error {
+	return nil
+}
+
 func (t *noopVCursor) ThrottleApp(ctx context.Context, throttleAppRule *topodatapb.ThrottledAppRule) error {
 	panic("implement me")
 }
@@ -124,6 +134,10 @@ func (t *noopVCursor) ShowExec(ctx context.Context, command sqlparser.ShowComman
 	panic("implement me")
 }
 
+func (t *noopVCursor) VitessMetadataRows(ctx context.Context, table string) ([][]sqltypes.Value, error) {
+	panic("implement me")
+}
+
 // SetContextWithValue implements VCursor interface.
 func (t *noopVCursor) SetContextWithValue(key, value interface{}) func() {
 	return func() {}
@@ -211,6 +225,22 @@ func (t *noopVCursor) SetSessionEnableSystemSettings(ctx context.Context, allow
 	panic("implement me")
 }
 
+func (t *noopVCursor) SetSnapshotReads(context.Context, bool) error {
+	panic("implement me")
+}
+
+func (t *noopVCursor) SetSkipReadRetry(context.Context, bool) error {
+	panic("implement me")
+}
+
+func (t *noopVCursor) SetReadOnlyTxOnReplica(context.Context, bool) error {
+	panic("implement me")
+}
+
+func (t *noopVCursor) SetTenantID(string) error {
+	panic("implement me")
+}
+
 func (t *noopVCursor) GetSessionEnableSystemSettings() bool {
 	panic("implement me")
 }
@@ -254,6 +284,9 @@ func (t *noopVCursor) ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedShard
 func (t *noopVCursor) NeedsReservedConn() {
 }
 
+func (t *noopVCursor) HasUntrackedReservedConnState() {
+}
+
 func (t *noopVCursor) SetUDV(key string, value any) error {
 	panic("implement me")
 }
@@ -289,8 +322,20 @@ func (t *noopVCursor) SetClientFoundRows(context.Context, bool) error {
 func (t *noopVCursor) SetQueryTimeout(maxExecutionTime int64) {
 }
 
-func (t *noopVCursor) GetQueryTimeout(queryTimeoutFromComments int) int {
-	return queryTimeoutFromComments
+func (t *noopVCursor) SetStreamChunkRows(rows int64) {
+}
+
+func (t *noopVCursor) SetStreamChunkTimeout(timeout int64) {
+}
+
+func (t *noopVCursor) SetMaxReplicaLag(lag int64) {
+}
+
+func (t *noopVCursor) SetTabletTags(tags string) {
+}
+
+func (t *noopVCursor) GetQueryTimeout(keyspace string, queryTimeoutFromComments int) (int, QueryTimeoutSource) {
+	return queryTimeoutFromComments, QueryTimeoutFromComment
 }
 
 func (t *noopVCursor) SetSkipQueryPlanCache(context.Context, bool) error {
@@ -329,6 +374,14 @@ func (t *noopVCursor) SetTarget(string) error {
 	panic("implement me")
 }
 
+func (t *noopVCursor) LoadDataBatchSize() int {
+	return testLoadDataBatchSize
+}
+
+func (t *noopVCursor) InsertBatchRows() int {
+	return testInsertBatchRows
+}
+
 func (t *noopVCursor) MaxMemoryRows() int {
 	return testMaxMemoryRows
 }
@@ -420,9 +473,15 @@ type loggingVCursor struct {
 
 	shardSession []*srvtopo.ResolvedShard
 
+	sequences *SequenceCache
+
 	parser *sqlparser.Parser
 }
 
+func (f *loggingVCursor) SequenceCache() *SequenceCache {
+	return f.sequences
+}
+
 func (f *loggingVCursor) HasCreatedTempTable() {
 	f.log = append(f.log, "temp table getting created")
 }
@@ -495,6 +554,10 @@ func (f *loggingVCursor) NeedsReservedConn() {
 	f.inReservedConn = true
 }
 
+func (f *loggingVCursor) HasUntrackedReservedConnState() {
+	f.log = append(f.log, "Untracked Reserved Conn State")
+}
+
 func (f *loggingVCursor) InReservedConn() bool {
 	return f.inReservedConn
 }
@@ -28,7 +28,6 @@ import (
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
-	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
@@ -108,9 +107,9 @@ func (ins *Insert) RouteType() string {
 }
 
 // TryExecute performs a non-streaming exec.
-func (ins *Insert) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
-	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, ins.QueryTimeout)
-	defer cancelFunc()
+func (ins *Insert) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (_ *sqltypes.Result, err error) {
+	ctx, done := addQueryTimeout(ctx, vcursor, ins.Keyspace, ins.QueryTimeout)
+	defer func() { err = done(err) }()
 
 	switch ins.Opcode {
 	case InsertUnsharded:
@@ -149,49 +148,29 @@ func (ins *Insert) insertIntoShardedTable(
 	if err != nil {
 		return nil, err
 	}
-	rss, queries, err := ins.getInsertShardedQueries(ctx, vcursor, bindVars)
+	rounds, err := ins.getInsertShardedBatches(ctx, vcursor, bindVars)
 	if err != nil {
 		return nil, err
 	}
 
-	return ins.executeInsertQueries(ctx, vcursor, rss, queries, uint64(insertID))
-}
-
-func (ins *Insert) executeInsertQueries(
-	ctx context.Context,
-	vcursor VCursor,
-	rss []*srvtopo.ResolvedShard,
-	queries []*querypb.BoundQuery,
-	insertID uint64,
-) (*sqltypes.Result, error) {
-	autocommit := (len(rss) == 1 || ins.MultiShardAutocommit) && vcursor.AutocommitApproval()
-	err := allowOnlyPrimary(rss...)
-	if err != nil {
-		return nil, err
-	}
-	result, errs := vcursor.ExecuteMultiShard(ctx, ins, rss, queries, true /* rollbackOnError */, autocommit)
-	if errs != nil {
-		return nil, vterrors.Aggregate(errs)
+	query := func(rows []int) (*querypb.BoundQuery, error) {
+		return ins.shardQuery(bindVars, rows)
 	}
-
-	if insertID != 0 {
-		result.InsertID = insertID
-	}
-	return result, nil
+	return ins.executeInsertBatches(ctx, vcursor, ins, rounds, query, uint64(insertID))
 }
 
-// getInsertShardedQueries performs all the vindex related work
-// and returns a map of shard to queries.
+// getInsertShardedBatches performs all the vindex related work
+// and returns the batches of rows to insert into each shard.
 // Using the primary vindex, it computes the target keyspace ids.
 // For owned vindexes, it creates entries.
 // For unowned vindexes with no input values, it reverse maps.
 // For unowned vindexes with values, it validates.
 // If it's an IGNORE or ON DUPLICATE key insert, it drops unroutable rows.
-func (ins *Insert) getInsertShardedQueries(
+func (ins *Insert) getInsertShardedBatches(
 	ctx context.Context,
 	vcursor VCursor,
 	bindVars map[string]*querypb.BindVariable,
-) ([]*srvtopo.ResolvedShard, []*querypb.BoundQuery, error) {
+) ([][]insertBatch, error) {
 
 	// vindexRowsValues builds the values of all vindex columns.
 	// the 3-d structure indexes are colVindex, row, col. Note that
@@ -201,7 +180,7 @@ func (ins *Insert) getInsertShardedQueries(
 	// require inputs in that format.
 	vindexRowsValues, err := ins.buildVindexRowsValues(ctx, vcursor, bindVars)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// The output from the following 'process' functions is a list of
@@ -209,12 +188,12 @@ func (ins *Insert) getInsertShardedQueries(
 	// results in an error. For 'ignore' type inserts, the keyspace
 	// id is returned as nil, which is used later to drop the corresponding rows.
 	if len(vindexRowsValues) == 0 || len(ins.ColVindexes) == 0 {
-		return nil, nil, vterrors.NewErrorf(vtrpcpb.Code_FAILED_PRECONDITION, vterrors.RequiresPrimaryKey, vterrors.PrimaryVindexNotSet, ins.TableName)
+		return nil, vterrors.NewErrorf(vtrpcpb.Code_FAILED_PRECONDITION, vterrors.RequiresPrimaryKey, vterrors.PrimaryVindexNotSet, ins.TableName)
 	}
 
 	keyspaceIDs, err := ins.processVindexes(ctx, vcursor, vindexRowsValues)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Build 3-d bindvars. Skip rows with nil keyspace ids in case
@@ -249,52 +228,46 @@ func (ins *Insert) getInsertShardedQueries(
 	if len(destinations) == 0 {
 		// In this case, all we have is nil KeyspaceIds, we don't do
 		// anything at all.
-		return nil, nil, nil
+		return ins.insertBatches(vcursor, nil, nil, nil), nil
 	}
 
 	rss, indexesPerRss, err := vcursor.ResolveDestinations(ctx, ins.Keyspace.Name, indexes, destinations)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return ins.insertBatches(vcursor, rss, indexesPerRss, keyspaceIDs), nil
+}
 
-	queries := make([]*querypb.BoundQuery, len(rss))
-	for i := range rss {
-		shardBindVars := map[string]*querypb.BindVariable{}
-		var mids []string
-		for _, indexValue := range indexesPerRss[i] {
-			index, _ := strconv.ParseInt(string(indexValue.Value), 0, 64)
-			if keyspaceIDs[index] != nil {
-				walkFunc := func(node sqlparser.SQLNode) (kontinue bool, err error) {
-					if arg, ok := node.(*sqlparser.Argument); ok {
-						bv, exists := bindVars[arg.Name]
-						if !exists {
-							return false, vterrors.VT03026(arg.Name)
-						}
-						shardBindVars[arg.Name] = bv
-					}
-					return true, nil
-				}
-				mids = append(mids, sqlparser.String(ins.Mid[index]))
-				for _, expr := range ins.Mid[index] {
-					err = sqlparser.Walk(walkFunc, expr, nil)
-					if err != nil {
-						return nil, nil, err
-					}
-				}
-				err = sqlparser.Walk(walkFunc, ins.Suffix, nil)
-				if err != nil {
-					return nil, nil, err
-				}
+// shardQuery returns the query that inserts the rows into a shard.
+func (ins *Insert) shardQuery(bindVars map[string]*querypb.BindVariable, rows []int) (*querypb.BoundQuery, error) {
+	shardBindVars := map[string]*querypb.BindVariable{}
+	walkFunc := func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		if arg, ok := node.(*sqlparser.Argument); ok {
+			bv, exists := bindVars[arg.Name]
+			if !exists {
+				return false, vterrors.VT03026(arg.Name)
 			}
+			shardBindVars[arg.Name] = bv
 		}
-		rewritten := ins.Prefix + strings.Join(mids, ",") + sqlparser.String(ins.Suffix)
-		queries[i] = &querypb.BoundQuery{
-			Sql:           rewritten,
-			BindVariables: shardBindVars,
+		return true, nil
+	}
+	mids := make([]string, 0, len(rows))
+	for _, index := range rows {
+		mids = append(mids, sqlparser.String(ins.Mid[index]))
+		for _, expr := range ins.Mid[index] {
+			if err := sqlparser.Walk(walkFunc, expr, nil); err != nil {
+				return nil, err
+			}
+		}
+		if err := sqlparser.Walk(walkFunc, ins.Suffix, nil); err != nil {
+			return nil, err
 		}
 	}
-
-	return rss, queries, nil
+	rewritten := ins.Prefix + strings.Join(mids, ",") + sqlparser.String(ins.Suffix)
+	return &querypb.BoundQuery{
+		Sql:           rewritten,
+		BindVariables: shardBindVars,
+	}, nil
 }
 
 func (ins *Insert) buildVindexRowsValues(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) ([][]sqltypes.Row, error) {
@@ -17,14 +17,17 @@ limitations under the License.
 package engine
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/srvtopo"
 
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/key"
@@ -65,6 +68,11 @@ type (
 		// QueryTimeout contains the optional timeout (in milliseconds) to apply to this query
 		QueryTimeout int
 
+		// BatchRows is the maximum number of rows inserted into a shard with
+		// a single query, as set by the INSERT_BATCH_ROWS directive. Zero
+		// uses the --insert-batch-rows of vtgate.
+		BatchRows int
+
 		// ForceNonStreaming is true when the insert table and select table are same.
 		// This will avoid locking by the select table.
 		ForceNonStreaming bool
@@ -84,6 +92,13 @@ type (
 
 	ksID = []byte
 
+	// insertBatch is the rows of a statement that are inserted into a
+	// shard with a single query, by their index in the statement.
+	insertBatch struct {
+		rs   *srvtopo.ResolvedShard
+		rows []int
+	}
+
 	// Generate represents the instruction to generate
 	// a value from a sequence.
 	Generate struct {
@@ -96,6 +111,11 @@ type (
 		Values evalengine.Expr
 		// Insert using Select, offset for auto increment column
 		Offset int
+		// Sequence is the name of the sequence table, qualified by its
+		// keyspace, and BlockSize is the number of values that are
+		// reserved at once from it (0 for the vtgate default).
+		Sequence  string
+		BlockSize int64
 	}
 
 	// InsertOpcode is a number representing the opcode
@@ -174,6 +194,101 @@ func (ins *InsertCommon) executeUnshardedTableQuery(ctx context.Context, vcursor
 	return qr, nil
 }
 
+// batchRows returns the maximum number of rows inserted into a shard with a
+// single query, 0 for no limit.
+func (ic *InsertCommon) batchRows(vcursor VCursor) int {
+	if ic.BatchRows > 0 {
+		return ic.BatchRows
+	}
+	return max(vcursor.InsertBatchRows(), 0)
+}
+
+// insertBatches splits the rows that go to each shard into batches of at most
+// batchRows rows, and returns the rounds of batches to execute one after the
+// other: a round has at most one batch per shard. When the rows are split, the
+// rows of a shard are sorted by keyspace id, so that the rows of range-sharded
+// tables are inserted in the order of their vindex values. The rows of INSERT
+// IGNORE and INSERT ... ON DUPLICATE KEY UPDATE keep their order, which
+// decides what happens to duplicate rows.
+func (ic *InsertCommon) insertBatches(vcursor VCursor, rss []*srvtopo.ResolvedShard, indexesPerRss [][]*querypb.Value, keyspaceIDs []ksID) [][]insertBatch {
+	batchRows := ic.batchRows(vcursor)
+	// There is always a round, even if it has no batch because all the rows
+	// of an INSERT IGNORE were dropped.
+	rounds := make([][]insertBatch, 1)
+	for i, rs := range rss {
+		rows := make([]int, 0, len(indexesPerRss[i]))
+		for _, indexValue := range indexesPerRss[i] {
+			index, _ := strconv.Atoi(string(indexValue.Value))
+			if keyspaceIDs[index] != nil {
+				rows = append(rows, index)
+			}
+		}
+		if batchRows == 0 || len(rows) <= batchRows {
+			rounds[0] = append(rounds[0], insertBatch{rs: rs, rows: rows})
+			continue
+		}
+		if !ic.Ignore {
+			slices.SortStableFunc(rows, func(a, b int) int {
+				return bytes.Compare(keyspaceIDs[a], keyspaceIDs[b])
+			})
+		}
+		for round := 0; len(rows) > 0; round++ {
+			if round == len(rounds) {
+				rounds = append(rounds, nil)
+			}
+			n := min(len(rows), batchRows)
+			rounds[round] = append(rounds[round], insertBatch{rs: rs, rows: rows[:n]})
+			rows = rows[n:]
+		}
+	}
+	return rounds
+}
+
+// executeInsertBatches executes the rounds of batches one after the other,
+// and aggregates their results. The insert is autocommitted only if it is
+// executed with a single query, or if MultiShardAutocommit is set and it is
+// executed in a single round.
+func (ic *InsertCommon) executeInsertBatches(
+	ctx context.Context,
+	vcursor VCursor,
+	loggingPrimitive Primitive,
+	rounds [][]insertBatch,
+	query func(rows []int) (*querypb.BoundQuery, error),
+	insertID uint64,
+) (*sqltypes.Result, error) {
+	result := &sqltypes.Result{}
+	for _, round := range rounds {
+		rss := make([]*srvtopo.ResolvedShard, 0, len(round))
+		queries := make([]*querypb.BoundQuery, 0, len(round))
+		for _, batch := range round {
+			q, err := query(batch.rows)
+			if err != nil {
+				return nil, err
+			}
+			rss = append(rss, batch.rs)
+			queries = append(queries, q)
+		}
+		if err := allowOnlyPrimary(rss...); err != nil {
+			return nil, err
+		}
+		autocommit := len(rounds) == 1 && (len(rss) == 1 || ic.MultiShardAutocommit) && vcursor.AutocommitApproval()
+		qr, errs := vcursor.ExecuteMultiShard(ctx, loggingPrimitive, rss, queries, true /* rollbackOnError */, autocommit)
+		if errs != nil {
+			return nil, vterrors.Aggregate(errs)
+		}
+		if len(rounds) == 1 {
+			result = qr
+			break
+		}
+		result.AppendResult(qr)
+	}
+
+	if insertID != 0 {
+		result.InsertID = insertID
+	}
+	return result, nil
+}
+
 func (ins *InsertCommon) processVindexes(ctx context.Context, vcursor VCursor, vindexRowsValues [][]sqltypes.Row) ([]ksID, error) {
 	colVindexes := ins.ColVindexes
 	keyspaceIDs, err := ins.processPrimary(ctx, vcursor, vindexRowsValues[0], colVindexes[0])
@@ -441,8 +556,17 @@ func (ic *InsertCommon) processGenerateFromValues(
 	return insertID, nil
 }
 
+// execGenerate reserves count consecutive values of the sequence, and returns
+// the first one. The values come from the sequence cache of the vcursor, which
+// reserves them in blocks from the sequence table.
 func (ic *InsertCommon) execGenerate(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, count int64) (int64, error) {
-	// If generation is needed, generate the requested number of values (as one call).
+	return vcursor.SequenceCache().Reserve(ctx, ic.Generate.Sequence, ic.Generate.BlockSize, count, func(ctx context.Context, n int64) (int64, error) {
+		return ic.fetchSequenceValues(ctx, vcursor, loggingPrimitive, n)
+	})
+}
+
+// fetchSequenceValues reserves n values in the sequence table.
+func (ic *InsertCommon) fetchSequenceValues(ctx context.Context, vcursor VCursor, loggingPrimitive Primitive, n int64) (int64, error) {
 	rss, _, err := vcursor.ResolveDestinations(ctx, ic.Generate.Keyspace.Name, nil, []key.Destination{key.DestinationAnyShard{}})
 	if err != nil {
 		return 0, err
@@ -450,7 +574,7 @@ func (ic *InsertCommon) execGenerate(ctx context.Context, vcursor VCursor, loggi
 	if len(rss) != 1 {
 		return 0, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "auto sequence generation can happen through single shard only, it is getting routed to %d shards", len(rss))
 	}
-	bindVars := map[string]*querypb.BindVariable{nextValBV: sqltypes.Int64BindVariable(count)}
+	bindVars := map[string]*querypb.BindVariable{nextValBV: sqltypes.Int64BindVariable(n)}
 	qr, err := vcursor.ExecuteStandalone(ctx, loggingPrimitive, ic.Generate.Query, bindVars, rss[0])
 	if err != nil {
 		return 0, err
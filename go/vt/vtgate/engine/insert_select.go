@@ -29,7 +29,6 @@ import (
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
-	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
@@ -92,9 +91,9 @@ func (ins *InsertSelect) RouteType() string {
 }
 
 // TryExecute performs a non-streaming exec.
-func (ins *InsertSelect) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (*sqltypes.Result, error) {
-	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, ins.QueryTimeout)
-	defer cancelFunc()
+func (ins *InsertSelect) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, _ bool) (_ *sqltypes.Result, err error) {
+	ctx, done := addQueryTimeout(ctx, vcursor, ins.Keyspace, ins.QueryTimeout)
+	defer func() { err = done(err) }()
 
 	if ins.Keyspace.Sharded {
 		return ins.execInsertSharded(ctx, vcursor, bindVars)
@@ -103,7 +102,7 @@ func (ins *InsertSelect) TryExecute(ctx context.Context, vcursor VCursor, bindVa
 }
 
 // TryStreamExecute performs a streaming exec.
-func (ins *InsertSelect) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+func (ins *InsertSelect) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) (err error) {
 	if ins.ForceNonStreaming {
 		res, err := ins.TryExecute(ctx, vcursor, bindVars, wantfields)
 		if err != nil {
@@ -111,12 +110,12 @@ func (ins *InsertSelect) TryStreamExecute(ctx context.Context, vcursor VCursor,
 		}
 		return callback(res)
 	}
-	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, ins.QueryTimeout)
-	defer cancelFunc()
+	ctx, done := addQueryTimeout(ctx, vcursor, ins.Keyspace, ins.QueryTimeout)
+	defer func() { err = done(err) }()
 
 	sharded := ins.Keyspace.Sharded
 	output := &sqltypes.Result{}
-	err := ins.execSelectStreaming(ctx, vcursor, bindVars, func(irr insertRowsResult) error {
+	err = ins.execSelectStreaming(ctx, vcursor, bindVars, func(irr insertRowsResult) error {
 		if len(irr.rows) == 0 {
 			return nil
 		}
@@ -133,10 +132,7 @@ func (ins *InsertSelect) TryStreamExecute(ctx context.Context, vcursor VCursor,
 		}
 
 		output.RowsAffected += qr.RowsAffected
-		// InsertID needs to be updated to the least insertID value in sqltypes.Result
-		if output.InsertID == 0 || output.InsertID > qr.InsertID {
-			output.InsertID = qr.InsertID
-		}
+		output.InsertID = sqltypes.MergeInsertID(output.InsertID, qr.InsertID)
 		return nil
 	})
 	if err != nil {
@@ -181,12 +177,15 @@ func (ins *InsertSelect) insertIntoShardedTable(
 	bindVars map[string]*querypb.BindVariable,
 	irr insertRowsResult,
 ) (*sqltypes.Result, error) {
-	rss, queries, err := ins.getInsertShardedQueries(ctx, vcursor, bindVars, irr.rows)
+	rounds, err := ins.getInsertShardedBatches(ctx, vcursor, irr.rows)
 	if err != nil {
 		return nil, err
 	}
 
-	qr, err := ins.executeInsertQueries(ctx, vcursor, rss, queries, irr.insertID)
+	query := func(rows []int) (*querypb.BoundQuery, error) {
+		return ins.shardQuery(bindVars, irr.rows, rows), nil
+	}
+	qr, err := ins.executeInsertBatches(ctx, vcursor, ins, rounds, query, irr.insertID)
 	if err != nil {
 		return nil, err
 	}
@@ -194,43 +193,19 @@ func (ins *InsertSelect) insertIntoShardedTable(
 	return qr, nil
 }
 
-func (ins *InsertSelect) executeInsertQueries(
-	ctx context.Context,
-	vcursor VCursor,
-	rss []*srvtopo.ResolvedShard,
-	queries []*querypb.BoundQuery,
-	insertID uint64,
-) (*sqltypes.Result, error) {
-	autocommit := (len(rss) == 1 || ins.MultiShardAutocommit) && vcursor.AutocommitApproval()
-	err := allowOnlyPrimary(rss...)
-	if err != nil {
-		return nil, err
-	}
-	result, errs := vcursor.ExecuteMultiShard(ctx, ins, rss, queries, true /* rollbackOnError */, autocommit)
-	if errs != nil {
-		return nil, vterrors.Aggregate(errs)
-	}
-
-	if insertID != 0 {
-		result.InsertID = insertID
-	}
-	return result, nil
-}
-
-func (ins *InsertSelect) getInsertShardedQueries(
+func (ins *InsertSelect) getInsertShardedBatches(
 	ctx context.Context,
 	vcursor VCursor,
-	bindVars map[string]*querypb.BindVariable,
 	rows []sqltypes.Row,
-) ([]*srvtopo.ResolvedShard, []*querypb.BoundQuery, error) {
+) ([][]insertBatch, error) {
 	vindexRowsValues, err := ins.buildVindexRowsValues(rows)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	keyspaceIDs, err := ins.processVindexes(ctx, vcursor, vindexRowsValues)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	var indexes []*querypb.Value
@@ -246,38 +221,35 @@ func (ins *InsertSelect) getInsertShardedQueries(
 	if len(destinations) == 0 {
 		// In this case, all we have is nil KeyspaceIds, we don't do
 		// anything at all.
-		return nil, nil, nil
+		return ins.insertBatches(vcursor, nil, nil, nil), nil
 	}
 
 	rss, indexesPerRss, err := vcursor.ResolveDestinations(ctx, ins.Keyspace.Name, indexes, destinations)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return ins.insertBatches(vcursor, rss, indexesPerRss, keyspaceIDs), nil
+}
 
-	queries := make([]*querypb.BoundQuery, len(rss))
-	for i := range rss {
-		bvs := sqltypes.CopyBindVariables(bindVars) // we don't want to create one huge bindvars for all values
-		var mids sqlparser.Values
-		for _, indexValue := range indexesPerRss[i] {
-			index, _ := strconv.Atoi(string(indexValue.Value))
-			if keyspaceIDs[index] != nil {
-				row := sqlparser.ValTuple{}
-				for colOffset, value := range rows[index] {
-					bvName := insertVarOffset(index, colOffset)
-					bvs[bvName] = sqltypes.ValueBindVariable(value)
-					row = append(row, sqlparser.NewArgument(bvName))
-				}
-				mids = append(mids, row)
-			}
-		}
-		rewritten := ins.Prefix + sqlparser.String(mids) + sqlparser.String(ins.Suffix)
-		queries[i] = &querypb.BoundQuery{
-			Sql:           rewritten,
-			BindVariables: bvs,
+// shardQuery returns the query that inserts the rows with the given indexes
+// into a shard.
+func (ins *InsertSelect) shardQuery(bindVars map[string]*querypb.BindVariable, rows []sqltypes.Row, indexes []int) *querypb.BoundQuery {
+	bvs := sqltypes.CopyBindVariables(bindVars) // we don't want to create one huge bindvars for all values
+	mids := make(sqlparser.Values, 0, len(indexes))
+	for _, index := range indexes {
+		row := sqlparser.ValTuple{}
+		for colOffset, value := range rows[index] {
+			bvName := insertVarOffset(index, colOffset)
+			bvs[bvName] = sqltypes.ValueBindVariable(value)
+			row = append(row, sqlparser.NewArgument(bvName))
 		}
+		mids = append(mids, row)
+	}
+	rewritten := ins.Prefix + sqlparser.String(mids) + sqlparser.String(ins.Suffix)
+	return &querypb.BoundQuery{
+		Sql:           rewritten,
+		BindVariables: bvs,
 	}
-
-	return rss, queries, nil
 }
 
 func (ins *InsertSelect) buildVindexRowsValues(rows []sqltypes.Row) ([][]sqltypes.Row, error) {
@@ -351,6 +323,9 @@ func (ic *InsertCommon) commonDesc() map[string]any {
 		"InputAsNonStreaming":  ic.ForceNonStreaming,
 		"NoAutoCommit":         ic.PreventAutoCommit,
 	}
+	if ic.BatchRows > 0 {
+		other["BatchRows"] = ic.BatchRows
+	}
 
 	if ic.Generate != nil {
 		if ic.Generate.Values == nil {
@@ -120,6 +120,65 @@ func TestInsertUnshardedGenerate(t *testing.T) {
 	expectResult(t, result, &sqltypes.Result{InsertID: 4})
 }
 
+func TestInsertUnshardedGenerateBlock(t *testing.T) {
+	ins := newQueryInsert(
+		InsertUnsharded,
+		&vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: false,
+		},
+		"dummy_insert",
+	)
+	ins.Generate = &Generate{
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks2",
+			Sharded: false,
+		},
+		Query: "dummy_generate",
+		Values: evalengine.NewTupleExpr(
+			evalengine.NewLiteralInt(1),
+			evalengine.NullExpr,
+			evalengine.NullExpr,
+		),
+		Sequence: "ks2.seq",
+	}
+
+	vc := newDMLTestVCursor("0")
+	vc.sequences = NewSequenceCache(10, 0)
+	vc.results = []*sqltypes.Result{
+		sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields(
+				"nextval",
+				"int64",
+			),
+			"4",
+		),
+		{InsertID: 1},
+		{InsertID: 1},
+	}
+
+	result, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		// Reserve a whole block from the sequence.
+		`ResolveDestinations ks2 [] Destinations:DestinationAnyShard()`,
+		`ExecuteStandalone dummy_generate n: type:INT64 value:"10" ks2 0`,
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"1" __seq1: type:INT64 value:"4" __seq2: type:INT64 value:"5"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 4})
+
+	// The next insert is served from the block.
+	vc.log = nil
+	result, err = ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`ExecuteMultiShard ks.0: dummy_insert {__seq0: type:INT64 value:"1" __seq1: type:INT64 value:"6" __seq2: type:INT64 value:"7"} true true`,
+	})
+	expectResult(t, result, &sqltypes.Result{InsertID: 6})
+}
+
 func TestInsertUnshardedGenerate_Zeros(t *testing.T) {
 	ins := newQueryInsert(
 		InsertUnsharded,
@@ -315,6 +374,107 @@ func TestInsertShardedSimple(t *testing.T) {
 	})
 }
 
+func TestInsertShardedBatches(t *testing.T) {
+	invschema := &vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"sharded": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"hash": {
+						Type: "hash",
+					},
+				},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{{
+							Name:    "hash",
+							Columns: []string{"id"},
+						}},
+					},
+				},
+			},
+		},
+	}
+	vs := vindexes.BuildVSchema(invschema, sqlparser.NewTestParser())
+	ks := vs.Keyspaces["sharded"]
+
+	newBatchedInsert := func(ignore bool) *Insert {
+		return newInsert(
+			InsertSharded,
+			ignore,
+			ks.Keyspace,
+			[][][]evalengine.Expr{{
+				// colVindex columns: id
+				// 4 rows.
+				{
+					evalengine.NewLiteralInt(4),
+					evalengine.NewLiteralInt(2),
+					evalengine.NewLiteralInt(3),
+					evalengine.NewLiteralInt(1),
+				},
+			}},
+			ks.Tables["t1"],
+			"prefix",
+			sqlparser.Values{
+				{&sqlparser.Argument{Name: "_id_0", Type: sqltypes.Int64}},
+				{&sqlparser.Argument{Name: "_id_1", Type: sqltypes.Int64}},
+				{&sqlparser.Argument{Name: "_id_2", Type: sqltypes.Int64}},
+				{&sqlparser.Argument{Name: "_id_3", Type: sqltypes.Int64}},
+			},
+			nil,
+		)
+	}
+	resolve := `ResolveDestinations sharded [value:"0" value:"1" value:"2" value:"3"] ` +
+		`Destinations:DestinationKeyspaceID(d2fd8867d50d2dfe),DestinationKeyspaceID(06e7ea22ce92708f),` +
+		`DestinationKeyspaceID(4eb190c9a2fa169c),DestinationKeyspaceID(166b40b44aba4bd6)`
+
+	// The rows of a shard are sorted by keyspace id and inserted in batches
+	// of BatchRows rows, one batch per shard at a time, in a transaction.
+	ins := newBatchedInsert(false)
+	ins.BatchRows = 2
+	ins.MultiShardAutocommit = true
+	vc := newDMLTestVCursor("-20", "20-")
+	vc.shardForKsid = []string{"20-", "-20", "20-", "20-"}
+	vc.results = []*sqltypes.Result{{RowsAffected: 3, InsertID: 10}, {RowsAffected: 1, InsertID: 5}}
+
+	result, err := ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		resolve,
+		`ExecuteMultiShard ` +
+			`sharded.20-: prefix(:_id_3 /* INT64 */),(:_id_2 /* INT64 */) {_id_2: type:INT64 value:"3" _id_3: type:INT64 value:"1"} ` +
+			`sharded.-20: prefix(:_id_1 /* INT64 */) {_id_1: type:INT64 value:"2"} ` +
+			`true false`,
+		`ExecuteMultiShard ` +
+			`sharded.20-: prefix(:_id_0 /* INT64 */) {_id_0: type:INT64 value:"4"} ` +
+			`true false`,
+	})
+	expectResult(t, result, &sqltypes.Result{RowsAffected: 4, InsertID: 5})
+
+	// The vtgate default applies when the statement doesn't set BatchRows,
+	// and the rows of an INSERT IGNORE keep their order.
+	saveBatchRows := testInsertBatchRows
+	testInsertBatchRows = 2
+	defer func() { testInsertBatchRows = saveBatchRows }()
+
+	ins = newBatchedInsert(true)
+	vc = newDMLTestVCursor("-20", "20-")
+	vc.shardForKsid = []string{"20-", "-20", "20-", "20-"}
+
+	_, err = ins.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		resolve,
+		`ExecuteMultiShard ` +
+			`sharded.20-: prefix(:_id_0 /* INT64 */),(:_id_2 /* INT64 */) {_id_0: type:INT64 value:"4" _id_2: type:INT64 value:"3"} ` +
+			`sharded.-20: prefix(:_id_1 /* INT64 */) {_id_1: type:INT64 value:"2"} ` +
+			`true false`,
+		`ExecuteMultiShard ` +
+			`sharded.20-: prefix(:_id_3 /* INT64 */) {_id_3: type:INT64 value:"1"} ` +
+			`true false`,
+	})
+}
+
 func TestInsertShardWithONDuplicateKey(t *testing.T) {
 	invschema := &vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
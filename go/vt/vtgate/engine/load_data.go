@@ -0,0 +1,614 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/key"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/srvtopo"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var _ Primitive = (*LoadData)(nil)
+
+// LocalInfileOpener asks the client for the content of the file of a
+// LOAD DATA LOCAL INFILE statement.
+type LocalInfileOpener func(fileName string) (io.ReadCloser, error)
+
+// WithLocalInfileOpener returns a context that lets the LOAD DATA LOCAL
+// INFILE statements executed with it read their file with open.
+func WithLocalInfileOpener(ctx context.Context, open LocalInfileOpener) context.Context {
+	return context.WithValue(ctx, localInfileOpenerKey, open)
+}
+
+// LoadDataFormat is the format of the lines and the fields of the file of a
+// LOAD DATA statement. EnclosedBy and EscapedBy are a single character, or
+// empty.
+type LoadDataFormat struct {
+	FieldsTerminatedBy string
+	EnclosedBy         string
+	EscapedBy          string
+	LinesStartingBy    string
+	LinesTerminatedBy  string
+}
+
+// DefaultLoadDataFormat is the format of LOAD DATA statements without FIELDS
+// and LINES clauses.
+var DefaultLoadDataFormat = LoadDataFormat{
+	FieldsTerminatedBy: "\t",
+	EscapedBy:          "\\",
+	LinesTerminatedBy:  "\n",
+}
+
+// LoadData reads the file of a LOAD DATA LOCAL INFILE statement from the
+// client, and inserts its rows in batches into the shards they belong to.
+type LoadData struct {
+	noInputs
+
+	// Keyspace specifies the keyspace of the table.
+	Keyspace *vindexes.Keyspace
+
+	// TargetDestination specifies an explicit target destination for all
+	// the rows. It is only used when Vindex is nil.
+	TargetDestination key.Destination
+
+	// TableName is the name of the table the rows are loaded into.
+	TableName string
+
+	// FileName is the name of the file that is requested from the client.
+	FileName string
+
+	// Format is the format of the file.
+	Format LoadDataFormat
+
+	// IgnoreLines is the number of lines skipped at the start of the file.
+	IgnoreLines int
+
+	// Columns is the number of columns read from each line, or 0 if the
+	// statement does not list them and the lines are inserted as they are.
+	Columns int
+
+	// Vindex maps the rows to the shards, from the values at VindexOffsets.
+	// If it is nil, all the rows go to a single shard.
+	Vindex        vindexes.Vindex
+	VindexOffsets []int
+
+	// Prefix is the insert sent to the shards, followed by the rows.
+	Prefix string
+
+	// RowSuffix is appended to the values of each row. It holds the
+	// expressions of the SET clause.
+	RowSuffix string
+
+	// Ignore is set when the rows that duplicate a unique key are skipped,
+	// and Replace when they replace the existing rows.
+	Ignore  bool
+	Replace bool
+}
+
+// RouteType implements the Primitive interface
+func (l *LoadData) RouteType() string {
+	return "LoadData"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (l *LoadData) GetKeyspaceName() string {
+	return l.Keyspace.Name
+}
+
+// GetTableName implements the Primitive interface
+func (l *LoadData) GetTableName() string {
+	return l.TableName
+}
+
+// NeedsTransaction implements the Primitive interface
+func (l *LoadData) NeedsTransaction() bool {
+	return true
+}
+
+// GetFields implements the Primitive interface
+func (l *LoadData) GetFields(context.Context, VCursor, map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{}, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (l *LoadData) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	qr, err := l.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(qr)
+}
+
+// TryExecute implements the Primitive interface
+func (l *LoadData) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (_ *sqltypes.Result, err error) {
+	open, ok := ctx.Value(localInfileOpenerKey).(LocalInfileOpener)
+	if !ok {
+		return nil, vterrors.VT12001("LOAD DATA LOCAL INFILE outside of the MySQL protocol")
+	}
+	file, err := open(l.FileName)
+	if err != nil {
+		return nil, err
+	}
+	// Closing the file reads what the client did not send yet, which must
+	// happen before the response, even on errors.
+	defer func() {
+		if cerr := file.Close(); err == nil && cerr != nil {
+			err = cerr
+		}
+	}()
+
+	batchSize := vcursor.LoadDataBatchSize()
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	loader := &loadDataLoader{
+		l:         l,
+		vcursor:   vcursor,
+		batchSize: batchSize,
+		batches:   make(map[string]*loadDataBatch),
+	}
+
+	parser := newLoadDataParser(file, l.Format)
+	for i := 0; i < l.IgnoreLines; i++ {
+		if _, err := parser.next(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	var rows [][]sqltypes.Value
+	for {
+		fields, err := parser.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		loader.records++
+		rows = append(rows, loader.row(fields))
+		if len(rows) < batchSize {
+			continue
+		}
+		if err := loader.route(ctx, rows); err != nil {
+			return nil, err
+		}
+		rows = nil
+	}
+	if err := loader.route(ctx, rows); err != nil {
+		return nil, err
+	}
+	if err := loader.flush(ctx, true); err != nil {
+		return nil, err
+	}
+	return loader.result(), nil
+}
+
+// loadDataBatch holds the rows waiting to be inserted into a shard.
+type loadDataBatch struct {
+	rs   *srvtopo.ResolvedShard
+	rows [][]sqltypes.Value
+}
+
+// loadDataLoader routes the rows of a LOAD DATA statement to their shards and
+// inserts them in batches.
+type loadDataLoader struct {
+	l         *LoadData
+	vcursor   VCursor
+	batchSize int
+
+	// batches are the rows waiting to be inserted, by shard, and shards
+	// the order in which the shards were first seen.
+	batches map[string]*loadDataBatch
+	shards  []string
+
+	records      int
+	warnings     int
+	rowsAffected uint64
+}
+
+// row returns the values of a row of the file, recording a warning if it
+// has fewer or more fields than the columns.
+func (ld *loadDataLoader) row(fields []loadDataField) []sqltypes.Value {
+	values := make([]sqltypes.Value, 0, len(fields))
+	for _, field := range fields {
+		if field.null {
+			values = append(values, sqltypes.NULL)
+			continue
+		}
+		values = append(values, sqltypes.MakeTrusted(sqltypes.VarChar, field.value))
+	}
+
+	columns := ld.l.Columns
+	switch {
+	case columns == 0:
+	case len(values) < columns:
+		ld.warn(sqlerror.ERWarnTooFewRecords, "Row %d doesn't contain data for all columns", ld.records)
+	case len(values) > columns:
+		ld.warn(sqlerror.ERWarnTooManyRecords, "Row %d was truncated; it contained more data than there were input columns", ld.records)
+		values = values[:columns]
+	}
+	return values
+}
+
+func (ld *loadDataLoader) warn(code sqlerror.ErrorCode, format string, args ...any) {
+	ld.warnings++
+	ld.vcursor.Session().RecordWarning(&querypb.QueryWarning{
+		Code:    uint32(code),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// route adds the rows to the batches of their shards, and inserts the
+// batches that are full.
+func (ld *loadDataLoader) route(ctx context.Context, rows [][]sqltypes.Value) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if ld.l.Vindex == nil {
+		destination := ld.l.TargetDestination
+		if destination == nil {
+			destination = key.DestinationAnyShard{}
+		}
+		rss, _, err := ld.vcursor.ResolveDestinations(ctx, ld.l.Keyspace.Name, nil, []key.Destination{destination})
+		if err != nil {
+			return err
+		}
+		if len(rss) != 1 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "LOAD DATA can only load the rows into a single shard, got: %v", destination)
+		}
+		ld.add(rss[0], rows...)
+		return ld.flush(ctx, false)
+	}
+
+	vindexValues := make([][]sqltypes.Value, len(rows))
+	for i, row := range rows {
+		values := make([]sqltypes.Value, len(ld.l.VindexOffsets))
+		for j, offset := range ld.l.VindexOffsets {
+			if offset < len(row) {
+				values[j] = row[offset]
+			} else {
+				values[j] = sqltypes.NULL
+			}
+		}
+		vindexValues[i] = values
+	}
+	destinations, err := vindexes.Map(ctx, ld.l.Vindex, ld.vcursor, vindexValues)
+	if err != nil {
+		return err
+	}
+	indexes := make([]*querypb.Value, len(rows))
+	for i, destination := range destinations {
+		if _, ok := destination.(key.DestinationKeyspaceID); !ok {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "could not map %v to a keyspace id", vindexValues[i])
+		}
+		indexes[i] = &querypb.Value{Value: strconv.AppendInt(nil, int64(i), 10)}
+	}
+	rss, indexesPerRss, err := ld.vcursor.ResolveDestinations(ctx, ld.l.Keyspace.Name, indexes, destinations)
+	if err != nil {
+		return err
+	}
+	for i, rs := range rss {
+		for _, indexValue := range indexesPerRss[i] {
+			index, _ := strconv.ParseInt(string(indexValue.Value), 0, 64)
+			ld.add(rs, rows[index])
+		}
+	}
+	return ld.flush(ctx, false)
+}
+
+func (ld *loadDataLoader) add(rs *srvtopo.ResolvedShard, rows ...[]sqltypes.Value) {
+	batch, ok := ld.batches[rs.Target.Shard]
+	if !ok {
+		batch = &loadDataBatch{rs: rs}
+		ld.batches[rs.Target.Shard] = batch
+		ld.shards = append(ld.shards, rs.Target.Shard)
+	}
+	batch.rows = append(batch.rows, rows...)
+}
+
+// flush inserts the batches that are full, or all of them if all is set,
+// concurrently on their shards.
+func (ld *loadDataLoader) flush(ctx context.Context, all bool) error {
+	var rss []*srvtopo.ResolvedShard
+	var queries []*querypb.BoundQuery
+	for _, shard := range ld.shards {
+		batch := ld.batches[shard]
+		for len(batch.rows) > 0 && (all || len(batch.rows) >= ld.batchSize) {
+			n := min(len(batch.rows), ld.batchSize)
+			rss = append(rss, batch.rs)
+			queries = append(queries, &querypb.BoundQuery{Sql: ld.l.insertQuery(batch.rows[:n])})
+			batch.rows = batch.rows[n:]
+		}
+	}
+	if len(rss) == 0 {
+		return nil
+	}
+	qr, errs := ld.vcursor.ExecuteMultiShard(ctx, ld.l, rss, queries, true /* rollbackOnError */, false /* canAutocommit */)
+	if err := vterrors.Aggregate(errs); err != nil {
+		return err
+	}
+	ld.rowsAffected += qr.RowsAffected
+	return nil
+}
+
+// result returns the result of the statement, with the info that MySQL
+// returns for LOAD DATA.
+func (ld *loadDataLoader) result() *sqltypes.Result {
+	records := uint64(ld.records)
+	var deleted, skipped uint64
+	switch {
+	case ld.l.Replace && ld.rowsAffected > records:
+		deleted = ld.rowsAffected - records
+	case ld.l.Ignore && ld.rowsAffected < records:
+		skipped = records - ld.rowsAffected
+	}
+	return &sqltypes.Result{
+		RowsAffected: ld.rowsAffected,
+		Info:         fmt.Sprintf("Records: %d  Deleted: %d  Skipped: %d  Warnings: %d", records, deleted, skipped, uint64(ld.warnings)+skipped),
+	}
+}
+
+// insertQuery returns the insert of the rows.
+func (l *LoadData) insertQuery(rows [][]sqltypes.Value) string {
+	var buf strings.Builder
+	buf.WriteString(l.Prefix)
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('(')
+		for j, value := range row {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			value.EncodeSQLStringBuilder(&buf)
+		}
+		// Missing columns are set to their default, as MySQL does.
+		for j := len(row); j < l.Columns; j++ {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString("default")
+		}
+		buf.WriteString(l.RowSuffix)
+		buf.WriteByte(')')
+	}
+	return buf.String()
+}
+
+func (l *LoadData) description() PrimitiveDescription {
+	other := map[string]any{
+		"Table":    l.TableName,
+		"FileName": l.FileName,
+		"Query":    l.Prefix,
+	}
+	if l.IgnoreLines > 0 {
+		other["IgnoreLines"] = l.IgnoreLines
+	}
+	if l.RowSuffix != "" {
+		other["RowSuffix"] = l.RowSuffix
+	}
+	if l.Vindex != nil {
+		other["Vindex"] = l.Vindex.String()
+		other["VindexOffsets"] = l.VindexOffsets
+	}
+	return PrimitiveDescription{
+		OperatorType:      "LoadData",
+		Keyspace:          l.Keyspace,
+		TargetDestination: l.TargetDestination,
+		TargetTabletType:  topodatapb.TabletType_PRIMARY,
+		Other:             other,
+	}
+}
+
+// loadDataField is a field of a line of the file of a LOAD DATA statement.
+type loadDataField struct {
+	value []byte
+	null  bool
+}
+
+// loadDataParser reads the lines of the file of a LOAD DATA statement, and
+// splits them into fields, following the rules of MySQL.
+type loadDataParser struct {
+	r      *bufio.Reader
+	format LoadDataFormat
+}
+
+func newLoadDataParser(r io.Reader, format LoadDataFormat) *loadDataParser {
+	return &loadDataParser{r: bufio.NewReader(r), format: format}
+}
+
+// hasPrefix returns true if the unread content starts with s.
+func (p *loadDataParser) hasPrefix(s string) bool {
+	if s == "" {
+		return false
+	}
+	data, _ := p.r.Peek(len(s))
+	return string(data) == s
+}
+
+func (p *loadDataParser) isByte(b byte, s string) bool {
+	return len(s) == 1 && s[0] == b
+}
+
+// skipToLineStart reads up to the end of the next LINES STARTING BY prefix.
+// It returns io.EOF if there is no other prefix.
+func (p *loadDataParser) skipToLineStart() error {
+	prefix := p.format.LinesStartingBy
+	for !p.hasPrefix(prefix) {
+		if _, err := p.r.ReadByte(); err != nil {
+			return err
+		}
+	}
+	_, err := p.r.Discard(len(prefix))
+	return err
+}
+
+// next returns the fields of the next line, or io.EOF at the end of the file.
+func (p *loadDataParser) next() ([]loadDataField, error) {
+	if p.format.LinesStartingBy != "" {
+		if err := p.skipToLineStart(); err != nil {
+			return nil, err
+		}
+	} else if _, err := p.r.Peek(1); err != nil {
+		return nil, err
+	}
+
+	var fields []loadDataField
+	for {
+		field, endOfLine, err := p.nextField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		if endOfLine {
+			return fields, nil
+		}
+	}
+}
+
+// nextField reads the next field, and the terminator that follows it. It
+// reports whether the terminator ends the line.
+func (p *loadDataParser) nextField() (loadDataField, bool, error) {
+	var field loadDataField
+	enclosed := false
+	if b, err := p.r.Peek(1); err == nil && p.isByte(b[0], p.format.EnclosedBy) {
+		enclosed = true
+		_, _ = p.r.Discard(1)
+	}
+
+	var value bytes.Buffer
+	nullEscape := false
+	for {
+		// An enclosed field only ends after its closing enclosing character.
+		if !enclosed {
+			if p.hasPrefix(p.format.FieldsTerminatedBy) {
+				_, _ = p.r.Discard(len(p.format.FieldsTerminatedBy))
+				return p.field(value.Bytes(), nullEscape, false), false, nil
+			}
+			if p.hasPrefix(p.format.LinesTerminatedBy) {
+				_, _ = p.r.Discard(len(p.format.LinesTerminatedBy))
+				return p.field(value.Bytes(), nullEscape, false), true, nil
+			}
+		}
+
+		b, err := p.r.ReadByte()
+		if err == io.EOF {
+			if enclosed {
+				// The field was not closed: its opening enclosing
+				// character is part of the value.
+				field.value = append([]byte(p.format.EnclosedBy), value.Bytes()...)
+				return field, true, nil
+			}
+			return p.field(value.Bytes(), nullEscape, false), true, nil
+		}
+		if err != nil {
+			return field, false, err
+		}
+
+		switch {
+		case p.isByte(b, p.format.EscapedBy):
+			e, err := p.r.ReadByte()
+			if err == io.EOF {
+				value.WriteByte(b)
+				continue
+			}
+			if err != nil {
+				return field, false, err
+			}
+			if e == 'N' && !enclosed && value.Len() == 0 {
+				nullEscape = true
+			} else {
+				nullEscape = false
+			}
+			value.WriteByte(unescapeLoadData(e))
+		case enclosed && p.isByte(b, p.format.EnclosedBy):
+			if p.hasPrefix(p.format.EnclosedBy) {
+				// A doubled enclosing character stands for itself.
+				_, _ = p.r.Discard(1)
+				value.WriteByte(b)
+				continue
+			}
+			if p.hasPrefix(p.format.FieldsTerminatedBy) {
+				_, _ = p.r.Discard(len(p.format.FieldsTerminatedBy))
+				return p.field(value.Bytes(), false, true), false, nil
+			}
+			if p.hasPrefix(p.format.LinesTerminatedBy) {
+				_, _ = p.r.Discard(len(p.format.LinesTerminatedBy))
+				return p.field(value.Bytes(), false, true), true, nil
+			}
+			if _, err := p.r.Peek(1); err == io.EOF {
+				return p.field(value.Bytes(), false, true), true, nil
+			}
+			value.WriteByte(b)
+		default:
+			nullEscape = false
+			value.WriteByte(b)
+		}
+	}
+}
+
+// field returns the field of value. Unenclosed fields are NULL if they are
+// \N, or NULL when the fields can be enclosed.
+func (p *loadDataParser) field(value []byte, nullEscape, enclosed bool) loadDataField {
+	if !enclosed {
+		if nullEscape && len(value) == 1 {
+			return loadDataField{null: true}
+		}
+		if p.format.EnclosedBy != "" && string(value) == "NULL" {
+			return loadDataField{null: true}
+		}
+	}
+	return loadDataField{value: bytes.Clone(value)}
+}
+
+// unescapeLoadData returns the character that an escape sequence stands for.
+func unescapeLoadData(b byte) byte {
+	switch b {
+	case '0':
+		return 0
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'Z':
+		return 26
+	default:
+		return b
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+func TestLoadDataParser(t *testing.T) {
+	csv := LoadDataFormat{
+		FieldsTerminatedBy: ",",
+		EnclosedBy:         `"`,
+		EscapedBy:          `\`,
+		LinesTerminatedBy:  "\r\n",
+	}
+	tcases := []struct {
+		name   string
+		format LoadDataFormat
+		input  string
+		want   [][]string
+	}{{
+		name:   "default format",
+		format: DefaultLoadDataFormat,
+		input:  "1\ta\n2\tb\\tc\\\\\n3\t\\N\n\n4",
+		want:   [][]string{{"1", "a"}, {"2", "b\tc\\"}, {"3", "NULL"}, {""}, {"4"}},
+	}, {
+		name:   "escaped terminators",
+		format: DefaultLoadDataFormat,
+		input:  "a\\\tb\tc\\\nd\n\\Nx\t\\0\n",
+		want:   [][]string{{"a\tb", "c\nd"}, {"Nx", "\x00"}},
+	}, {
+		name:   "enclosed fields",
+		format: csv,
+		input:  "1,\"a,b\",\"say \"\"hi\"\"\"\r\n2,NULL,\"NULL\"\r\n3,\"x\"y\",\"\\N\"\r\n",
+		want:   [][]string{{"1", "a,b", `say "hi"`}, {"2", "NULL", "'NULL'"}, {"3", `x"y`, "N"}},
+	}, {
+		name:   "unclosed enclosed field",
+		format: csv,
+		input:  "1,\"a",
+		want:   [][]string{{"1", `"a`}},
+	}, {
+		name: "lines starting by",
+		format: LoadDataFormat{
+			FieldsTerminatedBy: ",",
+			EscapedBy:          `\`,
+			LinesStartingBy:    "xxx",
+			LinesTerminatedBy:  "\n",
+		},
+		input: "xxx1,a\nskipped\nabcxxx2,b\n",
+		want:  [][]string{{"1", "a"}, {"2", "b"}},
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			parser := newLoadDataParser(strings.NewReader(tc.input), tc.format)
+			var got [][]string
+			for {
+				fields, err := parser.next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				var row []string
+				for _, field := range fields {
+					switch {
+					case field.null:
+						row = append(row, "NULL")
+					case string(field.value) == "NULL":
+						row = append(row, "'NULL'")
+					default:
+						row = append(row, string(field.value))
+					}
+				}
+				got = append(got, row)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func withLocalInfile(content string) context.Context {
+	return WithLocalInfileOpener(context.Background(), func(fileName string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(content)), nil
+	})
+}
+
+func TestLoadDataSharded(t *testing.T) {
+	saveBatchSize := testLoadDataBatchSize
+	testLoadDataBatchSize = 2
+	defer func() { testLoadDataBatchSize = saveBatchSize }()
+
+	hash, err := vindexes.CreateVindex("hash", "hash", nil)
+	require.NoError(t, err)
+	ld := &LoadData{
+		Keyspace:      &vindexes.Keyspace{Name: "sharded", Sharded: true},
+		TableName:     "t1",
+		FileName:      "data.tsv",
+		Format:        DefaultLoadDataFormat,
+		IgnoreLines:   1,
+		Columns:       2,
+		Vindex:        hash,
+		VindexOffsets: []int{0},
+		Prefix:        "insert ignore into t1(id, c) values ",
+		Ignore:        true,
+	}
+
+	vc := newDMLTestVCursor("-20", "20-")
+	vc.shardForKsid = []string{"-20", "20-", "-20"}
+	vc.results = []*sqltypes.Result{{RowsAffected: 2}, {RowsAffected: 0}}
+	result, err := ld.TryExecute(withLocalInfile("id\tc\n1\ta\n2\tb\tx\n3\n"), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations sharded [value:"0" value:"1"] Destinations:DestinationKeyspaceID(166b40b44aba4bd6),DestinationKeyspaceID(06e7ea22ce92708f)`,
+		`ResolveDestinations sharded [value:"0"] Destinations:DestinationKeyspaceID(4eb190c9a2fa169c)`,
+		// The batch of -20 is full once the third row is routed.
+		`ExecuteMultiShard sharded.-20: insert ignore into t1(id, c) values ('1', 'a'), ('3', default) {} true false`,
+		`ExecuteMultiShard sharded.20-: insert ignore into t1(id, c) values ('2', 'b') {} true false`,
+	})
+	expectResult(t, result, &sqltypes.Result{
+		RowsAffected: 2,
+		Info:         "Records: 3  Deleted: 0  Skipped: 1  Warnings: 3",
+	})
+	require.Len(t, vc.warnings, 2)
+	assert.Equal(t, uint32(sqlerror.ERWarnTooManyRecords), vc.warnings[0].Code)
+	assert.Equal(t, "Row 2 was truncated; it contained more data than there were input columns", vc.warnings[0].Message)
+	assert.Equal(t, uint32(sqlerror.ERWarnTooFewRecords), vc.warnings[1].Code)
+	assert.Equal(t, "Row 3 doesn't contain data for all columns", vc.warnings[1].Message)
+}
+
+func TestLoadDataUnsharded(t *testing.T) {
+	ld := &LoadData{
+		Keyspace:  &vindexes.Keyspace{Name: "ks"},
+		TableName: "t1",
+		FileName:  "data.csv",
+		Format: LoadDataFormat{
+			FieldsTerminatedBy: ",",
+			EnclosedBy:         `"`,
+			EscapedBy:          `\`,
+			LinesTerminatedBy:  "\n",
+		},
+		Prefix:    "replace into t1(id, c, d) values ",
+		RowSuffix: ", now()",
+		Replace:   true,
+	}
+
+	vc := newDMLTestVCursor("0")
+	vc.results = []*sqltypes.Result{{RowsAffected: 3}}
+	result, err := ld.TryExecute(withLocalInfile("1,\"it's\"\n2,NULL\n"), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAnyShard()`,
+		`ExecuteMultiShard ks.0: replace into t1(id, c, d) values ('1', 'it\'s', now()), ('2', null, now()) {} true false`,
+	})
+	expectResult(t, result, &sqltypes.Result{
+		RowsAffected: 3,
+		Info:         "Records: 2  Deleted: 1  Skipped: 0  Warnings: 0",
+	})
+
+	_, err = ld.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.ErrorContains(t, err, "LOAD DATA LOCAL INFILE outside of the MySQL protocol")
+}
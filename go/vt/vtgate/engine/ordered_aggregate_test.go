@@ -1164,3 +1164,50 @@ func TestGroupConcat(t *testing.T) {
 		})
 	}
 }
+
+// TestGroupConcatDistinctSeparator tests group_concat with a separator and distinct values on engine.
+func TestGroupConcatDistinctSeparator(t *testing.T) {
+	fields := sqltypes.MakeTestFields(
+		"c1|group_concat(c2)",
+		"int64|text",
+	)
+
+	aggr := NewAggregateParam(AggregateGroupConcat, 1, "", collations.MySQL8())
+	separator := " - "
+	aggr.Separator = &separator
+	aggr.Distinct = true
+	aggr.Type = evalengine.NewType(sqltypes.Text, collations.CollationUtf8mb4ID)
+
+	fp := &fakePrimitive{results: []*sqltypes.Result{sqltypes.MakeTestResult(fields,
+		"10|a", "10|b", "10|A", "10|a",
+		"20|b", "20|null", "20|b",
+		"30|c", "30|a",
+	)}}
+	oa := &OrderedAggregate{
+		Aggregates:  []*AggregateParams{aggr},
+		GroupByKeys: []*GroupByParams{{KeyCol: 0}},
+		Input:       fp,
+	}
+	assert.Equal(t, "group_concat(DISTINCT 1 COLLATE utf8mb4_0900_ai_ci SEPARATOR ' - ')", aggr.String())
+
+	want := sqltypes.MakeTestResult(fields,
+		`10|a - b`,
+		`20|b`,
+		`30|c - a`,
+	)
+	qr, err := oa.TryExecute(context.Background(), &noopVCursor{}, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, want, qr)
+
+	fp.rewind()
+	results := &sqltypes.Result{}
+	err = oa.TryStreamExecute(context.Background(), &noopVCursor{}, nil, true, func(qr *sqltypes.Result) error {
+		if qr.Fields != nil {
+			results.Fields = qr.Fields
+		}
+		results.Rows = append(results.Rows, qr.Rows...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, want, results)
+}
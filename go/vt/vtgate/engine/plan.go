@@ -24,6 +24,7 @@ import (
 
 	"vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
 
 // Plan represents the execution strategy for a given query.
@@ -32,12 +33,13 @@ import (
 // each node does its part by combining the results of the
 // sub-nodes.
 type Plan struct {
-	Type         sqlparser.StatementType // The type of query we have
-	Original     string                  // Original is the original query.
-	Instructions Primitive               // Instructions contains the instructions needed to fulfil the query.
-	BindVarNeeds *sqlparser.BindVarNeeds // Stores BindVars needed to be provided as part of expression rewriting
-	Warnings     []*query.QueryWarning   // Warnings that need to be yielded every time this query runs
-	TablesUsed   []string                // TablesUsed is the list of tables that this plan will query
+	Type         sqlparser.StatementType    // The type of query we have
+	Original     string                     // Original is the original query.
+	Instructions Primitive                  // Instructions contains the instructions needed to fulfil the query.
+	BindVarNeeds *sqlparser.BindVarNeeds    // Stores BindVars needed to be provided as part of expression rewriting
+	Warnings     []*query.QueryWarning      // Warnings that need to be yielded every time this query runs
+	BindVarTypes map[string]evalengine.Type // Stores the types of the vindex columns the bind variables are compared with
+	TablesUsed   []string                   // TablesUsed is the list of tables that this plan will query
 
 	ExecCount    uint64 // Count of times this plan was executed
 	ExecTime     uint64 // Total execution time
@@ -45,6 +47,9 @@ type Plan struct {
 	RowsReturned uint64 // Total number of rows
 	RowsAffected uint64 // Total number of rows
 	Errors       uint64 // Total number of errors
+	CacheHits    uint64 // Count of times this plan was found in the plan cache
+
+	lastError atomic.Pointer[string] // The message of the last error this plan returned
 }
 
 // AddStats updates the plan execution statistics
@@ -57,6 +62,28 @@ func (p *Plan) AddStats(execCount uint64, execTime time.Duration, shardQueries,
 	atomic.AddUint64(&p.Errors, errors)
 }
 
+// AddCacheHit counts a lookup of the plan cache that found this plan
+func (p *Plan) AddCacheHit() {
+	atomic.AddUint64(&p.CacheHits, 1)
+}
+
+// SetLastError records the error of the last failed execution of the plan
+func (p *Plan) SetLastError(err error) {
+	if err == nil {
+		return
+	}
+	msg := err.Error()
+	p.lastError.Store(&msg)
+}
+
+// LastError returns the message of the last error this plan returned, if any
+func (p *Plan) LastError() string {
+	if msg := p.lastError.Load(); msg != nil {
+		return *msg
+	}
+	return ""
+}
+
 // Stats returns a copy of the plan execution statistics
 func (p *Plan) Stats() (execCount uint64, execTime time.Duration, shardQueries, rowsAffected, rowsReturned, errors uint64) {
 	execCount = atomic.LoadUint64(&p.ExecCount)
@@ -86,6 +113,8 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsAffected uint64                `json:",omitempty"`
 		RowsReturned uint64                `json:",omitempty"`
 		Errors       uint64                `json:",omitempty"`
+		CacheHits    uint64                `json:",omitempty"`
+		LastError    string                `json:",omitempty"`
 		TablesUsed   []string              `json:",omitempty"`
 	}{
 		QueryType:    p.Type.String(),
@@ -97,6 +126,8 @@ func (p *Plan) MarshalJSON() ([]byte, error) {
 		RowsAffected: atomic.LoadUint64(&p.RowsAffected),
 		RowsReturned: atomic.LoadUint64(&p.RowsReturned),
 		Errors:       atomic.LoadUint64(&p.Errors),
+		CacheHits:    atomic.LoadUint64(&p.CacheHits),
+		LastError:    p.LastError(),
 		TablesUsed:   p.TablesUsed,
 	}
 
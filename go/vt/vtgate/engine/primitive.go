@@ -112,10 +112,14 @@ type (
 
 		// ShowExec takes in show command and use executor to execute the query, they are used when topo access is involved.
 		ShowExec(ctx context.Context, command sqlparser.ShowCommandType, filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
+		// VitessMetadataRows returns all the rows of a table of the vitess_metadata virtual schema.
+		VitessMetadataRows(ctx context.Context, table string) ([][]sqltypes.Value, error)
 		// SetExec takes in k,v pair and use executor to set them in topo metadata.
 		SetExec(ctx context.Context, name string, value string) error
 		// ThrottleApp sets a ThrottlerappRule in topo
 		ThrottleApp(ctx context.Context, throttleAppRule *topodatapb.ThrottledAppRule) error
+		// ClearPlans empties the plan cache of vtgate, if the caller is authorized to
+		ClearPlans(ctx context.Context) error
 
 		// CanUseSetVar returns true if system_settings can use SET_VAR hint.
 		CanUseSetVar() bool
@@ -131,6 +135,19 @@ type (
 
 		// CloneForReplicaWarming clones the VCursor for re-use in warming queries to replicas
 		CloneForReplicaWarming(ctx context.Context) VCursor
+
+		// SequenceCache returns the cache of the sequence values that are
+		// reserved by this vtgate. A nil cache reserves the values from the
+		// sequence table for every insert.
+		SequenceCache() *SequenceCache
+
+		// LoadDataBatchSize returns the number of rows that LOAD DATA
+		// inserts into a shard with a single query.
+		LoadDataBatchSize() int
+
+		// InsertBatchRows returns the maximum number of rows that an
+		// INSERT inserts into a shard with a single query, 0 for no limit.
+		InsertBatchRows() int
 	}
 
 	// SessionActions gives primitives ability to interact with the session state
@@ -176,6 +193,11 @@ type (
 		SetSessionEnableSystemSettings(context.Context, bool) error
 		GetSessionEnableSystemSettings() bool
 
+		SetSnapshotReads(context.Context, bool) error
+		SetSkipReadRetry(context.Context, bool) error
+		SetReadOnlyTxOnReplica(context.Context, bool) error
+		SetTenantID(string) error
+
 		GetSystemVariables(func(k string, v string))
 		HasSystemVariables() bool
 
@@ -186,6 +208,9 @@ type (
 
 		// HasCreatedTempTable will mark the session as having created temp tables
 		HasCreatedTempTable()
+		// HasUntrackedReservedConnState will mark the session as having changed the
+		// state of its reserved connections in a way that cannot be replayed
+		HasUntrackedReservedConnState()
 		GetWarnings() []*querypb.QueryWarning
 
 		// AnyAdvisoryLockTaken returns true of any advisory lock is taken
@@ -206,12 +231,25 @@ type (
 		// This is used to select the right shard session to perform the vindex lookup query.
 		SetCommitOrder(co vtgatepb.CommitOrder)
 
-		// GetQueryTimeout gets the query timeout and takes in the query timeout from comments
-		GetQueryTimeout(queryTimeoutFromComment int) int
+		// GetQueryTimeout returns the timeout of a query sent to the keyspace, given the timeout from
+		// the comment directive of the query, along with the level of the hierarchy it is set by.
+		GetQueryTimeout(keyspace string, queryTimeoutFromComment int) (int, QueryTimeoutSource)
 
 		// SetQueryTimeout sets the query timeout
 		SetQueryTimeout(queryTimeout int64)
 
+		// SetStreamChunkRows sets the number of rows per chunk of the streaming queries
+		SetStreamChunkRows(rows int64)
+
+		// SetStreamChunkTimeout sets the timeout in milliseconds between two chunks of the streaming queries
+		SetStreamChunkTimeout(timeout int64)
+
+		// SetMaxReplicaLag sets the maximum replication lag in seconds of the replicas that serve the queries
+		SetMaxReplicaLag(lag int64)
+
+		// SetTabletTags sets the tablet tags that the tablets serving the queries must carry
+		SetTabletTags(tags string)
+
 		// InTransaction returns true if the session has already opened transaction or
 		// will start a transaction on the query execution.
 		InTransaction() bool
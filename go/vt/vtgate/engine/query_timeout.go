@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// QueryTimeoutSource is the level of the query timeout hierarchy a query
+// timeout is set by. Each level overrides the ones before it.
+type QueryTimeoutSource int
+
+const (
+	// QueryTimeoutFromFlag is the default timeout of vtgate, set by the --query-timeout flag.
+	QueryTimeoutFromFlag QueryTimeoutSource = iota
+	// QueryTimeoutFromKeyspace is the query_timeout_ms setting of the keyspace in the vschema.
+	QueryTimeoutFromKeyspace
+	// QueryTimeoutFromSession is the query_timeout session variable.
+	QueryTimeoutFromSession
+	// QueryTimeoutFromComment is the QUERY_TIMEOUT_MS comment directive of the query.
+	QueryTimeoutFromComment
+)
+
+// String returns the description of the level used in the error messages.
+func (s QueryTimeoutSource) String() string {
+	switch s {
+	case QueryTimeoutFromKeyspace:
+		return "query_timeout_ms setting of the keyspace"
+	case QueryTimeoutFromSession:
+		return "query_timeout session variable"
+	case QueryTimeoutFromComment:
+		return "QUERY_TIMEOUT_MS comment directive"
+	default:
+		return "--query-timeout flag"
+	}
+}
+
+// queryTimeoutError is the cause of the cancellation of a context whose
+// query timeout fired.
+type queryTimeoutError struct {
+	timeout  int
+	source   QueryTimeoutSource
+	keyspace string
+}
+
+func (e *queryTimeoutError) Error() string {
+	if e.source == QueryTimeoutFromKeyspace {
+		return fmt.Sprintf("query timed out after %dms set by the %s %s", e.timeout, e.source, e.keyspace)
+	}
+	return fmt.Sprintf("query timed out after %dms set by the %s", e.timeout, e.source)
+}
+
+// addQueryTimeout adds the query timeout to the context it receives and returns the modified context,
+// along with the function to call with the result of the query once it is done. That function cancels
+// the context and, if the query failed because the timeout fired, states in the error which level of
+// the query timeout hierarchy the timeout was set by.
+func addQueryTimeout(ctx context.Context, vcursor VCursor, ks *vindexes.Keyspace, queryTimeout int) (context.Context, func(error) error) {
+	var keyspace string
+	if ks != nil {
+		keyspace = ks.Name
+	}
+	timeout, source := vcursor.Session().GetQueryTimeout(keyspace, queryTimeout)
+	if timeout == 0 {
+		return ctx, func(err error) error { return err }
+	}
+
+	cause := &queryTimeoutError{timeout: timeout, source: source, keyspace: keyspace}
+	ctx, cancel := context.WithTimeoutCause(ctx, time.Duration(timeout)*time.Millisecond, cause)
+	return ctx, func(err error) error {
+		// The errors of the queries whose context was canceled because of the
+		// timeout of an outer primitive are left for that primitive to annotate.
+		if err != nil && context.Cause(ctx) == error(cause) {
+			err = vterrors.Wrap(err, cause.Error())
+		}
+		cancel()
+		return err
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// queryTimeoutVCursor returns the same query timeout for all the keyspaces.
+type queryTimeoutVCursor struct {
+	noopVCursor
+	timeout int
+	source  QueryTimeoutSource
+}
+
+func (t *queryTimeoutVCursor) Session() SessionActions {
+	return t
+}
+
+func (t *queryTimeoutVCursor) GetQueryTimeout(keyspace string, queryTimeoutFromComment int) (int, QueryTimeoutSource) {
+	return t.timeout, t.source
+}
+
+func TestAddQueryTimeout(t *testing.T) {
+	ks := &vindexes.Keyspace{Name: "ks"}
+	queryErr := errors.New("vttablet: context deadline exceeded")
+
+	tests := []struct {
+		source QueryTimeoutSource
+		want   string
+	}{{
+		source: QueryTimeoutFromFlag,
+		want:   "query timed out after 10ms set by the --query-timeout flag: vttablet: context deadline exceeded",
+	}, {
+		source: QueryTimeoutFromKeyspace,
+		want:   "query timed out after 10ms set by the query_timeout_ms setting of the keyspace ks: vttablet: context deadline exceeded",
+	}, {
+		source: QueryTimeoutFromSession,
+		want:   "query timed out after 10ms set by the query_timeout session variable: vttablet: context deadline exceeded",
+	}, {
+		source: QueryTimeoutFromComment,
+		want:   "query timed out after 10ms set by the QUERY_TIMEOUT_MS comment directive: vttablet: context deadline exceeded",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.source.String(), func(t *testing.T) {
+			ctx, done := addQueryTimeout(context.Background(), &queryTimeoutVCursor{timeout: 10, source: tt.source}, ks, 0)
+			<-ctx.Done()
+			assert.EqualError(t, done(queryErr), tt.want)
+		})
+	}
+
+	// The errors of the queries that didn't time out are left as they are.
+	ctx, done := addQueryTimeout(context.Background(), &queryTimeoutVCursor{timeout: 10_000, source: QueryTimeoutFromFlag}, ks, 0)
+	_, hasDeadline := ctx.Deadline()
+	assert.True(t, hasDeadline)
+	assert.Equal(t, queryErr, done(queryErr))
+	assert.Equal(t, context.Canceled, ctx.Err())
+
+	// No deadline is set without a timeout.
+	ctx, done = addQueryTimeout(context.Background(), &queryTimeoutVCursor{}, ks, 0)
+	_, hasDeadline = ctx.Deadline()
+	assert.False(t, hasDeadline)
+	assert.NoError(t, done(nil))
+
+	// Only the primitive whose timeout fired annotates the error.
+	outer, outerDone := addQueryTimeout(context.Background(), &queryTimeoutVCursor{timeout: 10, source: QueryTimeoutFromSession}, ks, 0)
+	inner, innerDone := addQueryTimeout(outer, &queryTimeoutVCursor{timeout: 10_000, source: QueryTimeoutFromComment}, ks, 0)
+	<-inner.Done()
+	err := innerDone(queryErr)
+	assert.Equal(t, queryErr, err)
+	assert.EqualError(t, outerDone(err), "query timed out after 10ms set by the query_timeout session variable: vttablet: context deadline exceeded")
+}
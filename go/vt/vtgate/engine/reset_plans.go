@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+var _ Primitive = (*ResetPlans)(nil)
+
+// ResetPlans empties the plan cache of the vtgate that executes it, together
+// with the execution statistics of the cached plans. The caller must be
+// authorized by the vschema ACL (--vschema_ddl_authorized_users).
+type ResetPlans struct {
+	noTxNeeded
+	noInputs
+}
+
+func (r *ResetPlans) description() PrimitiveDescription {
+	return PrimitiveDescription{
+		OperatorType: "ResetPlans",
+	}
+}
+
+// RouteType implements the Primitive interface
+func (r *ResetPlans) RouteType() string {
+	return "ResetPlans"
+}
+
+// GetKeyspaceName implements the Primitive interface
+func (r *ResetPlans) GetKeyspaceName() string {
+	return ""
+}
+
+// GetTableName implements the Primitive interface
+func (r *ResetPlans) GetTableName() string {
+	return ""
+}
+
+// TryExecute implements the Primitive interface
+func (r *ResetPlans) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	if err := vcursor.ClearPlans(ctx); err != nil {
+		return nil, err
+	}
+	return &sqltypes.Result{}, nil
+}
+
+// TryStreamExecute implements the Primitive interface
+func (r *ResetPlans) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	result, err := r.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	return callback(result)
+}
+
+// GetFields implements the Primitive interface
+func (r *ResetPlans) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "[BUG] GetFields is not reachable")
+}
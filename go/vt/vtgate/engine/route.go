@@ -22,7 +22,6 @@ import (
 	"math/rand/v2"
 	"sort"
 	"strings"
-	"time"
 
 	"vitess.io/vitess/go/mysql/sqlerror"
 	"vitess.io/vitess/go/vt/log"
@@ -134,9 +133,9 @@ func (route *Route) SetTruncateColumnCount(count int) {
 }
 
 // TryExecute performs a non-streaming exec.
-func (route *Route) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
-	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, route.QueryTimeout)
-	defer cancelFunc()
+func (route *Route) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (_ *sqltypes.Result, err error) {
+	ctx, done := addQueryTimeout(ctx, vcursor, route.Keyspace, route.QueryTimeout)
+	defer func() { err = done(err) }()
 	qr, err := route.executeInternal(ctx, vcursor, bindVars, wantfields)
 	if err != nil {
 		return nil, err
@@ -144,19 +143,11 @@ func (route *Route) TryExecute(ctx context.Context, vcursor VCursor, bindVars ma
 	return qr.Truncate(route.TruncateColumnCount), nil
 }
 
-// addQueryTimeout adds a query timeout to the context it receives and returns the modified context along with the cancel function.
-func addQueryTimeout(ctx context.Context, vcursor VCursor, queryTimeout int) (context.Context, context.CancelFunc) {
-	timeout := vcursor.Session().GetQueryTimeout(queryTimeout)
-	if timeout != 0 {
-		return context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
-	}
-	return ctx, func() {}
-}
-
 type cxtKey int
 
 const (
 	IgnoreReserveTxn cxtKey = iota
+	localInfileOpenerKey
 )
 
 func (route *Route) executeInternal(
@@ -252,12 +243,9 @@ func (route *Route) TryStreamExecute(
 	bindVars map[string]*querypb.BindVariable,
 	wantfields bool,
 	callback func(*sqltypes.Result) error,
-) error {
-	if route.QueryTimeout != 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(route.QueryTimeout)*time.Millisecond)
-		defer cancel()
-	}
+) (err error) {
+	ctx, done := addQueryTimeout(ctx, vcursor, route.Keyspace, route.QueryTimeout)
+	defer func() { err = done(err) }()
 	rss, bvs, err := route.findRoute(ctx, vcursor, bindVars)
 	if err != nil {
 		return err
@@ -87,9 +87,9 @@ func (s *Send) GetTableName() string {
 }
 
 // TryExecute implements Primitive interface
-func (s *Send) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
-	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, 0)
-	defer cancelFunc()
+func (s *Send) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (_ *sqltypes.Result, err error) {
+	ctx, done := addQueryTimeout(ctx, vcursor, s.Keyspace, 0)
+	defer func() { err = done(err) }()
 
 	rss, err := s.checkAndReturnShards(ctx, vcursor)
 	if err != nil {
@@ -134,6 +134,9 @@ func (s *Send) checkAndReturnShards(ctx context.Context, vcursor VCursor) ([]*sr
 
 	if s.ReservedConnectionNeeded {
 		vcursor.Session().NeedsReservedConn()
+		// vtgate does not track the state, e.g. the read lock, that the query
+		// leaves on the reserved connection.
+		vcursor.Session().HasUntrackedReservedConnState()
 	}
 	return rss, nil
 }
@@ -154,7 +157,10 @@ func copyBindVars(in map[string]*querypb.BindVariable) map[string]*querypb.BindV
 }
 
 // TryStreamExecute implements Primitive interface
-func (s *Send) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+func (s *Send) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) (err error) {
+	ctx, done := addQueryTimeout(ctx, vcursor, s.Keyspace, 0)
+	defer func() { err = done(err) }()
+
 	rss, err := s.checkAndReturnShards(ctx, vcursor)
 	if err != nil {
 		return err
@@ -318,3 +318,24 @@ func TestSendGetFields(t *testing.T) {
 	require.Nil(t, qr.Rows)
 	require.Equal(t, 4, len(qr.Fields))
 }
+
+func TestSendReservedConnectionNeeded(t *testing.T) {
+	send := &Send{
+		Keyspace: &vindexes.Keyspace{
+			Name:    "ks",
+			Sharded: false,
+		},
+		Query:                    "flush tables with read lock",
+		TargetDestination:        key.DestinationAllShards{},
+		ReservedConnectionNeeded: true,
+	}
+	vc := &loggingVCursor{shards: []string{"0"}, results: []*sqltypes.Result{{}}}
+	_, err := send.TryExecute(context.Background(), vc, map[string]*querypb.BindVariable{}, false)
+	require.NoError(t, err)
+	vc.ExpectLog(t, []string{
+		`ResolveDestinations ks [] Destinations:DestinationAllShards()`,
+		`Needs Reserved Conn`,
+		`Untracked Reserved Conn State`,
+		`ExecuteMultiShard ks.0: flush tables with read lock {} false false`,
+	})
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	sequenceBlockRemaining = stats.NewGaugesWithSingleLabel(
+		"VtgateSequenceBlockRemainingPercent",
+		"Percentage of the values of the current block of a sequence that are left to hand out",
+		"Sequence")
+	sequenceAllocationLatency = stats.NewGaugesWithSingleLabel(
+		"VtgateSequenceAllocationLatencyMicros",
+		"Time it took to reserve the latest block of values of a sequence, in microseconds",
+		"Sequence")
+)
+
+// sequenceFetchBackoff is how long the SequenceCache waits before the first
+// retry of a failed fetch. It doubles for every following retry.
+var sequenceFetchBackoff = 50 * time.Millisecond
+
+// SequenceCache hands out the values of the sequence tables from blocks that
+// it reserves from them, so that most inserts into a table with an
+// auto-increment column don't need a round trip to the sequence's tablet.
+//
+// The values of a block are reserved in the sequence table before they are
+// handed out, so a block stays valid when the primary of the sequence fails
+// over: only the fetch of a new block needs the tablet, and it is retried
+// while the tablet returns a transient error. Values that are left in a block
+// when vtgate restarts, or when an insert needs more values than the block
+// has left, are never used.
+type SequenceCache struct {
+	// defaultBlockSize is used for the tables whose vschema doesn't set a
+	// block size. A block size of 0 or 1 disables the caching.
+	defaultBlockSize int64
+	// retries is the number of times a failed fetch is retried.
+	retries int
+
+	mu     sync.Mutex
+	blocks map[string]*sequenceBlock
+}
+
+// sequenceBlock holds the values [next, end) of a sequence.
+type sequenceBlock struct {
+	mu   sync.Mutex
+	next int64
+	end  int64
+	size int64
+}
+
+// NewSequenceCache creates a SequenceCache.
+func NewSequenceCache(defaultBlockSize int64, retries int) *SequenceCache {
+	return &SequenceCache{
+		defaultBlockSize: defaultBlockSize,
+		retries:          retries,
+		blocks:           make(map[string]*sequenceBlock),
+	}
+}
+
+// Reserve returns the first of count consecutive values of the sequence.
+// fetch reserves n values in the sequence table and returns the first one.
+// blockSize overrides the default block size of the cache if it is set.
+func (sc *SequenceCache) Reserve(ctx context.Context, sequence string, blockSize, count int64, fetch func(ctx context.Context, n int64) (int64, error)) (int64, error) {
+	if sc == nil {
+		return fetch(ctx, count)
+	}
+	if blockSize == 0 {
+		blockSize = sc.defaultBlockSize
+	}
+	if blockSize <= 1 {
+		return sc.fetch(ctx, sequence, count, fetch)
+	}
+
+	block := sc.block(sequence)
+	block.mu.Lock()
+	defer block.mu.Unlock()
+
+	if block.end-block.next < count {
+		n := max(blockSize, count)
+		first, err := sc.fetch(ctx, sequence, n, fetch)
+		if err != nil {
+			return 0, err
+		}
+		block.next, block.end, block.size = first, first+n, n
+	}
+
+	first := block.next
+	block.next += count
+	sequenceBlockRemaining.Set(sequence, (block.end-block.next)*100/block.size)
+	return first, nil
+}
+
+func (sc *SequenceCache) block(sequence string) *sequenceBlock {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	block, ok := sc.blocks[sequence]
+	if !ok {
+		block = &sequenceBlock{}
+		sc.blocks[sequence] = block
+	}
+	return block
+}
+
+// fetch calls fetch, and calls it again while it fails with an error that
+// the tablet returns while it is not serving, for example during a failover.
+func (sc *SequenceCache) fetch(ctx context.Context, sequence string, n int64, fetch func(ctx context.Context, n int64) (int64, error)) (int64, error) {
+	start := time.Now()
+	backoff := sequenceFetchBackoff
+	for retry := 0; ; retry++ {
+		first, err := fetch(ctx, n)
+		if err == nil {
+			sequenceAllocationLatency.Set(sequence, time.Since(start).Microseconds())
+			return first, nil
+		}
+		if retry >= sc.retries || !isSequenceFetchRetryable(err) {
+			return 0, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func isSequenceFetchRetryable(err error) bool {
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_FAILED_PRECONDITION, vtrpcpb.Code_CLUSTER_EVENT:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// fakeSequence reserves the values of a sequence table, failing with the
+// given errors first.
+type fakeSequence struct {
+	next    int64
+	fetches []int64
+	errs    []error
+}
+
+func (fs *fakeSequence) fetch(ctx context.Context, n int64) (int64, error) {
+	fs.fetches = append(fs.fetches, n)
+	if len(fs.errs) > 0 {
+		err := fs.errs[0]
+		fs.errs = fs.errs[1:]
+		return 0, err
+	}
+	first := fs.next
+	fs.next += n
+	return first, nil
+}
+
+func TestSequenceCacheReserve(t *testing.T) {
+	ctx := context.Background()
+	sc := NewSequenceCache(10, 0)
+	seq := &fakeSequence{next: 1}
+
+	first, err := sc.Reserve(ctx, "ks.seq", 0, 3, seq.fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, first)
+	assert.EqualValues(t, 70, sequenceBlockRemaining.Counts()["ks.seq"])
+
+	first, err = sc.Reserve(ctx, "ks.seq", 0, 7, seq.fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, first)
+	assert.EqualValues(t, 0, sequenceBlockRemaining.Counts()["ks.seq"])
+	assert.Equal(t, []int64{10}, seq.fetches)
+
+	// An insert that needs more values than a block reserves all of them at once.
+	first, err = sc.Reserve(ctx, "ks.seq", 0, 15, seq.fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 11, first)
+	assert.Equal(t, []int64{10, 15}, seq.fetches)
+
+	// The values left in a block are skipped if they are not enough.
+	_, err = sc.Reserve(ctx, "ks.seq", 0, 2, seq.fetch)
+	require.NoError(t, err)
+	first, err = sc.Reserve(ctx, "ks.seq", 0, 9, seq.fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 36, first)
+	assert.Equal(t, []int64{10, 15, 10, 10}, seq.fetches)
+
+	// The block size of the table overrides the default one.
+	other := &fakeSequence{next: 100}
+	first, err = sc.Reserve(ctx, "ks.other_seq", 1000, 1, other.fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, first)
+	assert.Equal(t, []int64{1000}, other.fetches)
+	assert.EqualValues(t, 99, sequenceBlockRemaining.Counts()["ks.other_seq"])
+	assert.Contains(t, sequenceAllocationLatency.Counts(), "ks.other_seq")
+}
+
+func TestSequenceCacheDisabled(t *testing.T) {
+	ctx := context.Background()
+	seq := &fakeSequence{next: 1}
+
+	var sc *SequenceCache
+	first, err := sc.Reserve(ctx, "ks.seq", 10, 2, seq.fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, first)
+
+	sc = NewSequenceCache(0, 0)
+	first, err = sc.Reserve(ctx, "ks.seq", 0, 2, seq.fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, first)
+	assert.Equal(t, []int64{2, 2}, seq.fetches)
+}
+
+func TestSequenceCacheRetry(t *testing.T) {
+	defer func(backoff time.Duration) {
+		sequenceFetchBackoff = backoff
+	}(sequenceFetchBackoff)
+	sequenceFetchBackoff = time.Millisecond
+
+	ctx := context.Background()
+	unavailable := vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "primary is not serving")
+
+	// The fetch is retried while the tablet fails over.
+	sc := NewSequenceCache(10, 2)
+	seq := &fakeSequence{next: 1, errs: []error{unavailable, unavailable}}
+	first, err := sc.Reserve(ctx, "ks.seq", 0, 1, seq.fetch)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, first)
+	assert.Equal(t, []int64{10, 10, 10}, seq.fetches)
+
+	// The error is returned when the retries are exhausted.
+	sc = NewSequenceCache(10, 1)
+	seq = &fakeSequence{next: 1, errs: []error{unavailable, unavailable}}
+	_, err = sc.Reserve(ctx, "ks.seq", 0, 1, seq.fetch)
+	require.ErrorContains(t, err, "primary is not serving")
+	assert.Len(t, seq.fetches, 2)
+
+	// Other errors are not retried.
+	sc = NewSequenceCache(10, 2)
+	seq = &fakeSequence{next: 1, errs: []error{vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "bad sequence")}}
+	_, err = sc.Reserve(ctx, "ks.seq", 0, 1, seq.fetch)
+	require.ErrorContains(t, err, "bad sequence")
+	assert.Len(t, seq.fetches, 1)
+}
@@ -38,6 +38,7 @@ import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/schema"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
@@ -445,6 +446,12 @@ func (svss *SysVarSetAware) Execute(ctx context.Context, vcursor VCursor, env *e
 		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetClientFoundRows)
 	case sysvars.SkipQueryPlanCache.Name:
 		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetSkipQueryPlanCache)
+	case sysvars.SnapshotReads.Name:
+		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetSnapshotReads)
+	case sysvars.SkipReadRetry.Name:
+		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetSkipReadRetry)
+	case sysvars.ReadOnlyTxOnReplica.Name:
+		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetReadOnlyTxOnReplica)
 	case sysvars.TxReadOnly.Name,
 		sysvars.TransactionReadOnly.Name:
 		// TODO (4127): This is a dangerous NOP.
@@ -494,12 +501,56 @@ func (svss *SysVarSetAware) Execute(ctx context.Context, vcursor VCursor, env *e
 			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid migration_context: %s", str)
 		}
 		vcursor.Session().SetMigrationContext(str)
+	case sysvars.TenantID.Name:
+		str, err := svss.evalAsString(env, vcursor)
+		if err != nil {
+			return err
+		}
+		if err := vcursor.Session().SetTenantID(str); err != nil {
+			return err
+		}
 	case sysvars.QueryTimeout.Name:
 		queryTimeout, err := svss.evalAsInt64(env, vcursor)
 		if err != nil {
 			return err
 		}
 		vcursor.Session().SetQueryTimeout(queryTimeout)
+	case sysvars.StreamChunkRows.Name:
+		rows, err := svss.evalAsInt64(env, vcursor)
+		if err != nil {
+			return err
+		}
+		if rows < 0 {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid stream_chunk_rows: %d", rows)
+		}
+		vcursor.Session().SetStreamChunkRows(rows)
+	case sysvars.StreamChunkTimeout.Name:
+		timeout, err := svss.evalAsInt64(env, vcursor)
+		if err != nil {
+			return err
+		}
+		if timeout < 0 {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid stream_chunk_timeout: %d", timeout)
+		}
+		vcursor.Session().SetStreamChunkTimeout(timeout)
+	case sysvars.MaxReplicaLag.Name:
+		lag, err := svss.evalAsInt64(env, vcursor)
+		if err != nil {
+			return err
+		}
+		if lag < 0 {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid max_replica_lag: %d", lag)
+		}
+		vcursor.Session().SetMaxReplicaLag(lag)
+	case sysvars.TabletTags.Name:
+		str, err := svss.evalAsString(env, vcursor)
+		if err != nil {
+			return err
+		}
+		if _, err := topoproto.ParseTags(str); err != nil {
+			return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "invalid tablet_tags: %s", str)
+		}
+		vcursor.Session().SetTabletTags(str)
 	case sysvars.SessionEnableSystemSettings.Name:
 		err = svss.setBoolSysVar(ctx, env, vcursor.Session().SetSessionEnableSystemSettings)
 	case sysvars.Charset.Name, sysvars.Names.Name:
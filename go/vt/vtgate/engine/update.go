@@ -52,9 +52,9 @@ type Update struct {
 }
 
 // TryExecute performs a non-streaming exec.
-func (upd *Update) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
-	ctx, cancelFunc := addQueryTimeout(ctx, vcursor, upd.QueryTimeout)
-	defer cancelFunc()
+func (upd *Update) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (_ *sqltypes.Result, err error) {
+	ctx, done := addQueryTimeout(ctx, vcursor, upd.Keyspace, upd.QueryTimeout)
+	defer func() { err = done(err) }()
 
 	rss, _, err := upd.findRoute(ctx, vcursor, bindVars)
 	if err != nil {
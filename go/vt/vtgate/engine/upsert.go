@@ -84,6 +84,11 @@ func (u *Upsert) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[s
 			return nil, err
 		}
 		result.RowsAffected += qr.RowsAffected
+		// The rows are upserted in the order of the statement, so the
+		// first insert id is the one of its first row, like in MySQL.
+		if result.InsertID == 0 {
+			result.InsertID = qr.InsertID
+		}
 	}
 	return result, nil
 }
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+var _ Primitive = (*VitessMetadataTable)(nil)
+
+// VitessMetadataTable is a primitive that reads a table of the vitess_metadata
+// virtual schema, e.g. the shards or tablets known to this vtgate.
+type VitessMetadataTable struct {
+	// VitessMetadataTable does not take inputs
+	noInputs
+
+	// VitessMetadataTable does not need to work inside a tx
+	noTxNeeded
+
+	// Table is the name of the vitess_metadata table.
+	Table string
+	// Fields is the field info for the result.
+	Fields []*querypb.Field
+	// Cols contains the offsets of the result columns in the table.
+	Cols []int
+}
+
+// VitessMetadataField returns the field of a column of a vitess_metadata table.
+func VitessMetadataField(column vindexes.Column) *querypb.Field {
+	field := &querypb.Field{
+		Name:    column.Name.String(),
+		Type:    column.Type,
+		Charset: collations.CollationBinaryID,
+		Flags:   uint32(querypb.MySqlFlag_NOT_NULL_FLAG),
+	}
+	if sqltypes.IsText(column.Type) {
+		field.Charset = uint32(collations.SystemCollation.Collation)
+	} else {
+		field.Flags |= uint32(querypb.MySqlFlag_NUM_FLAG)
+	}
+	return field
+}
+
+// RouteType returns a description of the query routing type used by the primitive
+func (vm *VitessMetadataTable) RouteType() string {
+	return "VitessMetadataTable"
+}
+
+// GetKeyspaceName specifies the Keyspace that this primitive routes to.
+func (vm *VitessMetadataTable) GetKeyspaceName() string {
+	return ""
+}
+
+// GetTableName specifies the table that this primitive routes to.
+func (vm *VitessMetadataTable) GetTableName() string {
+	return vm.Table
+}
+
+// TryExecute performs a non-streaming exec.
+func (vm *VitessMetadataTable) TryExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool) (*sqltypes.Result, error) {
+	rows, err := vcursor.VitessMetadataRows(ctx, vm.Table)
+	if err != nil {
+		return nil, err
+	}
+	result := &sqltypes.Result{Fields: vm.Fields}
+	for _, row := range rows {
+		out := make([]sqltypes.Value, 0, len(vm.Cols))
+		for _, col := range vm.Cols {
+			if col >= len(row) {
+				return nil, vterrors.NewErrorf(vtrpcpb.Code_OUT_OF_RANGE, vterrors.BadFieldError, "column %v out of range", col)
+			}
+			out = append(out, row[col])
+		}
+		result.Rows = append(result.Rows, out)
+	}
+	return result, nil
+}
+
+// TryStreamExecute performs a streaming exec.
+func (vm *VitessMetadataTable) TryStreamExecute(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable, wantfields bool, callback func(*sqltypes.Result) error) error {
+	r, err := vm.TryExecute(ctx, vcursor, bindVars, wantfields)
+	if err != nil {
+		return err
+	}
+	if err := callback(r.Metadata()); err != nil {
+		return err
+	}
+	return callback(&sqltypes.Result{Rows: r.Rows})
+}
+
+// GetFields fetches the field info.
+func (vm *VitessMetadataTable) GetFields(ctx context.Context, vcursor VCursor, bindVars map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	return &sqltypes.Result{Fields: vm.Fields}, nil
+}
+
+func (vm *VitessMetadataTable) description() PrimitiveDescription {
+	fields := map[string]string{}
+	for _, field := range vm.Fields {
+		fields[field.Name] = field.Type.String()
+	}
+
+	return PrimitiveDescription{
+		OperatorType: "VitessMetadataTable",
+		Other: map[string]any{
+			"Table":   vm.Table,
+			"Fields":  fields,
+			"Columns": vm.Cols,
+		},
+	}
+}
@@ -1390,3 +1390,76 @@ func TestCompareSorter(t *testing.T) {
 	}
 
 }
+
+func TestCompareCollatedText(t *testing.T) {
+	collationEnv := collations.MySQL8()
+	rows := func(values ...string) []sqltypes.Row {
+		var out []sqltypes.Row
+		for _, v := range values {
+			// the second column stands for the weight_string of the first one
+			out = append(out, sqltypes.Row{sqltypes.NewVarChar(v), sqltypes.NewVarBinary(strings.ToLower(v))})
+		}
+		return out
+	}
+	values := func(rows []sqltypes.Row) []string {
+		var out []string
+		for _, row := range rows {
+			out = append(out, row[0].ToString())
+		}
+		return out
+	}
+
+	tcases := []struct {
+		name      string
+		collation collations.ID
+		wsCol     int
+		desc      bool
+		want      []string
+	}{{
+		name:      "binary",
+		collation: collations.CollationBinaryID,
+		wsCol:     -1,
+		want:      []string{"B", "a", "c", "äb"},
+	}, {
+		name:      "accent and case insensitive",
+		collation: collationEnv.LookupByName("utf8mb4_0900_ai_ci"),
+		wsCol:     -1,
+		want:      []string{"a", "äb", "B", "c"},
+	}, {
+		name:      "accent and case insensitive descending",
+		collation: collationEnv.LookupByName("utf8mb4_0900_ai_ci"),
+		wsCol:     -1,
+		desc:      true,
+		want:      []string{"c", "B", "äb", "a"},
+	}, {
+		name:      "case sensitive",
+		collation: collationEnv.LookupByName("utf8mb4_0900_as_cs"),
+		wsCol:     -1,
+		want:      []string{"a", "äb", "B", "c"},
+	}, {
+		name:      "unsupported collation uses the weight string",
+		collation: collations.ID(1000),
+		wsCol:     1,
+		want:      []string{"a", "B", "c", "äb"},
+	}}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := &sqltypes.Result{
+				Fields: []*querypb.Field{
+					{Name: "col", Type: sqltypes.VarChar, Charset: uint32(tc.collation)},
+					{Name: "weight_string(col)", Type: sqltypes.VarBinary, Charset: collations.CollationBinaryID},
+				},
+				Rows: rows("c", "äb", "B", "a"),
+			}
+			cmp := Comparison{{
+				Col:             0,
+				WeightStringCol: tc.wsCol,
+				Desc:            tc.desc,
+				Type:            NewType(sqltypes.VarChar, tc.collation),
+				CollationEnv:    collationEnv,
+			}}
+			require.NoError(t, cmp.SortResult(result))
+			assert.Equal(t, tc.want, values(result.Rows))
+		})
+	}
+}
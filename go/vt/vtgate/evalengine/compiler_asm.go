@@ -2564,6 +2564,22 @@ func (asm *assembler) Fn_LEFT(col collations.TypedCollation) {
 	}, "FN LEFT VARCHAR(SP-2) INT64(SP-1)")
 }
 
+func (asm *assembler) Fn_SUBSTRING_INDEX(col collations.TypedCollation) {
+	asm.adjustStack(-2)
+
+	asm.emit(func(env *ExpressionEnv) int {
+		str := env.vm.stack[env.vm.sp-3].(*evalBytes)
+		delim := env.vm.stack[env.vm.sp-2].(*evalBytes)
+		count := env.vm.stack[env.vm.sp-1].(*evalInt64)
+
+		str.tt = int16(sqltypes.VarChar)
+		str.col = col
+		str.bytes = substringIndex(str.bytes, delim.bytes, count.i)
+		env.vm.sp -= 2
+		return 1
+	}, "FN SUBSTRING_INDEX VARCHAR(SP-3) VARCHAR(SP-2) INT64(SP-1)")
+}
+
 func (asm *assembler) Fn_RIGHT(col collations.TypedCollation) {
 	asm.adjustStack(-1)
 
@@ -3900,6 +3916,38 @@ func (asm *assembler) Fn_TO_DAYS() {
 	}, "FN TO_DAYS DATE(SP-1)")
 }
 
+func (asm *assembler) Fn_DATEDIFF() {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		if env.vm.stack[env.vm.sp-2] == nil || env.vm.stack[env.vm.sp-1] == nil {
+			env.vm.stack[env.vm.sp-2] = nil
+			env.vm.sp--
+			return 1
+		}
+		d1 := env.vm.stack[env.vm.sp-2].(*evalTemporal)
+		d2 := env.vm.stack[env.vm.sp-1].(*evalTemporal)
+		env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalInt64(dateDiff(d1.dt.Date, d2.dt.Date))
+		env.vm.sp--
+		return 1
+	}, "FN DATEDIFF DATE(SP-2), DATE(SP-1)")
+}
+
+func (asm *assembler) Fn_TIMESTAMPDIFF(unit datetime.IntervalType) {
+	asm.adjustStack(-1)
+	asm.emit(func(env *ExpressionEnv) int {
+		if env.vm.stack[env.vm.sp-2] == nil || env.vm.stack[env.vm.sp-1] == nil {
+			env.vm.stack[env.vm.sp-2] = nil
+			env.vm.sp--
+			return 1
+		}
+		dt1 := env.vm.stack[env.vm.sp-2].(*evalTemporal)
+		dt2 := env.vm.stack[env.vm.sp-1].(*evalTemporal)
+		env.vm.stack[env.vm.sp-2] = env.vm.arena.newEvalInt64(timestampDiff(dt1.dt, dt2.dt, unit))
+		env.vm.sp--
+		return 1
+	}, "FN TIMESTAMPDIFF DATETIME(SP-2), DATETIME(SP-1)")
+}
+
 func (asm *assembler) Fn_FROM_DAYS() {
 	asm.emit(func(env *ExpressionEnv) int {
 		arg := env.vm.stack[env.vm.sp-1].(*evalInt64)
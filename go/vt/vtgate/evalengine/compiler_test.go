@@ -631,6 +631,42 @@ func TestCompilerSingle(t *testing.T) {
 			expression: `REPLACE('www.mysql.com', '', 'Ww')`,
 			result:     `VARCHAR("www.mysql.com")`,
 		},
+		{
+			expression: `SUBSTRING_INDEX('www.mysql.com', '.', 2)`,
+			result:     `VARCHAR("www.mysql")`,
+		},
+		{
+			expression: `SUBSTRING_INDEX('www.mysql.com', '.', -2)`,
+			result:     `VARCHAR("mysql.com")`,
+		},
+		{
+			expression: `SUBSTRING_INDEX('www.mysql.com', '.', -5)`,
+			result:     `VARCHAR("www.mysql.com")`,
+		},
+		{
+			expression: `DATEDIFF('2007-12-31 23:59:59', '2007-12-30')`,
+			result:     `INT64(1)`,
+		},
+		{
+			expression: `DATEDIFF('2010-11-30 23:59:59', '2010-12-31')`,
+			result:     `INT64(-31)`,
+		},
+		{
+			expression: `TIMESTAMPDIFF(MONTH, '2003-02-01', '2003-05-01')`,
+			result:     `INT64(3)`,
+		},
+		{
+			expression: `TIMESTAMPDIFF(MONTH, '2023-01-31', '2023-02-28')`,
+			result:     `INT64(0)`,
+		},
+		{
+			expression: `TIMESTAMPDIFF(YEAR, '2002-05-01', '2001-01-01')`,
+			result:     `INT64(-1)`,
+		},
+		{
+			expression: `TIMESTAMPDIFF(MINUTE, '2003-02-01', '2003-05-01 12:05:55')`,
+			result:     `INT64(128885)`,
+		},
 	}
 
 	tz, _ := time.LoadLocation("Europe/Madrid")
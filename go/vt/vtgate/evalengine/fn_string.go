@@ -99,6 +99,11 @@ type (
 		collate collations.ID
 	}
 
+	builtinSubstringIndex struct {
+		CallExpr
+		collate collations.ID
+	}
+
 	builtinTrim struct {
 		CallExpr
 		collate collations.ID
@@ -155,6 +160,7 @@ var _ IR = (*builtinWeightString)(nil)
 var _ IR = (*builtinLeftRight)(nil)
 var _ IR = (*builtinPad)(nil)
 var _ IR = (*builtinStrcmp)(nil)
+var _ IR = (*builtinSubstringIndex)(nil)
 var _ IR = (*builtinTrim)(nil)
 var _ IR = (*builtinSubstring)(nil)
 var _ IR = (*builtinLocate)(nil)
@@ -988,6 +994,109 @@ func (call *builtinPad) compile(c *compiler) (ctype, error) {
 	return ctype{Type: sqltypes.VarChar, Flag: flagNullable, Col: col}, nil
 }
 
+// substringIndex returns the part of str before the count-th occurrence of
+// delim, counting from the left if count is positive and from the right if
+// it's negative. Like MySQL, the delimiter is matched byte by byte.
+func substringIndex(str, delim []byte, count int64) []byte {
+	if count == 0 || len(delim) == 0 {
+		return nil
+	}
+	if count > 0 {
+		pos := 0
+		for ; count > 0; count-- {
+			idx := bytes.Index(str[pos:], delim)
+			if idx < 0 {
+				return str
+			}
+			pos += idx + len(delim)
+		}
+		return str[:pos-len(delim)]
+	}
+	end := len(str)
+	for ; count < 0; count++ {
+		idx := bytes.LastIndex(str[:end], delim)
+		if idx < 0 {
+			return str
+		}
+		end = idx
+	}
+	return str[end+len(delim):]
+}
+
+func (call *builtinSubstringIndex) eval(env *ExpressionEnv) (eval, error) {
+	str, d, n, err := call.arg3(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if str == nil || d == nil || n == nil {
+		return nil, nil
+	}
+
+	text, ok := str.(*evalBytes)
+	if !ok {
+		text, err = evalToVarchar(str, call.collate, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cs := colldata.Lookup(text.col.Collation).Charset()
+	delim, ok := d.(*evalBytes)
+	if !ok || colldata.Lookup(delim.col.Collation).Charset() != cs {
+		delim, err = evalToVarchar(d, text.col.Collation, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	count := evalToInt64(n).i
+	return newEvalText(substringIndex(text.bytes, delim.bytes, count), text.col), nil
+}
+
+func (call *builtinSubstringIndex) compile(c *compiler) (ctype, error) {
+	str, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	delim, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	count, err := call.Arguments[2].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck3(str, delim, count)
+
+	col := typedCoercionCollation(sqltypes.VarChar, c.collation)
+	switch {
+	case str.isTextual():
+		col = str.Col
+	default:
+		c.asm.Convert_xce(3, sqltypes.VarChar, col.Collation)
+	}
+
+	switch {
+	case delim.isTextual():
+		fromCharset := colldata.Lookup(delim.Col.Collation).Charset()
+		toCharset := colldata.Lookup(col.Collation).Charset()
+		if fromCharset != toCharset && !toCharset.IsSuperset(fromCharset) {
+			c.asm.Convert_xce(2, sqltypes.VarChar, col.Collation)
+		}
+	default:
+		c.asm.Convert_xce(2, sqltypes.VarChar, col.Collation)
+	}
+	_ = c.compileToInt64(count, 1)
+
+	c.asm.Fn_SUBSTRING_INDEX(col)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.VarChar, Flag: flagNullable, Col: col}, nil
+}
+
 func strcmpCollate(left, right []byte, col collations.ID) int64 {
 	cmp := colldata.Lookup(col).Collate(left, right, false)
 	switch {
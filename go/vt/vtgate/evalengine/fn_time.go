@@ -165,6 +165,15 @@ type (
 		unit    datetime.IntervalType
 		collate collations.ID
 	}
+
+	builtinDateDiff struct {
+		CallExpr
+	}
+
+	builtinTimestampDiff struct {
+		CallExpr
+		unit datetime.IntervalType
+	}
 )
 
 var _ IR = (*builtinNow)(nil)
@@ -197,6 +206,8 @@ var _ IR = (*builtinWeekDay)(nil)
 var _ IR = (*builtinWeekOfYear)(nil)
 var _ IR = (*builtinYear)(nil)
 var _ IR = (*builtinYearWeek)(nil)
+var _ IR = (*builtinDateDiff)(nil)
+var _ IR = (*builtinTimestampDiff)(nil)
 
 func (call *builtinNow) eval(env *ExpressionEnv) (eval, error) {
 	now := env.time(call.utc)
@@ -1875,3 +1886,151 @@ func (call *builtinDateMath) compile(c *compiler) (ctype, error) {
 	}
 	return ret, nil
 }
+
+func dateDiff(d1, d2 datetime.Date) int64 {
+	return int64(datetime.MysqlDayNumber(d1.Year(), d1.Month(), d1.Day()) - datetime.MysqlDayNumber(d2.Year(), d2.Month(), d2.Day()))
+}
+
+func (b *builtinDateDiff) eval(env *ExpressionEnv) (eval, error) {
+	date1, date2, err := b.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if date1 == nil || date2 == nil {
+		return nil, nil
+	}
+	d1 := evalToDate(date1, env.now, false)
+	if d1 == nil {
+		return nil, nil
+	}
+	d2 := evalToDate(date2, env.now, false)
+	if d2 == nil {
+		return nil, nil
+	}
+	return newEvalInt64(dateDiff(d1.dt.Date, d2.dt.Date)), nil
+}
+
+func (call *builtinDateDiff) compile(c *compiler) (ctype, error) {
+	date1, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	date2, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(date1, date2)
+
+	switch date1.Type {
+	case sqltypes.Date, sqltypes.Datetime:
+	default:
+		c.asm.Convert_xD(2, false)
+	}
+	switch date2.Type {
+	case sqltypes.Date, sqltypes.Datetime:
+	default:
+		c.asm.Convert_xD(1, false)
+	}
+
+	c.asm.Fn_DATEDIFF()
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: date1.Flag | date2.Flag | flagNullable}, nil
+}
+
+// timestampDiff returns dt2 - dt1 in the given unit, truncated towards zero.
+// Like MySQL, months, quarters and years are calendar based: a month has only
+// passed once the same day and time of the next month has been reached.
+func timestampDiff(dt1, dt2 datetime.DateTime, unit datetime.IntervalType) int64 {
+	beg, end := dt1, dt2
+	neg := dt1.Compare(dt2) > 0
+	if neg {
+		beg, end = dt2, dt1
+	}
+
+	var diff int64
+	switch unit {
+	case datetime.IntervalMonth, datetime.IntervalQuarter, datetime.IntervalYear:
+		months := int64(end.Date.Year()-beg.Date.Year())*12 + int64(end.Date.Month()-beg.Date.Month())
+		if end.Date.Day() < beg.Date.Day() || (end.Date.Day() == beg.Date.Day() && end.Time.Compare(beg.Time) < 0) {
+			months--
+		}
+		switch unit {
+		case datetime.IntervalQuarter:
+			diff = months / 3
+		case datetime.IntervalYear:
+			diff = months / 12
+		default:
+			diff = months
+		}
+	default:
+		micros := dateDiff(end.Date, beg.Date)*int64(24*time.Hour/time.Microsecond) +
+			(end.Time.ToDuration() - beg.Time.ToDuration()).Microseconds()
+		switch unit {
+		case datetime.IntervalSecond:
+			diff = micros / int64(time.Second/time.Microsecond)
+		case datetime.IntervalMinute:
+			diff = micros / int64(time.Minute/time.Microsecond)
+		case datetime.IntervalHour:
+			diff = micros / int64(time.Hour/time.Microsecond)
+		case datetime.IntervalDay:
+			diff = micros / int64(24*time.Hour/time.Microsecond)
+		case datetime.IntervalWeek:
+			diff = micros / int64(7*24*time.Hour/time.Microsecond)
+		default:
+			diff = micros
+		}
+	}
+
+	if neg {
+		return -diff
+	}
+	return diff
+}
+
+func (call *builtinTimestampDiff) eval(env *ExpressionEnv) (eval, error) {
+	date1, date2, err := call.arg2(env)
+	if err != nil {
+		return nil, err
+	}
+	if date1 == nil || date2 == nil {
+		return nil, nil
+	}
+	dt1 := evalToDateTime(date1, -1, env.now, false)
+	if dt1 == nil {
+		return nil, nil
+	}
+	dt2 := evalToDateTime(date2, -1, env.now, false)
+	if dt2 == nil {
+		return nil, nil
+	}
+	return newEvalInt64(timestampDiff(dt1.dt, dt2.dt, call.unit)), nil
+}
+
+func (call *builtinTimestampDiff) compile(c *compiler) (ctype, error) {
+	date1, err := call.Arguments[0].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+	date2, err := call.Arguments[1].compile(c)
+	if err != nil {
+		return ctype{}, err
+	}
+
+	skip := c.compileNullCheck2(date1, date2)
+
+	switch date1.Type {
+	case sqltypes.Date, sqltypes.Datetime:
+	default:
+		c.asm.Convert_xDT(2, -1, false)
+	}
+	switch date2.Type {
+	case sqltypes.Date, sqltypes.Datetime:
+	default:
+		c.asm.Convert_xDT(1, -1, false)
+	}
+
+	c.asm.Fn_TIMESTAMPDIFF(call.unit)
+	c.asm.jumpDestination(skip)
+	return ctype{Type: sqltypes.Int64, Col: collationNumeric, Flag: date1.Flag | date2.Flag | flagNullable}, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evalengine
+
+import (
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+// Function is a scalar function implemented in Go, that mirrors a function
+// of the MySQL servers, like a loadable function. Once registered, the
+// queries that call it can be planned and evaluated at vtgate.
+type Function struct {
+	// Name is the name of the function in queries, case insensitive.
+	// Built-in functions take precedence over registered functions.
+	Name string
+	// Type is the type of the values returned by the function.
+	Type sqltypes.Type
+	// MinArgs and MaxArgs bound the number of arguments of the function. A
+	// negative MaxArgs allows any number of arguments above MinArgs.
+	MinArgs, MaxArgs int
+	// Deterministic is set if the function always returns the same value for
+	// the same arguments, which allows calls with constant arguments to be
+	// evaluated once, when the query is planned.
+	Deterministic bool
+	// Eval returns the value of the function for its arguments, which can
+	// be NULL. The value is cast to Type if it has a different type.
+	Eval func(args []sqltypes.Value) (sqltypes.Value, error)
+}
+
+var udfs = struct {
+	mu sync.RWMutex
+	m  map[string]*Function
+}{m: make(map[string]*Function)}
+
+// RegisterFunction registers a scalar function. It is meant to be called
+// when the process starts, before any query is planned.
+func RegisterFunction(fn Function) error {
+	name := strings.ToLower(fn.Name)
+	if name == "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the function has no name")
+	}
+	if fn.Eval == nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "function %s has no implementation", name)
+	}
+	if fn.MinArgs < 0 || (fn.MaxArgs >= 0 && fn.MaxArgs < fn.MinArgs) {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "function %s has an invalid number of arguments: %d to %d", name, fn.MinArgs, fn.MaxArgs)
+	}
+
+	udfs.mu.Lock()
+	defer udfs.mu.Unlock()
+	if _, ok := udfs.m[name]; ok {
+		return vterrors.Errorf(vtrpcpb.Code_ALREADY_EXISTS, "function %s is already registered", name)
+	}
+	fn.Name = name
+	udfs.m[name] = &fn
+	return nil
+}
+
+// UnregisterFunction removes a function registered with RegisterFunction.
+func UnregisterFunction(name string) {
+	udfs.mu.Lock()
+	defer udfs.mu.Unlock()
+	delete(udfs.m, strings.ToLower(name))
+}
+
+func lookupFunction(name string) *Function {
+	udfs.mu.RLock()
+	defer udfs.mu.RUnlock()
+	return udfs.m[name]
+}
+
+type builtinUDF struct {
+	CallExpr
+	fn      *Function
+	collate collations.ID
+}
+
+var _ IR = (*builtinUDF)(nil)
+
+func (call *builtinUDF) call(args []eval, sqlmode SQLMode) (eval, error) {
+	values := make([]sqltypes.Value, len(args))
+	for i, arg := range args {
+		values[i] = evalToSQLValue(arg)
+	}
+	v, err := call.fn.Eval(values)
+	if err != nil {
+		return nil, err
+	}
+	if v.IsNull() {
+		return nil, nil
+	}
+	return valueToEvalCast(v, call.fn.Type, call.collate, sqlmode)
+}
+
+func (call *builtinUDF) eval(env *ExpressionEnv) (eval, error) {
+	args, err := call.args(env)
+	if err != nil {
+		return nil, err
+	}
+	return call.call(args, env.sqlmode)
+}
+
+func (call *builtinUDF) compile(c *compiler) (ctype, error) {
+	for _, arg := range call.Arguments {
+		if _, err := arg.compile(c); err != nil {
+			return ctype{}, err
+		}
+	}
+
+	args := len(call.Arguments)
+	c.asm.adjustStack(1 - args)
+	c.asm.emit(func(env *ExpressionEnv) int {
+		res, err := call.call(env.vm.stack[env.vm.sp-args:env.vm.sp], env.sqlmode)
+		env.vm.stack[env.vm.sp-args] = res
+		env.vm.sp -= args - 1
+		env.vm.err = err
+		return 1
+	}, "FN %s (SP-%d) ... (SP-1)", call.fn.Name, args)
+
+	return ctype{Type: call.fn.Type, Flag: flagNullable, Col: typedCoercionCollation(call.fn.Type, call.collate)}, nil
+}
+
+func (call *builtinUDF) constant() bool {
+	return call.fn.Deterministic && call.Arguments.constant()
+}
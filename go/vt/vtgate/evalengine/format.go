@@ -278,6 +278,16 @@ func (c *builtinWeightString) format(buf *sqlparser.TrackedBuffer) {
 	buf.WriteByte(')')
 }
 
+func (c *builtinTimestampDiff) format(buf *sqlparser.TrackedBuffer) {
+	buf.WriteLiteral("timestampdiff(")
+	buf.WriteLiteral(c.unit.ToString())
+	for _, expr := range c.Arguments {
+		buf.WriteString(", ")
+		formatExpr(buf, c, expr, true)
+	}
+	buf.WriteByte(')')
+}
+
 func (n *NegateExpr) format(buf *sqlparser.TrackedBuffer) {
 	buf.WriteByte('-')
 	formatExpr(buf, n, n.Inner, true)
@@ -84,6 +84,7 @@ var Cases = []TestCase{
 	{Run: FnSubstr},
 	{Run: FnLocate},
 	{Run: FnReplace},
+	{Run: FnSubstringIndex},
 	{Run: FnConcat},
 	{Run: FnConcatWs},
 	{Run: FnChar},
@@ -141,6 +142,8 @@ var Cases = []TestCase{
 	{Run: FnLastDay},
 	{Run: FnToDays},
 	{Run: FnFromDays},
+	{Run: FnDateDiff},
+	{Run: FnTimestampDiff},
 	{Run: FnTimeToSec},
 	{Run: FnQuarter},
 	{Run: FnSecond},
@@ -1605,6 +1608,34 @@ func FnReplace(yield Query) {
 	}
 }
 
+func FnSubstringIndex(yield Query) {
+	cases := []string{
+		`SUBSTRING_INDEX('www.mysql.com', '.', 2)`,
+		`SUBSTRING_INDEX('www.mysql.com', '.', -2)`,
+		`SUBSTRING_INDEX('www.mysql.com', '.', 0)`,
+		`SUBSTRING_INDEX('www.mysql.com', '.', 10)`,
+		`SUBSTRING_INDEX('www.mysql.com', '', 1)`,
+		`SUBSTRING_INDEX('www.mysql.com', 'W', 1)`,
+		`SUBSTRING_INDEX('a::b::c', '::', 2)`,
+		`SUBSTRING_INDEX('a::b::c', '::', -2)`,
+		`SUBSTRING_INDEX('fooÿbarÿbaz', _latin1 0xFF, 2)`,
+		`SUBSTRING_INDEX(12345.678, 5, 1)`,
+	}
+
+	for _, q := range cases {
+		yield(q, nil)
+	}
+
+	counts := []string{"-2", "-1", "0", "1", "2", "1.9", "'1.9'", "NULL"}
+	for _, str := range inputStrings {
+		for _, delim := range inputStrings {
+			for _, cnt := range counts {
+				yield(fmt.Sprintf("SUBSTRING_INDEX(%s, %s, %s)", str, delim, cnt), nil)
+			}
+		}
+	}
+}
+
 func FnConcat(yield Query) {
 	for _, str := range inputStrings {
 		yield(fmt.Sprintf("CONCAT(%s)", str), nil)
@@ -1951,6 +1982,44 @@ func FnToDays(yield Query) {
 	}
 }
 
+var diffDates = []string{
+	`NULL`,
+	`0`,
+	`'0000-00-00'`,
+	`DATE'2023-09-03'`,
+	`DATE'2024-02-29'`,
+	`TIMESTAMP'2023-09-03 07:00:00'`,
+	`TIMESTAMP'2023-10-03 06:59:59.999999'`,
+	`TIMESTAMP'2024-09-03 07:00:00.5'`,
+	`TIME'10:04:58'`,
+	`950501`,
+	`20231231235959`,
+	`'2007-12-31 23:59:59'`,
+	`'2007-12-30'`,
+	`'2010-11-30 23:59:59'`,
+	`'1999-01-31'`,
+	`'foobar'`,
+}
+
+func FnDateDiff(yield Query) {
+	for _, d1 := range diffDates {
+		for _, d2 := range diffDates {
+			yield(fmt.Sprintf("DATEDIFF(%s, %s)", d1, d2), nil)
+		}
+	}
+}
+
+func FnTimestampDiff(yield Query) {
+	units := []string{"MICROSECOND", "SECOND", "MINUTE", "HOUR", "DAY", "WEEK", "MONTH", "QUARTER", "YEAR"}
+	for _, unit := range units {
+		for _, d1 := range diffDates {
+			for _, d2 := range diffDates {
+				yield(fmt.Sprintf("TIMESTAMPDIFF(%s, %s, %s)", unit, d1, d2), nil)
+			}
+		}
+	}
+}
+
 func FnFromDays(yield Query) {
 	for _, d := range inputConversions {
 		yield(fmt.Sprintf("FROM_DAYS(%s)", d), nil)
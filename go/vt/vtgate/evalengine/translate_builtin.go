@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
@@ -442,6 +443,11 @@ func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
 			return nil, argError(method)
 		}
 		return &builtinLastDay{CallExpr: call}, nil
+	case "datediff":
+		if len(args) != 2 {
+			return nil, argError(method)
+		}
+		return &builtinDateDiff{CallExpr: call}, nil
 	case "to_days":
 		if len(args) != 1 {
 			return nil, argError(method)
@@ -617,6 +623,11 @@ func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
 			return nil, argError(method)
 		}
 		return &builtinStrcmp{CallExpr: call, collate: ast.cfg.Collation}, nil
+	case "substring_index":
+		if len(args) != 3 {
+			return nil, argError(method)
+		}
+		return &builtinSubstringIndex{CallExpr: call, collate: ast.cfg.Collation}, nil
 	case "instr":
 		if len(args) != 2 {
 			return nil, argError(method)
@@ -629,6 +640,16 @@ func (ast *astCompiler) translateFuncExpr(fn *sqlparser.FuncExpr) (IR, error) {
 		}
 		return &builtinReplace{CallExpr: call, collate: ast.cfg.Collation}, nil
 	default:
+		if udf := lookupFunction(method); udf != nil && fn.Qualifier.IsEmpty() {
+			if len(args) < udf.MinArgs || (udf.MaxArgs >= 0 && len(args) > udf.MaxArgs) {
+				return nil, argError(method)
+			}
+			collate := ast.cfg.Collation
+			if sqltypes.IsBinary(udf.Type) {
+				collate = collations.CollationBinaryID
+			}
+			return &builtinUDF{CallExpr: call, fn: udf, collate: collate}, nil
+		}
 		return nil, translateExprNotSupported(fn)
 	}
 }
@@ -869,6 +890,25 @@ func (ast *astCompiler) translateCallable(call sqlparser.Callable) (IR, error) {
 			collate:  ast.cfg.Collation,
 		}, nil
 
+	case *sqlparser.TimestampDiffExpr:
+		var err error
+		args := make([]IR, 2)
+
+		args[0], err = ast.translateExpr(call.Expr1)
+		if err != nil {
+			return nil, err
+		}
+		args[1], err = ast.translateExpr(call.Expr2)
+		if err != nil {
+			return nil, err
+		}
+
+		cexpr := CallExpr{Arguments: args, Method: "TIMESTAMPDIFF"}
+		return &builtinTimestampDiff{
+			CallExpr: cexpr,
+			unit:     call.Unit,
+		}, nil
+
 	case *sqlparser.RegexpLikeExpr:
 		input, err := ast.translateExpr(call.Expr)
 		if err != nil {
@@ -18,6 +18,7 @@ package evalengine
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -452,3 +453,100 @@ func TestCardinalityWithBindVariables(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterFunction(t *testing.T) {
+	mask := Function{
+		Name:          "VT_TEST_MASK",
+		Type:          sqltypes.VarChar,
+		MinArgs:       1,
+		MaxArgs:       2,
+		Deterministic: true,
+		Eval: func(args []sqltypes.Value) (sqltypes.Value, error) {
+			if args[0].IsNull() {
+				return sqltypes.NULL, nil
+			}
+			keep := 0
+			if len(args) > 1 {
+				k, err := args[1].ToInt64()
+				if err != nil {
+					return sqltypes.Value{}, err
+				}
+				keep = int(k)
+			}
+			s := args[0].ToString()
+			return sqltypes.NewVarChar(strings.Repeat("*", len(s)-keep) + s[len(s)-keep:]), nil
+		},
+	}
+	var calls int64
+	counter := Function{
+		Name: "vt_test_counter",
+		Type: sqltypes.Int64,
+		Eval: func(args []sqltypes.Value) (sqltypes.Value, error) {
+			calls++
+			// The value is cast to the type of the function.
+			return sqltypes.NewVarChar(strconv.FormatInt(calls, 10)), nil
+		},
+	}
+	require.NoError(t, RegisterFunction(mask))
+	defer UnregisterFunction("vt_test_mask")
+	require.NoError(t, RegisterFunction(counter))
+	defer UnregisterFunction("vt_test_counter")
+
+	assert.ErrorContains(t, RegisterFunction(mask), "function vt_test_mask is already registered")
+	assert.ErrorContains(t, RegisterFunction(Function{Name: "vt_test_none"}), "function vt_test_none has no implementation")
+	assert.ErrorContains(t, RegisterFunction(Function{Name: "vt_test_args", MinArgs: 2, MaxArgs: 1, Eval: counter.Eval}), "invalid number of arguments: 2 to 1")
+
+	venv := vtenv.NewTestEnv()
+	translate := func(expression string, noConstantFolding bool) (Expr, error) {
+		stmt, err := sqlparser.NewTestParser().Parse("select " + expression)
+		require.NoError(t, err)
+		astExpr := stmt.(*sqlparser.Select).SelectExprs[0].(*sqlparser.AliasedExpr).Expr
+		return Translate(astExpr, &Config{
+			Collation:         venv.CollationEnv().DefaultConnectionCharset(),
+			Environment:       venv,
+			NoConstantFolding: noConstantFolding,
+		})
+	}
+	env := NewExpressionEnv(context.Background(), map[string]*querypb.BindVariable{
+		"card": sqltypes.StringBindVariable("4111111111111111"),
+	}, NewEmptyVCursor(venv, time.Local))
+
+	tests := []struct {
+		expression string
+		expected   sqltypes.Value
+		constant   bool
+	}{
+		{"vt_test_mask('secret')", sqltypes.NewVarChar("******"), true},
+		{"VT_TEST_MASK(:card, 4)", sqltypes.NewVarChar("************1111"), false},
+		{"concat('card: ', vt_test_mask('1234', 2))", sqltypes.NewVarChar("card: **34"), true},
+		{"vt_test_mask(null)", sqltypes.NULL, true},
+		{"vt_test_counter() + 0", sqltypes.NewInt64(1), false},
+	}
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			expr, err := translate(test.expression, false)
+			require.NoError(t, err)
+			_, constant := expr.(*Literal)
+			assert.Equal(t, test.constant, constant)
+
+			r, err := env.EvaluateAST(expr)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, r.Value(collations.MySQL8().DefaultConnectionCharset()))
+			calls = 0
+		})
+	}
+
+	// The compiled expression evaluates the function too.
+	expr, err := translate("vt_test_mask('secret', 1)", true)
+	require.NoError(t, err)
+	compiled, ok := expr.(*CompiledExpr)
+	require.True(t, ok)
+	r, err := env.EvaluateVM(compiled)
+	require.NoError(t, err)
+	assert.Equal(t, sqltypes.NewVarChar("*****t"), r.Value(collations.MySQL8().DefaultConnectionCharset()))
+
+	_, err = translate("vt_test_mask()", false)
+	assert.EqualError(t, err, "Incorrect parameter count in the call to native function 'vt_test_mask'")
+	_, err = translate("db.vt_test_mask('secret')", false)
+	assert.ErrorContains(t, err, "not supported")
+}
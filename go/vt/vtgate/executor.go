@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -57,6 +58,7 @@ import (
 	"vitess.io/vitess/go/vt/vtgate/logstats"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/quota"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
@@ -73,6 +75,15 @@ var (
 
 	queriesProcessedByTable = stats.NewCountersWithMultiLabels("QueriesProcessedByTable", "Queries processed at vtgate by plan type, keyspace and table", []string{"Plan", "Keyspace", "Table"})
 	queriesRoutedByTable    = stats.NewCountersWithMultiLabels("QueriesRoutedByTable", "Queries routed from vtgate to vttablet by plan type, keyspace and table", []string{"Plan", "Keyspace", "Table"})
+
+	queryPlanCacheCanonicalizedHits = stats.NewCounter("QueryPlanCacheCanonicalizedHits", "Query plan cache hits of queries that were rewritten in a canonical form")
+
+	readWriteSplittingReplicaReads     = stats.NewCounter("ReadWriteSplittingReplicaReads", "Autocommit reads of primary sessions that read/write splitting sent to the replicas")
+	readWriteSplittingPrimaryFallbacks = stats.NewCounter("ReadWriteSplittingPrimaryFallbacks", "Reads that read/write splitting sent to the replicas of a shard and that fell back to its primary")
+
+	savepointStatements     = stats.NewCountersWithMultiLabels("SavepointStatements", "Savepoint statements processed at vtgate by type, and whether they were executed on the shards of the transaction or deferred until shards join it", []string{"Type", "Execution"})
+	savepointsReplayed      = stats.NewCounter("SavepointsReplayed", "Savepoints created on the shards that joined a transaction after the savepoints were set")
+	savepointRollbackAborts = stats.NewCounter("SavepointRollbackAborts", "Transactions rolled back because a rollback to a savepoint failed on some of their shards only")
 )
 
 const (
@@ -123,6 +134,16 @@ type Executor struct {
 
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
+
+	// quotas enforces the quota rules of the vschema.
+	quotas *quota.Enforcer
+
+	// readRetry retries the single-shard reads that fail with a transient error.
+	readRetry *readRetryPolicy
+
+	// sequences hands out the values of the sequences from the blocks that
+	// this vtgate reserved.
+	sequences *engine.SequenceCache
 }
 
 var executorOnce sync.Once
@@ -171,6 +192,9 @@ func NewExecutor(
 		plans:               plans,
 		warmingReadsPercent: warmingReadsPercent,
 		warmingReadsChannel: make(chan bool, warmingReadsConcurrency),
+		quotas:              quota.NewEnforcer(quotaRejections),
+		readRetry:           newReadRetryPolicy(),
+		sequences:           engine.NewSequenceCache(sequenceBlockSize, sequenceFetchRetries),
 	}
 
 	vschemaacl.Init()
@@ -218,12 +242,14 @@ func (e *Executor) Execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConn
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars)
+	logStats.QueryAttributes = safeSession.GetQueryAttributes()
 	stmtType, result, err := e.execute(ctx, mysqlCtx, safeSession, sql, bindVars, logStats)
 	logStats.Error = err
 	if result == nil {
-		saveSessionStats(safeSession, stmtType, 0, 0, 0, err)
+		safeSession.SaveStatementStats(stmtType, 0, 0, 0, err)
 	} else {
-		saveSessionStats(safeSession, stmtType, result.RowsAffected, result.InsertID, len(result.Rows), err)
+		safeSession.SaveStatementStats(stmtType, result.RowsAffected, result.InsertID, len(result.Rows), err)
+		annotateSpan(span, logStats, result.RowsAffected, len(result.Rows))
 	}
 	if result != nil && len(result.Rows) > warnMemoryRows {
 		warnings.Add("ResultsExceeded", 1)
@@ -254,9 +280,7 @@ func (s *streaminResultReceiver) storeResultStats(typ sqlparser.StatementType, q
 	defer s.mu.Unlock()
 	s.rowsAffected += qr.RowsAffected
 	s.rowsReturned += len(qr.Rows)
-	if qr.InsertID != 0 {
-		s.insertID = qr.InsertID
-	}
+	s.insertID = sqltypes.MergeInsertID(s.insertID, qr.InsertID)
 	s.stmtType = typ
 	return s.callback(qr)
 }
@@ -277,6 +301,7 @@ func (e *Executor) StreamExecute(
 	defer span.Finish()
 
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars)
+	logStats.QueryAttributes = safeSession.GetQueryAttributes()
 	srr := &streaminResultReceiver{callback: callback}
 	var err error
 
@@ -358,7 +383,8 @@ func (e *Executor) StreamExecute(
 	err = e.newExecute(ctx, mysqlCtx, safeSession, sql, bindVars, logStats, resultHandler, srr.storeResultStats)
 
 	logStats.Error = err
-	saveSessionStats(safeSession, srr.stmtType, srr.rowsAffected, srr.insertID, srr.rowsReturned, err)
+	safeSession.SaveStatementStats(srr.stmtType, srr.rowsAffected, srr.insertID, srr.rowsReturned, err)
+	annotateSpan(span, logStats, srr.rowsAffected, srr.rowsReturned)
 	if srr.rowsReturned > warnMemoryRows {
 		warnings.Add("ResultsExceeded", 1)
 		piiSafeSQL, err := e.env.Parser().RedactSQLQuery(sql)
@@ -374,6 +400,18 @@ func (e *Executor) StreamExecute(
 
 }
 
+// annotateSpan adds the type of the plan of a query, the number of queries
+// it sent to the shards and its rows to its span.
+func annotateSpan(span trace.Span, logStats *logstats.LogStats, rowsAffected uint64, rowsReturned int) {
+	span.Annotate("plan_type", logStats.StmtType)
+	span.Annotate("shard_queries", logStats.ShardQueries)
+	span.Annotate("rows_affected", rowsAffected)
+	span.Annotate("rows_returned", rowsReturned)
+	if logStats.ActiveKeyspace != "" {
+		span.Annotate("keyspace", logStats.ActiveKeyspace)
+	}
+}
+
 func canReturnRows(stmtType sqlparser.StatementType) bool {
 	switch stmtType {
 	case sqlparser.StmtSelect, sqlparser.StmtShow, sqlparser.StmtExplain, sqlparser.StmtCallProc:
@@ -383,25 +421,6 @@ func canReturnRows(stmtType sqlparser.StatementType) bool {
 	}
 }
 
-func saveSessionStats(safeSession *SafeSession, stmtType sqlparser.StatementType, rowsAffected, insertID uint64, rowsReturned int, err error) {
-	safeSession.RowCount = -1
-	if err != nil {
-		return
-	}
-	if !safeSession.foundRowsHandled {
-		safeSession.FoundRows = uint64(rowsReturned)
-	}
-	if insertID > 0 {
-		safeSession.LastInsertId = insertID
-	}
-	switch stmtType {
-	case sqlparser.StmtInsert, sqlparser.StmtReplace, sqlparser.StmtUpdate, sqlparser.StmtDelete:
-		safeSession.RowCount = int64(rowsAffected)
-	case sqlparser.StmtDDL, sqlparser.StmtSet, sqlparser.StmtBegin, sqlparser.StmtCommit, sqlparser.StmtRollback, sqlparser.StmtFlush:
-		safeSession.RowCount = 0
-	}
-}
-
 func (e *Executor) execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConnection, safeSession *SafeSession, sql string, bindVars map[string]*querypb.BindVariable, logStats *logstats.LogStats) (sqlparser.StatementType, *sqltypes.Result, error) {
 	var err error
 	var qr *sqltypes.Result
@@ -441,6 +460,14 @@ func (e *Executor) addNeededBindVars(vcursor *vcursorImpl, bindVarNeeds *sqlpars
 			bindVars[key] = sqltypes.BoolBindVariable(session.Autocommit)
 		case sysvars.QueryTimeout.Name:
 			bindVars[key] = sqltypes.Int64BindVariable(session.GetQueryTimeout())
+		case sysvars.StreamChunkRows.Name:
+			bindVars[key] = sqltypes.Int64BindVariable(session.GetStreamChunkRows())
+		case sysvars.StreamChunkTimeout.Name:
+			bindVars[key] = sqltypes.Int64BindVariable(session.GetStreamChunkTimeout())
+		case sysvars.MaxReplicaLag.Name:
+			bindVars[key] = sqltypes.Int64BindVariable(session.GetMaxReplicaLag())
+		case sysvars.TabletTags.Name:
+			bindVars[key] = sqltypes.StringBindVariable(session.GetTabletTags())
 		case sysvars.ClientFoundRows.Name:
 			var v bool
 			ifOptionsExist(session, func(options *querypb.ExecuteOptions) {
@@ -479,6 +506,14 @@ func (e *Executor) addNeededBindVars(vcursor *vcursorImpl, bindVarNeeds *sqlpars
 			bindVars[key] = sqltypes.StringBindVariable(session.SessionUUID)
 		case sysvars.SessionEnableSystemSettings.Name:
 			bindVars[key] = sqltypes.BoolBindVariable(session.EnableSystemSettings)
+		case sysvars.SnapshotReads.Name:
+			bindVars[key] = sqltypes.BoolBindVariable(session.SnapshotReads)
+		case sysvars.SkipReadRetry.Name:
+			bindVars[key] = sqltypes.BoolBindVariable(session.SkipReadRetry)
+		case sysvars.ReadOnlyTxOnReplica.Name:
+			bindVars[key] = sqltypes.BoolBindVariable(session.ReadOnlyTransactionsOnReplica)
+		case sysvars.TenantID.Name:
+			bindVars[key] = sqltypes.StringBindVariable(session.TenantId)
 		case sysvars.ReadAfterWriteGTID.Name:
 			var v string
 			ifReadAfterWriteExist(session, func(raw *vtgatepb.ReadAfterWrite) {
@@ -597,7 +632,7 @@ func (e *Executor) handleRollback(ctx context.Context, safeSession *SafeSession,
 	return &sqltypes.Result{}, err
 }
 
-func (e *Executor) handleSavepoint(ctx context.Context, safeSession *SafeSession, sql string, planType string, logStats *logstats.LogStats, nonTxResponse func(query string) (*sqltypes.Result, error), ignoreMaxMemoryRows bool) (*sqltypes.Result, error) {
+func (e *Executor) handleSavepoint(ctx context.Context, safeSession *SafeSession, stmt sqlparser.Statement, sql string, planType string, logStats *logstats.LogStats, nonTxResponse func(query string) (*sqltypes.Result, error), ignoreMaxMemoryRows bool) (*sqltypes.Result, error) {
 	execStart := time.Now()
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
 	logStats.ShardQueries = uint64(len(safeSession.ShardSessions))
@@ -611,27 +646,54 @@ func (e *Executor) handleSavepoint(ctx context.Context, safeSession *SafeSession
 	// and later will be executed when a transaction is started.
 	if !safeSession.isTxOpen() {
 		if safeSession.InTransaction() {
+			savepointStatements.Add([]string{planType, "Deferred"}, 1)
 			// Storing, as this needs to be executed just after starting transaction on the shard.
-			safeSession.StoreSavepoint(sql)
+			if !storeSavepoint(safeSession, stmt) {
+				return nonTxResponse(sql)
+			}
 			return &sqltypes.Result{}, nil
 		}
 		return nonTxResponse(sql)
 	}
+	savepointStatements.Add([]string{planType, "Executed"}, 1)
 	orig := safeSession.commitOrder
-	qr, err := e.executeSPInAllSessions(ctx, safeSession, sql, ignoreMaxMemoryRows)
+	qr, partial, err := e.executeSPInAllSessions(ctx, safeSession, sql, ignoreMaxMemoryRows)
 	safeSession.SetCommitOrder(orig)
 	if err != nil {
+		if _, isRollback := stmt.(*sqlparser.SRollback); isRollback && partial {
+			// The shards that rolled back to the savepoint and the ones that did not are
+			// not consistent anymore, so the transaction has to be aborted.
+			savepointRollbackAborts.Add(1)
+			_ = e.txConn.Rollback(ctx, safeSession)
+			return nil, vterrors.Wrap(err, "transaction rolled back as the rollback to the savepoint failed on some of the shards")
+		}
 		return nil, err
 	}
-	safeSession.StoreSavepoint(sql)
+	storeSavepoint(safeSession, stmt)
 	return qr, nil
 }
 
+// storeSavepoint applies a savepoint statement to the savepoints stored in the session.
+// It returns false if the statement refers to a savepoint that does not exist.
+func storeSavepoint(safeSession *SafeSession, stmt sqlparser.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *sqlparser.Savepoint:
+		safeSession.AddSavepoint(stmt.Name)
+	case *sqlparser.SRollback:
+		return safeSession.RollbackToSavepoint(stmt.Name)
+	case *sqlparser.Release:
+		return safeSession.ReleaseSavepoint(stmt.Name)
+	}
+	return true
+}
+
 // executeSPInAllSessions function executes the savepoint query in all open shard sessions (pre, normal and post)
 // which has non-zero transaction id (i.e. an open transaction on the shard connection).
-func (e *Executor) executeSPInAllSessions(ctx context.Context, safeSession *SafeSession, sql string, ignoreMaxMemoryRows bool) (*sqltypes.Result, error) {
+// On error, it also returns whether the query succeeded on some of the shards.
+func (e *Executor) executeSPInAllSessions(ctx context.Context, safeSession *SafeSession, sql string, ignoreMaxMemoryRows bool) (*sqltypes.Result, bool, error) {
 	var qr *sqltypes.Result
 	var errs []error
+	succeeded := 0
 	for _, co := range []vtgatepb.CommitOrder{vtgatepb.CommitOrder_PRE, vtgatepb.CommitOrder_NORMAL, vtgatepb.CommitOrder_POST} {
 		safeSession.SetCommitOrder(co)
 
@@ -650,12 +712,13 @@ func (e *Executor) executeSPInAllSessions(ctx context.Context, safeSession *Safe
 			queries = append(queries, &querypb.BoundQuery{Sql: sql})
 		}
 		qr, errs = e.ExecuteMultiShard(ctx, nil, rss, queries, safeSession, false /*autocommit*/, ignoreMaxMemoryRows)
+		succeeded += len(rss) - len(errs)
 		err := vterrors.Aggregate(errs)
 		if err != nil {
-			return nil, err
+			return nil, succeeded > 0, err
 		}
 	}
-	return qr, nil
+	return qr, false, nil
 }
 
 // handleKill executed the kill statement.
@@ -744,8 +807,6 @@ func (e *Executor) showVitessMetadata(ctx context.Context, filter *sqlparser.Sho
 	}, nil
 }
 
-type tabletFilter func(tablet *topodatapb.Tablet, servingState string, primaryTermStartTime int64) bool
-
 func (e *Executor) showShards(ctx context.Context, filter *sqlparser.ShowFilter, destTabletType topodatapb.TabletType) (*sqltypes.Result, error) {
 	showVitessShardsFilters := func(filter *sqlparser.ShowFilter) ([]func(string) bool, []func(string, *topodatapb.ShardReference) bool) {
 		keyspaceFilters := []func(string) bool{}
@@ -771,11 +832,6 @@ func (e *Executor) showShards(ctx context.Context, filter *sqlparser.ShowFilter,
 			return keyspaceFilters, shardFilters
 		}
 
-		if filter.Filter != nil {
-			// TODO build a query planner I guess? lol that should be fun
-			log.Infof("SHOW VITESS_SHARDS where clause %+v. Ignoring this (for now).", filter.Filter)
-		}
-
 		return keyspaceFilters, shardFilters
 	}
 
@@ -831,162 +887,51 @@ func (e *Executor) showShards(ctx context.Context, filter *sqlparser.ShowFilter,
 }
 
 func (e *Executor) showTablets(filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
-	getTabletFilters := func(filter *sqlparser.ShowFilter) []tabletFilter {
-		var filters []tabletFilter
-
-		if filter == nil {
-			return filters
-		}
-
-		if filter.Like != "" {
-			tabletRegexp := sqlparser.LikeToRegexp(filter.Like)
-
-			f := func(tablet *topodatapb.Tablet, servingState string, primaryTermStartTime int64) bool {
-				return tabletRegexp.MatchString(tablet.Hostname)
-			}
-
-			filters = append(filters, f)
-			return filters
-		}
-
-		if filter.Filter != nil {
-			log.Infof("SHOW VITESS_TABLETS where clause: %+v. Ignoring this (for now).", filter.Filter)
-		}
-
-		return filters
+	rows := e.tabletRows()
+	if filter != nil && filter.Like != "" {
+		hostnameRegexp := sqlparser.LikeToRegexp(filter.Like)
+		rows = slices.DeleteFunc(rows, func(row []sqltypes.Value) bool {
+			// The Hostname is the seventh column.
+			return !hostnameRegexp.MatchString(row[6].ToString())
+		})
 	}
+	return &sqltypes.Result{
+		Fields: buildVarCharFields("Cell", "Keyspace", "Shard", "TabletType", "State", "Alias", "Hostname", "PrimaryTermStartTime", "CircuitBreaker"),
+		Rows:   rows,
+	}, nil
+}
 
-	tabletFilters := getTabletFilters(filter)
-
-	rows := [][]sqltypes.Value{}
-	status := e.scatterConn.GetHealthCheckCacheStatus()
-	for _, s := range status {
-		for _, ts := range s.TabletsStats {
-			state := "SERVING"
-			if !ts.Serving {
-				state = "NOT_SERVING"
-			}
-			ptst := ts.PrimaryTermStartTime
-			ptstStr := ""
-			if ptst > 0 {
-				// this code depends on the fact that PrimaryTermStartTime is the seconds since epoch start
-				ptstStr = time.Unix(ptst, 0).UTC().Format(time.RFC3339)
-			}
-
-			skipTablet := false
-			for _, filter := range tabletFilters {
-				if !filter(ts.Tablet, state, ptst) {
-					skipTablet = true
-					break
-				}
-			}
-
-			if skipTablet {
-				continue
-			}
-
-			rows = append(rows, buildVarCharRow(
-				s.Cell,
-				s.Target.Keyspace,
-				s.Target.Shard,
-				ts.Target.TabletType.String(),
-				state,
-				topoproto.TabletAliasString(ts.Tablet.Alias),
-				ts.Tablet.Hostname,
-				ptstStr,
-			))
-		}
+func (e *Executor) showPlans(filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
+	rows := e.planRows()
+	if filter != nil && filter.Like != "" {
+		queryRegexp := sqlparser.LikeToRegexp(filter.Like)
+		rows = slices.DeleteFunc(rows, func(row []sqltypes.Value) bool {
+			// The Query is the first column.
+			return !queryRegexp.MatchString(row[0].ToString())
+		})
+	}
+	var fields []*querypb.Field
+	for _, column := range vindexes.VitessMetadataTable(vindexes.VitessMetadataPlans).Columns {
+		fields = append(fields, engine.VitessMetadataField(column))
 	}
 	return &sqltypes.Result{
-		Fields: buildVarCharFields("Cell", "Keyspace", "Shard", "TabletType", "State", "Alias", "Hostname", "PrimaryTermStartTime"),
+		Fields: fields,
 		Rows:   rows,
 	}, nil
 }
 
 func (e *Executor) showVitessReplicationStatus(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error) {
-	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
-	defer cancel()
-	rows := [][]sqltypes.Value{}
-
-	status := e.scatterConn.GetHealthCheckCacheStatus()
-
-	for _, s := range status {
-		for _, ts := range s.TabletsStats {
-			// We only want to show REPLICA and RDONLY tablets
-			if ts.Target.TabletType != topodatapb.TabletType_REPLICA && ts.Target.TabletType != topodatapb.TabletType_RDONLY {
-				continue
-			}
-
-			// Allow people to filter by Keyspace and Shard using a LIKE clause
-			if filter != nil {
-				ksFilterRegex := sqlparser.LikeToRegexp(filter.Like)
-				keyspaceShardStr := fmt.Sprintf("%s/%s", ts.Target.Keyspace, ts.Target.Shard)
-				if !ksFilterRegex.MatchString(keyspaceShardStr) {
-					continue
-				}
-			}
-
-			tabletHostPort := ts.GetTabletHostPort()
-			throttlerStatus, err := getTabletThrottlerStatus(tabletHostPort)
-			if err != nil {
-				log.Warningf("Could not get throttler status from %s: %v", topoproto.TabletAliasString(ts.Tablet.Alias), err)
-			}
-
-			replSourceHost := ""
-			replSourcePort := int64(0)
-			replIOThreadHealth := ""
-			replSQLThreadHealth := ""
-			replLastError := ""
-			replLag := "-1" // A string to support NULL as a value
-			sql := "show slave status"
-			results, err := e.txConn.tabletGateway.Execute(ctx, ts.Target, sql, nil, 0, 0, nil)
-			if err != nil || results == nil {
-				log.Warningf("Could not get replication status from %s: %v", tabletHostPort, err)
-			} else if row := results.Named().Row(); row != nil {
-				replSourceHost = row["Master_Host"].ToString()
-				replSourcePort, _ = row["Master_Port"].ToInt64()
-				replIOThreadHealth = row["Slave_IO_Running"].ToString()
-				replSQLThreadHealth = row["Slave_SQL_Running"].ToString()
-				replLastError = row["Last_Error"].ToString()
-				// We cannot check the tablet's tabletenv config from here so
-				// we only use the tablet's stat -- which is managed by the
-				// ReplicationTracker -- if we can tell that it's enabled,
-				// meaning that it has a non-zero value. If it's actually
-				// enabled AND zero (rather than the zeroval), then mysqld
-				// should also return 0 so in this case the value is correct
-				// and equivalent either way. The only reason that we would
-				// want to use the ReplicationTracker based value, when we
-				// can, is because the polling method allows us to get the
-				// estimated lag value when replication is not running (based
-				// on how long we've seen that it's not been running).
-				if ts.Stats != nil && ts.Stats.ReplicationLagSeconds > 0 { // Use the value we get from the ReplicationTracker
-					replLag = fmt.Sprintf("%d", ts.Stats.ReplicationLagSeconds)
-				} else { // Use the value from mysqld
-					if row["Seconds_Behind_Master"].IsNull() {
-						replLag = strings.ToUpper(sqltypes.NullStr) // Uppercase to match mysqld's output in SHOW REPLICA STATUS
-					} else {
-						replLag = row["Seconds_Behind_Master"].ToString()
-					}
-				}
-			}
-			replicationHealth := fmt.Sprintf("{\"EventStreamRunning\":\"%s\",\"EventApplierRunning\":\"%s\",\"LastError\":\"%s\"}", replIOThreadHealth, replSQLThreadHealth, replLastError)
-
-			rows = append(rows, buildVarCharRow(
-				s.Target.Keyspace,
-				s.Target.Shard,
-				ts.Target.TabletType.String(),
-				topoproto.TabletAliasString(ts.Tablet.Alias),
-				ts.Tablet.Hostname,
-				fmt.Sprintf("%s:%d", replSourceHost, replSourcePort),
-				replicationHealth,
-				replLag,
-				throttlerStatus,
-			))
+	include := func(*querypb.Target) bool { return true }
+	// Allow people to filter by Keyspace and Shard using a LIKE clause
+	if filter != nil {
+		ksFilterRegex := sqlparser.LikeToRegexp(filter.Like)
+		include = func(target *querypb.Target) bool {
+			return ksFilterRegex.MatchString(fmt.Sprintf("%s/%s", target.Keyspace, target.Shard))
 		}
 	}
 	return &sqltypes.Result{
 		Fields: buildVarCharFields("Keyspace", "Shard", "TabletType", "Alias", "Hostname", "ReplicationSource", "ReplicationHealth", "ReplicationLag", "ThrottlerStatus"),
-		Rows:   rows,
+		Rows:   e.replicationStatusRows(ctx, include),
 	}, nil
 }
 
@@ -1017,6 +962,7 @@ func (e *Executor) SaveVSchema(vschema *vindexes.VSchema, stats *VSchemaStats) {
 	defer e.mu.Unlock()
 	if vschema != nil {
 		e.vschema = vschema
+		e.quotas.SetRules(vschema.QuotaRules)
 	}
 	e.vschemaStats = stats
 	e.ClearPlans()
@@ -1071,12 +1017,16 @@ func (e *Executor) getPlan(
 	vcursor.SetIgnoreMaxMemoryRows(sqlparser.IgnoreMaxMaxMemoryRowsDirective(stmt))
 	vcursor.SetConsolidator(sqlparser.Consolidator(stmt))
 	vcursor.SetWorkloadName(sqlparser.GetWorkloadNameFromStatement(stmt))
+	vcursor.SetStreamChunkOptions(sqlparser.StreamChunkOptions(stmt))
+	vcursor.SetMaxReplicaLagFromComments(sqlparser.MaxReplicaLag(stmt))
+	vcursor.splitReadWrite(stmt)
 	vcursor.UpdateForeignKeyChecksState(sqlparser.ForeignKeyChecksState(stmt))
 	priority, err := sqlparser.GetPriorityFromStatement(stmt)
 	if err != nil {
 		return nil, err
 	}
 	vcursor.SetPriority(priority)
+	vcursor.splitReads(stmt)
 
 	setVarComment, err := prepareSetVarComment(vcursor, stmt)
 	if err != nil {
@@ -1086,6 +1036,9 @@ func (e *Executor) getPlan(
 	// Normalize if possible
 	shouldNormalize := e.canNormalizeStatement(stmt, setVarComment)
 	parameterize := allowParameterization && shouldNormalize
+	// Canonicalize before normalizing, so that the bind variables are
+	// named in the canonical order.
+	canonicalized := canonicalizeQueries && shouldNormalize && sqlparser.Canonicalize(stmt)
 
 	rewriteASTResult, err := sqlparser.PrepareAST(
 		stmt,
@@ -1111,7 +1064,11 @@ func (e *Executor) getPlan(
 	logStats.SQL = comments.Leading + query + comments.Trailing
 	logStats.BindVariables = sqltypes.CopyBindVariables(bindVars)
 
-	return e.cacheAndBuildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds, logStats)
+	plan, err := e.cacheAndBuildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds, logStats)
+	if canonicalized && logStats.CachedPlan {
+		queryPlanCacheCanonicalizedHits.Add(1)
+	}
+	return plan, err
 }
 
 func (e *Executor) hashPlan(ctx context.Context, vcursor *vcursorImpl, query string) PlanCacheKey {
@@ -1138,6 +1095,8 @@ func (e *Executor) buildStatement(
 
 	plan.Warnings = vcursor.warnings
 	vcursor.warnings = nil
+	plan.BindVarTypes = vcursor.bindVarTypes
+	vcursor.bindVarTypes = nil
 
 	err = e.checkThatPlanIsValid(stmt, plan)
 	return plan, err
@@ -1161,6 +1120,9 @@ func (e *Executor) cacheAndBuildStatement(
 		plan, logStats.CachedPlan, err = e.plans.GetOrLoad(planKey, e.epoch.Load(), func() (*engine.Plan, error) {
 			return e.buildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds)
 		})
+		if logStats.CachedPlan {
+			plan.AddCacheHit()
+		}
 		return plan, err
 	}
 	return e.buildStatement(ctx, vcursor, query, stmt, reservedVars, bindVarNeeds)
@@ -1238,7 +1200,7 @@ func returnAsJSON(response http.ResponseWriter, stuff any) {
 	_, _ = response.Write(ebuf.Bytes())
 }
 
-// Plans returns the LRU plan cache
+// Plans returns the plan cache
 func (e *Executor) Plans() *PlanCache {
 	return e.plans
 }
@@ -1313,6 +1275,7 @@ func isValidPayloadSize(query string) bool {
 // Prepare executes a prepare statements.
 func (e *Executor) Prepare(ctx context.Context, method string, safeSession *SafeSession, sql string, bindVars map[string]*querypb.BindVariable) (fld []*querypb.Field, err error) {
 	logStats := logstats.NewLogStats(ctx, method, sql, safeSession.GetSessionUUID(), bindVars)
+	logStats.QueryAttributes = safeSession.GetQueryAttributes()
 	fld, err = e.prepare(ctx, safeSession, sql, bindVars, logStats)
 	logStats.Error = err
 
@@ -1366,10 +1329,11 @@ func (e *Executor) handlePrepare(ctx context.Context, safeSession *SafeSession,
 	query, comments := sqlparser.SplitMarginComments(sql)
 	vcursor, _ := newVCursorImpl(safeSession, comments, e, logStats, e.vm, e.VSchema(), e.resolver.resolver, e.serv, e.warnShardedOnly, e.pv)
 
-	stmt, reservedVars, err := parseAndValidateQuery(query, e.env.Parser())
+	stmt, reservedVars, positions, err := parseAndValidateQuery(sql, query, e.env.Parser())
 	if err != nil {
 		return nil, err
 	}
+	vcursor.SetQueryPositions(positions)
 
 	plan, err := e.getPlan(ctx, vcursor, sql, stmt, comments, bindVars, reservedVars /* parameterize */, false, logStats)
 	execStart := time.Now()
@@ -1401,15 +1365,20 @@ func (e *Executor) handlePrepare(ctx context.Context, safeSession *SafeSession,
 	return qr.Fields, err
 }
 
-func parseAndValidateQuery(query string, parser *sqlparser.Parser) (sqlparser.Statement, *sqlparser.ReservedVars, error) {
-	stmt, reserved, err := parser.Parse2(query)
+// parseAndValidateQuery parses the query, which the caller split out of sql with
+// sqlparser.SplitMarginComments. The returned positions are positions in sql.
+func parseAndValidateQuery(sql, query string, parser *sqlparser.Parser) (sqlparser.Statement, *sqlparser.ReservedVars, sqlparser.Positions, error) {
+	stmt, reserved, positions, err := parser.Parse2WithPositions(query)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	if !sqlparser.IgnoreMaxPayloadSizeDirective(stmt) && !isValidPayloadSize(query) {
-		return nil, nil, vterrors.NewErrorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.NetPacketTooLarge, "query payload size above threshold")
+		return nil, nil, nil, vterrors.NewErrorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.NetPacketTooLarge, "query payload size above threshold")
+	}
+	if offset := strings.Index(sql, query); offset > 0 {
+		positions.Offset(offset)
 	}
-	return stmt, sqlparser.NewReservedVars("vtg", reserved), nil
+	return stmt, sqlparser.NewReservedVars("vtg", reserved), positions, nil
 }
 
 // ExecuteMultiShard implements the IExecutor interface
@@ -1540,7 +1509,8 @@ func (e *Executor) ReleaseLock(ctx context.Context, session *SafeSession) error
 
 // planPrepareStmt implements the IExecutor interface
 func (e *Executor) planPrepareStmt(ctx context.Context, vcursor *vcursorImpl, query string) (*engine.Plan, sqlparser.Statement, error) {
-	stmt, reservedVars, err := parseAndValidateQuery(query, e.env.Parser())
+	// The plan is built from a clone of the statement, which the positions do not apply to.
+	stmt, reservedVars, _, err := parseAndValidateQuery(query, query, e.env.Parser())
 	if err != nil {
 		return nil, nil, err
 	}
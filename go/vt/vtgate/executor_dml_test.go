@@ -1871,6 +1871,28 @@ func TestInsertAutoincSharded(t *testing.T) {
 	assert.EqualValues(t, 2, session.LastInsertId)
 }
 
+func TestInsertAutoincMultiShard(t *testing.T) {
+	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
+	executor.normalize = true
+
+	// Each shard reports the id that its own auto-increment generated.
+	sbc1.SetResults([]*sqltypes.Result{{RowsAffected: 1, InsertID: 7}})
+	sbc2.SetResults([]*sqltypes.Result{{RowsAffected: 1, InsertID: 5}})
+	session := &vtgatepb.Session{
+		TargetString: "@primary",
+	}
+	result, err := executorExec(ctx, executor, session, "insert into user_extra(user_id) values (1), (3)", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, result.InsertID)
+	assert.EqualValues(t, 5, session.LastInsertId)
+	testRowCount(t, ctx, executor, session, 2)
+
+	// A statement that doesn't generate an id leaves the last one as it was.
+	_, err = executorExec(ctx, executor, session, "delete from user_extra where user_id in (1, 3)", nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, session.LastInsertId)
+}
+
 func TestInsertGeneratorUnsharded(t *testing.T) {
 	executor, _, _, sbclookup, ctx := createExecutorEnv(t)
 	session := &vtgatepb.Session{
@@ -1185,6 +1185,42 @@ func TestSelectBindvars(t *testing.T) {
 	testQueryLog(t, executor, logChan, "TestExecute", "SELECT", "select id from `user` where `name` = :name", 2)
 }
 
+// TestSelectBindvarsCoercedToColumnType tests that the string values compared with an
+// integral column are routed, and sent, as the numbers MySQL compares them as.
+func TestSelectBindvarsCoercedToColumnType(t *testing.T) {
+	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
+
+	sql := "select id from typed_user where id = :id"
+	session := &vtgatepb.Session{
+		TargetString: "@primary",
+	}
+	for _, id := range []string{"1", " 1", "1.0"} {
+		_, err := executorExec(ctx, executor, session, sql, map[string]*querypb.BindVariable{
+			"id": sqltypes.StringBindVariable(id),
+		})
+		require.NoError(t, err)
+		wantQueries := []*querypb.BoundQuery{{
+			Sql:           "select id from typed_user where id = :id",
+			BindVariables: map[string]*querypb.BindVariable{"id": sqltypes.Int64BindVariable(1)},
+		}}
+		utils.MustMatch(t, wantQueries, sbc1.Queries, id)
+		assert.Empty(t, sbc2.Queries)
+		sbc1.Queries = nil
+	}
+
+	// strings that are not numbers are not coerced, and the vindex maps them as they are:
+	// the hash vindex maps them to no shard
+	_, err := executorExec(ctx, executor, session, sql, map[string]*querypb.BindVariable{
+		"id": sqltypes.StringBindVariable("abc"),
+	})
+	require.NoError(t, err)
+	wantQueries := []*querypb.BoundQuery{{
+		Sql:           "select id from typed_user where 1 != 1",
+		BindVariables: map[string]*querypb.BindVariable{"id": sqltypes.StringBindVariable("abc")},
+	}}
+	utils.MustMatch(t, wantQueries, append(sbc1.Queries, sbc2.Queries...))
+}
+
 func TestSelectEqual(t *testing.T) {
 	executor, sbc1, sbc2, sbclookup, ctx := createExecutorEnv(t)
 
@@ -3343,18 +3379,12 @@ func TestGen4SelectStraightJoin(t *testing.T) {
 	require.NoError(t, err)
 	wantQueries := []*querypb.BoundQuery{
 		{
-			Sql:           "select u.id from `user` as u, user2 as u2 where u.id = u2.id",
+			Sql:           "select straight_join u.id from `user` as u, user2 as u2 where u.id = u2.id",
 			BindVariables: map[string]*querypb.BindVariable{},
 		},
 	}
-	wantWarnings := []*querypb.QueryWarning{
-		{
-			Code:    1235,
-			Message: "straight join is converted to normal join",
-		},
-	}
 	utils.MustMatch(t, wantQueries, sbc1.Queries)
-	utils.MustMatch(t, wantWarnings, session.Warnings)
+	assert.Empty(t, session.Warnings)
 }
 
 func TestGen4MultiColumnVindexEqual(t *testing.T) {
@@ -254,6 +254,21 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set @@enable_system_settings = false",
 		out: &vtgatepb.Session{Autocommit: true, EnableSystemSettings: false},
+	}, {
+		in:  "set @@snapshot_reads = on",
+		out: &vtgatepb.Session{Autocommit: true, SnapshotReads: true},
+	}, {
+		in:  "set @@snapshot_reads = 0",
+		out: &vtgatepb.Session{Autocommit: true, SnapshotReads: false},
+	}, {
+		in:  "set @@skip_read_retry = 1",
+		out: &vtgatepb.Session{Autocommit: true, SkipReadRetry: true},
+	}, {
+		in:  "set @@read_only_transactions_on_replica = on",
+		out: &vtgatepb.Session{Autocommit: true, ReadOnlyTransactionsOnReplica: true},
+	}, {
+		in:  "set @@tenant_id = 'acme'",
+		err: "unknown tenant: acme",
 	}, {
 		in:  "set @@socket = '/tmp/change.sock'",
 		err: "VT03010: variable 'socket' is a read only variable",
@@ -263,6 +278,33 @@ func TestExecutorSet(t *testing.T) {
 	}, {
 		in:  "set @@query_timeout = 50, query_timeout = 75",
 		out: &vtgatepb.Session{Autocommit: true, QueryTimeout: 75},
+	}, {
+		in:  "set @@stream_chunk_rows = 1000",
+		out: &vtgatepb.Session{Autocommit: true, StreamChunkRows: 1000},
+	}, {
+		in:  "set @@stream_chunk_rows = -1",
+		err: "invalid stream_chunk_rows: -1",
+	}, {
+		in:  "set @@stream_chunk_timeout = 500",
+		out: &vtgatepb.Session{Autocommit: true, StreamChunkTimeout: 500},
+	}, {
+		in:  "set @@stream_chunk_timeout = -1",
+		err: "invalid stream_chunk_timeout: -1",
+	}, {
+		in:  "set @@max_replica_lag = 10",
+		out: &vtgatepb.Session{Autocommit: true, MaxReplicaLag: 10},
+	}, {
+		in:  "set @@max_replica_lag = -1",
+		err: "invalid max_replica_lag: -1",
+	}, {
+		in:  "set @@tablet_tags = 'region=us-east,tier=analytics'",
+		out: &vtgatepb.Session{Autocommit: true, TabletTags: "region=us-east,tier=analytics"},
+	}, {
+		in:  "set @@tablet_tags = ''",
+		out: &vtgatepb.Session{Autocommit: true},
+	}, {
+		in:  "set @@tablet_tags = 'region'",
+		err: "invalid tablet_tags: region",
 	}}
 	for i, tcase := range testcases {
 		t.Run(fmt.Sprintf("%d-%s", i, tcase.in), func(t *testing.T) {
@@ -38,18 +38,21 @@ import (
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/buffer"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/logstats"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -848,10 +851,10 @@ func TestExecutorShow(t *testing.T) {
 	// Just test for first & last.
 	qr.Rows = [][]sqltypes.Value{qr.Rows[0], qr.Rows[len(qr.Rows)-1]}
 	wantqr = &sqltypes.Result{
-		Fields: buildVarCharFields("Cell", "Keyspace", "Shard", "TabletType", "State", "Alias", "Hostname", "PrimaryTermStartTime"),
+		Fields: buildVarCharFields("Cell", "Keyspace", "Shard", "TabletType", "State", "Alias", "Hostname", "PrimaryTermStartTime", "CircuitBreaker"),
 		Rows: [][]sqltypes.Value{
-			buildVarCharRow("aa", "TestExecutor", "-20", "PRIMARY", "SERVING", "aa-0000000001", "-20", "1970-01-01T00:00:01Z"),
-			buildVarCharRow("aa", "TestUnsharded", "0", "REPLICA", "SERVING", "aa-0000000010", "2", "1970-01-01T00:00:01Z"),
+			buildVarCharRow("aa", "TestExecutor", "-20", "PRIMARY", "SERVING", "aa-0000000001", "-20", "1970-01-01T00:00:01Z", ""),
+			buildVarCharRow("aa", "TestUnsharded", "0", "REPLICA", "SERVING", "aa-0000000010", "2", "1970-01-01T00:00:01Z", ""),
 		},
 	}
 	utils.MustMatch(t, wantqr, qr, query)
@@ -860,7 +863,7 @@ func TestExecutorShow(t *testing.T) {
 	qr, err = executor.Execute(ctx, nil, "TestExecute", session, query, nil)
 	require.NoError(t, err)
 	wantqr = &sqltypes.Result{
-		Fields: buildVarCharFields("Cell", "Keyspace", "Shard", "TabletType", "State", "Alias", "Hostname", "PrimaryTermStartTime"),
+		Fields: buildVarCharFields("Cell", "Keyspace", "Shard", "TabletType", "State", "Alias", "Hostname", "PrimaryTermStartTime", "CircuitBreaker"),
 		Rows:   [][]sqltypes.Value{},
 	}
 	utils.MustMatch(t, wantqr, qr, fmt.Sprintf("%q should be empty", query))
@@ -869,9 +872,9 @@ func TestExecutorShow(t *testing.T) {
 	qr, err = executor.Execute(ctx, nil, "TestExecute", session, query, nil)
 	require.NoError(t, err)
 	wantqr = &sqltypes.Result{
-		Fields: buildVarCharFields("Cell", "Keyspace", "Shard", "TabletType", "State", "Alias", "Hostname", "PrimaryTermStartTime"),
+		Fields: buildVarCharFields("Cell", "Keyspace", "Shard", "TabletType", "State", "Alias", "Hostname", "PrimaryTermStartTime", "CircuitBreaker"),
 		Rows: [][]sqltypes.Value{
-			buildVarCharRow("aa", "TestExecutor", "-20", "PRIMARY", "SERVING", "aa-0000000001", "-20", "1970-01-01T00:00:01Z"),
+			buildVarCharRow("aa", "TestExecutor", "-20", "PRIMARY", "SERVING", "aa-0000000001", "-20", "1970-01-01T00:00:01Z", ""),
 		},
 	}
 	utils.MustMatch(t, wantqr, qr, query)
@@ -1608,7 +1611,7 @@ func getPlanCached(t *testing.T, ctx context.Context, e *Executor, vcursor *vcur
 			Options: &querypb.ExecuteOptions{SkipQueryPlanCache: skipQueryPlanCache}},
 	}
 
-	stmt, reservedVars, err := parseAndValidateQuery(sql, sqlparser.NewTestParser())
+	stmt, reservedVars, _, err := parseAndValidateQuery(sql, sql, sqlparser.NewTestParser())
 	require.NoError(t, err)
 	plan, err := e.getPlan(context.Background(), vcursor, sql, stmt, comments, bindVars, reservedVars /* normalize */, e.normalize, logStats)
 	require.NoError(t, err)
@@ -1738,18 +1741,40 @@ func TestGetPlanNormalized(t *testing.T) {
 	wantSQL = normalized + " /* comment 3 */"
 	assert.Equal(t, wantSQL, logStats3.SQL)
 
+	// The plans of unshardedvc are other entries of the cache, with their own cache hits.
 	var logStats5 *logstats.LogStats
 	plan3, logStats5 = getPlanCached(t, ctx, r, unshardedvc, query1, makeComments(" /* comment 5 */"), map[string]*querypb.BindVariable{}, false)
-	assert.Equal(t, plan1, plan3)
+	assert.Equal(t, plan1.Instructions, plan3.Instructions)
 	wantSQL = normalized + " /* comment 5 */"
 	assert.Equal(t, wantSQL, logStats5.SQL)
 
 	plan4, _ := getPlanCached(t, ctx, r, unshardedvc, query1, makeComments(" /* comment 6 */"), map[string]*querypb.BindVariable{}, false)
-	assert.Equal(t, plan1, plan4)
+	assert.Same(t, plan3, plan4)
+	assert.EqualValues(t, 1, plan4.CacheHits)
 	assertCacheContains(t, r, emptyvc, normalized)
 	assertCacheContains(t, r, unshardedvc, normalized)
 }
 
+func TestGetPlanCanonicalized(t *testing.T) {
+	r, _, _, _, ctx := createExecutorEnv(t)
+	r.normalize = true
+	defer func(old bool) { canonicalizeQueries = old }(canonicalizeQueries)
+	canonicalizeQueries = true
+	emptyvc, _ := newVCursorImpl(NewSafeSession(&vtgatepb.Session{TargetString: "@unknown"}), makeComments(""), r, nil, r.vm, r.VSchema(), r.resolver.resolver, nil, false, pv)
+
+	hits := queryPlanCacheCanonicalizedHits.Get()
+	plan1, logStats1 := getPlanCached(t, ctx, r, emptyvc, "select * from user where name = 'a' and id = 1", makeComments(""), map[string]*querypb.BindVariable{}, false)
+	plan2, logStats2 := getPlanCached(t, ctx, r, emptyvc, "select * from user where 2 = id and 'b' = name", makeComments(""), map[string]*querypb.BindVariable{}, false)
+
+	normalized := "select * from `user` where `name` = :name /* VARCHAR */ and id = :id /* INT64 */"
+	assert.Equal(t, normalized, logStats1.SQL)
+	assert.Equal(t, normalized, logStats2.SQL)
+	assert.Same(t, plan1, plan2)
+	assertCacheSize(t, r.plans, 1)
+	assert.True(t, logStats2.CachedPlan)
+	assert.EqualValues(t, 1, queryPlanCacheCanonicalizedHits.Get()-hits)
+}
+
 func TestGetPlanPriority(t *testing.T) {
 
 	testCases := []struct {
@@ -2007,6 +2032,35 @@ func TestExecutorClearsWarnings(t *testing.T) {
 	require.Empty(t, session.Warnings)
 }
 
+func TestExecutorShardWarnings(t *testing.T) {
+	executor, sbc1, sbc2, _, ctx := createExecutorEnv(t)
+
+	sbc1.SetResults([]*sqltypes.Result{{
+		Warnings: []*querypb.QueryWarning{{Code: uint32(sqlerror.ERWarnDataTruncated), Message: "Data truncated for column 'name' at row 1"}},
+	}})
+	sbc2.SetResults([]*sqltypes.Result{{
+		Warnings: []*querypb.QueryWarning{{Code: uint32(sqlerror.ERTruncatedWrongValue), Message: "Truncated incorrect DOUBLE value: 'a'"}},
+	}})
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "@primary"})
+	_, err := executor.Execute(ctx, nil, "TestExecute", session, "select id from user", nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*querypb.QueryWarning{
+		{Code: uint32(sqlerror.ERWarnDataTruncated), Message: "TestExecutor/-20: Data truncated for column 'name' at row 1"},
+		{Code: uint32(sqlerror.ERTruncatedWrongValue), Message: "TestExecutor/40-60: Truncated incorrect DOUBLE value: 'a'"},
+	}, session.Warnings)
+
+	// The warnings of the shards are kept for SHOW WARNINGS.
+	qr, err := executor.Execute(ctx, nil, "TestExecute", session, "show warnings", nil)
+	require.NoError(t, err)
+	assert.Len(t, qr.Rows, 2)
+	assert.Len(t, session.Warnings, 2)
+
+	// They are cleared by the next statement.
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "select id from user", nil)
+	require.NoError(t, err)
+	assert.Empty(t, session.Warnings)
+}
+
 // TestServingKeyspaces tests that the dual queries are routed to the correct keyspaces from the list of serving keyspaces.
 func TestServingKeyspaces(t *testing.T) {
 	buffer.SetBufferingModeInTestingEnv(true)
@@ -2383,16 +2437,8 @@ func TestExecutorSavepointInTx(t *testing.T) {
 	require.NoError(t, err)
 	_, err = exec(executor, session, "rollback")
 	require.NoError(t, err)
+	// savepoint a was released before any shard joined the transaction, so it is not replayed.
 	sbc1WantQueries := []*querypb.BoundQuery{{
-		Sql:           "savepoint a",
-		BindVariables: map[string]*querypb.BindVariable{},
-	}, {
-		Sql:           "rollback to a",
-		BindVariables: map[string]*querypb.BindVariable{},
-	}, {
-		Sql:           "release savepoint a",
-		BindVariables: map[string]*querypb.BindVariable{},
-	}, {
 		Sql:           "select id from `user` where id = 1",
 		BindVariables: map[string]*querypb.BindVariable{},
 	}, {
@@ -2406,25 +2452,8 @@ func TestExecutorSavepointInTx(t *testing.T) {
 		BindVariables: map[string]*querypb.BindVariable{},
 	}}
 
+	// sbc2 joins the transaction once all the savepoints are released.
 	sbc2WantQueries := []*querypb.BoundQuery{{
-		Sql:           "savepoint a",
-		BindVariables: map[string]*querypb.BindVariable{},
-	}, {
-		Sql:           "rollback to a",
-		BindVariables: map[string]*querypb.BindVariable{},
-	}, {
-		Sql:           "release savepoint a",
-		BindVariables: map[string]*querypb.BindVariable{},
-	}, {
-		Sql:           "savepoint b",
-		BindVariables: map[string]*querypb.BindVariable{},
-	}, {
-		Sql:           "rollback to b",
-		BindVariables: map[string]*querypb.BindVariable{},
-	}, {
-		Sql:           "release savepoint b",
-		BindVariables: map[string]*querypb.BindVariable{},
-	}, {
 		Sql:           "select id from `user` where id = 3",
 		BindVariables: map[string]*querypb.BindVariable{},
 	}}
@@ -2484,14 +2513,9 @@ func TestExecutorSavepointInTxWithReservedConn(t *testing.T) {
 		Sql: "release savepoint a", BindVariables: emptyBV,
 	}}
 
+	// releasing savepoint a also released savepoint b, so sbc2 does not create any savepoint.
 	sbc2WantQueries := []*querypb.BoundQuery{{
 		Sql: "set sql_mode = ''", BindVariables: emptyBV,
-	}, {
-		Sql: "savepoint a", BindVariables: emptyBV,
-	}, {
-		Sql: "savepoint b", BindVariables: emptyBV,
-	}, {
-		Sql: "release savepoint a", BindVariables: emptyBV,
 	}, {
 		Sql: "select id from `user` where id = 3", BindVariables: emptyBV,
 	}}
@@ -2552,6 +2576,100 @@ func TestExecutorSavepointWithoutTx(t *testing.T) {
 	testQueryLog(t, executor, logChan, "TestExecute", "SELECT", "select id from `user` where id = 3", 1)
 }
 
+// TestExecutorSavepointReplay checks that the shards joining a transaction create the savepoints
+// that exist at that time, and only those.
+func TestExecutorSavepointReplay(t *testing.T) {
+	executor, sbc1, sbc2, _, _ := createExecutorEnv(t)
+
+	replayed := savepointsReplayed.Get()
+	deferred := savepointStatements.Counts()["Savepoint.Deferred"]
+
+	session := NewSafeSession(&vtgatepb.Session{Autocommit: false, TargetString: "@primary"})
+	for _, query := range []string{
+		"savepoint a",
+		"select id from user where id = 1",
+		"savepoint b",
+		// replaces the first savepoint a.
+		"savepoint A",
+		"rollback to b",
+		"select id from user where id = 3",
+		"rollback to b",
+	} {
+		_, err := exec(executor, session, query)
+		require.NoError(t, err, query)
+	}
+	emptyBV := map[string]*querypb.BindVariable{}
+	sbc1WantQueries := []*querypb.BoundQuery{
+		{Sql: "savepoint a", BindVariables: emptyBV},
+		{Sql: "select id from `user` where id = 1", BindVariables: emptyBV},
+		{Sql: "savepoint b", BindVariables: emptyBV},
+		{Sql: "savepoint A", BindVariables: emptyBV},
+		{Sql: "rollback to b", BindVariables: emptyBV},
+		{Sql: "rollback to b", BindVariables: emptyBV},
+	}
+	sbc2WantQueries := []*querypb.BoundQuery{
+		{Sql: "savepoint b", BindVariables: emptyBV},
+		{Sql: "select id from `user` where id = 3", BindVariables: emptyBV},
+		{Sql: "rollback to b", BindVariables: emptyBV},
+	}
+	utils.MustMatch(t, sbc1WantQueries, sbc1.Queries, "")
+	utils.MustMatch(t, sbc2WantQueries, sbc2.Queries, "")
+	assert.Equal(t, []string{"savepoint b"}, session.SavePoints())
+	assert.EqualValues(t, 2, savepointsReplayed.Get()-replayed)
+	assert.EqualValues(t, 1, savepointStatements.Counts()["Savepoint.Deferred"]-deferred)
+}
+
+func TestExecutorSavepointDoesNotExist(t *testing.T) {
+	executor, _, _, _, _ := createExecutorEnv(t)
+
+	session := NewSafeSession(&vtgatepb.Session{Autocommit: false, TargetString: "@primary"})
+	_, err := exec(executor, session, "savepoint a")
+	require.NoError(t, err)
+	_, err = exec(executor, session, "rollback to b")
+	require.ErrorContains(t, err, "SAVEPOINT does not exist: rollback to b")
+	assert.Equal(t, vterrors.SPDoesNotExist, vterrors.ErrState(err))
+	_, err = exec(executor, session, "release savepoint a")
+	require.NoError(t, err)
+	_, err = exec(executor, session, "release savepoint a")
+	require.ErrorContains(t, err, "SAVEPOINT does not exist: release savepoint a")
+	assert.Empty(t, session.SavePoints())
+}
+
+// TestExecutorSavepointRollbackPartialFailure checks that the transaction is rolled back
+// when a rollback to a savepoint fails on some of its shards only.
+func TestExecutorSavepointRollbackPartialFailure(t *testing.T) {
+	executor, sbc1, sbc2, _, _ := createExecutorEnv(t)
+
+	aborts := savepointRollbackAborts.Get()
+
+	session := NewSafeSession(&vtgatepb.Session{Autocommit: false, TargetString: "@primary"})
+	for _, query := range []string{
+		"select id from user where id = 1",
+		"select id from user where id = 3",
+		"savepoint a",
+	} {
+		_, err := exec(executor, session, query)
+		require.NoError(t, err, query)
+	}
+
+	// the rollback fails on all the shards: they are still consistent.
+	sbc1.MustFailExecute[sqlparser.StmtSRollback] = 1
+	sbc2.MustFailExecute[sqlparser.StmtSRollback] = 1
+	_, err := exec(executor, session, "rollback to a")
+	require.ErrorContains(t, err, "failed query: rollback to a")
+	require.True(t, session.isTxOpen())
+	assert.EqualValues(t, 0, savepointRollbackAborts.Get()-aborts)
+
+	sbc2.MustFailExecute[sqlparser.StmtSRollback] = 1
+	_, err = exec(executor, session, "rollback to a")
+	require.ErrorContains(t, err, "transaction rolled back as the rollback to the savepoint failed on some of the shards")
+	require.False(t, session.isTxOpen())
+	assert.Empty(t, session.SavePoints())
+	assert.EqualValues(t, 1, sbc1.RollbackCount.Load())
+	assert.EqualValues(t, 1, sbc2.RollbackCount.Load())
+	assert.EqualValues(t, 1, savepointRollbackAborts.Get()-aborts)
+}
+
 func TestExecutorCallProc(t *testing.T) {
 	executor, sbc1, sbc2, sbcUnsharded, _ := createExecutorEnv(t)
 
@@ -2730,6 +2848,424 @@ func TestExecutorStartTxnStmt(t *testing.T) {
 	}
 }
 
+func TestExecutorSnapshotReads(t *testing.T) {
+	var (
+		sbcs                               []*sandboxconn.SandboxConn
+		unshardedPrimary, unshardedReplica *sandboxconn.SandboxConn
+	)
+	executor, ctx := createExecutorEnvCallback(t, func(shard, ks string, tabletType topodatapb.TabletType, conn *sandboxconn.SandboxConn) {
+		switch {
+		case ks == KsTestSharded:
+			sbcs = append(sbcs, conn)
+		case tabletType == topodatapb.TabletType_PRIMARY:
+			unshardedPrimary = conn
+		default:
+			unshardedReplica = conn
+		}
+	})
+	queries := func(sbc *sandboxconn.SandboxConn) []string {
+		var queries []string
+		for _, query := range sbc.Queries {
+			queries = append(queries, query.Sql)
+		}
+		sbc.Queries = nil
+		return queries
+	}
+
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: "@primary"})
+	_, err := executor.Execute(ctx, nil, "TestExecutorSnapshotReads", session, "set @@snapshot_reads = 1", nil)
+	require.NoError(t, err)
+	require.True(t, session.GetSnapshotReads())
+
+	qr, err := executor.Execute(ctx, nil, "TestExecutorSnapshotReads", session, "select @@snapshot_reads", nil)
+	require.NoError(t, err)
+	utils.MustMatch(t, [][]sqltypes.Value{{sqltypes.NewInt64(1)}}, qr.Rows)
+
+	// a scatter read on the primaries opens a consistent snapshot on every
+	// shard and releases it afterwards. There is nothing to wait for.
+	_, err = executor.Execute(ctx, nil, "TestExecutorSnapshotReads", session, "select id from user", nil)
+	require.NoError(t, err)
+	for _, sbc := range sbcs {
+		shard := sbc.Tablet().Shard
+		assert.Equal(t, []string{"select id from `user`"}, queries(sbc), shard)
+		assert.EqualValues(t, 0, sbc.ReserveCount.Load(), shard)
+		assert.EqualValues(t, 1, sbc.BeginCount.Load(), shard)
+		assert.EqualValues(t, 1, sbc.ReleaseCount.Load(), shard)
+	}
+	assert.False(t, session.InTransaction())
+	assert.False(t, session.InReservedConn())
+	assert.Empty(t, session.ShardSessions)
+	assert.Empty(t, session.GetOrCreateOptions().TransactionAccessMode)
+
+	// a read that targets a single shard only begins on that shard.
+	_, err = executor.Execute(ctx, nil, "TestExecutorSnapshotReads", session, "select id from user where id = 1", nil)
+	require.NoError(t, err)
+	for _, sbc := range sbcs {
+		var want []string
+		begins := 1
+		if sbc.Tablet().Shard == "-20" {
+			want = []string{"select id from `user` where id = 1"}
+			begins = 2
+		}
+		assert.Equal(t, want, queries(sbc), sbc.Tablet().Shard)
+		assert.EqualValues(t, begins, sbc.BeginCount.Load(), sbc.Tablet().Shard)
+	}
+
+	// a read on a replica waits, on a reserved connection, until the replica
+	// executed what its primary had executed before its snapshot starts.
+	unshardedPrimary.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("@@global.gtid_executed", "varchar"), "uuid:1-5")})
+	session.TargetString = KsTestUnsharded + "@replica"
+	_, err = executor.Execute(ctx, nil, "TestExecutorSnapshotReads", session, "select id from t", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"select @@global.gtid_executed"}, queries(unshardedPrimary))
+	assert.EqualValues(t, 0, unshardedPrimary.BeginCount.Load())
+	assert.Equal(t, []string{"select WAIT_FOR_EXECUTED_GTID_SET('uuid:1-5')", "select id from t"}, queries(unshardedReplica))
+	assert.EqualValues(t, 1, unshardedReplica.ReserveCount.Load())
+	assert.EqualValues(t, 1, unshardedReplica.BeginCount.Load())
+	assert.EqualValues(t, 1, unshardedReplica.ReleaseCount.Load())
+	assert.False(t, session.InTransaction())
+	assert.False(t, session.InReservedConn())
+	session.TargetString = "@primary"
+
+	// reads inside an explicit transaction use the transaction as is.
+	_, err = executor.Execute(ctx, nil, "TestExecutorSnapshotReads", session, "begin", nil)
+	require.NoError(t, err)
+	_, err = executor.Execute(ctx, nil, "TestExecutorSnapshotReads", session, "select id from user", nil)
+	require.NoError(t, err)
+	assert.Empty(t, session.GetOrCreateOptions().TransactionAccessMode)
+	_, err = executor.Execute(ctx, nil, "TestExecutorSnapshotReads", session, "rollback", nil)
+	require.NoError(t, err)
+}
+
+func TestExecutorReadOnlyTransactions(t *testing.T) {
+	executor, primary, replica := createExecutorEnvWithPrimaryReplicaConn(t, context.Background(), 0)
+	ctx := context.Background()
+
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: KsTestUnsharded})
+	exec := func(sql string) error {
+		_, err := executor.Execute(ctx, nil, "TestExecutorReadOnlyTransactions", session, sql, nil)
+		return err
+	}
+	queryCounts := func() (int, int) {
+		defer primary.ClearQueries()
+		defer replica.ClearQueries()
+		return len(primary.Queries), len(replica.Queries)
+	}
+
+	// writes are rejected with the error of MySQL.
+	require.NoError(t, exec("start transaction read only"))
+	err := exec("insert into user(id) values (1)")
+	require.ErrorContains(t, err, "VT09025: Cannot execute statement in a READ ONLY transaction.")
+	sqlErr := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
+	assert.Equal(t, sqlerror.ERCantExecuteInReadOnlyTransaction, sqlErr.Number())
+	assert.Equal(t, sqlerror.SSCantExecuteInReadOnlyTransaction, sqlErr.SQLState())
+
+	// read-only transactions run on the primary by default.
+	require.NoError(t, exec("select id from user"))
+	require.NoError(t, exec("commit"))
+	primaryQueries, replicaQueries := queryCounts()
+	assert.Equal(t, 1, primaryQueries)
+	assert.Zero(t, replicaQueries)
+
+	// they run on the replicas when the session asks for it.
+	require.NoError(t, exec("set @@read_only_transactions_on_replica = 1"))
+	require.True(t, session.GetReadOnlyTxOnReplica())
+	require.NoError(t, exec("start transaction read only"))
+	require.NoError(t, exec("select id from user"))
+	require.Len(t, session.ShardSessions, 1)
+	assert.Equal(t, topodatapb.TabletType_REPLICA, session.ShardSessions[0].Target.TabletType)
+	require.ErrorContains(t, exec("set @@read_only_transactions_on_replica = 0"), "you have an active transaction")
+	require.ErrorContains(t, exec("delete from user"), "VT09025")
+	require.NoError(t, exec("commit"))
+	primaryQueries, replicaQueries = queryCounts()
+	assert.Zero(t, primaryQueries)
+	assert.Equal(t, 1, replicaQueries)
+
+	// read-write transactions, and sessions that target a tablet type, are
+	// not affected.
+	require.NoError(t, exec("start transaction read write"))
+	require.NoError(t, exec("insert into user(id) values (1)"))
+	require.NoError(t, exec("commit"))
+	session.TargetString = KsTestUnsharded + "@primary"
+	require.NoError(t, exec("start transaction read only"))
+	require.NoError(t, exec("select id from user"))
+	require.NoError(t, exec("commit"))
+	primaryQueries, replicaQueries = queryCounts()
+	assert.Equal(t, 2, primaryQueries)
+	assert.Zero(t, replicaQueries)
+}
+
+func TestExecutorMaxReplicaLag(t *testing.T) {
+	executor, primary, replica := createExecutorEnvWithPrimaryReplicaConn(t, context.Background(), 0)
+	ctx := context.Background()
+
+	hc := executor.resolver.resolver.GetGateway().(*TabletGateway).hc
+	th, err := hc.GetTabletHealthByAlias(replica.Tablet().Alias)
+	require.NoError(t, err)
+	th.Stats.ReplicationLagSeconds = 30
+
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: KsTestUnsharded + "@replica"})
+	exec := func(sql string) *sqltypes.Result {
+		qr, err := executor.Execute(ctx, nil, "TestExecutorMaxReplicaLag", session, sql, nil)
+		require.NoError(t, err)
+		return qr
+	}
+	queryCounts := func() (int, int) {
+		defer primary.ClearQueries()
+		defer replica.ClearQueries()
+		return len(primary.Queries), len(replica.Queries)
+	}
+
+	exec("select id from user")
+	primaryQueries, replicaQueries := queryCounts()
+	assert.Zero(t, primaryQueries)
+	assert.Equal(t, 1, replicaQueries)
+
+	// the replica lags too much for the directive, the query falls back to the primary.
+	exec("select /*vt+ MAX_REPLICA_LAG=10 */ id from user")
+	primaryQueries, replicaQueries = queryCounts()
+	assert.Equal(t, 1, primaryQueries)
+	assert.Zero(t, replicaQueries)
+
+	exec("set @@max_replica_lag = 60")
+	qr := exec("select @@max_replica_lag")
+	utils.MustMatch(t, [][]sqltypes.Value{{sqltypes.NewInt64(60)}}, qr.Rows)
+	exec("select id from user")
+	primaryQueries, replicaQueries = queryCounts()
+	assert.Zero(t, primaryQueries)
+	assert.Equal(t, 1, replicaQueries)
+
+	// the comment directive takes precedence over the session value.
+	exec("select /*vt+ MAX_REPLICA_LAG=10 */ id from user")
+	primaryQueries, replicaQueries = queryCounts()
+	assert.Equal(t, 1, primaryQueries)
+	assert.Zero(t, replicaQueries)
+}
+
+func TestExecutorReadWriteSplitting(t *testing.T) {
+	defer func(enabled bool) {
+		readWriteSplitting = enabled
+	}(readWriteSplitting)
+	readWriteSplitting = true
+
+	executor, primary, replica := createExecutorEnvWithPrimaryReplicaConn(t, context.Background(), 0)
+	ctx := context.Background()
+
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: KsTestUnsharded})
+	exec := func(sql string) {
+		_, err := executor.Execute(ctx, nil, "TestExecutorReadWriteSplitting", session, sql, nil)
+		require.NoError(t, err)
+	}
+	queries := func() ([]string, []string) {
+		defer primary.ClearQueries()
+		defer replica.ClearQueries()
+		return primary.StringQueries(), replica.StringQueries()
+	}
+
+	// without any write, the reads go to the replica.
+	exec("select id from user")
+	primaryQueries, replicaQueries := queries()
+	assert.Empty(t, primaryQueries)
+	assert.Equal(t, []string{"select id from `user`"}, replicaQueries)
+
+	// unless they ask for the primary, or take locks.
+	exec("select /*vt+ READ_FROM_PRIMARY */ id from user")
+	exec("select id from user for update")
+	primaryQueries, replicaQueries = queries()
+	assert.Len(t, primaryQueries, 2)
+	assert.Empty(t, replicaQueries)
+
+	// the autocommit writes return the GTID set that the primary executed.
+	gtids := "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5"
+	primary.SetResults([]*sqltypes.Result{{RowsAffected: 1, SessionStateChanges: gtids}})
+	exec("update user set a = 1 where id = 1")
+	assert.True(t, primary.Options[len(primary.Options)-1].TrackGtids)
+	assert.Equal(t, map[string]string{KsTestUnsharded + "/0": gtids}, session.LastWriteGtids)
+	queries()
+
+	// the replica waits to have executed it before it serves the reads.
+	replica.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("wait", "int64"), "0")})
+	exec("select id from user")
+	primaryQueries, replicaQueries = queries()
+	assert.Empty(t, primaryQueries)
+	assert.Equal(t, []string{
+		"select WAIT_FOR_EXECUTED_GTID_SET('3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5', 1)",
+		"select id from `user`",
+	}, replicaQueries)
+	assert.EqualValues(t, 1, replica.ReserveCount.Load())
+	assert.EqualValues(t, 1, replica.ReleaseCount.Load())
+
+	// and the reads fall back to the primary when it doesn't in time.
+	replica.SetResults([]*sqltypes.Result{sqltypes.MakeTestResult(sqltypes.MakeTestFields("wait", "int64"), "1")})
+	exec("select id from user")
+	primaryQueries, replicaQueries = queries()
+	assert.Equal(t, []string{"select id from `user`"}, primaryQueries)
+	assert.Len(t, replicaQueries, 1)
+	assert.EqualValues(t, 2, replica.ReleaseCount.Load())
+
+	// the GTID set of the writes of a transaction is not known, so the
+	// reads that follow them stay on the primary.
+	exec("begin")
+	exec("update user set a = 1 where id = 1")
+	exec("commit")
+	assert.Equal(t, map[string]string{KsTestUnsharded + "/0": ""}, session.LastWriteGtids)
+	queries()
+	exec("select id from user")
+	primaryQueries, replicaQueries = queries()
+	assert.Equal(t, []string{"select id from `user`"}, primaryQueries)
+	assert.Empty(t, replicaQueries)
+
+	// the reads of transactions stay on the primary.
+	session.LastWriteGtids = nil
+	exec("begin")
+	exec("select id from user")
+	exec("rollback")
+	primaryQueries, replicaQueries = queries()
+	assert.Len(t, primaryQueries, 1)
+	assert.Empty(t, replicaQueries)
+}
+
+func TestExecutorTenantRouting(t *testing.T) {
+	executor, sbc1, sbc2, sbclookup, ctx := createExecutorEnv(t)
+	executor.vschema.TenantRoutingRules = map[string]string{
+		"acme":   KsTestUnsharded,
+		"globex": KsTestSharded + ":-20",
+	}
+
+	session := NewAutocommitSession(&vtgatepb.Session{})
+	exec := func(sql string) error {
+		_, err := executor.Execute(ctx, nil, "TestExecutorTenantRouting", session, sql, nil)
+		return err
+	}
+	queryCounts := func() (int, int, int) {
+		defer sbc1.ClearQueries()
+		defer sbc2.ClearQueries()
+		defer sbclookup.ClearQueries()
+		return len(sbc1.Queries), len(sbc2.Queries), len(sbclookup.Queries)
+	}
+
+	// without a tenant, the query is scattered to all the shards.
+	require.NoError(t, exec("select id from user"))
+	c1, c2, clookup := queryCounts()
+	assert.Equal(t, []int{1, 1, 0}, []int{c1, c2, clookup})
+
+	// the tenant moves the session to its keyspace.
+	require.NoError(t, exec("set @@tenant_id = 'acme'"))
+	assert.Equal(t, "acme", session.GetTenantID())
+	require.NoError(t, exec("select id from user"))
+	c1, c2, clookup = queryCounts()
+	assert.Equal(t, []int{0, 0, 1}, []int{c1, c2, clookup})
+
+	// or to its shard.
+	require.NoError(t, exec("set @@tenant_id = 'globex'"))
+	require.NoError(t, exec("select id from user"))
+	c1, c2, clookup = queryCounts()
+	assert.Equal(t, []int{1, 0, 0}, []int{c1, c2, clookup})
+
+	// the tablet type still comes from the target of the session.
+	session.TargetString = "@primary"
+	require.NoError(t, exec("begin"))
+	require.NoError(t, exec("select id from user"))
+	require.Len(t, session.ShardSessions, 1)
+	assert.Equal(t, topodatapb.TabletType_PRIMARY, session.ShardSessions[0].Target.TabletType)
+	assert.Equal(t, "-20", session.ShardSessions[0].Target.Shard)
+	require.ErrorContains(t, exec("set @@tenant_id = 'acme'"), "you have an active transaction")
+	require.NoError(t, exec("rollback"))
+	session.TargetString = ""
+
+	// unknown tenants are rejected, and a tenant whose rule was removed can't
+	// run queries anymore.
+	require.ErrorContains(t, exec("set @@tenant_id = 'initech'"), "unknown tenant: initech")
+	assert.Equal(t, "globex", session.GetTenantID())
+	delete(executor.vschema.TenantRoutingRules, "globex")
+	require.ErrorContains(t, exec("select id from user"), "no tenant routing rule for tenant 'globex'")
+	require.NoError(t, exec("set @@tenant_id = ''"))
+	require.NoError(t, exec("select id from user"))
+	queryCounts()
+}
+
+func TestExecutorStreamChunkOptions(t *testing.T) {
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: "@primary"})
+	streamExecute := func(sql string) *querypb.ExecuteOptions {
+		sbc1.Options = nil
+		err := executor.StreamExecute(ctx, nil, "TestExecutorStreamChunkOptions", session, sql, nil, func(*sqltypes.Result) error {
+			return nil
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, sbc1.Options)
+		return sbc1.Options[0]
+	}
+
+	options := streamExecute("select id from user")
+	assert.Zero(t, options.GetStreamChunkRows())
+	assert.Zero(t, options.GetStreamChunkTimeoutMs())
+
+	// the session values are sent with every query.
+	_, err := executor.Execute(ctx, nil, "TestExecutorStreamChunkOptions", session, "set @@stream_chunk_rows = 1000, @@stream_chunk_timeout = 500", nil)
+	require.NoError(t, err)
+	qr, err := executor.Execute(ctx, nil, "TestExecutorStreamChunkOptions", session, "select @@stream_chunk_rows, @@stream_chunk_timeout", nil)
+	require.NoError(t, err)
+	utils.MustMatch(t, [][]sqltypes.Value{{sqltypes.NewInt64(1000), sqltypes.NewInt64(500)}}, qr.Rows)
+	options = streamExecute("select id from user")
+	assert.EqualValues(t, 1000, options.StreamChunkRows)
+	assert.EqualValues(t, 500, options.StreamChunkTimeoutMs)
+
+	// the comment directives take precedence over the session values, for that query only.
+	options = streamExecute("select /*vt+ STREAM_CHUNK_ROWS=10 */ id from user")
+	assert.EqualValues(t, 10, options.StreamChunkRows)
+	assert.EqualValues(t, 500, options.StreamChunkTimeoutMs)
+	options = streamExecute("select id from user")
+	assert.EqualValues(t, 1000, options.StreamChunkRows)
+}
+
+func TestExecutorErrorPositions(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: "@primary"})
+
+	_, err := executor.Execute(ctx, nil, "TestExecutorErrorPositions", session, "select id, json_arrayagg(col) from user", nil)
+	require.EqualError(t, err, "VT12001: unsupported: in scatter query: aggregation function 'json_arrayagg(col)' (near character 12)")
+	require.Equal(t, vtrpcpb.Code_UNIMPLEMENTED, vterrors.Code(err))
+
+	// the positions are in the query text that was sent, including the margin comments.
+	_, err = executor.Execute(ctx, nil, "TestExecutorErrorPositions", session, "/* leading */ select id, json_arrayagg(col) from user", nil)
+	require.EqualError(t, err, "VT12001: unsupported: in scatter query: aggregation function 'json_arrayagg(col)' (near character 26)")
+}
+
+func TestExecutorQuotaRules(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+
+	srvVSchema := executor.vm.GetCurrentSrvVschema().CloneVT()
+	srvVSchema.QuotaRules = &vschemapb.QuotaRules{
+		Rules: []*vschemapb.QuotaRule{{
+			Name:     "user1",
+			User:     "user1",
+			Keyspace: KsTestSharded,
+			MaxQps:   0.001,
+			Burst:    1,
+		}},
+	}
+	executor.vm.VSchemaUpdate(srvVSchema, nil)
+
+	ctx = callerid.NewContext(ctx, nil, &querypb.VTGateCallerID{Username: "user1"})
+	session := NewAutocommitSession(&vtgatepb.Session{TargetString: "@primary"})
+	_, err := executor.Execute(ctx, nil, "TestExecutorQuotaRules", session, "select id from user", nil)
+	require.NoError(t, err)
+
+	// the burst of the rule is exhausted.
+	_, err = executor.Execute(ctx, nil, "TestExecutorQuotaRules", session, "select id from user where id = 1", nil)
+	require.EqualError(t, err, "VT08001: quota 'user1' exceeded: rate limit of 0.001 queries per second")
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+
+	// queries on other keyspaces and of other users are not limited.
+	_, err = executor.Execute(ctx, nil, "TestExecutorQuotaRules", session, "select * from main1", nil)
+	require.NoError(t, err)
+	otherCtx := callerid.NewContext(ctx, nil, &querypb.VTGateCallerID{Username: "user2"})
+	_, err = executor.Execute(otherCtx, nil, "TestExecutorQuotaRules", session, "select id from user", nil)
+	require.NoError(t, err)
+}
+
 func TestExecutorPrepareExecute(t *testing.T) {
 	executor, _, _, _, _ := createExecutorEnv(t)
 
@@ -2937,3 +3473,54 @@ func exec(executor *Executor, session *SafeSession, sql string) (*sqltypes.Resul
 func makeComments(text string) sqlparser.MarginComments {
 	return sqlparser.MarginComments{Trailing: text}
 }
+
+func TestExecutorRoutingRuleTarget(t *testing.T) {
+	executor, sbc1, _, sbclookup, ctx := createExecutorEnv(t)
+
+	applyRule := func(rule *vschemapb.RoutingRule) {
+		srvVSchema := proto.Clone(executor.vm.GetCurrentSrvVschema()).(*vschemapb.SrvVSchema)
+		srvVSchema.RoutingRules = &vschemapb.RoutingRules{Rules: []*vschemapb.RoutingRule{rule}}
+		executor.vm.VSchemaUpdate(srvVSchema, nil)
+	}
+	exec := func(session *vtgatepb.Session, sql string) (toTarget bool) {
+		sbc1.Queries = nil
+		sbclookup.Queries = nil
+		_, err := executorExec(ctx, executor, session, sql, nil)
+		require.NoError(t, err)
+		require.NotEqual(t, len(sbc1.Queries) > 0, len(sbclookup.Queries) > 0, sql)
+		return len(sbc1.Queries) > 0
+	}
+
+	// All the reads outside of a transaction are routed to the target.
+	applyRule(&vschemapb.RoutingRule{
+		FromTable:         "moved",
+		ToTables:          []string{"TestUnsharded.main1"},
+		TargetTable:       "TestExecutor.user",
+		TargetReadPercent: 100,
+	})
+	session := &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+	assert.True(t, exec(session, "select id from moved where id = 1"))
+	assert.False(t, exec(session, "update moved set a = 2 where id = 1"))
+
+	txSession := &vtgatepb.Session{TargetString: "@primary", InTransaction: true}
+	assert.False(t, exec(txSession, "select id from moved where id = 1"))
+
+	// The reads keep going to the source before the cutover time.
+	applyRule(&vschemapb.RoutingRule{
+		FromTable:   "moved",
+		ToTables:    []string{"TestUnsharded.main1"},
+		TargetTable: "TestExecutor.user",
+		CutoverTime: protoutil.TimeToProto(time.Now().Add(time.Hour)),
+	})
+	assert.False(t, exec(session, "select id from moved where id = 1"))
+
+	// And everything goes to the target once it is passed.
+	applyRule(&vschemapb.RoutingRule{
+		FromTable:   "moved",
+		ToTables:    []string{"TestUnsharded.main1"},
+		TargetTable: "TestExecutor.user",
+		CutoverTime: protoutil.TimeToProto(time.Now()),
+	})
+	assert.True(t, exec(session, "select id from moved where id = 1"))
+	assert.True(t, exec(session, "update moved set a = 2 where id = 1"))
+}
@@ -37,6 +37,7 @@ import (
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate"
+	"vitess.io/vitess/go/vt/vtgate/arrowipc"
 	"vitess.io/vitess/go/vt/vtgate/vtgateservice"
 )
 
@@ -187,26 +188,49 @@ func (vtg *VTGate) StreamExecute(request *vtgatepb.StreamExecuteRequest, stream
 		session = &vtgatepb.Session{Autocommit: true}
 	}
 
-	session, vtgErr := vtg.server.StreamExecute(ctx, nil, session, request.Query.Sql, request.Query.BindVariables, func(value *sqltypes.Result) error {
+	callback := func(value *sqltypes.Result) error {
 		// Send is not safe to call concurrently, but vtgate
 		// guarantees that it's not.
 		return stream.Send(&vtgatepb.StreamExecuteResponse{
 			Result: sqltypes.ResultToProto3(value),
 		})
-	})
+	}
+	var arrowWriter *arrowipc.Writer
+	if request.ResultFormat == vtgatepb.ResultFormat_ARROW_IPC {
+		arrowWriter = arrowipc.NewWriter()
+		callback = func(value *sqltypes.Result) error {
+			b, err := arrowWriter.Write(value)
+			if err != nil || len(b) == 0 {
+				return err
+			}
+			return stream.Send(&vtgatepb.StreamExecuteResponse{
+				ArrowIpc: b,
+			})
+		}
+	}
+
+	session, vtgErr := vtg.server.StreamExecute(ctx, nil, session, request.Query.Sql, request.Query.BindVariables, callback)
 
 	var errs []error
 	if vtgErr != nil {
 		errs = append(errs, vtgErr)
 	}
 
+	var last *vtgatepb.StreamExecuteResponse
+	if arrowWriter != nil && vtgErr == nil {
+		// The end of the Arrow stream is sent in the last stream response.
+		last = &vtgatepb.StreamExecuteResponse{ArrowIpc: arrowWriter.Close()}
+	}
 	if sendSessionInStreaming {
 		// even if there is an error, session could have been modified.
 		// So, this needs to be sent back to the client. Session is sent in the last stream response.
-		lastErr := stream.Send(&vtgatepb.StreamExecuteResponse{
-			Session: session,
-		})
-		if lastErr != nil {
+		if last == nil {
+			last = &vtgatepb.StreamExecuteResponse{}
+		}
+		last.Session = session
+	}
+	if last != nil {
+		if lastErr := stream.Send(last); lastErr != nil {
 			errs = append(errs, lastErr)
 		}
 	}
@@ -57,6 +57,10 @@ type LogStats struct {
 	SessionUUID    string
 	CachedPlan     bool
 	ActiveKeyspace string // ActiveKeyspace is the selected keyspace `use ks`
+
+	// QueryAttributes are the query attributes the client sent along
+	// with the query.
+	QueryAttributes map[string]string
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -152,9 +156,9 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	var fmtString string
 	switch streamlog.GetQueryLogFormat() {
 	case streamlog.QueryLogFormatText:
-		fmtString = "%v\t%v\t%v\t'%v'\t'%v'\t%v\t%v\t%.6f\t%.6f\t%.6f\t%.6f\t%v\t%q\t%v\t%v\t%v\t%q\t%q\t%q\t%v\t%v\t%q\n"
+		fmtString = "%v\t%v\t%v\t'%v'\t'%v'\t%v\t%v\t%.6f\t%.6f\t%.6f\t%.6f\t%v\t%q\t%v\t%v\t%v\t%q\t%q\t%q\t%v\t%v\t%q\t%v\n"
 	case streamlog.QueryLogFormatJSON:
-		fmtString = "{\"Method\": %q, \"RemoteAddr\": %q, \"Username\": %q, \"ImmediateCaller\": %q, \"Effective Caller\": %q, \"Start\": \"%v\", \"End\": \"%v\", \"TotalTime\": %.6f, \"PlanTime\": %v, \"ExecuteTime\": %v, \"CommitTime\": %v, \"StmtType\": %q, \"SQL\": %q, \"BindVars\": %v, \"ShardQueries\": %v, \"RowsAffected\": %v, \"Error\": %q, \"TabletType\": %q, \"SessionUUID\": %q, \"Cached Plan\": %v, \"TablesUsed\": %v, \"ActiveKeyspace\": %q}\n"
+		fmtString = "{\"Method\": %q, \"RemoteAddr\": %q, \"Username\": %q, \"ImmediateCaller\": %q, \"Effective Caller\": %q, \"Start\": \"%v\", \"End\": \"%v\", \"TotalTime\": %.6f, \"PlanTime\": %v, \"ExecuteTime\": %v, \"CommitTime\": %v, \"StmtType\": %q, \"SQL\": %q, \"BindVars\": %v, \"ShardQueries\": %v, \"RowsAffected\": %v, \"Error\": %q, \"TabletType\": %q, \"SessionUUID\": %q, \"Cached Plan\": %v, \"TablesUsed\": %v, \"ActiveKeyspace\": %q, \"QueryAttributes\": %v}\n"
 	}
 
 	tables := stats.TablesUsed
@@ -165,6 +169,14 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	if marshalErr != nil {
 		return marshalErr
 	}
+	attributes := stats.QueryAttributes
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	queryAttributes, marshalErr := json.Marshal(attributes)
+	if marshalErr != nil {
+		return marshalErr
+	}
 	_, err := fmt.Fprintf(
 		w,
 		fmtString,
@@ -190,6 +202,7 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 		stats.CachedPlan,
 		string(tablesUsed),
 		stats.ActiveKeyspace,
+		string(queryAttributes),
 	)
 
 	return err
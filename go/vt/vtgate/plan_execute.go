@@ -19,10 +19,12 @@ package vtgate
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/log"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -80,11 +82,24 @@ func (e *Executor) newExecute(
 	query, comments := sqlparser.SplitMarginComments(sql)
 
 	// 2: Parse and Validate query
-	stmt, reservedVars, err := parseAndValidateQuery(query, e.env.Parser())
+	stmt, reservedVars, positions, err := parseAndValidateQuery(sql, query, e.env.Parser())
 	if err != nil {
 		return err
 	}
 
+	// MySQL rejects the writes of a read-only transaction. This has to be
+	// checked before planning, since the transaction may be on the replicas.
+	if sqlparser.IsDMLStatement(stmt) && safeSession.InReadOnlyTransaction() {
+		return vterrors.VT09025()
+	}
+
+	// Read/write splitting serves the reads that follow a write with the
+	// replicas that executed it, so it records the GTID sets of the writes.
+	if readWriteSplitting && mayWrite(stmt) {
+		safeSession.SetTrackWrites(true)
+		defer safeSession.SetTrackWrites(false)
+	}
+
 	var lastVSchemaCreated time.Time
 	vs := e.VSchema()
 	lastVSchemaCreated = vs.GetCreated()
@@ -97,10 +112,15 @@ func (e *Executor) newExecute(
 			}
 		}
 
+		if err := checkTenantRoutingRule(safeSession, vs, stmt); err != nil {
+			return err
+		}
+
 		vcursor, err := newVCursorImpl(safeSession, comments, e, logStats, e.vm, vs, e.resolver.resolver, e.serv, e.warnShardedOnly, e.pv)
 		if err != nil {
 			return err
 		}
+		vcursor.SetQueryPositions(positions)
 
 		// 3: Create a plan for the query
 		// If we are retrying, it is likely that the routing rules have changed and hence we need to
@@ -143,16 +163,30 @@ func (e *Executor) newExecute(
 			logStats.Error = err
 			return err
 		}
+		plan.CoerceBindVars(bindVars)
 
-		// 5: Execute the plan and retry if needed
+		// 5: Enforce the quota rules
+		release, err := e.acquireQuota(ctx, safeSession, plan, vcursor)
+		if err != nil {
+			logStats.Error = err
+			return err
+		}
+
+		// 6: Execute the plan and retry if needed
 		if plan.Instructions.NeedsTransaction() {
 			err = e.insideTransaction(ctx, safeSession, logStats,
 				func() error {
 					return execPlan(ctx, plan, vcursor, bindVars, execStart)
 				})
+		} else if plan.Type == sqlparser.StmtSelect && safeSession.GetSnapshotReads() && !safeSession.InTransaction() {
+			err = e.insideSnapshotRead(ctx, safeSession,
+				func() error {
+					return execPlan(ctx, plan, vcursor, bindVars, execStart)
+				})
 		} else {
 			err = execPlan(ctx, plan, vcursor, bindVars, execStart)
 		}
+		release()
 
 		if err == nil || safeSession.InTransaction() {
 			return err
@@ -170,6 +204,24 @@ func (e *Executor) newExecute(
 	return vterrors.New(vtrpcpb.Code_INTERNAL, fmt.Sprintf("query %s failed after retries: %v ", query, err))
 }
 
+// acquireQuota admits the execution of the plan according to the quota rules.
+// The keyspaces of the plan are the ones of the tables it uses, or the keyspace
+// of the session when it does not use any table.
+func (e *Executor) acquireQuota(ctx context.Context, safeSession *SafeSession, plan *engine.Plan, vcursor *vcursorImpl) (func(), error) {
+	var keyspaces []string
+	for _, table := range plan.TablesUsed {
+		keyspace, _, found := strings.Cut(table, ".")
+		if found && !slices.Contains(keyspaces, keyspace) {
+			keyspaces = append(keyspaces, keyspace)
+		}
+	}
+	if len(keyspaces) == 0 && vcursor.keyspace != "" {
+		keyspaces = append(keyspaces, vcursor.keyspace)
+	}
+	user := callerid.ImmediateCallerIDFromContext(ctx).GetUsername()
+	return e.quotas.Acquire(user, keyspaces, safeSession.GetOptions().GetWorkloadName())
+}
+
 // handleTransactions deals with transactional queries: begin, commit, rollback and savepoint management
 func (e *Executor) handleTransactions(
 	ctx context.Context,
@@ -193,19 +245,19 @@ func (e *Executor) handleTransactions(
 		qr, err := e.handleRollback(ctx, safeSession, logStats)
 		return qr, err
 	case sqlparser.StmtSavepoint:
-		qr, err := e.handleSavepoint(ctx, safeSession, plan.Original, "Savepoint", logStats, func(_ string) (*sqltypes.Result, error) {
+		qr, err := e.handleSavepoint(ctx, safeSession, stmt, plan.Original, "Savepoint", logStats, func(_ string) (*sqltypes.Result, error) {
 			// Safely to ignore as there is no transaction.
 			return &sqltypes.Result{}, nil
 		}, vcursor.ignoreMaxMemoryRows)
 		return qr, err
 	case sqlparser.StmtSRollback:
-		qr, err := e.handleSavepoint(ctx, safeSession, plan.Original, "Rollback Savepoint", logStats, func(query string) (*sqltypes.Result, error) {
+		qr, err := e.handleSavepoint(ctx, safeSession, stmt, plan.Original, "Rollback Savepoint", logStats, func(query string) (*sqltypes.Result, error) {
 			// Error as there is no transaction, so there is no savepoint that exists.
 			return nil, vterrors.NewErrorf(vtrpcpb.Code_NOT_FOUND, vterrors.SPDoesNotExist, "SAVEPOINT does not exist: %s", query)
 		}, vcursor.ignoreMaxMemoryRows)
 		return qr, err
 	case sqlparser.StmtRelease:
-		qr, err := e.handleSavepoint(ctx, safeSession, plan.Original, "Release Savepoint", logStats, func(query string) (*sqltypes.Result, error) {
+		qr, err := e.handleSavepoint(ctx, safeSession, stmt, plan.Original, "Release Savepoint", logStats, func(query string) (*sqltypes.Result, error) {
 			// Error as there is no transaction, so there is no savepoint that exists.
 			return nil, vterrors.NewErrorf(vtrpcpb.Code_NOT_FOUND, vterrors.SPDoesNotExist, "SAVEPOINT does not exist: %s", query)
 		}, vcursor.ignoreMaxMemoryRows)
@@ -225,6 +277,38 @@ func (e *Executor) startTxIfNecessary(ctx context.Context, safeSession *SafeSess
 	return nil
 }
 
+// insideSnapshotRead runs a read in a short-lived read-only transaction that is
+// started WITH CONSISTENT SNAPSHOT on every shard the read touches, so that
+// all the statements the read runs on a shard see the same state. A replica
+// first waits until it executed every transaction its primary had executed
+// when the read reached the shard, so it does not read a state older than the
+// primary's. The shards are not read at a common point in time: a transaction
+// that spans several shards can be seen on some of them only. The transaction
+// is always rolled back afterwards, since nothing was written.
+func (e *Executor) insideSnapshotRead(ctx context.Context, safeSession *SafeSession, execPlan func() error) error {
+	txAccessModes := []sqlparser.TxAccessMode{sqlparser.WithConsistentSnapshot, sqlparser.ReadOnly}
+	if err := e.txConn.Begin(ctx, safeSession, txAccessModes); err != nil {
+		return err
+	}
+	safeSession.SetSnapshotRead(true)
+	defer safeSession.SetSnapshotRead(false)
+
+	err := execPlan()
+
+	// The connections reserved for the read are released with the
+	// transaction, unless the session already used reserved connections.
+	var endErr error
+	if safeSession.InReservedConn() {
+		endErr = e.txConn.Rollback(ctx, safeSession)
+	} else {
+		endErr = e.txConn.Release(ctx, safeSession)
+	}
+	if err == nil {
+		err = endErr
+	}
+	return err
+}
+
 func (e *Executor) insideTransaction(ctx context.Context, safeSession *SafeSession, logStats *logstats.LogStats, execPlan func() error) error {
 	mustCommit := false
 	if safeSession.Autocommit && !safeSession.InTransaction() {
@@ -278,7 +362,9 @@ func (e *Executor) executePlan(
 ) (*sqltypes.Result, error) {
 
 	// 4: Execute!
-	qr, err := vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
+	qr, err := e.readRetry.execute(ctx, plan, safeSession, func() (*sqltypes.Result, error) {
+		return vcursor.ExecutePrimitive(ctx, plan.Instructions, bindVars, true)
+	})
 
 	// 5: Log and add statistics
 	e.setLogStats(logStats, plan, vcursor, execStart, err, qr)
@@ -344,6 +430,7 @@ func (e *Executor) setLogStats(logStats *logstats.LogStats, plan *engine.Plan, v
 	logStats.TabletType = vcursor.TabletType().String()
 	errCount := e.logExecutionEnd(logStats, execStart, plan, err, qr)
 	plan.AddStats(1, time.Since(logStats.StartTime), logStats.ShardQueries, logStats.RowsAffected, logStats.RowsReturned, errCount)
+	plan.SetLastError(err)
 }
 
 func (e *Executor) logExecutionEnd(logStats *logstats.LogStats, execStart time.Time, plan *engine.Plan, err error, qr *sqltypes.Result) uint64 {
@@ -370,3 +457,13 @@ func (e *Executor) logPlanningFinished(logStats *logstats.LogStats, plan *engine
 	logStats.PlanTime = execStart.Sub(logStats.StartTime)
 	return execStart
 }
+
+// mayWrite returns true if stmt may write data.
+func mayWrite(stmt sqlparser.Statement) bool {
+	switch sqlparser.ASTToStatementType(stmt) {
+	case sqlparser.StmtInsert, sqlparser.StmtReplace, sqlparser.StmtUpdate, sqlparser.StmtDelete,
+		sqlparser.StmtDDL, sqlparser.StmtPriv, sqlparser.StmtCallProc, sqlparser.StmtExecute, sqlparser.StmtRevert:
+		return true
+	}
+	return false
+}
@@ -181,6 +181,8 @@ func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Stat
 		return buildShowThrottledAppsPlan(query, vschema)
 	case *sqlparser.ShowThrottlerStatus:
 		return buildShowThrottlerStatusPlan(query, vschema)
+	case *sqlparser.ResetVitessPlans:
+		return newPlanResult(&engine.ResetPlans{}), nil
 	case *sqlparser.AlterVschema:
 		return buildVSchemaDDLPlan(stmt, vschema)
 	case *sqlparser.Use:
@@ -198,7 +200,7 @@ func createInstructionFor(ctx context.Context, query string, stmt sqlparser.Stat
 	case *sqlparser.Set:
 		return buildSetPlan(stmt, vschema)
 	case *sqlparser.Load:
-		return buildLoadPlan(query, vschema)
+		return buildLoadPlan(stmt, query, vschema)
 	case sqlparser.DBDDLStatement:
 		return buildRoutePlan(stmt, reservedVars, vschema, buildDBDDLPlan)
 	case *sqlparser.Begin, *sqlparser.Commit, *sqlparser.Rollback,
@@ -310,7 +312,11 @@ func buildDBDDLPlan(stmt sqlparser.Statement, _ *sqlparser.ReservedVars, vschema
 	return nil, vterrors.VT13001(fmt.Sprintf("database DDL not recognized: %s", sqlparser.String(dbDDLstmt)))
 }
 
-func buildLoadPlan(query string, vschema plancontext.VSchema) (*planResult, error) {
+func buildLoadPlan(stmt *sqlparser.Load, query string, vschema plancontext.VSchema) (*planResult, error) {
+	if stmt.Local {
+		return buildLoadDataLocalPlan(stmt, vschema)
+	}
+
 	keyspace, err := vschema.DefaultKeyspace()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"strconv"
+	"strings"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// buildLoadDataLocalPlan builds the plan of a LOAD DATA LOCAL INFILE
+// statement: vtgate reads the file from the client, and inserts its rows
+// into the shards that the primary vindex maps them to.
+func buildLoadDataLocalPlan(stmt *sqlparser.Load, vschema plancontext.VSchema) (*planResult, error) {
+	vschemaTable, _, _, dest, err := vschema.FindTable(stmt.Table)
+	if err != nil {
+		return nil, err
+	}
+	if vschemaTable == nil {
+		return nil, vterrors.VT05004(stmt.Table.Name.String())
+	}
+	switch stmt.Charset.Name {
+	case "", "utf8mb4", "utf8mb3", "utf8":
+	default:
+		return nil, vterrors.VT12001("LOAD DATA LOCAL INFILE with character set " + stmt.Charset.Name)
+	}
+
+	format, err := loadDataFormat(stmt)
+	if err != nil {
+		return nil, err
+	}
+	ignoreLines := 0
+	if stmt.IgnoreLines != nil {
+		ignoreLines, err = strconv.Atoi(stmt.IgnoreLines.Val)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	columns := stmt.Columns
+	if len(columns) == 0 && vschemaTable.ColumnListAuthoritative {
+		for _, col := range vschemaTable.Columns {
+			columns = append(columns, col.Name)
+		}
+	}
+
+	ld := &engine.LoadData{
+		Keyspace:          vschemaTable.Keyspace,
+		TargetDestination: dest,
+		TableName:         vschemaTable.Name.String(),
+		FileName:          stmt.File,
+		Format:            format,
+		IgnoreLines:       ignoreLines,
+		Columns:           len(columns),
+		// With LOCAL, the server cannot stop the client from sending the
+		// file, so duplicates are skipped as with IGNORE.
+		Ignore:  !stmt.Replace,
+		Replace: stmt.Replace,
+	}
+	if dest == nil && vschemaTable.Keyspace.Sharded {
+		if err := setLoadDataVindex(ld, vschemaTable, columns, stmt.SetExprs); err != nil {
+			return nil, err
+		}
+	}
+
+	insertColumns := columns
+	var suffix strings.Builder
+	for _, expr := range stmt.SetExprs {
+		insertColumns = append(insertColumns, expr.Name.Name)
+		suffix.WriteString(", ")
+		suffix.WriteString(sqlparser.String(expr.Expr))
+	}
+	ld.RowSuffix = suffix.String()
+
+	buf := sqlparser.NewTrackedBuffer(nil)
+	if stmt.Replace {
+		buf.WriteString("replace ")
+	} else {
+		buf.WriteString("insert ignore ")
+	}
+	buf.Myprintf("into %v", sqlparser.TableName{Name: vschemaTable.Name})
+	if len(insertColumns) > 0 {
+		buf.Myprintf("%v", insertColumns)
+	}
+	buf.WriteString(" values ")
+	ld.Prefix = buf.String()
+
+	return newPlanResult(ld, singleTable(vschemaTable.Keyspace.Name, vschemaTable.Name.String())), nil
+}
+
+// setLoadDataVindex sets the primary vindex that maps the rows of a LOAD DATA
+// statement to their shards, and the offsets of its columns in the rows.
+func setLoadDataVindex(ld *engine.LoadData, vschemaTable *vindexes.Table, columns sqlparser.Columns, setExprs sqlparser.UpdateExprs) error {
+	if vschemaTable.Type == vindexes.TypeReference {
+		return vterrors.VT12001("LOAD DATA LOCAL INFILE into a reference table of a sharded keyspace")
+	}
+	if len(vschemaTable.ColumnVindexes) == 0 {
+		return vterrors.VT09001(vschemaTable.Name)
+	}
+	if len(vschemaTable.Owned) > 0 {
+		return vterrors.VT12001("LOAD DATA LOCAL INFILE into a table with owned vindexes")
+	}
+	if vschemaTable.AutoIncrement != nil {
+		return vterrors.VT12001("LOAD DATA LOCAL INFILE into a table with an auto-increment sequence")
+	}
+	if len(columns) == 0 {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "LOAD DATA LOCAL INFILE into a sharded table should contain a column list or the table should have authoritative columns in vschema")
+	}
+
+	primary := vschemaTable.ColumnVindexes[0]
+	for _, col := range primary.Columns {
+		for _, expr := range setExprs {
+			if expr.Name.Name.Equal(col) {
+				return vterrors.VT12001("LOAD DATA LOCAL INFILE that sets the sharding column " + col.String())
+			}
+		}
+		offset := columns.FindColumn(col)
+		if offset < 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the columns of LOAD DATA LOCAL INFILE must contain the sharding column %s", col.String())
+		}
+		ld.VindexOffsets = append(ld.VindexOffsets, offset)
+	}
+	ld.Vindex = primary.Vindex
+	return nil
+}
+
+// loadDataFormat returns the format of the file of a LOAD DATA statement.
+func loadDataFormat(stmt *sqlparser.Load) (engine.LoadDataFormat, error) {
+	format := engine.DefaultLoadDataFormat
+	if fields := stmt.Fields; fields != nil {
+		if fields.TerminatedBy != nil {
+			format.FieldsTerminatedBy = fields.TerminatedBy.Val
+		}
+		if fields.EnclosedBy != nil {
+			format.EnclosedBy = fields.EnclosedBy.Val
+		}
+		if fields.EscapedBy != nil {
+			format.EscapedBy = fields.EscapedBy.Val
+		}
+	}
+	if lines := stmt.Lines; lines != nil {
+		if lines.StartingBy != nil {
+			format.LinesStartingBy = lines.StartingBy.Val
+		}
+		if lines.TerminatedBy != nil {
+			format.LinesTerminatedBy = lines.TerminatedBy.Val
+		}
+	}
+
+	if len(format.EnclosedBy) > 1 || len(format.EscapedBy) > 1 {
+		return format, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "Field separator argument is not what is expected; check the manual")
+	}
+	if format.FieldsTerminatedBy == "" && format.EnclosedBy == "" {
+		return format, vterrors.VT12001("LOAD DATA LOCAL INFILE with fixed-row format")
+	}
+	if format.LinesTerminatedBy == "" {
+		return format, vterrors.VT12001("LOAD DATA LOCAL INFILE with empty LINES TERMINATED BY")
+	}
+	return format, nil
+}
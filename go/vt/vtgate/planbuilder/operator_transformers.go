@@ -47,6 +47,8 @@ func transformToLogicalPlan(ctx *plancontext.PlanningContext, op operators.Opera
 		return transformUnionPlan(ctx, op)
 	case *operators.Vindex:
 		return transformVindexPlan(ctx, op)
+	case *operators.VitessMetadata:
+		return transformVitessMetadata(ctx, op)
 	case *operators.SubQuery:
 		return transformSubQuery(ctx, op)
 	case *operators.Filter:
@@ -172,6 +174,9 @@ func transformInsertionSelection(ctx *plancontext.PlanningContext, op *operators
 		},
 		VindexValueOffset: ins.VindexValueOffset,
 	}
+	if hints := getHints(rb.Comments); hints != nil {
+		eins.BatchRows = hints.insertBatchRows
+	}
 	lp := &insert{eInsertSelect: eins}
 
 	eins.Prefix, _, eins.Suffix = generateInsertShardedQuery(ins.AST)
@@ -285,7 +290,7 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 
 	for _, aggr := range op.Aggregations {
 		if aggr.OpCode == opcode.AggregateUnassigned {
-			return nil, vterrors.VT12001(fmt.Sprintf("in scatter query: aggregation function '%s'", sqlparser.String(aggr.Original)))
+			return nil, ctx.SemTable.ErrorAt(aggr.Original, vterrors.VT12001(fmt.Sprintf("in scatter query: aggregation function '%s'", sqlparser.String(aggr.Original))))
 		}
 		aggrParam := engine.NewAggregateParam(aggr.OpCode, aggr.ColOffset, aggr.Alias, ctx.VSchema.Environment().CollationEnv())
 		aggrParam.Expr = aggr.Func
@@ -293,6 +298,16 @@ func transformAggregator(ctx *plancontext.PlanningContext, op *operators.Aggrega
 		aggrParam.OrigOpcode = aggr.OriginalOpCode
 		aggrParam.WCol = aggr.WSOffset
 		aggrParam.Type = aggr.GetTypeCollation(ctx)
+		if gc, ok := aggr.Func.(*sqlparser.GroupConcatExpr); ok {
+			if gc.Separator != "" {
+				separator, err := sqltypes.DecodeStringSQL(gc.Separator)
+				if err != nil {
+					return nil, err
+				}
+				aggrParam.Separator = &separator
+			}
+			aggrParam.Distinct = aggr.Distinct
+		}
 		oa.aggregates = append(oa.aggregates, aggrParam)
 	}
 	for _, groupBy := range op.Grouping {
@@ -514,7 +529,8 @@ func newRoutingParams(ctx *plancontext.PlanningContext, opCode engine.Opcode) *e
 type queryHints struct {
 	scatterErrorsAsWarnings,
 	multiShardAutocommit bool
-	queryTimeout int
+	queryTimeout    int
+	insertBatchRows int
 }
 
 func getHints(cmt *sqlparser.ParsedComments) *queryHints {
@@ -529,6 +545,7 @@ func getHints(cmt *sqlparser.ParsedComments) *queryHints {
 		scatterErrorsAsWarnings: scatterAsWarns,
 		multiShardAutocommit:    multiShardAutoCommit,
 		queryTimeout:            timeout,
+		insertBatchRows:         insertBatchRows(directives),
 	}
 }
 
@@ -607,6 +624,7 @@ func buildInsertLogicalPlan(
 	if hints != nil {
 		ic.MultiShardAutocommit = hints.multiShardAutocommit
 		ic.QueryTimeout = hints.queryTimeout
+		ic.BatchRows = hints.insertBatchRows
 	}
 
 	eins := &engine.Insert{
@@ -641,10 +659,12 @@ func autoIncGenerate(gen *operators.Generate) *engine.Generate {
 		SelectExprs: sqlparser.SelectExprs{&sqlparser.Nextval{Expr: &sqlparser.Argument{Name: "n", Type: sqltypes.Int64}}},
 	}
 	return &engine.Generate{
-		Keyspace: gen.Keyspace,
-		Query:    sqlparser.String(selNext),
-		Values:   gen.Values,
-		Offset:   gen.Offset,
+		Keyspace:  gen.Keyspace,
+		Query:     sqlparser.String(selNext),
+		Values:    gen.Values,
+		Offset:    gen.Offset,
+		Sequence:  gen.Keyspace.Name + "." + gen.TableName.Name.String(),
+		BlockSize: gen.BlockSize,
 	}
 }
 
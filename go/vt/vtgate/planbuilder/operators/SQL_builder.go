@@ -291,6 +291,11 @@ func (qb *queryBuilder) sortTables() {
 			tbl: qb.ctx.SemTable,
 		}
 		sort.Sort(ts)
+		// the tables are now in the order of the original query, which MySQL
+		// has to keep when the query asked for a straight join.
+		if qb.ctx.SemTable.QuerySignature.StraightJoin && len(sel.From) > 1 {
+			sel.StraightJoinHint = true
+		}
 		return true, nil
 	}, qb.stmt)
 
@@ -308,18 +313,27 @@ func (ts *tableSorter) Len() int {
 
 // Less implements the Sort interface
 func (ts *tableSorter) Less(i, j int) bool {
-	lhs := ts.sel.From[i]
-	rhs := ts.sel.From[j]
-	left, ok := lhs.(*sqlparser.AliasedTableExpr)
+	left, ok := ts.tableOffset(ts.sel.From[i])
 	if !ok {
 		return i < j
 	}
-	right, ok := rhs.(*sqlparser.AliasedTableExpr)
+	right, ok := ts.tableOffset(ts.sel.From[j])
 	if !ok {
 		return i < j
 	}
 
-	return ts.tbl.TableSetFor(left).TableOffset() < ts.tbl.TableSetFor(right).TableOffset()
+	return left < right
+}
+
+func (ts *tableSorter) tableOffset(expr sqlparser.TableExpr) (int, bool) {
+	switch expr := expr.(type) {
+	case *sqlparser.AliasedTableExpr:
+		return ts.tbl.TableSetFor(expr).TableOffset(), true
+	case *sqlparser.JSONTableExpr:
+		return ts.tbl.TableSetForJSONTable(expr).TableOffset(), true
+	default:
+		return 0, false
+	}
 }
 
 // Swap implements the Sort interface
@@ -349,6 +363,7 @@ func stripDownQuery(from, to sqlparser.SelectStatement) {
 		toNode.OrderBy = node.OrderBy
 		toNode.Comments = node.Comments
 		toNode.Limit = node.Limit
+		toNode.Windows = node.Windows
 		toNode.SelectExprs = node.SelectExprs
 		for _, expr := range toNode.SelectExprs {
 			removeKeyspaceFromSelectExpr(expr)
@@ -371,6 +386,8 @@ func buildQuery(op Operator, qb *queryBuilder) {
 	switch op := op.(type) {
 	case *Table:
 		buildTable(op, qb)
+	case *JSONTable:
+		buildJSONTable(op, qb)
 	case *Projection:
 		buildProjection(op, qb)
 	case *ApplyJoin:
@@ -494,6 +511,16 @@ func buildTable(op *Table, qb *queryBuilder) {
 	}
 }
 
+func buildJSONTable(op *JSONTable, qb *queryBuilder) {
+	if qb.stmt == nil {
+		qb.stmt = &sqlparser.Select{}
+	}
+	qb.stmt.(FromStatement).SetFrom(append(qb.stmt.(FromStatement).GetFrom(), op.Expr))
+	for _, name := range op.Columns {
+		qb.addProjection(&sqlparser.AliasedExpr{Expr: name})
+	}
+}
+
 func buildProjection(op *Projection, qb *queryBuilder) {
 	buildQuery(op.Source, qb)
 
@@ -612,6 +639,7 @@ func buildDerivedSelect(op *Horizon, qb *queryBuilder, sel *sqlparser.Select) {
 	sel.OrderBy = opQuery.OrderBy
 	sel.GroupBy = opQuery.GroupBy
 	sel.Having = mergeHaving(sel.Having, opQuery.Having)
+	sel.Windows = opQuery.Windows
 	sel.SelectExprs = opQuery.SelectExprs
 	qb.addTableExpr(op.Alias, op.Alias, TableID(op), &sqlparser.DerivedTable{
 		Select: sel,
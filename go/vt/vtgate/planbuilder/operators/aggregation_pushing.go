@@ -57,6 +57,10 @@ func tryPushAggregator(ctx *plancontext.PlanningContext, aggregator *Aggregator)
 
 	switch src := aggregator.Source.(type) {
 	case *Route:
+		if needsConcatOrdering(ctx, aggregator) {
+			// the aggregation is done on vtgate, over the rows returned by the route
+			return aggregator, NoRewrite
+		}
 		// if we have a single sharded route, we can push it down
 		output, applyResult = pushAggregationThroughRoute(ctx, aggregator, src)
 	case *ApplyJoin:
@@ -141,7 +145,47 @@ func aggregateTheAggregate(a *Aggregator, i int) {
 		// Think of it as we are SUMming together a bunch of distributed COUNTs.
 		aggr.OriginalOpCode, aggr.OpCode = aggr.OpCode, opcode.AggregateSum
 		a.Aggregations[i] = aggr
+	case opcode.AggregateGroupConcat:
+		// A distinct group_concat is only pushed down when its values are unique across shards,
+		// so the values concatenated below do not need to be compared again.
+		aggr.Distinct = false
+		a.Aggregations[i] = aggr
+	}
+}
+
+// needsConcatOrdering returns true if the group_concat aggregations have an ORDER BY,
+// which can only be honored by concatenating the values on vtgate, in the requested order.
+// The ordering is then stored on the aggregator, that should not be split.
+func needsConcatOrdering(ctx *plancontext.PlanningContext, aggregator *Aggregator) bool {
+	var order sqlparser.OrderBy
+	for _, aggr := range aggregator.Aggregations {
+		gc, ok := aggr.Func.(*sqlparser.GroupConcatExpr)
+		if !ok || len(gc.OrderBy) == 0 {
+			continue
+		}
+		if order != nil && !equalOrderBy(ctx, order, gc.OrderBy) {
+			panic(vterrors.VT12001(fmt.Sprintf("group_concat aggregations with different ORDER BY: %s", sqlparser.String(aggr.Original))))
+		}
+		order = gc.OrderBy
+	}
+	if order == nil {
+		return false
+	}
+
+	for _, aggr := range aggregator.Aggregations {
+		if aggr.Distinct && aggr.OpCode != opcode.AggregateGroupConcat {
+			// distinct aggregations need the rows ordered by their expression instead
+			panic(vterrors.VT12001(fmt.Sprintf("distinct aggregation together with an ordered group_concat: %s", sqlparser.String(aggr.Original))))
+		}
 	}
+	aggregator.ConcatOrder = order
+	return true
+}
+
+func equalOrderBy(ctx *plancontext.PlanningContext, a, b sqlparser.OrderBy) bool {
+	return slices.EqualFunc(a, b, func(x, y *sqlparser.Order) bool {
+		return x.Direction == y.Direction && ctx.SemTable.EqualsExpr(x.Expr, y.Expr)
+	})
 }
 
 func pushAggregationThroughRoute(
@@ -546,6 +590,10 @@ func splitAggrColumnsToLeftAndRight(
 		outerJoin:   leftJoin,
 	}
 
+	if needsConcatOrdering(ctx, aggregator) {
+		return nil, errAbortAggrPushing
+	}
+
 	canPushDistinctAggr, distinctExprs := checkIfWeCanPush(ctx, aggregator)
 
 	// Distinct aggregation cannot be pushed down in the join.
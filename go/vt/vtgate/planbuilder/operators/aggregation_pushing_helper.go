@@ -133,16 +133,12 @@ func (ab *aggBuilder) handleAggr(ctx *plancontext.PlanningContext, aggr Aggr) er
 	case opcode.AggregateMax, opcode.AggregateMin, opcode.AggregateAnyValue:
 		return ab.handlePushThroughAggregation(ctx, aggr)
 	case opcode.AggregateGroupConcat:
-		f := aggr.Func.(*sqlparser.GroupConcatExpr)
-		if f.Distinct || len(f.OrderBy) > 0 || f.Separator != "" {
-			panic("fail here")
-		}
 		// this needs special handling, currently aborting the push of function
 		// and later will try pushing the column instead.
 		// TODO: this should be handled better by pushing the function down.
 		return errAbortAggrPushing
 	case opcode.AggregateUnassigned:
-		panic(vterrors.VT12001(fmt.Sprintf("in scatter query: aggregation function '%s'", sqlparser.String(aggr.Original))))
+		panic(ctx.SemTable.ErrorAt(aggr.Original, vterrors.VT12001(fmt.Sprintf("in scatter query: aggregation function '%s'", sqlparser.String(aggr.Original)))))
 	case opcode.AggregateGtid:
 		// this is only used for SHOW GTID queries that will never contain joins
 		panic(vterrors.VT13001("cannot do join with vgtid"))
@@ -45,6 +45,11 @@ type (
 		// this needs to be the last ORDER BY expression
 		DistinctExpr sqlparser.Expr
 
+		// We support a single ordering for the group_concat aggregations that are
+		// evaluated on vtgate. It is stored here, and planned after the grouping
+		// expressions, so that the values reach the OrderedAggregate in order
+		ConcatOrder sqlparser.OrderBy
+
 		// Pushed will be set to true once this aggregation has been pushed deeper in the tree
 		Pushed        bool
 		offsetPlanned bool
@@ -37,18 +37,38 @@ func translateQueryToOp(ctx *plancontext.PlanningContext, selStmt sqlparser.Stat
 	case *sqlparser.Union:
 		return createOperatorFromUnion(ctx, node)
 	case *sqlparser.Update:
+		errIfVitessMetadataTarget(ctx, ctx.SemTable.Targets)
 		return createOperatorFromUpdate(ctx, node)
 	case *sqlparser.Delete:
+		errIfVitessMetadataTarget(ctx, ctx.SemTable.Targets)
 		return createOperatorFromDelete(ctx, node)
 	case *sqlparser.Insert:
+		errIfVitessMetadataTarget(ctx, ctx.SemTable.TableSetFor(node.Table))
 		return createOperatorFromInsert(ctx, node)
 	default:
 		panic(vterrors.VT12001(fmt.Sprintf("operator: %T", selStmt)))
 	}
 }
 
+// errIfVitessMetadataTarget fails DML that modifies a vitess_metadata table,
+// since the rows of these tables are produced by vtgate itself.
+func errIfVitessMetadataTarget(ctx *plancontext.PlanningContext, targets semantics.TableSet) {
+	for _, target := range targets.Constituents() {
+		tableInfo, err := ctx.SemTable.TableInfoFor(target)
+		if err != nil {
+			panic(err)
+		}
+		if _, isVitessMetadata := tableInfo.(*semantics.VitessMetadataTable); isVitessMetadata {
+			panic(vterrors.VT12001("modifying vitess_metadata tables"))
+		}
+	}
+}
+
 func createOperatorFromSelect(ctx *plancontext.PlanningContext, sel *sqlparser.Select) Operator {
 	op := crossJoin(ctx, sel.From)
+	if sel.StraightJoinHint {
+		markStraightJoin(op)
+	}
 
 	if sel.Where != nil {
 		op = addWherePredicates(ctx, sel.Where.Expr, op)
@@ -216,6 +236,8 @@ func getOperatorFromTableExpr(ctx *plancontext.PlanningContext, tableExpr sqlpar
 		return getOperatorFromJoinTableExpr(ctx, tableExpr)
 	case *sqlparser.ParenTableExpr:
 		return crossJoin(ctx, tableExpr.Exprs)
+	case *sqlparser.JSONTableExpr:
+		return createJSONTableRoute(ctx, tableExpr)
 	default:
 		panic(vterrors.VT13001(fmt.Sprintf("unable to use: %T table type", tableExpr)))
 	}
@@ -226,7 +248,7 @@ func getOperatorFromJoinTableExpr(ctx *plancontext.PlanningContext, tableExpr *s
 	rhs := getOperatorFromTableExpr(ctx, tableExpr.RightExpr, false)
 
 	switch tableExpr.Join {
-	case sqlparser.NormalJoinType:
+	case sqlparser.NormalJoinType, sqlparser.StraightJoinType:
 		return createInnerJoin(ctx, tableExpr, lhs, rhs)
 	case sqlparser.LeftJoinType, sqlparser.RightJoinType:
 		return createOuterJoin(tableExpr, lhs, rhs)
@@ -257,6 +279,13 @@ func getOperatorFromAliasedTableExpr(ctx *plancontext.PlanningContext, tableExpr
 				Solved: solves,
 			}
 		}
+		if vt, isVitessMetadata := tableInfo.(*semantics.VitessMetadataTable); isVitessMetadata {
+			return &VitessMetadata{
+				TableID: tableID,
+				Alias:   tableExpr,
+				VTable:  vt.GetVindexTable(),
+			}
+		}
 		qg := newQueryGraph()
 		isInfSchema := tableInfo.IsInfSchema()
 		qt := &QueryTable{Alias: tableExpr, Table: tbl, ID: tableID, IsInfSchema: isInfSchema}
@@ -295,6 +324,23 @@ func crossJoin(ctx *plancontext.PlanningContext, exprs sqlparser.TableExprs) Ope
 	return output
 }
 
+// markStraightJoin makes the joins of the FROM clause of a SELECT STRAIGHT_JOIN
+// keep the order in which the tables are written.
+func markStraightJoin(op Operator) {
+	switch op := op.(type) {
+	case *QueryGraph:
+		op.StraightJoin = true
+	case *Join:
+		if !op.LeftJoin {
+			op.Straight = true
+		}
+		markStraightJoin(op.LHS)
+		markStraightJoin(op.RHS)
+	case *SubQueryContainer:
+		markStraightJoin(op.Outer)
+	}
+}
+
 func createQueryTableForDML(
 	ctx *plancontext.PlanningContext,
 	tableExpr sqlparser.TableExpr,
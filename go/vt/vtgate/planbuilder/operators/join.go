@@ -27,6 +27,9 @@ type Join struct {
 	LHS, RHS  Operator
 	Predicate sqlparser.Expr
 	LeftJoin  bool
+	// Straight is set for a STRAIGHT_JOIN, whose LHS must be read before its RHS.
+	// It is never merged into a QueryGraph, where the tables could be reordered.
+	Straight bool
 
 	noColumns
 }
@@ -43,6 +46,7 @@ func (j *Join) Clone(inputs []Operator) Operator {
 		RHS:       inputs[1],
 		Predicate: j.Predicate,
 		LeftJoin:  j.LeftJoin,
+		Straight:  j.Straight,
 	}
 }
 
@@ -61,8 +65,8 @@ func (j *Join) SetInputs(ops []Operator) {
 }
 
 func (j *Join) Compact(ctx *plancontext.PlanningContext) (Operator, *ApplyResult) {
-	if j.LeftJoin {
-		// we can't merge outer joins into a single QG
+	if j.LeftJoin || j.Straight {
+		// we can't merge outer joins or straight joins into a single QG
 		return j, NoRewrite
 	}
 
@@ -73,9 +77,10 @@ func (j *Join) Compact(ctx *plancontext.PlanningContext) (Operator, *ApplyResult
 	}
 
 	newOp := &QueryGraph{
-		Tables:     append(lqg.Tables, rqg.Tables...),
-		innerJoins: append(lqg.innerJoins, rqg.innerJoins...),
-		NoDeps:     ctx.SemTable.AndExpressions(lqg.NoDeps, rqg.NoDeps),
+		Tables:       append(lqg.Tables, rqg.Tables...),
+		innerJoins:   append(lqg.innerJoins, rqg.innerJoins...),
+		NoDeps:       ctx.SemTable.AndExpressions(lqg.NoDeps, rqg.NoDeps),
+		StraightJoin: lqg.StraightJoin || rqg.StraightJoin,
 	}
 	if j.Predicate != nil {
 		newOp.collectPredicate(ctx, j.Predicate)
@@ -101,9 +106,10 @@ func createJoin(ctx *plancontext.PlanningContext, LHS, RHS Operator) Operator {
 	rqg, rok := RHS.(*QueryGraph)
 	if lok && rok {
 		op := &QueryGraph{
-			Tables:     append(lqg.Tables, rqg.Tables...),
-			innerJoins: append(lqg.innerJoins, rqg.innerJoins...),
-			NoDeps:     ctx.SemTable.AndExpressions(lqg.NoDeps, rqg.NoDeps),
+			Tables:       append(lqg.Tables, rqg.Tables...),
+			innerJoins:   append(lqg.innerJoins, rqg.innerJoins...),
+			NoDeps:       ctx.SemTable.AndExpressions(lqg.NoDeps, rqg.NoDeps),
+			StraightJoin: lqg.StraightJoin || rqg.StraightJoin,
 		}
 		return op
 	}
@@ -111,7 +117,12 @@ func createJoin(ctx *plancontext.PlanningContext, LHS, RHS Operator) Operator {
 }
 
 func createInnerJoin(ctx *plancontext.PlanningContext, tableExpr *sqlparser.JoinTableExpr, lhs, rhs Operator) Operator {
-	op := createJoin(ctx, lhs, rhs)
+	var op Operator
+	if tableExpr.Join == sqlparser.StraightJoinType {
+		op = &Join{LHS: lhs, RHS: rhs, Straight: true}
+	} else {
+		op = createJoin(ctx, lhs, rhs)
+	}
 	sqc := &SubQueryBuilder{}
 	outerID := TableID(op)
 	joinPredicate := tableExpr.Condition.On
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"vitess.io/vitess/go/slice"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+)
+
+// JSONTable is the leaf operator for a JSON_TABLE table expression.
+// The table function is evaluated by MySQL, so it is planned under a route
+// with dual routing, which merges with the route of the tables it uses.
+type JSONTable struct {
+	Expr    *sqlparser.JSONTableExpr
+	TableID semantics.TableSet
+	Columns []*sqlparser.ColName
+
+	noInputs
+}
+
+func createJSONTableRoute(ctx *plancontext.PlanningContext, expr *sqlparser.JSONTableExpr) *Route {
+	return &Route{
+		Source: &JSONTable{
+			Expr:    expr,
+			TableID: ctx.SemTable.TableSetForJSONTable(expr),
+		},
+		Routing: &DualRouting{},
+	}
+}
+
+// Clone implements the Operator interface
+func (jt *JSONTable) Clone([]Operator) Operator {
+	var columns []*sqlparser.ColName
+	for _, name := range jt.Columns {
+		columns = append(columns, sqlparser.CloneRefOfColName(name))
+	}
+	return &JSONTable{
+		Expr:    jt.Expr,
+		TableID: jt.TableID,
+		Columns: columns,
+	}
+}
+
+// introducesTableID implements the tableIDIntroducer interface
+func (jt *JSONTable) introducesTableID() semantics.TableSet {
+	return jt.TableID
+}
+
+// AddPredicate implements the Operator interface
+func (jt *JSONTable) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(jt, expr)
+}
+
+func (jt *JSONTable) AddColumn(*plancontext.PlanningContext, bool, bool, *sqlparser.AliasedExpr) int {
+	panic(vterrors.VT13001("did not expect this method to be called"))
+}
+
+func (jt *JSONTable) FindCol(_ *plancontext.PlanningContext, expr sqlparser.Expr, _ bool) int {
+	colToFind, ok := expr.(*sqlparser.ColName)
+	if !ok {
+		return -1
+	}
+
+	for idx, colName := range jt.Columns {
+		if colName.Name.Equal(colToFind.Name) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+func (jt *JSONTable) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return slice.Map(jt.Columns, colNameToExpr)
+}
+
+func (jt *JSONTable) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, jt)
+}
+
+func (jt *JSONTable) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+func (jt *JSONTable) GetColNames() []*sqlparser.ColName {
+	return jt.Columns
+}
+
+func (jt *JSONTable) AddCol(col *sqlparser.ColName) {
+	jt.Columns = append(jt.Columns, col)
+}
+
+func (jt *JSONTable) ShortDescription() string {
+	return "JSON_TABLE AS " + jt.Expr.Alias.String()
+}
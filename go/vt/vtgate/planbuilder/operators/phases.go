@@ -242,6 +242,12 @@ func addOrderingFor(aggrOp *Aggregator) {
 			SimplifiedExpr: aggrOp.DistinctExpr,
 		})
 	}
+	for _, order := range aggrOp.ConcatOrder {
+		orderBys = append(orderBys, OrderBy{
+			Inner:          order,
+			SimplifiedExpr: order.Expr,
+		})
+	}
 	aggrOp.Source = &Ordering{
 		Source: aggrOp.Source,
 		Order:  orderBys,
@@ -255,6 +261,10 @@ func needsOrdering(ctx *plancontext.PlanningContext, in *Aggregator) bool {
 	if in.DistinctExpr != nil {
 		requiredOrder = append(requiredOrder, in.DistinctExpr)
 	}
+	if len(in.ConcatOrder) > 0 {
+		// the direction of these expressions matters, so we don't try to reuse the ordering of the source
+		return true
+	}
 	if len(requiredOrder) == 0 {
 		return false
 	}
@@ -71,11 +71,14 @@ func PlanQuery(ctx *plancontext.PlanningContext, stmt sqlparser.Statement) (resu
 	checkValid(op)
 	op = planQuery(ctx, op)
 
-	_, isRoute := op.(*Route)
+	route, isRoute := op.(*Route)
 	if !isRoute && ctx.SemTable.NotSingleRouteErr != nil {
 		// If we got here, we don't have a single shard plan
 		return nil, ctx.SemTable.NotSingleRouteErr
 	}
+	if ctx.SemTable.NotSingleShardErr != nil && (!isRoute || !route.IsSingleShard()) {
+		return nil, ctx.SemTable.NotSingleShardErr
+	}
 
 	return op, err
 }
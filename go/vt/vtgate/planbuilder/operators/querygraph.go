@@ -42,6 +42,10 @@ type (
 		// NoDeps contains the predicates that can be evaluated anywhere.
 		NoDeps sqlparser.Expr
 
+		// StraightJoin is set when the tables must be joined in the order of the
+		// FROM clause, as asked by SELECT STRAIGHT_JOIN.
+		StraightJoin bool
+
 		noInputs
 		noColumns
 	}
@@ -172,6 +176,7 @@ func (qg *QueryGraph) Clone([]Operator) Operator {
 	result.Tables = append([]*QueryTable{}, qg.Tables...)
 	result.innerJoins = append([]*innerJoin{}, qg.innerJoins...)
 	result.NoDeps = qg.NoDeps
+	result.StraightJoin = qg.StraightJoin
 	return result
 }
 
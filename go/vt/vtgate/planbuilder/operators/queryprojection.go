@@ -98,7 +98,12 @@ type (
 )
 
 func (aggr Aggr) NeedsWeightString(ctx *plancontext.PlanningContext) bool {
-	return aggr.OpCode.NeedsComparableValues() && ctx.SemTable.NeedsWeightString(aggr.Func.GetArg())
+	return aggr.needsComparableValues() && ctx.SemTable.NeedsWeightString(aggr.Func.GetArg())
+}
+
+// needsComparableValues returns true if the values of the aggregation are compared on vtgate
+func (aggr Aggr) needsComparableValues() bool {
+	return aggr.OpCode.NeedsComparableValues() || (aggr.OpCode == opcode.AggregateGroupConcat && aggr.Distinct)
 }
 
 func (aggr Aggr) GetTypeCollation(ctx *plancontext.PlanningContext) evalengine.Type {
@@ -109,7 +114,11 @@ func (aggr Aggr) GetTypeCollation(ctx *plancontext.PlanningContext) evalengine.T
 	case opcode.AggregateMin, opcode.AggregateMax, opcode.AggregateSumDistinct, opcode.AggregateCountDistinct:
 		typ, _ := ctx.SemTable.TypeForExpr(aggr.Func.GetArg())
 		return typ
-
+	case opcode.AggregateGroupConcat:
+		if aggr.Distinct {
+			typ, _ := ctx.SemTable.TypeForExpr(aggr.Func.GetArg())
+			return typ
+		}
 	}
 	return evalengine.Type{}
 }
@@ -59,7 +59,7 @@ func optimizeJoin(ctx *plancontext.PlanningContext, op *Join) (Operator, *ApplyR
 func optimizeQueryGraph(ctx *plancontext.PlanningContext, op *QueryGraph) (result Operator, changed *ApplyResult) {
 
 	switch {
-	case ctx.PlannerVersion == querypb.ExecuteOptions_Gen4Left2Right:
+	case ctx.PlannerVersion == querypb.ExecuteOptions_Gen4Left2Right, op.StraightJoin:
 		result = leftToRightSolve(ctx, op)
 	default:
 		result = greedySolve(ctx, op)
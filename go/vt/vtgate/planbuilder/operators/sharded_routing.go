@@ -685,6 +685,11 @@ func tryMergeJoinShardedRouting(
 // makeEvalEngineExpr transforms the given sqlparser.Expr into an evalengine expression
 func makeEvalEngineExpr(ctx *plancontext.PlanningContext, n sqlparser.Expr) evalengine.Expr {
 	for _, expr := range ctx.SemTable.GetExprAndEqualities(n) {
+		if ctx.SemTable.DeterminismOf(expr) != semantics.Deterministic {
+			// vtgate would route on a value that the shards evaluate again, and
+			// they can get a different result, so we can't route on it.
+			continue
+		}
 		ee, _ := evalengine.Translate(expr, &evalengine.Config{
 			Collation:   ctx.SemTable.Collation,
 			ResolveType: ctx.SemTable.TypeForExpr,
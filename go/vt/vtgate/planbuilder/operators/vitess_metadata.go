@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operators
+
+import (
+	"slices"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+	"vitess.io/vitess/go/vt/vtgate/semantics"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// VitessMetadata reads a table of the vitess_metadata virtual schema. The rows
+// are produced by vtgate, so all predicates, joins and aggregations on top of
+// it are evaluated in vtgate.
+type VitessMetadata struct {
+	TableID semantics.TableSet
+	Alias   *sqlparser.AliasedTableExpr
+	VTable  *vindexes.Table
+	Columns []*sqlparser.AliasedExpr
+
+	noInputs
+}
+
+// Introduces implements the Operator interface
+func (vm *VitessMetadata) introducesTableID() semantics.TableSet {
+	return vm.TableID
+}
+
+// Clone implements the Operator interface
+func (vm *VitessMetadata) Clone([]Operator) Operator {
+	clone := *vm
+	clone.Columns = slices.Clone(vm.Columns)
+	return &clone
+}
+
+// AddPredicate implements the Operator interface
+func (vm *VitessMetadata) AddPredicate(_ *plancontext.PlanningContext, expr sqlparser.Expr) Operator {
+	return newFilter(vm, expr)
+}
+
+// AddColumn implements the Operator interface. The rows of a vitess_metadata
+// table are produced by vtgate, so any expression can be added, not only
+// columns, and there is no query to add a grouping to.
+func (vm *VitessMetadata) AddColumn(ctx *plancontext.PlanningContext, reuse bool, _ bool, ae *sqlparser.AliasedExpr) int {
+	if reuse {
+		offset := vm.FindCol(ctx, ae.Expr, true)
+		if offset > -1 {
+			return offset
+		}
+	}
+
+	vm.Columns = append(vm.Columns, ae)
+	return len(vm.Columns) - 1
+}
+
+func (vm *VitessMetadata) FindCol(ctx *plancontext.PlanningContext, expr sqlparser.Expr, underRoute bool) int {
+	for idx, col := range vm.Columns {
+		if ctx.SemTable.EqualsExprWithDeps(expr, col.Expr) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+func (vm *VitessMetadata) GetColumns(*plancontext.PlanningContext) []*sqlparser.AliasedExpr {
+	return vm.Columns
+}
+
+func (vm *VitessMetadata) GetSelectExprs(ctx *plancontext.PlanningContext) sqlparser.SelectExprs {
+	return transformColumnsToSelectExprs(ctx, vm)
+}
+
+func (vm *VitessMetadata) GetOrdering(*plancontext.PlanningContext) []OrderBy {
+	return nil
+}
+
+// TablesUsed implements the Operator interface.
+func (vm *VitessMetadata) TablesUsed() []string {
+	return []string{vindexes.VitessMetadataSchema + "." + vm.VTable.Name.String()}
+}
+
+func (vm *VitessMetadata) ShortDescription() string {
+	return vindexes.VitessMetadataSchema + "." + vm.VTable.Name.String()
+}
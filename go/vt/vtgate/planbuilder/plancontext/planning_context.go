@@ -73,13 +73,14 @@ func CreatePlanningContext(stmt sqlparser.Statement,
 		ksName = ks.Name
 	}
 
-	semTable, err := semantics.Analyze(stmt, ksName, vschema)
+	semTable, err := semantics.AnalyzeWithPositions(stmt, ksName, vschema, vschema.QueryPositions())
 	if err != nil {
 		return nil, err
 	}
 
 	// record any warning as planner warning.
 	vschema.PlannerWarning(semTable.Warning)
+	vschema.PlannerBindVarTypes(semTable.BindVarTypes)
 
 	return &PlanningContext{
 		ReservedVars:      reservedVars,
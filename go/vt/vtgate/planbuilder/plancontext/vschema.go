@@ -8,6 +8,7 @@ import (
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/vt/key"
@@ -56,6 +57,14 @@ type VSchema interface {
 	// PlannerWarning records warning created during planning.
 	PlannerWarning(message string)
 
+	// PlannerBindVarTypes records the types of the vindex columns the bind variables
+	// of the query are compared with, for the client values to be coerced to.
+	PlannerBindVarTypes(types map[string]evalengine.Type)
+
+	// QueryPositions returns the positions of the expressions of the statement
+	// in the query text, or nil if they are unknown.
+	QueryPositions() sqlparser.Positions
+
 	// ForeignKeyMode returns the foreign_key flag value
 	ForeignKeyMode(keyspace string) (vschemapb.Keyspace_ForeignKeyMode, error)
 
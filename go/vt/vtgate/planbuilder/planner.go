@@ -65,6 +65,9 @@ func setCommentDirectivesOnPlan(plan logicalPlan, stmt sqlparser.Statement) {
 		setDirective(plan.prim, multiShardAutoCommit, timeout)
 	case *insert:
 		setDirective(plan.eInsert, multiShardAutoCommit, timeout)
+		if plan.eInsert != nil {
+			plan.eInsert.BatchRows = insertBatchRows(directives)
+		}
 	}
 }
 
@@ -90,3 +93,13 @@ func queryTimeout(d *sqlparser.CommentDirectives) int {
 	}
 	return 0
 }
+
+// insertBatchRows returns DirectiveInsertBatchRows value if set to a positive
+// integer, otherwise returns 0.
+func insertBatchRows(d *sqlparser.CommentDirectives) int {
+	val, _ := d.GetString(sqlparser.DirectiveInsertBatchRows, "0")
+	if intVal, err := strconv.Atoi(val); err == nil && intVal > 0 {
+		return intVal
+	}
+	return 0
+}
@@ -105,12 +105,13 @@ func gen4planSQLCalcFoundRows(vschema plancontext.VSchema, sel *sqlparser.Select
 	if ks, _ := vschema.DefaultKeyspace(); ks != nil {
 		ksName = ks.Name
 	}
-	semTable, err := semantics.Analyze(sel, ksName, vschema)
+	semTable, err := semantics.AnalyzeWithPositions(sel, ksName, vschema, vschema.QueryPositions())
 	if err != nil {
 		return nil, err
 	}
 	// record any warning as planner warning.
 	vschema.PlannerWarning(semTable.Warning)
+	vschema.PlannerBindVarTypes(semTable.BindVarTypes)
 
 	plan, tablesUsed, err := buildSQLCalcFoundRowsPlan(query, sel, reservedVars, vschema)
 	if err != nil {
@@ -43,11 +43,17 @@ const (
 	charset = "charset"
 )
 
-func buildShowPlan(sql string, stmt *sqlparser.Show, _ *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
+func buildShowPlan(sql string, stmt *sqlparser.Show, reservedVars *sqlparser.ReservedVars, vschema plancontext.VSchema) (*planResult, error) {
 	if vschema.Destination() != nil {
 		return buildByPassPlan(sql, vschema)
 	}
 
+	if show, ok := stmt.Internal.(*sqlparser.ShowBasic); ok {
+		if sel := vitessMetadataSelect(show); sel != nil {
+			return gen4SelectStmtPlanner(sqlparser.String(sel), Gen4, sel, reservedVars, vschema)
+		}
+	}
+
 	var prim engine.Primitive
 	var err error
 	switch show := stmt.Internal.(type) {
@@ -67,6 +73,44 @@ func buildShowPlan(sql string, stmt *sqlparser.Show, _ *sqlparser.ReservedVars,
 	return newPlanResult(prim), nil
 }
 
+// vitessMetadataSelect rewrites a SHOW VITESS_SHARDS, VITESS_TABLETS,
+// VITESS_REPLICATION_STATUS or VITESS_PLANS with a WHERE clause into a SELECT
+// over the matching vitess_metadata table, so that the filter can be planned
+// like any other query. It returns nil for every other SHOW statement.
+func vitessMetadataSelect(show *sqlparser.ShowBasic) *sqlparser.Select {
+	if show.Filter == nil || show.Filter.Filter == nil {
+		return nil
+	}
+
+	selectExprs := sqlparser.SelectExprs{&sqlparser.StarExpr{}}
+	var table string
+	switch show.Command {
+	case sqlparser.VitessShards:
+		table = vindexes.VitessMetadataShards
+		// SHOW VITESS_SHARDS returns a single keyspace/shard column.
+		selectExprs = sqlparser.SelectExprs{sqlparser.NewAliasedExpr(&sqlparser.FuncExpr{
+			Name: sqlparser.NewIdentifierCI("concat"),
+			Exprs: sqlparser.Exprs{
+				sqlparser.NewColName("Keyspace"),
+				sqlparser.NewStrLiteral("/"),
+				sqlparser.NewColName("Shard"),
+			},
+		}, "Shards")}
+	case sqlparser.VitessTablets:
+		table = vindexes.VitessMetadataTablets
+	case sqlparser.VitessReplicationStatus:
+		table = vindexes.VitessMetadataReplicationStatus
+	case sqlparser.VitessPlans:
+		table = vindexes.VitessMetadataPlans
+	default:
+		return nil
+	}
+
+	from := sqlparser.TableExprs{sqlparser.NewAliasedTableExpr(sqlparser.NewTableNameWithQualifier(table, vindexes.VitessMetadataSchema), "")}
+	where := sqlparser.NewWhere(sqlparser.WhereClause, show.Filter.Filter)
+	return sqlparser.NewSelect(nil, selectExprs, nil, nil, from, where, nil, nil, nil)
+}
+
 func buildShowOtherPlan(sql string, vschema plancontext.VSchema) (engine.Primitive, error) {
 	ks, err := vschema.AnyKeyspace()
 	if err != nil {
@@ -108,7 +152,7 @@ func buildShowBasicPlan(show *sqlparser.ShowBasic, vschema plancontext.VSchema)
 		return buildPluginsPlan()
 	case sqlparser.Engines:
 		return buildEnginesPlan()
-	case sqlparser.VitessReplicationStatus, sqlparser.VitessShards, sqlparser.VitessTablets, sqlparser.VitessVariables:
+	case sqlparser.VitessReplicationStatus, sqlparser.VitessShards, sqlparser.VitessTablets, sqlparser.VitessVariables, sqlparser.VitessPlans:
 		return &engine.ShowExec{
 			Command:    show.Command,
 			ShowFilter: show.Filter,
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planbuilder
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/operators"
+	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
+)
+
+// transformVitessMetadata plans the read of a vitess_metadata table. When the
+// operator only needs plain columns, the table primitive projects them itself;
+// any other expression is evaluated by a Projection on top of it.
+func transformVitessMetadata(ctx *plancontext.PlanningContext, op *operators.VitessMetadata) (logicalPlan, error) {
+	prim := &engine.VitessMetadataTable{
+		Table: op.VTable.Name.String(),
+	}
+	addColumn := func(col *sqlparser.ColName) (int, error) {
+		offset := -1
+		for idx, column := range op.VTable.Columns {
+			if column.Name.Equal(col.Name) {
+				offset = idx
+				break
+			}
+		}
+		if offset < 0 {
+			return 0, vterrors.VT03022(col.Name.String(), "field list")
+		}
+		prim.Fields = append(prim.Fields, engine.VitessMetadataField(op.VTable.Columns[offset]))
+		prim.Cols = append(prim.Cols, offset)
+		return len(prim.Cols) - 1, nil
+	}
+
+	var exprs []evalengine.Expr
+	var columnNames []string
+	onlyColumns := true
+	for _, ae := range op.Columns {
+		columnNames = append(columnNames, ae.ColumnName())
+		if col, isCol := ae.Expr.(*sqlparser.ColName); isCol {
+			offset, err := addColumn(col)
+			if err != nil {
+				return nil, err
+			}
+			typ, _ := ctx.SemTable.TypeForExpr(col)
+			exprs = append(exprs, evalengine.NewColumn(offset, typ, col))
+			continue
+		}
+		onlyColumns = false
+		expr, err := evalengine.Translate(ae.Expr, &evalengine.Config{
+			ResolveColumn: addColumn,
+			ResolveType:   ctx.SemTable.TypeForExpr,
+			Collation:     ctx.SemTable.Collation,
+			Environment:   ctx.VSchema.Environment(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if onlyColumns {
+		return &primitiveWrapper{prim: prim}, nil
+	}
+	return &projection{
+		source: &primitiveWrapper{prim: prim},
+		primitive: &engine.Projection{
+			Cols:  columnNames,
+			Exprs: exprs,
+		},
+	}, nil
+}
@@ -23,6 +23,7 @@ import (
 	"os"
 	"os/signal"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -42,11 +43,15 @@ import (
 	"vitess.io/vitess/go/vt/callinfo"
 	"vitess.io/vitess/go/vt/log"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/sysvars"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vttls"
 )
 
@@ -72,6 +77,9 @@ var (
 	mysqlQueryTimeout             time.Duration
 	mysqlSlowConnectWarnThreshold time.Duration
 	mysqlConnBufferPooling        bool
+	mysqlServerCompression        bool
+	mysqlServerLocalInfile        bool
+	mysqlServerBulkOperations     bool
 
 	mysqlDefaultWorkloadName = "OLTP"
 	mysqlDefaultWorkload     int32
@@ -84,7 +92,7 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&mysqlServerBindAddress, "mysql_server_bind_address", mysqlServerBindAddress, "Binds on this address when listening to MySQL binary protocol. Useful to restrict listening to 'localhost' only for instance.")
 	fs.StringVar(&mysqlServerSocketPath, "mysql_server_socket_path", mysqlServerSocketPath, "This option specifies the Unix socket file to use when listening for local connections. By default it will be empty and it won't listen to a unix socket")
 	fs.StringVar(&mysqlTCPVersion, "mysql_tcp_version", mysqlTCPVersion, "Select tcp, tcp4, or tcp6 to control the socket type.")
-	fs.StringVar(&mysqlAuthServerImpl, "mysql_auth_server_impl", mysqlAuthServerImpl, "Which auth server implementation to use. Options: none, ldap, clientcert, static, vault.")
+	fs.StringVar(&mysqlAuthServerImpl, "mysql_auth_server_impl", mysqlAuthServerImpl, "Which auth server implementation to use. Options: none, ldap, oidc, clientcert, static, vault.")
 	fs.BoolVar(&mysqlAllowClearTextWithoutTLS, "mysql_allow_clear_text_without_tls", mysqlAllowClearTextWithoutTLS, "If set, the server will allow the use of a clear text password over non-SSL connections.")
 	fs.BoolVar(&mysqlProxyProtocol, "proxy_protocol", mysqlProxyProtocol, "Enable HAProxy PROXY protocol on MySQL listener socket")
 	fs.BoolVar(&mysqlServerRequireSecureTransport, "mysql_server_require_secure_transport", mysqlServerRequireSecureTransport, "Reject insecure connections but only if mysql_server_ssl_cert and mysql_server_ssl_key are provided")
@@ -99,9 +107,13 @@ func registerPluginFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&mysqlConnWriteTimeout, "mysql_server_write_timeout", mysqlConnWriteTimeout, "connection write timeout")
 	fs.DurationVar(&mysqlQueryTimeout, "mysql_server_query_timeout", mysqlQueryTimeout, "mysql query timeout")
 	fs.BoolVar(&mysqlConnBufferPooling, "mysql-server-pool-conn-read-buffers", mysqlConnBufferPooling, "If set, the server will pool incoming connection read buffers")
+	fs.BoolVar(&mysqlServerCompression, "mysql-server-compression", mysqlServerCompression, "If set, the server will allow clients to use the compressed protocol, with zlib or zstd.")
+	fs.BoolVar(&mysqlServerLocalInfile, "mysql-server-local-infile", mysqlServerLocalInfile, "If set, the server will accept LOAD DATA LOCAL INFILE statements, and read their file from the clients.")
+	fs.BoolVar(&mysqlServerBulkOperations, "mysql-server-bulk-operations", mysqlServerBulkOperations, "If set, the server will advertise MariaDB's bulk operations capability, so MariaDB connectors can send batched prepared statement executions.")
 	fs.DurationVar(&mysqlKeepAlivePeriod, "mysql-server-keepalive-period", mysqlKeepAlivePeriod, "TCP period between keep-alives")
 	fs.DurationVar(&mysqlServerFlushDelay, "mysql_server_flush_delay", mysqlServerFlushDelay, "Delay after which buffered response will be flushed to the client.")
 	fs.StringVar(&mysqlDefaultWorkloadName, "mysql_default_workload", mysqlDefaultWorkloadName, "Default session workload (OLTP, OLAP, DBA)")
+	fs.DurationVar(&mysqlReservedConnIdleTimeout, "mysql-server-reserved-conn-idle-timeout", mysqlReservedConnIdleTimeout, "If set, release the reserved connections of a session that has been idle for this long outside of a transaction. They are reserved again, with the system variables of the session, on its next query. Sessions with temporary tables or table locks keep their reserved connections.")
 }
 
 // vtgateHandler implements the Listener interface.
@@ -112,6 +124,7 @@ type vtgateHandler struct {
 
 	vtg         *VTGate
 	connections map[uint32]*mysql.Conn
+	activity    map[uint32]*connActivity
 
 	busyConnections atomic.Int32
 }
@@ -120,6 +133,7 @@ func newVtgateHandler(vtg *VTGate) *vtgateHandler {
 	return &vtgateHandler{
 		vtg:         vtg,
 		connections: make(map[uint32]*mysql.Conn),
+		activity:    make(map[uint32]*connActivity),
 	}
 }
 
@@ -127,6 +141,7 @@ func (vh *vtgateHandler) NewConnection(c *mysql.Conn) {
 	vh.mu.Lock()
 	defer vh.mu.Unlock()
 	vh.connections[c.ConnectionID] = c
+	vh.activity[c.ConnectionID] = &connActivity{conn: c, lastUsed: time.Now()}
 }
 
 func (vh *vtgateHandler) numConnections() int {
@@ -136,6 +151,7 @@ func (vh *vtgateHandler) numConnections() int {
 }
 
 func (vh *vtgateHandler) ComResetConnection(c *mysql.Conn) {
+	defer vh.startCommand(c)()
 	ctx := context.Background()
 	session := vh.session(c)
 	if session.InTransaction {
@@ -152,8 +168,10 @@ func (vh *vtgateHandler) ConnectionClosed(c *mysql.Conn) {
 	defer func() {
 		vh.mu.Lock()
 		delete(vh.connections, c.ConnectionID)
+		delete(vh.activity, c.ConnectionID)
 		vh.mu.Unlock()
 	}()
+	defer vh.startCommand(c)()
 
 	var ctx context.Context
 	var cancel context.CancelFunc
@@ -205,6 +223,7 @@ func startSpan(ctx context.Context, query, label string) (trace.Span, context.Co
 }
 
 func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	defer vh.startCommand(c)()
 	session := vh.session(c)
 	if c.IsShuttingDown() && !session.InTransaction {
 		c.MarkForClose()
@@ -219,6 +238,11 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 		defer cancel()
 	}
 
+	// The keyspace of the session lets the tracer sample its queries at the
+	// rate of the keyspace.
+	if keyspace, _, _, err := topoproto.ParseDestination(session.TargetString, topodatapb.TabletType_PRIMARY); err == nil {
+		ctx = trace.WithKeyspace(ctx, keyspace)
+	}
 	span, ctx, err := startSpan(ctx, query, "vtgateHandler.ComQuery")
 	if err != nil {
 		return vterrors.Wrap(err, "failed to extract span")
@@ -248,6 +272,11 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 		}
 	}()
 
+	setQueryAttributes(ctx, c, session)
+
+	// LOAD DATA LOCAL INFILE statements read their file from the client.
+	ctx = engine.WithLocalInfileOpener(ctx, c.RequestLocalInfile)
+
 	if session.Options.Workload == querypb.ExecuteOptions_OLAP {
 		session, err := vh.vtg.StreamExecute(ctx, vh, session, query, make(map[string]*querypb.BindVariable), callback)
 		if err != nil {
@@ -265,6 +294,42 @@ func (vh *vtgateHandler) ComQuery(c *mysql.Conn, query string, callback func(*sq
 	return callback(result)
 }
 
+// setQueryAttributes passes the query attributes the client sent along with
+// the current query on to the tablets through the session options, and adds
+// them to the tracing span of the query.
+func setQueryAttributes(ctx context.Context, c *mysql.Conn, session *vtgatepb.Session) {
+	attributes := c.QueryAttributes()
+	if session.Options == nil {
+		if len(attributes) == 0 {
+			return
+		}
+		session.Options = &querypb.ExecuteOptions{}
+	}
+	session.Options.QueryAttributes = attributes
+
+	if span, ok := trace.FromContext(ctx); ok {
+		for name, value := range attributes {
+			span.Annotate("query_attribute."+name, value)
+		}
+	}
+}
+
+// setConnectionAttributes passes the connection attributes the client sent
+// in the handshake, and the host it connected from, on to the tablets through
+// the session options, so they can attribute queries to applications.
+func setConnectionAttributes(c *mysql.Conn, session *vtgatepb.Session) {
+	session.Options.ConnectionAttributes = c.ConnectionAttributes()
+	if conn := c.GetRawConn(); conn != nil {
+		addr := conn.RemoteAddr().String()
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			// Not a host:port address, e.g. a unix socket.
+			host = addr
+		}
+		session.Options.ClientHost = host
+	}
+}
+
 func fillInTxStatusFlags(c *mysql.Conn, session *vtgatepb.Session) {
 	if session.InTransaction {
 		c.StatusFlags |= mysql.ServerStatusInTrans
@@ -280,6 +345,7 @@ func fillInTxStatusFlags(c *mysql.Conn, session *vtgatepb.Session) {
 
 // ComPrepare is the handler for command prepare.
 func (vh *vtgateHandler) ComPrepare(c *mysql.Conn, query string, bindVars map[string]*querypb.BindVariable) ([]*querypb.Field, error) {
+	defer vh.startCommand(c)()
 	var ctx context.Context
 	var cancel context.CancelFunc
 	if mysqlQueryTimeout != 0 {
@@ -313,6 +379,8 @@ func (vh *vtgateHandler) ComPrepare(c *mysql.Conn, query string, bindVars map[st
 		}
 	}()
 
+	setQueryAttributes(ctx, c, session)
+
 	session, fld, err := vh.vtg.Prepare(ctx, session, query, bindVars)
 	err = sqlerror.NewSQLErrorFromError(err)
 	if err != nil {
@@ -322,6 +390,7 @@ func (vh *vtgateHandler) ComPrepare(c *mysql.Conn, query string, bindVars map[st
 }
 
 func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+	defer vh.startCommand(c)()
 	ctx, cancel := context.WithCancel(context.Background())
 	c.UpdateCancelCtx(cancel)
 
@@ -354,6 +423,8 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 		}
 	}()
 
+	setQueryAttributes(ctx, c, session)
+
 	if session.Options.Workload == querypb.ExecuteOptions_OLAP {
 		_, err := vh.vtg.StreamExecute(ctx, vh, session, prepare.PrepareStmt, prepare.BindVars, callback)
 		if err != nil {
@@ -371,6 +442,94 @@ func (vh *vtgateHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareDat
 	return callback(qr)
 }
 
+// ComStmtBulkExecute is part of the mysql.BulkHandler interface. A single-row
+// INSERT is rewritten into one multi-row INSERT, so that the whole batch is
+// executed at once. Other statements are executed once per row.
+func (vh *vtgateHandler) ComStmtBulkExecute(c *mysql.Conn, prepare *mysql.PrepareData, bindVars []map[string]*querypb.BindVariable) (*sqltypes.Result, error) {
+	execute := func(prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
+		return vh.ComStmtExecute(c, prepare, callback)
+	}
+	if query, batchBindVars, ok := batchInsertRows(vh.Env().Parser(), prepare, bindVars); ok {
+		batch := &mysql.PrepareData{
+			StatementID: prepare.StatementID,
+			PrepareStmt: query,
+			ParamsCount: uint16(len(batchBindVars)),
+		}
+		return mysql.ExecuteBulkRows(batch, []map[string]*querypb.BindVariable{batchBindVars}, execute)
+	}
+	return mysql.ExecuteBulkRows(prepare, bindVars, execute)
+}
+
+// batchInsertRows rewrites a prepared single-row INSERT into a multi-row
+// INSERT with one row per set of bind variables. It returns false if the
+// statement is not such an INSERT, or if it has parameters outside of the
+// inserted row, e.g. in an ON DUPLICATE KEY UPDATE clause.
+func batchInsertRows(parser *sqlparser.Parser, prepare *mysql.PrepareData, rows []map[string]*querypb.BindVariable) (string, map[string]*querypb.BindVariable, bool) {
+	if len(rows) < 2 || prepare.ParamsCount == 0 {
+		return "", nil, false
+	}
+	stmt, err := parser.Parse(prepare.PrepareStmt)
+	if err != nil {
+		return "", nil, false
+	}
+	ins, ok := stmt.(*sqlparser.Insert)
+	if !ok {
+		return "", nil, false
+	}
+	values, ok := ins.Rows.(sqlparser.Values)
+	if !ok || len(values) != 1 {
+		return "", nil, false
+	}
+	countArgs := func(node sqlparser.SQLNode) (count int) {
+		_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+			if _, ok := node.(*sqlparser.Argument); ok {
+				count++
+			}
+			return true, nil
+		}, node)
+		return count
+	}
+	paramsCount := int(prepare.ParamsCount)
+	if countArgs(values[0]) != paramsCount || countArgs(ins) != paramsCount {
+		return "", nil, false
+	}
+
+	argName := func(row, param int) string {
+		return fmt.Sprintf("v%d", row*paramsCount+param)
+	}
+	batchValues := make(sqlparser.Values, 0, len(rows))
+	batchBindVars := make(map[string]*querypb.BindVariable, len(rows)*paramsCount)
+	for r, bindVars := range rows {
+		var rewriteErr error
+		row := sqlparser.SafeRewrite(sqlparser.CloneValTuple(values[0]), nil, func(cursor *sqlparser.Cursor) bool {
+			arg, ok := cursor.Node().(*sqlparser.Argument)
+			if !ok {
+				return true
+			}
+			param, err := strconv.Atoi(strings.TrimPrefix(arg.Name, "v"))
+			if err != nil {
+				rewriteErr = err
+				return false
+			}
+			cursor.Replace(sqlparser.NewTypedArgument(argName(r, param), arg.Type))
+			return true
+		}).(sqlparser.ValTuple)
+		if rewriteErr != nil {
+			return "", nil, false
+		}
+		batchValues = append(batchValues, row)
+		for param := 1; param <= paramsCount; param++ {
+			bv, ok := bindVars[fmt.Sprintf("v%d", param)]
+			if !ok {
+				return "", nil, false
+			}
+			batchBindVars[argName(r, param)] = bv
+		}
+	}
+	ins.Rows = batchValues
+	return sqlparser.String(ins), batchBindVars, true
+}
+
 func (vh *vtgateHandler) WarningCount(c *mysql.Conn) uint16 {
 	return uint16(len(vh.session(c).GetWarnings()))
 }
@@ -445,6 +604,10 @@ func (vh *vtgateHandler) session(c *mysql.Conn) *vtgatepb.Session {
 		if c.Capabilities&mysql.CapabilityClientFoundRows != 0 {
 			session.Options.ClientFoundRows = true
 		}
+		setConnectionAttributes(c, session)
+		// The tenant of the session can be chosen at connection time, so
+		// that applications don't need to run a SET on every connection.
+		session.TenantId = c.ConnectionAttributes()[sysvars.TenantID.Name]
 		c.ClientData = session
 	}
 	return session
@@ -455,6 +618,9 @@ type mysqlServer struct {
 	unixListener *mysql.Listener
 	sigChan      chan os.Signal
 	vtgateHandle *vtgateHandler
+
+	// stopReclaim stops the release of the idle reserved connections.
+	stopReclaim context.CancelFunc
 }
 
 // initTLSConfig inits tls config for the given mysql listener
@@ -500,10 +666,7 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 		return nil
 	}
 
-	// Initialize registered AuthServer implementations (or other plugins)
-	for _, initFn := range pluginInitializers {
-		initFn()
-	}
+	initPlugins()
 	authServer := mysql.GetAuthServer(mysqlAuthServerImpl)
 
 	// Check mysql_default_workload
@@ -548,6 +711,9 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 			_ = initTLSConfig(context.Background(), srv, mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, mysqlServerRequireSecureTransport, tlsVersion)
 		}
 		srv.tcpListener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+		srv.tcpListener.AllowCompression.Store(mysqlServerCompression)
+		srv.tcpListener.AllowLocalInfile.Store(mysqlServerLocalInfile)
+		srv.tcpListener.AllowBulkOperations.Store(mysqlServerBulkOperations)
 		// Check for the connection threshold
 		if mysqlSlowConnectWarnThreshold != 0 {
 			log.Infof("setting mysql slow connection threshold to %v", mysqlSlowConnectWarnThreshold)
@@ -563,6 +729,12 @@ func initMySQLProtocol(vtgate *VTGate) *mysqlServer {
 			log.Exitf("mysql.NewListener failed: %v", err)
 		}
 	}
+
+	if mysqlReservedConnIdleTimeout > 0 {
+		var ctx context.Context
+		ctx, srv.stopReclaim = context.WithCancel(context.Background())
+		go srv.vtgateHandle.reclaimIdleReservedConns(ctx, mysqlReservedConnIdleTimeout)
+	}
 	return srv
 }
 
@@ -632,6 +804,9 @@ func (srv *mysqlServer) shutdownMysqlProtocolAndDrain() {
 	if srv.sigChan != nil {
 		signal.Stop(srv.sigChan)
 	}
+	if srv.stopReclaim != nil {
+		srv.stopReclaim()
+	}
 
 	if busy := srv.vtgateHandle.busyConnections.Load(); busy > 0 {
 		log.Infof("Waiting for all client connections to be idle (%d active)...", busy)
@@ -695,9 +870,22 @@ func init() {
 	servenv.OnParseFor("vtcombo", registerPluginFlags)
 }
 
-var pluginInitializers []func()
+var (
+	pluginInitializers []func()
+	pluginsOnce        sync.Once
+)
 
 // RegisterPluginInitializer lets plugins register themselves to be init'ed at servenv.OnRun-time
 func RegisterPluginInitializer(initializer func()) {
 	pluginInitializers = append(pluginInitializers, initializer)
 }
+
+// initPlugins initializes the registered AuthServer implementations (or other
+// plugins), once for the listeners of all the protocols.
+func initPlugins() {
+	pluginsOnce.Do(func() {
+		for _, initFn := range pluginInitializers {
+			initFn()
+		}
+	})
+}
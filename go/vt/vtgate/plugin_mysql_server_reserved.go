@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+)
+
+// This file releases the reserved connections of the MySQL client sessions
+// that have been idle for longer than --mysql-server-reserved-conn-idle-timeout,
+// instead of holding them until the session is closed. The next query of the
+// session reserves new connections, and replays the system variables of the
+// session on them.
+
+var (
+	mysqlReservedConnIdleTimeout time.Duration
+
+	reservedConns          = stats.NewGaugesWithSingleLabel("MysqlServerReservedConnections", "Reserved connections held by the MySQL client sessions", "Keyspace")
+	reservedConnsReclaimed = stats.NewCountersWithSingleLabel("MysqlServerReservedConnectionsReclaimed", "Reserved connections released because their MySQL client session was idle", "Keyspace")
+)
+
+// connActivity tracks the activity of a MySQL client connection, and the
+// reserved connections of its session.
+type connActivity struct {
+	conn *mysql.Conn
+
+	// mu is held while the connection runs a command, so that the reserved
+	// connections of its session are not released concurrently.
+	mu       sync.Mutex
+	lastUsed time.Time
+	// reserved is the number of reserved connections of the session by
+	// keyspace, as of the end of the last command.
+	reserved map[string]int
+}
+
+// updateReserved updates the reserved connections of the session in the
+// reservedConns gauge. It must be called with a.mu held.
+func (a *connActivity) updateReserved(session *vtgatepb.Session) {
+	var reserved map[string]int
+	if session != nil {
+		reserved = reservedConnsByKeyspace(session)
+	}
+	for keyspace, count := range a.reserved {
+		reservedConns.Add(keyspace, -int64(count))
+	}
+	for keyspace, count := range reserved {
+		reservedConns.Add(keyspace, int64(count))
+	}
+	a.reserved = reserved
+}
+
+func reservedConnsByKeyspace(session *vtgatepb.Session) map[string]int {
+	var reserved map[string]int
+	add := func(ss *vtgatepb.Session_ShardSession) {
+		if ss == nil || ss.ReservedId == 0 {
+			return
+		}
+		if reserved == nil {
+			reserved = make(map[string]int)
+		}
+		reserved[ss.Target.GetKeyspace()]++
+	}
+	for _, shardSessions := range [][]*vtgatepb.Session_ShardSession{session.PreSessions, session.ShardSessions, session.PostSessions} {
+		for _, ss := range shardSessions {
+			add(ss)
+		}
+	}
+	add(session.LockSession)
+	return reserved
+}
+
+// startCommand must be called before a command of the connection uses its
+// session, and the returned function once the command is done.
+func (vh *vtgateHandler) startCommand(c *mysql.Conn) (done func()) {
+	vh.mu.Lock()
+	a := vh.activity[c.ConnectionID]
+	vh.mu.Unlock()
+	if a == nil {
+		return func() {}
+	}
+
+	a.mu.Lock()
+	return func() {
+		a.updateReserved(vh.session(c))
+		a.lastUsed = time.Now()
+		a.mu.Unlock()
+	}
+}
+
+// canReleaseReservedConns returns true if the reserved connections of the
+// session can be released and reserved again without losing any state.
+func canReleaseReservedConns(session *vtgatepb.Session) bool {
+	return !session.InTransaction &&
+		!session.UntrackedReservedConnState &&
+		!session.GetOptions().GetHasCreatedTempTables()
+}
+
+// releaseIdleReservedConns releases the reserved connections of the sessions
+// that have not run a command for idleTimeout. The lock sessions, which hold
+// advisory locks, are kept.
+func (vh *vtgateHandler) releaseIdleReservedConns(ctx context.Context, idleTimeout time.Duration) {
+	vh.mu.Lock()
+	activities := make([]*connActivity, 0, len(vh.activity))
+	for _, a := range vh.activity {
+		activities = append(activities, a)
+	}
+	vh.mu.Unlock()
+
+	for _, a := range activities {
+		// The connection is running a command.
+		if !a.mu.TryLock() {
+			continue
+		}
+		vh.releaseReservedConns(ctx, a, idleTimeout)
+		a.mu.Unlock()
+	}
+}
+
+// releaseReservedConns releases the reserved connections of the session of a
+// if it has been idle for idleTimeout. It must be called with a.mu held.
+func (vh *vtgateHandler) releaseReservedConns(ctx context.Context, a *connActivity, idleTimeout time.Duration) {
+	if len(a.reserved) == 0 || time.Since(a.lastUsed) < idleTimeout {
+		return
+	}
+	session, _ := a.conn.ClientData.(*vtgatepb.Session)
+	if session == nil || !canReleaseReservedConns(session) {
+		return
+	}
+
+	before := a.reserved
+	if err := vh.vtg.executor.txConn.Release(ctx, NewSafeSession(session)); err != nil {
+		log.Warningf("Failed to release the idle reserved connections of connection %d: %v", a.conn.ConnectionID, err)
+	}
+	a.updateReserved(session)
+	for keyspace, count := range before {
+		if released := count - a.reserved[keyspace]; released > 0 {
+			reservedConnsReclaimed.Add(keyspace, int64(released))
+		}
+	}
+}
+
+// reclaimIdleReservedConns releases the idle reserved connections until ctx
+// is done.
+func (vh *vtgateHandler) reclaimIdleReservedConns(ctx context.Context, idleTimeout time.Duration) {
+	ticker := time.NewTicker(idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			vh.releaseIdleReservedConns(ctx, idleTimeout)
+		}
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/trace"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/tlstest"
 	"vitess.io/vitess/go/vt/vtenv"
 )
@@ -267,6 +268,13 @@ func TestDefaultWorkloadOLAP(t *testing.T) {
 	}
 }
 
+func TestSessionConnectionAttributes(t *testing.T) {
+	vh := &vtgateHandler{}
+	sess := vh.session(mysql.GetTestConn())
+	assert.Nil(t, sess.Options.ConnectionAttributes)
+	assert.Equal(t, "a", sess.Options.ClientHost)
+}
+
 func TestInitTLSConfigWithoutServerCA(t *testing.T) {
 	testInitTLSConfig(t, false)
 }
@@ -422,3 +430,134 @@ func TestGracefulShutdownWithTransaction(t *testing.T) {
 
 	require.True(t, mysqlConn.IsMarkedForClose())
 }
+
+func TestReleaseIdleReservedConns(t *testing.T) {
+	executor, sbc1, _, _, _ := createExecutorEnv(t)
+	vh := newVtgateHandler(&VTGate{executor: executor, timings: timings, rowsReturned: rowsReturned, rowsAffected: rowsAffected})
+	th := &testHandler{}
+	listener, err := mysql.NewListener("tcp", "127.0.0.1:", mysql.NewAuthServerNone(), th, 0, 0, false, false, 0, 0)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	mysqlConn := mysql.GetTestServerConn(listener)
+	mysqlConn.ConnectionID = 1
+	mysqlConn.UserData = &mysql.StaticUserData{}
+	vh.NewConnection(mysqlConn)
+	a := vh.activity[1]
+
+	// The session has a reserved connection, with a system variable to replay.
+	session := vh.session(mysqlConn)
+	session.TargetString = "TestExecutor"
+	session.InReservedConn = true
+	session.SystemVariables = map[string]string{"sql_mode": "''"}
+	query := func() {
+		err := vh.ComQuery(mysqlConn, "select id from user where id = 1", func(result *sqltypes.Result) error {
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	query()
+	require.EqualValues(t, 1, sbc1.ReserveCount.Load())
+	assert.Equal(t, map[string]int{KsTestSharded: 1}, a.reserved)
+	reclaimedBefore := reservedConnsReclaimed.Counts()[KsTestSharded]
+
+	// The connection is not idle yet.
+	vh.releaseIdleReservedConns(context.Background(), time.Hour)
+	assert.Len(t, session.ShardSessions, 1)
+	assert.Zero(t, sbc1.ReleaseCount.Load())
+
+	// A session in a transaction keeps its reserved connections.
+	session.InTransaction = true
+	vh.releaseIdleReservedConns(context.Background(), 0)
+	assert.Len(t, session.ShardSessions, 1)
+	session.InTransaction = false
+
+	vh.releaseIdleReservedConns(context.Background(), 0)
+	assert.Empty(t, session.ShardSessions)
+	assert.True(t, session.InReservedConn)
+	assert.EqualValues(t, 1, sbc1.ReleaseCount.Load())
+	assert.Empty(t, a.reserved)
+	assert.EqualValues(t, 1, reservedConnsReclaimed.Counts()[KsTestSharded]-reclaimedBefore)
+
+	// The next query reserves a new connection, and replays the system variables.
+	sbc1.Queries = nil
+	query()
+	require.EqualValues(t, 2, sbc1.ReserveCount.Load())
+	assert.Equal(t, map[string]int{KsTestSharded: 1}, a.reserved)
+	assert.Equal(t, "set sql_mode = ''", sbc1.Queries[0].Sql)
+
+	// The temporary tables of a session cannot be replayed.
+	session.Options.HasCreatedTempTables = true
+	vh.releaseIdleReservedConns(context.Background(), 0)
+	assert.Len(t, session.ShardSessions, 1)
+
+	vh.ConnectionClosed(mysqlConn)
+	assert.Empty(t, a.reserved)
+	assert.NotContains(t, vh.activity, uint32(1))
+}
+
+func TestBatchInsertRows(t *testing.T) {
+	rows := []map[string]*querypb.BindVariable{
+		{"v1": sqltypes.Int64BindVariable(1), "v2": sqltypes.StringBindVariable("a")},
+		{"v1": sqltypes.Int64BindVariable(2), "v2": sqltypes.NullBindVariable},
+	}
+	tcases := []struct {
+		name     string
+		query    string
+		params   uint16
+		rows     []map[string]*querypb.BindVariable
+		expected string
+	}{
+		{
+			name:     "insert",
+			query:    "insert into t(id, name) values (?, ?)",
+			params:   2,
+			rows:     rows,
+			expected: "insert into t(id, `name`) values (:v1, :v2), (:v3, :v4)",
+		},
+		{
+			name:     "insert with expression",
+			query:    "insert into t(id, name) values (? + 1, concat(?, 'x'))",
+			params:   2,
+			rows:     rows,
+			expected: "insert into t(id, `name`) values (:v1 + 1, concat(:v2, 'x')), (:v3 + 1, concat(:v4, 'x'))",
+		},
+		{
+			name:   "arguments outside of values",
+			query:  "insert into t(id, name) values (?, ?) on duplicate key update name = ?",
+			params: 3,
+			rows:   rows,
+		},
+		{
+			name:   "not an insert",
+			query:  "update t set name = ? where id = ?",
+			params: 2,
+			rows:   rows,
+		},
+		{
+			name:   "single row",
+			query:  "insert into t(id, name) values (?, ?)",
+			params: 2,
+			rows:   rows[:1],
+		},
+	}
+	parser := sqlparser.NewTestParser()
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			prepare := &mysql.PrepareData{PrepareStmt: tcase.query, ParamsCount: tcase.params}
+			query, bindVars, ok := batchInsertRows(parser, prepare, tcase.rows)
+			if tcase.expected == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tcase.expected, query)
+			assert.Equal(t, map[string]*querypb.BindVariable{
+				"v1": rows[0]["v1"],
+				"v2": rows[0]["v2"],
+				"v3": rows[1]["v1"],
+				"v4": rows[1]["v2"],
+			}, bindVars)
+		})
+	}
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/mysqlx"
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/callinfo"
+	"vitess.io/vitess/go/vt/log"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/sysvars"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttls"
+)
+
+// The X Protocol listener shares the authentication, TLS and timeout flags of
+// the MySQL binary protocol listener.
+var (
+	mysqlxServerPort        = -1
+	mysqlxServerBindAddress string
+)
+
+func registerMysqlxPluginFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&mysqlxServerPort, "mysqlx_server_port", mysqlxServerPort, "If set, also listen for MySQL X Protocol connections, of the X DevAPI connectors, on this port. The listener uses the auth server, TLS and timeout flags of the MySQL binary protocol.")
+	fs.StringVar(&mysqlxServerBindAddress, "mysqlx_server_bind_address", mysqlxServerBindAddress, "Binds on this address when listening to MySQL X Protocol. Useful to restrict listening to 'localhost' only for instance.")
+}
+
+// mysqlxHandler implements the mysqlx.Handler interface.
+// It stores the Session in the ClientData of a Connection.
+type mysqlxHandler struct {
+	mu sync.Mutex
+
+	vtg         *VTGate
+	connections map[uint32]*mysqlx.Conn
+	// cancels cancels the query being executed on a connection.
+	cancels map[uint32]context.CancelFunc
+
+	busyConnections atomic.Int32
+}
+
+func newMysqlxHandler(vtg *VTGate) *mysqlxHandler {
+	return &mysqlxHandler{
+		vtg:         vtg,
+		connections: make(map[uint32]*mysqlx.Conn),
+		cancels:     make(map[uint32]context.CancelFunc),
+	}
+}
+
+func (xh *mysqlxHandler) NewConnection(c *mysqlx.Conn) {
+	xh.mu.Lock()
+	defer xh.mu.Unlock()
+	xh.connections[c.ConnectionID] = c
+}
+
+func (xh *mysqlxHandler) numConnections() int {
+	xh.mu.Lock()
+	defer xh.mu.Unlock()
+	return len(xh.connections)
+}
+
+func (xh *mysqlxHandler) ComResetConnection(c *mysqlx.Conn) {
+	session := xh.session(c)
+	if session.InTransaction {
+		defer xh.busyConnections.Add(-1)
+	}
+	if err := xh.vtg.CloseSession(context.Background(), session); err != nil {
+		log.Errorf("Error happened in transaction rollback: %v", err)
+	}
+	// The next statements start a new session.
+	c.ClientData = nil
+}
+
+func (xh *mysqlxHandler) ConnectionClosed(c *mysqlx.Conn) {
+	// Rollback if there is an ongoing transaction. Ignore error.
+	defer func() {
+		xh.mu.Lock()
+		delete(xh.connections, c.ConnectionID)
+		xh.mu.Unlock()
+	}()
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if mysqlQueryTimeout != 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), mysqlQueryTimeout)
+		defer cancel()
+	} else {
+		ctx = context.Background()
+	}
+	session := xh.session(c)
+	if session.InTransaction {
+		defer xh.busyConnections.Add(-1)
+	}
+	_ = xh.vtg.CloseSession(ctx, session)
+}
+
+func (xh *mysqlxHandler) ComQuery(c *mysqlx.Conn, query string, callback func(*sqltypes.Result) error) error {
+	session := xh.session(c)
+	if c.IsShuttingDown() && !session.InTransaction {
+		return sqlerror.NewSQLError(sqlerror.ERServerShutdown, sqlerror.SSNetError, "Server shutdown in progress")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	xh.mu.Lock()
+	xh.cancels[c.ConnectionID] = cancel
+	xh.mu.Unlock()
+	defer func() {
+		xh.mu.Lock()
+		delete(xh.cancels, c.ConnectionID)
+		xh.mu.Unlock()
+		cancel()
+	}()
+
+	if mysqlQueryTimeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, mysqlQueryTimeout)
+		defer cancel()
+	}
+
+	span, ctx, err := startSpan(ctx, query, "mysqlxHandler.ComQuery")
+	if err != nil {
+		return vterrors.Wrap(err, "failed to extract span")
+	}
+	defer span.Finish()
+
+	ctx = callinfo.MysqlxCallInfo(ctx, c)
+
+	// As for the MySQL binary protocol, the UserData returned by the
+	// AuthServer plugin is the ImmediateCallerID.
+	im := c.UserData.Get()
+	ef := callerid.NewEffectiveCallerID(
+		c.User,                  /* principal: who */
+		c.RemoteAddr().String(), /* component: running client process */
+		"VTGate MySQL X Connector" /* subcomponent: part of the client */)
+	ctx = callerid.NewContext(ctx, ef, im)
+
+	if !session.InTransaction {
+		xh.busyConnections.Add(1)
+	}
+	defer func() {
+		if !session.InTransaction {
+			xh.busyConnections.Add(-1)
+		}
+	}()
+
+	if session.Options.Workload == querypb.ExecuteOptions_OLAP {
+		_, err := xh.vtg.StreamExecute(ctx, xh, session, query, make(map[string]*querypb.BindVariable), callback)
+		return sqlerror.NewSQLErrorFromError(err)
+	}
+	_, result, err := xh.vtg.Execute(ctx, xh, session, query, make(map[string]*querypb.BindVariable))
+	if err := sqlerror.NewSQLErrorFromError(err); err != nil {
+		return err
+	}
+	return callback(result)
+}
+
+// KillConnection closes an open connection by connection ID.
+func (xh *mysqlxHandler) KillConnection(ctx context.Context, connectionID uint32) error {
+	xh.mu.Lock()
+	defer xh.mu.Unlock()
+
+	c, exists := xh.connections[connectionID]
+	if !exists {
+		return sqlerror.NewSQLError(sqlerror.ERNoSuchThread, sqlerror.SSUnknownSQLState, "Unknown thread id: %d", connectionID)
+	}
+
+	// Closing the connection makes its session close, which rolls back its
+	// open transaction once its current query, cancelled here, returns.
+	c.Close()
+	if cancel := xh.cancels[connectionID]; cancel != nil {
+		cancel()
+	}
+	return nil
+}
+
+// KillQuery cancels any execution query on the provided connection ID.
+func (xh *mysqlxHandler) KillQuery(connectionID uint32) error {
+	xh.mu.Lock()
+	defer xh.mu.Unlock()
+	if _, exists := xh.connections[connectionID]; !exists {
+		return sqlerror.NewSQLError(sqlerror.ERNoSuchThread, sqlerror.SSUnknownSQLState, "Unknown thread id: %d", connectionID)
+	}
+	if cancel := xh.cancels[connectionID]; cancel != nil {
+		cancel()
+	}
+	return nil
+}
+
+func (xh *mysqlxHandler) session(c *mysqlx.Conn) *vtgatepb.Session {
+	session, _ := c.ClientData.(*vtgatepb.Session)
+	if session == nil {
+		u, _ := uuid.NewUUID()
+		session = &vtgatepb.Session{
+			Options: &querypb.ExecuteOptions{
+				IncludedFields: querypb.ExecuteOptions_ALL,
+				Workload:       querypb.ExecuteOptions_Workload(mysqlDefaultWorkload),
+			},
+			Autocommit:           true,
+			DDLStrategy:          defaultDDLStrategy,
+			SessionUUID:          u.String(),
+			EnableSystemSettings: sysVarSetEnabled,
+		}
+		session.Options.ConnectionAttributes = c.Attributes
+		addr := c.RemoteAddr().String()
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		session.Options.ClientHost = host
+		session.TenantId = c.Attributes[sysvars.TenantID.Name]
+		c.ClientData = session
+	}
+	return session
+}
+
+type mysqlxServer struct {
+	listener *mysqlx.Listener
+	handler  *mysqlxHandler
+}
+
+// initMysqlxProtocol starts the X Protocol listener.
+// It should be called only once in a process.
+func initMysqlxProtocol(vtgate *VTGate) *mysqlxServer {
+	if mysqlxServerPort < 0 || vtgate == nil {
+		return nil
+	}
+
+	initPlugins()
+	authServer := mysql.GetAuthServer(mysqlAuthServerImpl)
+
+	var ok bool
+	if mysqlDefaultWorkload, ok = querypb.ExecuteOptions_Workload_value[strings.ToUpper(mysqlDefaultWorkloadName)]; !ok {
+		log.Exitf("-mysql_default_workload must be one of [OLTP, OLAP, DBA, UNSPECIFIED]")
+	}
+
+	srv := &mysqlxServer{handler: newMysqlxHandler(vtgate)}
+	var err error
+	srv.listener, err = mysqlx.NewListener(
+		mysqlTCPVersion,
+		net.JoinHostPort(mysqlxServerBindAddress, fmt.Sprintf("%v", mysqlxServerPort)),
+		authServer,
+		srv.handler,
+		mysqlConnReadTimeout,
+		mysqlConnWriteTimeout,
+	)
+	if err != nil {
+		log.Exitf("mysqlx.NewListener failed: %v", err)
+	}
+	if mysqlSslCert != "" && mysqlSslKey != "" {
+		tlsVersion, err := vttls.TLSVersionToNumber(mysqlTLSMinVersion)
+		if err != nil {
+			log.Exitf("mysqlx.NewListener failed: %v", err)
+		}
+		serverConfig, err := vttls.ServerConfig(mysqlSslCert, mysqlSslKey, mysqlSslCa, mysqlSslCrl, mysqlSslServerCA, tlsVersion)
+		if err != nil {
+			log.Exitf("mysqlx.NewListener failed: %v", err)
+		}
+		srv.listener.TLSConfig.Store(serverConfig)
+		srv.listener.RequireSecureTransport = mysqlServerRequireSecureTransport
+	}
+	srv.listener.AllowClearTextWithoutTLS.Store(mysqlAllowClearTextWithoutTLS)
+	go srv.listener.Accept()
+	return srv
+}
+
+func (srv *mysqlxServer) shutdownAndDrain() {
+	srv.listener.Shutdown()
+
+	if busy := srv.handler.busyConnections.Load(); busy > 0 {
+		log.Infof("Waiting for all X Protocol client connections to be idle (%d active)...", busy)
+		start := time.Now()
+		reported := start
+		for busy > 0 {
+			if time.Since(reported) > 2*time.Second {
+				log.Infof("Still waiting for X Protocol client connections to be idle (%d active)...", busy)
+				reported = time.Now()
+			}
+
+			time.Sleep(1 * time.Millisecond)
+			busy = srv.handler.busyConnections.Load()
+		}
+	}
+}
+
+func (srv *mysqlxServer) rollbackAtShutdown() {
+	defer log.Flush()
+
+	// Close all open connections, which rolls back their open transactions.
+	func() {
+		srv.handler.mu.Lock()
+		defer srv.handler.mu.Unlock()
+		for id, c := range srv.handler.connections {
+			log.Infof("Rolling back transactions associated with X Protocol connection ID: %v", id)
+			c.Close()
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		if srv.handler.numConnections() == 0 {
+			log.Infof("All X Protocol connections have been rolled back.")
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	log.Errorf("All X Protocol connections did not go idle. Shutting down anyway.")
+}
+
+func init() {
+	servenv.OnParseFor("vtgate", registerMysqlxPluginFlags)
+	servenv.OnParseFor("vtcombo", registerMysqlxPluginFlags)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota enforces the query rate and concurrency limits that are
+// configured as quota rules in the SrvVSchema.
+package quota
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// Wildcard is the selector value that matches every user, keyspace or
+// workload, and gives each of them its own limits.
+const Wildcard = "*"
+
+// Enforcer admits or rejects queries according to a set of quota rules.
+// It is safe for concurrent use.
+type Enforcer struct {
+	rejections *stats.CountersWithMultiLabels
+	now        func() time.Time
+
+	mu    sync.RWMutex
+	rules []*rule
+}
+
+// NewEnforcer returns an Enforcer without any rules. Rejected queries are
+// counted in rejections, by rule and by limit, if it is not nil.
+func NewEnforcer(rejections *stats.CountersWithMultiLabels) *Enforcer {
+	return &Enforcer{
+		rejections: rejections,
+		now:        time.Now,
+	}
+}
+
+// SetRules replaces the rules of the enforcer. The state of the rules that
+// did not change is kept, so that reloading the same rules does not reset
+// their limits.
+func (e *Enforcer) SetRules(specs []*vschemapb.QuotaRule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rules := make([]*rule, 0, len(specs))
+	for _, spec := range specs {
+		r := e.findRule(spec)
+		if r == nil {
+			r = &rule{
+				spec:     spec,
+				limiters: make(map[limiterKey]*limiter),
+			}
+		}
+		rules = append(rules, r)
+	}
+	e.rules = rules
+}
+
+func (e *Enforcer) findRule(spec *vschemapb.QuotaRule) *rule {
+	for _, r := range e.rules {
+		if proto.Equal(r.spec, spec) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Acquire admits a query of the given user and workload that accesses the
+// given keyspaces. On success, the returned function must be called once
+// the query has finished executing. If any of the matching rules is over
+// its limits, the query is rejected with a VT08001 error.
+func (e *Enforcer) Acquire(user string, keyspaces []string, workload string) (func(), error) {
+	e.mu.RLock()
+	rules := e.rules
+	e.mu.RUnlock()
+
+	if len(rules) == 0 {
+		return func() {}, nil
+	}
+	if len(keyspaces) == 0 {
+		keyspaces = []string{""}
+	}
+
+	var held []*limiter
+	release := func() {
+		for _, l := range held {
+			l.release()
+		}
+	}
+
+	now := e.now()
+	for _, r := range rules {
+		for _, keyspace := range keyspaces {
+			if !r.matches(user, keyspace, workload) {
+				continue
+			}
+			l := r.limiterFor(user, keyspace, workload, now)
+			if containsLimiter(held, l) {
+				continue
+			}
+			if limit, err := l.admit(r.spec, now); err != nil {
+				release()
+				if e.rejections != nil {
+					e.rejections.Add([]string{r.spec.Name, limit}, 1)
+				}
+				return nil, err
+			}
+			held = append(held, l)
+		}
+	}
+	return release, nil
+}
+
+func containsLimiter(limiters []*limiter, l *limiter) bool {
+	for _, other := range limiters {
+		if other == l {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateRules checks that the rules are well formed.
+func ValidateRules(rules *vschemapb.QuotaRules) error {
+	names := make(map[string]bool, len(rules.GetRules()))
+	for _, spec := range rules.GetRules() {
+		if spec.Name == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "quota rule must have a name")
+		}
+		if names[spec.Name] {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate quota rule %s", spec.Name)
+		}
+		names[spec.Name] = true
+
+		if spec.MaxQps < 0 || math.IsNaN(spec.MaxQps) || math.IsInf(spec.MaxQps, 0) {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "quota rule %s: invalid max_qps %v", spec.Name, spec.MaxQps)
+		}
+		if spec.MaxQps == 0 && spec.MaxConcurrency == 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "quota rule %s must set max_qps or max_concurrency", spec.Name)
+		}
+		if spec.Burst > 0 && spec.MaxQps == 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "quota rule %s: burst requires max_qps", spec.Name)
+		}
+	}
+	return nil
+}
+
+type rule struct {
+	spec *vschemapb.QuotaRule
+
+	mu       sync.Mutex
+	limiters map[limiterKey]*limiter
+}
+
+// limiterKey holds the values of the wildcard selectors of a rule. The other
+// selectors are left empty, so that all matching queries share a limiter.
+type limiterKey struct {
+	user, keyspace, workload string
+}
+
+func selectorMatches(selector, value string) bool {
+	return selector == "" || selector == Wildcard || selector == value
+}
+
+func (r *rule) matches(user, keyspace, workload string) bool {
+	return selectorMatches(r.spec.User, user) &&
+		selectorMatches(r.spec.Keyspace, keyspace) &&
+		selectorMatches(r.spec.Workload, workload)
+}
+
+func (r *rule) limiterFor(user, keyspace, workload string, now time.Time) *limiter {
+	var key limiterKey
+	if r.spec.User == Wildcard {
+		key.user = user
+	}
+	if r.spec.Keyspace == Wildcard {
+		key.keyspace = keyspace
+	}
+	if r.spec.Workload == Wildcard {
+		key.workload = workload
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.limiters[key]
+	if !ok {
+		l = &limiter{}
+		if r.spec.MaxQps > 0 {
+			burst := float64(r.spec.Burst)
+			if burst == 0 {
+				burst = math.Max(1, math.Ceil(r.spec.MaxQps))
+			}
+			l.bucket = newTokenBucket(r.spec.MaxQps, burst, now)
+		}
+		r.limiters[key] = l
+	}
+	return l
+}
+
+// limiter holds the state of the limits of a rule for one limiterKey.
+type limiter struct {
+	mu       sync.Mutex
+	bucket   *tokenBucket
+	inflight uint32
+}
+
+// admit admits a query if both the concurrency and the rate limits allow it.
+// When it does not, it returns the name of the exceeded limit and the error
+// to return to the client.
+func (l *limiter) admit(spec *vschemapb.QuotaRule, now time.Time) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if spec.MaxConcurrency > 0 && l.inflight >= spec.MaxConcurrency {
+		return "Concurrency", vterrors.VT08001(spec.Name, fmt.Sprintf("concurrency limit of %d queries", spec.MaxConcurrency))
+	}
+	if l.bucket != nil && !l.bucket.take(now) {
+		return "Rate", vterrors.VT08001(spec.Name, fmt.Sprintf("rate limit of %v queries per second", spec.MaxQps))
+	}
+	l.inflight++
+	return "", nil
+}
+
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inflight--
+}
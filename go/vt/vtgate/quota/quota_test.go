@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func newTestEnforcer(t *testing.T, rules ...*vschemapb.QuotaRule) (*Enforcer, *time.Time, *stats.CountersWithMultiLabels) {
+	rejections := stats.NewCountersWithMultiLabels("", "", []string{"Rule", "Limit"})
+	e := NewEnforcer(rejections)
+	now := time.Unix(1000, 0)
+	e.now = func() time.Time { return now }
+	e.SetRules(rules)
+	return e, &now, rejections
+}
+
+func TestEnforcerNoRules(t *testing.T) {
+	e, _, _ := newTestEnforcer(t)
+	for i := 0; i < 100; i++ {
+		release, err := e.Acquire("user", []string{"ks"}, "")
+		require.NoError(t, err)
+		release()
+	}
+}
+
+func TestEnforcerRate(t *testing.T) {
+	e, now, rejections := newTestEnforcer(t, &vschemapb.QuotaRule{
+		Name:   "user1",
+		User:   "user1",
+		MaxQps: 2,
+	})
+
+	for i := 0; i < 2; i++ {
+		release, err := e.Acquire("user1", nil, "")
+		require.NoError(t, err)
+		release()
+	}
+	_, err := e.Acquire("user1", nil, "")
+	require.EqualError(t, err, "VT08001: quota 'user1' exceeded: rate limit of 2 queries per second")
+	assert.Equal(t, vtrpcpb.Code_RESOURCE_EXHAUSTED, vterrors.Code(err))
+	assert.EqualValues(t, 1, rejections.Counts()["user1.Rate"])
+
+	// Other users are not limited.
+	release, err := e.Acquire("user2", nil, "")
+	require.NoError(t, err)
+	release()
+
+	// The bucket refills over time.
+	*now = now.Add(500 * time.Millisecond)
+	release, err = e.Acquire("user1", nil, "")
+	require.NoError(t, err)
+	release()
+	_, err = e.Acquire("user1", nil, "")
+	require.Error(t, err)
+}
+
+func TestEnforcerConcurrency(t *testing.T) {
+	e, _, rejections := newTestEnforcer(t, &vschemapb.QuotaRule{
+		Name:           "ks",
+		Keyspace:       "ks",
+		MaxConcurrency: 2,
+	})
+
+	release1, err := e.Acquire("user1", []string{"ks"}, "")
+	require.NoError(t, err)
+	release2, err := e.Acquire("user2", []string{"other", "ks"}, "")
+	require.NoError(t, err)
+	_, err = e.Acquire("user3", []string{"ks"}, "")
+	require.EqualError(t, err, "VT08001: quota 'ks' exceeded: concurrency limit of 2 queries")
+	assert.EqualValues(t, 1, rejections.Counts()["ks.Concurrency"])
+
+	// Queries on other keyspaces are not limited.
+	release3, err := e.Acquire("user3", []string{"other"}, "")
+	require.NoError(t, err)
+	release3()
+
+	release1()
+	release3, err = e.Acquire("user3", []string{"ks"}, "")
+	require.NoError(t, err)
+	release2()
+	release3()
+}
+
+func TestEnforcerWildcard(t *testing.T) {
+	e, _, _ := newTestEnforcer(t, &vschemapb.QuotaRule{
+		Name:           "per-user",
+		User:           Wildcard,
+		Workload:       "batch",
+		MaxConcurrency: 1,
+	})
+
+	release1, err := e.Acquire("user1", nil, "batch")
+	require.NoError(t, err)
+	defer release1()
+	_, err = e.Acquire("user1", nil, "batch")
+	require.Error(t, err)
+
+	// Each user gets its own limit.
+	release2, err := e.Acquire("user2", nil, "batch")
+	require.NoError(t, err)
+	defer release2()
+
+	// Other workloads are not limited.
+	release3, err := e.Acquire("user1", nil, "oltp")
+	require.NoError(t, err)
+	release3()
+}
+
+func TestEnforcerRejectionReleases(t *testing.T) {
+	e, _, _ := newTestEnforcer(t, &vschemapb.QuotaRule{
+		Name:           "all",
+		MaxConcurrency: 1,
+	}, &vschemapb.QuotaRule{
+		Name:   "user1",
+		User:   "user1",
+		MaxQps: 1,
+	})
+
+	release, err := e.Acquire("user1", nil, "")
+	require.NoError(t, err)
+	release()
+
+	// The rate limit of user1 rejects the query, which must not keep the
+	// concurrency slot of the first rule.
+	_, err = e.Acquire("user1", nil, "")
+	require.ErrorContains(t, err, "quota 'user1' exceeded")
+	release, err = e.Acquire("user2", nil, "")
+	require.NoError(t, err)
+	release()
+}
+
+func TestEnforcerSetRulesKeepsState(t *testing.T) {
+	spec := &vschemapb.QuotaRule{
+		Name:           "all",
+		MaxConcurrency: 1,
+	}
+	e, _, _ := newTestEnforcer(t, spec)
+
+	release, err := e.Acquire("user1", nil, "")
+	require.NoError(t, err)
+
+	e.SetRules([]*vschemapb.QuotaRule{{
+		Name:           "all",
+		MaxConcurrency: 1,
+	}})
+	_, err = e.Acquire("user1", nil, "")
+	require.Error(t, err)
+
+	e.SetRules([]*vschemapb.QuotaRule{{
+		Name:           "all",
+		MaxConcurrency: 2,
+	}})
+	release2, err := e.Acquire("user1", nil, "")
+	require.NoError(t, err)
+	release2()
+	release()
+
+	e.SetRules(nil)
+	release, err = e.Acquire("user1", nil, "")
+	require.NoError(t, err)
+	release()
+}
+
+func TestValidateRules(t *testing.T) {
+	tcases := []struct {
+		rules *vschemapb.QuotaRules
+		err   string
+	}{{
+		rules: nil,
+	}, {
+		rules: &vschemapb.QuotaRules{Rules: []*vschemapb.QuotaRule{{Name: "a", MaxQps: 10, Burst: 20}, {Name: "b", MaxConcurrency: 1}}},
+	}, {
+		rules: &vschemapb.QuotaRules{Rules: []*vschemapb.QuotaRule{{MaxQps: 10}}},
+		err:   "quota rule must have a name",
+	}, {
+		rules: &vschemapb.QuotaRules{Rules: []*vschemapb.QuotaRule{{Name: "a", MaxQps: 10}, {Name: "a", MaxConcurrency: 1}}},
+		err:   "duplicate quota rule a",
+	}, {
+		rules: &vschemapb.QuotaRules{Rules: []*vschemapb.QuotaRule{{Name: "a", MaxQps: -1}}},
+		err:   "quota rule a: invalid max_qps -1",
+	}, {
+		rules: &vschemapb.QuotaRules{Rules: []*vschemapb.QuotaRule{{Name: "a", User: "user1"}}},
+		err:   "quota rule a must set max_qps or max_concurrency",
+	}, {
+		rules: &vschemapb.QuotaRules{Rules: []*vschemapb.QuotaRule{{Name: "a", Burst: 5, MaxConcurrency: 1}}},
+		err:   "quota rule a: burst requires max_qps",
+	}}
+	for _, tcase := range tcases {
+		err := ValidateRules(tcase.rules)
+		if tcase.err == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.EqualError(t, err, tcase.err)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tb := newTokenBucket(10, 3, now)
+
+	// The bucket starts full.
+	for i := 0; i < 3; i++ {
+		assert.True(t, tb.take(now))
+	}
+	assert.False(t, tb.take(now))
+
+	// One token is added every 100ms.
+	now = now.Add(50 * time.Millisecond)
+	assert.False(t, tb.take(now))
+	now = now.Add(50 * time.Millisecond)
+	assert.True(t, tb.take(now))
+	assert.False(t, tb.take(now))
+
+	// The bucket never holds more than burst tokens.
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		assert.True(t, tb.take(now))
+	}
+	assert.False(t, tb.take(now))
+
+	// Going back in time does not add tokens.
+	assert.False(t, tb.take(now.Add(-time.Second)))
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	// readRetried counts every retry of a failed read.
+	readRetried = "Retried"
+	// readRecovered counts the reads that succeeded after being retried.
+	readRecovered = "Recovered"
+	// readSurfaced counts the retryable reads whose error was returned to the
+	// client, because the retries were exhausted or did not apply.
+	readSurfaced = "Surfaced"
+)
+
+var readRetries = stats.NewCountersWithSingleLabel(
+	"VtgateReadRetries",
+	"Single-shard reads that failed with a transient tablet error, by outcome",
+	"Outcome",
+	readRetried, readRecovered, readSurfaced)
+
+// readRetryPolicy retries the single-shard reads that fail because their
+// tablet is briefly unavailable, for example while it restarts. The tablet
+// gateway already moves on to another healthy tablet of the shard; this
+// policy covers the case where there is none, by waiting with an exponential
+// backoff before executing the read again.
+type readRetryPolicy struct {
+	// retries is the maximum number of times a read is retried. Zero
+	// disables the policy.
+	retries int
+
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func newReadRetryPolicy() *readRetryPolicy {
+	return &readRetryPolicy{
+		retries:        readRetryCount,
+		initialBackoff: readRetryInitialBackoff,
+		maxBackoff:     readRetryMaxBackoff,
+	}
+}
+
+// backoff returns how long to wait before the given retry, counting from 1.
+func (p *readRetryPolicy) backoff(retry int) time.Duration {
+	d := p.initialBackoff
+	for i := 1; i < retry && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, p.maxBackoff)
+}
+
+// execute runs exec, and runs it again while it fails with a transient error
+// and the plan is an idempotent read that the session allows to retry.
+func (p *readRetryPolicy) execute(ctx context.Context, plan *engine.Plan, safeSession *SafeSession, exec func() (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	qr, err := exec()
+	if err == nil || p.retries <= 0 || !isTransientError(err) || !canRetryRead(plan, safeSession) {
+		return qr, err
+	}
+
+	for retry := 1; retry <= p.retries; retry++ {
+		timer := time.NewTimer(p.backoff(retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			readRetries.Add(readSurfaced, 1)
+			return nil, err
+		case <-timer.C:
+		}
+
+		readRetries.Add(readRetried, 1)
+		qr, err = exec()
+		if err == nil {
+			readRetries.Add(readRecovered, 1)
+			return qr, nil
+		}
+		if !isTransientError(err) {
+			break
+		}
+	}
+	readRetries.Add(readSurfaced, 1)
+	return nil, err
+}
+
+// canRetryRead tells whether the plan can be executed again for the session
+// without any side effect.
+func canRetryRead(plan *engine.Plan, safeSession *SafeSession) bool {
+	if safeSession.InTransaction() || safeSession.InReservedConn() || safeSession.GetSkipReadRetry() {
+		return false
+	}
+	return isIdempotentRead(plan)
+}
+
+// isIdempotentRead tells whether the plan is a read that is sent to a single
+// shard. Sequences are read with a SELECT too, but every read of a sequence
+// reserves new values, so their routes are not idempotent.
+func isIdempotentRead(plan *engine.Plan) bool {
+	if plan.Type != sqlparser.StmtSelect {
+		return false
+	}
+	route, ok := plan.Instructions.(*engine.Route)
+	if !ok {
+		return false
+	}
+	switch route.Opcode {
+	case engine.Unsharded, engine.EqualUnique, engine.Reference:
+		return true
+	}
+	return false
+}
+
+// isTransientError tells whether the error comes from a tablet that could not
+// serve the query for now, and may succeed later. Other FAILED_PRECONDITION
+// errors, such as a query rejected by the query rules, fail again on retry, so
+// only the ones a tablet returns while it changes type in a reparent count.
+func isTransientError(err error) bool {
+	switch vterrors.Code(err) {
+	case vtrpcpb.Code_UNAVAILABLE, vtrpcpb.Code_CLUSTER_EVENT:
+		return true
+	case vtrpcpb.Code_FAILED_PRECONDITION:
+		msg := err.Error()
+		return vterrors.RxWrongTablet.MatchString(msg) || vterrors.RxOp.MatchString(msg)
+	}
+	return false
+}
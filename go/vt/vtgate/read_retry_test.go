@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
+func TestReadRetryBackoff(t *testing.T) {
+	p := &readRetryPolicy{initialBackoff: 10 * time.Millisecond, maxBackoff: 50 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 20*time.Millisecond, p.backoff(2))
+	assert.Equal(t, 40*time.Millisecond, p.backoff(3))
+	assert.Equal(t, 50*time.Millisecond, p.backoff(4))
+	assert.Equal(t, 50*time.Millisecond, p.backoff(100))
+}
+
+func TestIsTransientError(t *testing.T) {
+	tcases := []struct {
+		err    error
+		expect bool
+	}{
+		{err: vterrors.New(vtrpcpb.Code_UNAVAILABLE, "connection refused"), expect: true},
+		{err: vterrors.New(vtrpcpb.Code_CLUSTER_EVENT, vterrors.NotServing), expect: true},
+		{err: vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, vterrors.WrongTablet+": REPLICA, want: PRIMARY"), expect: true},
+		{err: vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, vterrors.ShuttingDown), expect: true},
+		{err: vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, "disallowed due to rule: enforce denied tables"), expect: false},
+		{err: vterrors.New(vtrpcpb.Code_INVALID_ARGUMENT, "syntax error"), expect: false},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tcase.expect, isTransientError(tcase.err))
+		})
+	}
+}
+
+func TestExecutorReadRetry(t *testing.T) {
+	executor, sbc1, _, sbclookup, ctx := createExecutorEnv(t)
+	executor.readRetry = &readRetryPolicy{retries: 3, initialBackoff: time.Millisecond, maxBackoff: 2 * time.Millisecond}
+
+	tcases := []struct {
+		name    string
+		sql     string
+		session *vtgatepb.Session
+		failing int
+		retried bool
+	}{{
+		name:    "single shard read",
+		sql:     "select id from user where id = 1",
+		failing: 2,
+		retried: true,
+	}, {
+		name:    "unsharded read",
+		sql:     "select id from main1",
+		failing: 1,
+		retried: true,
+	}, {
+		name:    "retries exhausted",
+		sql:     "select id from user where id = 1",
+		failing: 4,
+		retried: true,
+	}, {
+		name:    "scatter read",
+		sql:     "select id from user",
+		failing: 1,
+	}, {
+		name:    "write",
+		sql:     "update user set a = 1 where id = 1",
+		failing: 1,
+	}, {
+		name:    "sequence",
+		sql:     "select next value from user_seq",
+		failing: 1,
+	}, {
+		name:    "session opted out",
+		sql:     "select id from user where id = 1",
+		session: &vtgatepb.Session{TargetString: "@primary", SkipReadRetry: true},
+		failing: 1,
+	}, {
+		name:    "in transaction",
+		sql:     "select id from user where id = 1",
+		session: &vtgatepb.Session{TargetString: "@primary", InTransaction: true},
+		failing: 1,
+	}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			sbc1.ExecCount.Store(0)
+			sbclookup.ExecCount.Store(0)
+			sbc1.MustFailCodes[vtrpcpb.Code_UNAVAILABLE] = tcase.failing
+			sbclookup.MustFailCodes[vtrpcpb.Code_UNAVAILABLE] = tcase.failing
+			defer func() {
+				sbc1.MustFailCodes[vtrpcpb.Code_UNAVAILABLE] = 0
+				sbclookup.MustFailCodes[vtrpcpb.Code_UNAVAILABLE] = 0
+			}()
+			retried := readRetries.Counts()[readRetried]
+			surfaced := readRetries.Counts()[readSurfaced]
+
+			session := tcase.session
+			if session == nil {
+				session = &vtgatepb.Session{TargetString: "@primary", Autocommit: true}
+			}
+			_, err := executorExec(ctx, executor, session, tcase.sql, nil)
+
+			recovered := tcase.retried && tcase.failing <= 3
+			if recovered {
+				require.NoError(t, err)
+				assert.EqualValues(t, tcase.failing, readRetries.Counts()[readRetried]-retried)
+				assert.EqualValues(t, 0, readRetries.Counts()[readSurfaced]-surfaced)
+				return
+			}
+			require.ErrorContains(t, err, "UNAVAILABLE")
+			if tcase.retried {
+				assert.EqualValues(t, 3, readRetries.Counts()[readRetried]-retried)
+				assert.EqualValues(t, 1, readRetries.Counts()[readSurfaced]-surfaced)
+			} else {
+				assert.EqualValues(t, 0, readRetries.Counts()[readRetried]-retried)
+			}
+		})
+	}
+}
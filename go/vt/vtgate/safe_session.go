@@ -18,6 +18,7 @@ package vtgate
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -30,6 +31,7 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/sysvars"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/engine"
 
@@ -64,6 +66,17 @@ type (
 		// as the query that started a new transaction on the shard belong to a vindex.
 		queryFromVindex bool
 
+		// snapshotRead is set while a snapshot read runs: every shard it
+		// touches begins a consistent snapshot, and replicas first catch up
+		// with their primary.
+		snapshotRead bool
+
+		// trackWrites is set while a statement that may write data runs
+		// with read/write splitting enabled, and splitRead while a read
+		// that it sent to the replicas runs.
+		trackWrites bool
+		splitRead   bool
+
 		logging *executeLogger
 
 		*vtgatepb.Session
@@ -217,6 +230,34 @@ func (session *SafeSession) GetQueryTimeout() int64 {
 	return session.QueryTimeout
 }
 
+// SetStreamChunkRows sets the number of rows per chunk of the streaming queries
+func (session *SafeSession) SetStreamChunkRows(rows int64) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.StreamChunkRows = rows
+}
+
+// GetStreamChunkRows gets the number of rows per chunk of the streaming queries
+func (session *SafeSession) GetStreamChunkRows() int64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.StreamChunkRows
+}
+
+// SetStreamChunkTimeout sets the timeout between two chunks of the streaming queries
+func (session *SafeSession) SetStreamChunkTimeout(timeout int64) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.StreamChunkTimeout = timeout
+}
+
+// GetStreamChunkTimeout gets the timeout between two chunks of the streaming queries
+func (session *SafeSession) GetStreamChunkTimeout() int64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.StreamChunkTimeout
+}
+
 // SavePoints returns the save points of the session. It's safe to use concurrently
 func (session *SafeSession) SavePoints() []string {
 	session.mu.Lock()
@@ -345,6 +386,14 @@ func (session *SafeSession) InTransaction() bool {
 	return session.Session.InTransaction
 }
 
+// InReadOnlyTransaction returns true if the session is in a transaction that
+// was started with START TRANSACTION READ ONLY.
+func (session *SafeSession) InReadOnlyTransaction() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.Session.InTransaction && slices.Contains(session.Options.GetTransactionAccessMode(), querypb.ExecuteOptions_READ_ONLY)
+}
+
 // FindAndChangeSessionIfInSingleTxMode returns the transactionId and tabletAlias, if any, for a session
 // modifies the shard session in a specific case for single mode transaction.
 func (session *SafeSession) FindAndChangeSessionIfInSingleTxMode(keyspace, shard string, tabletType topodatapb.TabletType, txMode vtgatepb.TransactionMode) (int64, int64, *topodatapb.TabletAlias, error) {
@@ -505,6 +554,24 @@ func (session *SafeSession) RecordWarning(warning *querypb.QueryWarning) {
 	session.Session.Warnings = append(session.Session.Warnings, warning)
 }
 
+// RecordShardWarnings stores the warnings a shard returned in the session.
+// The messages are prefixed with the keyspace and the shard, so that the
+// warnings of a statement that ran on several shards can be told apart.
+func (session *SafeSession) RecordShardWarnings(target *querypb.Target, warnings []*querypb.QueryWarning) {
+	if len(warnings) == 0 {
+		return
+	}
+	prefix := topoproto.KeyspaceShardString(target.GetKeyspace(), target.GetShard())
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	for _, warning := range warnings {
+		session.Session.Warnings = append(session.Session.Warnings, &querypb.QueryWarning{
+			Code:    warning.Code,
+			Message: fmt.Sprintf("%s: %s", prefix, warning.Message),
+		})
+	}
+}
+
 // ClearWarnings removes all the warnings from the session
 func (session *SafeSession) ClearWarnings() {
 	session.mu.Lock()
@@ -600,13 +667,56 @@ func (session *SafeSession) SetOptions(options *querypb.ExecuteOptions) {
 	session.Options = options
 }
 
-// StoreSavepoint stores the savepoint and release savepoint queries in the session
-func (session *SafeSession) StoreSavepoint(sql string) {
+// AddSavepoint stores a savepoint in the session, to be created on the shards that join the
+// transaction later. The session only keeps the savepoints that exist: a savepoint replaces
+// an existing one with the same name, as in MySQL.
+func (session *SafeSession) AddSavepoint(name sqlparser.IdentifierCI) {
 	session.mu.Lock()
 	defer session.mu.Unlock()
+	sql := sqlparser.String(&sqlparser.Savepoint{Name: name})
+	if i := session.findSavepointLocked(sql); i >= 0 {
+		session.Savepoints = slices.Delete(session.Savepoints, i, i+1)
+	}
 	session.Savepoints = append(session.Savepoints, sql)
 }
 
+// RollbackToSavepoint removes the savepoints set after the given savepoint from the session.
+// It returns false if the savepoint does not exist.
+func (session *SafeSession) RollbackToSavepoint(name sqlparser.IdentifierCI) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	i := session.findSavepointLocked(sqlparser.String(&sqlparser.Savepoint{Name: name}))
+	if i < 0 {
+		return false
+	}
+	session.Savepoints = session.Savepoints[:i+1]
+	return true
+}
+
+// ReleaseSavepoint removes the given savepoint, and the ones set after it, from the session.
+// It returns false if the savepoint does not exist.
+func (session *SafeSession) ReleaseSavepoint(name sqlparser.IdentifierCI) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	i := session.findSavepointLocked(sqlparser.String(&sqlparser.Savepoint{Name: name}))
+	if i < 0 {
+		return false
+	}
+	session.Savepoints = session.Savepoints[:i]
+	return true
+}
+
+// findSavepointLocked returns the index of a savepoint statement in the session, or -1.
+// The savepoint names are case-insensitive.
+func (session *SafeSession) findSavepointLocked(sql string) int {
+	for i := len(session.Savepoints) - 1; i >= 0; i-- {
+		if strings.EqualFold(session.Savepoints[i], sql) {
+			return i
+		}
+	}
+	return -1
+}
+
 // InReservedConn returns true if the session needs to execute on a dedicated connection
 func (session *SafeSession) InReservedConn() bool {
 	session.mu.Lock()
@@ -621,6 +731,14 @@ func (session *SafeSession) SetReservedConn(reservedConn bool) {
 	session.Session.InReservedConn = reservedConn
 }
 
+// SetUntrackedReservedConnState marks the reserved connections as having
+// state that cannot be replayed on new reserved connections.
+func (session *SafeSession) SetUntrackedReservedConnState() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.Session.UntrackedReservedConnState = true
+}
+
 // SetPreQueries returns the prequeries that need to be run when reserving a connection
 func (session *SafeSession) SetPreQueries() []string {
 	// extract keys
@@ -758,6 +876,13 @@ func (session *SafeSession) GetSessionUUID() string {
 	return session.SessionUUID
 }
 
+// GetQueryAttributes returns the query attributes of the current query.
+func (session *SafeSession) GetQueryAttributes() map[string]string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.GetOptions().GetQueryAttributes()
+}
+
 // SetSessionEnableSystemSettings set the SessionEnableSystemSettings setting.
 func (session *SafeSession) SetSessionEnableSystemSettings(allow bool) {
 	session.mu.Lock()
@@ -772,6 +897,166 @@ func (session *SafeSession) GetSessionEnableSystemSettings() bool {
 	return session.EnableSystemSettings
 }
 
+// SetSnapshotReads sets the SnapshotReads setting.
+func (session *SafeSession) SetSnapshotReads(enable bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.SnapshotReads = enable
+}
+
+// GetSnapshotReads returns the SnapshotReads value.
+func (session *SafeSession) GetSnapshotReads() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.SnapshotReads
+}
+
+// SetSkipReadRetry sets the SkipReadRetry setting.
+func (session *SafeSession) SetSkipReadRetry(skip bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.SkipReadRetry = skip
+}
+
+// GetSkipReadRetry returns the SkipReadRetry value.
+func (session *SafeSession) GetSkipReadRetry() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.SkipReadRetry
+}
+
+// SetReadOnlyTxOnReplica sets the ReadOnlyTransactionsOnReplica setting.
+func (session *SafeSession) SetReadOnlyTxOnReplica(enable bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.ReadOnlyTransactionsOnReplica = enable
+}
+
+// GetReadOnlyTxOnReplica returns the ReadOnlyTransactionsOnReplica value.
+func (session *SafeSession) GetReadOnlyTxOnReplica() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.ReadOnlyTransactionsOnReplica
+}
+
+// SetTenantID sets the tenant of the session.
+func (session *SafeSession) SetTenantID(tenantID string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.TenantId = tenantID
+}
+
+// GetTenantID returns the tenant of the session.
+func (session *SafeSession) GetTenantID() string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.TenantId
+}
+
+// SetMaxReplicaLag sets the maximum replication lag in seconds of the
+// replicas that serve the queries of the session.
+func (session *SafeSession) SetMaxReplicaLag(lag int64) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.MaxReplicaLag = lag
+}
+
+// GetMaxReplicaLag returns the maximum replication lag in seconds of the
+// replicas that serve the queries of the session.
+func (session *SafeSession) GetMaxReplicaLag() int64 {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.MaxReplicaLag
+}
+
+// SetTabletTags sets the tablet tags that the tablets serving the queries of
+// the session must carry.
+func (session *SafeSession) SetTabletTags(tags string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.TabletTags = tags
+}
+
+// GetTabletTags returns the tablet tags that the tablets serving the queries
+// of the session must carry.
+func (session *SafeSession) GetTabletTags() string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.TabletTags
+}
+
+// SetTrackWrites marks whether a statement that may write data is running on
+// the session, and records the GTID sets of its writes for read/write
+// splitting.
+func (session *SafeSession) SetTrackWrites(trackWrites bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.trackWrites = trackWrites
+}
+
+// TracksWrites returns true if the session records the GTID sets of the
+// writes of the statement that runs on it.
+func (session *SafeSession) TracksWrites() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.trackWrites
+}
+
+// RecordWriteGTIDs records that the statement that runs on the session may
+// have written to the primary of target, which executed gtids once the write
+// was committed. An empty gtids means that they are unknown.
+func (session *SafeSession) RecordWriteGTIDs(target *querypb.Target, gtids string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if !session.trackWrites || target.TabletType != topodatapb.TabletType_PRIMARY {
+		return
+	}
+	if session.LastWriteGtids == nil {
+		session.LastWriteGtids = make(map[string]string)
+	}
+	session.LastWriteGtids[topoproto.KeyspaceShardString(target.Keyspace, target.Shard)] = gtids
+}
+
+// LastWriteGTIDs returns the GTID set that the primary of the shard of target
+// had executed once the last write of the session to it was committed, and
+// whether the session wrote to it. The GTID set is empty when it is unknown.
+func (session *SafeSession) LastWriteGTIDs(target *querypb.Target) (string, bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	gtids, ok := session.LastWriteGtids[topoproto.KeyspaceShardString(target.Keyspace, target.Shard)]
+	return gtids, ok
+}
+
+// SetSplitRead marks whether the statement that runs on the session is a read
+// that read/write splitting sent to the replicas.
+func (session *SafeSession) SetSplitRead(splitRead bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.splitRead = splitRead
+}
+
+// InSplitRead returns true if the statement that runs on the session is a
+// read that read/write splitting sent to the replicas.
+func (session *SafeSession) InSplitRead() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.splitRead
+}
+
+// SetSnapshotRead marks whether a snapshot read is running on the session.
+func (session *SafeSession) SetSnapshotRead(snapshotRead bool) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.snapshotRead = snapshotRead
+}
+
+// InSnapshotRead returns true if a snapshot read is running on the session.
+func (session *SafeSession) InSnapshotRead() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.snapshotRead
+}
+
 // SetReadAfterWriteGTID set the ReadAfterWriteGtid setting.
 func (session *SafeSession) SetReadAfterWriteGTID(vtgtid string) {
 	session.mu.Lock()
@@ -792,6 +1077,49 @@ func (session *SafeSession) SetReadAfterWriteTimeout(timeout float64) {
 	session.ReadAfterWrite.ReadAfterWriteTimeout = timeout
 }
 
+// SetFoundRows sets the value that FOUND_ROWS() returns after the current
+// statement, which is then not computed from the rows it returned.
+func (session *SafeSession) SetFoundRows(foundRows uint64) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.FoundRows = foundRows
+	session.foundRowsHandled = true
+}
+
+// SaveStatementStats saves the values that LAST_INSERT_ID(), ROW_COUNT() and
+// FOUND_ROWS() return after a statement. The results of the queries of a
+// statement that spans several shards are merged first:
+//   - LAST_INSERT_ID() is the smallest insert id that is not 0 (see
+//     sqltypes.MergeInsertID), and is left as it was if there is none.
+//   - ROW_COUNT() is the sum of the rows that the queries affected for a DML,
+//     0 for the statements that don't return rows, and -1 for the others or
+//     if the statement failed.
+//   - FOUND_ROWS() is the number of rows that the statement returned, unless
+//     the statement set it with SQL_CALC_FOUND_ROWS.
+func (session *SafeSession) SaveStatementStats(stmtType sqlparser.StatementType, rowsAffected, insertID uint64, rowsReturned int, err error) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	foundRowsHandled := session.foundRowsHandled
+	session.foundRowsHandled = false
+	session.RowCount = -1
+	if err != nil {
+		return
+	}
+	if !foundRowsHandled {
+		session.FoundRows = uint64(rowsReturned)
+	}
+	if insertID > 0 {
+		session.LastInsertId = insertID
+	}
+	switch stmtType {
+	case sqlparser.StmtInsert, sqlparser.StmtReplace, sqlparser.StmtUpdate, sqlparser.StmtDelete:
+		session.RowCount = int64(rowsAffected)
+	case sqlparser.StmtDDL, sqlparser.StmtSet, sqlparser.StmtBegin, sqlparser.StmtCommit, sqlparser.StmtRollback, sqlparser.StmtFlush:
+		session.RowCount = 0
+	}
+}
+
 // SetSessionTrackGtids set the SessionTrackGtids setting.
 func (session *SafeSession) SetSessionTrackGtids(enable bool) {
 	session.mu.Lock()
@@ -18,6 +18,7 @@ package vtgate
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sync"
 	"time"
@@ -178,6 +179,10 @@ func (stc *ScatterConn) ExecuteMultiShard(
 			if session != nil && session.Session != nil {
 				opts = session.Session.Options
 			}
+			if session.TracksWrites() && rs.Target.TabletType == topodatapb.TabletType_PRIMARY {
+				// Read/write splitting needs the GTID set of the write.
+				opts = trackGtidsOptions(opts)
+			}
 
 			if autocommit {
 				// As this is auto-commit, the transactionID is supposed to be zero.
@@ -207,7 +212,13 @@ func (stc *ScatterConn) ExecuteMultiShard(
 
 			switch info.actionNeeded {
 			case nothing:
-				innerqr, err = qs.Execute(ctx, rs.Target, queries[i].Sql, queries[i].BindVariables, info.transactionID, info.reservedID, opts)
+				target, readReservedID := rs.Target, info.reservedID
+				if session.InSplitRead() && target.TabletType == topodatapb.TabletType_REPLICA {
+					var release func()
+					qs, target, readReservedID, release = stc.splitReadConn(ctx, rs, session, opts)
+					defer release()
+				}
+				innerqr, err = qs.Execute(ctx, target, queries[i].Sql, queries[i].BindVariables, info.transactionID, readReservedID, opts)
 				if err != nil {
 					retryRequest(func() {
 						// we seem to have lost our connection. it was a reserved connection, let's try to recreate it
@@ -219,8 +230,19 @@ func (stc *ScatterConn) ExecuteMultiShard(
 					})
 				}
 			case begin:
+				// On a reserved connection, a snapshot read on a replica waits
+				// for its primary before its consistent snapshot starts.
+				var waitQuery string
+				if waitQuery, err = stc.snapshotWaitQuery(ctx, session, rs.Target); err != nil {
+					return nil, err
+				}
+				if waitQuery != "" {
+					if _, err = qs.Execute(ctx, rs.Target, waitQuery, nil, 0, reservedID, opts); err != nil {
+						return nil, err
+					}
+				}
 				var state queryservice.TransactionState
-				state, innerqr, err = qs.BeginExecute(ctx, rs.Target, session.SavePoints(), queries[i].Sql, queries[i].BindVariables, reservedID, opts)
+				state, innerqr, err = qs.BeginExecute(ctx, rs.Target, savepointsToReplay(session), queries[i].Sql, queries[i].BindVariables, reservedID, opts)
 				transactionID = state.TransactionID
 				alias = state.TabletAlias
 				if err != nil {
@@ -228,7 +250,11 @@ func (stc *ScatterConn) ExecuteMultiShard(
 						// we seem to have lost our connection. it was a reserved connection, let's try to recreate it
 						info.actionNeeded = reserveBegin
 						var state queryservice.ReservedTransactionState
-						state, innerqr, err = qs.ReserveBeginExecute(ctx, rs.Target, session.SetPreQueries(), session.SavePoints(), queries[i].Sql, queries[i].BindVariables, opts)
+						var preQueries []string
+						if preQueries, err = stc.reserveBeginPreQueries(ctx, session, rs.Target); err != nil {
+							return
+						}
+						state, innerqr, err = qs.ReserveBeginExecute(ctx, rs.Target, preQueries, savepointsToReplay(session), queries[i].Sql, queries[i].BindVariables, opts)
 						transactionID = state.TransactionID
 						reservedID = state.ReservedID
 						alias = state.TabletAlias
@@ -241,7 +267,11 @@ func (stc *ScatterConn) ExecuteMultiShard(
 				alias = state.TabletAlias
 			case reserveBegin:
 				var state queryservice.ReservedTransactionState
-				state, innerqr, err = qs.ReserveBeginExecute(ctx, rs.Target, session.SetPreQueries(), session.SavePoints(), queries[i].Sql, queries[i].BindVariables, opts)
+				var preQueries []string
+				if preQueries, err = stc.reserveBeginPreQueries(ctx, session, rs.Target); err != nil {
+					return nil, err
+				}
+				state, innerqr, err = qs.ReserveBeginExecute(ctx, rs.Target, preQueries, savepointsToReplay(session), queries[i].Sql, queries[i].BindVariables, opts)
 				transactionID = state.TransactionID
 				reservedID = state.ReservedID
 				alias = state.TabletAlias
@@ -250,11 +280,20 @@ func (stc *ScatterConn) ExecuteMultiShard(
 			}
 			session.logging.log(primitive, rs.Target, rs.Gateway, queries[i].Sql, info.actionNeeded == begin || info.actionNeeded == reserveBegin, queries[i].BindVariables)
 
+			// A failed write may still have been committed.
+			var gtids string
+			if err == nil {
+				gtids = innerqr.SessionStateChanges
+			}
+			session.RecordWriteGTIDs(rs.Target, gtids)
+
 			// We need to new shard info irrespective of the error.
 			newInfo := info.updateTransactionAndReservedID(transactionID, reservedID, alias)
 			if err != nil {
 				return newInfo, err
 			}
+			session.RecordShardWarnings(rs.Target, innerqr.Warnings)
+
 			mu.Lock()
 			defer mu.Unlock()
 
@@ -405,7 +444,13 @@ func (stc *ScatterConn) StreamExecuteMulti(
 
 			switch info.actionNeeded {
 			case nothing:
-				err = qs.StreamExecute(ctx, rs.Target, query, bindVars[i], transactionID, reservedID, opts, callback)
+				target, readReservedID := rs.Target, reservedID
+				if session.InSplitRead() && target.TabletType == topodatapb.TabletType_REPLICA {
+					var release func()
+					qs, target, readReservedID, release = stc.splitReadConn(ctx, rs, session, opts)
+					defer release()
+				}
+				err = qs.StreamExecute(ctx, target, query, bindVars[i], transactionID, readReservedID, opts, callback)
 				if err != nil {
 					retryRequest(func() {
 						// we seem to have lost our connection. it was a reserved connection, let's try to recreate it
@@ -417,8 +462,19 @@ func (stc *ScatterConn) StreamExecuteMulti(
 					})
 				}
 			case begin:
+				// On a reserved connection, a snapshot read on a replica waits
+				// for its primary before its consistent snapshot starts.
+				var waitQuery string
+				if waitQuery, err = stc.snapshotWaitQuery(ctx, session, rs.Target); err != nil {
+					return nil, err
+				}
+				if waitQuery != "" {
+					if _, err = qs.Execute(ctx, rs.Target, waitQuery, nil, 0, reservedID, opts); err != nil {
+						return nil, err
+					}
+				}
 				var state queryservice.TransactionState
-				state, err = qs.BeginStreamExecute(ctx, rs.Target, session.SavePoints(), query, bindVars[i], reservedID, opts, callback)
+				state, err = qs.BeginStreamExecute(ctx, rs.Target, savepointsToReplay(session), query, bindVars[i], reservedID, opts, callback)
 				transactionID = state.TransactionID
 				alias = state.TabletAlias
 				if err != nil {
@@ -426,7 +482,11 @@ func (stc *ScatterConn) StreamExecuteMulti(
 						// we seem to have lost our connection. it was a reserved connection, let's try to recreate it
 						info.actionNeeded = reserveBegin
 						var state queryservice.ReservedTransactionState
-						state, err = qs.ReserveBeginStreamExecute(ctx, rs.Target, session.SetPreQueries(), session.SavePoints(), query, bindVars[i], opts, callback)
+						var preQueries []string
+						if preQueries, err = stc.reserveBeginPreQueries(ctx, session, rs.Target); err != nil {
+							return
+						}
+						state, err = qs.ReserveBeginStreamExecute(ctx, rs.Target, preQueries, savepointsToReplay(session), query, bindVars[i], opts, callback)
 						transactionID = state.TransactionID
 						reservedID = state.ReservedID
 						alias = state.TabletAlias
@@ -439,7 +499,11 @@ func (stc *ScatterConn) StreamExecuteMulti(
 				alias = state.TabletAlias
 			case reserveBegin:
 				var state queryservice.ReservedTransactionState
-				state, err = qs.ReserveBeginStreamExecute(ctx, rs.Target, session.SetPreQueries(), session.SavePoints(), query, bindVars[i], opts, callback)
+				var preQueries []string
+				if preQueries, err = stc.reserveBeginPreQueries(ctx, session, rs.Target); err != nil {
+					return nil, err
+				}
+				state, err = qs.ReserveBeginStreamExecute(ctx, rs.Target, preQueries, savepointsToReplay(session), query, bindVars[i], opts, callback)
 				transactionID = state.TransactionID
 				reservedID = state.ReservedID
 				alias = state.TabletAlias
@@ -448,6 +512,9 @@ func (stc *ScatterConn) StreamExecuteMulti(
 			}
 			session.logging.log(primitive, rs.Target, rs.Gateway, query, info.actionNeeded == begin || info.actionNeeded == reserveBegin, bindVars[i])
 
+			// The GTID set of a streamed write is not known.
+			session.RecordWriteGTIDs(rs.Target, "")
+
 			// We need to new shard info irrespective of the error.
 			newInfo := info.updateTransactionAndReservedID(transactionID, reservedID, alias)
 			if err != nil {
@@ -754,6 +821,14 @@ func (stc *ScatterConn) ExecuteLock(ctx context.Context, rs *srvtopo.ResolvedSha
 	return qr, err
 }
 
+// savepointsToReplay returns the savepoints to create on a shard that joins the transaction
+// of the session, so that it can roll back to the savepoints set before it joined.
+func savepointsToReplay(session *SafeSession) []string {
+	savepoints := session.SavePoints()
+	savepointsReplayed.Add(int64(len(savepoints)))
+	return savepoints
+}
+
 func wasConnectionClosed(err error) bool {
 	sqlErr := sqlerror.NewSQLErrorFromError(err).(*sqlerror.SQLError)
 	message := sqlErr.Error()
@@ -808,6 +883,12 @@ func actionInfo(ctx context.Context, target *querypb.Target, session *SafeSessio
 	shouldReserve := session.InReservedConn() && reservedID == 0
 	shouldBegin := session.InTransaction() && transactionID == 0 && !autocommit
 
+	// A snapshot read reserves the connections it begins on replicas, so
+	// that they wait for their primary before starting the snapshot.
+	if shouldBegin && reservedID == 0 && session.InSnapshotRead() && target.TabletType != topodatapb.TabletType_PRIMARY {
+		shouldReserve = true
+	}
+
 	var act = nothing
 	switch {
 	case shouldBegin && shouldReserve:
@@ -848,6 +929,102 @@ func lockInfo(target *querypb.Target, session *SafeSession, lockFuncType sqlpars
 	return info, nil
 }
 
+// reserveBeginPreQueries returns the queries to run on a new reserved
+// connection before its transaction begins: the session settings, then the
+// wait for the primary when it begins a snapshot read on a replica.
+func (stc *ScatterConn) reserveBeginPreQueries(ctx context.Context, session *SafeSession, target *querypb.Target) ([]string, error) {
+	preQueries := session.SetPreQueries()
+	waitQuery, err := stc.snapshotWaitQuery(ctx, session, target)
+	if err != nil {
+		return nil, err
+	}
+	if waitQuery != "" {
+		preQueries = append(preQueries, waitQuery)
+	}
+	return preQueries, nil
+}
+
+// snapshotWaitQuery returns the query that makes a replica wait, before the
+// consistent snapshot of a snapshot read starts, until it executed the
+// transactions that its primary executed when the read reached the shard. It
+// returns an empty string outside of snapshot reads and on primaries, which
+// have nothing to wait for.
+func (stc *ScatterConn) snapshotWaitQuery(ctx context.Context, session *SafeSession, target *querypb.Target) (string, error) {
+	if !session.InSnapshotRead() || target.TabletType == topodatapb.TabletType_PRIMARY {
+		return "", nil
+	}
+	primary := target.CloneVT()
+	primary.TabletType = topodatapb.TabletType_PRIMARY
+	qr, err := stc.gateway.Execute(ctx, primary, "select @@global.gtid_executed", nil, 0, 0, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != 1 {
+		return "", vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for gtid_executed on %s: %v", topoproto.KeyspaceShardString(target.Keyspace, target.Shard), qr.Rows)
+	}
+	return fmt.Sprintf("select WAIT_FOR_EXECUTED_GTID_SET(%s)", sqltypes.EncodeStringSQL(qr.Rows[0][0].ToString())), nil
+}
+
+// trackGtidsOptions returns a copy of opts that asks the tablet for the GTID
+// set of an autocommit write.
+func trackGtidsOptions(opts *querypb.ExecuteOptions) *querypb.ExecuteOptions {
+	if opts == nil {
+		opts = &querypb.ExecuteOptions{}
+	} else {
+		opts = opts.CloneVT()
+	}
+	opts.TrackGtids = true
+	return opts
+}
+
+// splitReadConn returns the query service, target and reserved connection
+// that serve a read that read/write splitting sent to the replicas of rs. On
+// a shard that the session wrote to, a replica first waits on a reserved
+// connection to have executed the GTID set of the last write of the session.
+// The read falls back to the primary when the replica doesn't within
+// --read-write-splitting-wait-timeout, when that GTID set is unknown, or when
+// the shard has no healthy replica. release releases the reserved connection
+// once the read is done.
+func (stc *ScatterConn) splitReadConn(ctx context.Context, rs *srvtopo.ResolvedShard, session *SafeSession, opts *querypb.ExecuteOptions) (qs queryservice.QueryService, target *querypb.Target, reservedID int64, release func()) {
+	noRelease := func() {}
+	fallback := func() (queryservice.QueryService, *querypb.Target, int64, func()) {
+		readWriteSplittingPrimaryFallbacks.Add(1)
+		primary := rs.Target.CloneVT()
+		primary.TabletType = topodatapb.TabletType_PRIMARY
+		return rs.Gateway, primary, 0, noRelease
+	}
+	if len(stc.gateway.hc.GetHealthyTabletStats(rs.Target)) == 0 {
+		return fallback()
+	}
+	gtids, wrote := session.LastWriteGTIDs(rs.Target)
+	if !wrote {
+		return rs.Gateway, rs.Target, 0, noRelease
+	}
+	if gtids == "" {
+		return fallback()
+	}
+
+	waitQuery := fmt.Sprintf("select WAIT_FOR_EXECUTED_GTID_SET(%s, %v)", sqltypes.EncodeStringSQL(gtids), readWriteSplittingWaitTimeout.Seconds())
+	state, qr, err := rs.Gateway.ReserveExecute(ctx, rs.Target, session.SetPreQueries(), waitQuery, nil, 0, opts)
+	if state.ReservedID == 0 {
+		return fallback()
+	}
+	qs, qsErr := rs.Gateway.QueryServiceByAlias(state.TabletAlias, rs.Target)
+	if qsErr != nil {
+		// The tablet kills the reserved connection once it is idle.
+		return fallback()
+	}
+	release = func() {
+		_ = qs.Release(ctx, rs.Target, 0, state.ReservedID)
+	}
+	// WAIT_FOR_EXECUTED_GTID_SET returns 1 when it times out.
+	if err != nil || len(qr.Rows) != 1 || len(qr.Rows[0]) != 1 || qr.Rows[0][0].ToString() != "0" {
+		release()
+		return fallback()
+	}
+	return qs, rs.Target, state.ReservedID, release
+}
+
 type shardActionInfo struct {
 	actionNeeded              actionNeeded
 	reservedID, transactionID int64
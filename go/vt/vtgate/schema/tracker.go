@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/ptr"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/log"
@@ -37,6 +38,10 @@ type (
 	tableNameStr = string
 	viewNameStr  = string
 
+	shardTable struct {
+		keyspace, shard, name string
+	}
+
 	// Tracker contains the required fields to perform schema tracking.
 	Tracker struct {
 		ch     chan *discovery.TabletHealth
@@ -52,6 +57,10 @@ type (
 		tracked      map[keyspaceStr]*updateController
 		consumeDelay time.Duration
 
+		// positions are the positions of the last changes the shards pushed for their tables and views,
+		// to ignore the changes that arrive out of order.
+		positions map[shardTable]replication.Position
+
 		parser *sqlparser.Parser
 	}
 )
@@ -67,6 +76,7 @@ func NewTracker(ch chan *discovery.TabletHealth, enableViews bool, parser *sqlpa
 		tables:       &tableMap{m: make(map[keyspaceStr]map[tableNameStr]*vindexes.TableInfo)},
 		tracked:      map[keyspaceStr]*updateController{},
 		consumeDelay: defaultConsumeDelay,
+		positions:    map[shardTable]replication.Position{},
 		parser:       parser,
 	}
 
@@ -256,6 +266,10 @@ func (t *Tracker) Views(ks string) map[string]sqlparser.SelectStatement {
 }
 
 func (t *Tracker) updateSchema(th *discovery.TabletHealth) bool {
+	if hasSchemaChanges(th.Stats) {
+		t.applySchemaChanges(th)
+		return true
+	}
 	success := true
 	if th.Stats.TableSchemaChanged != nil {
 		success = t.updatedTableSchema(th)
@@ -267,6 +281,66 @@ func (t *Tracker) updateSchema(th *discovery.TabletHealth) bool {
 	return t.updatedViewSchema(th)
 }
 
+// hasSchemaChanges returns true if the tablet pushed the definitions of all the tables and
+// views that changed, which can then be applied without fetching them.
+func hasSchemaChanges(stats *querypb.RealtimeStats) bool {
+	if len(stats.SchemaChanges) == 0 {
+		return false
+	}
+	pushed := make(map[string]bool, len(stats.SchemaChanges))
+	for _, change := range stats.SchemaChanges {
+		pushed[change.TableType.String()+"."+change.Name] = true
+	}
+	for _, table := range stats.TableSchemaChanged {
+		if !pushed[querypb.SchemaTableType_TABLES.String()+"."+table] {
+			return false
+		}
+	}
+	for _, view := range stats.ViewSchemaChanged {
+		if !pushed[querypb.SchemaTableType_VIEWS.String()+"."+view] {
+			return false
+		}
+	}
+	return true
+}
+
+// applySchemaChanges applies the definitions of the tables and views pushed by the tablet,
+// ignoring those older than the last change the shard pushed for them.
+func (t *Tracker) applySchemaChanges(th *discovery.TabletHealth) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ks := th.Target.Keyspace
+	for _, change := range th.Stats.SchemaChanges {
+		key := shardTable{keyspace: ks, shard: th.Target.Shard, name: change.Name}
+		if pos, err := replication.DecodePosition(change.Position); err == nil && !pos.IsZero() {
+			if last, ok := t.positions[key]; ok && !pos.AtLeast(last) {
+				log.Infof("ignoring the change of %s.%s at %v pushed by %s, older than %v", ks, change.Name, pos, th.Target.Shard, last)
+				continue
+			}
+			t.positions[key] = pos
+		} else {
+			delete(t.positions, key)
+		}
+
+		switch change.TableType {
+		case querypb.SchemaTableType_VIEWS:
+			if t.views == nil {
+				continue
+			}
+			t.views.delete(ks, change.Name)
+			if change.CreateStatement != "" {
+				t.updateViews(ks, map[string]string{change.Name: change.CreateStatement})
+			}
+		default:
+			t.tables.delete(ks, change.Name)
+			if change.CreateStatement != "" {
+				t.updateTables(ks, map[string]string{change.Name: change.CreateStatement})
+			}
+		}
+	}
+}
+
 func (t *Tracker) updatedTableSchema(th *discovery.TabletHealth) bool {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -264,6 +264,86 @@ func TestViewsTracking(t *testing.T) {
 	testTracker(t, schemaDefResult, testcases)
 }
 
+// TestPushedSchemaChanges tests that the tracker applies the definitions pushed by the tablets
+// without fetching them, ignoring those older than the last change pushed for the same table.
+func TestPushedSchemaChanges(t *testing.T) {
+	ch := make(chan *discovery.TabletHealth)
+	tracker := NewTracker(ch, true, sqlparser.NewTestParser())
+	tracker.consumeDelay = 1 * time.Millisecond
+	tracker.Start()
+	defer tracker.Stop()
+
+	wg := sync.WaitGroup{}
+	tracker.RegisterSignalReceiver(func() {
+		wg.Done()
+	})
+
+	target := &querypb.Target{Cell: cell, Keyspace: keyspace, Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY}
+	tablet := &topodatapb.Tablet{Keyspace: target.Keyspace, Shard: target.Shard, Type: target.TabletType}
+
+	sbc := sandboxconn.NewSandboxConn(tablet)
+	sbc.SetSchemaResult([]map[string]string{{
+		"prior": "create table prior(id int primary key)",
+	}, {
+		// initial load of view - kept empty
+	}, {
+		"t2": "create table t2(id int primary key)",
+	}})
+
+	const gtids = "MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-"
+	send := func(stats *querypb.RealtimeStats) {
+		wg.Add(1)
+		ch <- &discovery.TabletHealth{
+			Conn:    sbc,
+			Tablet:  tablet,
+			Target:  target,
+			Serving: true,
+			Stats:   stats,
+		}
+		require.False(t, waitTimeout(&wg, time.Second), "schema was updated but received no signal")
+	}
+	idColumn := func(typ querypb.Type) []vindexes.Column {
+		return []vindexes.Column{{Name: sqlparser.NewIdentifierCI("id"), Type: typ, CollationName: "binary", Nullable: true}}
+	}
+
+	send(&querypb.RealtimeStats{})
+	require.EqualValues(t, 2, sbc.GetSchemaCount.Load())
+
+	send(&querypb.RealtimeStats{
+		TableSchemaChanged: []string{"t1"},
+		ViewSchemaChanged:  []string{"v1"},
+		SchemaChanges: []*querypb.SchemaChange{
+			{Name: "t1", TableType: querypb.SchemaTableType_TABLES, CreateStatement: "create table t1(id bigint primary key)", Position: gtids + "5"},
+			{Name: "v1", TableType: querypb.SchemaTableType_VIEWS, CreateStatement: "create view v1 as select 1 from t1", Position: gtids + "5"},
+		},
+	})
+	require.EqualValues(t, 2, sbc.GetSchemaCount.Load())
+	utils.MustMatch(t, idColumn(querypb.Type_INT64), tracker.GetColumns(keyspace, "t1"))
+	assert.Equal(t, "select 1 from t1", sqlparser.String(tracker.GetViews(keyspace, "v1")))
+
+	// the change of t1 is older than the one already applied, and the drop of prior is applied
+	send(&querypb.RealtimeStats{
+		TableSchemaChanged: []string{"t1", "prior"},
+		SchemaChanges: []*querypb.SchemaChange{
+			{Name: "t1", TableType: querypb.SchemaTableType_TABLES, CreateStatement: "create table t1(id int primary key)", Position: gtids + "3"},
+			{Name: "prior", TableType: querypb.SchemaTableType_TABLES, Position: gtids + "6"},
+		},
+	})
+	require.EqualValues(t, 2, sbc.GetSchemaCount.Load())
+	utils.MustMatch(t, idColumn(querypb.Type_INT64), tracker.GetColumns(keyspace, "t1"))
+	assert.NotContains(t, tracker.Tables(keyspace), "prior")
+
+	// without the definitions of all the changed tables, they are fetched from the tablet
+	send(&querypb.RealtimeStats{
+		TableSchemaChanged: []string{"t1", "t2"},
+		SchemaChanges: []*querypb.SchemaChange{
+			{Name: "t1", TableType: querypb.SchemaTableType_TABLES, CreateStatement: "create table t1(id int primary key)", Position: gtids + "7"},
+		},
+	})
+	require.EqualValues(t, 3, sbc.GetSchemaCount.Load())
+	utils.MustMatch(t, idColumn(querypb.Type_INT32), tracker.GetColumns(keyspace, "t2"))
+}
+
 // TestFKInfoRetrieval tests that the tracker is able to retrieve required foreign key information from ddl statement.
 func TestFKInfoRetrieval(t *testing.T) {
 	schemaDefResult := []map[string]string{{
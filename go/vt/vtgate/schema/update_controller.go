@@ -124,6 +124,8 @@ func (u *updateController) getItemFromQueueLocked() *discovery.TabletHealth {
 					item.Stats.ViewSchemaChanged = append(item.Stats.ViewSchemaChanged, view)
 				}
 			}
+			// The pushed changes are applied in order, the last change of a table winning.
+			item.Stats.SchemaChanges = append(item.Stats.SchemaChanges, u.queue.items[i].Stats.SchemaChanges...)
 		}
 	}
 	// emptying queue's items as all items from 0 to i (length of the queue) are merged
@@ -137,6 +139,18 @@ func (u *updateController) add(th *discovery.TabletHealth) {
 		return
 	}
 
+	if !u.enqueue(th) {
+		return
+	}
+	// The tablet pushed the definitions of the tables and views that changed, and no update is
+	// queued before them: they are applied right away, without waiting for the consume delay.
+	if u.update(th) && u.signal != nil {
+		u.signal()
+	}
+}
+
+// enqueue queues the schema update of th, and returns true if it must be applied right away instead.
+func (u *updateController) enqueue(th *discovery.TabletHealth) bool {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
@@ -144,12 +158,12 @@ func (u *updateController) add(th *discovery.TabletHealth) {
 	// The connection will get reset and the tracker needs to reload the schema for the keyspace.
 	if !th.Serving {
 		u.loaded = false
-		return
+		return false
 	}
 
 	// If the keyspace schema is loaded and there is no schema change detected. Then there is nothing to process.
 	if len(th.Stats.TableSchemaChanged) == 0 && len(th.Stats.ViewSchemaChanged) == 0 && u.loaded {
-		return
+		return false
 	}
 
 	if (len(th.Stats.TableSchemaChanged) > 0 || len(th.Stats.ViewSchemaChanged) > 0) && u.ignore {
@@ -160,7 +174,11 @@ func (u *updateController) add(th *discovery.TabletHealth) {
 
 	if u.ignore {
 		// keyspace marked as not working correctly, so we are ignoring it for now
-		return
+		return false
+	}
+
+	if u.queue == nil && u.loaded && hasSchemaChanges(th.Stats) {
+		return true
 	}
 
 	if u.queue == nil {
@@ -168,6 +186,7 @@ func (u *updateController) add(th *discovery.TabletHealth) {
 		go u.consume()
 	}
 	u.queue.items = append(u.queue.items, th)
+	return false
 }
 
 func (u *updateController) setLoaded(loaded bool) {
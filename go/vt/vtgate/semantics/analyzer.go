@@ -39,12 +39,14 @@ type analyzer struct {
 	si          SchemaInformation
 	currentDb   string
 	recheck     bool
+	positions   sqlparser.Positions
 
 	err          error
 	inProjection int
 
 	projErr                 error
 	unshardedErr            error
+	singleShardErr          error
 	warning                 string
 	singleUnshardedKeyspace bool
 	fullAnalysis            bool
@@ -78,6 +80,7 @@ func (a *analyzer) lateInit() {
 		aliasMapCache:   map[*sqlparser.Select]map[string]exprContainer{},
 		reAnalyze:       a.reAnalyze,
 		tables:          a.tables,
+		positions:       a.positions,
 	}
 	a.fk = &fkManager{
 		binder:   a.binder,
@@ -89,11 +92,19 @@ func (a *analyzer) lateInit() {
 
 // Analyze analyzes the parsed query.
 func Analyze(statement sqlparser.Statement, currentDb string, si SchemaInformation) (*SemTable, error) {
-	return analyseAndGetSemTable(statement, currentDb, si, false)
+	return analyseAndGetSemTable(statement, currentDb, si, false, nil)
 }
 
-func analyseAndGetSemTable(statement sqlparser.Statement, currentDb string, si SchemaInformation, fullAnalysis bool) (*SemTable, error) {
+// AnalyzeWithPositions analyzes the parsed query like Analyze. The positions of
+// the expressions of the statement in the query text are used to point the
+// errors at the expression that caused them, and are kept in the SemTable.
+func AnalyzeWithPositions(statement sqlparser.Statement, currentDb string, si SchemaInformation, positions sqlparser.Positions) (*SemTable, error) {
+	return analyseAndGetSemTable(statement, currentDb, si, false, positions)
+}
+
+func analyseAndGetSemTable(statement sqlparser.Statement, currentDb string, si SchemaInformation, fullAnalysis bool, positions sqlparser.Positions) (*SemTable, error) {
 	analyzer := newAnalyzer(currentDb, newSchemaInfo(si), fullAnalysis)
+	analyzer.positions = positions
 
 	// Analysis for initial scope
 	err := analyzer.analyze(statement)
@@ -102,12 +113,19 @@ func analyseAndGetSemTable(statement sqlparser.Statement, currentDb string, si S
 	}
 
 	// Creation of the semantic table
-	return analyzer.newSemTable(statement, si.ConnCollation(), si.GetForeignKeyChecksState(), si.Environment().CollationEnv())
+	st, err := analyzer.newSemTable(statement, si.ConnCollation(), si.GetForeignKeyChecksState(), si.Environment().CollationEnv())
+	if err != nil {
+		return nil, err
+	}
+	st.Positions = positions
+	st.NonDeterministic = nonDeterministicExprs(statement)
+	st.columnUses = columnUsesOf(statement, st.Direct)
+	return st, nil
 }
 
 // AnalyzeStrict analyzes the parsed query, and fails the analysis for any possible errors
 func AnalyzeStrict(statement sqlparser.Statement, currentDb string, si SchemaInformation) (*SemTable, error) {
-	st, err := analyseAndGetSemTable(statement, currentDb, si, true)
+	st, err := analyseAndGetSemTable(statement, currentDb, si, true, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -118,6 +136,9 @@ func AnalyzeStrict(statement sqlparser.Statement, currentDb string, si SchemaInf
 	if st.NotSingleRouteErr != nil {
 		return nil, st.NotSingleRouteErr
 	}
+	if st.NotSingleShardErr != nil {
+		return nil, st.NotSingleShardErr
+	}
 
 	return st, nil
 }
@@ -141,8 +162,10 @@ func (a *analyzer) newSemTable(
 			Warning:                   a.warning,
 			Collation:                 coll,
 			ExprTypes:                 map[sqlparser.Expr]evalengine.Type{},
+			BindVarTypes:              map[string]evalengine.Type{},
 			NotSingleRouteErr:         a.projErr,
 			NotUnshardedErr:           a.unshardedErr,
+			NotSingleShardErr:         a.singleShardErr,
 			Recursive:                 ExprDependencies{},
 			Direct:                    ExprDependencies{},
 			ColumnEqualities:          map[columnName][]sqlparser.Expr{},
@@ -171,10 +194,12 @@ func (a *analyzer) newSemTable(
 		Recursive:                 a.binder.recursive,
 		Direct:                    a.binder.direct,
 		ExprTypes:                 a.typer.m,
+		BindVarTypes:              a.typer.bindVars,
 		Tables:                    a.tables.Tables,
 		Targets:                   a.binder.targets,
 		NotSingleRouteErr:         a.projErr,
 		NotUnshardedErr:           a.unshardedErr,
+		NotSingleShardErr:         a.singleShardErr,
 		Warning:                   a.warning,
 		Comments:                  comments,
 		ColumnEqualities:          map[columnName][]sqlparser.Expr{},
@@ -190,13 +215,25 @@ func (a *analyzer) newSemTable(
 	}, nil
 }
 
-func (a *analyzer) setError(err error) {
+// setError records the error that the analysis of the node produced.
+func (a *analyzer) setError(node sqlparser.SQLNode, err error) {
+	switch node.(type) {
+	case sqlparser.Expr, sqlparser.SelectExpr:
+	default:
+		// The first expression of a statement or a table is a poor hint of
+		// where its error is.
+		node = nil
+	}
+
 	switch err := err.(type) {
 	case ProjError:
-		a.projErr = err.Inner
+		a.projErr = errorAt(a.positions, node, err.Inner)
 	case ShardedError:
-		a.unshardedErr = err.Inner
+		a.unshardedErr = errorAt(a.positions, node, err.Inner)
+	case SingleShardError:
+		a.singleShardErr = errorAt(a.positions, node, err.Inner)
 	default:
+		err = errorAt(a.positions, node, err)
 		if a.inProjection > 0 && vterrors.ErrState(err) == vterrors.NonUniqError {
 			a.projErr = err
 		} else {
@@ -213,15 +250,15 @@ func (a *analyzer) analyzeDown(cursor *sqlparser.Cursor) bool {
 	}
 
 	if err := a.scoper.down(cursor); err != nil {
-		a.setError(err)
+		a.setError(cursor.Node(), err)
 		return true
 	}
 	if err := a.checkForInvalidConstructs(cursor); err != nil {
-		a.setError(err)
+		a.setError(cursor.Node(), err)
 		return true
 	}
 	if err := a.rewriter.down(cursor); err != nil {
-		a.setError(err)
+		a.setError(cursor.Node(), err)
 		return true
 	}
 	// log any warn in rewriting.
@@ -242,30 +279,30 @@ func (a *analyzer) analyzeUp(cursor *sqlparser.Cursor) bool {
 	}
 
 	if err := a.tables.up(cursor); err != nil {
-		a.setError(err)
+		a.setError(cursor.Node(), err)
 		return false
 	}
 
 	if err := a.binder.up(cursor); err != nil {
-		a.setError(err)
+		a.setError(cursor.Node(), err)
 		return true
 	}
 
 	if err := a.typer.up(cursor); err != nil {
-		a.setError(err)
+		a.setError(cursor.Node(), err)
 		return false
 	}
 
 	if !a.recheck {
 		// no need to run the rewriter on rechecking
 		if err := a.rewriter.up(cursor); err != nil {
-			a.setError(err)
+			a.setError(cursor.Node(), err)
 			return true
 		}
 	}
 
 	if err := a.scoper.up(cursor); err != nil {
-		a.setError(err)
+		a.setError(cursor.Node(), err)
 		return false
 	}
 
@@ -451,6 +488,13 @@ func (a *analyzer) noteQuerySignature(node sqlparser.SQLNode) {
 		if node.GroupBy != nil {
 			a.sig.Aggregation = true
 		}
+		if node.StraightJoinHint {
+			a.sig.StraightJoin = true
+		}
+	case *sqlparser.JoinTableExpr:
+		if node.Join == sqlparser.StraightJoinType {
+			a.sig.StraightJoin = true
+		}
 	case sqlparser.AggrFunc:
 		a.sig.Aggregation = true
 	case *sqlparser.Delete, *sqlparser.Update, *sqlparser.Insert:
@@ -466,6 +510,9 @@ func (a *analyzer) getError() error {
 	if a.unshardedErr != nil {
 		return a.unshardedErr
 	}
+	if a.singleShardErr != nil {
+		return a.singleShardErr
+	}
 	return a.err
 }
 
@@ -492,3 +539,18 @@ func (p ShardedError) Unwrap() error {
 func (p ShardedError) Error() string {
 	return p.Inner.Error()
 }
+
+// SingleShardError is used to mark an error as something that should only be
+// returned if the planner fails to plan the query as a single route that
+// targets a single shard
+type SingleShardError struct {
+	Inner error
+}
+
+func (p SingleShardError) Unwrap() error {
+	return p.Inner
+}
+
+func (p SingleShardError) Error() string {
+	return p.Inner.Error()
+}
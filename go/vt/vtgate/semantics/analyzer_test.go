@@ -25,6 +25,7 @@ import (
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
@@ -872,15 +873,18 @@ func TestInvalidQueries(t *testing.T) {
 	}, {
 		sql:  "select is_free_lock('xyz') from user",
 		serr: "is_free_lock('xyz') allowed only with dual",
-	}, {
-		sql: "SELECT * FROM JSON_TABLE('[ {\"c1\": null} ]','$[*]' COLUMNS( c1 INT PATH '$.c1' ERROR ON ERROR )) as jt",
-		err: &JSONTablesError{},
 	}, {
 		sql:             "select does_not_exist from t1",
 		notUnshardedErr: "column 'does_not_exist' not found in table 't1'",
 	}, {
 		sql:             "select t1.does_not_exist from t1, t2",
 		notUnshardedErr: "column 't1.does_not_exist' not found",
+	}, {
+		sql:             "select id from t1 union select uid from t2 order by uid",
+		notUnshardedErr: "Unknown column 'uid' in 'order clause'",
+	}, {
+		// the columns of the union are unknown until the * is expanded
+		sql: "select * from t union select uid from t2 order by uid",
 	}, {
 		sql:  "select 1 from t1 where id = (select 1, 2)",
 		serr: "Operand should contain 1 column(s)",
@@ -918,6 +922,45 @@ func TestInvalidQueries(t *testing.T) {
 	}
 }
 
+func TestErrorPositions(t *testing.T) {
+	tcases := []struct {
+		sql             string
+		serr            string
+		notUnshardedErr string
+	}{{
+		sql:             "select does_not_exist from t1",
+		notUnshardedErr: "column 'does_not_exist' not found in table 't1' (near character 8)",
+	}, {
+		sql:             "select 1 from t1 where id = 1 and t1.does_not_exist = 2",
+		notUnshardedErr: "column 't1.does_not_exist' not found (near character 35)",
+	}, {
+		sql:  "select 1 from t1 where id =  (select 1, 2)",
+		serr: "Operand should contain 1 column(s) (near character 30)",
+	}, {
+		sql:  "select is_free_lock('xyz') from user",
+		serr: "is_free_lock('xyz') allowed only with dual (near character 8)",
+	}, {
+		sql:  "select a.id, b.id from a, b union select 1, 2 order by id",
+		serr: "Column 'id' in field list is ambiguous (near character 56)",
+	}}
+
+	for _, tc := range tcases {
+		t.Run(tc.sql, func(t *testing.T) {
+			parse, _, positions, err := sqlparser.NewTestParser().Parse2WithPositions(tc.sql)
+			require.NoError(t, err)
+
+			st, err := AnalyzeWithPositions(parse, "dbName", fakeSchemaInfo(), positions)
+			if tc.serr != "" {
+				require.EqualError(t, err, tc.serr)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualError(t, st.NotUnshardedErr, tc.notUnshardedErr)
+			require.Equal(t, vterrors.BadFieldError, vterrors.ErrState(st.NotUnshardedErr))
+		})
+	}
+}
+
 func TestUnionWithOrderBy(t *testing.T) {
 	query := "select col1 from tabl1 union (select col2 from tabl2) order by 1"
 
@@ -1083,6 +1126,37 @@ func TestJoinPredicateDependencies(t *testing.T) {
 	}
 }
 
+func TestJSONTableDependencies(t *testing.T) {
+	// create table t2(uid bigint, name varchar(255))
+
+	queries := []struct {
+		query  string
+		expect []TableSet
+	}{{
+		query:  "select jt.c1, c1 from json_table('[{\"c1\": 1}]', '$[*]' columns(c1 int path '$.c1')) as jt",
+		expect: []TableSet{TS0, TS0},
+	}, {
+		query:  "select t2.uid, jt.rowid, jt.c1 from t2, json_table(t2.name, '$[*]' columns(rowid for ordinality, nested path '$.x' columns (c1 int path '$'))) as jt",
+		expect: []TableSet{TS0, TS1, TS1},
+	}}
+	for _, query := range queries {
+		t.Run(query.query, func(t *testing.T) {
+			parse, err := sqlparser.NewTestParser().Parse(query.query)
+			require.NoError(t, err)
+
+			st, err := Analyze(parse, "user", fakeSchemaInfo())
+			require.NoError(t, err)
+			require.NoError(t, st.NotUnshardedErr)
+			require.EqualError(t, st.NotSingleShardErr, "VT12001: unsupported: json_table expressions")
+
+			sel := parse.(*sqlparser.Select)
+			for idx, expect := range query.expect {
+				assert.Equal(t, expect, st.RecursiveDeps(extract(sel, idx)), "RecursiveDeps")
+			}
+		})
+	}
+}
+
 func TestScopingWVindexTables(t *testing.T) {
 	queries := []struct {
 		query                string
@@ -141,10 +141,34 @@ func (b *binder) bindColName(col *sqlparser.ColName) error {
 	b.direct[col] = deps.direct
 	if deps.typ.Valid() {
 		b.typer.setTypeFor(col, deps.typ)
+		if b.isShardedVindexColumn(col, deps.direct) {
+			b.typer.setVindexColumn(col)
+		}
 	}
 	return nil
 }
 
+// isShardedVindexColumn returns whether the column is a vindex column of a table
+// of a sharded keyspace, whose values are used to route the query.
+func (b *binder) isShardedVindexColumn(col *sqlparser.ColName, deps TableSet) bool {
+	ti, err := b.tc.tableInfoFor(deps)
+	if err != nil {
+		return false
+	}
+	vt := ti.GetVindexTable()
+	if vt == nil || vt.Keyspace == nil || !vt.Keyspace.Sharded {
+		return false
+	}
+	for _, cv := range vt.ColumnVindexes {
+		for _, name := range cv.Columns {
+			if name.Equal(col.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (b *binder) bindJoinCondition(condition *sqlparser.JoinCondition) error {
 	currScope := b.scoper.currentScope()
 	for _, ident := range condition.Using {
@@ -268,6 +292,10 @@ func (b *binder) resolveColumn(colName *sqlparser.ColName, current *scope, allow
 		return b.resolveColumnInHaving(colName, current, allowMulti)
 	}
 
+	if current.isUnion {
+		return b.resolveColumnInUnionOrderBy(colName, current, allowMulti)
+	}
+
 	var thisDeps dependencies
 	first := true
 	var tableName *sqlparser.TableName
@@ -303,6 +331,19 @@ func (b *binder) resolveColumn(colName *sqlparser.ColName, current *scope, allow
 	return dependency{}, ShardedError{ColumnNotFoundError{Column: colName, Table: tableName}}
 }
 
+// resolveColumnInUnionOrderBy resolves a column of the ORDER BY of a UNION, which
+// can only be one of the columns of the UNION
+func (b *binder) resolveColumnInUnionOrderBy(colName *sqlparser.ColName, current *scope, allowMulti bool) (dependency, error) {
+	deps, err := b.resolveColumnInScope(current, colName, allowMulti)
+	if err != nil {
+		return dependency{}, err
+	}
+	if !deps.empty() {
+		return deps.get(colName)
+	}
+	return dependency{}, ShardedError{&ColumnNotFoundClauseError{Column: colName.Name.String(), Clause: "order clause"}}
+}
+
 func isColumnNotFound(err error) bool {
 	switch err := err.(type) {
 	case ColumnNotFoundError:
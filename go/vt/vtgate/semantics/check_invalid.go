@@ -37,7 +37,7 @@ func (a *analyzer) checkForInvalidConstructs(cursor *sqlparser.Cursor) error {
 	case *sqlparser.Union:
 		return checkUnion(node)
 	case *sqlparser.JSONTableExpr:
-		return &JSONTablesError{}
+		return SingleShardError{Inner: &JSONTablesError{}}
 	case *sqlparser.DerivedTable:
 		return checkDerived(node)
 	case *sqlparser.AssignmentExpr:
@@ -53,7 +53,7 @@ func (a *analyzer) checkForInvalidConstructs(cursor *sqlparser.Cursor) error {
 			return ShardedError{Inner: &UnsupportedConstruct{errString: "REPLACE INTO with sharded keyspace"}}
 		}
 	case *sqlparser.OverClause:
-		return ShardedError{Inner: &UnsupportedConstruct{errString: "OVER CLAUSE with sharded keyspace"}}
+		return SingleShardError{Inner: &UnsupportedConstruct{errString: "OVER CLAUSE with sharded keyspace"}}
 	}
 
 	return nil
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// Clause is the clause of a statement that an expression belongs to.
+type Clause int
+
+// The clauses whose expressions are indexed by the columns they reference.
+const (
+	SelectClause Clause = iota
+	WhereClause
+	GroupByClause
+	HavingClause
+	OrderByClause
+)
+
+func (c Clause) String() string {
+	switch c {
+	case SelectClause:
+		return "select list"
+	case WhereClause:
+		return "where clause"
+	case GroupByClause:
+		return "group by clause"
+	case HavingClause:
+		return "having clause"
+	case OrderByClause:
+		return "order by clause"
+	}
+	return "unknown clause"
+}
+
+// ColumnUse is an expression of a clause of the query that references a column.
+type ColumnUse struct {
+	Clause Clause
+	// Expr is the expression of the clause: an expression of the select list,
+	// of the GROUP BY or of the ORDER BY, or a predicate of the WHERE or HAVING
+	// clause, split on AND.
+	Expr sqlparser.Expr
+	// Column is the reference to the column in Expr.
+	Column *sqlparser.ColName
+}
+
+// columnUsesOf indexes the expressions of the clauses of all the SELECT,
+// UPDATE and DELETE statements of the query by the columns they reference.
+// The columns of a subquery are indexed with the clauses of the subquery, not
+// with the expression that contains it. Columns are keyed by the table they
+// directly depend on, and by their lowercased name.
+func columnUsesOf(statement sqlparser.Statement, direct ExprDependencies) map[columnName][]ColumnUse {
+	uses := map[columnName][]ColumnUse{}
+	add := func(clause Clause, exprs ...sqlparser.Expr) {
+		for _, expr := range exprs {
+			_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+				switch node := node.(type) {
+				case *sqlparser.Subquery:
+					return false, nil
+				case *sqlparser.ColName:
+					key := columnName{Table: direct.dependencies(node), ColumnName: node.Name.Lowered()}
+					uses[key] = append(uses[key], ColumnUse{Clause: clause, Expr: expr, Column: node})
+				}
+				return true, nil
+			}, expr)
+		}
+	}
+	addWhere := func(clause Clause, where *sqlparser.Where) {
+		if where != nil {
+			add(clause, sqlparser.SplitAndExpression(nil, where.Expr)...)
+		}
+	}
+	addOrderBy := func(orderBy sqlparser.OrderBy) {
+		for _, order := range orderBy {
+			add(OrderByClause, order.Expr)
+		}
+	}
+
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Select:
+			for _, selectExpr := range node.SelectExprs {
+				if ae, ok := selectExpr.(*sqlparser.AliasedExpr); ok {
+					add(SelectClause, ae.Expr)
+				}
+			}
+			addWhere(WhereClause, node.Where)
+			add(GroupByClause, node.GroupBy...)
+			addWhere(HavingClause, node.Having)
+			addOrderBy(node.OrderBy)
+		case *sqlparser.Update:
+			addWhere(WhereClause, node.Where)
+			addOrderBy(node.OrderBy)
+		case *sqlparser.Delete:
+			addWhere(WhereClause, node.Where)
+			addOrderBy(node.OrderBy)
+		}
+		return true, nil
+	}, statement)
+	return uses
+}
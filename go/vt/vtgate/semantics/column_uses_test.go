@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestColumnUses(t *testing.T) {
+	query := "select t2.uid + 1, t1.id from t1 join t2 on t1.id = t2.uid " +
+		"where t2.uid > 3 and t1.id = 1 and t2.uid in (select x.id from t1 as x where x.id = t2.uid) " +
+		"group by t2.uid, t1.id order by t2.UID"
+	stmt, semTable := parseAndAnalyze(t, query, "d")
+	sel := stmt.(*sqlparser.Select)
+	t1 := semTable.DirectDeps(extract(sel, 1))
+	t2 := semTable.DirectDeps(extract(sel, 0))
+
+	uses := semTable.ColumnUses(t2, "UID")
+	var clauses []Clause
+	var exprs []string
+	for _, use := range uses {
+		clauses = append(clauses, use.Clause)
+		exprs = append(exprs, sqlparser.String(use.Expr))
+		assert.Equal(t, "uid", use.Column.Name.Lowered())
+	}
+	assert.Equal(t, []Clause{SelectClause, WhereClause, WhereClause, GroupByClause, OrderByClause, WhereClause}, clauses)
+	assert.Equal(t, []string{
+		"t2.uid + 1",
+		"t2.uid > 3",
+		"t2.uid in (select x.id from t1 as x where x.id = t2.uid)",
+		"t2.uid",
+		"t2.UID",
+		// The columns of the subquery are indexed with its own clauses.
+		"x.id = t2.uid",
+	}, exprs)
+	require.Same(t, extract(sel, 0), uses[0].Expr)
+
+	uses = semTable.ColumnUses(t1, "id")
+	require.Len(t, uses, 3)
+	assert.Equal(t, SelectClause, uses[0].Clause)
+	assert.Equal(t, "t1.id = 1", sqlparser.String(uses[1].Expr))
+	assert.Equal(t, GroupByClause, uses[2].Clause)
+
+	assert.Empty(t, semTable.ColumnUses(t1, "uid"))
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// Determinism tells whether an expression gives the same result every time, and
+// everywhere, it is evaluated. The values are ordered: an expression is as
+// non-deterministic as the least deterministic expression it contains.
+type Determinism int
+
+const (
+	// Deterministic expressions give the same result wherever they are evaluated,
+	// so vtgate and every shard agree on their value.
+	Deterministic Determinism = iota
+	// TimeDependent expressions depend on the time of the evaluation, like NOW() or
+	// CURRENT_DATE. MySQL evaluates them once per statement, but vtgate and the
+	// shards can see different times.
+	TimeDependent
+	// Random expressions give a different result every time they are evaluated,
+	// like RAND() or UUID().
+	Random
+)
+
+func (d Determinism) String() string {
+	switch d {
+	case Deterministic:
+		return "deterministic"
+	case TimeDependent:
+		return "time dependent"
+	case Random:
+		return "random"
+	}
+	return "unknown"
+}
+
+// nonDeterministicExprs classifies all the expressions of the statement and
+// returns the ones that are not deterministic.
+func nonDeterministicExprs(statement sqlparser.Statement) map[sqlparser.Expr]Determinism {
+	m := map[sqlparser.Expr]Determinism{}
+	_ = sqlparser.Rewrite(statement, nil, func(cursor *sqlparser.Cursor) bool {
+		expr, ok := cursor.Node().(sqlparser.Expr)
+		if !ok || !ValidAsMapKey(expr) {
+			return true
+		}
+		// The children are visited before the parent, so they have already
+		// pushed their determinism up to this expression.
+		d := max(m[expr], determinismOf(expr))
+		if d == Deterministic {
+			return true
+		}
+		m[expr] = d
+		if parent, ok := cursor.Parent().(sqlparser.Expr); ok && ValidAsMapKey(parent) {
+			m[parent] = max(m[parent], d)
+		}
+		return true
+	})
+	return m
+}
+
+// classifyExpr returns the determinism of the expression and of everything it contains.
+func classifyExpr(expr sqlparser.Expr, known map[sqlparser.Expr]Determinism) Determinism {
+	d := Deterministic
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		e, ok := node.(sqlparser.Expr)
+		if !ok {
+			return true, nil
+		}
+		if ValidAsMapKey(e) {
+			if known, found := known[e]; found {
+				d = max(d, known)
+				return false, nil
+			}
+		}
+		d = max(d, determinismOf(e))
+		return d != Random, nil
+	}, expr)
+	return d
+}
+
+// determinismOf returns the determinism of the expression itself, without looking at its children.
+func determinismOf(expr sqlparser.Expr) Determinism {
+	switch expr := expr.(type) {
+	case *sqlparser.CurTimeFuncExpr:
+		return TimeDependent
+	case *sqlparser.FuncExpr:
+		switch expr.Name.Lowered() {
+		case "rand", "uuid", "uuid_short", "random_bytes":
+			return Random
+		case "curdate", "current_date", "utc_date":
+			return TimeDependent
+		case "unix_timestamp":
+			if len(expr.Exprs) == 0 {
+				return TimeDependent
+			}
+		}
+	}
+	return Deterministic
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestDeterminism(t *testing.T) {
+	tcases := []struct {
+		expr     string
+		expected Determinism
+	}{
+		{expr: "col + 1", expected: Deterministic},
+		{expr: "unix_timestamp(col)", expected: Deterministic},
+		{expr: "now()", expected: TimeDependent},
+		{expr: "current_date", expected: TimeDependent},
+		{expr: "unix_timestamp()", expected: TimeDependent},
+		{expr: "date_add(now(), interval 1 day)", expected: TimeDependent},
+		{expr: "rand()", expected: Random},
+		{expr: "concat('x', uuid())", expected: Random},
+		{expr: "rand() > now()", expected: Random},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.expr, func(t *testing.T) {
+			stmt, semTable := parseAndAnalyze(t, "select "+tcase.expr+" from t2", "d")
+			expr := extract(stmt.(*sqlparser.Select), 0)
+			assert.Equal(t, tcase.expected, semTable.DeterminismOf(expr))
+
+			// expressions the planner creates later are classified too
+			clone := sqlparser.CloneExpr(expr)
+			assert.Equal(t, tcase.expected, semTable.DeterminismOf(clone))
+		})
+	}
+}
+
+func TestDeterminismOfContainingExpressions(t *testing.T) {
+	stmt, semTable := parseAndAnalyze(t, "select id from t2 where id = rand() and uid = 1", "d")
+	where := stmt.(*sqlparser.Select).Where.Expr.(*sqlparser.AndExpr)
+
+	assert.Equal(t, Random, semTable.DeterminismOf(where))
+	assert.Equal(t, Random, semTable.DeterminismOf(where.Left))
+	assert.Equal(t, Deterministic, semTable.DeterminismOf(where.Right))
+	assert.NotContains(t, semTable.NonDeterministic, where.Right)
+}
@@ -36,6 +36,9 @@ type earlyRewriter struct {
 	env             *vtenv.Environment
 	aliasMapCache   map[*sqlparser.Select]map[string]exprContainer
 	tables          *tableCollector
+	// positions are the positions of the expressions in the query text, that
+	// are given to the expressions that replace them.
+	positions sqlparser.Positions
 
 	// reAnalyze is used when we are running in the late stage, after the other parts of semantic analysis
 	// have happened, and we are introducing or changing the AST. We invoke it so all parts of the query have been
@@ -47,8 +50,6 @@ func (r *earlyRewriter) down(cursor *sqlparser.Cursor) error {
 	switch node := cursor.Node().(type) {
 	case sqlparser.SelectExprs:
 		return r.handleSelectExprs(cursor, node)
-	case *sqlparser.JoinTableExpr:
-		r.handleJoinTableExprDown(node)
 	case *sqlparser.OrExpr:
 		rewriteOrExpr(r.env, cursor, node)
 	case *sqlparser.AndExpr:
@@ -223,15 +224,6 @@ func (r *earlyRewriter) handleSelectExprs(cursor *sqlparser.Cursor, node sqlpars
 	return r.expandStar(cursor, node)
 }
 
-// handleJoinTableExprDown processes JOIN table expressions and handles the Straight Join type.
-func (r *earlyRewriter) handleJoinTableExprDown(node *sqlparser.JoinTableExpr) {
-	if node.Join != sqlparser.StraightJoinType {
-		return
-	}
-	node.Join = sqlparser.NormalJoinType
-	r.warning = "straight join is converted to normal join"
-}
-
 type orderByIterator struct {
 	node sqlparser.OrderBy
 	idx  int
@@ -494,7 +486,7 @@ func (r *earlyRewriter) rewriteAliasesInGroupBy(node sqlparser.Expr, sel *sqlpar
 			}
 
 			if item.ambiguous {
-				err = newAmbiguousColumnError(col)
+				err = errorAt(r.positions, col, newAmbiguousColumnError(col))
 			} else if aggrTrack.insideAggr && sqlparser.ContainsAggregation(item.expr) {
 				err = &InvalidUseOfGroupFunction{}
 			}
@@ -503,7 +495,9 @@ func (r *earlyRewriter) rewriteAliasesInGroupBy(node sqlparser.Expr, sel *sqlpar
 				return
 			}
 
-			cursor.Replace(sqlparser.CloneExpr(item.expr))
+			newExpr := sqlparser.CloneExpr(item.expr)
+			r.positions.Transfer(col, newExpr)
+			cursor.Replace(newExpr)
 		}
 	}, nil)
 
@@ -556,7 +550,7 @@ func (r *earlyRewriter) rewriteAliasesInHaving(node sqlparser.Expr, sel *sqlpars
 
 		// If we get here, it means we have found an alias and want to use it
 		if item.ambiguous {
-			err = newAmbiguousColumnError(col)
+			err = errorAt(r.positions, col, newAmbiguousColumnError(col))
 		} else if aggrTrack.insideAggr && sqlparser.ContainsAggregation(item.expr) {
 			err = &InvalidUseOfGroupFunction{}
 		}
@@ -566,7 +560,7 @@ func (r *earlyRewriter) rewriteAliasesInHaving(node sqlparser.Expr, sel *sqlpars
 		}
 
 		newColName := sqlparser.CopyOnRewrite(item.expr, nil, r.fillInQualifiers, nil)
-
+		r.positions.Transfer(col, newColName)
 		cursor.Replace(newColName)
 	}, nil)
 
@@ -650,7 +644,7 @@ func (r *earlyRewriter) rewriteAliasesInOrderBy(node sqlparser.Expr, sel *sqlpar
 		}
 
 		if item.ambiguous {
-			err = newAmbiguousColumnError(col)
+			err = errorAt(r.positions, col, newAmbiguousColumnError(col))
 		} else if aggrTrack.insideAggr && sqlparser.ContainsAggregation(item.expr) {
 			err = &InvalidUseOfGroupFunction{}
 		}
@@ -660,7 +654,7 @@ func (r *earlyRewriter) rewriteAliasesInOrderBy(node sqlparser.Expr, sel *sqlpar
 		}
 
 		newColName := sqlparser.CopyOnRewrite(item.expr, nil, r.fillInQualifiers, nil)
-
+		r.positions.Transfer(col, newColName)
 		cursor.Replace(newColName)
 	}, nil)
 
@@ -683,7 +677,9 @@ func (r *earlyRewriter) fillInQualifiers(cursor *sqlparser.CopyOnWriteCursor) {
 	if err != nil {
 		panic(err)
 	}
-	cursor.Replace(sqlparser.NewColNameWithQualifier(col.Name.String(), tblName))
+	newColName := sqlparser.NewColNameWithQualifier(col.Name.String(), tblName)
+	r.positions.Transfer(col, newColName)
+	cursor.Replace(newColName)
 }
 
 func (r *earlyRewriter) isColumnOnTable(col *sqlparser.ColName, currentScope *scope) (isColumn bool, isCertain bool) {
@@ -773,8 +769,7 @@ func (r *earlyRewriter) rewriteOrderByLiteral(node *sqlparser.Literal) (expr sql
 		}
 
 		// since column names can be ambiguous here, we want to do the binding by offset and not by column name
-		allColExprs := vtabl.cols[colOffset]
-		direct, recursive, typ := r.binder.org.depsForExpr(allColExprs)
+		direct, recursive, typ := vtabl.depsForCol(r.binder.org, colOffset)
 		r.binder.direct[colName] = direct
 		r.binder.recursive[colName] = recursive
 		r.binder.typer.m[colName] = typ
@@ -184,6 +184,9 @@ func TestExpandStar(t *testing.T) {
 		// if we are only star-expanding authoritative tables, we don't need to stop the expansion
 		sql:    "SELECT * FROM (SELECT t2.*, 12 AS foo FROM t3, t2) as results",
 		expSQL: "select c1, c2, foo from (select t2.c1, t2.c2, 12 as foo from t3, t2) as results",
+	}, {
+		sql:    "select * from t1, json_table(t1.a, '$[*]' columns(id for ordinality, nested path '$.x' columns (x int path '$'))) as jt",
+		expSQL: "select t1.a, t1.b, t1.c, jt.id, jt.x from t1, json_table(t1.a, '$[*]' columns(\n\tid for ordinality,\n\tnested path '$.x' columns(\n\tx int path '$' \n)\n\t)\n) as jt",
 	}}
 	for _, tcase := range tcases {
 		t.Run(tcase.sql, func(t *testing.T) {
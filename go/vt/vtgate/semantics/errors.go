@@ -17,6 +17,7 @@ limitations under the License.
 package semantics
 
 import (
+	"errors"
 	"fmt"
 
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
@@ -76,6 +77,11 @@ type (
 		Column string
 		Clause string
 	}
+	PositionedError struct {
+		Err error
+		// Pos is the 0-based offset of the expression in the query text.
+		Pos int
+	}
 )
 
 func eprintf(e error, format string, args ...any) string {
@@ -336,3 +342,37 @@ func (*ColumnNotFoundClauseError) ErrorCode() vtrpcpb.Code {
 func (e *ColumnNotFoundClauseError) ErrorState() vterrors.State {
 	return vterrors.BadFieldError
 }
+
+// PositionedError
+func (e *PositionedError) Error() string {
+	return fmt.Sprintf("%s (near character %d)", e.Err.Error(), e.Pos+1)
+}
+
+func (e *PositionedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *PositionedError) ErrorCode() vtrpcpb.Code {
+	return vterrors.Code(e.Err)
+}
+
+func (e *PositionedError) ErrorState() vterrors.State {
+	return vterrors.ErrState(e.Err)
+}
+
+// errorAt points the error at the position of the node in the query text,
+// when the position of the node, or of an expression it contains, is known.
+func errorAt(positions sqlparser.Positions, node sqlparser.SQLNode, err error) error {
+	if err == nil {
+		return nil
+	}
+	pos, ok := positions.Find(node)
+	if !ok {
+		return err
+	}
+	var positioned *PositionedError
+	if errors.As(err, &positioned) {
+		return err
+	}
+	return &PositionedError{Err: err, Pos: pos}
+}
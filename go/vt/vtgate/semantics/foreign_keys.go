@@ -184,6 +184,10 @@ func (fk *fkManager) getAllManagedForeignKeys() (map[TableSet][]vindexes.ChildFK
 			// If is not a real table, so should be skipped.
 			continue
 		}
+		if _, isVitessMetadata := table.(*VitessMetadataTable); isVitessMetadata {
+			// vitess_metadata tables are not backed by a keyspace and have no foreign keys.
+			continue
+		}
 		// Check whether Vitess needs to manage the foreign keys in this keyspace or not.
 		fkMode, err := fk.si.ForeignKeyMode(vi.Keyspace.Name)
 		if err != nil {
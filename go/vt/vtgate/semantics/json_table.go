@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// JSONTable contains the JSON_TABLE table expression. Its columns are the
+// ones declared in the COLUMNS clause, including the nested paths.
+type JSONTable struct {
+	ASTNode *sqlparser.JSONTableExpr
+	columns []ColumnInfo
+	tableID TableSet
+}
+
+var _ TableInfo = (*JSONTable)(nil)
+
+func newJSONTable(node *sqlparser.JSONTableExpr, id TableSet, collationEnv *collations.Environment) *JSONTable {
+	return &JSONTable{
+		ASTNode: node,
+		columns: jtColumnsToColumnInfo(node.Columns, collationEnv, nil),
+		tableID: id,
+	}
+}
+
+func jtColumnsToColumnInfo(defs []*sqlparser.JtColumnDefinition, collationEnv *collations.Environment, cols []ColumnInfo) []ColumnInfo {
+	for _, def := range defs {
+		switch {
+		case def.JtOrdinal != nil:
+			cols = append(cols, ColumnInfo{
+				Name: def.JtOrdinal.Name.String(),
+				Type: evalengine.NewType(sqltypes.Uint32, collations.CollationBinaryID),
+			})
+		case def.JtPath != nil:
+			info := ColumnInfo{Name: def.JtPath.Name.String()}
+			if def.JtPath.JtColExists {
+				info.Type = evalengine.NewType(sqltypes.Int32, collations.CollationBinaryID)
+			} else if def.JtPath.Type != nil {
+				typ := def.JtPath.Type.SQLType()
+				info.Type = evalengine.NewType(typ, collations.CollationForType(typ, collationEnv.DefaultConnectionCharset()))
+			}
+			cols = append(cols, info)
+		case def.JtNestedPath != nil:
+			cols = jtColumnsToColumnInfo(def.JtNestedPath.Columns, collationEnv, cols)
+		}
+	}
+	return cols
+}
+
+// dependencies implements the TableInfo interface
+func (jt *JSONTable) dependencies(colName string, _ originable) (dependencies, error) {
+	for _, info := range jt.columns {
+		if strings.EqualFold(info.Name, colName) {
+			return createCertain(jt.tableID, jt.tableID, info.Type), nil
+		}
+	}
+	return &nothing{}, nil
+}
+
+// getTableSet implements the TableInfo interface
+func (jt *JSONTable) getTableSet(_ originable) TableSet {
+	return jt.tableID
+}
+
+// getExprFor implements the TableInfo interface
+func (jt *JSONTable) getExprFor(s string) (sqlparser.Expr, error) {
+	return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "Unknown column '%s' in 'field list'", s)
+}
+
+// IsInfSchema implements the TableInfo interface
+func (jt *JSONTable) IsInfSchema() bool {
+	return false
+}
+
+// getColumns implements the TableInfo interface
+func (jt *JSONTable) getColumns() []ColumnInfo {
+	return jt.columns
+}
+
+// GetAliasedTableExpr implements the TableInfo interface
+func (jt *JSONTable) GetAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return nil
+}
+
+func (jt *JSONTable) canShortCut() shortCut {
+	// the table function is evaluated by MySQL, so it goes wherever the rest of the query goes
+	return canShortCut
+}
+
+// GetVindexTable implements the TableInfo interface
+func (jt *JSONTable) GetVindexTable() *vindexes.Table {
+	return nil
+}
+
+// Name implements the TableInfo interface
+func (jt *JSONTable) Name() (sqlparser.TableName, error) {
+	return sqlparser.TableName{Name: jt.ASTNode.Alias}, nil
+}
+
+// authoritative implements the TableInfo interface
+func (jt *JSONTable) authoritative() bool {
+	return true
+}
+
+// matches implements the TableInfo interface
+func (jt *JSONTable) matches(name sqlparser.TableName) bool {
+	return jt.ASTNode.Alias.String() == name.Name.String() && name.Qualifier.IsEmpty()
+}
@@ -160,6 +160,10 @@ func (s *scoper) enterJoinScope(cursor *sqlparser.Cursor) {
 		// To create this special context, we will find the parent scope of the select statement involved.
 		currScope := s.currentScope()
 		stmtScope := currScope.findParentScopeOfStatement()
+		if _, isJSONTable := cursor.Node().(*sqlparser.JSONTableExpr); isJSONTable {
+			// JSON_TABLE is allowed to use the columns of the tables preceding it in the FROM clause
+			stmtScope = currScope
+		}
 		nScope := newScope(stmtScope)
 		if stmtScope == nil {
 			// TODO: this feels hacky. revisit with a better plan
@@ -270,6 +274,8 @@ func (s *scoper) createSpecialScopePostProjection(parent sqlparser.SQLNode) erro
 			return vterrors.Errorf(vtrpcpb.Code_INTERNAL, "BUG: scope counts did not match")
 		}
 	case *sqlparser.Union:
+		// The ORDER BY of a UNION can only see the columns of the UNION, that are named after
+		// the columns of its first SELECT.
 		nScope := newScope(nil)
 		nScope.isUnion = true
 		var tableInfo *vTableInfo
@@ -277,12 +283,21 @@ func (s *scoper) createSpecialScopePostProjection(parent sqlparser.SQLNode) erro
 		for i, sel := range sqlparser.GetAllSelects(parent) {
 			if i == 0 {
 				nScope.stmt = sel
-				tableInfo = createVTableInfoForExpressions(sel.SelectExprs, nil /*needed for star expressions*/, s.org)
+				var tables []TableInfo
+				if selScope := s.wScope[sel]; selScope != nil {
+					// a * that could not be expanded makes the columns of the UNION unknown
+					tables = selScope.tables
+				}
+				tableInfo = createVTableInfoForExpressions(sel.SelectExprs, tables, s.org)
 				nScope.tables = append(nScope.tables, tableInfo)
 			}
 			thisTableInfo := createVTableInfoForExpressions(sel.SelectExprs, nil /*needed for star expressions*/, s.org)
 			if len(tableInfo.cols) != len(thisTableInfo.cols) {
-				return vterrors.NewErrorf(vtrpcpb.Code_FAILED_PRECONDITION, vterrors.WrongNumberOfColumnsInSelect, "The used SELECT statements have a different number of columns")
+				if containsStar(sel.SelectExprs) || tableInfo.hasStar() {
+					// the number of columns is only known once the * are expanded
+					continue
+				}
+				return &UnionColumnsDoNotMatchError{FirstProj: len(tableInfo.cols), SecondProj: len(thisTableInfo.cols)}
 			}
 			for i, col := range tableInfo.cols {
 				// at this stage, we don't store the actual dependencies, we only store the expressions.
@@ -291,6 +306,9 @@ func (s *scoper) createSpecialScopePostProjection(parent sqlparser.SQLNode) erro
 				tableInfo.cols[i] = sqlparser.AndExpressions(col, thisTableInfo.cols[i])
 			}
 		}
+		if !tableInfo.hasStar() {
+			tableInfo.types = unionColumnTypes(parent, len(tableInfo.cols), s.org)
+		}
 
 		s.push(nScope)
 	}
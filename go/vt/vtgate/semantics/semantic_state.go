@@ -18,6 +18,7 @@ package semantics
 
 import (
 	"fmt"
+	"strings"
 
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
@@ -81,8 +82,11 @@ type (
 		DML         bool
 		Distinct    bool
 		HashJoin    bool
-		SubQueries  bool
-		Union       bool
+		// StraightJoin is set when the query uses STRAIGHT_JOIN, either as a
+		// join or as a SELECT option, to fix the order of its joins.
+		StraightJoin bool
+		SubQueries   bool
+		Union        bool
 	}
 
 	// SemTable contains semantic analysis information about the query.
@@ -98,6 +102,10 @@ type (
 		Collation collations.ID
 		// ExprTypes maps expressions to their respective types in the query.
 		ExprTypes map[sqlparser.Expr]evalengine.Type
+		// BindVarTypes maps the bind variables compared with the vindex columns of sharded
+		// tables to the types of the columns, so that vtgate can coerce the values the client
+		// provides for them before routing the query.
+		BindVarTypes map[string]evalengine.Type
 
 		// NotSingleRouteErr stores errors related to missing schema information.
 		// This typically occurs when a column's existence is uncertain.
@@ -111,6 +119,11 @@ type (
 		// MySQL engine to handle errors appropriately.
 		NotUnshardedErr error
 
+		// NotSingleShardErr stores errors that occur if the query isn't planned as a single route
+		// targeting a single shard. Window functions, for instance, can be sent as is to a single
+		// shard, but vtgate cannot evaluate them across shards.
+		NotSingleShardErr error
+
 		// Recursive contains dependencies from the expression to the actual tables
 		// in the query (excluding derived tables). For columns in derived tables,
 		// this map holds the accumulated dependencies for the column expression.
@@ -139,6 +152,18 @@ type (
 		// QuerySignature is used to identify shortcuts in the planning process
 		QuerySignature QuerySignature
 
+		// Positions maps the expressions of the query to their position in the query text,
+		// when it is known. It is used to point planner errors at the expression that caused them.
+		Positions sqlparser.Positions
+
+		// NonDeterministic holds the expressions of the query that are time dependent or random,
+		// and that the planner can't evaluate more than once, or in more than one place.
+		// Expressions missing from the map are deterministic.
+		NonDeterministic map[sqlparser.Expr]Determinism
+
+		// columnUses indexes the expressions of the clauses of the query by the columns they reference.
+		columnUses map[columnName][]ColumnUse
+
 		// We store the child and parent foreign keys that are involved in the given query.
 		// The map is keyed by the tableset of the table that each of the foreign key belongs to.
 		childForeignKeysInvolved  map[TableSet][]vindexes.ChildFKInfo
@@ -187,9 +212,16 @@ func (st *SemTable) CopyDependencies(from, to sqlparser.Expr) {
 				st.ExprTypes[to] = typ
 			}
 		}
+		st.Positions.Transfer(from, to)
 	}
 }
 
+// ErrorAt points the error at the position of the node in the query text, when the position
+// of the node, or of an expression it contains, is known.
+func (st *SemTable) ErrorAt(node sqlparser.SQLNode, err error) error {
+	return errorAt(st.Positions, node, err)
+}
+
 // GetChildForeignKeysForTargets gets the child foreign keys as a list for all the target tables.
 func (st *SemTable) GetChildForeignKeysForTargets() (fks []vindexes.ChildFKInfo) {
 	for _, ts := range st.Targets.Constituents() {
@@ -555,6 +587,16 @@ func (st *SemTable) TableSetFor(t *sqlparser.AliasedTableExpr) TableSet {
 	return EmptyTableSet()
 }
 
+// TableSetForJSONTable returns the bitmask for the given JSON_TABLE expression
+func (st *SemTable) TableSetForJSONTable(t *sqlparser.JSONTableExpr) TableSet {
+	for idx, t2 := range st.Tables {
+		if jt, ok := t2.(*JSONTable); ok && jt.ASTNode == t {
+			return SingleTableSet(idx)
+		}
+	}
+	return EmptyTableSet()
+}
+
 // ReplaceTableSetFor replaces the given single TabletSet with the new *sqlparser.AliasedTableExpr
 func (st *SemTable) ReplaceTableSetFor(id TableSet, t *sqlparser.AliasedTableExpr) {
 	if st == nil {
@@ -608,6 +650,15 @@ func (st *SemTable) AddColumnEquality(colName *sqlparser.ColName, expr sqlparser
 	st.ColumnEqualities[columnName] = elem
 }
 
+// ColumnUses returns the expressions of the select list, WHERE, GROUP BY,
+// HAVING and ORDER BY clauses of the query that reference the column of the
+// table, as found by the analysis. The column name is case-insensitive.
+// Expressions that the planner rewrote or added after the analysis are not
+// part of the result.
+func (st *SemTable) ColumnUses(table TableSet, column string) []ColumnUse {
+	return st.columnUses[columnName{Table: table, ColumnName: strings.ToLower(column)}]
+}
+
 // GetExprAndEqualities returns a slice containing the given expression, and it's known equalities if any
 func (st *SemTable) GetExprAndEqualities(expr sqlparser.Expr) []sqlparser.Expr {
 	result := []sqlparser.Expr{expr}
@@ -634,6 +685,17 @@ func (st *SemTable) AddExprs(tbl *sqlparser.AliasedTableExpr, cols sqlparser.Sel
 	}
 }
 
+// DeterminismOf returns whether the expression gives the same result wherever it is evaluated.
+// Expressions that the planner created after the analysis are classified when asked for.
+func (st *SemTable) DeterminismOf(e sqlparser.Expr) Determinism {
+	if ValidAsMapKey(e) {
+		if d, found := st.NonDeterministic[e]; found {
+			return d
+		}
+	}
+	return classifyExpr(e, st.NonDeterministic)
+}
+
 // TypeForExpr returns the type of expressions in the query
 func (st *SemTable) TypeForExpr(e sqlparser.Expr) (evalengine.Type, bool) {
 	if typ, found := st.ExprTypes[e]; found {
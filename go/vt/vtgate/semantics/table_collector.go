@@ -110,11 +110,19 @@ func (tc *tableCollector) up(cursor *sqlparser.Cursor) error {
 		return tc.visitAliasedTableExpr(node)
 	case *sqlparser.Union:
 		return tc.visitUnion(node)
+	case *sqlparser.JSONTableExpr:
+		return tc.visitJSONTable(node)
 	default:
 		return nil
 	}
 }
 
+func (tc *tableCollector) visitJSONTable(node *sqlparser.JSONTableExpr) error {
+	tableInfo := newJSONTable(node, SingleTableSet(len(tc.Tables)), tc.org.collationEnv())
+	tc.Tables = append(tc.Tables, tableInfo)
+	return tc.scoper.currentScope().addTable(tableInfo)
+}
+
 func (tc *tableCollector) visitUnion(union *sqlparser.Union) error {
 	firstSelect := sqlparser.GetFirstSelect(union)
 	expanded, selectExprs := getColumnNames(firstSelect.SelectExprs)
@@ -129,32 +137,62 @@ func (tc *tableCollector) visitUnion(union *sqlparser.Union) error {
 
 	size := len(firstSelect.SelectExprs)
 	info.recursive = make([]TableSet, size)
+	_ = sqlparser.VisitAllSelects(union, func(s *sqlparser.Select, idx int) error {
+		for i, expr := range s.SelectExprs {
+			ae, ok := expr.(*sqlparser.AliasedExpr)
+			if !ok {
+				continue
+			}
+			_, recursiveDeps, _ := tc.org.depsForExpr(ae.Expr)
+			info.recursive[i] = info.recursive[i].Merge(recursiveDeps)
+		}
+		return nil
+	})
+	info.types = unionColumnTypes(union, size, tc.org)
+	tc.unionInfo[union] = info
+	return nil
+}
+
+var errStarInUnion = vterrors.VT13001("unexpanded * in UNION")
+
+// unionColumnTypes returns the types of the columns of the UNION, that are aggregated
+// from the types of the columns of all its SELECTs with the rules of MySQL. The type
+// of a column is unknown if the type of any of its expressions is unknown, or if the
+// types cannot be aggregated. The types of all the columns are unknown while a SELECT
+// has a * that could not be expanded.
+func unionColumnTypes(union *sqlparser.Union, size int, org originable) []evalengine.Type {
 	typers := make([]evalengine.TypeAggregator, size)
-	collations := tc.org.collationEnv()
+	failed := make([]bool, size)
+	collationEnv := org.collationEnv()
 
+	types := make([]evalengine.Type, size)
 	err := sqlparser.VisitAllSelects(union, func(s *sqlparser.Select, idx int) error {
+		if containsStar(s.SelectExprs) {
+			return errStarInUnion
+		}
 		for i, expr := range s.SelectExprs {
 			ae, ok := expr.(*sqlparser.AliasedExpr)
-			if !ok {
+			if !ok || i >= size {
 				continue
 			}
-			_, recursiveDeps, qt := tc.org.depsForExpr(ae.Expr)
-			info.recursive[i] = info.recursive[i].Merge(recursiveDeps)
-			if err := typers[i].Add(qt, collations); err != nil {
-				return err
+			_, _, qt := org.depsForExpr(ae.Expr)
+			if err := typers[i].Add(qt, collationEnv); err != nil {
+				// MySQL decides if the collations can be mixed, so we only lose the type information.
+				failed[i] = true
 			}
 		}
 		return nil
 	})
 	if err != nil {
-		return err
+		return types
 	}
 
-	for _, ts := range typers {
-		info.types = append(info.types, ts.Type())
+	for i := range typers {
+		if !failed[i] {
+			types[i] = typers[i].Type()
+		}
 	}
-	tc.unionInfo[union] = info
-	return nil
+	return types
 }
 
 func (tc *tableCollector) visitAliasedTableExpr(node *sqlparser.AliasedTableExpr) error {
@@ -186,6 +224,10 @@ func (tc *tableCollector) handleTableName(node *sqlparser.AliasedTableExpr, t sq
 }
 
 func getTableInfo(node *sqlparser.AliasedTableExpr, t sqlparser.TableName, si SchemaInformation, currentDb string) (TableInfo, error) {
+	if vindexes.IsVitessMetadataSchema(t.Qualifier.String()) {
+		return createVitessMetadataTable(t, node, si, currentDb)
+	}
+
 	var tbl *vindexes.Table
 	var vindex vindexes.Vindex
 	isInfSchema := sqlparser.SystemSchema(t.Qualifier.String())
@@ -18,6 +18,7 @@ package semantics
 
 import (
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vtgate/engine/opcode"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
@@ -28,12 +29,22 @@ import (
 type typer struct {
 	m            map[sqlparser.Expr]evalengine.Type
 	collationEnv *collations.Environment
+
+	// vindexColumns are the vindex columns of the tables of sharded keyspaces.
+	vindexColumns map[*sqlparser.ColName]bool
+	// bindVars maps the bind variables compared with vindex columns to the types of the columns.
+	bindVars map[string]evalengine.Type
+	// ambiguousBindVars are the bind variables compared with columns of different types.
+	ambiguousBindVars map[string]bool
 }
 
 func newTyper(collationEnv *collations.Environment) *typer {
 	return &typer{
-		m:            map[sqlparser.Expr]evalengine.Type{},
-		collationEnv: collationEnv,
+		m:                 map[sqlparser.Expr]evalengine.Type{},
+		collationEnv:      collationEnv,
+		vindexColumns:     map[*sqlparser.ColName]bool{},
+		bindVars:          map[string]evalengine.Type{},
+		ambiguousBindVars: map[string]bool{},
 	}
 }
 
@@ -61,10 +72,91 @@ func (t *typer) up(cursor *sqlparser.Cursor) error {
 			}
 		}
 		t.m[node] = code.ResolveType(inputType, t.collationEnv)
+	case *sqlparser.CollateExpr:
+		t.typeCollateExpr(node)
+	case *sqlparser.ComparisonExpr:
+		t.typeComparedBindVars(node)
 	}
 	return nil
 }
 
+// typeComparedBindVars records the type of the vindex column of a sharded table a bind
+// variable is compared with, so that the values the client provides for it can be coerced
+// to that type before they are used to route the query.
+func (t *typer) typeComparedBindVars(node *sqlparser.ComparisonExpr) {
+	switch node.Operator {
+	case sqlparser.EqualOp, sqlparser.NotEqualOp, sqlparser.LessThanOp, sqlparser.LessEqualOp,
+		sqlparser.GreaterThanOp, sqlparser.GreaterEqualOp, sqlparser.NullSafeEqualOp:
+		t.typeComparedBindVar(node.Left, node.Right)
+		t.typeComparedBindVar(node.Right, node.Left)
+	case sqlparser.InOp, sqlparser.NotInOp:
+		switch right := node.Right.(type) {
+		case sqlparser.ListArg:
+			if typ, ok := t.columnType(node.Left); ok {
+				t.setBindVarType(string(right), typ)
+			}
+		case sqlparser.ValTuple:
+			for _, expr := range right {
+				t.typeComparedBindVar(node.Left, expr)
+			}
+		}
+	}
+}
+
+func (t *typer) typeComparedBindVar(col, arg sqlparser.Expr) {
+	bv, ok := arg.(*sqlparser.Argument)
+	if !ok {
+		return
+	}
+	if typ, ok := t.columnType(col); ok {
+		t.setBindVarType(bv.Name, typ)
+	}
+}
+
+func (t *typer) columnType(expr sqlparser.Expr) (evalengine.Type, bool) {
+	col, ok := expr.(*sqlparser.ColName)
+	if !ok {
+		return evalengine.Type{}, false
+	}
+	if !t.vindexColumns[col] {
+		return evalengine.Type{}, false
+	}
+	typ, ok := t.m[col]
+	return typ, ok && typ.Valid()
+}
+
+func (t *typer) setBindVarType(name string, typ evalengine.Type) {
+	if t.ambiguousBindVars[name] {
+		return
+	}
+	if prev, ok := t.bindVars[name]; ok && prev.Type() != typ.Type() {
+		delete(t.bindVars, name)
+		t.ambiguousBindVars[name] = true
+		return
+	}
+	t.bindVars[name] = typ
+}
+
+// typeCollateExpr gives `expr COLLATE name` the type of expr with the explicit collation,
+// so that comparing and sorting on it can be done by the vtgate without weight_string.
+// The collation must be supported and belong to the character set of expr, otherwise
+// the expression is left untyped.
+func (t *typer) typeCollateExpr(node *sqlparser.CollateExpr) {
+	inner, ok := t.m[node.Expr]
+	if !ok || !sqltypes.IsText(inner.Type()) {
+		return
+	}
+	coll := t.collationEnv.LookupByName(node.Collation)
+	if coll == collations.Unknown || t.collationEnv.LookupCharsetName(coll) != t.collationEnv.LookupCharsetName(inner.Collation()) {
+		return
+	}
+	t.m[node] = evalengine.NewTypeEx(inner.Type(), coll, inner.Nullable(), inner.Size(), inner.Scale())
+}
+
 func (t *typer) setTypeFor(node *sqlparser.ColName, typ evalengine.Type) {
 	t.m[node] = typ
 }
+
+func (t *typer) setVindexColumn(node *sqlparser.ColName) {
+	t.vindexColumns[node] = true
+}
@@ -24,6 +24,8 @@ import (
 	"vitess.io/vitess/go/mysql/collations/colldata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
 func TestNormalizerAndSemanticAnalysisIntegration(t *testing.T) {
@@ -92,3 +94,150 @@ func TestColumnCollations(t *testing.T) {
 		})
 	}
 }
+
+// Tests that an explicit COLLATE clause overrides the collation of a text expression
+func TestCollateExprTypes(t *testing.T) {
+	tests := []struct {
+		query, collation string
+	}{
+		{query: "select name collate utf8mb3_general_ci from t2", collation: "utf8mb3_general_ci"},
+		{query: "select name collate utf8_unicode_ci from t2", collation: "utf8mb3_unicode_ci"},
+		{query: "select name collate utf8mb4_bin from t2"},
+		{query: "select textcol collate big5_chinese_ci from t2"},
+		{query: "select id collate utf8mb4_bin from t2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			parse, err := sqlparser.NewTestParser().Parse(test.query)
+			require.NoError(t, err)
+
+			st, err := Analyze(parse, "d", fakeSchemaInfo())
+			require.NoError(t, err)
+			expr := extract(parse.(*sqlparser.Select), 0)
+			typ, found := st.TypeForExpr(expr)
+			if test.collation == "" {
+				require.False(t, found, "expression should not be typed")
+				return
+			}
+			require.True(t, found, "expression was not typed")
+			require.Equal(t, "VARCHAR", typ.Type().String())
+			collation := colldata.Lookup(typ.Collation())
+			require.NotNil(t, collation)
+			require.Equal(t, test.collation, collation.Name())
+		})
+	}
+}
+
+// Tests that the columns of a UNION have the types aggregated from all its SELECTs
+func TestUnionColumnTypes(t *testing.T) {
+	tests := []struct {
+		query string
+		typ   []string
+	}{
+		{query: "select id from t1 union select uid from t2 order by id", typ: []string{"INT64"}},
+		{query: "select id as x from t1 union select 1.5 from t2 order by x", typ: []string{"DECIMAL"}},
+		{query: "select id, 'a' from t1 union select 1.5, uid from t2 union select 1e0, 2 from dual order by 1, 2", typ: []string{"FLOAT64", "VARCHAR"}},
+		{query: "select name from t2 union select uid from t2 order by name", typ: []string{"VARCHAR"}},
+		// big5 can't be merged with the numeric collation, so the type is left to MySQL
+		{query: "select id from t1 union select textcol from t2 order by id", typ: []string{""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			parse, err := sqlparser.NewTestParser().Parse(test.query)
+			require.NoError(t, err)
+
+			st, err := Analyze(parse, "d", fakeSchemaInfo())
+			require.NoError(t, err)
+
+			union := parse.(*sqlparser.Union)
+			for i, expr := range st.SelectExprs(union) {
+				typ, _ := st.TypeForExpr(expr.(*sqlparser.AliasedExpr).Expr)
+				require.Equal(t, test.typ[i], typeString(typ), "column %d", i)
+			}
+			for i, order := range union.OrderBy {
+				typ, _ := st.TypeForExpr(order.Expr)
+				require.Equal(t, test.typ[i], typeString(typ), "order by %d", i)
+			}
+		})
+	}
+}
+
+func typeString(typ evalengine.Type) string {
+	if !typ.Valid() {
+		return ""
+	}
+	return typ.Type().String()
+}
+
+// Tests that the bind variables compared with the vindex columns of sharded tables get the types of the columns
+func TestBindVarTypesFromComparisons(t *testing.T) {
+	column := func(name string, typ querypb.Type) vindexes.Column {
+		return vindexes.Column{Name: sqlparser.NewIdentifierCI(name), Type: typ}
+	}
+	vindexOn := func(columns ...string) *vindexes.ColumnVindex {
+		cv := &vindexes.ColumnVindex{Name: columns[0] + "_vindex"}
+		for _, col := range columns {
+			cv.Columns = append(cv.Columns, sqlparser.NewIdentifierCI(col))
+		}
+		return cv
+	}
+	si := &FakeSI{
+		Tables: map[string]*vindexes.Table{
+			"t1": {
+				Name:                    sqlparser.NewIdentifierCS("t1"),
+				Columns:                 []vindexes.Column{column("id", querypb.Type_INT64), column("col", querypb.Type_INT64)},
+				ColumnListAuthoritative: true,
+				ColumnVindexes:          []*vindexes.ColumnVindex{vindexOn("id")},
+				Keyspace:                ks2,
+			},
+			"t2": {
+				Name:                    sqlparser.NewIdentifierCS("t2"),
+				Columns:                 []vindexes.Column{column("uid", querypb.Type_INT64), column("name", querypb.Type_VARCHAR)},
+				ColumnListAuthoritative: true,
+				ColumnVindexes:          []*vindexes.ColumnVindex{vindexOn("uid"), vindexOn("name")},
+				Keyspace:                ks3,
+			},
+			"t": {
+				Name:                    sqlparser.NewIdentifierCS("t"),
+				Columns:                 []vindexes.Column{column("id", querypb.Type_INT64)},
+				ColumnListAuthoritative: true,
+				Keyspace:                unsharded,
+			},
+		},
+	}
+
+	tests := []struct {
+		query string
+		types map[string]string
+	}{
+		{query: "select * from t1 where id = :x", types: map[string]string{"x": "INT64"}},
+		{query: "select * from t1 where :x <= id and id > :y", types: map[string]string{"x": "INT64", "y": "INT64"}},
+		{query: "select * from t1 where id in ::list", types: map[string]string{"list": "INT64"}},
+		{query: "select * from t1 where id not in (:x, :y, 3)", types: map[string]string{"x": "INT64", "y": "INT64"}},
+		{query: "select * from t2 where name = :x and uid <=> :y", types: map[string]string{"x": "VARCHAR", "y": "INT64"}},
+		// compared with columns of different types, the bind variable is left untyped
+		{query: "select * from t2 where name = :x and uid = :x", types: map[string]string{}},
+		{query: "select * from t1 where id + 1 = :x and :y like id", types: map[string]string{}},
+		// the columns that don't route the query leave the bind variables untyped
+		{query: "select * from t1 where col = :x", types: map[string]string{}},
+		{query: "select * from t where id = :x", types: map[string]string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			parse, err := sqlparser.NewTestParser().Parse(test.query)
+			require.NoError(t, err)
+
+			st, err := Analyze(parse, "d", si)
+			require.NoError(t, err)
+
+			types := map[string]string{}
+			for name, typ := range st.BindVarTypes {
+				types[name] = typ.Type().String()
+			}
+			require.Equal(t, test.types, types)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semantics
+
+import (
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// VitessMetadataTable is a table of the vitess_metadata virtual schema. Its
+// rows are produced by vtgate itself, so it never goes to a keyspace.
+type VitessMetadataTable struct {
+	Table TableInfo
+}
+
+var _ TableInfo = (*VitessMetadataTable)(nil)
+
+func createVitessMetadataTable(t sqlparser.TableName, node *sqlparser.AliasedTableExpr, si SchemaInformation, currentDb string) (TableInfo, error) {
+	tbl := vindexes.VitessMetadataTable(t.Name.String())
+	if tbl == nil {
+		return nil, vterrors.VT05004(vindexes.VitessMetadataSchema + "." + t.Name.String())
+	}
+	table, err := createTable(t, node, tbl, false, nil, si, currentDb)
+	if err != nil {
+		return nil, err
+	}
+	return &VitessMetadataTable{Table: table}, nil
+}
+
+// dependencies implements the TableInfo interface
+func (v *VitessMetadataTable) dependencies(colName string, org originable) (dependencies, error) {
+	return v.Table.dependencies(colName, org)
+}
+
+// GetTables implements the TableInfo interface
+func (v *VitessMetadataTable) getTableSet(org originable) TableSet {
+	return v.Table.getTableSet(org)
+}
+
+// GetExprFor implements the TableInfo interface
+func (v *VitessMetadataTable) getExprFor(s string) (sqlparser.Expr, error) {
+	return v.Table.getExprFor(s)
+}
+
+// GetVindexTable implements the TableInfo interface
+func (v *VitessMetadataTable) GetVindexTable() *vindexes.Table {
+	return v.Table.GetVindexTable()
+}
+
+// Matches implements the TableInfo interface
+func (v *VitessMetadataTable) matches(name sqlparser.TableName) bool {
+	return v.Table.matches(name)
+}
+
+// Authoritative implements the TableInfo interface
+func (v *VitessMetadataTable) authoritative() bool {
+	return true
+}
+
+// Name implements the TableInfo interface
+func (v *VitessMetadataTable) Name() (sqlparser.TableName, error) {
+	return v.Table.Name()
+}
+
+// GetExpr implements the TableInfo interface
+func (v *VitessMetadataTable) GetAliasedTableExpr() *sqlparser.AliasedTableExpr {
+	return v.Table.GetAliasedTableExpr()
+}
+
+func (v *VitessMetadataTable) canShortCut() shortCut {
+	return cannotShortCut
+}
+
+// GetColumns implements the TableInfo interface
+func (v *VitessMetadataTable) getColumns() []ColumnInfo {
+	return v.Table.getColumns()
+}
+
+// IsInfSchema implements the TableInfo interface
+func (v *VitessMetadataTable) IsInfSchema() bool {
+	return false
+}
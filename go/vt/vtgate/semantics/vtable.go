@@ -20,6 +20,7 @@ import (
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 )
 
@@ -31,6 +32,10 @@ type vTableInfo struct {
 	cols            []sqlparser.Expr
 	tables          TableSet
 	isAuthoritative bool
+
+	// types, if set, are the types of the columns. The columns of a UNION have
+	// the types aggregated from all its SELECTs, and not the types of the cols.
+	types []evalengine.Type
 }
 
 var _ TableInfo = (*vTableInfo)(nil)
@@ -69,11 +74,20 @@ func (v *vTableInfo) dependenciesInGroupBy(colName string, org originable) (depe
 }
 
 func (v *vTableInfo) createCertainForCol(org originable, i int) *certain {
-	directDeps, recursiveDeps, qt := org.depsForExpr(v.cols[i])
+	directDeps, recursiveDeps, qt := v.depsForCol(org, i)
 	newDeps := createCertain(directDeps, recursiveDeps, qt)
 	return newDeps
 }
 
+// depsForCol returns the dependencies and the type of the column at the offset i
+func (v *vTableInfo) depsForCol(org originable, i int) (direct, recursive TableSet, typ evalengine.Type) {
+	direct, recursive, typ = org.depsForExpr(v.cols[i])
+	if i < len(v.types) {
+		typ = v.types[i]
+	}
+	return
+}
+
 // IsInfSchema implements the TableInfo interface
 func (v *vTableInfo) IsInfSchema() bool {
 	return false
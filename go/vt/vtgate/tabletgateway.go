@@ -19,7 +19,6 @@ package vtgate
 import (
 	"context"
 	"fmt"
-	"math/rand/v2"
 	"runtime/debug"
 	"sort"
 	"sync"
@@ -28,6 +27,7 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"vitess.io/vitess/go/flagutil"
 	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/vt/discovery"
 	"vitess.io/vitess/go/vt/log"
@@ -37,6 +37,8 @@ import (
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/balancer"
+	"vitess.io/vitess/go/vt/vtgate/breaker"
 	"vitess.io/vitess/go/vt/vtgate/buffer"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
 
@@ -54,14 +56,54 @@ var (
 	// retryCount is the number of times a query will be retried on error
 	retryCount = 2
 
+	// tabletSelectionPolicy is the policy used to select the tablet a query is sent to
+	tabletSelectionPolicy = balancer.PolicyRandom
+	// tabletSelectionPolicyByKeyspace overrides tabletSelectionPolicy for specific keyspaces
+	tabletSelectionPolicyByKeyspace flagutil.StringMapValue
+
 	logCollations = logutil.NewThrottledLogger("CollationInconsistent", 1*time.Minute)
+
+	// circuitBreakerEnabled enables the circuit breakers of the tablets
+	circuitBreakerEnabled = false
+	// circuitBreakerConfig is the configuration of the circuit breakers of the tablets
+	circuitBreakerConfig = breaker.Config{
+		Window:          30 * time.Second,
+		MinRequests:     20,
+		ErrorRate:       0.5,
+		TimeoutRate:     0.25,
+		PeerMultiplier:  2,
+		OpenDuration:    5 * time.Second,
+		MaxOpenDuration: time.Minute,
+		CanaryQueries:   3,
+		CanaryTimeout:   time.Second,
+	}
+
+	// maxReplicaLagFallbackToPrimary sends the replica queries to the primary
+	// when none of the replicas is within their maximum replication lag.
+	maxReplicaLagFallbackToPrimary = true
 )
 
+// canaryQuery is the query used to probe a tablet whose circuit breaker is open.
+const canaryQuery = "select 1 from dual"
+
 func init() {
 	servenv.OnParseFor("vtgate", func(fs *pflag.FlagSet) {
 		fs.StringVar(&CellsToWatch, "cells_to_watch", "", "comma-separated list of cells for watching tablets")
 		fs.DurationVar(&initialTabletTimeout, "gateway_initial_tablet_timeout", 30*time.Second, "At startup, the tabletGateway will wait up to this duration to get at least one tablet per keyspace/shard/tablet type")
 		fs.IntVar(&retryCount, "retry-count", 2, "retry count")
+		fs.StringVar(&tabletSelectionPolicy, "tablet-selection-policy", tabletSelectionPolicy, fmt.Sprintf("policy used to select the tablet a query is sent to, among the healthy tablets of its target. One of %v", balancer.Policies))
+		fs.Var(&tabletSelectionPolicyByKeyspace, "tablet-selection-policy-by-keyspace", "comma-separated list of keyspace:policy pairs that override --tablet-selection-policy for the given keyspaces")
+		fs.BoolVar(&circuitBreakerEnabled, "tablet-circuit-breaker", circuitBreakerEnabled, "take the tablets whose error or timeout rate is too high out of rotation until they answer canary queries again")
+		fs.DurationVar(&circuitBreakerConfig.Window, "tablet-circuit-breaker-window", circuitBreakerConfig.Window, "duration over which the error and timeout rates of a tablet are measured by its circuit breaker")
+		fs.IntVar(&circuitBreakerConfig.MinRequests, "tablet-circuit-breaker-min-requests", circuitBreakerConfig.MinRequests, "number of requests a tablet must have received in the window for its circuit breaker to trip")
+		fs.Float64Var(&circuitBreakerConfig.ErrorRate, "tablet-circuit-breaker-error-rate", circuitBreakerConfig.ErrorRate, "error rate at which the circuit breaker of a tablet trips")
+		fs.Float64Var(&circuitBreakerConfig.TimeoutRate, "tablet-circuit-breaker-timeout-rate", circuitBreakerConfig.TimeoutRate, "timeout rate at which the circuit breaker of a tablet trips")
+		fs.Float64Var(&circuitBreakerConfig.PeerMultiplier, "tablet-circuit-breaker-peer-multiplier", circuitBreakerConfig.PeerMultiplier, "raise the error and timeout rate thresholds of a tablet to this many times the rates of the other tablets of its target, so that errors affecting the whole target do not trip the circuit breakers")
+		fs.DurationVar(&circuitBreakerConfig.OpenDuration, "tablet-circuit-breaker-open-duration", circuitBreakerConfig.OpenDuration, "duration a tripped circuit breaker keeps a tablet out of rotation before probing it; doubled every time the probe fails")
+		fs.DurationVar(&circuitBreakerConfig.MaxOpenDuration, "tablet-circuit-breaker-max-open-duration", circuitBreakerConfig.MaxOpenDuration, "maximum duration a tripped circuit breaker keeps a tablet out of rotation before probing it")
+		fs.IntVar(&circuitBreakerConfig.CanaryQueries, "tablet-circuit-breaker-canary-queries", circuitBreakerConfig.CanaryQueries, "number of consecutive canary queries a tablet must answer for its circuit breaker to close")
+		fs.DurationVar(&circuitBreakerConfig.CanaryTimeout, "tablet-circuit-breaker-canary-timeout", circuitBreakerConfig.CanaryTimeout, "timeout of the canary queries sent to the tablets whose circuit breaker is open")
+		fs.BoolVar(&maxReplicaLagFallbackToPrimary, "max-replica-lag-fallback-to-primary", maxReplicaLagFallbackToPrimary, "send the queries that set a maximum replica lag, with the max_replica_lag session variable or the MAX_REPLICA_LAG directive, to the primary when no replica is within that lag, instead of failing them")
 	})
 }
 
@@ -76,6 +118,12 @@ type TabletGateway struct {
 	retryCount           int
 	defaultConnCollation atomic.Uint32
 
+	// balancer selects the tablet a query is sent to.
+	balancer *balancer.Balancer
+
+	// breakers, if enabled, keeps the tablets that fail too often out of rotation.
+	breakers *breaker.Manager
+
 	// mu protects the fields of this group.
 	mu sync.Mutex
 	// statusAggregators is a map indexed by the key
@@ -104,13 +152,21 @@ func NewTabletGateway(ctx context.Context, hc discovery.HealthCheck, serv srvtop
 		}
 		hc = createHealthCheck(ctx, healthCheckRetryDelay, healthCheckTimeout, topoServer, localCell, CellsToWatch)
 	}
+	tabletBalancer, err := balancer.New(localCell, tabletSelectionPolicy, tabletSelectionPolicyByKeyspace)
+	if err != nil {
+		log.Exitf("Unable to create new TabletGateway: %v", err)
+	}
 	gw := &TabletGateway{
 		hc:                hc,
 		srvTopoServer:     serv,
 		localCell:         localCell,
 		retryCount:        retryCount,
+		balancer:          tabletBalancer,
 		statusAggregators: make(map[string]*TabletStatusAggregator),
 	}
+	if circuitBreakerEnabled {
+		gw.breakers = breaker.NewManager(circuitBreakerConfig, probeTablet)
+	}
 	gw.setupBuffering(ctx)
 	gw.QueryService = queryservice.Wrap(nil, gw.withRetry)
 	return gw
@@ -279,6 +335,25 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 		}
 
 		tablets := gw.hc.GetHealthyTabletStats(target)
+		if len(target.TabletTags) > 0 {
+			tablets = filterTabletsByTags(tablets, target.TabletTags)
+		}
+		if target.MaxReplicaLagSeconds > 0 && target.TabletType != topodatapb.TabletType_PRIMARY {
+			tablets = filterTabletsByReplicationLag(tablets, target.MaxReplicaLagSeconds)
+			if len(tablets) == 0 {
+				if !maxReplicaLagFallbackToPrimary {
+					err = vterrors.Errorf(vtrpcpb.Code_UNAVAILABLE, "no healthy tablet available for '%s' within a replication lag of %d seconds", target.String(), target.MaxReplicaLagSeconds)
+					break
+				}
+				target = &querypb.Target{
+					Keyspace:   target.Keyspace,
+					Shard:      target.Shard,
+					TabletType: topodatapb.TabletType_PRIMARY,
+					Cell:       target.Cell,
+				}
+				tablets = gw.hc.GetHealthyTabletStats(target)
+			}
+		}
 		if len(tablets) == 0 {
 			// if we have a keyspace event watcher, check if the reason why our primary is not available is that it's currently being resharded
 			// or if a reparent operation is in progress.
@@ -306,7 +381,10 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 			break
 		}
 
-		gw.shuffleTablets(gw.localCell, tablets)
+		if gw.breakers != nil {
+			tablets = gw.breakers.Filter(target, tablets)
+		}
+		gw.balancer.Order(target, tablets)
 
 		var th *discovery.TabletHealth
 		// skip tablets we tried before
@@ -335,9 +413,19 @@ func (gw *TabletGateway) withRetry(ctx context.Context, target *querypb.Target,
 		gw.updateDefaultConnCollation(tabletLastUsed)
 
 		startTime := time.Now()
+		var done func()
+		if gw.balancer.TracksOutstanding() {
+			done = gw.balancer.Track(tabletLastUsed.Alias)
+		}
 		var canRetry bool
 		canRetry, err = inner(ctx, target, th.Conn)
+		if done != nil {
+			done()
+		}
 		gw.updateStats(target, startTime, err)
+		if gw.breakers != nil {
+			gw.breakers.Record(th, err)
+		}
 		if canRetry {
 			invalidTablets[topoproto.TabletAliasString(tabletLastUsed.Alias)] = true
 			continue
@@ -376,51 +464,45 @@ func (gw *TabletGateway) getStatsAggregator(target *querypb.Target) *TabletStatu
 	return aggr
 }
 
-func (gw *TabletGateway) shuffleTablets(cell string, tablets []*discovery.TabletHealth) {
-	sameCell, diffCell, sameCellMax := 0, 0, -1
-	length := len(tablets)
-
-	// move all same cell tablets to the front, this is O(n)
-	for {
-		sameCellMax = diffCell - 1
-		sameCell = gw.nextTablet(cell, tablets, sameCell, length, true)
-		diffCell = gw.nextTablet(cell, tablets, diffCell, length, false)
-		// either no more diffs or no more same cells should stop the iteration
-		if sameCell < 0 || diffCell < 0 {
-			break
-		}
-
-		if sameCell < diffCell {
-			// fast forward the `sameCell` lookup to `diffCell + 1`, `diffCell` unchanged
-			sameCell = diffCell + 1
-		} else {
-			// sameCell > diffCell, swap needed
-			tablets[sameCell], tablets[diffCell] = tablets[diffCell], tablets[sameCell]
-			sameCell++
-			diffCell++
-		}
+// CircuitBreakerState returns the state of the circuit breaker of the tablet,
+// or an empty string if the circuit breakers are disabled.
+func (gw *TabletGateway) CircuitBreakerState(th *discovery.TabletHealth) string {
+	if gw.breakers == nil {
+		return ""
 	}
+	return gw.breakers.State(th).String()
+}
 
-	// shuffle in same cell tablets
-	for i := sameCellMax; i > 0; i-- {
-		swap := rand.IntN(i + 1)
-		tablets[i], tablets[swap] = tablets[swap], tablets[i]
+// probeTablet sends a canary query to a tablet whose circuit breaker is open.
+func probeTablet(ctx context.Context, th *discovery.TabletHealth) error {
+	if th.Conn == nil {
+		return vterrors.VT14003(th.Tablet)
 	}
+	_, err := th.Conn.Execute(ctx, th.Target, canaryQuery, nil, 0, 0, nil)
+	return err
+}
 
-	// shuffle in diff cell tablets
-	for i, diffCellMin := length-1, sameCellMax+1; i > diffCellMin; i-- {
-		swap := rand.IntN(i-sameCellMax) + diffCellMin
-		tablets[i], tablets[swap] = tablets[swap], tablets[i]
+// filterTabletsByTags returns the tablets that carry all of the given tags.
+func filterTabletsByTags(tablets []*discovery.TabletHealth, tags map[string]string) []*discovery.TabletHealth {
+	filtered := make([]*discovery.TabletHealth, 0, len(tablets))
+	for _, th := range tablets {
+		if discovery.TabletHasTags(th.Tablet, tags) {
+			filtered = append(filtered, th)
+		}
 	}
+	return filtered
 }
 
-func (gw *TabletGateway) nextTablet(cell string, tablets []*discovery.TabletHealth, offset, length int, sameCell bool) int {
-	for ; offset < length; offset++ {
-		if (tablets[offset].Tablet.Alias.Cell == cell) == sameCell {
-			return offset
+// filterTabletsByReplicationLag returns the tablets whose replication lag is
+// at most maxLag seconds.
+func filterTabletsByReplicationLag(tablets []*discovery.TabletHealth, maxLag int64) []*discovery.TabletHealth {
+	filtered := make([]*discovery.TabletHealth, 0, len(tablets))
+	for _, th := range tablets {
+		if th.Stats != nil && int64(th.Stats.ReplicationLagSeconds) <= maxLag {
+			filtered = append(filtered, th)
 		}
 	}
-	return -1
+	return filtered
 }
 
 // TabletsCacheStatus returns a displayable version of the health check cache.
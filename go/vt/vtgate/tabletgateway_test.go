@@ -21,10 +21,12 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/flagutil"
 	"vitess.io/vitess/go/test/utils"
 
 	"vitess.io/vitess/go/sqltypes"
@@ -32,8 +34,10 @@ import (
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
-	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/balancer"
+	"vitess.io/vitess/go/vt/vtgate/breaker"
+	"vitess.io/vitess/go/vt/vttablet/sandboxconn"
 )
 
 func TestTabletGatewayExecute(t *testing.T) {
@@ -90,91 +94,198 @@ func TestTabletGatewayBeginExecute(t *testing.T) {
 	})
 }
 
-func TestTabletGatewayShuffleTablets(t *testing.T) {
+func TestTabletGatewayReplicaTransactionError(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
+	keyspace := "ks"
+	shard := "0"
+	// transactions on REPLICA are not allowed from tabletgateway
+	// they have to be executed directly on tabletserver
+	tabletType := topodatapb.TabletType_REPLICA
+	host := "1.1.1.1"
+	port := int32(1001)
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: tabletType,
+	}
 	hc := discovery.NewFakeHealthCheck(nil)
 	ts := &fakeTopoServer{}
-	tg := NewTabletGateway(ctx, hc, ts, "local")
+	tg := NewTabletGateway(ctx, hc, ts, "cell")
 	defer tg.Close(ctx)
 
-	ts1 := &discovery.TabletHealth{
-		Tablet:  topo.NewTablet(1, "cell1", "host1"),
-		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
-		Serving: true,
-		Stats:   &querypb.RealtimeStats{ReplicationLagSeconds: 1, CpuUsage: 0.2},
-	}
+	_ = hc.AddTestTablet("cell", host, port, keyspace, shard, tabletType, true, 10, nil)
+	_, err := tg.Execute(ctx, target, "query", nil, 1, 0, nil)
+	verifyContainsError(t, err, "query service can only be used for non-transactional queries on replicas", vtrpcpb.Code_INTERNAL)
+}
 
-	ts2 := &discovery.TabletHealth{
-		Tablet:  topo.NewTablet(2, "cell1", "host2"),
-		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
-		Serving: true,
-		Stats:   &querypb.RealtimeStats{ReplicationLagSeconds: 1, CpuUsage: 0.2},
-	}
+func TestTabletGatewayTabletTags(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
 
-	ts3 := &discovery.TabletHealth{
-		Tablet:  topo.NewTablet(3, "cell2", "host3"),
-		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
-		Serving: true,
-		Stats:   &querypb.RealtimeStats{ReplicationLagSeconds: 1, CpuUsage: 0.2},
-	}
+	keyspace := "ks"
+	shard := "0"
+	tabletType := topodatapb.TabletType_REPLICA
+	hc := discovery.NewFakeHealthCheck(nil)
+	ts := &fakeTopoServer{}
+	tg := NewTabletGateway(ctx, hc, ts, "cell")
+	defer tg.Close(ctx)
 
-	ts4 := &discovery.TabletHealth{
-		Tablet:  topo.NewTablet(4, "cell2", "host4"),
-		Target:  &querypb.Target{Keyspace: "k", Shard: "s", TabletType: topodatapb.TabletType_REPLICA},
-		Serving: true,
-		Stats:   &querypb.RealtimeStats{ReplicationLagSeconds: 1, CpuUsage: 0.2},
-	}
+	sbc1 := hc.AddTestTablet("cell", "1.1.1.1", 1001, keyspace, shard, tabletType, true, 10, nil)
+	sbc2 := hc.AddTestTablet("cell", "1.1.1.2", 1001, keyspace, shard, tabletType, true, 10, nil)
+	sbc2.Tablet().Tags = map[string]string{"tag": "analytics"}
 
-	sameCellTablets := []*discovery.TabletHealth{ts1, ts2}
-	diffCellTablets := []*discovery.TabletHealth{ts3, ts4}
-	mixedTablets := []*discovery.TabletHealth{ts1, ts2, ts3, ts4}
-	// repeat shuffling 10 times and every time the same cell tablets should be in the front
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: tabletType,
+		TabletTags: map[string]string{"tag": "analytics"},
+	}
 	for i := 0; i < 10; i++ {
-		tg.shuffleTablets("cell1", sameCellTablets)
-		assert.Len(t, sameCellTablets, 2, "Wrong number of TabletHealth")
-		assert.Equal(t, sameCellTablets[0].Tablet.Alias.Cell, "cell1", "Wrong tablet cell")
-		assert.Equal(t, sameCellTablets[1].Tablet.Alias.Cell, "cell1", "Wrong tablet cell")
+		_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 0, sbc1.ExecCount.Load())
+	assert.EqualValues(t, 10, sbc2.ExecCount.Load())
 
-		tg.shuffleTablets("cell1", diffCellTablets)
-		assert.Len(t, diffCellTablets, 2, "should shuffle in only diff cell tablets")
-		assert.Contains(t, diffCellTablets, ts3, "diffCellTablets should contain %v", ts3)
-		assert.Contains(t, diffCellTablets, ts4, "diffCellTablets should contain %v", ts4)
+	target.TabletTags = map[string]string{"tag": "oltp"}
+	_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+	verifyContainsError(t, err, "no healthy tablet available", vtrpcpb.Code_UNAVAILABLE)
+}
 
-		tg.shuffleTablets("cell1", mixedTablets)
-		assert.Len(t, mixedTablets, 4, "should have 4 tablets, got %+v", mixedTablets)
+func TestTabletGatewayMaxReplicaLag(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
 
-		assert.Contains(t, mixedTablets[0:2], ts1, "should have same cell tablets in the front, got %+v", mixedTablets)
-		assert.Contains(t, mixedTablets[0:2], ts2, "should have same cell tablets in the front, got %+v", mixedTablets)
+	defer func(fallback bool) {
+		maxReplicaLagFallbackToPrimary = fallback
+	}(maxReplicaLagFallbackToPrimary)
 
-		assert.Contains(t, mixedTablets[2:4], ts3, "should have diff cell tablets in the rear, got %+v", mixedTablets)
-		assert.Contains(t, mixedTablets[2:4], ts4, "should have diff cell tablets in the rear, got %+v", mixedTablets)
+	keyspace := "ks"
+	shard := "0"
+	hc := discovery.NewFakeHealthCheck(nil)
+	ts := &fakeTopoServer{}
+	tg := NewTabletGateway(ctx, hc, ts, "cell")
+	defer tg.Close(ctx)
+
+	primary := hc.AddTestTablet("cell", "1.1.1.1", 1001, keyspace, shard, topodatapb.TabletType_PRIMARY, true, 10, nil)
+	replica1 := hc.AddTestTablet("cell", "1.1.1.2", 1001, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	replica2 := hc.AddTestTablet("cell", "1.1.1.3", 1001, keyspace, shard, topodatapb.TabletType_REPLICA, true, 10, nil)
+	setLag := func(sbc *sandboxconn.SandboxConn, lag uint32) {
+		th, err := hc.GetTabletHealthByAlias(sbc.Tablet().Alias)
+		require.NoError(t, err)
+		th.Stats.ReplicationLagSeconds = lag
+	}
+	setLag(replica1, 5)
+	setLag(replica2, 30)
+
+	target := &querypb.Target{
+		Keyspace:             keyspace,
+		Shard:                shard,
+		TabletType:           topodatapb.TabletType_REPLICA,
+		MaxReplicaLagSeconds: 10,
 	}
+	for i := 0; i < 10; i++ {
+		_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 10, replica1.ExecCount.Load())
+	assert.EqualValues(t, 0, replica2.ExecCount.Load())
+	assert.EqualValues(t, 0, primary.ExecCount.Load())
+
+	// no replica is within the lag: the query falls back to the primary.
+	setLag(replica1, 20)
+	_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, primary.ExecCount.Load())
+	assert.EqualValues(t, topodatapb.TabletType_REPLICA, target.TabletType, "the target of the caller must not change")
+
+	maxReplicaLagFallbackToPrimary = false
+	_, err = tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+	verifyContainsError(t, err, "within a replication lag of 10 seconds", vtrpcpb.Code_UNAVAILABLE)
+	assert.EqualValues(t, 1, primary.ExecCount.Load())
+
+	// without a maximum lag, any replica can serve the query.
+	target.MaxReplicaLagSeconds = 0
+	_, err = tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, primary.ExecCount.Load())
 }
 
-func TestTabletGatewayReplicaTransactionError(t *testing.T) {
+func TestTabletGatewayTabletSelectionPolicy(t *testing.T) {
 	ctx := utils.LeakCheckContext(t)
 
+	defer func(policies flagutil.StringMapValue) {
+		tabletSelectionPolicyByKeyspace = policies
+	}(tabletSelectionPolicyByKeyspace)
+	tabletSelectionPolicyByKeyspace = flagutil.StringMapValue{"ks": balancer.PolicyWeightedRoundRobin}
+
 	keyspace := "ks"
 	shard := "0"
-	// transactions on REPLICA are not allowed from tabletgateway
-	// they have to be executed directly on tabletserver
 	tabletType := topodatapb.TabletType_REPLICA
-	host := "1.1.1.1"
-	port := int32(1001)
+	hc := discovery.NewFakeHealthCheck(nil)
+	ts := &fakeTopoServer{}
+	tg := NewTabletGateway(ctx, hc, ts, "cell")
+	defer tg.Close(ctx)
+
+	sbc1 := hc.AddTestTablet("cell", "1.1.1.1", 1001, keyspace, shard, tabletType, true, 10, nil)
+	sbc1.Tablet().Tags = map[string]string{balancer.WeightTag: "3"}
+	sbc2 := hc.AddTestTablet("cell", "1.1.1.2", 1001, keyspace, shard, tabletType, true, 10, nil)
+	sbc3 := hc.AddTestTablet("other", "1.1.1.3", 1001, keyspace, shard, tabletType, true, 10, nil)
+
 	target := &querypb.Target{
 		Keyspace:   keyspace,
 		Shard:      shard,
 		TabletType: tabletType,
 	}
+	for i := 0; i < 8; i++ {
+		_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 6, sbc1.ExecCount.Load())
+	assert.EqualValues(t, 2, sbc2.ExecCount.Load())
+	assert.EqualValues(t, 0, sbc3.ExecCount.Load())
+}
+
+func TestTabletGatewayCircuitBreaker(t *testing.T) {
+	ctx := utils.LeakCheckContext(t)
+
+	defer func(enabled bool, cfg breaker.Config) {
+		circuitBreakerEnabled = enabled
+		circuitBreakerConfig = cfg
+	}(circuitBreakerEnabled, circuitBreakerConfig)
+	circuitBreakerEnabled = true
+	circuitBreakerConfig.MinRequests = 5
+	circuitBreakerConfig.OpenDuration = time.Hour
+
+	keyspace := "ks"
+	shard := "0"
+	tabletType := topodatapb.TabletType_REPLICA
 	hc := discovery.NewFakeHealthCheck(nil)
 	ts := &fakeTopoServer{}
 	tg := NewTabletGateway(ctx, hc, ts, "cell")
 	defer tg.Close(ctx)
 
-	_ = hc.AddTestTablet("cell", host, port, keyspace, shard, tabletType, true, 10, nil)
-	_, err := tg.Execute(ctx, target, "query", nil, 1, 0, nil)
-	verifyContainsError(t, err, "query service can only be used for non-transactional queries on replicas", vtrpcpb.Code_INTERNAL)
+	sbc1 := hc.AddTestTablet("cell", "1.1.1.1", 1001, keyspace, shard, tabletType, true, 10, nil)
+	sbc1.MustFailCodes[vtrpcpb.Code_UNAVAILABLE] = 1000
+	sbc2 := hc.AddTestTablet("cell", "1.1.1.2", 1001, keyspace, shard, tabletType, true, 10, nil)
+
+	target := &querypb.Target{
+		Keyspace:   keyspace,
+		Shard:      shard,
+		TabletType: tabletType,
+	}
+	for i := 0; i < 100; i++ {
+		_, err := tg.Execute(ctx, target, "query", nil, 0, 0, nil)
+		require.NoError(t, err)
+	}
+	// The circuit breaker of the failing tablet trips after its fifth request.
+	assert.EqualValues(t, 5, sbc1.ExecCount.Load())
+	assert.EqualValues(t, 100, sbc2.ExecCount.Load())
+
+	states := make(map[string]string)
+	for _, th := range hc.GetHealthyTabletStats(target) {
+		states[th.Tablet.Hostname] = tg.CircuitBreakerState(th)
+	}
+	assert.Equal(t, map[string]string{"1.1.1.1": "OPEN", "1.1.1.2": "CLOSED"}, states)
 }
 
 func testTabletGatewayGeneric(t *testing.T, ctx context.Context, f func(ctx context.Context, tg *TabletGateway, target *querypb.Target) error) {
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
+	"math/rand/v2"
 	"sort"
 	"strings"
 	"sync/atomic"
@@ -50,6 +52,7 @@ import (
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/buffer"
 	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/evalengine"
 	"vitess.io/vitess/go/vt/vtgate/logstats"
 	"vitess.io/vitess/go/vt/vtgate/planbuilder/plancontext"
 	"vitess.io/vitess/go/vt/vtgate/semantics"
@@ -77,8 +80,11 @@ type iExecute interface {
 	showVitessReplicationStatus(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
 	showShards(ctx context.Context, filter *sqlparser.ShowFilter, destTabletType topodatapb.TabletType) (*sqltypes.Result, error)
 	showTablets(filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
+	showPlans(filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
 	showVitessMetadata(ctx context.Context, filter *sqlparser.ShowFilter) (*sqltypes.Result, error)
+	vitessMetadataRows(ctx context.Context, table string, destTabletType topodatapb.TabletType) ([][]sqltypes.Value, error)
 	setVitessMetadata(ctx context.Context, name, value string) error
+	ClearPlans()
 
 	// TODO: remove when resolver is gone
 	ParseDestinationTarget(targetString string) (string, topodatapb.TabletType, key.Destination, error)
@@ -100,6 +106,7 @@ type vcursorImpl struct {
 	safeSession    *SafeSession
 	keyspace       string
 	tabletType     topodatapb.TabletType
+	tabletTags     map[string]string
 	destination    key.Destination
 	marginComments sqlparser.MarginComments
 	executor       iExecute
@@ -114,16 +121,27 @@ type vcursorImpl struct {
 	// A nil value represents that no foreign_key_checks value was provided.
 	fkChecksState       *bool
 	ignoreMaxMemoryRows bool
+	maxReplicaLag       int64 // maximum replication lag in seconds of the replicas serving the query, 0 if unbounded
 	vschema             *vindexes.VSchema
 	vm                  VSchemaOperator
 	semTable            *semantics.SemTable
 	warnShardedOnly     bool // when using sharded only features, a warning will be warnings field
 
-	warnings []*querypb.QueryWarning // any warnings that are accumulated during the planning phase are stored here
-	pv       plancontext.PlannerVersion
+	warnings     []*querypb.QueryWarning    // any warnings that are accumulated during the planning phase are stored here
+	bindVarTypes map[string]evalengine.Type // the types of the vindex columns the bind variables are compared with, recorded during planning
+	pv           plancontext.PlannerVersion
+
+	// positions are the positions of the expressions of the query being planned, in the query text
+	positions sqlparser.Positions
 
 	warmingReadsPercent int
 	warmingReadsChannel chan bool
+
+	sequences *engine.SequenceCache
+
+	// routingState routes the tables of the routing rules that move them to
+	// another keyspace.
+	routingState vindexes.RoutingState
 }
 
 // newVcursorImpl creates a vcursorImpl. Before creating this object, you have to separate out any marginComments that came with
@@ -142,10 +160,23 @@ func newVCursorImpl(
 	warnShardedOnly bool,
 	pv plancontext.PlannerVersion,
 ) (*vcursorImpl, error) {
-	keyspace, tabletType, destination, err := parseDestinationTarget(safeSession.TargetString, vschema)
+	targetString := tenantTargetString(safeSession, vschema)
+	keyspace, tabletType, destination, err := parseDestinationTarget(targetString, vschema)
+	if err != nil {
+		return nil, err
+	}
+	if readOnlyTxOnReplica(safeSession, tabletType) {
+		tabletType = topodatapb.TabletType_REPLICA
+	}
+	tabletTags, err := topoprotopb.ParseTabletTags(targetString)
 	if err != nil {
 		return nil, err
 	}
+	if tabletTags == nil {
+		if tabletTags, err = topoprotopb.ParseTags(safeSession.GetTabletTags()); err != nil {
+			return nil, err
+		}
+	}
 
 	var ts *topo.Server
 	// We don't have access to the underlying TopoServer if this vtgate is
@@ -170,14 +201,17 @@ func newVCursorImpl(
 
 	warmingReadsPct := 0
 	var warmingReadsChan chan bool
+	var sequences *engine.SequenceCache
 	if executor != nil {
 		warmingReadsPct = executor.warmingReadsPercent
 		warmingReadsChan = executor.warmingReadsChannel
+		sequences = executor.sequences
 	}
 	return &vcursorImpl{
 		safeSession:         safeSession,
 		keyspace:            keyspace,
 		tabletType:          tabletType,
+		tabletTags:          tabletTags,
 		destination:         destination,
 		marginComments:      marginComments,
 		executor:            executor,
@@ -191,6 +225,8 @@ func newVCursorImpl(
 		pv:                  pv,
 		warmingReadsPercent: warmingReadsPct,
 		warmingReadsChannel: warmingReadsChan,
+		sequences:           sequences,
+		routingState:        vindexes.NewRoutingState(),
 	}, nil
 }
 
@@ -287,7 +323,7 @@ func (vc *vcursorImpl) FindRoutedTable(name sqlparser.TableName) (*vindexes.Tabl
 		destKeyspace = vc.keyspace
 	}
 
-	table, err := vc.vschema.FindRoutedTable(destKeyspace, name.Name.String(), destTabletType)
+	table, err := vc.vschema.FindRoutedTableWithState(destKeyspace, name.Name.String(), destTabletType, vc.routingState)
 	if err != nil {
 		return nil, err
 	}
@@ -310,7 +346,7 @@ func (vc *vcursorImpl) FindTableOrVindex(name sqlparser.TableName) (*vindexes.Ta
 	if destKeyspace == "" {
 		destKeyspace = vc.getActualKeyspace()
 	}
-	table, vindex, err := vc.vschema.FindTableOrVindex(destKeyspace, name.Name.String(), vc.tabletType)
+	table, vindex, err := vc.vschema.FindTableOrVindexWithState(destKeyspace, name.Name.String(), vc.tabletType, vc.routingState)
 	if err != nil {
 		return nil, nil, "", destTabletType, nil, err
 	}
@@ -704,6 +740,7 @@ func (vc *vcursorImpl) ResolveDestinations(ctx context.Context, keyspace string,
 	if err != nil {
 		return nil, nil, err
 	}
+	vc.setTargetFilters(rss)
 	if enableShardRouting {
 		rss, err = vc.fixupPartiallyMovedShards(rss)
 		if err != nil {
@@ -718,6 +755,7 @@ func (vc *vcursorImpl) ResolveDestinationsMultiCol(ctx context.Context, keyspace
 	if err != nil {
 		return nil, nil, err
 	}
+	vc.setTargetFilters(rss)
 	if enableShardRouting {
 		rss, err = vc.fixupPartiallyMovedShards(rss)
 		if err != nil {
@@ -727,6 +765,23 @@ func (vc *vcursorImpl) ResolveDestinationsMultiCol(ctx context.Context, keyspace
 	return rss, values, err
 }
 
+// setTargetFilters restricts the resolved shards to tablets that carry the
+// tablet tags of the session's target, if any, and, for replica and rdonly
+// targets, to tablets that lag at most maxReplicaLag seconds. The replication
+// lag is not checked in transactions, whose queries must all be served by the
+// tablet that began them.
+func (vc *vcursorImpl) setTargetFilters(rss []*srvtopo.ResolvedShard) {
+	checkLag := vc.maxReplicaLag > 0 && !vc.safeSession.InTransaction()
+	for _, rs := range rss {
+		if len(vc.tabletTags) > 0 {
+			rs.Target.TabletTags = vc.tabletTags
+		}
+		if checkLag && rs.Target.TabletType != topodatapb.TabletType_PRIMARY {
+			rs.Target.MaxReplicaLagSeconds = vc.maxReplicaLag
+		}
+	}
+}
+
 func (vc *vcursorImpl) Session() engine.SessionActions {
 	return vc
 }
@@ -747,6 +802,49 @@ func (vc *vcursorImpl) SetTarget(target string) error {
 	return nil
 }
 
+// readOnlyTxOnReplica returns true if the queries of the session must be sent
+// to the replicas because it is in a read-only transaction and it asked for
+// those to run on the replicas. A tablet type given in the target of the
+// session always wins.
+func readOnlyTxOnReplica(safeSession *SafeSession, tabletType topodatapb.TabletType) bool {
+	if tabletType != topodatapb.TabletType_PRIMARY || strings.Contains(safeSession.TargetString, "@") {
+		return false
+	}
+	return safeSession.GetReadOnlyTxOnReplica() && safeSession.InReadOnlyTransaction()
+}
+
+// tenantTargetString returns the target of the queries of the session. When
+// the session has a tenant, the keyspace and the shard or key range come from
+// the tenant routing rule of that tenant, and the tablet type and the tablet
+// tags from the target of the session.
+func tenantTargetString(safeSession *SafeSession, vschema *vindexes.VSchema) string {
+	target, ok := vschema.TenantRoutingRules[safeSession.GetTenantID()]
+	if !ok {
+		return safeSession.TargetString
+	}
+	if last := strings.LastIndex(safeSession.TargetString, "@"); last != -1 {
+		target += safeSession.TargetString[last:]
+	}
+	return target
+}
+
+// checkTenantRoutingRule returns an error if the tenant of the session has no
+// routing rule, e.g. because it was removed after the tenant was set. The
+// session can then only run SET statements, to choose another tenant.
+func checkTenantRoutingRule(safeSession *SafeSession, vschema *vindexes.VSchema, stmt sqlparser.Statement) error {
+	tenantID := safeSession.GetTenantID()
+	if tenantID == "" {
+		return nil
+	}
+	if _, ok := vschema.TenantRoutingRules[tenantID]; ok {
+		return nil
+	}
+	if _, isSet := stmt.(*sqlparser.Set); isSet {
+		return nil
+	}
+	return vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "no tenant routing rule for tenant '%s'", tenantID)
+}
+
 func ignoreKeyspace(keyspace string) bool {
 	return keyspace == "" || sqlparser.SystemSchema(keyspace)
 }
@@ -848,16 +946,71 @@ func (vc *vcursorImpl) SetQueryTimeout(maxExecutionTime int64) {
 // The priority of adding query timeouts -
 // 1. Query timeout comment directive.
 // 2. If the comment directive is unspecified, then we use the session setting.
-// 3. If the comment directive and session settings is unspecified, then we use the global default specified by a flag.
-func (vc *vcursorImpl) GetQueryTimeout(queryTimeoutFromComments int) int {
+// 3. If the session setting is unspecified as well, then we use the query_timeout_ms setting of the keyspace in the vschema.
+// 4. If none of them is specified, then we use the global default specified by a flag.
+func (vc *vcursorImpl) GetQueryTimeout(keyspace string, queryTimeoutFromComments int) (int, engine.QueryTimeoutSource) {
 	if queryTimeoutFromComments != 0 {
-		return queryTimeoutFromComments
+		return queryTimeoutFromComments, engine.QueryTimeoutFromComment
 	}
 	sessionQueryTimeout := int(vc.safeSession.GetQueryTimeout())
 	if sessionQueryTimeout != 0 {
-		return sessionQueryTimeout
+		return sessionQueryTimeout, engine.QueryTimeoutFromSession
+	}
+	if ks := vc.vschema.Keyspaces[keyspace]; ks != nil && ks.QueryTimeout != 0 {
+		return ks.QueryTimeout, engine.QueryTimeoutFromKeyspace
+	}
+	return queryTimeout, engine.QueryTimeoutFromFlag
+}
+
+// SetStreamChunkRows implements the SessionActions interface
+func (vc *vcursorImpl) SetStreamChunkRows(rows int64) {
+	vc.safeSession.SetStreamChunkRows(rows)
+}
+
+// SetStreamChunkTimeout implements the SessionActions interface
+func (vc *vcursorImpl) SetStreamChunkTimeout(timeout int64) {
+	vc.safeSession.SetStreamChunkTimeout(timeout)
+}
+
+// SetMaxReplicaLag implements the SessionActions interface
+func (vc *vcursorImpl) SetMaxReplicaLag(lag int64) {
+	vc.safeSession.SetMaxReplicaLag(lag)
+}
+
+// SetTabletTags implements the SessionActions interface
+func (vc *vcursorImpl) SetTabletTags(tags string) {
+	vc.safeSession.SetTabletTags(tags)
+}
+
+// SetMaxReplicaLagFromComments sets the maximum replication lag of the
+// replicas that serve the query. The value of the comment directive takes
+// precedence over the one of the session.
+func (vc *vcursorImpl) SetMaxReplicaLagFromComments(lagFromComments int64) {
+	vc.maxReplicaLag = lagFromComments
+	if vc.maxReplicaLag == 0 {
+		vc.maxReplicaLag = vc.safeSession.GetMaxReplicaLag()
+	}
+}
+
+// SetStreamChunkOptions sets the stream chunk options that are sent to the
+// tablets with the query. The values of the comment directives take
+// precedence over the ones of the session.
+func (vc *vcursorImpl) SetStreamChunkOptions(rowsFromComments, timeoutFromComments int64) {
+	rows, timeout := rowsFromComments, timeoutFromComments
+	if rows == 0 {
+		rows = vc.safeSession.GetStreamChunkRows()
+	}
+	if timeout == 0 {
+		timeout = vc.safeSession.GetStreamChunkTimeout()
+	}
+	// Avoid creating session Options when they do not yet exist and the
+	// stream chunk options are unspecified.
+	if rows == 0 && timeout == 0 && vc.safeSession.GetOptions() == nil {
+		return
 	}
-	return queryTimeout
+	options := vc.safeSession.GetOrCreateOptions()
+	options.StreamChunkRows = rows
+	options.StreamChunkTimeoutMs = timeout
 }
 
 // SetClientFoundRows implements the SessionActions interface
@@ -920,8 +1073,7 @@ func (vc *vcursorImpl) SetWorkloadName(workloadName string) {
 
 // SetFoundRows implements the SessionActions interface
 func (vc *vcursorImpl) SetFoundRows(foundRows uint64) {
-	vc.safeSession.FoundRows = foundRows
-	vc.safeSession.foundRowsHandled = true
+	vc.safeSession.SetFoundRows(foundRows)
 }
 
 // SetDDLStrategy implements the SessionActions interface
@@ -960,6 +1112,39 @@ func (vc *vcursorImpl) GetSessionEnableSystemSettings() bool {
 	return vc.safeSession.GetSessionEnableSystemSettings()
 }
 
+// SetSnapshotReads implements the SessionActions interface
+func (vc *vcursorImpl) SetSnapshotReads(_ context.Context, enable bool) error {
+	vc.safeSession.SetSnapshotReads(enable)
+	return nil
+}
+
+// SetSkipReadRetry implements the SessionActions interface
+func (vc *vcursorImpl) SetSkipReadRetry(_ context.Context, skip bool) error {
+	vc.safeSession.SetSkipReadRetry(skip)
+	return nil
+}
+
+// SetReadOnlyTxOnReplica implements the SessionActions interface
+func (vc *vcursorImpl) SetReadOnlyTxOnReplica(_ context.Context, enable bool) error {
+	if vc.safeSession.InTransaction() {
+		return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.LockOrActiveTransaction, "can't execute the given command because you have an active transaction")
+	}
+	vc.safeSession.SetReadOnlyTxOnReplica(enable)
+	return nil
+}
+
+// SetTenantID implements the SessionActions interface
+func (vc *vcursorImpl) SetTenantID(tenantID string) error {
+	if vc.safeSession.InTransaction() {
+		return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.LockOrActiveTransaction, "can't execute the given command because you have an active transaction")
+	}
+	if _, ok := vc.vschema.TenantRoutingRules[tenantID]; tenantID != "" && !ok {
+		return vterrors.NewErrorf(vtrpcpb.Code_INVALID_ARGUMENT, vterrors.WrongValueForVar, "unknown tenant: %s", tenantID)
+	}
+	vc.safeSession.SetTenantID(tenantID)
+	return nil
+}
+
 // SetReadAfterWriteGTID implements the SessionActions interface
 func (vc *vcursorImpl) SetReadAfterWriteGTID(vtgtid string) {
 	vc.safeSession.SetReadAfterWriteGTID(vtgtid)
@@ -980,6 +1165,11 @@ func (vc *vcursorImpl) HasCreatedTempTable() {
 	vc.safeSession.GetOrCreateOptions().HasCreatedTempTables = true
 }
 
+// HasUntrackedReservedConnState implements the SessionActions interface
+func (vc *vcursorImpl) HasUntrackedReservedConnState() {
+	vc.safeSession.SetUntrackedReservedConnState()
+}
+
 // GetWarnings implements the SessionActions interface
 func (vc *vcursorImpl) GetWarnings() []*querypb.QueryWarning {
 	return vc.safeSession.GetWarnings()
@@ -1055,6 +1245,27 @@ func (vc *vcursorImpl) PlannerWarning(message string) {
 	})
 }
 
+// PlannerBindVarTypes implements the VCursor interface
+func (vc *vcursorImpl) PlannerBindVarTypes(types map[string]evalengine.Type) {
+	if len(types) == 0 {
+		return
+	}
+	if vc.bindVarTypes == nil {
+		vc.bindVarTypes = make(map[string]evalengine.Type, len(types))
+	}
+	maps.Copy(vc.bindVarTypes, types)
+}
+
+// QueryPositions implements the VCursor interface
+func (vc *vcursorImpl) QueryPositions() sqlparser.Positions {
+	return vc.positions
+}
+
+// SetQueryPositions sets the positions of the expressions of the query in the query text
+func (vc *vcursorImpl) SetQueryPositions(positions sqlparser.Positions) {
+	vc.positions = positions
+}
+
 // ForeignKeyMode implements the VCursor interface
 func (vc *vcursorImpl) ForeignKeyMode(keyspace string) (vschemapb.Keyspace_ForeignKeyMode, error) {
 	if strings.ToLower(foreignKeyMode) == "disallow" {
@@ -1117,10 +1328,78 @@ func (vc *vcursorImpl) keyForPlan(ctx context.Context, query string, buf io.Stri
 			_, _ = buf.WriteString(vc.destination.String())
 		}
 	}
+	if routingKey := vc.vschema.RoutingKey(vc.routingState); routingKey != "" {
+		_, _ = buf.WriteString("+Routing:")
+		_, _ = buf.WriteString(routingKey)
+	}
 	_, _ = buf.WriteString("+Query:")
 	_, _ = buf.WriteString(query)
 }
 
+// splitReads draws the bucket that splits the reads of the tables that the
+// routing rules move to another keyspace, when stmt is a read outside of a
+// transaction. Reads in a transaction must see its writes, which are not
+// split.
+func (vc *vcursorImpl) splitReads(stmt sqlparser.Statement) {
+	if !vc.vschema.HasTargetRoutingRules() || vc.safeSession.InTransaction() {
+		return
+	}
+	if sqlparser.ASTToStatementType(stmt) != sqlparser.StmtSelect {
+		return
+	}
+	vc.routingState.ReadBucket = rand.IntN(100)
+}
+
+// splitReadWrite sends stmt to the replicas instead of the primary when
+// read/write splitting is enabled and stmt is a plain SELECT that the session
+// runs in autocommit. On the shards that the session wrote to, the replica
+// that serves it first waits to have executed the last write of the session,
+// and the read falls back to the primary when it doesn't in time.
+func (vc *vcursorImpl) splitReadWrite(stmt sqlparser.Statement) {
+	session := vc.safeSession
+	session.SetSplitRead(false)
+	if !readWriteSplitting {
+		return
+	}
+	if vc.tabletType != topodatapb.TabletType_PRIMARY || strings.Contains(session.TargetString, "@") {
+		return
+	}
+	if !session.Autocommit || session.InTransaction() || session.InReservedConn() || session.InLockSession() || session.HasAdvisoryLock() {
+		return
+	}
+	if !isSplittableRead(stmt) || sqlparser.ReadFromPrimaryDirective(stmt) {
+		return
+	}
+	vc.tabletType = topodatapb.TabletType_REPLICA
+	session.SetSplitRead(true)
+	readWriteSplittingReplicaReads.Add(1)
+}
+
+// isSplittableRead returns true if stmt is a SELECT that neither takes locks
+// nor consumes values of a sequence, and can thus be served by a replica.
+func isSplittableRead(stmt sqlparser.Statement) bool {
+	sel, ok := stmt.(sqlparser.SelectStatement)
+	if !ok {
+		return false
+	}
+	if sel.GetLock() != sqlparser.NoLock {
+		return false
+	}
+	splittable := true
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch node := node.(type) {
+		case *sqlparser.Select:
+			if node.Into != nil || node.Lock != sqlparser.NoLock {
+				splittable = false
+			}
+		case *sqlparser.Nextval, *sqlparser.LockingFunc:
+			splittable = false
+		}
+		return splittable, nil
+	}, sel)
+	return splittable
+}
+
 func (vc *vcursorImpl) GetKeyspace() string {
 	return vc.keyspace
 }
@@ -1180,6 +1459,8 @@ func (vc *vcursorImpl) ShowExec(ctx context.Context, command sqlparser.ShowComma
 		return vc.executor.showShards(ctx, filter, vc.tabletType)
 	case sqlparser.VitessTablets:
 		return vc.executor.showTablets(filter)
+	case sqlparser.VitessPlans:
+		return vc.executor.showPlans(filter)
 	case sqlparser.VitessVariables:
 		return vc.executor.showVitessMetadata(ctx, filter)
 	default:
@@ -1187,6 +1468,11 @@ func (vc *vcursorImpl) ShowExec(ctx context.Context, command sqlparser.ShowComma
 	}
 }
 
+// VitessMetadataRows implements the VCursor interface
+func (vc *vcursorImpl) VitessMetadataRows(ctx context.Context, table string) ([][]sqltypes.Value, error) {
+	return vc.executor.vitessMetadataRows(ctx, table, vc.tabletType)
+}
+
 func (vc *vcursorImpl) GetVSchema() *vindexes.VSchema {
 	return vc.vschema
 }
@@ -1199,6 +1485,16 @@ func (vc *vcursorImpl) SetExec(ctx context.Context, name string, value string) e
 	return vc.executor.setVitessMetadata(ctx, name, value)
 }
 
+// ClearPlans implements the VCursor interface
+func (vc *vcursorImpl) ClearPlans(ctx context.Context) error {
+	user := callerid.ImmediateCallerIDFromContext(ctx)
+	if !vschemaacl.Authorized(user) {
+		return vterrors.NewErrorf(vtrpcpb.Code_PERMISSION_DENIED, vterrors.AccessDeniedError, "User '%s' not authorized to reset vitess plans", user.GetUsername())
+	}
+	vc.executor.ClearPlans()
+	return nil
+}
+
 func (vc *vcursorImpl) ThrottleApp(ctx context.Context, throttledAppRule *topodatapb.ThrottledAppRule) (err error) {
 	if throttledAppRule == nil {
 		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "ThrottleApp: nil rule")
@@ -1320,6 +1616,21 @@ func (vc *vcursorImpl) GetWarmingReadsChannel() chan bool {
 	return vc.warmingReadsChannel
 }
 
+// SequenceCache is part of the engine.VCursor interface.
+func (vc *vcursorImpl) SequenceCache() *engine.SequenceCache {
+	return vc.sequences
+}
+
+// LoadDataBatchSize implements the VCursor interface
+func (vc *vcursorImpl) LoadDataBatchSize() int {
+	return loadDataBatchSize
+}
+
+// InsertBatchRows implements the VCursor interface
+func (vc *vcursorImpl) InsertBatchRows() int {
+	return insertBatchRows
+}
+
 func (vc *vcursorImpl) CloneForReplicaWarming(ctx context.Context) engine.VCursor {
 	callerId := callerid.EffectiveCallerIDFromContext(ctx)
 	immediateCallerId := callerid.ImmediateCallerIDFromContext(ctx)
@@ -14,6 +14,7 @@ import (
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/vtgate/engine"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -326,3 +327,80 @@ func TestFirstSortedKeyspace(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, ks3Schema.Keyspace, ks)
 }
+
+func TestGetQueryTimeout(t *testing.T) {
+	defer func(timeout int) {
+		queryTimeout = timeout
+	}(queryTimeout)
+	queryTimeout = 1000
+
+	ks1Schema := &vindexes.KeyspaceSchema{Keyspace: &vindexes.Keyspace{Name: "ks1"}, QueryTimeout: 500}
+	ks2Schema := &vindexes.KeyspaceSchema{Keyspace: &vindexes.Keyspace{Name: "ks2"}}
+	vschema := &vindexes.VSchema{
+		Keyspaces: map[string]*vindexes.KeyspaceSchema{
+			ks1Schema.Keyspace.Name: ks1Schema,
+			ks2Schema.Keyspace.Name: ks2Schema,
+		}}
+
+	r, _, _, _, _ := createExecutorEnv(t)
+	session := NewSafeSession(nil)
+	vc, err := newVCursorImpl(session, sqlparser.MarginComments{}, r, nil, &fakeVSchemaOperator{vschema: vschema}, vschema, srvtopo.NewResolver(&fakeTopoServer{}, nil, ""), nil, false, querypb.ExecuteOptions_Gen4)
+	require.NoError(t, err)
+
+	assertTimeout := func(keyspace string, fromComment int, want int, wantSource engine.QueryTimeoutSource) {
+		t.Helper()
+		timeout, source := vc.GetQueryTimeout(keyspace, fromComment)
+		require.Equal(t, want, timeout)
+		require.Equal(t, wantSource, source)
+	}
+	assertTimeout("ks2", 0, 1000, engine.QueryTimeoutFromFlag)
+	assertTimeout("", 0, 1000, engine.QueryTimeoutFromFlag)
+	assertTimeout("ks1", 0, 500, engine.QueryTimeoutFromKeyspace)
+	session.SetQueryTimeout(200)
+	assertTimeout("ks1", 0, 200, engine.QueryTimeoutFromSession)
+	assertTimeout("ks1", 100, 100, engine.QueryTimeoutFromComment)
+}
+
+func TestTabletTags(t *testing.T) {
+	ks1Schema := &vindexes.KeyspaceSchema{Keyspace: &vindexes.Keyspace{Name: "ks1"}}
+	vschema := &vindexes.VSchema{
+		Keyspaces: map[string]*vindexes.KeyspaceSchema{
+			ks1Schema.Keyspace.Name: ks1Schema,
+		}}
+	r, _, _, _, _ := createExecutorEnv(t)
+
+	tests := []struct {
+		name         string
+		targetString string
+		tabletTags   string
+		want         map[string]string
+	}{{
+		name:         "no tags",
+		targetString: "ks1@replica",
+	}, {
+		name:         "session tags",
+		targetString: "ks1@replica",
+		tabletTags:   "region=us-east, tier=analytics",
+		want:         map[string]string{"region": "us-east", "tier": "analytics"},
+	}, {
+		name:         "target string tags",
+		targetString: "ks1@replica{tier=oltp}",
+		want:         map[string]string{"tier": "oltp"},
+	}, {
+		name:         "target string tags take precedence over session tags",
+		targetString: "ks1@replica{tier=oltp}",
+		tabletTags:   "region=us-east,tier=analytics",
+		want:         map[string]string{"tier": "oltp"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := NewSafeSession(&vtgatepb.Session{TargetString: tt.targetString, TabletTags: tt.tabletTags})
+			vc, err := newVCursorImpl(session, sqlparser.MarginComments{}, r, nil, &fakeVSchemaOperator{vschema: vschema}, vschema, srvtopo.NewResolver(&fakeTopoServer{}, nil, ""), nil, false, querypb.ExecuteOptions_Gen4)
+			require.NoError(t, err)
+
+			rss := []*srvtopo.ResolvedShard{{Target: &querypb.Target{Keyspace: "ks1", Shard: "0", TabletType: topodatapb.TabletType_REPLICA}}}
+			vc.setTargetFilters(rss)
+			require.Equal(t, tt.want, rss[0].Target.TabletTags)
+		})
+	}
+}
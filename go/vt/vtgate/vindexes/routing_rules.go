@@ -0,0 +1,122 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"strings"
+	"time"
+
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// RoutingState holds what the routing rules with a target table need to
+// route the tables of a query.
+type RoutingState struct {
+	// Now is when the query is planned, which is compared to the cutover
+	// times of the rules.
+	Now time.Time
+	// ReadBucket is a number in [0, 100) that is drawn for a read that can
+	// be split between the tables of a rule and its target, or -1.
+	ReadBucket int
+}
+
+// NewRoutingState returns the RoutingState of a query that is planned now,
+// and whose reads are not split.
+func NewRoutingState() RoutingState {
+	return RoutingState{Now: time.Now(), ReadBucket: -1}
+}
+
+// route returns the table that the rule routes to. A rule without a target
+// routes to its first table. A rule with a target routes to it from its
+// cutover time on, and before that routes the reads whose bucket falls in its
+// target read percentage to it.
+func (rr *RoutingRule) route(state RoutingState) *Table {
+	if rr.routesToTarget(state) {
+		return rr.Target
+	}
+	return rr.Tables[0]
+}
+
+func (rr *RoutingRule) routesToTarget(state RoutingState) bool {
+	if rr.Target == nil {
+		return false
+	}
+	if !rr.CutoverTime.IsZero() && !state.Now.Before(rr.CutoverTime) {
+		return true
+	}
+	return state.ReadBucket >= 0 && state.ReadBucket < rr.TargetReadPercent
+}
+
+// HasTargetRoutingRules tells whether any routing rule moves its table to a
+// target table, in which case the reads can be split between them.
+func (vschema *VSchema) HasTargetRoutingRules() bool {
+	return vschema != nil && len(vschema.targetRoutingRules) > 0
+}
+
+// RoutingKey tells apart the states that route the tables of the routing
+// rules with a target table differently. The plans that are built for states
+// with different keys must not be shared.
+func (vschema *VSchema) RoutingKey(state RoutingState) string {
+	if !vschema.HasTargetRoutingRules() {
+		return ""
+	}
+	var key strings.Builder
+	for _, name := range vschema.targetRoutingRules {
+		if vschema.RoutingRules[name].routesToTarget(state) {
+			key.WriteByte('1')
+		} else {
+			key.WriteByte('0')
+		}
+	}
+	return key.String()
+}
+
+// ValidateRoutingRules checks the target tables, target read percentages and
+// cutover times of the routing rules.
+func ValidateRoutingRules(rules *vschemapb.RoutingRules) error {
+	for _, rule := range rules.GetRules() {
+		if rule.TargetTable == "" && rule.TargetReadPercent == 0 && rule.CutoverTime == nil {
+			continue
+		}
+		if err := validateRoutingRuleTarget(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRoutingRuleTarget(rule *vschemapb.RoutingRule) error {
+	if rule.TargetTable == "" {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "table %s has a target_read_percent or cutover_time but no target_table", rule.FromTable)
+	}
+	if _, _, err := extractTableParts(rule.TargetTable, false /* allowUnqualified */); err != nil {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid target_table for table %s: %v", rule.FromTable, err)
+	}
+	if len(rule.ToTables) != 1 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "table %s has a target_table and must have exactly one of to_tables: %v", rule.FromTable, rule.ToTables)
+	}
+	if rule.TargetReadPercent > 100 {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid target_read_percent %d for table %s", rule.TargetReadPercent, rule.FromTable)
+	}
+	if rule.CutoverTime != nil && (rule.CutoverTime.Nanoseconds < 0 || rule.CutoverTime.Nanoseconds >= 1e9) {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid cutover_time for table %s", rule.FromTable)
+	}
+	return nil
+}
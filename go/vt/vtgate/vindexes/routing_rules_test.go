@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/protoutil"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
+)
+
+func TestRoutingRuleTarget(t *testing.T) {
+	cutover := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	input := vschemapb.SrvVSchema{
+		RoutingRules: &vschemapb.RoutingRules{
+			Rules: []*vschemapb.RoutingRule{{
+				FromTable:         "t1",
+				ToTables:          []string{"source.t1"},
+				TargetTable:       "target.t1",
+				TargetReadPercent: 30,
+			}, {
+				FromTable:   "t2",
+				ToTables:    []string{"source.t2"},
+				TargetTable: "target.t2",
+				CutoverTime: protoutil.TimeToProto(cutover),
+			}, {
+				FromTable:         "over",
+				ToTables:          []string{"source.t1"},
+				TargetTable:       "target.t1",
+				TargetReadPercent: 101,
+			}, {
+				FromTable:   "notarget",
+				ToTables:    []string{"source.t1"},
+				CutoverTime: protoutil.TimeToProto(cutover),
+			}, {
+				FromTable:   "notfound",
+				ToTables:    []string{"source.t1"},
+				TargetTable: "other.t1",
+			}},
+		},
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"source": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {},
+					"t2": {},
+				},
+			},
+			"target": {
+				Tables: map[string]*vschemapb.Table{
+					"t1": {},
+					"t2": {},
+				},
+			},
+		},
+	}
+	vschema := BuildVSchema(&input, sqlparser.NewTestParser())
+	require.True(t, vschema.HasTargetRoutingRules())
+
+	route := func(table string, now time.Time, bucket int) string {
+		tbl, err := vschema.FindRoutedTableWithState("", table, topodatapb.TabletType_PRIMARY, RoutingState{Now: now, ReadBucket: bucket})
+		require.NoError(t, err)
+		return tbl.String()
+	}
+	before, after := cutover.Add(-time.Second), cutover
+
+	// Reads are split by their bucket, and the other queries are not.
+	assert.Equal(t, "target.t1", route("t1", before, 0))
+	assert.Equal(t, "target.t1", route("t1", before, 29))
+	assert.Equal(t, "source.t1", route("t1", before, 30))
+	assert.Equal(t, "source.t1", route("t1", before, -1))
+
+	// All the queries are routed to the target from the cutover time on.
+	assert.Equal(t, "source.t2", route("t2", before, 50))
+	assert.Equal(t, "target.t2", route("t2", after, -1))
+
+	// The routing key tells apart the states that route differently.
+	assert.Equal(t, "00", vschema.RoutingKey(RoutingState{Now: before, ReadBucket: -1}))
+	assert.Equal(t, "10", vschema.RoutingKey(RoutingState{Now: before, ReadBucket: 10}))
+	assert.Equal(t, "01", vschema.RoutingKey(RoutingState{Now: after, ReadBucket: 50}))
+
+	_, err := vschema.FindRoutedTable("", "over", topodatapb.TabletType_PRIMARY)
+	assert.EqualError(t, err, "invalid target_read_percent 101 for table over")
+	_, err = vschema.FindRoutedTable("", "notarget", topodatapb.TabletType_PRIMARY)
+	assert.EqualError(t, err, "table notarget has a target_read_percent or cutover_time but no target_table")
+	_, err = vschema.FindRoutedTable("", "notfound", topodatapb.TabletType_PRIMARY)
+	assert.ErrorContains(t, err, "other")
+
+	data, err := json.Marshal(vschema.RoutingRules["t2"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tables":["source.t2"],"target":"target.t2","cutover_time":"2024-06-01T12:00:00Z"}`, string(data))
+}
+
+func TestValidateRoutingRules(t *testing.T) {
+	tcases := []struct {
+		rule *vschemapb.RoutingRule
+		err  string
+	}{{
+		rule: &vschemapb.RoutingRule{FromTable: "t1", ToTables: []string{"ks1.t1", "ks2.t1"}},
+	}, {
+		rule: &vschemapb.RoutingRule{FromTable: "t1", ToTables: []string{"ks1.t1"}, TargetTable: "ks2.t1", TargetReadPercent: 100},
+	}, {
+		rule: &vschemapb.RoutingRule{FromTable: "t1", ToTables: []string{"ks1.t1"}, TargetTable: "t1"},
+		err:  "invalid target_table for table t1: invalid table name: t1, it must be of the qualified form <keyspace_name>.<table_name> (dots are not allowed in either name)",
+	}, {
+		rule: &vschemapb.RoutingRule{FromTable: "t1", TargetTable: "ks2.t1"},
+		err:  "table t1 has a target_table and must have exactly one of to_tables: []",
+	}, {
+		rule: &vschemapb.RoutingRule{FromTable: "t1", ToTables: []string{"ks1.t1"}, TargetReadPercent: 10},
+		err:  "table t1 has a target_read_percent or cutover_time but no target_table",
+	}}
+	for _, tcase := range tcases {
+		err := ValidateRoutingRules(&vschemapb.RoutingRules{Rules: []*vschemapb.RoutingRule{tcase.rule}})
+		if tcase.err == "" {
+			assert.NoError(t, err)
+			continue
+		}
+		assert.EqualError(t, err, tcase.err)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vindexes
+
+import (
+	"strings"
+
+	"vitess.io/vitess/go/sqltypes"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// VitessMetadataSchema is the virtual schema through which vtgate exposes
+// its own metadata, like the shards and tablets it knows about, as tables
+// that can be filtered and joined with plain SQL.
+const VitessMetadataSchema = "vitess_metadata"
+
+// The tables of the vitess_metadata schema.
+const (
+	VitessMetadataShards            = "shards"
+	VitessMetadataTablets           = "tablets"
+	VitessMetadataReplicationStatus = "replication_status"
+	VitessMetadataWorkflows         = "workflows"
+	VitessMetadataMigrations        = "migrations"
+	VitessMetadataPlans             = "plans"
+)
+
+// vitessMetadataCollation is the collation of the text columns of the
+// vitess_metadata tables. It matches collations.SystemCollation.
+const vitessMetadataCollation = "utf8mb3_general_ci"
+
+var vitessMetadataTables = map[string]*Table{}
+
+func init() {
+	addVitessMetadataTable(VitessMetadataShards,
+		"Keyspace", "Shard", "KeyRangeStart", "KeyRangeEnd")
+	// The columns of tablets and replication_status are the ones of
+	// SHOW VITESS_TABLETS and SHOW VITESS_REPLICATION_STATUS.
+	addVitessMetadataTable(VitessMetadataTablets,
+		"Cell", "Keyspace", "Shard", "TabletType", "State", "Alias", "Hostname", "PrimaryTermStartTime", "CircuitBreaker")
+	addVitessMetadataTable(VitessMetadataReplicationStatus,
+		"Keyspace", "Shard", "TabletType", "Alias", "Hostname", "ReplicationSource", "ReplicationHealth", "ReplicationLag", "ThrottlerStatus")
+	addVitessMetadataTable(VitessMetadataWorkflows,
+		"Keyspace", "Shard", "Id:INT64", "Workflow", "WorkflowType", "WorkflowSubType", "State", "Message", "Position",
+		"TimeUpdated:INT64", "TransactionTimestamp:INT64")
+	addVitessMetadataTable(VitessMetadataMigrations,
+		"Keyspace", "Shard", "MigrationUUID", "TableName", "Strategy", "Options", "DDLAction", "Status", "Progress:FLOAT64",
+		"Message", "AddedTimestamp", "StartedTimestamp", "CompletedTimestamp")
+	addVitessMetadataTable(VitessMetadataPlans,
+		"Query", "PlanType", "TablesUsed", "ExecCount:UINT64", "ExecTime:FLOAT64", "ShardQueries:UINT64",
+		"RowsReturned:UINT64", "RowsAffected:UINT64", "Errors:UINT64", "LastError", "CacheHits:UINT64", "MemorySize:INT64")
+}
+
+// addVitessMetadataTable adds a table to the vitess_metadata schema. Columns
+// are VARCHAR, unless their name is suffixed with the type, e.g. "Id:INT64".
+func addVitessMetadataTable(name string, columns ...string) {
+	tbl := &Table{
+		Name:                    sqlparser.NewIdentifierCS(name),
+		Keyspace:                &Keyspace{Name: VitessMetadataSchema},
+		ColumnListAuthoritative: true,
+	}
+	for _, column := range columns {
+		colName, typeName, found := strings.Cut(column, ":")
+		col := Column{
+			Name: sqlparser.NewIdentifierCI(colName),
+			Type: sqltypes.VarChar,
+		}
+		if found {
+			col.Type = querypb.Type(querypb.Type_value[typeName])
+		} else {
+			col.CollationName = vitessMetadataCollation
+		}
+		tbl.Columns = append(tbl.Columns, col)
+	}
+	vitessMetadataTables[name] = tbl
+}
+
+// IsVitessMetadataSchema returns true if the given database name is the
+// vitess_metadata schema.
+func IsVitessMetadataSchema(name string) bool {
+	return strings.EqualFold(name, VitessMetadataSchema)
+}
+
+// VitessMetadataTable returns the table of the vitess_metadata schema with
+// the given name, or nil if there is no such table.
+func VitessMetadataTable(name string) *Table {
+	return vitessMetadataTables[strings.ToLower(name)]
+}
@@ -27,6 +27,7 @@ import (
 
 	"vitess.io/vitess/go/json2"
 	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqlescape"
 	"vitess.io/vitess/go/sqltypes"
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -34,6 +35,7 @@ import (
 	vschemapb "vitess.io/vitess/go/vt/proto/vschema"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vtgate/evalengine"
 )
@@ -71,6 +73,17 @@ type VSchema struct {
 	uniqueVindexes    map[string]Vindex
 	Keyspaces         map[string]*KeyspaceSchema `json:"keyspaces"`
 	ShardRoutingRules map[string]string          `json:"shard_routing_rules"`
+	// QuotaRules are the query rate and concurrency limits enforced by vtgate.
+	QuotaRules []*vschemapb.QuotaRule `json:"quota_rules,omitempty"`
+	// TenantRoutingRules maps a tenant id to the target that serves the
+	// queries of the sessions of that tenant.
+	TenantRoutingRules map[string]string `json:"tenant_routing_rules,omitempty"`
+	// targetRoutingRules are the names of the routing rules that have a
+	// target table, sorted.
+	targetRoutingRules []string
+	// trackedGlobalTables are the names of the global tables that schema
+	// tracking added with AddTrackedTable.
+	trackedGlobalTables map[string]bool
 	// created is the time when the VSchema object was created. Used to detect if a cached
 	// copy of the vschema is stale.
 	created time.Time
@@ -80,6 +93,13 @@ type VSchema struct {
 type RoutingRule struct {
 	Tables []*Table
 	Error  error
+
+	// Target is the table that the rule moves its table to. It receives
+	// TargetReadPercent percent of the reads until CutoverTime, and all
+	// the queries from then on. See RoutingRule.route.
+	Target            *Table
+	TargetReadPercent int
+	CutoverTime       time.Time
 }
 
 // MarshalJSON returns a JSON representation of Column.
@@ -91,8 +111,24 @@ func (rr *RoutingRule) MarshalJSON() ([]byte, error) {
 	for _, t := range rr.Tables {
 		tables = append(tables, t.String())
 	}
+	if rr.Target == nil {
+		return json.Marshal(tables)
+	}
 
-	return json.Marshal(tables)
+	rrJ := struct {
+		Tables            []string   `json:"tables"`
+		Target            string     `json:"target"`
+		TargetReadPercent int        `json:"target_read_percent,omitempty"`
+		CutoverTime       *time.Time `json:"cutover_time,omitempty"`
+	}{
+		Tables:            tables,
+		Target:            rr.Target.String(),
+		TargetReadPercent: rr.TargetReadPercent,
+	}
+	if !rr.CutoverTime.IsZero() {
+		rrJ.CutoverTime = &rr.CutoverTime
+	}
+	return json.Marshal(rrJ)
 }
 
 // Table represents a table in VSchema.
@@ -231,15 +267,20 @@ func (col *Column) ToEvalengineType(collationEnv *collations.Environment) evalen
 type KeyspaceSchema struct {
 	Keyspace       *Keyspace
 	ForeignKeyMode vschemapb.Keyspace_ForeignKeyMode
+	QueryTimeout   int
 	Tables         map[string]*Table
 	Vindexes       map[string]Vindex
 	Views          map[string]sqlparser.SelectStatement
 	Error          error
+	// RequireExplicitRouting keeps the tables of the keyspace out of the
+	// global routing.
+	RequireExplicitRouting bool
 }
 
 type ksJSON struct {
 	Sharded        bool              `json:"sharded,omitempty"`
 	ForeignKeyMode string            `json:"foreignKeyMode,omitempty"`
+	QueryTimeout   int               `json:"queryTimeoutMs,omitempty"`
 	Tables         map[string]*Table `json:"tables,omitempty"`
 	Vindexes       map[string]Vindex `json:"vindexes,omitempty"`
 	Views          map[string]string `json:"views,omitempty"`
@@ -275,6 +316,7 @@ func (ks *KeyspaceSchema) MarshalJSON() ([]byte, error) {
 		Sharded:        ks.Keyspace.Sharded,
 		Tables:         ks.Tables,
 		ForeignKeyMode: ks.ForeignKeyMode.String(),
+		QueryTimeout:   ks.QueryTimeout,
 		Vindexes:       ks.Vindexes,
 	}
 	if ks.Error != nil {
@@ -294,6 +336,9 @@ func (ks *KeyspaceSchema) MarshalJSON() ([]byte, error) {
 type AutoIncrement struct {
 	Column   sqlparser.IdentifierCI `json:"column"`
 	Sequence *Table                 `json:"sequence"`
+	// BlockSize is the number of values vtgate reserves at once from the
+	// sequence. Zero means the vtgate default.
+	BlockSize int64 `json:"block_size,omitempty"`
 }
 
 type Source struct {
@@ -322,6 +367,8 @@ func BuildVSchema(source *vschemapb.SrvVSchema, parser *sqlparser.Parser) (vsche
 	buildReferences(source, vschema)
 	buildRoutingRule(source, vschema, parser)
 	buildShardRoutingRule(source, vschema)
+	vschema.QuotaRules = source.GetQuotaRules().GetRules()
+	buildTenantRoutingRule(source, vschema)
 	// Resolve auto-increments after routing rules are built since sequence tables also obey routing rules.
 	resolveAutoIncrement(source, vschema, parser)
 	return vschema
@@ -362,9 +409,11 @@ func buildKeyspaces(source *vschemapb.SrvVSchema, vschema *VSchema, parser *sqlp
 				Name:    ksname,
 				Sharded: ks.Sharded,
 			},
-			ForeignKeyMode: replaceUnspecifiedForeignKeyMode(ks.ForeignKeyMode),
-			Tables:         make(map[string]*Table),
-			Vindexes:       make(map[string]Vindex),
+			ForeignKeyMode:         replaceUnspecifiedForeignKeyMode(ks.ForeignKeyMode),
+			QueryTimeout:           int(ks.QueryTimeoutMs),
+			Tables:                 make(map[string]*Table),
+			Vindexes:               make(map[string]Vindex),
+			RequireExplicitRouting: ks.RequireExplicitRouting,
 		}
 		vschema.Keyspaces[ksname] = ksvschema
 		ksvschema.Error = buildTables(ks, vschema, ksvschema, parser)
@@ -807,6 +856,52 @@ func (vschema *VSchema) addTableName(t *Table) {
 	}
 }
 
+// AddTrackedTable adds a table that schema tracking found in its keyspace,
+// but that the vschema does not declare. A table of an unsharded keyspace
+// is globally routable unless a declared table has the same name, and it
+// replaces the table that the routing rules constructed for it, so that the
+// queries on it know its columns.
+func (vschema *VSchema) AddTrackedTable(t *Table) {
+	ks := vschema.Keyspaces[t.Keyspace.Name]
+	if ks == nil {
+		return
+	}
+	tname := t.Name.String()
+	ks.Tables[tname] = t
+	if t.Keyspace.Sharded {
+		return
+	}
+
+	if !ks.RequireExplicitRouting {
+		gt, ok := vschema.globalTables[tname]
+		switch {
+		case !ok:
+			vschema.globalTables[tname] = t
+			if vschema.trackedGlobalTables == nil {
+				vschema.trackedGlobalTables = make(map[string]bool)
+			}
+			vschema.trackedGlobalTables[tname] = true
+		case gt != nil && vschema.trackedGlobalTables[tname]:
+			// Another unsharded keyspace has a table with the same name.
+			vschema.globalTables[tname] = nil
+		}
+	}
+
+	isConstructed := func(rt *Table) bool {
+		return rt != nil && rt != t && rt.Keyspace.Name == t.Keyspace.Name && rt.Name.String() == tname
+	}
+	for _, rr := range vschema.RoutingRules {
+		for i, rt := range rr.Tables {
+			if isConstructed(rt) {
+				rr.Tables[i] = t
+			}
+		}
+		if isConstructed(rr.Target) {
+			rr.Target = t
+		}
+	}
+}
+
 func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema, parser *sqlparser.Parser) {
 	for ksname, ks := range source.Keyspaces {
 		ksvschema := vschema.Keyspaces[ksname]
@@ -824,6 +919,9 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema, parser
 					err = vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "table %s not found", seqtab)
 				}
 			}
+			if err == nil && table.AutoIncrement.BlockSize < 0 {
+				err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "invalid block_size %d", table.AutoIncrement.BlockSize)
+			}
 			if err != nil {
 				// Better to remove the table than to leave it partially initialized.
 				delete(ksvschema.Tables, tname)
@@ -838,8 +936,9 @@ func resolveAutoIncrement(source *vschemapb.SrvVSchema, vschema *VSchema, parser
 				continue
 			}
 			t.AutoIncrement = &AutoIncrement{
-				Column:   sqlparser.NewIdentifierCI(table.AutoIncrement.Column),
-				Sequence: seq,
+				Column:    sqlparser.NewIdentifierCI(table.AutoIncrement.Column),
+				Sequence:  seq,
+				BlockSize: table.AutoIncrement.BlockSize,
 			}
 		}
 	}
@@ -928,47 +1027,58 @@ outer:
 				continue outer
 			}
 
-			// we need to backtick the keyspace and table name before calling ParseTable
-			toTable, err = escapeQualifiedTable(toTable)
-			if err != nil {
-				vschema.RoutingRules[rule.FromTable] = &RoutingRule{
-					Error: vterrors.Errorf(
-						vtrpcpb.Code_INVALID_ARGUMENT,
-						err.Error(),
-					),
-				}
-				continue outer
-			}
-
-			toKeyspace, toTableName, err := parser.ParseTable(toTable)
-
+			t, err := resolveRoutingRuleTable(vschema, parser, toTable)
 			if err != nil {
 				vschema.RoutingRules[rule.FromTable] = &RoutingRule{
 					Error: err,
 				}
 				continue outer
 			}
-			if toKeyspace == "" {
-				vschema.RoutingRules[rule.FromTable] = &RoutingRule{
-					Error: vterrors.Errorf(
-						vtrpcpb.Code_INVALID_ARGUMENT,
-						"table %s must be qualified",
-						toTable,
-					),
-				}
-				continue outer
+			rr.Tables = append(rr.Tables, t)
+		}
+		if rule.TargetTable != "" || rule.TargetReadPercent != 0 || rule.CutoverTime != nil {
+			if err = validateRoutingRuleTarget(rule); err == nil {
+				rr.Target, err = resolveRoutingRuleTable(vschema, parser, rule.TargetTable)
 			}
-			t, err := vschema.FindTable(toKeyspace, toTableName)
 			if err != nil {
 				vschema.RoutingRules[rule.FromTable] = &RoutingRule{
 					Error: err,
 				}
-				continue outer
+				continue
 			}
-			rr.Tables = append(rr.Tables, t)
+			rr.TargetReadPercent = int(rule.TargetReadPercent)
+			rr.CutoverTime = protoutil.TimeFromProto(rule.CutoverTime)
+			vschema.targetRoutingRules = append(vschema.targetRoutingRules, rule.FromTable)
 		}
 		vschema.RoutingRules[rule.FromTable] = rr
 	}
+	sort.Strings(vschema.targetRoutingRules)
+}
+
+// resolveRoutingRuleTable finds the table, qualified by its keyspace, that a
+// routing rule routes to.
+func resolveRoutingRuleTable(vschema *VSchema, parser *sqlparser.Parser, toTable string) (*Table, error) {
+	// we need to backtick the keyspace and table name before calling ParseTable
+	toTable, err := escapeQualifiedTable(toTable)
+	if err != nil {
+		return nil, vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			err.Error(),
+		)
+	}
+
+	toKeyspace, toTableName, err := parser.ParseTable(toTable)
+	if err != nil {
+		return nil, err
+	}
+	if toKeyspace == "" {
+		return nil, vterrors.Errorf(
+			vtrpcpb.Code_INVALID_ARGUMENT,
+			"table %s must be qualified",
+			toTable,
+		)
+	}
+	return vschema.FindTable(toKeyspace, toTableName)
 }
 
 func buildShardRoutingRule(source *vschemapb.SrvVSchema, vschema *VSchema) {
@@ -981,6 +1091,45 @@ func buildShardRoutingRule(source *vschemapb.SrvVSchema, vschema *VSchema) {
 	}
 }
 
+func buildTenantRoutingRule(source *vschemapb.SrvVSchema, vschema *VSchema) {
+	rules := source.GetTenantRoutingRules().GetRules()
+	if len(rules) == 0 {
+		return
+	}
+	vschema.TenantRoutingRules = make(map[string]string, len(rules))
+	for _, rule := range rules {
+		vschema.TenantRoutingRules[rule.TenantId] = rule.Target
+	}
+}
+
+// ValidateTenantRoutingRules checks that every tenant routing rule has a
+// distinct tenant id and a target of the form keyspace, keyspace:shard or
+// keyspace[keyrange]. Tablet types are not allowed in the target, they come
+// from the session.
+func ValidateTenantRoutingRules(rules *vschemapb.TenantRoutingRules) error {
+	tenants := make(map[string]bool, len(rules.GetRules()))
+	for _, rule := range rules.GetRules() {
+		if rule.TenantId == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "tenant routing rule with target '%s' has no tenant id", rule.Target)
+		}
+		if tenants[rule.TenantId] {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "duplicate tenant routing rule for tenant '%s'", rule.TenantId)
+		}
+		tenants[rule.TenantId] = true
+		if strings.Contains(rule.Target, "@") {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "target '%s' of tenant '%s' must not have a tablet type", rule.Target, rule.TenantId)
+		}
+		keyspace, _, _, err := topoproto.ParseDestination(rule.Target, topodatapb.TabletType_PRIMARY)
+		if err != nil {
+			return vterrors.Wrapf(err, "invalid target '%s' of tenant '%s'", rule.Target, rule.TenantId)
+		}
+		if keyspace == "" {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "target '%s' of tenant '%s' has no keyspace", rule.Target, rule.TenantId)
+		}
+	}
+	return nil
+}
+
 // FindTable returns a pointer to the Table. If a keyspace is specified, only tables
 // from that keyspace are searched. If the specified keyspace is unsharded
 // and no tables matched, it's considered valid: FindTable will construct a table
@@ -1093,6 +1242,12 @@ func (vschema *VSchema) FirstKeyspace() *Keyspace {
 
 // FindRoutedTable finds a table checking the routing rules.
 func (vschema *VSchema) FindRoutedTable(keyspace, tablename string, tabletType topodatapb.TabletType) (*Table, error) {
+	return vschema.FindRoutedTableWithState(keyspace, tablename, tabletType, NewRoutingState())
+}
+
+// FindRoutedTableWithState finds a table checking the routing rules, and
+// routing the tables that move to another keyspace according to state.
+func (vschema *VSchema) FindRoutedTableWithState(keyspace, tablename string, tabletType topodatapb.TabletType, state RoutingState) (*Table, error) {
 	qualified := tablename
 	if keyspace != "" {
 		qualified = keyspace + "." + tablename
@@ -1113,7 +1268,7 @@ func (vschema *VSchema) FindRoutedTable(keyspace, tablename string, tabletType t
 					tablename,
 				)
 			}
-			return rr.Tables[0], nil
+			return rr.route(state), nil
 		}
 	}
 	return vschema.findTable(
@@ -1125,7 +1280,13 @@ func (vschema *VSchema) FindRoutedTable(keyspace, tablename string, tabletType t
 
 // FindTableOrVindex finds a table or a Vindex by name using Find and FindVindex.
 func (vschema *VSchema) FindTableOrVindex(keyspace, name string, tabletType topodatapb.TabletType) (*Table, Vindex, error) {
-	tables, err := vschema.FindRoutedTable(keyspace, name, tabletType)
+	return vschema.FindTableOrVindexWithState(keyspace, name, tabletType, NewRoutingState())
+}
+
+// FindTableOrVindexWithState is like FindTableOrVindex, but routes the tables
+// that move to another keyspace according to state.
+func (vschema *VSchema) FindTableOrVindexWithState(keyspace, name string, tabletType topodatapb.TabletType, state RoutingState) (*Table, Vindex, error) {
+	tables, err := vschema.FindRoutedTableWithState(keyspace, name, tabletType, state)
 	if err != nil {
 		return nil, nil, err
 	}
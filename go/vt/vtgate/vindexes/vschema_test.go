@@ -2214,6 +2214,56 @@ func TestBadSequence(t *testing.T) {
 	}
 }
 
+func TestSequenceBlockSize(t *testing.T) {
+	srvVSchema := vschemapb.SrvVSchema{
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded": {
+				Tables: map[string]*vschemapb.Table{
+					"seq": {
+						Type: "sequence",
+					},
+				},
+			},
+			"sharded": {
+				Sharded: true,
+				Vindexes: map[string]*vschemapb.Vindex{
+					"stfu1": {
+						Type: "stfu",
+					},
+				},
+				Tables: map[string]*vschemapb.Table{
+					"t1": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{{
+							Column: "c1",
+							Name:   "stfu1",
+						}},
+						AutoIncrement: &vschemapb.AutoIncrement{
+							Column:    "c1",
+							Sequence:  "seq",
+							BlockSize: 100,
+						},
+					},
+					"t2": {
+						ColumnVindexes: []*vschemapb.ColumnVindex{{
+							Column: "c1",
+							Name:   "stfu1",
+						}},
+						AutoIncrement: &vschemapb.AutoIncrement{
+							Column:    "c1",
+							Sequence:  "seq",
+							BlockSize: -1,
+						},
+					},
+				},
+			},
+		},
+	}
+	got := BuildVSchema(&srvVSchema, sqlparser.NewTestParser())
+	require.EqualError(t, got.Keyspaces["sharded"].Error, "cannot resolve sequence seq: invalid block_size -1")
+	assert.Nil(t, got.Keyspaces["sharded"].Tables["t2"])
+	assert.EqualValues(t, 100, got.Keyspaces["sharded"].Tables["t1"].AutoIncrement.BlockSize)
+}
+
 func TestBadSequenceName(t *testing.T) {
 	bad := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
@@ -2993,6 +3043,71 @@ func TestReferenceTableAndSourceAreGloballyRoutable(t *testing.T) {
 	require.EqualError(t, err, "table t1 not found")
 }
 
+func TestAddTrackedTable(t *testing.T) {
+	input := vschemapb.SrvVSchema{
+		RoutingRules: &vschemapb.RoutingRules{
+			Rules: []*vschemapb.RoutingRule{{
+				FromTable: "rt1",
+				ToTables:  []string{"unsharded1.t1"},
+			}},
+		},
+		Keyspaces: map[string]*vschemapb.Keyspace{
+			"unsharded1": {},
+			"unsharded2": {
+				Tables: map[string]*vschemapb.Table{
+					"t3": {},
+				},
+			},
+			"unsharded3": {
+				RequireExplicitRouting: true,
+			},
+			"sharded": {
+				Sharded: true,
+			},
+		},
+	}
+	vs := BuildVSchema(&input, sqlparser.NewTestParser())
+	tracked := func(ks, name string) *Table {
+		return &Table{
+			Name:                    sqlparser.NewIdentifierCS(name),
+			Keyspace:                vs.Keyspaces[ks].Keyspace,
+			ColumnListAuthoritative: true,
+		}
+	}
+	t1 := tracked("unsharded1", "t1")
+	vs.AddTrackedTable(t1)
+	vs.AddTrackedTable(tracked("unsharded1", "t2"))
+	vs.AddTrackedTable(tracked("unsharded2", "t2"))
+	vs.AddTrackedTable(tracked("unsharded1", "t3"))
+	vs.AddTrackedTable(tracked("unsharded3", "t4"))
+	vs.AddTrackedTable(tracked("sharded", "t5"))
+
+	// A tracked table of an unsharded keyspace is globally routable.
+	table, err := vs.FindTable("", "t1")
+	require.NoError(t, err)
+	assert.Same(t, t1, table)
+	// The routing rules route to the tracked table.
+	table, err = vs.FindRoutedTable("", "rt1", topodatapb.TabletType_PRIMARY)
+	require.NoError(t, err)
+	assert.Same(t, t1, table)
+	// Tracked tables with the same name are ambiguous.
+	_, err = vs.FindTable("", "t2")
+	require.EqualError(t, err, "ambiguous table reference: t2")
+	// A table declared in the vschema wins over a tracked table.
+	table, err = vs.FindTable("", "t3")
+	require.NoError(t, err)
+	assert.Equal(t, "unsharded2", table.Keyspace.Name)
+	// The tables of the keyspaces that require explicit routing, and of the
+	// sharded keyspaces, are not globally routable.
+	_, err = vs.FindTable("", "t4")
+	require.EqualError(t, err, "table t4 not found")
+	_, err = vs.FindTable("", "t5")
+	require.EqualError(t, err, "table t5 not found")
+	table, err = vs.FindTable("sharded", "t5")
+	require.NoError(t, err)
+	assert.True(t, table.ColumnListAuthoritative)
+}
+
 func TestOtherTablesMakeReferenceTableAndSourceAmbiguous(t *testing.T) {
 	input := vschemapb.SrvVSchema{
 		Keyspaces: map[string]*vschemapb.Keyspace{
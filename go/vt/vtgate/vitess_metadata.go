@@ -0,0 +1,307 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/constants/sidecar"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/log"
+	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/engine"
+	"vitess.io/vitess/go/vt/vtgate/vindexes"
+)
+
+// vitessMetadataRows returns the rows of the given table of the
+// vitess_metadata schema. The columns of the rows are the ones declared
+// by vindexes.VitessMetadataTable.
+func (e *Executor) vitessMetadataRows(ctx context.Context, table string, destTabletType topodatapb.TabletType) ([][]sqltypes.Value, error) {
+	switch table {
+	case vindexes.VitessMetadataShards:
+		return e.shardRows(ctx, destTabletType)
+	case vindexes.VitessMetadataTablets:
+		return e.tabletRows(), nil
+	case vindexes.VitessMetadataReplicationStatus:
+		return e.replicationStatusRows(ctx, func(*querypb.Target) bool { return true }), nil
+	case vindexes.VitessMetadataWorkflows:
+		return e.workflowRows(ctx), nil
+	case vindexes.VitessMetadataMigrations:
+		return e.migrationRows(ctx), nil
+	case vindexes.VitessMetadataPlans:
+		return e.planRows(), nil
+	default:
+		return nil, vterrors.VT05004(fmt.Sprintf("%s.%s", vindexes.VitessMetadataSchema, table))
+	}
+}
+
+func (e *Executor) shardRows(ctx context.Context, destTabletType topodatapb.TabletType) ([][]sqltypes.Value, error) {
+	keyspaces, err := e.resolver.resolver.GetAllKeyspaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]sqltypes.Value{}
+	for _, keyspace := range keyspaces {
+		_, _, shards, err := e.resolver.resolver.GetKeyspaceShards(ctx, keyspace, destTabletType)
+		if err != nil {
+			// There might be a misconfigured keyspace or no shards in the keyspace.
+			// Skip any errors and move on.
+			continue
+		}
+		for _, shard := range shards {
+			rows = append(rows, buildVarCharRow(
+				keyspace,
+				shard.Name,
+				hex.EncodeToString(shard.GetKeyRange().GetStart()),
+				hex.EncodeToString(shard.GetKeyRange().GetEnd()),
+			))
+		}
+	}
+	return rows, nil
+}
+
+func (e *Executor) tabletRows() [][]sqltypes.Value {
+	rows := [][]sqltypes.Value{}
+	status := e.scatterConn.GetHealthCheckCacheStatus()
+	for _, s := range status {
+		for _, ts := range s.TabletsStats {
+			state := "SERVING"
+			if !ts.Serving {
+				state = "NOT_SERVING"
+			}
+			ptst := ts.PrimaryTermStartTime
+			ptstStr := ""
+			if ptst > 0 {
+				// this code depends on the fact that PrimaryTermStartTime is the seconds since epoch start
+				ptstStr = time.Unix(ptst, 0).UTC().Format(time.RFC3339)
+			}
+			rows = append(rows, buildVarCharRow(
+				s.Cell,
+				s.Target.Keyspace,
+				s.Target.Shard,
+				ts.Target.TabletType.String(),
+				state,
+				topoproto.TabletAliasString(ts.Tablet.Alias),
+				ts.Tablet.Hostname,
+				ptstStr,
+				e.scatterConn.gateway.CircuitBreakerState(ts),
+			))
+		}
+	}
+	return rows
+}
+
+// replicationStatusRows returns the replication status of the REPLICA and
+// RDONLY tablets whose target is accepted by include.
+func (e *Executor) replicationStatusRows(ctx context.Context, include func(*querypb.Target) bool) [][]sqltypes.Value {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	rows := [][]sqltypes.Value{}
+
+	status := e.scatterConn.GetHealthCheckCacheStatus()
+
+	for _, s := range status {
+		for _, ts := range s.TabletsStats {
+			// We only want to show REPLICA and RDONLY tablets
+			if ts.Target.TabletType != topodatapb.TabletType_REPLICA && ts.Target.TabletType != topodatapb.TabletType_RDONLY {
+				continue
+			}
+			if !include(ts.Target) {
+				continue
+			}
+
+			tabletHostPort := ts.GetTabletHostPort()
+			throttlerStatus, err := getTabletThrottlerStatus(tabletHostPort)
+			if err != nil {
+				log.Warningf("Could not get throttler status from %s: %v", topoproto.TabletAliasString(ts.Tablet.Alias), err)
+			}
+
+			replSourceHost := ""
+			replSourcePort := int64(0)
+			replIOThreadHealth := ""
+			replSQLThreadHealth := ""
+			replLastError := ""
+			replLag := "-1" // A string to support NULL as a value
+			sql := "show slave status"
+			results, err := e.txConn.tabletGateway.Execute(ctx, ts.Target, sql, nil, 0, 0, nil)
+			if err != nil || results == nil {
+				log.Warningf("Could not get replication status from %s: %v", tabletHostPort, err)
+			} else if row := results.Named().Row(); row != nil {
+				replSourceHost = row["Master_Host"].ToString()
+				replSourcePort, _ = row["Master_Port"].ToInt64()
+				replIOThreadHealth = row["Slave_IO_Running"].ToString()
+				replSQLThreadHealth = row["Slave_SQL_Running"].ToString()
+				replLastError = row["Last_Error"].ToString()
+				// We cannot check the tablet's tabletenv config from here so
+				// we only use the tablet's stat -- which is managed by the
+				// ReplicationTracker -- if we can tell that it's enabled,
+				// meaning that it has a non-zero value. If it's actually
+				// enabled AND zero (rather than the zeroval), then mysqld
+				// should also return 0 so in this case the value is correct
+				// and equivalent either way. The only reason that we would
+				// want to use the ReplicationTracker based value, when we
+				// can, is because the polling method allows us to get the
+				// estimated lag value when replication is not running (based
+				// on how long we've seen that it's not been running).
+				if ts.Stats != nil && ts.Stats.ReplicationLagSeconds > 0 { // Use the value we get from the ReplicationTracker
+					replLag = fmt.Sprintf("%d", ts.Stats.ReplicationLagSeconds)
+				} else { // Use the value from mysqld
+					if row["Seconds_Behind_Master"].IsNull() {
+						replLag = strings.ToUpper(sqltypes.NullStr) // Uppercase to match mysqld's output in SHOW REPLICA STATUS
+					} else {
+						replLag = row["Seconds_Behind_Master"].ToString()
+					}
+				}
+			}
+			replicationHealth := fmt.Sprintf("{\"EventStreamRunning\":\"%s\",\"EventApplierRunning\":\"%s\",\"LastError\":\"%s\"}", replIOThreadHealth, replSQLThreadHealth, replLastError)
+
+			rows = append(rows, buildVarCharRow(
+				s.Target.Keyspace,
+				s.Target.Shard,
+				ts.Target.TabletType.String(),
+				topoproto.TabletAliasString(ts.Tablet.Alias),
+				ts.Tablet.Hostname,
+				fmt.Sprintf("%s:%d", replSourceHost, replSourcePort),
+				replicationHealth,
+				replLag,
+				throttlerStatus,
+			))
+		}
+	}
+	return rows
+}
+
+// primaryTargets returns the targets of the serving PRIMARY tablets known
+// to the health check.
+func (e *Executor) primaryTargets() []*querypb.Target {
+	var targets []*querypb.Target
+	for _, s := range e.scatterConn.GetHealthCheckCacheStatus() {
+		if s.Target.TabletType != topodatapb.TabletType_PRIMARY {
+			continue
+		}
+		for _, ts := range s.TabletsStats {
+			if ts.Serving {
+				targets = append(targets, s.Target)
+				break
+			}
+		}
+	}
+	return targets
+}
+
+// queryPrimaries runs the given query on every shard PRIMARY and calls
+// onRow for each returned row. Shards that fail to answer are logged and
+// skipped, so that one unhealthy shard doesn't hide the others.
+func (e *Executor) queryPrimaries(ctx context.Context, query string, onRow func(target *querypb.Target, row sqltypes.RowNamedValues)) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	for _, target := range e.primaryTargets() {
+		results, err := e.txConn.tabletGateway.Execute(ctx, target, query, nil, 0, 0, nil)
+		if err != nil || results == nil {
+			log.Warningf("Could not run %q on %s/%s: %v", query, target.Keyspace, target.Shard, err)
+			continue
+		}
+		for _, row := range results.Named().Rows {
+			onRow(target, row)
+		}
+	}
+}
+
+func (e *Executor) workflowRows(ctx context.Context) [][]sqltypes.Value {
+	query := sqlparser.BuildParsedQuery(
+		"select id, workflow, workflow_type, workflow_sub_type, state, message, pos, time_updated, transaction_timestamp from %s.vreplication",
+		sidecar.GetIdentifier(),
+	).Query
+
+	rows := [][]sqltypes.Value{}
+	e.queryPrimaries(ctx, query, func(target *querypb.Target, row sqltypes.RowNamedValues) {
+		workflowType, _ := row["workflow_type"].ToInt32()
+		workflowSubType, _ := row["workflow_sub_type"].ToInt32()
+		rows = append(rows, []sqltypes.Value{
+			sqltypes.NewVarChar(target.Keyspace),
+			sqltypes.NewVarChar(target.Shard),
+			sqltypes.NewInt64(row.AsInt64("id", 0)),
+			sqltypes.NewVarChar(row.AsString("workflow", "")),
+			sqltypes.NewVarChar(binlogdatapb.VReplicationWorkflowType(workflowType).String()),
+			sqltypes.NewVarChar(binlogdatapb.VReplicationWorkflowSubType(workflowSubType).String()),
+			sqltypes.NewVarChar(row.AsString("state", "")),
+			sqltypes.NewVarChar(row.AsString("message", "")),
+			sqltypes.NewVarChar(row.AsString("pos", "")),
+			sqltypes.NewInt64(row.AsInt64("time_updated", 0)),
+			sqltypes.NewInt64(row.AsInt64("transaction_timestamp", 0)),
+		})
+	})
+	return rows
+}
+
+func (e *Executor) migrationRows(ctx context.Context) [][]sqltypes.Value {
+	rows := [][]sqltypes.Value{}
+	e.queryPrimaries(ctx, "show vitess_migrations", func(target *querypb.Target, row sqltypes.RowNamedValues) {
+		progress, _ := row["progress"].ToFloat64()
+		rows = append(rows, []sqltypes.Value{
+			sqltypes.NewVarChar(target.Keyspace),
+			sqltypes.NewVarChar(target.Shard),
+			sqltypes.NewVarChar(row.AsString("migration_uuid", "")),
+			sqltypes.NewVarChar(row.AsString("mysql_table", "")),
+			sqltypes.NewVarChar(row.AsString("strategy", "")),
+			sqltypes.NewVarChar(row.AsString("options", "")),
+			sqltypes.NewVarChar(row.AsString("ddl_action", "")),
+			sqltypes.NewVarChar(row.AsString("migration_status", "")),
+			sqltypes.NewFloat64(progress),
+			sqltypes.NewVarChar(row.AsString("message", "")),
+			sqltypes.NewVarChar(row.AsString("added_timestamp", "")),
+			sqltypes.NewVarChar(row.AsString("started_timestamp", "")),
+			sqltypes.NewVarChar(row.AsString("completed_timestamp", "")),
+		})
+	})
+	return rows
+}
+
+func (e *Executor) planRows() [][]sqltypes.Value {
+	rows := [][]sqltypes.Value{}
+	e.ForEachPlan(func(plan *engine.Plan) bool {
+		execCount, execTime, shardQueries, rowsAffected, rowsReturned, errors := plan.Stats()
+		cacheHits := atomic.LoadUint64(&plan.CacheHits)
+		rows = append(rows, []sqltypes.Value{
+			sqltypes.NewVarChar(plan.Original),
+			sqltypes.NewVarChar(plan.Type.String()),
+			sqltypes.NewVarChar(strings.Join(plan.TablesUsed, ",")),
+			sqltypes.NewUint64(execCount),
+			sqltypes.NewFloat64(execTime.Seconds()),
+			sqltypes.NewUint64(shardQueries),
+			sqltypes.NewUint64(rowsReturned),
+			sqltypes.NewUint64(rowsAffected),
+			sqltypes.NewUint64(errors),
+			sqltypes.NewVarChar(plan.LastError()),
+			sqltypes.NewUint64(cacheHits),
+			sqltypes.NewInt64(plan.CachedSize(true)),
+		})
+		return true
+	})
+	return rows
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vtgate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	vtgatepb "vitess.io/vitess/go/vt/proto/vtgate"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vtgate/vschemaacl"
+)
+
+func TestExecutorVitessMetadata(t *testing.T) {
+	executor, _, _, _, ctx := createExecutorEnv(t)
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "TestExecutor"})
+
+	tcases := []struct {
+		query string
+		want  [][]sqltypes.Value
+	}{{
+		query: "select Alias, Hostname from vitess_metadata.tablets where Keyspace = 'TestExecutor' and Shard = '-20'",
+		want:  [][]sqltypes.Value{buildVarCharRow("aa-0000000001", "-20")},
+	}, {
+		query: "select Keyspace, Shard, KeyRangeStart, KeyRangeEnd from vitess_metadata.shards where Keyspace = 'TestExecutor' and Shard = '20-40'",
+		want:  [][]sqltypes.Value{buildVarCharRow("TestExecutor", "20-40", "20", "40")},
+	}, {
+		query: "select count(*) from vitess_metadata.shards where Keyspace = 'TestExecutor'",
+		want:  [][]sqltypes.Value{{sqltypes.NewInt64(8)}},
+	}, {
+		query: "select s.KeyRangeEnd from vitess_metadata.tablets t join vitess_metadata.shards s on t.Keyspace = s.Keyspace and t.Shard = s.Shard where t.Alias = 'aa-0000000001'",
+		want:  [][]sqltypes.Value{buildVarCharRow("20")},
+	}, {
+		query: "show vitess_shards where Keyspace = 'TestExecutor' and Shard = '-20'",
+		want:  [][]sqltypes.Value{buildVarCharRow("TestExecutor/-20")},
+	}, {
+		query: "show vitess_tablets where Alias = 'aa-0000000001'",
+		want:  [][]sqltypes.Value{buildVarCharRow("aa", "TestExecutor", "-20", "PRIMARY", "SERVING", "aa-0000000001", "-20", "1970-01-01T00:00:01Z", "")},
+	}, {
+		query: "show vitess_replication_status where Keyspace = 'TestExecutor'",
+		want:  nil,
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.query, func(t *testing.T) {
+			qr, err := executor.Execute(ctx, nil, "TestExecute", session, tcase.query, nil)
+			require.NoError(t, err)
+			utils.MustMatch(t, tcase.want, qr.Rows)
+		})
+	}
+
+	_, err := executor.Execute(ctx, nil, "TestExecute", session, "delete from vitess_metadata.tablets", nil)
+	require.ErrorContains(t, err, "modifying vitess_metadata tables")
+}
+
+func TestExecutorShowVitessPlans(t *testing.T) {
+	executor, sbc1, _, _, ctx := createExecutorEnv(t)
+	session := NewSafeSession(&vtgatepb.Session{TargetString: "@primary"})
+
+	query := "select id from user where id = 1"
+	for i := 0; i < 3; i++ {
+		_, err := executor.Execute(ctx, nil, "TestExecute", session, query, nil)
+		require.NoError(t, err)
+	}
+	sbc1.MustFailCodes[vtrpcpb.Code_INVALID_ARGUMENT] = 1
+	_, err := executor.Execute(ctx, nil, "TestExecute", session, query, nil)
+	require.Error(t, err)
+
+	qr, err := executor.Execute(ctx, nil, "TestExecute", session, "show vitess_plans like '%user%'", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+	row := qr.Rows[0]
+	assert.Equal(t, "ExecCount", qr.Fields[3].Name)
+	assert.EqualValues(t, "4", row[3].ToString())
+	assert.Equal(t, "Errors", qr.Fields[8].Name)
+	assert.EqualValues(t, "1", row[8].ToString())
+	assert.Equal(t, "LastError", qr.Fields[9].Name)
+	assert.Contains(t, row[9].ToString(), "INVALID_ARGUMENT")
+	assert.Equal(t, "CacheHits", qr.Fields[10].Name)
+	assert.EqualValues(t, "3", row[10].ToString())
+	assert.Equal(t, "MemorySize", qr.Fields[11].Name)
+	assert.NotEqual(t, "0", row[11].ToString())
+
+	qr, err = executor.Execute(ctx, nil, "TestExecute", session, "show vitess_plans where CacheHits > 2", nil)
+	require.NoError(t, err)
+	require.Len(t, qr.Rows, 1)
+
+	// Resetting the plans requires the vschema ACL.
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "reset vitess_plans", nil)
+	assert.Equal(t, vtrpcpb.Code_PERMISSION_DENIED, vterrors.Code(err), err)
+	qr, err = executor.Execute(ctx, nil, "TestExecute", session, "show vitess_plans", nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, qr.Rows)
+
+	vschemaacl.AuthorizedDDLUsers = "%"
+	vschemaacl.Init()
+	defer func() {
+		vschemaacl.AuthorizedDDLUsers = ""
+		vschemaacl.Init()
+	}()
+	_, err = executor.Execute(ctx, nil, "TestExecute", session, "reset vitess_plans", nil)
+	require.NoError(t, err)
+	qr, err = executor.Execute(ctx, nil, "TestExecute", session, "show vitess_plans", nil)
+	require.NoError(t, err)
+	require.Empty(t, qr.Rows)
+}
@@ -206,7 +206,7 @@ func (vm *VSchemaManager) updateFromSchema(vschema *vindexes.VSchema) {
 		// are created in the Vschema, so that later when we try to find the routed tables, we don't end up
 		// getting dummy tables.
 		for tblName, tblInfo := range m {
-			setColumns(ks, tblName, tblInfo.Columns)
+			setColumns(vschema, ks, tblName, tblInfo.Columns)
 		}
 
 		// Now that we have ensured that all the tables are created, we can start populating the foreign keys
@@ -318,16 +318,16 @@ func addCrossEdges(g *graph.Graph[string], from []string, to []string) {
 	}
 }
 
-func setColumns(ks *vindexes.KeyspaceSchema, tblName string, columns []vindexes.Column) *vindexes.Table {
+func setColumns(vschema *vindexes.VSchema, ks *vindexes.KeyspaceSchema, tblName string, columns []vindexes.Column) *vindexes.Table {
 	vTbl := ks.Tables[tblName]
 	if vTbl == nil {
 		// a table that is unknown by the vschema. we add it as a normal table
-		ks.Tables[tblName] = &vindexes.Table{
+		vschema.AddTrackedTable(&vindexes.Table{
 			Name:                    sqlparser.NewIdentifierCS(tblName),
 			Keyspace:                ks.Keyspace,
 			Columns:                 columns,
 			ColumnListAuthoritative: true,
-		}
+		})
 		return ks.Tables[tblName]
 	}
 	// if we found the matching table and the vschema view of it is not authoritative, then we just update the columns of the table
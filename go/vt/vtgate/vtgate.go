@@ -62,6 +62,10 @@ var (
 	normalizeQueries = true
 	streamBufferSize = 32 * 1024
 
+	// canonicalizeQueries reorders commutative expressions before the
+	// queries are normalized, to share plans between equivalent queries.
+	canonicalizeQueries bool
+
 	terseErrors      bool
 	truncateErrorLen int
 
@@ -117,11 +121,33 @@ var (
 	warmingReadsPercent      = 0
 	warmingReadsQueryTimeout = 5 * time.Second
 	warmingReadsConcurrency  = 500
+
+	// read retry related flags
+	readRetryCount          = 0
+	readRetryInitialBackoff = 50 * time.Millisecond
+	readRetryMaxBackoff     = time.Second
+
+	// sequence related flags
+	sequenceBlockSize    int64
+	sequenceFetchRetries = 3
+
+	// loadDataBatchSize is the number of rows that LOAD DATA inserts into
+	// a shard with a single query.
+	loadDataBatchSize = 1000
+
+	// insertBatchRows is the maximum number of rows that an INSERT inserts
+	// into a shard with a single query, 0 for no limit.
+	insertBatchRows = 0
+
+	// read/write splitting related flags
+	readWriteSplitting            = false
+	readWriteSplittingWaitTimeout = 1 * time.Second
 )
 
 func registerFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&transactionMode, "transaction_mode", transactionMode, "SINGLE: disallow multi-db transactions, MULTI: allow multi-db transactions with best effort commit, TWOPC: allow multi-db transactions with 2pc commit")
 	fs.BoolVar(&normalizeQueries, "normalize_queries", normalizeQueries, "Rewrite queries with bind vars. Turn this off if the app itself sends normalized queries with bind vars.")
+	fs.BoolVar(&canonicalizeQueries, "canonicalize-queries", canonicalizeQueries, "Rewrite the comparisons, the AND conditions and the constants of queries in a canonical order and format before normalizing them, so that equivalent queries share the same cached plan.")
 	fs.BoolVar(&terseErrors, "vtgate-config-terse-errors", terseErrors, "prevent bind vars from escaping in returned errors")
 	fs.IntVar(&truncateErrorLen, "truncate-error-len", truncateErrorLen, "truncate errors sent to client if they are longer than this value (0 means do not truncate)")
 	fs.IntVar(&streamBufferSize, "stream_buffer_size", streamBufferSize, "the number of bytes sent from vtgate for each stream call. It's recommended to keep this value in sync with vttablet's query-server-config-stream-buffer-size.")
@@ -144,7 +170,7 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&enableOnlineDDL, "enable_online_ddl", enableOnlineDDL, "Allow users to submit, review and control Online DDL")
 	fs.BoolVar(&enableDirectDDL, "enable_direct_ddl", enableDirectDDL, "Allow users to submit direct DDL statements")
 	fs.BoolVar(&enableSchemaChangeSignal, "schema_change_signal", enableSchemaChangeSignal, "Enable the schema tracker; requires queryserver-config-schema-change-signal to be enabled on the underlying vttablets for this to work")
-	fs.IntVar(&queryTimeout, "query-timeout", queryTimeout, "Sets the default query timeout (in ms). Can be overridden by session variable (query_timeout) or comment directive (QUERY_TIMEOUT_MS)")
+	fs.IntVar(&queryTimeout, "query-timeout", queryTimeout, "Sets the default query timeout (in ms). Can be overridden by the keyspace setting (query_timeout_ms in the vschema), session variable (query_timeout) or comment directive (QUERY_TIMEOUT_MS)")
 	fs.StringVar(&queryLogToFile, "log_queries_to_file", queryLogToFile, "Enable query logging to the specified file")
 	fs.IntVar(&queryLogBufferSize, "querylog-buffer-size", queryLogBufferSize, "Maximum number of buffered query logs before throttling log output")
 	fs.DurationVar(&messageStreamGracePeriod, "message_stream_grace_period", messageStreamGracePeriod, "the amount of time to give for a vttablet to resume if it ends a message stream, usually because of a reparent.")
@@ -153,6 +179,15 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&warmingReadsPercent, "warming-reads-percent", 0, "Percentage of reads on the primary to forward to replicas. Useful for keeping buffer pools warm")
 	fs.IntVar(&warmingReadsConcurrency, "warming-reads-concurrency", 500, "Number of concurrent warming reads allowed")
 	fs.DurationVar(&warmingReadsQueryTimeout, "warming-reads-query-timeout", 5*time.Second, "Timeout of warming read queries")
+	fs.IntVar(&readRetryCount, "read-retry-count", readRetryCount, "Number of times a single-shard read outside of a transaction is retried when its tablet returns a transient error (0 disables the retries). Sessions can opt out with @@skip_read_retry")
+	fs.DurationVar(&readRetryInitialBackoff, "read-retry-initial-backoff", readRetryInitialBackoff, "Time to wait before the first retry of a failed read, doubled for every following retry")
+	fs.DurationVar(&readRetryMaxBackoff, "read-retry-max-backoff", readRetryMaxBackoff, "Maximum time to wait between two retries of a failed read")
+	fs.Int64Var(&sequenceBlockSize, "sequence-block-size", sequenceBlockSize, "Number of values vtgate reserves at once from a sequence and hands out from memory, for the auto-increment columns whose vschema doesn't set a block_size (0 reserves the values of every insert from the sequence table)")
+	fs.IntVar(&loadDataBatchSize, "load-data-batch-size", loadDataBatchSize, "Number of rows of a LOAD DATA LOCAL INFILE statement that are inserted into a shard with a single query")
+	fs.IntVar(&insertBatchRows, "insert-batch-rows", insertBatchRows, "Maximum number of rows of an INSERT that are inserted into a shard with a single query: the rows of the shard are then sorted by keyspace id and inserted in batches within a transaction (0 for no limit). Statements can override it with the INSERT_BATCH_ROWS directive")
+	fs.BoolVar(&readWriteSplitting, "read-write-splitting", readWriteSplitting, "Route the autocommit SELECTs that sessions send to the primary to the replicas. On the shards that the session wrote to, the replica first waits to have executed the GTID set of the last write of the session, and the read falls back to the primary when it doesn't. Statements can opt out with the READ_FROM_PRIMARY directive")
+	fs.DurationVar(&readWriteSplittingWaitTimeout, "read-write-splitting-wait-timeout", readWriteSplittingWaitTimeout, "Maximum time that a replica waits to have executed the last write of a session before the read that --read-write-splitting sent to it falls back to the primary")
+	fs.IntVar(&sequenceFetchRetries, "sequence-fetch-retries", sequenceFetchRetries, "Number of times vtgate retries reserving values from a sequence when its tablet returns a transient error, for example while it fails over")
 }
 
 func init() {
@@ -192,6 +227,11 @@ var (
 	vstreamSkewDelayCount = stats.NewCounter("VStreamEventsDelayedBySkewAlignment",
 		"Number of events that had to wait because the skew across shards was too high")
 
+	quotaRejections = stats.NewCountersWithMultiLabels(
+		"VtgateQuotaRejections",
+		"Queries rejected by the quota rules, by rule and exceeded limit",
+		[]string{"Rule", "Limit"})
+
 	vindexUnknownParams = stats.NewGauge("VindexUnknownParameters", "Number of parameters unrecognized by Vindexes")
 
 	timings = stats.NewMultiTimings(
@@ -358,6 +398,10 @@ func Init(
 			servenv.OnTermSync(srv.shutdownMysqlProtocolAndDrain)
 			servenv.OnClose(srv.rollbackAtShutdown)
 		}
+		if srv := initMysqlxProtocol(vtgateInst); srv != nil {
+			servenv.OnTermSync(srv.shutdownAndDrain)
+			servenv.OnClose(srv.rollbackAtShutdown)
+		}
 	})
 	servenv.OnTerm(func() {
 		if st != nil && enableSchemaChangeSignal {
@@ -446,7 +490,7 @@ func (vtg *VTGate) Execute(ctx context.Context, mysqlCtx vtgateservice.MySQLConn
 	// In this context, we don't care if we can't fully parse destination
 	destKeyspace, destTabletType, _, _ := vtg.executor.ParseDestinationTarget(session.TargetString)
 	statsKey := []string{"Execute", destKeyspace, topoproto.TabletTypeLString(destTabletType)}
-	defer vtg.timings.Record(statsKey, time.Now())
+	defer vtg.timings.Start(ctx, statsKey).Stop()
 
 	if bvErr := sqltypes.ValidateBindVariables(bindVariables); bvErr != nil {
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
@@ -475,7 +519,7 @@ func (vtg *VTGate) ExecuteBatch(ctx context.Context, session *vtgatepb.Session,
 	// In this context, we don't care if we can't fully parse destination
 	destKeyspace, destTabletType, _, _ := vtg.executor.ParseDestinationTarget(session.TargetString)
 	statsKey := []string{"ExecuteBatch", destKeyspace, topoproto.TabletTypeLString(destTabletType)}
-	defer vtg.timings.Record(statsKey, time.Now())
+	defer vtg.timings.Start(ctx, statsKey).Stop()
 
 	for _, bindVariables := range bindVariablesList {
 		if bvErr := sqltypes.ValidateBindVariables(bindVariables); bvErr != nil {
@@ -505,7 +549,7 @@ func (vtg *VTGate) StreamExecute(ctx context.Context, mysqlCtx vtgateservice.MyS
 	destKeyspace, destTabletType, _, _ := vtg.executor.ParseDestinationTarget(session.TargetString)
 	statsKey := []string{"StreamExecute", destKeyspace, topoproto.TabletTypeLString(destTabletType)}
 
-	defer vtg.timings.Record(statsKey, time.Now())
+	defer vtg.timings.Start(ctx, statsKey).Stop()
 
 	safeSession := NewSafeSession(session)
 	var err error
@@ -554,7 +598,7 @@ func (vtg *VTGate) Prepare(ctx context.Context, session *vtgatepb.Session, sql s
 	// In this context, we don't care if we can't fully parse destination
 	destKeyspace, destTabletType, _, _ := vtg.executor.ParseDestinationTarget(session.TargetString)
 	statsKey := []string{"Prepare", destKeyspace, topoproto.TabletTypeLString(destTabletType)}
-	defer vtg.timings.Record(statsKey, time.Now())
+	defer vtg.timings.Start(ctx, statsKey).Stop()
 
 	if bvErr := sqltypes.ValidateBindVariables(bindVariables); bvErr != nil {
 		err = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "%v", bvErr)
@@ -353,7 +353,7 @@ CREATE TABLE vitess_tablet (
 	cell varchar(128) NOT NULL,
 	tablet_type smallint(5) NOT NULL,
 	primary_timestamp timestamp NOT NULL,
-	info varchar(512) NOT NULL,
+	info blob NOT NULL,
 	PRIMARY KEY (alias)
 )`,
 	`
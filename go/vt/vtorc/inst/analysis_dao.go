@@ -24,8 +24,6 @@ import (
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/topo/topoproto"
 
-	"google.golang.org/protobuf/encoding/prototext"
-
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtctl/reparentutil"
@@ -307,17 +305,16 @@ func GetReplicationAnalysis(keyspace string, shard string, hints *ReplicationAna
 			ProcessingNodeToken:    util.ProcessToken.Hash,
 		}
 
-		tablet := &topodatapb.Tablet{}
-		opts := prototext.UnmarshalOptions{DiscardUnknown: true}
-		if err := opts.Unmarshal([]byte(m.GetString("tablet_info")), tablet); err != nil {
-			log.Errorf("could not read tablet %v: %v", m.GetString("tablet_info"), err)
+		tablet, err := unmarshalTablet(m.GetString("tablet_info"))
+		if err != nil {
+			log.Errorf("could not read tablet %v: %v", m.GetString("hostname"), err)
 			return nil
 		}
 
 		primaryTablet := &topodatapb.Tablet{}
 		if str := m.GetString("primary_tablet_info"); str != "" {
-			if err := opts.Unmarshal([]byte(str), primaryTablet); err != nil {
-				log.Errorf("could not read tablet %v: %v", str, err)
+			if primaryTablet, err = unmarshalTablet(str); err != nil {
+				log.Errorf("could not read the primary tablet of %v: %v", m.GetString("hostname"), err)
 				return nil
 			}
 		}
@@ -1077,6 +1077,7 @@ func ForgetInstance(tabletAlias string) error {
 		log.Error(err)
 		return err
 	}
+	tabletCache.invalidate(tabletAlias)
 
 	// Also delete from the 'database_instance' table.
 	sqlResult, err := db.ExecVTOrc(`
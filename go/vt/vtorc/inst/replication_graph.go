@@ -0,0 +1,214 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inst
+
+import (
+	"fmt"
+	"sort"
+
+	"vitess.io/vitess/go/vt/external/golib/sqlutils"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vtorc/db"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+// ReplicationGraphNode is a tablet of a shard's replication graph, along with
+// the replication status VTOrc last read from it.
+type ReplicationGraphNode struct {
+	TabletAlias string
+	TabletType  string
+	Hostname    string
+	Port        int
+	// SourceAlias is the alias of the tablet this tablet replicates from. It is
+	// empty if the tablet doesn't replicate, or replicates from a server that
+	// isn't a tablet of the shard.
+	SourceAlias string
+	SourceHost  string
+	SourcePort  int
+
+	ReadOnly                    bool
+	ReplicationIOThreadRunning  bool
+	ReplicationSQLThreadRunning bool
+	// ReplicationLagSeconds is nil when the lag is unknown.
+	ReplicationLagSeconds *int64
+	SQLDelay              uint32
+	ExecutedGtidSet       string
+	GtidErrant            string
+	IsLastCheckValid      bool
+	LastSeenTimestamp     string
+
+	// Problems are the problems of the instance, like "not_replicating".
+	Problems []string
+	// Analysis are the problems the replication analysis detected on the
+	// tablet, which VTOrc recovers from.
+	Analysis []AnalysisCode
+}
+
+// ReplicationGraphEdge is a replication stream between two tablets of a shard.
+type ReplicationGraphEdge struct {
+	SourceAlias  string
+	ReplicaAlias string
+}
+
+// ShardReplicationGraph is the analyzed replication topology of a shard: who
+// replicates from whom, and the problems detected on the way.
+type ShardReplicationGraph struct {
+	Keyspace string
+	Shard    string
+	// PrimaryAlias is the primary of the shard according to the shard record.
+	PrimaryAlias string
+	Nodes        []*ReplicationGraphNode
+	Edges        []*ReplicationGraphEdge
+}
+
+// ReadShardReplicationGraphs returns the replication graphs of the shards,
+// sorted by keyspace and shard. The keyspace and the shard are optional filters.
+func ReadShardReplicationGraphs(keyspace string, shard string) ([]*ShardReplicationGraph, error) {
+	condition := `
+			keyspace LIKE (CASE WHEN ? = '' THEN '%' ELSE ? END)
+			and shard LIKE (CASE WHEN ? = '' THEN '%' ELSE ? END)
+		`
+	args := sqlutils.Args(keyspace, keyspace, shard, shard)
+
+	// The instances don't hold the keyspace, the shard and the type of the tablets,
+	// so we read them from the vitess_tablet table.
+	type tabletPlacement struct {
+		keyspace   string
+		shard      string
+		tabletType string
+	}
+	placements := make(map[string]tabletPlacement)
+	query := `
+		select
+			alias,
+			keyspace,
+			shard,
+			tablet_type
+		from
+			vitess_tablet
+		where
+			` + condition
+	err := db.QueryVTOrc(query, args, func(row sqlutils.RowMap) error {
+		placements[row.GetString("alias")] = tabletPlacement{
+			keyspace:   row.GetString("keyspace"),
+			shard:      row.GetString("shard"),
+			tabletType: topoproto.TabletTypeLString(topodatapb.TabletType(row.GetInt32("tablet_type"))),
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	instances, err := readInstancesByCondition(condition, args, "")
+	if err != nil {
+		return nil, err
+	}
+	analysis, err := GetReplicationAnalysis(keyspace, shard, &ReplicationAnalysisHints{})
+	if err != nil {
+		return nil, err
+	}
+	analysisByTablet := make(map[string][]AnalysisCode)
+	for _, entry := range analysis {
+		if entry.Analysis != NoProblem {
+			analysisByTablet[entry.AnalyzedInstanceAlias] = append(analysisByTablet[entry.AnalyzedInstanceAlias], entry.Analysis)
+		}
+	}
+
+	graphs := make(map[string]*ShardReplicationGraph)
+	aliasByAddress := make(map[string]string)
+	for _, instance := range instances {
+		placement, ok := placements[instance.InstanceAlias]
+		if !ok {
+			// The tablet was forgotten between the two reads.
+			continue
+		}
+		keyspaceShard := getKeyspaceShardName(placement.keyspace, placement.shard)
+		graph, ok := graphs[keyspaceShard]
+		if !ok {
+			graph = &ShardReplicationGraph{
+				Keyspace: placement.keyspace,
+				Shard:    placement.shard,
+			}
+			graph.PrimaryAlias, _, err = ReadShardPrimaryInformation(placement.keyspace, placement.shard)
+			if err != nil && err != ErrShardNotFound {
+				return nil, err
+			}
+			graphs[keyspaceShard] = graph
+		}
+		node := &ReplicationGraphNode{
+			TabletAlias:                 instance.InstanceAlias,
+			TabletType:                  placement.tabletType,
+			Hostname:                    instance.Hostname,
+			Port:                        instance.Port,
+			SourceHost:                  instance.SourceHost,
+			SourcePort:                  instance.SourcePort,
+			ReadOnly:                    instance.ReadOnly,
+			ReplicationIOThreadRunning:  instance.ReplicationIOThreadRuning,
+			ReplicationSQLThreadRunning: instance.ReplicationSQLThreadRuning,
+			SQLDelay:                    instance.SQLDelay,
+			ExecutedGtidSet:             instance.ExecutedGtidSet,
+			GtidErrant:                  instance.GtidErrant,
+			IsLastCheckValid:            instance.IsLastCheckValid,
+			LastSeenTimestamp:           instance.LastSeenTimestamp,
+			Problems:                    instance.Problems,
+			Analysis:                    analysisByTablet[instance.InstanceAlias],
+		}
+		if instance.ReplicationLagSeconds.Valid {
+			lag := instance.ReplicationLagSeconds.Int64
+			node.ReplicationLagSeconds = &lag
+		}
+		graph.Nodes = append(graph.Nodes, node)
+		aliasByAddress[replicationGraphAddress(keyspaceShard, instance.Hostname, instance.Port)] = instance.InstanceAlias
+	}
+
+	var result []*ShardReplicationGraph
+	for keyspaceShard, graph := range graphs {
+		sort.Slice(graph.Nodes, func(i, j int) bool {
+			return graph.Nodes[i].TabletAlias < graph.Nodes[j].TabletAlias
+		})
+		for _, node := range graph.Nodes {
+			if node.SourceHost == "" {
+				continue
+			}
+			node.SourceAlias = aliasByAddress[replicationGraphAddress(keyspaceShard, node.SourceHost, node.SourcePort)]
+			if node.SourceAlias != "" && node.SourceAlias != node.TabletAlias {
+				graph.Edges = append(graph.Edges, &ReplicationGraphEdge{
+					SourceAlias:  node.SourceAlias,
+					ReplicaAlias: node.TabletAlias,
+				})
+			}
+		}
+		result = append(result, graph)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Keyspace != result[j].Keyspace {
+			return result[i].Keyspace < result[j].Keyspace
+		}
+		return result[i].Shard < result[j].Shard
+	})
+	return result, nil
+}
+
+// replicationGraphAddress returns the key under which the tablets of a shard
+// are looked up by their MySQL address.
+func replicationGraphAddress(keyspaceShard string, hostname string, port int) string {
+	return fmt.Sprintf("%s/%s:%d", keyspaceShard, hostname, port)
+}
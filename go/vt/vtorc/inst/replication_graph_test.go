@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inst
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/vtorc/config"
+	"vitess.io/vitess/go/vt/vtorc/db"
+)
+
+// TestReadShardReplicationGraphs tests that ReadShardReplicationGraphs links the tablets of a shard to their sources,
+// and reports the problems detected on them.
+func TestReadShardReplicationGraphs(t *testing.T) {
+	// We need to set InstancePollSeconds to a large value otherwise all the instances are reported as having problems since their last_checked is very old.
+	oldVal := config.Config.InstancePollSeconds
+	defer func() {
+		config.Config.InstancePollSeconds = oldVal
+	}()
+	config.Config.InstancePollSeconds = 60 * 60 * 24 * 365 * 100
+
+	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+	defer func() {
+		db.ClearVTOrcDatabase()
+	}()
+	// Check initialSQL for more details. All the replicas replicate from zone1-0000000101.
+	sql := append(initialSQL, "update database_instance set replication_sql_thread_state = 0, replica_sql_running = 0 where alias = 'zone1-0000000112'")
+	for _, query := range sql {
+		_, err := db.ExecVTOrc(query)
+		require.NoError(t, err)
+	}
+
+	graphs, err := ReadShardReplicationGraphs("ks", "0")
+	require.NoError(t, err)
+	require.Len(t, graphs, 1)
+	graph := graphs[0]
+	require.Equal(t, "ks", graph.Keyspace)
+	require.Equal(t, "0", graph.Shard)
+	require.Equal(t, "zone1-0000000101", graph.PrimaryAlias)
+
+	var tabletAliases []string
+	nodes := make(map[string]*ReplicationGraphNode)
+	for _, node := range graph.Nodes {
+		tabletAliases = append(tabletAliases, node.TabletAlias)
+		nodes[node.TabletAlias] = node
+	}
+	require.Equal(t, []string{"zone1-0000000100", "zone1-0000000101", "zone1-0000000112", "zone2-0000000200"}, tabletAliases)
+	require.Equal(t, "primary", nodes["zone1-0000000101"].TabletType)
+	require.Equal(t, "rdonly", nodes["zone1-0000000112"].TabletType)
+	require.Empty(t, nodes["zone1-0000000101"].SourceAlias)
+	require.Equal(t, "729a4cc4-8680-11ed-a104-47706090afbd:1-54", nodes["zone1-0000000101"].ExecutedGtidSet)
+
+	require.ElementsMatch(t, []*ReplicationGraphEdge{
+		{SourceAlias: "zone1-0000000101", ReplicaAlias: "zone1-0000000100"},
+		{SourceAlias: "zone1-0000000101", ReplicaAlias: "zone1-0000000112"},
+		{SourceAlias: "zone1-0000000101", ReplicaAlias: "zone2-0000000200"},
+	}, graph.Edges)
+
+	require.Equal(t, []string{"not_replicating"}, nodes["zone1-0000000112"].Problems)
+	require.Equal(t, []AnalysisCode{ReplicationStopped}, nodes["zone1-0000000112"].Analysis)
+	require.Empty(t, nodes["zone1-0000000100"].Problems)
+	require.Empty(t, nodes["zone1-0000000100"].Analysis)
+
+	// Filtering on another shard returns nothing.
+	graphs, err = ReadShardReplicationGraphs("ks", "-80")
+	require.NoError(t, err)
+	require.Empty(t, graphs)
+}
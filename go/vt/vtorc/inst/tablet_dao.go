@@ -19,8 +19,8 @@ package inst
 import (
 	"context"
 	"errors"
-
-	"google.golang.org/protobuf/encoding/prototext"
+	"strings"
+	"sync"
 
 	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/vt/external/golib/sqlutils"
@@ -54,36 +54,146 @@ func fullStatus(tabletAlias string) (*replicationdatapb.FullStatus, error) {
 	return tmc.FullStatus(tmcCtx, tablet)
 }
 
+// tabletCache caches the tablet records of the vitess_tablet table, keyed by
+// tablet alias, so that the analysis and the recoveries don't read and
+// unmarshal them on every call. The discovery loop keeps it up to date:
+// SaveTablet replaces the cached tablet, and ForgetInstance invalidates it.
+var tabletCache = newTabletRecordCache()
+
+// readTabletsBatchSize is the maximum number of tablets that ReadTablets
+// reads with a single query.
+const readTabletsBatchSize = 500
+
+// tabletRecordCache is an in-memory cache of tablet records. It hands out
+// copies, so that callers can't modify the cached tablets.
+type tabletRecordCache struct {
+	mu      sync.RWMutex
+	tablets map[string]*topodatapb.Tablet
+}
+
+func newTabletRecordCache() *tabletRecordCache {
+	return &tabletRecordCache{tablets: make(map[string]*topodatapb.Tablet)}
+}
+
+func (c *tabletRecordCache) get(tabletAlias string) (*topodatapb.Tablet, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	tablet, ok := c.tablets[tabletAlias]
+	if !ok {
+		return nil, false
+	}
+	return tablet.CloneVT(), true
+}
+
+func (c *tabletRecordCache) set(tabletAlias string, tablet *topodatapb.Tablet) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tablets[tabletAlias] = tablet.CloneVT()
+}
+
+func (c *tabletRecordCache) invalidate(tabletAliases ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tabletAlias := range tabletAliases {
+		delete(c.tablets, tabletAlias)
+	}
+}
+
+func (c *tabletRecordCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tablets = make(map[string]*topodatapb.Tablet)
+}
+
+// InvalidateTabletCache removes the given tablets from the tablet cache, or
+// all the tablets if none is given. The next reads of these tablets go to
+// the vitess_tablet table.
+func InvalidateTabletCache(tabletAliases ...string) {
+	if len(tabletAliases) == 0 {
+		tabletCache.clear()
+		return
+	}
+	tabletCache.invalidate(tabletAliases...)
+}
+
+// unmarshalTablet decodes a tablet record of the vitess_tablet table.
+func unmarshalTablet(info string) (*topodatapb.Tablet, error) {
+	tablet := &topodatapb.Tablet{}
+	if err := tablet.UnmarshalVT([]byte(info)); err != nil {
+		return nil, err
+	}
+	return tablet, nil
+}
+
 // ReadTablet reads the vitess tablet record.
 func ReadTablet(tabletAlias string) (*topodatapb.Tablet, error) {
-	query := `
-		select
-			info
-		from
-			vitess_tablet
-		where alias = ?
-		`
-	args := sqlutils.Args(tabletAlias)
-	tablet := &topodatapb.Tablet{}
-	opts := prototext.UnmarshalOptions{DiscardUnknown: true}
-	err := db.QueryVTOrc(query, args, func(row sqlutils.RowMap) error {
-		return opts.Unmarshal([]byte(row.GetString("info")), tablet)
-	})
+	tablets, err := ReadTablets([]string{tabletAlias})
 	if err != nil {
 		return nil, err
 	}
-	if tablet.Alias == nil {
+	tablet, ok := tablets[tabletAlias]
+	if !ok || tablet.Alias == nil {
 		return nil, ErrTabletAliasNil
 	}
 	return tablet, nil
 }
 
+// ReadTablets reads the vitess tablet records of the given tablets, keyed by
+// tablet alias. The tablets that are not found are missing from the result.
+// The cached tablets are not read again, and the others are read in batches.
+func ReadTablets(tabletAliases []string) (map[string]*topodatapb.Tablet, error) {
+	tablets := make(map[string]*topodatapb.Tablet, len(tabletAliases))
+	var toRead []string
+	for _, tabletAlias := range tabletAliases {
+		if tablet, ok := tabletCache.get(tabletAlias); ok {
+			tablets[tabletAlias] = tablet
+			continue
+		}
+		toRead = append(toRead, tabletAlias)
+	}
+
+	for len(toRead) > 0 {
+		batch := toRead[:min(len(toRead), readTabletsBatchSize)]
+		toRead = toRead[len(batch):]
+
+		query := `
+		select
+			alias,
+			info
+		from
+			vitess_tablet
+		where alias in (?` + strings.Repeat(", ?", len(batch)-1) + `)
+		`
+		args := make([]any, 0, len(batch))
+		for _, tabletAlias := range batch {
+			args = append(args, tabletAlias)
+		}
+		err := db.QueryVTOrc(query, args, func(row sqlutils.RowMap) error {
+			tabletAlias := row.GetString("alias")
+			tablet, err := unmarshalTablet(row.GetString("info"))
+			if err != nil {
+				return err
+			}
+			tablets[tabletAlias] = tablet
+			if tablet.Alias != nil {
+				tabletCache.set(tabletAlias, tablet)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tablets, nil
+}
+
 // SaveTablet saves the tablet record against the instanceKey.
 func SaveTablet(tablet *topodatapb.Tablet) error {
-	tabletp, err := prototext.Marshal(tablet)
+	tabletp, err := tablet.MarshalVT()
 	if err != nil {
 		return err
 	}
+	tabletAlias := topoproto.TabletAliasString(tablet.Alias)
 	_, err = db.ExecVTOrc(`
 		replace
 			into vitess_tablet (
@@ -92,7 +202,7 @@ func SaveTablet(tablet *topodatapb.Tablet) error {
 				?, ?, ?, ?, ?, ?, ?, ?, ?
 			)
 		`,
-		topoproto.TabletAliasString(tablet.Alias),
+		tabletAlias,
 		tablet.MysqlHostname,
 		int(tablet.MysqlPort),
 		tablet.Alias.Cell,
@@ -102,5 +212,10 @@ func SaveTablet(tablet *topodatapb.Tablet) error {
 		protoutil.TimeFromProto(tablet.PrimaryTermStartTime).UTC(),
 		tabletp,
 	)
-	return err
+	if err != nil {
+		tabletCache.invalidate(tabletAlias)
+		return err
+	}
+	tabletCache.set(tabletAlias, tablet)
+	return nil
 }
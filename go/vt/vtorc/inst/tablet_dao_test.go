@@ -91,3 +91,54 @@ func TestSaveAndReadTablet(t *testing.T) {
 		})
 	}
 }
+
+func TestReadTablets(t *testing.T) {
+	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
+	defer func() {
+		db.ClearVTOrcDatabase()
+		InvalidateTabletCache()
+	}()
+
+	var tabletAliases []string
+	for uid := uint32(100); uid < 100+readTabletsBatchSize+5; uid++ {
+		tablet := &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: uid},
+			Keyspace: "ks",
+			Shard:    "0",
+			Type:     topodatapb.TabletType_REPLICA,
+		}
+		require.NoError(t, SaveTablet(tablet))
+		tabletAliases = append(tabletAliases, topoproto.TabletAliasString(tablet.Alias))
+	}
+
+	// The tablets are read from the database, in more than one batch.
+	InvalidateTabletCache()
+	tablets, err := ReadTablets(append(tabletAliases, "zone1-0000000099"))
+	require.NoError(t, err)
+	require.Len(t, tablets, len(tabletAliases))
+	for _, tabletAlias := range tabletAliases {
+		require.Equal(t, tabletAlias, topoproto.TabletAliasString(tablets[tabletAlias].Alias))
+	}
+
+	// The cached tablets are copies, that callers can't modify.
+	tablets["zone1-0000000100"].Type = topodatapb.TabletType_PRIMARY
+	tablet, err := ReadTablet("zone1-0000000100")
+	require.NoError(t, err)
+	require.Equal(t, topodatapb.TabletType_REPLICA, tablet.Type)
+
+	// A tablet that is changed in the database behind the cache's back is
+	// only read again once it is invalidated.
+	_, err = db.ExecVTOrc("update vitess_tablet set info = ? where alias = ?", "", "zone1-0000000100")
+	require.NoError(t, err)
+	_, err = ReadTablet("zone1-0000000100")
+	require.NoError(t, err)
+	InvalidateTabletCache("zone1-0000000100")
+	_, err = ReadTablet("zone1-0000000100")
+	require.EqualError(t, err, ErrTabletAliasNil.Error())
+
+	// Forgetting a tablet removes it from the cache. There is no
+	// database_instance row for it, so ForgetInstance reports it not found.
+	require.EqualError(t, ForgetInstance("zone1-0000000101"), "ForgetInstance(): tablet zone1-0000000101 not found")
+	_, err = ReadTablet("zone1-0000000101")
+	require.EqualError(t, err, ErrTabletAliasNil.Error())
+}
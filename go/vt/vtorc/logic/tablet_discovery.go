@@ -28,7 +28,6 @@ import (
 
 	"github.com/spf13/pflag"
 
-	"google.golang.org/protobuf/encoding/prototext"
 	"google.golang.org/protobuf/proto"
 
 	"vitess.io/vitess/go/vt/external/golib/sqlutils"
@@ -71,6 +70,7 @@ func OpenTabletDiscovery() <-chan time.Time {
 	if _, err := db.ExecVTOrc("delete from vitess_tablet"); err != nil {
 		log.Error(err)
 	}
+	inst.InvalidateTabletCache()
 	// We refresh all information from the topo once before we start the ticks to do it on a timer.
 	populateAllInformation()
 	return time.Tick(time.Second * time.Duration(config.Config.TopoInformationRefreshSeconds)) //nolint SA1015: using time.Tick leaks the underlying ticker
@@ -205,7 +205,7 @@ func refreshTabletsInKeyspaceShard(ctx context.Context, keyspace, shard string,
 func refreshTablets(tablets map[string]*topo.TabletInfo, query string, args []any, loader func(tabletAlias string), forceRefresh bool, tabletsToIgnore []string) {
 	// Discover new tablets.
 	latestInstances := make(map[string]bool)
-	var wg sync.WaitGroup
+	var tabletAliases []string
 	for _, tabletInfo := range tablets {
 		tablet := tabletInfo.Tablet
 		if tablet.Type != topodatapb.TabletType_PRIMARY && !topo.IsReplicaType(tablet.Type) {
@@ -213,12 +213,24 @@ func refreshTablets(tablets map[string]*topo.TabletInfo, query string, args []an
 		}
 		tabletAliasString := topoproto.TabletAliasString(tablet.Alias)
 		latestInstances[tabletAliasString] = true
-		old, err := inst.ReadTablet(tabletAliasString)
-		if err != nil && err != inst.ErrTabletAliasNil {
-			log.Error(err)
+		tabletAliases = append(tabletAliases, tabletAliasString)
+	}
+	// Read the stored records of all the tablets at once, to only save
+	// and load the tablets that changed.
+	oldTablets, err := inst.ReadTablets(tabletAliases)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, tabletInfo := range tablets {
+		tablet := tabletInfo.Tablet
+		tabletAliasString := topoproto.TabletAliasString(tablet.Alias)
+		if !latestInstances[tabletAliasString] {
 			continue
 		}
-		if !forceRefresh && proto.Equal(tablet, old) {
+		if !forceRefresh && proto.Equal(tablet, oldTablets[tabletAliasString]) {
 			continue
 		}
 		if err := inst.SaveTablet(tablet); err != nil {
@@ -239,7 +251,7 @@ func refreshTablets(tablets map[string]*topo.TabletInfo, query string, args []an
 
 	// Forget tablets that were removed.
 	var toForget []string
-	err := db.QueryVTOrc(query, args, func(row sqlutils.RowMap) error {
+	err = db.QueryVTOrc(query, args, func(row sqlutils.RowMap) error {
 		tabletAlias := row.GetString("alias")
 		if !latestInstances[tabletAlias] {
 			toForget = append(toForget, tabletAlias)
@@ -328,8 +340,7 @@ func shardPrimary(keyspace string, shard string) (primary *topodatapb.Tablet, er
 	err = db.Db.QueryVTOrc(query, sqlutils.Args(keyspace, shard, topodatapb.TabletType_PRIMARY), func(m sqlutils.RowMap) error {
 		if primary == nil {
 			primary = &topodatapb.Tablet{}
-			opts := prototext.UnmarshalOptions{DiscardUnknown: true}
-			return opts.Unmarshal([]byte(m.GetString("info")), primary)
+			return primary.UnmarshalVT([]byte(m.GetString("info")))
 		}
 		return nil
 	})
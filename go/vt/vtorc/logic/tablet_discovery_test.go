@@ -109,6 +109,7 @@ func TestRefreshTabletsInKeyspaceShard(t *testing.T) {
 	// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
 	defer func() {
 		db.ClearVTOrcDatabase()
+		inst.InvalidateTabletCache()
 	}()
 
 	// Create a memory topo-server and create the keyspace and shard records
@@ -244,6 +245,7 @@ func TestShardPrimary(t *testing.T) {
 			// Clear the database after the test. The easiest way to do that is to run all the initialization commands again.
 			defer func() {
 				db.ClearVTOrcDatabase()
+				inst.InvalidateTabletCache()
 			}()
 
 			// Create a memory topo-server and create the keyspace and shard records
@@ -106,6 +106,7 @@ func TestElectNewPrimaryPanic(t *testing.T) {
 		ts = oldTs
 		_, err = orcDb.Exec("delete from vitess_tablet")
 		require.NoError(t, err)
+		inst.InvalidateTabletCache()
 	}()
 
 	tablet := &topodatapb.Tablet{
@@ -142,6 +143,7 @@ func TestDifferentAnalysescHaveDifferentCooldowns(t *testing.T) {
 		ts = oldTs
 		_, err = orcDb.Exec("delete from vitess_tablet")
 		require.NoError(t, err)
+		inst.InvalidateTabletCache()
 	}()
 
 	primary := &topodatapb.Tablet{
@@ -45,6 +45,7 @@ const (
 	disableGlobalRecoveriesAPI    = "/api/disable-global-recoveries"
 	enableGlobalRecoveriesAPI     = "/api/enable-global-recoveries"
 	replicationAnalysisAPI        = "/api/replication-analysis"
+	replicationGraphAPI           = "/api/replication-graph"
 	healthAPI                     = "/debug/health"
 	AggregatedDiscoveryMetricsAPI = "/api/aggregated-discovery-metrics"
 
@@ -60,6 +61,7 @@ var (
 		disableGlobalRecoveriesAPI,
 		enableGlobalRecoveriesAPI,
 		replicationAnalysisAPI,
+		replicationGraphAPI,
 		healthAPI,
 		AggregatedDiscoveryMetricsAPI,
 	}
@@ -86,6 +88,8 @@ func (v *vtorcAPI) ServeHTTP(response http.ResponseWriter, request *http.Request
 		errantGTIDsAPIHandler(response, request)
 	case replicationAnalysisAPI:
 		replicationAnalysisAPIHandler(response, request)
+	case replicationGraphAPI:
+		replicationGraphAPIHandler(response, request)
 	case AggregatedDiscoveryMetricsAPI:
 		AggregatedDiscoveryMetricsAPIHandler(response, request)
 	default:
@@ -102,7 +106,7 @@ func getACLPermissionLevelForAPI(apiEndpoint string) string {
 		return acl.MONITORING
 	case disableGlobalRecoveriesAPI, enableGlobalRecoveriesAPI:
 		return acl.ADMIN
-	case replicationAnalysisAPI:
+	case replicationAnalysisAPI, replicationGraphAPI:
 		return acl.MONITORING
 	case healthAPI:
 		return acl.MONITORING
@@ -231,6 +235,23 @@ func replicationAnalysisAPIHandler(response http.ResponseWriter, request *http.R
 	returnAsJSON(response, http.StatusOK, analysis)
 }
 
+// replicationGraphAPIHandler is the handler for the replicationGraphAPI endpoint
+func replicationGraphAPIHandler(response http.ResponseWriter, request *http.Request) {
+	// This api also supports filtering by shard and keyspace provided.
+	shard := request.URL.Query().Get("shard")
+	keyspace := request.URL.Query().Get("keyspace")
+	if shard != "" && keyspace == "" {
+		http.Error(response, shardWithoutKeyspaceFilteringErrorStr, http.StatusBadRequest)
+		return
+	}
+	graphs, err := inst.ReadShardReplicationGraphs(keyspace, shard)
+	if err != nil {
+		http.Error(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	returnAsJSON(response, http.StatusOK, graphs)
+}
+
 // healthAPIHandler is the handler for the healthAPI endpoint
 func healthAPIHandler(response http.ResponseWriter, request *http.Request) {
 	health, err := process.HealthTest()
@@ -20,8 +20,6 @@ import (
 	"fmt"
 	"time"
 
-	"google.golang.org/protobuf/encoding/prototext"
-
 	"vitess.io/vitess/go/vt/external/golib/sqlutils"
 
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -137,7 +135,7 @@ func (info *InfoForRecoveryAnalysis) ConvertToRowMap() sqlutils.RowMap {
 	if info.PrimaryTabletInfo == nil {
 		rowMap["primary_tablet_info"] = sqlutils.CellData{Valid: false}
 	} else {
-		res, _ := prototext.Marshal(info.PrimaryTabletInfo)
+		res, _ := info.PrimaryTabletInfo.MarshalVT()
 		rowMap["primary_tablet_info"] = sqlutils.CellData{String: string(res), Valid: true}
 	}
 	rowMap["primary_timestamp"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.PrimaryTimestamp), Valid: true}
@@ -150,7 +148,7 @@ func (info *InfoForRecoveryAnalysis) ConvertToRowMap() sqlutils.RowMap {
 	rowMap["semi_sync_primary_status"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.SemiSyncPrimaryStatus), Valid: true}
 	rowMap["semi_sync_primary_wait_for_replica_count"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.SemiSyncPrimaryWaitForReplicaCount), Valid: true}
 	rowMap["semi_sync_replica_enabled"] = sqlutils.CellData{String: fmt.Sprintf("%v", info.SemiSyncReplicaEnabled), Valid: true}
-	res, _ := prototext.Marshal(info.TabletInfo)
+	res, _ := info.TabletInfo.MarshalVT()
 	rowMap["tablet_info"] = sqlutils.CellData{String: string(res), Valid: true}
 	return rowMap
 }
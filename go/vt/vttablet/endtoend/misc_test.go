@@ -251,6 +251,7 @@ func TestSidecarTables(t *testing.T) {
 		"redo_statement",
 		"dt_state",
 		"dt_participant",
+		"dt_audit",
 	} {
 		_, err = conn.ExecuteFetch(fmt.Sprintf("describe _vt.%s", table), 10, false)
 		if err != nil {
@@ -207,6 +207,16 @@ func (client *FakeTabletManagerClient) ExecuteFetchAsApp(ctx context.Context, ta
 	return &querypb.QueryResult{}, nil
 }
 
+// KillQueries is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) KillQueries(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.KillQueriesRequest) (*tabletmanagerdatapb.KillQueriesResponse, error) {
+	return &tabletmanagerdatapb.KillQueriesResponse{}, nil
+}
+
+// UpdateQueryFingerprintRules is part of the tmclient.TabletManagerClient interface.
+func (client *FakeTabletManagerClient) UpdateQueryFingerprintRules(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest) (*tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse, error) {
+	return &tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse{}, nil
+}
+
 //
 // Replication related methods
 //
@@ -366,6 +376,34 @@ func (client *FakeTabletManagerClient) CheckThrottler(ctx context.Context, table
 	return &tabletmanagerdatapb.CheckThrottlerResponse{}, nil
 }
 
+// Distributed transaction related methods
+
+func (client *FakeTabletManagerClient) GetUnresolvedTransactions(ctx context.Context, tablet *topodatapb.Tablet, abandonAge int64) ([]*querypb.TransactionMetadata, error) {
+	return nil, nil
+}
+
+func (client *FakeTabletManagerClient) ResolveTransaction(ctx context.Context, tablet *topodatapb.Tablet, dtid string) error {
+	return nil
+}
+
+// Messaging related methods
+
+func (client *FakeTabletManagerClient) RequeueDeadLetters(ctx context.Context, tablet *topodatapb.Tablet, table string, ids []string) (int64, error) {
+	return 0, nil
+}
+
+// Diagnostics related methods
+
+func (client *FakeTabletManagerClient) RunDiagnosticQuery(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RunDiagnosticQueryRequest) (*tabletmanagerdatapb.RunDiagnosticQueryResponse, error) {
+	return &tabletmanagerdatapb.RunDiagnosticQueryResponse{}, nil
+}
+
+// MySQL upgrade related methods
+
+func (client *FakeTabletManagerClient) UpgradeMysql(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpgradeMysqlRequest) (*tabletmanagerdatapb.UpgradeMysqlResponse, error) {
+	return &tabletmanagerdatapb.UpgradeMysqlResponse{}, nil
+}
+
 //
 // Management related methods
 //
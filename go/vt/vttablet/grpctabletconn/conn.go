@@ -46,6 +46,8 @@ var (
 	ca   string
 	crl  string
 	name string
+
+	vstreamCompression string
 )
 
 func registerFlags(fs *pflag.FlagSet) {
@@ -54,6 +56,16 @@ func registerFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&ca, "tablet_grpc_ca", ca, "the server ca to use to validate servers when connecting")
 	fs.StringVar(&crl, "tablet_grpc_crl", crl, "the server crl to use to validate server certificates when connecting")
 	fs.StringVar(&name, "tablet_grpc_server_name", name, "the server name to use to validate server certificate")
+	fs.StringVar(&vstreamCompression, "vstream_grpc_compression", vstreamCompression, "Which protocol to use for compressing the events of VStream, VStreamRows and VStreamTables calls to tablets, overriding --grpc_compression. Default: nothing. Supported: snappy, zstd")
+}
+
+// vstreamCallOptions returns the call options of the VStream calls.
+func vstreamCallOptions() []grpc.CallOption {
+	switch vstreamCompression {
+	case "snappy", "zstd":
+		return []grpc.CallOption{grpc.UseCompressor(vstreamCompression)}
+	}
+	return nil
 }
 
 func init() {
@@ -672,7 +684,7 @@ func (conn *gRPCQueryClient) VStream(ctx context.Context, request *binlogdatapb.
 			Filter:            request.Filter,
 			TableLastPKs:      request.TableLastPKs,
 		}
-		stream, err := conn.c.VStream(ctx, req)
+		stream, err := conn.c.VStream(ctx, req, vstreamCallOptions()...)
 		if err != nil {
 			return nil, tabletconn.ErrorFromGRPC(err)
 		}
@@ -719,7 +731,7 @@ func (conn *gRPCQueryClient) VStreamRows(ctx context.Context, request *binlogdat
 			Query:             request.Query,
 			Lastpk:            request.Lastpk,
 		}
-		stream, err := conn.c.VStreamRows(ctx, req)
+		stream, err := conn.c.VStreamRows(ctx, req, vstreamCallOptions()...)
 		if err != nil {
 			return nil, tabletconn.ErrorFromGRPC(err)
 		}
@@ -762,7 +774,7 @@ func (conn *gRPCQueryClient) VStreamTables(ctx context.Context, request *binlogd
 			EffectiveCallerId: callerid.EffectiveCallerIDFromContext(ctx),
 			ImmediateCallerId: callerid.ImmediateCallerIDFromContext(ctx),
 		}
-		stream, err := conn.c.VStreamTables(ctx, req)
+		stream, err := conn.c.VStreamTables(ctx, req, vstreamCallOptions()...)
 		if err != nil {
 			return nil, tabletconn.ErrorFromGRPC(err)
 		}
@@ -587,6 +587,28 @@ func (client *Client) ExecuteFetchAsApp(ctx context.Context, tablet *topodatapb.
 	return response.Result, nil
 }
 
+// KillQueries is part of the tmclient.TabletManagerClient interface.
+func (client *Client) KillQueries(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.KillQueriesRequest) (*tabletmanagerdatapb.KillQueriesResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	return c.KillQueries(ctx, req)
+}
+
+// UpdateQueryFingerprintRules is part of the tmclient.TabletManagerClient interface.
+func (client *Client) UpdateQueryFingerprintRules(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest) (*tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	return c.UpdateQueryFingerprintRules(ctx, req)
+}
+
 //
 // Replication related methods
 //
@@ -1125,6 +1147,93 @@ func (client *Client) CheckThrottler(ctx context.Context, tablet *topodatapb.Tab
 	return response, nil
 }
 
+//
+// Distributed transaction related methods
+//
+
+// GetUnresolvedTransactions is part of the tmclient.TabletManagerClient interface.
+func (client *Client) GetUnresolvedTransactions(ctx context.Context, tablet *topodatapb.Tablet, abandonAge int64) ([]*querypb.TransactionMetadata, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	response, err := c.GetUnresolvedTransactions(ctx, &tabletmanagerdatapb.GetUnresolvedTransactionsRequest{
+		AbandonAge: abandonAge,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.Transactions, nil
+}
+
+// ResolveTransaction is part of the tmclient.TabletManagerClient interface.
+func (client *Client) ResolveTransaction(ctx context.Context, tablet *topodatapb.Tablet, dtid string) error {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	_, err = c.ResolveTransaction(ctx, &tabletmanagerdatapb.ResolveTransactionRequest{
+		Dtid: dtid,
+	})
+	return err
+}
+
+//
+// Messaging related methods
+//
+
+// RequeueDeadLetters is part of the tmclient.TabletManagerClient interface.
+func (client *Client) RequeueDeadLetters(ctx context.Context, tablet *topodatapb.Tablet, table string, ids []string) (int64, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return 0, err
+	}
+	defer closer.Close()
+
+	response, err := c.RequeueDeadLetters(ctx, &tabletmanagerdatapb.RequeueDeadLettersRequest{
+		Table: table,
+		Ids:   ids,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return response.Count, nil
+}
+
+//
+// Diagnostics related methods
+//
+
+// RunDiagnosticQuery is part of the tmclient.TabletManagerClient interface.
+func (client *Client) RunDiagnosticQuery(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RunDiagnosticQueryRequest) (*tabletmanagerdatapb.RunDiagnosticQueryResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	return c.RunDiagnosticQuery(ctx, req)
+}
+
+//
+// MySQL upgrade related methods
+//
+
+// UpgradeMysql is part of the tmclient.TabletManagerClient interface.
+func (client *Client) UpgradeMysql(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpgradeMysqlRequest) (*tabletmanagerdatapb.UpgradeMysqlResponse, error) {
+	c, closer, err := client.dialer.dial(ctx, tablet)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	return c.UpgradeMysql(ctx, req)
+}
+
 type restoreFromBackupStreamAdapter struct {
 	stream tabletmanagerservicepb.TabletManager_RestoreFromBackupClient
 	closer io.Closer
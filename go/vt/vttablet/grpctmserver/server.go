@@ -264,6 +264,18 @@ func (s *server) ExecuteFetchAsApp(ctx context.Context, request *tabletmanagerda
 	return response, nil
 }
 
+func (s *server) KillQueries(ctx context.Context, request *tabletmanagerdatapb.KillQueriesRequest) (response *tabletmanagerdatapb.KillQueriesResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "KillQueries", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	return s.tm.KillQueries(ctx, request)
+}
+
+func (s *server) UpdateQueryFingerprintRules(ctx context.Context, request *tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest) (response *tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "UpdateQueryFingerprintRules", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	return s.tm.UpdateQueryFingerprintRules(ctx, request)
+}
+
 //
 // Replication related methods
 //
@@ -585,6 +597,42 @@ func (s *server) CheckThrottler(ctx context.Context, request *tabletmanagerdatap
 	return response, err
 }
 
+func (s *server) GetUnresolvedTransactions(ctx context.Context, request *tabletmanagerdatapb.GetUnresolvedTransactionsRequest) (response *tabletmanagerdatapb.GetUnresolvedTransactionsResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "GetUnresolvedTransactions", request, response, false /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.GetUnresolvedTransactionsResponse{}
+	response.Transactions, err = s.tm.GetUnresolvedTransactions(ctx, time.Duration(request.AbandonAge)*time.Second)
+	return response, err
+}
+
+func (s *server) ResolveTransaction(ctx context.Context, request *tabletmanagerdatapb.ResolveTransactionRequest) (response *tabletmanagerdatapb.ResolveTransactionResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "ResolveTransaction", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.ResolveTransactionResponse{}
+	err = s.tm.ResolveTransaction(ctx, request.Dtid)
+	return response, err
+}
+
+func (s *server) RequeueDeadLetters(ctx context.Context, request *tabletmanagerdatapb.RequeueDeadLettersRequest) (response *tabletmanagerdatapb.RequeueDeadLettersResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "RequeueDeadLetters", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	response = &tabletmanagerdatapb.RequeueDeadLettersResponse{}
+	response.Count, err = s.tm.RequeueDeadLetters(ctx, request.Table, request.Ids)
+	return response, err
+}
+
+func (s *server) RunDiagnosticQuery(ctx context.Context, request *tabletmanagerdatapb.RunDiagnosticQueryRequest) (response *tabletmanagerdatapb.RunDiagnosticQueryResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "RunDiagnosticQuery", request, response, false /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	return s.tm.RunDiagnosticQuery(ctx, request)
+}
+
+func (s *server) UpgradeMysql(ctx context.Context, request *tabletmanagerdatapb.UpgradeMysqlRequest) (response *tabletmanagerdatapb.UpgradeMysqlResponse, err error) {
+	defer s.tm.HandleRPCPanic(ctx, "UpgradeMysql", request, response, true /*verbose*/, &err)
+	ctx = callinfo.GRPCCallInfo(ctx)
+	return s.tm.UpgradeMysql(ctx, request)
+}
+
 // registration glue
 
 func init() {
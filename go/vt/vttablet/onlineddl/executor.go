@@ -24,6 +24,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"os"
 	"path"
 	"strconv"
@@ -249,6 +251,16 @@ func getMigrationCutOverThreshold(onlineDDL *schema.OnlineDDL) time.Duration {
 	return defaultCutOverThreshold
 }
 
+// getMigrationCutOverLockWait returns the time the cut-over of the given migration waits to lock the migrated
+// table. It is bounded by the migration's cut-over threshold, and may be further reduced by --cut-over-lock-wait.
+func getMigrationCutOverLockWait(onlineDDL *schema.OnlineDDL) time.Duration {
+	lockWait := getMigrationCutOverThreshold(onlineDDL)
+	if d, _ := onlineDDL.StrategySetting().CutOverLockWait(); d > 0 && d < lockWait {
+		lockWait = d
+	}
+	return lockWait
+}
+
 // NewExecutor creates a new gh-ost executor.
 func NewExecutor(env tabletenv.Env, tabletAlias *topodatapb.TabletAlias, ts *topo.Server,
 	lagThrottler *throttle.Throttler,
@@ -1105,7 +1117,16 @@ func (e *Executor) cutOverVReplMigration(ctx context.Context, s *VReplStream, sh
 		// real production
 
 		e.updateMigrationStage(ctx, onlineDDL.UUID, "locking tables")
-		lockCtx, cancel := context.WithTimeout(ctx, migrationCutOverThreshold)
+		// MySQL gives up waiting for the lock after lock_wait_timeout, even if the context would let
+		// it wait longer.
+		lockWait := getMigrationCutOverLockWait(onlineDDL)
+		lockWaitSeconds := max(int64(math.Ceil(lockWait.Seconds())), 1)
+		setLockWaitQuery := sqlparser.BuildParsedQuery(sqlSetLockWaitTimeout, strconv.FormatInt(lockWaitSeconds, 10))
+		if _, err := lockConn.Conn.Exec(ctx, setLockWaitQuery.Query, 1, false); err != nil {
+			return err
+		}
+		defer lockConn.Conn.Exec(ctx, sqlResetLockWaitTimeout, 1, false)
+		lockCtx, cancel := context.WithTimeout(ctx, lockWait)
 		defer cancel()
 		lockTableQuery := sqlparser.BuildParsedQuery(sqlLockTwoTablesWrite, sentryTableName, onlineDDL.Table)
 		if _, err := lockConn.Conn.Exec(lockCtx, lockTableQuery.Query, 1, false); err != nil {
@@ -2375,8 +2396,12 @@ func (e *Executor) scheduleNextMigration(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	// waitingTables are the tables of the migrations that wait for their dependencies. Migrations
+	// on the same tables, submitted later, wait for them to be scheduled first.
+	waitingTables := map[string]bool{}
 	for _, row := range r.Named().Rows {
 		uuid := row["migration_uuid"].ToString()
+		table := row["mysql_table"].ToString()
 		postponeLaunch := row.AsBool("postpone_launch", false)
 		postponeCompletion := row.AsBool("postpone_completion", false)
 		readyToComplete := row.AsBool("ready_to_complete", false)
@@ -2386,6 +2411,19 @@ func (e *Executor) scheduleNextMigration(ctx context.Context) error {
 			// We don't even look into this migration until its postpone_launch flag is cleared
 			continue
 		}
+		if waitingTables[table] {
+			continue
+		}
+		dependenciesComplete, err := e.reviewMigrationDependencies(ctx, uuid)
+		if err != nil {
+			return err
+		}
+		if !dependenciesComplete {
+			if table != "" {
+				waitingTables[table] = true
+			}
+			continue
+		}
 
 		if !readyToComplete {
 			// see if we need to update ready_to_complete
@@ -2415,6 +2453,71 @@ func (e *Executor) scheduleNextMigration(ctx context.Context) error {
 	return err
 }
 
+// pendingMigrationDependency returns the first migration of dependsOn that has not completed yet, given
+// the statuses of the migrations, or an empty string if all have completed. It returns an error if a
+// migration of dependsOn cannot complete anymore, because it failed, was cancelled or does not exist.
+func pendingMigrationDependency(dependsOn []string, statuses map[string]schema.OnlineDDLStatus) (pendingUUID string, err error) {
+	for _, uuid := range dependsOn {
+		status, ok := statuses[uuid]
+		if !ok {
+			return "", fmt.Errorf("migration %s not found", uuid)
+		}
+		switch status {
+		case schema.OnlineDDLStatusComplete:
+		case schema.OnlineDDLStatusFailed, schema.OnlineDDLStatusCancelled:
+			return "", fmt.Errorf("migration %s is %s", uuid, status)
+		default:
+			if pendingUUID == "" {
+				pendingUUID = uuid
+			}
+		}
+	}
+	return pendingUUID, nil
+}
+
+// reviewMigrationDependencies checks whether the migrations that a queued migration depends on,
+// via --depends-on, have completed. While they have not, the stage of the migration says which
+// migration it waits for. The migration fails if any of its dependencies cannot complete.
+func (e *Executor) reviewMigrationDependencies(ctx context.Context, uuid string) (dependenciesComplete bool, err error) {
+	onlineDDL, row, err := e.readMigration(ctx, uuid)
+	if err != nil {
+		return false, err
+	}
+	dependsOn, err := onlineDDL.StrategySetting().DependsOn()
+	if err != nil {
+		_ = e.failMigration(ctx, onlineDDL, err)
+		return false, nil
+	}
+	if len(dependsOn) == 0 {
+		return true, nil
+	}
+	statuses := map[string]schema.OnlineDDLStatus{}
+	for _, dependencyUUID := range dependsOn {
+		dependency, _, err := e.readMigration(ctx, dependencyUUID)
+		if err == ErrMigrationNotFound {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		statuses[dependencyUUID] = dependency.Status
+	}
+	pendingUUID, err := pendingMigrationDependency(dependsOn, statuses)
+	if err != nil {
+		_ = e.failMigration(ctx, onlineDDL, fmt.Errorf("migration %s depends on %v", uuid, err))
+		return false, nil
+	}
+	if pendingUUID == "" {
+		return true, nil
+	}
+	if stage := fmt.Sprintf("waiting for migration %s", pendingUUID); row["stage"].ToString() != stage {
+		if err := e.updateMigrationStage(ctx, uuid, stage); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
 // reviewEmptyTableRevertMigrations reviews a queued REVERT migration. Such a migration has the following SQL:
 // "REVERT VITESS_MIGRATION '...'"
 // There's nothing in this SQL to indicate:
@@ -2654,6 +2757,35 @@ func (e *Executor) validateMigrationRevertible(ctx context.Context, revertMigrat
 	return nil
 }
 
+// validateRevertWindow checks that the given completed migration is still within its revert window. A migration
+// can only be reverted while its artifacts are retained (see --retain-artifacts and --retain-online-ddl-tables):
+// for ALTER TABLE, the artifact is the original table, which the revert brings back up to date by replicating
+// the writes made after cut-over; for DROP TABLE, the artifact is the renamed table, which the revert renames back.
+// Once artifacts are garbage collected, the migration can no longer be reverted.
+func (e *Executor) validateRevertWindow(ctx context.Context, revertMigration *schema.OnlineDDL, row sqltypes.RowNamedValues) error {
+	if cleanupTimestamp := row["cleanup_timestamp"]; !cleanupTimestamp.IsNull() {
+		return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot revert migration %s: revert window has expired, artifacts were cleaned up at %s", revertMigration.UUID, cleanupTimestamp.ToString())
+	}
+	artifactTables := textutil.SplitDelimitedList(row["artifacts"].ToString())
+	switch row["ddl_action"].ToString() {
+	case sqlparser.AlterStr:
+		if !row.AsBool("is_view", false) && len(artifactTables) == 0 {
+			return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot revert migration %s: revert window has expired, no artifact tables found", revertMigration.UUID)
+		}
+	case sqlparser.DropStr:
+		for _, artifactTable := range artifactTables {
+			exists, err := e.tableExists(ctx, artifactTable)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot revert migration %s: revert window has expired, artifact table %s no longer exists", revertMigration.UUID, artifactTable)
+			}
+		}
+	}
+	return nil
+}
+
 // executeRevert is called for 'revert' migrations (SQL is of the form "revert 99caeca2_74e2_11eb_a693_f875a4d24e90", not a real SQL of course).
 // In this function we:
 // - figure out whether the revert is valid: can we really revert requested migration?
@@ -2672,6 +2804,9 @@ func (e *Executor) executeRevert(ctx context.Context, onlineDDL *schema.OnlineDD
 	if err := e.validateMigrationRevertible(ctx, revertMigration, onlineDDL.UUID); err != nil {
 		return err
 	}
+	if err := e.validateRevertWindow(ctx, revertMigration, row); err != nil {
+		return err
+	}
 
 	revertedActionStr := row["ddl_action"].ToString()
 	switch revertedActionStr {
@@ -3469,6 +3604,9 @@ func (e *Executor) runNextMigration(ctx context.Context) error {
 		if err != nil {
 			return nil, err
 		}
+		// skippedTables are the tables of the ready migrations that cannot run yet. Migrations on
+		// the same table run in order of submission, so later migrations on these tables are skipped, too.
+		skippedTables := map[string]bool{}
 		for _, row := range r.Named().Rows {
 			uuid := row["migration_uuid"].ToString()
 			onlineDDL, migrationRow, err := e.readMigration(ctx, uuid)
@@ -3477,15 +3615,26 @@ func (e *Executor) runNextMigration(ctx context.Context) error {
 			}
 			isImmediateOperation := migrationRow.AsBool("is_immediate_operation", false)
 
+			if skippedTables[onlineDDL.Table] {
+				continue // an earlier migration on the same table runs first
+			}
+			skip := func() {
+				if onlineDDL.Table != "" {
+					skippedTables[onlineDDL.Table] = true
+				}
+			}
 			if conflictFound, _ := e.isAnyConflictingMigrationRunning(onlineDDL); conflictFound {
+				skip()
 				continue // this migration conflicts with a running one
 			}
 			if e.countOwnedRunningMigrations() >= maxConcurrentOnlineDDLs {
+				skip()
 				continue // too many running migrations
 			}
 			if isImmediateOperation && onlineDDL.StrategySetting().IsInOrderCompletion() {
 				// This migration is immediate: if we run it now, it will complete within a second or two at most.
 				if len(pendingMigrationsUUIDs) > 0 && pendingMigrationsUUIDs[0] != onlineDDL.UUID {
+					skip()
 					continue
 				}
 			}
@@ -3739,6 +3888,51 @@ func shouldCutOverAccordingToBackoff(
 	return false, false
 }
 
+// cutOverPreflightCheck is called for migrations using --cut-over-preflight, when the migration is otherwise
+// about to cut-over. It returns a non-empty reason when the cut-over should be delayed:
+//   - the throttler reports replication lag (or other metric) beyond its threshold, since the cut-over
+//     would only add to the lag on the replicas.
+//   - there are transactions holding locks on the migrated table, which have been open for longer than the
+//     cut-over threshold. The cut-over would then wait on those locks and likely time out, while blocking
+//     all other queries on the table.
+func (e *Executor) cutOverPreflightCheck(ctx context.Context, onlineDDL *schema.OnlineDDL) (reason string, err error) {
+	checkResult := e.lagThrottler.CheckByType(ctx, throttlerapp.OnlineDDLName.String(), "", throttle.StandardCheckFlags, throttle.ThrottleCheckPrimaryWrite)
+	if checkResult.StatusCode != http.StatusOK {
+		return fmt.Sprintf("throttler check failed: value=%v, threshold=%v", checkResult.Value, checkResult.Threshold), nil
+	}
+
+	conn, err := dbconnpool.NewDBConnection(ctx, e.env.Config().DB.DbaWithDB())
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	capableOf := mysql.ServerVersionCapableOf(conn.ServerVersion)
+	capable, err := capableOf(capabilities.PerformanceSchemaDataLocksTableCapability)
+	if err != nil {
+		return "", err
+	}
+	if !capable {
+		return "", nil
+	}
+	migrationCutOverThreshold := getMigrationCutOverThreshold(onlineDDL)
+	query, err := sqlparser.ParseAndBind(sqlLongTransactionsWithLocksOnTable,
+		sqltypes.StringBindVariable(onlineDDL.Table),
+		sqltypes.Int64BindVariable(int64(migrationCutOverThreshold.Seconds())),
+	)
+	if err != nil {
+		return "", err
+	}
+	rs, err := conn.Conn.ExecuteFetch(query, -1, true)
+	if err != nil {
+		return "", err
+	}
+	if len(rs.Rows) > 0 {
+		return fmt.Sprintf("%d transactions open for more than %v hold locks on table %s", len(rs.Rows), migrationCutOverThreshold, onlineDDL.Table), nil
+	}
+	return "", nil
+}
+
 // reviewRunningMigrations iterates migrations in 'running' state. Normally there's only one running, which was
 // spawned by this tablet; but vreplication migrations could also resume from failure.
 func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning int, cancellable []*cancellableMigration, err error) {
@@ -3779,6 +3973,7 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 		}
 		postponeCompletion := row.AsBool("postpone_completion", false)
 		shouldForceCutOver := row.AsBool("force_cutover", false)
+		cutOverApproved := row.AsBool("cut_over_approved", false)
 		elapsedSeconds := row.AsInt64("elapsed_seconds", 0)
 		strategySetting := onlineDDL.StrategySetting()
 		// --force-cut-over-after flag is validated when DDL strategy is first parsed.
@@ -3866,6 +4061,11 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 					// override. Even if migration is ready, we do not complete it.
 					return nil
 				}
+				if strategySetting.IsPostponeCutOver() && !cutOverApproved && !shouldForceCutOver {
+					// The cut-over waits for ALTER VITESS_MIGRATION ... CUTOVER, or FORCE_CUTOVER.
+					_ = e.updateMigrationStage(ctx, uuid, "ready to cut-over, waiting for CUTOVER approval")
+					return nil
+				}
 				if strategySetting.IsInOrderCompletion() {
 					if len(pendingMigrationsUUIDs) > 0 && pendingMigrationsUUIDs[0] != onlineDDL.UUID {
 						// wait for earlier pending migrations to complete
@@ -3878,6 +4078,17 @@ func (e *Executor) reviewRunningMigrations(ctx context.Context) (countRunnning i
 				if !shouldCutOver {
 					return nil
 				}
+				if !shouldForceCutOver && strategySetting.IsCutOverPreflightFlag() {
+					reason, err := e.cutOverPreflightCheck(ctx, onlineDDL)
+					if err != nil {
+						return err
+					}
+					if reason != "" {
+						// Not a good time to cut-over. We will try again on the next review.
+						_ = e.updateMigrationStage(ctx, uuid, "cut-over delayed by preflight check: %s", reason)
+						return nil
+					}
+				}
 				if err := e.cutOverVReplMigration(ctx, s, shouldForceCutOver); err != nil {
 					_ = e.updateMigrationMessage(ctx, uuid, err.Error())
 					log.Errorf("cutOverVReplMigration failed: err=%v", err)
@@ -4750,6 +4961,34 @@ func (e *Executor) ForceCutOverMigration(ctx context.Context, uuid string) (resu
 	return rs, nil
 }
 
+// CutOverMigration approves the cut-over of a migration submitted with --postpone-cut-over. The migration
+// cuts over once it is ready, and once the --cut-over-preflight checks, if any, pass.
+func (e *Executor) CutOverMigration(ctx context.Context, uuid string) (result *sqltypes.Result, err error) {
+	if atomic.LoadInt64(&e.isOpen) == 0 {
+		return nil, vterrors.New(vtrpcpb.Code_FAILED_PRECONDITION, schema.ErrOnlineDDLDisabled.Error())
+	}
+	if !schema.IsOnlineDDLUUID(uuid) {
+		return nil, vterrors.Errorf(vtrpcpb.Code_UNKNOWN, "Not a valid migration ID in CUTOVER: %s", uuid)
+	}
+	log.Infof("CutOverMigration: request to approve cut-over of migration %s", uuid)
+	e.migrationMutex.Lock()
+	defer e.migrationMutex.Unlock()
+
+	query, err := sqlparser.ParseAndBind(sqlUpdateCutOverApproved,
+		sqltypes.StringBindVariable(uuid),
+	)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := e.execQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	e.triggerNextCheckInterval()
+	log.Infof("CutOverMigration: migration %s approved for cut-over", uuid)
+	return rs, nil
+}
+
 // ForceCutOverPendingMigrations sets force_cutover flag for all pending migrations
 func (e *Executor) ForceCutOverPendingMigrations(ctx context.Context) (result *sqltypes.Result, err error) {
 	if atomic.LoadInt64(&e.isOpen) == 0 {
@@ -22,6 +22,7 @@ package onlineddl
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/vtenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 
@@ -491,3 +493,159 @@ func TestShouldCutOverAccordingToBackoff(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMigrationCutOverLockWait(t *testing.T) {
+	tcases := []struct {
+		strategy string
+		expect   time.Duration
+	}{
+		{
+			strategy: "vitess",
+			expect:   defaultCutOverThreshold,
+		},
+		{
+			strategy: "vitess --cut-over-lock-wait=3s",
+			expect:   3 * time.Second,
+		},
+		{
+			strategy: "vitess --cut-over-threshold=20s",
+			expect:   20 * time.Second,
+		},
+		{
+			strategy: "vitess --cut-over-threshold=5s --cut-over-lock-wait=30s",
+			expect:   5 * time.Second,
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.strategy, func(t *testing.T) {
+			onlineDDL := &schema.OnlineDDL{Strategy: schema.DDLStrategyVitess, Options: tcase.strategy[len("vitess"):]}
+			assert.Equal(t, tcase.expect, getMigrationCutOverLockWait(onlineDDL))
+		})
+	}
+}
+
+func TestValidateRevertWindow(t *testing.T) {
+	uuid := "a5a4b3ac_b2d6_11ee_a25b_0a43f95f28a3"
+	fields := sqltypes.MakeTestFields(
+		"migration_uuid|ddl_action|is_view|artifacts|cleanup_timestamp",
+		"varchar|varchar|int64|varchar|timestamp",
+	)
+	tcases := []struct {
+		name           string
+		row            string
+		existingTables []string
+		expectError    string
+	}{
+		{
+			name: "alter with artifacts",
+			row:  uuid + "|alter|0|_vt_vrp_a5a4b3acb2d611eea25b0a43f95f28a3_20240101000000_,|null",
+		},
+		{
+			name:        "alter without artifacts",
+			row:         uuid + "|alter|0||null",
+			expectError: "no artifact tables found",
+		},
+		{
+			name: "alter view without artifacts",
+			row:  uuid + "|alter|1||null",
+		},
+		{
+			name: "create without artifacts",
+			row:  uuid + "|create|0||null",
+		},
+		{
+			name:           "drop with artifacts",
+			row:            uuid + "|drop|0|_vt_hld_a5a4b3acb2d611eea25b0a43f95f28a3_20240101000000_|null",
+			existingTables: []string{"_vt_hld_a5a4b3acb2d611eea25b0a43f95f28a3_20240101000000_"},
+		},
+		{
+			name:        "drop with missing artifacts",
+			row:         uuid + "|drop|0|_vt_hld_a5a4b3acb2d611eea25b0a43f95f28a3_20240101000000_|null",
+			expectError: "artifact table _vt_hld_a5a4b3acb2d611eea25b0a43f95f28a3_20240101000000_ no longer exists",
+		},
+		{
+			name:        "cleaned up",
+			row:         uuid + "|alter|0||2024-01-02 00:00:00",
+			expectError: "revert window has expired, artifacts were cleaned up at 2024-01-02 00:00:00",
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			e := &Executor{
+				execQuery: func(ctx context.Context, query string) (*sqltypes.Result, error) {
+					if strings.Contains(query, "FROM _vt.schema_migrations") {
+						return sqltypes.MakeTestResult(fields, tcase.row), nil
+					}
+					result := sqltypes.MakeTestResult(sqltypes.MakeTestFields("table_name", "varchar"))
+					for _, table := range tcase.existingTables {
+						if query == sqlparser.BuildParsedQuery(sqlShowTablesLike, strings.ReplaceAll(table, "_", `\_`)).Query {
+							result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar(table)})
+						}
+					}
+					return result, nil
+				},
+			}
+			ctx := context.Background()
+			revertMigration, row, err := e.readMigration(ctx, uuid)
+			require.NoError(t, err)
+			err = e.validateRevertWindow(ctx, revertMigration, row)
+			if tcase.expectError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tcase.expectError)
+			}
+		})
+	}
+}
+
+func TestPendingMigrationDependency(t *testing.T) {
+	uuid1 := "a5a4b3ac_b2d6_11ee_a25b_0a43f95f28a3"
+	uuid2 := "b5a4b3ac_b2d6_11ee_a25b_0a43f95f28a3"
+	tcases := []struct {
+		name        string
+		statuses    map[string]schema.OnlineDDLStatus
+		expectUUID  string
+		expectError string
+	}{
+		{
+			name:     "complete",
+			statuses: map[string]schema.OnlineDDLStatus{uuid1: schema.OnlineDDLStatusComplete, uuid2: schema.OnlineDDLStatusComplete},
+		},
+		{
+			name:       "running",
+			statuses:   map[string]schema.OnlineDDLStatus{uuid1: schema.OnlineDDLStatusComplete, uuid2: schema.OnlineDDLStatusRunning},
+			expectUUID: uuid2,
+		},
+		{
+			name:       "first pending",
+			statuses:   map[string]schema.OnlineDDLStatus{uuid1: schema.OnlineDDLStatusQueued, uuid2: schema.OnlineDDLStatusReady},
+			expectUUID: uuid1,
+		},
+		{
+			name:        "failed",
+			statuses:    map[string]schema.OnlineDDLStatus{uuid1: schema.OnlineDDLStatusQueued, uuid2: schema.OnlineDDLStatusFailed},
+			expectError: "migration " + uuid2 + " is failed",
+		},
+		{
+			name:        "cancelled",
+			statuses:    map[string]schema.OnlineDDLStatus{uuid1: schema.OnlineDDLStatusCancelled, uuid2: schema.OnlineDDLStatusComplete},
+			expectError: "migration " + uuid1 + " is cancelled",
+		},
+		{
+			name:        "not found",
+			statuses:    map[string]schema.OnlineDDLStatus{uuid1: schema.OnlineDDLStatusComplete},
+			expectError: "migration " + uuid2 + " not found",
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			pendingUUID, err := pendingMigrationDependency([]string{uuid1, uuid2}, tcase.statuses)
+			if tcase.expectError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tcase.expectError)
+			}
+			assert.Equal(t, tcase.expectUUID, pendingUUID)
+		})
+	}
+}
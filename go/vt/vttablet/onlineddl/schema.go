@@ -43,6 +43,7 @@ const (
 
 	sqlSelectQueuedMigrations = `SELECT
 			migration_uuid,
+			mysql_table,
 			ddl_action,
 			is_view,
 			is_immediate_operation,
@@ -174,6 +175,11 @@ const (
 		WHERE
 			migration_uuid=%a
 	`
+	sqlUpdateCutOverApproved = `UPDATE _vt.schema_migrations
+			SET cut_over_approved=1
+		WHERE
+			migration_uuid=%a
+	`
 	sqlUpdateLaunchMigration = `UPDATE _vt.schema_migrations
 			SET postpone_launch=0
 		WHERE
@@ -296,6 +302,7 @@ const (
 			migration_uuid,
 			postpone_completion,
 			force_cutover,
+			cut_over_approved,
 			cutover_attempts,
 			ifnull(timestampdiff(second, ready_to_complete_timestamp, now()), 0) as seconds_since_ready_to_complete,
 			ifnull(timestampdiff(second, last_cutover_attempt_timestamp, now()), 0) as seconds_since_last_cutover_attempt,
@@ -417,7 +424,8 @@ const (
 			postpone_launch,
 			postpone_completion,
 			is_immediate_operation,
-			reviewed_timestamp
+			reviewed_timestamp,
+			cleanup_timestamp
 		FROM _vt.schema_migrations
 		WHERE
 			migration_uuid=%a
@@ -584,6 +592,8 @@ const (
 	sqlRenameTable             = "RENAME TABLE `%a` TO `%a`"
 	sqlLockTwoTablesWrite      = "LOCK TABLES `%a` WRITE, `%a` WRITE"
 	sqlUnlockTables            = "UNLOCK TABLES"
+	sqlSetLockWaitTimeout      = "SET SESSION lock_wait_timeout=%a"
+	sqlResetLockWaitTimeout    = "SET SESSION lock_wait_timeout=@@global.lock_wait_timeout"
 	sqlCreateSentryTable       = "CREATE TABLE IF NOT EXISTS `%a` (id INT PRIMARY KEY)"
 	sqlFindProcess             = "SELECT id, Info as info FROM information_schema.processlist WHERE id=%a AND Info LIKE %a"
 	sqlFindProcessByInfo       = "SELECT id, Info as info FROM information_schema.processlist WHERE Info LIKE %a and id != connection_id()"
@@ -596,6 +606,16 @@ const (
 		where
 			data_locks.OBJECT_SCHEMA=database() AND data_locks.OBJECT_NAME=%a
 	`
+	sqlLongTransactionsWithLocksOnTable = `
+		SELECT
+			DISTINCT innodb_trx.trx_mysql_thread_id
+		from
+			performance_schema.data_locks
+			join information_schema.innodb_trx on (data_locks.ENGINE_TRANSACTION_ID=innodb_trx.trx_id)
+		where
+			data_locks.OBJECT_SCHEMA=database() AND data_locks.OBJECT_NAME=%a
+			AND innodb_trx.trx_started < NOW() - INTERVAL %a SECOND
+	`
 )
 
 var (
@@ -0,0 +1,266 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package slowlog implements an optional plugin that writes the slow queries
+// to a file in the MySQL slow query log format, so that the tools built for
+// it (e.g. pt-query-digest) can be used on the queries served by vttablet.
+package slowlog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+)
+
+var (
+	slowQueryLogFile       string
+	slowQueryLogThreshold  = time.Second
+	slowQueryLogMaxSize    int64
+	slowQueryLogMaxBackups = 5
+)
+
+func registerFlags(fs *pflag.FlagSet) {
+	// slowQueryLogFile is the vttablet startup flag that must be set for this plugin to be active.
+	fs.StringVar(&slowQueryLogFile, "slow-query-log-file", slowQueryLogFile, "Enable logging the slow queries in the MySQL slow query log format to the specified file")
+	fs.DurationVar(&slowQueryLogThreshold, "slow-query-log-long-query-time", slowQueryLogThreshold, "Queries that take at least this long are written to the slow query log")
+	fs.Int64Var(&slowQueryLogMaxSize, "slow-query-log-max-size", slowQueryLogMaxSize, "Rotate the slow query log when it grows beyond this many bytes (0 means never)")
+	fs.IntVar(&slowQueryLogMaxBackups, "slow-query-log-max-backups", slowQueryLogMaxBackups, "Number of rotated slow query log files to keep")
+}
+
+func init() {
+	servenv.OnParseFor("vtcombo", registerFlags)
+	servenv.OnParseFor("vttablet", registerFlags)
+
+	servenv.OnRun(func() {
+		if slowQueryLogFile != "" {
+			if _, err := Init(slowQueryLogFile, slowQueryLogThreshold, slowQueryLogMaxSize, slowQueryLogMaxBackups); err != nil {
+				log.Errorf("Unable to log slow queries to %s: %v", slowQueryLogFile, err)
+			}
+		}
+	})
+}
+
+// SlowLogger is an opaque interface used to control the slow query logging.
+type SlowLogger interface {
+	// Stop logging to the file
+	Stop()
+}
+
+type slowLogger struct {
+	path       string
+	threshold  time.Duration
+	maxSize    int64
+	maxBackups int
+
+	file *os.File
+	size int64
+
+	logChan chan *tabletenv.LogStats
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// Init starts logging the queries that take at least threshold to the
+// given file path. The file is rotated when it grows beyond maxSize bytes,
+// keeping maxBackups of the previous files as path.1, path.2, etc.
+func Init(path string, threshold time.Duration, maxSize int64, maxBackups int) (SlowLogger, error) {
+	log.Infof("Logging slow queries to file %s", path)
+	l := &slowLogger{
+		path:       path,
+		threshold:  threshold,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	l.logChan = tabletenv.StatsLogger.Subscribe("SlowLog")
+	go l.run()
+	return l, nil
+}
+
+// Stop stops logging and closes the file once the entries that were
+// already received are written.
+func (l *slowLogger) Stop() {
+	tabletenv.StatsLogger.Unsubscribe(l.logChan)
+	close(l.done)
+	<-l.stopped
+}
+
+func (l *slowLogger) run() {
+	defer close(l.stopped)
+	defer func() {
+		l.file.Close()
+	}()
+
+	var b strings.Builder
+	for {
+		select {
+		case stats := <-l.logChan:
+			l.write(&b, stats)
+		case <-l.done:
+			for {
+				select {
+				case stats := <-l.logChan:
+					l.write(&b, stats)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *slowLogger) write(b *strings.Builder, stats *tabletenv.LogStats) {
+	if stats.TotalTime() < l.threshold {
+		return
+	}
+	b.Reset()
+	formatEntry(b, stats)
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(b.Len()) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			log.Errorf("Error rotating the slow query log %s: %v", l.path, err)
+			return
+		}
+	}
+	n, err := l.file.WriteString(b.String())
+	l.size += int64(n)
+	if err != nil {
+		log.Errorf("Error writing to the slow query log %s: %v", l.path, err)
+	}
+}
+
+func (l *slowLogger) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file = f
+	l.size = fi.Size()
+	return nil
+}
+
+// rotate moves the current file to path.1, shifting the older files up
+// and dropping the ones beyond maxBackups, and opens a new file.
+func (l *slowLogger) rotate() error {
+	l.file.Close()
+	if l.maxBackups > 0 {
+		for i := l.maxBackups - 1; i > 0; i-- {
+			err := os.Rename(l.backupPath(i), l.backupPath(i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(l.path, l.backupPath(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(l.path); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+func (l *slowLogger) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
+
+// formatEntry writes stats as an entry of the MySQL slow query log. The
+// Vitess specific fields are added as extra attributes to the header, which
+// the tools parsing the slow query log either use or ignore.
+func formatEntry(b *strings.Builder, stats *tabletenv.LogStats) {
+	redacted := streamlog.GetRedactDebugUIQueries()
+
+	host := stats.ClientHost
+	if host == "" {
+		host, _ = stats.CallInfo()
+	}
+	fmt.Fprintf(b, "# Time: %s\n", stats.StartTime.UTC().Format("2006-01-02T15:04:05.000000Z"))
+	fmt.Fprintf(b, "# User@Host: %s[%s] @ %s []  Id: %d\n", stats.ImmediateCaller(), stats.EffectiveCaller(), host, stats.ReservedID)
+	// vttablet doesn't know the locking time and the examined rows of the
+	// queries, so they are always logged as zero.
+	fmt.Fprintf(b, "# Query_time: %.6f  Lock_time: 0.000000  Rows_sent: %d  Rows_examined: 0  Rows_affected: %d\n",
+		stats.TotalTime().Seconds(),
+		len(stats.Rows),
+		stats.RowsAffected,
+	)
+	fmt.Fprintf(b, "# Vitess_method: %s  Vitess_plan_type: %s  Vitess_query_sources: %s  Vitess_consolidated: %s\n",
+		orNone(stats.Method),
+		orNone(stats.PlanType),
+		stats.FmtQuerySources(),
+		yesNo(stats.QuerySources&tabletenv.QuerySourceConsolidator != 0),
+	)
+	fmt.Fprintf(b, "# Vitess_mysql_time: %.6f  Vitess_conn_wait_time: %.6f  Vitess_queries: %d  Vitess_transaction_id: %d\n",
+		stats.MysqlResponseTime.Seconds(),
+		stats.WaitingForConnection.Seconds(),
+		stats.NumberOfQueries,
+		stats.TransactionID,
+	)
+	if stats.Target != nil {
+		fmt.Fprintf(b, "# Vitess_keyspace: %s  Vitess_shard: %s  Vitess_tablet_type: %s\n", stats.Target.Keyspace, stats.Target.Shard, stats.Target.TabletType)
+	}
+	if stats.Error != nil {
+		fmt.Fprintf(b, "# Vitess_error: %s\n", singleLine(stats.ErrorStr()))
+	}
+
+	// The statement of the entry is what was sent to MySQL, and the query
+	// received from vtgate is added as an attribute. The queries sent to
+	// MySQL have the bind variables substituted, so only the query from
+	// vtgate is logged when the queries are redacted.
+	query := stats.RewrittenSQL()
+	if redacted || query == "" {
+		query = stats.OriginalSQL
+	} else {
+		fmt.Fprintf(b, "# Vitess_original_query: %s\n", singleLine(stats.OriginalSQL))
+	}
+	fmt.Fprintf(b, "SET timestamp=%d;\n", stats.StartTime.Unix())
+	b.WriteString(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	b.WriteString(";\n")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// singleLine keeps a value written in the header of an entry on its line.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
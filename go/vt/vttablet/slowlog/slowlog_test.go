@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slowlog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/callerid"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func newLogStats(sql string, took time.Duration) *tabletenv.LogStats {
+	ctx := callerid.NewContext(context.Background(), callerid.NewEffectiveCallerID("app", "", ""), callerid.NewImmediateCallerID("vtgate"))
+	start := time.Date(2024, time.March, 1, 10, 20, 30, 0, time.UTC)
+	return &tabletenv.LogStats{
+		Ctx:         ctx,
+		Method:      "Execute",
+		PlanType:    "Select",
+		OriginalSQL: sql,
+		StartTime:   start,
+		EndTime:     start.Add(took),
+		Target:      &querypb.Target{Keyspace: "commerce", Shard: "-80", TabletType: topodatapb.TabletType_PRIMARY},
+		ClientHost:  "10.0.0.1",
+	}
+}
+
+func TestFormatEntry(t *testing.T) {
+	stats := newLogStats("select * from t where id = :id", 1500*time.Millisecond)
+	stats.AddRewrittenSQL("select * from t where id = 1 limit 10001", time.Now())
+	stats.MysqlResponseTime = time.Second
+	stats.QuerySources |= tabletenv.QuerySourceConsolidator
+	stats.Rows = [][]sqltypes.Value{{sqltypes.NewInt64(1)}, {sqltypes.NewInt64(2)}}
+
+	var b strings.Builder
+	formatEntry(&b, stats)
+	want := `# Time: 2024-03-01T10:20:30.000000Z
+# User@Host: vtgate[app] @ 10.0.0.1 []  Id: 0
+# Query_time: 1.500000  Lock_time: 0.000000  Rows_sent: 2  Rows_examined: 0  Rows_affected: 0
+# Vitess_method: Execute  Vitess_plan_type: Select  Vitess_query_sources: mysql,consolidator  Vitess_consolidated: Yes
+# Vitess_mysql_time: 1.000000  Vitess_conn_wait_time: 0.000000  Vitess_queries: 1  Vitess_transaction_id: 0
+# Vitess_keyspace: commerce  Vitess_shard: -80  Vitess_tablet_type: PRIMARY
+# Vitess_original_query: select * from t where id = :id
+SET timestamp=1709288430;
+select * from t where id = 1 limit 10001;
+`
+	assert.Equal(t, want, b.String())
+
+	// Only the query received from vtgate is logged when the queries are
+	// redacted, and the errors are kept on a single line.
+	streamlog.SetRedactDebugUIQueries(true)
+	defer streamlog.SetRedactDebugUIQueries(false)
+	stats.Error = errors.New("query failed:\ncontext canceled")
+	b.Reset()
+	formatEntry(&b, stats)
+	assert.Contains(t, b.String(), "# Vitess_error: query failed: context canceled\nSET timestamp=1709288430;\nselect * from t where id = :id;\n")
+	assert.NotContains(t, b.String(), "id = 1")
+}
+
+func TestSlowLog(t *testing.T) {
+	logPath := path.Join(t.TempDir(), "slow.log")
+	logger, err := Init(logPath, time.Second, 0, 0)
+	require.NoError(t, err)
+
+	tabletenv.StatsLogger.Send(newLogStats("select fast", 10*time.Millisecond))
+	tabletenv.StatsLogger.Send(newLogStats("select slow", 2*time.Second))
+	logger.Stop()
+
+	contents, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "select fast")
+	assert.Contains(t, string(contents), "# Query_time: 2.000000 ")
+	assert.Contains(t, string(contents), "\nselect slow;\n")
+}
+
+func TestSlowLogRotation(t *testing.T) {
+	var b strings.Builder
+	formatEntry(&b, newLogStats("select 1", time.Second))
+	entrySize := int64(b.Len())
+
+	// Every file fits two entries.
+	logPath := path.Join(t.TempDir(), "slow.log")
+	logger, err := Init(logPath, 0, 2*entrySize, 2)
+	require.NoError(t, err)
+	for i := 0; i < 7; i++ {
+		tabletenv.StatsLogger.Send(newLogStats("select 1", time.Second))
+	}
+	logger.Stop()
+
+	for _, file := range []string{logPath, logPath + ".1", logPath + ".2"} {
+		fi, err := os.Stat(file)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, fi.Size(), 2*entrySize, file)
+	}
+	fi, err := os.Stat(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, entrySize, fi.Size())
+	_, err = os.Stat(logPath + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -36,6 +36,7 @@ import (
 	"vitess.io/vitess/go/vt/logutil"
 	"vitess.io/vitess/go/vt/mysqlctl"
 	"vitess.io/vitess/go/vt/mysqlctl/backupstats"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
 	"vitess.io/vitess/go/vt/proto/vttime"
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo"
@@ -59,6 +60,10 @@ var (
 	restoreConcurrency     = 4
 	waitForBackupInterval  time.Duration
 
+	restoreCatchupMaxLag       time.Duration
+	restoreCatchupTimeout      time.Duration
+	restoreCatchupPollInterval = 1 * time.Second
+
 	statsRestoreBackupTime     *stats.String
 	statsRestoreBackupPosition *stats.String
 )
@@ -68,6 +73,8 @@ func registerRestoreFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&restoreFromBackupTsStr, "restore_from_backup_ts", restoreFromBackupTsStr, "(init restore parameter) if set, restore the latest backup taken at or before this timestamp. Example: '2021-04-29.133050'")
 	fs.IntVar(&restoreConcurrency, "restore_concurrency", restoreConcurrency, "(init restore parameter) how many concurrent files to restore at once")
 	fs.DurationVar(&waitForBackupInterval, "wait_for_backup_interval", waitForBackupInterval, "(init restore parameter) if this is greater than 0, instead of starting up empty when no backups are found, keep checking at this interval for a backup to appear")
+	fs.DurationVar(&restoreCatchupMaxLag, "restore-catchup-max-lag", restoreCatchupMaxLag, "(init restore parameter) if this is greater than 0, after restoring a replica and starting replication, wait for its replication lag to fall under this value before serving queries")
+	fs.DurationVar(&restoreCatchupTimeout, "restore-catchup-timeout", restoreCatchupTimeout, "(init restore parameter) if this is greater than 0, fail the restore if replication has not caught up under --restore-catchup-max-lag after this long")
 }
 
 var (
@@ -257,8 +264,24 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 	}
 	if !ok {
 		params.Logger.Infof("Attempting to restore, but mysqld already contains data. Assuming vttablet was just restarted.")
+		tm.restoreProgress.start()
+		tm.restoreProgress.setPhase(RestorePhaseSkipped)
 		return nil
 	}
+	tm.restoreProgress.start()
+	// A snapshot keyspace is restored from its base keyspace as of its snapshot time.
+	// If the incremental backups of the base keyspace archive the binary logs up to
+	// that time, restore to it with them, which resolves the matching GTID position
+	// without a binlog server.
+	snapshotFromBackups := false
+	if keyspaceInfo.SnapshotTime != nil && !params.IsIncrementalRecovery() {
+		snapshotTime := protoutil.TimeFromProto(keyspaceInfo.SnapshotTime).UTC()
+		if tm.canRestoreToTimeFromBackups(ctx, params, snapshotTime) {
+			params.Logger.Infof("Restore: restoring to snapshot time %v with the incremental backups of %v/%v", snapshotTime, keyspace, tablet.Shard)
+			params.RestoreToTimestamp = snapshotTime
+			snapshotFromBackups = true
+		}
+	}
 	// We should not become primary after restore, because that would incorrectly
 	// start a new primary term, and it's likely our data dir will be out of date.
 	if originalType == topodatapb.TabletType_PRIMARY {
@@ -270,10 +293,12 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 	// Loop until a backup exists, unless we were told to give up immediately.
 	var backupManifest *mysqlctl.BackupManifest
 	for {
+		tm.restoreProgress.setPhase(RestorePhaseRestoring)
 		backupManifest, err = mysqlctl.Restore(ctx, params)
 		if backupManifest != nil {
 			statsRestoreBackupPosition.Set(replication.EncodePosition(backupManifest.Position))
 			statsRestoreBackupTime.Set(backupManifest.BackupTime)
+			tm.restoreProgress.setBackup(backupManifest.BackupTime, replication.EncodePosition(backupManifest.Position))
 		}
 		params.Logger.Infof("Restore: got a restore manifest: %v, err=%v, waitForBackupInterval=%v", backupManifest, err, waitForBackupInterval)
 		if waitForBackupInterval == 0 {
@@ -285,8 +310,10 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 		}
 
 		log.Infof("No backup found. Waiting %v (from -wait_for_backup_interval flag) to check again.", waitForBackupInterval)
+		tm.restoreProgress.setPhase(RestorePhaseWaitingForBackup)
 		select {
 		case <-ctx.Done():
+			tm.restoreProgress.setFailed(ctx.Err())
 			return ctx.Err()
 		case <-time.After(waitForBackupInterval):
 		}
@@ -298,7 +325,7 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 		params.Logger.Infof("Restore: pos=%v", replication.EncodePosition(pos))
 	}
 	// If SnapshotTime is set , then apply the incremental change
-	if keyspaceInfo.SnapshotTime != nil {
+	if keyspaceInfo.SnapshotTime != nil && !snapshotFromBackups {
 		params.Logger.Infof("Restore: Restoring to time %v from binlog", keyspaceInfo.SnapshotTime)
 		err = tm.restoreToTimeFromBinlog(ctx, pos, keyspaceInfo.SnapshotTime)
 		if err != nil {
@@ -318,18 +345,27 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 			if err := tm.disableReplication(context.Background()); err != nil {
 				return err
 			}
+			if snapshotFromBackups {
+				if err := tm.reportSnapshotPosition(params.RestoreToTimestamp); err != nil {
+					return err
+				}
+			}
 		} else if keyspaceInfo.KeyspaceType == topodatapb.KeyspaceType_NORMAL {
 			// Reconnect to primary only for "NORMAL" keyspaces
 			params.Logger.Infof("Restore: starting replication at position %v", pos)
+			tm.restoreProgress.setPhase(RestorePhaseStartingReplication)
 			if err := tm.startReplication(context.Background(), pos, originalType); err != nil {
+				tm.restoreProgress.setFailed(err)
 				return err
 			}
 		}
 	case err == mysqlctl.ErrNoBackup:
 		// Starting with empty database.
 		// We just need to initialize replication
+		tm.restoreProgress.setPhase(RestorePhaseStartingReplication)
 		_, err := tm.initializeReplication(ctx, originalType)
 		if err != nil {
+			tm.restoreProgress.setFailed(err)
 			return err
 		}
 	case err == nil && params.DryRun:
@@ -341,7 +377,9 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 		if err := tm.tmState.ChangeTabletType(bgCtx, originalType, DBActionNone); err != nil {
 			log.Errorf("Could not change back to original tablet type %v: %v", originalType, err)
 		}
-		return vterrors.Wrap(err, "Can't restore backup")
+		err = vterrors.Wrap(err, "Can't restore backup")
+		tm.restoreProgress.setFailed(err)
+		return err
 	}
 
 	// If we had type BACKUP or RESTORE it's better to set our type to the init_tablet_type to make result of the restore
@@ -352,7 +390,7 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 			originalType = initType
 		}
 	}
-	if params.IsIncrementalRecovery() && !params.DryRun {
+	if params.IsIncrementalRecovery() && !params.DryRun && !snapshotFromBackups {
 		// override
 		params.Logger.Infof("Restore: will set tablet type to DRAINED as this is a point in time recovery")
 		originalType = topodatapb.TabletType_DRAINED
@@ -360,7 +398,93 @@ func (tm *TabletManager) restoreDataLocked(ctx context.Context, logger logutil.L
 	params.Logger.Infof("Restore: changing tablet type to %v for %s", originalType, tm.tabletAlias.String())
 	// Change type back to original type if we're ok to serve.
 	bgCtx := context.Background()
-	return tm.tmState.ChangeTabletType(bgCtx, originalType, DBActionNone)
+	if err := tm.tmState.ChangeTabletType(bgCtx, originalType, DBActionNone); err != nil {
+		tm.restoreProgress.setFailed(err)
+		return err
+	}
+	tm.restoreProgress.setPhase(RestorePhaseRestored)
+	return nil
+}
+
+// waitForReplicationCatchup waits for the replication lag of a replica that
+// was just restored to fall under --restore-catchup-max-lag, so it doesn't
+// start serving queries with stale data. It doesn't wait if the flag is not
+// set, or if the tablet doesn't replicate.
+func (tm *TabletManager) waitForReplicationCatchup(ctx context.Context) error {
+	if restoreCatchupMaxLag <= 0 {
+		return nil
+	}
+	if tabletType := tm.Tablet().Type; !topo.IsReplicaType(tabletType) {
+		log.Infof("Not waiting for replication to catch up for tablet type %v.", tabletType)
+		return nil
+	}
+	if restoreCatchupTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, restoreCatchupTimeout)
+		defer cancel()
+	}
+
+	log.Infof("Waiting for replication lag to fall under %v before serving.", restoreCatchupMaxLag)
+	tm.restoreProgress.setPhase(RestorePhaseCatchingUp)
+	for {
+		status, err := tm.MysqlDaemon.ReplicationStatus()
+		switch {
+		case err == mysql.ErrNotReplica:
+			log.Infof("Replication is not configured, not waiting for it to catch up.")
+			return nil
+		case err != nil:
+			log.Warningf("Can't get replication status while waiting for it to catch up: %v", err)
+		case !status.Healthy():
+			log.Warningf("Replication is not running while waiting for it to catch up, IO error: %q, SQL error: %q", status.LastIOError, status.LastSQLError)
+		case !status.ReplicationLagUnknown:
+			lag := time.Duration(status.ReplicationLagSeconds) * time.Second
+			tm.restoreProgress.setReplicationLag(lag, restoreCatchupMaxLag)
+			if lag <= restoreCatchupMaxLag {
+				log.Infof("Replication caught up, lag is %v.", lag)
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return vterrors.Wrapf(ctx.Err(), "replication did not catch up under %v", restoreCatchupMaxLag)
+		case <-time.After(restoreCatchupPollInterval):
+		}
+	}
+}
+
+// canRestoreToTimeFromBackups returns whether the backups of the shard can restore
+// it up to restoreToTime, with a full backup and incremental backups. It returns
+// false if they can't, or if that can't be found out.
+func (tm *TabletManager) canRestoreToTimeFromBackups(ctx context.Context, params mysqlctl.RestoreParams, restoreToTime time.Time) bool {
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		log.Warningf("Can't look for incremental backups to restore to %v: %v", restoreToTime, err)
+		return false
+	}
+	defer bs.Close()
+
+	manifests, err := mysqlctl.FindPITRToTimeBackups(ctx, bs, params.Keyspace, params.Shard, restoreToTime)
+	if err != nil {
+		log.Infof("Can't restore to %v with the backups of %v/%v, falling back to a binlog server: %v", restoreToTime, params.Keyspace, params.Shard, err)
+		return false
+	}
+	log.Infof("Found %d backups to restore to %v, from full backup %v", len(manifests), restoreToTime, manifests[0].BackupName)
+	return true
+}
+
+// reportSnapshotPosition reports the GTID position the tablet was restored to, once
+// the binary logs up to the snapshot time of its keyspace have been applied.
+func (tm *TabletManager) reportSnapshotPosition(snapshotTime time.Time) error {
+	pos, err := tm.MysqlDaemon.PrimaryPosition()
+	if err != nil {
+		return vterrors.Wrap(err, "can't get the position restored to")
+	}
+	posStr := replication.EncodePosition(pos)
+	log.Infof("Restore: snapshot time %v resolved to position %v", snapshotTime, posStr)
+	statsRestoreBackupPosition.Set(posStr)
+	tm.restoreProgress.setBackup(mysqlctl.FormatRFC3339(snapshotTime), posStr)
+	return nil
 }
 
 // restoreToTimeFromBinlog restores to the snapshot time of the keyspace
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/acl"
+	"vitess.io/vitess/go/vt/servenv"
+)
+
+// The phases of a restore, as reported in RestoreProgress.
+const (
+	RestorePhaseNotStarted          = "NotStarted"
+	RestorePhaseSkipped             = "Skipped"
+	RestorePhaseWaitingForBackup    = "WaitingForBackup"
+	RestorePhaseRestoring           = "Restoring"
+	RestorePhaseStartingReplication = "StartingReplication"
+	RestorePhaseRestored            = "Restored"
+	RestorePhaseCatchingUp          = "CatchingUp"
+	RestorePhaseServing             = "Serving"
+	RestorePhaseFailed              = "Failed"
+)
+
+// RestoreProgress is the progress of the last restore of the tablet, from
+// the --restore_from_backup startup restore or the RestoreFromBackup RPC.
+// It is exported as JSON on /debug/restore_status.
+type RestoreProgress struct {
+	Phase          string
+	StartTime      time.Time
+	PhaseStartTime time.Time
+	// BackupTime and Position describe the backup that was restored.
+	BackupTime string
+	Position   string
+	// ReplicationLagSeconds is the last replication lag seen while catching
+	// up, or -1 if it is not known yet.
+	ReplicationLagSeconds    int64
+	MaxReplicationLagSeconds int64
+	Error                    string
+}
+
+// restoreProgress tracks the RestoreProgress of a TabletManager.
+type restoreProgress struct {
+	mu       sync.Mutex
+	progress RestoreProgress
+}
+
+// start resets the progress for a new restore.
+func (rp *restoreProgress) start() {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	now := time.Now()
+	rp.progress = RestoreProgress{
+		Phase:                 RestorePhaseRestoring,
+		StartTime:             now,
+		PhaseStartTime:        now,
+		ReplicationLagSeconds: -1,
+	}
+}
+
+func (rp *restoreProgress) setPhase(phase string) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.progress.Phase = phase
+	rp.progress.PhaseStartTime = time.Now()
+}
+
+func (rp *restoreProgress) setBackup(backupTime, position string) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.progress.BackupTime = backupTime
+	rp.progress.Position = position
+}
+
+func (rp *restoreProgress) setReplicationLag(lag, maxLag time.Duration) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.progress.ReplicationLagSeconds = int64(lag.Seconds())
+	rp.progress.MaxReplicationLagSeconds = int64(maxLag.Seconds())
+}
+
+func (rp *restoreProgress) setFailed(err error) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.progress.Phase = RestorePhaseFailed
+	rp.progress.PhaseStartTime = time.Now()
+	rp.progress.Error = err.Error()
+}
+
+func (rp *restoreProgress) get() RestoreProgress {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	progress := rp.progress
+	if progress.Phase == "" {
+		progress.Phase = RestorePhaseNotStarted
+		progress.ReplicationLagSeconds = -1
+	}
+	return progress
+}
+
+// RestoreProgress returns the progress of the last restore of the tablet.
+func (tm *TabletManager) RestoreProgress() RestoreProgress {
+	return tm.restoreProgress.get()
+}
+
+const restoreTemplate = `
+{{if ne .Phase "NotStarted"}}
+<table>
+  <tr><td>Phase</td><td>{{.Phase}} (since {{.PhaseStartTime.Format "2006-01-02 15:04:05"}})</td></tr>
+  <tr><td>Started</td><td>{{.StartTime.Format "2006-01-02 15:04:05"}}</td></tr>
+  {{if .BackupTime}}<tr><td>Backup</td><td>{{.BackupTime}} at {{.Position}}</td></tr>{{end}}
+  {{if ge .ReplicationLagSeconds 0}}<tr><td>Replication Lag</td><td>{{.ReplicationLagSeconds}}s (serving under {{.MaxReplicationLagSeconds}}s)</td></tr>{{end}}
+  {{if .Error}}<tr><td>Error</td><td>{{.Error}}</td></tr>{{end}}
+</table>
+<a href="/debug/restore_status">JSON Restore Status</a>
+{{else}}
+No restore was run by this tablet.
+{{end}}
+`
+
+// AddRestoreStatusPart adds the restore progress to the status page, and
+// serves it as JSON on /debug/restore_status.
+func (tm *TabletManager) AddRestoreStatusPart() {
+	servenv.AddStatusPart("Restore", restoreTemplate, func() any {
+		return tm.RestoreProgress()
+	})
+	servenv.HTTPHandleFunc("/debug/restore_status", func(w http.ResponseWriter, r *http.Request) {
+		if err := acl.CheckAccessHTTP(r, acl.MONITORING); err != nil {
+			acl.SendError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		b, err := json.MarshalIndent(tm.RestoreProgress(), "", " ")
+		if err != nil {
+			w.Write([]byte(err.Error()))
+			return
+		}
+		buf := bytes.NewBuffer(nil)
+		json.HTMLEscape(buf, b)
+		w.Write(buf.Bytes())
+	})
+}
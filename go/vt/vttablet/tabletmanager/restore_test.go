@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+)
+
+func TestWaitForReplicationCatchup(t *testing.T) {
+	oldMaxLag, oldTimeout, oldPollInterval := restoreCatchupMaxLag, restoreCatchupTimeout, restoreCatchupPollInterval
+	defer func() {
+		restoreCatchupMaxLag, restoreCatchupTimeout, restoreCatchupPollInterval = oldMaxLag, oldTimeout, oldPollInterval
+	}()
+	restoreCatchupPollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tm := newTestTM(t, ts, 1, "ks", "0")
+	defer tm.Stop()
+	fmd := tm.MysqlDaemon.(*mysqlctl.FakeMysqlDaemon)
+
+	// Disabled: doesn't even look at replication.
+	restoreCatchupMaxLag = 0
+	fmd.ReplicationStatusError = assert.AnError
+	require.NoError(t, tm.waitForReplicationCatchup(ctx))
+
+	// No replication configured.
+	restoreCatchupMaxLag = 10 * time.Second
+	fmd.ReplicationStatusError = mysql.ErrNotReplica
+	require.NoError(t, tm.waitForReplicationCatchup(ctx))
+
+	// Replication never catches up.
+	fmd.ReplicationStatusError = nil
+	fmd.Replicating = true
+	fmd.IOThreadRunning = true
+	fmd.ReplicationLagSeconds = 60
+	restoreCatchupTimeout = 100 * time.Millisecond
+	err := tm.waitForReplicationCatchup(ctx)
+	require.ErrorContains(t, err, "replication did not catch up")
+	progress := tm.RestoreProgress()
+	assert.Equal(t, RestorePhaseCatchingUp, progress.Phase)
+	assert.EqualValues(t, 60, progress.ReplicationLagSeconds)
+	assert.EqualValues(t, 10, progress.MaxReplicationLagSeconds)
+
+	// Replication caught up.
+	restoreCatchupTimeout = 0
+	fmd.ReplicationLagSeconds = 5
+	require.NoError(t, tm.waitForReplicationCatchup(ctx))
+	assert.EqualValues(t, 5, tm.RestoreProgress().ReplicationLagSeconds)
+}
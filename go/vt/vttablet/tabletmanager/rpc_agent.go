@@ -79,6 +79,10 @@ type RPCTM interface {
 
 	ExecuteFetchAsApp(ctx context.Context, req *tabletmanagerdatapb.ExecuteFetchAsAppRequest) (*querypb.QueryResult, error)
 
+	KillQueries(ctx context.Context, req *tabletmanagerdatapb.KillQueriesRequest) (*tabletmanagerdatapb.KillQueriesResponse, error)
+
+	UpdateQueryFingerprintRules(ctx context.Context, req *tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest) (*tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse, error)
+
 	// Replication related methods
 	PrimaryStatus(ctx context.Context) (*replicationdatapb.PrimaryStatus, error)
 
@@ -152,4 +156,18 @@ type RPCTM interface {
 
 	// Throttler
 	CheckThrottler(ctx context.Context, request *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error)
+
+	// Distributed transactions
+	GetUnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error)
+
+	ResolveTransaction(ctx context.Context, dtid string) error
+
+	// Messaging
+	RequeueDeadLetters(ctx context.Context, table string, ids []string) (int64, error)
+
+	// Diagnostics
+	RunDiagnosticQuery(ctx context.Context, req *tabletmanagerdatapb.RunDiagnosticQueryRequest) (*tabletmanagerdatapb.RunDiagnosticQueryResponse, error)
+
+	// MySQL upgrade
+	UpgradeMysql(ctx context.Context, req *tabletmanagerdatapb.UpgradeMysqlRequest) (*tabletmanagerdatapb.UpgradeMysqlResponse, error)
 }
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+const (
+	innodbStatusQuery = "SHOW ENGINE INNODB STATUS"
+	processlistQuery  = "SELECT id, user, host, db, command, time, state, info FROM information_schema.processlist ORDER BY id"
+
+	// innodbStatusHeader is the name of the section of the InnoDB status that
+	// precedes the first named section.
+	innodbStatusHeader = "HEADER"
+	innodbStatusEnd    = "END OF INNODB MONITOR OUTPUT"
+)
+
+// RunDiagnosticQuery runs one of the allow-listed, read-only diagnostic
+// queries on MySQL, and returns its result as a structured proto. Only the
+// queries of RunDiagnosticQueryRequest_Query can be run, so callers do not
+// need MySQL credentials to troubleshoot a tablet.
+func (tm *TabletManager) RunDiagnosticQuery(ctx context.Context, req *tabletmanagerdatapb.RunDiagnosticQueryRequest) (*tabletmanagerdatapb.RunDiagnosticQueryResponse, error) {
+	if err := tm.waitForGrantsToHaveApplied(ctx); err != nil {
+		return nil, err
+	}
+
+	switch req.Query {
+	case tabletmanagerdatapb.RunDiagnosticQueryRequest_INNODB_STATUS:
+		qr, err := tm.MysqlDaemon.FetchSuperQuery(ctx, innodbStatusQuery)
+		if err != nil {
+			return nil, err
+		}
+		if len(qr.Rows) != 1 || len(qr.Rows[0]) != 3 {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for %s: %d rows", innodbStatusQuery, len(qr.Rows))
+		}
+		return &tabletmanagerdatapb.RunDiagnosticQueryResponse{
+			InnodbStatus: parseInnodbStatus(qr.Rows[0][2].ToString()),
+		}, nil
+	case tabletmanagerdatapb.RunDiagnosticQueryRequest_REPLICATION_STATUS:
+		status, err := tm.MysqlDaemon.ReplicationStatus()
+		if err != nil {
+			return nil, err
+		}
+		return &tabletmanagerdatapb.RunDiagnosticQueryResponse{
+			ReplicationStatus: replication.ReplicationStatusToProto(status),
+		}, nil
+	case tabletmanagerdatapb.RunDiagnosticQueryRequest_PROCESSLIST:
+		qr, err := tm.MysqlDaemon.FetchSuperQuery(ctx, processlistQuery)
+		if err != nil {
+			return nil, err
+		}
+		resp := &tabletmanagerdatapb.RunDiagnosticQueryResponse{
+			Processlist: make([]*tabletmanagerdatapb.RunDiagnosticQueryResponse_Process, 0, len(qr.Rows)),
+		}
+		for _, row := range qr.Rows {
+			if len(row) != 8 {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INTERNAL, "unexpected result for the processlist: %d columns", len(row))
+			}
+			id, err := row[0].ToCastUint64()
+			if err != nil {
+				return nil, err
+			}
+			time, err := row[5].ToCastInt64()
+			if err != nil {
+				return nil, err
+			}
+			process := &tabletmanagerdatapb.RunDiagnosticQueryResponse_Process{
+				Id:      id,
+				User:    row[1].ToString(),
+				Host:    row[2].ToString(),
+				Db:      row[3].ToString(),
+				Command: row[4].ToString(),
+				Time:    time,
+				State:   row[6].ToString(),
+			}
+			if req.IncludeQueryText {
+				process.Info = row[7].ToString()
+			}
+			resp.Processlist = append(resp.Processlist, process)
+		}
+		return resp, nil
+	}
+	return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown diagnostic query %v", req.Query)
+}
+
+// parseInnodbStatus splits the output of SHOW ENGINE INNODB STATUS into its
+// sections. A section starts with its name between two lines of dashes.
+func parseInnodbStatus(status string) []*tabletmanagerdatapb.RunDiagnosticQueryResponse_InnodbStatusSection {
+	lines := strings.Split(status, "\n")
+	isRule := func(line string) bool {
+		return line != "" && strings.Trim(line, "-") == ""
+	}
+
+	var (
+		sections []*tabletmanagerdatapb.RunDiagnosticQueryResponse_InnodbStatusSection
+		name     = innodbStatusHeader
+		text     []string
+	)
+	flush := func() {
+		if body := strings.TrimSpace(strings.Join(text, "\n")); body != "" || name != innodbStatusHeader {
+			sections = append(sections, &tabletmanagerdatapb.RunDiagnosticQueryResponse_InnodbStatusSection{Name: name, Text: body})
+		}
+		text = nil
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if i+1 < len(lines) && isRule(line) && strings.TrimSpace(lines[i+1]) == innodbStatusEnd {
+			// The end of the output is followed by a banner rather than by a
+			// line of dashes.
+			break
+		}
+		if i+2 < len(lines) && isRule(line) && isRule(lines[i+2]) && !isRule(lines[i+1]) {
+			flush()
+			name = strings.TrimSpace(lines[i+1])
+			i += 2
+			continue
+		}
+		if strings.Trim(line, "=") == "" && line != "" {
+			// The banner around the title of the output.
+			continue
+		}
+		text = append(text, line)
+	}
+	flush()
+	return sections
+}
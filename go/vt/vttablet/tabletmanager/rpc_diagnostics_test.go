@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
+	"vitess.io/vitess/go/vt/mysqlctl"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+)
+
+const testInnodbStatus = `
+=====================================
+2024-05-02 10:11:12 0x7f1c INNODB MONITOR OUTPUT
+=====================================
+Per second averages calculated from the last 5 seconds
+-----------------
+BACKGROUND THREAD
+-----------------
+srv_master_thread loops: 10 srv_active, 0 srv_shutdown, 100 srv_idle
+----------
+SEMAPHORES
+----------
+OS WAIT ARRAY INFO: reservation count 20
+------------
+TRANSACTIONS
+------------
+Trx id counter 1234
+---TRANSACTION 421, not started
+0 lock struct(s), heap size 1128, 0 row lock(s)
+----------------------------
+END OF INNODB MONITOR OUTPUT
+============================
+`
+
+func TestParseInnodbStatus(t *testing.T) {
+	sections := parseInnodbStatus(testInnodbStatus)
+	utils.MustMatch(t, []*tabletmanagerdatapb.RunDiagnosticQueryResponse_InnodbStatusSection{
+		{Name: "HEADER", Text: "2024-05-02 10:11:12 0x7f1c INNODB MONITOR OUTPUT\nPer second averages calculated from the last 5 seconds"},
+		{Name: "BACKGROUND THREAD", Text: "srv_master_thread loops: 10 srv_active, 0 srv_shutdown, 100 srv_idle"},
+		{Name: "SEMAPHORES", Text: "OS WAIT ARRAY INFO: reservation count 20"},
+		{Name: "TRANSACTIONS", Text: "Trx id counter 1234\n---TRANSACTION 421, not started\n0 lock struct(s), heap size 1128, 0 row lock(s)"},
+	}, sections)
+}
+
+func TestTabletManager_RunDiagnosticQuery(t *testing.T) {
+	ctx := context.Background()
+	db := fakesqldb.New(t)
+	defer db.Close()
+	daemon := mysqlctl.NewFakeMysqlDaemon(db)
+	daemon.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		innodbStatusQuery: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("Type|Name|Status", "varchar|varchar|varchar"),
+			"InnoDB||"+testInnodbStatus,
+		),
+		processlistQuery: sqltypes.MakeTestResult(
+			sqltypes.MakeTestFields("id|user|host|db|command|time|state|info", "uint64|varchar|varchar|varchar|varchar|int64|varchar|varchar"),
+			"5|vt_app|localhost:1234|vt_ks|Query|2|executing|select * from t where secret = 'x'",
+			"6|vt_dba|localhost|null|Sleep|30||null",
+		),
+	}
+	daemon.CurrentSourceHost = "primary"
+	daemon.CurrentSourcePort = 3306
+	daemon.Replicating = true
+	daemon.IOThreadRunning = true
+
+	tm := &TabletManager{
+		MysqlDaemon:            daemon,
+		_waitForGrantsComplete: make(chan struct{}),
+	}
+	close(tm._waitForGrantsComplete)
+
+	resp, err := tm.RunDiagnosticQuery(ctx, &tabletmanagerdatapb.RunDiagnosticQueryRequest{
+		Query: tabletmanagerdatapb.RunDiagnosticQueryRequest_INNODB_STATUS,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.InnodbStatus, 4)
+	assert.Equal(t, "TRANSACTIONS", resp.InnodbStatus[3].Name)
+
+	resp, err = tm.RunDiagnosticQuery(ctx, &tabletmanagerdatapb.RunDiagnosticQueryRequest{
+		Query: tabletmanagerdatapb.RunDiagnosticQueryRequest_REPLICATION_STATUS,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "primary", resp.ReplicationStatus.SourceHost)
+	assert.Equal(t, int32(3306), resp.ReplicationStatus.SourcePort)
+
+	processlist := []*tabletmanagerdatapb.RunDiagnosticQueryResponse_Process{
+		{Id: 5, User: "vt_app", Host: "localhost:1234", Db: "vt_ks", Command: "Query", Time: 2, State: "executing"},
+		{Id: 6, User: "vt_dba", Host: "localhost", Command: "Sleep", Time: 30},
+	}
+	resp, err = tm.RunDiagnosticQuery(ctx, &tabletmanagerdatapb.RunDiagnosticQueryRequest{
+		Query: tabletmanagerdatapb.RunDiagnosticQueryRequest_PROCESSLIST,
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, processlist, resp.Processlist, "the query text must be left out by default")
+
+	processlist[0].Info = "select * from t where secret = 'x'"
+	resp, err = tm.RunDiagnosticQuery(ctx, &tabletmanagerdatapb.RunDiagnosticQueryRequest{
+		Query:            tabletmanagerdatapb.RunDiagnosticQueryRequest_PROCESSLIST,
+		IncludeQueryText: true,
+	})
+	require.NoError(t, err)
+	utils.MustMatch(t, processlist, resp.Processlist)
+
+	_, err = tm.RunDiagnosticQuery(ctx, &tabletmanagerdatapb.RunDiagnosticQueryRequest{})
+	assert.ErrorContains(t, err, "unknown diagnostic query UNKNOWN")
+}
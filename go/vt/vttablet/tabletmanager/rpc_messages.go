@@ -0,0 +1,27 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+)
+
+// RequeueDeadLetters moves messages from the dead-letter table of a message
+// table back to it, and returns the number of messages that were requeued.
+func (tm *TabletManager) RequeueDeadLetters(ctx context.Context, table string, ids []string) (int64, error) {
+	return tm.QueryServiceControl.RequeueDeadLetters(ctx, table, ids)
+}
@@ -20,11 +20,14 @@ import (
 	"context"
 
 	"vitess.io/vitess/go/constants/sidecar"
+	"vitess.io/vitess/go/protoutil"
 	"vitess.io/vitess/go/sqlescape"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
@@ -291,3 +294,80 @@ func (tm *TabletManager) ExecuteQuery(ctx context.Context, req *tabletmanagerdat
 	result, err := tm.QueryServiceControl.QueryService().Execute(ctx, target, uq, nil, 0, 0, nil)
 	return sqltypes.ResultToProto3(result), err
 }
+
+// KillQueries kills the queries running on the tablet that match the request,
+// and returns them.
+func (tm *TabletManager) KillQueries(ctx context.Context, req *tabletmanagerdatapb.KillQueriesRequest) (*tabletmanagerdatapb.KillQueriesResponse, error) {
+	killed, err := tm.QueryServiceControl.KillQueries(ctx, tabletserver.QueryFilter{
+		Query:    req.Query,
+		PlanType: req.PlanType,
+		Caller:   req.Caller,
+	}, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &tabletmanagerdatapb.KillQueriesResponse{
+		Queries: make([]*tabletmanagerdatapb.KillQueriesResponse_Query, 0, len(killed)),
+	}
+	for _, q := range killed {
+		resp.Queries = append(resp.Queries, &tabletmanagerdatapb.KillQueriesResponse_Query{
+			ConnectionId: q.ConnID,
+			Fingerprint:  q.Fingerprint,
+			PlanType:     q.PlanType,
+			Caller:       q.Caller,
+			Elapsed:      protoutil.DurationToProto(q.Elapsed),
+		})
+	}
+	return resp, nil
+}
+
+// UpdateQueryFingerprintRules updates the query fingerprint rules of the
+// tablet, and returns the rules after the update.
+func (tm *TabletManager) UpdateQueryFingerprintRules(ctx context.Context, req *tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest) (*tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse, error) {
+	setRules := make([]tabletserver.FingerprintRule, 0, len(req.SetRules))
+	for _, r := range req.SetRules {
+		rule := tabletserver.FingerprintRule{
+			Fingerprint:  r.Fingerprint,
+			ErrorMessage: r.ErrorMessage,
+		}
+		switch r.Action {
+		case tabletmanagerdatapb.QueryFingerprintRule_PIN:
+			planType, ok := planbuilder.PlanByNameIC(r.PlanType)
+			if !ok {
+				return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "unknown plan type %q to pin %q to", r.PlanType, r.Fingerprint)
+			}
+			rule.Action = tabletserver.FingerprintPin
+			rule.PlanType = planType
+		case tabletmanagerdatapb.QueryFingerprintRule_BLOCK:
+			rule.Action = tabletserver.FingerprintBlock
+		default:
+			return nil, vterrors.Errorf(vtrpc.Code_INVALID_ARGUMENT, "unknown action %v for %q", r.Action, r.Fingerprint)
+		}
+		setRules = append(setRules, rule)
+	}
+
+	rules, err := tm.QueryServiceControl.UpdateQueryFingerprintRules(ctx, setRules, req.DeleteQueries)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse{
+		Rules: make([]*tabletmanagerdatapb.QueryFingerprintRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		r := &tabletmanagerdatapb.QueryFingerprintRule{
+			Fingerprint:  rule.Fingerprint,
+			ErrorMessage: rule.ErrorMessage,
+		}
+		switch rule.Action {
+		case tabletserver.FingerprintPin:
+			r.Action = tabletmanagerdatapb.QueryFingerprintRule_PIN
+			r.PlanType = rule.PlanType.String()
+		case tabletserver.FingerprintBlock:
+			r.Action = tabletmanagerdatapb.QueryFingerprintRule_BLOCK
+		}
+		resp.Rules = append(resp.Rules, r)
+	}
+	return resp, nil
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"time"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+)
+
+// GetUnresolvedTransactions returns the distributed transactions managed by
+// this tablet that were not resolved within abandonAge.
+func (tm *TabletManager) GetUnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error) {
+	return tm.QueryServiceControl.UnresolvedTransactions(ctx, abandonAge)
+}
+
+// ResolveTransaction asks the 2PC coordinator to resolve the distributed
+// transaction right away.
+func (tm *TabletManager) ResolveTransaction(ctx context.Context, dtid string) error {
+	return tm.QueryServiceControl.ResolveTransaction(ctx, dtid)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// UpgradeMysql restarts MySQL with the MySQL binaries installed on the host
+// of the tablet, and upgrades its data directory to them, see
+// mysqlctl.Upgrade. Replicas must be taken out of serving first, and primaries
+// are only upgraded when the request allows it. Replication is restarted
+// after the upgrade if it was running before.
+func (tm *TabletManager) UpgradeMysql(ctx context.Context, req *tabletmanagerdatapb.UpgradeMysqlRequest) (*tabletmanagerdatapb.UpgradeMysqlResponse, error) {
+	if tm.Cnf == nil {
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "cannot upgrade MySQL without my.cnf")
+	}
+
+	if err := tm.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer tm.unlock()
+
+	tablet := tm.Tablet()
+	switch tablet.Type {
+	case topodatapb.TabletType_PRIMARY:
+		if !req.AllowPrimary {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "tablet %s is a primary, reparent its shard to an upgraded replica first", topoproto.TabletAliasString(tablet.Alias))
+		}
+	case topodatapb.TabletType_REPLICA, topodatapb.TabletType_RDONLY:
+		return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "tablet %s is serving as %s, change its type to DRAINED first", topoproto.TabletAliasString(tablet.Alias), topoproto.TabletTypeLString(tablet.Type))
+	}
+
+	versionBefore, err := tm.MysqlDaemon.GetVersionString(ctx)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get the version of MySQL")
+	}
+	replicating := false
+	if tablet.Type != topodatapb.TabletType_PRIMARY {
+		status, err := tm.MysqlDaemon.ReplicationStatus()
+		replicating = err == nil && status.SQLHealthy()
+	}
+
+	log.Infof("Upgrading MySQL %s of tablet %s", versionBefore, topoproto.TabletAliasString(tablet.Alias))
+	if err := mysqlctl.Upgrade(ctx, tm.MysqlDaemon, tm.Cnf, mysqlShutdownTimeout); err != nil {
+		return nil, err
+	}
+
+	if replicating {
+		if err := tm.MysqlDaemon.StartReplication(tm.hookExtraEnv()); err != nil {
+			return nil, vterrors.Wrapf(err, "failed to restart replication after the upgrade")
+		}
+	}
+
+	versionAfter, err := tm.MysqlDaemon.GetVersionString(ctx)
+	if err != nil {
+		return nil, vterrors.Wrapf(err, "failed to get the version of MySQL after the upgrade")
+	}
+	log.Infof("Upgraded MySQL of tablet %s from %s to %s", topoproto.TabletAliasString(tablet.Alias), versionBefore, versionAfter)
+
+	return &tabletmanagerdatapb.UpgradeMysqlResponse{
+		VersionBefore: versionBefore,
+		VersionAfter:  versionAfter,
+	}, nil
+}
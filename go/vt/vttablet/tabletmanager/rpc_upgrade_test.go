@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+
+	tabletmanagerdatapb "vitess.io/vitess/go/vt/proto/tabletmanagerdata"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestUpgradeMysql(t *testing.T) {
+	// No mysqld_upgrade hook, the fake daemon is restarted as is.
+	t.Setenv("VTROOT", t.TempDir())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ts := memorytopo.NewServer(ctx, "cell1")
+	tm := newTestTM(t, ts, 1, "ks", "0")
+	defer tm.Stop()
+
+	_, err := tm.UpgradeMysql(ctx, &tabletmanagerdatapb.UpgradeMysqlRequest{})
+	assert.ErrorContains(t, err, "cannot upgrade MySQL without my.cnf")
+
+	tm.Cnf = &mysqlctl.Mycnf{}
+	mysqld := tm.MysqlDaemon.(*mysqlctl.FakeMysqlDaemon)
+	mysqld.Running = true
+	mysqld.Version = "8.0.36"
+	mysqld.Replicating = true
+	mysqld.IOThreadRunning = true
+	mysqld.ExpectedExecuteSuperQueryList = []string{"START SLAVE"}
+
+	_, err = tm.UpgradeMysql(ctx, &tabletmanagerdatapb.UpgradeMysqlRequest{})
+	assert.ErrorContains(t, err, "tablet cell1-0000000001 is serving as replica, change its type to DRAINED first")
+
+	require.NoError(t, tm.ChangeType(ctx, topodatapb.TabletType_DRAINED, false))
+	resp, err := tm.UpgradeMysql(ctx, &tabletmanagerdatapb.UpgradeMysqlRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "8.0.36", resp.VersionBefore)
+	assert.Equal(t, "8.0.36", resp.VersionAfter)
+	assert.True(t, mysqld.Running)
+	require.NoError(t, mysqld.CheckSuperQueryList(), "replication must be restarted after the upgrade")
+
+	require.NoError(t, tm.ChangeType(ctx, topodatapb.TabletType_PRIMARY, false))
+	_, err = tm.UpgradeMysql(ctx, &tabletmanagerdatapb.UpgradeMysqlRequest{})
+	assert.ErrorContains(t, err, "tablet cell1-0000000001 is a primary, reparent its shard to an upgraded replica first")
+
+	resp, err = tm.UpgradeMysql(ctx, &tabletmanagerdatapb.UpgradeMysqlRequest{AllowPrimary: true})
+	require.NoError(t, err)
+	assert.Equal(t, "8.0.36", resp.VersionAfter)
+}
@@ -168,6 +168,9 @@ type TabletManager struct {
 	// when we transition back from something like PRIMARY.
 	baseTabletType topodatapb.TabletType
 
+	// restoreProgress is the progress of the last restore, for the status page.
+	restoreProgress restoreProgress
+
 	// actionSema is there to run only one action at a time.
 	// This semaphore can be held for long periods of time (hours),
 	// like in the case of a restore. This semaphore must be obtained
@@ -821,6 +824,12 @@ func (tm *TabletManager) handleRestore(ctx context.Context, config *tabletenv.Ta
 				log.Exitf("RestoreFromBackup failed: %v", err)
 			}
 
+			// Don't serve queries until replication has caught up.
+			if err := tm.waitForReplicationCatchup(ctx); err != nil {
+				tm.restoreProgress.setFailed(err)
+				log.Exitf("RestoreFromBackup failed: %v", err)
+			}
+
 			// Make sure we have the correct privileges for the DBA user before we start the state manager.
 			err := tm.waitForDBAGrants(config, dbaGrantWaitTime)
 			if err != nil {
@@ -829,6 +838,7 @@ func (tm *TabletManager) handleRestore(ctx context.Context, config *tabletenv.Ta
 
 			// Open the state manager after restore is done.
 			tm.tmState.Open()
+			tm.restoreProgress.setPhase(RestorePhaseServing)
 		}()
 		return true, nil
 	}
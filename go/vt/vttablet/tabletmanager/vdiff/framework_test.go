@@ -526,7 +526,7 @@ func newTestVDiffEnv(t *testing.T) *testVDiffEnv {
 
 	tstenv.KeyspaceName = vdiffDBName
 
-	vdiffenv.vse = vstreamer.NewEngine(tstenv.TabletEnv, tstenv.SrvTopo, vdiffenv.se, nil, tstenv.Cells[0])
+	vdiffenv.vse = vstreamer.NewEngine(tstenv.TabletEnv, tstenv.SrvTopo, vdiffenv.se, nil, nil, tstenv.Cells[0])
 	vdiffenv.vse.InitDBConfig(tstenv.KeyspaceName, tstenv.ShardName)
 	vdiffenv.vse.Open()
 
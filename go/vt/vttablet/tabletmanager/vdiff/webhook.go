@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+)
+
+var (
+	mismatchWebhookURL     string
+	mismatchWebhookTimeout = 10 * time.Second
+)
+
+func registerWebhookFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&mismatchWebhookURL, "vdiff-mismatch-webhook-url", mismatchWebhookURL, "If set, the URL a JSON summary of a VDiff is POSTed to when the VDiff completes on this tablet with mismatches.")
+	fs.DurationVar(&mismatchWebhookTimeout, "vdiff-mismatch-webhook-timeout", mismatchWebhookTimeout, "Timeout of the requests to the --vdiff-mismatch-webhook-url.")
+}
+
+func init() {
+	servenv.OnParseFor("vtcombo", registerWebhookFlags)
+	servenv.OnParseFor("vttablet", registerWebhookFlags)
+}
+
+// MismatchNotification is the body of the request sent to the
+// --vdiff-mismatch-webhook-url when a VDiff found mismatches on a shard.
+type MismatchNotification struct {
+	Keyspace string        `json:"keyspace"`
+	Shard    string        `json:"shard"`
+	Tablet   string        `json:"tablet"`
+	Workflow string        `json:"workflow"`
+	UUID     string        `json:"uuid"`
+	Tables   []*DiffReport `json:"tables"`
+}
+
+// notifyMismatches sends the reports of the tables that did not match to the
+// --vdiff-mismatch-webhook-url, if any. Failing to notify doesn't fail the
+// VDiff: the error is logged, and recorded in the log of the VDiff.
+func (ct *controller) notifyMismatches(ctx context.Context, dbClient binlogplayer.DBClient) {
+	if mismatchWebhookURL == "" {
+		return
+	}
+	if err := ct.sendMismatchNotification(ctx, dbClient, mismatchWebhookURL); err != nil {
+		log.Errorf("Failed to notify the mismatches of vdiff %s: %v", ct.uuid, err)
+		insertVDiffLog(ctx, dbClient, ct.id, fmt.Sprintf("Failed to notify mismatches: %s", err))
+	}
+}
+
+func (ct *controller) sendMismatchNotification(ctx context.Context, dbClient binlogplayer.DBClient, url string) error {
+	query, err := sqlparser.ParseAndBind(sqlGetMismatchedTables, sqltypes.Int64BindVariable(ct.id))
+	if err != nil {
+		return err
+	}
+	qr, err := dbClient.ExecuteFetch(query, -1)
+	if err != nil {
+		return err
+	}
+	if len(qr.Rows) == 0 {
+		return nil
+	}
+
+	notification := &MismatchNotification{
+		Keyspace: ct.vde.thisTablet.Keyspace,
+		Shard:    ct.vde.thisTablet.Shard,
+		Tablet:   topoproto.TabletAliasString(ct.vde.thisTablet.Alias),
+		Workflow: ct.workflow,
+		UUID:     ct.uuid,
+	}
+	for _, row := range qr.Named().Rows {
+		dr := &DiffReport{}
+		if report := row.AsBytes("report", nil); len(report) > 0 {
+			if err := json.Unmarshal(report, dr); err != nil {
+				return err
+			}
+		}
+		dr.TableName = row.AsString("table_name", "")
+		notification.Tables = append(notification.Tables, dr)
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mismatchWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	log.Infof("Notified the mismatches of vdiff %s on %d table(s)", ct.uuid, len(notification.Tables))
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vdiff
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestSendMismatchNotification(t *testing.T) {
+	var received *MismatchNotification
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received = &MismatchNotification{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	ct := &controller{
+		id:       1,
+		uuid:     "a037a9e2-5628-11ee-8c99-0242ac120002",
+		workflow: "commerce2customer",
+		vde: &Engine{thisTablet: &topodatapb.Tablet{
+			Alias:    &topodatapb.TabletAlias{Cell: "zone1", Uid: 100},
+			Keyspace: "customer",
+			Shard:    "-80",
+		}},
+	}
+	query := "select table_name as table_name, report as report from _vt.vdiff_table where vdiff_id = 1 and mismatch = 1 order by table_name"
+	fields := sqltypes.MakeTestFields("table_name|report", "varbinary|json")
+	dbClient := binlogplayer.NewMockDBClient(t)
+	ctx := context.Background()
+
+	// Nothing is sent when all of the tables matched.
+	dbClient.ExpectRequest(query, sqltypes.MakeTestResult(fields), nil)
+	require.NoError(t, ct.sendMismatchNotification(ctx, dbClient, server.URL))
+	require.Nil(t, received)
+
+	dbClient.ExpectRequest(query, sqltypes.MakeTestResult(fields,
+		`customer|{"TableName":"customer","ProcessedRows":2,"MatchingRows":1,"MismatchedRows":1}`,
+	), nil)
+	require.NoError(t, ct.sendMismatchNotification(ctx, dbClient, server.URL))
+	require.NotNil(t, received)
+	require.Equal(t, "customer", received.Keyspace)
+	require.Equal(t, "-80", received.Shard)
+	require.Equal(t, "zone1-0000000100", received.Tablet)
+	require.Equal(t, "commerce2customer", received.Workflow)
+	require.Equal(t, ct.uuid, received.UUID)
+	require.Len(t, received.Tables, 1)
+	require.Equal(t, "customer", received.Tables[0].TableName)
+	require.EqualValues(t, 1, received.Tables[0].MismatchedRows)
+
+	status = http.StatusInternalServerError
+	dbClient.ExpectRequest(query, sqltypes.MakeTestResult(fields,
+		`customer|{"TableName":"customer","ProcessedRows":2,"MatchingRows":1,"MismatchedRows":1}`,
+	), nil)
+	require.ErrorContains(t, ct.sendMismatchNotification(ctx, dbClient, server.URL), "500")
+	dbClient.Wait()
+}
@@ -327,6 +327,7 @@ func (wd *workflowDiffer) markIfCompleted(ctx context.Context, dbClient binlogpl
 		if err := wd.ct.updateState(dbClient, CompletedState, nil); err != nil {
 			return err
 		}
+		wd.ct.notifyMismatches(ctx, dbClient)
 	}
 	return nil
 }
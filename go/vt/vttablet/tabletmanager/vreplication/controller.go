@@ -243,7 +243,7 @@ func (ct *controller) runBlp(ctx context.Context) (err error) {
 		var vsClient VStreamerClient
 		var err error
 		if name := ct.source.GetExternalMysql(); name != "" {
-			vsClient, err = ct.vre.ec.Get(name)
+			vsClient, err = ct.vre.ec.Get(name, ct.source.GetExternalMysqlOptions())
 			if err != nil {
 				return err
 			}
@@ -18,8 +18,10 @@ package vreplication
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/sqltypes"
 	"vitess.io/vitess/go/vt/dbconfigs"
 	"vitess.io/vitess/go/vt/grpcclient"
@@ -35,6 +37,7 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/vstreamer"
+	"vitess.io/vitess/go/vt/vttls"
 )
 
 var (
@@ -79,24 +82,29 @@ func (ec *externalConnector) Close() {
 	ec.connectors = make(map[string]*mysqlConnector)
 }
 
-func (ec *externalConnector) Get(name string) (*mysqlConnector, error) {
+// Get returns the connector to the external mysql of the given name, with
+// the settings of its config overridden by opts, which can be nil.
+func (ec *externalConnector) Get(name string, opts *binlogdatapb.ExternalMysqlOptions) (*mysqlConnector, error) {
 	ec.mu.Lock()
 	defer ec.mu.Unlock()
-	if c, ok := ec.connectors[name]; ok {
+	key := externalConnectorKey(name, opts)
+	if c, ok := ec.connectors[key]; ok {
 		return c, nil
 	}
 
 	// Construct
-	config := tabletenv.NewDefaultConfig()
-	config.DB = ec.dbconfigs[name]
-	if config.DB == nil {
+	dbcfgs := ec.dbconfigs[name]
+	if dbcfgs == nil {
 		return nil, vterrors.Errorf(vtrpcpb.Code_NOT_FOUND, "external mysqlConnector %v not found", name)
 	}
+	config := tabletenv.NewDefaultConfig()
+	config.DB = applyExternalMysqlOptions(dbcfgs, opts, ec.env)
 	c := &mysqlConnector{}
 	c.env = tabletenv.NewEnv(ec.env, config, name)
 	c.se = schema.NewEngine(c.env)
-	c.vstreamer = vstreamer.NewEngine(c.env, nil, c.se, nil, "")
+	c.vstreamer = vstreamer.NewEngine(c.env, nil, c.se, nil, nil, "")
 	c.vstreamer.InitDBConfig("", "")
+	c.vstreamer.SetBinlogServerID(opts.GetServerId())
 	c.se.InitDBConfig(c.env.Config().DB.AllPrivsWithDB())
 
 	// Open
@@ -106,10 +114,58 @@ func (ec *externalConnector) Get(name string) (*mysqlConnector, error) {
 	c.vstreamer.Open()
 
 	// Register
-	ec.connectors[name] = c
+	ec.connectors[key] = c
 	return c, nil
 }
 
+// externalConnectorKey returns the key of the connector to the external mysql
+// of the given name with the given options. The streams that connect to the
+// same external mysql with different options each get their own connector.
+func externalConnectorKey(name string, opts *binlogdatapb.ExternalMysqlOptions) string {
+	if opts == nil {
+		return name
+	}
+	return fmt.Sprintf("%s/%t/%d/%s/%s/%s/%s/%s", name, opts.UseFilePosition, opts.ServerId,
+		opts.SslMode, opts.SslCa, opts.SslCert, opts.SslKey, opts.ServerName)
+}
+
+// applyExternalMysqlOptions returns the config of the connection to an
+// external mysql with the settings of opts applied to it. The TLS settings
+// apply to all the users of the connection.
+func applyExternalMysqlOptions(dbcfgs *dbconfigs.DBConfigs, opts *binlogdatapb.ExternalMysqlOptions, env *vtenv.Environment) *dbconfigs.DBConfigs {
+	if opts == nil {
+		return dbcfgs
+	}
+	dbcfgs = dbcfgs.Clone()
+	if opts.UseFilePosition {
+		// The positions of the sources without GTIDs are their binlog
+		// file and position.
+		dbcfgs.Flavor = replication.FilePosFlavorID
+	}
+	if opts.SslMode != "" || opts.SslCa != "" || opts.SslCert != "" || opts.SslKey != "" || opts.ServerName != "" {
+		if opts.SslMode != "" {
+			dbcfgs.SslMode = vttls.SslMode(opts.SslMode)
+		}
+		if opts.SslCa != "" {
+			dbcfgs.SslCa = opts.SslCa
+		}
+		if opts.SslCert != "" {
+			dbcfgs.SslCert = opts.SslCert
+		}
+		if opts.SslKey != "" {
+			dbcfgs.SslKey = opts.SslKey
+		}
+		if opts.ServerName != "" {
+			dbcfgs.ServerName = opts.ServerName
+		}
+		for _, uc := range []*dbconfigs.UserConfig{&dbcfgs.App, &dbcfgs.Dba, &dbcfgs.Filtered, &dbcfgs.Repl, &dbcfgs.Appdebug, &dbcfgs.Allprivs} {
+			uc.UseSSL = true
+		}
+	}
+	dbcfgs.InitWithSocket("", env.CollationEnv())
+	return dbcfgs
+}
+
 //-----------------------------------------------------------
 
 type mysqlConnector struct {
@@ -21,10 +21,15 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/vt/binlog/binlogplayer"
+	"vitess.io/vitess/go/vt/dbconfigs"
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
+	"vitess.io/vitess/go/vt/vtenv"
 	qh "vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication/queryhistory"
+	"vitess.io/vitess/go/vt/vttls"
 )
 
 func TestExternalConnectorCopy(t *testing.T) {
@@ -170,6 +175,47 @@ func TestExternalConnectorPlay(t *testing.T) {
 	}, pos)
 }
 
+func TestApplyExternalMysqlOptions(t *testing.T) {
+	venv := vtenv.NewTestEnv()
+	dbcfgs := &dbconfigs.DBConfigs{
+		Host:     "rds.example.com",
+		Port:     3306,
+		DBName:   "commerce",
+		SslMode:  vttls.Preferred,
+		Filtered: dbconfigs.UserConfig{User: "vt_filtered"},
+	}
+	dbcfgs.InitWithSocket("", venv.CollationEnv())
+
+	// The config is used as is without options.
+	assert.Same(t, dbcfgs, applyExternalMysqlOptions(dbcfgs, nil, venv))
+
+	got := applyExternalMysqlOptions(dbcfgs, &binlogdatapb.ExternalMysqlOptions{
+		UseFilePosition: true,
+		SslMode:         string(vttls.VerifyIdentity),
+		SslCa:           "/etc/ssl/rds-ca.pem",
+		ServerName:      "rds.example.com",
+	}, venv)
+	params, err := got.FilteredWithDB().MysqlParams()
+	require.NoError(t, err)
+	assert.Equal(t, replication.FilePosFlavorID, params.Flavor)
+	assert.Equal(t, vttls.VerifyIdentity, params.SslMode)
+	assert.Equal(t, "/etc/ssl/rds-ca.pem", params.SslCa)
+	assert.Equal(t, "rds.example.com", params.ServerName)
+	assert.Equal(t, "rds.example.com", params.Host)
+	assert.Equal(t, "vt_filtered", params.Uname)
+
+	// The config of the tablet is left untouched.
+	params, err = dbcfgs.FilteredWithDB().MysqlParams()
+	require.NoError(t, err)
+	assert.Empty(t, params.Flavor)
+	assert.Empty(t, params.SslCa)
+
+	assert.Equal(t, "ext1", externalConnectorKey("ext1", nil))
+	assert.NotEqual(t,
+		externalConnectorKey("ext1", &binlogdatapb.ExternalMysqlOptions{ServerId: 1000}),
+		externalConnectorKey("ext1", &binlogdatapb.ExternalMysqlOptions{ServerId: 1001}))
+}
+
 func expectDBClientAndVreplicationQueries(t *testing.T, queries []string, pos string) {
 	t.Helper()
 	vrepQueries := getExpectedVreplicationQueries(t, pos)
@@ -142,7 +142,7 @@ func setup(ctx context.Context) (func(), int) {
 	vttablet.VReplicationExperimentalFlags = 0
 
 	// Engines cannot be initialized in testenv because it introduces circular dependencies.
-	streamerEngine = vstreamer.NewEngine(env.TabletEnv, env.SrvTopo, env.SchemaEngine, nil, env.Cells[0])
+	streamerEngine = vstreamer.NewEngine(env.TabletEnv, env.SrvTopo, env.SchemaEngine, nil, nil, env.Cells[0])
 	streamerEngine.InitDBConfig(env.KeyspaceName, env.ShardName)
 	streamerEngine.Open()
 
@@ -48,7 +48,7 @@ func NewReplicaConnector(venv *vtenv.Environment, connParams *mysql.ConnParams)
 	env := tabletenv.NewEnv(venv, config, "source")
 	c.se = schema.NewEngine(env)
 	c.se.SkipMetaCheck = true
-	c.vstreamer = vstreamer.NewEngine(env, nil, c.se, nil, "")
+	c.vstreamer = vstreamer.NewEngine(env, nil, c.se, nil, nil, "")
 	c.se.InitDBConfig(dbconfigs.New(connParams))
 
 	// Open
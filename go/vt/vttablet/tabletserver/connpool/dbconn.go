@@ -166,8 +166,8 @@ func (dbc *Conn) execOnce(ctx context.Context, query string, maxrows int, wantfi
 		return nil, fmt.Errorf("%v before execution started", err)
 	}
 
-	now := time.Now()
-	defer dbc.stats.MySQLTimings.Record("Exec", now)
+	timer := dbc.stats.MySQLTimings.Start(ctx, "Exec")
+	defer timer.Stop()
 
 	type execResult struct {
 		result *sqltypes.Result
@@ -176,7 +176,10 @@ func (dbc *Conn) execOnce(ctx context.Context, query string, maxrows int, wantfi
 
 	ch := make(chan execResult)
 	go func() {
-		result, err := dbc.conn.ExecuteFetch(query, maxrows, wantfields)
+		result, warnings, err := dbc.conn.ExecuteFetchWithWarnings(query, maxrows, wantfields)
+		if result != nil {
+			result.Warnings = warnings
+		}
 		ch <- execResult{result, err}
 	}()
 
@@ -185,7 +188,7 @@ func (dbc *Conn) execOnce(ctx context.Context, query string, maxrows int, wantfi
 		killCtx, cancel := context.WithTimeout(context.Background(), dbc.killTimeout)
 		defer cancel()
 
-		_ = dbc.KillWithContext(killCtx, ctx.Err().Error(), time.Since(now))
+		_ = dbc.KillWithContext(killCtx, ctx.Err().Error(), timer.Elapsed())
 		return nil, dbc.Err()
 	case r := <-ch:
 		if dbcErr := dbc.Err(); dbcErr != nil {
@@ -215,8 +218,10 @@ func (dbc *Conn) FetchNext(ctx context.Context, maxrows int, wantfields bool) (*
 
 }
 
-// Stream executes the query and streams the results.
-func (dbc *Conn) Stream(ctx context.Context, query string, callback func(*sqltypes.Result) error, alloc func() *sqltypes.Result, streamBufferSize int, includedFields querypb.ExecuteOptions_IncludedFields) error {
+// Stream executes the query and streams the results. If streamBufferRows is
+// set, the rows are sent in chunks of that many rows instead of in chunks of
+// streamBufferSize bytes.
+func (dbc *Conn) Stream(ctx context.Context, query string, callback func(*sqltypes.Result) error, alloc func() *sqltypes.Result, streamBufferSize, streamBufferRows int, includedFields querypb.ExecuteOptions_IncludedFields) error {
 	span, ctx := trace.NewSpan(ctx, "DBConn.Stream")
 	trace.AnnotateSQL(span, sqlparser.Preview(query))
 	defer span.Finish()
@@ -235,6 +240,7 @@ func (dbc *Conn) Stream(ctx context.Context, query string, callback func(*sqltyp
 			},
 			alloc,
 			streamBufferSize,
+			streamBufferRows,
 		)
 		switch {
 		case err == nil:
@@ -267,16 +273,16 @@ func (dbc *Conn) Stream(ctx context.Context, query string, callback func(*sqltyp
 	panic("unreachable")
 }
 
-func (dbc *Conn) streamOnce(ctx context.Context, query string, callback func(*sqltypes.Result) error, alloc func() *sqltypes.Result, streamBufferSize int) error {
+func (dbc *Conn) streamOnce(ctx context.Context, query string, callback func(*sqltypes.Result) error, alloc func() *sqltypes.Result, streamBufferSize, streamBufferRows int) error {
 	dbc.current.Store(&query)
 	defer dbc.current.Store(nil)
 
-	now := time.Now()
-	defer dbc.stats.MySQLTimings.Record("ExecStream", now)
+	timer := dbc.stats.MySQLTimings.Start(ctx, "ExecStream")
+	defer timer.Stop()
 
 	ch := make(chan error)
 	go func() {
-		ch <- dbc.conn.ExecuteStreamFetch(query, callback, alloc, streamBufferSize)
+		ch <- dbc.conn.ExecuteStreamFetch(query, callback, alloc, streamBufferSize, streamBufferRows)
 	}()
 
 	select {
@@ -284,7 +290,7 @@ func (dbc *Conn) streamOnce(ctx context.Context, query string, callback func(*sq
 		killCtx, cancel := context.WithTimeout(context.Background(), dbc.killTimeout)
 		defer cancel()
 
-		_ = dbc.KillWithContext(killCtx, ctx.Err().Error(), time.Since(now))
+		_ = dbc.KillWithContext(killCtx, ctx.Err().Error(), timer.Elapsed())
 		return dbc.Err()
 	case err := <-ch:
 		if dbcErr := dbc.Err(); dbcErr != nil {
@@ -295,7 +301,7 @@ func (dbc *Conn) streamOnce(ctx context.Context, query string, callback func(*sq
 }
 
 // StreamOnce executes the query and streams the results. But, does not retry on connection errors.
-func (dbc *Conn) StreamOnce(ctx context.Context, query string, callback func(*sqltypes.Result) error, alloc func() *sqltypes.Result, streamBufferSize int, includedFields querypb.ExecuteOptions_IncludedFields) error {
+func (dbc *Conn) StreamOnce(ctx context.Context, query string, callback func(*sqltypes.Result) error, alloc func() *sqltypes.Result, streamBufferSize, streamBufferRows int, includedFields querypb.ExecuteOptions_IncludedFields) error {
 	resultSent := false
 	return dbc.streamOnce(
 		ctx,
@@ -309,6 +315,7 @@ func (dbc *Conn) StreamOnce(ctx context.Context, query string, callback func(*sq
 		},
 		alloc,
 		streamBufferSize,
+		streamBufferRows,
 	)
 }
 
@@ -466,7 +466,7 @@ func TestDBConnStream(t *testing.T) {
 		}, func() *sqltypes.Result {
 			return &sqltypes.Result{}
 		},
-		10, querypb.ExecuteOptions_ALL)
+		10, 0, querypb.ExecuteOptions_ALL)
 	if err != nil {
 		t.Fatalf("should not get an error, err: %v", err)
 	}
@@ -482,7 +482,7 @@ func TestDBConnStream(t *testing.T) {
 		}, func() *sqltypes.Result {
 			return &sqltypes.Result{}
 		},
-		10, querypb.ExecuteOptions_ALL)
+		10, 0, querypb.ExecuteOptions_ALL)
 	db.DisableConnFail()
 	want := "no such file or directory (errno 2002)"
 	if err == nil || !strings.Contains(err.Error(), want) {
@@ -490,6 +490,51 @@ func TestDBConnStream(t *testing.T) {
 	}
 }
 
+func TestDBConnStreamRows(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	sql := "select * from test_table limit 1000"
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Type: sqltypes.VarChar},
+		},
+	}
+	for i := 0; i < 5; i++ {
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewVarChar("123")})
+	}
+	db.AddQuery(sql, result)
+	connPool := newPool()
+	params := dbconfigs.New(db.ConnParams())
+	connPool.Open(params, params, params)
+	defer connPool.Close()
+	dbConn, err := newPooledConn(context.Background(), connPool, params)
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	streamChunks := func(streamBufferSize, streamBufferRows int) []int {
+		var chunks []int
+		err := dbConn.Stream(context.Background(), sql,
+			func(r *sqltypes.Result) error {
+				if len(r.Rows) > 0 {
+					chunks = append(chunks, len(r.Rows))
+				}
+				return nil
+			},
+			func() *sqltypes.Result {
+				return &sqltypes.Result{}
+			},
+			streamBufferSize, streamBufferRows, querypb.ExecuteOptions_ALL)
+		require.NoError(t, err)
+		return chunks
+	}
+
+	// The chunks are cut every 6 bytes, i.e. every 2 rows.
+	assert.Equal(t, []int{2, 2, 1}, streamChunks(6, 0))
+	// The row count takes precedence over the byte size.
+	assert.Equal(t, []int{3, 2}, streamChunks(6, 3))
+	assert.Equal(t, []int{1, 1, 1, 1, 1}, streamChunks(1000, 1))
+}
+
 func TestDBConnStreamKill(t *testing.T) {
 	db := fakesqldb.New(t)
 	defer db.Close()
@@ -521,7 +566,7 @@ func TestDBConnStreamKill(t *testing.T) {
 		func() *sqltypes.Result {
 			return &sqltypes.Result{}
 		},
-		10, querypb.ExecuteOptions_ALL)
+		10, 0, querypb.ExecuteOptions_ALL)
 
 	assert.Contains(t, err.Error(), "(errno 2013) due to")
 }
@@ -92,6 +92,25 @@ type Controller interface {
 
 	// CheckThrottler
 	CheckThrottler(ctx context.Context, appName string, flags *throttle.CheckFlags) *throttle.CheckResult
+
+	// UnresolvedTransactions returns the distributed transactions managed by this
+	// tablet that were not resolved within abandonAge.
+	UnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error)
+
+	// ResolveTransaction asks the 2PC coordinator to resolve the distributed transaction.
+	ResolveTransaction(ctx context.Context, dtid string) error
+
+	// RequeueDeadLetters moves messages from the dead-letter table of a message
+	// table back to it. All the dead letters are requeued if ids is empty.
+	RequeueDeadLetters(ctx context.Context, table string, ids []string) (int64, error)
+
+	// KillQueries kills the running queries that match the filter, and
+	// returns them. The queries are left running if dryRun is set.
+	KillQueries(ctx context.Context, filter QueryFilter, dryRun bool) ([]KilledQuery, error)
+
+	// UpdateQueryFingerprintRules updates the query fingerprint rules of the
+	// tablet, reloads them, and returns them.
+	UpdateQueryFingerprintRules(ctx context.Context, setRules []FingerprintRule, deleteQueries []string) ([]FingerprintRule, error)
 }
 
 // Ensure TabletServer satisfies Controller interface.
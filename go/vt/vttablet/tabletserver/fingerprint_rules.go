@@ -0,0 +1,309 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"vitess.io/vitess/go/constants/sidecar"
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/dbconnpool"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// FingerprintAction is what a fingerprint rule does with the queries of its
+// fingerprint.
+type FingerprintAction int
+
+const (
+	// FingerprintPin executes the queries with the plan type of the rule.
+	FingerprintPin FingerprintAction = iota
+	// FingerprintBlock fails the queries with the error message of the rule.
+	FingerprintBlock
+)
+
+// String returns the name of the action, as stored in the sidecar database.
+func (a FingerprintAction) String() string {
+	if a == FingerprintBlock {
+		return "block"
+	}
+	return "pin"
+}
+
+// FingerprintRule pins the queries of a fingerprint to a plan type, or
+// blocks them, to mitigate pathological queries.
+type FingerprintRule struct {
+	// Fingerprint is the fingerprint of the queries that the rule applies to.
+	// In updates, it can be any query with that fingerprint.
+	Fingerprint string
+	Action      FingerprintAction
+	// PlanType is the plan type that pin rules execute the queries with.
+	PlanType planbuilder.PlanType
+	// ErrorMessage is the error that block rules fail the queries with.
+	ErrorMessage string
+}
+
+// pinnablePlans maps the plan types that the queries can be pinned to, other
+// than their own, to the plan type that the queries must have. An update or
+// a delete pinned to UpdateLimit or DeleteLimit runs in a transaction that
+// is rolled back if it changes more rows than the maximum result size.
+// Pinning a query to a plan type that it can't be executed with fails it.
+var pinnablePlans = map[planbuilder.PlanType]planbuilder.PlanType{
+	planbuilder.PlanUpdateLimit: planbuilder.PlanUpdate,
+	planbuilder.PlanDeleteLimit: planbuilder.PlanDelete,
+}
+
+// maxFingerprintLength is the length of the fingerprint column of the sidecar
+// table of the rules.
+const maxFingerprintLength = 3072
+
+const (
+	sqlReadFingerprintRules   = "select fingerprint, action, plan_type, error_message from %s.query_fingerprint_rules"
+	sqlUpsertFingerprintRule  = "insert into %s.query_fingerprint_rules(fingerprint, action, plan_type, error_message) values (%a, %a, %a, %a) on duplicate key update action = values(action), plan_type = values(plan_type), error_message = values(error_message)"
+	sqlDeleteFingerprintRule  = "delete from %s.query_fingerprint_rules where fingerprint = %a"
+	defaultFingerprintMessage = "the query is blocked"
+)
+
+// applyFingerprintRule applies the fingerprint rule of the query, if any, to
+// its plan. Streaming plans are only subject to block rules.
+func (qe *QueryEngine) applyFingerprintRule(plan *TabletPlan, sql string, streaming bool) {
+	rules := qe.fingerprintRules.Load()
+	if rules == nil || len(*rules) == 0 {
+		return
+	}
+	fingerprint, err := queryFingerprint(qe.env.Environment().Parser(), sql)
+	if err != nil {
+		return
+	}
+	rule, ok := (*rules)[fingerprint]
+	if !ok {
+		return
+	}
+
+	switch rule.Action {
+	case FingerprintBlock:
+		plan.FingerprintRule = rule
+		plan.FingerprintErr = vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "disallowed due to fingerprint rule: %s", rule.ErrorMessage)
+	case FingerprintPin:
+		if streaming || plan.PlanID == rule.PlanType {
+			return
+		}
+		plan.FingerprintRule = rule
+		if from, ok := pinnablePlans[rule.PlanType]; ok && from == plan.PlanID {
+			pinned := *plan.Plan
+			pinned.PlanID = rule.PlanType
+			plan.Plan = &pinned
+			return
+		}
+		plan.FingerprintErr = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the query is pinned to plan type %s, but cannot be executed with it instead of %s", rule.PlanType, plan.PlanID)
+	}
+}
+
+// FingerprintRules returns the fingerprint rules of the tablet, sorted by
+// fingerprint.
+func (qe *QueryEngine) FingerprintRules() []FingerprintRule {
+	rules := qe.fingerprintRules.Load()
+	if rules == nil {
+		return nil
+	}
+	result := make([]FingerprintRule, 0, len(*rules))
+	for _, rule := range *rules {
+		result = append(result, *rule)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Fingerprint < result[j].Fingerprint
+	})
+	return result
+}
+
+// LoadFingerprintRules loads the fingerprint rules from the sidecar database,
+// and clears the plan cache so that they apply to the cached plans too.
+func (qe *QueryEngine) LoadFingerprintRules(ctx context.Context) error {
+	conn, err := dbconnpool.NewDBConnection(ctx, qe.env.Config().DB.DbaWithDB())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	qr, err := conn.ExecuteFetch(fmt.Sprintf(sqlReadFingerprintRules, sidecar.GetIdentifier()), -1, false)
+	if err != nil {
+		return err
+	}
+	rules := make(map[string]*FingerprintRule, len(qr.Rows))
+	for _, row := range qr.Rows {
+		rule := &FingerprintRule{
+			Fingerprint:  row[0].ToString(),
+			ErrorMessage: row[3].ToString(),
+		}
+		switch row[1].ToString() {
+		case FingerprintBlock.String():
+			rule.Action = FingerprintBlock
+		case FingerprintPin.String():
+			planType, ok := planbuilder.PlanByNameIC(row[2].ToString())
+			if !ok {
+				log.Warningf("Ignoring the fingerprint rule of %q: unknown plan type %q", rule.Fingerprint, row[2].ToString())
+				continue
+			}
+			rule.Action = FingerprintPin
+			rule.PlanType = planType
+		default:
+			log.Warningf("Ignoring the fingerprint rule of %q: unknown action %q", rule.Fingerprint, row[1].ToString())
+			continue
+		}
+		rules[rule.Fingerprint] = rule
+	}
+	qe.fingerprintRules.Store(&rules)
+	qe.ClearQueryPlanCache()
+	return nil
+}
+
+// loadFingerprintRulesOnOpen loads the fingerprint rules when the query
+// engine opens. The sidecar database or its table of the rules may not exist
+// yet, in which case the tablet has no rules.
+func (qe *QueryEngine) loadFingerprintRulesOnOpen() {
+	err := qe.LoadFingerprintRules(context.Background())
+	if sqlErr, ok := err.(*sqlerror.SQLError); ok && (sqlErr.Number() == sqlerror.ERNoSuchTable || sqlErr.Number() == sqlerror.ERBadDb) {
+		return
+	}
+	if err != nil {
+		log.Warningf("Failed to load the query fingerprint rules: %v", err)
+	}
+}
+
+// UpdateQueryFingerprintRules adds setRules to the fingerprint rules of the
+// tablet and deletes the rules of the fingerprints of deleteQueries, in the
+// sidecar database, and then reloads the rules from there. The rules can only
+// be updated on the primary, whose sidecar database replicates them to the
+// other tablets of the shard, which only reload them.
+func (tsv *TabletServer) UpdateQueryFingerprintRules(ctx context.Context, setRules []FingerprintRule, deleteQueries []string) ([]FingerprintRule, error) {
+	if len(setRules) > 0 || len(deleteQueries) > 0 {
+		if tabletType := tsv.sm.Target().TabletType; tabletType != topodatapb.TabletType_PRIMARY {
+			return nil, vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "the query fingerprint rules can only be updated on the primary, not on a %v tablet", tabletType)
+		}
+		queries, err := tsv.fingerprintRuleUpdates(setRules, deleteQueries)
+		if err != nil {
+			return nil, err
+		}
+		if err := tsv.execFingerprintRuleUpdates(ctx, queries); err != nil {
+			return nil, err
+		}
+		log.Infof("UpdateQueryFingerprintRules: set %d rules, deleted %d rules", len(setRules), len(deleteQueries))
+	}
+
+	if err := tsv.qe.LoadFingerprintRules(ctx); err != nil {
+		return nil, vterrors.Wrap(err, "cannot load the query fingerprint rules")
+	}
+	return tsv.qe.FingerprintRules(), nil
+}
+
+// fingerprintRuleUpdates validates the updates of the fingerprint rules, and
+// returns the queries that write them to the sidecar database.
+func (tsv *TabletServer) fingerprintRuleUpdates(setRules []FingerprintRule, deleteQueries []string) ([]string, error) {
+	dbname := sidecar.GetIdentifier()
+	upsert := sqlparser.BuildParsedQuery(sqlUpsertFingerprintRule, dbname, ":fingerprint", ":action", ":plan_type", ":error_message")
+	del := sqlparser.BuildParsedQuery(sqlDeleteFingerprintRule, dbname, ":fingerprint")
+
+	fingerprint := func(query string) (string, error) {
+		fp, err := queryFingerprint(tsv.env.Parser(), query)
+		if err != nil {
+			return "", vterrors.Wrapf(err, "cannot compute the fingerprint of %q", query)
+		}
+		if len(fp) > maxFingerprintLength {
+			return "", vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the fingerprint of %q is longer than %d bytes", query, maxFingerprintLength)
+		}
+		return fp, nil
+	}
+
+	var queries []string
+	for _, rule := range setRules {
+		fp, err := fingerprint(rule.Fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		planType, errorMessage := "", ""
+		switch rule.Action {
+		case FingerprintPin:
+			if rule.PlanType < 0 || rule.PlanType >= planbuilder.NumPlans {
+				return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a plan type is required to pin %q", rule.Fingerprint)
+			}
+			planType = rule.PlanType.String()
+		case FingerprintBlock:
+			errorMessage = rule.ErrorMessage
+			if errorMessage == "" {
+				errorMessage = defaultFingerprintMessage
+			}
+		default:
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown action %d for %q", rule.Action, rule.Fingerprint)
+		}
+		query, err := upsert.GenerateQuery(map[string]*querypb.BindVariable{
+			"fingerprint":   sqltypes.StringBindVariable(fp),
+			"action":        sqltypes.StringBindVariable(rule.Action.String()),
+			"plan_type":     sqltypes.StringBindVariable(planType),
+			"error_message": sqltypes.StringBindVariable(errorMessage),
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, query)
+	}
+	for _, deleteQuery := range deleteQueries {
+		fp, err := fingerprint(deleteQuery)
+		if err != nil {
+			return nil, err
+		}
+		query, err := del.GenerateQuery(map[string]*querypb.BindVariable{
+			"fingerprint": sqltypes.StringBindVariable(fp),
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
+
+// execFingerprintRuleUpdates writes the updates of the fingerprint rules to
+// the sidecar database in a single transaction.
+func (tsv *TabletServer) execFingerprintRuleUpdates(ctx context.Context, queries []string) error {
+	conn, err := dbconnpool.NewDBConnection(ctx, tsv.config.DB.DbaWithDB())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecuteFetch("begin", 0, false); err != nil {
+		return err
+	}
+	for _, query := range queries {
+		if _, err := conn.ExecuteFetch(query, 0, false); err != nil {
+			_, _ = conn.ExecuteFetch("rollback", 0, false)
+			return err
+		}
+	}
+	_, err = conn.ExecuteFetch("commit", 0, false)
+	return err
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql/fakesqldb"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema/schematest"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+func TestFingerprintRules(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	schematest.AddDefaultQueries(db)
+	addSchemaEngineQueries(db)
+
+	fingerprint := func(query string) string {
+		fp, err := queryFingerprint(sqlparser.NewTestParser(), query)
+		require.NoError(t, err)
+		return fp
+	}
+	db.AddQuery("select fingerprint, action, plan_type, error_message from _vt.query_fingerprint_rules", sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("fingerprint|action|plan_type|error_message", "varbinary|varbinary|varbinary|varbinary"),
+		fmt.Sprintf("%s|block||too expensive", fingerprint("select * from test_table_01 where name = 1")),
+		fmt.Sprintf("%s|pin|UpdateLimit|", fingerprint("update test_table_01 set name = 2 where pk = 1 limit 5")),
+		fmt.Sprintf("%s|pin|DeleteLimit|", fingerprint("select * from test_table_02 where pk = 1")),
+		fmt.Sprintf("%s|pin|Bogus|", fingerprint("select * from test_table_03")),
+	))
+
+	qe := newTestQueryEngine(10*time.Second, true, newDBConfigs(db))
+	qe.se.Open()
+	require.NoError(t, qe.Open())
+	defer qe.Close()
+
+	rules := qe.FingerprintRules()
+	require.Len(t, rules, 3, "the rule with an unknown plan type is ignored")
+
+	ctx := context.Background()
+	getPlan := func(query string) *TabletPlan {
+		plan, err := qe.GetPlan(ctx, tabletenv.NewLogStats(ctx, "GetPlanStats"), query, false)
+		require.NoError(t, err)
+		return plan
+	}
+
+	// Blocked queries fail with the error message of the rule, whatever their
+	// literals.
+	plan := getPlan("select * from test_table_01 where name = 42")
+	require.Error(t, plan.FingerprintErr)
+	assert.Equal(t, vtrpcpb.Code_INVALID_ARGUMENT, vterrors.Code(plan.FingerprintErr))
+	assert.Contains(t, plan.FingerprintErr.Error(), "too expensive")
+
+	// An update with a limit is pinned to UpdateLimit.
+	plan = getPlan("update test_table_01 set name = 3 where pk = 7 limit 5")
+	require.NoError(t, plan.FingerprintErr)
+	assert.Equal(t, planbuilder.PlanUpdateLimit, plan.PlanID)
+
+	// A select cannot be pinned to DeleteLimit.
+	plan = getPlan("select * from test_table_02 where pk = 2")
+	require.Error(t, plan.FingerprintErr)
+	assert.Equal(t, vtrpcpb.Code_FAILED_PRECONDITION, vterrors.Code(plan.FingerprintErr))
+	assert.Equal(t, planbuilder.PlanSelect, plan.PlanID)
+
+	// Other queries are unaffected.
+	plan = getPlan("select * from test_table_03")
+	assert.Nil(t, plan.FingerprintRule)
+	assert.NoError(t, plan.FingerprintErr)
+
+	// Streaming queries are only subject to block rules.
+	plan, err := qe.GetStreamPlan(ctx, tabletenv.NewLogStats(ctx, "GetPlanStats"), "select * from test_table_02 where pk = 3", false)
+	require.NoError(t, err)
+	assert.NoError(t, plan.FingerprintErr)
+	plan, err = qe.GetStreamPlan(ctx, tabletenv.NewLogStats(ctx, "GetPlanStats"), "select * from test_table_01 where name = 3", false)
+	require.NoError(t, err)
+	assert.Error(t, plan.FingerprintErr)
+
+	// Reloading the rules applies them to the cached plans.
+	db.AddQuery("select fingerprint, action, plan_type, error_message from _vt.query_fingerprint_rules", &sqltypes.Result{})
+	require.NoError(t, qe.LoadFingerprintRules(ctx))
+	assert.Empty(t, qe.FingerprintRules())
+	plan = getPlan("select * from test_table_01 where name = 42")
+	assert.NoError(t, plan.FingerprintErr)
+}
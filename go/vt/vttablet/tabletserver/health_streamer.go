@@ -33,10 +33,12 @@ import (
 	"vitess.io/vitess/go/vt/servenv"
 
 	"vitess.io/vitess/go/history"
+	"vitess.io/vitess/go/mysql/replication"
 	"vitess.io/vitess/go/vt/log"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
 	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/sqlparser"
 	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tabletmanager/vreplication"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
@@ -85,6 +87,7 @@ type healthStreamer struct {
 	reloadTimeout          time.Duration
 
 	viewsEnabled bool
+	parser       *sqlparser.Parser
 }
 
 func newHealthStreamer(env tabletenv.Env, alias *topodatapb.TabletAlias, engine *schema.Engine) *healthStreamer {
@@ -114,6 +117,7 @@ func newHealthStreamer(env tabletenv.Env, alias *topodatapb.TabletAlias, engine
 		signalWhenSchemaChange: env.Config().SignalWhenSchemaChange,
 		reloadTimeout:          env.Config().SchemaChangeReloadTimeout,
 		viewsEnabled:           env.Config().EnableViews,
+		parser:                 env.Environment().Parser(),
 		se:                     engine,
 	}
 	hs.unhealthyThreshold.Store(env.Config().Healthcheck.UnhealthyThreshold.Nanoseconds())
@@ -370,12 +374,98 @@ func (hs *healthStreamer) reload(full map[string]*schema.Table, created, altered
 		return nil
 	}
 
+	changes, err := hs.schemaChanges(ctx, conn.Conn, tables, views)
+	if err != nil {
+		// vtgate fetches the definitions of the tables and views that changed when they are not pushed.
+		log.Warningf("cannot read the definitions of the changed tables %v and views %v: %v", tables, views, err)
+		changes = nil
+	}
+
 	hs.state.RealtimeStats.TableSchemaChanged = tables
 	hs.state.RealtimeStats.ViewSchemaChanged = views
+	hs.state.RealtimeStats.SchemaChanges = changes
 	shr := hs.state.CloneVT()
 	hs.broadCastToClients(shr)
 	hs.state.RealtimeStats.TableSchemaChanged = nil
 	hs.state.RealtimeStats.ViewSchemaChanged = nil
+	hs.state.RealtimeStats.SchemaChanges = nil
 
 	return nil
 }
+
+// maxSchemaChangesSize bounds the size of the definitions pushed in a health stream response.
+// Beyond it, vtgate fetches them.
+const maxSchemaChangesSize = 1 << 20
+
+// schemaChanges reads the definitions of the changed tables and views from the copy that the
+// schema engine keeps in the sidecar database, and the position the tablet has applied. The
+// tables and views missing from the copy were dropped.
+func (hs *healthStreamer) schemaChanges(ctx context.Context, conn *connpool.Conn, tables, views []string) ([]*querypb.SchemaChange, error) {
+	position, err := currentPosition(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*querypb.SchemaChange
+	size := 0
+	read := func(tableType querypb.SchemaTableType, names []string, query string) error {
+		qr, err := conn.Exec(ctx, query, 10000, false)
+		if err != nil {
+			return err
+		}
+		definitions := make(map[string]string, len(qr.Rows))
+		for _, row := range qr.Rows {
+			definitions[row[0].ToString()] = row[1].ToString()
+			size += row[1].Len()
+		}
+		if size > maxSchemaChangesSize {
+			return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "definitions larger than %d bytes", maxSchemaChangesSize)
+		}
+		for _, name := range names {
+			changes = append(changes, &querypb.SchemaChange{
+				Name:            name,
+				TableType:       tableType,
+				CreateStatement: definitions[name],
+				Position:        position,
+			})
+		}
+		return nil
+	}
+
+	if len(tables) > 0 {
+		query, err := schema.GetFetchTableQuery(tables, hs.parser)
+		if err != nil {
+			return nil, err
+		}
+		if err := read(querypb.SchemaTableType_TABLES, tables, query); err != nil {
+			return nil, err
+		}
+	}
+	if len(views) > 0 {
+		query, err := schema.GetFetchViewQuery(views, hs.parser)
+		if err != nil {
+			return nil, err
+		}
+		if err := read(querypb.SchemaTableType_VIEWS, views, query); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// currentPosition returns the encoded GTID position the tablet has applied, or an empty
+// string if it does not use MySQL GTIDs.
+func currentPosition(ctx context.Context, conn *connpool.Conn) (string, error) {
+	qr, err := conn.Exec(ctx, "select @@global.gtid_executed", 1, false)
+	if err != nil {
+		return "", err
+	}
+	if len(qr.Rows) != 1 {
+		return "", nil
+	}
+	gtidSet, err := replication.ParseMysql56GTIDSet(qr.Rows[0][0].ToString())
+	if err != nil || len(gtidSet) == 0 {
+		return "", nil
+	}
+	return replication.EncodePosition(replication.Position{GTIDSet: gtidSet}), nil
+}
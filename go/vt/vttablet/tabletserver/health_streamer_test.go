@@ -32,6 +32,7 @@ import (
 	"vitess.io/vitess/go/mysql"
 	"vitess.io/vitess/go/mysql/fakesqldb"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/dbconfigs"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
@@ -270,6 +271,15 @@ func TestReloadSchema(t *testing.T) {
 				"product|id",
 				"users|id",
 			))
+			// The definitions of the changed tables pushed to vtgate, users having been dropped meanwhile.
+			db.AddQuery("select @@global.gtid_executed", sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("@@global.gtid_executed", "varchar"),
+				"00010203-0405-0607-0809-0a0b0c0d0e0f:1-5",
+			))
+			db.AddQueryPattern("select table_name, create_statement from _vt.`tables` where table_schema = database\\(\\) and table_name in .*", sqltypes.MakeTestResult(
+				sqltypes.MakeTestFields("table_name|create_statement", "varchar|varchar"),
+				"product|create table product (id int primary key)",
+			))
 
 			hs.InitDBConfig(target, configs.DbaWithDB())
 			se.InitDBConfig(configs.DbaWithDB())
@@ -299,6 +309,11 @@ func TestReloadSchema(t *testing.T) {
 				hs.Stream(ctx, func(response *querypb.StreamHealthResponse) error {
 					if response.RealtimeStats.TableSchemaChanged != nil {
 						assert.Equal(t, []string{"product", "users"}, response.RealtimeStats.TableSchemaChanged)
+						position := "MySQL56/00010203-0405-0607-0809-0a0b0c0d0e0f:1-5"
+						utils.MustMatch(t, []*querypb.SchemaChange{
+							{Name: "product", TableType: querypb.SchemaTableType_TABLES, CreateStatement: "create table product (id int primary key)", Position: position},
+							{Name: "users", TableType: querypb.SchemaTableType_TABLES, Position: position},
+						}, response.RealtimeStats.SchemaChanges)
 						wg.Done()
 					}
 					return nil
@@ -351,6 +366,11 @@ func TestReloadView(t *testing.T) {
 	db.AddQuery("begin", &sqltypes.Result{})
 	db.AddQuery("commit", &sqltypes.Result{})
 	db.AddQuery("rollback", &sqltypes.Result{})
+	// The definitions of the changed views pushed to vtgate.
+	db.AddQuery("select @@global.gtid_executed", sqltypes.MakeTestResult(sqltypes.MakeTestFields("@@global.gtid_executed", "varchar")))
+	db.AddQueryPattern("select table_name, create_statement from _vt.views where table_schema = database\\(\\) and table_name in .*", sqltypes.MakeTestResult(
+		sqltypes.MakeTestFields("table_name|create_statement", "varchar|varchar"),
+	))
 	// Add the query pattern for the query that schema.Engine uses to get the tables.
 	db.AddQueryPattern("SELECT .* information_schema.innodb_tablespaces .*",
 		sqltypes.MakeTestResult(
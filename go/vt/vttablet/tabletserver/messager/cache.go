@@ -19,6 +19,7 @@ package messager
 import (
 	"container/heap"
 	"sync"
+	"time"
 
 	"vitess.io/vitess/go/vt/log"
 
@@ -48,10 +49,18 @@ func (mh messageHeap) Len() int {
 }
 
 func (mh messageHeap) Less(i, j int) bool {
-	// Lower epoch is more important.
+	// Lower priority is more important.
+	// If priorities match, lower epoch is more important: messages
+	// that are being retried don't hold back the ones that were
+	// never sent.
 	// If epochs match, newer messages are more important.
-	return mh[i].Priority < mh[j].Priority ||
-		(mh[i].Priority == mh[j].Priority && mh[i].TimeNext > mh[j].TimeNext)
+	if mh[i].Priority != mh[j].Priority {
+		return mh[i].Priority < mh[j].Priority
+	}
+	if mh[i].Epoch != mh[j].Epoch {
+		return mh[i].Epoch < mh[j].Epoch
+	}
+	return mh[i].TimeNext > mh[j].TimeNext
 }
 
 func (mh messageHeap) Swap(i, j int) {
@@ -70,21 +79,31 @@ func (mh *messageHeap) Pop() any {
 	return x
 }
 
+// scheduleHeap orders messages by the time they are due.
+type scheduleHeap struct {
+	messageHeap
+}
+
+func (sh scheduleHeap) Less(i, j int) bool {
+	return sh.messageHeap[i].TimeNext < sh.messageHeap[j].TimeNext
+}
+
 //_______________________________________________
 
-// cache is the cache for the messager. Messages initially
-// start in the sendQueue. When they are popped, they move
-// to the inFlight set. They are eventually discarded
-// after being successfully sent. Messages can be discarded
-// early (while still in the send queue) by any kind of
-// update to a message (like an ack). If so, such messages
-// are marked as defunct in the cache, and are eventually
-// discarded when popped.
+// cache is the cache for the messager. Messages that are due
+// start in the sendQueue, and messages whose time_next is still
+// in the future wait in the schedule until they are due. When they
+// are popped, they move to the inFlight set. They are eventually
+// discarded after being successfully sent. Messages can be discarded
+// early (while still queued) by any kind of update to a message
+// (like an ack). If so, such messages are marked as defunct in the
+// cache, and are eventually discarded when popped.
 type cache struct {
 	mu   sync.Mutex
 	size int
 
 	sendQueue messageHeap
+	schedule  scheduleHeap
 	// inQueue is used to efficiently find items in sendQueue.
 	// The message id is the key.
 	inQueue map[string]*MessageRow
@@ -108,7 +127,29 @@ func newCache(size int) *cache {
 func (mc *cache) IsEmpty() bool {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
-	return len(mc.sendQueue) == 0
+	return len(mc.sendQueue) == 0 && len(mc.schedule.messageHeap) == 0
+}
+
+// HasDue returns true if the cache has messages that can
+// be sent right away.
+func (mc *cache) HasDue() bool {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if len(mc.sendQueue) != 0 {
+		return true
+	}
+	return len(mc.schedule.messageHeap) != 0 && mc.schedule.messageHeap[0].TimeNext <= time.Now().UnixNano()
+}
+
+// NextDue returns the time_next of the scheduled message that
+// is due first. It returns false if no message is scheduled.
+func (mc *cache) NextDue() (int64, bool) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if len(mc.schedule.messageHeap) == 0 {
+		return 0, false
+	}
+	return mc.schedule.messageHeap[0].TimeNext, true
 }
 
 // Clear clears the cache.
@@ -118,40 +159,65 @@ func (mc *cache) Clear() {
 	log.Infof("messager cache - acquired lock")
 	defer mc.mu.Unlock()
 	mc.sendQueue = nil
+	mc.schedule.messageHeap = nil
 	mc.inQueue = make(map[string]*MessageRow)
 	mc.inFlight = make(map[string]bool)
 	log.Infof("messager cache - cache cleared")
 }
 
 // Add adds a MessageRow to the cache. It returns
-// false if the cache is full.
+// false if the cache is full. A message whose time_next
+// is in the future is not sent before it's due.
+// If the message is already queued with a different priority
+// or time_next, it's requeued with the new values.
 func (mc *cache) Add(mr *MessageRow) bool {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
-	if len(mc.sendQueue) >= mc.size {
+	if len(mc.sendQueue)+len(mc.schedule.messageHeap) >= mc.size {
 		return false
 	}
 	id := mr.Row[0].ToString()
 	if mc.inFlight[id] {
 		return true
 	}
-	if _, ok := mc.inQueue[id]; ok {
-		return true
+	if old, ok := mc.inQueue[id]; ok {
+		if old.Priority == mr.Priority && old.TimeNext == mr.TimeNext {
+			return true
+		}
+		old.defunct = true
+	}
+	if mr.TimeNext > time.Now().UnixNano() {
+		heap.Push(&mc.schedule, mr)
+	} else {
+		heap.Push(&mc.sendQueue, mr)
 	}
-	heap.Push(&mc.sendQueue, mr)
 	mc.inQueue[id] = mr
 	return true
 }
 
+// Remove drops the specified ids from the queues. Unlike
+// Discard, it leaves the messages that are in flight alone.
+func (mc *cache) Remove(ids []string) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	for _, id := range ids {
+		if mr := mc.inQueue[id]; mr != nil {
+			mr.defunct = true
+			delete(mc.inQueue, id)
+		}
+	}
+}
+
 // Pop removes the next MessageRow. Once the
 // message has been sent, Discard must be called.
 // The discard has to happen as a separate operation
 // to prevent the poller thread from repopulating the
 // message while it's being sent.
-// If the Cache is empty Pop returns nil.
+// If no message is due Pop returns nil.
 func (mc *cache) Pop() *MessageRow {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
+	mc.promoteDue()
 	for {
 		if len(mc.sendQueue) == 0 {
 			return nil
@@ -171,6 +237,19 @@ func (mc *cache) Pop() *MessageRow {
 	}
 }
 
+// promoteDue moves the scheduled messages that are due
+// to the sendQueue.
+func (mc *cache) promoteDue() {
+	now := time.Now().UnixNano()
+	for len(mc.schedule.messageHeap) != 0 && mc.schedule.messageHeap[0].TimeNext <= now {
+		mr := heap.Pop(&mc.schedule).(*MessageRow)
+		if mr.defunct {
+			continue
+		}
+		heap.Push(&mc.sendQueue, mr)
+	}
+}
+
 // Discard forgets the specified id.
 func (mc *cache) Discard(ids []string) {
 	mc.mu.Lock()
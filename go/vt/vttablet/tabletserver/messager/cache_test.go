@@ -19,6 +19,7 @@ package messager
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"vitess.io/vitess/go/sqltypes"
 )
@@ -214,3 +215,155 @@ func TestMessagerCacheEmpty(t *testing.T) {
 		t.Errorf("Pop(non-empty): nil, want %v", row)
 	}
 }
+
+func TestMessagerCacheEpochOrder(t *testing.T) {
+	mc := newCache(10)
+	for _, mr := range []*MessageRow{{
+		Priority: 1,
+		TimeNext: 3,
+		Epoch:    2,
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary("row23")},
+	}, {
+		Priority: 1,
+		TimeNext: 1,
+		Epoch:    0,
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary("row01")},
+	}, {
+		Priority: 1,
+		TimeNext: 2,
+		Epoch:    1,
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary("row12")},
+	}} {
+		if !mc.Add(mr) {
+			t.Fatal("Add returned false")
+		}
+	}
+	var rows []string
+	for i := 0; i < 3; i++ {
+		rows = append(rows, mc.Pop().Row[0].ToString())
+	}
+	want := []string{"row01", "row12", "row23"}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Pop order: %+v, want %+v", rows, want)
+	}
+}
+
+func TestMessagerCacheSchedule(t *testing.T) {
+	mc := newCache(10)
+	due := time.Now().Add(50 * time.Millisecond).UnixNano()
+	if !mc.Add(&MessageRow{
+		Priority: 1,
+		TimeNext: due,
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary("row01")},
+	}) {
+		t.Fatal("Add returned false")
+	}
+	if mc.IsEmpty() {
+		t.Error("IsEmpty: true, want false")
+	}
+	if mc.HasDue() {
+		t.Error("HasDue: true, want false")
+	}
+	if next, ok := mc.NextDue(); !ok || next != due {
+		t.Errorf("NextDue: %v, %v, want %v, true", next, ok, due)
+	}
+	if row := mc.Pop(); row != nil {
+		t.Errorf("Pop(scheduled): want nil, got %v", row.Row[0])
+	}
+
+	// A message that is due is sent first, even with a lower priority.
+	if !mc.Add(&MessageRow{
+		Priority: 2,
+		TimeNext: 1,
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary("row02")},
+	}) {
+		t.Fatal("Add returned false")
+	}
+	if row := mc.Pop(); row == nil || row.Row[0].ToString() != "row02" {
+		t.Errorf("Pop: want row02, got %v", row)
+	}
+
+	time.Sleep(time.Until(time.Unix(0, due)))
+	if !mc.HasDue() {
+		t.Error("HasDue: false, want true")
+	}
+	if row := mc.Pop(); row == nil || row.Row[0].ToString() != "row01" {
+		t.Errorf("Pop: want row01, got %v", row)
+	}
+	if _, ok := mc.NextDue(); ok {
+		t.Error("NextDue: true, want false")
+	}
+}
+
+func TestMessagerCacheReschedule(t *testing.T) {
+	mc := newCache(10)
+	if !mc.Add(&MessageRow{
+		TimeNext: time.Now().Add(time.Hour).UnixNano(),
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary("row01")},
+	}) {
+		t.Fatal("Add returned false")
+	}
+	if row := mc.Pop(); row != nil {
+		t.Errorf("Pop(scheduled): want nil, got %v", row.Row[0])
+	}
+
+	// Bringing time_next forward makes the message due.
+	if !mc.Add(&MessageRow{
+		TimeNext: 1,
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary("row01")},
+	}) {
+		t.Fatal("Add returned false")
+	}
+	if row := mc.Pop(); row == nil || row.Row[0].ToString() != "row01" {
+		t.Errorf("Pop: want row01, got %v", row)
+	}
+	mc.Discard([]string{"row01"})
+
+	// The old schedule is defunct.
+	if !mc.Add(&MessageRow{
+		TimeNext: 1,
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary("row02")},
+	}) {
+		t.Fatal("Add returned false")
+	}
+	if row := mc.Pop(); row == nil || row.Row[0].ToString() != "row02" {
+		t.Errorf("Pop: want row02, got %v", row)
+	}
+	if row := mc.Pop(); row != nil {
+		t.Errorf("Pop: want nil, got %v", row.Row[0])
+	}
+}
+
+func TestMessagerCacheRemove(t *testing.T) {
+	mc := newCache(10)
+	for _, id := range []string{"row01", "row02", "row03"} {
+		if !mc.Add(&MessageRow{
+			TimeNext: 1,
+			Row:      []sqltypes.Value{sqltypes.NewVarBinary(id)},
+		}) {
+			t.Fatal("Add returned false")
+		}
+	}
+	if !mc.Add(&MessageRow{
+		TimeNext: time.Now().Add(time.Hour).UnixNano(),
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary("row04")},
+	}) {
+		t.Fatal("Add returned false")
+	}
+	inFlight := mc.Pop().Row[0].ToString()
+	mc.Remove([]string{"row01", "row02", "row03", "row04"})
+	if row := mc.Pop(); row != nil {
+		t.Errorf("Pop: want nil, got %v", row.Row[0])
+	}
+
+	// Messages in flight are not affected.
+	if !mc.Add(&MessageRow{
+		TimeNext: 1,
+		Row:      []sqltypes.Value{sqltypes.NewVarBinary(inFlight)},
+	}) {
+		t.Fatal("Add returned false")
+	}
+	if row := mc.Pop(); row != nil {
+		t.Errorf("Pop(in flight): want nil, got %v", row.Row[0])
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand/v2"
+	"slices"
 	"sync"
 	"time"
 
@@ -33,12 +34,14 @@ import (
 	"vitess.io/vitess/go/timer"
 	"vitess.io/vitess/go/vt/log"
 	"vitess.io/vitess/go/vt/sqlparser"
+	"vitess.io/vitess/go/vt/vterrors"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/throttlerapp"
 
 	binlogdatapb "vitess.io/vitess/go/vt/proto/binlogdata"
 	querypb "vitess.io/vitess/go/vt/proto/query"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
 )
 
 var (
@@ -53,6 +56,8 @@ type QueryGenerator interface {
 	GenerateAckQuery(ids []string) (string, map[string]*querypb.BindVariable)
 	GeneratePostponeQuery(ids []string) (string, map[string]*querypb.BindVariable)
 	GeneratePurgeQuery(timeCutoff int64) (string, map[string]*querypb.BindVariable)
+	GenerateDeadLetterQueries(ids []string) ([]string, map[string]*querypb.BindVariable, error)
+	GenerateRequeueQueries(ids []string) ([]string, map[string]*querypb.BindVariable, error)
 }
 
 type messageReceiver struct {
@@ -133,6 +138,14 @@ type receiverWithStatus struct {
 // to the cache. Most of these items are likely to be those that did not
 // receive a timely ack.
 //
+// Scheduled delivery
+// The time_next of a message is the earliest time it can be sent. Both the
+// poller and the vstream load the messages that become due before the next
+// poll, and the cache holds on to them until their time_next. This lets
+// applications schedule a message, or postpone it for a custom backoff, by
+// setting its time_next. Among the messages that are due, the ones with the
+// lowest priority value are sent first.
+//
 // messagesPending mode
 // This mode is a variation of the steady state mode. This mode is
 // entered when there are outstanding items in the database that need to be sent
@@ -166,6 +179,13 @@ type receiverWithStatus struct {
 // The Purge thread
 // This thread is mostly independent. It wakes up periodically
 // to delete old rows that were successfully acked.
+//
+// Dead letters
+// If the table has a max number of attempts, the send loop doesn't send
+// the messages that reached it. Such poison messages are moved to the
+// dead-letter table instead, so that clients don't keep receiving them.
+// They can be moved back to the message table once the cause of the
+// failure is fixed.
 type messageManager struct {
 	tsv TabletService
 	vs  VStreamer
@@ -176,6 +196,7 @@ type messageManager struct {
 	purgeAfter   time.Duration
 	minBackoff   time.Duration
 	maxBackoff   time.Duration
+	maxAttempts  int
 	batchSize    int
 	pollerTicks  *timer.Timer
 	purgeTicks   *timer.Timer
@@ -236,6 +257,13 @@ type messageManager struct {
 	ackQuery                  *sqlparser.ParsedQuery
 	postponeQuery             *sqlparser.ParsedQuery
 	purgeQuery                *sqlparser.ParsedQuery
+
+	// The dead-letter queries are nil if the table has no
+	// dead-letter table.
+	deadLetterQueries     []*sqlparser.ParsedQuery
+	requeueQueries        []*sqlparser.ParsedQuery
+	requeueAllQueries     []*sqlparser.ParsedQuery
+	deadLetterFailureText string
 }
 
 // newMessageManager creates a new message manager.
@@ -253,6 +281,7 @@ func newMessageManager(tsv TabletService, vs VStreamer, table *schema.Table, pos
 		purgeAfter:      table.MessageInfo.PurgeAfterDuration,
 		minBackoff:      table.MessageInfo.MinBackoff,
 		maxBackoff:      table.MessageInfo.MaxBackoff,
+		maxAttempts:     table.MessageInfo.MaxAttempts,
 		batchSize:       table.MessageInfo.BatchSize,
 		cache:           newCache(table.MessageInfo.CacheSize),
 		pollerTicks:     timer.NewTimer(table.MessageInfo.PollInterval),
@@ -283,9 +312,44 @@ func newMessageManager(tsv TabletService, vs VStreamer, table *schema.Table, pos
 
 	mm.postponeQuery = buildPostponeQuery(mm.name, mm.minBackoff, mm.maxBackoff)
 
+	if table.MessageInfo.DeadLetterTable != "" {
+		mm.buildDeadLetterQueries(table)
+	}
+
 	return mm
 }
 
+// buildDeadLetterQueries builds the queries that move messages between
+// the message table and its dead-letter table.
+func (mm *messageManager) buildDeadLetterQueries(table *schema.Table) {
+	dlName := sqlparser.NewIdentifierCS(table.MessageInfo.DeadLetterTable)
+	allCols := buildColumnList(table.Fields)
+	// When requeued, a message gets a fresh schedule and attempt count.
+	userCols := buildColumnList(table.Fields, "time_next", "epoch", "time_acked")
+
+	mm.deadLetterQueries = []*sqlparser.ParsedQuery{
+		sqlparser.BuildParsedQuery(
+			"insert into %v(%s, time_dead_lettered, failure_reason) select %s, %a, %a from %v where id in %a and time_acked is null and epoch >= %a",
+			dlName, allCols, allCols, ":time_now", ":failure_reason", mm.name, "::ids", ":max_attempts"),
+		sqlparser.BuildParsedQuery(
+			"delete from %v where id in %a and time_acked is null and epoch >= %a",
+			mm.name, "::ids", ":max_attempts"),
+	}
+	mm.requeueQueries = []*sqlparser.ParsedQuery{
+		sqlparser.BuildParsedQuery(
+			"insert into %v(%s, time_next, epoch) select %s, %a, 0 from %v where id in %a",
+			mm.name, userCols, userCols, ":time_now", dlName, "::ids"),
+		sqlparser.BuildParsedQuery("delete from %v where id in %a", dlName, "::ids"),
+	}
+	mm.requeueAllQueries = []*sqlparser.ParsedQuery{
+		sqlparser.BuildParsedQuery(
+			"insert into %v(%s, time_next, epoch) select %s, %a, 0 from %v",
+			mm.name, userCols, userCols, ":time_now", dlName),
+		sqlparser.BuildParsedQuery("delete from %v", dlName),
+	}
+	mm.deadLetterFailureText = fmt.Sprintf("exceeded %d delivery attempts", mm.maxAttempts)
+}
+
 func buildPostponeQuery(name sqlparser.IdentifierCS, minBackoff, maxBackoff time.Duration) *sqlparser.ParsedQuery {
 	var args []any
 
@@ -335,10 +399,19 @@ func buildPostponeQuery(name sqlparser.IdentifierCS, minBackoff, maxBackoff time
 // buildSelectColumnList is a convenience function that
 // builds a 'select' list for the user-defined columns.
 func buildSelectColumnList(t *schema.Table) string {
+	return buildColumnList(t.MessageInfo.Fields)
+}
+
+// buildColumnList builds a column list for the fields,
+// leaving out the excluded columns.
+func buildColumnList(fields []*querypb.Field, exclude ...string) string {
 	buf := sqlparser.NewTrackedBuffer(nil)
-	for i, c := range t.MessageInfo.Fields {
+	for _, c := range fields {
+		if slices.Contains(exclude, c.Name) {
+			continue
+		}
 		// Column names may have to be escaped.
-		if i == 0 {
+		if buf.Len() == 0 {
 			buf.Myprintf("%v", sqlparser.NewIdentifierCI(c.Name))
 		} else {
 			buf.Myprintf(", %v", sqlparser.NewIdentifierCI(c.Name))
@@ -493,9 +566,9 @@ func (mm *messageManager) Add(mr *MessageRow) bool {
 	if len(mm.receivers) == 0 {
 		return false
 	}
-	// If cache is empty, we have to broadcast that we're not empty
-	// any more.
-	if mm.cache.IsEmpty() {
+	// If no message is due, the send loop may be waiting. We have
+	// to broadcast so that it looks at the new message.
+	if !mm.cache.HasDue() {
 		defer mm.cond.Broadcast()
 	}
 	if !mm.cache.Add(mr) {
@@ -549,22 +622,43 @@ func (mm *messageManager) runSend() {
 
 			// Fetch rows from cache.
 			lateCount := int64(0)
+			var poisonIDs []string
 			for i := 0; i < mm.batchSize; i++ {
 				mr := mm.cache.Pop()
 				if mr == nil {
 					break
 				}
+				if mm.maxAttempts > 0 && mr.Epoch >= int64(mm.maxAttempts) {
+					poisonIDs = append(poisonIDs, mr.Row[0].ToString())
+					continue
+				}
 				if mr.Epoch >= 1 {
 					lateCount++
 				}
 				rows = append(rows, mr.Row)
 			}
 			MessageStats.Add([]string{mm.name.String(), "Delayed"}, lateCount)
+			if poisonIDs != nil {
+				mm.wg.Add(1)
+				go mm.deadLetter(poisonIDs) // calls the offsetting mm.wg.Done()
+			}
 
 			// If we have rows to send, break out of this loop.
 			if rows != nil {
 				break
 			}
+
+			// Only scheduled messages are left. Wait until the first
+			// one is due, or until something else changes.
+			if next, ok := mm.cache.NextDue(); ok {
+				t := time.AfterFunc(time.Until(time.Unix(0, next)), func() {
+					mm.mu.Lock()
+					defer mm.mu.Unlock()
+					mm.cond.Broadcast()
+				})
+				mm.cond.Wait()
+				t.Stop()
+			}
 		}
 		MessageStats.Add([]string{mm.name.String(), "Sent"}, int64(len(rows)))
 		// If we're here, there is a current receiver, and messages
@@ -642,6 +736,40 @@ func (mm *messageManager) postpone(ctx context.Context, tsv TabletService, ackWa
 	return nil
 }
 
+// deadLetter moves the messages that reached the max number of
+// attempts to the dead-letter table.
+func (mm *messageManager) deadLetter(ids []string) {
+	defer func() {
+		mm.tsv.LogError()
+		mm.wg.Done()
+	}()
+
+	defer func() {
+		// See send for why cacheManagementMu is needed.
+		mm.cacheManagementMu.Lock()
+		defer mm.cacheManagementMu.Unlock()
+		mm.cache.Discard(ids)
+	}()
+
+	// Dead letters share the postpone semaphore: both occupy
+	// tx pool connections.
+	if err := mm.postponeSema.Acquire(tabletenv.LocalContext(), 1); err != nil {
+		return
+	}
+	defer mm.postponeSema.Release(1)
+	ctx, cancel := context.WithTimeout(tabletenv.LocalContext(), mm.ackWaitTime)
+	defer cancel()
+	count, err := mm.tsv.DeadLetterMessages(ctx, nil, mm, ids)
+	if err != nil {
+		// The messages stay in the message table. They'll be
+		// tried again after the next poll.
+		MessageStats.Add([]string{mm.name.String(), "DeadLetterFailed"}, 1)
+		log.Errorf("messageManager - unable to move messages of %s to the dead-letter table: %v", mm.name.String(), err)
+		return
+	}
+	MessageStats.Add([]string{mm.name.String(), "DeadLettered"}, count)
+}
+
 func (mm *messageManager) startVStream() {
 	if mm.streamCancel != nil {
 		return
@@ -752,7 +880,9 @@ func (mm *messageManager) processRowEvent(fields []*querypb.Field, rowEvent *bin
 		return fmt.Errorf("internal error: unexpected rows without fields")
 	}
 
-	now := time.Now().UnixNano()
+	// Messages that are due before the next poll are added to the
+	// cache, which holds on to them until their time_next.
+	horizon := time.Now().Add(mm.pollerTicks.Interval()).UnixNano()
 	for _, rc := range rowEvent.RowChanges {
 		if rc.After == nil {
 			continue
@@ -762,7 +892,10 @@ func (mm *messageManager) processRowEvent(fields []*querypb.Field, rowEvent *bin
 		if err != nil {
 			return err
 		}
-		if mr.TimeAcked != 0 || mr.TimeNext > now {
+		if mr.TimeAcked != 0 || mr.TimeNext > horizon {
+			// The message was acked, or postponed past the next
+			// poll: it must not be sent from the cache anymore.
+			mm.cache.Remove([]string{mr.Row[0].ToString()})
 			continue
 		}
 		mm.Add(mr)
@@ -792,7 +925,8 @@ func (mm *messageManager) runPoller() {
 
 	size := mm.cache.Size()
 	bindVars := map[string]*querypb.BindVariable{
-		"time_next": sqltypes.Int64BindVariable(time.Now().UnixNano()),
+		// Also load the messages that become due before the next poll.
+		"time_next": sqltypes.Int64BindVariable(time.Now().Add(mm.pollerTicks.Interval()).UnixNano()),
 		"max":       sqltypes.Int64BindVariable(int64(size)),
 	}
 
@@ -850,41 +984,20 @@ func (mm *messageManager) runPurge() {
 
 // GenerateAckQuery returns the query and bind vars for acking a message.
 func (mm *messageManager) GenerateAckQuery(ids []string) (string, map[string]*querypb.BindVariable) {
-	idbvs := &querypb.BindVariable{
-		Type:   querypb.Type_TUPLE,
-		Values: make([]*querypb.Value, 0, len(ids)),
-	}
-	for _, id := range ids {
-		idbvs.Values = append(idbvs.Values, &querypb.Value{
-			Type:  querypb.Type_VARBINARY,
-			Value: []byte(id),
-		})
-	}
 	return mm.ackQuery.Query, map[string]*querypb.BindVariable{
 		"time_acked": sqltypes.Int64BindVariable(time.Now().UnixNano()),
-		"ids":        idbvs,
+		"ids":        idsBindVariable(ids),
 	}
 }
 
 // GeneratePostponeQuery returns the query and bind vars for postponing a message.
 func (mm *messageManager) GeneratePostponeQuery(ids []string) (string, map[string]*querypb.BindVariable) {
-	idbvs := &querypb.BindVariable{
-		Type:   querypb.Type_TUPLE,
-		Values: make([]*querypb.Value, 0, len(ids)),
-	}
-	for _, id := range ids {
-		idbvs.Values = append(idbvs.Values, &querypb.Value{
-			Type:  querypb.Type_VARBINARY,
-			Value: []byte(id),
-		})
-	}
-
 	bvs := map[string]*querypb.BindVariable{
 		"time_now":    sqltypes.Int64BindVariable(time.Now().UnixNano()),
 		"wait_time":   sqltypes.Int64BindVariable(int64(mm.ackWaitTime)),
 		"min_backoff": sqltypes.Int64BindVariable(int64(mm.minBackoff)),
 		"jitter":      sqltypes.Float64BindVariable(.666666 + rand.Float64()*.666666),
-		"ids":         idbvs,
+		"ids":         idsBindVariable(ids),
 	}
 
 	if mm.maxBackoff > 0 {
@@ -901,6 +1014,65 @@ func (mm *messageManager) GeneratePurgeQuery(timeCutoff int64) (string, map[stri
 	}
 }
 
+// GenerateDeadLetterQueries returns the queries and bind vars for moving
+// messages to the dead-letter table. The queries must run in the same
+// transaction.
+func (mm *messageManager) GenerateDeadLetterQueries(ids []string) ([]string, map[string]*querypb.BindVariable, error) {
+	if mm.deadLetterQueries == nil {
+		return nil, nil, mm.noDeadLetterTableError()
+	}
+	return parsedQueries(mm.deadLetterQueries), map[string]*querypb.BindVariable{
+		"time_now":       sqltypes.Int64BindVariable(time.Now().UnixNano()),
+		"failure_reason": sqltypes.StringBindVariable(mm.deadLetterFailureText),
+		"max_attempts":   sqltypes.Int64BindVariable(int64(mm.maxAttempts)),
+		"ids":            idsBindVariable(ids),
+	}, nil
+}
+
+// GenerateRequeueQueries returns the queries and bind vars for moving
+// messages from the dead-letter table back to the message table, with
+// a fresh attempt count. All the dead letters are requeued if ids is
+// empty. The queries must run in the same transaction.
+func (mm *messageManager) GenerateRequeueQueries(ids []string) ([]string, map[string]*querypb.BindVariable, error) {
+	if mm.requeueQueries == nil {
+		return nil, nil, mm.noDeadLetterTableError()
+	}
+	bvs := map[string]*querypb.BindVariable{
+		"time_now": sqltypes.Int64BindVariable(time.Now().UnixNano()),
+	}
+	if len(ids) == 0 {
+		return parsedQueries(mm.requeueAllQueries), bvs, nil
+	}
+	bvs["ids"] = idsBindVariable(ids)
+	return parsedQueries(mm.requeueQueries), bvs, nil
+}
+
+func (mm *messageManager) noDeadLetterTableError() error {
+	return vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "message table %s has no dead-letter table", mm.name.String())
+}
+
+func parsedQueries(pqs []*sqlparser.ParsedQuery) []string {
+	queries := make([]string, 0, len(pqs))
+	for _, pq := range pqs {
+		queries = append(queries, pq.Query)
+	}
+	return queries
+}
+
+func idsBindVariable(ids []string) *querypb.BindVariable {
+	idbvs := &querypb.BindVariable{
+		Type:   querypb.Type_TUPLE,
+		Values: make([]*querypb.Value, 0, len(ids)),
+	}
+	for _, id := range ids {
+		idbvs.Values = append(idbvs.Values, &querypb.Value{
+			Type:  querypb.Type_VARBINARY,
+			Value: []byte(id),
+		})
+	}
+	return idbvs
+}
+
 // BuildMessageRow builds a MessageRow from a db row.
 func BuildMessageRow(row []sqltypes.Value) (*MessageRow, error) {
 	mr := &MessageRow{Row: row[4:]}
@@ -95,6 +95,21 @@ func newMMTableWithBackoff() *schema.Table {
 	}
 }
 
+func newMMTableWithDeadLetters() *schema.Table {
+	ti := newMMTable()
+	ti.Fields = []*querypb.Field{
+		{Name: "id", Type: sqltypes.Int64},
+		{Name: "priority", Type: sqltypes.Int64},
+		{Name: "time_next", Type: sqltypes.Int64},
+		{Name: "epoch", Type: sqltypes.Int64},
+		{Name: "time_acked", Type: sqltypes.Int64},
+		{Name: "message", Type: sqltypes.VarBinary},
+	}
+	ti.MessageInfo.MaxAttempts = 2
+	ti.MessageInfo.DeadLetterTable = "foo_dlq"
+	return ti
+}
+
 func newMMRow(id int64) *querypb.Row {
 	return sqltypes.RowToProto3([]sqltypes.Value{
 		sqltypes.NewInt64(1),
@@ -298,6 +313,51 @@ func TestMessageManagerSend(t *testing.T) {
 	<-r1.ch
 }
 
+func TestMessageManagerDeadLetter(t *testing.T) {
+	tsv := newFakeTabletServer()
+	mm := newMessageManager(tsv, newFakeVStreamer(), newMMTableWithDeadLetters(), semaphore.NewWeighted(1))
+	mm.Open()
+	defer mm.Close()
+
+	r1 := newTestReceiver(1)
+	mm.Subscribe(context.Background(), r1.rcv)
+	<-r1.ch
+
+	ch := make(chan string, 2)
+	tsv.SetChannel(ch)
+	deadLettered := MessageStats.Counts()["foo.DeadLettered"]
+
+	// The message reached the max attempts: it's not sent again.
+	mm.Add(&MessageRow{
+		Epoch: 2,
+		Row:   []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("1")},
+	})
+	assert.Equal(t, "deadletter", <-ch)
+	assert.Equal(t, []string{"1"}, tsv.deadLetterIDs())
+	select {
+	case got := <-r1.ch:
+		t.Errorf("Expecting no value, got: %v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.EqualValues(t, 1, MessageStats.Counts()["foo.DeadLettered"]-deadLettered)
+
+	// The message can be retried.
+	mm.Add(&MessageRow{
+		Epoch: 1,
+		Row:   []sqltypes.Value{sqltypes.NewInt64(2), sqltypes.NewVarBinary("2")},
+	})
+	want := &sqltypes.Result{
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewInt64(2),
+			sqltypes.NewVarBinary("2"),
+		}},
+	}
+	if got := <-r1.ch; !got.Equal(want) {
+		t.Errorf("Received: %v, want %v", got, want)
+	}
+	assert.Equal(t, "postpone", <-ch)
+}
+
 func TestMessageManagerPostponeThrottle(t *testing.T) {
 	tsv := newFakeTabletServer()
 	mm := newMessageManager(tsv, newFakeVStreamer(), newMMTable(), semaphore.NewWeighted(1))
@@ -486,6 +546,110 @@ func TestMessageManagerStreamerSimple(t *testing.T) {
 	}
 }
 
+func TestMessageManagerStreamerScheduled(t *testing.T) {
+	due := time.Now().Add(200 * time.Millisecond)
+	scheduledRow := func(id int64, timeNext time.Time) *querypb.Row {
+		return sqltypes.RowToProto3([]sqltypes.Value{
+			sqltypes.NewInt64(1),
+			sqltypes.NewInt64(timeNext.UnixNano()),
+			sqltypes.NewInt64(0),
+			sqltypes.NULL,
+			sqltypes.NewInt64(id),
+			sqltypes.NewVarBinary(fmt.Sprintf("%v", id)),
+		})
+	}
+	fvs := newFakeVStreamer()
+	fvs.setStreamerResponse([][]*binlogdatapb.VEvent{{{
+		Type: binlogdatapb.VEventType_FIELD,
+		FieldEvent: &binlogdatapb.FieldEvent{
+			TableName: "foo",
+			Fields:    testDBFields,
+		},
+	}}, {{
+		// Row 1 is due before the next poll: it's held by the cache.
+		// Row 2 is due after the next poll: it's left to the poller.
+		Type: binlogdatapb.VEventType_ROW,
+		RowEvent: &binlogdatapb.RowEvent{
+			TableName: "foo",
+			RowChanges: []*binlogdatapb.RowChange{{
+				After: scheduledRow(1, due),
+			}, {
+				After: scheduledRow(2, due.Add(time.Hour)),
+			}},
+		},
+	}, {
+		Type: binlogdatapb.VEventType_GTID,
+		Gtid: "MySQL56/33333333-3333-3333-3333-333333333333:1-101",
+	}, {
+		Type: binlogdatapb.VEventType_COMMIT,
+	}}})
+	ti := newMMTable()
+	ti.MessageInfo.PollInterval = 30 * time.Second
+	mm := newMessageManager(newFakeTabletServer(), fvs, ti, semaphore.NewWeighted(1))
+	mm.Open()
+	defer mm.Close()
+
+	r1 := newTestReceiver(1)
+	mm.Subscribe(context.Background(), r1.rcv)
+	<-r1.ch
+
+	want := &sqltypes.Result{
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewInt64(1),
+			sqltypes.NewVarBinary("1"),
+		}},
+	}
+	got := <-r1.ch
+	assert.False(t, time.Now().Before(due), "message sent before its time_next")
+	assert.True(t, got.Equal(want), "Received: %v, want %v", got, want)
+
+	select {
+	case got := <-r1.ch:
+		t.Errorf("Expecting no value, got: %v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestMessageManagerStreamerAcked(t *testing.T) {
+	ackedRow := sqltypes.RowToProto3([]sqltypes.Value{
+		sqltypes.NewInt64(1),
+		sqltypes.NULL,
+		sqltypes.NewInt64(0),
+		sqltypes.NewInt64(1),
+		sqltypes.NewInt64(1),
+		sqltypes.NewVarBinary("1"),
+	})
+	fvs := newFakeVStreamer()
+	ti := newMMTable()
+	ti.MessageInfo.PollInterval = 30 * time.Second
+	mm := newMessageManager(newFakeTabletServer(), fvs, ti, semaphore.NewWeighted(1))
+	mm.Open()
+	defer mm.Close()
+
+	r1 := newTestReceiver(1)
+	mm.Subscribe(context.Background(), r1.rcv)
+	<-r1.ch
+
+	// Schedule the message for later, then ack it before it's due.
+	mm.cacheManagementMu.Lock()
+	mm.Add(&MessageRow{
+		TimeNext: time.Now().Add(100 * time.Millisecond).UnixNano(),
+		Row:      []sqltypes.Value{sqltypes.NewInt64(1), sqltypes.NewVarBinary("1")},
+	})
+	err := mm.processRowEvent(testDBFields, &binlogdatapb.RowEvent{
+		TableName:  "foo",
+		RowChanges: []*binlogdatapb.RowChange{{After: ackedRow}},
+	})
+	mm.cacheManagementMu.Unlock()
+	assert.NoError(t, err)
+
+	select {
+	case got := <-r1.ch:
+		t.Errorf("Expecting no value, got: %v", got)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
 func TestMessageManagerStreamerAndPoller(t *testing.T) {
 	fvs := newFakeVStreamer()
 	fvs.setPollerResponse([]*binlogdatapb.VStreamResultsResponse{{
@@ -787,6 +951,52 @@ func TestMMGenerate(t *testing.T) {
 	}
 }
 
+func TestMMGenerateDeadLetters(t *testing.T) {
+	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), newMMTableWithDeadLetters(), semaphore.NewWeighted(1))
+	wantids := sqltypes.TestBindVariable([]any{[]byte{'1'}, []byte{'2'}})
+
+	queries, bv, err := mm.GenerateDeadLetterQueries([]string{"1", "2"})
+	assert.NoError(t, err)
+	wantQueries := []string{
+		"insert into foo_dlq(id, priority, time_next, epoch, time_acked, message, time_dead_lettered, failure_reason) select id, priority, time_next, epoch, time_acked, message, :time_now, :failure_reason from foo where id in ::ids and time_acked is null and epoch >= :max_attempts",
+		"delete from foo where id in ::ids and time_acked is null and epoch >= :max_attempts",
+	}
+	assert.Equal(t, wantQueries, queries)
+	assert.Contains(t, bv, "time_now")
+	delete(bv, "time_now")
+	wantbv := map[string]*querypb.BindVariable{
+		"failure_reason": sqltypes.StringBindVariable("exceeded 2 delivery attempts"),
+		"max_attempts":   sqltypes.Int64BindVariable(2),
+		"ids":            wantids,
+	}
+	utils.MustMatch(t, wantbv, bv, "did not match")
+
+	queries, bv, err = mm.GenerateRequeueQueries([]string{"1", "2"})
+	assert.NoError(t, err)
+	wantQueries = []string{
+		"insert into foo(id, priority, message, time_next, epoch) select id, priority, message, :time_now, 0 from foo_dlq where id in ::ids",
+		"delete from foo_dlq where id in ::ids",
+	}
+	assert.Equal(t, wantQueries, queries)
+	assert.Contains(t, bv, "time_now")
+	utils.MustMatch(t, wantids, bv["ids"], "did not match")
+
+	queries, bv, err = mm.GenerateRequeueQueries(nil)
+	assert.NoError(t, err)
+	wantQueries = []string{
+		"insert into foo(id, priority, message, time_next, epoch) select id, priority, message, :time_now, 0 from foo_dlq",
+		"delete from foo_dlq",
+	}
+	assert.Equal(t, wantQueries, queries)
+	assert.NotContains(t, bv, "ids")
+
+	mm = newMessageManager(newFakeTabletServer(), newFakeVStreamer(), newMMTable(), semaphore.NewWeighted(1))
+	_, _, err = mm.GenerateDeadLetterQueries([]string{"1"})
+	assert.EqualError(t, err, "message table foo has no dead-letter table")
+	_, _, err = mm.GenerateRequeueQueries(nil)
+	assert.EqualError(t, err, "message table foo has no dead-letter table")
+}
+
 func TestMMGenerateWithBackoff(t *testing.T) {
 	mm := newMessageManager(newFakeTabletServer(), newFakeVStreamer(), newMMTableWithBackoff(), semaphore.NewWeighted(1))
 	mm.Open()
@@ -827,8 +1037,9 @@ type fakeTabletServer struct {
 	postponeCount atomic.Int64
 	purgeCount    atomic.Int64
 
-	mu sync.Mutex
-	ch chan string
+	mu           sync.Mutex
+	ch           chan string
+	deadLettered []string
 }
 
 func newFakeTabletServer() *fakeTabletServer {
@@ -857,6 +1068,23 @@ func (fts *fakeTabletServer) PostponeMessages(ctx context.Context, target *query
 	return 0, nil
 }
 
+func (fts *fakeTabletServer) DeadLetterMessages(ctx context.Context, target *querypb.Target, gen QueryGenerator, ids []string) (count int64, err error) {
+	fts.mu.Lock()
+	ch := fts.ch
+	fts.deadLettered = append(fts.deadLettered, ids...)
+	fts.mu.Unlock()
+	if ch != nil {
+		ch <- "deadletter"
+	}
+	return int64(len(ids)), nil
+}
+
+func (fts *fakeTabletServer) deadLetterIDs() []string {
+	fts.mu.Lock()
+	defer fts.mu.Unlock()
+	return fts.deadLettered
+}
+
 func (fts *fakeTabletServer) PurgeMessages(ctx context.Context, target *querypb.Target, gen QueryGenerator, timeCutoff int64) (count int64, err error) {
 	fts.purgeCount.Add(1)
 	fts.mu.Lock()
@@ -63,6 +63,11 @@ type TabletPlan struct {
 	Rules      *rules.Rules
 	Authorized []*tableacl.ACLResult
 
+	// FingerprintRule is the fingerprint rule of the query, if it applies to
+	// the plan, and FingerprintErr is the error it fails the query with.
+	FingerprintRule *FingerprintRule
+	FingerprintErr  error
+
 	QueryCount   uint64
 	Time         uint64
 	MysqlTime    uint64
@@ -155,6 +160,10 @@ type QueryEngine struct {
 	settings         *SettingsCache
 	queryRuleSources *rules.Map
 
+	// fingerprintRules are the query fingerprint rules of the tablet, by
+	// fingerprint.
+	fingerprintRules atomic.Pointer[map[string]*FingerprintRule]
+
 	// Pools
 	conns       *connpool.Pool
 	streamConns *connpool.Pool
@@ -189,10 +198,14 @@ type QueryEngine struct {
 	// Note: queryErrorCountsWithCode is similar to queryErrorCounts except it contains error code as an additional dimension
 	queryCounts, queryCountsWithTabletType, queryTimes, queryErrorCounts, queryErrorCountsWithCode, queryRowsAffected, queryRowsReturned *stats.CountersWithMultiLabels
 	queryCacheHits, queryCacheMisses                                                                                                     *stats.CounterFunc
+	fingerprintRuleFailures                                                                                                              *stats.CountersWithSingleLabel
 
 	// stats flags
 	enablePerWorkloadTableMetrics bool
 
+	// tableStats is nil if the table metrics are disabled.
+	tableStats *tableStats
+
 	// Loggers
 	accessCheckerLogger *logutil.ThrottledLogger
 }
@@ -300,6 +313,8 @@ func NewQueryEngine(env tabletenv.Env, se *schema.Engine) *QueryEngine {
 	qe.queryRowsReturned = env.Exporter().NewCountersWithMultiLabels("QueryRowsReturned", "query rows returned", labels)
 	qe.queryErrorCounts = env.Exporter().NewCountersWithMultiLabels("QueryErrorCounts", "query error counts", labels)
 	qe.queryErrorCountsWithCode = env.Exporter().NewCountersWithMultiLabels("QueryErrorCountsWithCode", "query error counts with error code", []string{"Table", "Plan", "Code"})
+	qe.fingerprintRuleFailures = env.Exporter().NewCountersWithSingleLabel("QueryFingerprintRuleFailures", "queries failed by the query fingerprint rules", "Action")
+	qe.tableStats = newTableStats(env)
 
 	env.Exporter().HandleFunc("/debug/hotrows", qe.txSerializer.ServeHTTP)
 	env.Exporter().HandleFunc("/debug/tablet_plans", qe.handleHTTPQueryPlans)
@@ -341,6 +356,7 @@ func (qe *QueryEngine) Open() error {
 	qe.se.RegisterNotifier("qe", qe.schemaChanged, true)
 	qe.plans.EnsureOpen()
 	qe.settings.EnsureOpen()
+	qe.loadFingerprintRulesOnOpen()
 	qe.isOpen.Store(true)
 	return nil
 }
@@ -375,6 +391,7 @@ func (qe *QueryEngine) getPlan(curSchema *currentSchema, sql string) (*TabletPla
 		return nil, err
 	}
 	plan := &TabletPlan{Plan: splan, Original: sql}
+	qe.applyFingerprintRule(plan, sql, false)
 	plan.Rules = qe.queryRuleSources.FilterByPlan(sql, plan.PlanID, plan.TableNames()...)
 	plan.buildAuthorized()
 	if sqlparser.CachePlan(statement) {
@@ -421,6 +438,7 @@ func (qe *QueryEngine) getStreamPlan(curSchema *currentSchema, sql string) (*Tab
 	}
 
 	plan := &TabletPlan{Plan: splan, Original: sql}
+	qe.applyFingerprintRule(plan, sql, true)
 	plan.Rules = qe.queryRuleSources.FilterByPlan(sql, plan.PlanID, plan.TableName().String())
 	plan.buildAuthorized()
 
@@ -23,6 +23,7 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"vitess.io/vitess/go/mysql"
@@ -146,6 +147,7 @@ func (qre *QueryExecutor) Execute() (reply *sqltypes.Result, err error) {
 
 		qre.tsv.qe.AddStats(qre.plan.PlanID, tableName, qre.options.GetWorkloadName(), qre.targetTabletType, 1, duration, mysqlTime, int64(reply.RowsAffected), int64(len(reply.Rows)), 0, errCode)
 		qre.plan.AddStats(1, duration, mysqlTime, reply.RowsAffected, uint64(len(reply.Rows)), 0)
+		qre.tsv.qe.tableStats.add(qre.plan.TableNames(), qre.targetTabletType, int64(len(reply.Rows)), int64(reply.RowsAffected))
 		qre.logStats.RowsAffected = int(reply.RowsAffected)
 		qre.logStats.Rows = reply.Rows
 		qre.tsv.Stats().ResultHistogram.Add(int64(len(reply.Rows)))
@@ -241,6 +243,25 @@ func (qre *QueryExecutor) execAutocommit(f func(conn *StatefulConnection) (*sqlt
 	}
 	defer qre.tsv.te.txPool.RollbackAndRelease(qre.ctx, conn)
 
+	if qre.options.GetTrackGtids() {
+		return qre.execTrackingGtids(conn, f)
+	}
+	return f(conn)
+}
+
+// execTrackingGtids executes f on conn while MySQL tracks the GTIDs of the
+// session, so that the result of an autocommit write carries the GTID set that
+// MySQL executed once the write was committed. The write still runs when MySQL
+// can't track them, and then carries none.
+func (qre *QueryExecutor) execTrackingGtids(conn *StatefulConnection, f func(conn *StatefulConnection) (*sqltypes.Result, error)) (*sqltypes.Result, error) {
+	if _, err := conn.Exec(qre.ctx, trackAllGtidsQuery, 1, false); err != nil {
+		return f(conn)
+	}
+	defer func() {
+		// The connection goes back to the pool once the write is done. It
+		// only reports GTIDs in vain if this fails.
+		_, _ = conn.Exec(qre.ctx, untrackGtidsQuery, 1, false)
+	}()
 	return f(conn)
 }
 
@@ -439,6 +460,11 @@ func (qre *QueryExecutor) checkPermissions() error {
 		return nil
 	}
 
+	if qre.plan.FingerprintErr != nil {
+		qre.tsv.qe.fingerprintRuleFailures.Add(qre.plan.FingerprintRule.Action.String(), 1)
+		return qre.plan.FingerprintErr
+	}
+
 	// Check if the query relates to a table that is in the denylist.
 	remoteAddr := ""
 	username := ""
@@ -697,6 +723,7 @@ func (qre *QueryExecutor) execSelect() (*sqltypes.Result, error) {
 	if err != nil {
 		return nil, err
 	}
+	qre.tsv.qe.tableStats.sampleExplain(qre.tsv.qe.conns, sqlWithoutComments, qre.plan.TableNames(), qre.targetTabletType)
 	// Check tablet type.
 	if qre.shouldConsolidate() {
 		q, original := qre.tsv.qe.consolidator.Create(sqlWithoutComments)
@@ -848,15 +875,21 @@ func (qre *QueryExecutor) generateFinalSQL(parsedQuery *sqlparser.ParsedQuery, b
 		qre.marginComments.Leading = buf.String()
 	}
 
-	if qre.marginComments.Leading == "" && qre.marginComments.Trailing == "" {
+	// The trace comment lets MySQL-side tooling join the query to its trace.
+	trailing := qre.marginComments.Trailing
+	if comment := trace.SQLComment(qre.ctx); comment != "" {
+		trailing += " " + comment
+	}
+
+	if qre.marginComments.Leading == "" && trailing == "" {
 		return query, query, nil
 	}
 
 	var buf strings.Builder
-	buf.Grow(len(qre.marginComments.Leading) + len(query) + len(qre.marginComments.Trailing))
+	buf.Grow(len(qre.marginComments.Leading) + len(query) + len(trailing))
 	buf.WriteString(qre.marginComments.Leading)
 	buf.WriteString(query)
-	buf.WriteString(qre.marginComments.Trailing)
+	buf.WriteString(trailing)
 	return buf.String(), query, nil
 }
 
@@ -963,6 +996,8 @@ func (qre *QueryExecutor) execAlterMigration() (*sqltypes.Result, error) {
 		return qre.tsv.onlineDDLExecutor.ForceCutOverMigration(qre.ctx, alterMigration.UUID)
 	case sqlparser.ForceCutOverAllMigrationType:
 		return qre.tsv.onlineDDLExecutor.ForceCutOverPendingMigrations(qre.ctx)
+	case sqlparser.CutOverMigrationType:
+		return qre.tsv.onlineDDLExecutor.CutOverMigration(qre.ctx, alterMigration.UUID)
 	}
 	return nil, vterrors.New(vtrpcpb.Code_UNIMPLEMENTED, "ALTER VITESS_MIGRATION not implemented")
 }
@@ -1078,13 +1113,24 @@ func (qre *QueryExecutor) getSelectLimit() int64 {
 	return qre.tsv.qe.maxResultSize.Load()
 }
 
+// newQueryDetail returns the QueryDetail of the query, to track it while it
+// runs on conn.
+func (qre *QueryExecutor) newQueryDetail(conn killable) *QueryDetail {
+	qd := NewQueryDetail(qre.logStats.Ctx, conn)
+	qd.query = qre.query
+	if qre.plan != nil {
+		qd.planType = qre.plan.PlanID.String()
+	}
+	return qd
+}
+
 func (qre *QueryExecutor) execDBConn(conn *connpool.Conn, sql string, wantfields bool) (*sqltypes.Result, error) {
 	span, ctx := trace.NewSpan(qre.ctx, "QueryExecutor.execDBConn")
 	defer span.Finish()
 
 	defer qre.logStats.AddRewrittenSQL(sql, time.Now())
 
-	qd := NewQueryDetail(qre.logStats.Ctx, conn)
+	qd := qre.newQueryDetail(conn)
 	qre.tsv.statelessql.Add(qd)
 	defer qre.tsv.statelessql.Remove(qd)
 
@@ -1097,7 +1143,7 @@ func (qre *QueryExecutor) execStatefulConn(conn *StatefulConnection, sql string,
 
 	defer qre.logStats.AddRewrittenSQL(sql, time.Now())
 
-	qd := NewQueryDetail(qre.logStats.Ctx, conn)
+	qd := qre.newQueryDetail(conn)
 	qre.tsv.statefulql.Add(qd)
 	defer qre.tsv.statefulql.Remove(qd)
 
@@ -1107,28 +1153,105 @@ func (qre *QueryExecutor) execStatefulConn(conn *StatefulConnection, sql string,
 func (qre *QueryExecutor) execStreamSQL(conn *connpool.PooledConn, isTransaction bool, sql string, callback func(*sqltypes.Result) error) error {
 	span, ctx := trace.NewSpan(qre.ctx, "QueryExecutor.execStreamSQL")
 	trace.AnnotateSQL(span, sqlparser.Preview(sql))
+
+	var chunkTimeout *streamChunkTimeout
+	if timeout := time.Duration(qre.options.GetStreamChunkTimeoutMs()) * time.Millisecond; timeout > 0 {
+		ctx, chunkTimeout = newStreamChunkTimeout(ctx, timeout)
+		defer chunkTimeout.stop()
+	}
 	callBackClosingSpan := func(result *sqltypes.Result) error {
 		defer span.Finish()
-		return callback(result)
+		qre.recordStreamChunk(result)
+		err := callback(result)
+		chunkTimeout.reset()
+		return err
 	}
 
 	start := time.Now()
 	defer qre.logStats.AddRewrittenSQL(sql, start)
 
+	streamBufferSize := int(qre.tsv.qe.streamBufferSize.Load())
+	streamBufferRows := int(qre.options.GetStreamChunkRows())
+
 	// Add query detail object into QueryExecutor TableServer list w.r.t if it is a transactional or not. Previously we were adding it
 	// to olapql list regardless but that resulted in problems, where long-running stream queries which can be stateful (or transactional)
 	// weren't getting cleaned up during unserveCommon>terminateAllQueries in state_manager.go.
 	// This change will ensure that long-running streaming stateful queries get gracefully shutdown during ServingTypeChange
 	// once their grace period is over.
-	qd := NewQueryDetail(qre.logStats.Ctx, conn.Conn)
+	qd := qre.newQueryDetail(conn.Conn)
+	var err error
 	if isTransaction {
 		qre.tsv.statefulql.Add(qd)
 		defer qre.tsv.statefulql.Remove(qd)
-		return conn.Conn.StreamOnce(ctx, sql, callBackClosingSpan, allocStreamResult, int(qre.tsv.qe.streamBufferSize.Load()), sqltypes.IncludeFieldsOrDefault(qre.options))
+		err = conn.Conn.StreamOnce(ctx, sql, callBackClosingSpan, allocStreamResult, streamBufferSize, streamBufferRows, sqltypes.IncludeFieldsOrDefault(qre.options))
+	} else {
+		qre.tsv.olapql.Add(qd)
+		defer qre.tsv.olapql.Remove(qd)
+		err = conn.Conn.Stream(ctx, sql, callBackClosingSpan, allocStreamResult, streamBufferSize, streamBufferRows, sqltypes.IncludeFieldsOrDefault(qre.options))
+	}
+	if err != nil && chunkTimeout.expired() {
+		return vterrors.Errorf(vtrpcpb.Code_DEADLINE_EXCEEDED, "no chunk of the streaming query was sent within the stream chunk timeout of %v", chunkTimeout.timeout)
+	}
+	return err
+}
+
+// recordStreamChunk records the size of a chunk of rows of a streaming query.
+func (qre *QueryExecutor) recordStreamChunk(result *sqltypes.Result) {
+	if len(result.Rows) == 0 {
+		return
+	}
+	var size int64
+	for _, row := range result.Rows {
+		for _, v := range row {
+			size += int64(v.Len())
+		}
+	}
+	qre.tsv.stats.StreamChunkRows.Add(int64(len(result.Rows)))
+	qre.tsv.stats.StreamChunkBytes.Add(size)
+}
+
+// streamChunkTimeout cancels the context of a streaming query when no chunk
+// was sent for longer than its timeout. All of its methods accept a nil
+// receiver, which never expires.
+type streamChunkTimeout struct {
+	timeout time.Duration
+	timer   *time.Timer
+	cancel  context.CancelFunc
+	fired   atomic.Bool
+}
+
+func newStreamChunkTimeout(ctx context.Context, timeout time.Duration) (context.Context, *streamChunkTimeout) {
+	ctx, cancel := context.WithCancel(ctx)
+	ct := &streamChunkTimeout{
+		timeout: timeout,
+		cancel:  cancel,
+	}
+	ct.timer = time.AfterFunc(timeout, func() {
+		ct.fired.Store(true)
+		cancel()
+	})
+	return ctx, ct
+}
+
+// reset restarts the timeout after a chunk was sent.
+func (ct *streamChunkTimeout) reset() {
+	if ct == nil || ct.fired.Load() {
+		return
+	}
+	ct.timer.Reset(ct.timeout)
+}
+
+// expired returns true if the timeout canceled the query.
+func (ct *streamChunkTimeout) expired() bool {
+	return ct != nil && ct.fired.Load()
+}
+
+func (ct *streamChunkTimeout) stop() {
+	if ct == nil {
+		return
 	}
-	qre.tsv.olapql.Add(qd)
-	defer qre.tsv.olapql.Remove(qd)
-	return conn.Conn.Stream(ctx, sql, callBackClosingSpan, allocStreamResult, int(qre.tsv.qe.streamBufferSize.Load()), sqltypes.IncludeFieldsOrDefault(qre.options))
+	ct.timer.Stop()
+	ct.cancel()
 }
 
 func (qre *QueryExecutor) recordUserQuery(queryType string, duration int64) {
@@ -1300,6 +1300,93 @@ func TestReplaceSchemaName(t *testing.T) {
 	}
 }
 
+func TestQueryExecutorStreamChunks(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+
+	query := "select * from test_table"
+	result := &sqltypes.Result{
+		Fields: getTestTableFields(),
+	}
+	for i := 0; i < 5; i++ {
+		result.Rows = append(result.Rows, []sqltypes.Value{sqltypes.NewInt32(int32(i)), sqltypes.NewInt32(1), sqltypes.NewInt32(2)})
+	}
+	db.AddQuery(query, result)
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	defer tsv.StopService()
+
+	// Test the row count of the chunks.
+	{
+		qre := newTestQueryExecutorStreaming(ctx, tsv, query, 0)
+		qre.options = &querypb.ExecuteOptions{StreamChunkRows: 2}
+		var chunks []int
+		err := qre.Stream(func(qr *sqltypes.Result) error {
+			if len(qr.Rows) > 0 {
+				chunks = append(chunks, len(qr.Rows))
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 2, 1}, chunks)
+		assert.EqualValues(t, 3, tsv.stats.StreamChunkRows.Count())
+		assert.EqualValues(t, 5, tsv.stats.StreamChunkRows.Total())
+	}
+
+	// Test the timeout between two chunks.
+	{
+		qre := newTestQueryExecutorStreaming(ctx, tsv, query, 0)
+		qre.options = &querypb.ExecuteOptions{StreamChunkRows: 1, StreamChunkTimeoutMs: 10}
+		err := qre.Stream(func(qr *sqltypes.Result) error {
+			if len(qr.Rows) > 0 {
+				time.Sleep(100 * time.Millisecond)
+			}
+			return nil
+		})
+		require.EqualError(t, err, "no chunk of the streaming query was sent within the stream chunk timeout of 10ms")
+		assert.Equal(t, vtrpcpb.Code_DEADLINE_EXCEEDED, vterrors.Code(err))
+	}
+}
+
+func TestQueryExecutorTrackGtids(t *testing.T) {
+	db := setUpQueryExecutorTest(t)
+	defer db.Close()
+
+	query := "insert into test_table(a) values(1)"
+	db.AddQuery("insert into test_table(a) values (1)", &sqltypes.Result{RowsAffected: 1})
+	db.AddQuery(trackAllGtidsQuery, &sqltypes.Result{})
+	db.AddQuery(untrackGtidsQuery, &sqltypes.Result{})
+
+	ctx := context.Background()
+	tsv := newTestTabletServer(ctx, noFlags, db)
+	defer tsv.StopService()
+
+	// MySQL tracks the GTIDs of the write, and the connection stops
+	// tracking them before it goes back to the pool.
+	db.ResetQueryLog()
+	qre := newTestQueryExecutor(ctx, tsv, query, 0)
+	qre.options = &querypb.ExecuteOptions{TrackGtids: true}
+	_, err := qre.Execute()
+	require.NoError(t, err)
+	want := strings.Join([]string{trackAllGtidsQuery, "insert into test_table(a) values (1)", untrackGtidsQuery}, ";")
+	assert.Contains(t, db.QueryLog(), strings.ToLower(want))
+
+	// Without the option, it doesn't track them.
+	qre = newTestQueryExecutor(ctx, tsv, query, 0)
+	_, err = qre.Execute()
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.GetQueryCalledNum(trackAllGtidsQuery))
+
+	// The write still runs when MySQL can't track them.
+	db.DeleteQuery(trackAllGtidsQuery)
+	qre = newTestQueryExecutor(ctx, tsv, query, 0)
+	qre.options = &querypb.ExecuteOptions{TrackGtids: true}
+	_, err = qre.Execute()
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.GetQueryCalledNum(untrackGtidsQuery))
+}
+
 func TestQueryExecutorShouldConsolidate(t *testing.T) {
 	testCases := []struct {
 		// whether or not the consolidator is enabled by default on the tablet
@@ -1600,7 +1687,7 @@ func initQueryExecutorTestDB(db *fakesqldb.DB) {
 		Rows: [][]sqltypes.Value{
 			mysql.BaseShowTablesRow("test_table", false, ""),
 			mysql.BaseShowTablesRow("seq", false, "vitess_sequence"),
-			mysql.BaseShowTablesRow("msg", false, "vitess_message,vt_ack_wait=30,vt_purge_after=120,vt_batch_size=1,vt_cache_size=10,vt_poller_interval=30"),
+			mysql.BaseShowTablesRow("msg", false, "vitess_message,vt_ack_wait=30,vt_purge_after=120,vt_batch_size=1,vt_cache_size=10,vt_poller_interval=30,vt_max_attempts=3,vt_dead_letter_table=msg_dlq"),
 		},
 	})
 	db.AddQuery("show status like 'Innodb_rows_read'", sqltypes.MakeTestResult(sqltypes.MakeTestFields(
@@ -1630,6 +1717,8 @@ func addQueryExecutorSupportedQueries(db *fakesqldb.DB) {
 				{sqltypes.NewInt32(1427325875)},
 			},
 		},
+		// query for the fingerprint rules
+		"select fingerprint, action, plan_type, error_message from _vt.query_fingerprint_rules": {},
 		"select @@global.sql_mode": {
 			Fields: []*querypb.Field{{
 				Type: sqltypes.VarChar,
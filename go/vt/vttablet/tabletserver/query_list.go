@@ -25,7 +25,9 @@ import (
 	"github.com/google/safehtml"
 
 	"vitess.io/vitess/go/streamlog"
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/callinfo"
+	querypb "vitess.io/vitess/go/vt/proto/query"
 	"vitess.io/vitess/go/vt/sqlparser"
 )
 
@@ -35,6 +37,13 @@ type QueryDetail struct {
 	conn   killable
 	connID int64
 	start  time.Time
+
+	// query and planType describe the query that is running. They are only
+	// set for the queries of the QueryExecutor.
+	query    string
+	planType string
+	// fingerprint is computed from query when it is first needed.
+	fingerprint string
 }
 
 type killable interface {
@@ -128,6 +137,118 @@ func (ql *QueryList) TerminateAll() {
 	}
 }
 
+// QueryFilter selects running queries. A query must match all the fields
+// that are set.
+type QueryFilter struct {
+	// Query matches the queries with the same fingerprint as this query.
+	Query string
+	// PlanType matches the queries with this plan type.
+	PlanType string
+	// Caller matches the queries of this caller: either the principal of the
+	// effective caller, or the username of the immediate caller.
+	Caller string
+}
+
+// KilledQuery describes a running query that was selected to be killed.
+type KilledQuery struct {
+	ConnID      int64
+	Fingerprint string
+	PlanType    string
+	Caller      string
+	Elapsed     time.Duration
+}
+
+// Kill kills the connections of the running queries that match filter, and
+// returns these queries. The connections are left alone if dryRun is set.
+// The fingerprint of filter.Query must already be computed.
+func (ql *QueryList) Kill(filter QueryFilter, dryRun bool) []KilledQuery {
+	ql.mu.Lock()
+	defer ql.mu.Unlock()
+	var killed []KilledQuery
+	for _, qds := range ql.queryDetails {
+		for _, qd := range qds {
+			principal, username := queryCallers(qd.ctx)
+			if filter.Caller != "" && filter.Caller != principal && filter.Caller != username {
+				continue
+			}
+			if filter.PlanType != "" && qd.planType != filter.PlanType {
+				continue
+			}
+			if filter.Query != "" && ql.fingerprint(qd) != filter.Query {
+				continue
+			}
+
+			caller := principal
+			if caller == "" {
+				caller = username
+			}
+			elapsed := time.Since(qd.start)
+			killed = append(killed, KilledQuery{
+				ConnID:      qd.connID,
+				Fingerprint: ql.fingerprint(qd),
+				PlanType:    qd.planType,
+				Caller:      caller,
+				Elapsed:     elapsed,
+			})
+			if !dryRun {
+				_ = qd.conn.Kill("QueryList.Kill()", elapsed)
+			}
+		}
+	}
+	return killed
+}
+
+func (ql *QueryList) fingerprint(qd *QueryDetail) string {
+	if qd.fingerprint == "" && qd.query != "" {
+		fingerprint, err := queryFingerprint(ql.parser, qd.query)
+		if err != nil {
+			// the query cannot match any fingerprint
+			fingerprint = "<unparsable>"
+		}
+		qd.fingerprint = fingerprint
+	}
+	return qd.fingerprint
+}
+
+// queryCallers returns the principal of the effective caller and the
+// username of the immediate caller of a query.
+func queryCallers(ctx context.Context) (principal, username string) {
+	if ef := callerid.EffectiveCallerIDFromContext(ctx); ef != nil {
+		principal = ef.Principal
+	}
+	if im := callerid.ImmediateCallerIDFromContext(ctx); im != nil {
+		username = im.Username
+	}
+	return principal, username
+}
+
+// queryFingerprint returns the fingerprint of a query: its text once its
+// literals and bind variables are replaced by placeholders, so that all the
+// executions of a query share the same fingerprint, whatever their values.
+func queryFingerprint(parser *sqlparser.Parser, sql string) (string, error) {
+	stripped, _ := sqlparser.SplitMarginComments(sql)
+	stmt, reservedVars, err := parser.Parse2(stripped)
+	if err != nil {
+		return "", err
+	}
+	bv := make(map[string]*querypb.BindVariable)
+	if err := sqlparser.Normalize(stmt, sqlparser.NewReservedVars("bv", reservedVars), bv); err != nil {
+		return "", err
+	}
+	_ = sqlparser.Rewrite(stmt, func(cursor *sqlparser.Cursor) bool {
+		switch node := cursor.Node().(type) {
+		case *sqlparser.Argument:
+			cursor.Replace(sqlparser.NewArgument("?"))
+		case sqlparser.ListArg:
+			if node != "?" {
+				cursor.Replace(sqlparser.ListArg("?"))
+			}
+		}
+		return true
+	}, nil)
+	return sqlparser.CanonicalString(stmt), nil
+}
+
 // QueryDetailzRow is used for rendering QueryDetail in a template
 type QueryDetailzRow struct {
 	Type              string
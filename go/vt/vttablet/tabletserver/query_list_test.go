@@ -23,6 +23,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/vt/callerid"
 	"vitess.io/vitess/go/vt/sqlparser"
 )
 
@@ -92,3 +93,77 @@ func TestQueryListChangeConnIDInMiddle(t *testing.T) {
 	require.Equal(t, qd1, ql.queryDetails[1][0])
 	require.NotEqual(t, qd2, ql.queryDetails[1][0])
 }
+
+func TestQueryFingerprint(t *testing.T) {
+	parser := sqlparser.NewTestParser()
+	testcases := []struct {
+		query string
+		want  string
+	}{{
+		query: "select * from t where id = 1",
+		want:  "SELECT * FROM `t` WHERE `id` = :?",
+	}, {
+		query: "/* comment */ select * from t where id = :id",
+		want:  "SELECT * FROM `t` WHERE `id` = :?",
+	}, {
+		query: "select a from t where id in (1, 2, 3) and b = 'x'",
+		want:  "SELECT `a` FROM `t` WHERE `id` IN ::? AND `b` = :?",
+	}, {
+		query: "update t set a = 2 where id = 5",
+		want:  "UPDATE `t` SET `a` = :? WHERE `id` = :?",
+	}}
+	for _, tc := range testcases {
+		t.Run(tc.query, func(t *testing.T) {
+			got, err := queryFingerprint(parser, tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := queryFingerprint(parser, "not a query")
+	require.Error(t, err)
+}
+
+func TestQueryListKill(t *testing.T) {
+	ql := NewQueryList("test", sqlparser.NewTestParser())
+	callerCtx := func(user string) context.Context {
+		return callerid.NewContext(context.Background(), nil, callerid.NewImmediateCallerID(user))
+	}
+
+	conn1 := &testConn{id: 1}
+	qd1 := NewQueryDetail(callerCtx("user1"), conn1)
+	qd1.query, qd1.planType = "select * from t where id = 1", "Select"
+	ql.Add(qd1)
+
+	conn2 := &testConn{id: 2}
+	qd2 := NewQueryDetail(callerCtx("user2"), conn2)
+	qd2.query, qd2.planType = "select * from t where id = 2", "Select"
+	ql.Add(qd2)
+
+	conn3 := &testConn{id: 3}
+	qd3 := NewQueryDetail(callerCtx("user1"), conn3)
+	qd3.query, qd3.planType = "update t set a = 1 where id = 3", "UpdateLimit"
+	ql.Add(qd3)
+
+	fingerprint, err := queryFingerprint(ql.parser, "select * from t where id = 42")
+	require.NoError(t, err)
+
+	killed := ql.Kill(QueryFilter{Query: fingerprint, Caller: "user1"}, true)
+	require.Len(t, killed, 1)
+	require.EqualValues(t, 1, killed[0].ConnID)
+	require.Equal(t, fingerprint, killed[0].Fingerprint)
+	require.Equal(t, "Select", killed[0].PlanType)
+	require.Equal(t, "user1", killed[0].Caller)
+	require.False(t, conn1.killed)
+
+	killed = ql.Kill(QueryFilter{Query: fingerprint}, false)
+	require.Len(t, killed, 2)
+	require.True(t, conn1.killed)
+	require.True(t, conn2.killed)
+	require.False(t, conn3.killed)
+
+	killed = ql.Kill(QueryFilter{PlanType: "UpdateLimit"}, false)
+	require.Len(t, killed, 1)
+	require.EqualValues(t, 3, killed[0].ConnID)
+	require.True(t, conn3.killed)
+}
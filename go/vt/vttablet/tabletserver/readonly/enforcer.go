@@ -0,0 +1,308 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readonly continuously checks that read_only and super_read_only
+// match the type of the tablet, repairs them when they drift, and reports
+// the drift as a health error.
+package readonly
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/mysql/sqlerror"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	checkInterval time.Duration
+	repair        = true
+)
+
+var (
+	violations = stats.NewGaugesWithSingleLabel(
+		"ReadOnlyViolation",
+		"Whether read_only or super_read_only doesn't match the tablet type (1) or does (0)",
+		"Variable")
+	repairs = stats.NewCountersWithSingleLabel(
+		"ReadOnlyRepairs",
+		"Number of times read_only or super_read_only was repaired to match the tablet type",
+		"Variable")
+	checkErrors = stats.NewCounter(
+		"ReadOnlyCheckErrors",
+		"Number of times read_only and super_read_only could not be read")
+)
+
+func init() {
+	servenv.OnParseFor("vtcombo", registerFlags)
+	servenv.OnParseFor("vttablet", registerFlags)
+}
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&checkInterval, "read-only-check-interval", checkInterval, "Interval between checks that read_only and super_read_only match the tablet type: off on a serving primary, on otherwise. A mismatch is reported as a health error. 0 disables the checks.")
+	fs.BoolVar(&repair, "read-only-repair", repair, "Repair read_only and super_read_only when they don't match the tablet type. Requires --read-only-check-interval.")
+}
+
+// Enforcer periodically checks that read_only and super_read_only match the
+// type of the tablet. A serving primary must be writable, and any other
+// tablet must be super_read_only. A primary that doesn't serve, like one that
+// is being demoted or promoted, and a tablet that is being restored are left
+// alone: the reparent and restore operations own their read-only state.
+//
+// The variables that don't match are exported in the ReadOnlyViolation stat,
+// and returned as an error by Status, which the tablet reports as a health
+// error.
+type Enforcer struct {
+	interval time.Duration
+	repair   bool
+	mysqld   mysqlctl.MysqlDaemon
+
+	mu         sync.Mutex
+	isOpen     bool
+	ticks      *timer.Timer
+	tabletType topodatapb.TabletType
+	serving    bool
+	err        error
+}
+
+// NewEnforcer creates an Enforcer that uses the values of the flags.
+func NewEnforcer() *Enforcer {
+	return newEnforcer(checkInterval, repair)
+}
+
+func newEnforcer(interval time.Duration, repair bool) *Enforcer {
+	e := &Enforcer{
+		interval: interval,
+		repair:   repair,
+	}
+	if interval > 0 {
+		e.ticks = timer.NewTimer(interval)
+	}
+	return e
+}
+
+// InitDBConfig sets the MySQL daemon whose read-only state is enforced.
+func (e *Enforcer) InitDBConfig(mysqld mysqlctl.MysqlDaemon) {
+	e.mysqld = mysqld
+}
+
+// Open checks the read-only state and starts the periodic checks. It does
+// nothing if the checks are disabled.
+func (e *Enforcer) Open() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.isOpen || e.ticks == nil || e.mysqld == nil {
+		return
+	}
+	e.isOpen = true
+	e.checkLocked()
+	e.ticks.Start(e.check)
+	log.Infof("Read-only enforcer: opened with interval %v", e.interval)
+}
+
+// Close stops the periodic checks.
+func (e *Enforcer) Close() {
+	e.mu.Lock()
+	if !e.isOpen {
+		e.mu.Unlock()
+		return
+	}
+	e.isOpen = false
+	e.err = nil
+	e.mu.Unlock()
+	// The timer waits for a running check, which needs the lock.
+	e.ticks.Stop()
+	log.Info("Read-only enforcer: closed")
+}
+
+// SetTabletType sets the type of the tablet, and whether it serves, which
+// the read-only state must match from the next check on. A check that is
+// running when the type changes completes before SetTabletType returns.
+func (e *Enforcer) SetTabletType(tabletType topodatapb.TabletType, serving bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.tabletType == tabletType && e.serving == serving {
+		return
+	}
+	e.tabletType = tabletType
+	e.serving = serving
+	// The previous result doesn't apply to the new type.
+	e.err = nil
+}
+
+// Status returns the error of the latest check, which lists the variables
+// that don't match the tablet type.
+func (e *Enforcer) Status() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+func (e *Enforcer) check() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.isOpen {
+		return
+	}
+	e.checkLocked()
+}
+
+func (e *Enforcer) checkLocked() {
+	var wantWritable bool
+	switch {
+	case e.tabletType == topodatapb.TabletType_UNKNOWN:
+		// The tablet type isn't known yet.
+		return
+	case e.tabletType == topodatapb.TabletType_RESTORE:
+		// A restore may need to write, like to apply binary logs.
+		e.setViolationsLocked(nil)
+		return
+	case e.tabletType == topodatapb.TabletType_PRIMARY && !e.serving:
+		// The reparent operations own the read-only state.
+		e.setViolationsLocked(nil)
+		return
+	case e.tabletType == topodatapb.TabletType_PRIMARY:
+		wantWritable = true
+	}
+
+	mismatches, err := e.readMismatches(wantWritable)
+	if err != nil {
+		checkErrors.Add(1)
+		// The tablet reports MySQL being unreachable on its own.
+		log.Warningf("Read-only enforcer: %v", err)
+		return
+	}
+	if e.repair && len(mismatches) > 0 {
+		mismatches = e.correct(wantWritable, mismatches)
+	}
+	e.setViolationsLocked(mismatches)
+}
+
+func (e *Enforcer) setViolationsLocked(mismatches []mismatch) {
+	violations.Set("read_only", 0)
+	violations.Set("super_read_only", 0)
+	if len(mismatches) == 0 {
+		if e.err != nil {
+			log.Info("Read-only enforcer: read_only and super_read_only match the tablet type again")
+		}
+		e.err = nil
+		return
+	}
+	for _, m := range mismatches {
+		violations.Set(m.name, 1)
+	}
+	err := vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "read-only state doesn't match tablet type %s: %s", e.tabletTypeString(), joinMismatches(mismatches))
+	if e.err == nil || e.err.Error() != err.Error() {
+		log.Warningf("Read-only enforcer: %v", err)
+	}
+	e.err = err
+}
+
+func (e *Enforcer) tabletTypeString() string {
+	if e.tabletType == topodatapb.TabletType_PRIMARY {
+		return "serving primary"
+	}
+	return topoproto.TabletTypeLString(e.tabletType)
+}
+
+// mismatch is a variable that has another value than the one it needs.
+type mismatch struct {
+	name string
+	got  bool
+}
+
+func (e *Enforcer) readMismatches(wantWritable bool) ([]mismatch, error) {
+	readOnly, err := e.mysqld.IsReadOnly()
+	if err != nil {
+		return nil, err
+	}
+	superReadOnly, err := e.mysqld.IsSuperReadOnly()
+	if err != nil {
+		if !isUnknownSystemVariable(err) {
+			return nil, err
+		}
+		// Flavors without super_read_only only need read_only.
+		superReadOnly = readOnly
+	}
+
+	var mismatches []mismatch
+	if readOnly == wantWritable {
+		mismatches = append(mismatches, mismatch{name: "read_only", got: readOnly})
+	}
+	if superReadOnly == wantWritable {
+		mismatches = append(mismatches, mismatch{name: "super_read_only", got: superReadOnly})
+	}
+	return mismatches, nil
+}
+
+// correct sets the read-only state that the tablet type needs, and returns
+// the mismatches that are left.
+func (e *Enforcer) correct(wantWritable bool, mismatches []mismatch) []mismatch {
+	var err error
+	if wantWritable {
+		// Turning read_only off also turns super_read_only off.
+		if _, err = e.mysqld.SetSuperReadOnly(false); err == nil || isUnknownSystemVariable(err) {
+			err = e.mysqld.SetReadOnly(false)
+		}
+	} else {
+		// Turning super_read_only on also turns read_only on.
+		if _, err = e.mysqld.SetSuperReadOnly(true); isUnknownSystemVariable(err) {
+			err = e.mysqld.SetReadOnly(true)
+		}
+	}
+	if err != nil {
+		log.Warningf("Read-only enforcer: could not repair %s on a %s tablet: %v", joinMismatches(mismatches), e.tabletTypeString(), err)
+		return mismatches
+	}
+	log.Infof("Read-only enforcer: repaired %s on a %s tablet", joinMismatches(mismatches), e.tabletTypeString())
+	for _, m := range mismatches {
+		repairs.Add(m.name, 1)
+	}
+	return nil
+}
+
+func isUnknownSystemVariable(err error) bool {
+	sqlErr, ok := err.(*sqlerror.SQLError)
+	return ok && sqlErr.Number() == sqlerror.ERUnknownSystemVariable
+}
+
+func joinMismatches(mismatches []mismatch) string {
+	parts := make([]string, 0, len(mismatches))
+	for _, m := range mismatches {
+		parts = append(parts, fmt.Sprintf("%s is %s", m.name, onOff(m.got)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func onOff(on bool) string {
+	if on {
+		return "ON"
+	}
+	return "OFF"
+}
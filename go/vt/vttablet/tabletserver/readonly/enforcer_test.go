@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readonly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/vt/mysqlctl"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestEnforcer(t *testing.T) {
+	mysqld := mysqlctl.NewFakeMysqlDaemon(nil)
+	defer mysqld.Close()
+
+	e := newEnforcer(time.Hour, false)
+	e.InitDBConfig(mysqld)
+	e.SetTabletType(topodatapb.TabletType_REPLICA, true)
+	e.Open()
+	defer e.Close()
+
+	// A writable replica is reported.
+	assert.EqualError(t, e.Status(), "read-only state doesn't match tablet type replica: read_only is OFF, super_read_only is OFF")
+	assert.EqualValues(t, 1, violations.Counts()["super_read_only"])
+
+	_, _ = mysqld.SetSuperReadOnly(true)
+	e.check()
+	assert.NoError(t, e.Status())
+	assert.EqualValues(t, 0, violations.Counts()["super_read_only"])
+
+	// A read-only primary is reported only while it serves.
+	e.SetTabletType(topodatapb.TabletType_PRIMARY, false)
+	e.check()
+	assert.NoError(t, e.Status())
+
+	e.SetTabletType(topodatapb.TabletType_PRIMARY, true)
+	e.check()
+	assert.EqualError(t, e.Status(), "read-only state doesn't match tablet type serving primary: read_only is ON, super_read_only is ON")
+
+	// The error is cleared when the enforcer is closed.
+	e.Close()
+	assert.NoError(t, e.Status())
+}
+
+func TestEnforcerRepair(t *testing.T) {
+	mysqld := mysqlctl.NewFakeMysqlDaemon(nil)
+	defer mysqld.Close()
+
+	e := newEnforcer(time.Hour, true)
+	e.InitDBConfig(mysqld)
+	e.SetTabletType(topodatapb.TabletType_RDONLY, true)
+	e.Open()
+	defer e.Close()
+
+	assert.NoError(t, e.Status())
+	assert.True(t, mysqld.SuperReadOnly.Load())
+	assert.True(t, mysqld.ReadOnly)
+	assert.EqualValues(t, 1, repairs.Counts()["super_read_only"])
+
+	e.SetTabletType(topodatapb.TabletType_PRIMARY, true)
+	e.check()
+	assert.NoError(t, e.Status())
+	assert.False(t, mysqld.SuperReadOnly.Load())
+	assert.False(t, mysqld.ReadOnly)
+
+	// A tablet that is being restored is left alone.
+	e.SetTabletType(topodatapb.TabletType_RESTORE, false)
+	e.check()
+	assert.NoError(t, e.Status())
+	assert.False(t, mysqld.ReadOnly)
+}
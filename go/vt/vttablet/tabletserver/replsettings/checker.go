@@ -0,0 +1,275 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replsettings checks that the MySQL settings that VReplication and
+// vstream rely on, like binlog_format and binlog_row_image, have the values
+// they need, and reports the settings that drift.
+package replsettings
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/timer"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/servenv"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+var (
+	checkInterval time.Duration
+	autoCorrect   bool
+
+	checkTimeout = 10 * time.Second
+)
+
+var (
+	settingMismatch = stats.NewGaugesWithSingleLabel(
+		"ReplicationSettingMismatch",
+		"Whether a replication-critical MySQL setting doesn't have the value that VReplication needs (1) or has it (0)",
+		"Setting")
+	settingCorrections = stats.NewCountersWithSingleLabel(
+		"ReplicationSettingCorrections",
+		"Number of times a replication-critical MySQL setting was corrected",
+		"Setting")
+	checkErrors = stats.NewCounter(
+		"ReplicationSettingCheckErrors",
+		"Number of times the replication-critical MySQL settings could not be read")
+)
+
+func init() {
+	servenv.OnParseFor("vtcombo", registerFlags)
+	servenv.OnParseFor("vttablet", registerFlags)
+}
+
+func registerFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&checkInterval, "repl-settings-check-interval", checkInterval, "Interval between checks of the MySQL settings that VReplication needs, like binlog_format=ROW and binlog_row_image=FULL or NOBLOB. A setting that drifts is exported in the ReplicationSettingMismatch stat and shown on the status page. 0 disables the checks.")
+	fs.BoolVar(&autoCorrect, "repl-settings-auto-correct", autoCorrect, "Correct the replication-critical MySQL settings that can be changed at runtime, like binlog_format and binlog_row_image, when they drift. Only the sessions that are opened after the correction use the new value.")
+}
+
+// setting is a MySQL variable that must have one of the given values.
+type setting struct {
+	name string
+	// want are the values that VReplication accepts. A drifted setting is
+	// corrected to the first one.
+	want []string
+	// settable is true for the variables that have a session scope, and
+	// that can be changed globally without a restart.
+	settable bool
+}
+
+// settings are the variables that are checked, in the order of the columns
+// of checkQuery.
+var settings = []setting{
+	{name: "log_bin", want: []string{"1"}},
+	{name: "log_slave_updates", want: []string{"1"}},
+	{name: "gtid_mode", want: []string{"ON"}},
+	{name: "enforce_gtid_consistency", want: []string{"ON"}},
+	{name: "binlog_format", want: []string{"ROW"}, settable: true},
+	// VReplication handles the blob and text columns that NOBLOB leaves out
+	// of the row images when they are not changed.
+	{name: "binlog_row_image", want: []string{"FULL", "NOBLOB"}, settable: true},
+}
+
+var checkQuery = func() string {
+	columns := make([]string, 0, len(settings))
+	for _, s := range settings {
+		columns = append(columns, "@@global."+s.name)
+	}
+	return "select " + strings.Join(columns, ", ")
+}()
+
+// Checker periodically checks the replication-critical MySQL settings.
+// The settings that drift are exported in the ReplicationSettingMismatch
+// stat and returned as an error by Status, which the tablet shows on its
+// status page.
+type Checker struct {
+	interval    time.Duration
+	autoCorrect bool
+	mysqld      mysqlctl.MysqlDaemon
+
+	mu     sync.Mutex
+	isOpen bool
+	ticks  *timer.Timer
+	err    error
+}
+
+// NewChecker creates a Checker that uses the values of the flags.
+func NewChecker() *Checker {
+	return newChecker(checkInterval, autoCorrect)
+}
+
+func newChecker(interval time.Duration, autoCorrect bool) *Checker {
+	c := &Checker{
+		interval:    interval,
+		autoCorrect: autoCorrect,
+	}
+	if interval > 0 {
+		c.ticks = timer.NewTimer(interval)
+	}
+	return c
+}
+
+// InitDBConfig sets the MySQL daemon whose settings are checked.
+func (c *Checker) InitDBConfig(mysqld mysqlctl.MysqlDaemon) {
+	c.mysqld = mysqld
+}
+
+// Open checks the settings and starts the periodic checks. It does nothing
+// if the checks are disabled.
+func (c *Checker) Open() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.isOpen || c.ticks == nil || c.mysqld == nil {
+		return
+	}
+	c.isOpen = true
+	c.checkLocked()
+	c.ticks.Start(c.check)
+	log.Infof("Replication settings checker: opened with interval %v", c.interval)
+}
+
+// Close stops the periodic checks.
+func (c *Checker) Close() {
+	c.mu.Lock()
+	if !c.isOpen {
+		c.mu.Unlock()
+		return
+	}
+	c.isOpen = false
+	c.err = nil
+	c.mu.Unlock()
+
+	// The timer waits for a running check, which needs the lock.
+	c.ticks.Stop()
+	log.Info("Replication settings checker: closed")
+}
+
+// Status returns the error of the latest check, which lists the settings
+// that don't have the value that VReplication needs.
+func (c *Checker) Status() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *Checker) check() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.isOpen {
+		return
+	}
+	c.checkLocked()
+}
+
+func (c *Checker) checkLocked() {
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+
+	mismatches, err := c.readMismatches(ctx)
+	if err != nil {
+		checkErrors.Add(1)
+		// The tablet reports MySQL being unreachable on its own.
+		log.Warningf("Replication settings checker: %v", err)
+		return
+	}
+	if c.autoCorrect && len(mismatches) > 0 {
+		mismatches = c.correct(ctx, mismatches)
+	}
+
+	for _, s := range settings {
+		settingMismatch.Set(s.name, 0)
+	}
+	if len(mismatches) == 0 {
+		if c.err != nil {
+			log.Info("Replication settings checker: the settings are back to their required values")
+		}
+		c.err = nil
+		return
+	}
+	for _, m := range mismatches {
+		settingMismatch.Set(m.name, 1)
+	}
+	err = vterrors.Errorf(vtrpcpb.Code_FAILED_PRECONDITION, "replication settings needed by VReplication have drifted: %s", joinMismatches(mismatches))
+	if c.err == nil || c.err.Error() != err.Error() {
+		log.Warningf("Replication settings checker: %v", err)
+	}
+	c.err = err
+}
+
+// mismatch is a setting that has another value than the one it needs.
+type mismatch struct {
+	setting
+	got string
+}
+
+func (c *Checker) readMismatches(ctx context.Context) ([]mismatch, error) {
+	qr, err := c.mysqld.FetchSuperQuery(ctx, checkQuery)
+	if err != nil {
+		return nil, err
+	}
+	if len(qr.Rows) != 1 || len(qr.Rows[0]) != len(settings) {
+		return nil, fmt.Errorf("unexpected result for %s: %v", checkQuery, qr.Rows)
+	}
+
+	var mismatches []mismatch
+	for i, s := range settings {
+		got := qr.Rows[0][i].ToString()
+		if !slices.ContainsFunc(s.want, func(want string) bool { return strings.EqualFold(got, want) }) {
+			mismatches = append(mismatches, mismatch{setting: s, got: got})
+		}
+	}
+	return mismatches, nil
+}
+
+// correct sets the settable mismatches to their required value, and returns
+// the mismatches that are left.
+func (c *Checker) correct(ctx context.Context, mismatches []mismatch) []mismatch {
+	var left []mismatch
+	for _, m := range mismatches {
+		if !m.settable {
+			left = append(left, m)
+			continue
+		}
+		query := fmt.Sprintf("SET GLOBAL %s = '%s'", m.name, m.want[0])
+		if err := c.mysqld.ExecuteSuperQueryList(ctx, []string{query}); err != nil {
+			log.Warningf("Replication settings checker: could not correct %s from %s to %s: %v", m.name, m.got, m.want[0], err)
+			left = append(left, m)
+			continue
+		}
+		log.Infof("Replication settings checker: corrected %s from %s to %s", m.name, m.got, m.want[0])
+		settingCorrections.Add(m.name, 1)
+	}
+	return left
+}
+
+func joinMismatches(mismatches []mismatch) string {
+	parts := make([]string, 0, len(mismatches))
+	for _, m := range mismatches {
+		parts = append(parts, fmt.Sprintf("%s is %s instead of %s", m.name, m.got, strings.Join(m.want, " or ")))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replsettings
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/mysqlctl"
+)
+
+func settingsResult(values ...string) *sqltypes.Result {
+	fields := make([]string, 0, len(settings))
+	types := make([]string, 0, len(settings))
+	for _, s := range settings {
+		fields = append(fields, "@@global."+s.name)
+		types = append(types, "varchar")
+	}
+	return sqltypes.MakeTestResult(sqltypes.MakeTestFields(strings.Join(fields, "|"), strings.Join(types, "|")), strings.Join(values, "|"))
+}
+
+func TestChecker(t *testing.T) {
+	mysqld := mysqlctl.NewFakeMysqlDaemon(nil)
+	defer mysqld.Close()
+	mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		checkQuery: settingsResult("1", "1", "ON", "ON", "ROW", "FULL"),
+	}
+
+	c := newChecker(time.Hour, false)
+	c.InitDBConfig(mysqld)
+	c.Open()
+	defer c.Close()
+	assert.NoError(t, c.Status())
+	assert.EqualValues(t, 0, settingMismatch.Counts()["binlog_row_image"])
+
+	mysqld.FetchSuperQueryMap[checkQuery] = settingsResult("1", "0", "ON", "ON", "ROW", "MINIMAL")
+	c.check()
+	assert.EqualError(t, c.Status(), "replication settings needed by VReplication have drifted: log_slave_updates is 0 instead of 1, binlog_row_image is MINIMAL instead of FULL or NOBLOB")
+	assert.EqualValues(t, 1, settingMismatch.Counts()["binlog_row_image"])
+	assert.EqualValues(t, 1, settingMismatch.Counts()["log_slave_updates"])
+
+	// The previous result is kept while the settings can't be read.
+	delete(mysqld.FetchSuperQueryMap, checkQuery)
+	c.check()
+	assert.Error(t, c.Status())
+
+	mysqld.FetchSuperQueryMap[checkQuery] = settingsResult("1", "1", "ON", "ON", "row", "full")
+	c.check()
+	assert.NoError(t, c.Status())
+	assert.EqualValues(t, 0, settingMismatch.Counts()["binlog_row_image"])
+
+	mysqld.FetchSuperQueryMap[checkQuery] = settingsResult("1", "1", "ON", "ON", "ROW", "NOBLOB")
+	c.check()
+	assert.NoError(t, c.Status())
+	assert.EqualValues(t, 0, settingMismatch.Counts()["binlog_row_image"])
+
+	// The error is cleared when the checker is closed.
+	mysqld.FetchSuperQueryMap[checkQuery] = settingsResult("0", "1", "ON", "ON", "ROW", "FULL")
+	c.check()
+	assert.Error(t, c.Status())
+	c.Close()
+	assert.NoError(t, c.Status())
+}
+
+func TestCheckerAutoCorrect(t *testing.T) {
+	mysqld := mysqlctl.NewFakeMysqlDaemon(nil)
+	defer mysqld.Close()
+	mysqld.FetchSuperQueryMap = map[string]*sqltypes.Result{
+		checkQuery: settingsResult("1", "1", "OFF", "ON", "MIXED", "MINIMAL"),
+	}
+	mysqld.ExpectedExecuteSuperQueryList = []string{
+		"SET GLOBAL binlog_format = 'ROW'",
+		"SET GLOBAL binlog_row_image = 'FULL'",
+	}
+
+	c := newChecker(time.Hour, true)
+	c.InitDBConfig(mysqld)
+	c.Open()
+	defer c.Close()
+	require.NoError(t, mysqld.CheckSuperQueryList())
+
+	// The settings that need a restart are left as they are.
+	assert.EqualError(t, c.Status(), "replication settings needed by VReplication have drifted: gtid_mode is OFF instead of ON")
+	assert.EqualValues(t, 1, settingCorrections.Counts()["binlog_row_image"])
+	assert.EqualValues(t, 0, settingMismatch.Counts()["binlog_format"])
+}
+
+func TestCheckerDisabled(t *testing.T) {
+	mysqld := mysqlctl.NewFakeMysqlDaemon(nil)
+	defer mysqld.Close()
+
+	// No query is expected while the checks are disabled.
+	c := newChecker(0, true)
+	c.InitDBConfig(mysqld)
+	c.Open()
+	assert.NoError(t, c.Status())
+	c.Close()
+}
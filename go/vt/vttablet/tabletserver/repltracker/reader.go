@@ -121,6 +121,7 @@ func (r *heartbeatReader) Close() {
 	r.pool.Close()
 
 	currentLagNs.Set(0)
+	currentLagMs.Set(0)
 
 	r.isOpen = false
 	log.Info("Heartbeat Reader: closed")
@@ -158,6 +159,7 @@ func (r *heartbeatReader) readHeartbeat() {
 	lag := r.now().Sub(time.Unix(0, ts))
 	cumulativeLagNs.Add(lag.Nanoseconds())
 	currentLagNs.Set(lag.Nanoseconds())
+	currentLagMs.Set(lag.Milliseconds())
 	heartbeatLagNsHistogram.Add(lag.Nanoseconds())
 	reads.Add(1)
 
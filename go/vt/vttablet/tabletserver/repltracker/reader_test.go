@@ -67,6 +67,7 @@ func TestReaderReadHeartbeat(t *testing.T) {
 	assert.Equal(t, expectedLag, lag, "wrong latest lag")
 	expectedCumLag := 10 * time.Second.Nanoseconds()
 	assert.Equal(t, expectedCumLag, cumulativeLagNs.Get(), "wrong cumulative lag")
+	assert.Equal(t, int64(10000), currentLagMs.Get(), "wrong current lag in milliseconds")
 	assert.Equal(t, int64(1), reads.Get(), "wrong read count")
 	assert.Equal(t, int64(0), readErrors.Get(), "wrong read error count")
 	expectedHisto := map[string]int64{
@@ -83,6 +84,34 @@ func TestReaderReadHeartbeat(t *testing.T) {
 	utils.MustMatch(t, expectedHisto, heartbeatLagNsHistogram.Counts(), "wrong counts in histogram")
 }
 
+// TestReaderReadHeartbeatSubSecond tests that a sub-second lag is reported with millisecond resolution.
+func TestReaderReadHeartbeatSubSecond(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+
+	now := time.Now()
+	tr := newReader(db, &now)
+	defer tr.Close()
+
+	tr.pool.Open(tr.env.Config().DB.AppWithDB(), tr.env.Config().DB.DbaWithDB(), tr.env.Config().DB.AppDebugWithDB())
+
+	db.AddQuery(fmt.Sprintf("SELECT ts FROM %s.heartbeat WHERE keyspaceShard='%s'", "_vt", tr.keyspaceShard), &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Name: "ts", Type: sqltypes.Int64},
+		},
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewInt64(now.Add(-250 * time.Millisecond).UnixNano()),
+		}},
+	})
+
+	tr.readHeartbeat()
+	lag, err := tr.Status()
+
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, lag, "wrong latest lag")
+	assert.Equal(t, int64(250), currentLagMs.Get(), "wrong current lag in milliseconds")
+}
+
 // TestReaderCloseSetsCurrentLagToZero tests that when closing the heartbeat reader, the current lag is
 // set to zero.
 func TestReaderCloseSetsCurrentLagToZero(t *testing.T) {
@@ -100,15 +129,18 @@ func TestReaderCloseSetsCurrentLagToZero(t *testing.T) {
 	})
 
 	currentLagNs.Reset()
+	currentLagMs.Reset()
 
 	tr.Open()
 	time.Sleep(2 * time.Second)
 
 	assert.Greater(t, currentLagNs.Get(), int64(0), "lag should be greater than zero")
+	assert.Greater(t, currentLagMs.Get(), int64(0), "lag should be greater than zero")
 
 	tr.Close()
 
 	assert.Equal(t, int64(0), currentLagNs.Get(), "lag should be be zero after closing the reader.")
+	assert.Equal(t, int64(0), currentLagMs.Get(), "lag should be be zero after closing the reader.")
 }
 
 // TestReaderReadHeartbeatError tests that we properly account for errors
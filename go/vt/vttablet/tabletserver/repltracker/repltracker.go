@@ -44,6 +44,9 @@ var (
 	cumulativeLagNs = stats.NewCounter("HeartbeatCumulativeLagNs", "Incremented by the current lag at each heartbeat read interval")
 	// HeartbeatCurrentLagNs is a point-in-time calculation of the lag, updated at each heartbeat read interval.
 	currentLagNs = stats.NewGauge("HeartbeatCurrentLagNs", "Point in time calculation of the heartbeat lag")
+	// HeartbeatCurrentLagMs is HeartbeatCurrentLagNs in milliseconds, for sub-second lag resolution in
+	// monitoring systems that do not cope well with nanosecond values.
+	currentLagMs = stats.NewGauge("HeartbeatCurrentLagMs", "Point in time calculation of the heartbeat lag in milliseconds")
 	// HeartbeatLagNsHistogram is a histogram of the lag values. Cutoffs are 0, 1ms, 10ms, 100ms, 1s, 10s, 100s, 1000s
 	heartbeatLagNsHistogram = stats.NewGenericHistogram("HeartbeatLagNsHistogram",
 		"Histogram of lag values in nanoseconds", []int64{0, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11, 1e12},
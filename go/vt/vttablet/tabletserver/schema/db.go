@@ -275,7 +275,7 @@ func getViewDefinition(ctx context.Context, conn *connpool.Conn, bv map[string]*
 	if err != nil {
 		return err
 	}
-	return conn.Stream(ctx, viewsDefQuery, callback, alloc, bufferSize, 0)
+	return conn.Stream(ctx, viewsDefQuery, callback, alloc, bufferSize, 0, 0)
 }
 
 // getCreateStatement gets the create-statement for the given view/table.
@@ -305,7 +305,7 @@ func getChangedViewNames(ctx context.Context, conn *connpool.Conn, isServingPrim
 	bufferSize := 1000
 
 	viewChangeQuery := sqlparser.BuildParsedQuery(detectViewChange, sidecar.GetIdentifier()).Query
-	err := conn.Stream(ctx, viewChangeQuery, callback, alloc, bufferSize, 0)
+	err := conn.Stream(ctx, viewChangeQuery, callback, alloc, bufferSize, 0, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -338,7 +338,7 @@ func (se *Engine) getMismatchedTableNames(ctx context.Context, conn *connpool.Co
 	alloc := func() *sqltypes.Result { return &sqltypes.Result{} }
 	bufferSize := 1000
 	readTableCreateTimesQuery := sqlparser.BuildParsedQuery(readTableCreateTimes, sidecar.GetIdentifier()).Query
-	err := conn.Stream(ctx, readTableCreateTimesQuery, callback, alloc, bufferSize, 0)
+	err := conn.Stream(ctx, readTableCreateTimesQuery, callback, alloc, bufferSize, 0, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -97,6 +97,9 @@ type Engine struct {
 	tableAllocatedSizeGauge *stats.GaugesWithSingleLabel
 	innoDbReadRowsCounter   *stats.Counter
 	SchemaReloadTimings     *servenv.TimingsWrapper
+
+	// tableBufferPoolGauge is only set if the table metrics are enabled.
+	tableBufferPoolGauge *stats.GaugesWithSingleLabel
 }
 
 // NewEngine creates a new Engine.
@@ -118,6 +121,9 @@ func NewEngine(env tabletenv.Env) *Engine {
 	se.tableAllocatedSizeGauge = env.Exporter().NewGaugesWithSingleLabel("TableAllocatedSize", "tracks table allocated size", "Table")
 	se.innoDbReadRowsCounter = env.Exporter().NewCounter("InnodbRowsRead", "number of rows read by mysql")
 	se.SchemaReloadTimings = env.Exporter().NewTimings("SchemaReload", "time taken to reload the schema", "type")
+	if env.Config().TableMetrics.Enable {
+		se.tableBufferPoolGauge = env.Exporter().NewGaugesWithSingleLabel("TableBufferPoolSize", "tracks the size of the pages of a table in the InnoDB buffer pool", "Table")
+	}
 	se.reloadTimeout = env.Config().SchemaChangeReloadTimeout
 	env.Exporter().HandleFunc("/debug/schema", se.handleDebugSchema)
 	env.Exporter().HandleFunc("/schemaz", func(w http.ResponseWriter, r *http.Request) {
@@ -447,6 +453,10 @@ func (se *Engine) reload(ctx context.Context, includeStats bool) error {
 		return err
 	}
 
+	if includeStats && se.tableBufferPoolGauge != nil {
+		se.updateTableBufferPoolSizes(ctx, conn.Conn)
+	}
+
 	rec := concurrency.AllErrorRecorder{}
 	// curTables keeps track of tables in the new snapshot so we can detect what was dropped.
 	curTables := map[string]bool{"dual": true}
@@ -565,6 +575,9 @@ func (se *Engine) getDroppedTables(curTables map[string]bool, changedViews map[s
 			// Many monitoring tools will drop zero-valued metrics.
 			se.tableFileSizeGauge.Reset(tableName)
 			se.tableAllocatedSizeGauge.Reset(tableName)
+			if se.tableBufferPoolGauge != nil {
+				se.tableBufferPoolGauge.Reset(tableName)
+			}
 		}
 	}
 
@@ -620,6 +633,35 @@ func (se *Engine) updateInnoDBRowsRead(ctx context.Context, conn *connpool.Conn)
 	return nil
 }
 
+// updateTableBufferPoolSizes publishes the size of each table in the InnoDB
+// buffer pool. The sizes are only available from MySQL 8.0, so a failure is
+// logged rather than failing the reload.
+func (se *Engine) updateTableBufferPoolSizes(ctx context.Context, conn *connpool.Conn) {
+	qr, err := conn.Exec(ctx, mysql.TableBufferPoolSizes, 10000, false)
+	if err != nil {
+		log.Warningf("could not read the buffer pool sizes of the tables: %v", err)
+		return
+	}
+	sizes := make(map[string]int64, len(qr.Rows))
+	for _, row := range qr.Rows {
+		size, err := row[1].ToCastInt64()
+		if err != nil {
+			log.Warningf("got strange buffer pool size for table %s: %v", row[0].ToString(), row[1])
+			continue
+		}
+		sizes[row[0].ToString()] = size
+	}
+	// The tables that have no pages left in the buffer pool are not returned.
+	for tableName := range se.tableBufferPoolGauge.Counts() {
+		if _, ok := sizes[tableName]; !ok {
+			se.tableBufferPoolGauge.Reset(tableName)
+		}
+	}
+	for tableName, size := range sizes {
+		se.tableBufferPoolGauge.Set(tableName, size)
+	}
+}
+
 func (se *Engine) mysqlTime(ctx context.Context, conn *connpool.Conn) (int64, error) {
 	// Keep `SELECT UNIX_TIMESTAMP` is in uppercase because binlog server queries are case sensitive and expect it to be so.
 	tm, err := conn.Exec(ctx, "SELECT UNIX_TIMESTAMP()", 1, false)
@@ -958,6 +958,32 @@ func TestEngineUpdateInnoDBRowsRead(t *testing.T) {
 	}
 }
 
+func TestEngineUpdateTableBufferPoolSizes(t *testing.T) {
+	db := fakesqldb.New(t)
+	defer db.Close()
+	env := tabletenv.NewEnv(vtenv.NewTestEnv(), nil, "TestEngineUpdateTableBufferPoolSizes")
+	conn, err := connpool.NewConn(context.Background(), dbconfigs.New(db.ConnParams()), nil, nil, env)
+	require.NoError(t, err)
+	defer conn.Close()
+	se := &Engine{}
+	se.tableBufferPoolGauge = stats.NewGaugesWithSingleLabel("TestEngineUpdateTableBufferPoolSizes", "", "Table")
+
+	fields := sqltypes.MakeTestFields("table_name|size", "varchar|int64")
+	db.AddQuery(mysql.TableBufferPoolSizes, sqltypes.MakeTestResult(fields, "t1|16384", "t2|32768"))
+	se.updateTableBufferPoolSizes(context.Background(), conn)
+	assert.Equal(t, map[string]int64{"t1": 16384, "t2": 32768}, se.tableBufferPoolGauge.Counts())
+
+	// The pages of t1 were evicted from the buffer pool.
+	db.AddQuery(mysql.TableBufferPoolSizes, sqltypes.MakeTestResult(fields, "t2|49152"))
+	se.updateTableBufferPoolSizes(context.Background(), conn)
+	assert.Equal(t, map[string]int64{"t1": 0, "t2": 49152}, se.tableBufferPoolGauge.Counts())
+
+	// A failure, e.g. on MySQL 5.7, keeps the sizes.
+	db.AddRejectedQuery(mysql.TableBufferPoolSizes, errors.New("Unknown table 'INNODB_CACHED_INDEXES' in information_schema"))
+	se.updateTableBufferPoolSizes(context.Background(), conn)
+	assert.Equal(t, map[string]int64{"t1": 0, "t2": 49152}, se.tableBufferPoolGauge.Counts())
+}
+
 // TestEngineGetTableData tests the functionality of getTableData function
 func TestEngineGetTableData(t *testing.T) {
 	db := fakesqldb.New(t)
@@ -107,6 +107,13 @@ func loadMessageInfo(ta *Table, comment string, collationEnv *collations.Environ
 
 	ta.MessageInfo.MaxBackoff, _ = getDuration(keyvals, "vt_max_backoff")
 
+	// poison messages are moved to a dead-letter table if vt_max_attempts is set
+	ta.MessageInfo.MaxAttempts, _ = getNum(keyvals, "vt_max_attempts")
+	ta.MessageInfo.DeadLetterTable = keyvals["vt_dead_letter_table"]
+	if ta.MessageInfo.MaxAttempts > 0 && ta.MessageInfo.DeadLetterTable == "" {
+		return fmt.Errorf("vt_dead_letter_table must be specified with vt_max_attempts: %s", ta.Name.String())
+	}
+
 	// these columns are required for message manager to function properly, but only
 	// id is required to be streamed to subscribers
 	requiredCols := []string{
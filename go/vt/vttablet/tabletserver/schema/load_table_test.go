@@ -160,6 +160,19 @@ func TestLoadTableMessage(t *testing.T) {
 	want.MessageInfo.MaxBackoff = 100 * time.Second
 	assert.Equal(t, want, table)
 
+	// Test loading max attempts and dead-letter table
+	table, err = newTestLoadTable("USER_TABLE", "vitess_message,vt_ack_wait=30,vt_purge_after=120,vt_batch_size=1,vt_cache_size=10,vt_poller_interval=30,vt_min_backoff=10,vt_max_backoff=100,vt_max_attempts=5,vt_dead_letter_table=test_table_dlq", db)
+	require.NoError(t, err)
+	want.MessageInfo.MaxAttempts = 5
+	want.MessageInfo.DeadLetterTable = "test_table_dlq"
+	assert.Equal(t, want, table)
+	want.MessageInfo.MaxAttempts = 0
+	want.MessageInfo.DeadLetterTable = ""
+
+	// Test max attempts without dead-letter table
+	_, err = newTestLoadTable("USER_TABLE", "vitess_message,vt_ack_wait=30,vt_purge_after=120,vt_batch_size=1,vt_cache_size=10,vt_poller_interval=30,vt_max_attempts=5", db)
+	require.Equal(t, errors.New("vt_dead_letter_table must be specified with vt_max_attempts: test_table"), err)
+
 	//
 	// multiple tests for vt_message_cols
 	//
@@ -125,6 +125,16 @@ type MessageInfo struct {
 	// MaxBackoff specifies the longest duration message manager
 	// should wait before rescheduling a message
 	MaxBackoff time.Duration
+
+	// MaxAttempts specifies how many times a message is sent
+	// without being acked before it's moved to the dead-letter
+	// table. Zero means that messages are retried forever.
+	MaxAttempts int
+
+	// DeadLetterTable is the table that receives the messages
+	// that exceeded MaxAttempts. It must have the columns of the
+	// message table, plus time_dead_lettered and failure_reason.
+	DeadLetterTable string
 }
 
 // NewTable creates a new Table.
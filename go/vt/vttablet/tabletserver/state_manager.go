@@ -123,6 +123,8 @@ type stateManager struct {
 	ddle        onlineDDLExecutor
 	throttler   lagThrottler
 	tableGC     tableGarbageCollector
+	rsc         replSettingsChecker
+	roe         readOnlyEnforcer
 
 	// hcticks starts on initialization and runs forever.
 	hcticks *timer.Timer
@@ -190,6 +192,19 @@ type (
 		Open() error
 		Close()
 	}
+
+	replSettingsChecker interface {
+		Open()
+		Close()
+		Status() error
+	}
+
+	readOnlyEnforcer interface {
+		Open()
+		Close()
+		SetTabletType(tabletType topodatapb.TabletType, serving bool)
+		Status() error
+	}
 )
 
 // Init performs the second phase of initialization.
@@ -535,6 +550,8 @@ func (sm *stateManager) connect(tabletType topodatapb.TabletType) error {
 		return err
 	}
 	sm.vstreamer.Open()
+	sm.rsc.Open()
+	sm.roe.Open()
 	if err := sm.qe.Open(); err != nil {
 		return err
 	}
@@ -617,6 +634,8 @@ func (sm *stateManager) closeAll() {
 	sm.txThrottler.Close()
 	sm.qe.Close()
 	sm.watcher.Close()
+	sm.roe.Close()
+	sm.rsc.Close()
 	sm.vstreamer.Close()
 	sm.rt.Close()
 	sm.se.Close()
@@ -655,6 +674,7 @@ func (sm *stateManager) setState(tabletType topodatapb.TabletType, state serving
 		sm.target.Cell, sm.target.Keyspace, sm.target.Shard)
 	sm.handleTransitionGracePeriod(tabletType)
 	sm.target.TabletType = tabletType
+	sm.roe.SetTabletType(tabletType, state == StateServing)
 	if sm.state == StateNotConnected {
 		// If we're transitioning out of StateNotConnected, we have
 		// to also ensure replication status is healthy.
@@ -704,6 +724,11 @@ func (sm *stateManager) Broadcast() {
 	defer sm.mu.Unlock()
 
 	lag, err := sm.refreshReplHealthLocked()
+	if err == nil {
+		// A read-only state that doesn't match the tablet type doesn't
+		// change the serving state, but it is reported as a health error.
+		err = sm.roe.Status()
+	}
 	sm.hs.ChangeState(sm.target.TabletType, sm.ptsTimestamp, lag, err, sm.isServingLocked())
 }
 
@@ -818,6 +843,13 @@ func (sm *stateManager) AppendDetails(details []*kv) []*kv {
 			Value: sm.alsoAllow[0].String(),
 		})
 	}
+	if err := sm.rsc.Status(); err != nil {
+		details = append(details, &kv{
+			Key:   "Replication Settings",
+			Class: unhappyClass,
+			Value: err.Error(),
+		})
+	}
 	return details
 }
 
@@ -701,6 +701,79 @@ func TestRefreshReplHealthLocked(t *testing.T) {
 	assert.False(t, sm.replHealthy)
 }
 
+func TestStateManagerReplSettings(t *testing.T) {
+	sm := newTestStateManager(t)
+	defer sm.StopService()
+	err := sm.SetServingType(topodatapb.TabletType_PRIMARY, testNow, StateServing, "")
+	require.NoError(t, err)
+	rsc := sm.rsc.(*testReplSettingsChecker)
+
+	healthError := func() string {
+		sm.hs.mu.Lock()
+		defer sm.hs.mu.Unlock()
+		return sm.hs.state.RealtimeStats.HealthError
+	}
+	settingsDetail := func() *kv {
+		for _, detail := range sm.AppendDetails(nil) {
+			if detail.Key == "Replication Settings" {
+				return detail
+			}
+		}
+		return nil
+	}
+
+	// A drift is shown on the status page, but it is not a health error.
+	rsc.err = errors.New("binlog_row_image is MINIMAL instead of FULL")
+	sm.Broadcast()
+	assert.Empty(t, healthError())
+	assert.True(t, sm.IsServing())
+	assert.Equal(t, &kv{
+		Key:   "Replication Settings",
+		Class: unhappyClass,
+		Value: "binlog_row_image is MINIMAL instead of FULL",
+	}, settingsDetail())
+
+	rsc.err = nil
+	assert.Nil(t, settingsDetail())
+}
+
+func TestStateManagerReadOnlyEnforcer(t *testing.T) {
+	sm := newTestStateManager(t)
+	defer sm.StopService()
+	roe := sm.roe.(*testReadOnlyEnforcer)
+
+	// The enforcer follows the tablet type and the serving state.
+	err := sm.SetServingType(topodatapb.TabletType_PRIMARY, testNow, StateServing, "")
+	require.NoError(t, err)
+	assert.Equal(t, topodatapb.TabletType_PRIMARY, roe.tabletType)
+	assert.True(t, roe.serving)
+
+	err = sm.SetServingType(topodatapb.TabletType_PRIMARY, testNow, StateNotServing, "")
+	require.NoError(t, err)
+	assert.Equal(t, topodatapb.TabletType_PRIMARY, roe.tabletType)
+	assert.False(t, roe.serving)
+
+	err = sm.SetServingType(topodatapb.TabletType_REPLICA, testNow, StateServing, "")
+	require.NoError(t, err)
+	assert.Equal(t, topodatapb.TabletType_REPLICA, roe.tabletType)
+	assert.True(t, roe.serving)
+
+	// Its violations are reported as a health error, without changing the serving state.
+	healthError := func() string {
+		sm.hs.mu.Lock()
+		defer sm.hs.mu.Unlock()
+		return sm.hs.state.RealtimeStats.HealthError
+	}
+	roe.err = errors.New("super_read_only is OFF")
+	sm.Broadcast()
+	assert.Equal(t, "super_read_only is OFF", healthError())
+	assert.True(t, sm.IsServing())
+
+	roe.err = nil
+	sm.Broadcast()
+	assert.Empty(t, healthError())
+}
+
 // TestPanicInWait tests that we don't panic when we wait for requests if more StartRequest calls come up after we start waiting.
 func TestPanicInWait(t *testing.T) {
 	sm := newTestStateManager(t)
@@ -752,6 +825,8 @@ func newTestStateManager(t *testing.T) *stateManager {
 		ddle:        &testOnlineDDLExecutor{},
 		throttler:   &testLagThrottler{},
 		tableGC:     &testTableGC{},
+		rsc:         &testReplSettingsChecker{},
+		roe:         &testReadOnlyEnforcer{},
 		rw:          newRequestsWaiter(),
 	}
 	sm.Init(env, &querypb.Target{})
@@ -859,6 +934,40 @@ func (te *testReplTracker) Status() (time.Duration, error) {
 	return te.lag, te.err
 }
 
+// testReplSettingsChecker doesn't record its order, which the tests of the
+// transitions don't check.
+type testReplSettingsChecker struct {
+	err error
+}
+
+func (te *testReplSettingsChecker) Open() {}
+
+func (te *testReplSettingsChecker) Close() {}
+
+func (te *testReplSettingsChecker) Status() error {
+	return te.err
+}
+
+// testReadOnlyEnforcer doesn't record its order either.
+type testReadOnlyEnforcer struct {
+	tabletType topodatapb.TabletType
+	serving    bool
+	err        error
+}
+
+func (te *testReadOnlyEnforcer) Open() {}
+
+func (te *testReadOnlyEnforcer) Close() {}
+
+func (te *testReadOnlyEnforcer) SetTabletType(tabletType topodatapb.TabletType, serving bool) {
+	te.tabletType = tabletType
+	te.serving = serving
+}
+
+func (te *testReadOnlyEnforcer) Status() error {
+	return te.err
+}
+
 type testQueryEngine struct {
 	testOrderState
 
@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/connpool"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+const (
+	// tableStatsOtherLabel is the Table label of the queries of the tables
+	// beyond the maximum number of tables of the table metrics.
+	tableStatsOtherLabel = "(other)"
+
+	explainTimeout = 5 * time.Second
+)
+
+// tableStats tracks the load that the queries put on each table, so that
+// capacity planning can see which tables drive the load of the tablet.
+type tableStats struct {
+	maxTables         int
+	explainSampleRate float64
+
+	mu     sync.Mutex
+	tables map[string]bool
+
+	// explaining is set while a sampled query is being explained, so that
+	// at most one explain runs at a time.
+	explaining atomic.Bool
+
+	rowsReturned, rowsAffected, fullScans *stats.CountersWithMultiLabels
+}
+
+// newTableStats returns the table stats of the config, or nil if they are
+// disabled.
+func newTableStats(env tabletenv.Env) *tableStats {
+	config := env.Config().TableMetrics
+	if !config.Enable {
+		return nil
+	}
+	labels := []string{"Table", "TabletType"}
+	return &tableStats{
+		maxTables:         config.MaxTables,
+		explainSampleRate: config.ExplainSampleRate,
+		tables:            make(map[string]bool),
+		rowsReturned:      env.Exporter().NewCountersWithMultiLabels("TableRowsReturned", "rows returned by the queries of each table", labels),
+		rowsAffected:      env.Exporter().NewCountersWithMultiLabels("TableRowsAffected", "rows affected by the queries of each table", labels),
+		fullScans:         env.Exporter().NewCountersWithMultiLabels("TableFullScans", "full table scans detected in the explained sample of the selects", labels),
+	}
+}
+
+// label returns the Table label of the table, which is tableStatsOtherLabel
+// once maxTables tables have their own label.
+func (ts *tableStats) label(table string) string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.tables[table] {
+		return table
+	}
+	if len(ts.tables) >= ts.maxTables {
+		return tableStatsOtherLabel
+	}
+	ts.tables[table] = true
+	return table
+}
+
+// add adds the rows of a query to all its tables.
+func (ts *tableStats) add(tables []string, tabletType topodatapb.TabletType, rowsReturned, rowsAffected int64) {
+	if ts == nil || (rowsReturned == 0 && rowsAffected == 0) {
+		return
+	}
+	for _, table := range tables {
+		if table == "" {
+			continue
+		}
+		keys := []string{ts.label(table), tabletType.String()}
+		if rowsReturned > 0 {
+			ts.rowsReturned.Add(keys, rowsReturned)
+		}
+		if rowsAffected > 0 {
+			ts.rowsAffected.Add(keys, rowsAffected)
+		}
+	}
+}
+
+// sampleExplain explains the select on the tables in the background if it is
+// sampled, and counts the full table scans of its plan.
+func (ts *tableStats) sampleExplain(conns *connpool.Pool, sql string, tables []string, tabletType topodatapb.TabletType) {
+	if ts == nil || ts.explainSampleRate == 0 || rand.Float64() >= ts.explainSampleRate {
+		return
+	}
+	if !ts.explaining.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer ts.explaining.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+		defer cancel()
+
+		conn, err := conns.Get(ctx, nil)
+		if err != nil {
+			log.Warningf("table metrics: cannot get a connection to explain a query: %v", err)
+			return
+		}
+		defer conn.Recycle()
+
+		qr, err := conn.Conn.Exec(ctx, "explain "+sql, 1000, true)
+		if err != nil {
+			log.Warningf("table metrics: cannot explain a query: %v", err)
+			return
+		}
+		for _, table := range fullScanTables(qr, tables) {
+			ts.fullScans.Add([]string{ts.label(table), tabletType.String()}, 1)
+		}
+	}()
+}
+
+// fullScanTables returns the tables of the query that are read with a full
+// table scan according to the result of its explain. The explain names the
+// tables by their alias, so an alias that is not a table of the query is only
+// resolved if the query has a single table.
+func fullScanTables(qr *sqltypes.Result, tables []string) []string {
+	tableIdx, typeIdx := -1, -1
+	for i, field := range qr.Fields {
+		switch strings.ToLower(field.Name) {
+		case "table":
+			tableIdx = i
+		case "type":
+			typeIdx = i
+		}
+	}
+	if tableIdx == -1 || typeIdx == -1 {
+		return nil
+	}
+
+	var scanned []string
+	for _, row := range qr.Rows {
+		table := row[tableIdx].ToString()
+		// Derived tables and subqueries show up as <derived2> or <subquery2>.
+		if row[typeIdx].ToString() != "ALL" || table == "" || strings.HasPrefix(table, "<") {
+			continue
+		}
+		switch {
+		case slices.Contains(tables, table):
+			scanned = append(scanned, table)
+		case len(tables) == 1:
+			scanned = append(scanned, tables[0])
+		}
+	}
+	return scanned
+}
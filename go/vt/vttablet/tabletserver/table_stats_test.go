@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tabletserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestTableStats(t *testing.T) {
+	cfg := tabletenv.NewDefaultConfig()
+	assert.Nil(t, newTableStats(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "TestTableStatsDisabled")))
+
+	cfg.TableMetrics.Enable = true
+	cfg.TableMetrics.MaxTables = 2
+	ts := newTableStats(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "TestTableStats"))
+	require.NotNil(t, ts)
+
+	ts.add([]string{"t1"}, topodatapb.TabletType_PRIMARY, 10, 0)
+	ts.add([]string{"t1", "t2"}, topodatapb.TabletType_REPLICA, 3, 0)
+	ts.add([]string{"t3"}, topodatapb.TabletType_PRIMARY, 0, 5)
+	ts.add([]string{"t4"}, topodatapb.TabletType_PRIMARY, 0, 1)
+	ts.add([]string{""}, topodatapb.TabletType_PRIMARY, 1, 0)
+
+	assert.Equal(t, map[string]int64{
+		"t1.PRIMARY": 10,
+		"t1.REPLICA": 3,
+		"t2.REPLICA": 3,
+	}, ts.rowsReturned.Counts())
+	assert.Equal(t, map[string]int64{
+		"(other).PRIMARY": 6,
+	}, ts.rowsAffected.Counts())
+
+	// A nil tableStats is disabled.
+	var disabled *tableStats
+	disabled.add([]string{"t1"}, topodatapb.TabletType_PRIMARY, 1, 1)
+	disabled.sampleExplain(nil, "select * from t1", []string{"t1"}, topodatapb.TabletType_PRIMARY)
+}
+
+func TestFullScanTables(t *testing.T) {
+	fields := sqltypes.MakeTestFields("id|select_type|table|type|key|rows|Extra", "int64|varchar|varchar|varchar|varchar|int64|varchar")
+	qr := sqltypes.MakeTestResult(fields,
+		"1|PRIMARY|<derived2>|ALL|null|10|null",
+		"1|PRIMARY|t1|ALL|null|1000|Using where",
+		"1|PRIMARY|t2|eq_ref|PRIMARY|1|null",
+		"2|DERIVED|t3|ALL|null|10|null",
+		"3|SUBQUERY|a|ALL|null|10|null",
+	)
+	assert.Equal(t, []string{"t1", "t3"}, fullScanTables(qr, []string{"t1", "t2", "t3"}))
+
+	// An alias is resolved if the query has a single table.
+	assert.Equal(t, []string{"t1"}, fullScanTables(sqltypes.MakeTestResult(fields, "1|SIMPLE|a|ALL|null|1000|null"), []string{"t1"}))
+
+	assert.Empty(t, fullScanTables(sqltypes.MakeTestResult(sqltypes.MakeTestFields("EXPLAIN", "varchar"), "-> Table scan on t1"), []string{"t1"}))
+}
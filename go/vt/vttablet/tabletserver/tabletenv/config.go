@@ -55,6 +55,15 @@ const (
 	Heartbeat    = "heartbeat"
 )
 
+// These constants represent the policies for resolving abandoned distributed transactions.
+const (
+	// TwoPCResolveByCoordinator makes the watchdog ask the coordinator to resolve abandoned transactions.
+	TwoPCResolveByCoordinator = "coordinator"
+	// TwoPCResolveManually makes the watchdog only report abandoned transactions, leaving their
+	// resolution to an operator.
+	TwoPCResolveManually = "manual"
+)
+
 var (
 	currentConfig TabletConfig
 
@@ -167,6 +176,7 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&currentConfig.TwoPCEnable, "twopc_enable", defaultConfig.TwoPCEnable, "if the flag is on, 2pc is enabled. Other 2pc flags must be supplied.")
 	fs.StringVar(&currentConfig.TwoPCCoordinatorAddress, "twopc_coordinator_address", defaultConfig.TwoPCCoordinatorAddress, "address of the (VTGate) process(es) that will be used to notify of abandoned transactions.")
 	SecondsVar(fs, &currentConfig.TwoPCAbandonAge, "twopc_abandon_age", defaultConfig.TwoPCAbandonAge, "time in seconds. Any unresolved transaction older than this time will be sent to the coordinator to be resolved.")
+	fs.StringVar(&currentConfig.TwoPCResolutionPolicy, "twopc_resolution_policy", defaultConfig.TwoPCResolutionPolicy, "how the 2pc watchdog handles abandoned transactions. 'coordinator' asks the coordinator to resolve them, 'manual' only reports them so that an operator can resolve them.")
 	// Tx throttler config
 	flagutil.DualFormatBoolVar(fs, &currentConfig.EnableTxThrottler, "enable_tx_throttler", defaultConfig.EnableTxThrottler, "If true replication-lag-based throttling on transactions will be enabled.")
 	flagutil.DualFormatVar(fs, currentConfig.TxThrottlerConfig, "tx_throttler_config", "The configuration of the transaction throttler as a text-formatted throttlerdata.Configuration protocol buffer message.")
@@ -175,6 +185,8 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 	fs.Var(currentConfig.TxThrottlerTabletTypes, "tx-throttler-tablet-types", "A comma-separated list of tablet types. Only tablets of this type are monitored for replication lag by the transaction throttler. Supported types are replica and/or rdonly.")
 	fs.BoolVar(&currentConfig.TxThrottlerDryRun, "tx-throttler-dry-run", defaultConfig.TxThrottlerDryRun, "If present, the transaction throttler only records metrics about requests received and throttled, but does not actually throttle any requests.")
 	fs.DurationVar(&currentConfig.TxThrottlerTopoRefreshInterval, "tx-throttler-topo-refresh-interval", time.Minute*5, "The rate that the transaction throttler will refresh the topology to find cells.")
+	fs.IntVar(&currentConfig.TxThrottlerVersion, "tx-throttler-version", defaultConfig.TxThrottlerVersion, "Version of the transaction throttler. Version 1 throttles with the throttler module on the replication lag of the --tx-throttler-tablet-types tablets. Version 2 throttles with a controller keeping the replication lag of all the replicas of the shard under --tx-throttler-lag-ceiling.")
+	fs.DurationVar(&currentConfig.TxThrottlerLagCeiling, "tx-throttler-lag-ceiling", defaultConfig.TxThrottlerLagCeiling, "Replication lag the version 2 transaction throttler keeps the replicas of the shard under.")
 
 	fs.BoolVar(&enableHotRowProtection, "enable_hot_row_protection", false, "If true, incoming transactions for the same row (range) will be queued and cannot consume all txpool slots.")
 	fs.BoolVar(&enableHotRowProtectionDryRun, "enable_hot_row_protection_dry_run", false, "If true, hot row protection is not enforced but logs if transactions would have been queued.")
@@ -217,6 +229,10 @@ func registerTabletEnvFlags(fs *pflag.FlagSet) {
 
 	fs.BoolVar(&currentConfig.EnablePerWorkloadTableMetrics, "enable-per-workload-table-metrics", defaultConfig.EnablePerWorkloadTableMetrics, "If true, query counts and query error metrics include a label that identifies the workload")
 
+	fs.BoolVar(&currentConfig.TableMetrics.Enable, "enable-table-metrics", defaultConfig.TableMetrics.Enable, "If true, export the rows returned and affected by the queries of each table, and the size of each table in the InnoDB buffer pool")
+	fs.IntVar(&currentConfig.TableMetrics.MaxTables, "table-metrics-max-tables", defaultConfig.TableMetrics.MaxTables, "Maximum number of tables with their own label in the table metrics. The queries of the other tables are counted under the '(other)' label")
+	fs.Float64Var(&currentConfig.TableMetrics.ExplainSampleRate, "table-metrics-explain-sample-rate", defaultConfig.TableMetrics.ExplainSampleRate, "Fraction of the selects that are explained to count the full table scans of each table in the table metrics, between 0 and 1")
+
 	fs.BoolVar(&currentConfig.Unmanaged, "unmanaged", false, "Indicates an unmanaged tablet, i.e. using an external mysql-compatible database")
 }
 
@@ -347,6 +363,7 @@ type TabletConfig struct {
 	TwoPCEnable             bool    `json:"-"`
 	TwoPCCoordinatorAddress string  `json:"-"`
 	TwoPCAbandonAge         Seconds `json:"-"`
+	TwoPCResolutionPolicy   string  `json:"-"`
 
 	EnableTxThrottler              bool                          `json:"-"`
 	TxThrottlerConfig              *TxThrottlerConfigFlag        `json:"-"`
@@ -355,6 +372,8 @@ type TabletConfig struct {
 	TxThrottlerTabletTypes         *topoproto.TabletTypeListFlag `json:"-"`
 	TxThrottlerTopoRefreshInterval time.Duration                 `json:"-"`
 	TxThrottlerDryRun              bool                          `json:"-"`
+	TxThrottlerVersion             int                           `json:"-"`
+	TxThrottlerLagCeiling          time.Duration                 `json:"-"`
 
 	EnableTableGC bool `json:"-"` // can be turned off programmatically by tests
 
@@ -369,6 +388,8 @@ type TabletConfig struct {
 	EnableViews bool `json:"-"`
 
 	EnablePerWorkloadTableMetrics bool `json:"-"`
+
+	TableMetrics TableMetricsConfig `json:"-"`
 }
 
 func (cfg *TabletConfig) MarshalJSON() ([]byte, error) {
@@ -822,6 +843,17 @@ type RowStreamerConfig struct {
 	MaxMySQLReplLagSecs int64 `json:"maxMySQLReplLagSecs,omitempty"`
 }
 
+// TableMetricsConfig contains the configuration of the per-table metrics, which
+// show which tables drive the load of the tablet.
+type TableMetricsConfig struct {
+	Enable bool
+	// MaxTables limits the cardinality of the Table label of the metrics.
+	MaxTables int
+	// ExplainSampleRate is the fraction of the selects that are explained to
+	// detect full table scans.
+	ExplainSampleRate float64
+}
+
 // NewCurrentConfig returns a copy of the current config.
 func NewCurrentConfig() *TabletConfig {
 	return currentConfig.Clone()
@@ -877,6 +909,11 @@ func (c *TabletConfig) Verify() error {
 	if err := c.verifyTxThrottlerConfig(); err != nil {
 		return err
 	}
+	switch c.TwoPCResolutionPolicy {
+	case TwoPCResolveByCoordinator, TwoPCResolveManually:
+	default:
+		return fmt.Errorf("--twopc_resolution_policy must be one of %q or %q (specified value: %q)", TwoPCResolveByCoordinator, TwoPCResolveManually, c.TwoPCResolutionPolicy)
+	}
 	if v := c.HotRowProtection.MaxQueueSize; v <= 0 {
 		return fmt.Errorf("--hot_row_protection_max_queue_size must be > 0 (specified value: %v)", v)
 	}
@@ -889,6 +926,12 @@ func (c *TabletConfig) Verify() error {
 	if v := c.HotRowProtection.MaxConcurrency; v <= 0 {
 		return fmt.Errorf("--hot_row_protection_concurrent_transactions must be > 0 (specified value: %v)", v)
 	}
+	if v := c.TableMetrics.MaxTables; v <= 0 {
+		return fmt.Errorf("--table-metrics-max-tables must be > 0 (specified value: %v)", v)
+	}
+	if v := c.TableMetrics.ExplainSampleRate; v < 0 || v > 1 {
+		return fmt.Errorf("--table-metrics-explain-sample-rate must be between 0 and 1 (specified value: %v)", v)
+	}
 	return nil
 }
 
@@ -977,6 +1020,16 @@ func (c *TabletConfig) verifyTxThrottlerConfig() error {
 		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "failed to parse throttlerdatapb.Configuration config: %v", err)
 	}
 
+	switch c.TxThrottlerVersion {
+	case 1:
+	case 2:
+		if c.TxThrottlerLagCeiling <= 0 {
+			return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--tx-throttler-lag-ceiling must be positive (specified value: %v)", c.TxThrottlerLagCeiling)
+		}
+	default:
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--tx-throttler-version must be 1 or 2 (specified value: %d)", c.TxThrottlerVersion)
+	}
+
 	if v := c.TxThrottlerDefaultPriority; v > sqlparser.MaxPriorityValue || v < 0 {
 		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "--tx-throttler-default-priority must be > 0 and < 100 (specified value: %d)", v)
 	}
@@ -1071,9 +1124,13 @@ var defaultConfig = TabletConfig{
 	TxThrottlerTabletTypes:         &topoproto.TabletTypeListFlag{topodatapb.TabletType_REPLICA},
 	TxThrottlerDryRun:              false,
 	TxThrottlerTopoRefreshInterval: time.Minute * 5,
+	TxThrottlerVersion:             1,
+	TxThrottlerLagCeiling:          5 * time.Second,
 
 	TransactionLimitConfig: defaultTransactionLimitConfig(),
 
+	TwoPCResolutionPolicy: TwoPCResolveByCoordinator,
+
 	EnforceStrictTransTables: true,
 	EnableOnlineDDL:          true,
 	EnableTableGC:            true,
@@ -1085,6 +1142,10 @@ var defaultConfig = TabletConfig{
 
 	EnablePerWorkloadTableMetrics: false,
 	EnableSettingsPool:            true,
+
+	TableMetrics: TableMetricsConfig{
+		MaxTables: 1000,
+	},
 }
 
 // defaultTxThrottlerConfig returns the default TxThrottlerConfigFlag object based on
@@ -358,6 +358,8 @@ func TestVerifyTxThrottlerConfig(t *testing.T) {
 		TxThrottlerHealthCheckCells []string
 		TxThrottlerTabletTypes      *topoproto.TabletTypeListFlag
 		TxThrottlerDefaultPriority  int
+		TxThrottlerVersion          int
+		TxThrottlerLagCeiling       time.Duration
 	}
 
 	tests := []testConfig{
@@ -418,6 +420,31 @@ func TestVerifyTxThrottlerConfig(t *testing.T) {
 			TxThrottlerDefaultPriority:  12345,
 			TxThrottlerHealthCheckCells: []string{"cell1"},
 		},
+		{
+			// enabled + version 2
+			Name:                  "enabled version 2",
+			EnableTxThrottler:     true,
+			TxThrottlerConfig:     &TxThrottlerConfigFlag{defaultMaxReplicationLagModuleConfig},
+			TxThrottlerVersion:    2,
+			TxThrottlerLagCeiling: 10 * time.Second,
+		},
+		{
+			// enabled + version 2 with a negative lag ceiling
+			Name:                  "enabled version 2 negative lag ceiling",
+			ExpectedErrorCode:     vtrpcpb.Code_INVALID_ARGUMENT,
+			EnableTxThrottler:     true,
+			TxThrottlerConfig:     &TxThrottlerConfigFlag{defaultMaxReplicationLagModuleConfig},
+			TxThrottlerVersion:    2,
+			TxThrottlerLagCeiling: -time.Second,
+		},
+		{
+			// enabled + unsupported version
+			Name:               "enabled unsupported version",
+			ExpectedErrorCode:  vtrpcpb.Code_INVALID_ARGUMENT,
+			EnableTxThrottler:  true,
+			TxThrottlerConfig:  &TxThrottlerConfigFlag{defaultMaxReplicationLagModuleConfig},
+			TxThrottlerVersion: 3,
+		},
 	}
 
 	for _, test := range tests {
@@ -435,6 +462,12 @@ func TestVerifyTxThrottlerConfig(t *testing.T) {
 			if test.TxThrottlerTabletTypes != nil {
 				config.TxThrottlerTabletTypes = test.TxThrottlerTabletTypes
 			}
+			if test.TxThrottlerVersion != 0 {
+				config.TxThrottlerVersion = test.TxThrottlerVersion
+			}
+			if test.TxThrottlerLagCeiling != 0 {
+				config.TxThrottlerLagCeiling = test.TxThrottlerLagCeiling
+			}
 
 			err := config.verifyTxThrottlerConfig()
 			if test.ExpectedErrorCode == vtrpcpb.Code_OK {
@@ -481,3 +514,28 @@ func TestVerifyUnmanagedTabletConfig(t *testing.T) {
 	err = config.verifyUnmanagedTabletConfig()
 	assert.Nil(t, err)
 }
+
+func TestVerifyTwoPCResolutionPolicy(t *testing.T) {
+	config := defaultConfig
+
+	for _, policy := range []string{TwoPCResolveByCoordinator, TwoPCResolveManually} {
+		config.TwoPCResolutionPolicy = policy
+		assert.NoError(t, config.Verify())
+	}
+
+	config.TwoPCResolutionPolicy = "rollback"
+	assert.EqualError(t, config.Verify(), `--twopc_resolution_policy must be one of "coordinator" or "manual" (specified value: "rollback")`)
+}
+
+func TestVerifyTableMetricsConfig(t *testing.T) {
+	config := defaultConfig
+	config.TableMetrics.ExplainSampleRate = 0.01
+	assert.NoError(t, config.Verify())
+
+	config.TableMetrics.ExplainSampleRate = 1.5
+	assert.EqualError(t, config.Verify(), "--table-metrics-explain-sample-rate must be between 0 and 1 (specified value: 1.5)")
+
+	config.TableMetrics.ExplainSampleRate = 0
+	config.TableMetrics.MaxTables = 0
+	assert.EqualError(t, config.Verify(), "--table-metrics-max-tables must be > 0 (specified value: 0)")
+}
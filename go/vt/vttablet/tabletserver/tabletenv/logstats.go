@@ -18,6 +18,7 @@ package tabletenv
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
@@ -62,6 +63,9 @@ type LogStats struct {
 	ReservedID           int64
 	Error                error
 	CachedPlan           bool
+	QueryAttributes      map[string]string
+	ConnectionAttributes map[string]string
+	ClientHost           string
 }
 
 // NewLogStats constructs a new LogStats with supplied Method and ctx
@@ -198,18 +202,7 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 	// TODO: remove username here we fully enforce immediate caller id
 	callInfo, username := stats.CallInfo()
 
-	// Valid options for the QueryLogFormat are text or json
-	var fmtString string
-	switch streamlog.GetQueryLogFormat() {
-	case streamlog.QueryLogFormatText:
-		fmtString = "%v\t%v\t%v\t'%v'\t'%v'\t%v\t%v\t%.6f\t%v\t%q\t%v\t%v\t%q\t%v\t%.6f\t%.6f\t%v\t%v\t%v\t%q\t\n"
-	case streamlog.QueryLogFormatJSON:
-		fmtString = "{\"Method\": %q, \"CallInfo\": %q, \"Username\": %q, \"ImmediateCaller\": %q, \"Effective Caller\": %q, \"Start\": \"%v\", \"End\": \"%v\", \"TotalTime\": %.6f, \"PlanType\": %q, \"OriginalSQL\": %q, \"BindVars\": %v, \"Queries\": %v, \"RewrittenSQL\": %q, \"QuerySources\": %q, \"MysqlTime\": %.6f, \"ConnWaitTime\": %.6f, \"RowsAffected\": %v,\"TransactionID\": %v,\"ResponseSize\": %v, \"Error\": %q}\n"
-	}
-
-	_, err := fmt.Fprintf(
-		w,
-		fmtString,
+	args := []any{
 		stats.Method,
 		callInfo,
 		username,
@@ -230,6 +223,38 @@ func (stats *LogStats) Logf(w io.Writer, params url.Values) error {
 		stats.TransactionID,
 		stats.SizeOfResponse(),
 		stats.ErrorStr(),
-	)
+	}
+
+	// Valid options for the QueryLogFormat are text or json
+	var fmtString string
+	switch streamlog.GetQueryLogFormat() {
+	case streamlog.QueryLogFormatText:
+		fmtString = "%v\t%v\t%v\t'%v'\t'%v'\t%v\t%v\t%.6f\t%v\t%q\t%v\t%v\t%q\t%v\t%.6f\t%.6f\t%v\t%v\t%v\t%q\t\n"
+	case streamlog.QueryLogFormatJSON:
+		fmtString = "{\"Method\": %q, \"CallInfo\": %q, \"Username\": %q, \"ImmediateCaller\": %q, \"Effective Caller\": %q, \"Start\": \"%v\", \"End\": \"%v\", \"TotalTime\": %.6f, \"PlanType\": %q, \"OriginalSQL\": %q, \"BindVars\": %v, \"Queries\": %v, \"RewrittenSQL\": %q, \"QuerySources\": %q, \"MysqlTime\": %.6f, \"ConnWaitTime\": %.6f, \"RowsAffected\": %v,\"TransactionID\": %v,\"ResponseSize\": %v, \"Error\": %q, \"QueryAttributes\": %v, \"ConnectionAttributes\": %v, \"ClientHost\": %q}\n"
+
+		// The attributes and the client host are only logged in the JSON
+		// format, which doesn't depend on the position of the fields.
+		queryAttributes, err := marshalAttributes(stats.QueryAttributes)
+		if err != nil {
+			return err
+		}
+		connectionAttributes, err := marshalAttributes(stats.ConnectionAttributes)
+		if err != nil {
+			return err
+		}
+		args = append(args, string(queryAttributes), string(connectionAttributes), stats.ClientHost)
+	}
+
+	_, err := fmt.Fprintf(w, fmtString, args...)
 	return err
 }
+
+// marshalAttributes returns the JSON representation of query or connection
+// attributes, with no attributes logged as an empty object.
+func marshalAttributes(attributes map[string]string) ([]byte, error) {
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	return json.Marshal(attributes)
+}
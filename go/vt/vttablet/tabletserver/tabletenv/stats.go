@@ -34,12 +34,16 @@ type Stats struct {
 	ErrorCounters          *stats.CountersWithSingleLabel
 	InternalErrors         *stats.CountersWithSingleLabel
 	Warnings               *stats.CountersWithSingleLabel
-	Unresolved             *stats.GaugesWithSingleLabel   // For now, only Prepares are tracked
+	Unresolved             *stats.GaugesWithSingleLabel   // Unresolved prepares, failed commits and abandoned transactions
+	TwopcTransactions      *stats.CountersWithSingleLabel // 2PC transactions by the state they reached
+	TwopcResolutions       *stats.CountersWithSingleLabel // Resolutions requested from the 2PC coordinator
 	UserTableQueryCount    *stats.CountersWithMultiLabels // Per CallerID/table counts
 	UserTableQueryTimesNs  *stats.CountersWithMultiLabels // Per CallerID/table latencies
 	UserTransactionCount   *stats.CountersWithMultiLabels // Per CallerID transaction counts
 	UserTransactionTimesNs *stats.CountersWithMultiLabels // Per CallerID transaction latencies
 	ResultHistogram        *stats.Histogram               // Row count histograms
+	StreamChunkRows        *stats.Histogram               // Row count histograms of streamed chunks
+	StreamChunkBytes       *stats.Histogram               // Byte size histograms of streamed chunks
 	TableaclAllowed        *stats.CountersWithMultiLabels // Number of allows
 	TableaclDenied         *stats.CountersWithMultiLabels // Number of denials
 	TableaclPseudoDenied   *stats.CountersWithMultiLabels // Number of pseudo denials
@@ -49,6 +53,8 @@ type Stats struct {
 	UserReservedTimesNs     *stats.CountersWithSingleLabel // Per CallerID reserved connection duration
 
 	QueryTimingsByTabletType *servenv.TimingsWrapper // Query timings split by current tablet type
+
+	Dimensions *stats.Dimensions // Keyspace, shard and cell of the tablet, to tag the metrics created with them
 }
 
 // NewStats instantiates a new set of stats scoped by exporter.
@@ -83,12 +89,16 @@ func NewStats(exporter *servenv.Exporter) *Stats {
 		),
 		InternalErrors:         exporter.NewCountersWithSingleLabel("InternalErrors", "Internal component errors", "type", "Task", "StrayTransactions", "Panic", "HungQuery", "Schema", "TwopcCommit", "TwopcResurrection", "WatchdogFail", "Messages"),
 		Warnings:               exporter.NewCountersWithSingleLabel("Warnings", "Warnings", "type", "ResultsExceeded"),
-		Unresolved:             exporter.NewGaugesWithSingleLabel("Unresolved", "Unresolved items", "item_type", "Prepares"),
+		Unresolved:             exporter.NewGaugesWithSingleLabel("Unresolved", "Unresolved items", "item_type", "Prepares", "Failed", "Abandoned"),
+		TwopcTransactions:      exporter.NewCountersWithSingleLabel("TwopcTransactions", "Two-phase commit transactions by the state they reached", "state", "Prepared", "Committed", "RolledBack", "Failed", "Concluded"),
+		TwopcResolutions:       exporter.NewCountersWithSingleLabel("TwopcResolutions", "Resolutions of distributed transactions requested from the coordinator", "source", "Watchdog", "Operator"),
 		UserTableQueryCount:    exporter.NewCountersWithMultiLabels("UserTableQueryCount", "Queries received for each CallerID/table combination", []string{"TableName", "CallerID", "Type"}),
 		UserTableQueryTimesNs:  exporter.NewCountersWithMultiLabels("UserTableQueryTimesNs", "Total latency for each CallerID/table combination", []string{"TableName", "CallerID", "Type"}),
 		UserTransactionCount:   exporter.NewCountersWithMultiLabels("UserTransactionCount", "transactions received for each CallerID", []string{"CallerID", "Conclusion"}),
 		UserTransactionTimesNs: exporter.NewCountersWithMultiLabels("UserTransactionTimesNs", "Total transaction latency for each CallerID", []string{"CallerID", "Conclusion"}),
 		ResultHistogram:        exporter.NewHistogram("Results", "Distribution of rows returned", []int64{0, 1, 5, 10, 50, 100, 500, 1000, 5000, 10000}),
+		StreamChunkRows:        exporter.NewHistogram("StreamChunkRows", "Distribution of rows per chunk of streaming queries", []int64{1, 5, 10, 50, 100, 500, 1000, 5000, 10000}),
+		StreamChunkBytes:       exporter.NewHistogram("StreamChunkBytes", "Distribution of bytes per chunk of streaming queries", []int64{1024, 4096, 16384, 32768, 65536, 262144, 1048576, 4194304}),
 		TableaclAllowed:        exporter.NewCountersWithMultiLabels("TableACLAllowed", "ACL acceptances", []string{"TableName", "TableGroup", "PlanID", "Username"}),
 		TableaclDenied:         exporter.NewCountersWithMultiLabels("TableACLDenied", "ACL denials", []string{"TableName", "TableGroup", "PlanID", "Username"}),
 		TableaclPseudoDenied:   exporter.NewCountersWithMultiLabels("TableACLPseudoDenied", "ACL pseudodenials", []string{"TableName", "TableGroup", "PlanID", "Username"}),
@@ -98,6 +108,7 @@ func NewStats(exporter *servenv.Exporter) *Stats {
 		UserReservedTimesNs:     exporter.NewCountersWithSingleLabel("UserReservedTimesNs", "Total reserved connection latency for each CallerID", "CallerID"),
 
 		QueryTimingsByTabletType: exporter.NewTimings("QueryTimingsByTabletType", "Query timings broken down by active tablet type", "TabletType"),
+		Dimensions:               stats.NewDimensions("", "", ""),
 	}
 	stats.QPSRates = exporter.NewRates("QPS", stats.QueryTimings, 15*60/5, 5*time.Second)
 	return stats
@@ -57,6 +57,8 @@ import (
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/gc"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/messager"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/planbuilder"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/readonly"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/replsettings"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/repltracker"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
@@ -117,6 +119,8 @@ type TabletServer struct {
 	hs           *healthStreamer
 	lagThrottler *throttle.Throttler
 	tableGC      *gc.TableGC
+	rsc          *replsettings.Checker
+	roe          *readonly.Enforcer
 
 	// sm manages state transitions.
 	sm                *stateManager
@@ -176,7 +180,7 @@ func NewTabletServer(ctx context.Context, env *vtenv.Environment, name string, c
 	tsv.hs = newHealthStreamer(tsv, alias, tsv.se)
 	tsv.rt = repltracker.NewReplTracker(tsv, alias)
 	tsv.lagThrottler = throttle.NewThrottler(tsv, srvTopoServer, topoServer, alias.Cell, tsv.rt.HeartbeatWriter(), tabletTypeFunc)
-	tsv.vstreamer = vstreamer.NewEngine(tsv, srvTopoServer, tsv.se, tsv.lagThrottler, alias.Cell)
+	tsv.vstreamer = vstreamer.NewEngine(tsv, srvTopoServer, tsv.se, tsv.lagThrottler, tsv.rt.HeartbeatWriter(), alias.Cell)
 	tsv.tracker = schema.NewTracker(tsv, tsv.vstreamer, tsv.se)
 	tsv.watcher = NewBinlogWatcher(tsv, tsv.vstreamer, tsv.config)
 	tsv.qe = NewQueryEngine(tsv, tsv.se)
@@ -185,6 +189,8 @@ func NewTabletServer(ctx context.Context, env *vtenv.Environment, name string, c
 	tsv.messager = messager.NewEngine(tsv, tsv.se, tsv.vstreamer)
 
 	tsv.tableGC = gc.NewTableGC(tsv, topoServer, tsv.lagThrottler)
+	tsv.rsc = replsettings.NewChecker()
+	tsv.roe = readonly.NewEnforcer()
 	tsv.onlineDDLExecutor = onlineddl.NewExecutor(tsv, alias, topoServer, tsv.lagThrottler, tabletTypeFunc, tsv.onlineDDLExecutorToggleTableBuffer, tsv.tableGC.RequestChecks)
 
 	tsv.sm = &stateManager{
@@ -204,6 +210,8 @@ func NewTabletServer(ctx context.Context, env *vtenv.Environment, name string, c
 		ddle:        tsv.onlineDDLExecutor,
 		throttler:   tsv.lagThrottler,
 		tableGC:     tsv.tableGC,
+		rsc:         tsv.rsc,
+		roe:         tsv.roe,
 		rw:          newRequestsWaiter(),
 	}
 
@@ -266,9 +274,12 @@ func (tsv *TabletServer) InitDBConfig(target *querypb.Target, dbcfgs *dbconfigs.
 	tsv.sm.Init(tsv, target)
 	tsv.sm.target = target.CloneVT()
 	tsv.config.DB = dbcfgs
+	tsv.stats.Dimensions.Set(target.Keyspace, target.Shard, tsv.alias.GetCell())
 
 	tsv.se.InitDBConfig(tsv.config.DB.DbaWithDB())
 	tsv.rt.InitDBConfig(target, mysqld)
+	tsv.rsc.InitDBConfig(mysqld)
+	tsv.roe.InitDBConfig(mysqld)
 	tsv.txThrottler.InitDBConfig(target)
 	tsv.vstreamer.InitDBConfig(target.Keyspace, target.Shard)
 	tsv.hs.InitDBConfig(target, tsv.config.DB.DbaWithDB())
@@ -620,7 +631,7 @@ func (tsv *TabletServer) Rollback(ctx context.Context, target *querypb.Target, t
 		"Rollback", "rollback", nil,
 		target, nil, true, /* allowOnShutdown */
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
-			defer tsv.stats.QueryTimings.Record("ROLLBACK", time.Now())
+			defer tsv.stats.QueryTimings.Start(ctx, "ROLLBACK").Stop()
 			targetType, err := tsv.resolveTargetType(ctx, target)
 			if err != nil {
 				return err
@@ -779,6 +790,17 @@ func (tsv *TabletServer) ReadTransaction(ctx context.Context, target *querypb.Ta
 	return metadata, err
 }
 
+// UnresolvedTransactions returns the distributed transactions managed by this
+// tablet that were not resolved within abandonAge.
+func (tsv *TabletServer) UnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error) {
+	return tsv.te.UnresolvedTransactions(ctx, abandonAge)
+}
+
+// ResolveTransaction asks the 2PC coordinator to resolve the distributed transaction.
+func (tsv *TabletServer) ResolveTransaction(ctx context.Context, dtid string) error {
+	return tsv.te.ResolveTransaction(ctx, dtid)
+}
+
 // Execute executes the query and returns the result as response.
 func (tsv *TabletServer) Execute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, transactionID, reservedID int64, options *querypb.ExecuteOptions) (result *sqltypes.Result, err error) {
 	span, ctx := trace.NewSpan(ctx, "TabletServer.Execute")
@@ -789,7 +811,12 @@ func (tsv *TabletServer) Execute(ctx context.Context, target *querypb.Target, sq
 		return nil, vterrors.New(vtrpcpb.Code_INTERNAL, "[BUG] transactionID and reserveID must match if both are non-zero")
 	}
 
-	return tsv.execute(ctx, target, sql, bindVariables, transactionID, reservedID, nil, options)
+	result, err = tsv.execute(ctx, target, sql, bindVariables, transactionID, reservedID, nil, options)
+	if result != nil {
+		span.Annotate("rows_affected", result.RowsAffected)
+		span.Annotate("rows_returned", len(result.Rows))
+	}
+	return result, err
 }
 
 func (tsv *TabletServer) execute(ctx context.Context, target *querypb.Target, sql string, bindVariables map[string]*querypb.BindVariable, transactionID int64, reservedID int64, settings []string, options *querypb.ExecuteOptions) (result *sqltypes.Result, err error) {
@@ -818,6 +845,9 @@ func (tsv *TabletServer) execute(ctx context.Context, target *querypb.Target, sq
 			if err != nil {
 				return err
 			}
+			if span, ok := trace.FromContext(ctx); ok {
+				span.Annotate("plan_type", plan.PlanID.String())
+			}
 			if err = plan.IsValid(reservedID != 0, len(settings) > 0); err != nil {
 				return err
 			}
@@ -1144,14 +1174,82 @@ func (tsv *TabletServer) PurgeMessages(ctx context.Context, target *querypb.Targ
 	})
 }
 
+// DeadLetterMessages moves the list of messages for a given message table
+// to its dead-letter table.
+// It returns the number of messages successfully moved.
+func (tsv *TabletServer) DeadLetterMessages(ctx context.Context, target *querypb.Target, querygen messager.QueryGenerator, ids []string) (count int64, err error) {
+	return tsv.execDMLs(ctx, target, func() ([]string, map[string]*querypb.BindVariable, error) {
+		return querygen.GenerateDeadLetterQueries(ids)
+	})
+}
+
+// RequeueDeadLetters moves messages from the dead-letter table of the given
+// message table back to it, to be sent again. All the dead letters are
+// requeued if ids is empty.
+// It returns the number of messages successfully requeued.
+func (tsv *TabletServer) RequeueDeadLetters(ctx context.Context, name string, ids []string) (count int64, err error) {
+	querygen, err := tsv.messager.GetGenerator(name)
+	if err != nil {
+		return 0, err
+	}
+	count, err = tsv.execDMLs(ctx, tsv.sm.Target(), func() ([]string, map[string]*querypb.BindVariable, error) {
+		return querygen.GenerateRequeueQueries(ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	messager.MessageStats.Add([]string{name, "Requeued"}, count)
+	return count, nil
+}
+
+// KillQueries kills the running queries that match the filter, and returns
+// them. The queries are only returned, and left running, if dryRun is set.
+func (tsv *TabletServer) KillQueries(ctx context.Context, filter QueryFilter, dryRun bool) ([]KilledQuery, error) {
+	if filter.Query == "" && filter.PlanType == "" && filter.Caller == "" {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "a query, a plan type or a caller is required to select the queries to kill")
+	}
+	if filter.Query != "" {
+		fingerprint, err := queryFingerprint(tsv.env.Parser(), filter.Query)
+		if err != nil {
+			return nil, vterrors.Wrapf(err, "cannot compute the fingerprint of %q", filter.Query)
+		}
+		filter.Query = fingerprint
+	}
+	if filter.PlanType != "" {
+		planType, ok := planbuilder.PlanByNameIC(filter.PlanType)
+		if !ok {
+			return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "unknown plan type: %s", filter.PlanType)
+		}
+		filter.PlanType = planType.String()
+	}
+
+	var killed []KilledQuery
+	for _, ql := range []*QueryList{tsv.statelessql, tsv.statefulql, tsv.olapql} {
+		killed = append(killed, ql.Kill(filter, dryRun)...)
+	}
+	if !dryRun {
+		log.Infof("KillQueries(query: %q, plan type: %q, caller: %q) killed %d queries", filter.Query, filter.PlanType, filter.Caller, len(killed))
+	}
+	return killed, nil
+}
+
 func (tsv *TabletServer) execDML(ctx context.Context, target *querypb.Target, queryGenerator func() (string, map[string]*querypb.BindVariable, error)) (count int64, err error) {
+	return tsv.execDMLs(ctx, target, func() ([]string, map[string]*querypb.BindVariable, error) {
+		query, bv, err := queryGenerator()
+		return []string{query}, bv, err
+	})
+}
+
+// execDMLs executes the queries in a single transaction. It returns the
+// number of rows affected by the last one.
+func (tsv *TabletServer) execDMLs(ctx context.Context, target *querypb.Target, queryGenerator func() ([]string, map[string]*querypb.BindVariable, error)) (count int64, err error) {
 	if err = tsv.sm.StartRequest(ctx, target, false /* allowOnShutdown */); err != nil {
 		return 0, err
 	}
 	defer tsv.sm.EndRequest()
 	defer tsv.handlePanicAndSendLogStats("ack", nil, nil)
 
-	query, bv, err := queryGenerator()
+	queries, bv, err := queryGenerator()
 	if err != nil {
 		return 0, err
 	}
@@ -1167,9 +1265,11 @@ func (tsv *TabletServer) execDML(ctx context.Context, target *querypb.Target, qu
 			tsv.Rollback(ctx, target, state.TransactionID)
 		}
 	}()
-	qr, err := tsv.Execute(ctx, target, query, bv, state.TransactionID, 0, nil)
-	if err != nil {
-		return 0, err
+	var qr *sqltypes.Result
+	for _, query := range queries {
+		if qr, err = tsv.Execute(ctx, target, query, bv, state.TransactionID, 0, nil); err != nil {
+			return 0, err
+		}
 	}
 	if _, err = tsv.Commit(ctx, target, state.TransactionID); err != nil {
 		state.TransactionID = 0
@@ -1243,7 +1343,7 @@ func (tsv *TabletServer) ReserveBeginExecute(ctx context.Context, target *queryp
 		"ReserveBegin", "begin", bindVariables,
 		target, options, false, /* allowOnShutdown */
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
-			defer tsv.stats.QueryTimings.Record("RESERVE", time.Now())
+			defer tsv.stats.QueryTimings.Start(ctx, "RESERVE").Stop()
 			targetType, err := tsv.resolveTargetType(ctx, target)
 			if err != nil {
 				return err
@@ -1293,7 +1393,7 @@ func (tsv *TabletServer) ReserveBeginStreamExecute(
 		"ReserveBegin", "begin", bindVariables,
 		target, options, false, /* allowOnShutdown */
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
-			defer tsv.stats.QueryTimings.Record("RESERVE", time.Now())
+			defer tsv.stats.QueryTimings.Start(ctx, "RESERVE").Stop()
 			targetType, err := tsv.resolveTargetType(ctx, target)
 			if err != nil {
 				return err
@@ -1351,7 +1451,7 @@ func (tsv *TabletServer) ReserveExecute(ctx context.Context, target *querypb.Tar
 		"Reserve", "", bindVariables,
 		target, options, allowOnShutdown,
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
-			defer tsv.stats.QueryTimings.Record("RESERVE", time.Now())
+			defer tsv.stats.QueryTimings.Start(ctx, "RESERVE").Stop()
 			targetType, err := tsv.resolveTargetType(ctx, target)
 			if err != nil {
 				return err
@@ -1406,7 +1506,7 @@ func (tsv *TabletServer) ReserveStreamExecute(
 		"Reserve", "", bindVariables,
 		target, options, allowOnShutdown,
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
-			defer tsv.stats.QueryTimings.Record("RESERVE", time.Now())
+			defer tsv.stats.QueryTimings.Start(ctx, "RESERVE").Stop()
 			targetType, err := tsv.resolveTargetType(ctx, target)
 			if err != nil {
 				return err
@@ -1440,7 +1540,7 @@ func (tsv *TabletServer) Release(ctx context.Context, target *querypb.Target, tr
 		"Release", "", nil,
 		target, nil, true, /* allowOnShutdown */
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
-			defer tsv.stats.QueryTimings.Record("RELEASE", time.Now())
+			defer tsv.stats.QueryTimings.Start(ctx, "RELEASE").Stop()
 			targetType, err := tsv.resolveTargetType(ctx, target)
 			if err != nil {
 				return err
@@ -1502,7 +1602,7 @@ func (tsv *TabletServer) GetSchema(ctx context.Context, target *querypb.Target,
 		"GetSchema", "", nil,
 		target, nil, false, /* allowOnShutdown */
 		func(ctx context.Context, logStats *tabletenv.LogStats) error {
-			defer tsv.stats.QueryTimings.Record("GetSchema", time.Now())
+			defer tsv.stats.QueryTimings.Start(ctx, "GetSchema").Stop()
 
 			qre := &QueryExecutor{
 				ctx:      ctx,
@@ -1527,6 +1627,9 @@ func (tsv *TabletServer) execRequest(
 	if options != nil {
 		span.Annotate("isolation-level", options.TransactionIsolation)
 		span.Annotate("workload_name", options.WorkloadName)
+		for name, value := range options.QueryAttributes {
+			span.Annotate("query_attribute."+name, value)
+		}
 	}
 	trace.AnnotateSQL(span, sqlparser.Preview(sql))
 	// With a tabletenv.LocalContext() the target will be nil.
@@ -1542,6 +1645,9 @@ func (tsv *TabletServer) execRequest(
 	logStats.Target = target
 	logStats.OriginalSQL = sql
 	logStats.BindVariables = sqltypes.CopyBindVariables(bindVariables)
+	logStats.QueryAttributes = options.GetQueryAttributes()
+	logStats.ConnectionAttributes = options.GetConnectionAttributes()
+	logStats.ClientHost = options.GetClientHost()
 	defer tsv.handlePanicAndSendLogStats(sql, bindVariables, logStats)
 
 	if err = tsv.sm.StartRequest(ctx, target, allowOnShutdown); err != nil {
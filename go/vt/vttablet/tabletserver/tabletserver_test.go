@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
@@ -148,6 +149,19 @@ func TestBeginOnReplica(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTabletServerStatsDimensions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, tsv := setupTabletServerTest(t, ctx, "dimensionsKeyspace")
+	defer tsv.StopService()
+	defer db.Close()
+
+	keyspace, shard, cell := tsv.stats.Dimensions.Get()
+	assert.Equal(t, "dimensionsKeyspace", keyspace)
+	assert.Equal(t, "", shard)
+	assert.Equal(t, "", cell)
+}
+
 func TestTabletServerPrimaryToReplica(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -270,12 +284,38 @@ func TestTabletServerRedoLogIsKeptBetweenRestarts(t *testing.T) {
 	got = tsv.te.preparedPool.conns["a:b:10"].TxProperties().Queries
 	want = []string{"update test_table set `name` = 2 where pk = 1 limit 10001"}
 	utils.MustMatch(t, want, got, "Prepared queries")
-	wantFailed := map[string]error{"a:b:20": errPrepFailed}
+	// bogus cannot be replayed, and must not be reported as committed.
+	wantFailed := map[string]error{"bogus": errPrepFailed, "a:b:20": errPrepFailed}
 	utils.MustMatch(t, tsv.te.preparedPool.reserved, wantFailed, fmt.Sprintf("Failed dtids: %v, want %v", tsv.te.preparedPool.reserved, wantFailed))
 	// Verify last id got adjusted.
 	assert.EqualValues(t, 20, tsv.te.txPool.scp.lastID.Load(), "tsv.te.txPool.lastID.Get()")
 	turnOffTxEngine()
 	assert.Empty(t, tsv.te.preparedPool.conns, "tsv.te.preparedPool.conns")
+
+	// A transaction whose commit was decided is committed on replay.
+	db.AddQuery(tpc.readAllRedo, &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Type: sqltypes.VarBinary},
+			{Type: sqltypes.Uint64},
+			{Type: sqltypes.Uint64},
+			{Type: sqltypes.VarBinary},
+		},
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewVarBinary("a:b:30"),
+			sqltypes.NewInt64(RedoStateCommitting),
+			sqltypes.NewVarBinary(""),
+			sqltypes.NewVarBinary("update test_table set `name` = 2 where pk = 1 limit 10001"),
+		}},
+	})
+	db.AddQuery("update _vt.redo_state set state = 2 where dtid = 'a:b:30'", &sqltypes.Result{})
+	db.AddQuery("delete from _vt.redo_state where dtid = 'a:b:30'", &sqltypes.Result{})
+	db.AddQuery("delete from _vt.redo_statement where dtid = 'a:b:30'", &sqltypes.Result{})
+	db.AddQueryPattern("insert into _vt\\.dt_audit\\(dtid, action, source, message, time_created\\) values \\('a:b:30', 'commit', 'Recovery', 'success',.*", &sqltypes.Result{})
+	turnOnTxEngine()
+	assert.Empty(t, tsv.te.preparedPool.conns, "tsv.te.preparedPool.conns")
+	assert.Empty(t, tsv.te.preparedPool.reserved, "tsv.te.preparedPool.reserved")
+	assert.Equal(t, 1, db.GetQueryCalledNum("delete from _vt.redo_state where dtid = 'a:b:30'"))
+	turnOffTxEngine()
 }
 
 func TestTabletServerCreateTransaction(t *testing.T) {
@@ -1576,6 +1616,53 @@ func TestPurgeMessages(t *testing.T) {
 	require.EqualValues(t, 1, count)
 }
 
+func TestDeadLetterMessages(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, tsv, db := newTestTxExecutor(t, ctx)
+	defer db.Close()
+	defer tsv.StopService()
+	target := querypb.Target{TabletType: topodatapb.TabletType_PRIMARY}
+
+	gen, err := tsv.messager.GetGenerator("msg")
+	require.NoError(t, err)
+
+	_, err = tsv.DeadLetterMessages(ctx, &target, gen, []string{"1", "2"})
+	want := "query: 'insert into msg_dlq"
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), want)
+
+	db.AddQueryPattern("insert into msg_dlq.*", &sqltypes.Result{RowsAffected: 2})
+	_, err = tsv.DeadLetterMessages(ctx, &target, gen, []string{"1", "2"})
+	want = "query: 'delete from msg where"
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), want)
+
+	db.AddQueryPattern("delete from msg where .*", &sqltypes.Result{RowsAffected: 2})
+	count, err := tsv.DeadLetterMessages(ctx, &target, gen, []string{"1", "2"})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+}
+
+func TestRequeueDeadLetters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, tsv, db := newTestTxExecutor(t, ctx)
+	defer db.Close()
+	defer tsv.StopService()
+
+	_, err := tsv.RequeueDeadLetters(ctx, "nonmsg", nil)
+	want := "message table nonmsg not found in schema"
+	require.Error(t, err)
+	require.Contains(t, err.Error(), want)
+
+	db.AddQueryPattern("insert into msg\\(.*", &sqltypes.Result{RowsAffected: 3})
+	db.AddQueryPattern("delete from msg_dlq.*", &sqltypes.Result{RowsAffected: 3})
+	count, err := tsv.RequeueDeadLetters(ctx, "msg", nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, count)
+}
+
 func TestHandleExecUnknownError(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -2209,6 +2296,37 @@ func TestReserveBeginExecute(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestReserveBeginExecuteWaitsBeforeSnapshot(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db, tsv := setupTabletServerTest(t, ctx, "")
+	tsv.config.EnableSettingsPool = false
+	defer tsv.StopService()
+	defer db.Close()
+	target := querypb.Target{TabletType: topodatapb.TabletType_PRIMARY}
+
+	// vtgate snapshot reads wait for the captured GTID set before the
+	// consistent snapshot starts, so the snapshot includes it.
+	waitQuery := "select wait_for_executed_gtid_set('uuid:1-5')"
+	db.AddQuery(waitQuery, &sqltypes.Result{})
+	db.AddQuery("start transaction with consistent snapshot, read only", &sqltypes.Result{})
+	options := &querypb.ExecuteOptions{
+		TransactionAccessMode: []querypb.ExecuteOptions_TransactionAccessMode{querypb.ExecuteOptions_CONSISTENT_SNAPSHOT, querypb.ExecuteOptions_READ_ONLY},
+	}
+	state, _, err := tsv.ReserveBeginExecute(ctx, &target, []string{waitQuery}, nil, "select 42", nil, options)
+	require.NoError(t, err)
+
+	splitOutput := strings.Split(db.QueryLog(), ";")
+	waitIdx := slices.Index(splitOutput, waitQuery)
+	beginIdx := slices.Index(splitOutput, "start transaction with consistent snapshot, read only")
+	require.NotEqual(t, -1, waitIdx, "expected the wait to run: %v", splitOutput)
+	require.NotEqual(t, -1, beginIdx, "expected the snapshot to start: %v", splitOutput)
+	assert.Less(t, waitIdx, beginIdx, "expected the wait to run before the snapshot starts: %v", splitOutput)
+
+	err = tsv.Release(ctx, &target, state.TransactionID, state.ReservedID)
+	require.NoError(t, err)
+}
+
 func TestReserveExecute_WithoutTx(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -2654,6 +2772,8 @@ func addTabletServerSupportedQueries(db *fakesqldb.DB) {
 				{sqltypes.NewVarBinary("1427325875")},
 			},
 		},
+		// query for the fingerprint rules
+		"select fingerprint, action, plan_type, error_message from _vt.query_fingerprint_rules": {},
 		"select @@global.sql_mode": {
 			Fields: []*querypb.Field{{
 				Type: sqltypes.VarChar,
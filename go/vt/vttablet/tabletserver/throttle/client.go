@@ -52,6 +52,10 @@ type Client struct {
 	checkType ThrottleCheckType
 	flags     CheckFlags
 
+	// checkResources indicates the client should also be throttled on local
+	// resource metrics, such as disk usage and write IOPS.
+	checkResources bool
+
 	lastSuccessfulThrottleMu sync.Mutex
 	lastSuccessfulThrottle   int64
 }
@@ -83,6 +87,15 @@ func NewBackgroundClient(throttler *Throttler, appName throttlerapp.Name, checkT
 	}
 }
 
+// NewResourceAwareBackgroundClient creates a background client which, in addition to the regular
+// throttler check, is also throttled when the local disk usage or write IOPS exceed their configured
+// thresholds. This is suitable for jobs that write large amounts of data to this tablet, e.g. vreplication.
+func NewResourceAwareBackgroundClient(throttler *Throttler, appName throttlerapp.Name, checkType ThrottleCheckType) *Client {
+	c := NewBackgroundClient(throttler, appName, checkType)
+	c.checkResources = true
+	return c
+}
+
 // ThrottleCheckOK checks the throttler, and returns 'true' when the throttler is satisfied.
 // It does not sleep.
 // The function caches results for a brief amount of time, hence it's safe and efficient to
@@ -112,6 +125,9 @@ func (c *Client) ThrottleCheckOK(ctx context.Context, overrideAppName throttlera
 	if checkResult.StatusCode != http.StatusOK {
 		return false
 	}
+	if c.checkResources && c.throttler.CheckResources() != nil {
+		return false
+	}
 	c.lastSuccessfulThrottle = atomic.LoadInt64(&throttleTicks)
 	return true
 
@@ -0,0 +1,244 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"vitess.io/vitess/go/stats"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle/base"
+)
+
+const (
+	resourceCollectInterval = time.Second
+
+	dataDirQuery          = "select @@global.datadir as datadir"
+	innodbDataWritesQuery = "show global status like 'Innodb_data_writes'"
+
+	// ResourceDiskUsage is the name of the resource metric that reports the
+	// used ratio (0..1) of the file system holding the MySQL data directory.
+	ResourceDiskUsage = "disk_usage"
+	// ResourceWriteIOPS is the name of the resource metric that reports the
+	// rate of InnoDB data writes per second.
+	ResourceWriteIOPS = "write_iops"
+)
+
+var (
+	// flag vars
+	throttleDiskUsageThreshold float64
+	throttleWriteIOPSThreshold float64
+
+	resourceMetricsGauges = stats.NewGaugesWithSingleLabel("ThrottlerResourceMetrics", "Resource metrics collected by the throttler on this tablet", "Metric")
+)
+
+// resourceMetrics holds the latest local resource readings: disk usage of the MySQL data directory
+// and InnoDB write IOPS. These are consulted by clients that opt in to resource checks, such as
+// VReplication, on top of the regular replication lag (or custom query) based check.
+type resourceMetrics struct {
+	diskUsage atomic.Uint64 // float64 bits
+	writeIOPS atomic.Uint64 // float64 bits
+
+	// collecting is set while a collection is in progress, so that a slow collection (e.g. a hanging
+	// query or file system) does not pile up concurrent collections on each tick.
+	collecting atomic.Bool
+
+	mu             sync.Mutex
+	dataDir        string
+	lastDataWrites int64
+	lastSampleTime time.Time
+}
+
+// resourceThresholds returns the configured disk usage and write IOPS thresholds. A zero value
+// means that the associated check is disabled.
+func resourceThresholds() (diskUsage float64, writeIOPS float64) {
+	return throttleDiskUsageThreshold, throttleWriteIOPSThreshold
+}
+
+// resourceChecksEnabled returns true when at least one resource threshold is configured.
+func resourceChecksEnabled() bool {
+	diskUsage, writeIOPS := resourceThresholds()
+	return diskUsage > 0 || writeIOPS > 0
+}
+
+func (m *resourceMetrics) DiskUsage() float64 {
+	return math.Float64frombits(m.diskUsage.Load())
+}
+
+func (m *resourceMetrics) WriteIOPS() float64 {
+	return math.Float64frombits(m.writeIOPS.Load())
+}
+
+func (m *resourceMetrics) storeDiskUsage(value float64) {
+	m.diskUsage.Store(math.Float64bits(value))
+	resourceMetricsGauges.Set(ResourceDiskUsage, int64(value*100))
+}
+
+func (m *resourceMetrics) storeWriteIOPS(value float64) {
+	m.writeIOPS.Store(math.Float64bits(value))
+	resourceMetricsGauges.Set(ResourceWriteIOPS, int64(value))
+}
+
+// sampleDataWrites records a new reading of the Innodb_data_writes counter and returns the write
+// rate since the previous reading. The boolean is false when there is no previous reading, or the
+// counter went backwards (e.g. MySQL was restarted), in which case no rate can be computed yet.
+func (m *resourceMetrics) sampleDataWrites(dataWrites int64, now time.Time) (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lastDataWrites, lastSampleTime := m.lastDataWrites, m.lastSampleTime
+	m.lastDataWrites, m.lastSampleTime = dataWrites, now
+	if lastSampleTime.IsZero() || dataWrites < lastDataWrites {
+		return 0, false
+	}
+	elapsed := now.Sub(lastSampleTime).Seconds()
+	if elapsed <= 0 {
+		return 0, false
+	}
+	return float64(dataWrites-lastDataWrites) / elapsed, true
+}
+
+// exceededResource returns the name, value, and threshold of the first resource metric that
+// exceeds its configured threshold. The name is empty when all resources are within their limits.
+func (m *resourceMetrics) exceededResource(diskUsageThreshold, writeIOPSThreshold float64) (name string, value float64, threshold float64) {
+	if diskUsageThreshold > 0 {
+		if value := m.DiskUsage(); value >= diskUsageThreshold {
+			return ResourceDiskUsage, value, diskUsageThreshold
+		}
+	}
+	if writeIOPSThreshold > 0 {
+		if value := m.WriteIOPS(); value >= writeIOPSThreshold {
+			return ResourceWriteIOPS, value, writeIOPSThreshold
+		}
+	}
+	return "", 0, 0
+}
+
+// diskUsageRatio returns the used ratio of the file system that holds the given path.
+func diskUsageRatio(path string) (float64, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return 0, err
+	}
+	// Blocks reserved for the super user are not available to MySQL, so we compute the usage
+	// out of the blocks that are available to unprivileged users.
+	used := fs.Blocks - fs.Bfree
+	total := used + fs.Bavail
+	if total == 0 {
+		return 0, nil
+	}
+	return float64(used) / float64(total), nil
+}
+
+// collectResourceMetrics reads the local disk usage and write IOPS and stores them for use by
+// CheckResources. It returns immediately if a previous collection is still in progress.
+func (throttler *Throttler) collectResourceMetrics(ctx context.Context) {
+	if !throttler.resourceMetrics.collecting.CompareAndSwap(false, true) {
+		return
+	}
+	defer throttler.resourceMetrics.collecting.Store(false)
+
+	diskUsageThreshold, writeIOPSThreshold := resourceThresholds()
+	if diskUsageThreshold > 0 {
+		if err := throttler.collectDiskUsage(ctx); err != nil {
+			log.Errorf("Throttler: failed to collect disk usage: %v", err)
+		}
+	}
+	if writeIOPSThreshold > 0 {
+		if err := throttler.collectWriteIOPS(ctx); err != nil {
+			log.Errorf("Throttler: failed to collect write IOPS: %v", err)
+		}
+	}
+}
+
+func (throttler *Throttler) collectDiskUsage(ctx context.Context) error {
+	m := &throttler.resourceMetrics
+	m.mu.Lock()
+	dataDir := m.dataDir
+	m.mu.Unlock()
+	if dataDir == "" {
+		conn, err := throttler.pool.Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer conn.Recycle()
+		qr, err := conn.Conn.Exec(ctx, dataDirQuery, 1, true)
+		if err != nil {
+			return err
+		}
+		row := qr.Named().Row()
+		if row == nil {
+			return fmt.Errorf("no results for %s", dataDirQuery)
+		}
+		dataDir = row.AsString("datadir", "")
+		m.mu.Lock()
+		m.dataDir = dataDir
+		m.mu.Unlock()
+	}
+	usage, err := diskUsageRatio(dataDir)
+	if err != nil {
+		return err
+	}
+	m.storeDiskUsage(usage)
+	return nil
+}
+
+func (throttler *Throttler) collectWriteIOPS(ctx context.Context) error {
+	conn, err := throttler.pool.Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Recycle()
+	qr, err := conn.Conn.Exec(ctx, innodbDataWritesQuery, 1, true)
+	if err != nil {
+		return err
+	}
+	row := qr.Named().Row()
+	if row == nil {
+		return fmt.Errorf("no results for %s", innodbDataWritesQuery)
+	}
+	dataWrites, err := strconv.ParseInt(row["Value"].ToString(), 10, 64)
+	if err != nil {
+		return err
+	}
+	if iops, ok := throttler.resourceMetrics.sampleDataWrites(dataWrites, time.Now()); ok {
+		throttler.resourceMetrics.storeWriteIOPS(iops)
+	}
+	return nil
+}
+
+// CheckResources checks the local disk usage and write IOPS against their configured thresholds.
+// It returns an error describing the exceeded resource, or nil when all resources are within
+// their limits, when no thresholds are configured, or when the throttler is not running.
+func (throttler *Throttler) CheckResources() error {
+	if !throttler.IsRunning() {
+		return nil
+	}
+	diskUsageThreshold, writeIOPSThreshold := resourceThresholds()
+	name, value, threshold := throttler.resourceMetrics.exceededResource(diskUsageThreshold, writeIOPSThreshold)
+	if name == "" {
+		return nil
+	}
+	return fmt.Errorf("%w: %s=%.2f, threshold=%.2f", base.ErrThresholdExceeded, name, value, threshold)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleDataWrites(t *testing.T) {
+	m := &resourceMetrics{}
+	now := time.Now()
+
+	_, ok := m.sampleDataWrites(1000, now)
+	assert.False(t, ok, "first sample has nothing to compare against")
+
+	iops, ok := m.sampleDataWrites(3000, now.Add(2*time.Second))
+	require.True(t, ok)
+	assert.Equal(t, 1000.0, iops)
+
+	_, ok = m.sampleDataWrites(10, now.Add(3*time.Second))
+	assert.False(t, ok, "counter reset should not produce a rate")
+
+	iops, ok = m.sampleDataWrites(510, now.Add(4*time.Second))
+	require.True(t, ok)
+	assert.Equal(t, 500.0, iops)
+}
+
+func TestExceededResource(t *testing.T) {
+	m := &resourceMetrics{}
+	m.storeDiskUsage(0.85)
+	m.storeWriteIOPS(2000)
+
+	tcases := []struct {
+		name               string
+		diskUsageThreshold float64
+		writeIOPSThreshold float64
+		expect             string
+	}{
+		{
+			name: "no thresholds",
+		},
+		{
+			name:               "below thresholds",
+			diskUsageThreshold: 0.9,
+			writeIOPSThreshold: 5000,
+		},
+		{
+			name:               "disk usage exceeded",
+			diskUsageThreshold: 0.8,
+			writeIOPSThreshold: 5000,
+			expect:             ResourceDiskUsage,
+		},
+		{
+			name:               "write iops exceeded",
+			diskUsageThreshold: 0.9,
+			writeIOPSThreshold: 1000,
+			expect:             ResourceWriteIOPS,
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			name, _, _ := m.exceededResource(tcase.diskUsageThreshold, tcase.writeIOPSThreshold)
+			assert.Equal(t, tcase.expect, name)
+		})
+	}
+}
+
+func TestDiskUsageRatio(t *testing.T) {
+	ratio, err := diskUsageRatio(t.TempDir())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, ratio, 0.0)
+	assert.LessOrEqual(t, ratio, 1.0)
+}
+
+func TestCollectResourceMetricsSkipsOverlap(t *testing.T) {
+	defer func(threshold float64) { throttleDiskUsageThreshold = threshold }(throttleDiskUsageThreshold)
+	throttleDiskUsageThreshold = 0.9
+
+	throttler := &Throttler{}
+	throttler.resourceMetrics.dataDir = t.TempDir()
+	throttler.resourceMetrics.storeDiskUsage(-1)
+
+	// A collection is in progress: this tick is skipped.
+	throttler.resourceMetrics.collecting.Store(true)
+	throttler.collectResourceMetrics(context.Background())
+	assert.Equal(t, -1.0, throttler.resourceMetrics.DiskUsage())
+
+	throttler.resourceMetrics.collecting.Store(false)
+	throttler.collectResourceMetrics(context.Background())
+	assert.GreaterOrEqual(t, throttler.resourceMetrics.DiskUsage(), 0.0)
+	assert.False(t, throttler.resourceMetrics.collecting.Load())
+}
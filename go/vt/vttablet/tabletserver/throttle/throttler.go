@@ -117,6 +117,8 @@ func init() {
 
 func registerThrottlerFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&throttleTabletTypes, "throttle_tablet_types", throttleTabletTypes, "Comma separated VTTablet types to be considered by the throttler. default: 'replica'. example: 'replica,rdonly'. 'replica' always implicitly included")
+	fs.Float64Var(&throttleDiskUsageThreshold, "throttle-disk-usage-threshold", throttleDiskUsageThreshold, "Ratio (0..1) of used disk space on the MySQL data directory's file system above which resource aware throttler clients, such as VReplication, are throttled. 0 disables the check.")
+	fs.Float64Var(&throttleWriteIOPSThreshold, "throttle-write-iops-threshold", throttleWriteIOPSThreshold, "Rate of InnoDB data writes per second above which resource aware throttler clients, such as VReplication, are throttled. 0 disables the check.")
 }
 
 var (
@@ -204,6 +206,8 @@ type Throttler struct {
 
 	readSelfThrottleMetric func(context.Context, *mysql.Probe) *mysql.MySQLThrottleMetric // overwritten by unit test
 
+	resourceMetrics resourceMetrics
+
 	nonLowPriorityAppRequestsThrottled *cache.Cache
 	httpClient                         *http.Client
 }
@@ -664,6 +668,7 @@ func (throttler *Throttler) Operate(ctx context.Context, wg *sync.WaitGroup) {
 	mysqlAggregateTicker := addTicker(throttler.mysqlAggregateInterval)
 	throttledAppsTicker := addTicker(throttler.throttledAppsSnapshotInterval)
 	recentCheckTicker := addTicker(time.Second)
+	resourceCollectTicker := addTicker(resourceCollectInterval)
 
 	wg.Add(1)
 	go func() {
@@ -754,6 +759,10 @@ func (throttler *Throttler) Operate(ctx context.Context, wg *sync.WaitGroup) {
 				if throttler.IsOpen() {
 					go throttler.expireThrottledApps()
 				}
+			case <-resourceCollectTicker.C:
+				if throttler.IsOpen() && resourceChecksEnabled() {
+					go throttler.collectResourceMetrics(ctx)
+				}
 			case throttlerConfig := <-throttler.throttlerConfigChan:
 				throttler.applyThrottlerConfig(ctx, throttlerConfig)
 			case <-recentCheckTicker.C:
@@ -19,8 +19,11 @@ package tabletserver
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
+	"golang.org/x/exp/maps"
+
 	"vitess.io/vitess/go/constants/sidecar"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tx"
 
@@ -42,6 +45,10 @@ const (
 	RedoStateFailed = 0
 	// RedoStatePrepared represents the Prepared state for redo_state.
 	RedoStatePrepared = 1
+	// RedoStateCommitting represents the state of a prepared transaction
+	// that was asked to commit, for redo_state. Such a transaction is
+	// committed as soon as it is replayed from the redo log.
+	RedoStateCommitting = 2
 	// DTStatePrepare represents the PREPARE state for dt_state.
 	DTStatePrepare = querypb.TransactionState_PREPARE
 	// DTStateCommit represents the COMMIT state for dt_state.
@@ -81,6 +88,8 @@ type TwoPC struct {
 	readParticipants    *sqlparser.ParsedQuery
 	readAbandoned       *sqlparser.ParsedQuery
 	readAllTransactions string
+
+	insertAudit *sqlparser.ParsedQuery
 }
 
 // NewTwoPC creates a TwoPC variable.
@@ -132,6 +141,10 @@ func NewTwoPC(readPool *connpool.Pool) *TwoPC {
 		"select dtid, time_created from %s.dt_state where time_created < %a",
 		dbname, ":time_created")
 	tpc.readAllTransactions = fmt.Sprintf(sqlReadAllTransactions, dbname, dbname)
+
+	tpc.insertAudit = sqlparser.BuildParsedQuery(
+		"insert into %s.dt_audit(dtid, action, source, message, time_created) values (%a, %a, %a, %a, %a)",
+		dbname, ":dtid", ":action", ":source", ":message", ":time_created")
 	return tpc
 }
 
@@ -236,7 +249,8 @@ func (tpc *TwoPC) ReadAllRedo(ctx context.Context) (prepared, failed []*tx.Prepa
 				log.Errorf("Error parsing state for dtid %s: %v.", dtid, err)
 			}
 			switch st {
-			case RedoStatePrepared:
+			case RedoStatePrepared, RedoStateCommitting:
+				curTx.Committing = st == RedoStateCommitting
 				prepared = append(prepared, curTx)
 			default:
 				if st != RedoStateFailed {
@@ -411,6 +425,43 @@ func (tpc *TwoPC) ReadAbandoned(ctx context.Context, abandonTime time.Time) (map
 	return txs, nil
 }
 
+// ReadUnresolved returns the metadata of the transactions that
+// were created before abandonTime, ordered by dtid.
+func (tpc *TwoPC) ReadUnresolved(ctx context.Context, abandonTime time.Time) ([]*querypb.TransactionMetadata, error) {
+	abandoned, err := tpc.ReadAbandoned(ctx, abandonTime)
+	if err != nil {
+		return nil, err
+	}
+	dtids := maps.Keys(abandoned)
+	slices.Sort(dtids)
+	unresolved := make([]*querypb.TransactionMetadata, 0, len(dtids))
+	for _, dtid := range dtids {
+		transaction, err := tpc.ReadTransaction(ctx, dtid)
+		if err != nil {
+			return nil, err
+		}
+		// The transaction may have been concluded in the meantime.
+		if transaction.Dtid == "" {
+			continue
+		}
+		unresolved = append(unresolved, transaction)
+	}
+	return unresolved, nil
+}
+
+// RecordAudit records an action taken on a distributed transaction.
+func (tpc *TwoPC) RecordAudit(ctx context.Context, conn *StatefulConnection, dtid, action, source, message string) error {
+	bindVars := map[string]*querypb.BindVariable{
+		"dtid":         sqltypes.StringBindVariable(dtid),
+		"action":       sqltypes.StringBindVariable(action),
+		"source":       sqltypes.StringBindVariable(source),
+		"message":      sqltypes.StringBindVariable(message),
+		"time_created": sqltypes.Int64BindVariable(time.Now().UnixNano()),
+	}
+	_, err := tpc.exec(ctx, conn, tpc.insertAudit, bindVars)
+	return err
+}
+
 // ReadAllTransactions returns info about all distributed transactions.
 func (tpc *TwoPC) ReadAllTransactions(ctx context.Context) ([]*tx.DistributedTx, error) {
 	conn, err := tpc.readPool.Get(ctx, nil)
@@ -201,7 +201,7 @@ func TestReadAllRedo(t *testing.T) {
 			sqltypes.NewVarBinary("stmt22"),
 		}, {
 			sqltypes.NewVarBinary("dtid3"),
-			sqltypes.NewInt64(RedoStatePrepared),
+			sqltypes.NewInt64(RedoStateCommitting),
 			sqltypes.NewVarBinary("1"),
 			sqltypes.NewVarBinary("stmt31"),
 		}},
@@ -215,9 +215,10 @@ func TestReadAllRedo(t *testing.T) {
 		Queries: []string{"stmt01", "stmt02"},
 		Time:    time.Unix(0, 1),
 	}, {
-		Dtid:    "dtid3",
-		Queries: []string{"stmt31"},
-		Time:    time.Unix(0, 1),
+		Dtid:       "dtid3",
+		Queries:    []string{"stmt31"},
+		Time:       time.Unix(0, 1),
+		Committing: true,
 	}}
 	if !reflect.DeepEqual(prepared, want) {
 		t.Errorf("ReadAllRedo: %s, want %s", jsonStr(prepared), jsonStr(want))
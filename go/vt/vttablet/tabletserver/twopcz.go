@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/safehtml/template"
 
@@ -146,6 +147,9 @@ func twopczHandler(txe *TxExecutor, w http.ResponseWriter, r *http.Request) {
 	case "Conclude":
 		err = txe.ConcludeTransaction(dtid)
 	}
+	if action != "" && txe.te.twopcEnabled {
+		txe.te.audit(r.Context(), dtid, strings.ToLower(action), "twopcz", err)
+	}
 	var msg string
 	if action != "" {
 		if err != nil {
@@ -36,4 +36,7 @@ type PreparedTx struct {
 	Dtid    string
 	Queries []string
 	Time    time.Time
+	// Committing is set once the decision to commit the transaction
+	// was recorded in the redo log.
+	Committing bool
 }
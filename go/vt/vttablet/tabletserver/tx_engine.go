@@ -81,6 +81,7 @@ type TxEngine struct {
 	shutdownGracePeriod time.Duration
 	coordinatorAddress  string
 	abandonAge          time.Duration
+	resolutionPolicy    string
 	ticks               *timer.Timer
 
 	// reservedConnStats keeps statistics about reserved connections
@@ -115,6 +116,7 @@ func NewTxEngine(env tabletenv.Env) *TxEngine {
 	}
 	te.coordinatorAddress = config.TwoPCCoordinatorAddress
 	te.abandonAge = config.TwoPCAbandonAge.Get()
+	te.resolutionPolicy = config.TwoPCResolutionPolicy
 	te.ticks = timer.NewTimer(te.abandonAge / 2)
 
 	// Set the prepared pool capacity to something lower than
@@ -375,7 +377,10 @@ func (te *TxEngine) shutdownLocked() {
 // prepareFromRedo replays and prepares the transactions
 // from the redo log, loads previously failed transactions
 // into the reserved list, and adjusts the txPool LastID
-// to ensure there are no future collisions.
+// to ensure there are no future collisions. Transactions
+// that cannot be replayed are marked as failed, so that
+// they cannot be reported as committed. The replayed
+// transactions are then resolved by resolveReplayed.
 func (te *TxEngine) prepareFromRedo() error {
 	ctx := tabletenv.LocalContext()
 	var allErr concurrency.AllErrorRecorder
@@ -384,6 +389,7 @@ func (te *TxEngine) prepareFromRedo() error {
 		return err
 	}
 
+	var committing, inDoubt []string
 	maxid := int64(0)
 outer:
 	for _, preparedTx := range prepared {
@@ -397,6 +403,7 @@ outer:
 		conn, _, _, err := te.txPool.Begin(ctx, &querypb.ExecuteOptions{}, false, 0, nil, nil)
 		if err != nil {
 			allErr.RecordError(err)
+			te.preparedPool.SetFailed(preparedTx.Dtid)
 			continue
 		}
 		for _, stmt := range preparedTx.Queries {
@@ -405,6 +412,7 @@ outer:
 			if err != nil {
 				allErr.RecordError(err)
 				te.txPool.RollbackAndRelease(ctx, conn)
+				te.preparedPool.SetFailed(preparedTx.Dtid)
 				continue outer
 			}
 		}
@@ -413,8 +421,15 @@ outer:
 		err = te.preparedPool.Put(conn, preparedTx.Dtid)
 		if err != nil {
 			allErr.RecordError(err)
+			te.txPool.RollbackAndRelease(ctx, conn)
+			te.preparedPool.SetFailed(preparedTx.Dtid)
 			continue
 		}
+		if preparedTx.Committing {
+			committing = append(committing, preparedTx.Dtid)
+		} else {
+			inDoubt = append(inDoubt, preparedTx.Dtid)
+		}
 	}
 	for _, preparedTx := range failed {
 		txid, err := dtids.TransactionID(preparedTx.Dtid)
@@ -428,9 +443,61 @@ outer:
 	}
 	te.txPool.AdjustLastID(maxid)
 	log.Infof("TwoPC: Prepared %d transactions, and registered %d failures.", len(prepared), len(failed))
+	te.resolveReplayed(committing, inDoubt)
 	return allErr.Error()
 }
 
+// resolveReplayed resolves the transactions replayed from the redo log,
+// instead of leaving them prepared until the watchdog of their coordinator
+// finds them abandoned. The transactions whose commit was decided before
+// the restart are committed right away, and the coordinator is asked to
+// resolve the others, unless they must be resolved by an operator.
+func (te *TxEngine) resolveReplayed(committing, inDoubt []string) {
+	ctx, cancel := context.WithTimeout(tabletenv.LocalContext(), te.abandonAge/4)
+	defer cancel()
+
+	txe := &TxExecutor{
+		ctx:      tabletenv.LocalContext(),
+		logStats: tabletenv.NewLogStats(ctx, "TwoPCRecovery"),
+		te:       te,
+	}
+	for _, dtid := range committing {
+		err := txe.CommitPrepared(dtid)
+		te.audit(ctx, dtid, "commit", "Recovery", err)
+		if err != nil {
+			log.Errorf("Could not commit replayed transaction %s: %v", dtid, err)
+		}
+	}
+
+	if len(inDoubt) == 0 {
+		return
+	}
+	if te.resolutionPolicy == tabletenv.TwoPCResolveManually {
+		log.Warningf("TwoPC: %d replayed transactions are waiting to be resolved by an operator", len(inDoubt))
+		return
+	}
+	coordConn, err := vtgateconn.Dial(ctx, te.coordinatorAddress)
+	if err != nil {
+		te.env.Stats().InternalErrors.Add("TwopcResurrection", 1)
+		log.Errorf("Error connecting to coordinator '%v': %v", te.coordinatorAddress, err)
+		return
+	}
+	defer coordConn.Close()
+
+	var wg sync.WaitGroup
+	for _, dtid := range inDoubt {
+		wg.Add(1)
+		go func(dtid string) {
+			defer wg.Done()
+			if err := te.resolve(ctx, coordConn, dtid, "Recovery"); err != nil {
+				te.env.Stats().InternalErrors.Add("TwopcResurrection", 1)
+				log.Errorf("Error resolving replayed transaction %s: %v", dtid, err)
+			}
+		}(dtid)
+	}
+	wg.Wait()
+}
+
 // shutdownTransactions rolls back all open transactions
 // including the prepared ones.
 // This is used for transitioning from a primary to a non-primary
@@ -468,6 +535,7 @@ func (te *TxEngine) startWatchdog() {
 			log.Errorf("Error reading unresolved prepares: '%v': %v", te.coordinatorAddress, err)
 		}
 		te.env.Stats().Unresolved.Set("Prepares", count)
+		te.env.Stats().Unresolved.Set("Failed", int64(te.preparedPool.CountFailed()))
 
 		// Resolve lingering distributed transactions.
 		txs, err := te.twoPC.ReadAbandoned(ctx, time.Now().Add(-te.abandonAge))
@@ -476,9 +544,14 @@ func (te *TxEngine) startWatchdog() {
 			log.Errorf("Error reading transactions for 2pc watchdog: %v", err)
 			return
 		}
+		te.env.Stats().Unresolved.Set("Abandoned", int64(len(txs)))
 		if len(txs) == 0 {
 			return
 		}
+		if te.resolutionPolicy == tabletenv.TwoPCResolveManually {
+			log.Warningf("2pc watchdog: %d abandoned transactions are waiting to be resolved by an operator", len(txs))
+			return
+		}
 
 		coordConn, err := vtgateconn.Dial(ctx, te.coordinatorAddress)
 		if err != nil {
@@ -493,7 +566,7 @@ func (te *TxEngine) startWatchdog() {
 			wg.Add(1)
 			go func(dtid string) {
 				defer wg.Done()
-				if err := coordConn.ResolveTransaction(ctx, dtid); err != nil {
+				if err := te.resolve(ctx, coordConn, dtid, "Watchdog"); err != nil {
 					te.env.Stats().InternalErrors.Add("WatchdogFail", 1)
 					log.Errorf("Error notifying for dtid %s: %v", dtid, err)
 				}
@@ -503,6 +576,62 @@ func (te *TxEngine) startWatchdog() {
 	})
 }
 
+// UnresolvedTransactions returns the distributed transactions managed by
+// this tablet that were created more than abandonAge ago.
+func (te *TxEngine) UnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error) {
+	if !te.twopcEnabled {
+		return nil, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "2pc is not enabled")
+	}
+	return te.twoPC.ReadUnresolved(ctx, time.Now().Add(-abandonAge))
+}
+
+// ResolveTransaction asks the coordinator to resolve the distributed
+// transaction right away, regardless of its age and of the resolution policy.
+func (te *TxEngine) ResolveTransaction(ctx context.Context, dtid string) error {
+	if !te.twopcEnabled {
+		return vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "2pc is not enabled")
+	}
+	coordConn, err := vtgateconn.Dial(ctx, te.coordinatorAddress)
+	if err != nil {
+		return vterrors.Wrapf(err, "error connecting to coordinator '%v'", te.coordinatorAddress)
+	}
+	defer coordConn.Close()
+	return te.resolve(ctx, coordConn, dtid, "Operator")
+}
+
+// resolve asks the coordinator to resolve the distributed transaction and
+// records the outcome in the audit log.
+func (te *TxEngine) resolve(ctx context.Context, coordConn *vtgateconn.VTGateConn, dtid, source string) error {
+	te.env.Stats().TwopcResolutions.Add(source, 1)
+	err := coordConn.ResolveTransaction(ctx, dtid)
+	te.audit(ctx, dtid, "resolve", source, err)
+	return err
+}
+
+// audit records an action taken on a distributed transaction and its outcome
+// in the dt_audit table. Failing to do so is only logged, so that auditing
+// never gets in the way of resolving transactions.
+func (te *TxEngine) audit(ctx context.Context, dtid, action, source string, actionErr error) {
+	message := "success"
+	if actionErr != nil {
+		message = actionErr.Error()
+	}
+	conn, _, _, err := te.txPool.Begin(ctx, &querypb.ExecuteOptions{}, false, 0, nil, nil)
+	if err != nil {
+		log.Errorf("audit: Begin failed for dtid %s: %v", dtid, err)
+		return
+	}
+	defer te.txPool.RollbackAndRelease(ctx, conn)
+
+	if err = te.twoPC.RecordAudit(ctx, conn, dtid, action, source, message); err != nil {
+		log.Errorf("audit: RecordAudit failed for dtid %s: %v", dtid, err)
+		return
+	}
+	if _, err = te.txPool.Commit(ctx, conn); err != nil {
+		log.Errorf("audit: Commit failed for dtid %s: %v", dtid, err)
+	}
+}
+
 // stopWatchdog stops the watchdog goroutine.
 func (te *TxEngine) stopWatchdog() {
 	te.ticks.Stop()
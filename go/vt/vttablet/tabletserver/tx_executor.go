@@ -68,10 +68,14 @@ func (txe *TxExecutor) Prepare(transactionID int64, dtid string) error {
 		return vterrors.Errorf(vtrpcpb.Code_RESOURCE_EXHAUSTED, "prepare failed for transaction %d: %v", transactionID, err)
 	}
 
-	return txe.inTransaction(func(localConn *StatefulConnection) error {
+	err = txe.inTransaction(func(localConn *StatefulConnection) error {
 		return txe.te.twoPC.SaveRedo(txe.ctx, localConn, dtid, conn.TxProperties().Queries)
 	})
-
+	if err != nil {
+		return err
+	}
+	txe.te.env.Stats().TwopcTransactions.Add("Prepared", 1)
+	return nil
 }
 
 // CommitPrepared commits a prepared transaction. If the operation
@@ -93,6 +97,17 @@ func (txe *TxExecutor) CommitPrepared(dtid string) error {
 	// even if the original context expires.
 	ctx := trace.CopySpan(context.Background(), txe.ctx)
 	defer txe.te.txPool.RollbackAndRelease(ctx, conn)
+	// Record the decision first, so that the transaction gets committed
+	// when it is replayed if the tablet goes down before the commit.
+	// Failing to do so only delays the resolution of the transaction
+	// in that case.
+	err = txe.inTransaction(func(localConn *StatefulConnection) error {
+		return txe.te.twoPC.UpdateRedo(ctx, localConn, dtid, RedoStateCommitting)
+	})
+	if err != nil {
+		txe.te.env.Stats().InternalErrors.Add("TwopcJournal", 1)
+		log.Errorf("CommitPrepared: could not record the commit decision for dtid %s: %v", dtid, err)
+	}
 	err = txe.te.twoPC.DeleteRedo(ctx, conn, dtid)
 	if err != nil {
 		txe.markFailed(ctx, dtid)
@@ -104,6 +119,7 @@ func (txe *TxExecutor) CommitPrepared(dtid string) error {
 		return err
 	}
 	txe.te.preparedPool.Forget(dtid)
+	txe.te.env.Stats().TwopcTransactions.Add("Committed", 1)
 	return nil
 }
 
@@ -116,6 +132,7 @@ func (txe *TxExecutor) CommitPrepared(dtid string) error {
 // instead of TxExecutor's context.
 func (txe *TxExecutor) markFailed(ctx context.Context, dtid string) {
 	txe.te.env.Stats().InternalErrors.Add("TwopcCommit", 1)
+	txe.te.env.Stats().TwopcTransactions.Add("Failed", 1)
 	txe.te.preparedPool.SetFailed(dtid)
 	conn, _, _, err := txe.te.txPool.Begin(ctx, &querypb.ExecuteOptions{}, false, 0, nil, nil)
 	if err != nil {
@@ -165,9 +182,14 @@ func (txe *TxExecutor) RollbackPrepared(dtid string, originalID int64) error {
 			txe.te.Rollback(txe.ctx, originalID)
 		}
 	}()
-	return txe.inTransaction(func(conn *StatefulConnection) error {
+	err := txe.inTransaction(func(conn *StatefulConnection) error {
 		return txe.te.twoPC.DeleteRedo(txe.ctx, conn, dtid)
 	})
+	if err != nil {
+		return err
+	}
+	txe.te.env.Stats().TwopcTransactions.Add("RolledBack", 1)
+	return nil
 }
 
 // CreateTransaction creates the metadata for a 2PC transaction.
@@ -230,9 +252,14 @@ func (txe *TxExecutor) ConcludeTransaction(dtid string) error {
 	}
 	defer txe.te.env.Stats().QueryTimings.Record("RESOLVE", time.Now())
 
-	return txe.inTransaction(func(conn *StatefulConnection) error {
+	err := txe.inTransaction(func(conn *StatefulConnection) error {
 		return txe.te.twoPC.DeleteTransaction(txe.ctx, conn, dtid)
 	})
+	if err != nil {
+		return err
+	}
+	txe.te.env.Stats().TwopcTransactions.Add("Concluded", 1)
+	return nil
 }
 
 // ReadTransaction returns the metadata for the specified dtid.
@@ -31,6 +31,7 @@ import (
 
 	"vitess.io/vitess/go/mysql/fakesqldb"
 	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/test/utils"
 	"vitess.io/vitess/go/vt/vtgate/fakerpcvtgateconn"
 	"vitess.io/vitess/go/vt/vtgate/vtgateconn"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -463,6 +464,7 @@ func TestExecutorResolveTransaction(t *testing.T) {
 	defer db.Close()
 	defer tsv.StopService()
 	want := "aa"
+	db.AddQueryPattern("insert into _vt\\.dt_audit\\(dtid, action, source, message, time_created\\) values \\('aa', 'resolve', 'Watchdog', 'success',.*", &sqltypes.Result{})
 	db.AddQueryPattern(
 		"select dtid, time_created from _vt\\.dt_state where time_created.*",
 		&sqltypes.Result{
@@ -481,6 +483,68 @@ func TestExecutorResolveTransaction(t *testing.T) {
 	}
 }
 
+func TestExecutorUnresolvedTransactions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, tsv, db := newTestTxExecutor(t, ctx)
+	defer db.Close()
+	defer tsv.StopService()
+
+	db.AddQueryPattern(
+		"select dtid, time_created from _vt\\.dt_state where time_created.*",
+		&sqltypes.Result{
+			Fields: []*querypb.Field{
+				{Type: sqltypes.VarChar},
+				{Type: sqltypes.Int64},
+			},
+			Rows: [][]sqltypes.Value{{
+				sqltypes.NewVarBinary("bb"),
+				sqltypes.NewVarBinary("1"),
+			}, {
+				sqltypes.NewVarBinary("aa"),
+				sqltypes.NewVarBinary("1"),
+			}},
+		})
+	db.AddQuery("select dtid, state, time_created from _vt.dt_state where dtid = 'aa'", &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Type: sqltypes.VarChar},
+			{Type: sqltypes.Int64},
+			{Type: sqltypes.Int64},
+		},
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewVarBinary("aa"),
+			sqltypes.NewInt64(int64(querypb.TransactionState_PREPARE)),
+			sqltypes.NewVarBinary("1"),
+		}},
+	})
+	db.AddQuery("select keyspace, shard from _vt.dt_participant where dtid = 'aa'", &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Type: sqltypes.VarChar},
+			{Type: sqltypes.VarChar},
+		},
+		Rows: [][]sqltypes.Value{{
+			sqltypes.NewVarBinary("test1"),
+			sqltypes.NewVarBinary("0"),
+		}},
+	})
+	// bb was concluded after it was found to be abandoned.
+	db.AddQuery("select dtid, state, time_created from _vt.dt_state where dtid = 'bb'", &sqltypes.Result{})
+
+	got, err := tsv.UnresolvedTransactions(ctx, 30*time.Second)
+	require.NoError(t, err)
+	want := []*querypb.TransactionMetadata{{
+		Dtid:        "aa",
+		State:       querypb.TransactionState_PREPARE,
+		TimeCreated: 1,
+		Participants: []*querypb.Target{{
+			Keyspace:   "test1",
+			Shard:      "0",
+			TabletType: topodatapb.TabletType_PRIMARY,
+		}},
+	}}
+	utils.MustMatch(t, want, got)
+}
+
 func TestNoTwopc(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -524,6 +588,15 @@ func TestNoTwopc(t *testing.T) {
 			_, _, _, err := txe.ReadTwopcInflight()
 			return err
 		},
+	}, {
+		desc: "UnresolvedTransactions",
+		fun: func() error {
+			_, err := tsv.UnresolvedTransactions(ctx, time.Minute)
+			return err
+		},
+	}, {
+		desc: "ResolveTransaction",
+		fun:  func() error { return tsv.ResolveTransaction(ctx, "aa") },
 	}}
 
 	want := "2pc is not enabled"
@@ -539,6 +612,7 @@ func newTestTxExecutor(t *testing.T, ctx context.Context) (txe *TxExecutor, tsv
 	tsv = newTestTabletServer(ctx, smallTxPool, db)
 	db.AddQueryPattern("insert into _vt\\.redo_state\\(dtid, state, time_created\\) values \\('aa', 1,.*", &sqltypes.Result{})
 	db.AddQueryPattern("insert into _vt\\.redo_statement.*", &sqltypes.Result{})
+	db.AddQuery("update _vt.redo_state set state = 2 where dtid = 'aa'", &sqltypes.Result{})
 	db.AddQuery("delete from _vt.redo_state where dtid = 'aa'", &sqltypes.Result{})
 	db.AddQuery("delete from _vt.redo_statement where dtid = 'aa'", &sqltypes.Result{})
 	db.AddQuery("update test_table set `name` = 2 where pk = 1 limit 10001", &sqltypes.Result{})
@@ -556,6 +630,7 @@ func newShortAgeExecutor(t *testing.T, ctx context.Context) (txe *TxExecutor, ts
 	tsv = newTestTabletServer(ctx, smallTxPool|shortTwopcAge, db)
 	db.AddQueryPattern("insert into _vt\\.redo_state\\(dtid, state, time_created\\) values \\('aa', 1,.*", &sqltypes.Result{})
 	db.AddQueryPattern("insert into _vt\\.redo_statement.*", &sqltypes.Result{})
+	db.AddQuery("update _vt.redo_state set state = 2 where dtid = 'aa'", &sqltypes.Result{})
 	db.AddQuery("delete from _vt.redo_state where dtid = 'aa'", &sqltypes.Result{})
 	db.AddQuery("delete from _vt.redo_statement where dtid = 'aa'", &sqltypes.Result{})
 	db.AddQuery("update test_table set `name` = 2 where pk = 1 limit 10001", &sqltypes.Result{})
@@ -40,9 +40,11 @@ import (
 )
 
 const (
-	txLogInterval  = 1 * time.Minute
-	beginWithCSRO  = "start transaction with consistent snapshot, read only"
-	trackGtidQuery = "set session session_track_gtids = START_GTID"
+	txLogInterval      = 1 * time.Minute
+	beginWithCSRO      = "start transaction with consistent snapshot, read only"
+	trackGtidQuery     = "set session session_track_gtids = START_GTID"
+	trackAllGtidsQuery = "set session session_track_gtids = ALL_GTIDS"
+	untrackGtidsQuery  = "set session session_track_gtids = OFF"
 )
 
 var txIsolations = map[querypb.ExecuteOptions_TransactionIsolation]string{
@@ -121,6 +121,19 @@ func (pp *TxPreparedPool) Forget(dtid string) {
 	delete(pp.reserved, dtid)
 }
 
+// CountFailed returns the number of dtids whose commit failed.
+func (pp *TxPreparedPool) CountFailed() int {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	count := 0
+	for _, err := range pp.reserved {
+		if err == errPrepFailed {
+			count++
+		}
+	}
+	return count
+}
+
 // FetchAll removes all connections and returns them as a list.
 // It also forgets all reserved dtids.
 func (pp *TxPreparedPool) FetchAll() []*StatefulConnection {
@@ -116,6 +116,21 @@ func TestPrepFetchForCommit(t *testing.T) {
 	}
 }
 
+func TestPrepCountFailed(t *testing.T) {
+	pp := NewTxPreparedPool(2)
+	conn := &StatefulConnection{}
+	pp.Put(conn, "aa")
+	pp.SetFailed("bb")
+	pp.SetFailed("cc")
+	if got := pp.CountFailed(); got != 2 {
+		t.Errorf("CountFailed: %d, want 2", got)
+	}
+	pp.Forget("bb")
+	if got := pp.CountFailed(); got != 1 {
+		t.Errorf("CountFailed: %d, want 1", got)
+	}
+}
+
 func TestPrepFetchAll(t *testing.T) {
 	pp := NewTxPreparedPool(2)
 	conn1 := &StatefulConnection{}
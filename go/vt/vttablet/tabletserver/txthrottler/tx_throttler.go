@@ -103,6 +103,8 @@ func fetchKnownCells(ctx context.Context, topoServer *topo.Server, target *query
 // txThrottler implements TxThrottle for throttling transactions based on replication lag.
 // It's a thin wrapper around the throttler found in vitess/go/vt/throttler.
 // It uses a discovery.HealthCheck to send replication-lag updates to the wrapped throttler.
+// With --tx-throttler-version=2, it instead throttles the ratio of the transactions computed
+// by a controller from the replication lag of all the replicas of the shard (see txThrottlerStateV2).
 //
 // Intended Usage:
 //
@@ -143,6 +145,13 @@ type txThrottler struct {
 	healthChecksRecordedTotal *stats.CountersWithMultiLabels
 	requestsTotal             *stats.CountersWithSingleLabel
 	requestsThrottled         *stats.CountersWithSingleLabel
+	// requestsThrottledRates are the rates of the requests throttled per
+	// workload while the version 2 of the throttler is open.
+	requestsThrottledRates atomic.Pointer[stats.Rates]
+
+	// stats of the version 2 of the throttler
+	shardMaxLag     *stats.Gauge
+	throttlePercent *stats.Gauge
 }
 
 type txThrottlerState interface {
@@ -151,6 +160,18 @@ type txThrottlerState interface {
 	throttle() bool
 }
 
+// healthCheckStream streams the health of the tablets of the watched cells
+// from a discovery.HealthCheck. When the cells are fetched from the topology,
+// the stream is restarted whenever they change.
+type healthCheckStream struct {
+	stopHealthCheck context.CancelFunc
+
+	healthCheck      discovery.HealthCheck
+	healthCheckChan  chan *discovery.TabletHealth
+	healthCheckCells []string
+	cellsFromTopo    bool
+}
+
 // txThrottlerStateImpl holds the state of an open TxThrottler object.
 type txThrottlerStateImpl struct {
 	config      *tabletenv.TabletConfig
@@ -158,14 +179,10 @@ type txThrottlerStateImpl struct {
 
 	// throttleMu serializes calls to throttler.Throttler.Throttle(threadId).
 	// That method is required to be called in serial for each threadId.
-	throttleMu      sync.Mutex
-	throttler       ThrottlerInterface
-	stopHealthCheck context.CancelFunc
+	throttleMu sync.Mutex
+	throttler  ThrottlerInterface
 
-	healthCheck      discovery.HealthCheck
-	healthCheckChan  chan *discovery.TabletHealth
-	healthCheckCells []string
-	cellsFromTopo    bool
+	healthCheckStream
 
 	// tabletTypes stores the tablet types for throttling
 	tabletTypes map[topodatapb.TabletType]bool
@@ -180,7 +197,11 @@ type txThrottlerStateImpl struct {
 func NewTxThrottler(env tabletenv.Env, topoServer *topo.Server) TxThrottler {
 	config := env.Config()
 	if config.EnableTxThrottler {
-		if len(config.TxThrottlerHealthCheckCells) == 0 {
+		if config.TxThrottlerVersion == 2 {
+			defer log.Infof("Initialized transaction throttler version 2 using lagCeiling: %s, healthCheckCells: %+v",
+				config.TxThrottlerLagCeiling, config.TxThrottlerHealthCheckCells,
+			)
+		} else if len(config.TxThrottlerHealthCheckCells) == 0 {
 			defer log.Infof("Initialized transaction throttler using tabletTypes: %+v, cellsFromTopo: true, topoRefreshInterval: %s, throttlerConfig: %q",
 				config.TxThrottlerTabletTypes, config.TxThrottlerTopoRefreshInterval, config.TxThrottlerConfig.Get(),
 			)
@@ -191,7 +212,7 @@ func NewTxThrottler(env tabletenv.Env, topoServer *topo.Server) TxThrottler {
 		}
 	}
 
-	return &txThrottler{
+	t := &txThrottler{
 		config:           config,
 		topoServer:       topoServer,
 		throttlerRunning: env.Exporter().NewGauge(TxThrottlerName+"Running", "transaction throttler running state"),
@@ -201,7 +222,20 @@ func NewTxThrottler(env tabletenv.Env, topoServer *topo.Server) TxThrottler {
 			[]string{"cell", "DbType"}),
 		requestsTotal:     env.Exporter().NewCountersWithSingleLabel(TxThrottlerName+"Requests", "transaction throttler requests", "workload"),
 		requestsThrottled: env.Exporter().NewCountersWithSingleLabel(TxThrottlerName+"Throttled", "transaction throttler requests throttled", "workload"),
+		shardMaxLag:       env.Exporter().NewGauge(TxThrottlerName+"ShardMaxLagSeconds", "maximum replication lag of the replicas of the shard seen by the transaction throttler"),
+		throttlePercent:   env.Exporter().NewGauge(TxThrottlerName+"ThrottlePercent", "percentage of the transactions the transaction throttler throttles"),
 	}
+	if config.EnableTxThrottler && config.TxThrottlerVersion == 2 {
+		// The rates sample the counters in a goroutine, which only runs while
+		// the throttler is open.
+		env.Exporter().Publish(TxThrottlerName+"ThrottledRates", stats.NewRateFunc("", "transaction throttler requests throttled per second", func() map[string][]float64 {
+			if rates := t.requestsThrottledRates.Load(); rates != nil {
+				return rates.Get()
+			}
+			return nil
+		}))
+	}
+	return t
 }
 
 // InitDBConfig initializes the target parameters for the throttler.
@@ -217,7 +251,13 @@ func (t *txThrottler) Open() (err error) {
 	}
 	log.Info("txThrottler: opening")
 	t.throttlerRunning.Set(1)
-	t.state, err = newTxThrottlerState(t, t.config, t.target)
+	if t.config.TxThrottlerVersion == 2 {
+		// The rates of the requests throttled per workload, over the last 15 minutes.
+		t.requestsThrottledRates.Store(stats.NewRates("", t.requestsThrottled, 15*60/5, 5*time.Second))
+		t.state, err = newTxThrottlerStateV2(t, t.config, t.target)
+	} else {
+		t.state, err = newTxThrottlerState(t, t.config, t.target)
+	}
 	return err
 }
 
@@ -233,6 +273,9 @@ func (t *txThrottler) Close() {
 	}
 	t.state.deallocateResources()
 	t.state = nil
+	if rates := t.requestsThrottledRates.Swap(nil); rates != nil {
+		rates.Stop()
+	}
 	t.throttlerRunning.Set(0)
 	log.Info("txThrottler: closed")
 }
@@ -285,39 +328,47 @@ func newTxThrottlerState(txThrottler *txThrottler, config *tabletenv.TabletConfi
 	}
 
 	state := &txThrottlerStateImpl{
-		config:           config,
-		healthCheckCells: config.TxThrottlerHealthCheckCells,
-		tabletTypes:      tabletTypes,
-		throttler:        t,
-		txThrottler:      txThrottler,
-		done:             make(chan bool, 1),
+		config:      config,
+		tabletTypes: tabletTypes,
+		throttler:   t,
+		txThrottler: txThrottler,
+		done:        make(chan bool, 1),
 	}
 
+	state.start(config, txThrottler.topoServer, target, state.StatsUpdate)
+	state.waitForTermination.Add(1)
+	go state.updateMaxLag()
+
+	return state, nil
+}
+
+// start starts streaming the health of the tablets of the cells configured
+// with --tx-throttler-healthcheck-cells, or of all the cells of the topology
+// if none is configured, to statsUpdate.
+func (ts *healthCheckStream) start(config *tabletenv.TabletConfig, topoServer *topo.Server, target *querypb.Target, statsUpdate func(*discovery.TabletHealth)) {
+	ts.healthCheckCells = config.TxThrottlerHealthCheckCells
+
 	// get cells from topo if none defined in tabletenv config
-	if len(state.healthCheckCells) == 0 {
+	if len(ts.healthCheckCells) == 0 {
 		ctx, cancel := context.WithTimeout(context.Background(), topo.RemoteOperationTimeout)
 		defer cancel()
-		state.healthCheckCells = fetchKnownCells(ctx, txThrottler.topoServer, target)
-		state.cellsFromTopo = true
+		ts.healthCheckCells = fetchKnownCells(ctx, topoServer, target)
+		ts.cellsFromTopo = true
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
-	state.stopHealthCheck = cancel
-	state.initHealthCheckStream(txThrottler.topoServer, target)
-	go state.healthChecksProcessor(ctx, txThrottler.topoServer, target)
-	state.waitForTermination.Add(1)
-	go state.updateMaxLag()
-
-	return state, nil
+	ts.stopHealthCheck = cancel
+	ts.initHealthCheckStream(topoServer, target)
+	go ts.healthChecksProcessor(ctx, topoServer, target, config.TxThrottlerTopoRefreshInterval, statsUpdate)
 }
 
-func (ts *txThrottlerStateImpl) initHealthCheckStream(topoServer *topo.Server, target *querypb.Target) {
+func (ts *healthCheckStream) initHealthCheckStream(topoServer *topo.Server, target *querypb.Target) {
 	ts.healthCheck = healthCheckFactory(topoServer, target.Cell, ts.healthCheckCells)
 	ts.healthCheckChan = ts.healthCheck.Subscribe()
 
 }
 
-func (ts *txThrottlerStateImpl) closeHealthCheckStream() {
+func (ts *healthCheckStream) closeHealthCheckStream() {
 	if ts.healthCheck == nil {
 		return
 	}
@@ -325,7 +376,7 @@ func (ts *txThrottlerStateImpl) closeHealthCheckStream() {
 	ts.healthCheck.Close()
 }
 
-func (ts *txThrottlerStateImpl) updateHealthCheckCells(ctx context.Context, topoServer *topo.Server, target *querypb.Target) {
+func (ts *healthCheckStream) updateHealthCheckCells(ctx context.Context, topoServer *topo.Server, target *querypb.Target) {
 	fetchCtx, cancel := context.WithTimeout(ctx, topo.RemoteOperationTimeout)
 	defer cancel()
 
@@ -338,10 +389,10 @@ func (ts *txThrottlerStateImpl) updateHealthCheckCells(ctx context.Context, topo
 	}
 }
 
-func (ts *txThrottlerStateImpl) healthChecksProcessor(ctx context.Context, topoServer *topo.Server, target *querypb.Target) {
+func (ts *healthCheckStream) healthChecksProcessor(ctx context.Context, topoServer *topo.Server, target *querypb.Target, topoRefreshInterval time.Duration, statsUpdate func(*discovery.TabletHealth)) {
 	var cellsUpdateTicks <-chan time.Time
 	if ts.cellsFromTopo {
-		ticker := time.NewTicker(ts.config.TxThrottlerTopoRefreshInterval)
+		ticker := time.NewTicker(topoRefreshInterval)
 		cellsUpdateTicks = ticker.C
 		defer ticker.Stop()
 	}
@@ -352,7 +403,7 @@ func (ts *txThrottlerStateImpl) healthChecksProcessor(ctx context.Context, topoS
 		case <-cellsUpdateTicks:
 			ts.updateHealthCheckCells(ctx, topoServer, target)
 		case th := <-ts.healthCheckChan:
-			ts.StatsUpdate(th)
+			statsUpdate(th)
 		}
 	}
 }
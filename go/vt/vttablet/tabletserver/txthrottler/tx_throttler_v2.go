@@ -0,0 +1,223 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package txthrottler
+
+import (
+	"math"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/topo/topoproto"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+var (
+	// controllerInterval is the interval at which the throttle ratio is
+	// updated from the replication lag of the shard.
+	controllerInterval = 250 * time.Millisecond
+
+	// replicaLagTTL is the duration after which the lag reported by a
+	// replica is no longer taken into account.
+	replicaLagTTL = time.Minute
+)
+
+// The gains of the lag controller. The errors are relative to the lag
+// ceiling: a lag of twice the ceiling immediately throttles half of the
+// transactions, and all of them after a couple of seconds.
+const (
+	lagControllerKp = 0.5
+	lagControllerKi = 0.2
+	lagControllerKd = 0.1
+)
+
+// ignoredTabletTypes are the types of the tablets that stop replicating on
+// purpose, whose lag must not throttle the transactions.
+var ignoredTabletTypes = map[topodatapb.TabletType]bool{
+	topodatapb.TabletType_PRIMARY: true,
+	topodatapb.TabletType_BACKUP:  true,
+	topodatapb.TabletType_RESTORE: true,
+	topodatapb.TabletType_DRAINED: true,
+}
+
+// lagController is a PID controller computing the ratio of the transactions
+// to throttle to keep the replication lag under a ceiling.
+type lagController struct {
+	ceiling float64
+
+	integral  float64
+	prevError float64
+	hasPrev   bool
+}
+
+// update returns the ratio of the transactions to throttle, between 0 and 1,
+// for the replication lag measured dt after the previous update.
+func (c *lagController) update(lag time.Duration, dt time.Duration) float64 {
+	err := (lag.Seconds() - c.ceiling) / c.ceiling
+
+	// The integral is bounded so that it alone never throttles more than all
+	// the transactions, and unwinds quickly once the lag is under control.
+	c.integral = min(max(c.integral+err*dt.Seconds(), 0), 1/lagControllerKi)
+
+	var derivative float64
+	if c.hasPrev && dt > 0 {
+		derivative = (err - c.prevError) / dt.Seconds()
+	}
+	c.prevError, c.hasPrev = err, true
+
+	return min(max(lagControllerKp*err+lagControllerKi*c.integral+lagControllerKd*derivative, 0), 1)
+}
+
+// replicaLag is the last replication lag reported by a replica.
+type replicaLag struct {
+	lag     time.Duration
+	updated time.Time
+}
+
+// txThrottlerStateV2 holds the state of an open TxThrottler object with the
+// version 2 of the throttler. It aggregates the replication lag of all the
+// replicas of the shard, and throttles a ratio of the transactions computed by
+// a lagController to keep the maximum lag under --tx-throttler-lag-ceiling.
+type txThrottlerStateV2 struct {
+	config      *tabletenv.TabletConfig
+	txThrottler *txThrottler
+	target      *querypb.Target
+
+	healthCheckStream
+
+	mu          sync.Mutex
+	replicaLags map[string]replicaLag
+	controller  lagController
+	lastUpdate  time.Time
+
+	// ratio holds the bits of the float64 ratio of the transactions to throttle.
+	ratio atomic.Uint64
+
+	done               chan struct{}
+	waitForTermination sync.WaitGroup
+}
+
+func newTxThrottlerStateV2(txThrottler *txThrottler, config *tabletenv.TabletConfig, target *querypb.Target) (txThrottlerState, error) {
+	state := &txThrottlerStateV2{
+		config:      config,
+		txThrottler: txThrottler,
+		target:      target,
+		replicaLags: make(map[string]replicaLag),
+		controller:  lagController{ceiling: config.TxThrottlerLagCeiling.Seconds()},
+		lastUpdate:  time.Now(),
+		done:        make(chan struct{}),
+	}
+
+	state.start(config, txThrottler.topoServer, target, state.StatsUpdate)
+	state.waitForTermination.Add(1)
+	go state.runController()
+
+	return state, nil
+}
+
+func (ts *txThrottlerStateV2) runController() {
+	defer ts.waitForTermination.Done()
+	ticker := time.NewTicker(controllerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			ts.updateThrottleRatio(now)
+		case <-ts.done:
+			return
+		}
+	}
+}
+
+// updateThrottleRatio updates the ratio of the transactions to throttle from
+// the maximum replication lag of the shard.
+func (ts *txThrottlerStateV2) updateThrottleRatio(now time.Time) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var maxLag time.Duration
+	for alias, replica := range ts.replicaLags {
+		if now.Sub(replica.updated) > replicaLagTTL {
+			delete(ts.replicaLags, alias)
+			continue
+		}
+		maxLag = max(maxLag, replica.lag)
+	}
+
+	ratio := ts.controller.update(maxLag, now.Sub(ts.lastUpdate))
+	ts.lastUpdate = now
+	ts.ratio.Store(math.Float64bits(ratio))
+
+	ts.txThrottler.shardMaxLag.Set(int64(maxLag.Seconds()))
+	ts.txThrottler.throttlePercent.Set(int64(math.Round(ratio * 100)))
+}
+
+func (ts *txThrottlerStateV2) throttle() bool {
+	ratio := math.Float64frombits(ts.ratio.Load())
+	return ratio > 0 && rand.Float64() < ratio
+}
+
+func (ts *txThrottlerStateV2) deallocateResources() {
+	// Close healthcheck and topo watchers
+	ts.closeHealthCheckStream()
+	ts.healthCheck = nil
+
+	close(ts.done)
+	ts.waitForTermination.Wait()
+	ts.txThrottler.throttlePercent.Set(0)
+}
+
+// StatsUpdate records the replication lag of the replicas of the shard.
+func (ts *txThrottlerStateV2) StatsUpdate(tabletStats *discovery.TabletHealth) {
+	if tabletStats.Target == nil {
+		return
+	}
+	tabletType := tabletStats.Target.TabletType
+	metricLabels := []string{tabletStats.Target.Cell, tabletType.String()}
+	ts.txThrottler.healthChecksReadTotal.Add(metricLabels, 1)
+
+	if tabletStats.Target.Keyspace != ts.target.Keyspace || tabletStats.Target.Shard != ts.target.Shard || ignoredTabletTypes[tabletType] {
+		return
+	}
+	if tabletStats.Tablet == nil {
+		log.Warningf("txThrottler: ignoring the health of a tablet of unknown alias: %v", tabletStats.Target)
+		return
+	}
+	alias := topoproto.TabletAliasString(tabletStats.Tablet.Alias)
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	// The lag of a tablet whose health stream is broken is unknown. The lag
+	// of the tablets that are not serving is still recorded, as they usually
+	// stopped serving because of their lag.
+	if tabletStats.LastError != nil || tabletStats.Stats == nil {
+		delete(ts.replicaLags, alias)
+		return
+	}
+	ts.replicaLags[alias] = replicaLag{
+		lag:     time.Duration(tabletStats.Stats.ReplicationLagSeconds) * time.Second,
+		updated: time.Now(),
+	}
+	ts.txThrottler.healthChecksRecordedTotal.Add(metricLabels, 1)
+}
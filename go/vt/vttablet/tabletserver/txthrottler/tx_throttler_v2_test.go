@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package txthrottler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"vitess.io/vitess/go/vt/discovery"
+	"vitess.io/vitess/go/vt/topo"
+	"vitess.io/vitess/go/vt/topo/memorytopo"
+	"vitess.io/vitess/go/vt/vtenv"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
+
+	querypb "vitess.io/vitess/go/vt/proto/query"
+	topodatapb "vitess.io/vitess/go/vt/proto/topodata"
+)
+
+func TestLagController(t *testing.T) {
+	c := &lagController{ceiling: 5}
+
+	// no throttling under the ceiling, but for a lag quickly approaching it.
+	assert.Zero(t, c.update(2*time.Second, time.Second))
+	assert.InDelta(t, 0.06, c.update(5*time.Second, time.Second), 0.001)
+	assert.Zero(t, c.update(5*time.Second, time.Second))
+
+	// a lag of twice the ceiling throttles half of the transactions at once,
+	// then more and more of them while it lasts.
+	ratio := c.update(10*time.Second, time.Second)
+	assert.InDelta(t, 0.5+0.2+0.1, ratio, 0.001)
+	for range 10 {
+		next := c.update(10*time.Second, time.Second)
+		assert.GreaterOrEqual(t, next, ratio)
+		ratio = next
+	}
+	assert.Equal(t, 1.0, ratio)
+
+	// the throttling stops soon after the lag is under control.
+	ratio = c.update(4*time.Second, time.Second)
+	assert.Less(t, ratio, 1.0)
+	for range 5 {
+		ratio = c.update(time.Second, time.Second)
+	}
+	assert.Zero(t, ratio)
+}
+
+func replicaHealth(cell string, uid uint32, shard string, tabletType topodatapb.TabletType, lag uint32) *discovery.TabletHealth {
+	return &discovery.TabletHealth{
+		Tablet: &topodatapb.Tablet{Alias: &topodatapb.TabletAlias{Cell: cell, Uid: uid}},
+		Target: &querypb.Target{
+			Keyspace:   "keyspace",
+			Shard:      shard,
+			Cell:       cell,
+			TabletType: tabletType,
+		},
+		Stats:   &querypb.RealtimeStats{ReplicationLagSeconds: lag},
+		Serving: true,
+	}
+}
+
+func TestEnabledThrottlerV2(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	defer resetTxThrottlerFactories()
+	defer func(interval time.Duration) { controllerInterval = interval }(controllerInterval)
+	// The controller is run by the test.
+	controllerInterval = time.Hour
+	ts := memorytopo.NewServer(ctx, "cell1", "cell2")
+
+	mockHealthCheck := NewMockHealthCheck(mockCtrl)
+	hcCall1 := mockHealthCheck.EXPECT().Subscribe()
+	hcCall1.Do(func() {})
+	hcCall2 := mockHealthCheck.EXPECT().Close()
+	hcCall2.After(hcCall1)
+	healthCheckFactory = func(topoServer *topo.Server, cell string, cellsToWatch []string) discovery.HealthCheck {
+		assert.Equal(t, []string{"cell1", "cell2"}, cellsToWatch)
+		return mockHealthCheck
+	}
+
+	cfg := tabletenv.NewDefaultConfig()
+	cfg.EnableTxThrottler = true
+	cfg.TxThrottlerVersion = 2
+	cfg.TxThrottlerLagCeiling = 5 * time.Second
+
+	env := tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, t.Name())
+	throttler := NewTxThrottler(env, ts)
+	throttlerImpl, _ := throttler.(*txThrottler)
+	require.NotNil(t, throttlerImpl)
+	assert.Nil(t, throttlerImpl.requestsThrottledRates.Load())
+	throttler.InitDBConfig(&querypb.Target{
+		Cell:     "cell1",
+		Keyspace: "keyspace",
+		Shard:    "-80",
+	})
+
+	require.NoError(t, throttler.Open())
+	state, ok := throttlerImpl.state.(*txThrottlerStateV2)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), throttlerImpl.throttlerRunning.Get())
+	assert.NotNil(t, throttlerImpl.requestsThrottledRates.Load())
+
+	// The replicas of all the types are monitored, but not the primary, the
+	// tablets not replicating on purpose, or the ones of the other shards.
+	state.StatsUpdate(replicaHealth("cell1", 101, "-80", topodatapb.TabletType_REPLICA, 1))
+	state.StatsUpdate(replicaHealth("cell2", 201, "-80", topodatapb.TabletType_RDONLY, 2))
+	state.StatsUpdate(replicaHealth("cell1", 100, "-80", topodatapb.TabletType_PRIMARY, 30))
+	state.StatsUpdate(replicaHealth("cell1", 102, "-80", topodatapb.TabletType_BACKUP, 30))
+	state.StatsUpdate(replicaHealth("cell1", 111, "80-", topodatapb.TabletType_REPLICA, 30))
+	assert.Equal(t, map[string]int64{"cell1.REPLICA": 2, "cell2.RDONLY": 1, "cell1.PRIMARY": 1, "cell1.BACKUP": 1}, throttlerImpl.healthChecksReadTotal.Counts())
+	assert.Equal(t, map[string]int64{"cell1.REPLICA": 1, "cell2.RDONLY": 1}, throttlerImpl.healthChecksRecordedTotal.Counts())
+
+	now := time.Now()
+	state.updateThrottleRatio(now)
+	assert.Equal(t, int64(2), throttlerImpl.shardMaxLag.Get())
+	assert.Zero(t, throttlerImpl.throttlePercent.Get())
+	assert.False(t, throttler.Throttle(100, "some-workload"))
+
+	// A lagging rdonly tablet throttles all the transactions after a while.
+	state.StatsUpdate(replicaHealth("cell2", 201, "-80", topodatapb.TabletType_RDONLY, 20))
+	for range 5 {
+		now = now.Add(time.Second)
+		state.updateThrottleRatio(now)
+	}
+	assert.Equal(t, int64(20), throttlerImpl.shardMaxLag.Get())
+	assert.Equal(t, int64(100), throttlerImpl.throttlePercent.Get())
+	assert.True(t, throttler.Throttle(100, "some-workload"))
+	assert.False(t, throttler.Throttle(0, "some-workload"))
+	assert.Equal(t, int64(3), throttlerImpl.requestsTotal.Counts()["some-workload"])
+	assert.Equal(t, int64(1), throttlerImpl.requestsThrottled.Counts()["some-workload"])
+
+	// The lag of a tablet whose health stream broke is forgotten.
+	broken := replicaHealth("cell2", 201, "-80", topodatapb.TabletType_RDONLY, 20)
+	broken.LastError = errors.New("connection refused")
+	state.StatsUpdate(broken)
+	now = now.Add(time.Second)
+	state.updateThrottleRatio(now)
+	assert.Equal(t, int64(1), throttlerImpl.shardMaxLag.Get())
+
+	// So is the lag of the tablets that no longer report it.
+	state.updateThrottleRatio(now.Add(replicaLagTTL + time.Second))
+	assert.Zero(t, throttlerImpl.shardMaxLag.Get())
+	assert.Empty(t, state.replicaLags)
+
+	throttler.Close()
+	assert.Zero(t, throttlerImpl.throttlerRunning.Get())
+	assert.Zero(t, throttlerImpl.throttlePercent.Get())
+	// The rates are stopped with the throttler.
+	assert.Nil(t, throttlerImpl.requestsThrottledRates.Load())
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vstreamer
+
+import (
+	"context"
+	"os"
+
+	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/mysql/replication"
+	"vitess.io/vitess/go/vt/binlog"
+	"vitess.io/vitess/go/vt/log"
+	"vitess.io/vitess/go/vt/logutil"
+	"vitess.io/vitess/go/vt/mysqlctl"
+	"vitess.io/vitess/go/vt/mysqlctl/backupstorage"
+	"vitess.io/vitess/go/vt/vterrors"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+)
+
+// startBinlogDump starts the binlog dump from vs.pos. If the filter asks for
+// it, and vs.pos was purged from the binary logs of the source, the stream
+// first catches up from the binary logs archived by the incremental backups
+// of the shard, then continues with the binary logs of the source.
+func (vs *vstreamer) startBinlogDump(conn *binlog.BinlogConnection) (<-chan mysql.BinlogEvent, <-chan error, error) {
+	if !vs.filter.GetCatchUpFromBackups() || vs.pos.IsZero() {
+		return conn.StartBinlogDumpFromPosition(vs.ctx, "", vs.pos)
+	}
+	purged, err := conn.GetGTIDPurged()
+	if err != nil {
+		return nil, nil, vterrors.Wrap(err, "cannot read gtid_purged")
+	}
+	if purged.IsZero() || vs.pos.GTIDSet.Contains(purged.GTIDSet) {
+		return conn.StartBinlogDumpFromPosition(vs.ctx, "", vs.pos)
+	}
+	if _, ok := vs.pos.GTIDSet.(replication.Mysql56GTIDSet); !ok {
+		return nil, nil, vterrors.Errorf(vtrpcpb.Code_UNIMPLEMENTED, "catching up from backups is only supported for MySQL 5.6+ positions, not %v", vs.pos)
+	}
+
+	bs, err := backupstorage.GetBackupStorage()
+	if err != nil {
+		return nil, nil, vterrors.Wrapf(err, "position %v was purged, and the backups cannot be read to catch up", vs.pos)
+	}
+	handles, err := mysqlctl.FindArchivedBinlogBackups(vs.ctx, bs, vs.vse.keyspace, vs.vse.shard, vs.pos, purged)
+	if err != nil {
+		bs.Close()
+		return nil, nil, vterrors.Wrapf(err, "position %v was purged, and the backups cannot catch up to %v", vs.pos, purged)
+	}
+	log.Infof("Position %v was purged up to %v, catching up from %d incremental backups", vs.pos, purged, len(handles))
+
+	events := make(chan mysql.BinlogEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer bs.Close()
+		pos, err := vs.streamArchivedBinlogs(handles, events)
+		if err == nil {
+			err = forwardBinlogDump(vs.ctx, conn, pos, events)
+		}
+		if err != nil {
+			// events is left open for parseEvents to return err.
+			errs <- err
+			return
+		}
+		close(events)
+	}()
+	return events, errs, nil
+}
+
+// streamArchivedBinlogs sends the events of the binary logs archived by the
+// backups of handles, and returns the position at which they end.
+func (vs *vstreamer) streamArchivedBinlogs(handles []backupstorage.BackupHandle, events chan<- mysql.BinlogEvent) (replication.Position, error) {
+	pos := vs.pos
+	for _, bh := range handles {
+		dir, binlogFiles, err := mysqlctl.RestoreArchivedBinlogs(vs.ctx, bh, logutil.NewConsoleLogger())
+		if err != nil {
+			vs.vse.errorCounts.Add("ArchivedBinlogs", 1)
+			return pos, err
+		}
+		for _, binlogFile := range binlogFiles {
+			pos, err = binlog.StreamBinlogFile(vs.ctx, binlogFile, pos, events)
+			if err != nil {
+				os.RemoveAll(dir)
+				vs.vse.errorCounts.Add("ArchivedBinlogs", 1)
+				return pos, err
+			}
+			vs.vse.vstreamerArchivedBinlogsStreamed.Add(1)
+		}
+		os.RemoveAll(dir)
+	}
+	return pos, nil
+}
+
+// forwardBinlogDump starts the binlog dump from pos and forwards its events
+// until the dump ends. It returns nil if the dump ends without an error.
+func forwardBinlogDump(ctx context.Context, conn *binlog.BinlogConnection, pos replication.Position, events chan<- mysql.BinlogEvent) error {
+	dumpEvents, dumpErrs, err := conn.StartBinlogDumpFromPosition(ctx, "", pos)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case ev, ok := <-dumpEvents:
+			if !ok {
+				return nil
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return nil
+			}
+		case err, ok := <-dumpErrs:
+			if !ok {
+				return nil
+			}
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
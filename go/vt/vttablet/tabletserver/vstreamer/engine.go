@@ -40,6 +40,7 @@ import (
 	"vitess.io/vitess/go/vt/srvtopo"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vtgate/vindexes"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver/heartbeat"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/throttle"
@@ -61,6 +62,10 @@ type Engine struct {
 	keyspace string
 	shard    string
 
+	// binlogServerID is the server id of the binlog connections. If it is
+	// not set, the ids are picked from the pool of the binlog package.
+	binlogServerID uint32
+
 	// wg is incremented for every Stream, and decremented on end.
 	// Close waits for all current streams to end by waiting on wg.
 	wg sync.WaitGroup
@@ -91,6 +96,7 @@ type Engine struct {
 	vstreamerCompressedTransactionsDecoded *stats.Counter
 	vstreamerPacketSize                    *stats.GaugeFunc
 	vstreamerNumPackets                    *stats.Counter
+	vstreamerBatchedTransactions           *stats.Counter
 	resultStreamerNumRows                  *stats.Counter
 	resultStreamerNumPackets               *stats.Counter
 	rowStreamerNumRows                     *stats.Counter
@@ -100,9 +106,11 @@ type Engine struct {
 	vstreamersCreated                      *stats.Counter
 	vstreamersEndedWithErrors              *stats.Counter
 	vstreamerFlushedBinlogs                *stats.Counter
+	vstreamerArchivedBinlogsStreamed       *stats.Counter
 	tableStreamerNumTables                 *stats.Counter
 
 	throttlerClient *throttle.Client
+	heartbeatWriter heartbeat.HeartbeatWriter
 }
 
 const throttledLoggerInterval = 5 * time.Minute
@@ -110,13 +118,14 @@ const throttledLoggerInterval = 5 * time.Minute
 // NewEngine creates a new Engine.
 // Initialization sequence is: NewEngine->InitDBConfig->Open.
 // Open and Close can be called multiple times and are idempotent.
-func NewEngine(env tabletenv.Env, ts srvtopo.Server, se *schema.Engine, lagThrottler *throttle.Throttler, cell string) *Engine {
+func NewEngine(env tabletenv.Env, ts srvtopo.Server, se *schema.Engine, lagThrottler *throttle.Throttler, heartbeatWriter heartbeat.HeartbeatWriter, cell string) *Engine {
 	vse := &Engine{
 		env:             env,
 		ts:              ts,
 		se:              se,
 		cell:            cell,
 		throttlerClient: throttle.NewBackgroundClient(lagThrottler, throttlerapp.VStreamerName, throttle.ThrottleCheckSelf),
+		heartbeatWriter: heartbeatWriter,
 
 		streamers:       make(map[int]*uvstreamer),
 		rowStreamers:    make(map[int]*rowStreamer),
@@ -134,6 +143,7 @@ func NewEngine(env tabletenv.Env, ts srvtopo.Server, se *schema.Engine, lagThrot
 		vstreamerCompressedTransactionsDecoded: env.Exporter().NewCounter("VStreamerCompressedTransactionsDecoded", "Count of compressed transactions (MySQL's binlog_transaction_compression=ON) decoded in the VStream API"),
 		vstreamerPacketSize:                    env.Exporter().NewGaugeFunc("VStreamPacketSize", "Max packet size for sending vstreamer events", getPacketSize),
 		vstreamerNumPackets:                    env.Exporter().NewCounter("VStreamerNumPackets", "Number of packets in vstreamer"),
+		vstreamerBatchedTransactions:           env.Exporter().NewCounter("VStreamerBatchedTransactions", "Number of transactions held back by vstreamer to be sent together with the following ones"),
 		resultStreamerNumPackets:               env.Exporter().NewCounter("ResultStreamerNumPackets", "Number of packets in result streamer"),
 		resultStreamerNumRows:                  env.Exporter().NewCounter("ResultStreamerNumRows", "Number of rows sent in result streamer"),
 		rowStreamerNumPackets:                  env.Exporter().NewCounter("RowStreamerNumPackets", "Number of packets in row streamer"),
@@ -144,6 +154,7 @@ func NewEngine(env tabletenv.Env, ts srvtopo.Server, se *schema.Engine, lagThrot
 		vstreamersEndedWithErrors:              env.Exporter().NewCounter("VStreamersEndedWithErrors", "Count of vstreamers that ended with errors"),
 		errorCounts:                            env.Exporter().NewCountersWithSingleLabel("VStreamerErrors", "Tracks errors in vstreamer", "type", "Catchup", "Copy", "Send", "TablePlan"),
 		vstreamerFlushedBinlogs:                env.Exporter().NewCounter("VStreamerFlushedBinlogs", "Number of times we've successfully executed a FLUSH BINARY LOGS statement when starting a vstream"),
+		vstreamerArchivedBinlogsStreamed:       env.Exporter().NewCounter("VStreamerArchivedBinlogsStreamed", "Number of binary logs archived by the incremental backups streamed to catch up from a purged position"),
 	}
 	env.Exporter().NewGaugeFunc("RowStreamerMaxInnoDBTrxHistLen", "", func() int64 { return env.Config().RowStreamer.MaxInnoDBTrxHistLen })
 	env.Exporter().NewGaugeFunc("RowStreamerMaxMySQLReplLagSecs", "", func() int64 { return env.Config().RowStreamer.MaxMySQLReplLagSecs })
@@ -161,6 +172,12 @@ func (vse *Engine) InitDBConfig(keyspace, shard string) {
 	vse.shard = shard
 }
 
+// SetBinlogServerID sets the server id the binlog connections of the
+// engine identify themselves with to mysqld.
+func (vse *Engine) SetBinlogServerID(serverID uint32) {
+	vse.binlogServerID = serverID
+}
+
 // Open starts the Engine service.
 func (vse *Engine) Open() {
 	log.Info("VStreamer: opening")
@@ -231,6 +248,16 @@ func (vse *Engine) validateBinlogRowImage(ctx context.Context, db dbconfigs.Conn
 	return nil
 }
 
+// requestHeartbeats requests on-demand heartbeats for the streams of the VStream API, whose
+// consumers use the heartbeats to measure their lag on idle shards. The internal streams of
+// the tablet do not need heartbeats, and must not keep them running.
+func (vse *Engine) requestHeartbeats(throttlerApp throttlerapp.Name) {
+	if vse.heartbeatWriter == nil || throttlerApp != throttlerapp.VStreamerName {
+		return
+	}
+	go vse.heartbeatWriter.RequestHeartbeats()
+}
+
 // Stream starts a new stream.
 // This streams events from the binary logs
 func (vse *Engine) Stream(ctx context.Context, startPos string, tablePKs []*binlogdatapb.TableLastPK, filter *binlogdatapb.Filter, throttlerApp throttlerapp.Name, send func([]*binlogdatapb.VEvent) error) error {
@@ -57,7 +57,7 @@ func TestMain(m *testing.M) {
 
 		// engine cannot be initialized in testenv because it introduces
 		// circular dependencies
-		engine = NewEngine(env.TabletEnv, env.SrvTopo, env.SchemaEngine, nil, env.Cells[0])
+		engine = NewEngine(env.TabletEnv, env.SrvTopo, env.SchemaEngine, nil, nil, env.Cells[0])
 		engine.InitDBConfig(env.KeyspaceName, env.ShardName)
 		engine.Open()
 		defer engine.Close()
@@ -82,7 +82,7 @@ func newEngine(t *testing.T, ctx context.Context, binlogRowImage string) {
 
 	// engine cannot be initialized in testenv because it introduces
 	// circular dependencies
-	engine = NewEngine(env.TabletEnv, env.SrvTopo, env.SchemaEngine, nil, env.Cells[0])
+	engine = NewEngine(env.TabletEnv, env.SrvTopo, env.SchemaEngine, nil, nil, env.Cells[0])
 	engine.InitDBConfig(env.KeyspaceName, env.ShardName)
 	engine.Open()
 }
@@ -94,7 +94,7 @@ func customEngine(t *testing.T, modifier func(mysql.ConnParams) mysql.ConnParams
 	cfg := env.TabletEnv.Config().Clone()
 	cfg.DB = dbconfigs.NewTestDBConfigs(modified, modified, modified.DbName)
 
-	engine := NewEngine(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "VStreamerTest"), env.SrvTopo, env.SchemaEngine, nil, env.Cells[0])
+	engine := NewEngine(tabletenv.NewEnv(vtenv.NewTestEnv(), cfg, "VStreamerTest"), env.SrvTopo, env.SchemaEngine, nil, nil, env.Cells[0])
 	engine.InitDBConfig(env.KeyspaceName, env.ShardName)
 	engine.Open()
 	return engine
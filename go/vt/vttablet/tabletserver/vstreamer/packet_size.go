@@ -29,6 +29,14 @@ import (
 var (
 	defaultPacketSize    = 250000
 	useDynamicPacketSize = true
+
+	// batchMaxLatency is how long the events of committed transactions can
+	// be held back to be sent together with the following ones. Batching is
+	// disabled when it is zero.
+	batchMaxLatency time.Duration
+	// batchMaxBytes is the size of the held back events at which they are
+	// sent right away. defaultPacketSize is used when it is zero.
+	batchMaxBytes int
 )
 
 func init() {
@@ -41,6 +49,17 @@ func registerPacketSizeFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&defaultPacketSize, "vstream_packet_size", defaultPacketSize, "Suggested packet size for VReplication streamer. This is used only as a recommendation. The actual packet size may be more or less than this amount.")
 	// useDynamicPacketSize controls whether to use dynamic packet size adjustments to increase performance while streaming
 	fs.BoolVar(&useDynamicPacketSize, "vstream_dynamic_packet_size", useDynamicPacketSize, "Enable dynamic packet sizing for VReplication. This will adjust the packet size during replication to improve performance.")
+	fs.DurationVar(&batchMaxLatency, "vstream_batch_max_latency", batchMaxLatency, "Maximum time the events of committed transactions can be held back by the VReplication streamer to be sent together with the following ones, which reduces the number of packets sent over constrained links. Batching is disabled when zero.")
+	fs.IntVar(&batchMaxBytes, "vstream_batch_max_bytes", batchMaxBytes, "Size of the events held back by --vstream_batch_max_latency at which they are sent right away. --vstream_packet_size is used when zero.")
+}
+
+// getBatchMaxBytes returns the size of the batched events at which they
+// are sent.
+func getBatchMaxBytes() int {
+	if batchMaxBytes > 0 {
+		return batchMaxBytes
+	}
+	return defaultPacketSize
 }
 
 // PacketSizer is a controller that adjusts the size of the packets being sent by the vstreamer at runtime
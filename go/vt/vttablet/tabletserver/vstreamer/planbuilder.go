@@ -58,6 +58,10 @@ type Plan struct {
 	// of the table.
 	Filters []Filter
 
+	// projected is set if the columns of the rule limit the columns
+	// of the table that are sent.
+	projected bool
+
 	env *vtenv.Environment
 }
 
@@ -359,14 +363,76 @@ func buildPlan(env *vtenv.Environment, ti *Table, vschema *localVSchema, filter
 			if !result {
 				continue
 			}
+			if len(rule.Columns) > 0 {
+				return buildProjectedPlan(env, ti, vschema, rule)
+			}
 			return buildREPlan(env, ti, vschema, rule.Filter)
 		case rule.Match == ti.Name:
+			if len(rule.Columns) > 0 {
+				return buildProjectedPlan(env, ti, vschema, rule)
+			}
 			return buildTablePlan(env, ti, vschema, rule.Filter)
 		}
 	}
 	return nil, nil
 }
 
+// buildProjectedPlan handles the rules that list the columns to send. The
+// filter of the rule is turned into the equivalent select statement, which
+// only selects those columns.
+func buildProjectedPlan(env *vtenv.Environment, ti *Table, vschema *localVSchema, rule *binlogdatapb.Rule) (*Plan, error) {
+	query, err := projectQuery(getQuery(ti.Name, rule.Filter), rule.Columns, env.Parser())
+	if err != nil {
+		return nil, err
+	}
+	plan, err := buildTablePlan(env, ti, vschema, query)
+	if err != nil {
+		return nil, err
+	}
+	plan.projected = true
+	return plan, nil
+}
+
+// projectQuery limits the columns selected by the select statement of a
+// filter to the given ones, in the given order. The columns must either be
+// selected by the statement or be columns of the table if it selects all of
+// them.
+func projectQuery(query string, columns []string, parser *sqlparser.Parser) (string, error) {
+	sel, _, err := analyzeSelect(query, parser)
+	if err != nil {
+		return "", err
+	}
+	star := false
+	selected := make(map[string]sqlparser.SelectExpr, len(sel.SelectExprs))
+	for _, expr := range sel.SelectExprs {
+		switch expr := expr.(type) {
+		case *sqlparser.StarExpr:
+			star = true
+		case *sqlparser.AliasedExpr:
+			// The columns are sent with their names in the table,
+			// regardless of their aliases.
+			name := expr.ColumnName()
+			if col, ok := expr.Expr.(*sqlparser.ColName); ok {
+				name = col.Name.String()
+			}
+			selected[strings.ToLower(name)] = expr
+		}
+	}
+	exprs := make(sqlparser.SelectExprs, 0, len(columns))
+	for _, column := range columns {
+		if expr, ok := selected[strings.ToLower(column)]; ok {
+			exprs = append(exprs, expr)
+			continue
+		}
+		if !star {
+			return "", fmt.Errorf("column %s is not selected by the filter: %s", column, query)
+		}
+		exprs = append(exprs, &sqlparser.AliasedExpr{Expr: sqlparser.NewColName(column)})
+	}
+	sel.SelectExprs = exprs
+	return sqlparser.String(sel), nil
+}
+
 // buildREPlan handles cases where Match has a regular expression.
 // If so, the Filter can be an empty string or a keyrange, like "-80".
 func buildREPlan(env *vtenv.Environment, ti *Table, vschema *localVSchema, filter string) (*Plan, error) {
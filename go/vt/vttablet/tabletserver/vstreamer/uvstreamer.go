@@ -152,7 +152,7 @@ func (uvs *uvstreamer) buildTablePlan() error {
 		}
 	}
 	for tableName := range tables {
-		rule, err := matchTable(tableName, uvs.filter, tables)
+		rule, err := matchTable(tableName, uvs.filter, tables, uvs.vse.env.Environment().Parser())
 		if err != nil {
 			return err
 		}
@@ -183,7 +183,7 @@ func (uvs *uvstreamer) buildTablePlan() error {
 }
 
 // check which rule matches table, validate table is in schema
-func matchTable(tableName string, filter *binlogdatapb.Filter, tables map[string]*schema.Table) (*binlogdatapb.Rule, error) {
+func matchTable(tableName string, filter *binlogdatapb.Filter, tables map[string]*schema.Table, parser *sqlparser.Parser) (*binlogdatapb.Rule, error) {
 	if tableName == "dual" {
 		return nil, nil
 	}
@@ -205,9 +205,19 @@ func matchTable(tableName string, filter *binlogdatapb.Filter, tables map[string
 			found = true
 		}
 		if found {
+			query := getQuery(tableName, rule.Filter)
+			if len(rule.Columns) > 0 {
+				// The rows are copied with the same projection as the
+				// one of the row events.
+				projected, err := projectQuery(query, rule.Columns, parser)
+				if err != nil {
+					return nil, err
+				}
+				query = projected
+			}
 			return &binlogdatapb.Rule{
 				Match:  tableName,
-				Filter: getQuery(tableName, rule.Filter),
+				Filter: query,
 			}, nil
 		}
 	}
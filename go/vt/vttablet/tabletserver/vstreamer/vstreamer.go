@@ -77,10 +77,13 @@ type vstreamer struct {
 	journalTableID uint64
 	versionTableID uint64
 
-	// format and pos are updated by parseEvent.
+	// format, pos and transaction are updated by parseEvent.
 	format  mysql.BinlogFormat
 	pos     replication.Position
 	stopPos string
+	// transaction is the metadata of the transaction being parsed. It's
+	// started by the GTID event of the transaction.
+	transaction *binlogdatapb.TransactionMetadata
 
 	phase string
 	vse   *Engine
@@ -185,13 +188,19 @@ func (vs *vstreamer) replicate(ctx context.Context) error {
 		return wrapError(err, vs.pos, vs.vse)
 	}
 
-	conn, err := binlog.NewBinlogConnection(vs.cp)
+	var conn *binlog.BinlogConnection
+	var err error
+	if vs.vse.binlogServerID != 0 {
+		conn, err = binlog.NewBinlogConnectionWithServerID(vs.cp, vs.vse.binlogServerID)
+	} else {
+		conn, err = binlog.NewBinlogConnection(vs.cp)
+	}
 	if err != nil {
 		return wrapError(err, vs.pos, vs.vse)
 	}
 	defer conn.Close()
 
-	events, errs, err := conn.StartBinlogDumpFromPosition(vs.ctx, "", vs.pos)
+	events, errs, err := vs.startBinlogDump(conn)
 	if err != nil {
 		return wrapError(err, vs.pos, vs.vse)
 	}
@@ -205,7 +214,56 @@ func (vs *vstreamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 	var (
 		bufferedEvents []*binlogdatapb.VEvent
 		curSize        int
+
+		// When batching is enabled, the events of committed transactions are
+		// held back until batchTimer fires, or until they reach the maximum
+		// batch size. batchEnd and batchSize are the number and the size of
+		// the held back events, and batchC is nil when there are none.
+		batchEnd   int
+		batchSize  int
+		batchTimer *time.Timer
+		batchC     <-chan time.Time
 	)
+	defer func() {
+		if batchTimer != nil {
+			batchTimer.Stop()
+		}
+	}()
+	send := func(vevents []*binlogdatapb.VEvent) error {
+		if batchC != nil {
+			batchTimer.Stop()
+			batchC = nil
+		}
+		batchEnd, batchSize = 0, 0
+		return vs.send(vevents)
+	}
+	// holdBack holds back the buffered events, which end with a COMMIT,
+	// unless the batch is full.
+	holdBack := func() bool {
+		if batchMaxLatency <= 0 || curSize >= getBatchMaxBytes() {
+			return false
+		}
+		if batchC == nil {
+			if batchTimer == nil {
+				batchTimer = time.NewTimer(batchMaxLatency)
+			} else {
+				batchTimer.Reset(batchMaxLatency)
+			}
+			batchC = batchTimer.C
+		}
+		batchEnd, batchSize = len(bufferedEvents), curSize
+		vs.vse.vstreamerBatchedTransactions.Add(1)
+		return true
+	}
+	// flushBatch sends the held back events, and keeps buffering the
+	// events of the current transaction.
+	flushBatch := func() error {
+		vevents := bufferedEvents[:batchEnd]
+		bufferedEvents = append([]*binlogdatapb.VEvent(nil), bufferedEvents[batchEnd:]...)
+		curSize -= batchSize
+		batchC = nil
+		return send(vevents)
+	}
 
 	// Only the following patterns are possible:
 	// BEGIN->ROWs or Statements->GTID->COMMIT. In the case of large transactions, this can be broken into chunks.
@@ -237,19 +295,25 @@ func (vs *vstreamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 			// Although unlikely, it's possible to get a HEARTBEAT in the middle
 			// of a transaction. If so, we still send the partial transaction along
 			// with the heartbeat.
+			// If batching is enabled, committed transactions are held back
+			// to be sent together with the following ones.
 			bufferedEvents = append(bufferedEvents, vevent)
+			if vevent.Type == binlogdatapb.VEventType_COMMIT && holdBack() {
+				return nil
+			}
 			vevents := bufferedEvents
 			bufferedEvents = nil
 			curSize = 0
-			return vs.send(vevents)
-		case binlogdatapb.VEventType_INSERT, binlogdatapb.VEventType_DELETE, binlogdatapb.VEventType_UPDATE, binlogdatapb.VEventType_REPLACE:
-			newSize := len(vevent.GetDml())
+			return send(vevents)
+		case binlogdatapb.VEventType_INSERT, binlogdatapb.VEventType_DELETE, binlogdatapb.VEventType_UPDATE, binlogdatapb.VEventType_REPLACE,
+			binlogdatapb.VEventType_SAVEPOINT:
+			newSize := len(vevent.GetDml()) + len(vevent.GetStatement())
 			if curSize+newSize > defaultPacketSize {
 				vs.vse.vstreamerNumPackets.Add(1)
 				vevents := bufferedEvents
 				bufferedEvents = []*binlogdatapb.VEvent{vevent}
 				curSize = newSize
-				return vs.send(vevents)
+				return send(vevents)
 			}
 			curSize += newSize
 			bufferedEvents = append(bufferedEvents, vevent)
@@ -270,7 +334,7 @@ func (vs *vstreamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 				vevents := bufferedEvents
 				bufferedEvents = []*binlogdatapb.VEvent{vevent}
 				curSize = newSize
-				return vs.send(vevents)
+				return send(vevents)
 			}
 			curSize += newSize
 			bufferedEvents = append(bufferedEvents, vevent)
@@ -284,6 +348,7 @@ func (vs *vstreamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 	// Main loop: calls bufferAndTransmit as events arrive.
 	hbTimer := time.NewTimer(HeartbeatTime)
 	defer hbTimer.Stop()
+	vs.vse.requestHeartbeats(vs.throttlerApp)
 
 	injectHeartbeat := func(throttled bool) error {
 		now := time.Now().UnixNano()
@@ -390,7 +455,16 @@ func (vs *vstreamer) parseEvents(ctx context.Context, events <-chan mysql.Binlog
 			return err
 		case <-ctx.Done():
 			return nil
+		case <-batchC:
+			if err := flushBatch(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				vs.vse.errorCounts.Add("Send", 1)
+				return fmt.Errorf("error sending event: %v", err)
+			}
 		case <-hbTimer.C:
+			vs.vse.requestHeartbeats(vs.throttlerApp)
 			if err := injectHeartbeat(false); err != nil {
 				if err == io.EOF {
 					return nil
@@ -445,9 +519,14 @@ func (vs *vstreamer) parseEvent(ev mysql.BinlogEvent) ([]*binlogdatapb.VEvent, e
 		if err != nil {
 			return nil, fmt.Errorf("can't get GTID from binlog event: %v, event data: %#v", err, ev)
 		}
+		vs.transaction = &binlogdatapb.TransactionMetadata{
+			Id:              gtid.String(),
+			CommitTimestamp: ev.CommitTimestamp(vs.format),
+		}
 		if hasBegin {
 			vevents = append(vevents, &binlogdatapb.VEvent{
-				Type: binlogdatapb.VEventType_BEGIN,
+				Type:        binlogdatapb.VEventType_BEGIN,
+				Transaction: vs.beginTransaction(),
 			})
 		}
 		vs.pos = replication.AppendGTID(vs.pos, gtid)
@@ -456,7 +535,8 @@ func (vs *vstreamer) parseEvent(ev mysql.BinlogEvent) ([]*binlogdatapb.VEvent, e
 			Type: binlogdatapb.VEventType_GTID,
 			Gtid: replication.EncodePosition(vs.pos),
 		}, &binlogdatapb.VEvent{
-			Type: binlogdatapb.VEventType_COMMIT,
+			Type:        binlogdatapb.VEventType_COMMIT,
+			Transaction: vs.commitTransaction(),
 		})
 	case ev.IsQuery():
 		q, err := ev.Query(vs.format)
@@ -500,11 +580,13 @@ func (vs *vstreamer) parseEvent(ev mysql.BinlogEvent) ([]*binlogdatapb.VEvent, e
 			}
 		case sqlparser.StmtBegin:
 			vevents = append(vevents, &binlogdatapb.VEvent{
-				Type: binlogdatapb.VEventType_BEGIN,
+				Type:        binlogdatapb.VEventType_BEGIN,
+				Transaction: vs.beginTransaction(),
 			})
 		case sqlparser.StmtCommit:
 			vevents = append(vevents, &binlogdatapb.VEvent{
-				Type: binlogdatapb.VEventType_COMMIT,
+				Type:        binlogdatapb.VEventType_COMMIT,
+				Transaction: vs.commitTransaction(),
 			})
 		case sqlparser.StmtDDL:
 			if mustSendDDL(q, vs.cp.DBName(), vs.filter, vs.vse.env.Environment().Parser()) {
@@ -527,8 +609,8 @@ func (vs *vstreamer) parseEvent(ev mysql.BinlogEvent) ([]*binlogdatapb.VEvent, e
 			if schema.MustReloadSchemaOnDDL(q.SQL, vs.cp.DBName(), vs.vse.env.Environment().Parser()) {
 				vs.se.ReloadAt(context.Background(), vs.pos)
 			}
-		case sqlparser.StmtSavepoint:
-			// We currently completely skip `SAVEPOINT ...` statements.
+		case sqlparser.StmtSavepoint, sqlparser.StmtSRollback:
+			// We skip `SAVEPOINT ...` statements unless the filter asks for them.
 			//
 			// MySQL inserts `SAVEPOINT ...` statements into the binlog in row based, statement based
 			// and in mixed replication modes, but only ever writes `ROLLBACK TO ...` statements to the
@@ -537,6 +619,14 @@ func (vs *vstreamer) parseEvent(ev mysql.BinlogEvent) ([]*binlogdatapb.VEvent, e
 			//
 			// Vitess only supports row based replication, so skipping the creation of savepoints
 			// reduces the amount of data send over to vplayer.
+			if vs.filter.GetStreamSavepoints() {
+				vevents = append(vevents, &binlogdatapb.VEvent{
+					Type:      binlogdatapb.VEventType_SAVEPOINT,
+					Statement: q.SQL,
+				})
+			} else if cat == sqlparser.StmtSRollback {
+				return nil, fmt.Errorf("unexpected statement type %s in row-based replication: %q", cat, q.SQL)
+			}
 		case sqlparser.StmtOther, sqlparser.StmtAnalyze, sqlparser.StmtPriv, sqlparser.StmtSet, sqlparser.StmtComment, sqlparser.StmtFlush:
 			// These are either:
 			// 1) DBA statements like REPAIR that can be ignored.
@@ -660,10 +750,38 @@ func (vs *vstreamer) parseEvent(ev mysql.BinlogEvent) ([]*binlogdatapb.VEvent, e
 	for _, vevent := range vevents {
 		vevent.Timestamp = int64(ev.Timestamp())
 		vevent.CurrentTime = time.Now().UnixNano()
+		// The events of a transaction payload were counted when parsed.
+		if vs.transaction != nil && !ev.IsTransactionPayload() {
+			switch vevent.Type {
+			case binlogdatapb.VEventType_ROW, binlogdatapb.VEventType_INSERT, binlogdatapb.VEventType_UPDATE,
+				binlogdatapb.VEventType_DELETE, binlogdatapb.VEventType_REPLACE, binlogdatapb.VEventType_SAVEPOINT:
+				vs.transaction.StatementCount++
+			}
+		}
 	}
 	return vevents, nil
 }
 
+// beginTransaction returns the metadata of the BEGIN event of the current
+// transaction, if its GTID event was seen.
+func (vs *vstreamer) beginTransaction() *binlogdatapb.TransactionMetadata {
+	if vs.transaction == nil {
+		return nil
+	}
+	return &binlogdatapb.TransactionMetadata{
+		Id:              vs.transaction.Id,
+		CommitTimestamp: vs.transaction.CommitTimestamp,
+	}
+}
+
+// commitTransaction returns the metadata of the COMMIT event of the current
+// transaction, if its GTID event was seen, and ends the transaction.
+func (vs *vstreamer) commitTransaction() *binlogdatapb.TransactionMetadata {
+	transaction := vs.transaction
+	vs.transaction = nil
+	return transaction
+}
+
 func (vs *vstreamer) buildJournalPlan(id uint64, tm *mysql.TableMap) error {
 	conn, err := vs.cp.Connect(vs.ctx)
 	if err != nil {
@@ -958,6 +1076,10 @@ func (vs *vstreamer) processRowEvent(vevents []*binlogdatapb.VEvent, plan *strea
 		if !beforeOK && !afterOK {
 			continue
 		}
+		if plan.projected && beforeOK && afterOK && !partial && sqltypes.RowEqual(beforeValues, afterValues) {
+			// None of the columns the stream is limited to changed.
+			continue
+		}
 		rowChange := &binlogdatapb.RowChange{}
 		if beforeOK {
 			rowChange.Before = sqltypes.RowToProto3(beforeValues)
@@ -327,7 +327,7 @@ func TestVersion(t *testing.T) {
 	require.NoError(t, err)
 	defer env.SchemaEngine.EnableHistorian(false)
 
-	engine = NewEngine(engine.env, env.SrvTopo, env.SchemaEngine, nil, env.Cells[0])
+	engine = NewEngine(engine.env, env.SrvTopo, env.SchemaEngine, nil, nil, env.Cells[0])
 	engine.InitDBConfig(env.KeyspaceName, env.ShardName)
 	engine.Open()
 	defer engine.Close()
@@ -827,6 +827,90 @@ func TestSavepointWithFilter(t *testing.T) {
 	ts.Run()
 }
 
+// TestSavepointEvents tests that savepoints are sent when the filter asks for them.
+func TestSavepointEvents(t *testing.T) {
+	ts := &TestSpec{
+		t: t,
+		ddls: []string{
+			"create table stream1(id int, val varbinary(128), primary key(id))",
+		},
+		options: &TestSpecOptions{
+			filter: &binlogdatapb.Filter{
+				Rules: []*binlogdatapb.Rule{{
+					Match: "/.*/",
+				}},
+				StreamSavepoints: true,
+			},
+		},
+	}
+	defer ts.Close()
+	require.NoError(t, ts.Init())
+	ts.tests = [][]*TestQuery{{
+		{"begin", nil},
+		{"insert into stream1 values (1, 'aaa')", nil},
+		{"savepoint a", []TestRowEvent{
+			{event: "type:SAVEPOINT statement:\"SAVEPOINT `a`\""},
+		}},
+		{"insert into stream1 values (2, 'aaa')", noEvents},
+		{"rollback work to savepoint a", noEvents},
+		{"update stream1 set val='bbb' where id = 1", []TestRowEvent{
+			{spec: &TestRowEventSpec{table: "stream1", changes: []TestRowChange{{before: []string{"1", "aaa"}, after: []string{"1", "bbb"}}}}},
+		}},
+		{"commit", nil},
+	}}
+	ts.Run()
+}
+
+// TestTransactionMetadata tests that the BEGIN and COMMIT events of a transaction describe it.
+func TestTransactionMetadata(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	execStatements(t, []string{
+		"create table stream1(id int, val varbinary(128), primary key(id))",
+	})
+	defer execStatements(t, []string{
+		"drop table stream1",
+	})
+	engine.se.Reload(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wg, ch := startStream(ctx, t, nil, "", nil)
+	defer wg.Wait()
+	execStatements(t, []string{
+		"begin",
+		"insert into stream1 values (1, 'aaa')",
+		"insert into stream1 values (2, 'bbb')",
+		"commit",
+	})
+
+	var begin, commit *binlogdatapb.VEvent
+	for commit == nil {
+		for _, ev := range <-ch {
+			switch ev.Type {
+			case binlogdatapb.VEventType_BEGIN:
+				begin = ev
+			case binlogdatapb.VEventType_COMMIT:
+				commit = ev
+			}
+		}
+	}
+	cancel()
+
+	require.NotNil(t, begin)
+	require.NotNil(t, begin.Transaction)
+	require.NotNil(t, commit.Transaction)
+	assert.NotEmpty(t, commit.Transaction.Id)
+	assert.Equal(t, commit.Transaction.Id, begin.Transaction.Id)
+	assert.NotZero(t, commit.Transaction.CommitTimestamp)
+	assert.Equal(t, commit.Transaction.CommitTimestamp, begin.Transaction.CommitTimestamp)
+	assert.Zero(t, begin.Transaction.StatementCount)
+	assert.EqualValues(t, 2, commit.Transaction.StatementCount)
+}
+
 func TestStatements(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
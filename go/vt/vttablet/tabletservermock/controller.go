@@ -27,6 +27,7 @@ import (
 	"vitess.io/vitess/go/vt/servenv"
 	"vitess.io/vitess/go/vt/topo"
 	"vitess.io/vitess/go/vt/vttablet/queryservice"
+	"vitess.io/vitess/go/vt/vttablet/tabletserver"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/rules"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/schema"
 	"vitess.io/vitess/go/vt/vttablet/tabletserver/tabletenv"
@@ -221,6 +222,31 @@ func (tqsc *Controller) CheckThrottler(ctx context.Context, appName string, flag
 	return nil
 }
 
+// UnresolvedTransactions is part of the tabletserver.Controller interface
+func (tqsc *Controller) UnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error) {
+	return nil, nil
+}
+
+// ResolveTransaction is part of the tabletserver.Controller interface
+func (tqsc *Controller) ResolveTransaction(ctx context.Context, dtid string) error {
+	return nil
+}
+
+// RequeueDeadLetters is part of the tabletserver.Controller interface
+func (tqsc *Controller) RequeueDeadLetters(ctx context.Context, table string, ids []string) (int64, error) {
+	return 0, nil
+}
+
+// KillQueries is part of the tabletserver.Controller interface
+func (tqsc *Controller) KillQueries(ctx context.Context, filter tabletserver.QueryFilter, dryRun bool) ([]tabletserver.KilledQuery, error) {
+	return nil, nil
+}
+
+// UpdateQueryFingerprintRules is part of the tabletserver.Controller interface
+func (tqsc *Controller) UpdateQueryFingerprintRules(ctx context.Context, setRules []tabletserver.FingerprintRule, deleteQueries []string) ([]tabletserver.FingerprintRule, error) {
+	return nil, nil
+}
+
 // EnterLameduck implements tabletserver.Controller.
 func (tqsc *Controller) EnterLameduck() {
 	tqsc.mu.Lock()
@@ -142,6 +142,15 @@ type TabletManagerClient interface {
 	// query faster. Close() should close the pool in that case.
 	ExecuteFetchAsApp(ctx context.Context, tablet *topodatapb.Tablet, usePool bool, req *tabletmanagerdatapb.ExecuteFetchAsAppRequest) (*querypb.QueryResult, error)
 
+	// KillQueries kills the queries running on the tablet that match a query
+	// fingerprint, a plan type or a caller, and returns them.
+	KillQueries(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.KillQueriesRequest) (*tabletmanagerdatapb.KillQueriesResponse, error)
+
+	// UpdateQueryFingerprintRules updates the query fingerprint rules of the
+	// tablet, which pin queries to a plan type or block them, and returns the
+	// rules after the update. An empty request only reloads the rules.
+	UpdateQueryFingerprintRules(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest) (*tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse, error)
+
 	//
 	// Replication related methods
 	//
@@ -258,6 +267,43 @@ type TabletManagerClient interface {
 	// Throttler
 	CheckThrottler(ctx context.Context, tablet *topodatapb.Tablet, request *tabletmanagerdatapb.CheckThrottlerRequest) (*tabletmanagerdatapb.CheckThrottlerResponse, error)
 
+	//
+	// Distributed transaction related methods
+	//
+
+	// GetUnresolvedTransactions returns the distributed transactions managed by
+	// the tablet that were not resolved within abandonAge seconds.
+	GetUnresolvedTransactions(ctx context.Context, tablet *topodatapb.Tablet, abandonAge int64) ([]*querypb.TransactionMetadata, error)
+
+	// ResolveTransaction asks the 2PC coordinator of the tablet to resolve a
+	// distributed transaction.
+	ResolveTransaction(ctx context.Context, tablet *topodatapb.Tablet, dtid string) error
+
+	//
+	// Messaging related methods
+	//
+
+	// RequeueDeadLetters moves messages from the dead-letter table of a
+	// message table back to it. All the dead letters are requeued if ids
+	// is empty. It returns the number of messages that were requeued.
+	RequeueDeadLetters(ctx context.Context, tablet *topodatapb.Tablet, table string, ids []string) (int64, error)
+
+	//
+	// Diagnostics related methods
+	//
+
+	// RunDiagnosticQuery runs one of an allow-listed set of read-only
+	// diagnostic queries on the MySQL of the tablet, and returns its result.
+	RunDiagnosticQuery(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RunDiagnosticQueryRequest) (*tabletmanagerdatapb.RunDiagnosticQueryResponse, error)
+
+	//
+	// MySQL upgrade related methods
+	//
+
+	// UpgradeMysql restarts the MySQL of the tablet with the MySQL binaries
+	// installed on its host, and upgrades its data directory to them.
+	UpgradeMysql(ctx context.Context, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.UpgradeMysqlRequest) (*tabletmanagerdatapb.UpgradeMysqlResponse, error)
+
 	//
 	// Management methods
 	//
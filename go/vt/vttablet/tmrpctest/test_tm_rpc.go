@@ -1328,6 +1328,232 @@ func (fra *fakeRPCTM) CheckThrottler(ctx context.Context, req *tabletmanagerdata
 	panic("implement me")
 }
 
+//
+// Distributed transaction related methods
+//
+
+var testAbandonAge = 30 * time.Second
+var testDtid = "ks:-80:1234"
+var testUnresolvedTransactions = []*querypb.TransactionMetadata{{
+	Dtid:        testDtid,
+	State:       querypb.TransactionState_PREPARE,
+	TimeCreated: 1000,
+	Participants: []*querypb.Target{{
+		Keyspace:   "ks",
+		Shard:      "80-",
+		TabletType: topodatapb.TabletType_PRIMARY,
+	}},
+}}
+var testResolveTransactionCalled = false
+
+func (fra *fakeRPCTM) GetUnresolvedTransactions(ctx context.Context, abandonAge time.Duration) ([]*querypb.TransactionMetadata, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "GetUnresolvedTransactions abandonAge", abandonAge, testAbandonAge)
+	return testUnresolvedTransactions, nil
+}
+
+func tmRPCTestGetUnresolvedTransactions(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	transactions, err := client.GetUnresolvedTransactions(ctx, tablet, int64(testAbandonAge.Seconds()))
+	compareError(t, "GetUnresolvedTransactions", err, transactions, testUnresolvedTransactions)
+}
+
+func tmRPCTestGetUnresolvedTransactionsPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.GetUnresolvedTransactions(ctx, tablet, int64(testAbandonAge.Seconds()))
+	expectHandleRPCPanic(t, "GetUnresolvedTransactions", false /*verbose*/, err)
+}
+
+func (fra *fakeRPCTM) ResolveTransaction(ctx context.Context, dtid string) error {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "ResolveTransaction dtid", dtid, testDtid)
+	testResolveTransactionCalled = true
+	return nil
+}
+
+func tmRPCTestResolveTransaction(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	err := client.ResolveTransaction(ctx, tablet, testDtid)
+	compareError(t, "ResolveTransaction", err, true, testResolveTransactionCalled)
+}
+
+func tmRPCTestResolveTransactionPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	err := client.ResolveTransaction(ctx, tablet, testDtid)
+	expectHandleRPCPanic(t, "ResolveTransaction", true /*verbose*/, err)
+}
+
+//
+// Messaging related methods
+//
+
+var testRequeueTable = "msg"
+var testRequeueIDs = []string{"1", "2"}
+var testRequeueCount int64 = 2
+
+func (fra *fakeRPCTM) RequeueDeadLetters(ctx context.Context, table string, ids []string) (int64, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "RequeueDeadLetters table", table, testRequeueTable)
+	compare(fra.t, "RequeueDeadLetters ids", ids, testRequeueIDs)
+	return testRequeueCount, nil
+}
+
+func tmRPCTestRequeueDeadLetters(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	count, err := client.RequeueDeadLetters(ctx, tablet, testRequeueTable, testRequeueIDs)
+	compareError(t, "RequeueDeadLetters", err, count, testRequeueCount)
+}
+
+func tmRPCTestRequeueDeadLettersPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.RequeueDeadLetters(ctx, tablet, testRequeueTable, testRequeueIDs)
+	expectHandleRPCPanic(t, "RequeueDeadLetters", true /*verbose*/, err)
+}
+
+//
+// Diagnostics related methods
+//
+
+var testRunDiagnosticQueryRequest = &tabletmanagerdatapb.RunDiagnosticQueryRequest{
+	Query:            tabletmanagerdatapb.RunDiagnosticQueryRequest_PROCESSLIST,
+	IncludeQueryText: true,
+}
+
+var testRunDiagnosticQueryResponse = &tabletmanagerdatapb.RunDiagnosticQueryResponse{
+	Processlist: []*tabletmanagerdatapb.RunDiagnosticQueryResponse_Process{{
+		Id:      1,
+		User:    "vt_app",
+		Host:    "localhost",
+		Db:      "vt_test_keyspace",
+		Command: "Query",
+		Time:    3,
+		State:   "executing",
+		Info:    "select 1",
+	}},
+}
+
+func (fra *fakeRPCTM) RunDiagnosticQuery(ctx context.Context, req *tabletmanagerdatapb.RunDiagnosticQueryRequest) (*tabletmanagerdatapb.RunDiagnosticQueryResponse, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "RunDiagnosticQuery request", req, testRunDiagnosticQueryRequest)
+	return testRunDiagnosticQueryResponse, nil
+}
+
+func tmRPCTestRunDiagnosticQuery(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	resp, err := client.RunDiagnosticQuery(ctx, tablet, testRunDiagnosticQueryRequest)
+	compareError(t, "RunDiagnosticQuery", err, resp, testRunDiagnosticQueryResponse)
+}
+
+func tmRPCTestRunDiagnosticQueryPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.RunDiagnosticQuery(ctx, tablet, testRunDiagnosticQueryRequest)
+	expectHandleRPCPanic(t, "RunDiagnosticQuery", false /*verbose*/, err)
+}
+
+var testKillQueriesRequest = &tabletmanagerdatapb.KillQueriesRequest{
+	Query:  "select * from t where id = 1",
+	Caller: "user1",
+	DryRun: true,
+}
+
+var testKillQueriesResponse = &tabletmanagerdatapb.KillQueriesResponse{
+	Queries: []*tabletmanagerdatapb.KillQueriesResponse_Query{{
+		ConnectionId: 12,
+		Fingerprint:  "select * from t where id = ?",
+		PlanType:     "Select",
+		Caller:       "user1",
+		Elapsed:      protoutil.DurationToProto(3 * time.Second),
+	}},
+}
+
+func (fra *fakeRPCTM) KillQueries(ctx context.Context, req *tabletmanagerdatapb.KillQueriesRequest) (*tabletmanagerdatapb.KillQueriesResponse, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "KillQueries request", req, testKillQueriesRequest)
+	return testKillQueriesResponse, nil
+}
+
+func tmRPCTestKillQueries(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	resp, err := client.KillQueries(ctx, tablet, testKillQueriesRequest)
+	compareError(t, "KillQueries", err, resp, testKillQueriesResponse)
+}
+
+func tmRPCTestKillQueriesPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.KillQueries(ctx, tablet, testKillQueriesRequest)
+	expectHandleRPCPanic(t, "KillQueries", true /*verbose*/, err)
+}
+
+var testUpdateQueryFingerprintRulesRequest = &tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest{
+	SetRules: []*tabletmanagerdatapb.QueryFingerprintRule{{
+		Fingerprint:  "select * from t where id = 1",
+		Action:       tabletmanagerdatapb.QueryFingerprintRule_BLOCK,
+		ErrorMessage: "too expensive",
+	}},
+	DeleteQueries: []string{"delete from t where id = 1"},
+}
+
+var testUpdateQueryFingerprintRulesResponse = &tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse{
+	Rules: []*tabletmanagerdatapb.QueryFingerprintRule{{
+		Fingerprint:  "select * from t where id = ?",
+		Action:       tabletmanagerdatapb.QueryFingerprintRule_BLOCK,
+		ErrorMessage: "too expensive",
+	}, {
+		Fingerprint: "update t set c = ? where id = ?",
+		Action:      tabletmanagerdatapb.QueryFingerprintRule_PIN,
+		PlanType:    "UpdateLimit",
+	}},
+}
+
+func (fra *fakeRPCTM) UpdateQueryFingerprintRules(ctx context.Context, req *tabletmanagerdatapb.UpdateQueryFingerprintRulesRequest) (*tabletmanagerdatapb.UpdateQueryFingerprintRulesResponse, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "UpdateQueryFingerprintRules request", req, testUpdateQueryFingerprintRulesRequest)
+	return testUpdateQueryFingerprintRulesResponse, nil
+}
+
+func tmRPCTestUpdateQueryFingerprintRules(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	resp, err := client.UpdateQueryFingerprintRules(ctx, tablet, testUpdateQueryFingerprintRulesRequest)
+	compareError(t, "UpdateQueryFingerprintRules", err, resp, testUpdateQueryFingerprintRulesResponse)
+}
+
+func tmRPCTestUpdateQueryFingerprintRulesPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.UpdateQueryFingerprintRules(ctx, tablet, testUpdateQueryFingerprintRulesRequest)
+	expectHandleRPCPanic(t, "UpdateQueryFingerprintRules", true /*verbose*/, err)
+}
+
+//
+// MySQL upgrade related methods
+//
+
+var testUpgradeMysqlRequest = &tabletmanagerdatapb.UpgradeMysqlRequest{
+	AllowPrimary: true,
+}
+
+var testUpgradeMysqlResponse = &tabletmanagerdatapb.UpgradeMysqlResponse{
+	VersionBefore: "8.0.35",
+	VersionAfter:  "8.0.36",
+}
+
+func (fra *fakeRPCTM) UpgradeMysql(ctx context.Context, req *tabletmanagerdatapb.UpgradeMysqlRequest) (*tabletmanagerdatapb.UpgradeMysqlResponse, error) {
+	if fra.panics {
+		panic(fmt.Errorf("test-triggered panic"))
+	}
+	compare(fra.t, "UpgradeMysql request", req, testUpgradeMysqlRequest)
+	return testUpgradeMysqlResponse, nil
+}
+
+func tmRPCTestUpgradeMysql(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	resp, err := client.UpgradeMysql(ctx, tablet, testUpgradeMysqlRequest)
+	compareError(t, "UpgradeMysql", err, resp, testUpgradeMysqlResponse)
+}
+
+func tmRPCTestUpgradeMysqlPanic(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet) {
+	_, err := client.UpgradeMysql(ctx, tablet, testUpgradeMysqlRequest)
+	expectHandleRPCPanic(t, "UpgradeMysql", true /*verbose*/, err)
+}
+
 func tmRPCTestRestoreFromBackup(ctx context.Context, t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.Tablet, req *tabletmanagerdatapb.RestoreFromBackupRequest) {
 	stream, err := client.RestoreFromBackup(ctx, tablet, req)
 	if err != nil {
@@ -1441,6 +1667,21 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	// Throttler related methods
 	tmRPCTestCheckThrottler(ctx, t, client, tablet, checkThrottlerRequest)
 
+	// Distributed transaction related methods
+	tmRPCTestGetUnresolvedTransactions(ctx, t, client, tablet)
+	tmRPCTestResolveTransaction(ctx, t, client, tablet)
+
+	// Messaging related methods
+	tmRPCTestRequeueDeadLetters(ctx, t, client, tablet)
+
+	// Diagnostics related methods
+	tmRPCTestRunDiagnosticQuery(ctx, t, client, tablet)
+	tmRPCTestKillQueries(ctx, t, client, tablet)
+	tmRPCTestUpdateQueryFingerprintRules(ctx, t, client, tablet)
+
+	// MySQL upgrade related methods
+	tmRPCTestUpgradeMysql(ctx, t, client, tablet)
+
 	//
 	// Tests panic handling everywhere now
 	//
@@ -1494,5 +1735,20 @@ func Run(t *testing.T, client tmclient.TabletManagerClient, tablet *topodatapb.T
 	tmRPCTestBackupPanic(ctx, t, client, tablet)
 	tmRPCTestRestoreFromBackupPanic(ctx, t, client, tablet, restoreFromBackupRequest)
 
+	// Distributed transaction related methods
+	tmRPCTestGetUnresolvedTransactionsPanic(ctx, t, client, tablet)
+	tmRPCTestResolveTransactionPanic(ctx, t, client, tablet)
+
+	// Messaging related methods
+	tmRPCTestRequeueDeadLettersPanic(ctx, t, client, tablet)
+
+	// Diagnostics related methods
+	tmRPCTestRunDiagnosticQueryPanic(ctx, t, client, tablet)
+	tmRPCTestKillQueriesPanic(ctx, t, client, tablet)
+	tmRPCTestUpdateQueryFingerprintRulesPanic(ctx, t, client, tablet)
+
+	// MySQL upgrade related methods
+	tmRPCTestUpgradeMysqlPanic(ctx, t, client, tablet)
+
 	client.Close()
 }
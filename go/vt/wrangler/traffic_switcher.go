@@ -1603,22 +1603,23 @@ func (ts *trafficSwitcher) changeShardRouting(ctx context.Context) error {
 		log.Errorf("%w", err2)
 		return err2
 	}
-	err := ts.ForAllSources(func(source *workflow.MigrationSource) error {
-		_, err := ts.TopoServer().UpdateShardFields(ctx, ts.SourceKeyspaceName(), source.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			si.IsPrimaryServing = false
-			return nil
-		})
-		return err
-	})
-	if err != nil {
-		return err
-	}
-	err = ts.ForAllTargets(func(target *workflow.MigrationTarget) error {
-		_, err := ts.TopoServer().UpdateShardFields(ctx, ts.TargetKeyspaceName(), target.GetShard().ShardName(), func(si *topo.ShardInfo) error {
-			si.IsPrimaryServing = true
-			return nil
-		})
-		return err
+	// Stop serving from the sources and start serving from the targets
+	// in a single topo transaction, so there is no point in time where
+	// both or neither of them are serving.
+	targets := make(map[string]bool)
+	for _, target := range ts.TargetShards() {
+		targets[target.ShardName()] = true
+	}
+	var shards []string
+	for _, source := range ts.SourceShards() {
+		shards = append(shards, source.ShardName())
+	}
+	for _, target := range ts.TargetShards() {
+		shards = append(shards, target.ShardName())
+	}
+	_, err := ts.TopoServer().UpdateShardsFields(ctx, ts.TargetKeyspaceName(), shards, nil, func(si *topo.ShardInfo) error {
+		si.IsPrimaryServing = targets[si.ShardName()]
+		return nil
 	})
 	if err != nil {
 		return err